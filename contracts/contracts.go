@@ -0,0 +1,136 @@
+// Package contracts embeds the contract files of the repository so binaries
+// ship the exact contract versions they were built from, without a copy step.
+package contracts
+
+import (
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+
+	"github.com/ready-to-release/eac/contracts/extenv"
+)
+
+// CLIVersion is the version of the CLI contracts embedded in this build
+const CLIVersion = "0.1.0"
+
+// MetadataSchemaVersion is the newest extension-meta schema version of this
+// build; MetadataSchemaVersions lists all of them
+const MetadataSchemaVersion = "1.0"
+
+// CommitConfigVersion is the version of the .r2r/commit.yml schema embedded
+// in this build
+const CommitConfigVersion = "0.1.0"
+
+//go:embed cli/*/schema.json cli/*/command.ebnf modules/*/*.yml extension/*/metadata.schema.json extension-env/*/schema.json commit-config/*/schema.json
+var files embed.FS
+
+// FS returns the embedded contract files, rooted at the contracts directory
+func FS() fs.FS {
+	return files
+}
+
+// CLISchema returns the r2r-cli.yml JSON schema
+func CLISchema() string {
+	return mustRead(path.Join("cli", CLIVersion, "schema.json"))
+}
+
+// CommandGrammar returns the EBNF grammar of the r2r command line
+func CommandGrammar() string {
+	return mustRead(path.Join("cli", CLIVersion, "command.ebnf"))
+}
+
+// ExtensionEnvSchema returns the JSON schema of the environment passed to
+// extension containers, see package extenv
+func ExtensionEnvSchema() string {
+	return mustRead(path.Join("extension-env", extenv.Version, "schema.json"))
+}
+
+// CommitConfigSchema returns the JSON schema of .r2r/commit.yml
+func CommitConfigSchema() string {
+	return mustRead(path.Join("commit-config", CommitConfigVersion, "schema.json"))
+}
+
+// MetadataSchema returns the JSON schema of the extension-meta output for a
+// schema version (e.g. "1.0")
+func MetadataSchema(version string) (string, error) {
+	data, err := files.ReadFile(path.Join("extension", version, "metadata.schema.json"))
+	if err != nil {
+		return "", fmt.Errorf("no extension metadata schema embedded for version %s", version)
+	}
+	return string(data), nil
+}
+
+// MetadataSchemaVersions returns the extension-meta schema versions embedded
+// in this build (e.g. ["1.0", "1.1"]), in lexical rather than version order
+func MetadataSchemaVersions() []string {
+	paths, _ := fs.Glob(files, path.Join("extension", "*", "metadata.schema.json"))
+	versions := make([]string, 0, len(paths))
+	for _, p := range paths {
+		versions = append(versions, path.Base(path.Dir(p)))
+	}
+	return versions
+}
+
+// ModuleContracts returns the module contract files of a version, keyed by
+// filename (e.g. "src-cli.yml")
+func ModuleContracts(version string) (map[string][]byte, error) {
+	paths, err := fs.Glob(files, path.Join("modules", version, "*.yml"))
+	if err != nil {
+		return nil, err
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no module contracts embedded for version %s", version)
+	}
+
+	contracts := make(map[string][]byte, len(paths))
+	for _, p := range paths {
+		data, err := files.ReadFile(p)
+		if err != nil {
+			return nil, err
+		}
+		contracts[path.Base(p)] = data
+	}
+	return contracts, nil
+}
+
+// File describes an embedded contract file
+type File struct {
+	Path   string `json:"path"` // relative to the contracts directory
+	Size   int    `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// Files lists every embedded contract file with its checksum, sorted by path
+func Files() ([]File, error) {
+	var list []File
+	err := fs.WalkDir(files, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		data, err := files.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		sum := sha256.Sum256(data)
+		list = append(list, File{Path: p, Size: len(data), SHA256: hex.EncodeToString(sum[:])})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Path < list[j].Path })
+	return list, nil
+}
+
+// mustRead reads a file the embed patterns guarantee to exist
+func mustRead(name string) string {
+	data, err := files.ReadFile(name)
+	if err != nil {
+		panic(fmt.Sprintf("contract %s is not embedded: %v", name, err))
+	}
+	return string(data)
+}
@@ -0,0 +1,106 @@
+// Code generated by go run ./gen from contracts/extension-env/1.0/schema.json; DO NOT EDIT.
+
+package extenv
+
+// Env holds the variables of the extension environment contract. Unset
+// variables are empty.
+type Env struct {
+	// ContainerRepoRoot is R2R_CONTAINER_REPOROOT: Path of the repository inside the container, also the working directory
+	ContainerRepoRoot string
+	// HostRepoRoot is R2R_HOST_REPOROOT: Path of the repository on the host, for output that refers to host paths
+	HostRepoRoot string
+	// Columns is COLUMNS: Width of the terminal r2r runs in; 80 when it cannot be detected
+	Columns string
+	// Lines is LINES: Height of the terminal r2r runs in; 24 when it cannot be detected
+	Lines string
+	// TerminalDetection is R2R_TERMINAL_DETECTION: auto when COLUMNS and LINES were detected, default when they come from the environment of r2r or the defaults
+	TerminalDetection string
+	// CI is CI: Set when r2r runs in CI; colors are then disabled
+	CI string
+	// Term is TERM: Terminal type: dumb in CI, otherwise inherited from the host or xterm-256color
+	Term string
+	// ColorTerm is COLORTERM: Color support of the terminal, inherited from the host or truecolor
+	ColorTerm string
+	// NoColor is NO_COLOR: Disables colors when set; 1 in CI, otherwise inherited from the host
+	NoColor string
+	// ForceColor is FORCE_COLOR: Forces colors on or off; 0 in CI, otherwise inherited from the host
+	ForceColor string
+	// CLIColor is CLICOLOR: Inherited from the host outside of CI
+	CLIColor string
+	// CLIColorForce is CLICOLOR_FORCE: Inherited from the host outside of CI
+	CLIColorForce string
+	// Color is COLOR: Inherited from the host outside of CI
+	Color string
+	// Home is HOME: /tmp when the extension runs as a user other than root and the extension sets no HOME
+	Home string
+	// GitHubUsername is GITHUB_USERNAME: Forwarded from the host when set, for access to the GitHub Container Registry
+	GitHubUsername string
+	// GitHubToken is GITHUB_TOKEN: Forwarded from the host when set, for access to the GitHub Container Registry
+	GitHubToken string
+	// MetadataSchemaVersions is R2R_METADATA_SCHEMA_VERSIONS: Comma separated extension-meta schema versions the CLI reads; only set for the extension-meta command
+	MetadataSchemaVersions string
+}
+
+// Variables describes the variables of the contract, in schema order
+var Variables = []Variable{
+	{Name: "R2R_CONTAINER_REPOROOT", Description: "Path of the repository inside the container, also the working directory", Required: true, Secret: false},
+	{Name: "R2R_HOST_REPOROOT", Description: "Path of the repository on the host, for output that refers to host paths", Required: true, Secret: false},
+	{Name: "COLUMNS", Description: "Width of the terminal r2r runs in; 80 when it cannot be detected", Required: true, Secret: false},
+	{Name: "LINES", Description: "Height of the terminal r2r runs in; 24 when it cannot be detected", Required: true, Secret: false},
+	{Name: "R2R_TERMINAL_DETECTION", Description: "auto when COLUMNS and LINES were detected, default when they come from the environment of r2r or the defaults", Required: true, Secret: false},
+	{Name: "CI", Description: "Set when r2r runs in CI; colors are then disabled", Required: false, Secret: false},
+	{Name: "TERM", Description: "Terminal type: dumb in CI, otherwise inherited from the host or xterm-256color", Required: false, Secret: false},
+	{Name: "COLORTERM", Description: "Color support of the terminal, inherited from the host or truecolor", Required: false, Secret: false},
+	{Name: "NO_COLOR", Description: "Disables colors when set; 1 in CI, otherwise inherited from the host", Required: false, Secret: false},
+	{Name: "FORCE_COLOR", Description: "Forces colors on or off; 0 in CI, otherwise inherited from the host", Required: false, Secret: false},
+	{Name: "CLICOLOR", Description: "Inherited from the host outside of CI", Required: false, Secret: false},
+	{Name: "CLICOLOR_FORCE", Description: "Inherited from the host outside of CI", Required: false, Secret: false},
+	{Name: "COLOR", Description: "Inherited from the host outside of CI", Required: false, Secret: false},
+	{Name: "HOME", Description: "/tmp when the extension runs as a user other than root and the extension sets no HOME", Required: false, Secret: false},
+	{Name: "GITHUB_USERNAME", Description: "Forwarded from the host when set, for access to the GitHub Container Registry", Required: false, Secret: false},
+	{Name: "GITHUB_TOKEN", Description: "Forwarded from the host when set, for access to the GitHub Container Registry", Required: false, Secret: true},
+	{Name: "R2R_METADATA_SCHEMA_VERSIONS", Description: "Comma separated extension-meta schema versions the CLI reads; only set for the extension-meta command", Required: false, Secret: false},
+}
+
+// set assigns a contract variable, reporting whether name is one
+func (e *Env) set(name, value string) bool {
+	switch name {
+	case "R2R_CONTAINER_REPOROOT":
+		e.ContainerRepoRoot = value
+	case "R2R_HOST_REPOROOT":
+		e.HostRepoRoot = value
+	case "COLUMNS":
+		e.Columns = value
+	case "LINES":
+		e.Lines = value
+	case "R2R_TERMINAL_DETECTION":
+		e.TerminalDetection = value
+	case "CI":
+		e.CI = value
+	case "TERM":
+		e.Term = value
+	case "COLORTERM":
+		e.ColorTerm = value
+	case "NO_COLOR":
+		e.NoColor = value
+	case "FORCE_COLOR":
+		e.ForceColor = value
+	case "CLICOLOR":
+		e.CLIColor = value
+	case "CLICOLOR_FORCE":
+		e.CLIColorForce = value
+	case "COLOR":
+		e.Color = value
+	case "HOME":
+		e.Home = value
+	case "GITHUB_USERNAME":
+		e.GitHubUsername = value
+	case "GITHUB_TOKEN":
+		e.GitHubToken = value
+	case "R2R_METADATA_SCHEMA_VERSIONS":
+		e.MetadataSchemaVersions = value
+	default:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,53 @@
+// Package extenv is the environment contract between r2r and extensions: the
+// variables r2r passes to every extension container. The contract is the
+// versioned schema in contracts/extension-env; the Env struct, Variables and
+// the reference in contracts/extension-env/<version>/README.md are generated
+// from it.
+package extenv
+
+//go:generate go run ./gen
+
+import (
+	"os"
+	"strings"
+)
+
+// Version is the version of the extension environment contract
+const Version = "1.0"
+
+// Variable describes one variable of the contract
+type Variable struct {
+	Name        string
+	Description string
+	// Required variables are set for every extension run
+	Required bool
+	// Secret variables must not be shown
+	Secret bool
+}
+
+// Parse returns the contract variables of an environment in the form of
+// os.Environ. Later entries win, as they do for Docker.
+func Parse(environ []string) Env {
+	var env Env
+	for _, entry := range environ {
+		name, value, _ := strings.Cut(entry, "=")
+		env.set(name, value)
+	}
+	return env
+}
+
+// FromEnv returns the contract variables of the current process, for use
+// inside an extension
+func FromEnv() Env {
+	return Parse(os.Environ())
+}
+
+// Lookup returns the contract variable name, if it is one
+func Lookup(name string) (Variable, bool) {
+	for _, v := range Variables {
+		if v.Name == name {
+			return v, true
+		}
+	}
+	return Variable{}, false
+}
@@ -0,0 +1,195 @@
+// Command gen generates the Env struct of package extenv and the reference
+// documentation of the extension environment contract from its schema. Run
+// it with go generate in contracts/extenv.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// property is a variable of the schema
+type property struct {
+	Name        string   `json:"-"`
+	Description string   `json:"description"`
+	Const       string   `json:"const"`
+	Enum        []string `json:"enum"`
+	Pattern     string   `json:"pattern"`
+	Field       string   `json:"x-go-field"`
+	Secret      bool     `json:"x-secret"`
+	Required    bool     `json:"-"`
+}
+
+type schema struct {
+	Title       string
+	Description string
+	Properties  []property
+}
+
+func main() {
+	version := flag.String("version", "1.0", "contract version to generate from")
+	out := flag.String("out", "env_gen.go", "Go file to write")
+	flag.Parse()
+
+	dir := filepath.Join("..", "extension-env", *version)
+	s, err := readSchema(filepath.Join(dir, "schema.json"))
+	if err != nil {
+		fail(err)
+	}
+
+	code, err := renderGo(s, *version)
+	if err != nil {
+		fail(err)
+	}
+	if err := os.WriteFile(*out, code, 0o644); err != nil {
+		fail(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "README.md"), renderDocs(s, *version), 0o644); err != nil {
+		fail(err)
+	}
+}
+
+func fail(err error) {
+	fmt.Fprintf(os.Stderr, "gen: %v\n", err)
+	os.Exit(1)
+}
+
+// readSchema reads the variables of the schema in their order in the file,
+// which encoding/json maps do not keep
+func readSchema(path string) (*schema, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var raw struct {
+		Title       string                     `json:"title"`
+		Description string                     `json:"description"`
+		Required    []string                   `json:"required"`
+		Properties  map[string]json.RawMessage `json:"properties"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	order, err := propertyOrder(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	s := &schema{Title: raw.Title, Description: raw.Description}
+	for _, name := range order {
+		var p property
+		if err := json.Unmarshal(raw.Properties[name], &p); err != nil {
+			return nil, fmt.Errorf("%s: property %s: %w", path, name, err)
+		}
+		if p.Field == "" {
+			return nil, fmt.Errorf("%s: property %s has no x-go-field", path, name)
+		}
+		p.Name = name
+		p.Required = slices.Contains(raw.Required, name)
+		s.Properties = append(s.Properties, p)
+	}
+	return s, nil
+}
+
+// propertyOrder returns the keys of the top level properties object in
+// document order
+func propertyOrder(data []byte) ([]string, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if _, err := dec.Token(); err != nil { // {
+		return nil, err
+	}
+	for dec.More() {
+		key, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		if key != "properties" {
+			var skip json.RawMessage
+			if err := dec.Decode(&skip); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if _, err := dec.Token(); err != nil { // {
+			return nil, err
+		}
+		var names []string
+		for dec.More() {
+			name, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			var skip json.RawMessage
+			if err := dec.Decode(&skip); err != nil {
+				return nil, err
+			}
+			names = append(names, name.(string))
+		}
+		return names, nil
+	}
+	return nil, fmt.Errorf("no properties")
+}
+
+func renderGo(s *schema, version string) ([]byte, error) {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated by go run ./gen from contracts/extension-env/%s/schema.json; DO NOT EDIT.\n\n", version)
+	b.WriteString("package extenv\n\n")
+	b.WriteString("// Env holds the variables of the extension environment contract. Unset\n// variables are empty.\n")
+	b.WriteString("type Env struct {\n")
+	for _, p := range s.Properties {
+		fmt.Fprintf(&b, "\t// %s is %s: %s\n\t%s string\n", p.Field, p.Name, p.Description, p.Field)
+	}
+	b.WriteString("}\n\n")
+
+	b.WriteString("// Variables describes the variables of the contract, in schema order\n")
+	b.WriteString("var Variables = []Variable{\n")
+	for _, p := range s.Properties {
+		fmt.Fprintf(&b, "\t{Name: %q, Description: %q, Required: %t, Secret: %t},\n", p.Name, p.Description, p.Required, p.Secret)
+	}
+	b.WriteString("}\n\n")
+
+	b.WriteString("// set assigns a contract variable, reporting whether name is one\n")
+	b.WriteString("func (e *Env) set(name, value string) bool {\n\tswitch name {\n")
+	for _, p := range s.Properties {
+		fmt.Fprintf(&b, "\tcase %q:\n\t\te.%s = value\n", p.Name, p.Field)
+	}
+	b.WriteString("\tdefault:\n\t\treturn false\n\t}\n\treturn true\n}\n")
+	return format.Source(b.Bytes())
+}
+
+func renderDocs(s *schema, version string) []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "<!-- Code generated by go run ./gen in contracts/extenv; DO NOT EDIT. -->\n\n")
+	fmt.Fprintf(&b, "# %s %s\n\n%s\n\n", s.Title, version, s.Description)
+	b.WriteString("Run `r2r run --print-env <extension>` to see the environment an extension\nwill receive. Go extensions can read it with\n`github.com/ready-to-release/eac/contracts/extenv`.\n\n")
+	b.WriteString("| Variable | Required | Values | Description |\n")
+	b.WriteString("| --- | --- | --- | --- |\n")
+	for _, p := range s.Properties {
+		required := "no"
+		if p.Required {
+			required = "yes"
+		}
+		var values string
+		switch {
+		case p.Const != "":
+			values = "`" + p.Const + "`"
+		case len(p.Enum) > 0:
+			values = "`" + strings.Join(p.Enum, "`, `") + "`"
+		case p.Pattern != "":
+			values = "`" + strings.ReplaceAll(p.Pattern, "|", `\|`) + "`"
+		}
+		description := p.Description
+		if p.Secret {
+			description += " (secret, masked by --print-env)"
+		}
+		fmt.Fprintf(&b, "| `%s` | %s | %s | %s |\n", p.Name, required, values, description)
+	}
+	return b.Bytes()
+}
@@ -0,0 +1,3 @@
+module github.com/ready-to-release/eac/contracts
+
+go 1.25.3
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/ready-to-release/eac/src/cli/internal/progress"
 	"github.com/ready-to-release/eac/src/core/ai"
 	"github.com/ready-to-release/eac/src/core/ai/providers"
 	"github.com/ready-to-release/eac/src/core/repository"
@@ -61,6 +62,15 @@ Examples:
 		logger := ai.NewFileLogger(workspaceRoot)
 		executor.SetLogger(logger)
 
+		// Record or replay responses when R2R_AI_RECORDING is set
+		recorder, err := ai.RecorderFromEnv(workspaceRoot)
+		if err != nil {
+			return err
+		}
+		if recorder != nil {
+			executor.SetRecorder(recorder)
+		}
+
 		// Get prompt from args
 		prompt := args[0]
 
@@ -77,7 +87,9 @@ Examples:
 		fmt.Printf("🤖 Asking AI: %s\n\n", prompt)
 
 		ctx := context.Background()
+		waiting := progress.Spinner("Waiting for the AI response")
 		response, err := executor.Execute(ctx, prompt, opts...)
+		waiting.Done("")
 		if err != nil {
 			return fmt.Errorf("AI execution failed: %w", err)
 		}
@@ -0,0 +1,184 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ready-to-release/eac/src/core/audit"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	RootCmd.AddCommand(AuditCmd)
+	AuditCmd.AddCommand(AuditTailCmd)
+
+	AuditTailCmd.Flags().IntP("lines", "n", 20, "Number of entries to show, 0 for all")
+	AuditTailCmd.Flags().BoolP("follow", "f", false, "Keep showing entries as they are written")
+	AuditTailCmd.Flags().Bool("json", false, "Print the entries as JSON lines")
+}
+
+// auditFollowInterval is how often audit tail --follow checks for new
+// entries
+const auditFollowInterval = 500 * time.Millisecond
+
+var AuditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Show the audit log of commands and MCP tool calls",
+	Long: `Show the audit log of commands and MCP tool calls.
+
+Every r2r command and every tool call of the r2r MCP servers appends one JSON
+line to .r2r/audit.log in the repository root: when it ran, its arguments with
+credentials masked, its duration, its exit status and the containers it
+started. The log is rotated at 10 MB and the last 5 rotated files are kept.
+
+Set R2R_AUDIT_LOG to write the log elsewhere, or R2R_AUDIT=off to turn it off.`,
+}
+
+var AuditTailCmd = &cobra.Command{
+	Use:   "tail",
+	Short: "Show the last entries of the audit log",
+	Example: `  r2r audit tail
+  r2r audit tail -n 100 --json
+  r2r audit tail --follow`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		lines, _ := cmd.Flags().GetInt("lines")
+		follow, _ := cmd.Flags().GetBool("follow")
+		asJSON, _ := cmd.Flags().GetBool("json")
+
+		path, err := audit.DefaultPath()
+		if err != nil {
+			return fmt.Errorf("failed to locate the audit log: %w", err)
+		}
+		auditLog := audit.New(path)
+
+		entries, err := auditLog.Tail(lines)
+		if err != nil {
+			return err
+		}
+		if len(entries) == 0 && !follow {
+			fmt.Fprintf(os.Stderr, "No audit entries in %s\n", path)
+			return nil
+		}
+		for _, entry := range entries {
+			printAuditEntry(os.Stdout, entry, asJSON)
+		}
+
+		if follow {
+			return followAuditLog(path, asJSON)
+		}
+		return nil
+	},
+}
+
+// followAuditLog prints entries appended to the log until the process is
+// interrupted. A rotated log is read again from its start.
+func followAuditLog(path string, asJSON bool) error {
+	var offset int64
+	if info, err := os.Stat(path); err == nil {
+		offset = info.Size()
+	}
+	var partial []byte
+
+	for {
+		time.Sleep(auditFollowInterval)
+
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		if info.Size() < offset {
+			offset, partial = 0, nil
+		}
+		if info.Size() == offset {
+			continue
+		}
+
+		f, err := os.Open(path)
+		if err != nil {
+			continue
+		}
+		data, err := io.ReadAll(io.NewSectionReader(f, offset, info.Size()-offset))
+		f.Close()
+		if err != nil {
+			return fmt.Errorf("failed to read audit log: %w", err)
+		}
+		offset += int64(len(data))
+
+		// Keep an incomplete last line until the rest is written
+		data = append(partial, data...)
+		end := strings.LastIndexByte(string(data), '\n') + 1
+		partial = append([]byte(nil), data[end:]...)
+
+		entries, err := audit.Read(strings.NewReader(string(data[:end])))
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			printAuditEntry(os.Stdout, entry, asJSON)
+		}
+	}
+}
+
+func printAuditEntry(w io.Writer, entry audit.Entry, asJSON bool) {
+	if asJSON {
+		data, _ := json.Marshal(entry)
+		fmt.Fprintln(w, string(data))
+		return
+	}
+
+	call := entry.Name
+	switch {
+	case entry.Kind == audit.KindCommand:
+		call = strings.Join(append([]string{"r2r"}, entry.Args...), " ")
+	case len(entry.Arguments) > 0:
+		call += " " + string(entry.Arguments)
+	}
+	status := "ok"
+	if entry.ExitCode != 0 {
+		status = fmt.Sprintf("exit %d", entry.ExitCode)
+	}
+	duration := (time.Duration(entry.DurationMS) * time.Millisecond).Round(time.Millisecond)
+	fmt.Fprintf(w, "%s  %-20s  %-8s %8s  %s\n", entry.Time.Local().Format(time.DateTime), entry.Source, status, duration, call)
+	if entry.Error != "" {
+		fmt.Fprintf(w, "    error: %s\n", entry.Error)
+	}
+	for _, c := range entry.Containers {
+		fmt.Fprintf(w, "    container %s  %s  %s\n", shortID(c.ID), c.Image, shortID(strings.TrimPrefix(c.ImageID, "sha256:")))
+	}
+}
+
+func shortID(id string) string {
+	if len(id) > 12 {
+		return id[:12]
+	}
+	return id
+}
+
+// startCommandAudit begins the audit record of the command in os.Args
+func startCommandAudit() {
+	name := "r2r"
+	if cmd, _, err := RootCmd.Find(os.Args[1:]); err == nil && cmd != nil {
+		name = cmd.CommandPath()
+	}
+	audit.SetCurrent(audit.Start(audit.SourceCLI, audit.KindCommand, name, os.Args[1:]))
+}
+
+// finishCommandAudit writes the audit record of the command. A failure to
+// write the audit log is logged and does not change the exit code.
+func finishCommandAudit(code int, err error) {
+	if writeErr := audit.Current().Finish(code, err); writeErr != nil && log != nil {
+		log.WithField("error", writeErr.Error()).Warn().Msg("Failed to write the audit log")
+	}
+}
+
+// exitProcess writes the audit record and exits with code. Commands call it
+// instead of os.Exit so every exit is audited.
+func exitProcess(code int) {
+	finishCommandAudit(code, nil)
+	os.Exit(code)
+}
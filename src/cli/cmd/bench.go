@@ -0,0 +1,181 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/ready-to-release/eac/src/cli/internal/bench"
+	"github.com/ready-to-release/eac/src/cli/internal/conf"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	RootCmd.AddCommand(BenchCmd)
+
+	BenchCmd.Flags().String("bench", "", "Run only benchmarks matching this regular expression")
+	BenchCmd.Flags().Int("count", 5, "Runs per benchmark; the median is reported")
+	BenchCmd.Flags().String("benchtime", "", "Run time or iterations per benchmark, e.g. 2s or 100x")
+	BenchCmd.Flags().String("save", "", "Write the results to this file, e.g. as a new baseline")
+	BenchCmd.Flags().String("compare", "", "Compare the results with this baseline and fail on regressions")
+	BenchCmd.Flags().Float64("threshold", bench.DefaultThreshold, "Slowdown in percent that counts as a regression")
+	BenchCmd.Flags().BoolP("verbose", "v", false, "Show the go test output")
+	BenchCmd.Flags().Bool("json", false, "Print the results, or the comparison, as JSON")
+}
+
+var BenchCmd = &cobra.Command{
+	Use:   "bench",
+	Short: "Run the benchmark suite and check for performance regressions",
+	Long: `Run the Go benchmarks of the repository: configuration load, merge and
+validation, commit message validation, module detection and MCP message
+round trips. Each benchmark runs --count times and the median time per
+operation is reported.
+
+With --compare the results are compared with a baseline saved earlier with
+--save. The command fails when a benchmark is more than --threshold percent
+slower than its baseline, so it can gate CI: save a baseline on the main
+branch and compare against it in pull requests. Baselines are only
+comparable when taken on the same kind of machine.
+
+Examples:
+  r2r bench
+  r2r bench --bench Config --count 10
+  r2r bench --save bench-baseline.json
+  r2r bench --compare bench-baseline.json --threshold 20`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		pattern, _ := cmd.Flags().GetString("bench")
+		count, _ := cmd.Flags().GetInt("count")
+		benchTime, _ := cmd.Flags().GetString("benchtime")
+		savePath, _ := cmd.Flags().GetString("save")
+		comparePath, _ := cmd.Flags().GetString("compare")
+		threshold, _ := cmd.Flags().GetFloat64("threshold")
+		verbose, _ := cmd.Flags().GetBool("verbose")
+		asJSON, _ := cmd.Flags().GetBool("json")
+		if count < 1 {
+			return fmt.Errorf("--count must be at least 1")
+		}
+		if threshold < 0 {
+			return fmt.Errorf("--threshold must not be negative")
+		}
+
+		// Load the baseline first so a missing file fails before the run
+		var baseline *bench.Report
+		if comparePath != "" {
+			var err error
+			if baseline, err = bench.LoadReport(comparePath); err != nil {
+				return err
+			}
+		}
+
+		repoRoot, err := conf.FindRepositoryRoot()
+		if err != nil {
+			return fmt.Errorf("failed to find repository root: %w", err)
+		}
+
+		opts := bench.Options{Bench: pattern, Count: count, BenchTime: benchTime}
+		if verbose {
+			opts.Output = os.Stderr
+		}
+		ctx, stop := interruptContext(context.Background())
+		defer stop()
+
+		results, err := bench.Run(ctx, repoRoot, bench.Suite, opts)
+		if err != nil {
+			return err
+		}
+		report := bench.NewReport(results)
+
+		if savePath != "" {
+			if err := report.Save(savePath); err != nil {
+				return err
+			}
+			if !asJSON {
+				fmt.Printf("💾 Saved %d benchmark result(s) to %s\n", len(results), savePath)
+			}
+		}
+
+		if baseline == nil {
+			if asJSON {
+				return printBenchJSON(report)
+			}
+			printBenchResults(results)
+			return nil
+		}
+
+		comparison := bench.Compare(baseline.Results, results, threshold)
+		if asJSON {
+			if err := printBenchJSON(comparison); err != nil {
+				return err
+			}
+		} else {
+			printBenchComparison(comparison)
+		}
+
+		if regressions := comparison.Regressions(); len(regressions) > 0 {
+			return fmt.Errorf("%d benchmark(s) regressed by more than %.0f%%", len(regressions), threshold)
+		}
+		return nil
+	},
+}
+
+func printBenchJSON(v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode JSON: %w", err)
+	}
+	fmt.Println(string(data))
+	return nil
+}
+
+func printBenchResults(results []bench.Result) {
+	if len(results) == 0 {
+		fmt.Println("No benchmarks matched")
+		return
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "BENCHMARK\tTIME/OP\tBYTES/OP\tALLOCS/OP\tRUNS\t")
+	for _, result := range results {
+		fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%d\t\n", result.Key(), formatNs(result.NsPerOp), result.BytesPerOp, result.AllocsPerOp, result.Runs)
+	}
+	w.Flush()
+}
+
+func printBenchComparison(comparison *bench.Comparison) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "BENCHMARK\tBASELINE\tCURRENT\tCHANGE\t\t")
+	for _, delta := range comparison.Deltas {
+		mark := ""
+		if delta.Regressed {
+			mark = "❌ regression"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%+.1f%%\t%s\t\n", delta.Key, formatNs(delta.Baseline), formatNs(delta.Current), delta.Change, mark)
+	}
+	w.Flush()
+
+	for _, key := range comparison.Added {
+		fmt.Printf("➕ %s is not in the baseline\n", key)
+	}
+	for _, key := range comparison.Removed {
+		fmt.Printf("➖ %s is only in the baseline\n", key)
+	}
+	if len(comparison.Regressions()) == 0 {
+		fmt.Printf("✅ No benchmark is more than %.0f%% slower than the baseline\n", comparison.Threshold)
+	}
+}
+
+// formatNs formats a time per operation with a readable unit
+func formatNs(ns float64) string {
+	switch {
+	case ns >= 1e9:
+		return fmt.Sprintf("%.2fs", ns/1e9)
+	case ns >= 1e6:
+		return fmt.Sprintf("%.2fms", ns/1e6)
+	case ns >= 1e3:
+		return fmt.Sprintf("%.2fµs", ns/1e3)
+	default:
+		return fmt.Sprintf("%.0fns", ns)
+	}
+}
@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ready-to-release/eac/src/cli/internal/conf"
+	"github.com/ready-to-release/eac/src/cli/internal/docker"
+	"github.com/ready-to-release/eac/src/cli/internal/notify"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	RootCmd.AddCommand(BuildCmd)
+
+	BuildCmd.Flags().Bool("use", false, "Set load_local for the extension in r2r-cli.local.yml so later runs use the built image")
+	BuildCmd.Flags().Bool("no-cache", false, "Do not use the Docker build cache")
+}
+
+var BuildCmd = &cobra.Command{
+	Use:   "build <extension>",
+	Short: "Build an extension image from a local Dockerfile",
+	Long: `Build an extension image from the Dockerfile declared in its build section.
+
+The image is tagged with the extension's configured image, so it is picked up
+as a local development image when load_local is enabled. Use --use to enable
+load_local for the extension in r2r-cli.local.yml.
+
+Configuration:
+  extensions:
+    - name: pwsh
+      image: ghcr.io/ready-to-release/r2r-pwsh:latest
+      build:
+        context: extensions/pwsh     # Relative to the repository root
+        dockerfile: Dockerfile       # Relative to the context (default)
+        args:
+          PWSH_VERSION: "7.4"
+
+Examples:
+  # Build the image
+  r2r build pwsh
+
+  # Build and use it for subsequent runs
+  r2r build pwsh --use`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := conf.InitConfig()
+
+		extensionName := args[0]
+		ext, found := cfg.FindExtension(extensionName)
+		if !found {
+			fmt.Printf("❌ Extension %s not found in configuration\n", extensionName)
+			exitProcess(1)
+		}
+		if ext.Build == nil {
+			fmt.Printf("❌ Extension %s has no build configuration\n", extensionName)
+			fmt.Println("\nAdd a build section to the extension in r2r-cli.yml:")
+			fmt.Println("  build:")
+			fmt.Println("    context: path/to/extension")
+			fmt.Println("    dockerfile: Dockerfile")
+			exitProcess(1)
+		}
+
+		repoRoot, err := conf.FindRepositoryRoot()
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to find repository root")
+			exitProcess(1)
+		}
+
+		host, err := docker.NewContainerHostWithConfig(cfg)
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to create container host")
+			exitProcess(1)
+		}
+		defer host.Close()
+
+		noCache, _ := cmd.Flags().GetBool("no-cache")
+		opts := docker.BuildOptions{
+			ContextDir: filepath.Join(repoRoot, ext.Build.Context),
+			Dockerfile: ext.Build.Dockerfile,
+			Args:       ext.Build.Args,
+			Tags:       []string{ext.Image},
+			NoCache:    noCache,
+		}
+
+		// Ctrl-C aborts the build
+		ctx, stop := interruptContext(context.Background())
+		defer stop()
+
+		fmt.Printf("🔨 Building %s from %s\n", ext.Image, ext.Build.Context)
+
+		started := time.Now()
+		imageID, err := host.BuildImage(ctx, opts, os.Stdout)
+		if ctx.Err() != nil {
+			fmt.Println("\n⚠️  Build interrupted")
+			exitProcess(exitCodeInterrupted)
+		}
+		notify.New(cfg).Done(ctx, notify.Event{Operation: "build", Subject: ext.Name, Success: err == nil}, started)
+		if err != nil {
+			log.Error().Err(err).Str("extension", ext.Name).Msg("Failed to build extension image")
+			fmt.Printf("❌ Failed to build %s: %v\n", ext.Name, err)
+			exitProcess(1)
+		}
+
+		fmt.Printf("✅ Built %s (%s)\n", ext.Image, shortImageID(imageID))
+
+		if use, _ := cmd.Flags().GetBool("use"); use {
+			overridePath := filepath.Join(repoRoot, conf.LocalOverrideFile)
+			if err := conf.EnableLoadLocal(overridePath, ext.Name); err != nil {
+				log.Error().Err(err).Msg("Failed to update local configuration")
+				fmt.Printf("❌ Failed to enable load_local for %s: %v\n", ext.Name, err)
+				exitProcess(1)
+			}
+			fmt.Printf("🏠 %s now uses the local image (load_local set in %s)\n", ext.Name, conf.LocalOverrideFile)
+		} else if !ext.LoadLocal && !cfg.LoadLocal {
+			fmt.Printf("💡 Run 'r2r build %s --use' or set load_local to use the local image\n", ext.Name)
+		}
+	},
+}
+
+// shortImageID returns the first 12 hex characters of an image ID
+func shortImageID(id string) string {
+	id = strings.TrimPrefix(id, "sha256:")
+	if len(id) > 12 {
+		return id[:12]
+	}
+	return id
+}
@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ready-to-release/eac/src/cli/internal/cache"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	RootCmd.AddCommand(CacheCmd)
+	CacheCmd.AddCommand(CacheInfoCmd)
+	CacheCmd.AddCommand(CacheClearCmd)
+
+	CacheInfoCmd.Flags().Bool("json", false, "Print the cache information as JSON")
+	CacheClearCmd.Flags().Bool("all", false, "Clear the caches of all shell sessions, not only the current one")
+}
+
+var CacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Inspect and clear the registry cache",
+	Long: `Inspect and clear the registry cache.
+
+The registry cache keeps the tags of extension images looked up in the GitHub
+Container Registry, one file per shell session, so pin checks do not query the
+registry on every command. A corrupted or outdated cache file is discarded
+automatically.`,
+}
+
+var CacheInfoCmd = &cobra.Command{
+	Use:   "info",
+	Short: "Show the registry cache of the current session",
+	Example: `  r2r cache info
+  r2r cache info --json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		asJSON, _ := cmd.Flags().GetBool("json")
+
+		info, err := cache.Stat()
+		if err != nil {
+			return err
+		}
+
+		if asJSON {
+			data, err := json.MarshalIndent(info, "", "  ")
+			if err != nil {
+				return fmt.Errorf("failed to encode cache information: %w", err)
+			}
+			fmt.Println(string(data))
+			return nil
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintf(w, "Path:\t%s\n", info.Path)
+		switch {
+		case !info.Exists:
+			fmt.Fprintf(w, "Status:\tempty\n")
+		case info.Invalid != "":
+			fmt.Fprintf(w, "Status:\tinvalid, discarded on next use (%s)\n", info.Invalid)
+			fmt.Fprintf(w, "Size:\t%d bytes\n", info.Size)
+		default:
+			fmt.Fprintf(w, "Status:\tok\n")
+			fmt.Fprintf(w, "Version:\t%s\n", info.Version)
+			fmt.Fprintf(w, "Size:\t%d bytes\n", info.Size)
+			fmt.Fprintf(w, "Extensions:\t%d\n", info.Extensions)
+			if !info.UpdatedAt.IsZero() {
+				fmt.Fprintf(w, "Updated:\t%s (%s ago)\n", info.UpdatedAt.Local().Format(time.RFC3339), time.Since(info.UpdatedAt).Round(time.Second))
+			}
+		}
+		fmt.Fprintf(w, "Sessions:\t%d\n", info.Sessions)
+		return w.Flush()
+	},
+}
+
+var CacheClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Delete the registry cache",
+	Example: `  r2r cache clear
+  r2r cache clear --all`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		all, _ := cmd.Flags().GetBool("all")
+
+		if all {
+			removed, err := cache.RemoveAll()
+			if err != nil {
+				return err
+			}
+			fmt.Printf("✅ Cleared %d session cache(s)\n", removed)
+			return nil
+		}
+
+		if err := cache.Remove(); err != nil {
+			return err
+		}
+		fmt.Println("✅ Cache cleared successfully")
+		return nil
+	},
+}
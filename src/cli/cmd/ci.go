@@ -0,0 +1,21 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	RootCmd.AddCommand(CiCmd)
+}
+
+// CiCmd is the parent command for the continuous integration checks
+var CiCmd = &cobra.Command{
+	Use:   "ci",
+	Short: "Run the repository checks of continuous integration",
+	Long:  `Run the repository checks of continuous integration, such as the checks a pull request must pass.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := cmd.Help(); err != nil {
+			log.WithField("error", err).Error().Msg("Failed to show help")
+		}
+	},
+}
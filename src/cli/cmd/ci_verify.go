@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ready-to-release/eac/src/cli/internal/civerify"
+	"github.com/ready-to-release/eac/src/cli/internal/version"
+	"github.com/ready-to-release/eac/src/core/repository"
+	"github.com/spf13/cobra"
+)
+
+var (
+	ciVerifyBase   string
+	ciVerifyHead   string
+	ciVerifyFormat string
+	ciVerifyOutput string
+)
+
+func init() {
+	CiCmd.AddCommand(CiVerifyCmd)
+
+	CiVerifyCmd.Flags().StringVar(&ciVerifyBase, "base", "", "Base of the pull request (default: origin/$GITHUB_BASE_REF when set)")
+	CiVerifyCmd.Flags().StringVar(&ciVerifyHead, "head", "HEAD", "Head of the pull request")
+	CiVerifyCmd.Flags().StringVar(&ciVerifyFormat, "format", civerify.FormatText, "Report format: text, json or sarif")
+	CiVerifyCmd.Flags().StringVar(&ciVerifyOutput, "output", "", "Write the report to this file and print the text summary")
+}
+
+var CiVerifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Run the pull request checks in one process with one report",
+	Long: `Run the repository checks of a pull request in one process and report them
+together, for a single required GitHub check:
+
+  schema            r2r-cli.yml and .r2r/commit.yml against their schemas
+  pinned-images     extension images in r2r-cli.yml without a version tag
+                    or digest (the unpinned-tag rule, failing unless off)
+  commit-messages   the commit message rules on each commit of base..head,
+                    except merges and reverts
+  contracts-drift   the directory tree against the module contracts, as
+                    r2r contracts drift
+  affected-modules  the modules owning the files changed since the merge
+                    base, and the modules depending on them
+
+Without --base, and outside a pull request workflow, the commit-messages and
+affected-modules checks are skipped. The command fails when a check finds an
+error or cannot run; all checks run regardless.
+
+--format json includes the affected modules and their path filters for the
+jobs that follow. --format sarif writes SARIF 2.1.0 for
+github/codeql-action/upload-sarif. With --output, the report goes to the file
+and the text summary to stdout.
+
+Example:
+  r2r ci verify --base origin/main
+  r2r ci verify --format sarif --output r2r.sarif
+  r2r ci verify --format json | jq .affected_modules.affected`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if ciVerifyFormat != civerify.FormatText && ciVerifyFormat != civerify.FormatJSON && ciVerifyFormat != civerify.FormatSARIF {
+			return fmt.Errorf("--format must be %s, %s or %s", civerify.FormatText, civerify.FormatJSON, civerify.FormatSARIF)
+		}
+
+		workspaceRoot, err := repository.GetRepositoryRoot("")
+		if err != nil {
+			return fmt.Errorf("failed to find repository root: %w", err)
+		}
+
+		base := ciVerifyBase
+		if base == "" && os.Getenv("GITHUB_BASE_REF") != "" {
+			base = "origin/" + os.Getenv("GITHUB_BASE_REF")
+		}
+
+		report := civerify.Verify(civerify.Options{WorkspaceRoot: workspaceRoot, Base: base, Head: ciVerifyHead})
+
+		if ciVerifyOutput != "" {
+			f, err := os.Create(ciVerifyOutput)
+			if err != nil {
+				return fmt.Errorf("failed to create report file: %w", err)
+			}
+			err = civerify.Write(f, report, ciVerifyFormat, version.GetInfo().Version)
+			if closeErr := f.Close(); err == nil {
+				err = closeErr
+			}
+			if err != nil {
+				return fmt.Errorf("failed to write report: %w", err)
+			}
+			if err := civerify.Write(cmd.OutOrStdout(), report, civerify.FormatText, ""); err != nil {
+				return err
+			}
+		} else if err := civerify.Write(cmd.OutOrStdout(), report, ciVerifyFormat, version.GetInfo().Version); err != nil {
+			return fmt.Errorf("failed to write report: %w", err)
+		}
+
+		if !report.Passed {
+			return fmt.Errorf("ci verify failed")
+		}
+		return nil
+	},
+}
@@ -2,7 +2,6 @@ package cmd
 
 import (
 	"fmt"
-	"os"
 	"os/exec"
 	"strings"
 
@@ -42,21 +41,21 @@ Use --keep to retain more versions, or --all to also clean non-extension images.
   # Clean all Docker images (not just extensions)
   r2r cleanup --all`,
 	Run: func(cmd *cobra.Command, args []string) {
-		conf.InitConfig()
+		cfg := conf.InitConfig()
 
 		if cleanupAll {
 			cleanAllDockerImages()
 		} else {
-			cleanExtensionImages()
+			cleanExtensionImages(cfg)
 		}
 	},
 }
 
-func cleanExtensionImages() {
+func cleanExtensionImages(cfg *conf.Config) {
 	fmt.Println("🧹 Cleaning up old extension images...")
 
 	// Get list of configured extensions
-	extensions := conf.Global.Extensions
+	extensions := cfg.Extensions
 	if len(extensions) == 0 {
 		fmt.Println("No extensions configured")
 		return
@@ -183,7 +182,7 @@ func cleanAllDockerImages() {
 			fmt.Print(string(output))
 		} else {
 			fmt.Printf("Error: %v\n", err)
-			os.Exit(1)
+			exitProcess(1)
 		}
 
 		// Show disk usage after cleanup
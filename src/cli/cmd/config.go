@@ -0,0 +1,21 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	RootCmd.AddCommand(ConfigCmd)
+}
+
+// ConfigCmd is the parent command for r2r-cli.yml maintenance
+var ConfigCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Check and maintain r2r-cli.yml configuration files",
+	Long:  `Lint and reformat r2r-cli.yml configuration files and export their schema for editors.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := cmd.Help(); err != nil {
+			log.WithField("error", err).Error().Msg("Failed to show help")
+		}
+	},
+}
@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ready-to-release/eac/src/cli/internal/conf"
+	"github.com/ready-to-release/eac/src/cli/internal/configlint"
+	"github.com/spf13/cobra"
+)
+
+var (
+	configLintFix    bool
+	configLintStrict bool
+)
+
+func init() {
+	ConfigCmd.AddCommand(ConfigLintCmd)
+
+	ConfigLintCmd.Flags().BoolVar(&configLintFix, "fix", false, "Rewrite the file in canonical form, fixing what can be fixed")
+	ConfigLintCmd.Flags().BoolVar(&configLintStrict, "strict", false, "Treat warnings as errors")
+}
+
+var ConfigLintCmd = &cobra.Command{
+	Use:   "lint [config-file]",
+	Short: "Lint an r2r-cli.yml configuration file",
+	Long: `Lint an r2r-cli.yml configuration file with three kinds of checks:
+  - schema: the embedded JSON schema and warning rules, as in 'r2r validate'
+  - semantic: the checks applied when the configuration is loaded
+  - style: canonical key order, repeated or redundant environment variables,
+    and canonical formatting
+
+--fix reorders keys, removes repeated and redundant environment entries and
+rewrites the file with 2-space indentation. Comments are kept; blank lines
+are not.
+
+Examples:
+  r2r config lint
+  r2r config lint ./r2r-cli.local.yml --fix
+  r2r config lint --strict`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var configFile string
+		if len(args) > 0 {
+			configFile = args[0]
+		} else {
+			repoRoot, err := conf.FindRepositoryRoot()
+			if err != nil {
+				return fmt.Errorf("no configuration file specified and could not find repository root: %w", err)
+			}
+			configFile = filepath.Join(repoRoot, "r2r-cli.yml")
+		}
+
+		data, err := os.ReadFile(configFile)
+		if err != nil {
+			return fmt.Errorf("failed to read configuration file: %w", err)
+		}
+
+		report, err := configlint.Lint(data, configFile)
+		if err != nil {
+			return err
+		}
+
+		if configLintFix && report.Fixable() > 0 {
+			fixed, err := configlint.Fix(data)
+			if err != nil {
+				return fmt.Errorf("failed to fix %s: %w", configFile, err)
+			}
+			info, err := os.Stat(configFile)
+			if err != nil {
+				return err
+			}
+			if err := os.WriteFile(configFile, fixed, info.Mode().Perm()); err != nil {
+				return fmt.Errorf("failed to write %s: %w", configFile, err)
+			}
+
+			fixable := report.Fixable()
+			if report, err = configlint.Lint(fixed, configFile); err != nil {
+				return err
+			}
+			report.Fixed = fixable - report.Fixable()
+			fmt.Printf("🔧 Fixed %d finding(s) in %s\n", report.Fixed, configFile)
+		}
+
+		if len(report.Findings) == 0 {
+			fmt.Printf("✅ %s has no lint findings\n", configFile)
+			return nil
+		}
+
+		errors, warnings := 0, 0
+		for _, f := range report.Findings {
+			icon := "⚠️ "
+			if f.Severity == "error" {
+				icon = "❌"
+				errors++
+			} else {
+				warnings++
+			}
+			location := configFile
+			if f.Line > 0 {
+				location = fmt.Sprintf("%s:%d:%d", configFile, f.Line, f.Column)
+			}
+			fmt.Printf("%s %s: [%s/%s] %s\n", icon, location, f.Source, f.Rule, f.Message)
+			if f.Suggestion != "" {
+				fmt.Printf("    💡 %s\n", f.Suggestion)
+			}
+		}
+		fmt.Printf("\n%d error(s), %d warning(s)", errors, warnings)
+		if fixable := report.Fixable(); fixable > 0 {
+			fmt.Printf(", %d fixable with --fix", fixable)
+		}
+		fmt.Println()
+
+		if errors > 0 || (configLintStrict && warnings > 0) {
+			return fmt.Errorf("lint failed: %d error(s), %d warning(s)", errors, warnings)
+		}
+		return nil
+	},
+}
@@ -0,0 +1,157 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ready-to-release/eac/src/cli/internal/conf"
+	"github.com/ready-to-release/eac/src/cli/internal/configlint"
+	"github.com/ready-to-release/eac/src/cli/internal/validator"
+	"github.com/spf13/cobra"
+)
+
+// editorSchemaFile is where r2r config schema-header exports the schema,
+// relative to the repository root
+const editorSchemaFile = ".r2r/r2r-cli.schema.json"
+
+var (
+	configSchemaFormat string
+	configSchemaOutput string
+	configSchemaRef    string
+)
+
+func init() {
+	ConfigCmd.AddCommand(ConfigSchemaCmd, ConfigSchemaHeaderCmd)
+
+	ConfigSchemaCmd.Flags().StringVar(&configSchemaFormat, "format", "json", "Schema format: json (as embedded) or yaml-language-server (draft-07)")
+	ConfigSchemaCmd.Flags().StringVarP(&configSchemaOutput, "output", "o", "", "Write the schema to a file instead of stdout")
+	ConfigSchemaHeaderCmd.Flags().StringVar(&configSchemaRef, "schema", "", "Schema URL or path to reference instead of exporting "+editorSchemaFile)
+}
+
+var ConfigSchemaCmd = &cobra.Command{
+	Use:   "schema",
+	Short: "Print the JSON schema of r2r-cli.yml",
+	Long: `Print the JSON schema of r2r-cli.yml embedded in this build, the one
+'r2r validate' and 'r2r config lint' check against.
+
+--format yaml-language-server converts the schema to draft-07, the newest
+draft the YAML language server and the editors built on it support.`,
+	Example: `  r2r config schema
+  r2r config schema --format yaml-language-server -o r2r-cli.schema.json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		schema, err := exportSchema(configSchemaFormat)
+		if err != nil {
+			return err
+		}
+		if configSchemaOutput == "" {
+			fmt.Print(schema)
+			return nil
+		}
+		if err := os.WriteFile(configSchemaOutput, []byte(schema), 0o644); err != nil {
+			return fmt.Errorf("failed to write schema: %w", err)
+		}
+		fmt.Printf("✅ Wrote the r2r-cli.yml schema to %s\n", configSchemaOutput)
+		return nil
+	},
+}
+
+var ConfigSchemaHeaderCmd = &cobra.Command{
+	Use:   "schema-header [config-file...]",
+	Short: "Point r2r-cli.yml files to the schema for editor validation",
+	Long: `Write the '# yaml-language-server: $schema=' comment into configuration
+files, so editors using the YAML language server validate and complete them.
+An existing schema comment is replaced; without one it becomes the first line.
+
+Without --schema the schema is exported to ` + editorSchemaFile + ` in the
+repository root and referenced by a relative path; commit it along with the
+configuration. Without files the r2r-cli.yml of the repository is updated.`,
+	Example: `  r2r config schema-header
+  r2r config schema-header r2r-cli.yml r2r-cli.local.yml
+  r2r config schema-header --schema https://example.com/r2r-cli.schema.json`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		files := args
+		repoRoot, rootErr := conf.FindRepositoryRoot()
+		if len(files) == 0 {
+			if rootErr != nil {
+				return fmt.Errorf("no configuration file specified and could not find repository root: %w", rootErr)
+			}
+			files = []string{filepath.Join(repoRoot, "r2r-cli.yml")}
+		}
+
+		schemaPath := ""
+		if configSchemaRef == "" {
+			if rootErr != nil {
+				return fmt.Errorf("could not find repository root to export the schema, pass --schema: %w", rootErr)
+			}
+			schema, err := exportSchema("yaml-language-server")
+			if err != nil {
+				return err
+			}
+			schemaPath = filepath.Join(repoRoot, filepath.FromSlash(editorSchemaFile))
+			if err := os.MkdirAll(filepath.Dir(schemaPath), 0o755); err != nil {
+				return fmt.Errorf("failed to create %s: %w", filepath.Dir(schemaPath), err)
+			}
+			if err := os.WriteFile(schemaPath, []byte(schema), 0o644); err != nil {
+				return fmt.Errorf("failed to write schema: %w", err)
+			}
+			fmt.Printf("✅ Wrote the r2r-cli.yml schema to %s\n", schemaPath)
+		}
+
+		for _, file := range files {
+			ref := configSchemaRef
+			if ref == "" {
+				var err error
+				if ref, err = schemaRef(file, schemaPath); err != nil {
+					return err
+				}
+			}
+
+			data, err := os.ReadFile(file)
+			if err != nil {
+				return fmt.Errorf("failed to read configuration file: %w", err)
+			}
+			updated, changed := configlint.SetSchemaModeline(data, ref)
+			if !changed {
+				fmt.Printf("✅ %s already references %s\n", file, ref)
+				continue
+			}
+			info, err := os.Stat(file)
+			if err != nil {
+				return err
+			}
+			if err := os.WriteFile(file, updated, info.Mode().Perm()); err != nil {
+				return fmt.Errorf("failed to write %s: %w", file, err)
+			}
+			fmt.Printf("🔧 %s now references %s\n", file, ref)
+		}
+		return nil
+	},
+}
+
+// exportSchema returns the embedded r2r-cli.yml schema in format
+func exportSchema(format string) (string, error) {
+	switch format {
+	case "json":
+		return validator.GetEmbeddedSchema(), nil
+	case "yaml-language-server":
+		return validator.EditorSchema()
+	default:
+		return "", fmt.Errorf("unknown schema format %q: use json or yaml-language-server", format)
+	}
+}
+
+// schemaRef returns the path of schemaPath relative to the directory of
+// file, with forward slashes as the YAML language server expects
+func schemaRef(file, schemaPath string) (string, error) {
+	dir, err := filepath.Abs(filepath.Dir(file))
+	if err != nil {
+		return "", err
+	}
+	rel, err := filepath.Rel(dir, schemaPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to reference the schema from %s: %w", file, err)
+	}
+	return filepath.ToSlash(rel), nil
+}
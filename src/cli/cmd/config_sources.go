@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/ready-to-release/eac/src/cli/internal/conf"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	ConfigCmd.AddCommand(ConfigSourcesCmd)
+	ConfigSourcesCmd.Flags().Bool("json", false, "Print the sources as JSON")
+}
+
+// configSources is the JSON output of r2r config sources
+type configSources struct {
+	Files      []string          `json:"files"`
+	Extensions map[string]string `json:"extensions"`
+}
+
+var ConfigSourcesCmd = &cobra.Command{
+	Use:   "sources",
+	Short: "Show which configuration files are merged and where extensions come from",
+	Long: `Show the configuration files r2r merges in the current directory, lowest
+precedence first, and the file that defined or last changed each extension.
+
+Precedence, lowest first:
+  1. r2r-cli.yml in the repository root
+  2. r2r-cli.yml files in the directories between the repository root and
+     the current directory (CLI_ORIGINAL_PWD when set), outermost first
+  3. r2r-cli.local.yml, r2r-cli.personal.yml and r2r-cli.dev.yml in the
+     repository root
+
+Nested files extend the configuration: they add extensions, or change fields
+of extensions defined further up, so a subproject can scope its own
+extensions to its directory.`,
+	Example: `  r2r config sources
+  cd services/api && r2r config sources --json`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		asJSON, _ := cmd.Flags().GetBool("json")
+		cfg := conf.InitConfig()
+
+		sources := configSources{Files: cfg.Sources, Extensions: make(map[string]string, len(cfg.Extensions))}
+		for _, ext := range cfg.Extensions {
+			sources.Extensions[ext.Name] = ext.Source
+		}
+		if asJSON {
+			data, err := json.MarshalIndent(sources, "", "  ")
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: failed to encode sources: %v\n", err)
+				exitProcess(exitCodeError)
+			}
+			fmt.Println(string(data))
+			return
+		}
+
+		repoRoot, _ := conf.FindRepositoryRoot()
+		fmt.Println("Configuration files, lowest precedence first:")
+		for i, file := range cfg.Sources {
+			fmt.Printf("  %d. %s\n", i+1, relativeToRoot(repoRoot, file))
+		}
+		fmt.Println()
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "EXTENSION\tSOURCE")
+		for _, ext := range cfg.Extensions {
+			fmt.Fprintf(w, "%s\t%s\n", ext.Name, relativeToRoot(repoRoot, ext.Source))
+		}
+		w.Flush()
+	},
+}
+
+// relativeToRoot shortens a path inside the repository to a relative one
+func relativeToRoot(repoRoot, path string) string {
+	if repoRoot == "" {
+		return path
+	}
+	rel, err := filepath.Rel(repoRoot, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return path
+	}
+	return rel
+}
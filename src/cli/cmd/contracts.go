@@ -0,0 +1,21 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	RootCmd.AddCommand(ContractsCmd)
+}
+
+// ContractsCmd is the parent command for contract file management
+var ContractsCmd = &cobra.Command{
+	Use:   "contracts",
+	Short: "Inspect the embedded contract files and the module contracts",
+	Long:  `Inspect the contract files embedded in the CLI at build time, and compare the module contracts of the repository with its directory tree.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := cmd.Help(); err != nil {
+			log.WithField("error", err).Error().Msg("Failed to show help")
+		}
+	},
+}
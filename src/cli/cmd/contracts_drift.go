@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ready-to-release/eac/src/cli/internal/contractdrift"
+	"github.com/ready-to-release/eac/src/core/contracts/modules"
+	"github.com/ready-to-release/eac/src/core/repository"
+	"github.com/spf13/cobra"
+)
+
+var contractsDriftPatch string
+
+func init() {
+	ContractsCmd.AddCommand(ContractsDriftCmd)
+
+	ContractsDriftCmd.Flags().StringVar(&contractsDriftPatch, "patch", "", "Write the suggested contract updates as a patch to this file, or - for stdout")
+}
+
+var ContractsDriftCmd = &cobra.Command{
+	Use:   "drift",
+	Short: "Compare the directory tree with the module contracts",
+	Long: `Compare the tracked files of the repository with the module contracts in
+contracts/modules and the definitions.yml files, and report:
+
+  untracked-directory  a top-level directory no module owns, apart from the
+                       catch-all module (directories starting with a dot
+                       are skipped)
+  dead-module          a module none of whose include globs matches a file
+  dead-glob            an include glob matching no file
+  definition           a definitions.yml naming a module that does not own it
+
+With --patch, the suggested contract updates are written as a patch for git
+apply: dead globs and dead modules are removed and each untracked directory
+gets a new contract. Review it before applying; definition findings have no
+suggestion. The command fails when it finds drift.
+
+Example:
+  r2r contracts drift
+  r2r contracts drift --patch contracts.diff && git apply contracts.diff
+  r2r contracts drift --patch - | git apply --check`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		workspaceRoot, err := repository.GetRepositoryRoot("")
+		if err != nil {
+			return fmt.Errorf("failed to find repository root: %w", err)
+		}
+		registry, err := modules.LoadFromWorkspaceLatest(workspaceRoot)
+		if err != nil {
+			return fmt.Errorf("failed to load module contracts: %w", err)
+		}
+		findings, err := contractdrift.DetectWorkspace(workspaceRoot, registry)
+		if err != nil {
+			return err
+		}
+
+		// Keep stdout for the patch when it is written there
+		var out io.Writer = cmd.OutOrStdout()
+		if contractsDriftPatch == "-" {
+			out = cmd.ErrOrStderr()
+		}
+		for _, finding := range findings {
+			fmt.Fprintf(out, "❌ %s\n", finding)
+		}
+		if len(findings) == 0 {
+			fmt.Fprintf(out, "✅ %d module contract(s) match the directory tree\n", registry.Count())
+			return nil
+		}
+
+		if contractsDriftPatch != "" {
+			patch, err := contractdrift.Patch(workspaceRoot, registry, findings)
+			if err != nil {
+				return fmt.Errorf("failed to render patch: %w", err)
+			}
+			if contractsDriftPatch == "-" {
+				fmt.Fprint(cmd.OutOrStdout(), patch)
+			} else {
+				if err := os.WriteFile(contractsDriftPatch, []byte(patch), 0644); err != nil {
+					return fmt.Errorf("failed to write patch: %w", err)
+				}
+				fmt.Fprintf(out, "💡 Suggested contract updates written to %s; review and apply with: git apply %s\n", contractsDriftPatch, contractsDriftPatch)
+			}
+		}
+
+		return fmt.Errorf("%d drift finding(s) between the directory tree and the module contracts", len(findings))
+	},
+}
@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/ready-to-release/eac/contracts"
+	"github.com/spf13/cobra"
+)
+
+var contractsListCheck bool
+
+func init() {
+	ContractsCmd.AddCommand(ContractsListCmd)
+
+	ContractsListCmd.Flags().BoolVar(&contractsListCheck, "check", false, "Compare the embedded files with the contracts directory; exit non-zero when they differ")
+}
+
+var ContractsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the contract files embedded in this binary",
+	Long: `List the contract files embedded in this binary with their SHA-256 checksums.
+
+With --check, each embedded file is compared with the contracts directory of
+the current repository, to confirm the binary was built from the same
+contract versions.
+
+Example:
+  r2r contracts list
+  r2r contracts list --check`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		files, err := contracts.Files()
+		if err != nil {
+			return fmt.Errorf("failed to list embedded contracts: %w", err)
+		}
+
+		if !contractsListCheck {
+			fmt.Printf("📦 %d embedded contract file(s), CLI contracts %s\n", len(files), contracts.CLIVersion)
+			for _, file := range files {
+				fmt.Printf("  %s  %s (%d bytes)\n", file.SHA256[:12], file.Path, file.Size)
+			}
+			return nil
+		}
+
+		return checkEmbeddedContracts()
+	},
+}
@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/ready-to-release/eac/contracts"
+	"github.com/ready-to-release/eac/src/cli/internal/contractsync"
+	"github.com/ready-to-release/eac/src/core/repository"
+	"github.com/spf13/cobra"
+)
+
+var contractsSyncCheck bool
+
+func init() {
+	ContractsCmd.AddCommand(ContractsSyncCmd)
+
+	ContractsSyncCmd.Flags().BoolVar(&contractsSyncCheck, "check", false, "Verify the embedded files match their sources; exit non-zero when they do not")
+}
+
+var ContractsSyncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Check the embedded contract files against the contracts directory",
+	Long: `Compare the SHA-256 checksums of the contract files embedded in this binary
+with the contracts directory of the current repository.
+
+Contracts are embedded at build time with go:embed, so there are no copies
+to write: rebuild the CLI to pick up changed contracts. Use --check in CI to
+fail when the binary was built from other contract versions. The same check
+is available as r2r contracts list --check.
+
+Example:
+  r2r contracts sync --check`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !contractsSyncCheck {
+			return fmt.Errorf("contracts are embedded at build time and need no sync; rebuild the CLI, or compare with --check")
+		}
+		return checkEmbeddedContracts()
+	},
+}
+
+// checkEmbeddedContracts prints the state of every embedded contract file and
+// fails when one differs from the contracts directory
+func checkEmbeddedContracts() error {
+	workspaceRoot, err := repository.GetRepositoryRoot("")
+	if err != nil {
+		return fmt.Errorf("failed to find repository root: %w", err)
+	}
+
+	statuses, err := contractsync.Check(contracts.FS(), filepath.Join(workspaceRoot, "contracts"))
+	if err != nil {
+		return fmt.Errorf("failed to compare embedded contracts: %w", err)
+	}
+
+	differ := 0
+	for _, status := range statuses {
+		switch status.State {
+		case contractsync.StateInSync:
+			fmt.Printf("✅ contracts/%s\n", status.Path)
+		case contractsync.StateStale:
+			fmt.Printf("❌ contracts/%s changed since this binary was built\n", status.Path)
+			differ++
+		case contractsync.StateSourceMissing:
+			fmt.Printf("❌ contracts/%s no longer exists\n", status.Path)
+			differ++
+		}
+	}
+
+	if differ > 0 {
+		return fmt.Errorf("%d embedded contract file(s) differ from the contracts directory, rebuild the CLI", differ)
+	}
+	return nil
+}
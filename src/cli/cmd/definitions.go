@@ -37,13 +37,13 @@ func processDefinitions(cmd *cobra.Command) {
 		absPath, err := filepath.Abs(definitionsPath)
 		if err != nil {
 			log.Error().Err(err).Str("path", definitionsPath).Msg("Failed to resolve absolute path")
-			os.Exit(1)
+			exitProcess(1)
 		}
 
 		// Check if path exists
 		if _, err := os.Stat(absPath); os.IsNotExist(err) {
 			log.Error().Str("path", absPath).Msg("Path does not exist")
-			os.Exit(1)
+			exitProcess(1)
 		}
 
 		targetPath = absPath
@@ -53,7 +53,7 @@ func processDefinitions(cmd *cobra.Command) {
 		repoRoot, err := conf.FindRepositoryRoot()
 		if err != nil {
 			log.Error().Err(err).Msg("Failed to find repository root. Please run this command from within a git repository.")
-			os.Exit(1)
+			exitProcess(1)
 		}
 		targetPath = repoRoot
 		log.Debug().Str("path", targetPath).Msg("Using repository root")
@@ -64,13 +64,13 @@ func processDefinitions(cmd *cobra.Command) {
 	merged, err := definitions.ProcessDirectory(targetPath)
 	if err != nil {
 		log.Error().Err(err).Msg("Failed to process definition files")
-		os.Exit(1)
+		exitProcess(1)
 	}
 
 	yamlOutput, err := yaml.Marshal(merged)
 	if err != nil {
 		log.Error().Err(err).Msg("Failed to marshal merged YAML")
-		os.Exit(1)
+		exitProcess(1)
 	}
 
 	fmt.Fprint(cmd.OutOrStdout(), string(yamlOutput))
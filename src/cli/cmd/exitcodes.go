@@ -0,0 +1,15 @@
+package cmd
+
+// Exit codes of r2r. When an extension container runs to completion its own
+// exit status is passed through unchanged; these codes mark failures around
+// it. They sit below the shell's 126/127 and the 128+n signal codes so they
+// cannot be mistaken for them.
+const (
+	exitCodeError           = 1   // configuration or usage error
+	exitCodeImagePullFailed = 121 // the extension image could not be pulled
+	exitCodeCreateFailed    = 122 // the container could not be created
+	exitCodeOOMKilled       = 123 // the container was killed for running out of memory
+	exitCodeTimedOut        = 124 // the container was stopped for exceeding its timeout
+	exitCodeDockerError     = 125 // any other Docker failure (inspect, attach, start, wait)
+	exitCodeInterrupted     = 130 // interrupted by SIGINT or SIGTERM
+)
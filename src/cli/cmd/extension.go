@@ -0,0 +1,225 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ready-to-release/eac/src/cli/internal/conf"
+	"github.com/ready-to-release/eac/src/cli/internal/extensions"
+	"github.com/ready-to-release/eac/src/cli/internal/version"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	RootCmd.AddCommand(ExtensionCmd)
+	ExtensionCmd.AddCommand(ExtensionTestCmd)
+
+	ExtensionTestCmd.Flags().String("junit-out", "", "Copy the JUnit XML report to this path")
+}
+
+var ExtensionCmd = &cobra.Command{
+	Use:   "extension",
+	Short: "Develop and check extensions",
+}
+
+var ExtensionTestCmd = &cobra.Command{
+	Use:   "test <extension>",
+	Short: "Test an extension and check its metadata contract",
+	Long: `Test an extension image:
+
+  1. Validate the extension-meta output against the matching metadata schema
+  2. Check the CLI version range the extension declares
+  3. Run the declared test command inside the extension container
+  4. Summarize the JUnit XML report the tests write, if configured
+
+The test command is declared in the extension's test section. Without it, the
+extension's "test" command from its metadata is run, if it has one.
+
+Configuration:
+  extensions:
+    - name: pwsh
+      image: ghcr.io/ready-to-release/r2r-pwsh:latest
+      test:
+        command: ["Invoke-Pester", "-CI"]
+        junit: out/pwsh/testResults.xml   # Relative to the repository root
+
+Examples:
+  r2r extension test pwsh
+  r2r extension test pwsh --junit-out results.xml`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := conf.InitConfig()
+
+		extensionName := args[0]
+		confExt, found := cfg.FindExtension(extensionName)
+		if !found {
+			fmt.Printf("❌ Extension %s not found in configuration\n", extensionName)
+			exitProcess(exitCodeError)
+		}
+
+		repoRoot, err := conf.FindRepositoryRoot()
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to find repository root")
+			exitProcess(exitCodeError)
+		}
+
+		installer, err := extensions.NewInstaller(cfg)
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to create extension installer")
+			exitProcess(exitCodeDockerError)
+		}
+		defer installer.Close()
+		host := installer.GetContainerHost()
+
+		ext, err := host.FindExtension(extensionName)
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to find extension")
+			exitProcess(exitCodeError)
+		}
+
+		// Ctrl-C stops the running container
+		ctx, stop := interruptContext(context.Background())
+		defer stop()
+		exitIfInterrupted := func() {
+			if ctx.Err() != nil {
+				fmt.Println("\n⚠️  Extension test interrupted")
+				exitProcess(exitCodeInterrupted)
+			}
+		}
+
+		fmt.Printf("🧪 Testing %s (%s)\n\n", ext.Name, ext.Image)
+		failed := false
+
+		if _, err := installer.EnsureExtensionImage(ctx, ext.Name); err != nil {
+			exitIfInterrupted()
+			fmt.Printf("❌ Image: %v\n", err)
+			exitProcess(exitCodeImagePullFailed)
+		}
+
+		// Metadata contract
+		var meta *extensions.Metadata
+		output, err := host.ExecuteMetadataCommand(ctx, ext)
+		exitIfInterrupted()
+		if err == nil {
+			meta, err = extensions.ParseMetadata(output)
+		}
+		if err != nil {
+			fmt.Printf("❌ Metadata: %v\n", err)
+			failed = true
+		} else {
+			schemaVersion, err := meta.NegotiateSchema(ext.Name, ext.MetadataSchemaVersion)
+			var violations []string
+			if err == nil {
+				violations, err = meta.Validate(schemaVersion)
+			}
+			switch {
+			case err != nil:
+				fmt.Printf("❌ Metadata: %v\n", err)
+				failed = true
+			case len(violations) > 0:
+				fmt.Printf("❌ Metadata: %d violation(s) of schema %s\n", len(violations), schemaVersion)
+				for _, violation := range violations {
+					fmt.Printf("     - %s\n", violation)
+				}
+				failed = true
+			default:
+				fmt.Printf("✅ Metadata: %s %s matches schema %s (r2r supports %s)\n",
+					meta.Name, meta.Version, schemaVersion, extensions.SupportedSchemaRange())
+			}
+
+			compatibility := meta.CheckCLICompatibility(version.GetInfo().Version)
+			if compatibility.Compatible {
+				fmt.Printf("✅ CLI compatibility: %s\n", compatibility.Reason)
+			} else {
+				fmt.Printf("❌ CLI compatibility: %s\n", compatibility.Reason)
+				failed = true
+			}
+		}
+
+		// Test command
+		var testCommand []string
+		junitPath := ""
+		if confExt.Test != nil {
+			testCommand = confExt.Test.Command
+			if confExt.Test.JUnit != "" {
+				junitPath = filepath.Join(repoRoot, confExt.Test.JUnit)
+			}
+		} else if meta != nil {
+			if _, ok := meta.Commands["test"]; ok {
+				testCommand = []string{"test"}
+			}
+		}
+
+		if len(testCommand) == 0 {
+			fmt.Println("⚠️  Tests: no test command declared, add a test section to the extension")
+		} else {
+			if junitPath != "" {
+				// A report left from an earlier run must not be mistaken for this one
+				_ = os.Remove(junitPath)
+			}
+
+			fmt.Printf("\n▶️  %v\n", testCommand)
+			exit, err := host.RunToCompletion(ctx, ext, testCommand, os.Stdout, os.Stderr)
+			exitIfInterrupted()
+			fmt.Println()
+			switch {
+			case err != nil:
+				fmt.Printf("❌ Tests: %v\n", err)
+				failed = true
+			case exit.OOMKilled:
+				fmt.Println("❌ Tests: killed for running out of memory")
+				failed = true
+			case exit.StatusCode != 0:
+				fmt.Printf("❌ Tests: exited with code %d\n", exit.StatusCode)
+				failed = true
+			default:
+				fmt.Println("✅ Tests: passed")
+			}
+
+			if junitPath != "" && err == nil {
+				if !reportJUnit(cmd, junitPath) {
+					failed = true
+				}
+			}
+		}
+
+		if failed {
+			fmt.Printf("\n❌ %s failed its extension tests\n", ext.Name)
+			exitProcess(exitCodeError)
+		}
+		fmt.Printf("\n✅ %s passed its extension tests\n", ext.Name)
+	},
+}
+
+// reportJUnit summarizes the JUnit report at path and copies it to the
+// --junit-out destination. It returns false when the report is missing,
+// invalid or has failures.
+func reportJUnit(cmd *cobra.Command, path string) bool {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		fmt.Printf("❌ JUnit: report not written: %v\n", err)
+		return false
+	}
+
+	summary, err := extensions.ParseJUnitSummary(data)
+	if err != nil {
+		fmt.Printf("❌ JUnit: %v\n", err)
+		return false
+	}
+	icon := "✅"
+	if !summary.Passed() {
+		icon = "❌"
+	}
+	fmt.Printf("%s JUnit: %d tests, %d failures, %d errors, %d skipped\n",
+		icon, summary.Tests, summary.Failures, summary.Errors, summary.Skipped)
+
+	if out, _ := cmd.Flags().GetString("junit-out"); out != "" {
+		if err := os.WriteFile(out, data, 0644); err != nil {
+			fmt.Printf("❌ JUnit: failed to copy report: %v\n", err)
+			return false
+		}
+	}
+	return summary.Passed()
+}
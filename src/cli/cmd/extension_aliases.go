@@ -10,14 +10,14 @@ import (
 
 // CreateExtensionAliases creates direct command aliases for configured extensions
 // This allows users to run "r2r pwsh" instead of "r2r run pwsh"
-func CreateExtensionAliases() {
+func CreateExtensionAliases(cfg *conf.Config) {
 	// Only create aliases if config is loaded successfully
-	if len(conf.Global.Extensions) == 0 {
+	if len(cfg.Extensions) == 0 {
 		return
 	}
 
 	// Create an alias command for each configured extension
-	for _, ext := range conf.Global.Extensions {
+	for _, ext := range cfg.Extensions {
 		// Create a local copy to avoid closure issues
 		extension := ext
 
@@ -83,9 +83,9 @@ func InitializeExtensionAliases() {
 
 	if configFile != "" {
 		// Try to load the config
-		if err := conf.LoadConfig(configFile); err == nil {
+		if cfg, err := conf.Load(configFile); err == nil {
 			// Config loaded successfully, create aliases
-			CreateExtensionAliases()
+			CreateExtensionAliases(cfg)
 		} else {
 			log.Debug().Err(err).Str("config", configFile).Msg("Failed to load config for aliases")
 		}
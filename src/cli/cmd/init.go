@@ -36,14 +36,14 @@ func createConfigFile(cmd *cobra.Command) {
 		pwd, err := os.Getwd()
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error: Failed to get current working directory: %v\n", err)
-			os.Exit(1)
+			exitProcess(1)
 		}
 
 		gitPath := filepath.Join(pwd, ".git")
 		if _, err := os.Stat(gitPath); os.IsNotExist(err) {
 			if err := os.Mkdir(gitPath, 0755); err != nil {
 				fmt.Fprintf(os.Stderr, "Error: Failed to create .git directory: %v\n", err)
-				os.Exit(1)
+				exitProcess(1)
 			}
 			fmt.Fprintf(os.Stderr, "💡 Created .git folder to simulate repository root\n")
 		}
@@ -53,7 +53,7 @@ func createConfigFile(cmd *cobra.Command) {
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: Not a git repository. %v\n", err)
 		fmt.Fprintf(os.Stderr, "💡 To enable r2r-cli in non-git projects, use: r2r init --use-pwd-as-root\n")
-		os.Exit(1)
+		exitProcess(1)
 	}
 
 	// Check if --delete-configs flag is set
@@ -67,7 +67,7 @@ func createConfigFile(cmd *cobra.Command) {
 
 	if _, err := os.Stat(configFile); err == nil {
 		fmt.Fprintf(os.Stderr, "Error: r2r-cli.yml already exists in the repository root\n")
-		os.Exit(1)
+		exitProcess(1)
 	}
 
 	minimalConfig := `extensions:
@@ -78,7 +78,7 @@ func createConfigFile(cmd *cobra.Command) {
 	err = os.WriteFile(configFile, []byte(minimalConfig), 0644)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: Failed to create r2r-cli.yml: %v\n", err)
-		os.Exit(1)
+		exitProcess(1)
 	}
 
 	cmd.Printf("Created %s\n", configFile)
@@ -1,14 +1,16 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/ready-to-release/eac/src/cli/internal/conf"
 	"github.com/ready-to-release/eac/src/cli/internal/extensions"
-	"github.com/ready-to-release/eac/src/cli/internal/github"
+	"github.com/ready-to-release/eac/src/cli/internal/notify"
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v3"
 )
@@ -47,7 +49,7 @@ Examples:
 			// Add extension to config (will create config file if needed)
 			if err := addExtensionToConfig(extensionName); err != nil {
 				log.Error().Err(err).Msg("Failed to add extension to config")
-				os.Exit(1)
+				exitProcess(1)
 			}
 			fmt.Printf("✅ Added %s to configuration\n", extensionName)
 		} else {
@@ -55,7 +57,7 @@ Examples:
 			repoRoot, err := conf.FindRepositoryRoot()
 			if err != nil {
 				log.Error().Err(err).Msg("Failed to find repository root")
-				os.Exit(1)
+				exitProcess(1)
 			}
 
 			configPath := filepath.Join(repoRoot, "r2r-cli.yml")
@@ -68,33 +70,24 @@ Examples:
 				fmt.Println("\nExamples:")
 				fmt.Println("  r2r install pwsh")
 				fmt.Println("  r2r install python")
-				os.Exit(1)
+				exitProcess(1)
 			}
 		}
 
 		// Load configuration
-		conf.InitConfig()
-
-		// Check for --load-local flag and temporarily override global setting
-		loadLocal, _ := cmd.Flags().GetBool("load-local")
-		var originalLoadLocal bool
-		if loadLocal {
-			originalLoadLocal = conf.Global.LoadLocal
-			conf.Global.LoadLocal = true
-			log.Debug().Bool("load_local", true).Msg("Temporarily overriding load_local setting from --load-local flag")
+		cfg := conf.InitConfig()
+
+		// --load-local overrides the setting for this command only
+		if loadLocal, _ := cmd.Flags().GetBool("load-local"); loadLocal {
+			cfg.LoadLocal = true
+			log.Debug().Bool("load_local", true).Msg("Overriding load_local setting from --load-local flag")
 		}
-		defer func() {
-			if loadLocal {
-				conf.Global.LoadLocal = originalLoadLocal
-				log.Debug().Bool("load_local", originalLoadLocal).Msg("Restored original load_local setting")
-			}
-		}()
 
 		// Create extension installer
-		installer, err := extensions.NewInstaller()
+		installer, err := extensions.NewInstaller(cfg)
 		if err != nil {
 			log.Error().Err(err).Msg("Failed to create extension installer")
-			os.Exit(1)
+			exitProcess(1)
 		}
 		defer installer.Close()
 
@@ -106,7 +99,7 @@ Examples:
 
 			// First try to find in existing configuration
 			found := false
-			for _, ext := range conf.Global.Extensions {
+			for _, ext := range cfg.Extensions {
 				if ext.Name == extensionName {
 					extsToInstall = append(extsToInstall, ext)
 					found = true
@@ -116,29 +109,37 @@ Examples:
 
 			if !found {
 				fmt.Printf("❌ Extension %s not found in configuration\n", extensionName)
-				os.Exit(1)
+				exitProcess(1)
 			}
 		} else {
 			// Install all configured extensions
-			extsToInstall = conf.Global.Extensions
+			extsToInstall = cfg.Extensions
 			if len(extsToInstall) == 0 {
 				fmt.Println("❌ No extensions configured. Add an extension with:")
 				fmt.Println("  r2r install <extension-name>")
 				fmt.Println("\nExamples:")
 				fmt.Println("  r2r install pwsh")
 				fmt.Println("  r2r install python")
-				os.Exit(1)
+				exitProcess(1)
 			}
 		}
 
-		// Install the extensions
+		// Install the extensions; Ctrl-C aborts the running pull
+		ctx, stop := interruptContext(context.Background())
+		defer stop()
+
 		fmt.Printf("📦 Installing %d extension(s)...\n", len(extsToInstall))
+		started := time.Now()
 
 		successCount := 0
 		for _, ext := range extsToInstall {
 			fmt.Printf("\n🔧 Installing %s...\n", ext.Name)
 
-			pulled, err := installer.EnsureExtensionImage(ext.Name)
+			pulled, err := installer.EnsureExtensionImage(ctx, ext.Name)
+			if ctx.Err() != nil {
+				fmt.Println("\n⚠️  Installation interrupted")
+				exitProcess(exitCodeInterrupted)
+			}
 			if err != nil {
 				log.Error().Err(err).Str("extension", ext.Name).Msg("Failed to install extension")
 				fmt.Printf("❌ Failed to install %s: %v\n", ext.Name, err)
@@ -152,11 +153,22 @@ Examples:
 			}
 		}
 
+		names := make([]string, len(extsToInstall))
+		for i, ext := range extsToInstall {
+			names[i] = ext.Name
+		}
+		notify.New(cfg).Done(ctx, notify.Event{
+			Operation: "install",
+			Subject:   strings.Join(names, ", "),
+			Success:   successCount == len(extsToInstall),
+			Message:   fmt.Sprintf("%d of %d installed", successCount, len(extsToInstall)),
+		}, started)
+
 		if successCount == len(extsToInstall) {
 			fmt.Println("\n✅ All extensions installed successfully")
 		} else {
 			fmt.Printf("\n⚠️  %d of %d extensions installed successfully\n", successCount, len(extsToInstall))
-			os.Exit(1)
+			exitProcess(1)
 		}
 	},
 }
@@ -227,7 +239,7 @@ func addExtensionToConfig(extensionName string) error {
 		}
 
 		// Query registry to verify extension exists
-		client, err := github.NewRegistryClient()
+		client, err := conf.NewRegistryClient(conf.Current())
 		if err != nil {
 			return fmt.Errorf("failed to create registry client: %w", err)
 		}
@@ -2,10 +2,6 @@ package cmd
 
 import (
 	"context"
-	"os"
-	"os/exec"
-	"os/signal"
-	"syscall"
 	"time"
 
 	"github.com/ready-to-release/eac/src/cli/internal/conf"
@@ -24,20 +20,29 @@ var InteractiveCmd = &cobra.Command{
 	Long:  `Start an extension container in interactive mode with shell access.`,
 	Args:  cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
-		conf.InitConfig()
+		ctx := context.Background()
+		ctx = logger.ContextWithCommand(ctx, "interactive")
+		ctx = logger.ContextWithComponent(ctx, "docker")
+		log := logger.WithContext(ctx)
+
+		// Cancel all Docker operations on Ctrl-C
+		ctx, stop := interruptContext(ctx)
+		defer stop()
+
+		cfg := conf.InitConfig()
 
 		// Create container host
-		host, err := docker.NewContainerHost()
+		host, err := docker.NewContainerHostWithConfig(cfg)
 		if err != nil {
 			cmd.PrintErrln(err)
-			os.Exit(1)
+			exitProcess(1)
 		}
 		defer host.Close()
 
 		// Validate extensions
 		if err := host.ValidateExtensions(); err != nil {
 			cmd.PrintErrln(err)
-			os.Exit(1)
+			exitProcess(1)
 		}
 
 		cmd.Println("Root directory found:", host.GetRootDir())
@@ -47,99 +52,99 @@ var InteractiveCmd = &cobra.Command{
 		ext, err := host.FindExtension(extensionName)
 		if err != nil {
 			cmd.PrintErrln(err)
-			os.Exit(1)
+			exitProcess(1)
 		}
 		cmd.Println("Loading extension image:", ext.Image)
 
 		// Ensure image exists locally (pull if necessary)
-		if err := host.EnsureImageExists(ext.Image, ext.ImagePullPolicy, ext.LoadLocal); err != nil {
+		if err := host.EnsureImageExists(ctx, ext.Image, ext.ImagePullPolicy, ext.LoadLocal); err != nil {
 			cmd.PrintErrf("Error ensuring image exists: %v\n", err)
-			os.Exit(1)
+			exitProcess(1)
 		}
 
 		// Inspect image
-		imageInspect, err := host.InspectImage(ext.Image)
+		imageInspect, err := host.InspectImage(ctx, ext.Image)
 		if err != nil {
 			cmd.PrintErrln(err)
-			os.Exit(1)
+			exitProcess(1)
 		}
 
 		// Create container configuration
 		containerConfig := host.CreateContainerConfig(ext, docker.ModeInteractive, nil, imageInspect)
-		hostConfig := host.CreateHostConfig()
+		hostConfig := host.CreateHostConfig(ext)
 
-		// Create and start container
-		containerID, err := host.CreateContainer(containerConfig, hostConfig)
+		// Create the container and attach before starting it, so no output is lost
+		containerID, err := host.CreateContainer(ctx, containerConfig, hostConfig)
 		if err != nil {
 			cmd.PrintErrln(err)
-			os.Exit(1)
+			exitProcess(1)
 		}
 
-		if err := host.StartContainer(containerID); err != nil {
+		attachResp, err := host.AttachToContainer(ctx, containerID)
+		if err != nil {
+			cmd.PrintErrln(err)
+			host.ShutdownContainer(ctx, containerID)
+			exitProcess(1)
+		}
+		defer attachResp.Close()
+
+		statusCh, errCh := host.WaitForContainer(ctx, containerID)
+
+		started := time.Now()
+		if err := host.StartContainer(ctx, containerID); err != nil {
 			cmd.PrintErrln(err)
-			os.Exit(1)
+			host.ShutdownContainer(ctx, containerID)
+			exitProcess(1)
 		}
 
 		cmd.Printf("Starting interactive session for extension '%s'...\n", extensionName)
 		cmd.Println("Type 'exit' to quit the interactive session.")
 
-		// Set up signal handling for graceful shutdown
-		signalChan := make(chan os.Signal, 1)
-		signal.Notify(signalChan, syscall.SIGINT, syscall.SIGTERM)
+		// The session puts the terminal in raw mode, so Ctrl-C is sent to the
+		// container; SIGTERM still reaches r2r
+		session := host.NewSession(containerID, attachResp, docker.SessionOptions{
+			TTY:   containerConfig.Tty,
+			Stdin: containerConfig.OpenStdin,
+		})
+		if err := session.Start(ctx); err != nil {
+			cmd.PrintErrln(err)
+			host.ShutdownContainer(ctx, containerID)
+			exitProcess(1)
+		}
+		defer session.Close()
 
+		// Stop and remove the container on interrupt
 		go func() {
-			sig := <-signalChan
-
-			// Create context for logging
-			ctx := context.Background()
-			ctx = logger.ContextWithCommand(ctx, "interactive")
-			ctx = logger.ContextWithComponent(ctx, "docker")
-			log := logger.WithContext(ctx)
-
-			log.WithFields(map[string]interface{}{
-				"signal":       sig.String(),
-				"container_id": containerID,
-			}).Info().Msg("Received interrupt signal, stopping container gracefully")
-
-			// Try to stop the container gracefully with a timeout
-			stopCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-			defer cancel()
-
-			if err := host.StopContainerWithContext(stopCtx, containerID); err != nil {
-				log.Warn().Msgf("Failed to stop container gracefully: %v, forcing termination", err)
-				// Force stop if graceful stop failed
-				host.StopContainer(containerID)
+			<-ctx.Done()
+			stop()
+			session.Close()
+
+			log.WithField("container_id", containerID).Info().Msg("Received interrupt signal, stopping container gracefully")
+			if err := host.ShutdownContainer(ctx, containerID); err != nil {
+				log.Warn().Msgf("Failed to stop container: %v", err)
 			} else {
 				log.Info().Msg("Container stopped gracefully")
 			}
-			os.Exit(0)
+			exitProcess(0)
 		}()
 
-		// For containers with entrypoint, attach to the running container
-		// For containers without entrypoint, exec into the shell
-		if len(imageInspect.Config.Entrypoint) > 0 {
-			// Container has entrypoint - attach to it directly
-			execCmd := exec.Command("docker", "attach", containerID)
-			execCmd.Stdin = os.Stdin
-			execCmd.Stdout = os.Stdout
-			execCmd.Stderr = os.Stderr
-
-			if err := execCmd.Run(); err != nil {
-				cmd.PrintErrln("Error attaching to container:", err)
-				os.Exit(1)
-			}
-		} else {
-			// Container has no entrypoint - exec into shell
-			execCmd := exec.Command("docker", "exec", "-it", containerID, "/bin/sh")
-			execCmd.Stdin = os.Stdin
-			execCmd.Stdout = os.Stdout
-			execCmd.Stderr = os.Stderr
-
-			if err := execCmd.Run(); err != nil {
-				cmd.PrintErrln("Error running interactive session:", err)
-				os.Exit(1)
+		// The session ends when the shell or entrypoint exits
+		select {
+		case <-statusCh:
+		case err := <-errCh:
+			if err != nil && ctx.Err() == nil {
+				log.Debug().Err(err).Msg("Container wait ended")
 			}
 		}
+		select {
+		case <-session.Done():
+		case <-time.After(time.Second):
+		}
+		session.Close()
+
+		if err := host.FixOwnership(context.WithoutCancel(ctx), ext, started); err != nil {
+			log.Warn().Msgf("Failed to fix the ownership of created files: %v", err)
+		}
 
 		cmd.Println("Interactive session ended.")
 	},
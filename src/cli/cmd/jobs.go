@@ -0,0 +1,211 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ready-to-release/eac/src/cli/internal/conf"
+	"github.com/ready-to-release/eac/src/cli/internal/docker"
+	"github.com/ready-to-release/eac/src/cli/internal/extensions"
+	"github.com/ready-to-release/eac/src/cli/internal/jobs"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	RootCmd.AddCommand(JobsCmd)
+	JobsCmd.AddCommand(JobsSubmitCmd, JobsListCmd, JobsLogsCmd, JobsCancelCmd)
+
+	// Flags after the extension name belong to the extension command
+	JobsSubmitCmd.Flags().SetInterspersed(false)
+	JobsSubmitCmd.Flags().Bool("json", false, "Print the job as JSON")
+	JobsListCmd.Flags().Bool("json", false, "Print the jobs as a JSON array")
+	JobsLogsCmd.Flags().BoolP("follow", "f", false, "Stream the output until the job finishes")
+	JobsCancelCmd.Flags().Bool("json", false, "Print the cancelled job as JSON")
+}
+
+var JobsCmd = &cobra.Command{
+	Use:   "jobs",
+	Short: "Run extension commands in the background",
+	Long: `Run extension commands in the background and manage them.
+
+Jobs run in detached containers labeled with their job ID. Job state and the
+output of finished jobs are kept in .r2r/jobs, so jobs keep running when r2r
+exits and any later r2r process can list, follow or cancel them.
+
+Examples:
+  r2r jobs submit go test ./...
+  r2r jobs list
+  r2r jobs logs 20261016-120000-a1b2c3 --follow
+  r2r jobs cancel 20261016-120000-a1b2c3`,
+}
+
+var JobsSubmitCmd = &cobra.Command{
+	Use:   "submit <extension> [args...]",
+	Short: "Start an extension command as a background job",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := conf.InitConfig()
+		asJSON, _ := cmd.Flags().GetBool("json")
+
+		installer, err := extensions.NewInstaller(cfg)
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to create extension installer")
+			exitProcess(exitCodeDockerError)
+		}
+		defer installer.Close()
+		host := installer.GetContainerHost()
+
+		ext, err := host.FindExtension(args[0])
+		if err == nil {
+			err = extensions.CheckDeclaredSchema(ext.Name, ext.MetadataSchemaVersion)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			exitProcess(exitCodeError)
+		}
+
+		ctx, stop := interruptContext(context.Background())
+		defer stop()
+
+		if _, err := installer.EnsureExtensionImage(ctx, ext.Name); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Failed to prepare image for %s: %v\n", ext.Name, err)
+			exitProcess(exitCodeImagePullFailed)
+		}
+
+		manager := jobs.NewManager(jobs.NewStore(jobs.DefaultDir(host.GetRootDir())), host)
+		job, err := manager.Submit(ctx, ext, args[1:])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			exitProcess(exitCodeCreateFailed)
+		}
+
+		if asJSON {
+			printJobsJSON(job)
+			return
+		}
+		fmt.Printf("✅ Submitted job %s (%s)\n", job.ID, job.Extension)
+		fmt.Printf("   Follow its output with: r2r jobs logs %s --follow\n", job.ID)
+	},
+}
+
+var JobsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List background jobs",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		asJSON, _ := cmd.Flags().GetBool("json")
+		manager, closeHost := newJobManager()
+		defer closeHost()
+
+		list, err := manager.List(context.Background())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Failed to list jobs: %v\n", err)
+			exitProcess(exitCodeDockerError)
+		}
+
+		if asJSON {
+			if list == nil {
+				list = []*jobs.Job{}
+			}
+			printJobsJSON(list)
+			return
+		}
+		if len(list) == 0 {
+			fmt.Println("No jobs")
+			return
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tEXTENSION\tSTATUS\tEXIT\tSTARTED\tCOMMAND")
+		for _, job := range list {
+			exit := "-"
+			if job.ExitCode != nil {
+				exit = fmt.Sprint(*job.ExitCode)
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", job.ID, job.Extension, job.Status, exit,
+				job.CreatedAt.Local().Format(time.DateTime), strings.Join(job.Args, " "))
+		}
+		w.Flush()
+	},
+}
+
+var JobsLogsCmd = &cobra.Command{
+	Use:   "logs <job-id>",
+	Short: "Show the output of a background job",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		follow, _ := cmd.Flags().GetBool("follow")
+		manager, closeHost := newJobManager()
+		defer closeHost()
+
+		// Ctrl-C stops following; the job keeps running
+		ctx, stop := interruptContext(context.Background())
+		defer stop()
+
+		err := manager.Logs(ctx, args[0], follow, os.Stdout, os.Stderr)
+		if ctx.Err() != nil {
+			return
+		}
+		exitOnJobError(err)
+	},
+}
+
+var JobsCancelCmd = &cobra.Command{
+	Use:   "cancel <job-id>",
+	Short: "Stop a background job",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		asJSON, _ := cmd.Flags().GetBool("json")
+		manager, closeHost := newJobManager()
+		defer closeHost()
+
+		job, err := manager.Cancel(context.Background(), args[0])
+		exitOnJobError(err)
+
+		if asJSON {
+			printJobsJSON(job)
+			return
+		}
+		fmt.Printf("⏹️  Cancelled job %s (%s)\n", job.ID, job.Extension)
+	},
+}
+
+// newJobManager returns a job manager for the repository and a function that
+// closes its Docker connection
+func newJobManager() (*jobs.Manager, func()) {
+	cfg := conf.InitConfig()
+	host, err := docker.NewContainerHostWithConfig(cfg)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to create container host")
+		exitProcess(exitCodeDockerError)
+	}
+	manager := jobs.NewManager(jobs.NewStore(jobs.DefaultDir(host.GetRootDir())), host)
+	return manager, func() { host.Close() }
+}
+
+// exitOnJobError reports err and exits, if set
+func exitOnJobError(err error) {
+	if err == nil {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+	if errors.Is(err, jobs.ErrJobNotFound) {
+		fmt.Fprintln(os.Stderr, "   List jobs with: r2r jobs list")
+	}
+	exitProcess(exitCodeError)
+}
+
+func printJobsJSON(v interface{}) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to encode jobs: %v\n", err)
+		exitProcess(exitCodeError)
+	}
+	fmt.Println(string(data))
+}
@@ -9,7 +9,6 @@ import (
 
 	"github.com/ready-to-release/eac/src/cli/internal/cache"
 	"github.com/ready-to-release/eac/src/cli/internal/conf"
-	"github.com/ready-to-release/eac/src/cli/internal/github"
 	"github.com/spf13/cobra"
 )
 
@@ -47,7 +46,7 @@ Can optionally show all available tags for each extension.`,
 				err := registryCache.Save()
 				if err != nil {
 					fmt.Printf("❌ Failed to clear cache: %v\n", err)
-					os.Exit(1)
+					exitProcess(1)
 				}
 				fmt.Println("✅ Cache cleared successfully")
 			} else {
@@ -68,7 +67,7 @@ Can optionally show all available tags for each extension.`,
 			// Try to query registry if we have credentials
 			if os.Getenv("GITHUB_TOKEN") != "" && os.Getenv("GITHUB_USERNAME") != "" {
 				// We have credentials, try to discover extensions
-				client, err := github.NewRegistryClient()
+				client, err := conf.NewRegistryClient(conf.Current())
 				if err == nil {
 					extensions, err := client.ListExtensions()
 					if err == nil && len(extensions) > 0 {
@@ -88,7 +87,7 @@ Can optionally show all available tags for each extension.`,
 				fmt.Println("\nTo set credentials:")
 				fmt.Println("  export GITHUB_TOKEN=your_github_token")
 				fmt.Println("  export GITHUB_USERNAME=your_username")
-				os.Exit(1)
+				exitProcess(1)
 			}
 		} else {
 			// Use cached extension list
@@ -110,11 +109,11 @@ Can optionally show all available tags for each extension.`,
 		}
 
 		// Load configuration to get currently installed extensions
-		conf.InitConfig()
+		cfg := conf.InitConfig()
 
 		// Build a map of configured extensions for status checking
 		configuredExtensions := make(map[string]string)
-		for _, ext := range conf.Global.Extensions {
+		for _, ext := range cfg.Extensions {
 			configuredExtensions[ext.Name] = ext.Image
 		}
 
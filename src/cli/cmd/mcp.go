@@ -0,0 +1,21 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	RootCmd.AddCommand(McpCmd)
+}
+
+// McpCmd is the parent command for MCP server management
+var McpCmd = &cobra.Command{
+	Use:   "mcp",
+	Short: "Manage MCP servers for editors",
+	Long:  `Build the MCP servers in src/mcp and register them with editor MCP configurations.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := cmd.Help(); err != nil {
+			log.WithField("error", err).Error().Msg("Failed to show help")
+		}
+	},
+}
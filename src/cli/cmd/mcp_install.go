@@ -0,0 +1,427 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ready-to-release/eac/src/core/contracts"
+	toolregistry "github.com/ready-to-release/eac/src/core/contracts/mcp"
+	"github.com/ready-to-release/eac/src/core/repository"
+	"github.com/spf13/cobra"
+)
+
+// mcpToolsTimeout bounds the tools/list call of a built server; the commands
+// server compiles src/commands to list its tools
+const mcpToolsTimeout = 3 * time.Minute
+
+var (
+	mcpInstallTargets   []string
+	mcpInstallSkipBuild bool
+	mcpInstallDryRun    bool
+)
+
+func init() {
+	McpCmd.AddCommand(McpInstallCmd)
+
+	McpInstallCmd.Flags().StringSliceVarP(&mcpInstallTargets, "target", "t", []string{"vscode"}, "Editor configuration to update (vscode, claude-desktop, cursor, all)")
+	McpInstallCmd.Flags().BoolVar(&mcpInstallSkipBuild, "skip-build", false, "Do not compile the servers, only update configuration")
+	McpInstallCmd.Flags().BoolVarP(&mcpInstallDryRun, "dry-run", "n", false, "Show the resulting configuration without writing it")
+}
+
+var McpInstallCmd = &cobra.Command{
+	Use:   "install",
+	Short: "Register MCP servers in editor configuration",
+	Long: `Compile the MCP servers in src/mcp and register them in editor MCP configuration.
+
+Each server is registered as "eac-<name>" with the absolute path to its compiled
+binary and WORKSPACE_ROOT (plus DOCS_PATH for the docs server). Entries pointing
+into src/mcp for servers that no longer exist are removed. Other entries are kept.
+
+Tool names are checked against the namespace registry (contracts/mcp/tools.yml):
+every server needs a namespace, and the tools each built server lists must belong
+to its namespace and to no other server.
+
+Targets:
+  - vscode:         .vscode/settings.json (mcp.servers)
+  - cursor:         .cursor/mcp.json (mcpServers)
+  - claude-desktop: claude_desktop_config.json in the user config directory (mcpServers)
+
+Example:
+  r2r mcp install
+  r2r mcp install --target vscode,cursor
+  r2r mcp install --target all --dry-run`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		workspaceRoot, err := repository.GetRepositoryRoot("")
+		if err != nil {
+			return fmt.Errorf("failed to find repository root: %w", err)
+		}
+
+		targets, err := resolveMcpTargets(workspaceRoot, mcpInstallTargets)
+		if err != nil {
+			return err
+		}
+
+		servers, err := discoverMcpServers(workspaceRoot)
+		if err != nil {
+			return err
+		}
+		if len(servers) == 0 {
+			fmt.Println("No MCP servers found in src/mcp")
+			return nil
+		}
+
+		fmt.Println("🔌 Install MCP Servers")
+		fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+		fmt.Println("")
+
+		registry, err := toolregistry.Load(workspaceRoot)
+		if err != nil && !contracts.IsNotFound(err) {
+			return err
+		}
+		if registry == nil {
+			fmt.Printf("⚠️  No %s, tool names are not checked\n\n", toolregistry.ToolsFile)
+		}
+
+		if !mcpInstallSkipBuild && !mcpInstallDryRun {
+			for _, server := range servers {
+				fmt.Printf("🔨 Building %s\n", server.name)
+				if err := buildMcpServer(server); err != nil {
+					return err
+				}
+			}
+			fmt.Println("")
+		}
+
+		if registry != nil {
+			if err := checkMcpToolNames(registry, servers, !mcpInstallDryRun); err != nil {
+				return err
+			}
+		}
+
+		mcpRoot := filepath.Join(workspaceRoot, "src", "mcp")
+		for _, target := range targets {
+			content, removed, err := updateMcpConfig(target, servers, mcpRoot)
+			if err != nil {
+				return fmt.Errorf("failed to update %s configuration: %w", target.name, err)
+			}
+
+			if mcpInstallDryRun {
+				fmt.Printf("[DRY RUN] %s (%s):\n%s\n", target.name, target.path, content)
+				continue
+			}
+
+			if err := os.MkdirAll(filepath.Dir(target.path), 0755); err != nil {
+				return fmt.Errorf("failed to create directory for %s: %w", target.path, err)
+			}
+			if err := os.WriteFile(target.path, content, 0644); err != nil {
+				return fmt.Errorf("failed to write %s: %w", target.path, err)
+			}
+
+			fmt.Printf("✅ %s: %d server(s) registered\n", target.name, len(servers))
+			fmt.Printf("   File: %s\n", target.path)
+			for _, name := range removed {
+				fmt.Printf("   Removed stale entry: %s\n", name)
+			}
+		}
+
+		return nil
+	},
+}
+
+// mcpServer is an MCP server module found under src/mcp
+type mcpServer struct {
+	name   string // directory name, e.g. "commands"
+	dir    string // absolute module directory
+	binary string // absolute path to the compiled binary
+	env    map[string]string
+}
+
+// mcpTarget is an editor MCP configuration file
+type mcpTarget struct {
+	name string   // "vscode", "cursor", "claude-desktop"
+	path string   // absolute path of the configuration file
+	keys []string // JSON path to the servers object
+}
+
+// resolveMcpTargets maps --target values to configuration files
+func resolveMcpTargets(workspaceRoot string, names []string) ([]mcpTarget, error) {
+	var expanded []string
+	for _, name := range names {
+		if strings.ToLower(name) == "all" {
+			expanded = append(expanded, "vscode", "cursor", "claude-desktop")
+			continue
+		}
+		expanded = append(expanded, strings.ToLower(name))
+	}
+
+	seen := make(map[string]bool)
+	var targets []mcpTarget
+	for _, name := range expanded {
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		switch name {
+		case "vscode":
+			targets = append(targets, mcpTarget{
+				name: name,
+				path: filepath.Join(workspaceRoot, ".vscode", "settings.json"),
+				keys: []string{"mcp", "servers"},
+			})
+		case "cursor":
+			targets = append(targets, mcpTarget{
+				name: name,
+				path: filepath.Join(workspaceRoot, ".cursor", "mcp.json"),
+				keys: []string{"mcpServers"},
+			})
+		case "claude-desktop":
+			path, err := claudeDesktopConfigPath()
+			if err != nil {
+				return nil, err
+			}
+			targets = append(targets, mcpTarget{
+				name: name,
+				path: path,
+				keys: []string{"mcpServers"},
+			})
+		default:
+			return nil, fmt.Errorf("unsupported target: %s\nSupported: vscode, cursor, claude-desktop, all", name)
+		}
+	}
+
+	return targets, nil
+}
+
+// claudeDesktopConfigPath returns the platform-specific Claude Desktop config file
+func claudeDesktopConfigPath() (string, error) {
+	switch runtime.GOOS {
+	case "windows":
+		appData := os.Getenv("APPDATA")
+		if appData == "" {
+			return "", fmt.Errorf("APPDATA is not set")
+		}
+		return filepath.Join(appData, "Claude", "claude_desktop_config.json"), nil
+	case "darwin":
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to find home directory: %w", err)
+		}
+		return filepath.Join(home, "Library", "Application Support", "Claude", "claude_desktop_config.json"), nil
+	default:
+		configDir, err := os.UserConfigDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to find config directory: %w", err)
+		}
+		return filepath.Join(configDir, "Claude", "claude_desktop_config.json"), nil
+	}
+}
+
+// discoverMcpServers lists Go modules under src/mcp
+func discoverMcpServers(workspaceRoot string) ([]mcpServer, error) {
+	mcpRoot := filepath.Join(workspaceRoot, "src", "mcp")
+	entries, err := os.ReadDir(mcpRoot)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", mcpRoot, err)
+	}
+
+	binarySuffix := ""
+	if runtime.GOOS == "windows" {
+		binarySuffix = ".exe"
+	}
+
+	var servers []mcpServer
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		dir := filepath.Join(mcpRoot, entry.Name())
+		if _, err := os.Stat(filepath.Join(dir, "go.mod")); err != nil {
+			continue
+		}
+
+		env := map[string]string{
+			"WORKSPACE_ROOT": workspaceRoot,
+		}
+		if entry.Name() == "docs" {
+			env["DOCS_PATH"] = filepath.Join(workspaceRoot, "docs")
+		}
+
+		servers = append(servers, mcpServer{
+			name:   entry.Name(),
+			dir:    dir,
+			binary: filepath.Join(dir, "mcp-server-"+entry.Name()+binarySuffix),
+			env:    env,
+		})
+	}
+
+	return servers, nil
+}
+
+// buildMcpServer compiles a server next to its sources
+func buildMcpServer(server mcpServer) error {
+	cmd := exec.Command("go", "build", "-o", server.binary, ".")
+	cmd.Dir = server.dir
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to build %s: %w", server.name, err)
+	}
+	return nil
+}
+
+// checkMcpToolNames checks that every server has a namespace in the tool
+// registry and, with listTools, that the tools of the built servers do not
+// collide. Servers that are not built are skipped.
+func checkMcpToolNames(registry *toolregistry.Registry, servers []mcpServer, listTools bool) error {
+	advertised := make(map[string][]string, len(servers))
+	for _, server := range servers {
+		advertised[server.name] = nil
+		if !listTools {
+			continue
+		}
+		if _, err := os.Stat(server.binary); err != nil {
+			continue
+		}
+		tools, err := listMcpTools(server)
+		if err != nil {
+			return err
+		}
+		advertised[server.name] = tools
+	}
+
+	if problems := registry.Check(advertised); len(problems) > 0 {
+		return fmt.Errorf("tool names do not match %s:\n  - %s", toolregistry.ToolsFile, strings.Join(problems, "\n  - "))
+	}
+	if listTools {
+		fmt.Printf("✅ Tool names match %s\n\n", toolregistry.ToolsFile)
+	}
+	return nil
+}
+
+// listMcpTools starts a built server and returns the names from tools/list
+func listMcpTools(server mcpServer) ([]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), mcpToolsTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, server.binary)
+	cmd.Dir = server.dir
+	cmd.Env = os.Environ()
+	for name, value := range server.env {
+		cmd.Env = append(cmd.Env, name+"="+value)
+	}
+	cmd.Stdin = strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}` + "\n" +
+		`{"jsonrpc":"2.0","id":2,"method":"tools/list"}` + "\n")
+	var stdout bytes.Buffer
+	cmd.Stdout = &stdout
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("failed to list the tools of %s: %w", server.name, err)
+	}
+
+	scanner := bufio.NewScanner(&stdout)
+	scanner.Buffer(make([]byte, 0, 64*1024), 32*1024*1024)
+	for scanner.Scan() {
+		var response struct {
+			ID     interface{} `json:"id"`
+			Result struct {
+				Tools []struct {
+					Name string `json:"name"`
+				} `json:"tools"`
+			} `json:"result"`
+		}
+		if json.Unmarshal(scanner.Bytes(), &response) != nil || fmt.Sprint(response.ID) != "2" {
+			continue
+		}
+		names := make([]string, 0, len(response.Result.Tools))
+		for _, tool := range response.Result.Tools {
+			names = append(names, tool.Name)
+		}
+		return names, nil
+	}
+	return nil, fmt.Errorf("%s did not answer tools/list", server.name)
+}
+
+// updateMcpConfig merges the servers into the target configuration file.
+// Returns the new file content and the names of removed stale entries.
+func updateMcpConfig(target mcpTarget, servers []mcpServer, mcpRoot string) ([]byte, []string, error) {
+	config := make(map[string]interface{})
+
+	data, err := os.ReadFile(target.path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, nil, err
+	}
+	if len(strings.TrimSpace(string(data))) > 0 {
+		if err := json.Unmarshal(data, &config); err != nil {
+			return nil, nil, fmt.Errorf("cannot parse %s (comments are not supported): %w", target.path, err)
+		}
+	}
+
+	// Walk down to the servers object, creating it as needed
+	parent := config
+	for _, key := range target.keys {
+		child, ok := parent[key].(map[string]interface{})
+		if !ok {
+			child = make(map[string]interface{})
+			parent[key] = child
+		}
+		parent = child
+	}
+	entries := parent
+
+	current := make(map[string]bool)
+	for _, server := range servers {
+		name := "eac-" + server.name
+		current[name] = true
+
+		entry := map[string]interface{}{
+			"command": server.binary,
+			"args":    []string{},
+			"env":     server.env,
+		}
+		if target.name == "vscode" {
+			entry["type"] = "stdio"
+		}
+		entries[name] = entry
+	}
+
+	// Remove entries that point into src/mcp but are no longer provided
+	var removed []string
+	prefix := mcpRoot + string(filepath.Separator)
+	for name, value := range entries {
+		if current[name] {
+			continue
+		}
+		entry, ok := value.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		command, _ := entry["command"].(string)
+		if strings.HasPrefix(command, prefix) {
+			delete(entries, name)
+			removed = append(removed, name)
+		}
+	}
+	sort.Strings(removed)
+
+	content, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return append(content, '\n'), removed, nil
+}
@@ -0,0 +1,248 @@
+//go:build L0
+// +build L0
+
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestUpdateMcpConfig(t *testing.T) {
+	root := t.TempDir()
+	mcpRoot := filepath.Join(root, "src", "mcp")
+	servers := []mcpServer{{
+		name:   "commands",
+		binary: filepath.Join(mcpRoot, "commands", "mcp-server-commands"),
+		env:    map[string]string{"WORKSPACE_ROOT": root},
+	}}
+	// jsonPath quotes a path for JSON, escaping the separators on Windows
+	jsonPath := func(path string) string {
+		quoted, _ := json.Marshal(path)
+		return string(quoted)
+	}
+
+	tests := []struct {
+		name        string
+		target      mcpTarget
+		existing    string // file content, empty for no file
+		wantServers []string
+		wantRemoved []string
+		wantKeys    []string // top-level keys that must survive
+		wantErr     string
+	}{
+		{
+			name:        "new vscode settings",
+			target:      mcpTarget{name: "vscode", keys: []string{"mcp", "servers"}},
+			wantServers: []string{"eac-commands"},
+		},
+		{
+			name:   "user servers are kept",
+			target: mcpTarget{name: "cursor", keys: []string{"mcpServers"}},
+			existing: `{"mcpServers": {
+				"github": {"command": "npx", "args": ["@modelcontextprotocol/server-github"]},
+				"local": {"command": "/usr/local/bin/mcp-local"}
+			}}`,
+			wantServers: []string{"eac-commands", "github", "local"},
+		},
+		{
+			name:   "stale r2r entries are removed",
+			target: mcpTarget{name: "cursor", keys: []string{"mcpServers"}},
+			existing: `{"mcpServers": {
+				"eac-docs": {"command": ` + jsonPath(filepath.Join(mcpRoot, "docs", "mcp-server-docs")) + `},
+				"renamed": {"command": ` + jsonPath(filepath.Join(mcpRoot, "old", "mcp-server-old")) + `},
+				"github": {"command": "npx"}
+			}}`,
+			wantServers: []string{"eac-commands", "github"},
+			wantRemoved: []string{"eac-docs", "renamed"},
+		},
+		{
+			name:   "entries outside src/mcp are kept whatever their name",
+			target: mcpTarget{name: "cursor", keys: []string{"mcpServers"}},
+			existing: `{"mcpServers": {
+				"eac-custom": {"command": "/opt/eac/mcp-server-custom"},
+				"sibling": {"command": ` + jsonPath(filepath.Join(mcpRoot+"-tools", "x")) + `},
+				"url": {"url": "https://example.com/mcp"},
+				"disabled": false
+			}}`,
+			wantServers: []string{"disabled", "eac-commands", "eac-custom", "sibling", "url"},
+		},
+		{
+			name:   "other settings are kept",
+			target: mcpTarget{name: "vscode", keys: []string{"mcp", "servers"}},
+			existing: `{"editor.tabSize": 4, "mcp": {"inputs": [], "servers": {
+				"eac-commands": {"command": "old", "env": {"WORKSPACE_ROOT": "/elsewhere"}}
+			}}}`,
+			wantServers: []string{"eac-commands"},
+			wantKeys:    []string{"editor.tabSize", "mcp"},
+		},
+		{
+			name:     "comments are not supported",
+			target:   mcpTarget{name: "vscode", keys: []string{"mcp", "servers"}},
+			existing: "{\n  // settings\n}",
+			wantErr:  "comments are not supported",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.target.path = filepath.Join(t.TempDir(), "config.json")
+			if tt.existing != "" {
+				if err := os.WriteFile(tt.target.path, []byte(tt.existing), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			content, removed, err := updateMcpConfig(tt.target, servers, mcpRoot)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("updateMcpConfig() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("updateMcpConfig() error = %v", err)
+			}
+			if !reflect.DeepEqual(removed, tt.wantRemoved) {
+				t.Errorf("removed = %v, want %v", removed, tt.wantRemoved)
+			}
+
+			var config map[string]interface{}
+			if err := json.Unmarshal(content, &config); err != nil {
+				t.Fatalf("invalid JSON %s: %v", content, err)
+			}
+			for _, key := range tt.wantKeys {
+				if _, ok := config[key]; !ok {
+					t.Errorf("key %q was dropped", key)
+				}
+			}
+			entries := config
+			for _, key := range tt.target.keys {
+				entries, _ = entries[key].(map[string]interface{})
+			}
+			var names []string
+			for name := range entries {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			if !reflect.DeepEqual(names, tt.wantServers) {
+				t.Errorf("servers = %v, want %v", names, tt.wantServers)
+			}
+
+			// The r2r entry is replaced, not merged with the old one
+			entry, _ := entries["eac-commands"].(map[string]interface{})
+			env, _ := entry["env"].(map[string]interface{})
+			if entry["command"] != servers[0].binary || env["WORKSPACE_ROOT"] != root {
+				t.Errorf("eac-commands = %v", entry)
+			}
+			if _, ok := entry["type"]; ok != (tt.target.name == "vscode") {
+				t.Errorf("eac-commands = %v, want type stdio only for vscode", entry)
+			}
+		})
+	}
+}
+
+func TestResolveMcpTargets(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv("APPDATA", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	tests := []struct {
+		name    string
+		targets []string
+		want    []string
+		wantErr bool
+	}{
+		{name: "default", targets: []string{"vscode"}, want: []string{"vscode"}},
+		{name: "all", targets: []string{"all"}, want: []string{"vscode", "cursor", "claude-desktop"}},
+		{name: "duplicates and case", targets: []string{"Cursor", "all", "cursor"}, want: []string{"cursor", "vscode", "claude-desktop"}},
+		{name: "unsupported", targets: []string{"vscode", "emacs"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			targets, err := resolveMcpTargets(root, tt.targets)
+			if tt.wantErr {
+				if err == nil || !strings.Contains(err.Error(), "unsupported target") {
+					t.Fatalf("resolveMcpTargets(%v) error = %v, want unsupported target", tt.targets, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			var names []string
+			for _, target := range targets {
+				names = append(names, target.name)
+			}
+			if !reflect.DeepEqual(names, tt.want) {
+				t.Errorf("resolveMcpTargets(%v) = %v, want %v", tt.targets, names, tt.want)
+			}
+		})
+	}
+
+	targets, _ := resolveMcpTargets(root, []string{"all"})
+	wantPaths := map[string]string{
+		"vscode": filepath.Join(root, ".vscode", "settings.json"),
+		"cursor": filepath.Join(root, ".cursor", "mcp.json"),
+	}
+	for _, target := range targets {
+		if want, ok := wantPaths[target.name]; ok && target.path != want {
+			t.Errorf("%s path = %s, want %s", target.name, target.path, want)
+		}
+		if target.name == "claude-desktop" && filepath.Base(target.path) != "claude_desktop_config.json" {
+			t.Errorf("claude-desktop path = %s", target.path)
+		}
+	}
+}
+
+func TestDiscoverMcpServers(t *testing.T) {
+	root := t.TempDir()
+	mcpRoot := filepath.Join(root, "src", "mcp")
+	for _, dir := range []string{"commands", "docs", "notes"} {
+		if err := os.MkdirAll(filepath.Join(mcpRoot, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, file := range []string{"commands/go.mod", "docs/go.mod", "README.md"} {
+		if err := os.WriteFile(filepath.Join(mcpRoot, filepath.FromSlash(file)), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	servers, err := discoverMcpServers(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// notes has no go.mod and README.md is not a directory
+	if len(servers) != 2 || servers[0].name != "commands" || servers[1].name != "docs" {
+		t.Fatalf("servers = %+v, want commands and docs", servers)
+	}
+
+	suffix := ""
+	if runtime.GOOS == "windows" {
+		suffix = ".exe"
+	}
+	commands := servers[0]
+	if commands.dir != filepath.Join(mcpRoot, "commands") || commands.binary != filepath.Join(mcpRoot, "commands", "mcp-server-commands"+suffix) {
+		t.Errorf("commands = %+v", commands)
+	}
+	if !reflect.DeepEqual(commands.env, map[string]string{"WORKSPACE_ROOT": root}) {
+		t.Errorf("commands env = %v", commands.env)
+	}
+	if servers[1].env["DOCS_PATH"] != filepath.Join(root, "docs") {
+		t.Errorf("docs env = %v, want DOCS_PATH", servers[1].env)
+	}
+
+	// A workspace without src/mcp has no servers
+	servers, err = discoverMcpServers(t.TempDir())
+	if err != nil || servers != nil {
+		t.Errorf("discoverMcpServers without src/mcp = %+v, %v", servers, err)
+	}
+}
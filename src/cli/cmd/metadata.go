@@ -1,11 +1,13 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
-	"os"
 
 	"github.com/ready-to-release/eac/src/cli/internal/conf"
 	"github.com/ready-to-release/eac/src/cli/internal/docker"
+	"github.com/ready-to-release/eac/src/cli/internal/extensions"
+	"github.com/ready-to-release/eac/src/cli/internal/progress"
 	"github.com/spf13/cobra"
 )
 
@@ -16,23 +18,28 @@ func init() {
 var MetadataCmd = &cobra.Command{
 	Use:   "metadata <extension>",
 	Short: "Retrieve metadata from an extension",
-	Long:  `Retrieve metadata from an extension by executing its extension-meta command.`,
-	Args:  cobra.ExactArgs(1),
+	Long: `Retrieve metadata from an extension by executing its extension-meta command.
+
+The output is validated against the embedded metadata schema matching its
+schema-version. The command fails with an upgrade hint when the extension
+uses a schema version this r2r does not support, or one outside the
+metadata_schema_version declared in configuration.`,
+	Args: cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
-		conf.InitConfig()
+		cfg := conf.InitConfig()
 
 		// Create container host
-		host, err := docker.NewContainerHost()
+		host, err := docker.NewContainerHostWithConfig(cfg)
 		if err != nil {
 			cmd.PrintErrf("Error creating container host: %v\n", err)
-			os.Exit(1)
+			exitProcess(1)
 		}
 		defer host.Close()
 
 		// Validate extensions
 		if err := host.ValidateExtensions(); err != nil {
 			cmd.PrintErrf("Error: %v\n", err)
-			os.Exit(1)
+			exitProcess(1)
 		}
 
 		// Find extension
@@ -40,17 +47,55 @@ var MetadataCmd = &cobra.Command{
 		ext, err := host.FindExtension(extensionName)
 		if err != nil {
 			cmd.PrintErrf("Error: %v\n", err)
-			os.Exit(1)
+			exitProcess(1)
+		}
+
+		// Fail before starting a container when the declared schema is unsupported
+		if err := extensions.CheckDeclaredSchema(ext.Name, ext.MetadataSchemaVersion); err != nil {
+			cmd.PrintErrf("Error: %v\n", err)
+			exitProcess(1)
 		}
 
 		cmd.PrintErrln("Retrieving metadata from extension:", ext.Name)
 		cmd.PrintErrln("Image:", ext.Image)
 
 		// Execute metadata command
-		output, err := host.ExecuteMetadataCommand(ext)
+		ctx, stop := interruptContext(context.Background())
+		defer stop()
+
+		retrieving := progress.Spinner("Retrieving metadata from " + ext.Name)
+		output, err := host.ExecuteMetadataCommand(ctx, ext)
+		retrieving.Done("")
 		if err != nil {
+			if ctx.Err() != nil {
+				exitProcess(exitCodeInterrupted)
+			}
 			cmd.PrintErrf("Error retrieving metadata: %v\n", err)
-			os.Exit(1)
+			exitProcess(1)
+		}
+
+		// Negotiate the schema version and validate the output against it
+		meta, err := extensions.ParseMetadata(output)
+		if err != nil {
+			cmd.PrintErrf("Error: %v\n", err)
+			exitProcess(1)
+		}
+		schemaVersion, err := meta.NegotiateSchema(ext.Name, ext.MetadataSchemaVersion)
+		if err != nil {
+			cmd.PrintErrf("Error: %v\n", err)
+			exitProcess(1)
+		}
+		violations, err := meta.Validate(schemaVersion)
+		if err != nil {
+			cmd.PrintErrf("Error validating metadata: %v\n", err)
+			exitProcess(1)
+		}
+		if len(violations) > 0 {
+			cmd.PrintErrf("Error: metadata of %s does not match schema %s:\n", ext.Name, schemaVersion)
+			for _, violation := range violations {
+				cmd.PrintErrf("  - %s\n", violation)
+			}
+			exitProcess(1)
 		}
 
 		// Output the metadata to stdout
@@ -0,0 +1,21 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	RootCmd.AddCommand(ModulesCmd)
+}
+
+// ModulesCmd is the parent command for the module contracts of the repository
+var ModulesCmd = &cobra.Command{
+	Use:   "modules",
+	Short: "Work with the module contracts of the repository",
+	Long:  `Work with the module contracts in contracts/modules, which declare the deployable units of the repository and their source files.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := cmd.Help(); err != nil {
+			log.WithField("error", err).Error().Msg("Failed to show help")
+		}
+	},
+}
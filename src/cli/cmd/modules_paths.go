@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ready-to-release/eac/src/cli/internal/pathfilter"
+	"github.com/ready-to-release/eac/src/core/contracts/modules"
+	"github.com/ready-to-release/eac/src/core/repository"
+	"github.com/spf13/cobra"
+)
+
+var (
+	modulesPathsFormat string
+	modulesPathsCheck  []string
+)
+
+func init() {
+	ModulesCmd.AddCommand(ModulesPathsCmd)
+
+	ModulesPathsCmd.Flags().StringVar(&modulesPathsFormat, "format", pathfilter.FormatGHA, "Output format: gha (paths: blocks) or dorny (dorny/paths-filter filters)")
+	ModulesPathsCmd.Flags().StringArrayVar(&modulesPathsCheck, "check", nil, "Check the filters pasted into a workflow or filters file against the contracts; repeatable")
+}
+
+var ModulesPathsCmd = &cobra.Command{
+	Use:   "paths [moniker...]",
+	Short: "Render the source globs of deployable units as GitHub Actions path filters",
+	Long: `Render the source globs of each deployable unit in contracts/modules as a
+GitHub Actions path filter, ready to paste into a workflow.
+
+--format gha prints a paths: block per unit for on.push and on.pull_request;
+--format dorny prints a dorny/paths-filter filters config. Each filter starts
+with a "# r2r modules paths <moniker>" comment. Keep it when pasting: with
+--check, the filters after these comments are compared with the contracts,
+and the command fails when a unit's globs changed.
+
+Example:
+  r2r modules paths src-cli
+  r2r modules paths --format dorny > .github/filters.yml
+  r2r modules paths --check .github/workflows/cli.yml --check .github/filters.yml`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if modulesPathsFormat != pathfilter.FormatGHA && modulesPathsFormat != pathfilter.FormatDorny {
+			return fmt.Errorf("--format must be %s or %s", pathfilter.FormatGHA, pathfilter.FormatDorny)
+		}
+
+		if len(modulesPathsCheck) > 0 && len(args) > 0 {
+			return fmt.Errorf("--check compares every marked filter with its contract; it takes no monikers")
+		}
+
+		workspaceRoot, err := repository.GetRepositoryRoot("")
+		if err != nil {
+			return fmt.Errorf("failed to find repository root: %w", err)
+		}
+		registry, err := modules.LoadFromWorkspaceLatest(workspaceRoot)
+		if err != nil {
+			return fmt.Errorf("failed to load module contracts: %w", err)
+		}
+		units, err := pathfilter.Units(registry, args)
+		if err != nil {
+			return err
+		}
+
+		if len(modulesPathsCheck) == 0 {
+			return pathfilter.Render(os.Stdout, units, modulesPathsFormat)
+		}
+		return checkPathFilters(modulesPathsCheck, units)
+	},
+}
+
+// checkPathFilters reports the filters of each file that differ from the
+// contracts; a file without filters is an error, as its markers were lost
+func checkPathFilters(files []string, units []pathfilter.Unit) error {
+	drifted := 0
+	for _, file := range files {
+		f, err := os.Open(file)
+		if err != nil {
+			return err
+		}
+		filters, err := pathfilter.Parse(f)
+		f.Close()
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", file, err)
+		}
+		if len(filters) == 0 {
+			fmt.Printf("❌ %s: no filters marked with %q\n", file, pathfilter.MarkerPrefix+"<moniker>")
+			drifted++
+			continue
+		}
+
+		drifts := pathfilter.Check(filters, units)
+		for _, drift := range drifts {
+			fmt.Printf("❌ %s:%d: %s\n", file, drift.Line, drift)
+		}
+		if len(drifts) == 0 {
+			fmt.Printf("✅ %s: %d filter(s) match the contracts\n", file, len(filters))
+		}
+		drifted += len(drifts)
+	}
+
+	if drifted > 0 {
+		return fmt.Errorf("%d path filter(s) out of sync with the module contracts, regenerate them with r2r modules paths", drifted)
+	}
+	return nil
+}
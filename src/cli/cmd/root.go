@@ -7,8 +7,11 @@ import (
 	"runtime"
 	"strings"
 
+	"github.com/ready-to-release/eac/src/cli/internal/argv"
 	"github.com/ready-to-release/eac/src/cli/internal/logger"
+	"github.com/ready-to-release/eac/src/cli/internal/progress"
 	"github.com/ready-to-release/eac/src/cli/internal/version"
+	"github.com/ready-to-release/eac/src/core/redact"
 	"github.com/spf13/cobra"
 )
 
@@ -64,6 +67,10 @@ var RootCmd = &cobra.Command{
 			logLevel = "error"
 		}
 
+		if quiet {
+			progress.SetMode(progress.ModeOff)
+		}
+
 		// Set the log level
 		if err := log.SetLevel(logLevel); err != nil {
 			return fmt.Errorf("failed to set log level: %w", err)
@@ -82,7 +89,7 @@ var RootCmd = &cobra.Command{
 				"original_args": os.Getenv("R2R_ORIGINAL_ARGS"),
 				"filtered_args": os.Getenv("R2R_FILTERED_ARGS"),
 				"fix_applied":   "redirect_pollution",
-			}).Warn().Msg("Fixed bash redirect pollution in arguments (removed spurious '2' from '2>&1')")
+			}).Warn().Msgf("Removed a trailing '2>&1' passed as arguments; put '--' before arguments that must be kept or set %s=true", argv.DisableEnv)
 
 			// Clean up env vars
 			os.Unsetenv("R2R_FIXED_REDIRECT")
@@ -125,7 +132,7 @@ func init() {
 	// Initialize logger
 	if err := logger.Initialize(logCfg); err != nil {
 		fmt.Fprintf(os.Stderr, "Failed to initialize logger: %v\n", err)
-		os.Exit(1)
+		exitProcess(1)
 	}
 	log = logger.Get()
 
@@ -140,6 +147,8 @@ func init() {
 	}
 
 	RootCmd.CompletionOptions.DisableDefaultCmd = true
+	// Cobra prints command errors itself, which may carry tokens
+	RootCmd.SetErr(redact.NewWriter(os.Stderr))
 
 	// Keep normal help functionality for most commands
 	// Only the run command will disable help flags since it needs to pass them through
@@ -159,7 +168,7 @@ func init() {
 
 func buildErrorContext(err error) map[string]interface{} {
 	fields := map[string]interface{}{
-		"error":   err.Error(),
+		"error":   redact.String(err.Error()),
 		"command": RootCmd.Name(),
 		"args":    os.Args[1:],
 		"version": version.Version,
@@ -189,16 +198,21 @@ func buildErrorContext(err error) map[string]interface{} {
 }
 
 func Execute() {
+	startCommandAudit()
+
 	// Check if --version flag is passed as first argument
 	if len(os.Args) > 1 && os.Args[1] == "--version" {
 		// Execute the version command instead
 		versionCmd.Run(versionCmd, []string{})
+		finishCommandAudit(0, nil)
 		return
 	}
 
 	if err := RootCmd.Execute(); err != nil {
 		fields := buildErrorContext(err)
 		log.WithFields(fields).Error().Msg("Command execution failed")
+		finishCommandAudit(1, err)
 		os.Exit(1)
 	}
+	finishCommandAudit(0, nil)
 }
@@ -2,18 +2,20 @@ package cmd
 
 import (
 	"context"
+	"fmt"
 	"io"
 	"os"
-	"os/signal"
 	"strings"
-	"syscall"
 	"time"
 
-	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/ready-to-release/eac/src/cli/internal/argv"
 	"github.com/ready-to-release/eac/src/cli/internal/conf"
 	"github.com/ready-to-release/eac/src/cli/internal/docker"
+	"github.com/ready-to-release/eac/src/cli/internal/events"
 	"github.com/ready-to-release/eac/src/cli/internal/extensions"
+	"github.com/ready-to-release/eac/src/cli/internal/hooks"
 	"github.com/ready-to-release/eac/src/cli/internal/logger"
+	"github.com/ready-to-release/eac/src/cli/internal/terminal"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 )
@@ -28,12 +30,18 @@ func init() {
 		cmd.Printf("Run an extension using its configured Docker image.\n\n")
 		cmd.Printf("Usage:\n  %s\n\n", cmd.UseLine())
 
+		// Colors degrade to plain text when the terminal does not show them
+		caps := terminal.Detect()
+		reset := caps.Escape("\033[0m")
+		warn := caps.Escape("\033[1;33m")
+		dim := caps.Escape("\033[0;37m")
+
 		// Try to load configuration and show available extensions
-		cmd.Printf("\n\033[1;36mAvailable Extensions:\033[0m\n")
+		cmd.Printf("\n%sAvailable Extensions:%s\n", caps.Escape("\033[1;36m"), reset)
 		// Initialize configuration safely (handle errors gracefully)
 		defer func() {
 			if r := recover(); r != nil {
-				cmd.Printf("  \033[1;33m⚠️  Unable to load configuration - run 'r2r init' to initialize\033[0m\n")
+				cmd.Printf("  %s⚠️  Unable to load configuration - run 'r2r init' to initialize%s\n", warn, reset)
 			}
 		}()
 
@@ -43,28 +51,28 @@ func init() {
 		defer log.SetLevel(originalLevel.String())
 
 		// Try to load config
-		conf.InitConfig()
+		cfg := conf.InitConfig()
 
-		if len(conf.Global.Extensions) == 0 {
-			cmd.Printf("  \033[1;33m⚠️  No extensions configured - check your r2r-cli.yml\033[0m\n")
+		if len(cfg.Extensions) == 0 {
+			cmd.Printf("  %s⚠️  No extensions configured - check your r2r-cli.yml%s\n", warn, reset)
 		} else {
 			// Create container host for metadata extraction
-			host, err := docker.NewContainerHost()
+			host, err := docker.NewContainerHostWithConfig(cfg)
 			if err != nil {
 				// Fallback to basic display if Docker is unavailable
-				for _, ext := range conf.Global.Extensions {
+				for _, ext := range cfg.Extensions {
 					description := ext.Description
 					if description == "" {
 						description = "No description available"
 					}
 					icon := getExtensionIcon(ext.Name)
-					nameColor := getExtensionNameColor(ext.Name)
-					cmd.Printf("  %s  %s%-13s\033[0m  \033[0;37m%s\033[0m\n", icon, nameColor, ext.Name, description)
+					nameColor := caps.Escape(getExtensionNameColor(ext.Name))
+					cmd.Printf("  %s  %s%-13s%s  %s%s%s\n", icon, nameColor, ext.Name, reset, dim, description, reset)
 				}
 			} else {
 				defer host.Close()
 
-				for _, ext := range conf.Global.Extensions {
+				for _, ext := range cfg.Extensions {
 					description := ext.Description
 
 					// If no description in config, try to get it from extension metadata
@@ -76,12 +84,43 @@ func init() {
 						}
 					}
 					icon := getExtensionIcon(ext.Name)
-					nameColor := getExtensionNameColor(ext.Name)
-					cmd.Printf("  %s  %s%-13s\033[0m  \033[0;37m%s\033[0m\n", icon, nameColor, ext.Name, description)
+					nameColor := caps.Escape(getExtensionNameColor(ext.Name))
+					cmd.Printf("  %s  %s%-13s%s  %s%s%s\n", icon, nameColor, ext.Name, reset, dim, description, reset)
 				}
 			}
 		}
 
+		cmd.Printf("\nRun Options (before the extension name):\n")
+		cmd.Printf("      --json              Print the result as a JSON line on stdout when the run ends\n")
+		cmd.Printf("      --quiet             Do not show the extension output\n")
+		cmd.Printf("      --no-ansi           Strip all ANSI escape sequences from the extension output\n")
+		cmd.Printf("      --log-file <path>   Also write the raw extension output to a file\n")
+		cmd.Printf("      --timeout <dur>     Stop the extension after a duration (e.g. 90s, 10m; 0 for none),\n")
+		cmd.Printf("                          overriding defaults.timeout\n")
+		cmd.Printf("      --all               Run every configured extension\n")
+		cmd.Printf("      --fail-fast         Stop the other extensions when one fails (default)\n")
+		cmd.Printf("      --keep-going        Let the other extensions finish when one fails\n")
+		cmd.Printf("      --print-env         Print the environment the extension would receive, secrets\n")
+		cmd.Printf("                          masked, instead of running it\n")
+		cmd.Printf("      --events <fd|path>  Write lifecycle events as JSON lines to a file descriptor\n")
+		cmd.Printf("                          inherited from the caller (e.g. 3) or to a file\n")
+
+		cmd.Printf("\nSeveral Extensions:\n")
+		cmd.Printf("  Extensions named before -- run concurrently, each with the arguments after it.\n")
+		cmd.Printf("  Output lines are prefixed with the extension name.\n")
+		cmd.Printf("    r2r run go python -- test\n")
+		cmd.Printf("    r2r run --all --keep-going -- lint\n")
+
+		cmd.Printf("\nExit Codes:\n")
+		cmd.Printf("  The extension's exit code is passed through; with several extensions, that of\n")
+		cmd.Printf("  the first failing one in argument order. r2r itself exits with:\n")
+		cmd.Printf("    %-4d  configuration or usage error\n", exitCodeError)
+		cmd.Printf("    %-4d  image pull failed\n", exitCodeImagePullFailed)
+		cmd.Printf("    %-4d  container could not be created\n", exitCodeCreateFailed)
+		cmd.Printf("    %-4d  container was killed for running out of memory\n", exitCodeOOMKilled)
+		cmd.Printf("    %-4d  other Docker failure\n", exitCodeDockerError)
+		cmd.Printf("    %-4d  interrupted\n", exitCodeInterrupted)
+
 		cmd.Printf("\nGlobal Flags:\n")
 		cmd.Root().PersistentFlags().VisitAll(func(flag *pflag.Flag) {
 			if !flag.Hidden {
@@ -102,7 +141,7 @@ func getExtensionDescription(host *docker.ContainerHost, extensionName string) s
 	}
 
 	// Try to inspect the image for labels first
-	imageInspect, err := host.InspectImage(ext.Image)
+	imageInspect, err := host.InspectImage(context.Background(), ext.Image)
 	if err == nil && imageInspect.Config != nil && imageInspect.Config.Labels != nil {
 		// Common Docker label conventions for descriptions
 		labelKeys := []string{
@@ -228,7 +267,7 @@ func getExtensionNameColor(extensionName string) string {
 }
 
 var RunCmd = &cobra.Command{
-	Use:                "run <extension> [args...]",
+	Use:                "run [options] <extension> [args...]",
 	Short:              "Run an extension from the config",
 	Long:               `Run an extension using its configured Docker image.`,
 	DisableFlagParsing: true, // Don't parse flags - pass them through to the extension
@@ -272,47 +311,187 @@ var RunCmd = &cobra.Command{
 			}
 		}
 
+		runOpts, err := parseRunOptions(parsedCmd.RunFlags)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			exitProcess(exitCodeError)
+		}
+		if runOpts.quiet {
+			// Only errors from r2r itself remain visible
+			logger.Get().SetLevel("error")
+		}
+
+		if runOpts.printEnv {
+			// The environment is computed without a Docker daemon
+			cfg := conf.InitConfig()
+			repoRoot, err := conf.FindRepositoryRoot()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				exitProcess(exitCodeError)
+			}
+			host := docker.NewContainerHostWithRuntime(nil, repoRoot, cfg)
+			ext, err := host.FindExtension(extensionName)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				exitProcess(exitCodeError)
+			}
+			if err := printExtensionEnv(os.Stdout, extensionEnv(cfg, host.ContainerEnv(ext)), runOpts.json); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				exitProcess(exitCodeError)
+			}
+			return
+		}
+
 		log.WithFields(map[string]interface{}{
 			"extension":      extensionName,
 			"args":           containerArgs,
 			"parsed_boundary": parsedCmd.ArgumentBoundary,
 		}).Info().Msg("Running extension")
 
+		// Several extensions run concurrently: r2r run --all, r2r run ext1 ext2
+		var cfg *conf.Config
+		if runOpts.all || len(containerArgs) > 0 {
+			cfg = conf.InitConfig()
+			positional := append([]string{extensionName}, containerArgs...)
+			if runOpts.all && parsedCmd.ExtensionName == "" {
+				positional = containerArgs
+			}
+			if names, sharedArgs, ok := multiRunTargets(cfg, positional, runOpts.all); ok {
+				if runOpts.events != "" {
+					fmt.Fprintln(os.Stderr, "Error: --events applies when running one extension")
+					exitProcess(exitCodeError)
+				}
+				log.WithFields(map[string]interface{}{
+					"extensions": names,
+					"args":       sharedArgs,
+				}).Info().Msg("Running extensions concurrently")
+				runMultiple(ctx, cfg, names, sharedArgs, runOpts)
+				return
+			}
+		}
+		if runOpts.failFast || runOpts.keepGoing {
+			fmt.Fprintln(os.Stderr, "Error: --fail-fast and --keep-going apply when running several extensions")
+			exitProcess(exitCodeError)
+		}
+		containerArgs = extensionArgs(containerArgs)
+
 		// If no arguments are provided, switch to interactive mode
 		// This makes "r2r pwsh" behave like "r2r interactive pwsh"
 		if len(containerArgs) == 0 {
+			if runOpts.events != "" {
+				fmt.Fprintln(os.Stderr, "Error: --events applies when running an extension with arguments")
+				exitProcess(exitCodeError)
+			}
 			log.Info().Msg("No arguments provided, switching to interactive mode")
 			// Call the interactive command directly
 			InteractiveCmd.Run(cmd, []string{extensionName})
 			return
 		}
 
-		conf.InitConfig()
+		// Report the lifecycle of the run to a wrapper with --events
+		var emitter *events.Writer
+		if runOpts.events != "" {
+			if emitter, err = events.Open(runOpts.events); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: failed to open --events target: %v\n", err)
+				exitProcess(exitCodeError)
+			}
+			defer emitter.Close()
+			emitter.Emit(events.Event{Type: events.ConfigLoaded, Extension: extensionName, Files: cfg.Sources})
+		}
+
+		// Cancel all Docker operations on Ctrl-C
+		ctx, stop := interruptContext(ctx)
+		defer stop()
+
+		// session streams the container I/O once it has started
+		var session *docker.Session
+
+		// runEnded fires the hooks at the end of the run once pre_run has run
+		var runEnded func(code int, status string)
+
+		// exit ends the run with code, reporting the result in JSON mode
+		runStarted := time.Now()
+		result := &runResult{Extension: extensionName, Status: runStatusSucceeded, LogFile: runOpts.logFile}
+		exit := func(code int, status string, err error) {
+			if session != nil {
+				session.Close()
+			}
+			result.ExitCode = code
+			result.Status = status
+			if err != nil {
+				result.Error = err.Error()
+			}
+			if runEnded != nil {
+				runEnded(code, status)
+			}
+			notifyRunEnd(ctx, cfg, []*runResult{result}, runStarted)
+			if runOpts.json {
+				printRunResult(result)
+			}
+			emitExit(emitter, result)
+			emitter.Close()
+			os.Stdout.Sync()
+			os.Stderr.Sync()
+			finishCommandAudit(code, err)
+			os.Exit(code)
+		}
 
 		// Create extension installer
 		log.Debug().Msg("Creating extension installer")
-		installer, err := extensions.NewInstaller()
+		installer, err := extensions.NewInstaller(cfg)
 		if err != nil {
 			log.Error().Msgf("Failed to create extension installer: %v", err)
-			os.Exit(1)
+			exit(exitCodeDockerError, runStatusDockerError, err)
 		}
 		defer installer.Close()
 
 		// Get the container host for running
 		host := installer.GetContainerHost()
+		if runOpts.hasTimeout {
+			host.SetRunTimeout(runOpts.timeout)
+		}
+
+		// exitIfInterrupted stops and removes the container, if any, and exits
+		// when ctx was cancelled by a signal
+		exitIfInterrupted := func(containerID string) {
+			if ctx.Err() == nil {
+				return
+			}
+			stop()
+			if session != nil {
+				session.Close()
+			}
+			log.WithField("container_id", containerID).Info().Msg("Received interrupt signal, stopping container")
+
+			cleanupCtx := context.WithoutCancel(ctx)
+			if docker.IsRunningInContainer() {
+				log.Info().Msg("Detected Docker-in-Docker, cleaning up child containers")
+				if err := host.CleanupChildContainers(cleanupCtx); err != nil {
+					log.WithField("error", err.Error()).Warn().Msg("Failed to clean up some child containers")
+				}
+			}
+			if containerID != "" {
+				if err := host.ShutdownContainer(ctx, containerID); err != nil {
+					log.Error().Msgf("Failed to remove container: %v", err)
+				} else {
+					log.WithField("container_id", containerID).Info().Msg("Container stopped and removed")
+				}
+			}
+			exit(exitCodeInterrupted, runStatusInterrupted, nil)
+		}
 
 		// Validate extensions
 		log.Debug().Msg("Validating extensions")
 		if err := host.ValidateExtensions(); err != nil {
 			log.Error().Msgf("Extension validation failed: %v", err)
-			os.Exit(1)
+			exit(exitCodeError, runStatusError, err)
 		}
 
 		log.WithField("root_dir", host.GetRootDir()).Debug().Msg("Root directory found")
 
 		// Debug: List all available extensions before searching
-		log.Debug().Int("extension_count", len(conf.Global.Extensions)).Msg("Available extensions in config")
-		for _, ext := range conf.Global.Extensions {
+		log.Debug().Int("extension_count", len(cfg.Extensions)).Msg("Available extensions in config")
+		for _, ext := range cfg.Extensions {
 			log.Debug().Str("name", ext.Name).Str("image", ext.Image).Msg("Extension found in config")
 		}
 
@@ -321,15 +500,19 @@ var RunCmd = &cobra.Command{
 		ext, err := host.FindExtension(extensionName)
 		if err != nil {
 			log.Error().Msgf("Extension '%s' not found", extensionName)
-			// Ensure output is flushed before exit
-			os.Stdout.Sync()
-			os.Stderr.Sync()
-			os.Exit(1)
+			exit(exitCodeError, runStatusError, err)
+		}
+		result.Image = ext.Image
+
+		// An extension declaring an unsupported metadata schema needs an upgrade
+		if err := extensions.CheckDeclaredSchema(ext.Name, ext.MetadataSchemaVersion); err != nil {
+			log.Error().Msgf("%v", err)
+			exit(exitCodeError, runStatusError, err)
 		}
 		log.WithField("image", ext.Image).Info().Msg("Loading extension image")
 
 		// Take snapshot of running containers before starting
-		beforeSnapshot, err := host.GetContainerSnapshot()
+		beforeSnapshot, err := host.GetContainerSnapshot(ctx)
 		if err != nil {
 			log.WithField("error", err.Error()).Debug().Msg("Failed to take container snapshot before run")
 			beforeSnapshot = make(map[string]string) // Continue with empty snapshot
@@ -340,144 +523,113 @@ var RunCmd = &cobra.Command{
 			"image":       ext.Image,
 			"pull_policy": ext.ImagePullPolicy,
 		}).Debug().Msg("Ensuring image exists")
-		if _, err := installer.EnsureExtensionImage(extensionName); err != nil {
+		emitter.Emit(events.Event{Type: events.ImagePullStart, Extension: extensionName, Image: ext.Image})
+		updated, err := installer.EnsureExtensionImage(ctx, extensionName)
+		pulled := events.Event{Type: events.ImagePullFinish, Extension: extensionName, Image: ext.Image, Updated: &updated}
+		if err != nil {
+			pulled.Updated, pulled.Error = nil, err.Error()
+		}
+		emitter.Emit(pulled)
+		if err != nil {
+			exitIfInterrupted("")
 			log.Error().Msgf("Error ensuring image exists: %v", err)
-			os.Exit(1)
+			exit(exitCodeImagePullFailed, runStatusImagePullFailed, err)
 		}
 
 		// Inspect image
 		log.WithField("image", ext.Image).Debug().Msg("Inspecting image")
-		imageInspect, err := host.InspectImage(ext.Image)
+		imageInspect, err := host.InspectImage(ctx, ext.Image)
 		if err != nil {
+			exitIfInterrupted("")
 			log.Error().Msgf("Failed to inspect image '%s': %v", ext.Image, err)
-			os.Exit(1)
+			exit(exitCodeDockerError, runStatusDockerError, err)
+		}
+
+		// Run the pre_run hooks; the others run once the run has ended
+		hookRunner := newHookRunner(host, installer)
+		hookRun := hooks.Run{Extension: extensionName, Image: ext.Image}
+		if err := hookRunner.Fire(ctx, cfg, hooks.PreRun, hookRun); err != nil {
+			exitIfInterrupted("")
+			log.Error().Msgf("Extension '%s' not started: %v", extensionName, err)
+			exit(exitCodeError, runStatusHookFailed, err)
+		}
+		started := time.Now()
+		runEnded = func(code int, status string) {
+			runEnded = nil
+			hookRun.ExitCode, hookRun.Status = code, status
+			fireRunEndHooks(ctx, hookRunner, cfg, hookRun, started)
 		}
 
 		// Create container configuration
 		containerConfig := host.CreateContainerConfig(ext, docker.ModeRun, containerArgs, imageInspect)
-		hostConfig := host.CreateHostConfig()
+		hostConfig := host.CreateHostConfig(ext)
+
+		// Keep the container after it exits so its exit state, including an
+		// OOM kill, can be inspected; it is removed once the run ends
+		hostConfig.AutoRemove = false
 
 		// Create container
 		log.Debug().Msg("Creating container")
-		containerID, err := host.CreateContainer(containerConfig, hostConfig)
+		containerID, err := host.CreateContainer(ctx, containerConfig, hostConfig)
 		if err != nil {
+			exitIfInterrupted("")
 			log.Error().Msgf("Failed to create container: %v", err)
-			os.Exit(1)
+			exit(exitCodeCreateFailed, runStatusCreateFailed, err)
 		}
+		result.ContainerID = containerID
 		log.WithField("container_id", containerID).Debug().Msg("Container created")
 
 		// Attach to container for input/output FIRST
 		log.WithField("container_id", containerID).Debug().Msg("Attaching to container")
-		attachResp, err := host.AttachToContainer(containerID)
+		attachResp, err := host.AttachToContainer(ctx, containerID)
 		if err != nil {
+			exitIfInterrupted(containerID)
 			log.Error().Msgf("Failed to attach to container %s: %v", containerID, err)
-			os.Exit(1)
+			host.ShutdownContainer(ctx, containerID)
+			exit(exitCodeDockerError, runStatusDockerError, err)
 		}
 		defer attachResp.Close()
 
 		// Set up wait for container AFTER attach but BEFORE starting it
 		log.WithField("container_id", containerID).Debug().Msg("Setting up container wait")
-		statusCh, errCh := host.WaitForContainer(containerID)
+		statusCh, errCh := host.WaitForContainer(ctx, containerID)
 
 		// Start container
 		log.WithField("container_id", containerID).Debug().Msg("Starting container")
-		if err := host.StartContainer(containerID); err != nil {
+		containerStarted := time.Now()
+		if err := host.StartContainer(ctx, containerID); err != nil {
+			exitIfInterrupted(containerID)
 			log.Error().Msgf("Failed to start container %s: %v", containerID, err)
-			os.Exit(1)
+			host.ShutdownContainer(ctx, containerID)
+			exit(exitCodeDockerError, runStatusDockerError, err)
 		}
-
-		// Set up signal handling for graceful shutdown
-		signalChan := make(chan os.Signal, 1)
-		signal.Notify(signalChan, syscall.SIGINT, syscall.SIGTERM)
-
-		// Track if we're shutting down
-		shuttingDown := false
-
-		go func() {
-			sig := <-signalChan
-			shuttingDown = true
-
-			log.WithFields(map[string]interface{}{
-				"signal":       sig.String(),
-				"container_id": containerID,
-			}).Info().Msg("Received interrupt signal, stopping container gracefully")
-
-			// Start cleanup in a separate goroutine to avoid blocking
-			go func() {
-				// If we're running in Docker (Docker-in-Docker), clean up child containers first
-				if docker.IsRunningInContainer() {
-					log.Info().Msg("Detected Docker-in-Docker, cleaning up child containers")
-					if err := host.CleanupChildContainers(); err != nil {
-						log.WithField("error", err.Error()).Warn().Msg("Failed to clean up some child containers")
-					}
-				}
-
-				// Try to stop the container gracefully
-				stopCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-				defer cancel()
-
-				if err := host.StopContainerWithContext(stopCtx, containerID); err != nil {
-					log.WithFields(map[string]interface{}{
-						"container_id": containerID,
-						"error":        err.Error(),
-					}).Warn().Msg("Failed to stop container gracefully, forcing termination")
-
-					// Force stop if graceful stop failed
-					if err := host.StopContainer(containerID); err != nil {
-						log.Error().Msgf("Failed to force stop container: %v", err)
-					}
-				} else {
-					log.WithField("container_id", containerID).Info().Msg("Container stopped gracefully")
-				}
-			}()
-
-			// Give cleanup a moment to start, then exit
-			time.Sleep(100 * time.Millisecond)
-			os.Exit(130) // Standard exit code for SIGINT
-		}()
-
-		// Copy stdin/stdout/stderr in goroutines
-		// At this point we know we have arguments (command mode)
-		// since interactive mode is handled above
-		done := make(chan error, 1)
-		if containerConfig.Tty {
-			// TTY mode with commands - apply ANSI filter
-			// When TTY is enabled, Docker doesn't multiplex the stream
-			go func() {
-				// Wrap stdout with ANSI filter to remove problematic sequences
-				ansiFilter := docker.NewAnsiFilter(os.Stdout)
-				_, err := io.Copy(ansiFilter, attachResp.Reader)
-				done <- err
-			}()
-		} else {
-			// Non-TTY mode - use stdcopy to demultiplex the stream
-			// This removes the 8-byte headers that cause control characters
-			go func() {
-				_, err := stdcopy.StdCopy(os.Stdout, os.Stderr, attachResp.Reader)
-				done <- err
-			}()
-		}
-
-		// Copy stdin to container if OpenStdin is enabled
-		// This should work in both TTY and non-TTY modes
-		if containerConfig.OpenStdin {
-			go func() {
-				defer func() {
-					// Close stdin side of the connection when we're done
-					if conn, ok := attachResp.Conn.(interface {
-						CloseWrite() error
-					}); ok {
-						conn.CloseWrite()
-					}
-				}()
-
-				// Copy stdin to the connection
-				_, err := io.Copy(attachResp.Conn, os.Stdin)
-				if err != nil && err != io.EOF {
-					log.Debug().Err(err).Msg("stdin copy error")
-				}
-			}()
+		emitter.Emit(events.Event{Type: events.ContainerStart, Extension: extensionName, Image: ext.Image, ContainerID: containerID})
+		stats := host.CollectStats(ctx, containerID)
+		expired, stopTimer := host.RunTimer()
+		defer stopTimer()
+
+		// Stream the container I/O to the terminal
+		sessionOpts := docker.SessionOptions{
+			TTY:   containerConfig.Tty,
+			Stdin: containerConfig.OpenStdin,
 		}
+		sessionOpts.Output, sessionOpts.Error, err = runOutputWriters(runOpts, containerConfig.Tty)
+		if err != nil {
+			log.Error().Msgf("Failed to open log file: %v", err)
+			host.ShutdownContainer(ctx, containerID)
+			exit(exitCodeError, runStatusError, err)
+		}
+		if emitter != nil {
+			sessionOpts.Output = io.MultiWriter(sessionOpts.Output, emitter.Output(extensionName, events.Stdout))
+			sessionOpts.Error = io.MultiWriter(sessionOpts.Error, emitter.Output(extensionName, events.Stderr))
+		}
+		session = host.NewSession(containerID, attachResp, sessionOpts)
+		if err := session.Start(ctx); err != nil {
+			log.Error().Msgf("Failed to start session for container %s: %v", containerID, err)
+			host.ShutdownContainer(ctx, containerID)
+			exit(exitCodeDockerError, runStatusDockerError, err)
+		}
+		defer session.Close()
 
 		// Wait for container to finish (wait channels already set up before start)
 		log.WithField("container_id", containerID).Debug().Msg("Waiting for container to finish")
@@ -492,17 +644,18 @@ var RunCmd = &cobra.Command{
 
 		for !containerDone || !ioDone {
 			select {
+			case <-ctx.Done():
+				// Ctrl-C: stop and remove the container, then exit
+				exitIfInterrupted(containerID)
+			case <-expired:
+				// Stop gracefully, killing the container after the stop timeout
+				log.Error().Msgf("Extension '%s' timed out after %s, stopping it", extensionName, host.Timeouts().Run)
+				host.ShutdownContainer(ctx, containerID)
+				exit(exitCodeTimedOut, runStatusTimedOut, &docker.TimeoutError{Timeout: host.Timeouts().Run})
 			case status := <-statusCh:
 				if !containerDone {
 					containerDone = true
 					log.Debug().Msg("Received status from container")
-					if shuttingDown {
-						log.WithFields(map[string]interface{}{
-							"container_id": containerID,
-							"status_code":  status.StatusCode,
-						}).Info().Msg("Container stopped by user interrupt")
-						os.Exit(0)
-					}
 					log.WithFields(map[string]interface{}{
 						"container_id": containerID,
 						"status_code":  status.StatusCode,
@@ -519,6 +672,8 @@ var RunCmd = &cobra.Command{
 				// 3. nil error - normal completion
 				// We must handle all three cases to avoid spurious failures in CI/CD environments
 				if !containerDone && ok {
+					// The wait is cancelled together with ctx on Ctrl-C
+					exitIfInterrupted(containerID)
 					if err != nil {
 						errStr := err.Error()
 						// Check if this is the "No such container" error from AutoRemove
@@ -533,7 +688,8 @@ var RunCmd = &cobra.Command{
 								"container_id": containerID,
 								"error":        errStr,
 							}).Error().Msg("Error waiting for container")
-							os.Exit(1)
+							host.ShutdownContainer(ctx, containerID)
+							exit(exitCodeDockerError, runStatusDockerError, err)
 						} else {
 							// Error with empty message - Docker's signal that wait completed but
 							// can't provide status (container was auto-removed)
@@ -546,7 +702,7 @@ var RunCmd = &cobra.Command{
 						containerDone = true
 					}
 				}
-			case ioErr := <-done:
+			case ioErr := <-session.Done():
 				if !ioDone {
 					ioDone = true
 					if ioErr != nil && ioErr != io.EOF {
@@ -556,27 +712,111 @@ var RunCmd = &cobra.Command{
 				}
 			}
 		}
+		session.Close()
+		result.Resources = stats.Stop()
+		if result.Resources != nil && !runOpts.quiet {
+			fmt.Fprintf(os.Stderr, "Resource usage of '%s': %s\n", extensionName, result.Resources)
+		}
+
+		// Read the exit state, then remove the container
+		cleanupCtx := context.WithoutCancel(ctx)
+		oomKilled := false
+		if exitState, err := host.InspectExit(cleanupCtx, containerID); err != nil {
+			log.WithField("error", err.Error()).Debug().Msg("Failed to inspect container exit state")
+		} else {
+			containerExitCode = int64(exitState.StatusCode)
+			oomKilled = exitState.OOMKilled
+		}
+		if err := host.RemoveContainer(cleanupCtx, containerID); err != nil {
+			log.WithField("error", err.Error()).Warn().Msg("Failed to remove container")
+		}
+		if err := host.FixOwnership(cleanupCtx, ext, containerStarted); err != nil {
+			log.WithField("error", err.Error()).Warn().Msg("Failed to fix the ownership of created files")
+		}
 
 		// Check for new containers that appeared during execution
-		afterSnapshot, err := host.GetContainerSnapshot()
+		afterSnapshot, err := host.GetContainerSnapshot(ctx)
 		if err != nil {
 			log.WithField("error", err.Error()).Debug().Msg("Failed to take container snapshot after run")
 		} else {
-			host.WarnAboutNewContainers(beforeSnapshot, afterSnapshot, ext.Image, ext.AutoRemoveChildren)
+			host.WarnAboutNewContainers(ctx, beforeSnapshot, afterSnapshot, ext.Image, ext.AutoRemoveChildren)
 		}
 
 		// Clean up any child containers if we're in Docker-in-Docker
 		if docker.IsRunningInContainer() {
 			log.Debug().Msg("Cleaning up any remaining child containers before exit")
-			if err := host.CleanupChildContainers(); err != nil {
+			if err := host.CleanupChildContainers(ctx); err != nil {
 				log.WithField("error", err.Error()).Warn().Msg("Failed to clean up some child containers")
 			}
 		}
 
-		// Exit with the same code as the container (unless we were interrupted)
-		if !shuttingDown && containerExitCode != 0 {
-			os.Exit(int(containerExitCode))
+		// Exit with the same code as the container, unless it was OOM-killed
+		code := int(containerExitCode)
+		result.ContainerExitCode = &code
+		result.OOMKilled = oomKilled
+		switch {
+		case oomKilled:
+			log.Error().Msgf("Extension '%s' was killed for running out of memory", extensionName)
+			exit(exitCodeOOMKilled, runStatusOOMKilled, nil)
+		case code != 0:
+			exit(code, runStatusFailed, nil)
 		}
-
+		runEnded(0, runStatusSucceeded)
+		notifyRunEnd(ctx, cfg, []*runResult{result}, runStarted)
+		if runOpts.json {
+			printRunResult(result)
+		}
+		emitExit(emitter, result)
 	},
 }
+
+// emitExit reports the end of a run as the exit event
+func emitExit(emitter *events.Writer, result *runResult) {
+	code := result.ExitCode
+	emitter.Emit(events.Event{
+		Type:        events.Exit,
+		Extension:   result.Extension,
+		Image:       result.Image,
+		ContainerID: result.ContainerID,
+		ExitCode:    &code,
+		Status:      result.Status,
+		Error:       result.Error,
+	})
+}
+
+// runOutputWriters returns where the container stdout and stderr go:
+// discarded with --quiet, stripped of all escape sequences with --no-ansi,
+// and otherwise filtered for problematic sequences when a TTY is used. With
+// --log-file the raw output is also written to the file.
+func runOutputWriters(opts runOptions, tty bool) (stdout, stderr io.Writer, err error) {
+	stdout, stderr = os.Stdout, os.Stderr
+	switch {
+	case opts.quiet:
+		stdout, stderr = io.Discard, io.Discard
+	case opts.noANSI:
+		stdout, stderr = docker.NewAnsiStripper(os.Stdout), docker.NewAnsiStripper(os.Stderr)
+	case tty:
+		stdout = docker.NewAnsiFilter(os.Stdout)
+	}
+
+	if opts.logFile != "" {
+		// The file stays open until the process exits
+		file, err := os.Create(opts.logFile)
+		if err != nil {
+			return nil, nil, err
+		}
+		stdout, stderr = io.MultiWriter(file, stdout), io.MultiWriter(file, stderr)
+	}
+	return stdout, stderr, nil
+}
+
+// extensionArgs drops the "--" that separates the arguments of one
+// extension from the command line of r2r; a later "--" is passed on.
+//
+//	r2r run go -- version 2>&1
+func extensionArgs(containerArgs []string) []string {
+	if len(containerArgs) > 0 && containerArgs[0] == argv.Separator {
+		return containerArgs[1:]
+	}
+	return containerArgs
+}
@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/ready-to-release/eac/contracts/extenv"
+	"github.com/ready-to-release/eac/src/cli/internal/conf"
+)
+
+// maskedValue replaces the values of secrets printed by --print-env
+const maskedValue = "********"
+
+// envEntry is a variable printed by r2r run --print-env --json
+type envEntry struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+	// Contract is set for variables of the extension-env contract, unset
+	// for those configured in r2r-cli.yml
+	Contract bool `json:"contract"`
+	Secret   bool `json:"secret,omitempty"`
+}
+
+// extensionEnv describes the environment of a container, env in the form
+// of os.Environ, masking the values of secrets
+func extensionEnv(cfg *conf.Config, env []string) []envEntry {
+	secrets := make(map[string]bool)
+	if cfg != nil && cfg.Environment != nil {
+		for _, secret := range cfg.Environment.Secrets {
+			secrets[secret.Name] = true
+		}
+	}
+
+	entries := make([]envEntry, 0, len(env))
+	for _, variable := range env {
+		name, value, _ := strings.Cut(variable, "=")
+		contract, inContract := extenv.Lookup(name)
+		entry := envEntry{Name: name, Value: value, Contract: inContract, Secret: secrets[name] || contract.Secret}
+		if entry.Secret && value != "" {
+			entry.Value = maskedValue
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// printExtensionEnv writes the environment of an extension as NAME=value
+// lines, or as a JSON array
+func printExtensionEnv(w io.Writer, entries []envEntry, asJSON bool) error {
+	if asJSON {
+		data, err := json.MarshalIndent(entries, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(w, string(data))
+		return err
+	}
+	for _, entry := range entries {
+		if _, err := fmt.Fprintf(w, "%s=%s\n", entry.Name, entry.Value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+//go:build L0
+// +build L0
+
+package cmd
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/ready-to-release/eac/src/cli/internal/conf"
+)
+
+func TestExtensionEnv(t *testing.T) {
+	cfg := &conf.Config{Environment: &conf.Environment{Secrets: []conf.SecretVar{{Name: "API_KEY", Env: "HOST_API_KEY"}}}}
+	entries := extensionEnv(cfg, []string{
+		"R2R_CONTAINER_REPOROOT=/var/task",
+		"GITHUB_TOKEN=ghp_secret",
+		"API_KEY=key",
+		"GOFLAGS=-mod=mod",
+	})
+
+	want := []envEntry{
+		{Name: "R2R_CONTAINER_REPOROOT", Value: "/var/task", Contract: true},
+		{Name: "GITHUB_TOKEN", Value: maskedValue, Contract: true, Secret: true},
+		{Name: "API_KEY", Value: maskedValue, Secret: true},
+		{Name: "GOFLAGS", Value: "-mod=mod"},
+	}
+	if !reflect.DeepEqual(entries, want) {
+		t.Errorf("extensionEnv() = %+v, want %+v", entries, want)
+	}
+
+	var out bytes.Buffer
+	if err := printExtensionEnv(&out, entries[:2], false); err != nil {
+		t.Fatal(err)
+	}
+	if got := out.String(); got != "R2R_CONTAINER_REPOROOT=/var/task\nGITHUB_TOKEN=********\n" {
+		t.Errorf("Unexpected output %q", got)
+	}
+}
+
+func TestParseRunOptionsPrintEnv(t *testing.T) {
+	opts, err := parseRunOptions([]string{"--print-env", "--json"})
+	if err != nil || !opts.printEnv || !opts.json {
+		t.Errorf("Unexpected options %+v, error %v", opts, err)
+	}
+}
@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ready-to-release/eac/src/cli/internal/conf"
+	"github.com/ready-to-release/eac/src/cli/internal/docker"
+	"github.com/ready-to-release/eac/src/cli/internal/extensions"
+	"github.com/ready-to-release/eac/src/cli/internal/hooks"
+)
+
+// runStatusHookFailed marks a run aborted by a failing pre_run hook
+const runStatusHookFailed = "hook_failed"
+
+// newHookRunner returns the runner of the hooks around r2r run. Hook output
+// goes to stderr so it never mixes with the --json result. Extension hooks
+// run to completion and fire no hooks of their own.
+func newHookRunner(host *docker.ContainerHost, installer *extensions.Installer) *hooks.Runner {
+	return &hooks.Runner{
+		Dir:    host.GetRootDir(),
+		Stdout: os.Stderr,
+		Stderr: os.Stderr,
+		RunExtension: func(ctx context.Context, name string, args []string, env []string) (int, error) {
+			if _, err := installer.EnsureExtensionImage(ctx, name); err != nil {
+				return 0, err
+			}
+			ext, err := host.FindExtension(name)
+			if err != nil {
+				return 0, err
+			}
+			for _, variable := range env {
+				name, value, _ := strings.Cut(variable, "=")
+				ext.Env = append(ext.Env, conf.EnvVar{Name: name, Value: value})
+			}
+			exit, err := host.RunToCompletion(ctx, ext, args, os.Stderr, os.Stderr)
+			if err != nil {
+				return 0, err
+			}
+			return exit.StatusCode, nil
+		},
+	}
+}
+
+// fireRunEndHooks runs the on_failure hooks of a failed run, then its
+// post_run hooks. They are skipped for interrupted runs. Hook failures are
+// logged and do not change the outcome of the run.
+func fireRunEndHooks(ctx context.Context, runner *hooks.Runner, cfg *conf.Config, run hooks.Run, started time.Time) {
+	if run.Status == runStatusInterrupted || run.Status == runStatusCancelled {
+		return
+	}
+	ctx = context.WithoutCancel(ctx)
+	run.Duration = time.Since(started)
+	if run.Status != runStatusSucceeded {
+		_ = runner.Fire(ctx, cfg, hooks.OnFailure, run)
+	}
+	_ = runner.Fire(ctx, cfg, hooks.PostRun, run)
+}
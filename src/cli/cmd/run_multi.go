@@ -0,0 +1,335 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/ready-to-release/eac/src/cli/internal/conf"
+	"github.com/ready-to-release/eac/src/cli/internal/docker"
+	"github.com/ready-to-release/eac/src/cli/internal/extensions"
+	"github.com/ready-to-release/eac/src/cli/internal/hooks"
+	"github.com/ready-to-release/eac/src/cli/internal/logger"
+	"github.com/ready-to-release/eac/src/cli/internal/progress"
+)
+
+// runStatusCancelled marks an extension stopped by --fail-fast because
+// another extension failed
+const runStatusCancelled = "cancelled"
+
+// multiRunTargets returns the extensions and shared arguments of a run of
+// several extensions. With --all every configured extension runs; otherwise
+// the arguments before "--" must each name a different configured extension.
+// The arguments after "--" are passed to every extension.
+//
+//	r2r run --all -- test
+//	r2r run go python -- test
+func multiRunTargets(cfg *conf.Config, positional []string, all bool) (names, args []string, ok bool) {
+	if all {
+		for _, ext := range cfg.Extensions {
+			names = append(names, ext.Name)
+		}
+		if len(positional) > 0 && positional[0] == "--" {
+			positional = positional[1:]
+		}
+		return names, positional, true
+	}
+
+	before, after := positional, []string(nil)
+	for i, arg := range positional {
+		if arg == "--" {
+			before, after = positional[:i], positional[i+1:]
+			break
+		}
+	}
+	if len(before) < 2 {
+		return nil, nil, false
+	}
+
+	seen := make(map[string]bool, len(before))
+	for _, name := range before {
+		if _, found := cfg.FindExtension(name); !found || seen[name] {
+			return nil, nil, false
+		}
+		seen[name] = true
+	}
+	return before, after, true
+}
+
+// runMultiple runs several extensions concurrently with their output lines
+// prefixed by the extension name, then exits: with 0 when all succeed,
+// otherwise with the exit code of the first failing extension in argument
+// order. Without --keep-going the first failure stops the others.
+func runMultiple(ctx context.Context, cfg *conf.Config, names, args []string, opts runOptions) {
+	log := logger.WithContext(ctx)
+	failFast := !opts.keepGoing
+	started := time.Now()
+
+	if len(names) == 0 {
+		fmt.Fprintln(os.Stderr, "Error: no extensions configured")
+		exitProcess(exitCodeError)
+	}
+
+	installer, err := extensions.NewInstaller(cfg)
+	if err != nil {
+		log.Error().Msgf("Failed to create extension installer: %v", err)
+		exitProcess(exitCodeDockerError)
+	}
+	defer installer.Close()
+	host := installer.GetContainerHost()
+	if opts.hasTimeout {
+		host.SetRunTimeout(opts.timeout)
+	}
+
+	if err := host.ValidateExtensions(); err != nil {
+		log.Error().Msgf("Extension validation failed: %v", err)
+		exitProcess(exitCodeError)
+	}
+
+	// Cancel all Docker operations on Ctrl-C
+	ctx, stop := interruptContext(ctx)
+	defer stop()
+
+	results := make([]*runResult, len(names))
+	exts := make([]*docker.ExtensionConfig, len(names))
+	failed := false
+	for i, name := range names {
+		results[i] = &runResult{Extension: name, Status: runStatusSucceeded, LogFile: opts.logFile}
+		ext, err := host.FindExtension(name)
+		if err == nil {
+			err = extensions.CheckDeclaredSchema(ext.Name, ext.MetadataSchemaVersion)
+		}
+		if err != nil {
+			results[i].fail(exitCodeError, runStatusError, err)
+			failed = true
+			continue
+		}
+		results[i].Image = ext.Image
+		exts[i] = ext
+	}
+
+	// Pull one image at a time so the progress output stays readable
+	pulling := progress.Bar("Preparing images", int64(len(exts)))
+	pulling.SetUnit(progress.UnitCount)
+	for i, ext := range exts {
+		pulling.SetCurrent(int64(i))
+		if ext == nil || (failed && failFast) {
+			continue
+		}
+		pulling.SetMessage("Preparing image of " + ext.Name)
+		if _, err := installer.EnsureExtensionImage(ctx, ext.Name); err != nil {
+			if ctx.Err() != nil {
+				break
+			}
+			results[i].fail(exitCodeImagePullFailed, runStatusImagePullFailed, err)
+			exts[i] = nil
+			failed = true
+		}
+	}
+	pulling.Done("")
+
+	if ctx.Err() == nil && !(failed && failFast) {
+		stdout, stderr, err := runOutputWriters(opts, false)
+		if err != nil {
+			log.Error().Msgf("Failed to open log file: %v", err)
+			exitProcess(exitCodeError)
+		}
+		runExtensions(ctx, host, newHookRunner(host, installer), cfg, exts, results, args, stdout, stderr, failFast)
+	}
+
+	for i, result := range results {
+		switch {
+		case ctx.Err() != nil && (result.Status == runStatusSucceeded || result.Status == runStatusCancelled):
+			result.fail(exitCodeInterrupted, runStatusInterrupted, nil)
+		case exts[i] != nil && failed && failFast && result.Status == runStatusSucceeded && result.ContainerExitCode == nil:
+			// Never started because an earlier step failed
+			result.fail(exitCodeInterrupted, runStatusCancelled, nil)
+		}
+	}
+
+	printMultiRunSummary(results, opts)
+	notifyRunEnd(ctx, cfg, results, started)
+	os.Stdout.Sync()
+	os.Stderr.Sync()
+	exitProcess(multiRunExitCode(ctx, results))
+}
+
+// runExtensions runs the extensions concurrently, each between its hooks, and
+// records their results. A nil extension is skipped. With failFast the first
+// failure cancels the others.
+func runExtensions(ctx context.Context, host *docker.ContainerHost, hookRunner *hooks.Runner, cfg *conf.Config, exts []*docker.ExtensionConfig, results []*runResult, args []string, stdout, stderr io.Writer, failFast bool) {
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	width := 0
+	for _, result := range results {
+		width = max(width, len(result.Extension))
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for i, ext := range exts {
+		if ext == nil {
+			continue
+		}
+		wg.Add(1)
+		go func(ext *docker.ExtensionConfig, result *runResult) {
+			defer wg.Done()
+
+			prefix := fmt.Sprintf("%s%-*s\033[0m | ", getExtensionNameColor(ext.Name), width, ext.Name)
+			out := &linePrefixer{mu: &mu, out: stdout, prefix: prefix}
+			errOut := &linePrefixer{mu: &mu, out: stderr, prefix: prefix}
+
+			run := hooks.Run{Extension: ext.Name, Image: ext.Image}
+			if err := hookRunner.Fire(runCtx, cfg, hooks.PreRun, run); err != nil {
+				result.fail(exitCodeError, runStatusHookFailed, err)
+				if failFast {
+					cancel()
+				}
+				return
+			}
+			started := time.Now()
+			defer func() {
+				run.ExitCode, run.Status = result.ExitCode, result.Status
+				fireRunEndHooks(runCtx, hookRunner, cfg, run, started)
+			}()
+
+			exit, err := host.RunToCompletion(runCtx, ext, args, out, errOut)
+			var timeoutErr *docker.TimeoutError
+			out.Flush()
+			errOut.Flush()
+
+			switch {
+			case err != nil && runCtx.Err() != nil:
+				result.fail(exitCodeInterrupted, runStatusCancelled, nil)
+				return
+			case errors.As(err, &timeoutErr):
+				result.fail(exitCodeTimedOut, runStatusTimedOut, err)
+			case err != nil:
+				result.fail(exitCodeDockerError, runStatusDockerError, err)
+			default:
+				code := exit.StatusCode
+				result.ContainerExitCode = &code
+				result.OOMKilled = exit.OOMKilled
+				result.Resources = exit.Resources
+				switch {
+				case exit.OOMKilled:
+					result.fail(exitCodeOOMKilled, runStatusOOMKilled, nil)
+				case code != 0:
+					result.fail(code, runStatusFailed, nil)
+				default:
+					return
+				}
+			}
+			if failFast {
+				cancel()
+			}
+		}(ext, results[i])
+	}
+	wg.Wait()
+}
+
+// fail records an unsuccessful outcome
+func (r *runResult) fail(code int, status string, err error) {
+	r.ExitCode = code
+	r.Status = status
+	if err != nil {
+		r.Error = err.Error()
+	}
+}
+
+// multiRunExitCode is 130 when interrupted, otherwise the exit code of the
+// first failed extension, or 0
+func multiRunExitCode(ctx context.Context, results []*runResult) int {
+	if ctx.Err() != nil {
+		return exitCodeInterrupted
+	}
+	for _, result := range results {
+		if result.Status != runStatusSucceeded && result.Status != runStatusCancelled {
+			return result.ExitCode
+		}
+	}
+	return 0
+}
+
+// printMultiRunSummary reports every extension on stderr, and as JSON lines
+// on stdout with --json
+func printMultiRunSummary(results []*runResult, opts runOptions) {
+	width := 0
+	for _, result := range results {
+		width = max(width, len(result.Extension))
+	}
+
+	fmt.Fprintln(os.Stderr)
+	for _, result := range results {
+		icon, detail := "❌", result.Status
+		switch result.Status {
+		case runStatusSucceeded:
+			icon, detail = "✅", "exit 0"
+		case runStatusFailed:
+			detail = fmt.Sprintf("exit %d", result.ExitCode)
+		case runStatusCancelled, runStatusInterrupted:
+			icon = "⏹️ "
+		}
+		if result.Error != "" {
+			detail += ": " + result.Error
+		}
+		fmt.Fprintf(os.Stderr, "%s %-*s  %s\n", icon, width, result.Extension, detail)
+	}
+
+	if opts.json {
+		for _, result := range results {
+			printRunResult(result)
+		}
+	}
+}
+
+// linePrefixer writes each complete line to out behind a prefix, holding a
+// partial line back until it is completed or flushed. Writers sharing mu
+// never interleave within a line.
+type linePrefixer struct {
+	mu     *sync.Mutex
+	out    io.Writer
+	prefix string
+	buf    []byte
+}
+
+func (w *linePrefixer) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		if err := w.writeLine(w.buf[:i+1]); err != nil {
+			return 0, err
+		}
+		w.buf = w.buf[i+1:]
+	}
+	return len(p), nil
+}
+
+// Flush writes a pending partial line, terminated by a newline
+func (w *linePrefixer) Flush() error {
+	if len(w.buf) == 0 {
+		return nil
+	}
+	line := append(w.buf, '\n')
+	w.buf = nil
+	return w.writeLine(line)
+}
+
+func (w *linePrefixer) writeLine(line []byte) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if _, err := io.WriteString(w.out, w.prefix); err != nil {
+		return err
+	}
+	_, err := w.out.Write(line)
+	return err
+}
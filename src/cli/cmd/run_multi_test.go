@@ -0,0 +1,143 @@
+//go:build L0
+// +build L0
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ready-to-release/eac/src/cli/internal/conf"
+)
+
+func TestMultiRunTargets(t *testing.T) {
+	cfg := &conf.Config{Extensions: []conf.Extension{{Name: "go"}, {Name: "python"}, {Name: "pwsh"}}}
+
+	testCases := map[string]struct {
+		positional []string
+		all        bool
+		names      []string
+		args       []string
+		ok         bool
+	}{
+		"two extensions":        {positional: []string{"go", "python"}, names: []string{"go", "python"}, ok: true},
+		"shared arguments":      {positional: []string{"go", "python", "--", "test", "-v"}, names: []string{"go", "python"}, args: []string{"test", "-v"}, ok: true},
+		"single extension":      {positional: []string{"go", "test"}},
+		"argument after name":   {positional: []string{"go", "python", "test"}},
+		"unknown extension":     {positional: []string{"go", "rust", "--", "test"}},
+		"duplicate extension":   {positional: []string{"go", "go"}},
+		"all":                   {all: true, names: []string{"go", "python", "pwsh"}, ok: true},
+		"all with separator":    {positional: []string{"--", "lint"}, all: true, names: []string{"go", "python", "pwsh"}, args: []string{"lint"}, ok: true},
+		"all without separator": {positional: []string{"lint"}, all: true, names: []string{"go", "python", "pwsh"}, args: []string{"lint"}, ok: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			names, args, ok := multiRunTargets(cfg, tc.positional, tc.all)
+			if ok != tc.ok {
+				t.Fatalf("ok = %v, want %v", ok, tc.ok)
+			}
+			if !reflect.DeepEqual(names, tc.names) || len(args) != len(tc.args) || (len(args) > 0 && !reflect.DeepEqual(args, tc.args)) {
+				t.Errorf("got %v %v, want %v %v", names, args, tc.names, tc.args)
+			}
+		})
+	}
+}
+
+func TestLinePrefixer(t *testing.T) {
+	var out bytes.Buffer
+	var mu sync.Mutex
+	a := &linePrefixer{mu: &mu, out: &out, prefix: "a | "}
+	b := &linePrefixer{mu: &mu, out: &out, prefix: "b | "}
+
+	a.Write([]byte("first "))
+	b.Write([]byte("one\ntwo\n"))
+	a.Write([]byte("line\nsecond"))
+	a.Flush()
+	b.Flush()
+
+	expected := "b | one\nb | two\na | first line\na | second\n"
+	if out.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, out.String())
+	}
+}
+
+func TestMultiRunExitCode(t *testing.T) {
+	results := []*runResult{
+		{Extension: "go", Status: runStatusSucceeded},
+		{Extension: "python", Status: runStatusCancelled, ExitCode: exitCodeInterrupted},
+		{Extension: "pwsh", Status: runStatusFailed, ExitCode: 3},
+		{Extension: "node", Status: runStatusFailed, ExitCode: 1},
+	}
+	if code := multiRunExitCode(context.Background(), results); code != 3 {
+		t.Errorf("Expected exit code 3, got %d", code)
+	}
+
+	if code := multiRunExitCode(context.Background(), results[:2]); code != 0 {
+		t.Errorf("Expected exit code 0, got %d", code)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if code := multiRunExitCode(ctx, results); code != exitCodeInterrupted {
+		t.Errorf("Expected exit code %d, got %d", exitCodeInterrupted, code)
+	}
+}
+
+func TestParseRunOptionsFailFastKeepGoing(t *testing.T) {
+	opts, err := parseRunOptions([]string{"--all", "--keep-going"})
+	if err != nil || !opts.all || !opts.keepGoing {
+		t.Errorf("Unexpected options %+v, error %v", opts, err)
+	}
+	if _, err := parseRunOptions([]string{"--fail-fast", "--keep-going"}); err == nil {
+		t.Error("Expected an error when combining --fail-fast and --keep-going")
+	}
+}
+
+func TestParseRunOptionsTimeout(t *testing.T) {
+	testCases := map[string]struct {
+		flags   []string
+		timeout time.Duration
+		wantErr bool
+	}{
+		"duration":       {flags: []string{"--timeout", "10m"}, timeout: 10 * time.Minute},
+		"seconds":        {flags: []string{"--timeout=90"}, timeout: 90 * time.Second},
+		"no limit":       {flags: []string{"--timeout", "0"}},
+		"missing value":  {flags: []string{"--timeout"}, wantErr: true},
+		"negative":       {flags: []string{"--timeout=-5s"}, wantErr: true},
+		"not a duration": {flags: []string{"--timeout", "soon"}, wantErr: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			opts, err := parseRunOptions(tc.flags)
+			if tc.wantErr {
+				if err == nil {
+					t.Errorf("Expected an error, got options %+v", opts)
+				}
+				return
+			}
+			if err != nil || !opts.hasTimeout || opts.timeout != tc.timeout {
+				t.Errorf("Unexpected options %+v, error %v", opts, err)
+			}
+		})
+	}
+}
+
+func TestParseRunOptionsEvents(t *testing.T) {
+	for _, flags := range [][]string{{"--events", "3"}, {"--events=3"}} {
+		opts, err := parseRunOptions(flags)
+		if err != nil || opts.events != "3" {
+			t.Errorf("Unexpected options %+v for %v, error %v", opts, flags, err)
+		}
+	}
+	for _, flags := range [][]string{{"--events"}, {"--events="}} {
+		if _, err := parseRunOptions(flags); err == nil {
+			t.Errorf("Expected an error for %v", flags)
+		}
+	}
+}
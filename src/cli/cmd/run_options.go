@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// runOptions are the options of r2r run. They are given between "run" and the
+// extension name, so they never collide with the extension's own arguments:
+//
+//	r2r run --quiet --log-file build.log go build ./...
+type runOptions struct {
+	json      bool   // print a JSON result line when the run ends
+	quiet     bool   // do not stream the extension output to the terminal
+	noANSI    bool   // strip all ANSI escape sequences from the streamed output
+	logFile   string // tee the raw extension output to this file
+	all       bool   // run every configured extension
+	failFast  bool   // stop the other extensions once one fails (default)
+	keepGoing bool   // let the other extensions finish when one fails
+	printEnv  bool   // print the environment of the extension instead of running it
+	events    string // write NDJSON lifecycle events to this file descriptor or file
+
+	timeout    time.Duration // bound the run, overriding defaults.timeout; zero for no limit
+	hasTimeout bool          // --timeout was given
+}
+
+// parseRunOptions reads the run options recognised by the command parser
+func parseRunOptions(flags []string) (runOptions, error) {
+	var opts runOptions
+	for i := 0; i < len(flags); i++ {
+		flag, value, hasValue := strings.Cut(flags[i], "=")
+		switch flag {
+		case "--json":
+			opts.json = true
+		case "--quiet":
+			opts.quiet = true
+		case "--no-ansi":
+			opts.noANSI = true
+		case "--all":
+			opts.all = true
+		case "--fail-fast":
+			opts.failFast = true
+		case "--keep-going":
+			opts.keepGoing = true
+		case "--print-env":
+			opts.printEnv = true
+		case "--log-file":
+			if !hasValue {
+				if i+1 >= len(flags) {
+					return opts, fmt.Errorf("--log-file requires a path")
+				}
+				i++
+				value = flags[i]
+			}
+			if value == "" {
+				return opts, fmt.Errorf("--log-file requires a path")
+			}
+			opts.logFile = value
+		case "--events":
+			if !hasValue {
+				if i+1 >= len(flags) {
+					return opts, fmt.Errorf("--events requires a file descriptor or path")
+				}
+				i++
+				value = flags[i]
+			}
+			if value == "" {
+				return opts, fmt.Errorf("--events requires a file descriptor or path")
+			}
+			opts.events = value
+		case "--timeout":
+			if !hasValue {
+				if i+1 >= len(flags) {
+					return opts, fmt.Errorf("--timeout requires a duration")
+				}
+				i++
+				value = flags[i]
+			}
+			timeout, err := parseTimeout(value)
+			if err != nil {
+				return opts, err
+			}
+			opts.timeout, opts.hasTimeout = timeout, true
+		}
+	}
+	if opts.failFast && opts.keepGoing {
+		return opts, fmt.Errorf("--fail-fast and --keep-going cannot be combined")
+	}
+	return opts, nil
+}
+
+// parseTimeout reads a --timeout value: a duration such as "90s" or "10m", or
+// a number of seconds like defaults.timeout. Zero removes the limit.
+func parseTimeout(value string) (time.Duration, error) {
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("--timeout requires a duration such as 90s or 10m, got %q", value)
+	}
+	return d, nil
+}
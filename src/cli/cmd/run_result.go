@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ready-to-release/eac/src/cli/internal/conf"
+	"github.com/ready-to-release/eac/src/cli/internal/docker"
+	"github.com/ready-to-release/eac/src/cli/internal/notify"
+)
+
+// Run statuses reported in the JSON result of r2r run
+const (
+	runStatusSucceeded       = "succeeded"
+	runStatusFailed          = "failed"
+	runStatusError           = "error"
+	runStatusImagePullFailed = "image_pull_failed"
+	runStatusCreateFailed    = "create_failed"
+	runStatusOOMKilled       = "oom_killed"
+	runStatusTimedOut        = "timed_out"
+	runStatusDockerError     = "docker_error"
+	runStatusInterrupted     = "interrupted"
+)
+
+// runResult is the outcome of r2r run. With --json it is printed as a single
+// line on stdout after the extension output.
+type runResult struct {
+	Extension         string `json:"extension"`
+	Image             string `json:"image,omitempty"`
+	ContainerID       string `json:"container_id,omitempty"`
+	Status            string `json:"status"`
+	ExitCode          int    `json:"exit_code"`
+	ContainerExitCode *int   `json:"container_exit_code,omitempty"`
+	OOMKilled         bool   `json:"oom_killed,omitempty"`
+	LogFile           string `json:"log_file,omitempty"`
+	Error             string `json:"error,omitempty"`
+	// Resources is what the container used, when Docker reported stats
+	Resources *docker.ResourceUsage `json:"resources,omitempty"`
+}
+
+func printRunResult(result *runResult) {
+	data, err := json.Marshal(result)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to encode run result: %v\n", err)
+		return
+	}
+	fmt.Fprintln(os.Stdout, string(data))
+}
+
+// notifyRunEnd announces the end of a long r2r run as configured in
+// notifications. Runs interrupted by the user stay silent.
+func notifyRunEnd(ctx context.Context, cfg *conf.Config, results []*runResult, started time.Time) {
+	names := make([]string, 0, len(results))
+	succeeded := 0
+	for _, result := range results {
+		if result.Status == runStatusInterrupted {
+			return
+		}
+		names = append(names, result.Extension)
+		if result.Status == runStatusSucceeded {
+			succeeded++
+		}
+	}
+
+	event := notify.Event{Operation: "run", Subject: strings.Join(names, ", "), Success: succeeded == len(results)}
+	switch {
+	case len(results) > 1:
+		event.Message = fmt.Sprintf("%d of %d succeeded", succeeded, len(results))
+	case len(results) == 1 && results[0].Status == runStatusFailed:
+		event.Message = fmt.Sprintf("exit code %d", results[0].ExitCode)
+	case len(results) == 1 && !event.Success:
+		event.Message = results[0].Status
+	}
+	notify.New(cfg).Done(ctx, event, started)
+}
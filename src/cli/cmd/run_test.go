@@ -0,0 +1,44 @@
+//go:build L0
+// +build L0
+
+package cmd
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	commandparser "github.com/ready-to-release/eac/src/cli/internal/command-parser"
+	"github.com/ready-to-release/eac/src/cli/internal/conf"
+)
+
+func TestExtensionArgs(t *testing.T) {
+	cfg := &conf.Config{Extensions: []conf.Extension{{Name: "go"}, {Name: "python"}}}
+
+	testCases := map[string]struct {
+		commandLine string
+		want        []string
+	}{
+		"separator":               {commandLine: "r2r run go -- version", want: []string{"version"}},
+		"kept redirect":           {commandLine: "r2r run pwsh -- -c Get-Date 2>&1", want: []string{"-c", "Get-Date", "2>&1"}},
+		"separator only":          {commandLine: "r2r run go --", want: []string{}},
+		"no separator":            {commandLine: "r2r run go test ./...", want: []string{"test", "./..."}},
+		"separator of the tool":   {commandLine: "r2r run go test -- -v", want: []string{"test", "--", "-v"}},
+		"separator passed on":     {commandLine: "r2r run go -- -- -v", want: []string{"--", "-v"}},
+		"extension name as value": {commandLine: "r2r run go -- python", want: []string{"python"}},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			parsed := commandparser.NewParser().Parse(strings.Fields(tc.commandLine))
+			// A single extension runs: the arguments do not name several
+			positional := append([]string{parsed.ExtensionName}, parsed.ContainerArgs...)
+			if _, _, ok := multiRunTargets(cfg, positional, false); ok {
+				t.Fatalf("%q runs several extensions", tc.commandLine)
+			}
+			if got := extensionArgs(parsed.ContainerArgs); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("extensionArgs(%q) = %q, want %q", parsed.ContainerArgs, got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,15 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// interruptContext returns a context cancelled on SIGINT or SIGTERM. Calling
+// stop restores the default signal handling, so a second Ctrl-C terminates
+// the process even while cleanup is still running.
+func interruptContext(parent context.Context) (ctx context.Context, stop context.CancelFunc) {
+	return signal.NotifyContext(parent, os.Interrupt, syscall.SIGTERM)
+}
@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ready-to-release/eac/src/core/ai"
+	"github.com/ready-to-release/eac/src/core/repository"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	RootCmd.AddCommand(StatsCmd)
+	StatsCmd.AddCommand(StatsAgentsCmd)
+
+	StatsAgentsCmd.Flags().Int("days", 30, "Number of days to include, including today")
+	StatsAgentsCmd.Flags().Bool("by-stage", false, "Break each day down by pipeline stage")
+	StatsAgentsCmd.Flags().Bool("json", false, "Print the statistics as a JSON array")
+}
+
+var StatsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show usage statistics",
+}
+
+var StatsAgentsCmd = &cobra.Command{
+	Use:   "agents",
+	Short: "Show token usage and cost of agent calls per day",
+	Long: `Show the token usage and cost of AI agent calls per day, from
+.r2r/logs/ai-executions.jsonl.
+
+Token counts and cost come from the provider when it reports them. Otherwise
+tokens are estimated from the text length and cost from a price table; such
+values are marked with ~.
+
+Examples:
+  r2r stats agents
+  r2r stats agents --days 7 --by-stage
+  r2r stats agents --json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		days, _ := cmd.Flags().GetInt("days")
+		byStage, _ := cmd.Flags().GetBool("by-stage")
+		asJSON, _ := cmd.Flags().GetBool("json")
+		if days < 1 {
+			return fmt.Errorf("--days must be at least 1")
+		}
+
+		workspaceRoot, err := repository.GetRepositoryRoot("")
+		if err != nil {
+			return fmt.Errorf("failed to find repository root: %w", err)
+		}
+
+		records, err := ai.ReadExecutionLog(ai.ExecutionLogPath(workspaceRoot))
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		since := time.Date(now.Year(), now.Month(), now.Day()-(days-1), 0, 0, 0, 0, time.Local)
+		stats := ai.AggregateDaily(records, since, byStage)
+
+		if asJSON {
+			data, err := json.MarshalIndent(stats, "", "  ")
+			if err != nil {
+				return fmt.Errorf("failed to encode statistics: %w", err)
+			}
+			fmt.Println(string(data))
+			return nil
+		}
+
+		if len(stats) == 0 {
+			fmt.Printf("No agent calls in the last %d day(s)\n", days)
+			return nil
+		}
+
+		var total ai.Usage
+		failures := 0
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		if byStage {
+			fmt.Fprintln(w, "DATE\tSTAGE\tCALLS\tFAILED\tINPUT\tOUTPUT\tCOST\t")
+		} else {
+			fmt.Fprintln(w, "DATE\tCALLS\tFAILED\tINPUT\tOUTPUT\tCOST\t")
+		}
+		for _, day := range stats {
+			if byStage {
+				stage := day.Stage
+				if stage == "" {
+					stage = "-"
+				}
+				fmt.Fprintf(w, "%s\t%s\t", day.Date, stage)
+			} else {
+				fmt.Fprintf(w, "%s\t", day.Date)
+			}
+			fmt.Fprintf(w, "%d\t%d\t%d\t%d\t%s\t\n", day.Calls, day.Failures, day.InputTokens, day.OutputTokens, formatCost(day.Usage))
+			total.Add(day.Usage)
+			failures += day.Failures
+		}
+		w.Flush()
+
+		fmt.Printf("\n📊 Total: %s", total)
+		if failures > 0 {
+			fmt.Printf(", %d failed", failures)
+		}
+		fmt.Println()
+		return nil
+	},
+}
+
+// formatCost formats a cost in USD, marking estimates with ~
+func formatCost(usage ai.Usage) string {
+	cost := fmt.Sprintf("$%.4f", usage.CostUSD)
+	if usage.Estimated {
+		return "~" + cost
+	}
+	return cost
+}
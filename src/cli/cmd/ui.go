@@ -0,0 +1,162 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/ready-to-release/eac/src/cli/internal/conf"
+	"github.com/ready-to-release/eac/src/cli/internal/docker"
+	"github.com/ready-to-release/eac/src/cli/internal/extensions"
+	"github.com/ready-to-release/eac/src/cli/internal/jobs"
+	"github.com/ready-to-release/eac/src/cli/internal/terminal"
+	"github.com/ready-to-release/eac/src/cli/internal/tui"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	RootCmd.AddCommand(UICmd)
+	UICmd.Flags().Bool("json", false, "Print the dashboard data once as JSON instead of showing the dashboard")
+}
+
+var UICmd = &cobra.Command{
+	Use:   "ui",
+	Short: "Show a dashboard of extensions, containers and jobs",
+	Long: `Show an interactive dashboard of the repository.
+
+The dashboard lists the configured extensions and how their images are pinned,
+the running extension containers of every r2r process and the recent
+background jobs. It refreshes every few seconds.
+
+Keys:
+  tab        switch between extensions, containers and jobs
+  ↑/↓ j/k    select
+  r          run the selected extension as a background job
+  s          stop the selected container or cancel the selected job
+  l, enter   follow the output of the selected container or job
+  esc        close the output
+  o          open the docs_url of the selected extension
+  q          quit
+
+With --json the dashboard data is printed once, for scripts.`,
+	Example: `  r2r ui
+  r2r ui --json`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		asJSON, _ := cmd.Flags().GetBool("json")
+		cfg := conf.InitConfig()
+
+		installer, err := extensions.NewInstaller(cfg)
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to create extension installer")
+			exitProcess(exitCodeDockerError)
+		}
+		defer installer.Close()
+		host := installer.GetContainerHost()
+		source := &dashboardSource{
+			cfg:       cfg,
+			installer: installer,
+			host:      host,
+			manager:   jobs.NewManager(jobs.NewStore(jobs.DefaultDir(host.GetRootDir())), host),
+		}
+
+		ctx, stop := interruptContext(context.Background())
+		defer stop()
+
+		if asJSON {
+			snapshot, err := source.Snapshot(ctx)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+				exitProcess(exitCodeDockerError)
+			}
+			printJobsJSON(snapshot)
+			return
+		}
+
+		if !terminal.IsTerminal() {
+			fmt.Fprintln(os.Stderr, "❌ r2r ui needs a terminal, use r2r ui --json for scripts")
+			exitProcess(exitCodeError)
+		}
+		if err := tui.RunDashboard(ctx, source); err != nil && ctx.Err() == nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			exitProcess(exitCodeError)
+		}
+	},
+}
+
+// dashboardSource feeds the dashboard from the same container host and job
+// manager as r2r jobs
+type dashboardSource struct {
+	cfg       *conf.Config
+	installer *extensions.Installer
+	host      *docker.ContainerHost
+	manager   *jobs.Manager
+}
+
+func (s *dashboardSource) Snapshot(ctx context.Context) (*tui.Snapshot, error) {
+	containers, err := s.host.ListExtensionContainers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	jobList, err := s.manager.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	running := make(map[string]int)
+	for _, c := range containers {
+		running[c.Extension]++
+	}
+	snapshot := &tui.Snapshot{
+		Extensions: make([]tui.ExtensionStatus, 0, len(s.cfg.Extensions)),
+		Containers: containers,
+		Jobs:       jobList,
+		UpdatedAt:  time.Now().UTC(),
+	}
+	if snapshot.Jobs == nil {
+		snapshot.Jobs = []*jobs.Job{}
+	}
+	for _, ext := range s.cfg.Extensions {
+		snapshot.Extensions = append(snapshot.Extensions, tui.ExtensionStatus{
+			Name:    ext.Name,
+			Image:   ext.Image,
+			Pin:     conf.PinStatus(ext.Image),
+			DocsURL: ext.DocsURL,
+			Running: running[ext.Name],
+		})
+	}
+	return snapshot, nil
+}
+
+func (s *dashboardSource) Run(ctx context.Context, name string) (*jobs.Job, error) {
+	ext, err := s.host.FindExtension(name)
+	if err != nil {
+		return nil, err
+	}
+	if err := extensions.CheckDeclaredSchema(ext.Name, ext.MetadataSchemaVersion); err != nil {
+		return nil, err
+	}
+	if _, err := s.installer.EnsureExtensionImage(ctx, ext.Name); err != nil {
+		return nil, fmt.Errorf("failed to prepare image for %s: %w", ext.Name, err)
+	}
+	return s.manager.Submit(ctx, ext, nil)
+}
+
+func (s *dashboardSource) StopContainer(ctx context.Context, containerID string) error {
+	return s.host.StopContainer(ctx, containerID)
+}
+
+func (s *dashboardSource) CancelJob(ctx context.Context, jobID string) error {
+	_, err := s.manager.Cancel(ctx, jobID)
+	return err
+}
+
+func (s *dashboardSource) ContainerLogs(ctx context.Context, containerID string, w io.Writer) error {
+	return s.host.ContainerOutput(ctx, containerID, true, w)
+}
+
+func (s *dashboardSource) JobLogs(ctx context.Context, jobID string, w io.Writer) error {
+	return s.manager.Logs(ctx, jobID, true, w, w)
+}
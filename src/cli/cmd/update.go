@@ -16,7 +16,9 @@ import (
 	"strings"
 
 	"github.com/ready-to-release/eac/src/cli/internal/logger"
+	"github.com/ready-to-release/eac/src/cli/internal/terminal"
 	"github.com/ready-to-release/eac/src/cli/internal/version"
+	"github.com/ready-to-release/eac/src/core/redact"
 	"github.com/rs/zerolog"
 	"github.com/spf13/cobra"
 )
@@ -46,9 +48,9 @@ var updateCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		// Create a pretty console writer for update progress
 		prettyWriter := zerolog.ConsoleWriter{
-			Out:        os.Stdout,
+			Out:        redact.NewWriter(os.Stdout),
 			TimeFormat: "", // No timestamp for clean output
-			NoColor:    os.Getenv("NO_COLOR") != "",
+			NoColor:    !terminal.Detect().ColorEnabled(),
 			FormatLevel: func(i interface{}) string {
 				if level, ok := i.(string); ok {
 					switch level {
@@ -115,7 +117,7 @@ var updateCmd = &cobra.Command{
 		release, err := getLatestRelease()
 		if err != nil {
 			log.Error().Err(err).Msg("Failed to get latest release info")
-			os.Exit(1)
+			exitProcess(1)
 		}
 
 		currentVersion := strings.TrimPrefix(version.Version, "v")
@@ -199,7 +201,7 @@ var updateCmd = &cobra.Command{
 			for _, asset := range release.Assets {
 				verboseLogger.Info().Msgf("  - %s", asset.Name)
 			}
-			os.Exit(1)
+			exitProcess(1)
 		}
 
 		// Download new binary using GitHub API asset endpoint (matching installer pattern)
@@ -211,7 +213,7 @@ var updateCmd = &cobra.Command{
 		req, err := http.NewRequestWithContext(context.Background(), "GET", apiDownloadURL, nil)
 		if err != nil {
 			log.Error().Err(err).Msg("Failed to create download request")
-			os.Exit(1)
+			exitProcess(1)
 		}
 
 		// Add authentication headers (matching installer pattern)
@@ -225,13 +227,13 @@ var updateCmd = &cobra.Command{
 		resp, err := http.DefaultClient.Do(req)
 		if err != nil {
 			log.Error().Err(err).Msg("Failed to download update")
-			os.Exit(1)
+			exitProcess(1)
 		}
 		defer resp.Body.Close()
 
 		if resp.StatusCode != http.StatusOK {
 			log.Error().Msgf("Download failed with status %d", resp.StatusCode)
-			os.Exit(1)
+			exitProcess(1)
 		}
 
 		// Create temp file with appropriate extension
@@ -242,7 +244,7 @@ var updateCmd = &cobra.Command{
 		tmpFile, err := os.CreateTemp("", tempSuffix)
 		if err != nil {
 			log.Error().Err(err).Msg("Failed to create temporary file")
-			os.Exit(1)
+			exitProcess(1)
 		}
 		defer os.Remove(tmpFile.Name())
 
@@ -250,12 +252,12 @@ var updateCmd = &cobra.Command{
 		verboseLogger.Info().Msg("Downloading...")
 		if _, err := io.Copy(tmpFile, resp.Body); err != nil {
 			log.Error().Err(err).Msg("Failed to write update to temp file")
-			os.Exit(1)
+			exitProcess(1)
 		}
 
 		if err := tmpFile.Close(); err != nil {
 			log.Error().Err(err).Msg("Failed to close temp file")
-			os.Exit(1)
+			exitProcess(1)
 		}
 
 		// Validate downloaded file (matching installer pattern)
@@ -263,29 +265,29 @@ var updateCmd = &cobra.Command{
 		fileInfo, err := os.Stat(tmpFile.Name())
 		if err != nil {
 			log.Error().Err(err).Msg("Failed to get file info")
-			os.Exit(1)
+			exitProcess(1)
 		}
 
 		if fileInfo.Size() == 0 {
 			log.Error().Msg("Downloaded file is empty")
-			os.Exit(1)
+			exitProcess(1)
 		}
 
 		if fileInfo.Size() < 1000 {
 			log.Error().Msgf("Downloaded file is too small (%d bytes) - likely corrupted", fileInfo.Size())
-			os.Exit(1)
+			exitProcess(1)
 		}
 
 		// Check file headers (matching installer pattern)
 		fileBytes, err := os.ReadFile(tmpFile.Name())
 		if err != nil {
 			log.Error().Err(err).Msg("Failed to read downloaded file")
-			os.Exit(1)
+			exitProcess(1)
 		}
 
 		if len(fileBytes) < 2 {
 			log.Error().Msg("Downloaded file is too small to be valid")
-			os.Exit(1)
+			exitProcess(1)
 		}
 
 		isZipFile := strings.HasSuffix(selectedAsset.Name, ".zip")
@@ -295,19 +297,19 @@ var updateCmd = &cobra.Command{
 			// Check for ZIP header
 			if len(fileBytes) < 2 || string(fileBytes[0:2]) != "PK" {
 				log.Error().Msg("Downloaded file is not a valid ZIP archive")
-				os.Exit(1)
+				exitProcess(1)
 			}
 		} else if isTarGzFile {
 			// Check for gzip header (1f 8b)
 			if len(fileBytes) < 2 || fileBytes[0] != 0x1f || fileBytes[1] != 0x8b {
 				log.Error().Msg("Downloaded file is not a valid gzip archive")
-				os.Exit(1)
+				exitProcess(1)
 			}
 		} else {
 			// Check for PE header (Windows executable)
 			if runtime.GOOS == "windows" && (len(fileBytes) < 2 || string(fileBytes[0:2]) != "MZ") {
 				log.Error().Msg("Downloaded file is not a valid Windows executable")
-				os.Exit(1)
+				exitProcess(1)
 			}
 		}
 
@@ -317,12 +319,12 @@ var updateCmd = &cobra.Command{
 		exePath, err := os.Executable()
 		if err != nil {
 			log.Error().Err(err).Msg("Failed to get executable path")
-			os.Exit(1)
+			exitProcess(1)
 		}
 		exePath, err = filepath.EvalSymlinks(exePath)
 		if err != nil {
 			log.Error().Err(err).Msg("Failed to resolve executable path")
-			os.Exit(1)
+			exitProcess(1)
 		}
 
 		var binaryPath string
@@ -334,7 +336,7 @@ var updateCmd = &cobra.Command{
 			extractDir := filepath.Join(os.TempDir(), fmt.Sprintf("r2r-cli-extract-%d", os.Getpid()))
 			if err := os.MkdirAll(extractDir, 0755); err != nil {
 				log.Error().Err(err).Msg("Failed to create extraction directory")
-				os.Exit(1)
+				exitProcess(1)
 			}
 			defer os.RemoveAll(extractDir)
 
@@ -342,7 +344,7 @@ var updateCmd = &cobra.Command{
 			reader, err := zip.OpenReader(tmpFile.Name())
 			if err != nil {
 				log.Error().Err(err).Msg("Failed to open ZIP file")
-				os.Exit(1)
+				exitProcess(1)
 			}
 			defer reader.Close()
 
@@ -383,7 +385,7 @@ var updateCmd = &cobra.Command{
 
 			if foundExe == "" {
 				log.Error().Msg("No executable found in ZIP archive")
-				os.Exit(1)
+				exitProcess(1)
 			}
 
 			binaryPath = foundExe
@@ -395,7 +397,7 @@ var updateCmd = &cobra.Command{
 			extractDir := filepath.Join(os.TempDir(), fmt.Sprintf("r2r-cli-extract-%d", os.Getpid()))
 			if err := os.MkdirAll(extractDir, 0755); err != nil {
 				log.Error().Err(err).Msg("Failed to create extraction directory")
-				os.Exit(1)
+				exitProcess(1)
 			}
 			defer os.RemoveAll(extractDir)
 
@@ -403,7 +405,7 @@ var updateCmd = &cobra.Command{
 			file, err := os.Open(tmpFile.Name())
 			if err != nil {
 				log.Error().Err(err).Msg("Failed to open tar.gz file")
-				os.Exit(1)
+				exitProcess(1)
 			}
 			defer file.Close()
 
@@ -411,7 +413,7 @@ var updateCmd = &cobra.Command{
 			gzReader, err := gzip.NewReader(file)
 			if err != nil {
 				log.Error().Err(err).Msg("Failed to create gzip reader")
-				os.Exit(1)
+				exitProcess(1)
 			}
 			defer gzReader.Close()
 
@@ -426,7 +428,7 @@ var updateCmd = &cobra.Command{
 				}
 				if err != nil {
 					log.Error().Err(err).Msg("Failed to read tar header")
-					os.Exit(1)
+					exitProcess(1)
 				}
 
 				// Look for executable files (r2r-cli* without extension)
@@ -465,7 +467,7 @@ var updateCmd = &cobra.Command{
 
 			if foundExe == "" {
 				log.Error().Msg("No executable found in tar.gz archive")
-				os.Exit(1)
+				exitProcess(1)
 			}
 
 			binaryPath = foundExe
@@ -478,7 +480,7 @@ var updateCmd = &cobra.Command{
 		if runtime.GOOS != "windows" {
 			if err := os.Chmod(binaryPath, 0755); err != nil {
 				log.Error().Err(err).Msg("Failed to make binary executable")
-				os.Exit(1)
+				exitProcess(1)
 			}
 		}
 
@@ -489,7 +491,7 @@ var updateCmd = &cobra.Command{
 			bakPath := exePath + ".bak"
 			if err := os.Rename(exePath, bakPath); err != nil {
 				log.Error().Err(err).Msg("Failed to rename current executable")
-				os.Exit(1)
+				exitProcess(1)
 			}
 			if err := copyFile(binaryPath, exePath); err != nil {
 				// Try to restore backup on failure
@@ -497,13 +499,13 @@ var updateCmd = &cobra.Command{
 					log.Error().Err(restoreErr).Msg("Failed to restore backup executable")
 				}
 				log.Error().Err(err).Msg("Failed to copy new executable into place")
-				os.Exit(1)
+				exitProcess(1)
 			}
 			os.Remove(bakPath)
 		} else {
 			if err := copyFile(binaryPath, exePath); err != nil {
 				log.Error().Err(err).Msg("Failed to replace current executable")
-				os.Exit(1)
+				exitProcess(1)
 			}
 		}
 
@@ -9,7 +9,6 @@ import (
 	"github.com/ready-to-release/eac/src/cli/internal/conf"
 	"github.com/ready-to-release/eac/src/cli/internal/validator"
 	"github.com/spf13/cobra"
-	"github.com/spf13/viper"
 )
 
 var (
@@ -30,6 +29,19 @@ This command checks your configuration file for:
   - Resource limits and port ranges
   - Duplicate extension names
 
+It also warns about risky settings:
+  - unpinned-tag: images without a version tag or digest, or using :latest
+  - missing-resource-limits: extensions without memory_limit or cpu_limit
+  - privileged-extension: extensions running privileged
+  - host-network: extensions using network_mode: host
+
+Severities can be changed per rule in the configuration file:
+
+  validation:
+    severities:
+      unpinned-tag: error
+      missing-resource-limits: off
+
 Examples:
   # Validate the default configuration file
   r2r validate
@@ -37,7 +49,7 @@ Examples:
   # Validate a specific configuration file
   r2r validate ./r2r-cli.local.yml
 
-  # Use strict validation mode (warnings become errors)
+  # Use strict validation mode in CI (warnings become errors)
   r2r validate --strict
 
   # Show the embedded schema version and details
@@ -69,23 +81,19 @@ Examples:
 
 		fmt.Printf("Validating configuration file: %s\n", configFile)
 
-		// Load the configuration using viper
-		viper.SetConfigFile(configFile)
-		if err := viper.ReadInConfig(); err != nil {
+		data, err := os.ReadFile(configFile)
+		if err != nil {
 			return fmt.Errorf("failed to read configuration file: %w", err)
 		}
 
-		// Get the raw configuration as a map (preserves field names)
-		configMap := viper.AllSettings()
-
 		// Create validator
 		v, err := validator.NewEmbeddedValidator()
 		if err != nil {
 			return fmt.Errorf("failed to initialize validator: %w", err)
 		}
 
-		// Validate the configuration using the raw map
-		result, err := v.ValidateInterface(configMap)
+		// Validate the YAML document itself so errors keep their positions
+		result, err := v.ValidateYAML(data, configFile)
 		if err != nil {
 			return fmt.Errorf("validation error: %w", err)
 		}
@@ -100,15 +108,7 @@ Examples:
 		if len(result.Errors) > 0 {
 			fmt.Println("❌ Validation errors found:")
 			for _, e := range result.Errors {
-				if e.Field != "" {
-					fmt.Printf("  - %s: %s", e.Field, e.Message)
-				} else {
-					fmt.Printf("  - %s", e.Message)
-				}
-				if e.Expected != "" && e.Expected != e.Rule {
-					fmt.Printf(" (expected: %s)", e.Expected)
-				}
-				fmt.Println()
+				printValidationError(e)
 			}
 		}
 
@@ -116,12 +116,7 @@ Examples:
 		if len(result.Warnings) > 0 {
 			fmt.Println("⚠️  Validation warnings:")
 			for _, w := range result.Warnings {
-				if w.Field != "" {
-					fmt.Printf("  - %s: %s", w.Field, w.Message)
-				} else {
-					fmt.Printf("  - %s", w.Message)
-				}
-				fmt.Println()
+				printValidationError(w)
 			}
 		}
 
@@ -149,6 +144,26 @@ func init() {
 		"Display the embedded schema information")
 }
 
+// printValidationError prints one error as "file:line:column: field: message"
+// followed by its quick-fix suggestion, if any
+func printValidationError(e validator.ValidationError) {
+	fmt.Print("  - ")
+	if location := e.Location(); location != "" {
+		fmt.Printf("%s: ", location)
+	}
+	if e.Field != "" {
+		fmt.Printf("%s: ", e.Field)
+	}
+	fmt.Print(e.Message)
+	if e.Expected != "" && e.Expected != e.Rule {
+		fmt.Printf(" (expected: %s)", e.Expected)
+	}
+	fmt.Println()
+	if e.Suggestion != "" {
+		fmt.Printf("    💡 %s\n", e.Suggestion)
+	}
+}
+
 // showEmbeddedSchema displays information about the embedded schema
 func showEmbeddedSchema() error {
 	fmt.Printf("Embedded Schema Information:\n")
@@ -2,11 +2,12 @@ package cmd
 
 import (
 	"context"
-	"os"
+	"errors"
+	"strings"
 
 	"github.com/docker/docker/client"
+	"github.com/ready-to-release/eac/src/cli/internal/auth"
 	"github.com/ready-to-release/eac/src/cli/internal/conf"
-	"github.com/ready-to-release/eac/src/cli/internal/docker"
 	"github.com/ready-to-release/eac/src/cli/internal/validator"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -29,12 +30,12 @@ func verifySystem(cmd *cobra.Command) {
 	cmd.Println("🔍 Verifying system prerequisites...")
 
 	// Initialize configuration - will exit with detailed error if it fails
-	conf.InitConfig()
+	cfg := conf.InitConfig()
 
 	allChecksPass := true
 
 	// Check config file (just reports it's verified since InitConfig succeeded)
-	if !checkConfigFile(cmd) {
+	if !checkConfigFile(cmd, cfg) {
 		allChecksPass = false
 	}
 
@@ -54,26 +55,37 @@ func verifySystem(cmd *cobra.Command) {
 	} else {
 		cmd.PrintErrln("❌ Some checks failed. Please fix the issues above.")
 		log.Error().Msg("System verification failed")
-		os.Exit(1)
+		exitProcess(1)
 	}
 }
 
 func checkGitHubAuth(cmd *cobra.Command) bool {
 	cmd.Println("🔑 Checking GitHub authentication...")
 
-	// Use centralized authentication function
-	authConfig, authStr, err := docker.CreateGitHubAuthConfig()
+	// Credentials come from the same manager as registry lookups and pulls
+	cred, err := auth.Default().Get(cmd.Context(), auth.GHCR)
 	if err != nil {
 		cmd.PrintErrf("❌ %v\n", err)
 		return false
 	}
 
-	cmd.Printf("✅ GitHub credentials found (username: %s)\n", authConfig.Username)
+	cmd.Printf("✅ GitHub credentials found (username: %s, source: %s)\n", cred.Username, cred.Source)
 
-	// Validate the auth string was created successfully
-	if authStr == "" {
-		cmd.PrintErrln("❌ Error creating authentication string")
+	check, err := auth.ValidateScopes(cmd.Context(), cred, auth.ScopeReadPackages)
+	switch {
+	case errors.Is(err, auth.ErrTokenRejected):
+		cmd.PrintErrf("❌ %v\n", err)
+		return false
+	case err != nil:
+		cmd.Printf("⚠️  Could not check token scopes: %v\n", err)
+	case !check.Known:
+		cmd.Println("ℹ️  Token scopes are not reported for this token type; make sure it can read packages")
+	case len(check.Missing) > 0:
+		cmd.PrintErrf("❌ Token is missing the scope(s) %s needed to pull extensions (has: %s)\n",
+			strings.Join(check.Missing, ", "), strings.Join(check.Scopes, ", "))
 		return false
+	default:
+		cmd.Printf("✅ Token has the %s scope\n", auth.ScopeReadPackages)
 	}
 
 	cmd.Println("✅ GitHub authentication configuration is valid")
@@ -113,7 +125,7 @@ func checkDockerService(cmd *cobra.Command) bool {
 	return true
 }
 
-func checkConfigFile(cmd *cobra.Command) bool {
+func checkConfigFile(cmd *cobra.Command, cfg *conf.Config) bool {
 	cmd.Println("📋 Checking r2r-cli configuration...")
 	
 	// Configuration is already loaded and verified at startup
@@ -122,13 +134,13 @@ func checkConfigFile(cmd *cobra.Command) bool {
 	
 	// The configuration has already been validated during loading in conf.InitConfig()
 	// Check if we have at least one extension configured
-	if len(conf.Global.Extensions) == 0 {
+	if len(cfg.Extensions) == 0 {
 		cmd.Println("⚠️  No extensions configured")
 		return false
 	}
 	
 	// Verify each extension has required fields
-	for i, ext := range conf.Global.Extensions {
+	for i, ext := range cfg.Extensions {
 		if ext.Name == "" {
 			cmd.Printf("❌ Extension %d: missing name\n", i)
 			return false
@@ -140,12 +152,12 @@ func checkConfigFile(cmd *cobra.Command) bool {
 	}
 	
 	// If we got here, config is valid (it was already validated during load)
-	cmd.Printf("✅ Configuration valid with %d extension(s)\n", len(conf.Global.Extensions))
+	cmd.Printf("✅ Configuration valid with %d extension(s)\n", len(cfg.Extensions))
 	return true
 }
 
 // Original validator-based function kept for reference but not used
-func checkConfigFileWithValidator(cmd *cobra.Command) bool {
+func checkConfigFileWithValidator(cmd *cobra.Command, cfg *conf.Config) bool {
 	cmd.Println("📋 Checking r2r-cli configuration...")
 	cmd.Println("✅ Configuration file loaded")
 	
@@ -192,8 +204,8 @@ func checkConfigFileWithValidator(cmd *cobra.Command) bool {
 	cmd.Printf("✅ Configuration is valid (schema version: %s)\n", validator.GetEmbeddedSchemaVersion())
 	
 	// Show summary of what's configured
-	if len(conf.Global.Extensions) > 0 {
-		cmd.Printf("   Extensions configured: %d\n", len(conf.Global.Extensions))
+	if len(cfg.Extensions) > 0 {
+		cmd.Printf("   Extensions configured: %d\n", len(cfg.Extensions))
 	}
 	
 	return true
@@ -9,6 +9,9 @@ require (
 	github.com/cucumber/godog v0.15.1
 	github.com/docker/docker v28.0.0+incompatible
 	github.com/hitoshi44/go-uid64 v0.2.0
+	github.com/moby/term v0.5.2
+	github.com/opencontainers/image-spec v1.1.1
+	github.com/ready-to-release/eac/contracts v0.0.0
 	github.com/ready-to-release/eac/src/core v0.0.0
 	github.com/ready-to-release/eac/src/core/ai v0.0.0-00010101000000-000000000000
 	github.com/rs/zerolog v1.34.0
@@ -28,6 +31,7 @@ require (
 	cloud.google.com/go/auth/oauth2adapt v0.2.3 // indirect
 	cloud.google.com/go/compute/metadata v0.7.0 // indirect
 	cloud.google.com/go/longrunning v0.5.7 // indirect
+	github.com/Azure/go-ansiterm v0.0.0-20250102033503-faa5f7b0171c // indirect
 	github.com/Microsoft/go-winio v0.4.21 // indirect
 	github.com/anthropics/anthropic-sdk-go v1.17.0 // indirect
 	github.com/aymanbagabas/go-osc52/v2 v2.0.1 // indirect
@@ -68,13 +72,11 @@ require (
 	github.com/mattn/go-localereader v0.0.1 // indirect
 	github.com/mattn/go-runewidth v0.0.16 // indirect
 	github.com/moby/docker-image-spec v1.3.1 // indirect
-	github.com/moby/term v0.5.2 // indirect
 	github.com/morikuni/aec v1.0.0 // indirect
 	github.com/muesli/ansi v0.0.0-20230316100256-276c6243b2f6 // indirect
 	github.com/muesli/cancelreader v0.2.2 // indirect
 	github.com/muesli/termenv v0.16.0 // indirect
 	github.com/opencontainers/go-digest v1.0.0 // indirect
-	github.com/opencontainers/image-spec v1.1.1 // indirect
 	github.com/pelletier/go-toml/v2 v2.2.4 // indirect
 	github.com/pkg/errors v0.9.1 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
@@ -116,6 +118,8 @@ require (
 	gotest.tools/v3 v3.5.2 // indirect
 )
 
+replace github.com/ready-to-release/eac/contracts => ../../contracts
+
 replace github.com/ready-to-release/eac/src/core => ../core
 
 replace github.com/ready-to-release/eac/src/core/ai => ../core/ai
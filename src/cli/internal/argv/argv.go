@@ -0,0 +1,121 @@
+// Package argv repairs the command line before it is parsed. Some callers,
+// such as agents running r2r through a shell wrapper on Windows, pass the
+// stderr redirect "2>&1" to r2r as arguments instead of applying it. Only
+// the trailing arguments of "r2r run <extension>" are repaired, since these
+// are forwarded to the extension container where a stray "2" changes the
+// command. Everything after "--" is passed as it is, and R2R_NO_ARGV_REPAIR
+// turns the repair off.
+package argv
+
+import (
+	"strconv"
+
+	commandparser "github.com/ready-to-release/eac/src/cli/internal/command-parser"
+)
+
+// DisableEnv names the environment variable that turns the repair off when
+// set to a true value
+const DisableEnv = "R2R_NO_ARGV_REPAIR"
+
+// Separator marks the following arguments as literal
+const Separator = "--"
+
+// redirects are the trailing argument sequences a stderr redirect is split
+// into. Longer sequences come first, so "2 > &1" is not taken for "&1".
+var redirects = [][]string{
+	{"2", ">", "&1"},
+	{"2>", "&1"},
+	{"2", ">&1"},
+	{"2", "&1"},
+	{"2>&1"},
+}
+
+// knownWrappers are the extensions invoked by wrappers that are known to
+// drop the ">&1" of a redirect and leave a single "2" after the script
+var knownWrappers = map[string]bool{
+	"pwsh":       true,
+	"powershell": true,
+}
+
+// scriptFlags are the flags of the known wrappers that take the whole
+// script as one argument
+var scriptFlags = map[string]bool{
+	"-c":       true,
+	"-Command": true,
+	"-command": true,
+}
+
+// Result is a preprocessed command line
+type Result struct {
+	Args    []string // the arguments to parse
+	Removed []string // the trailing arguments that were removed
+}
+
+// Repaired reports whether any argument was removed
+func (r Result) Repaired() bool {
+	return len(r.Removed) > 0
+}
+
+// Preprocess returns args, including the binary name, without a trailing
+// redirect. getenv is os.Getenv outside of tests.
+func Preprocess(args []string, getenv func(string) string) Result {
+	result := Result{Args: args}
+	if disabled, err := strconv.ParseBool(getenv(DisableEnv)); err == nil && disabled {
+		return result
+	}
+
+	parsed := commandparser.NewParser().Parse(args)
+	if parsed.Subcommand != "run" || parsed.ExtensionName == "" {
+		return result
+	}
+	container := parsed.ContainerArgs
+	for _, arg := range container {
+		if arg == Separator {
+			return result
+		}
+	}
+
+	n := trailingRedirect(container)
+	if n == 0 && knownWrappers[parsed.ExtensionName] && strayRedirect(container) {
+		n = 1
+	}
+	if n == 0 {
+		return result
+	}
+
+	keep := len(args) - n
+	result.Args = args[:keep:keep]
+	result.Removed = args[keep:]
+	return result
+}
+
+// trailingRedirect returns the number of trailing arguments that form a
+// complete redirect
+func trailingRedirect(args []string) int {
+	for _, redirect := range redirects {
+		if hasSuffix(args, redirect) {
+			return len(redirect)
+		}
+	}
+	return 0
+}
+
+// strayRedirect reports whether args are a script flag, the script and the
+// "2" left of a redirect, such as `-c "Get-ChildItem" 2`. Any other "2" may
+// be a value and is kept.
+func strayRedirect(args []string) bool {
+	return len(args) == 3 && scriptFlags[args[0]] && args[2] == "2"
+}
+
+func hasSuffix(args, suffix []string) bool {
+	if len(args) < len(suffix) {
+		return false
+	}
+	offset := len(args) - len(suffix)
+	for i, arg := range suffix {
+		if args[offset+i] != arg {
+			return false
+		}
+	}
+	return true
+}
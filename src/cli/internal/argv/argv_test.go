@@ -0,0 +1,85 @@
+//go:build L0
+// +build L0
+
+package argv
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPreprocess(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    string
+		env     map[string]string
+		want    string
+		removed string
+	}{
+		// Complete redirects at the end of a run
+		{name: "joined redirect", args: "r2r run go test ./... 2>&1", want: "r2r run go test ./...", removed: "2>&1"},
+		{name: "redirect split after 2>", args: "r2r run go test 2> &1", want: "r2r run go test", removed: "2> &1"},
+		{name: "redirect split before >&1", args: "r2r run go test 2 >&1", want: "r2r run go test", removed: "2 >&1"},
+		{name: "redirect without >", args: "r2r run go test 2 &1", want: "r2r run go test", removed: "2 &1"},
+		{name: "redirect split in three", args: "r2r run go test 2 > &1", want: "r2r run go test", removed: "2 > &1"},
+		{name: "redirect only", args: "r2r run pwsh 2>&1", want: "r2r run pwsh", removed: "2>&1"},
+		{name: "after global and run flags", args: "r2r --r2r-debug run --json go build 2>&1", want: "r2r --r2r-debug run --json go build", removed: "2>&1"},
+		{name: "full binary path", args: `C:\tools\r2r.exe run go lint 2>&1`, want: `C:\tools\r2r.exe run go lint`, removed: "2>&1"},
+
+		// A single 2 is only removed after the script of a known wrapper
+		{name: "pwsh -c script", args: "r2r run pwsh -c Get-ChildItem 2", want: "r2r run pwsh -c Get-ChildItem", removed: "2"},
+		{name: "pwsh -Command script", args: "r2r run pwsh -Command Invoke-Build 2", want: "r2r run pwsh -Command Invoke-Build", removed: "2"},
+		{name: "powershell -c script", args: "r2r run powershell -c Get-Date 2", want: "r2r run powershell -c Get-Date", removed: "2"},
+		{name: "pwsh -File argument", args: "r2r run pwsh -File build.ps1 -Retries 2", want: "r2r run pwsh -File build.ps1 -Retries 2"},
+		{name: "pwsh value after script", args: "r2r run pwsh -c Start-Sleep -Seconds 2", want: "r2r run pwsh -c Start-Sleep -Seconds 2"},
+		{name: "other extension", args: "r2r run go test -count 2", want: "r2r run go test -count 2"},
+		{name: "bash -c script", args: "r2r run bash -c make 2", want: "r2r run bash -c make 2"},
+		{name: "only 2", args: "r2r run pwsh 2", want: "r2r run pwsh 2"},
+
+		// Redirect tokens that are not at the end are arguments
+		{name: "redirect in the middle", args: "r2r run go test 2>&1 ./...", want: "r2r run go test 2>&1 ./..."},
+		{name: "&1 alone", args: "r2r run go echo &1", want: "r2r run go echo &1"},
+
+		// Arguments after -- are literal
+		{name: "separator", args: "r2r run pwsh -- -c Get-ChildItem 2", want: "r2r run pwsh -- -c Get-ChildItem 2"},
+		{name: "separator before redirect", args: "r2r run go echo -- 2>&1", want: "r2r run go echo -- 2>&1"},
+		{name: "multi-run separator", args: "r2r run go python -- test 2>&1", want: "r2r run go python -- test 2>&1"},
+
+		// Only run is repaired
+		{name: "other command", args: "r2r jobs logs 2", want: "r2r jobs logs 2"},
+		{name: "other command with redirect", args: "r2r version 2>&1", want: "r2r version 2>&1"},
+		{name: "run without extension", args: "r2r run --json", want: "r2r run --json"},
+		{name: "binary only", args: "r2r", want: "r2r"},
+
+		// Opt-out
+		{name: "disabled", args: "r2r run go test 2>&1", env: map[string]string{DisableEnv: "true"}, want: "r2r run go test 2>&1"},
+		{name: "disabled with 1", args: "r2r run pwsh -c Get-Date 2", env: map[string]string{DisableEnv: "1"}, want: "r2r run pwsh -c Get-Date 2"},
+		{name: "explicitly enabled", args: "r2r run go test 2>&1", env: map[string]string{DisableEnv: "false"}, want: "r2r run go test", removed: "2>&1"},
+		{name: "invalid opt-out value", args: "r2r run go test 2>&1", env: map[string]string{DisableEnv: "maybe"}, want: "r2r run go test", removed: "2>&1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			getenv := func(key string) string { return tt.env[key] }
+			result := Preprocess(strings.Fields(tt.args), getenv)
+			assert.Equal(t, tt.want, strings.Join(result.Args, " "))
+			assert.Equal(t, tt.removed, strings.Join(result.Removed, " "))
+			assert.Equal(t, tt.removed != "", result.Repaired())
+		})
+	}
+}
+
+func TestPreprocessKeepsInput(t *testing.T) {
+	args := []string{"r2r", "run", "go", "test", "2>&1"}
+	result := Preprocess(args, func(string) string { return "" })
+	result.Args = append(result.Args, "-v")
+	assert.Equal(t, []string{"r2r", "run", "go", "test", "2>&1"}, args, "appending to the result does not overwrite the input")
+}
+
+func TestPreprocessEmpty(t *testing.T) {
+	result := Preprocess(nil, func(string) string { return "" })
+	assert.Empty(t, result.Args)
+	assert.False(t, result.Repaired())
+}
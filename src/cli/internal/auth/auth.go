@@ -0,0 +1,172 @@
+// Package auth resolves registry credentials for the CLI. A Manager asks a
+// chain of providers (environment, GitHub CLI, Docker config and credential
+// helpers, OS keychain) in order and caches the first credential found, so
+// the GHCR API client and Docker pulls share one source of truth.
+package auth
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/api/types/registry"
+	"github.com/ready-to-release/eac/src/core/redact"
+	"github.com/rs/zerolog/log"
+)
+
+// GHCR is the host of the GitHub Container Registry
+const GHCR = "ghcr.io"
+
+// DefaultUsername is used with a token whose source does not name a user.
+// GHCR accepts any username with a personal access token or GITHUB_TOKEN.
+const DefaultUsername = "github-actions"
+
+// DefaultTTL is how long a resolved credential is cached when its provider
+// gives no expiry
+const DefaultTTL = 15 * time.Minute
+
+// ErrNoCredentials is returned by a provider that has no credential for a
+// host, and by Manager.Get when no provider has one
+var ErrNoCredentials = errors.New("no credentials found")
+
+// Credential is a username and token for a registry host
+type Credential struct {
+	Host      string
+	Username  string
+	Token     string
+	Source    string    // name of the provider that resolved it
+	ExpiresAt time.Time // zero when the provider gives no expiry
+}
+
+// AuthConfig returns the credential in the form of the Docker API
+func (c *Credential) AuthConfig() registry.AuthConfig {
+	return registry.AuthConfig{
+		Username:      c.Username,
+		Password:      c.Token,
+		ServerAddress: c.Host,
+	}
+}
+
+// EncodedAuth returns the base64-encoded auth config Docker expects in the
+// X-Registry-Auth header of image pulls
+func (c *Credential) EncodedAuth() (string, error) {
+	data, err := json.Marshal(c.AuthConfig())
+	if err != nil {
+		return "", fmt.Errorf("error encoding auth config: %w", err)
+	}
+	return base64.StdEncoding.EncodeToString(data), nil
+}
+
+// Provider is one source of credentials
+type Provider interface {
+	// Name identifies the provider in logs and in Credential.Source
+	Name() string
+	// Resolve returns the credential for host, or ErrNoCredentials
+	Resolve(ctx context.Context, host string) (*Credential, error)
+}
+
+// Manager resolves credentials through its providers and caches them per
+// host until they expire or are invalidated
+type Manager struct {
+	providers []Provider
+	ttl       time.Duration
+	now       func() time.Time
+
+	mu    sync.Mutex
+	cache map[string]*Credential
+}
+
+// NewManager returns a manager asking providers in the given order
+func NewManager(providers ...Provider) *Manager {
+	return &Manager{
+		providers: providers,
+		ttl:       DefaultTTL,
+		now:       time.Now,
+		cache:     make(map[string]*Credential),
+	}
+}
+
+// DefaultProviders returns the providers of the default manager, in order of
+// precedence: GITHUB_TOKEN, the GitHub CLI, the Docker config and its
+// credential helpers, and the OS keychain
+func DefaultProviders() []Provider {
+	return []Provider{
+		EnvProvider{TokenEnv: "GITHUB_TOKEN", UsernameEnv: "GITHUB_USERNAME"},
+		GitHubCLIProvider{},
+		DockerConfigProvider{},
+		KeychainProvider{},
+	}
+}
+
+var (
+	defaultManager     *Manager
+	defaultManagerOnce sync.Once
+)
+
+// Default returns the manager shared by the registry client and Docker pulls
+func Default() *Manager {
+	defaultManagerOnce.Do(func() {
+		defaultManager = NewManager(DefaultProviders()...)
+	})
+	return defaultManager
+}
+
+// Get returns the credential for host from the cache, or resolves it through
+// the providers
+func (m *Manager) Get(ctx context.Context, host string) (*Credential, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if cred, ok := m.cache[host]; ok && m.now().Before(cred.ExpiresAt) {
+		return cred, nil
+	}
+
+	var failures []string
+	for _, provider := range m.providers {
+		cred, err := provider.Resolve(ctx, host)
+		if errors.Is(err, ErrNoCredentials) {
+			continue
+		}
+		if err != nil {
+			// A broken source does not hide the ones after it
+			log.Debug().Err(err).Str("provider", provider.Name()).Str("host", host).Msg("Credential provider failed")
+			failures = append(failures, fmt.Sprintf("%s: %v", provider.Name(), err))
+			continue
+		}
+
+		cred.Host = host
+		cred.Source = provider.Name()
+		if cred.Username == "" {
+			cred.Username = DefaultUsername
+		}
+		if expires := m.now().Add(m.ttl); cred.ExpiresAt.IsZero() || cred.ExpiresAt.After(expires) {
+			cred.ExpiresAt = expires
+		}
+		m.cache[host] = cred
+		// Tokens of the GitHub CLI, Docker config or keychain are not in the
+		// environment, so the redactor has to be told about them
+		redact.AddSecret(cred.Token)
+		log.Debug().Str("provider", cred.Source).Str("host", host).Str("username", cred.Username).Msg("Resolved registry credentials")
+		return cred, nil
+	}
+
+	err := fmt.Errorf("%w for %s: set GITHUB_TOKEN or run 'gh auth login'", ErrNoCredentials, host)
+	if len(failures) > 0 {
+		err = fmt.Errorf("%w (%s)", err, strings.Join(failures, "; "))
+	}
+	return nil, err
+}
+
+// Invalidate drops the cached credential of host, so the next Get resolves
+// it again. Callers invalidate after the registry rejected a credential, for
+// example because a token was rotated or revoked.
+func (m *Manager) Invalidate(host string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.cache, host)
+}
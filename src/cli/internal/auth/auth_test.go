@@ -0,0 +1,194 @@
+//go:build L1
+// +build L1
+
+package auth
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeProvider returns a fixed credential and counts its calls
+type fakeProvider struct {
+	name  string
+	cred  *Credential
+	err   error
+	calls int
+}
+
+func (p *fakeProvider) Name() string { return p.name }
+
+func (p *fakeProvider) Resolve(ctx context.Context, host string) (*Credential, error) {
+	p.calls++
+	if p.err != nil {
+		return nil, p.err
+	}
+	cred := *p.cred
+	return &cred, nil
+}
+
+// stubCommands replaces runCommand with canned outputs by command name
+func stubCommands(t *testing.T, outputs map[string]string) {
+	t.Helper()
+	original := runCommand
+	runCommand = func(ctx context.Context, stdin string, name string, args ...string) ([]byte, error) {
+		if out, ok := outputs[name]; ok {
+			return []byte(out), nil
+		}
+		return nil, errors.New(name + ": not found")
+	}
+	t.Cleanup(func() { runCommand = original })
+}
+
+func TestManagerOrderAndCache(t *testing.T) {
+	missing := &fakeProvider{name: "missing", err: ErrNoCredentials}
+	broken := &fakeProvider{name: "broken", err: errors.New("keychain locked")}
+	found := &fakeProvider{name: "found", cred: &Credential{Token: "t1"}}
+	later := &fakeProvider{name: "later", cred: &Credential{Token: "t2"}}
+	m := NewManager(missing, broken, found, later)
+
+	cred, err := m.Get(context.Background(), GHCR)
+	require.NoError(t, err)
+	assert.Equal(t, "t1", cred.Token)
+	assert.Equal(t, "found", cred.Source)
+	assert.Equal(t, DefaultUsername, cred.Username)
+	assert.Equal(t, GHCR, cred.Host)
+	assert.Zero(t, later.calls)
+
+	_, err = m.Get(context.Background(), GHCR)
+	require.NoError(t, err)
+	assert.Equal(t, 1, found.calls, "served from the cache")
+
+	m.Invalidate(GHCR)
+	_, err = m.Get(context.Background(), GHCR)
+	require.NoError(t, err)
+	assert.Equal(t, 2, found.calls)
+}
+
+func TestManagerExpiry(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	provider := &fakeProvider{name: "p", cred: &Credential{Token: "t", ExpiresAt: now.Add(time.Minute)}}
+	m := NewManager(provider)
+	m.now = func() time.Time { return now }
+
+	cred, err := m.Get(context.Background(), GHCR)
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(time.Minute), cred.ExpiresAt, "provider expiry before the TTL is kept")
+
+	now = now.Add(2 * time.Minute)
+	_, err = m.Get(context.Background(), GHCR)
+	require.NoError(t, err)
+	assert.Equal(t, 2, provider.calls, "expired credential is resolved again")
+}
+
+func TestManagerNoCredentials(t *testing.T) {
+	m := NewManager(&fakeProvider{name: "broken", err: errors.New("keychain locked")})
+	_, err := m.Get(context.Background(), GHCR)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrNoCredentials)
+	assert.Contains(t, err.Error(), "keychain locked")
+}
+
+func TestEnvProvider(t *testing.T) {
+	t.Setenv("R2R_TEST_TOKEN", "secret")
+	t.Setenv("R2R_TEST_USER", "octocat")
+	cred, err := EnvProvider{TokenEnv: "R2R_TEST_TOKEN", UsernameEnv: "R2R_TEST_USER"}.Resolve(context.Background(), GHCR)
+	require.NoError(t, err)
+	assert.Equal(t, &Credential{Username: "octocat", Token: "secret"}, cred)
+
+	_, err = EnvProvider{TokenEnv: "R2R_TEST_UNSET"}.Resolve(context.Background(), GHCR)
+	assert.ErrorIs(t, err, ErrNoCredentials)
+}
+
+func TestGitHubCLIProvider(t *testing.T) {
+	stubCommands(t, map[string]string{"gh": "gho_token\n"})
+	cred, err := GitHubCLIProvider{}.Resolve(context.Background(), GHCR)
+	require.NoError(t, err)
+	assert.Equal(t, "gho_token", cred.Token)
+
+	_, err = GitHubCLIProvider{}.Resolve(context.Background(), "registry.example.com")
+	assert.ErrorIs(t, err, ErrNoCredentials, "only GitHub hosts")
+
+	assert.Equal(t, "octocat", parseGitHubCLIUsername("github.com\n  ✓ Logged in to github.com account octocat (keyring)\n"))
+}
+
+func TestDockerConfigProvider(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.json")
+	inline := base64.StdEncoding.EncodeToString([]byte("octocat:ghp_inline"))
+
+	t.Run("inline auth", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(path, []byte(`{"auths":{"https://ghcr.io":{"auth":"`+inline+`"}}}`), 0600))
+		cred, err := DockerConfigProvider{Path: path}.Resolve(context.Background(), GHCR)
+		require.NoError(t, err)
+		assert.Equal(t, &Credential{Username: "octocat", Token: "ghp_inline"}, cred)
+	})
+
+	t.Run("credential helper", func(t *testing.T) {
+		stubCommands(t, map[string]string{"docker-credential-gh": `{"ServerURL":"ghcr.io","Username":"octocat","Secret":"ghp_helper"}`})
+		content := `{"auths":{"ghcr.io":{"auth":"` + inline + `"}},"credHelpers":{"ghcr.io":"gh"}}`
+		require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+		cred, err := DockerConfigProvider{Path: path}.Resolve(context.Background(), GHCR)
+		require.NoError(t, err)
+		assert.Equal(t, "ghp_helper", cred.Token, "helper takes precedence over inline auths")
+	})
+
+	t.Run("failing credential store", func(t *testing.T) {
+		stubCommands(t, nil)
+		require.NoError(t, os.WriteFile(path, []byte(`{"auths":{"ghcr.io":{"auth":"`+inline+`"}},"credsStore":"desktop"}`), 0600))
+		cred, err := DockerConfigProvider{Path: path}.Resolve(context.Background(), GHCR)
+		require.Error(t, err, "a failing store is reported")
+		assert.Nil(t, cred)
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		_, err := DockerConfigProvider{Path: filepath.Join(dir, "none.json")}.Resolve(context.Background(), GHCR)
+		assert.ErrorIs(t, err, ErrNoCredentials)
+	})
+}
+
+func TestValidateScopes(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Header.Get("Authorization") {
+		case "Bearer classic":
+			w.Header().Set("X-OAuth-Scopes", "repo, write:packages")
+		case "Bearer no-packages":
+			w.Header().Set("X-OAuth-Scopes", "repo")
+		case "Bearer fine-grained":
+		default:
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"login":"octocat"}`))
+	}))
+	defer server.Close()
+	original := githubAPI
+	githubAPI = server.URL
+	defer func() { githubAPI = original }()
+
+	check, err := ValidateScopes(context.Background(), &Credential{Token: "classic"}, ScopeReadPackages)
+	require.NoError(t, err)
+	assert.True(t, check.Known)
+	assert.Empty(t, check.Missing, "write:packages implies read:packages")
+
+	check, err = ValidateScopes(context.Background(), &Credential{Token: "no-packages"}, ScopeReadPackages)
+	require.NoError(t, err)
+	assert.Equal(t, []string{ScopeReadPackages}, check.Missing)
+
+	check, err = ValidateScopes(context.Background(), &Credential{Token: "fine-grained"}, ScopeReadPackages)
+	require.NoError(t, err)
+	assert.False(t, check.Known)
+
+	_, err = ValidateScopes(context.Background(), &Credential{Token: "revoked", Source: "gh"}, ScopeReadPackages)
+	assert.ErrorIs(t, err, ErrTokenRejected)
+}
@@ -0,0 +1,266 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// runCommand runs an external credential source and returns its stdout.
+// Tests replace it.
+var runCommand = func(ctx context.Context, stdin string, name string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, name, args...)
+	if stdin != "" {
+		cmd.Stdin = strings.NewReader(stdin)
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%s: %w: %s", name, err, msg)
+		}
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return out, nil
+}
+
+// notInstalled reports whether err means the command does not exist, which
+// is not a failure of the source but the absence of it
+func notInstalled(err error) bool {
+	return errors.Is(err, exec.ErrNotFound)
+}
+
+// EnvProvider reads a token, and optionally a username, from environment
+// variables
+type EnvProvider struct {
+	TokenEnv    string
+	UsernameEnv string
+}
+
+func (p EnvProvider) Name() string { return "env:" + p.TokenEnv }
+
+func (p EnvProvider) Resolve(ctx context.Context, host string) (*Credential, error) {
+	token := os.Getenv(p.TokenEnv)
+	if token == "" {
+		return nil, ErrNoCredentials
+	}
+	cred := &Credential{Token: token}
+	if p.UsernameEnv != "" {
+		cred.Username = os.Getenv(p.UsernameEnv)
+	}
+	return cred, nil
+}
+
+// GitHubCLIProvider uses the token of `gh auth login`. It only serves GitHub
+// hosts.
+type GitHubCLIProvider struct{}
+
+func (GitHubCLIProvider) Name() string { return "gh" }
+
+func (GitHubCLIProvider) Resolve(ctx context.Context, host string) (*Credential, error) {
+	if host != GHCR && host != "github.com" {
+		return nil, ErrNoCredentials
+	}
+
+	// Fails when gh is not installed or not logged in
+	out, err := runCommand(ctx, "", "gh", "auth", "token")
+	if err != nil {
+		return nil, ErrNoCredentials
+	}
+	token := strings.TrimSpace(string(out))
+	if token == "" {
+		return nil, ErrNoCredentials
+	}
+
+	cred := &Credential{Token: token}
+	// The username is optional, so a failure here is ignored
+	if status, err := runCommand(ctx, "", "gh", "auth", "status", "-h", "github.com"); err == nil {
+		cred.Username = parseGitHubCLIUsername(string(status))
+	}
+	return cred, nil
+}
+
+// parseGitHubCLIUsername extracts the account from `gh auth status` output
+// like "✓ Logged in to github.com account USERNAME (GH_TOKEN)"
+func parseGitHubCLIUsername(status string) string {
+	for _, line := range strings.Split(status, "\n") {
+		if !strings.Contains(line, "Logged in to github.com account") {
+			continue
+		}
+		parts := strings.Fields(line)
+		for i, part := range parts {
+			if part == "account" && i+1 < len(parts) {
+				return strings.TrimSpace(parts[i+1])
+			}
+		}
+	}
+	return ""
+}
+
+// DockerConfigProvider reads the credentials of `docker login` from the
+// Docker config file, asking its credential helpers when it has them
+type DockerConfigProvider struct {
+	// Path overrides the config file, by default $DOCKER_CONFIG/config.json
+	// or ~/.docker/config.json
+	Path string
+}
+
+func (DockerConfigProvider) Name() string { return "docker-config" }
+
+type dockerConfig struct {
+	Auths       map[string]dockerAuth `json:"auths"`
+	CredsStore  string                `json:"credsStore"`
+	CredHelpers map[string]string     `json:"credHelpers"`
+}
+
+type dockerAuth struct {
+	Auth          string `json:"auth"`
+	Username      string `json:"username"`
+	Password      string `json:"password"`
+	IdentityToken string `json:"identitytoken"`
+}
+
+func (p DockerConfigProvider) path() string {
+	if p.Path != "" {
+		return p.Path
+	}
+	if dir := os.Getenv("DOCKER_CONFIG"); dir != "" {
+		return filepath.Join(dir, "config.json")
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".docker", "config.json")
+}
+
+func (p DockerConfigProvider) Resolve(ctx context.Context, host string) (*Credential, error) {
+	path := p.path()
+	if path == "" {
+		return nil, ErrNoCredentials
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, ErrNoCredentials
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Docker config: %w", err)
+	}
+	var cfg dockerConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("invalid Docker config %s: %w", path, err)
+	}
+
+	// A helper for the host takes precedence over the store for all hosts,
+	// which takes precedence over inline auths, as in the Docker CLI
+	if helper := cfg.CredHelpers[host]; helper != "" {
+		return credentialFromHelper(ctx, helper, host)
+	}
+	if cfg.CredsStore != "" {
+		cred, err := credentialFromHelper(ctx, cfg.CredsStore, host)
+		if !errors.Is(err, ErrNoCredentials) {
+			return cred, err
+		}
+	}
+
+	for _, key := range []string{host, "https://" + host, "https://" + host + "/"} {
+		if entry, ok := cfg.Auths[key]; ok {
+			return credentialFromAuth(entry)
+		}
+	}
+	return nil, ErrNoCredentials
+}
+
+func credentialFromAuth(entry dockerAuth) (*Credential, error) {
+	if entry.Auth != "" {
+		decoded, err := base64.StdEncoding.DecodeString(entry.Auth)
+		if err != nil {
+			return nil, fmt.Errorf("invalid auth in Docker config: %w", err)
+		}
+		username, password, ok := strings.Cut(string(decoded), ":")
+		if !ok || password == "" {
+			return nil, ErrNoCredentials
+		}
+		return &Credential{Username: username, Token: password}, nil
+	}
+	if entry.Password != "" {
+		return &Credential{Username: entry.Username, Token: entry.Password}, nil
+	}
+	if entry.IdentityToken != "" {
+		return &Credential{Username: entry.Username, Token: entry.IdentityToken}, nil
+	}
+	return nil, ErrNoCredentials
+}
+
+// credentialFromHelper runs `docker-credential-<helper> get`, which reads the
+// host on stdin and prints {"Username": ..., "Secret": ...}
+func credentialFromHelper(ctx context.Context, helper, host string) (*Credential, error) {
+	out, err := runCommand(ctx, host, "docker-credential-"+helper, "get")
+	if err != nil {
+		// Helpers exit non-zero with "credentials not found in native keychain"
+		if notInstalled(err) || strings.Contains(err.Error(), "credentials not found") {
+			return nil, ErrNoCredentials
+		}
+		return nil, err
+	}
+	var result struct {
+		Username string `json:"Username"`
+		Secret   string `json:"Secret"`
+	}
+	if err := json.Unmarshal(out, &result); err != nil {
+		return nil, fmt.Errorf("invalid output of docker-credential-%s: %w", helper, err)
+	}
+	if result.Secret == "" {
+		return nil, ErrNoCredentials
+	}
+	// Identity tokens are returned with the username "<token>"
+	if result.Username == "<token>" {
+		result.Username = ""
+	}
+	return &Credential{Username: result.Username, Token: result.Secret}, nil
+}
+
+// KeychainService is the service name of r2r tokens in the OS keychain,
+// followed by ":" and the host
+const KeychainService = "r2r-cli"
+
+// KeychainProvider reads a token stored for r2r in the OS keychain: the
+// macOS keychain (security) or the Secret Service on Linux (secret-tool).
+// On Windows, credentials of the Credential Manager are read through the
+// wincred Docker credential helper instead.
+type KeychainProvider struct{}
+
+func (KeychainProvider) Name() string { return "keychain" }
+
+func (KeychainProvider) Resolve(ctx context.Context, host string) (*Credential, error) {
+	var (
+		out []byte
+		err error
+	)
+	switch runtime.GOOS {
+	case "darwin":
+		out, err = runCommand(ctx, "", "security", "find-generic-password", "-s", KeychainService+":"+host, "-w")
+	case "linux":
+		out, err = runCommand(ctx, "", "secret-tool", "lookup", "service", KeychainService, "host", host)
+	default:
+		return nil, ErrNoCredentials
+	}
+	if err != nil {
+		// Both tools exit non-zero when nothing is stored
+		return nil, ErrNoCredentials
+	}
+	token := strings.TrimSpace(string(out))
+	if token == "" {
+		return nil, ErrNoCredentials
+	}
+	return &Credential{Token: token}, nil
+}
@@ -0,0 +1,91 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ScopeReadPackages is the OAuth scope needed to pull from GHCR
+const ScopeReadPackages = "read:packages"
+
+// impliedScopes lists the scopes that include another one
+var impliedScopes = map[string][]string{
+	ScopeReadPackages: {"write:packages", "delete:packages"},
+}
+
+// ErrTokenRejected is returned by ValidateScopes when GitHub does not accept
+// the token
+var ErrTokenRejected = errors.New("GitHub rejected the token")
+
+// githubAPI is the GitHub API base URL; tests replace it
+var githubAPI = "https://api.github.com"
+
+// ScopeCheck is the result of ValidateScopes
+type ScopeCheck struct {
+	// Known is false when GitHub does not report the scopes of the token:
+	// fine-grained tokens and the GITHUB_TOKEN of Actions have permissions
+	// instead, which cannot be listed
+	Known   bool
+	Scopes  []string
+	Missing []string
+}
+
+// ValidateScopes asks GitHub for the OAuth scopes of the credential and
+// reports the required ones it lacks. It fails when GitHub rejects the token.
+func ValidateScopes(ctx context.Context, cred *Credential, required ...string) (*ScopeCheck, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, githubAPI+"/user", nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+cred.Token)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("checking token scopes: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		return nil, fmt.Errorf("%w from %s: it is invalid, expired or revoked", ErrTokenRejected, cred.Source)
+	}
+	header, reported := resp.Header["X-Oauth-Scopes"]
+	if !reported {
+		return &ScopeCheck{}, nil
+	}
+
+	check := &ScopeCheck{Known: true}
+	for _, scope := range strings.Split(strings.Join(header, ","), ",") {
+		if scope = strings.TrimSpace(scope); scope != "" {
+			check.Scopes = append(check.Scopes, scope)
+		}
+	}
+	for _, scope := range required {
+		if !hasScope(check.Scopes, scope) {
+			check.Missing = append(check.Missing, scope)
+		}
+	}
+	return check, nil
+}
+
+func hasScope(scopes []string, scope string) bool {
+	for _, s := range scopes {
+		if s == scope {
+			return true
+		}
+		for _, implying := range impliedScopes[scope] {
+			if s == implying {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,242 @@
+// Package bench runs the Go benchmark suite of the repository and compares
+// results against a saved baseline, so performance regressions fail CI.
+package bench
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultThreshold is the slowdown in percent above which a benchmark counts
+// as a regression
+const DefaultThreshold = 20.0
+
+// Result is the summary of one benchmark. With several runs (-count) the
+// values are medians.
+type Result struct {
+	Package     string  `json:"package"`
+	Name        string  `json:"name"`
+	Runs        int     `json:"runs"`
+	NsPerOp     float64 `json:"ns_per_op"`
+	BytesPerOp  int64   `json:"bytes_per_op"`
+	AllocsPerOp int64   `json:"allocs_per_op"`
+}
+
+// Key identifies a benchmark across reports
+func (r Result) Key() string {
+	return r.Package + "." + r.Name
+}
+
+// Report is a saved benchmark run
+type Report struct {
+	CreatedAt time.Time `json:"created_at"`
+	GoVersion string    `json:"go_version"`
+	GOOS      string    `json:"goos"`
+	GOARCH    string    `json:"goarch"`
+	Results   []Result  `json:"results"`
+}
+
+// NewReport returns a report of results for the current platform
+func NewReport(results []Result) *Report {
+	return &Report{
+		CreatedAt: time.Now().UTC(),
+		GoVersion: runtime.Version(),
+		GOOS:      runtime.GOOS,
+		GOARCH:    runtime.GOARCH,
+		Results:   results,
+	}
+}
+
+// Save writes the report as JSON, creating the directory if needed
+func (r *Report) Save(path string) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode benchmark report: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write benchmark report: %w", err)
+	}
+	return nil
+}
+
+// LoadReport reads a report written by Save
+func LoadReport(path string) (*Report, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read benchmark report: %w", err)
+	}
+	var report Report
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("invalid benchmark report %s: %w", path, err)
+	}
+	return &report, nil
+}
+
+// A result line of go test -bench -benchmem looks like
+// "BenchmarkLoad-8   1234   956789 ns/op   12345 B/op   123 allocs/op". Log
+// output of the benchmark can come between the name and the measurements,
+// so they are matched separately.
+var (
+	benchProcs   = regexp.MustCompile(`-\d+$`) // the GOMAXPROCS suffix of a name
+	benchMetrics = regexp.MustCompile(`(?:^|\s)(\d+)\s+([\d.]+) ns/op(?:\s+[\d.]+ MB/s)?(?:\s+(\d+) B/op)?(?:\s+(\d+) allocs/op)?`)
+)
+
+// ParseOutput reads go test -bench output and returns one Result per run, in
+// order. The package of a result is taken from the preceding "pkg:" line.
+func ParseOutput(r io.Reader) ([]Result, error) {
+	var results []Result
+	pkg, name := "", ""
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if after, ok := strings.CutPrefix(line, "pkg: "); ok {
+			pkg = strings.TrimSpace(after)
+			continue
+		}
+		if strings.HasPrefix(line, "Benchmark") {
+			field, rest, _ := strings.Cut(line, "\t")
+			name = benchProcs.ReplaceAllString(strings.TrimSpace(field), "")
+			line = rest
+		}
+		if name == "" {
+			continue
+		}
+		m := benchMetrics.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		nsPerOp, err := strconv.ParseFloat(m[2], 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid benchmark result %q: %w", line, err)
+		}
+		result := Result{Package: pkg, Name: name, Runs: 1, NsPerOp: nsPerOp}
+		result.BytesPerOp, _ = strconv.ParseInt(m[3], 10, 64)
+		result.AllocsPerOp, _ = strconv.ParseInt(m[4], 10, 64)
+		results = append(results, result)
+		name = ""
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read benchmark output: %w", err)
+	}
+	return results, nil
+}
+
+// Summarize merges the runs of each benchmark into their medians, sorted by
+// key
+func Summarize(runs []Result) []Result {
+	byKey := make(map[string][]Result)
+	for _, run := range runs {
+		byKey[run.Key()] = append(byKey[run.Key()], run)
+	}
+
+	summary := make([]Result, 0, len(byKey))
+	for _, group := range byKey {
+		ns := make([]float64, len(group))
+		bytes := make([]float64, len(group))
+		allocs := make([]float64, len(group))
+		for i, run := range group {
+			ns[i] = run.NsPerOp
+			bytes[i] = float64(run.BytesPerOp)
+			allocs[i] = float64(run.AllocsPerOp)
+		}
+		summary = append(summary, Result{
+			Package:     group[0].Package,
+			Name:        group[0].Name,
+			Runs:        len(group),
+			NsPerOp:     median(ns),
+			BytesPerOp:  int64(median(bytes)),
+			AllocsPerOp: int64(median(allocs)),
+		})
+	}
+	sort.Slice(summary, func(i, j int) bool { return summary[i].Key() < summary[j].Key() })
+	return summary
+}
+
+func median(values []float64) float64 {
+	sort.Float64s(values)
+	n := len(values)
+	if n%2 == 1 {
+		return values[n/2]
+	}
+	return (values[n/2-1] + values[n/2]) / 2
+}
+
+// Delta compares one benchmark with its baseline
+type Delta struct {
+	Key       string  `json:"key"`
+	Baseline  float64 `json:"baseline_ns_per_op"`
+	Current   float64 `json:"current_ns_per_op"`
+	Change    float64 `json:"change_percent"` // positive is slower
+	Regressed bool    `json:"regressed"`
+}
+
+// Comparison is the result of comparing a run with a baseline
+type Comparison struct {
+	Threshold float64  `json:"threshold_percent"`
+	Deltas    []Delta  `json:"deltas"`
+	Added     []string `json:"added,omitempty"`   // not in the baseline
+	Removed   []string `json:"removed,omitempty"` // only in the baseline
+}
+
+// Regressions returns the deltas slower than the threshold
+func (c *Comparison) Regressions() []Delta {
+	var regressions []Delta
+	for _, delta := range c.Deltas {
+		if delta.Regressed {
+			regressions = append(regressions, delta)
+		}
+	}
+	return regressions
+}
+
+// Compare compares the time per operation of current with baseline. A
+// benchmark regressed when it is more than threshold percent slower.
+func Compare(baseline, current []Result, threshold float64) *Comparison {
+	base := make(map[string]Result, len(baseline))
+	for _, result := range baseline {
+		base[result.Key()] = result
+	}
+
+	comparison := &Comparison{Threshold: threshold}
+	seen := make(map[string]bool, len(current))
+	for _, result := range current {
+		key := result.Key()
+		seen[key] = true
+		old, ok := base[key]
+		if !ok || old.NsPerOp <= 0 {
+			comparison.Added = append(comparison.Added, key)
+			continue
+		}
+		change := (result.NsPerOp - old.NsPerOp) / old.NsPerOp * 100
+		comparison.Deltas = append(comparison.Deltas, Delta{
+			Key:       key,
+			Baseline:  old.NsPerOp,
+			Current:   result.NsPerOp,
+			Change:    change,
+			Regressed: change > threshold,
+		})
+	}
+	for _, result := range baseline {
+		if !seen[result.Key()] {
+			comparison.Removed = append(comparison.Removed, result.Key())
+		}
+	}
+	sort.Slice(comparison.Deltas, func(i, j int) bool { return comparison.Deltas[i].Key < comparison.Deltas[j].Key })
+	sort.Strings(comparison.Added)
+	sort.Strings(comparison.Removed)
+	return comparison
+}
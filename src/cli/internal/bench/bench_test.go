@@ -0,0 +1,95 @@
+//go:build L0
+// +build L0
+
+package bench
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const sampleOutput = `goos: linux
+goarch: amd64
+pkg: example.com/conf
+cpu: Intel(R) Xeon(R) Processor
+BenchmarkLoad-8         	      20	  1000 ns/op	 300 B/op	   5 allocs/op
+BenchmarkMerge-8        	{"level":"debug","message":"Merging override configuration"}
+      20	   2000 ns/op	 100 B/op	   2 allocs/op
+BenchmarkLoad-8         	      20	  3000 ns/op	 500 B/op	   7 allocs/op
+BenchmarkLoad-8         	      20	  2000 ns/op	  22.98 MB/s	 400 B/op	   6 allocs/op
+PASS
+ok  	example.com/conf	0.472s
+pkg: example.com/mcp
+BenchmarkRoundTrip/tools-list-16	  100	  50.5 ns/op
+PASS
+`
+
+func TestParseOutput(t *testing.T) {
+	runs, err := ParseOutput(strings.NewReader(sampleOutput))
+	require.NoError(t, err)
+	require.Len(t, runs, 5)
+
+	assert.Equal(t, Result{Package: "example.com/conf", Name: "BenchmarkLoad", Runs: 1, NsPerOp: 1000, BytesPerOp: 300, AllocsPerOp: 5}, runs[0])
+	assert.Equal(t, "BenchmarkMerge", runs[1].Name, "log output between name and result")
+	assert.Equal(t, 2000.0, runs[1].NsPerOp)
+	assert.Equal(t, Result{Package: "example.com/mcp", Name: "BenchmarkRoundTrip/tools-list", Runs: 1, NsPerOp: 50.5}, runs[4])
+}
+
+func TestSummarize(t *testing.T) {
+	runs, err := ParseOutput(strings.NewReader(sampleOutput))
+	require.NoError(t, err)
+
+	summary := Summarize(runs)
+	require.Len(t, summary, 3)
+	assert.Equal(t, "example.com/conf.BenchmarkLoad", summary[0].Key())
+	assert.Equal(t, 3, summary[0].Runs)
+	assert.Equal(t, 2000.0, summary[0].NsPerOp, "median of three runs")
+	assert.Equal(t, int64(400), summary[0].BytesPerOp)
+	assert.Equal(t, "example.com/conf.BenchmarkMerge", summary[1].Key())
+	assert.Equal(t, "example.com/mcp.BenchmarkRoundTrip/tools-list", summary[2].Key())
+}
+
+func TestCompare(t *testing.T) {
+	baseline := []Result{
+		{Package: "p", Name: "BenchmarkSame", NsPerOp: 1000},
+		{Package: "p", Name: "BenchmarkSlower", NsPerOp: 1000},
+		{Package: "p", Name: "BenchmarkFaster", NsPerOp: 1000},
+		{Package: "p", Name: "BenchmarkGone", NsPerOp: 1000},
+	}
+	current := []Result{
+		{Package: "p", Name: "BenchmarkSame", NsPerOp: 1150},
+		{Package: "p", Name: "BenchmarkSlower", NsPerOp: 1250},
+		{Package: "p", Name: "BenchmarkFaster", NsPerOp: 500},
+		{Package: "p", Name: "BenchmarkNew", NsPerOp: 10},
+	}
+
+	comparison := Compare(baseline, current, DefaultThreshold)
+
+	require.Len(t, comparison.Deltas, 3)
+	regressions := comparison.Regressions()
+	require.Len(t, regressions, 1)
+	assert.Equal(t, "p.BenchmarkSlower", regressions[0].Key)
+	assert.InDelta(t, 25.0, regressions[0].Change, 0.001)
+	assert.Equal(t, []string{"p.BenchmarkNew"}, comparison.Added)
+	assert.Equal(t, []string{"p.BenchmarkGone"}, comparison.Removed)
+}
+
+func TestReportSaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bench", "baseline.json")
+	report := NewReport([]Result{{Package: "p", Name: "BenchmarkLoad", Runs: 5, NsPerOp: 1000}})
+
+	require.NoError(t, report.Save(path))
+	loaded, err := LoadReport(path)
+	require.NoError(t, err)
+	assert.Equal(t, report.Results, loaded.Results)
+	assert.Equal(t, report.GoVersion, loaded.GoVersion)
+}
+
+func TestGoTestArgs(t *testing.T) {
+	args := goTestArgs(Target{Packages: []string{"./internal/conf"}, Tags: []string{"L1"}}, Options{Count: 3, BenchTime: "10x"})
+	assert.Equal(t, []string{"test", "-run", "^$", "-bench", ".", "-benchmem", "-count", "3", "-benchtime", "10x", "-tags", "L1", "./internal/conf"}, args)
+}
@@ -0,0 +1,95 @@
+package bench
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Target is a set of benchmark packages in one Go module of the repository
+type Target struct {
+	Module   string   // directory of the go.mod, relative to the repository root
+	Packages []string // package patterns within the module
+	Tags     []string // build tags the benchmark files need
+}
+
+// Suite is the benchmark suite run by r2r bench: configuration loading,
+// commit message validation, module detection and MCP message handling
+var Suite = []Target{
+	{Module: "src/cli", Packages: []string{"./internal/conf"}, Tags: []string{"L1"}},
+	{Module: "src/commands", Packages: []string{"./impl/commit/internal"}},
+	{Module: "src/core", Packages: []string{"./repository"}},
+	{Module: "src/mcp/commands", Packages: []string{"."}},
+}
+
+// Options configures a benchmark run
+type Options struct {
+	Bench     string    // -bench pattern; all benchmarks when empty
+	Count     int       // runs per benchmark; 1 when zero
+	BenchTime string    // -benchtime, e.g. "2s" or "100x"; go test's default when empty
+	Output    io.Writer // receives the go test output; discarded when nil
+}
+
+// Run runs the benchmarks of targets from repoRoot and returns the results
+// summarized per benchmark
+func Run(ctx context.Context, repoRoot string, targets []Target, opts Options) ([]Result, error) {
+	output := opts.Output
+	if output == nil {
+		output = io.Discard
+	}
+
+	var runs []Result
+	for _, target := range targets {
+		args := goTestArgs(target, opts)
+		cmd := exec.CommandContext(ctx, "go", args...)
+		cmd.Dir = filepath.Join(repoRoot, filepath.FromSlash(target.Module))
+		cmd.Env = os.Environ()
+
+		var stdout bytes.Buffer
+		cmd.Stdout = io.MultiWriter(&stdout, output)
+		cmd.Stderr = output
+
+		fmt.Fprintf(output, "▶ %s: go %s\n", target.Module, strings.Join(args, " "))
+		if err := cmd.Run(); err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			return nil, fmt.Errorf("benchmarks in %s failed: %w", target.Module, err)
+		}
+
+		results, err := ParseOutput(&stdout)
+		if err != nil {
+			return nil, err
+		}
+		runs = append(runs, results...)
+	}
+	return Summarize(runs), nil
+}
+
+// goTestArgs returns the go test arguments that run only the benchmarks of a
+// target
+func goTestArgs(target Target, opts Options) []string {
+	pattern := opts.Bench
+	if pattern == "" {
+		pattern = "."
+	}
+	count := opts.Count
+	if count < 1 {
+		count = 1
+	}
+
+	args := []string{"test", "-run", "^$", "-bench", pattern, "-benchmem", "-count", strconv.Itoa(count)}
+	if opts.BenchTime != "" {
+		args = append(args, "-benchtime", opts.BenchTime)
+	}
+	if len(target.Tags) > 0 {
+		args = append(args, "-tags", strings.Join(target.Tags, ","))
+	}
+	return append(args, target.Packages...)
+}
@@ -0,0 +1,65 @@
+package cache
+
+import "time"
+
+// Failed registry lookups are cached too, so an unreachable GHCR does not
+// slow down every command with the same failing requests
+const (
+	// FailureTTL is how long a failed lookup of an extension is not retried
+	FailureTTL = time.Minute
+	// breakerThreshold is the number of consecutive failed lookups, of any
+	// extension, after which all lookups are suspended
+	breakerThreshold = 3
+	// breakerCooldown is how long lookups stay suspended. The first lookup
+	// after it probes the registry; another failure suspends them again.
+	breakerCooldown = 5 * time.Minute
+)
+
+// LookupFailure records the last failed registry lookup of an extension
+type LookupFailure struct {
+	Error    string    `json:"error"`
+	FailedAt time.Time `json:"failed_at"`
+}
+
+// CircuitBreaker suspends registry lookups after repeated failures
+type CircuitBreaker struct {
+	ConsecutiveFailures int       `json:"consecutive_failures"`
+	OpenUntil           time.Time `json:"open_until,omitempty"`
+}
+
+// RecordFailure caches a failed lookup of an extension and trips the circuit
+// breaker after breakerThreshold consecutive failures
+func (c *RegistryCache) RecordFailure(name string, err error) {
+	now := time.Now()
+	if c.Failures == nil {
+		c.Failures = make(map[string]*LookupFailure)
+	}
+	c.Failures[name] = &LookupFailure{Error: err.Error(), FailedAt: now}
+
+	c.Breaker.ConsecutiveFailures++
+	if c.Breaker.ConsecutiveFailures >= breakerThreshold {
+		c.Breaker.OpenUntil = now.Add(breakerCooldown)
+	}
+}
+
+// RecordSuccess clears the failure of an extension and resets the circuit
+// breaker
+func (c *RegistryCache) RecordSuccess(name string) {
+	delete(c.Failures, name)
+	c.Breaker = CircuitBreaker{}
+}
+
+// RecentFailure returns the failed lookup of an extension if it is younger
+// than FailureTTL
+func (c *RegistryCache) RecentFailure(name string) (*LookupFailure, bool) {
+	failure, ok := c.Failures[name]
+	if !ok || time.Since(failure.FailedAt) > FailureTTL {
+		return nil, false
+	}
+	return failure, true
+}
+
+// CircuitOpen reports whether registry lookups are suspended
+func (c *RegistryCache) CircuitOpen() bool {
+	return time.Now().Before(c.Breaker.OpenUntil)
+}
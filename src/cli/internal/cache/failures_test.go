@@ -0,0 +1,68 @@
+//go:build L1
+// +build L1
+
+package cache
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecentFailure(t *testing.T) {
+	c := newRegistryCache()
+	c.RecordFailure("go", errors.New("connection refused"))
+
+	failure, ok := c.RecentFailure("go")
+	require.True(t, ok)
+	assert.Equal(t, "connection refused", failure.Error)
+	_, ok = c.RecentFailure("python")
+	assert.False(t, ok)
+
+	failure.FailedAt = time.Now().Add(-FailureTTL - time.Second)
+	_, ok = c.RecentFailure("go")
+	assert.False(t, ok, "failures expire after FailureTTL")
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	c := newRegistryCache()
+	for i := 0; i < breakerThreshold-1; i++ {
+		c.RecordFailure("go", errors.New("timeout"))
+	}
+	assert.False(t, c.CircuitOpen())
+
+	c.RecordFailure("python", errors.New("timeout"))
+	assert.True(t, c.CircuitOpen())
+
+	// The probe after the cooldown reopens the breaker on failure...
+	c.Breaker.OpenUntil = time.Now().Add(-time.Second)
+	assert.False(t, c.CircuitOpen())
+	c.RecordFailure("go", errors.New("timeout"))
+	assert.True(t, c.CircuitOpen())
+
+	// ...and closes it on success
+	c.RecordSuccess("go")
+	assert.False(t, c.CircuitOpen())
+	assert.Zero(t, c.Breaker.ConsecutiveFailures)
+	_, ok := c.RecentFailure("go")
+	assert.False(t, ok)
+}
+
+func TestFailuresArePersisted(t *testing.T) {
+	withCacheDir(t)
+
+	c, _ := Load()
+	for i := 0; i < breakerThreshold; i++ {
+		c.RecordFailure("go", errors.New("timeout"))
+	}
+	require.NoError(t, c.Save())
+
+	loaded, err := Load()
+	require.NoError(t, err)
+	assert.True(t, loaded.CircuitOpen())
+	_, ok := loaded.RecentFailure("go")
+	assert.True(t, ok)
+}
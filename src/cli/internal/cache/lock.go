@@ -0,0 +1,47 @@
+package cache
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+// lockTimeout bounds how long Load and Save wait for another r2r process
+// holding the cache lock
+const lockTimeout = 2 * time.Second
+
+// lockPath returns the advisory lock file guarding the cache file at path.
+// The cache file itself is replaced on every save, so it cannot carry the
+// lock.
+func lockPath(path string) string {
+	return path + ".lock"
+}
+
+// acquireLock takes an advisory lock on the cache file at path, shared for
+// readers and exclusive for writers. The returned function releases it.
+func acquireLock(path string, exclusive bool) (func(), error) {
+	f, err := os.OpenFile(lockPath(path), os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open cache lock: %w", err)
+	}
+
+	deadline := time.Now().Add(lockTimeout)
+	for {
+		locked, err := tryLock(f, exclusive)
+		if err != nil {
+			f.Close()
+			return nil, fmt.Errorf("failed to lock cache: %w", err)
+		}
+		if locked {
+			return func() {
+				unlock(f)
+				f.Close()
+			}, nil
+		}
+		if time.Now().After(deadline) {
+			f.Close()
+			return nil, fmt.Errorf("timed out after %s waiting for the cache lock %s", lockTimeout, lockPath(path))
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
@@ -2,9 +2,11 @@ package cache
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/ready-to-release/eac/src/cli/internal/session"
@@ -13,116 +15,284 @@ import (
 
 // RegistryCache manages cached GitHub Container Registry data
 type RegistryCache struct {
-	Version    string                       `json:"version"`
-	Extensions map[string]*ExtensionCache   `json:"extensions"`
-	UpdatedAt  time.Time                    `json:"updated_at"`
+	Version    string                     `json:"version"`
+	Extensions map[string]*ExtensionCache `json:"extensions"`
+	Failures   map[string]*LookupFailure  `json:"failures,omitempty"` // failed lookups by extension
+	Breaker    CircuitBreaker             `json:"breaker"`
+	UpdatedAt  time.Time                  `json:"updated_at"`
 }
 
 // ExtensionCache holds cached data for a single extension
 type ExtensionCache struct {
 	Name      string    `json:"name"`
-	LatestSHA string    `json:"latest_sha"`  // e.g., "sha-84f1a65"
-	Tags      []string  `json:"tags"`        // All available tags
+	LatestSHA string    `json:"latest_sha"` // e.g., "sha-84f1a65"
+	Tags      []string  `json:"tags"`       // All available tags
 	UpdatedAt time.Time `json:"updated_at"`
 }
 
+// cacheVersion is the schema version of the cache file. A file of another
+// version, or one that cannot be parsed, is discarded on load.
 const (
-	cacheVersion  = "1.0"
+	cacheVersion = "1.1"
 )
 
+// cacheFilePrefix names the session cache files in the cache directory
+const cacheFilePrefix = "r2r-cli-cache-"
+
+// GetCacheDir returns the directory holding the cache files of all sessions
+func GetCacheDir() string {
+	return filepath.Join(os.TempDir(), "r2r-cli-cache")
+}
+
 // GetCachePath returns the path to the session-specific cache file
 func GetCachePath() string {
 	// Get session identifier for session-specific cache
 	sessionID := session.GetIdentifier()
-	
-	// Use temp directory for cache file
-	cacheDir := filepath.Join(os.TempDir(), "r2r-cli-cache")
+
+	cacheDir := GetCacheDir()
 	// Create directory if it doesn't exist
 	os.MkdirAll(cacheDir, 0755)
-	
+
 	// Session-specific cache file name
-	cacheFileName := fmt.Sprintf("r2r-cli-cache-%s.json", sessionID)
+	cacheFileName := fmt.Sprintf("%s%s.json", cacheFilePrefix, sessionID)
 	return filepath.Join(cacheDir, cacheFileName)
 }
 
-// Load reads the cache from disk
+func newRegistryCache() *RegistryCache {
+	return &RegistryCache{
+		Version:    cacheVersion,
+		Extensions: make(map[string]*ExtensionCache),
+		UpdatedAt:  time.Time{},
+	}
+}
+
+// Load reads the cache from disk. It always returns a usable cache: when the
+// file is missing, unreadable, corrupted or of another version, the cache is
+// empty, so a broken cache never blocks pin checks. A corrupted or outdated
+// file is removed.
 func Load() (*RegistryCache, error) {
 	cachePath := GetCachePath()
 	log.Debug().Str("path", cachePath).Msg("Loading registry cache from disk")
-	
-	data, err := os.ReadFile(cachePath)
+
+	// Saves replace the file atomically, so reading without the lock still
+	// sees a complete file; the lock only orders this read after a save in
+	// progress
+	if release, err := acquireLock(cachePath, false); err != nil {
+		log.Debug().Err(err).Msg("Reading registry cache without lock")
+	} else {
+		defer release()
+	}
+
+	cache, err := readCache(cachePath)
+	switch {
+	case err == nil:
+		return cache, nil
+	case errors.Is(err, os.ErrNotExist):
+		return newRegistryCache(), nil
+	case errors.Is(err, errInvalidCache):
+		log.Warn().Err(err).Str("path", cachePath).Msg("Discarding invalid registry cache")
+		if err := os.Remove(cachePath); err != nil && !os.IsNotExist(err) {
+			log.Debug().Err(err).Msg("Failed to remove invalid registry cache")
+		}
+		return newRegistryCache(), nil
+	default:
+		return newRegistryCache(), err
+	}
+}
+
+// errInvalidCache marks a cache file that is corrupted or of another version
+var errInvalidCache = errors.New("invalid registry cache")
+
+func readCache(path string) (*RegistryCache, error) {
+	data, err := os.ReadFile(path)
 	if err != nil {
 		if os.IsNotExist(err) {
-			// Return empty cache if file doesn't exist
-			return &RegistryCache{
-				Version:    cacheVersion,
-				Extensions: make(map[string]*ExtensionCache),
-				UpdatedAt:  time.Time{},
-			}, nil
+			return nil, err
 		}
 		return nil, fmt.Errorf("failed to read cache: %w", err)
 	}
-	
+
 	var cache RegistryCache
 	if err := json.Unmarshal(data, &cache); err != nil {
-		log.Warn().Err(err).Msg("Failed to parse cache file, creating new cache")
-		// Return empty cache if parsing fails
-		return &RegistryCache{
-			Version:    cacheVersion,
-			Extensions: make(map[string]*ExtensionCache),
-			UpdatedAt:  time.Time{},
-		}, nil
-	}
-	
+		return nil, fmt.Errorf("%w: %v", errInvalidCache, err)
+	}
+
 	// Check version compatibility
 	if cache.Version != cacheVersion {
-		log.Debug().
-			Str("cache_version", cache.Version).
-			Str("expected_version", cacheVersion).
-			Msg("Cache version mismatch, creating new cache")
-		return &RegistryCache{
-			Version:    cacheVersion,
-			Extensions: make(map[string]*ExtensionCache),
-			UpdatedAt:  time.Time{},
-		}, nil
-	}
-	
+		return nil, fmt.Errorf("%w: version %q, expected %q", errInvalidCache, cache.Version, cacheVersion)
+	}
+
 	// Initialize map if nil
 	if cache.Extensions == nil {
 		cache.Extensions = make(map[string]*ExtensionCache)
 	}
-	
+
 	return &cache, nil
 }
 
-// Save writes the cache to disk
+// Save writes the cache to disk. The file is replaced atomically under an
+// exclusive lock, so concurrent r2r processes never see a partial file.
 func (c *RegistryCache) Save() error {
 	cachePath := GetCachePath()
 	log.Debug().Str("path", cachePath).Msg("Saving registry cache")
-	
+
+	c.Version = cacheVersion
 	data, err := json.MarshalIndent(c, "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to marshal cache: %w", err)
 	}
-	
-	if err := os.WriteFile(cachePath, data, 0644); err != nil {
+
+	release, err := acquireLock(cachePath, true)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	if err := writeFileAtomic(cachePath, data); err != nil {
 		return fmt.Errorf("failed to write cache: %w", err)
 	}
-	
+
 	log.Debug().
 		Str("path", cachePath).
 		Int("extensions", len(c.Extensions)).
 		Msg("Saved registry cache")
-	
+
+	return nil
+}
+
+// writeFileAtomic writes data to a temporary file next to path and renames
+// it over path
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Info describes a cache file for `r2r cache info`
+type Info struct {
+	Path       string    `json:"path"`
+	Exists     bool      `json:"exists"`
+	Size       int64     `json:"size"`
+	Version    string    `json:"version,omitempty"`
+	UpdatedAt  time.Time `json:"updated_at,omitempty"`
+	Extensions int       `json:"extensions"`
+	Invalid    string    `json:"invalid,omitempty"` // why the file would be discarded on load
+	Sessions   int       `json:"sessions"`          // cache files of all sessions
+}
+
+// Stat describes the cache file of the current session without modifying it
+func Stat() (*Info, error) {
+	cachePath := GetCachePath()
+	info := &Info{Path: cachePath}
+
+	sessions, err := sessionFiles()
+	if err != nil {
+		return nil, err
+	}
+	info.Sessions = len(sessions)
+
+	stat, err := os.Stat(cachePath)
+	if os.IsNotExist(err) {
+		return info, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat cache: %w", err)
+	}
+	info.Exists = true
+	info.Size = stat.Size()
+
+	cache, err := readCache(cachePath)
+	switch {
+	case err == nil:
+		info.Version = cache.Version
+		info.UpdatedAt = cache.UpdatedAt
+		info.Extensions = len(cache.Extensions)
+	case errors.Is(err, errInvalidCache):
+		info.Invalid = err.Error()
+	default:
+		return nil, err
+	}
+	return info, nil
+}
+
+// Remove deletes the cache file of the current session
+func Remove() error {
+	cachePath := GetCachePath()
+	release, err := acquireLock(cachePath, true)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	if err := os.Remove(cachePath); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove cache: %w", err)
+	}
 	return nil
 }
 
+// RemoveAll deletes the cache files of all sessions and returns how many
+// were removed
+func RemoveAll() (int, error) {
+	files, err := sessionFiles()
+	if err != nil {
+		return 0, err
+	}
+	removed := 0
+	for _, file := range files {
+		release, err := acquireLock(file, true)
+		if err != nil {
+			return removed, err
+		}
+		err = os.Remove(file)
+		release()
+		if err != nil && !os.IsNotExist(err) {
+			return removed, fmt.Errorf("failed to remove cache: %w", err)
+		}
+		removed++
+	}
+	return removed, nil
+}
+
+// sessionFiles returns the cache files of all sessions
+func sessionFiles() ([]string, error) {
+	entries, err := os.ReadDir(GetCacheDir())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cache directory: %w", err)
+	}
+	var files []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if !entry.IsDir() && strings.HasPrefix(name, cacheFilePrefix) && strings.HasSuffix(name, ".json") {
+			files = append(files, filepath.Join(GetCacheDir(), name))
+		}
+	}
+	return files, nil
+}
+
 // IsExpired checks if the cache needs refresh based on the configured TTL
 func (c *RegistryCache) IsExpired(ttlSeconds int) bool {
 	if c.UpdatedAt.IsZero() {
 		return true // Never updated
 	}
-	
+
 	ttl := time.Duration(ttlSeconds) * time.Second
 	return time.Since(c.UpdatedAt) > ttl
 }
@@ -138,7 +308,7 @@ func (c *RegistryCache) SetExtension(name string, latestSHA string, tags []strin
 	if c.Extensions == nil {
 		c.Extensions = make(map[string]*ExtensionCache)
 	}
-	
+
 	c.Extensions[name] = &ExtensionCache{
 		Name:      name,
 		LatestSHA: latestSHA,
@@ -159,5 +329,7 @@ func (c *RegistryCache) GetLatestSHA(extensionName string) (string, bool) {
 // Clear removes all cached data
 func (c *RegistryCache) Clear() {
 	c.Extensions = make(map[string]*ExtensionCache)
+	c.Failures = nil
+	c.Breaker = CircuitBreaker{}
 	c.UpdatedAt = time.Time{}
-}
\ No newline at end of file
+}
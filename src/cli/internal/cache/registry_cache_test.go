@@ -0,0 +1,97 @@
+//go:build L1
+// +build L1
+
+package cache
+
+import (
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// withCacheDir points the cache at a temporary directory
+func withCacheDir(t *testing.T) {
+	t.Helper()
+	t.Setenv("TMPDIR", t.TempDir())
+}
+
+func TestSaveLoad(t *testing.T) {
+	withCacheDir(t)
+
+	c, err := Load()
+	require.NoError(t, err)
+	c.SetExtension("go", "sha-84f1a65", []string{"sha-84f1a65", "latest"})
+	require.NoError(t, c.Save())
+
+	loaded, err := Load()
+	require.NoError(t, err)
+	sha, ok := loaded.GetLatestSHA("go")
+	assert.True(t, ok)
+	assert.Equal(t, "sha-84f1a65", sha)
+}
+
+func TestLoadDiscardsInvalidCache(t *testing.T) {
+	tests := map[string]string{
+		"corrupted":     `{"version": "1.1", "extensions": {`,
+		"other version": `{"version": "1.0", "extensions": {}}`,
+	}
+	for name, content := range tests {
+		t.Run(name, func(t *testing.T) {
+			withCacheDir(t)
+			require.NoError(t, os.WriteFile(GetCachePath(), []byte(content), 0644))
+
+			info, err := Stat()
+			require.NoError(t, err)
+			assert.NotEmpty(t, info.Invalid)
+
+			c, err := Load()
+			require.NoError(t, err)
+			require.NotNil(t, c)
+			assert.Empty(t, c.Extensions)
+			assert.NoFileExists(t, GetCachePath(), "invalid cache is reset")
+		})
+	}
+}
+
+func TestConcurrentSaves(t *testing.T) {
+	withCacheDir(t)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c, _ := Load()
+			c.SetExtension("go", "sha-84f1a65", []string{"latest"})
+			assert.NoError(t, c.Save())
+		}()
+	}
+	wg.Wait()
+
+	info, err := Stat()
+	require.NoError(t, err)
+	assert.Empty(t, info.Invalid)
+	assert.Equal(t, 1, info.Extensions)
+}
+
+func TestRemove(t *testing.T) {
+	withCacheDir(t)
+
+	c, _ := Load()
+	c.SetExtension("go", "sha-84f1a65", nil)
+	require.NoError(t, c.Save())
+
+	info, err := Stat()
+	require.NoError(t, err)
+	assert.True(t, info.Exists)
+	assert.Equal(t, 1, info.Sessions)
+
+	removed, err := RemoveAll()
+	require.NoError(t, err)
+	assert.Equal(t, 1, removed)
+	assert.NoFileExists(t, GetCachePath())
+	require.NoError(t, Remove(), "removing a missing cache is not an error")
+}
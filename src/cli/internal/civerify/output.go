@@ -0,0 +1,166 @@
+package civerify
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// Output formats
+const (
+	FormatText  = "text"
+	FormatJSON  = "json"
+	FormatSARIF = "sarif" // for github/codeql-action/upload-sarif
+)
+
+// Write renders the report in format
+func Write(w io.Writer, report *Report, format string, toolVersion string) error {
+	switch format {
+	case FormatText:
+		writeText(w, report)
+		return nil
+	case FormatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(report)
+	case FormatSARIF:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(toSARIF(report, toolVersion))
+	}
+	return fmt.Errorf("unknown format %q (use %s, %s or %s)", format, FormatText, FormatJSON, FormatSARIF)
+}
+
+func writeText(w io.Writer, report *Report) {
+	icons := map[string]string{StatusPassed: "✅", StatusFailed: "❌", StatusError: "❌", StatusSkipped: "⏭️ "}
+	for _, result := range report.Checks {
+		fmt.Fprintf(w, "%s %s: %s\n", icons[result.Status], result.Check, result.Summary)
+		for _, finding := range result.Findings {
+			icon := "❌"
+			if finding.Level == LevelWarning {
+				icon = "⚠️ "
+			}
+			location := ""
+			if finding.File != "" {
+				location = finding.File
+				if finding.Line > 0 {
+					location += fmt.Sprintf(":%d", finding.Line)
+				}
+				location += ": "
+			}
+			fmt.Fprintf(w, "  %s %s%s\n", icon, location, finding.Message)
+		}
+	}
+	if report.Passed {
+		fmt.Fprintln(w, "\n✅ All checks passed")
+	} else {
+		fmt.Fprintln(w, "\n❌ Verification failed")
+	}
+}
+
+// SARIF 2.1.0, reduced to the properties GitHub code scanning reads
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name    string      `json:"name"`
+	Version string      `json:"version,omitempty"`
+	Rules   []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+}
+
+// toSARIF turns each finding into a result with the rule "<check>/<rule>".
+// Checks that could not run become an error result of "<check>/error", so
+// the upload shows them too. Commit message findings and check errors have
+// no file; code scanning keeps them without displaying them, and the exit
+// status still fails the check.
+func toSARIF(report *Report, toolVersion string) sarifLog {
+	rules := make(map[string]string)
+	results := []sarifResult{}
+
+	for _, check := range report.Checks {
+		if check.Status == StatusError {
+			id := check.Check + "/error"
+			rules[id] = fmt.Sprintf("The %s check could not run", check.Check)
+			results = append(results, sarifResult{RuleID: id, Level: LevelError, Message: sarifMessage{Text: check.Summary}})
+			continue
+		}
+		for _, finding := range check.Findings {
+			id := check.Check + "/" + finding.Rule
+			if _, ok := rules[id]; !ok {
+				rules[id] = fmt.Sprintf("%s: %s", check.Check, finding.Rule)
+			}
+			result := sarifResult{RuleID: id, Level: finding.Level, Message: sarifMessage{Text: finding.Message}}
+			if finding.File != "" {
+				location := sarifLocation{PhysicalLocation: sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: finding.File}}}
+				if finding.Line > 0 {
+					location.PhysicalLocation.Region = &sarifRegion{StartLine: finding.Line, StartColumn: finding.Column}
+				}
+				result.Locations = []sarifLocation{location}
+			}
+			results = append(results, result)
+		}
+	}
+
+	ids := make([]string, 0, len(rules))
+	for id := range rules {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	driver := sarifDriver{Name: "r2r", Version: toolVersion, Rules: []sarifRule{}}
+	for _, id := range ids {
+		driver.Rules = append(driver.Rules, sarifRule{ID: id, ShortDescription: sarifMessage{Text: rules[id]}})
+	}
+
+	return sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{{Tool: sarifTool{Driver: driver}, Results: results}},
+	}
+}
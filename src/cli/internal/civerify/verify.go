@@ -0,0 +1,319 @@
+// Package civerify runs the repository checks of a pull request in one
+// process and collects them in one report: configuration schemas, pinned
+// extension images, the commit messages of the pull request, contract drift
+// and the modules the pull request affects. The report renders as text, JSON
+// or SARIF, so one required GitHub check covers all of them.
+package civerify
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ready-to-release/eac/contracts"
+	"github.com/ready-to-release/eac/src/cli/internal/contractdrift"
+	"github.com/ready-to-release/eac/src/cli/internal/validator"
+	"github.com/ready-to-release/eac/src/core/commitrules"
+	"github.com/ready-to-release/eac/src/core/contracts/modules"
+	"github.com/ready-to-release/eac/src/core/repository"
+)
+
+// Check names, in the order they run
+const (
+	CheckSchema          = "schema"
+	CheckPinnedImages    = "pinned-images"
+	CheckCommitMessages  = "commit-messages"
+	CheckContractsDrift  = "contracts-drift"
+	CheckAffectedModules = "affected-modules"
+)
+
+// Check statuses
+const (
+	StatusPassed  = "passed"
+	StatusFailed  = "failed"  // the check found errors
+	StatusError   = "error"   // the check could not run
+	StatusSkipped = "skipped" // nothing to check, e.g. no pull request range
+)
+
+// Finding levels, as in SARIF
+const (
+	LevelError   = "error"
+	LevelWarning = "warning"
+)
+
+// ConfigFile and CommitConfigFile are the configuration files validated
+// against their schemas, relative to the repository root
+const (
+	ConfigFile       = "r2r-cli.yml"
+	CommitConfigFile = ".r2r/commit.yml"
+)
+
+// Options select the repository and the pull request range
+type Options struct {
+	WorkspaceRoot string
+	Base          string // base of the pull request; without it the range checks are skipped
+	Head          string // default HEAD
+}
+
+// Finding is one problem reported by a check
+type Finding struct {
+	Rule    string `json:"rule"`
+	Level   string `json:"level"`
+	Message string `json:"message"`
+	File    string `json:"file,omitempty"` // relative to the repository root
+	Line    int    `json:"line,omitempty"`
+	Column  int    `json:"column,omitempty"`
+}
+
+// Result is the outcome of one check
+type Result struct {
+	Check    string    `json:"check"`
+	Status   string    `json:"status"`
+	Summary  string    `json:"summary"`
+	Findings []Finding `json:"findings"`
+}
+
+// Report is the outcome of all checks
+type Report struct {
+	Base            string                      `json:"base,omitempty"`
+	Head            string                      `json:"head"`
+	Passed          bool                        `json:"passed"`
+	Checks          []Result                    `json:"checks"`
+	AffectedModules *repository.AffectedModules `json:"affected_modules,omitempty"`
+}
+
+// Verify runs every check. A check that cannot run is reported with
+// StatusError and does not stop the others.
+func Verify(opts Options) *Report {
+	if opts.Head == "" {
+		opts.Head = "HEAD"
+	}
+	report := &Report{Base: opts.Base, Head: opts.Head}
+
+	schema, pinned := checkConfigFiles(opts.WorkspaceRoot)
+	report.Checks = append(report.Checks, schema, pinned)
+
+	registry, registryErr := modules.LoadFromWorkspaceLatest(opts.WorkspaceRoot)
+	if registryErr != nil {
+		for _, check := range []string{CheckCommitMessages, CheckContractsDrift, CheckAffectedModules} {
+			report.Checks = append(report.Checks, errorResult(check, fmt.Errorf("failed to load module contracts: %w", registryErr)))
+		}
+	} else {
+		affected, affectedResult := checkAffectedModules(opts, registry)
+		report.AffectedModules = affected
+		report.Checks = append(report.Checks,
+			checkCommitMessages(opts, registry),
+			checkContractsDrift(opts.WorkspaceRoot, registry),
+			affectedResult,
+		)
+	}
+
+	report.Passed = true
+	for _, result := range report.Checks {
+		if result.Status == StatusFailed || result.Status == StatusError {
+			report.Passed = false
+		}
+	}
+	return report
+}
+
+// newResult sets the status from the findings: failed with an error, passed otherwise
+func newResult(check string, findings []Finding, summary string) Result {
+	result := Result{Check: check, Status: StatusPassed, Summary: summary, Findings: findings}
+	if result.Findings == nil {
+		result.Findings = []Finding{}
+	}
+	for _, finding := range findings {
+		if finding.Level == LevelError {
+			result.Status = StatusFailed
+		}
+	}
+	return result
+}
+
+func errorResult(check string, err error) Result {
+	return Result{Check: check, Status: StatusError, Summary: err.Error(), Findings: []Finding{}}
+}
+
+func skippedResult(check string, summary string) Result {
+	return Result{Check: check, Status: StatusSkipped, Summary: summary, Findings: []Finding{}}
+}
+
+// checkConfigFiles validates r2r-cli.yml and .r2r/commit.yml against their
+// schemas, and reports the unpinned-tag findings of r2r-cli.yml as the
+// pinned-images check. Unpinned images fail the check whatever severity the
+// validation block gives them, unless the rule is off.
+func checkConfigFiles(workspaceRoot string) (Result, Result) {
+	var schemaFindings, pinnedFindings []Finding
+	validated := 0
+
+	for _, file := range []string{ConfigFile, CommitConfigFile} {
+		data, err := os.ReadFile(filepath.Join(workspaceRoot, filepath.FromSlash(file)))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			err = fmt.Errorf("failed to read %s: %w", file, err)
+			return errorResult(CheckSchema, err), errorResult(CheckPinnedImages, err)
+		}
+
+		v, err := validator.NewEmbeddedValidator()
+		if file == CommitConfigFile {
+			v, err = validator.NewSchemaValidator(contracts.CommitConfigSchema())
+		}
+		if err != nil {
+			return errorResult(CheckSchema, err), errorResult(CheckPinnedImages, err)
+		}
+		result, err := v.ValidateYAML(data, file)
+		if err != nil {
+			schemaFindings = append(schemaFindings, Finding{Rule: "parse", Level: LevelError, Message: err.Error(), File: file})
+			validated++
+			continue
+		}
+		validated++
+
+		for _, list := range []struct {
+			errors []validator.ValidationError
+			level  string
+		}{{result.Errors, LevelError}, {result.Warnings, LevelWarning}} {
+			for _, e := range list.errors {
+				finding := Finding{Rule: e.Rule, Level: list.level, Message: e.Error(), File: file, Line: e.Line, Column: e.Column}
+				if e.Rule == validator.WarnUnpinnedTag {
+					finding.Level = LevelError
+					pinnedFindings = append(pinnedFindings, finding)
+					continue
+				}
+				schemaFindings = append(schemaFindings, finding)
+			}
+		}
+	}
+
+	if validated == 0 {
+		return skippedResult(CheckSchema, fmt.Sprintf("no %s or %s", ConfigFile, CommitConfigFile)),
+			skippedResult(CheckPinnedImages, "no "+ConfigFile)
+	}
+	schema := newResult(CheckSchema, schemaFindings, fmt.Sprintf("%d configuration file(s), %d finding(s)", validated, len(schemaFindings)))
+	pinned := newResult(CheckPinnedImages, pinnedFindings, fmt.Sprintf("%d unpinned extension image(s)", len(pinnedFindings)))
+	return schema, pinned
+}
+
+// checkCommitMessages runs the commit message rules on each commit of the
+// range that is not a merge or a revert, with the modules of the files the
+// commit changed as its affected modules
+func checkCommitMessages(opts Options, registry *modules.Registry) Result {
+	if opts.Base == "" {
+		return skippedResult(CheckCommitMessages, "no pull request base")
+	}
+
+	ruleConfig, err := commitrules.LoadRuleConfig(opts.WorkspaceRoot)
+	if err != nil {
+		return errorResult(CheckCommitMessages, err)
+	}
+	output, err := repository.GitOutput(opts.WorkspaceRoot, "rev-list", "--no-merges", "--reverse", opts.Base+".."+opts.Head)
+	if err != nil {
+		return errorResult(CheckCommitMessages, fmt.Errorf("failed to list the commits of %s..%s: %w", opts.Base, opts.Head, err))
+	}
+	commits := lines(output)
+
+	var findings []Finding
+	for _, commit := range commits {
+		message, err := repository.GitOutput(opts.WorkspaceRoot, "log", "-1", "--format=%B", commit)
+		if err != nil {
+			return errorResult(CheckCommitMessages, err)
+		}
+		subject := strings.SplitN(message, "\n", 2)[0]
+		// Reverts keep git's message format
+		if strings.HasPrefix(subject, `Revert "`) && strings.Contains(message, "This reverts commit ") {
+			continue
+		}
+
+		files, err := repository.GitOutput(opts.WorkspaceRoot, "diff-tree", "--no-commit-id", "--name-only", "-r", commit)
+		if err != nil {
+			return errorResult(CheckCommitMessages, err)
+		}
+		for _, e := range commitrules.VerifyCommitMessage(message, owningModules(registry, lines(files)), ruleConfig) {
+			findings = append(findings, Finding{
+				Rule:    e.Rule,
+				Level:   e.Severity,
+				Message: fmt.Sprintf("%s %s: %s", shortCommit(commit), subject, e.Error()),
+			})
+		}
+	}
+	return newResult(CheckCommitMessages, findings, fmt.Sprintf("%d commit(s), %d finding(s)", len(commits), len(findings)))
+}
+
+// checkContractsDrift reports the contract drift findings as errors, located
+// at the contract, directory or definitions file concerned
+func checkContractsDrift(workspaceRoot string, registry *modules.Registry) Result {
+	drift, err := contractdrift.DetectWorkspace(workspaceRoot, registry)
+	if err != nil {
+		return errorResult(CheckContractsDrift, err)
+	}
+
+	findings := make([]Finding, 0, len(drift))
+	for _, d := range drift {
+		finding := Finding{Rule: d.Kind, Level: LevelError, Message: d.String(), File: d.Path}
+		if d.Kind == contractdrift.KindDeadModule || d.Kind == contractdrift.KindDeadGlob {
+			finding.File = path.Join("contracts", "modules", registry.Version(), d.Module+".yml")
+		}
+		findings = append(findings, finding)
+	}
+	return newResult(CheckContractsDrift, findings, fmt.Sprintf("%d drift finding(s) in %d module contract(s)", len(findings), registry.Count()))
+}
+
+// checkAffectedModules computes the modules owning the files changed between
+// the merge base and head, and the modules depending on them. It reports no
+// findings; the modules go into the report for the jobs after it.
+func checkAffectedModules(opts Options, registry *modules.Registry) (*repository.AffectedModules, Result) {
+	if opts.Base == "" {
+		return nil, skippedResult(CheckAffectedModules, "no pull request base")
+	}
+
+	output, err := repository.GitOutput(opts.WorkspaceRoot, "diff", "--name-only", opts.Base+"..."+opts.Head)
+	if err != nil {
+		return nil, errorResult(CheckAffectedModules, fmt.Errorf("failed to list the changed files: %w", err))
+	}
+	affected, err := repository.GetAffectedModules(lines(output), opts.WorkspaceRoot, registry.Version())
+	if err != nil {
+		return nil, errorResult(CheckAffectedModules, err)
+	}
+
+	summary := fmt.Sprintf("%d changed, %d dependent module(s)", len(affected.Changed), len(affected.Dependents))
+	if len(affected.Affected) > 0 {
+		summary += ": " + strings.Join(affected.Affected, ", ")
+	}
+	return affected, newResult(CheckAffectedModules, nil, summary)
+}
+
+// owningModules returns the modules owning files, sorted
+func owningModules(registry *modules.Registry, files []string) []string {
+	seen := make(map[string]bool)
+	var monikers []string
+	for _, file := range files {
+		for _, module := range registry.FindModulesForFile(file) {
+			if !seen[module.Moniker] {
+				seen[module.Moniker] = true
+				monikers = append(monikers, module.Moniker)
+			}
+		}
+	}
+	sort.Strings(monikers)
+	return monikers
+}
+
+func lines(output string) []string {
+	if output == "" {
+		return nil
+	}
+	return strings.Split(output, "\n")
+}
+
+func shortCommit(commit string) string {
+	if len(commit) > 7 {
+		return commit[:7]
+	}
+	return commit
+}
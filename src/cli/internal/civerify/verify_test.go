@@ -0,0 +1,140 @@
+//go:build L0
+// +build L0
+
+package civerify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFile(t *testing.T, root string, name string, content string) {
+	t.Helper()
+	path := filepath.Join(root, filepath.FromSlash(name))
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+}
+
+func TestCheckConfigFiles_NoFiles(t *testing.T) {
+	schema, pinned := checkConfigFiles(t.TempDir())
+
+	assert.Equal(t, StatusSkipped, schema.Status)
+	assert.Equal(t, StatusSkipped, pinned.Status)
+	assert.NotNil(t, schema.Findings)
+}
+
+func TestCheckConfigFiles_UnpinnedImagesFailPinnedCheck(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, ConfigFile, `extensions:
+  - name: pwsh
+    image: pwsh:latest
+  - name: pinned
+    image: localhost:5000/org/pinned:1.2.3
+  - name: digest
+    image: org/digest@sha256:abc
+`)
+
+	schema, pinned := checkConfigFiles(root)
+
+	assert.Equal(t, StatusPassed, schema.Status, "warnings other than unpinned-tag do not fail the schema check")
+	for _, finding := range schema.Findings {
+		assert.Equal(t, LevelWarning, finding.Level)
+	}
+
+	assert.Equal(t, StatusFailed, pinned.Status)
+	require.Len(t, pinned.Findings, 1)
+	assert.Equal(t, "unpinned-tag", pinned.Findings[0].Rule)
+	assert.Equal(t, LevelError, pinned.Findings[0].Level)
+	assert.Equal(t, ConfigFile, pinned.Findings[0].File)
+	assert.Greater(t, pinned.Findings[0].Line, 0)
+}
+
+func TestCheckConfigFiles_ParseError(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, CommitConfigFile, "rules: [unclosed\n")
+
+	schema, pinned := checkConfigFiles(root)
+
+	assert.Equal(t, StatusFailed, schema.Status)
+	require.Len(t, schema.Findings, 1)
+	assert.Equal(t, CommitConfigFile, schema.Findings[0].File)
+	assert.Equal(t, StatusPassed, pinned.Status)
+}
+
+func TestNewResult(t *testing.T) {
+	assert.Equal(t, StatusPassed, newResult("c", nil, "").Status)
+	assert.Equal(t, StatusPassed, newResult("c", []Finding{{Level: LevelWarning}}, "").Status)
+	assert.Equal(t, StatusFailed, newResult("c", []Finding{{Level: LevelWarning}, {Level: LevelError}}, "").Status)
+}
+
+func TestCheckCommitMessages_SkippedWithoutBase(t *testing.T) {
+	result := checkCommitMessages(Options{WorkspaceRoot: t.TempDir()}, nil)
+	assert.Equal(t, StatusSkipped, result.Status)
+
+	affected, result := checkAffectedModules(Options{WorkspaceRoot: t.TempDir()}, nil)
+	assert.Nil(t, affected)
+	assert.Equal(t, StatusSkipped, result.Status)
+}
+
+func sampleReport() *Report {
+	return &Report{
+		Head: "HEAD",
+		Checks: []Result{
+			newResult(CheckPinnedImages, []Finding{{Rule: "unpinned-tag", Level: LevelError, Message: "pwsh:latest", File: ConfigFile, Line: 3, Column: 12}}, "1 unpinned extension image(s)"),
+			newResult(CheckCommitMessages, []Finding{{Rule: "subject-length", Level: LevelWarning, Message: "abc1234 too long"}}, "1 commit(s), 1 finding(s)"),
+			errorResult(CheckContractsDrift, fmt.Errorf("failed to list tracked files")),
+			skippedResult(CheckAffectedModules, "no pull request base"),
+		},
+	}
+}
+
+func TestWrite_SARIF(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, sampleReport(), FormatSARIF, "1.2.3"))
+
+	var log sarifLog
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &log))
+	assert.Equal(t, "2.1.0", log.Version)
+	require.Len(t, log.Runs, 1)
+
+	driver := log.Runs[0].Tool.Driver
+	assert.Equal(t, "r2r", driver.Name)
+	assert.Equal(t, "1.2.3", driver.Version)
+	var ruleIDs []string
+	for _, rule := range driver.Rules {
+		ruleIDs = append(ruleIDs, rule.ID)
+	}
+	assert.Equal(t, []string{"commit-messages/subject-length", "contracts-drift/error", "pinned-images/unpinned-tag"}, ruleIDs)
+
+	results := log.Runs[0].Results
+	require.Len(t, results, 3)
+	assert.Equal(t, "pinned-images/unpinned-tag", results[0].RuleID)
+	require.Len(t, results[0].Locations, 1)
+	assert.Equal(t, ConfigFile, results[0].Locations[0].PhysicalLocation.ArtifactLocation.URI)
+	assert.Equal(t, 3, results[0].Locations[0].PhysicalLocation.Region.StartLine)
+	assert.Equal(t, LevelWarning, results[1].Level)
+	assert.Empty(t, results[1].Locations)
+	assert.Equal(t, "contracts-drift/error", results[2].RuleID)
+	assert.Equal(t, "failed to list tracked files", results[2].Message.Text)
+}
+
+func TestWrite_Text(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, sampleReport(), FormatText, ""))
+
+	out := buf.String()
+	assert.Contains(t, out, "pinned-images: 1 unpinned extension image(s)")
+	assert.Contains(t, out, ConfigFile+":3: pwsh:latest")
+	assert.Contains(t, out, "Verification failed")
+}
+
+func TestWrite_UnknownFormat(t *testing.T) {
+	assert.Error(t, Write(&bytes.Buffer{}, sampleReport(), "xml", ""))
+}
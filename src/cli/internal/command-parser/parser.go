@@ -4,15 +4,13 @@
 package commandparser
 
 import (
-	_ "embed"
 	"strings"
+
+	"github.com/ready-to-release/eac/contracts"
 )
 
-// Embed the EBNF command schema at compile time from contracts
-// Note: Path is relative to this file's location
-//
-//go:embed command.ebnf
-var embeddedEBNFSchema string
+// The EBNF command schema is embedded from contracts/cli/<version>/command.ebnf
+var embeddedEBNFSchema = contracts.CommandGrammar()
 
 // ParsedCommand represents a parsed command structure
 type ParsedCommand struct {
@@ -21,6 +19,7 @@ type ParsedCommand struct {
 	GlobalFlags   []string
 	Subcommand    string
 	ExtensionName string
+	RunFlags      []string // Options of the run command, between "run" and the extension name
 
 	// Argument separation - the key distinction
 	ViperArgs     []string // Arguments processed by CLI framework
@@ -38,6 +37,7 @@ type Parser struct {
 
 	validBinaryNames  map[string]bool
 	validGlobalFlags  map[string]bool
+	validRunFlags     map[string]bool // value reports whether the flag takes a value
 	validSubcommands  map[string]bool
 	requiresExtension map[string]bool
 }
@@ -60,12 +60,30 @@ func NewParser() *Parser {
 			"--r2r-quiet": true,
 		},
 
+		// From RunOption production in schema.ebnf
+		validRunFlags: map[string]bool{
+			"--json":       false,
+			"--quiet":      false,
+			"--no-ansi":    false,
+			"--log-file":   true,
+			"--all":        false,
+			"--fail-fast":  false,
+			"--keep-going": false,
+			"--timeout":    true,
+			"--print-env":  false,
+			"--events":     true,
+		},
+
 		// From Subcommand production in schema.ebnf
 		validSubcommands: map[string]bool{
 			"run":         true,
 			"version":     true,
 			"init":        true,
 			"install":     true,
+			"build":       true,
+			"extension":   true,
+			"jobs":        true,
+			"stats":       true,
 			"verify":      true,
 			"validate":    true,
 			"definitions": true,
@@ -74,6 +92,7 @@ func NewParser() *Parser {
 			"cleanup":     true,
 			"list":        true,
 			"interactive": true,
+			"ui":          true,
 			"help":        true,
 		},
 
@@ -89,6 +108,7 @@ func NewParser() *Parser {
 func (p *Parser) Parse(args []string) *ParsedCommand {
 	cmd := &ParsedCommand{
 		GlobalFlags:      []string{},
+		RunFlags:         []string{},
 		ViperArgs:        []string{},
 		ContainerArgs:    []string{},
 		ArgumentBoundary: -1,
@@ -132,7 +152,21 @@ func (p *Parser) Parse(args []string) *ParsedCommand {
 		}
 	}
 
-	// 4. Parse ExtensionName if required
+	// 4. Parse run options, which precede the extension name
+	if cmd.Subcommand == "run" {
+		for pos < len(args) && p.IsRunFlag(args[pos]) {
+			cmd.RunFlags = append(cmd.RunFlags, args[pos])
+			cmd.ViperArgs = append(cmd.ViperArgs, args[pos])
+			if p.FlagTakesValue(args[pos]) && pos+1 < len(args) {
+				pos++
+				cmd.RunFlags = append(cmd.RunFlags, args[pos])
+				cmd.ViperArgs = append(cmd.ViperArgs, args[pos])
+			}
+			pos++
+		}
+	}
+
+	// 5. Parse ExtensionName if required
 	if p.requiresExtension[cmd.Subcommand] && pos < len(args) {
 		// Accept any non-flag token as extension name
 		if !strings.HasPrefix(args[pos], "-") {
@@ -142,7 +176,7 @@ func (p *Parser) Parse(args []string) *ParsedCommand {
 		}
 	}
 
-	// 5. Handle remaining arguments
+	// 6. Handle remaining arguments
 	if cmd.Subcommand == "run" && pos < len(args) {
 		// For run command, separate r2r flags from container args
 		boundary := pos
@@ -214,6 +248,16 @@ func (p *Parser) IsValidSubcommand(cmd string) bool {
 	return p.validSubcommands[cmd]
 }
 
+// IsRunFlag checks if an argument is an option of the run command
+// A flag that takes a value may also be given as --flag=value.
+func (p *Parser) IsRunFlag(arg string) bool {
+	if name, _, found := strings.Cut(arg, "="); found {
+		return p.validRunFlags[name]
+	}
+	_, ok := p.validRunFlags[arg]
+	return ok
+}
+
 // IsR2RFlag checks if an argument is an r2r flag that should be processed by Viper
 func (p *Parser) IsR2RFlag(arg string) bool {
 	// Check global flags
@@ -226,9 +270,8 @@ func (p *Parser) IsR2RFlag(arg string) bool {
 
 // FlagTakesValue checks if a flag expects a value argument
 func (p *Parser) FlagTakesValue(flag string) bool {
-	// Currently, none of r2r's flags take values (they're all boolean)
-	// This method is here for future extensibility
-	return false
+	// Global flags are all boolean; run options declare whether they take one
+	return p.validRunFlags[flag]
 }
 
 // RequiresExtension checks if a subcommand requires an extension name
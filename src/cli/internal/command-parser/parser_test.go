@@ -171,6 +171,45 @@ func TestParser_Parse(t *testing.T) {
 	}
 }
 
+func TestParser_ParseRunFlags(t *testing.T) {
+	p := NewParser()
+
+	cmd := p.Parse([]string{"r2r", "run", "--json", "pwsh", "--json", "-c", "Get-Date"})
+	if !reflect.DeepEqual(cmd.RunFlags, []string{"--json"}) {
+		t.Errorf("RunFlags = %v, want [--json]", cmd.RunFlags)
+	}
+	if cmd.ExtensionName != "pwsh" {
+		t.Errorf("ExtensionName = %v, want pwsh", cmd.ExtensionName)
+	}
+	// Run options after the extension name belong to the container
+	if !reflect.DeepEqual(cmd.ContainerArgs, []string{"--json", "-c", "Get-Date"}) {
+		t.Errorf("ContainerArgs = %v, want [--json -c Get-Date]", cmd.ContainerArgs)
+	}
+
+	// Values follow the flag or are given inline
+	cmd = p.Parse([]string{"r2r", "run", "--log-file", "out.log", "--quiet", "--log-file=x.log", "go", "test"})
+	if !reflect.DeepEqual(cmd.RunFlags, []string{"--log-file", "out.log", "--quiet", "--log-file=x.log"}) {
+		t.Errorf("RunFlags = %v, want [--log-file out.log --quiet --log-file=x.log]", cmd.RunFlags)
+	}
+	if cmd.ExtensionName != "go" {
+		t.Errorf("ExtensionName = %v, want go", cmd.ExtensionName)
+	}
+
+	// Several extensions share the arguments after --
+	cmd = p.Parse([]string{"r2r", "run", "--keep-going", "go", "python", "--", "test"})
+	if !reflect.DeepEqual(cmd.RunFlags, []string{"--keep-going"}) {
+		t.Errorf("RunFlags = %v, want [--keep-going]", cmd.RunFlags)
+	}
+	if cmd.ExtensionName != "go" || !reflect.DeepEqual(cmd.ContainerArgs, []string{"python", "--", "test"}) {
+		t.Errorf("ExtensionName = %v, ContainerArgs = %v, want go and [python -- test]", cmd.ExtensionName, cmd.ContainerArgs)
+	}
+
+	// Run options are only recognised for the run command
+	if cmd := p.Parse([]string{"r2r", "metadata", "--json"}); len(cmd.RunFlags) != 0 {
+		t.Errorf("RunFlags = %v, want none for metadata", cmd.RunFlags)
+	}
+}
+
 func TestParser_IsValidExtensionName(t *testing.T) {
 	p := NewParser()
 
@@ -0,0 +1,140 @@
+//go:build L1
+// +build L1
+
+package conf
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Benchmarks for r2r bench. The configuration is sized like a large
+// repository: many extensions, each with its own environment.
+const (
+	benchExtensions = 50
+	benchEnvVars    = 10
+)
+
+// benchConfigYAML returns a valid configuration with n extensions
+func benchConfigYAML(n int) string {
+	var b strings.Builder
+	b.WriteString("registry:\n  default: ghcr.io\n")
+	b.WriteString("environment:\n  global:\n")
+	for i := 0; i < benchEnvVars; i++ {
+		fmt.Fprintf(&b, "    - name: GLOBAL_VAR_%d\n      value: \"%d\"\n", i, i)
+	}
+	b.WriteString("extensions:\n")
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&b, "  - name: \"ext-%d\"\n", i)
+		fmt.Fprintf(&b, "    image: \"ghcr.io/ready-to-release/r2r-cli/extensions/ext-%d:v1.%d.0\"\n", i, i)
+		b.WriteString("    env:\n")
+		for j := 0; j < benchEnvVars; j++ {
+			fmt.Fprintf(&b, "      - name: EXT_%d_VAR_%d\n        value: \"value-%d\"\n", i, j, j)
+		}
+	}
+	return b.String()
+}
+
+func writeBenchConfig(b *testing.B, name, content string) string {
+	b.Helper()
+	path := filepath.Join(b.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		b.Fatal(err)
+	}
+	return path
+}
+
+func BenchmarkConfigLoad(b *testing.B) {
+	b.Setenv("R2R_TESTING", "true")
+	path := writeBenchConfig(b, "r2r-cli.yml", benchConfigYAML(benchExtensions))
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := Load(path); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkConfigMergeFile(b *testing.B) {
+	b.Setenv("R2R_TESTING", "true")
+	base, err := Load(writeBenchConfig(b, "r2r-cli.yml", benchConfigYAML(benchExtensions)))
+	if err != nil {
+		b.Fatal(err)
+	}
+	// The override touches every tenth extension and adds a new one
+	var override strings.Builder
+	override.WriteString("extensions:\n")
+	for i := 0; i < benchExtensions; i += 10 {
+		fmt.Fprintf(&override, "  - name: \"ext-%d\"\n    load_local: true\n", i)
+	}
+	override.WriteString("  - name: \"local\"\n    image: \"local:1.0.0\"\n")
+	overridePath := writeBenchConfig(b, "r2r-cli.local.yml", override.String())
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := MergeFile(base, overridePath); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkConfigValidate(b *testing.B) {
+	b.Setenv("R2R_TESTING", "true")
+	cfg, err := ParseConfig([]byte(benchConfigYAML(benchExtensions)))
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		// Measure the checks themselves, not the cache
+		validationResults.reset()
+		if err := cfg.Validate(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkConfigValidate_Cached(b *testing.B) {
+	b.Setenv("R2R_TESTING", "true")
+	cfg, err := ParseConfig([]byte(benchConfigYAML(benchExtensions)))
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := cfg.Validate(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkConfigRevalidate_RuntimeFlags measures the --load-local case: only
+// runtime flags differ from an already validated configuration
+func BenchmarkConfigRevalidate_RuntimeFlags(b *testing.B) {
+	b.Setenv("R2R_TESTING", "true")
+	prev, err := ParseConfig([]byte(benchConfigYAML(benchExtensions)))
+	if err != nil {
+		b.Fatal(err)
+	}
+	next := prev.Clone()
+	next.LoadLocal = true
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		validationResults.reset()
+		if err := next.Revalidate(prev); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -1,7 +1,10 @@
 package conf
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"net"
 	"net/url"
 	"os"
 	"os/exec"
@@ -13,7 +16,9 @@ import (
 
 	"github.com/ready-to-release/eac/src/cli/internal/cache"
 	"github.com/ready-to-release/eac/src/cli/internal/github"
+	"github.com/ready-to-release/eac/src/cli/internal/progress"
 	"github.com/ready-to-release/eac/src/cli/internal/session"
+	"github.com/ready-to-release/eac/src/cli/internal/terminal"
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/viper"
 )
@@ -48,13 +53,26 @@ type Environment struct {
 }
 
 type Defaults struct {
-	Registry    string   `mapstructure:"registry"`
-	PullPolicy  string   `mapstructure:"pull_policy"`
-	RemoveAfter bool     `mapstructure:"remove_after"`
-	Timeout     int      `mapstructure:"timeout"`
-	MemoryLimit string   `mapstructure:"memory_limit"`
-	CPULimit    string   `mapstructure:"cpu_limit"`
-	Environment []EnvVar `mapstructure:"environment,omitempty"`
+	Registry    string    `mapstructure:"registry"`
+	PullPolicy  string    `mapstructure:"pull_policy"`
+	RemoveAfter bool      `mapstructure:"remove_after"`
+	Timeout     int       `mapstructure:"timeout"`
+	Timeouts    *Timeouts `mapstructure:"timeouts,omitempty"`
+	MemoryLimit string    `mapstructure:"memory_limit"`
+	CPULimit    string    `mapstructure:"cpu_limit"`
+	RepoAccess  string    `mapstructure:"repo_access,omitempty"`
+	RunAs       string    `mapstructure:"run_as,omitempty"`
+	Environment []EnvVar  `mapstructure:"environment,omitempty"`
+}
+
+// Timeouts bounds individual Docker operations, in seconds. Zero keeps the
+// built-in default for that operation.
+type Timeouts struct {
+	Pull     int `mapstructure:"pull"`
+	Create   int `mapstructure:"create"`
+	Start    int `mapstructure:"start"`
+	Stop     int `mapstructure:"stop"`
+	Metadata int `mapstructure:"metadata"`
 }
 
 type VolumeMount struct {
@@ -69,34 +87,150 @@ type PortMapping struct {
 }
 
 type Extension struct {
-	Name                  string        `mapstructure:"name,omitempty"`
-	Description           string        `mapstructure:"description,omitempty"`
-	Version               string        `mapstructure:"version,omitempty"`
-	Image                 string        `mapstructure:"image,omitempty"`
-	ImagePullPolicy       string        `mapstructure:"image_pull_policy,omitempty"`
-	LoadLocal             bool          `mapstructure:"load_local"`
-	AutoRemoveChildren    bool          `mapstructure:"auto_remove_children"`
-	RepoURL               string        `mapstructure:"repo_url,omitempty"`
-	DocsURL               string        `mapstructure:"docs_url,omitempty"`
-	Env                   []EnvVar      `mapstructure:"env,omitempty"`
-	Volumes               []VolumeMount `mapstructure:"volumes,omitempty"`
-	Ports                 []PortMapping `mapstructure:"ports,omitempty"`
-	WorkingDir            string        `mapstructure:"working_dir,omitempty"`
-	Entrypoint            []string      `mapstructure:"entrypoint,omitempty"`
-	Command               []string      `mapstructure:"command,omitempty"`
-	Privileged            bool          `mapstructure:"privileged"`
-	NetworkMode           string        `mapstructure:"network_mode,omitempty"`
-	MetadataSchemaVersion string        `mapstructure:"metadata_schema_version,omitempty"`
-	MemoryLimit           string        `mapstructure:"memory_limit,omitempty"`
-	CPULimit              string        `mapstructure:"cpu_limit,omitempty"`
+	Name                  string         `mapstructure:"name,omitempty"`
+	Description           string         `mapstructure:"description,omitempty"`
+	Version               string         `mapstructure:"version,omitempty"`
+	Image                 string         `mapstructure:"image,omitempty"`
+	ImagePullPolicy       string         `mapstructure:"image_pull_policy,omitempty"`
+	LoadLocal             bool           `mapstructure:"load_local"`
+	Build                 *BuildConfig   `mapstructure:"build,omitempty"`
+	Test                  *ExtensionTest `mapstructure:"test,omitempty"`
+	AutoRemoveChildren    bool           `mapstructure:"auto_remove_children"`
+	RepoURL               string         `mapstructure:"repo_url,omitempty"`
+	DocsURL               string         `mapstructure:"docs_url,omitempty"`
+	Env                   []EnvVar       `mapstructure:"env,omitempty"`
+	Volumes               []VolumeMount  `mapstructure:"volumes,omitempty"`
+	Ports                 []PortMapping  `mapstructure:"ports,omitempty"`
+	WorkingDir            string         `mapstructure:"working_dir,omitempty"`
+	Entrypoint            []string       `mapstructure:"entrypoint,omitempty"`
+	Command               []string       `mapstructure:"command,omitempty"`
+	Privileged            bool           `mapstructure:"privileged"`
+	NetworkMode           string         `mapstructure:"network_mode,omitempty"`
+	MetadataSchemaVersion string         `mapstructure:"metadata_schema_version,omitempty"`
+	MemoryLimit           string         `mapstructure:"memory_limit,omitempty"`
+	CPULimit              string         `mapstructure:"cpu_limit,omitempty"`
+	Egress                *EgressConfig  `mapstructure:"egress,omitempty"`
+	RepoAccess            string         `mapstructure:"repo_access,omitempty"`
+	MaskPaths             []string       `mapstructure:"mask_paths,omitempty"`
+	RunAs                 string         `mapstructure:"run_as,omitempty"`
+	FixOwnership          *bool          `mapstructure:"fix_ownership,omitempty"`
+	UsernsMode            string         `mapstructure:"userns_mode,omitempty"`
+	Hooks                 *Hooks         `mapstructure:"hooks,omitempty"`
+	// Source is the configuration file that defined or last changed the
+	// extension; it is set when loading, never read from the file
+	Source string `mapstructure:"-" json:"source,omitempty"`
+}
+
+// Users an extension container can run as, besides a numeric "uid[:gid]"
+const (
+	RunAsImage = "image" // the USER of the image
+	RunAsHost  = "host"  // the UID and GID of the user running r2r
+	RunAsRoot  = "root"
+)
+
+// EffectiveRunAs returns the user ext runs as: its run_as, else
+// defaults.run_as, else the user of its image
+func (c *Config) EffectiveRunAs(ext Extension) string {
+	if ext.RunAs != "" {
+		return ext.RunAs
+	}
+	if c != nil && c.Defaults != nil && c.Defaults.RunAs != "" {
+		return c.Defaults.RunAs
+	}
+	return RunAsImage
+}
+
+// Access of an extension container to the repository mounted at /var/task
+const (
+	RepoAccessReadWrite = "rw"
+	RepoAccessReadOnly  = "ro"
+	RepoAccessNone      = "none"
+)
+
+// TrustedImagePrefix is where the images of the project's own extensions are
+// published. Other images default to read-only repository access.
+const TrustedImagePrefix = "ghcr.io/ready-to-release/"
+
+// EffectiveRepoAccess returns the repository access of ext: its repo_access,
+// else defaults.repo_access, else read-write for trusted and locally built
+// images and read-only for all others
+func (c *Config) EffectiveRepoAccess(ext Extension) string {
+	if ext.RepoAccess != "" {
+		return ext.RepoAccess
+	}
+	if c != nil && c.Defaults != nil && c.Defaults.RepoAccess != "" {
+		return c.Defaults.RepoAccess
+	}
+	if ext.Build != nil || strings.HasPrefix(ext.Image, TrustedImagePrefix) {
+		return RepoAccessReadWrite
+	}
+	return RepoAccessReadOnly
+}
+
+// EgressConfig restricts the outbound network access of an extension to an
+// allow-list. The extension joins the network namespace of a helper container
+// that drops all other outbound traffic with iptables.
+type EgressConfig struct {
+	// Allow lists hostnames, IP addresses or CIDR ranges, optionally with a
+	// port, such as "proxy.golang.org", "10.0.0.0/8" or "ghcr.io:443".
+	// Hostnames are resolved when the extension starts.
+	Allow []string `mapstructure:"allow"`
+	// HelperImage is the image of the helper container; it needs a shell
+	// and either iptables or apk. Default: alpine.
+	HelperImage string `mapstructure:"helper_image,omitempty"`
+}
+
+// Hook is run around an extension run: a host command, run with the shell
+// in the repository root, or another extension with its arguments
+type Hook struct {
+	Command   string   `mapstructure:"command,omitempty"`
+	Extension string   `mapstructure:"extension,omitempty"`
+	Args      []string `mapstructure:"args,omitempty"`
+}
+
+// Hooks are run at the events of an extension run. Global hooks run before
+// the hooks of the extension.
+type Hooks struct {
+	PreRun    []Hook `mapstructure:"pre_run,omitempty"`    // before the container starts; a failure aborts the run
+	PostRun   []Hook `mapstructure:"post_run,omitempty"`   // after the run, whatever its outcome
+	OnFailure []Hook `mapstructure:"on_failure,omitempty"` // after a failed run, before post_run
+}
+
+// Notifications announce the end of operations that took longer than the
+// threshold, such as image pulls and extension runs. They are never sent in CI.
+type Notifications struct {
+	Threshold    int    `mapstructure:"threshold,omitempty"`     // seconds; default 60
+	Desktop      bool   `mapstructure:"desktop"`                 // show a notification of the OS
+	Webhook      string `mapstructure:"webhook,omitempty"`       // POST the event as JSON to this URL
+	SlackWebhook string `mapstructure:"slack_webhook,omitempty"` // post a message to this Slack incoming webhook
+}
+
+// BuildConfig describes how to build an extension image from a local
+// Dockerfile with `r2r build`. Paths are relative to the repository root.
+type BuildConfig struct {
+	Context    string            `mapstructure:"context"`
+	Dockerfile string            `mapstructure:"dockerfile,omitempty"` // Relative to Context, default "Dockerfile"
+	Args       map[string]string `mapstructure:"args,omitempty"`
+}
+
+// ExtensionTest declares the test command `r2r extension test` runs inside the
+// extension container
+type ExtensionTest struct {
+	Command []string `mapstructure:"command"`
+	JUnit   string   `mapstructure:"junit,omitempty"` // JUnit XML report, relative to the repository root
 }
 
 type Config struct {
-	Registry    *Registry    `mapstructure:"registry,omitempty"`
-	Defaults    *Defaults    `mapstructure:"defaults,omitempty"`
-	Environment *Environment `mapstructure:"environment,omitempty"`
-	Extensions  []Extension  `mapstructure:"extensions,omitempty"`
-	LoadLocal   bool         `mapstructure:"load_local"` // Global flag to use local development images
+	Registry      *Registry      `mapstructure:"registry,omitempty"`
+	Defaults      *Defaults      `mapstructure:"defaults,omitempty"`
+	Environment   *Environment   `mapstructure:"environment,omitempty"`
+	Extensions    []Extension    `mapstructure:"extensions,omitempty"`
+	Hooks         *Hooks         `mapstructure:"hooks,omitempty"`
+	Notifications *Notifications `mapstructure:"notifications,omitempty"`
+	LoadLocal     bool           `mapstructure:"load_local"` // Global flag to use local development images
+	// Sources are the configuration files merged into this configuration,
+	// lowest precedence first; they are set when loading
+	Sources []string `mapstructure:"-" json:"sources,omitempty"`
 }
 
 func (c *Config) GetExtensions() []Extension {
@@ -104,6 +238,10 @@ func (c *Config) GetExtensions() []Extension {
 	return []Extension{}
 }
 
+// Global is the configuration loaded by InitConfig.
+//
+// Deprecated: use the *Config returned by InitConfig, Load, MergeFile or
+// Discover, passed explicitly or through a context (see WithConfig).
 var Global Config
 
 // configLoaded tracks whether the configuration has been loaded
@@ -131,14 +269,42 @@ func (ve *ValidationError) HasErrors() bool {
 	return len(ve.Errors) > 0
 }
 
-// validateConfig performs comprehensive validation of configuration
+// Patterns used by validateConfig and image tag resolution, compiled once
+var (
+	// Docker image reference, with or without a tag
+	imagePattern         = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9._/-]*:[a-zA-Z0-9._-]+$|^[a-zA-Z0-9][a-zA-Z0-9._/-]*$`)
+	schemaVersionPattern = regexp.MustCompile(`^\d+\.\d+(-\d+\.\d+)?$`)
+	envVarNamePattern    = regexp.MustCompile(`^[A-Z][A-Z0-9_]*$`)
+	registryHostPattern  = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9.-]*[a-zA-Z0-9]$`)
+	registryPathPattern  = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9.-/]*[a-zA-Z0-9]$`)
+	memoryPattern        = regexp.MustCompile(`^(\d+(\.\d+)?)\s*([bBkKmMgG][bB]?)$`)
+	runTagPattern        = regexp.MustCompile(`^run-\d+$`)
+	runAsPattern         = regexp.MustCompile(`^(image|host|root|\d+(:\d+)?)$`)
+	hostnamePattern      = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9.-]*[a-zA-Z0-9])?$`)
+	semverTagPattern     = regexp.MustCompile(`^v?\d+\.\d+\.\d+$`)
+)
+
+// validateConfig performs comprehensive validation of configuration. Results
+// are cached by content, see validation_cache.go.
 func validateConfig(cfg *Config) error {
+	key, ok := validationKey(cfg)
+	if !ok {
+		return checkConfig(cfg)
+	}
+	if err, hit := validationResults.get(key); hit {
+		return err
+	}
+	err := checkConfig(cfg)
+	validationResults.put(key, err)
+	return err
+}
+
+// checkConfig runs the validation rules. It must only depend on cfg, since
+// its result is cached.
+func checkConfig(cfg *Config) error {
 	validationErrors := &ValidationError{}
 	extensionNames := make(map[string]bool)
 
-	// Docker image reference regex pattern
-	imagePattern := regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9._/-]*:[a-zA-Z0-9._-]+$|^[a-zA-Z0-9][a-zA-Z0-9._/-]*$`)
-
 	for i, ext := range cfg.Extensions {
 		extContext := fmt.Sprintf("extension[%d]", i)
 		if ext.Name != "" {
@@ -194,6 +360,33 @@ func validateConfig(cfg *Config) error {
 			}
 		}
 
+		// Build validation
+		if ext.Build != nil {
+			if ext.Build.Context == "" {
+				validationErrors.Add(fmt.Sprintf("%s.build: context is required", extContext))
+			} else if filepath.IsAbs(ext.Build.Context) {
+				validationErrors.Add(fmt.Sprintf("%s.build: context %q must be relative to the repository root", extContext, ext.Build.Context))
+			}
+			if ext.Build.Dockerfile != "" && filepath.IsAbs(ext.Build.Dockerfile) {
+				validationErrors.Add(fmt.Sprintf("%s.build: dockerfile %q must be relative to the build context", extContext, ext.Build.Dockerfile))
+			}
+		}
+
+		// Test validation
+		if ext.Test != nil {
+			if len(ext.Test.Command) == 0 {
+				validationErrors.Add(fmt.Sprintf("%s.test: command is required", extContext))
+			}
+			if ext.Test.JUnit != "" && filepath.IsAbs(ext.Test.JUnit) {
+				validationErrors.Add(fmt.Sprintf("%s.test: junit %q must be relative to the repository root", extContext, ext.Test.JUnit))
+			}
+		}
+
+		// Metadata schema version: "1.0" or an inclusive range "1.0-1.2"
+		if ext.MetadataSchemaVersion != "" && !schemaVersionPattern.MatchString(ext.MetadataSchemaVersion) {
+			validationErrors.Add(fmt.Sprintf("%s: invalid metadata_schema_version %q, must be a version like 1.0 or a range like 1.0-1.2", extContext, ext.MetadataSchemaVersion))
+		}
+
 		// Environment variable validation
 		envNames := make(map[string]bool)
 		for j, envVar := range ext.Env {
@@ -209,7 +402,7 @@ func validateConfig(cfg *Config) error {
 				envNames[envVar.Name] = true
 
 				// Validate environment variable name format
-				if !regexp.MustCompile(`^[A-Z][A-Z0-9_]*$`).MatchString(envVar.Name) {
+				if !envVarNamePattern.MatchString(envVar.Name) {
 					validationErrors.Add(fmt.Sprintf("%s: invalid environment variable name %q, must be uppercase alphanumeric with underscores", envContext, envVar.Name))
 				}
 			}
@@ -227,6 +420,34 @@ func validateConfig(cfg *Config) error {
 			}
 		}
 
+		// Egress allow-list validation
+		if ext.Egress != nil {
+			for j, entry := range ext.Egress.Allow {
+				if _, _, err := ParseEgressEntry(entry); err != nil {
+					validationErrors.Add(fmt.Sprintf("%s.egress.allow[%d]: %v", extContext, j, err))
+				}
+			}
+			if ext.NetworkMode != "" {
+				validationErrors.Add(fmt.Sprintf("%s: egress cannot be combined with network_mode %q", extContext, ext.NetworkMode))
+			}
+		}
+
+		// Repository access validation
+		if ext.RepoAccess != "" && !validRepoAccess(ext.RepoAccess) {
+			validationErrors.Add(fmt.Sprintf("%s: invalid repo_access %q, must be one of: rw, ro, none", extContext, ext.RepoAccess))
+		}
+		if ext.RunAs != "" && !validRunAs(ext.RunAs) {
+			validationErrors.Add(fmt.Sprintf("%s: invalid run_as %q, must be image, host, root or a numeric uid[:gid]", extContext, ext.RunAs))
+		}
+		for j, path := range ext.MaskPaths {
+			clean := filepath.ToSlash(filepath.Clean(path))
+			if path == "" || filepath.IsAbs(path) || strings.HasPrefix(path, "/") || clean == "." || clean == ".." || strings.HasPrefix(clean, "../") {
+				validationErrors.Add(fmt.Sprintf("%s.mask_paths[%d]: %q must be a path inside the repository, relative to its root", extContext, j, path))
+			}
+		}
+
+		validateHooks(cfg, ext.Hooks, extContext+".hooks", validationErrors)
+
 		// Volume mount validation
 		for j, volume := range ext.Volumes {
 			volumeContext := fmt.Sprintf("%s.volumes[%d]", extContext, j)
@@ -266,11 +487,30 @@ func validateConfig(cfg *Config) error {
 		}
 	}
 
+	validateHooks(cfg, cfg.Hooks, "hooks", validationErrors)
+
+	// Notification validation
+	if n := cfg.Notifications; n != nil {
+		if n.Threshold < 0 {
+			validationErrors.Add("notifications.threshold: must be non-negative")
+		}
+		webhooks := []struct{ name, url string }{{"webhook", n.Webhook}, {"slack_webhook", n.SlackWebhook}}
+		for _, webhook := range webhooks {
+			if webhook.url == "" {
+				continue
+			}
+			if parsedURL, err := url.Parse(webhook.url); err != nil || (parsedURL.Scheme != "https" && parsedURL.Scheme != "http") || parsedURL.Host == "" {
+				// The URL is left out: the path of a webhook is its secret
+				validationErrors.Add(fmt.Sprintf("notifications.%s: invalid URL: must be an http or https URL", webhook.name))
+			}
+		}
+	}
+
 	// Registry configuration validation
 	if cfg.Registry != nil {
 		if cfg.Registry.Default != "" {
 			// Validate hostname format
-			if !regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9.-]*[a-zA-Z0-9]$`).MatchString(cfg.Registry.Default) {
+			if !registryHostPattern.MatchString(cfg.Registry.Default) {
 				validationErrors.Add(fmt.Sprintf("registry.default: invalid hostname %q", cfg.Registry.Default))
 			}
 		}
@@ -282,10 +522,10 @@ func validateConfig(cfg *Config) error {
 		}
 		if cfg.Registry.Authentication != nil {
 			auth := cfg.Registry.Authentication
-			if auth.UsernameEnv != "" && !regexp.MustCompile(`^[A-Z][A-Z0-9_]*$`).MatchString(auth.UsernameEnv) {
+			if auth.UsernameEnv != "" && !envVarNamePattern.MatchString(auth.UsernameEnv) {
 				validationErrors.Add(fmt.Sprintf("registry.authentication.username_env: invalid environment variable name %q", auth.UsernameEnv))
 			}
-			if auth.TokenEnv != "" && !regexp.MustCompile(`^[A-Z][A-Z0-9_]*$`).MatchString(auth.TokenEnv) {
+			if auth.TokenEnv != "" && !envVarNamePattern.MatchString(auth.TokenEnv) {
 				validationErrors.Add(fmt.Sprintf("registry.authentication.token_env: invalid environment variable name %q", auth.TokenEnv))
 			}
 		}
@@ -304,7 +544,7 @@ func validateConfig(cfg *Config) error {
 					validationErrors.Add(fmt.Sprintf("%s: duplicate environment variable name %q", envContext, envVar.Name))
 				}
 				globalVarNames[envVar.Name] = true
-				if !regexp.MustCompile(`^[A-Z][A-Z0-9_]*$`).MatchString(envVar.Name) {
+				if !envVarNamePattern.MatchString(envVar.Name) {
 					validationErrors.Add(fmt.Sprintf("%s: invalid environment variable name %q", envContext, envVar.Name))
 				}
 			}
@@ -321,14 +561,14 @@ func validateConfig(cfg *Config) error {
 					validationErrors.Add(fmt.Sprintf("%s: duplicate secret variable name %q", secretContext, secretVar.Name))
 				}
 				secretVarNames[secretVar.Name] = true
-				if !regexp.MustCompile(`^[A-Z][A-Z0-9_]*$`).MatchString(secretVar.Name) {
+				if !envVarNamePattern.MatchString(secretVar.Name) {
 					validationErrors.Add(fmt.Sprintf("%s: invalid environment variable name %q", secretContext, secretVar.Name))
 				}
 			}
 			if secretVar.Env == "" {
 				validationErrors.Add(fmt.Sprintf("%s: env is required", secretContext))
 			} else {
-				if !regexp.MustCompile(`^[A-Z][A-Z0-9_]*$`).MatchString(secretVar.Env) {
+				if !envVarNamePattern.MatchString(secretVar.Env) {
 					validationErrors.Add(fmt.Sprintf("%s: invalid host environment variable name %q", secretContext, secretVar.Env))
 				}
 			}
@@ -339,7 +579,7 @@ func validateConfig(cfg *Config) error {
 	if cfg.Defaults != nil {
 		if cfg.Defaults.Registry != "" {
 			// Validate registry prefix format
-			if !regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9.-/]*[a-zA-Z0-9]$`).MatchString(cfg.Defaults.Registry) {
+			if !registryPathPattern.MatchString(cfg.Defaults.Registry) {
 				validationErrors.Add(fmt.Sprintf("defaults.registry: invalid registry prefix %q", cfg.Defaults.Registry))
 			}
 		}
@@ -360,6 +600,14 @@ func validateConfig(cfg *Config) error {
 		if cfg.Defaults.Timeout < 0 {
 			validationErrors.Add("defaults.timeout: must be non-negative")
 		}
+		if t := cfg.Defaults.Timeouts; t != nil {
+			names := []string{"pull", "create", "start", "stop", "metadata"}
+			for i, seconds := range []int{t.Pull, t.Create, t.Start, t.Stop, t.Metadata} {
+				if seconds < 0 {
+					validationErrors.Add(fmt.Sprintf("defaults.timeouts.%s: must be non-negative", names[i]))
+				}
+			}
+		}
 		if cfg.Defaults.MemoryLimit != "" {
 			if err := validateMemoryLimit(cfg.Defaults.MemoryLimit); err != nil {
 				validationErrors.Add(fmt.Sprintf("defaults.memory_limit: %v", err))
@@ -370,6 +618,12 @@ func validateConfig(cfg *Config) error {
 				validationErrors.Add(fmt.Sprintf("defaults.cpu_limit: %v", err))
 			}
 		}
+		if cfg.Defaults.RunAs != "" && !validRunAs(cfg.Defaults.RunAs) {
+			validationErrors.Add(fmt.Sprintf("defaults.run_as: invalid value %q, must be image, host, root or a numeric uid[:gid]", cfg.Defaults.RunAs))
+		}
+		if cfg.Defaults.RepoAccess != "" && !validRepoAccess(cfg.Defaults.RepoAccess) {
+			validationErrors.Add(fmt.Sprintf("defaults.repo_access: invalid value %q, must be one of: rw, ro, none", cfg.Defaults.RepoAccess))
+		}
 
 		// Validate default environment variables
 		defaultVarNames := make(map[string]bool)
@@ -382,7 +636,7 @@ func validateConfig(cfg *Config) error {
 					validationErrors.Add(fmt.Sprintf("%s: duplicate environment variable name %q", envContext, envVar.Name))
 				}
 				defaultVarNames[envVar.Name] = true
-				if !regexp.MustCompile(`^[A-Z][A-Z0-9_]*$`).MatchString(envVar.Name) {
+				if !envVarNamePattern.MatchString(envVar.Name) {
 					validationErrors.Add(fmt.Sprintf("%s: invalid environment variable name %q", envContext, envVar.Name))
 				}
 			}
@@ -395,63 +649,54 @@ func validateConfig(cfg *Config) error {
 	return nil
 }
 
-// LoadConfig takes a named config file and loads it using viper
-func LoadConfig(configFile string) error {
-	viper.SetConfigFile(configFile)
-	if err := viper.ReadInConfig(); err != nil {
-		return WrapConfigError(err, configFile)
+// Validate runs the semantic checks applied when a configuration is loaded
+func (c *Config) Validate() error {
+	return validateConfig(c)
+}
+
+// ParseConfig decodes YAML configuration content without touching Global
+func ParseConfig(data []byte) (*Config, error) {
+	v := viper.New()
+	v.SetConfigType("yaml")
+	if err := v.ReadConfig(bytes.NewReader(data)); err != nil {
+		return nil, err
 	}
 
-	if err := viper.Unmarshal(&Global); err != nil {
-		return NewYAMLUnmarshalError(configFile, err)
+	var cfg Config
+	if err := v.Unmarshal(&cfg); err != nil {
+		return nil, err
 	}
+	return &cfg, nil
+}
 
-	if err := validateConfig(&Global); err != nil {
-		return NewValidationError(configFile, err)
+// LoadConfig takes a named config file and loads it into Global.
+//
+// Deprecated: use Load, which returns the configuration instead.
+func LoadConfig(configFile string) error {
+	cfg, err := Load(configFile)
+	if err != nil {
+		return err
 	}
+	setGlobal(cfg)
 
 	// Check for "latest" tags and log warnings only if not already loaded
 	if !configLoaded {
-		checkLatestTags(&Global)
+		checkLatestTags(cfg)
 		configLoaded = true
 	}
 
 	return nil
 }
 
-// MergeConfigFile merges an override configuration file into the existing Global config
+// MergeConfigFile merges an override configuration file into Global.
+//
+// Deprecated: use MergeFile, which returns the merged configuration instead.
 func MergeConfigFile(configFile string) error {
-	// Create a new viper instance for the override file
-	overrideViper := viper.New()
-	overrideViper.SetConfigFile(configFile)
-
-	if err := overrideViper.ReadInConfig(); err != nil {
-		return WrapConfigError(err, configFile)
-	}
-
-	// Create a temporary config to hold the override values
-	var overrideConfig Config
-	if err := overrideViper.Unmarshal(&overrideConfig); err != nil {
-		return NewYAMLUnmarshalError(configFile, err)
-	}
-
-	// Don't validate the override config - it's allowed to have partial definitions
-	// The validation will happen after merging with the base config
-	log.Debug().Str("file", configFile).Msg("Merging override configuration (validation skipped for partial config)")
-
-	// Merge the override config into the Global config
-	mergeConfigs(&Global, &overrideConfig)
-
-	// Re-validate the merged configuration
-	if err := validateConfig(&Global); err != nil {
-		// Don't attribute the error to the override file - it's the merged config that failed
-		log.Error().Str("file", configFile).Err(err).Msg("Merged configuration validation failed")
-		return fmt.Errorf("merged configuration is invalid after applying %s: %w", configFile, err)
+	merged, err := MergeFile(Current(), configFile)
+	if err != nil {
+		return err
 	}
-
-	// Don't check for latest tags again - already done in LoadConfig
-	// checkLatestTags(&Global)
-
+	setGlobal(merged)
 	return nil
 }
 
@@ -495,12 +740,21 @@ func mergeConfigs(base *Config, override *Config) {
 			if override.Defaults.Timeout != 0 {
 				base.Defaults.Timeout = override.Defaults.Timeout
 			}
+			if override.Defaults.Timeouts != nil {
+				base.Defaults.Timeouts = mergeTimeouts(base.Defaults.Timeouts, override.Defaults.Timeouts)
+			}
 			if override.Defaults.MemoryLimit != "" {
 				base.Defaults.MemoryLimit = override.Defaults.MemoryLimit
 			}
 			if override.Defaults.CPULimit != "" {
 				base.Defaults.CPULimit = override.Defaults.CPULimit
 			}
+			if override.Defaults.RepoAccess != "" {
+				base.Defaults.RepoAccess = override.Defaults.RepoAccess
+			}
+			if override.Defaults.RunAs != "" {
+				base.Defaults.RunAs = override.Defaults.RunAs
+			}
 			if len(override.Defaults.Environment) > 0 {
 				base.Defaults.Environment = mergeEnvVars(base.Defaults.Environment, override.Defaults.Environment)
 			}
@@ -521,6 +775,17 @@ func mergeConfigs(base *Config, override *Config) {
 		}
 	}
 
+	// Hooks of the override replace the base hooks
+	if override.Hooks != nil {
+		base.Hooks = override.Hooks
+	}
+
+	// Notifications of the override replace the base notifications, so a
+	// local file can turn them on for one user
+	if override.Notifications != nil {
+		base.Notifications = override.Notifications
+	}
+
 	// Merge Extensions - this is the most important part for the integration tests
 	// Override extensions completely replace base extensions with the same name
 	if len(override.Extensions) > 0 {
@@ -574,6 +839,14 @@ func mergeExtension(base *Extension, override *Extension) {
 		base.LoadLocal = override.LoadLocal
 	}
 
+	// Override build and test as a whole, so a local file can point them elsewhere
+	if override.Build != nil {
+		base.Build = override.Build
+	}
+	if override.Test != nil {
+		base.Test = override.Test
+	}
+
 	// Merge environment variables
 	if len(override.Env) > 0 {
 		base.Env = mergeEnvVars(base.Env, override.Env)
@@ -615,6 +888,58 @@ func mergeExtension(base *Extension, override *Extension) {
 	if override.Privileged {
 		base.Privileged = override.Privileged
 	}
+
+	// Override access controls if specified
+	if override.Egress != nil {
+		base.Egress = override.Egress
+	}
+	if override.RepoAccess != "" {
+		base.RepoAccess = override.RepoAccess
+	}
+	if len(override.MaskPaths) > 0 {
+		base.MaskPaths = override.MaskPaths
+	}
+	if override.RunAs != "" {
+		base.RunAs = override.RunAs
+	}
+	if override.FixOwnership != nil {
+		base.FixOwnership = override.FixOwnership
+	}
+	if override.UsernsMode != "" {
+		base.UsernsMode = override.UsernsMode
+	}
+
+	// Override hooks as a whole
+	if override.Hooks != nil {
+		base.Hooks = override.Hooks
+	}
+
+	if override.Source != "" {
+		base.Source = override.Source
+	}
+}
+
+// mergeTimeouts overrides the non-zero timeouts of base
+func mergeTimeouts(base *Timeouts, override *Timeouts) *Timeouts {
+	if base == nil {
+		return override
+	}
+	if override.Pull != 0 {
+		base.Pull = override.Pull
+	}
+	if override.Create != 0 {
+		base.Create = override.Create
+	}
+	if override.Start != 0 {
+		base.Start = override.Start
+	}
+	if override.Stop != 0 {
+		base.Stop = override.Stop
+	}
+	if override.Metadata != 0 {
+		base.Metadata = override.Metadata
+	}
+	return base
 }
 
 // mergeEnvVars merges environment variables, with override taking precedence
@@ -665,42 +990,16 @@ func mergeSecretVars(base []SecretVar, override []SecretVar) []SecretVar {
 
 // detectCIEnvironment checks if the CLI is running in a CI/CD environment
 func detectCIEnvironment() bool {
-	// Common CI environment variables
-	ciVars := []string{
-		"CI",                     // Generic CI indicator (GitHub Actions, GitLab CI, CircleCI, etc.)
-		"GITHUB_ACTIONS",         // GitHub Actions
-		"GITLAB_CI",              // GitLab CI
-		"JENKINS_URL",            // Jenkins
-		"TEAMCITY_VERSION",       // TeamCity
-		"BUILDKITE",              // Buildkite
-		"DRONE",                  // Drone
-		"TRAVIS",                 // Travis CI
-		"CIRCLECI",               // CircleCI
-		"APPVEYOR",               // AppVeyor
-		"CODEBUILD_BUILD_ID",     // AWS CodeBuild
-		"BITBUCKET_BUILD_NUMBER", // Bitbucket Pipelines
-		"AZURE_PIPELINES",        // Azure DevOps
-		"BUILD_ID",               // Various CI systems
-		"CONTINUOUS_INTEGRATION", // Generic
-	}
-
-	for _, v := range ciVars {
-		if val := os.Getenv(v); val != "" && val != "false" && val != "0" {
-			return true
-		}
-	}
-
-	return false
+	return terminal.IsCI()
 }
 
 // ValidatePinnedExtensions validates that extensions use pinned tags in CI environments
 // Returns an error if running in CI and extensions have unpinned tags
 func ValidatePinnedExtensions(cfg *Config, isCI bool) ([]string, error) {
-	// Load or create cache
-	registryCache, _ := cache.Load()
-	if registryCache == nil {
-		// Create new empty cache if none exists
-		registryCache, _ = cache.Load() // This returns an empty cache when file doesn't exist
+	// Load the cache; a missing or broken cache file yields an empty cache
+	registryCache, err := cache.Load()
+	if err != nil {
+		log.Debug().Err(err).Msg("Failed to load registry cache, starting empty")
 	}
 
 	// Determine cache TTL
@@ -715,6 +1014,11 @@ func ValidatePinnedExtensions(cfg *Config, isCI bool) ([]string, error) {
 	// Collect unpinned extensions for error reporting (CI) and warning suppression check
 	var unpinnedExtensions []string
 
+	// Registry failures are reported once for all extensions
+	var lookupErr error
+	var skippedLookups int
+	var staleExtensions []string
+
 	for _, ext := range cfg.Extensions {
 		if hasLatestTag(ext.Image) {
 			// Extract the base image without tag
@@ -743,11 +1047,30 @@ func ValidatePinnedExtensions(cfg *Config, isCI bool) ([]string, error) {
 					}
 				}
 
-				// If not in cache or cache expired, fetch from GHCR
+				// If not in cache or cache expired, fetch from GHCR unless the
+				// lookup failed recently or lookups are suspended
+				if pinnedVersion == "" {
+					if _, failed := registryCache.RecentFailure(extensionName); failed || registryCache.CircuitOpen() {
+						log.Debug().Str("extension", extensionName).Msg("Skipping GHCR lookup after recent failures")
+						skippedLookups++
+					} else {
+						log.Debug().Str("extension", extensionName).Str("baseImage", baseImage).Msg("Fetching latest tags from GHCR")
+						tag, err := fetchAndCacheExtensionTags(cfg, baseImage, extensionName, registryCache)
+						if err != nil && !errors.Is(err, errNoRegistryClient) {
+							registryCache.RecordFailure(extensionName, err)
+							lookupErr = err
+						}
+						pinnedVersion = tag
+						log.Debug().Str("extension", extensionName).Str("pinnedVersion", pinnedVersion).Msg("Fetched pin version")
+					}
+				}
+
+				// Degrade to expired cache data when the registry gives nothing
 				if pinnedVersion == "" {
-					log.Debug().Str("extension", extensionName).Str("baseImage", baseImage).Msg("Fetching latest tags from GHCR")
-					pinnedVersion = fetchAndCacheExtensionTags(baseImage, extensionName, registryCache)
-					log.Debug().Str("extension", extensionName).Str("pinnedVersion", pinnedVersion).Msg("Fetched pin version")
+					if extCache, ok := registryCache.GetExtension(extensionName); ok && extCache.LatestSHA != "" {
+						pinnedVersion = extCache.LatestSHA
+						staleExtensions = append(staleExtensions, extensionName)
+					}
 				}
 			}
 
@@ -762,6 +1085,14 @@ func ValidatePinnedExtensions(cfg *Config, isCI bool) ([]string, error) {
 		}
 	}
 
+	// Warn when a lookup failed in this run; lookups skipped after earlier
+	// failures were already reported then
+	if lookupErr != nil {
+		warnRegistryUnavailable(registryCache, lookupErr, staleExtensions)
+	} else if skippedLookups > 0 {
+		log.Debug().Int("skipped", skippedLookups).Strs("stale", staleExtensions).Msg("GHCR lookups suspended after recent failures")
+	}
+
 	// Save updated cache
 	log.Debug().
 		Int("extensionsInCache", len(registryCache.Extensions)).
@@ -792,7 +1123,7 @@ func checkLatestTags(cfg *Config) {
 
 	// Determine if we should suppress warnings (but still update cache)
 	suppressWarnings := false
-	var sessionID string
+	var state *session.Store
 
 	// Check warning suppression for non-CI environments
 	if !isCI {
@@ -800,17 +1131,9 @@ func checkLatestTags(cfg *Config) {
 		if os.Getenv("R2R_SKIP_PIN_WARNING") == "true" {
 			suppressWarnings = true
 		} else {
-			// Get session identifier (parent process ID works across platforms)
-			sessionID = session.GetIdentifier()
-
-			// Check if warnings were shown recently (within last hour) for this session
-			warningFile := filepath.Join(os.TempDir(), fmt.Sprintf("nncli-%s-warning-disabled", sessionID))
-			if stat, err := os.Stat(warningFile); err == nil {
-				// If file exists and was modified within the last hour, suppress warnings
-				if time.Since(stat.ModTime()) < time.Hour {
-					suppressWarnings = true
-				}
-			}
+			// Warnings are shown once per hour per shell session
+			state = session.DefaultStore()
+			suppressWarnings = state.Marked(session.KeyPinWarningShown)
 		}
 	}
 	// In CI, we NEVER suppress - always check and fail on unpinned extensions
@@ -833,30 +1156,42 @@ func checkLatestTags(cfg *Config) {
 			log.Warn().Msg(msg)
 		}
 
-		// Create/touch warning file to indicate warnings were shown for this session
-		if sessionID == "" {
-			sessionID = session.GetIdentifier()
-		}
-		warningFilePath := filepath.Join(os.TempDir(), fmt.Sprintf("nncli-%s-warning-disabled", sessionID))
-		if file, err := os.Create(warningFilePath); err == nil {
-			file.Close()
-			log.Debug().Str("file", warningFilePath).Str("session", sessionID).Msg("Created warning flag file")
+		// Remember that warnings were shown for this session
+		if err := state.Mark(session.KeyPinWarningShown, time.Hour); err != nil {
+			log.Debug().Err(err).Str("file", state.Path()).Msg("Failed to record pin warnings in session state")
 		}
+	}
+}
 
-		// Also set environment variable for current process tree
-		os.Setenv("R2R_SKIP_PIN_WARNING", "true")
+// NewRegistryClient creates a GitHub registry client whose requests are
+// bounded by registry.timeout
+func NewRegistryClient(cfg *Config) (*github.RegistryClient, error) {
+	client, err := github.NewRegistryClient()
+	if err != nil {
+		return nil, err
+	}
+	if cfg != nil && cfg.Registry != nil {
+		client.SetTimeout(time.Duration(cfg.Registry.Timeout) * time.Second)
 	}
+	return client, nil
 }
 
+// errNoRegistryClient is returned by fetchAndCacheExtensionTags when no
+// registry credentials are configured. It is not a registry failure.
+var errNoRegistryClient = errors.New("no registry client")
+
 // fetchAndCacheExtensionTags fetches tags from GHCR and updates cache
-func fetchAndCacheExtensionTags(baseImage, extensionName string, registryCache *cache.RegistryCache) string {
+func fetchAndCacheExtensionTags(cfg *Config, baseImage, extensionName string, registryCache *cache.RegistryCache) (string, error) {
 	log.Debug().Str("baseImage", baseImage).Str("extensionName", extensionName).Msg("fetchAndCacheExtensionTags called")
-	client, err := github.NewRegistryClient()
+	client, err := NewRegistryClient(cfg)
 	if err != nil {
 		log.Debug().Err(err).Msg("Failed to create registry client")
-		return ""
+		return "", fmt.Errorf("%w: %v", errNoRegistryClient, err)
 	}
 
+	lookup := progress.Spinner("Checking the latest tag of " + extensionName)
+	defer lookup.Done("")
+
 	// Get the latest stable tag
 	latestTag, err := client.GetLatestStableTag(baseImage)
 	if err != nil {
@@ -865,7 +1200,7 @@ func fetchAndCacheExtensionTags(baseImage, extensionName string, registryCache *
 		latestTag, err = client.GetLatestTag(baseImage)
 		if err != nil {
 			log.Debug().Err(err).Str("baseImage", baseImage).Msg("Failed to get any tag")
-			return ""
+			return "", err
 		}
 	}
 	log.Debug().Str("latestTag", latestTag).Msg("Got latest tag")
@@ -875,6 +1210,7 @@ func fetchAndCacheExtensionTags(baseImage, extensionName string, registryCache *
 
 	// Update cache
 	registryCache.SetExtension(extensionName, latestTag, allTags)
+	registryCache.RecordSuccess(extensionName)
 	log.Debug().
 		Str("extensionName", extensionName).
 		Str("latestTag", latestTag).
@@ -882,11 +1218,26 @@ func fetchAndCacheExtensionTags(baseImage, extensionName string, registryCache *
 		Int("cacheExtensions", len(registryCache.Extensions)).
 		Msg("Updated cache with extension data")
 
-	return latestTag
+	return latestTag, nil
+}
+
+// warnRegistryUnavailable logs a single warning for the failed GHCR lookups
+// of a pin check, naming the extensions served from expired cache data
+func warnRegistryUnavailable(registryCache *cache.RegistryCache, lookupErr error, staleExtensions []string) {
+	event := log.Warn().Err(lookupErr)
+	if registryCache.CircuitOpen() {
+		event = event.Time("retry_after", registryCache.Breaker.OpenUntil)
+	}
+	if len(staleExtensions) > 0 {
+		event.Strs("extensions", staleExtensions).
+			Msg("GitHub Container Registry unavailable, using cached tags for pin checks")
+		return
+	}
+	event.Msg("GitHub Container Registry unavailable, pin checks cannot suggest tags")
 }
 
 // getActualImageVersion tries to suggest a proper version tag
-func getActualImageVersion(image string) string {
+func getActualImageVersion(cfg *Config, image string) string {
 	// Extract base image name without tag
 	baseImage := image
 	if idx := strings.LastIndex(image, ":"); idx > 0 {
@@ -904,8 +1255,8 @@ func getActualImageVersion(image string) string {
 
 	// Determine cache TTL (default 300 seconds = 5 minutes)
 	cacheTTL := 300
-	if Global.Registry != nil && Global.Registry.CacheTTL > 0 {
-		cacheTTL = Global.Registry.CacheTTL
+	if cfg.Registry != nil && cfg.Registry.CacheTTL > 0 {
+		cacheTTL = cfg.Registry.CacheTTL
 	}
 
 	// Try to use cached data first
@@ -926,7 +1277,7 @@ func getActualImageVersion(image string) string {
 	}
 
 	// Cache miss or expired, try to query GitHub registry
-	client, err := github.NewRegistryClient()
+	client, err := NewRegistryClient(cfg)
 	if err == nil {
 		log.Debug().
 			Str("image", baseImage).
@@ -975,7 +1326,6 @@ func getActualImageVersion(image string) string {
 		lines := strings.Split(string(output), "\n")
 
 		// Look for run-XXX tags first (stable releases)
-		runTagPattern := regexp.MustCompile(`^run-\d+$`)
 		var bestRunTag string
 		var bestRunNum int
 
@@ -998,7 +1348,7 @@ func getActualImageVersion(image string) string {
 		// Look for stable semantic version tags (e.g., 1.0.0, v1.2.3)
 		for _, line := range lines {
 			tag := strings.TrimSpace(line)
-			if tag != "" && (regexp.MustCompile(`^v?\d+\.\d+\.\d+$`).MatchString(tag)) {
+			if tag != "" && (semverTagPattern.MatchString(tag)) {
 				return tag
 			}
 		}
@@ -1046,6 +1396,26 @@ func hasLatestTag(image string) bool {
 	return false
 }
 
+// Pin states of an extension image, see PinStatus
+const (
+	PinPinned   = "pinned"   // a sha- tag or a digest
+	PinTagged   = "tagged"   // another fixed tag, e.g. a version
+	PinUnpinned = "unpinned" // latest, main, master or no tag
+)
+
+// PinStatus classifies how firmly an image reference is pinned, without
+// querying the registry
+func PinStatus(image string) string {
+	switch {
+	case image == "" || hasLatestTag(image):
+		return PinUnpinned
+	case strings.Contains(image, "@sha256:"), strings.Contains(image[strings.LastIndex(image, "/")+1:], ":sha-"):
+		return PinPinned
+	default:
+		return PinTagged
+	}
+}
+
 // validateMemoryLimit validates Docker memory limit format (e.g., "512MB", "1GB")
 func validateMemoryLimit(limit string) error {
 	if limit == "" {
@@ -1054,7 +1424,6 @@ func validateMemoryLimit(limit string) error {
 
 	// Match Docker memory limit format: number followed by unit (b, k, m, g)
 	// Case insensitive, supports: b, k, m, g (bytes, kilobytes, megabytes, gigabytes)
-	memoryPattern := regexp.MustCompile(`^(\d+(\.\d+)?)\s*([bBkKmMgG][bB]?)$`)
 	if !memoryPattern.MatchString(limit) {
 		return fmt.Errorf("invalid memory limit format %q: must be a number followed by unit (B, KB, MB, GB)", limit)
 	}
@@ -1090,3 +1459,58 @@ func validateCPULimit(limit string) error {
 
 	return nil
 }
+
+// validateHooks checks that every hook runs either a command or a configured
+// extension
+func validateHooks(cfg *Config, hooks *Hooks, context string, validationErrors *ValidationError) {
+	if hooks == nil {
+		return
+	}
+	events := []struct {
+		name  string
+		hooks []Hook
+	}{{"pre_run", hooks.PreRun}, {"post_run", hooks.PostRun}, {"on_failure", hooks.OnFailure}}
+	for _, event := range events {
+		for i, hook := range event.hooks {
+			hookContext := fmt.Sprintf("%s.%s[%d]", context, event.name, i)
+			switch {
+			case (hook.Command == "") == (hook.Extension == ""):
+				validationErrors.Add(fmt.Sprintf("%s: exactly one of command or extension is required", hookContext))
+			case hook.Command != "" && len(hook.Args) > 0:
+				validationErrors.Add(fmt.Sprintf("%s: args apply to extension hooks, put them in the command", hookContext))
+			case hook.Extension != "":
+				if _, ok := cfg.FindExtension(hook.Extension); !ok {
+					validationErrors.Add(fmt.Sprintf("%s: extension %q is not configured", hookContext, hook.Extension))
+				}
+			}
+		}
+	}
+}
+
+func validRunAs(runAs string) bool {
+	return runAsPattern.MatchString(runAs)
+}
+
+func validRepoAccess(access string) bool {
+	return access == RepoAccessReadWrite || access == RepoAccessReadOnly || access == RepoAccessNone
+}
+
+// ParseEgressEntry splits an egress allow-list entry into its host, IP
+// address or CIDR range and its port, 0 for any port
+func ParseEgressEntry(entry string) (string, int, error) {
+	host, port := entry, 0
+	if h, p, err := net.SplitHostPort(entry); err == nil {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 1 || n > 65535 {
+			return "", 0, fmt.Errorf("invalid port %q in %q", p, entry)
+		}
+		host, port = h, n
+	}
+	if _, _, err := net.ParseCIDR(host); err == nil || net.ParseIP(host) != nil {
+		return host, port, nil
+	}
+	if !hostnamePattern.MatchString(host) {
+		return "", 0, fmt.Errorf("invalid entry %q: must be a hostname, IP address or CIDR range, optionally with a port", entry)
+	}
+	return host, port, nil
+}
@@ -1,12 +1,14 @@
 package conf
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"os/user"
 	"path/filepath"
 	"strings"
 
+	"github.com/ready-to-release/eac/src/core/reporoot"
 	"github.com/rs/zerolog/log"
 )
 
@@ -85,38 +87,20 @@ func getConfigFileCandidates(repoRoot string) []string {
 	return candidates
 }
 
-// FindRepositoryRoot searches up the directory tree from the current working directory
-// until it finds a .git folder or reaches the root of the filesystem
+// FindRepositoryRoot returns the repository root containing the current
+// working directory, see reporoot for worktrees, submodules and R2R_REPO_ROOT
 func FindRepositoryRoot() (string, error) {
-	// Get the current working directory
-	currentDir, err := os.Getwd()
-	if err != nil {
-		return "", err
-	}
-
-	startDir := currentDir // Keep track of starting directory for error message
-
-	// Loop until we reach the root of the filesystem
-	for {
-		// Check if the .git directory exists in the current directory
-		gitPath := filepath.Join(currentDir, ".git")
-		if _, err := os.Stat(gitPath); err == nil {
-			return currentDir, nil
-		}
-
-		// Move up one directory
-		parentDir := filepath.Dir(currentDir)
-		if parentDir == currentDir {
-			break // Reached the root of the filesystem
-		}
-		currentDir = parentDir
+	root, err := reporoot.Find("")
+	if errors.Is(err, reporoot.ErrNotFound) {
+		startDir, _ := os.Getwd()
+		return "", NewRepositoryNotFoundError(startDir)
 	}
-
-	return "", NewRepositoryNotFoundError(startDir)
+	return root, err
 }
 
-// InitConfig initializes the configuration by finding and loading the config file
-func InitConfig() {
+// InitConfig finds and loads the configuration, merges local overrides and
+// returns it. The result is also stored in Global for code not yet migrated.
+func InitConfig() *Config {
 	// CRITICAL: Block configuration access in test environment
 	if os.Getenv("R2R_TESTING") == "true" {
 		log.Fatal().Msg("CRITICAL: InitConfig() called in test environment. Tests must use isolated configurations.")
@@ -127,40 +111,23 @@ func InitConfig() {
 		log.Fatal().Msg("CRITICAL: Production configuration access blocked in test binary. Use test-specific configuration.")
 	}
 
-	// Load base configuration file
-	configFile, err := findConfigFile("r2r-cli.yml")
-	if err != nil {
-		log.Fatal().Err(err).Msg("Error finding config file. Please run 'r2r init' from the root of your project.")
-	}
-	err = LoadConfig(configFile)
+	// Load the base configuration file and merge local overrides
+	// Priority order (highest to lowest): r2r-cli.local.yml, r2r-cli.personal.yml, r2r-cli.dev.yml
+	cfg, err := Discover()
 	if err != nil {
+		if ce, ok := err.(*ConfigError); ok && (ce.Type == ErrorTypeConfigFileNotFound || ce.Type == ErrorTypeRepositoryNotFound) {
+			log.Fatal().Err(err).Msg("Error finding config file. Please run 'r2r init' from the root of your project.")
+		}
 		log.Fatal().Err(err).Msg("Error parsing config file")
 	}
+	setGlobal(cfg)
 
-	// Check for and merge local override configurations
-	// Priority order (highest to lowest): r2r-cli.local.yml, r2r-cli.personal.yml, r2r-cli.dev.yml
-	repoRoot, _ := FindRepositoryRoot()
-	if repoRoot != "" {
-		overrideFiles := []string{
-			"r2r-cli.local.yml",
-			"r2r-cli.personal.yml",
-			"r2r-cli.dev.yml",
-		}
-
-		for _, overrideFile := range overrideFiles {
-			overridePath := filepath.Join(repoRoot, overrideFile)
-			if _, err := os.Stat(overridePath); err == nil {
-				log.Debug().Str("override", overridePath).Msg("Loading configuration override")
-				if err := MergeConfigFile(overridePath); err != nil {
-					log.Warn().Err(err).Str("file", overridePath).Msg("Failed to load override configuration")
-				} else {
-					log.Info().Str("file", overrideFile).Msg("Applied configuration override")
-				}
-			}
-		}
-	}
+	// Policies see the configuration after all overrides are merged
+	enforcePolicies(cfg)
 
 	// Check for latest tags after all configs are merged
 	// This ensures we check extensions from override files too
-	checkLatestTags(&Global)
+	checkLatestTags(cfg)
+
+	return cfg
 }
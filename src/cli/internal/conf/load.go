@@ -0,0 +1,235 @@
+package conf
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/viper"
+)
+
+// OverrideFiles are merged over r2r-cli.yml when present in the repository
+// root, in this order (later files take precedence)
+var OverrideFiles = []string{
+	LocalOverrideFile,
+	"r2r-cli.personal.yml",
+	"r2r-cli.dev.yml",
+}
+
+// globalMu guards Global for the compatibility shims
+var globalMu sync.RWMutex
+
+// Load reads and validates a configuration file. Each call uses its own viper
+// instance and returns a new Config, so concurrent loads do not interfere.
+func Load(configFile string) (*Config, error) {
+	v := viper.New()
+	v.SetConfigFile(configFile)
+	if err := v.ReadInConfig(); err != nil {
+		return nil, WrapConfigError(err, configFile)
+	}
+
+	var cfg Config
+	if err := v.Unmarshal(&cfg); err != nil {
+		return nil, NewYAMLUnmarshalError(configFile, err)
+	}
+
+	if err := validateConfig(&cfg); err != nil {
+		return nil, NewValidationError(configFile, err)
+	}
+
+	setSource(&cfg, configFile)
+	cfg.Sources = []string{configFile}
+	return &cfg, nil
+}
+
+// MergeFile returns base with the override file merged over it. base is not
+// modified. The override may be partial; only the merged result is validated.
+func MergeFile(base *Config, configFile string) (*Config, error) {
+	v := viper.New()
+	v.SetConfigFile(configFile)
+	if err := v.ReadInConfig(); err != nil {
+		return nil, WrapConfigError(err, configFile)
+	}
+
+	var override Config
+	if err := v.Unmarshal(&override); err != nil {
+		return nil, NewYAMLUnmarshalError(configFile, err)
+	}
+
+	log.Debug().Str("file", configFile).Msg("Merging override configuration (validation skipped for partial config)")
+
+	setSource(&override, configFile)
+	merged := base.Clone()
+	mergeConfigs(merged, &override)
+	merged.Sources = append(merged.Sources, configFile)
+
+	if err := validateConfig(merged); err != nil {
+		// Don't attribute the error to the override file - it's the merged config that failed
+		log.Error().Str("file", configFile).Err(err).Msg("Merged configuration validation failed")
+		return nil, fmt.Errorf("merged configuration is invalid after applying %s: %w", configFile, err)
+	}
+
+	return merged, nil
+}
+
+// Discover finds r2r-cli.yml from the current directory and loads it. It
+// then merges, lowest precedence first, the nested r2r-cli.yml files between
+// the repository root and the directory r2r was started in (outermost
+// first), and the override files found in the repository root.
+func Discover() (*Config, error) {
+	configFile, err := findConfigFile("r2r-cli.yml")
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := Load(configFile)
+	if err != nil {
+		return nil, err
+	}
+
+	repoRoot, _ := FindRepositoryRoot()
+	if repoRoot == "" {
+		return cfg, nil
+	}
+
+	for _, nestedPath := range NestedConfigFiles(repoRoot, StartDir()) {
+		log.Debug().Str("nested", nestedPath).Msg("Loading nested configuration")
+		merged, err := MergeFile(cfg, nestedPath)
+		if err != nil {
+			log.Warn().Err(err).Str("file", nestedPath).Msg("Failed to load nested configuration")
+			continue
+		}
+		cfg = merged
+		log.Info().Str("file", nestedPath).Msg("Applied nested configuration")
+	}
+
+	for _, overrideFile := range OverrideFiles {
+		overridePath := filepath.Join(repoRoot, overrideFile)
+		if _, err := os.Stat(overridePath); err != nil {
+			continue
+		}
+		log.Debug().Str("override", overridePath).Msg("Loading configuration override")
+		merged, err := MergeFile(cfg, overridePath)
+		if err != nil {
+			log.Warn().Err(err).Str("file", overridePath).Msg("Failed to load override configuration")
+			continue
+		}
+		cfg = merged
+		log.Info().Str("file", overrideFile).Msg("Applied configuration override")
+	}
+
+	return cfg, nil
+}
+
+// OriginalPwdEnv names the environment variable a wrapper sets to the
+// directory r2r was started in, when it changes directory before running r2r
+const OriginalPwdEnv = "CLI_ORIGINAL_PWD"
+
+// StartDir returns the directory r2r was started in: CLI_ORIGINAL_PWD when
+// set, else the current directory
+func StartDir() string {
+	if dir := os.Getenv(OriginalPwdEnv); dir != "" {
+		return dir
+	}
+	dir, _ := os.Getwd()
+	return dir
+}
+
+// NestedConfigFiles returns the r2r-cli.yml files in the directories from
+// start up to, but not including, repoRoot, outermost first. It returns none
+// when start is outside repoRoot.
+func NestedConfigFiles(repoRoot, start string) []string {
+	root, dir := realPath(repoRoot), realPath(start)
+	rel, err := filepath.Rel(root, dir)
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return nil
+	}
+
+	var files []string
+	for ; dir != root && dir != filepath.Dir(dir); dir = filepath.Dir(dir) {
+		path := filepath.Join(dir, "r2r-cli.yml")
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			files = append(files, path)
+		}
+	}
+	slices.Reverse(files)
+	return files
+}
+
+// realPath returns the absolute path of path with symlinks resolved, so
+// paths from the environment and from os.Getwd compare equal
+func realPath(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolved
+	}
+	return path
+}
+
+// setSource records file as the source of the extensions of cfg
+func setSource(cfg *Config, file string) {
+	for i := range cfg.Extensions {
+		cfg.Extensions[i].Source = file
+	}
+}
+
+// Clone returns a deep copy of the configuration
+func (c *Config) Clone() *Config {
+	if c == nil {
+		return &Config{}
+	}
+	data, err := json.Marshal(c)
+	if err != nil {
+		panic(fmt.Sprintf("failed to copy configuration: %v", err))
+	}
+	var clone Config
+	if err := json.Unmarshal(data, &clone); err != nil {
+		panic(fmt.Sprintf("failed to copy configuration: %v", err))
+	}
+	return &clone
+}
+
+// FindExtension returns the extension with the given name
+func (c *Config) FindExtension(name string) (*Extension, bool) {
+	for i := range c.Extensions {
+		if c.Extensions[i].Name == name {
+			return &c.Extensions[i], true
+		}
+	}
+	return nil, false
+}
+
+// Current returns a copy of Global, for code not yet given a Config explicitly
+func Current() *Config {
+	globalMu.RLock()
+	defer globalMu.RUnlock()
+	return Global.Clone()
+}
+
+func setGlobal(cfg *Config) {
+	globalMu.Lock()
+	defer globalMu.Unlock()
+	Global = *cfg
+}
+
+type contextKey struct{}
+
+// WithConfig returns a context carrying the configuration
+func WithConfig(ctx context.Context, cfg *Config) context.Context {
+	return context.WithValue(ctx, contextKey{}, cfg)
+}
+
+// FromContext returns the configuration carried by ctx, falling back to Current
+func FromContext(ctx context.Context) *Config {
+	if cfg, ok := ctx.Value(contextKey{}).(*Config); ok && cfg != nil {
+		return cfg
+	}
+	return Current()
+}
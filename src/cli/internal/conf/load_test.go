@@ -0,0 +1,132 @@
+//go:build L1
+// +build L1
+
+package conf
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeConfig(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	return path
+}
+
+// TestMergeFileLeavesBaseUnchanged verifies MergeFile returns a new Config
+func TestMergeFileLeavesBaseUnchanged(t *testing.T) {
+	t.Setenv("R2R_TESTING", "true")
+	tempDir := t.TempDir()
+
+	base, err := Load(writeConfig(t, tempDir, "r2r-cli.yml", `extensions:
+  - name: "pwsh"
+    image: "ghcr.io/ready-to-release/r2r-cli/extensions/pwsh:v1.0.0"
+`))
+	require.NoError(t, err)
+
+	merged, err := MergeFile(base, writeConfig(t, tempDir, "r2r-cli.local.yml", `extensions:
+  - name: "pwsh"
+    load_local: true
+  - name: "go"
+    image: "golang:1.25"
+`))
+	require.NoError(t, err)
+
+	assert.Len(t, base.Extensions, 1)
+	assert.False(t, base.Extensions[0].LoadLocal)
+	assert.Len(t, merged.Extensions, 2)
+	assert.True(t, merged.Extensions[0].LoadLocal)
+}
+
+// TestLoadConcurrent verifies independent loads do not share state
+func TestLoadConcurrent(t *testing.T) {
+	t.Setenv("R2R_TESTING", "true")
+	tempDir := t.TempDir()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		path := writeConfig(t, tempDir, fmt.Sprintf("r2r-cli-%d.yml", i), fmt.Sprintf(`extensions:
+  - name: "ext-%d"
+    image: "image:%d.0.0"
+`, i, i))
+
+		wg.Add(1)
+		go func(i int, path string) {
+			defer wg.Done()
+			cfg, err := Load(path)
+			if assert.NoError(t, err) && assert.Len(t, cfg.Extensions, 1) {
+				assert.Equal(t, fmt.Sprintf("ext-%d", i), cfg.Extensions[0].Name)
+			}
+		}(i, path)
+	}
+	wg.Wait()
+}
+
+// TestConfigFromContext verifies configuration passed through a context
+func TestConfigFromContext(t *testing.T) {
+	cfg := &Config{Extensions: []Extension{{Name: "pwsh", Image: "pwsh:1.0.0"}}}
+	ctx := WithConfig(context.Background(), cfg)
+
+	assert.Same(t, cfg, FromContext(ctx))
+
+	ext, ok := FromContext(ctx).FindExtension("pwsh")
+	require.True(t, ok)
+	assert.Equal(t, "pwsh:1.0.0", ext.Image)
+}
+
+// TestNestedConfigFiles verifies the nested files are found outermost first
+func TestNestedConfigFiles(t *testing.T) {
+	root := t.TempDir()
+	api := filepath.Join(root, "services", "api")
+	require.NoError(t, os.MkdirAll(filepath.Join(api, "src"), 0755))
+	writeConfig(t, root, "r2r-cli.yml", "extensions: []\n")
+	services := writeConfig(t, filepath.Join(root, "services"), "r2r-cli.yml", "extensions: []\n")
+	apiConfig := writeConfig(t, api, "r2r-cli.yml", "extensions: []\n")
+
+	realServices, _ := filepath.EvalSymlinks(services)
+	realAPI, _ := filepath.EvalSymlinks(apiConfig)
+	assert.Equal(t, []string{realServices, realAPI}, NestedConfigFiles(root, filepath.Join(api, "src")))
+	assert.Empty(t, NestedConfigFiles(root, root), "the root file is not nested")
+	assert.Empty(t, NestedConfigFiles(api, root), "directories outside the root are ignored")
+}
+
+// TestMergeFileProvenance verifies the sources of a merged configuration
+func TestMergeFileProvenance(t *testing.T) {
+	t.Setenv("R2R_TESTING", "true")
+	tempDir := t.TempDir()
+
+	rootFile := writeConfig(t, tempDir, "r2r-cli.yml", `extensions:
+  - name: "go"
+    image: "golang:1.24"
+  - name: "pwsh"
+    image: "ghcr.io/ready-to-release/r2r-cli/extensions/pwsh:v1.0.0"
+`)
+	base, err := Load(rootFile)
+	require.NoError(t, err)
+
+	nestedFile := writeConfig(t, t.TempDir(), "r2r-cli.yml", `extensions:
+  - name: "go"
+    image: "golang:1.25"
+  - name: "api"
+    image: "alpine:3.20"
+`)
+	merged, err := MergeFile(base, nestedFile)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{rootFile}, base.Sources)
+	assert.Equal(t, []string{rootFile, nestedFile}, merged.Sources)
+	sources := map[string]string{}
+	for _, ext := range merged.Extensions {
+		sources[ext.Name] = ext.Source
+	}
+	assert.Equal(t, map[string]string{"go": nestedFile, "pwsh": rootFile, "api": nestedFile}, sources)
+}
@@ -308,6 +308,28 @@ func TestMergeConfigsDefaults(t *testing.T) {
 	assert.Equal(t, "1.0", base.Defaults.CPULimit, "CPULimit should remain from base")
 }
 
+// TestMergeConfigsTimeouts tests merging per-operation timeouts
+func TestMergeConfigsTimeouts(t *testing.T) {
+	base := &Config{
+		Defaults: &Defaults{
+			Timeouts: &Timeouts{Pull: 600, Stop: 10},
+		},
+	}
+	override := &Config{
+		Defaults: &Defaults{
+			Timeouts: &Timeouts{Stop: 30, Metadata: 120},
+		},
+	}
+
+	mergeConfigs(base, override)
+
+	require.NotNil(t, base.Defaults.Timeouts)
+	assert.Equal(t, 600, base.Defaults.Timeouts.Pull, "Pull should remain from base")
+	assert.Equal(t, 30, base.Defaults.Timeouts.Stop, "Stop should be updated")
+	assert.Equal(t, 120, base.Defaults.Timeouts.Metadata, "Metadata should be added")
+	assert.Equal(t, 0, base.Defaults.Timeouts.Create, "Create should stay unset")
+}
+
 // TestMergeConfigsEnvironment tests merging environment settings
 func TestMergeConfigsEnvironment(t *testing.T) {
 	// Create base config with environment
@@ -0,0 +1,95 @@
+package conf
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// LocalOverrideFile is the highest priority override file, kept out of
+// version control
+const LocalOverrideFile = "r2r-cli.local.yml"
+
+// EnableLoadLocal sets load_local for an extension in the given override
+// file, creating the file or the extension entry when needed. The rest of
+// the file, including comments, is preserved.
+func EnableLoadLocal(overrideFile, extension string) error {
+	doc := &yaml.Node{Kind: yaml.DocumentNode}
+
+	data, err := os.ReadFile(overrideFile)
+	if err != nil && !os.IsNotExist(err) {
+		return NewConfigFilePermissionError(overrideFile, err)
+	}
+	if len(bytes.TrimSpace(data)) > 0 {
+		if err := yaml.Unmarshal(data, doc); err != nil {
+			return NewYAMLParseError(overrideFile, err)
+		}
+	}
+	if len(doc.Content) == 0 {
+		doc.Content = []*yaml.Node{{Kind: yaml.MappingNode}}
+	}
+
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return fmt.Errorf("%s: expected a mapping at the top level", overrideFile)
+	}
+
+	extensions := mappingValue(root, "extensions")
+	if extensions == nil {
+		extensions = &yaml.Node{Kind: yaml.SequenceNode}
+		root.Content = append(root.Content, scalarNode("extensions"), extensions)
+	}
+	if extensions.Kind != yaml.SequenceNode {
+		return fmt.Errorf("%s: extensions must be a list", overrideFile)
+	}
+
+	var entry *yaml.Node
+	for _, item := range extensions.Content {
+		if name := mappingValue(item, "name"); name != nil && name.Value == extension {
+			entry = item
+			break
+		}
+	}
+	if entry == nil {
+		entry = &yaml.Node{Kind: yaml.MappingNode, Content: []*yaml.Node{scalarNode("name"), scalarNode(extension)}}
+		extensions.Content = append(extensions.Content, entry)
+	}
+
+	loadLocal := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: "true"}
+	if existing := mappingValue(entry, "load_local"); existing != nil {
+		*existing = *loadLocal
+	} else {
+		entry.Content = append(entry.Content, scalarNode("load_local"), loadLocal)
+	}
+
+	var out bytes.Buffer
+	encoder := yaml.NewEncoder(&out)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(doc); err != nil {
+		return fmt.Errorf("failed to encode %s: %w", overrideFile, err)
+	}
+	if err := encoder.Close(); err != nil {
+		return fmt.Errorf("failed to encode %s: %w", overrideFile, err)
+	}
+
+	return os.WriteFile(overrideFile, out.Bytes(), 0644)
+}
+
+// mappingValue returns the value node of key in a mapping node, or nil
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+func scalarNode(value string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+}
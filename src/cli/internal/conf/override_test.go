@@ -0,0 +1,56 @@
+//go:build L1
+// +build L1
+
+package conf
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestEnableLoadLocalCreatesFile tests creating the override file from scratch
+func TestEnableLoadLocalCreatesFile(t *testing.T) {
+	overridePath := filepath.Join(t.TempDir(), LocalOverrideFile)
+
+	require.NoError(t, EnableLoadLocal(overridePath, "pwsh"))
+
+	data, err := os.ReadFile(overridePath)
+	require.NoError(t, err)
+	assert.Equal(t, "extensions:\n  - name: pwsh\n    load_local: true\n", string(data))
+}
+
+// TestEnableLoadLocalUpdatesExistingEntry tests that other content is kept
+func TestEnableLoadLocalUpdatesExistingEntry(t *testing.T) {
+	overridePath := filepath.Join(t.TempDir(), LocalOverrideFile)
+	existing := `# My overrides
+extensions:
+  - name: python
+    load_local: true
+  - name: pwsh
+    load_local: false
+    env:
+      - name: DEBUG
+        value: "1"
+`
+	require.NoError(t, os.WriteFile(overridePath, []byte(existing), 0644))
+
+	require.NoError(t, EnableLoadLocal(overridePath, "pwsh"))
+
+	merged, err := MergeFile(&Config{Extensions: []Extension{
+		{Name: "python", Image: "python:latest"},
+		{Name: "pwsh", Image: "pwsh:latest"},
+	}}, overridePath)
+	require.NoError(t, err)
+	require.Len(t, merged.Extensions, 2)
+	assert.True(t, merged.Extensions[1].LoadLocal)
+	require.Len(t, merged.Extensions[1].Env, 1)
+	assert.Equal(t, "DEBUG", merged.Extensions[1].Env[0].Name)
+
+	data, err := os.ReadFile(overridePath)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "# My overrides")
+}
@@ -0,0 +1,56 @@
+package conf
+
+import (
+	"github.com/ready-to-release/eac/src/cli/internal/policy"
+	"github.com/rs/zerolog/log"
+)
+
+// PolicyExtension converts an extension to the input of policy rules
+func PolicyExtension(ext Extension) policy.Extension {
+	in := policy.Extension{
+		Name:        ext.Name,
+		Image:       ext.Image,
+		Privileged:  ext.Privileged,
+		NetworkMode: ext.NetworkMode,
+		CPULimit:    ext.CPULimit,
+		MemoryLimit: ext.MemoryLimit,
+	}
+	for _, v := range ext.Volumes {
+		in.Volumes = append(in.Volumes, policy.Volume{Host: v.Host, Container: v.Container, Readonly: v.Readonly})
+	}
+	for _, env := range ext.Env {
+		in.Env = append(in.Env, env.Name)
+	}
+	return in
+}
+
+// CheckPolicies evaluates the policies of the repository against every
+// extension of cfg. Violations of policies in warn mode are logged.
+func CheckPolicies(cfg *Config, policies *policy.Set) error {
+	for _, ext := range cfg.Extensions {
+		in := PolicyExtension(ext)
+		in.RepoAccess = cfg.EffectiveRepoAccess(ext)
+		if runAs := cfg.EffectiveRunAs(ext); runAs != RunAsImage {
+			in.User = runAs
+		}
+		err := policies.Enforce(policy.PhaseConfig, in, func(v policy.Violation) {
+			log.Warn().Str("policy", v.Policy).Msg(v.String())
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// enforcePolicies checks the loaded configuration against the policies of
+// the repository and exits on a violation in deny mode
+func enforcePolicies(cfg *Config) {
+	policies, err := policy.Default()
+	if err != nil {
+		log.Fatal().Err(err).Msg("Error loading policies")
+	}
+	if err := CheckPolicies(cfg, policies); err != nil {
+		log.Fatal().Err(err).Msg("Configuration violates the policies of the repository")
+	}
+}
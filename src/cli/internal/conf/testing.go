@@ -100,17 +100,12 @@ func LoadTestConfig(t *testing.T, configPath string) (*Config, error) {
 	// Ensure test environment
 	t.Setenv("R2R_TESTING", "true")
 
-	// Create a new config instance to avoid global state
-	config := &Config{}
-
-	// Use a copy of LoadConfig logic that doesn't affect global state
-	// This would need to be implemented to avoid using the global Config variable
-	// For now, we'll return the error to indicate this needs implementation
-	return config, nil
+	// Load returns a new Config and leaves global state untouched
+	return Load(configPath)
 }
 
 // ResetGlobalConfig resets the global config for test isolation
 // This should be called in test cleanup to ensure no state leakage
 func ResetGlobalConfig() {
-	Global = Config{}
+	setGlobal(&Config{})
 }
@@ -0,0 +1,100 @@
+package conf
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"sync"
+)
+
+// maxCachedValidations bounds the validation cache. A process validates a
+// handful of configurations (the base file and its merged overrides), so the
+// cache is simply cleared when it fills up.
+const maxCachedValidations = 32
+
+// validationResults caches validateConfig results by configuration content
+var validationResults = &validationCache{results: make(map[[sha256.Size]byte][]string)}
+
+// validationCache maps a configuration fingerprint to its validation errors.
+// A valid configuration maps to an empty slice.
+type validationCache struct {
+	mu      sync.Mutex
+	results map[[sha256.Size]byte][]string
+}
+
+func (c *validationCache) get(key [sha256.Size]byte) (error, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	errs, ok := c.results[key]
+	if !ok {
+		return nil, false
+	}
+	if len(errs) == 0 {
+		return nil, true
+	}
+	// Callers get their own copy so they cannot alter the cached result
+	return &ValidationError{Errors: append([]string(nil), errs...)}, true
+}
+
+func (c *validationCache) put(key [sha256.Size]byte, err error) {
+	var errs []string
+	if err != nil {
+		ve, ok := err.(*ValidationError)
+		if !ok {
+			return
+		}
+		errs = append([]string(nil), ve.Errors...)
+	}
+	if errs == nil {
+		errs = []string{}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.results) >= maxCachedValidations {
+		c.results = make(map[[sha256.Size]byte][]string)
+	}
+	c.results[key] = errs
+}
+
+func (c *validationCache) reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.results = make(map[[sha256.Size]byte][]string)
+}
+
+// withoutRuntimeFlags returns a shallow copy of cfg with the runtime flags
+// cleared. Runtime flags (load_local, globally and per extension) are set
+// from the command line and overrides and do not take part in validation.
+func withoutRuntimeFlags(cfg *Config) *Config {
+	stripped := *cfg
+	stripped.LoadLocal = false
+	stripped.Extensions = make([]Extension, len(cfg.Extensions))
+	for i, ext := range cfg.Extensions {
+		ext.LoadLocal = false
+		stripped.Extensions[i] = ext
+	}
+	return &stripped
+}
+
+// validationKey fingerprints the parts of cfg that validation depends on
+func validationKey(cfg *Config) ([sha256.Size]byte, bool) {
+	data, err := json.Marshal(withoutRuntimeFlags(cfg))
+	if err != nil {
+		return [sha256.Size]byte{}, false
+	}
+	return sha256.Sum256(data), true
+}
+
+// Revalidate validates c, a configuration derived from prev, which already
+// passed validation. When the two differ only in runtime flags such as
+// load_local the checks are skipped.
+func (c *Config) Revalidate(prev *Config) error {
+	if prev != nil {
+		prevKey, okPrev := validationKey(prev)
+		key, ok := validationKey(c)
+		if okPrev && ok && prevKey == key {
+			return nil
+		}
+	}
+	return validateConfig(c)
+}
@@ -0,0 +1,63 @@
+//go:build L1
+// +build L1
+
+package conf
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateConfigCachesResults(t *testing.T) {
+	validationResults.reset()
+	invalid := &Config{Extensions: []Extension{{Name: "bad", Image: "x:1", Env: []EnvVar{{Name: "lower"}}}}}
+
+	first := validateConfig(invalid)
+	require.Error(t, first)
+	second := validateConfig(invalid)
+	require.Error(t, second)
+	assert.Equal(t, first.Error(), second.Error())
+
+	// Callers get their own copy of the cached errors
+	second.(*ValidationError).Add("mutated")
+	third := validateConfig(invalid)
+	assert.Len(t, third.(*ValidationError).Errors, 1)
+
+	// Content changes are not served from the cache
+	invalid.Extensions[0].Env[0].Name = "UPPER"
+	assert.NoError(t, validateConfig(invalid))
+}
+
+func TestValidationKeyIgnoresRuntimeFlags(t *testing.T) {
+	cfg := &Config{Extensions: []Extension{{Name: "go", Image: "go:1"}}}
+	key, ok := validationKey(cfg)
+	require.True(t, ok)
+
+	flagged := cfg.Clone()
+	flagged.LoadLocal = true
+	flagged.Extensions[0].LoadLocal = true
+	flaggedKey, ok := validationKey(flagged)
+	require.True(t, ok)
+	assert.Equal(t, key, flaggedKey)
+	assert.False(t, cfg.Extensions[0].LoadLocal, "fingerprinting does not modify the configuration")
+
+	changed := cfg.Clone()
+	changed.Extensions[0].Image = "go:2"
+	changedKey, _ := validationKey(changed)
+	assert.NotEqual(t, key, changedKey)
+}
+
+func TestRevalidate(t *testing.T) {
+	prev := &Config{Extensions: []Extension{{Name: "go", Image: "go:1"}}}
+
+	next := prev.Clone()
+	next.Extensions[0].LoadLocal = true
+	assert.NoError(t, next.Revalidate(prev))
+
+	broken := prev.Clone()
+	broken.Extensions[0].Image = ""
+	assert.Error(t, broken.Revalidate(prev))
+	assert.Error(t, broken.Revalidate(nil))
+}
@@ -0,0 +1,550 @@
+// Package configlint checks r2r-cli.yml files against the schema, the semantic
+// rules applied when loading, and style rules, and rewrites them in canonical
+// form while keeping comments.
+package configlint
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ready-to-release/eac/src/cli/internal/conf"
+	"github.com/ready-to-release/eac/src/cli/internal/validator"
+	"gopkg.in/yaml.v3"
+)
+
+// Finding sources
+const (
+	SourceSchema   = "schema"
+	SourceSemantic = "semantic"
+	SourceStyle    = "style"
+)
+
+// Style rule identifiers
+const (
+	RuleKeyOrder     = "key-order"
+	RuleDuplicateEnv = "duplicate-env"
+	RuleRedundantEnv = "redundant-env"
+	RuleFormat       = "format"
+)
+
+// Finding is a single lint result
+type Finding struct {
+	Source     string `json:"source"`
+	Rule       string `json:"rule"`
+	Severity   string `json:"severity"` // "error" or "warning"
+	Field      string `json:"field,omitempty"`
+	Line       int    `json:"line,omitempty"`
+	Column     int    `json:"column,omitempty"`
+	Message    string `json:"message"`
+	Suggestion string `json:"suggestion,omitempty"`
+	Fixable    bool   `json:"fixable"`
+}
+
+// Report is the result of linting one file
+type Report struct {
+	File     string    `json:"file"`
+	Findings []Finding `json:"findings"`
+	Fixed    int       `json:"fixed"`
+}
+
+// HasErrors reports whether any finding has error severity
+func (r *Report) HasErrors() bool {
+	for _, f := range r.Findings {
+		if f.Severity == "error" {
+			return true
+		}
+	}
+	return false
+}
+
+// Fixable returns the number of findings --fix can resolve
+func (r *Report) Fixable() int {
+	count := 0
+	for _, f := range r.Findings {
+		if f.Fixable {
+			count++
+		}
+	}
+	return count
+}
+
+// Lint runs schema, semantic and style checks on configuration content
+func Lint(data []byte, file string) (*Report, error) {
+	report := &Report{File: file, Findings: []Finding{}}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", file, err)
+	}
+
+	v, err := validator.NewEmbeddedValidator()
+	if err != nil {
+		return nil, err
+	}
+	result, err := v.ValidateYAML(data, file)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range result.Errors {
+		report.Findings = append(report.Findings, fromValidationError(e, "error"))
+	}
+	for _, w := range result.Warnings {
+		report.Findings = append(report.Findings, fromValidationError(w, "warning"))
+	}
+
+	if cfg, err := conf.ParseConfig(data); err == nil {
+		if err := cfg.Validate(); err != nil {
+			if ve, ok := err.(*conf.ValidationError); ok {
+				for _, message := range ve.Errors {
+					report.Findings = append(report.Findings, Finding{
+						Source:   SourceSemantic,
+						Rule:     "config",
+						Severity: "error",
+						Message:  message,
+					})
+				}
+			}
+		}
+	}
+
+	if len(doc.Content) == 0 {
+		return report, nil
+	}
+	report.Findings = append(report.Findings, styleFindings(doc.Content[0])...)
+
+	canonical, err := encode(&doc)
+	if err != nil {
+		return nil, err
+	}
+	if !sameIgnoringBlankLines(data, canonical) {
+		report.Findings = append(report.Findings, Finding{
+			Source:     SourceStyle,
+			Rule:       RuleFormat,
+			Severity:   "warning",
+			Line:       firstDifference(data, canonical),
+			Message:    "file is not in canonical format (2-space indentation, block style)",
+			Suggestion: "run with --fix",
+			Fixable:    true,
+		})
+	}
+
+	return report, nil
+}
+
+// Fix applies the fixable style rules and returns the canonical YAML.
+// Comments attached to nodes are kept; blank lines are not.
+func Fix(data []byte) ([]byte, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	if len(doc.Content) == 0 {
+		return data, nil
+	}
+
+	root := doc.Content[0]
+	walk(root, func(node *yaml.Node, order []string, _ string) {
+		sortKeys(node, order)
+	})
+	for _, list := range envLists(root) {
+		list.node.Content = dedupeEnv(list.node.Content)
+	}
+	removeRedundantEnv(root)
+
+	return encode(&doc)
+}
+
+func fromValidationError(e validator.ValidationError, severity string) Finding {
+	return Finding{
+		Source:     SourceSchema,
+		Rule:       e.Rule,
+		Severity:   severity,
+		Field:      e.Field,
+		Line:       e.Line,
+		Column:     e.Column,
+		Message:    e.Message,
+		Suggestion: e.Suggestion,
+	}
+}
+
+// Canonical key orders, following the field order of conf.Config
+var (
+	rootOrder      = []string{"version", "registry", "defaults", "environment", "extensions", "hooks", "notifications", "load_local", validator.ValidationConfigKey}
+	registryOrder  = []string{"default", "authentication", "timeout", "retry_attempts", "ghcr_cache_seconds"}
+	authOrder      = []string{"required", "username_env", "token_env"}
+	defaultsOrder  = []string{"registry", "pull_policy", "remove_after", "timeout", "timeouts", "memory_limit", "cpu_limit", "repo_access", "run_as", "environment"}
+	timeoutsOrder  = []string{"pull", "create", "start", "stop", "metadata"}
+	envGroupsOrder = []string{"global", "secrets"}
+	extensionOrder = []string{
+		"name", "description", "version", "image", "image_pull_policy", "load_local", "build", "test", "auto_remove_children",
+		"repo_url", "docs_url", "env", "volumes", "ports", "working_dir", "entrypoint", "command",
+		"privileged", "network_mode", "metadata_schema_version", "memory_limit", "cpu_limit", "egress",
+		"repo_access", "mask_paths", "run_as", "fix_ownership", "userns_mode", "hooks",
+	}
+	buildOrder  = []string{"context", "dockerfile", "args"}
+	testOrder   = []string{"command", "junit"}
+	envOrder    = []string{"name", "value"}
+	secretOrder = []string{"name", "env"}
+	volumeOrder = []string{"host", "container", "readonly"}
+	portOrder   = []string{"host", "container"}
+	egressOrder = []string{"allow", "helper_image"}
+	hooksOrder  = []string{"pre_run", "post_run", "on_failure"}
+	hookOrder   = []string{"command", "extension", "args"}
+
+	notificationsOrder = []string{"threshold", "desktop", "webhook", "slack_webhook"}
+)
+
+// walk calls fn for every mapping with a canonical key order
+func walk(root *yaml.Node, fn func(node *yaml.Node, order []string, field string)) {
+	visit := func(node *yaml.Node, order []string, field string) {
+		if node != nil && node.Kind == yaml.MappingNode {
+			fn(node, order, field)
+		}
+	}
+	each := func(node *yaml.Node, order []string, field string) {
+		if node == nil || node.Kind != yaml.SequenceNode {
+			return
+		}
+		for i, item := range node.Content {
+			visit(item, order, fmt.Sprintf("%s[%d]", field, i))
+		}
+	}
+
+	eachHook := func(hooks *yaml.Node, field string) {
+		visit(hooks, hooksOrder, field)
+		for _, event := range hooksOrder {
+			each(value(hooks, event), hookOrder, field+"."+event)
+		}
+	}
+
+	visit(root, rootOrder, "")
+	eachHook(value(root, "hooks"), "hooks")
+	visit(value(root, "notifications"), notificationsOrder, "notifications")
+
+	registry := value(root, "registry")
+	visit(registry, registryOrder, "registry")
+	visit(value(registry, "authentication"), authOrder, "registry.authentication")
+
+	defaults := value(root, "defaults")
+	visit(defaults, defaultsOrder, "defaults")
+	visit(value(defaults, "timeouts"), timeoutsOrder, "defaults.timeouts")
+	each(value(defaults, "environment"), envOrder, "defaults.environment")
+
+	environment := value(root, "environment")
+	visit(environment, envGroupsOrder, "environment")
+	each(value(environment, "global"), envOrder, "environment.global")
+	each(value(environment, "secrets"), secretOrder, "environment.secrets")
+
+	if extensions := value(root, "extensions"); extensions != nil && extensions.Kind == yaml.SequenceNode {
+		for i, ext := range extensions.Content {
+			field := fmt.Sprintf("extensions[%d]", i)
+			visit(ext, extensionOrder, field)
+			visit(value(ext, "build"), buildOrder, field+".build")
+			visit(value(ext, "test"), testOrder, field+".test")
+			each(value(ext, "env"), envOrder, field+".env")
+			each(value(ext, "volumes"), volumeOrder, field+".volumes")
+			each(value(ext, "ports"), portOrder, field+".ports")
+			visit(value(ext, "egress"), egressOrder, field+".egress")
+			eachHook(value(ext, "hooks"), field+".hooks")
+		}
+	}
+}
+
+func styleFindings(root *yaml.Node) []Finding {
+	var findings []Finding
+
+	walk(root, func(node *yaml.Node, order []string, field string) {
+		keys := keysOf(node)
+		sorted := append([]string(nil), keys...)
+		sort.SliceStable(sorted, func(i, j int) bool { return rank(order, sorted[i]) < rank(order, sorted[j]) })
+		for i := range keys {
+			if keys[i] != sorted[i] {
+				location := field
+				if location == "" {
+					location = "top level"
+				}
+				findings = append(findings, Finding{
+					Source:     SourceStyle,
+					Rule:       RuleKeyOrder,
+					Severity:   "warning",
+					Field:      field,
+					Line:       node.Content[2*i].Line,
+					Column:     node.Content[2*i].Column,
+					Message:    fmt.Sprintf("keys of %s are not in canonical order, expected: %s", location, strings.Join(sorted, ", ")),
+					Suggestion: "run with --fix",
+					Fixable:    true,
+				})
+				break
+			}
+		}
+	})
+
+	for _, list := range envLists(root) {
+		seen := make(map[string]*yaml.Node)
+		for i, item := range list.node.Content {
+			name := scalar(value(item, "name"))
+			if name == "" {
+				continue
+			}
+			if first, ok := seen[name]; ok {
+				identical := sameEntry(first, item)
+				finding := Finding{
+					Source:   SourceStyle,
+					Rule:     RuleDuplicateEnv,
+					Severity: "warning",
+					Field:    fmt.Sprintf("%s[%d]", list.field, i),
+					Line:     item.Line,
+					Column:   item.Column,
+					Message:  fmt.Sprintf("%s is already defined on line %d", name, first.Line),
+					Fixable:  identical,
+				}
+				if identical {
+					finding.Suggestion = "run with --fix to remove the repeated entry"
+				} else {
+					finding.Suggestion = "keep one definition; the values differ"
+				}
+				findings = append(findings, finding)
+				continue
+			}
+			seen[name] = item
+		}
+	}
+
+	for _, redundant := range redundantEnv(root) {
+		findings = append(findings, Finding{
+			Source:     SourceStyle,
+			Rule:       RuleRedundantEnv,
+			Severity:   "warning",
+			Field:      redundant.field,
+			Line:       redundant.node.Line,
+			Column:     redundant.node.Column,
+			Message:    fmt.Sprintf("%s repeats environment.global with the same value", scalar(value(redundant.node, "name"))),
+			Suggestion: "run with --fix to remove it from the extension",
+			Fixable:    true,
+		})
+	}
+
+	return findings
+}
+
+type fieldNode struct {
+	field string
+	node  *yaml.Node
+}
+
+// envLists returns every name/value (or name/env) list in the document
+func envLists(root *yaml.Node) []fieldNode {
+	var lists []fieldNode
+	add := func(node *yaml.Node, field string) {
+		if node != nil && node.Kind == yaml.SequenceNode {
+			lists = append(lists, fieldNode{field: field, node: node})
+		}
+	}
+	add(value(value(root, "defaults"), "environment"), "defaults.environment")
+	add(value(value(root, "environment"), "global"), "environment.global")
+	add(value(value(root, "environment"), "secrets"), "environment.secrets")
+	if extensions := value(root, "extensions"); extensions != nil && extensions.Kind == yaml.SequenceNode {
+		for i, ext := range extensions.Content {
+			add(value(ext, "env"), fmt.Sprintf("extensions[%d].env", i))
+		}
+	}
+	return lists
+}
+
+// redundantEnv returns extension env entries identical to an environment.global entry
+func redundantEnv(root *yaml.Node) []fieldNode {
+	global := make(map[string]*yaml.Node)
+	if list := value(value(root, "environment"), "global"); list != nil {
+		for _, item := range list.Content {
+			global[scalar(value(item, "name"))] = item
+		}
+	}
+
+	var redundant []fieldNode
+	if extensions := value(root, "extensions"); extensions != nil && extensions.Kind == yaml.SequenceNode {
+		for i, ext := range extensions.Content {
+			env := value(ext, "env")
+			if env == nil {
+				continue
+			}
+			for j, item := range env.Content {
+				if g, ok := global[scalar(value(item, "name"))]; ok && sameEntry(g, item) {
+					redundant = append(redundant, fieldNode{field: fmt.Sprintf("extensions[%d].env[%d]", i, j), node: item})
+				}
+			}
+		}
+	}
+	return redundant
+}
+
+func removeRedundantEnv(root *yaml.Node) {
+	remove := make(map[*yaml.Node]bool)
+	for _, redundant := range redundantEnv(root) {
+		remove[redundant.node] = true
+	}
+	if len(remove) == 0 {
+		return
+	}
+	for _, ext := range value(root, "extensions").Content {
+		env := value(ext, "env")
+		if env == nil {
+			continue
+		}
+		kept := env.Content[:0]
+		for _, item := range env.Content {
+			if !remove[item] {
+				kept = append(kept, item)
+			}
+		}
+		env.Content = kept
+		if len(kept) == 0 {
+			deleteKey(ext, "env")
+		}
+	}
+}
+
+// dedupeEnv drops repeated entries whose name and value are identical
+func dedupeEnv(items []*yaml.Node) []*yaml.Node {
+	var kept []*yaml.Node
+	seen := make(map[string]*yaml.Node)
+	for _, item := range items {
+		name := scalar(value(item, "name"))
+		if first, ok := seen[name]; ok && name != "" && sameEntry(first, item) {
+			continue
+		}
+		if _, ok := seen[name]; !ok {
+			seen[name] = item
+		}
+		kept = append(kept, item)
+	}
+	return kept
+}
+
+// sameEntry compares two flat mappings by key and scalar value
+func sameEntry(a, b *yaml.Node) bool {
+	if a.Kind != yaml.MappingNode || b.Kind != yaml.MappingNode || len(a.Content) != len(b.Content) {
+		return false
+	}
+	for _, key := range keysOf(a) {
+		if scalar(value(a, key)) != scalar(value(b, key)) {
+			return false
+		}
+	}
+	return true
+}
+
+// sortKeys reorders the pairs of a mapping by canonical rank; unknown keys keep
+// their relative order after the known ones
+func sortKeys(node *yaml.Node, order []string) {
+	type pair struct{ key, value *yaml.Node }
+	pairs := make([]pair, 0, len(node.Content)/2)
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		pairs = append(pairs, pair{node.Content[i], node.Content[i+1]})
+	}
+	sort.SliceStable(pairs, func(i, j int) bool { return rank(order, pairs[i].key.Value) < rank(order, pairs[j].key.Value) })
+	node.Content = node.Content[:0]
+	for _, p := range pairs {
+		node.Content = append(node.Content, p.key, p.value)
+	}
+}
+
+func rank(order []string, key string) int {
+	for i, k := range order {
+		if k == key {
+			return i
+		}
+	}
+	return len(order)
+}
+
+func keysOf(node *yaml.Node) []string {
+	var keys []string
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		keys = append(keys, node.Content[i].Value)
+	}
+	return keys
+}
+
+// value returns the value of key in a mapping node, or nil
+func value(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+func deleteKey(node *yaml.Node, key string) {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			node.Content = append(node.Content[:i], node.Content[i+2:]...)
+			return
+		}
+	}
+}
+
+func scalar(node *yaml.Node) string {
+	if node == nil || node.Kind != yaml.ScalarNode {
+		return ""
+	}
+	return node.Value
+}
+
+func encode(doc *yaml.Node) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(doc); err != nil {
+		return nil, fmt.Errorf("failed to render YAML: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func nonBlankLines(data []byte) []string {
+	var lines []string
+	for _, line := range strings.Split(string(data), "\n") {
+		if trimmed := strings.TrimRight(line, " \t\r"); trimmed != "" {
+			lines = append(lines, trimmed)
+		}
+	}
+	return lines
+}
+
+func sameIgnoringBlankLines(a, b []byte) bool {
+	la, lb := nonBlankLines(a), nonBlankLines(b)
+	if len(la) != len(lb) {
+		return false
+	}
+	for i := range la {
+		if la[i] != lb[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// firstDifference returns the line of data where it first departs from canonical
+func firstDifference(data, canonical []byte) int {
+	want := nonBlankLines(canonical)
+	n := 0
+	for i, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimRight(line, " \t\r")
+		if line == "" {
+			continue
+		}
+		if n >= len(want) || want[n] != line {
+			return i + 1
+		}
+		n++
+	}
+	return 0
+}
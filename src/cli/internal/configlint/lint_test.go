@@ -0,0 +1,148 @@
+//go:build L0
+// +build L0
+
+package configlint
+
+import (
+	"strings"
+	"testing"
+)
+
+func findingsByRule(report *Report) map[string]int {
+	rules := make(map[string]int)
+	for _, f := range report.Findings {
+		rules[f.Rule]++
+	}
+	return rules
+}
+
+func TestLintStyleRules(t *testing.T) {
+	data := []byte(`extensions:
+  - image: pwsh:1.0.0
+    name: pwsh
+    memory_limit: 1g
+    cpu_limit: "1.0"
+    env:
+      - name: LOG_LEVEL
+        value: debug
+      - name: LOG_LEVEL
+        value: debug
+      - name: SHARED
+        value: one
+environment:
+  global:
+    - name: SHARED
+      value: one
+`)
+
+	report, err := Lint(data, "r2r-cli.yml")
+	if err != nil {
+		t.Fatalf("Lint failed: %v", err)
+	}
+
+	rules := findingsByRule(report)
+	if rules[RuleKeyOrder] != 2 {
+		t.Errorf("Expected key-order findings for the top level and the extension, got %d", rules[RuleKeyOrder])
+	}
+	if rules[RuleDuplicateEnv] != 1 {
+		t.Errorf("Expected 1 duplicate-env finding, got %d", rules[RuleDuplicateEnv])
+	}
+	if rules[RuleRedundantEnv] != 1 {
+		t.Errorf("Expected 1 redundant-env finding, got %d", rules[RuleRedundantEnv])
+	}
+}
+
+func TestLintDuplicateEnvWithDifferentValues(t *testing.T) {
+	report, err := Lint([]byte(`environment:
+  global:
+    - name: A
+      value: one
+    - name: A
+      value: two
+`), "r2r-cli.yml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range report.Findings {
+		if f.Rule == RuleDuplicateEnv {
+			if f.Fixable {
+				t.Error("Duplicates with different values should not be fixable")
+			}
+			if f.Line != 5 {
+				t.Errorf("Expected duplicate at line 5, got %d", f.Line)
+			}
+			return
+		}
+	}
+	t.Error("Expected a duplicate-env finding")
+}
+
+func TestFixPreservesComments(t *testing.T) {
+	data := []byte(`# Project configuration
+extensions:
+    # The PowerShell extension
+    - image: pwsh:1.0.0 # pinned
+      name: pwsh
+      env:
+        - name: SHARED
+          value: one
+        - name: KEEP
+          value: yes
+environment:
+    global:
+        - name: SHARED
+          value: one
+`)
+
+	fixed, err := Fix(data)
+	if err != nil {
+		t.Fatalf("Fix failed: %v", err)
+	}
+	out := string(fixed)
+
+	for _, comment := range []string{"# Project configuration", "# The PowerShell extension", "# pinned"} {
+		if !strings.Contains(out, comment) {
+			t.Errorf("Comment %q lost:\n%s", comment, out)
+		}
+	}
+	if strings.Index(out, "environment:") > strings.Index(out, "extensions:") {
+		t.Errorf("environment should precede extensions:\n%s", out)
+	}
+	if strings.Index(out, "name: pwsh") > strings.Index(out, "image: pwsh") {
+		t.Errorf("name should precede image:\n%s", out)
+	}
+	if strings.Count(out, "name: SHARED") != 1 {
+		t.Errorf("Redundant SHARED should be removed from the extension:\n%s", out)
+	}
+	if !strings.Contains(out, "\n  global:\n") {
+		t.Errorf("Expected 2-space indentation:\n%s", out)
+	}
+
+	report, err := Lint(fixed, "r2r-cli.yml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Fixable() != 0 {
+		t.Errorf("Fixed output should have no fixable findings, got: %+v", report.Findings)
+	}
+}
+
+func TestLintCanonicalFile(t *testing.T) {
+	report, err := Lint([]byte(`environment:
+  global:
+    - name: A
+      value: one
+
+extensions:
+  - name: pwsh
+    image: pwsh:1.0.0
+    memory_limit: 1g
+    cpu_limit: "1.0"
+`), "r2r-cli.yml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Findings) != 0 {
+		t.Errorf("Expected no findings, got: %+v", report.Findings)
+	}
+}
@@ -0,0 +1,27 @@
+package configlint
+
+import (
+	"bytes"
+	"regexp"
+)
+
+// SchemaModeline starts the comment that points the YAML language server,
+// and the editors built on it, to the schema of a file
+const SchemaModeline = "# yaml-language-server: $schema="
+
+var modelinePattern = regexp.MustCompile(`(?m)^#\s*yaml-language-server:\s*\$schema=[^\r\n]*`)
+
+// SetSchemaModeline points the schema comment of a YAML document to ref,
+// replacing an existing one, or adding it as the first line. It reports
+// whether the document changed.
+func SetSchemaModeline(data []byte, ref string) ([]byte, bool) {
+	line := []byte(SchemaModeline + ref)
+	if loc := modelinePattern.FindIndex(data); loc != nil {
+		if bytes.Equal(data[loc[0]:loc[1]], line) {
+			return data, false
+		}
+		updated := append(append(append([]byte{}, data[:loc[0]]...), line...), data[loc[1]:]...)
+		return updated, true
+	}
+	return append(append(line, '\n'), data...), true
+}
@@ -0,0 +1,41 @@
+//go:build L0
+// +build L0
+
+package configlint
+
+import "testing"
+
+func TestSetSchemaModeline(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    string
+		changed bool
+	}{
+		{
+			name:    "adds the first line",
+			data:    "extensions: []\n",
+			want:    "# yaml-language-server: $schema=schema.json\nextensions: []\n",
+			changed: true,
+		},
+		{
+			name:    "replaces an existing comment",
+			data:    "# Config\n#yaml-language-server:  $schema=old.json\r\nextensions: []\n",
+			want:    "# Config\n# yaml-language-server: $schema=schema.json\r\nextensions: []\n",
+			changed: true,
+		},
+		{
+			name: "keeps a matching comment",
+			data: "# yaml-language-server: $schema=schema.json\nextensions: []\n",
+			want: "# yaml-language-server: $schema=schema.json\nextensions: []\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, changed := SetSchemaModeline([]byte(tt.data), "schema.json")
+			if string(got) != tt.want || changed != tt.changed {
+				t.Errorf("SetSchemaModeline() = %q, %v; want %q, %v", got, changed, tt.want, tt.changed)
+			}
+		})
+	}
+}
@@ -0,0 +1,296 @@
+// Package contractdrift compares the directory tree with the module
+// contracts: top-level directories no module tracks, modules and include
+// globs matching no file, and definitions.yml files naming a module that
+// does not own them. The contract updates it suggests are rendered as a
+// patch for git apply.
+package contractdrift
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ready-to-release/eac/src/core/contracts/modules"
+	"github.com/ready-to-release/eac/src/core/repository"
+	"github.com/ready-to-release/eac/src/core/repository/definitions"
+	"gopkg.in/yaml.v3"
+)
+
+// Finding kinds
+const (
+	KindUntrackedDirectory = "untracked-directory" // a top-level directory owned by no module but the catch-all
+	KindDeadModule         = "dead-module"         // no include glob of the module matches a file
+	KindDeadGlob           = "dead-glob"           // one include glob of a module matches no file
+	KindDefinition         = "definition"          // a definitions.yml names a module that does not own it
+)
+
+// Finding is one difference between the tree and the contracts
+type Finding struct {
+	Kind    string `json:"kind"`
+	Module  string `json:"module,omitempty"`
+	Path    string `json:"path"` // directory, include glob or definitions file
+	Message string `json:"message"`
+
+	files []string // files of an untracked directory, relative to it
+}
+
+func (f Finding) String() string {
+	return fmt.Sprintf("%s %s: %s", f.Kind, f.Path, f.Message)
+}
+
+// Definition is a definitions.yml file and the module it names
+type Definition struct {
+	Path   string // relative to the repository root, with forward slashes
+	Module string
+}
+
+// LoadDefinitions returns the definitions.yml files under workspaceRoot that
+// name a module with a top-level module key
+func LoadDefinitions(workspaceRoot string) ([]Definition, error) {
+	files, err := definitions.EnumerateDefinitionFiles(workspaceRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	var found []Definition
+	for _, file := range files {
+		var content struct {
+			Module string `yaml:"module"`
+		}
+		if err := file.Content.Decode(&content); err != nil || content.Module == "" {
+			continue
+		}
+		relPath, err := filepath.Rel(workspaceRoot, file.Path)
+		if err != nil {
+			return nil, err
+		}
+		found = append(found, Definition{Path: filepath.ToSlash(relPath), Module: content.Module})
+	}
+	sort.Slice(found, func(i, j int) bool { return found[i].Path < found[j].Path })
+	return found, nil
+}
+
+// Detect compares the tracked files and definitions files with the module
+// contracts. Findings come grouped by kind, in the order of the kinds above.
+// Top-level directories starting with a dot hold tooling rather than
+// deployable units and are not reported.
+func Detect(registry *modules.Registry, files []string, definitionFiles []Definition) []Finding {
+	var findings []Finding
+	catchAll := registry.GetCatchAllModule()
+
+	// Untracked top-level directories
+	dirFiles := make(map[string][]string)
+	covered := make(map[string]bool)
+	for _, file := range files {
+		dir, rest, nested := strings.Cut(file, "/")
+		if !nested || strings.HasPrefix(dir, ".") {
+			continue
+		}
+		dirFiles[dir] = append(dirFiles[dir], rest)
+		if covered[dir] {
+			continue
+		}
+		for _, owner := range registry.FindModulesForFile(file) {
+			if owner != catchAll {
+				covered[dir] = true
+			}
+		}
+	}
+	for _, dir := range sortedKeys(dirFiles) {
+		if covered[dir] {
+			continue
+		}
+		message := fmt.Sprintf("%d file(s) belong to no module", len(dirFiles[dir]))
+		if catchAll != nil {
+			message = fmt.Sprintf("%d file(s) fall into catch-all module '%s'", len(dirFiles[dir]), catchAll.Moniker)
+		}
+		findings = append(findings, Finding{Kind: KindUntrackedDirectory, Path: dir + "/", Message: message, files: dirFiles[dir]})
+	}
+
+	// Dead modules and globs
+	var deadGlobs []Finding
+	for _, moniker := range registry.AllMonikers() {
+		module, _ := registry.Get(moniker)
+		if module == catchAll || len(module.Source.Includes) == 0 {
+			continue
+		}
+		var dead []string
+		for _, include := range module.Source.Includes {
+			if !matchesAny(module, include, files) {
+				dead = append(dead, include)
+			}
+		}
+		if len(dead) == len(module.Source.Includes) {
+			findings = append(findings, Finding{
+				Kind:    KindDeadModule,
+				Module:  moniker,
+				Path:    module.Source.Root,
+				Message: fmt.Sprintf("none of the %d include glob(s) matches a tracked file", len(dead)),
+			})
+			continue
+		}
+		for _, include := range dead {
+			deadGlobs = append(deadGlobs, Finding{
+				Kind:    KindDeadGlob,
+				Module:  moniker,
+				Path:    include,
+				Message: fmt.Sprintf("include of '%s' matches no tracked file", moniker),
+			})
+		}
+	}
+	findings = append(findings, deadGlobs...)
+
+	// Definitions files
+	for _, definition := range definitionFiles {
+		if !registry.Has(definition.Module) {
+			findings = append(findings, Finding{
+				Kind:    KindDefinition,
+				Module:  definition.Module,
+				Path:    definition.Path,
+				Message: fmt.Sprintf("names module '%s', which has no contract", definition.Module),
+			})
+			continue
+		}
+		var owners []string
+		owned := false
+		for _, owner := range registry.FindModulesForFile(definition.Path) {
+			owners = append(owners, owner.Moniker)
+			owned = owned || owner.Moniker == definition.Module
+		}
+		if owned {
+			continue
+		}
+		message := fmt.Sprintf("names module '%s', which does not include it", definition.Module)
+		if len(owners) > 0 {
+			sort.Strings(owners)
+			message += fmt.Sprintf(" (owned by %s)", strings.Join(owners, ", "))
+		}
+		findings = append(findings, Finding{Kind: KindDefinition, Module: definition.Module, Path: definition.Path, Message: message})
+	}
+
+	return findings
+}
+
+// DetectWorkspace compares the tracked files and definitions files of the
+// repository at workspaceRoot with the module contracts
+func DetectWorkspace(workspaceRoot string, registry *modules.Registry) ([]Finding, error) {
+	tracked, err := repository.GetRepositoryFiles(true, false, false, false, workspaceRoot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tracked files: %w", err)
+	}
+	files := make([]string, 0, len(tracked))
+	for _, file := range tracked {
+		files = append(files, file.Path)
+	}
+
+	definitionFiles, err := LoadDefinitions(workspaceRoot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read definitions files: %w", err)
+	}
+
+	return Detect(registry, files, definitionFiles), nil
+}
+
+// matchesAny reports whether one include glob of module matches one of files
+func matchesAny(module *modules.ModuleContract, include string, files []string) bool {
+	base := module.BaseContract
+	base.Source.Includes = []string{include}
+	single := modules.NewModuleContract(base, "")
+	for _, file := range files {
+		if single.MatchesFile(file) {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedKeys(m map[string][]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// contractFile mirrors the fields of a module contract written for an
+// untracked directory, as "module new" writes them
+type contractFile struct {
+	Moniker     string `yaml:"moniker"`
+	Name        string `yaml:"name"`
+	Description string `yaml:"description"`
+	Versioning  struct {
+		VersionScheme string `yaml:"version_scheme"`
+	} `yaml:"versioning"`
+	Source struct {
+		Root     string   `yaml:"root"`
+		Includes []string `yaml:"includes"`
+	} `yaml:"source"`
+	DependsOn []string `yaml:"depends_on"`
+}
+
+// suggestedContract renders a contract owning the files of an untracked directory
+func suggestedContract(moniker string, dir string, files []string) ([]byte, error) {
+	contract := contractFile{
+		Moniker:     moniker,
+		Name:        displayName(moniker),
+		Description: fmt.Sprintf("Sources under %s/", dir),
+		DependsOn:   []string{},
+	}
+	contract.Versioning.VersionScheme = "semver"
+	contract.Source.Root = dir
+	contract.Source.Includes = inferIncludes(files)
+
+	var buf strings.Builder
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(contract); err != nil {
+		return nil, fmt.Errorf("failed to render contract for %s: %w", dir, err)
+	}
+	return []byte(buf.String()), nil
+}
+
+// inferIncludes derives one "**/*.<ext>" glob per file extension and one
+// "**/<name>" glob per extension-less file name
+func inferIncludes(files []string) []string {
+	seen := make(map[string]bool)
+	var includes []string
+	for _, file := range files {
+		base := filepath.Base(file)
+		pattern := "**/" + base
+		if ext := filepath.Ext(base); ext != "" && ext != base {
+			pattern = "**/*" + ext
+		}
+		if !seen[pattern] {
+			seen[pattern] = true
+			includes = append(includes, pattern)
+		}
+	}
+	sort.Strings(includes)
+	return includes
+}
+
+// monikerFor turns a directory name into a moniker ("My_Tool" -> "my-tool")
+func monikerFor(dir string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(dir) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+		} else {
+			b.WriteRune('-')
+		}
+	}
+	return strings.Trim(b.String(), "-")
+}
+
+// displayName turns a moniker into a readable name ("my-tool" -> "My Tool")
+func displayName(moniker string) string {
+	words := strings.Split(moniker, "-")
+	for i, word := range words {
+		if word != "" {
+			words[i] = strings.ToUpper(word[:1]) + word[1:]
+		}
+	}
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,141 @@
+//go:build L0
+// +build L0
+
+package contractdrift
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ready-to-release/eac/src/core/contracts/modules"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const cliContract = `moniker: src-cli
+name: CLI
+source:
+  root: src/cli
+  includes:
+    - "**/*.go"
+    - "**/*.rs" # rewritten in Go
+    - go.mod
+depends_on: []
+`
+
+const docsContract = `moniker: docs
+name: Docs
+source:
+  root: docs
+  includes:
+    - "**/*.md"
+`
+
+const catchAllContract = `moniker: repo
+name: Repository
+source:
+  root: /
+  includes:
+    - "**"
+  is_catch_all_singleton: true
+`
+
+// testWorkspace writes the contracts to a workspace and loads them
+func testWorkspace(t *testing.T) (string, *modules.Registry) {
+	root := t.TempDir()
+	dir := filepath.Join(root, "contracts", "modules", "0.1.0")
+	require.NoError(t, os.MkdirAll(dir, 0755))
+	for name, content := range map[string]string{"src-cli": cliContract, "docs": docsContract, "repo": catchAllContract} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name+".yml"), []byte(content), 0644))
+	}
+	registry, err := modules.LoadFromWorkspace(root, "0.1.0")
+	require.NoError(t, err)
+	return root, registry
+}
+
+var testFiles = []string{
+	"README.md",
+	".github/workflows/ci.yml",
+	"src/cli/main.go",
+	"src/cli/go.mod",
+	"src/cli/definitions.yml",
+	"tools/Makefile",
+	"tools/lint/lint.sh",
+}
+
+func TestDetect(t *testing.T) {
+	_, registry := testWorkspace(t)
+	definitionFiles := []Definition{
+		{Path: "src/cli/definitions.yml", Module: "src-cli"},
+		{Path: "tools/definitions.yml", Module: "tools"},
+		{Path: "src/definitions.yml", Module: "docs"},
+	}
+
+	findings := Detect(registry, testFiles, definitionFiles)
+
+	var got []string
+	for _, finding := range findings {
+		got = append(got, finding.Kind+" "+finding.Path)
+	}
+	assert.Equal(t, []string{
+		"untracked-directory tools/",
+		"dead-module docs",
+		"dead-glob **/*.rs",
+		"definition src/cli/definitions.yml",
+		"definition tools/definitions.yml",
+		"definition src/definitions.yml",
+	}, got, "src/cli/definitions.yml is not matched by the src-cli includes")
+	assert.Contains(t, findings[0].Message, "catch-all module 'repo'")
+	assert.Contains(t, findings[5].Message, "owned by repo")
+}
+
+func TestPatch(t *testing.T) {
+	root, registry := testWorkspace(t)
+	findings := Detect(registry, testFiles, nil)
+
+	patch, err := Patch(root, registry, findings)
+	require.NoError(t, err)
+
+	assert.Contains(t, patch, "deleted file mode 100644\n--- a/contracts/modules/0.1.0/docs.yml\n+++ /dev/null\n@@ -1,6 +0,0 @@\n-moniker: docs\n")
+	assert.Contains(t, patch, `--- a/contracts/modules/0.1.0/src-cli.yml
++++ b/contracts/modules/0.1.0/src-cli.yml
+@@ -4,6 +4,5 @@
+   root: src/cli
+   includes:
+     - "**/*.go"
+-    - "**/*.rs" # rewritten in Go
+     - go.mod
+ depends_on: []
+`)
+	assert.Contains(t, patch, "new file mode 100644\n--- /dev/null\n+++ b/contracts/modules/0.1.0/tools.yml\n")
+	assert.Contains(t, patch, "+  root: tools\n+  includes:\n+    - '**/*.sh'\n+    - '**/Makefile'\n")
+}
+
+func TestDeletionDiff_MergesHunks(t *testing.T) {
+	lines := strings.Split("a b c d e f g h i j k l m n o p", " ")
+
+	diff := deletionDiff("f", lines, []int{2, 5, 14})
+
+	assert.Equal(t, `diff --git a/f b/f
+--- a/f
++++ b/f
+@@ -1,9 +1,7 @@
+ a
+ b
+-c
+ d
+ e
+-f
+ g
+ h
+ i
+@@ -12,5 +10,4 @@
+ l
+ m
+ n
+-o
+ p
+`, diff)
+}
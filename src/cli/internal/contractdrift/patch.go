@@ -0,0 +1,174 @@
+package contractdrift
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ready-to-release/eac/src/core/contracts/modules"
+)
+
+// contextLines is the number of unchanged lines around each change
+const contextLines = 3
+
+// Patch renders the contract updates suggested by findings as a unified diff
+// of the contract files under workspaceRoot, for git apply: dead globs are
+// removed from their contract, dead modules are deleted, and each untracked
+// directory gets a new contract. Definitions findings need a decision on
+// which module is right and have no suggestion.
+func Patch(workspaceRoot string, registry *modules.Registry, findings []Finding) (string, error) {
+	contractsDir := path.Join("contracts", "modules", registry.Version())
+
+	var patch strings.Builder
+	deadGlobs := make(map[string][]string)
+	for _, finding := range findings {
+		switch finding.Kind {
+		case KindDeadModule:
+			contractPath := path.Join(contractsDir, finding.Module+".yml")
+			lines, err := readLines(workspaceRoot, contractPath)
+			if err != nil {
+				return "", err
+			}
+			patch.WriteString(fileDiff(contractPath, lines, false))
+		case KindDeadGlob:
+			deadGlobs[finding.Module] = append(deadGlobs[finding.Module], finding.Path)
+		}
+	}
+
+	for _, moniker := range sortedKeys(deadGlobs) {
+		contractPath := path.Join(contractsDir, moniker+".yml")
+		lines, err := readLines(workspaceRoot, contractPath)
+		if err != nil {
+			return "", err
+		}
+		removed := includeLines(lines, deadGlobs[moniker])
+		if len(removed) > 0 {
+			patch.WriteString(deletionDiff(contractPath, lines, removed))
+		}
+	}
+
+	for _, finding := range findings {
+		if finding.Kind != KindUntrackedDirectory {
+			continue
+		}
+		dir := strings.TrimSuffix(finding.Path, "/")
+		moniker := monikerFor(dir)
+		contractPath := path.Join(contractsDir, moniker+".yml")
+		if moniker == "" || registry.Has(moniker) {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(workspaceRoot, filepath.FromSlash(contractPath))); err == nil {
+			continue
+		}
+		content, err := suggestedContract(moniker, dir, finding.files)
+		if err != nil {
+			return "", err
+		}
+		patch.WriteString(fileDiff(contractPath, splitLines(string(content)), true))
+	}
+
+	return patch.String(), nil
+}
+
+// includeLines returns the indexes of the list items of lines holding one of
+// includes, quoted or not
+func includeLines(lines []string, includes []string) []int {
+	wanted := make(map[string]bool, len(includes))
+	for _, include := range includes {
+		wanted[include] = true
+	}
+
+	var found []int
+	for i, line := range lines {
+		item := strings.TrimSpace(line)
+		if !strings.HasPrefix(item, "- ") {
+			continue
+		}
+		value := strings.TrimSpace(strings.TrimPrefix(item, "- "))
+		if comment := strings.Index(value, " #"); comment >= 0 {
+			value = strings.TrimSpace(value[:comment])
+		}
+		value = strings.Trim(value, `"'`)
+		if wanted[value] {
+			found = append(found, i)
+		}
+	}
+	return found
+}
+
+// fileDiff renders the creation (added) or deletion of a whole file
+func fileDiff(name string, lines []string, added bool) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "diff --git a/%s b/%s\n", name, name)
+	if added {
+		fmt.Fprintf(&b, "new file mode 100644\n--- /dev/null\n+++ b/%s\n@@ -0,0 +1,%d @@\n", name, len(lines))
+	} else {
+		fmt.Fprintf(&b, "deleted file mode 100644\n--- a/%s\n+++ /dev/null\n@@ -1,%d +0,0 @@\n", name, len(lines))
+	}
+	prefix := "-"
+	if added {
+		prefix = "+"
+	}
+	for _, line := range lines {
+		b.WriteString(prefix + line + "\n")
+	}
+	return b.String()
+}
+
+// deletionDiff renders the removal of the lines at the sorted indexes removed
+// from a file, with contextLines of context and overlapping hunks merged
+func deletionDiff(name string, lines []string, removed []int) string {
+	sort.Ints(removed)
+	isRemoved := make(map[int]bool, len(removed))
+	for _, index := range removed {
+		isRemoved[index] = true
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "diff --git a/%s b/%s\n--- a/%s\n+++ b/%s\n", name, name, name, name)
+
+	removedBefore := 0
+	for i := 0; i < len(removed); {
+		start := max(removed[i]-contextLines, 0)
+		end := min(removed[i]+contextLines, len(lines)-1)
+		j := i + 1
+		for j < len(removed) && removed[j]-contextLines <= end+1 {
+			end = min(removed[j]+contextLines, len(lines)-1)
+			j++
+		}
+
+		oldCount := end - start + 1
+		newCount := oldCount - (j - i)
+		fmt.Fprintf(&b, "@@ -%d,%d +%d,%d @@\n", start+1, oldCount, start+1-removedBefore, newCount)
+		for k := start; k <= end; k++ {
+			if isRemoved[k] {
+				b.WriteString("-" + lines[k] + "\n")
+			} else {
+				b.WriteString(" " + lines[k] + "\n")
+			}
+		}
+
+		removedBefore += j - i
+		i = j
+	}
+	return b.String()
+}
+
+func readLines(workspaceRoot string, name string) ([]string, error) {
+	data, err := os.ReadFile(filepath.Join(workspaceRoot, filepath.FromSlash(name)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read contract: %w", err)
+	}
+	return splitLines(string(data)), nil
+}
+
+// splitLines splits content into lines without the final newline
+func splitLines(content string) []string {
+	if content == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+}
@@ -0,0 +1,81 @@
+// Package contractsync compares the contract files embedded in the CLI at
+// build time with the source of truth in the contracts directory.
+package contractsync
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// State describes how an embedded file relates to its source
+type State string
+
+const (
+	StateInSync        State = "in-sync"
+	StateStale         State = "stale"          // source changed since the build
+	StateSourceMissing State = "source-missing" // source no longer exists
+)
+
+// Status is the state of a single embedded file
+type Status struct {
+	Path         string // relative to the contracts directory, with forward slashes
+	State        State
+	EmbeddedHash string
+	SourceHash   string
+}
+
+// Check compares the SHA-256 checksum of every file in embedded with the
+// file of the same path under contractsDir, without changing anything
+func Check(embedded fs.FS, contractsDir string) ([]Status, error) {
+	var statuses []Status
+	err := fs.WalkDir(embedded, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		data, err := fs.ReadFile(embedded, p)
+		if err != nil {
+			return err
+		}
+		status := Status{Path: p, EmbeddedHash: hash(data)}
+
+		source, err := os.ReadFile(filepath.Join(contractsDir, filepath.FromSlash(p)))
+		switch {
+		case os.IsNotExist(err):
+			status.State = StateSourceMissing
+		case err != nil:
+			return err
+		default:
+			status.SourceHash = hash(source)
+			status.State = StateStale
+			if status.SourceHash == status.EmbeddedHash {
+				status.State = StateInSync
+			}
+		}
+		statuses = append(statuses, status)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Path < statuses[j].Path })
+	return statuses, nil
+}
+
+// InSync reports whether every embedded file matches its source
+func InSync(statuses []Status) bool {
+	for _, status := range statuses {
+		if status.State != StateInSync {
+			return false
+		}
+	}
+	return true
+}
+
+func hash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,74 @@
+//go:build L0
+// +build L0
+
+package contractsync
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	contractsDir := t.TempDir()
+	embedded := fstest.MapFS{
+		"cli/0.1.0/schema.json":  {Data: []byte("{}")},
+		"cli/0.1.0/command.ebnf": {Data: []byte("old")},
+		"modules/0.1.0/gone.yml": {Data: []byte("moniker: gone")},
+	}
+	writeFile(t, filepath.Join(contractsDir, "cli/0.1.0/schema.json"), "{}")
+	writeFile(t, filepath.Join(contractsDir, "cli/0.1.0/command.ebnf"), "new")
+	writeFile(t, filepath.Join(contractsDir, "modules/0.1.0/added.yml"), "moniker: added")
+
+	statuses, err := Check(embedded, contractsDir)
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+
+	want := map[string]State{
+		"cli/0.1.0/command.ebnf": StateStale,
+		"cli/0.1.0/schema.json":  StateInSync,
+		"modules/0.1.0/gone.yml": StateSourceMissing,
+	}
+	if len(statuses) != len(want) {
+		t.Fatalf("Check() returned %d statuses, want %d: %+v", len(statuses), len(want), statuses)
+	}
+	for _, status := range statuses {
+		if status.State != want[status.Path] {
+			t.Errorf("%s: state = %s, want %s", status.Path, status.State, want[status.Path])
+		}
+	}
+	if statuses[0].Path != "cli/0.1.0/command.ebnf" {
+		t.Errorf("statuses not sorted: %+v", statuses)
+	}
+	if InSync(statuses) {
+		t.Error("InSync() = true with stale files")
+	}
+}
+
+func TestCheckInSync(t *testing.T) {
+	contractsDir := t.TempDir()
+	writeFile(t, filepath.Join(contractsDir, "a.json"), "a")
+
+	statuses, err := Check(fstest.MapFS{"a.json": {Data: []byte("a")}}, contractsDir)
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	if !InSync(statuses) {
+		t.Errorf("InSync() = false, statuses: %+v", statuses)
+	}
+	if statuses[0].EmbeddedHash != statuses[0].SourceHash || len(statuses[0].EmbeddedHash) != 64 {
+		t.Errorf("unexpected hashes: %+v", statuses[0])
+	}
+}
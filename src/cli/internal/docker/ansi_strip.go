@@ -0,0 +1,88 @@
+package docker
+
+import (
+	"io"
+)
+
+// AnsiStripper wraps an io.Writer and removes every ANSI escape sequence,
+// including colors, leaving plain text. Unlike AnsiFilter it keeps no
+// formatting, for output that is read by tools or written to CI logs.
+//
+// Removed sequences:
+// - CSI sequences: ESC[ ... final byte (colors, cursor movement, modes)
+// - OSC sequences: ESC] ... terminated by BEL or ESC\ (titles, hyperlinks)
+// - DCS, SOS, PM and APC strings: ESC P/X/^/_ ... ESC\
+// - Two-byte escapes: ESC followed by a single character
+//
+// A sequence split across writes is held back until it is complete.
+type AnsiStripper struct {
+	writer io.Writer
+	state  stripState
+}
+
+type stripState int
+
+const (
+	stripText     stripState = iota
+	stripEscape              // after ESC
+	stripCSI                 // inside ESC[
+	stripString              // inside OSC/DCS/SOS/PM/APC
+	stripStringST            // ESC seen inside a string, expecting '\'
+)
+
+// NewAnsiStripper creates a new ANSI stripper that wraps the given writer
+func NewAnsiStripper(w io.Writer) *AnsiStripper {
+	return &AnsiStripper{writer: w}
+}
+
+// Write removes escape sequences from p and writes the remaining text
+func (s *AnsiStripper) Write(p []byte) (int, error) {
+	out := make([]byte, 0, len(p))
+
+	for _, b := range p {
+		switch s.state {
+		case stripText:
+			if b == 0x1b {
+				s.state = stripEscape
+			} else {
+				out = append(out, b)
+			}
+		case stripEscape:
+			switch b {
+			case '[':
+				s.state = stripCSI
+			case ']', 'P', 'X', '^', '_':
+				s.state = stripString
+			default:
+				s.state = stripText
+			}
+		case stripCSI:
+			// Parameter and intermediate bytes are 0x20-0x3f; a final byte ends it
+			if b >= 0x40 && b <= 0x7e {
+				s.state = stripText
+			}
+		case stripString:
+			switch b {
+			case 0x07:
+				s.state = stripText
+			case 0x1b:
+				s.state = stripStringST
+			}
+		case stripStringST:
+			if b == '\\' {
+				s.state = stripText
+			} else {
+				s.state = stripString
+			}
+		}
+	}
+
+	if len(out) > 0 {
+		if _, err := s.writer.Write(out); err != nil {
+			return 0, err
+		}
+	}
+
+	// Return original length to satisfy io.Writer contract
+	return len(p), nil
+}
@@ -0,0 +1,51 @@
+//go:build L0
+// +build L0
+
+package docker
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestAnsiStripper(t *testing.T) {
+	testCases := map[string]struct {
+		input    string
+		expected string
+	}{
+		"plain text":      {"hello\r\n", "hello\r\n"},
+		"colors":          {"\x1b[1;32mok\x1b[0m done", "ok done"},
+		"cursor movement": {"a\x1b[2Kb\x1b[10;5Hc", "abc"},
+		"dec private":     {"\x1b[?25lhidden\x1b[?25h", "hidden"},
+		"osc bel":         {"\x1b]0;title\x07text", "text"},
+		"osc st":          {"\x1b]8;;https://example.com\x1b\\link\x1b]8;;\x1b\\", "link"},
+		"two byte":        {"\x1b7saved\x1b8", "saved"},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			var out bytes.Buffer
+			n, err := NewAnsiStripper(&out).Write([]byte(tc.input))
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if n != len(tc.input) {
+				t.Errorf("Expected %d bytes written, got %d", len(tc.input), n)
+			}
+			if out.String() != tc.expected {
+				t.Errorf("Expected %q, got %q", tc.expected, out.String())
+			}
+		})
+	}
+}
+
+func TestAnsiStripperSplitSequence(t *testing.T) {
+	var out bytes.Buffer
+	stripper := NewAnsiStripper(&out)
+	for _, chunk := range []string{"red: \x1b", "[3", "1mX\x1b[", "0m!"} {
+		stripper.Write([]byte(chunk))
+	}
+	if out.String() != "red: X!" {
+		t.Errorf("Expected %q, got %q", "red: X!", out.String())
+	}
+}
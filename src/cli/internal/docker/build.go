@@ -0,0 +1,269 @@
+package docker
+
+import (
+	"archive/tar"
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	"github.com/rs/zerolog/log"
+)
+
+// BuildOptions describes a local image build
+type BuildOptions struct {
+	ContextDir string            // Absolute path of the build context
+	Dockerfile string            // Relative to ContextDir, default "Dockerfile"
+	Args       map[string]string // Build arguments
+	Tags       []string          // Tags applied to the built image
+	NoCache    bool
+}
+
+// buildMessage is one line of the Docker build JSON stream
+type buildMessage struct {
+	Stream string `json:"stream"`
+	Status string `json:"status"`
+	Error  string `json:"error"`
+	Aux    *struct {
+		ID string `json:"ID"`
+	} `json:"aux"`
+}
+
+// BuildImage builds an image from a local context and returns its ID. Build
+// output is written to out as it arrives. The build is not bounded by a
+// timeout; cancelling ctx aborts it.
+func (ch *ContainerHost) BuildImage(ctx context.Context, opts BuildOptions, out io.Writer) (string, error) {
+	dockerfile := opts.Dockerfile
+	if dockerfile == "" {
+		dockerfile = "Dockerfile"
+	}
+	if _, err := os.Stat(filepath.Join(opts.ContextDir, dockerfile)); err != nil {
+		return "", fmt.Errorf("dockerfile not found: %w", err)
+	}
+
+	buildArgs := make(map[string]*string, len(opts.Args))
+	for name, value := range opts.Args {
+		value := value
+		buildArgs[name] = &value
+	}
+
+	log.Info().Str("context", opts.ContextDir).Str("dockerfile", dockerfile).Strs("tags", opts.Tags).Msg("Building image")
+
+	buildContext := tarBuildContext(opts.ContextDir, filepath.ToSlash(dockerfile))
+	defer buildContext.Close()
+
+	resp, err := ch.client.ImageBuild(ctx, buildContext, types.ImageBuildOptions{
+		Dockerfile:  filepath.ToSlash(dockerfile),
+		Tags:        opts.Tags,
+		BuildArgs:   buildArgs,
+		NoCache:     opts.NoCache,
+		Remove:      true,
+		ForceRemove: true,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to start build: %w", err)
+	}
+	defer resp.Body.Close()
+
+	imageID, err := displayBuildOutput(resp.Body, out)
+	if err != nil {
+		if ctx.Err() != nil {
+			return "", fmt.Errorf("build cancelled: %w", ctx.Err())
+		}
+		return "", err
+	}
+	return imageID, nil
+}
+
+// displayBuildOutput writes the build log from the Docker JSON stream to out
+// and returns the ID of the built image
+func displayBuildOutput(reader io.Reader, out io.Writer) (string, error) {
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	imageID := ""
+
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		var msg buildMessage
+		if err := json.Unmarshal(line, &msg); err != nil {
+			log.Debug().Str("line", string(line)).Msg("Unparseable Docker build output")
+			continue
+		}
+
+		switch {
+		case msg.Error != "":
+			return "", fmt.Errorf("build failed: %s", strings.TrimSpace(msg.Error))
+		case msg.Aux != nil && msg.Aux.ID != "":
+			imageID = msg.Aux.ID
+		case msg.Stream != "":
+			fmt.Fprint(out, msg.Stream)
+		case msg.Status != "":
+			fmt.Fprintln(out, msg.Status)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return "", fmt.Errorf("failed to read build output: %w", err)
+	}
+	return imageID, nil
+}
+
+// tarBuildContext streams contextDir as a tar archive, honouring its
+// .dockerignore. The Dockerfile and .dockerignore are always included.
+func tarBuildContext(contextDir, dockerfile string) io.ReadCloser {
+	reader, writer := io.Pipe()
+
+	go func() {
+		ignore, err := readDockerignore(contextDir)
+		if err != nil {
+			writer.CloseWithError(err)
+			return
+		}
+
+		tw := tar.NewWriter(writer)
+		err = filepath.Walk(contextDir, func(file string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			rel, err := filepath.Rel(contextDir, file)
+			if err != nil || rel == "." {
+				return err
+			}
+			rel = filepath.ToSlash(rel)
+
+			if rel != dockerfile && rel != ".dockerignore" && ignore.excludes(rel) {
+				if info.IsDir() && !ignore.hasExceptions() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			return addToTar(tw, file, rel, info)
+		})
+		if err == nil {
+			err = tw.Close()
+		}
+		writer.CloseWithError(err)
+	}()
+
+	return reader
+}
+
+// addToTar writes a single file, directory or symlink to tw
+func addToTar(tw *tar.Writer, file, name string, info os.FileInfo) error {
+	link := ""
+	if info.Mode()&os.ModeSymlink != 0 {
+		target, err := os.Readlink(file)
+		if err != nil {
+			return err
+		}
+		link = target
+	}
+
+	header, err := tar.FileInfoHeader(info, link)
+	if err != nil {
+		return err
+	}
+	header.Name = name
+	if info.IsDir() {
+		header.Name += "/"
+	}
+	if err := tw.WriteHeader(header); err != nil {
+		return err
+	}
+	if !info.Mode().IsRegular() {
+		return nil
+	}
+
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = io.Copy(tw, f)
+	return err
+}
+
+// dockerignore holds the patterns of a .dockerignore file
+type dockerignore struct {
+	patterns []ignorePattern
+}
+
+type ignorePattern struct {
+	pattern   string
+	exception bool
+}
+
+// readDockerignore loads contextDir/.dockerignore, if present
+func readDockerignore(contextDir string) (*dockerignore, error) {
+	ignore := &dockerignore{}
+
+	data, err := os.ReadFile(filepath.Join(contextDir, ".dockerignore"))
+	if os.IsNotExist(err) {
+		return ignore, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read .dockerignore: %w", err)
+	}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		p := ignorePattern{}
+		if strings.HasPrefix(line, "!") {
+			p.exception = true
+			line = strings.TrimSpace(line[1:])
+		}
+		p.pattern = strings.Trim(path.Clean(filepath.ToSlash(line)), "/")
+		ignore.patterns = append(ignore.patterns, p)
+	}
+	return ignore, nil
+}
+
+// excludes reports whether name is excluded. As in Docker, the last matching
+// pattern wins and a pattern matching a directory excludes its contents.
+func (d *dockerignore) excludes(name string) bool {
+	excluded := false
+	for _, p := range d.patterns {
+		if matchIgnorePattern(p.pattern, name) {
+			excluded = !p.exception
+		}
+	}
+	return excluded
+}
+
+func (d *dockerignore) hasExceptions() bool {
+	for _, p := range d.patterns {
+		if p.exception {
+			return true
+		}
+	}
+	return false
+}
+
+// matchIgnorePattern matches name or one of its parent directories against
+// pattern. A leading "**/" matches at any depth.
+func matchIgnorePattern(pattern, name string) bool {
+	anyDepth := strings.HasPrefix(pattern, "**/")
+	pattern = strings.TrimPrefix(pattern, "**/")
+
+	parts := strings.Split(name, "/")
+	for end := 1; end <= len(parts); end++ {
+		for start := 0; start < end; start++ {
+			if start > 0 && !anyDepth {
+				break
+			}
+			if ok, _ := path.Match(pattern, strings.Join(parts[start:end], "/")); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,113 @@
+//go:build L0
+// +build L0
+
+package docker
+
+import (
+	"archive/tar"
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestMatchIgnorePattern(t *testing.T) {
+	testCases := []struct {
+		pattern  string
+		name     string
+		expected bool
+	}{
+		{"node_modules", "node_modules", true},
+		{"node_modules", "node_modules/pkg/index.js", true},
+		{"node_modules", "src/node_modules", false},
+		{"*.log", "build.log", true},
+		{"*.log", "logs/build.log", false},
+		{"**/*.log", "logs/build.log", true},
+		{"docs/*.md", "docs/readme.md", true},
+		{"docs/*.md", "docs/api/readme.md", false},
+	}
+
+	for _, tc := range testCases {
+		if got := matchIgnorePattern(tc.pattern, tc.name); got != tc.expected {
+			t.Errorf("matchIgnorePattern(%q, %q) = %v, expected %v", tc.pattern, tc.name, got, tc.expected)
+		}
+	}
+}
+
+func TestTarBuildContextHonoursDockerignore(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"Dockerfile":         "FROM alpine\n",
+		".dockerignore":      "# comment\n*.log\ntmp\nDockerfile\nsecrets/*\n!secrets/public.pem\n",
+		"main.sh":            "echo hi\n",
+		"debug.log":          "noise\n",
+		"tmp/cache.bin":      "cache\n",
+		"secrets/key.pem":    "private\n",
+		"secrets/public.pem": "public\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	reader := tarBuildContext(dir, "Dockerfile")
+	defer reader.Close()
+
+	var names []string
+	tr := tar.NewReader(reader)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Failed to read tar: %v", err)
+		}
+		names = append(names, header.Name)
+	}
+	sort.Strings(names)
+
+	expected := []string{".dockerignore", "Dockerfile", "main.sh", "secrets/", "secrets/public.pem"}
+	if strings.Join(names, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected %v, got %v", expected, names)
+	}
+}
+
+func TestDisplayBuildOutput(t *testing.T) {
+	stream := `{"stream":"Step 1/2 : FROM alpine\n"}
+{"status":"Pulling from library/alpine"}
+{"aux":{"ID":"sha256:abc123"}}
+{"stream":"Successfully tagged test:latest\n"}
+`
+	var out bytes.Buffer
+	imageID, err := displayBuildOutput(strings.NewReader(stream), &out)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if imageID != "sha256:abc123" {
+		t.Errorf("Expected image ID sha256:abc123, got %q", imageID)
+	}
+	expected := "Step 1/2 : FROM alpine\nPulling from library/alpine\nSuccessfully tagged test:latest\n"
+	if out.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, out.String())
+	}
+}
+
+func TestDisplayBuildOutputError(t *testing.T) {
+	stream := `{"stream":"Step 1/2 : RUN false\n"}
+{"error":"The command '/bin/sh -c false' returned a non-zero code: 1"}
+`
+	_, err := displayBuildOutput(strings.NewReader(stream), io.Discard)
+	if err == nil || !strings.Contains(err.Error(), "non-zero code") {
+		t.Errorf("Expected build error, got %v", err)
+	}
+}
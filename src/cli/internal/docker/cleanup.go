@@ -39,8 +39,7 @@ func IsRunningInContainer() bool {
 
 // CleanupChildContainers stops all containers that were started from within this container
 // This is useful for Docker-in-Docker scenarios where the parent container starts child containers
-func (ch *ContainerHost) CleanupChildContainers() error {
-	ctx := context.Background()
+func (ch *ContainerHost) CleanupChildContainers(ctx context.Context) error {
 	log := logger.Get()
 
 	// Get our own container ID if we're running in a container
@@ -140,8 +139,7 @@ func (ch *ContainerHost) CleanupChildContainers() error {
 }
 
 // CleanupOrphanedContainers removes containers that match r2r-cli patterns but are no longer needed
-func (ch *ContainerHost) CleanupOrphanedContainers() error {
-	ctx := context.Background()
+func (ch *ContainerHost) CleanupOrphanedContainers(ctx context.Context) error {
 	log := logger.Get()
 
 	// Create filters for r2r-cli managed containers
@@ -0,0 +1,81 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/pkg/stdcopy"
+)
+
+// ExtensionContainer is a running extension container, started by r2r run
+// or as a background job
+type ExtensionContainer struct {
+	ID        string    `json:"id"`
+	Extension string    `json:"extension"`
+	Image     string    `json:"image"`
+	JobID     string    `json:"job_id,omitempty"`
+	State     string    `json:"state"`
+	Created   time.Time `json:"created"`
+}
+
+// ListExtensionContainers returns the running extension containers of all
+// r2r processes, oldest first
+func (ch *ContainerHost) ListExtensionContainers(ctx context.Context) ([]ExtensionContainer, error) {
+	filterArgs := filters.NewArgs()
+	filterArgs.Add("label", ExtensionLabel)
+
+	containers, err := ch.client.ContainerList(ctx, container.ListOptions{Filters: filterArgs})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list containers: %w", err)
+	}
+
+	result := make([]ExtensionContainer, 0, len(containers))
+	for _, cont := range containers {
+		result = append(result, ExtensionContainer{
+			ID:        cont.ID,
+			Extension: cont.Labels[ExtensionLabel],
+			Image:     cont.Image,
+			JobID:     cont.Labels[JobLabel],
+			State:     cont.State,
+			Created:   time.Unix(cont.Created, 0).UTC(),
+		})
+	}
+	sort.SliceStable(result, func(i, j int) bool { return result[i].Created.Before(result[j].Created) })
+	return result, nil
+}
+
+// ContainerOutput copies the output of any container to w, stdout and stderr
+// interleaved. With follow it returns once the container has exited or ctx
+// is cancelled.
+func (ch *ContainerHost) ContainerOutput(ctx context.Context, containerID string, follow bool, w io.Writer) error {
+	inspect, err := ch.client.ContainerInspect(ctx, containerID)
+	if err != nil {
+		return fmt.Errorf("error inspecting container: %w", err)
+	}
+
+	reader, err := ch.client.ContainerLogs(ctx, containerID, container.LogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+		Follow:     follow,
+	})
+	if err != nil {
+		return fmt.Errorf("error reading container logs: %w", err)
+	}
+	defer reader.Close()
+
+	// Only containers without a TTY multiplex their streams
+	if inspect.Config != nil && inspect.Config.Tty {
+		_, err = io.Copy(w, reader)
+	} else {
+		_, err = stdcopy.StdCopy(w, w, reader)
+	}
+	if err != nil && ctx.Err() == nil {
+		return fmt.Errorf("error reading container logs: %w", err)
+	}
+	return ctx.Err()
+}
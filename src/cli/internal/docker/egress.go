@@ -0,0 +1,295 @@
+package docker
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/ready-to-release/eac/src/cli/internal/conf"
+	"github.com/rs/zerolog/log"
+)
+
+// Labels describing the egress controls of a container. EgressLabel holds
+// the allow-list, comma separated; an empty value allows no outbound traffic.
+const (
+	EgressLabel            = "r2r.egress"
+	EgressHelperImageLabel = "r2r.egress.helper_image"
+	// EgressHelperLabel marks a helper container; its value is the name of
+	// the extension it restricts
+	EgressHelperLabel = "r2r.egress.helper"
+)
+
+// DefaultHelperImage is the image of the helper containers that set up
+// egress controls, unless an extension sets egress.helper_image, and that
+// fix the ownership of files extensions created
+const DefaultHelperImage = "alpine:3.20"
+
+// egressReadyMarker is printed by the helper once its rules are in place
+const egressReadyMarker = "r2r-egress-ready"
+
+// egressSetupTimeout bounds the helper setup, which may install iptables
+const egressSetupTimeout = 2 * time.Minute
+
+// egressScript runs in the helper container. It drops all outbound traffic
+// of the network namespace except DNS and the allow-list in R2R_EGRESS_ALLOW
+// (entries "host" or "host:port"), then blocks on stdin. The helper exits,
+// and is removed, when r2r closes stdin or exits.
+const egressScript = `set -e
+command -v iptables >/dev/null 2>&1 || apk add --no-cache iptables >/dev/null
+iptables -F OUTPUT
+iptables -A OUTPUT -o lo -j ACCEPT
+iptables -A OUTPUT -m conntrack --ctstate ESTABLISHED,RELATED -j ACCEPT
+for ns in $(awk '/^nameserver/ {print $2}' /etc/resolv.conf); do
+  case "$ns" in *:*) continue ;; esac
+  iptables -A OUTPUT -d "$ns" -p udp --dport 53 -j ACCEPT
+  iptables -A OUTPUT -d "$ns" -p tcp --dport 53 -j ACCEPT
+done
+for entry in $R2R_EGRESS_ALLOW; do
+  host="${entry%:*}"; port="${entry##*:}"
+  [ "$host" = "$entry" ] && port=""
+  case "$host" in
+    *[!0-9./]*) addrs=$(getent ahostsv4 "$host" | awk '{print $1}' | sort -u) ;;
+    *) addrs="$host" ;;
+  esac
+  [ -n "$addrs" ] || { echo "cannot resolve $host" >&2; exit 1; }
+  for addr in $addrs; do
+    if [ -n "$port" ]; then
+      iptables -A OUTPUT -d "$addr" -p tcp --dport "$port" -j ACCEPT
+      iptables -A OUTPUT -d "$addr" -p udp --dport "$port" -j ACCEPT
+    else
+      iptables -A OUTPUT -d "$addr" -j ACCEPT
+    fi
+  done
+done
+iptables -P OUTPUT DROP
+if command -v ip6tables >/dev/null 2>&1; then
+  ip6tables -A OUTPUT -o lo -j ACCEPT 2>/dev/null || true
+  ip6tables -P OUTPUT DROP 2>/dev/null || true
+fi
+echo ` + egressReadyMarker + `
+cat >/dev/null
+`
+
+// egressLabels returns the labels that request egress controls for ext, or
+// nil when it has none
+func egressLabels(ext *ExtensionConfig) map[string]string {
+	if ext.Egress == nil {
+		return nil
+	}
+	labels := map[string]string{EgressLabel: strings.Join(ext.Egress.Allow, ",")}
+	if ext.Egress.HelperImage != "" {
+		labels[EgressHelperImageLabel] = ext.Egress.HelperImage
+	}
+	return labels
+}
+
+// egressAllowList converts an allow-list label to the entries of the helper
+// script, "host" or "host:port"
+func egressAllowList(label string) ([]string, error) {
+	var entries []string
+	for _, entry := range strings.Split(label, ",") {
+		if entry = strings.TrimSpace(entry); entry == "" {
+			continue
+		}
+		host, port, err := conf.ParseEgressEntry(entry)
+		if err != nil {
+			return nil, err
+		}
+		if port != 0 {
+			host += ":" + strconv.Itoa(port)
+		}
+		entries = append(entries, host)
+	}
+	return entries, nil
+}
+
+// egressHelper is a running helper container. Closing its attach connection
+// ends it.
+type egressHelper struct {
+	id     string
+	attach types.HijackedResponse
+}
+
+// restrictEgress starts the egress helper for a container whose
+// configuration requests egress controls and moves the container into the
+// helper's network namespace. The Docker socket is not mounted into
+// restricted containers, since it would let them start unrestricted ones.
+// It returns nil when the container has no egress controls.
+func (ch *ContainerHost) restrictEgress(ctx context.Context, containerConfig *container.Config, hostConfig *container.HostConfig) (*egressHelper, error) {
+	allow, ok := containerConfig.Labels[EgressLabel]
+	if !ok {
+		return nil, nil
+	}
+	entries, err := egressAllowList(allow)
+	if err != nil {
+		return nil, fmt.Errorf("invalid egress allow-list: %w", err)
+	}
+	helperImage := containerConfig.Labels[EgressHelperImageLabel]
+	if helperImage == "" {
+		helperImage = DefaultHelperImage
+	}
+
+	setupCtx, cancel := withTimeout(ctx, egressSetupTimeout)
+	defer cancel()
+
+	if err := ch.EnsureImageExists(setupCtx, helperImage, "IfNotPresent", false); err != nil {
+		return nil, fmt.Errorf("error preparing egress helper image: %w", err)
+	}
+
+	extension := containerConfig.Labels[ExtensionLabel]
+	resp, err := ch.client.ContainerCreate(setupCtx, &container.Config{
+		Image:      helperImage,
+		Entrypoint: []string{"/bin/sh", "-c", egressScript},
+		Cmd:        []string{},
+		Env:        []string{"R2R_EGRESS_ALLOW=" + strings.Join(entries, " ")},
+		OpenStdin:  true,
+		StdinOnce:  true,
+		Labels:     map[string]string{"r2r-cli": "", EgressHelperLabel: extension},
+	}, &container.HostConfig{
+		AutoRemove: true,
+		CapAdd:     []string{"NET_ADMIN"},
+	}, nil, nil, "")
+	if err != nil {
+		return nil, fmt.Errorf("error creating egress helper: %w", err)
+	}
+	helper := &egressHelper{id: resp.ID}
+
+	// The attach connection outlives the setup: closing it ends the helper
+	helper.attach, err = ch.client.ContainerAttach(context.WithoutCancel(ctx), resp.ID, container.AttachOptions{
+		Stream: true,
+		Stdin:  true,
+		Stdout: true,
+		Stderr: true,
+	})
+	if err != nil {
+		_ = ch.RemoveContainer(context.WithoutCancel(ctx), resp.ID)
+		return nil, fmt.Errorf("error attaching to egress helper: %w", err)
+	}
+	if err := ch.client.ContainerStart(setupCtx, resp.ID, container.StartOptions{}); err != nil {
+		ch.releaseEgress(context.WithoutCancel(ctx), helper)
+		return nil, fmt.Errorf("error starting egress helper: %w", err)
+	}
+	if err := waitForEgressHelper(setupCtx, helper.attach); err != nil {
+		ch.releaseEgress(context.WithoutCancel(ctx), helper)
+		return nil, err
+	}
+
+	log.Debug().Str("extension", extension).Strs("allow", entries).Str("helper", resp.ID[:12]).Msg("Egress controls in place")
+
+	hostConfig.NetworkMode = container.NetworkMode("container:" + resp.ID)
+	mounts := hostConfig.Mounts[:0]
+	for _, m := range hostConfig.Mounts {
+		if m.Target != "/var/run/docker.sock" {
+			mounts = append(mounts, m)
+		}
+	}
+	hostConfig.Mounts = mounts
+	return helper, nil
+}
+
+// waitForEgressHelper reads the helper's output until it reports its rules
+// are in place, and keeps draining it afterwards
+func waitForEgressHelper(ctx context.Context, attach types.HijackedResponse) error {
+	stdout, stdoutWriter := io.Pipe()
+	var stderr strings.Builder
+	go func() {
+		_, err := stdcopy.StdCopy(stdoutWriter, &limitedWriter{w: &stderr, n: 4096}, attach.Reader)
+		stdoutWriter.CloseWithError(err)
+	}()
+
+	ready := make(chan bool, 1)
+	go func() {
+		scanner := bufio.NewScanner(stdout)
+		for scanner.Scan() {
+			if strings.TrimSpace(scanner.Text()) == egressReadyMarker {
+				ready <- true
+				_, _ = io.Copy(io.Discard, stdout)
+				return
+			}
+		}
+		ready <- false
+	}()
+
+	select {
+	case ok := <-ready:
+		if !ok {
+			return fmt.Errorf("egress helper failed: %s", strings.TrimSpace(stderr.String()))
+		}
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("egress helper did not become ready: %w", ctx.Err())
+	}
+}
+
+// limitedWriter keeps the first n bytes written to it
+type limitedWriter struct {
+	w io.Writer
+	n int
+}
+
+func (l *limitedWriter) Write(p []byte) (int, error) {
+	if l.n > 0 {
+		keep := p
+		if len(keep) > l.n {
+			keep = keep[:l.n]
+		}
+		l.n -= len(keep)
+		_, _ = l.w.Write(keep)
+	}
+	return len(p), nil
+}
+
+// releaseEgress ends a helper: closing stdin lets it exit, and removing it
+// covers helpers that do not
+func (ch *ContainerHost) releaseEgress(ctx context.Context, helper *egressHelper) {
+	if helper == nil {
+		return
+	}
+	if helper.attach.Conn != nil {
+		helper.attach.Close()
+	}
+	if err := ch.RemoveContainer(ctx, helper.id); err != nil {
+		log.Debug().Err(err).Str("container_id", helper.id).Msg("Failed to remove egress helper")
+	}
+}
+
+// trackEgress remembers the helper of a container, to release it with the
+// container
+func (ch *ContainerHost) trackEgress(containerID string, helper *egressHelper) {
+	if helper == nil {
+		return
+	}
+	ch.egressMu.Lock()
+	defer ch.egressMu.Unlock()
+	if ch.egressHelpers == nil {
+		ch.egressHelpers = make(map[string]*egressHelper)
+	}
+	ch.egressHelpers[containerID] = helper
+}
+
+// releaseEgressOf releases the helper of a container, if it has one
+func (ch *ContainerHost) releaseEgressOf(ctx context.Context, containerID string) {
+	ch.egressMu.Lock()
+	helper := ch.egressHelpers[containerID]
+	delete(ch.egressHelpers, containerID)
+	ch.egressMu.Unlock()
+	ch.releaseEgress(ctx, helper)
+}
+
+// releaseAllEgress releases the helpers of all containers
+func (ch *ContainerHost) releaseAllEgress(ctx context.Context) {
+	ch.egressMu.Lock()
+	helpers := ch.egressHelpers
+	ch.egressHelpers = nil
+	ch.egressMu.Unlock()
+	for _, helper := range helpers {
+		ch.releaseEgress(ctx, helper)
+	}
+}
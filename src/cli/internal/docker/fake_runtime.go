@@ -0,0 +1,752 @@
+package docker
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/api/types/registry"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/errdefs"
+	"github.com/docker/docker/pkg/stdcopy"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// FakeProcess is what runs in a container of a FakeImage. It gets the
+// container's command and environment, writes its output and returns the exit
+// code. ctx is cancelled when the container is stopped; a process that does
+// not return within the stop timeout is killed with exit code 137.
+type FakeProcess func(ctx context.Context, run FakeRun) int
+
+// FakeRun is the I/O of one FakeProcess
+type FakeRun struct {
+	Cmd    []string
+	Env    []string
+	Stdin  io.Reader // empty unless the container has OpenStdin
+	Stdout io.Writer
+	Stderr io.Writer
+}
+
+// FakeImage is an image known to a FakeRuntime
+type FakeImage struct {
+	Name        string
+	Entrypoint  []string
+	RepoDigests []string
+	// Run is the process of the image's containers; nil exits with 0
+	Run FakeProcess
+	// Stats are the samples the stats stream of the image's containers
+	// returns once they have started
+	Stats []container.StatsResponse
+}
+
+// FakeContainer is a snapshot of a container created on a FakeRuntime
+type FakeContainer struct {
+	ID         string
+	Config     container.Config
+	HostConfig container.HostConfig
+	Started    bool
+	Running    bool
+	ExitCode   int
+	Removed    bool
+	Width      uint
+	Height     uint
+	Stdout     string
+	Stderr     string
+}
+
+// FakeRuntime is an in-memory ContainerRuntime. Containers run their image's
+// FakeProcess in a goroutine and stream output over the attach connection the
+// way Docker does: raw with a TTY, multiplexed without one. It is meant for
+// L0/L1 tests that exercise the ContainerHost without a Docker daemon.
+type FakeRuntime struct {
+	mu         sync.Mutex
+	images     map[string]FakeImage
+	registry   map[string]FakeImage
+	containers map[string]*fakeContainer
+	nextID     int
+	failures   map[string]error
+	pulls      []string
+}
+
+var _ ContainerRuntime = (*FakeRuntime)(nil)
+
+// NewFakeRuntime creates a FakeRuntime with the given local images
+func NewFakeRuntime(images ...FakeImage) *FakeRuntime {
+	f := &FakeRuntime{
+		images:     make(map[string]FakeImage),
+		registry:   make(map[string]FakeImage),
+		containers: make(map[string]*fakeContainer),
+		failures:   make(map[string]error),
+	}
+	for _, img := range images {
+		f.images[img.Name] = img
+	}
+	return f
+}
+
+// AddRegistryImage makes an image available to ImagePull without it being
+// present locally
+func (f *FakeRuntime) AddRegistryImage(img FakeImage) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.registry[img.Name] = img
+}
+
+// Fail makes every later call of the named method, e.g. "ContainerStart",
+// return err. A nil err clears the failure.
+func (f *FakeRuntime) Fail(method string, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err == nil {
+		delete(f.failures, method)
+		return
+	}
+	f.failures[method] = err
+}
+
+// Pulls returns the images pulled so far, in order
+func (f *FakeRuntime) Pulls() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.pulls...)
+}
+
+// Containers returns snapshots of all containers created so far, including
+// removed ones, in creation order
+func (f *FakeRuntime) Containers() []FakeContainer {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	all := make([]FakeContainer, 0, len(f.containers))
+	for _, c := range f.containers {
+		all = append(all, c.snapshot())
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].ID < all[j].ID })
+	return all
+}
+
+// Container returns a snapshot of one container
+func (f *FakeRuntime) Container(id string) (FakeContainer, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	c, ok := f.containers[id]
+	if !ok {
+		return FakeContainer{}, false
+	}
+	return c.snapshot(), true
+}
+
+// fakeContainer is the mutable state behind a FakeContainer. All fields are
+// guarded by the runtime's mutex.
+type fakeContainer struct {
+	FakeContainer
+	process    FakeProcess
+	entrypoint []string // the image's, used when the config sets none
+	stats      []container.StatsResponse
+	output     []fakeChunk
+	attached   []fakeAttach
+	startedAt  time.Time
+	finishedAt time.Time
+	cancel     context.CancelFunc
+	exited     chan struct{} // closed on exit, replaced on restart
+	removed    chan struct{}
+}
+
+type fakeChunk struct {
+	stream stdcopy.StdType
+	data   []byte
+}
+
+type fakeAttach struct {
+	conn   net.Conn
+	stdout io.Writer
+	stderr io.Writer
+	stdin  bool
+}
+
+func (c *fakeContainer) snapshot() FakeContainer {
+	s := c.FakeContainer
+	var stdout, stderr strings.Builder
+	for _, chunk := range c.output {
+		if chunk.stream == stdcopy.Stderr {
+			stderr.Write(chunk.data)
+		} else {
+			stdout.Write(chunk.data)
+		}
+	}
+	s.Stdout, s.Stderr = stdout.String(), stderr.String()
+	return s
+}
+
+func (c *fakeContainer) status() string {
+	switch {
+	case c.Running:
+		return "running"
+	case c.Started:
+		return "exited"
+	default:
+		return "created"
+	}
+}
+
+// failure returns the error configured with Fail; callers hold the mutex
+func (f *FakeRuntime) failure(method string) error {
+	return f.failures[method]
+}
+
+// lookup returns a container that has not been removed; callers hold the mutex
+func (f *FakeRuntime) lookup(id string) (*fakeContainer, error) {
+	c, ok := f.containers[id]
+	if !ok || c.Removed {
+		return nil, errdefs.NotFound(fmt.Errorf("No such container: %s", id))
+	}
+	return c, nil
+}
+
+func (f *FakeRuntime) Ping(ctx context.Context) (types.Ping, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.failure("Ping"); err != nil {
+		return types.Ping{}, err
+	}
+	return types.Ping{APIVersion: "fake", OSType: "linux"}, nil
+}
+
+func (f *FakeRuntime) Close() error {
+	return nil
+}
+
+func (f *FakeRuntime) ImageInspect(ctx context.Context, imageID string, inspectOpts ...client.ImageInspectOption) (image.InspectResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.failure("ImageInspect"); err != nil {
+		return image.InspectResponse{}, err
+	}
+	img, ok := f.images[imageID]
+	if !ok {
+		return image.InspectResponse{}, errdefs.NotFound(fmt.Errorf("No such image: %s", imageID))
+	}
+	return image.InspectResponse{
+		ID:          "sha256:" + img.Name,
+		RepoTags:    []string{img.Name},
+		RepoDigests: img.RepoDigests,
+		Config:      &container.Config{Entrypoint: img.Entrypoint},
+	}, nil
+}
+
+func (f *FakeRuntime) ImagePull(ctx context.Context, refStr string, options image.PullOptions) (io.ReadCloser, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.failure("ImagePull"); err != nil {
+		return nil, err
+	}
+	img, ok := f.registry[refStr]
+	if !ok {
+		return nil, errdefs.NotFound(fmt.Errorf("manifest unknown: %s", refStr))
+	}
+	f.images[refStr] = img
+	f.pulls = append(f.pulls, refStr)
+	return io.NopCloser(strings.NewReader(fmt.Sprintf(`{"status":"Status: Downloaded newer image for %s"}`+"\n", refStr))), nil
+}
+
+// ImageBuild registers the tagged images; they run no process
+func (f *FakeRuntime) ImageBuild(ctx context.Context, buildContext io.Reader, options types.ImageBuildOptions) (types.ImageBuildResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.failure("ImageBuild"); err != nil {
+		return types.ImageBuildResponse{}, err
+	}
+	if _, err := io.Copy(io.Discard, buildContext); err != nil {
+		return types.ImageBuildResponse{}, err
+	}
+	for _, tag := range options.Tags {
+		f.images[tag] = FakeImage{Name: tag}
+	}
+	body := `{"stream":"Successfully built fake\n"}` + "\n"
+	return types.ImageBuildResponse{Body: io.NopCloser(strings.NewReader(body)), OSType: "linux"}, nil
+}
+
+func (f *FakeRuntime) RegistryLogin(ctx context.Context, auth registry.AuthConfig) (registry.AuthenticateOKBody, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.failure("RegistryLogin"); err != nil {
+		return registry.AuthenticateOKBody{}, err
+	}
+	return registry.AuthenticateOKBody{Status: "Login Succeeded"}, nil
+}
+
+func (f *FakeRuntime) ContainerCreate(ctx context.Context, config *container.Config, hostConfig *container.HostConfig, networkingConfig *network.NetworkingConfig, platform *ocispec.Platform, containerName string) (container.CreateResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.failure("ContainerCreate"); err != nil {
+		return container.CreateResponse{}, err
+	}
+	img, ok := f.images[config.Image]
+	if !ok {
+		return container.CreateResponse{}, errdefs.NotFound(fmt.Errorf("No such image: %s", config.Image))
+	}
+
+	f.nextID++
+	c := &fakeContainer{
+		FakeContainer: FakeContainer{
+			ID:     fmt.Sprintf("%064x", f.nextID),
+			Config: *config,
+		},
+		process:    img.Run,
+		entrypoint: img.Entrypoint,
+		stats:      img.Stats,
+		exited:     make(chan struct{}),
+		removed:    make(chan struct{}),
+	}
+	if hostConfig != nil {
+		c.HostConfig = *hostConfig
+	}
+	f.containers[c.ID] = c
+	return container.CreateResponse{ID: c.ID}, nil
+}
+
+func (f *FakeRuntime) ContainerStart(ctx context.Context, containerID string, options container.StartOptions) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.failure("ContainerStart"); err != nil {
+		return err
+	}
+	c, err := f.lookup(containerID)
+	if err != nil {
+		return err
+	}
+	if c.Running {
+		return nil
+	}
+
+	if c.Started {
+		c.exited = make(chan struct{})
+	}
+	runCtx, cancel := context.WithCancel(context.Background())
+	c.Started, c.Running, c.ExitCode = true, true, 0
+	c.startedAt, c.cancel = time.Now(), cancel
+
+	stdin := io.Reader(strings.NewReader(""))
+	for _, a := range c.attached {
+		if a.stdin && c.Config.OpenStdin {
+			stdin = a.conn
+		}
+	}
+	entrypoint := []string(c.Config.Entrypoint)
+	if len(entrypoint) == 0 {
+		entrypoint = c.entrypoint
+	}
+	run := FakeRun{
+		Cmd:    append(append([]string(nil), entrypoint...), c.Config.Cmd...),
+		Env:    append([]string(nil), c.Config.Env...),
+		Stdin:  stdin,
+		Stdout: &fakeStream{runtime: f, container: c, stream: stdcopy.Stdout},
+		Stderr: &fakeStream{runtime: f, container: c, stream: stdcopy.Stderr},
+	}
+	process, exited := c.process, c.exited
+
+	go func() {
+		code := 0
+		if process != nil {
+			code = process(runCtx, run)
+		}
+		f.exit(c, exited, code)
+	}()
+	return nil
+}
+
+// exit records the end of a run unless the container was already killed
+func (f *FakeRuntime) exit(c *fakeContainer, exited chan struct{}, code int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !c.Running || c.exited != exited {
+		return
+	}
+	c.cancel()
+	c.Running, c.ExitCode, c.finishedAt = false, code, time.Now()
+
+	// Close the attach streams before reporting the exit, so readers see all
+	// output before a waiter returns
+	for _, a := range c.attached {
+		a.conn.Close()
+	}
+	c.attached = nil
+	close(exited)
+
+	if c.HostConfig.AutoRemove {
+		f.remove(c)
+	}
+}
+
+// remove marks a container removed; callers hold the mutex
+func (f *FakeRuntime) remove(c *fakeContainer) {
+	if c.Removed {
+		return
+	}
+	c.Removed = true
+	for _, a := range c.attached {
+		a.conn.Close()
+	}
+	c.attached = nil
+	close(c.removed)
+}
+
+// fakeStream writes one output stream of a running container to its logs and
+// attach connections
+type fakeStream struct {
+	runtime   *FakeRuntime
+	container *fakeContainer
+	stream    stdcopy.StdType
+}
+
+func (s *fakeStream) Write(p []byte) (int, error) {
+	s.runtime.mu.Lock()
+	s.container.output = append(s.container.output, fakeChunk{stream: s.stream, data: append([]byte(nil), p...)})
+	var writers []io.Writer
+	for _, a := range s.container.attached {
+		if s.stream == stdcopy.Stderr {
+			writers = append(writers, a.stderr)
+		} else {
+			writers = append(writers, a.stdout)
+		}
+	}
+	s.runtime.mu.Unlock()
+
+	// Attach connections are synchronous; write outside the lock and ignore
+	// readers that went away
+	for _, w := range writers {
+		_, _ = w.Write(p)
+	}
+	return len(p), nil
+}
+
+func (f *FakeRuntime) ContainerInspect(ctx context.Context, containerID string) (container.InspectResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.failure("ContainerInspect"); err != nil {
+		return container.InspectResponse{}, err
+	}
+	c, err := f.lookup(containerID)
+	if err != nil {
+		return container.InspectResponse{}, err
+	}
+
+	state := &container.State{
+		Status:   c.status(),
+		Running:  c.Running,
+		ExitCode: c.ExitCode,
+	}
+	if !c.startedAt.IsZero() {
+		state.StartedAt = c.startedAt.UTC().Format(time.RFC3339Nano)
+	}
+	if !c.finishedAt.IsZero() {
+		state.FinishedAt = c.finishedAt.UTC().Format(time.RFC3339Nano)
+	}
+	config := c.Config
+	hostConfig := c.HostConfig
+	return container.InspectResponse{
+		ContainerJSONBase: &container.ContainerJSONBase{
+			ID:         c.ID,
+			Image:      c.Config.Image,
+			State:      state,
+			HostConfig: &hostConfig,
+		},
+		Config: &config,
+	}, nil
+}
+
+func (f *FakeRuntime) ContainerResize(ctx context.Context, containerID string, options container.ResizeOptions) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.failure("ContainerResize"); err != nil {
+		return err
+	}
+	c, err := f.lookup(containerID)
+	if err != nil {
+		return err
+	}
+	if !c.Running {
+		return errdefs.Conflict(fmt.Errorf("container %s is not running", containerID))
+	}
+	c.Width, c.Height = options.Width, options.Height
+	return nil
+}
+
+// ContainerAttach returns one end of an in-memory connection. Attaching to a
+// container that is not running ends the stream immediately.
+func (f *FakeRuntime) ContainerAttach(ctx context.Context, containerID string, options container.AttachOptions) (types.HijackedResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.failure("ContainerAttach"); err != nil {
+		return types.HijackedResponse{}, err
+	}
+	c, err := f.lookup(containerID)
+	if err != nil {
+		return types.HijackedResponse{}, err
+	}
+
+	local, remote := net.Pipe()
+	response := types.HijackedResponse{Conn: local, Reader: bufio.NewReader(local)}
+	if c.Started && !c.Running {
+		remote.Close()
+		return response, nil
+	}
+
+	a := fakeAttach{conn: remote, stdout: io.Discard, stderr: io.Discard, stdin: options.Stdin}
+	if c.Config.Tty {
+		// A TTY merges stderr into the raw stream
+		if options.Stdout {
+			a.stdout = remote
+		}
+		if options.Stderr {
+			a.stderr = remote
+		}
+	} else {
+		if options.Stdout {
+			a.stdout = stdcopy.NewStdWriter(remote, stdcopy.Stdout)
+		}
+		if options.Stderr {
+			a.stderr = stdcopy.NewStdWriter(remote, stdcopy.Stderr)
+		}
+	}
+	c.attached = append(c.attached, a)
+	return response, nil
+}
+
+func (f *FakeRuntime) ContainerWait(ctx context.Context, containerID string, condition container.WaitCondition) (<-chan container.WaitResponse, <-chan error) {
+	statusCh := make(chan container.WaitResponse, 1)
+	errCh := make(chan error, 1)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.failure("ContainerWait"); err != nil {
+		errCh <- err
+		return statusCh, errCh
+	}
+	c, err := f.lookup(containerID)
+	if err != nil {
+		errCh <- err
+		return statusCh, errCh
+	}
+
+	// Like Docker, "not-running" is met at once by a container that is created
+	// or exited, while "next-exit" waits for the next run to end
+	var done <-chan struct{}
+	switch condition {
+	case container.WaitConditionRemoved:
+		done = c.removed
+	case container.WaitConditionNextExit:
+		done = c.exited
+	default:
+		if !c.Running {
+			statusCh <- container.WaitResponse{StatusCode: int64(c.ExitCode)}
+			return statusCh, errCh
+		}
+		done = c.exited
+	}
+
+	go func() {
+		select {
+		case <-done:
+			f.mu.Lock()
+			code := c.ExitCode
+			f.mu.Unlock()
+			statusCh <- container.WaitResponse{StatusCode: int64(code)}
+		case <-ctx.Done():
+			errCh <- ctx.Err()
+		}
+	}()
+	return statusCh, errCh
+}
+
+// ContainerStop cancels the process and kills it with exit code 137 if it has
+// not returned within the stop timeout
+func (f *FakeRuntime) ContainerStop(ctx context.Context, containerID string, options container.StopOptions) error {
+	f.mu.Lock()
+	if err := f.failure("ContainerStop"); err != nil {
+		f.mu.Unlock()
+		return err
+	}
+	c, err := f.lookup(containerID)
+	if err != nil || !c.Running {
+		f.mu.Unlock()
+		return err
+	}
+	c.cancel()
+	exited := c.exited
+	f.mu.Unlock()
+
+	timeout := 10 * time.Second
+	if options.Timeout != nil {
+		timeout = time.Duration(*options.Timeout) * time.Second
+	}
+	select {
+	case <-exited:
+		return nil
+	case <-time.After(timeout):
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.kill(c)
+	return nil
+}
+
+// kill ends a running container with exit code 137; callers hold the mutex
+func (f *FakeRuntime) kill(c *fakeContainer) {
+	if !c.Running {
+		return
+	}
+	c.cancel()
+	c.Running, c.ExitCode, c.finishedAt = false, 137, time.Now()
+	for _, a := range c.attached {
+		a.conn.Close()
+	}
+	c.attached = nil
+	close(c.exited)
+	if c.HostConfig.AutoRemove {
+		f.remove(c)
+	}
+}
+
+func (f *FakeRuntime) ContainerRemove(ctx context.Context, containerID string, options container.RemoveOptions) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.failure("ContainerRemove"); err != nil {
+		return err
+	}
+	c, err := f.lookup(containerID)
+	if err != nil {
+		return err
+	}
+	if c.Running {
+		if !options.Force {
+			return errdefs.Conflict(fmt.Errorf("cannot remove running container %s", containerID))
+		}
+		f.kill(c)
+	}
+	f.remove(c)
+	return nil
+}
+
+// ContainerList supports the All option and "label" filters of the form key
+// or key=value
+func (f *FakeRuntime) ContainerList(ctx context.Context, options container.ListOptions) ([]container.Summary, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.failure("ContainerList"); err != nil {
+		return nil, err
+	}
+
+	labelFilters := options.Filters.Get("label")
+	var summaries []container.Summary
+	for _, c := range f.containers {
+		if c.Removed || (!options.All && !c.Running) || !matchesLabels(c.Config.Labels, labelFilters) {
+			continue
+		}
+		summaries = append(summaries, container.Summary{
+			ID:      c.ID,
+			Image:   c.Config.Image,
+			Command: strings.Join(c.Config.Cmd, " "),
+			Labels:  c.Config.Labels,
+			State:   c.status(),
+		})
+	}
+	sort.Slice(summaries, func(i, j int) bool { return summaries[i].ID < summaries[j].ID })
+	return summaries, nil
+}
+
+func matchesLabels(labels map[string]string, filters []string) bool {
+	for _, filter := range filters {
+		key, value, hasValue := strings.Cut(filter, "=")
+		actual, ok := labels[key]
+		if !ok || (hasValue && actual != value) {
+			return false
+		}
+	}
+	return true
+}
+
+// ContainerLogs returns the output so far, multiplexed unless the container
+// has a TTY. With Follow the stream ends when the container exits.
+func (f *FakeRuntime) ContainerLogs(ctx context.Context, containerID string, options container.LogsOptions) (io.ReadCloser, error) {
+	f.mu.Lock()
+	if err := f.failure("ContainerLogs"); err != nil {
+		f.mu.Unlock()
+		return nil, err
+	}
+	c, err := f.lookup(containerID)
+	if err != nil {
+		f.mu.Unlock()
+		return nil, err
+	}
+	running, exited := c.Running, c.exited
+	f.mu.Unlock()
+
+	if options.Follow && running {
+		select {
+		case <-exited:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var buf bytes.Buffer
+	for _, chunk := range c.output {
+		if (chunk.stream == stdcopy.Stderr && !options.ShowStderr) || (chunk.stream == stdcopy.Stdout && !options.ShowStdout) {
+			continue
+		}
+		if c.Config.Tty {
+			buf.Write(chunk.data)
+		} else {
+			_, _ = stdcopy.NewStdWriter(&buf, chunk.stream).Write(chunk.data)
+		}
+	}
+	return io.NopCloser(&buf), nil
+}
+
+// ContainerStats returns the stats samples of the container's image, or a
+// single empty sample for a container that has not started. The stream ends
+// after the last sample.
+func (f *FakeRuntime) ContainerStats(ctx context.Context, containerID string, stream bool) (container.StatsResponseReader, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.failure("ContainerStats"); err != nil {
+		return container.StatsResponseReader{}, err
+	}
+	c, err := f.lookup(containerID)
+	if err != nil {
+		return container.StatsResponseReader{}, err
+	}
+
+	samples := c.stats
+	if !c.Started || len(samples) == 0 {
+		samples = []container.StatsResponse{{ID: c.ID}}
+	}
+	if !stream {
+		samples = samples[len(samples)-1:]
+	}
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	for _, sample := range samples {
+		if err := encoder.Encode(sample); err != nil {
+			return container.StatsResponseReader{}, err
+		}
+	}
+	return container.StatsResponseReader{Body: io.NopCloser(&buf), OSType: "linux"}, nil
+}
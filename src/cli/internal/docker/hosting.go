@@ -1,15 +1,14 @@
 package docker
 
 import (
+	"bytes"
 	"context"
-	"encoding/base64"
-	"encoding/json"
 	"fmt"
-	"io"
 	"os"
-	"os/exec"
+	"runtime"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/docker/docker/api/types"
@@ -18,8 +17,15 @@ import (
 	"github.com/docker/docker/api/types/mount"
 	"github.com/docker/docker/api/types/registry"
 	"github.com/docker/docker/client"
+	"github.com/docker/docker/errdefs"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/ready-to-release/eac/contracts"
+	"github.com/ready-to-release/eac/src/cli/internal/auth"
 	"github.com/ready-to-release/eac/src/cli/internal/conf"
+	"github.com/ready-to-release/eac/src/cli/internal/policy"
+	"github.com/ready-to-release/eac/src/cli/internal/progress"
 	"github.com/ready-to-release/eac/src/cli/internal/terminal"
+	"github.com/ready-to-release/eac/src/core/audit"
 	"github.com/rs/zerolog/log"
 )
 
@@ -31,6 +37,10 @@ const (
 	ModeInteractive
 )
 
+// MetadataSchemaVersionsEnv lists the metadata schema versions the CLI can
+// read, comma separated, for the extension-meta command to pick from
+const MetadataSchemaVersionsEnv = "R2R_METADATA_SCHEMA_VERSIONS"
+
 // ExtensionConfig holds the configuration for an extension
 type ExtensionConfig struct {
 	Name               string
@@ -39,18 +49,41 @@ type ExtensionConfig struct {
 	LoadLocal          bool
 	AutoRemoveChildren bool
 	Env                []conf.EnvVar
+	Egress             *conf.EgressConfig // nil without egress controls
+	RepoAccess         string             // rw, ro or none; read-write when empty
+	MaskPaths          []string           // relative to the repository root
+	RunAs              string             // image, host, root or uid[:gid]
+	FixOwnership       bool               // give created files to the user running r2r
+	UsernsMode         string             // e.g. "keep-id" on Podman
+
+	MetadataSchemaVersion string // Declared metadata schema version or range
 }
 
-// ContainerHost manages Docker container operations for extensions
+// ContainerHost manages Docker container operations for extensions. Every
+// operation takes a context; cancelling it aborts the Docker API call.
 type ContainerHost struct {
-	client  *client.Client
-	ctx     context.Context
-	rootDir string
+	client   ContainerRuntime
+	rootDir  string
+	config   *conf.Config
+	timeouts Timeouts
+	policies *policy.Set // the policies of the repository when nil
+
+	egressMu      sync.Mutex
+	egressHelpers map[string]*egressHelper // by the ID of the restricted container
 }
 
-// NewContainerHost creates a new ContainerHost instance
+// NewContainerHost creates a new ContainerHost instance using the configuration
+// loaded by conf.InitConfig.
+//
+// Deprecated: use NewContainerHostWithConfig.
 func NewContainerHost() (*ContainerHost, error) {
-	ctx := context.Background()
+	return NewContainerHostWithConfig(conf.Current())
+}
+
+// NewContainerHostWithConfig creates a new ContainerHost instance for cfg
+func NewContainerHostWithConfig(cfg *conf.Config) (*ContainerHost, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
 
 	// Configure Docker client options
 	clientOpts := []client.Opt{client.FromEnv}
@@ -91,15 +124,21 @@ func NewContainerHost() (*ContainerHost, error) {
 	}
 
 	return &ContainerHost{
-		client:  cli,
-		ctx:     ctx,
-		rootDir: rootDir,
+		client:   cli,
+		rootDir:  rootDir,
+		config:   cfg,
+		timeouts: TimeoutsFromConfig(cfg),
 	}, nil
 }
 
+// Config returns the configuration the host was created with
+func (ch *ContainerHost) Config() *conf.Config {
+	return ch.config
+}
+
 // ValidateExtensions checks if extensions are configured
 func (ch *ContainerHost) ValidateExtensions() error {
-	if len(conf.Global.Extensions) == 0 {
+	if len(ch.config.Extensions) == 0 {
 		return fmt.Errorf("config file does not contain any extensions. Please run 'r2r init' to initialize the configuration")
 	}
 	return nil
@@ -107,7 +146,7 @@ func (ch *ContainerHost) ValidateExtensions() error {
 
 // FindExtension locates an extension by name in the configuration
 func (ch *ContainerHost) FindExtension(name string) (*ExtensionConfig, error) {
-	for _, ext := range conf.Global.Extensions {
+	for _, ext := range ch.config.Extensions {
 		if ext.Name == name {
 			// Apply default ImagePullPolicy if not specified
 			imagePullPolicy := ext.ImagePullPolicy
@@ -116,6 +155,13 @@ func (ch *ContainerHost) FindExtension(name string) (*ExtensionConfig, error) {
 				imagePullPolicy = "AutoDetect"
 			}
 
+			// Ownership only differs from the host user on native Linux
+			// daemons; Docker Desktop maps it on bind mounts
+			fixOwnership := runtime.GOOS == "linux"
+			if ext.FixOwnership != nil {
+				fixOwnership = *ext.FixOwnership
+			}
+
 			// Note: Version extraction from image tag is not currently used
 			// but kept for potential future metadata operations
 
@@ -123,9 +169,17 @@ func (ch *ContainerHost) FindExtension(name string) (*ExtensionConfig, error) {
 				Name:               ext.Name,
 				Image:              ext.Image,
 				ImagePullPolicy:    imagePullPolicy,
-				LoadLocal:          conf.Global.LoadLocal,  // Use global LoadLocal flag
+				LoadLocal:          ch.config.LoadLocal || ext.LoadLocal, // Global flag or per extension
 				AutoRemoveChildren: ext.AutoRemoveChildren,
 				Env:                ext.Env,
+				Egress:             ext.Egress,
+				RepoAccess:         ch.config.EffectiveRepoAccess(ext),
+				MaskPaths:          ext.MaskPaths,
+				RunAs:              ch.config.EffectiveRunAs(ext),
+				FixOwnership:       fixOwnership,
+				UsernsMode:         ext.UsernsMode,
+
+				MetadataSchemaVersion: ext.MetadataSchemaVersion,
 			}
 
 
@@ -142,20 +196,15 @@ func (ch *ContainerHost) BuildEnvironmentVars(ext *ExtensionConfig) []string {
 		"R2R_HOST_REPOROOT=" + ch.rootDir,
 	}
 
-	// Add terminal dimensions
-	// First try to get from environment (if already set)
-	cols := os.Getenv("COLUMNS")
-	lines := os.Getenv("LINES")
+	caps := terminal.Detect()
 
-	// Always try to detect terminal size for better accuracy
-	if width, height, err := terminal.GetSize(); err == nil && width > 0 && height > 0 {
-		// Successfully detected terminal size
-		cols = strconv.Itoa(width)
-		lines = strconv.Itoa(height)
-		log.Debug().Int("detected_width", width).Int("detected_height", height).Msg("Terminal size detected")
-		envVars = append(envVars, "COLUMNS="+cols, "LINES="+lines, "R2R_TERMINAL_DETECTION=auto")
+	// Add terminal dimensions, detected or else from the environment
+	if caps.Width > 0 && caps.Height > 0 {
+		log.Debug().Int("detected_width", caps.Width).Int("detected_height", caps.Height).Msg("Terminal size detected")
+		envVars = append(envVars, "COLUMNS="+strconv.Itoa(caps.Width), "LINES="+strconv.Itoa(caps.Height), "R2R_TERMINAL_DETECTION=auto")
 	} else {
-		// Failed to detect, use environment or defaults
+		cols := os.Getenv("COLUMNS")
+		lines := os.Getenv("LINES")
 		if cols == "" {
 			cols = "80"
 		}
@@ -166,29 +215,25 @@ func (ch *ContainerHost) BuildEnvironmentVars(ext *ExtensionConfig) []string {
 		envVars = append(envVars, "COLUMNS="+cols, "LINES="+lines, "R2R_TERMINAL_DETECTION=default")
 	}
 
-	// 1. CI Environment Detection & Defaults
-	if ch.detectCIEnvironment() {
-		envVars = append(envVars, ch.getCIDefaults()...)
-	} else {
-		// 2. Inherit Current Shell Settings (when not in CI)
-		envVars = append(envVars, ch.getShellColorSettings()...)
-	}
+	// Colors follow the terminal capabilities: disabled in CI, otherwise
+	// inherited from the shell
+	envVars = append(envVars, caps.ContainerEnv()...)
 
-	// 3. Add global environment variables from config
-	if conf.Global.Environment != nil {
-		for _, env := range conf.Global.Environment.Global {
+	// 1. Add global environment variables from config
+	if ch.config.Environment != nil {
+		for _, env := range ch.config.Environment.Global {
 			envVars = append(envVars, env.Name+"="+env.Value)
 		}
 
 		// Add secrets from config (these get values from host environment)
-		for _, secret := range conf.Global.Environment.Secrets {
+		for _, secret := range ch.config.Environment.Secrets {
 			if value := os.Getenv(secret.Env); value != "" {
 				envVars = append(envVars, secret.Name+"="+value)
 			}
 		}
 	}
 
-	// 4. Always ensure GITHUB_USERNAME and GITHUB_TOKEN are available if set in host environment
+	// 2. Always ensure GITHUB_USERNAME and GITHUB_TOKEN are available if set in host environment
 	// This is critical for extensions that need to access GitHub Container Registry
 	if githubUsername := os.Getenv("GITHUB_USERNAME"); githubUsername != "" {
 		envVars = append(envVars, "GITHUB_USERNAME="+githubUsername)
@@ -197,7 +242,7 @@ func (ch *ContainerHost) BuildEnvironmentVars(ext *ExtensionConfig) []string {
 		envVars = append(envVars, "GITHUB_TOKEN="+githubToken)
 	}
 
-	// 5. Add extension-specific env vars (these can override defaults)
+	// 3. Add extension-specific env vars (these can override defaults)
 	for _, env := range ext.Env {
 		envVars = append(envVars, env.Name+"="+env.Value)
 	}
@@ -205,76 +250,15 @@ func (ch *ContainerHost) BuildEnvironmentVars(ext *ExtensionConfig) []string {
 	return envVars
 }
 
-// detectCIEnvironment checks multiple CI indicators beyond just CI=true
-func (ch *ContainerHost) detectCIEnvironment() bool {
-	ciIndicators := []string{
-		"CI", "CONTINUOUS_INTEGRATION",
-		"GITHUB_ACTIONS", "AZUREDEVOPS_URL", "GITLAB_CI",
-		"AZURE_HTTP_USER_AGENT", "TF_BUILD", "BUILDKITE",
-		"CIRCLECI", "TRAVIS", "DRONE", "SEMAPHORE",
-		"APPVEYOR", "CODEBUILD_BUILD_ID", "TEAMCITY_VERSION",
-	}
-
-	for _, indicator := range ciIndicators {
-		if value := os.Getenv(indicator); value != "" && value != "false" && value != "0" {
-			return true
-		}
-	}
-	return false
-}
-
-// getCIDefaults returns CI-appropriate environment settings
-func (ch *ContainerHost) getCIDefaults() []string {
-	return []string{
-		"NO_COLOR=1",    // Disable colors in CI
-		"TERM=dumb",     // Simple terminal for CI
-		"FORCE_COLOR=0", // Force disable color
-		"CI=true",       // Indicate CI environment
-	}
-}
-
-// getShellColorSettings inherits current shell color capabilities
-func (ch *ContainerHost) getShellColorSettings() []string {
-	envVars := []string{}
-
-	// Inherit color support from current shell
-	colorEnvVars := []string{
-		"TERM", "COLORTERM", "CLICOLOR", "CLICOLOR_FORCE",
-		"NO_COLOR", "FORCE_COLOR", "COLOR",
-	}
-
-	for _, envVar := range colorEnvVars {
-		if value := os.Getenv(envVar); value != "" {
-			envVars = append(envVars, envVar+"="+value)
-		}
-	}
-
-	// Apply sensible defaults if no color settings detected
-	if len(envVars) == 0 {
-		envVars = append(envVars, ch.getDefaultColorSettings()...)
-	}
-
-	return envVars
-}
-
-// getDefaultColorSettings provides fallback color settings for environments without explicit settings
-func (ch *ContainerHost) getDefaultColorSettings() []string {
-	// Detect terminal capabilities
-	term := os.Getenv("TERM")
-	if term == "" {
-		term = "xterm-256color" // Sensible default for modern terminals
-	}
-
-	return []string{
-		"TERM=" + term,
-		"COLORTERM=truecolor", // Modern terminal default supporting full color
-		// Don't set NO_COLOR or FORCE_COLOR - let programs decide based on their logic
-	}
+// ContainerEnv returns the environment an extension container receives: the
+// variables of the extension-env contract followed by those configured
+func (ch *ContainerHost) ContainerEnv(ext *ExtensionConfig) []string {
+	return append(ch.BuildEnvironmentVars(ext), userEnv(ext, containerUser(ext.RunAs))...)
 }
 
 // InspectImage inspects a Docker image and returns the inspection result
-func (ch *ContainerHost) InspectImage(image string) (*image.InspectResponse, error) {
-	imageInspect, err := ch.client.ImageInspect(ch.ctx, image)
+func (ch *ContainerHost) InspectImage(ctx context.Context, image string) (*image.InspectResponse, error) {
+	imageInspect, err := ch.client.ImageInspect(ctx, image)
 	if err != nil {
 		return nil, fmt.Errorf("error inspecting image: %w", err)
 	}
@@ -283,12 +267,15 @@ func (ch *ContainerHost) InspectImage(image string) (*image.InspectResponse, err
 
 // CreateContainerConfig creates a container configuration based on mode and extension
 func (ch *ContainerHost) CreateContainerConfig(ext *ExtensionConfig, mode ContainerMode, args []string, imageInspect *image.InspectResponse) *container.Config {
-	envVars := ch.BuildEnvironmentVars(ext)
-
 	config := &container.Config{
-		Image: ext.Image,
-		Env:   envVars,
+		Image:  ext.Image,
+		Env:    ch.ContainerEnv(ext),
+		Labels: map[string]string{ExtensionLabel: ext.Name},
+	}
+	for key, value := range egressLabels(ext) {
+		config.Labels[key] = value
 	}
+	config.User = containerUser(ext.RunAs)
 
 	switch mode {
 	case ModeInteractive:
@@ -318,7 +305,7 @@ func (ch *ContainerHost) CreateContainerConfig(ext *ExtensionConfig, mode Contai
 
 	// Only set WorkingDir if container does NOT have an entrypoint defined
 	if len(imageInspect.Config.Entrypoint) == 0 {
-		workdir := "/var/task"
+		workdir := WorkspaceDir
 		log.Debug().Str("workdir", workdir).Msg("No entrypoint found in extension container, setting workingdir")
 		config.WorkingDir = workdir
 	} else {
@@ -328,15 +315,11 @@ func (ch *ContainerHost) CreateContainerConfig(ext *ExtensionConfig, mode Contai
 	return config
 }
 
-// CreateHostConfig creates the host configuration with volume mounts
-func (ch *ContainerHost) CreateHostConfig() *container.HostConfig {
-	mounts := []mount.Mount{
-		{
-			Type:   mount.TypeBind,
-			Source: ch.rootDir,
-			Target: "/var/task",
-		},
-	}
+// CreateHostConfig creates the host configuration with volume mounts. The
+// repository is mounted at /var/task with the access of ext, and its masked
+// paths are hidden.
+func (ch *ContainerHost) CreateHostConfig(ext *ExtensionConfig) *container.HostConfig {
+	mounts := ch.repositoryMounts(ext)
 
 	// Add Docker service mount based on platform
 	dockerMount := ch.getDockerServiceMount()
@@ -344,10 +327,14 @@ func (ch *ContainerHost) CreateHostConfig() *container.HostConfig {
 		mounts = append(mounts, *dockerMount)
 	}
 
-	return &container.HostConfig{
+	hostConfig := &container.HostConfig{
 		AutoRemove: true,
 		Mounts:     mounts,
 	}
+	if ext != nil {
+		hostConfig.UsernsMode = container.UsernsMode(ext.UsernsMode)
+	}
+	return hostConfig
 }
 
 // getDockerServiceMount returns the appropriate Docker service mount for the current platform
@@ -361,12 +348,27 @@ func (ch *ContainerHost) getDockerServiceMount() *mount.Mount {
 	}
 }
 
-// CreateContainer creates a new Docker container with the specified configuration
-func (ch *ContainerHost) CreateContainer(containerConfig *container.Config, hostConfig *container.HostConfig) (string, error) {
-	resp, err := ch.client.ContainerCreate(ch.ctx, containerConfig, hostConfig, nil, nil, "")
+// CreateContainer creates a new Docker container with the specified
+// configuration. It fails when the container violates a policy in deny mode.
+func (ch *ContainerHost) CreateContainer(ctx context.Context, containerConfig *container.Config, hostConfig *container.HostConfig) (string, error) {
+	helper, err := ch.restrictEgress(ctx, containerConfig, hostConfig)
+	if err != nil {
+		return "", err
+	}
+	if err := ch.checkPolicies(containerConfig, hostConfig); err != nil {
+		ch.releaseEgress(context.WithoutCancel(ctx), helper)
+		return "", err
+	}
+
+	createCtx, cancel := withTimeout(ctx, ch.timeouts.Create)
+	defer cancel()
+
+	resp, err := ch.client.ContainerCreate(createCtx, containerConfig, hostConfig, nil, nil, "")
 	if err != nil {
+		ch.releaseEgress(context.WithoutCancel(ctx), helper)
 		return "", fmt.Errorf("error creating container: %w", err)
 	}
+	ch.trackEgress(resp.ID, helper)
 
 	// TTY resize will be done after container starts (in StartContainer)
 
@@ -374,25 +376,26 @@ func (ch *ContainerHost) CreateContainer(containerConfig *container.Config, host
 }
 
 // StartContainer starts a Docker container by ID
-func (ch *ContainerHost) StartContainer(containerID string) error {
-	if err := ch.client.ContainerStart(ch.ctx, containerID, container.StartOptions{}); err != nil {
+func (ch *ContainerHost) StartContainer(ctx context.Context, containerID string) error {
+	ctx, cancel := withTimeout(ctx, ch.timeouts.Start)
+	defer cancel()
+
+	if err := ch.client.ContainerStart(ctx, containerID, container.StartOptions{}); err != nil {
 		return fmt.Errorf("error starting container: %w", err)
 	}
 
 	// After starting, resize the TTY if needed
 	// Check if container has TTY enabled
-	inspect, err := ch.client.ContainerInspect(ch.ctx, containerID)
+	inspect, err := ch.client.ContainerInspect(ctx, containerID)
+	if err == nil {
+		audit.AddCurrentContainer(audit.Container{ID: containerID, Image: inspect.Config.Image, ImageID: inspect.Image})
+	} else {
+		audit.AddCurrentContainer(audit.Container{ID: containerID})
+	}
 	if err == nil && inspect.Config.Tty {
 		if width, height, err := terminal.GetSize(); err == nil && width > 0 && height > 0 {
-			log.Debug().Int("terminal_width", width).Int("terminal_height", height).Msg("Resizing container TTY after start")
-			resizeOptions := container.ResizeOptions{
-				Height: uint(height),
-				Width:  uint(width),
-			}
-			if err := ch.client.ContainerResize(ch.ctx, containerID, resizeOptions); err != nil {
+			if err := ch.ResizeContainer(ctx, containerID, width, height); err != nil {
 				log.Debug().Err(err).Msg("Failed to resize container TTY after start")
-			} else {
-				log.Debug().Msg("Successfully resized container TTY after start")
 			}
 		}
 	}
@@ -400,10 +403,20 @@ func (ch *ContainerHost) StartContainer(containerID string) error {
 	return nil
 }
 
+// ResizeContainer sets the TTY size of a running container. Sessions call it
+// again whenever the local terminal is resized.
+func (ch *ContainerHost) ResizeContainer(ctx context.Context, containerID string, width, height int) error {
+	log.Debug().Int("terminal_width", width).Int("terminal_height", height).Msg("Resizing container TTY")
+	return ch.client.ContainerResize(ctx, containerID, container.ResizeOptions{
+		Height: uint(height),
+		Width:  uint(width),
+	})
+}
+
 // AttachToContainer attaches to a container for I/O operations
-func (ch *ContainerHost) AttachToContainer(containerID string) (types.HijackedResponse, error) {
+func (ch *ContainerHost) AttachToContainer(ctx context.Context, containerID string) (types.HijackedResponse, error) {
 	// Inspect container to determine if stdin should be attached
-	inspect, err := ch.client.ContainerInspect(ch.ctx, containerID)
+	inspect, err := ch.client.ContainerInspect(ctx, containerID)
 	if err != nil {
 		return types.HijackedResponse{}, fmt.Errorf("error inspecting container: %w", err)
 	}
@@ -417,7 +430,7 @@ func (ch *ContainerHost) AttachToContainer(containerID string) (types.HijackedRe
 		Str("container_id", containerID).
 		Msg("Attaching to container with appropriate stdin setting")
 
-	attachResp, err := ch.client.ContainerAttach(ch.ctx, containerID, container.AttachOptions{
+	attachResp, err := ch.client.ContainerAttach(ctx, containerID, container.AttachOptions{
 		Stream: true,
 		Stdin:  attachStdin,
 		Stdout: true,
@@ -429,21 +442,69 @@ func (ch *ContainerHost) AttachToContainer(containerID string) (types.HijackedRe
 	return attachResp, nil
 }
 
-// WaitForContainer waits for a container to finish execution
-func (ch *ContainerHost) WaitForContainer(containerID string) (<-chan container.WaitResponse, <-chan error) {
-	return ch.client.ContainerWait(ch.ctx, containerID, container.WaitConditionNotRunning)
+// WaitForContainer waits for the next exit of a container. Call it before
+// StartContainer: a created container is already "not running", so only the
+// next-exit condition waits for the run. The wait is not bounded by a
+// timeout; cancel ctx to stop waiting.
+func (ch *ContainerHost) WaitForContainer(ctx context.Context, containerID string) (<-chan container.WaitResponse, <-chan error) {
+	return ch.client.ContainerWait(ctx, containerID, container.WaitConditionNextExit)
+}
+
+// StopContainer stops a running container. Docker sends SIGTERM, then SIGKILL
+// once the stop timeout has passed.
+func (ch *ContainerHost) StopContainer(ctx context.Context, containerID string) error {
+	seconds := int(ch.timeouts.Stop / time.Second)
+	ctx, cancel := withTimeout(ctx, ch.timeouts.Stop+5*time.Second)
+	defer cancel()
+
+	return ch.client.ContainerStop(ctx, containerID, container.StopOptions{Timeout: &seconds})
+}
+
+// ContainerExit describes how a container ended
+type ContainerExit struct {
+	StatusCode int
+	OOMKilled  bool
+	// Resources is what the container used, nil when no stats were sampled
+	Resources *ResourceUsage
+}
+
+// InspectExit returns the exit state of a stopped container. It needs the
+// container to still exist, so the container must not use AutoRemove.
+func (ch *ContainerHost) InspectExit(ctx context.Context, containerID string) (*ContainerExit, error) {
+	inspect, err := ch.client.ContainerInspect(ctx, containerID)
+	if err != nil {
+		return nil, fmt.Errorf("error inspecting container: %w", err)
+	}
+	if inspect.State == nil {
+		return nil, fmt.Errorf("container %s has no state", containerID)
+	}
+	return &ContainerExit{
+		StatusCode: inspect.State.ExitCode,
+		OOMKilled:  inspect.State.OOMKilled,
+	}, nil
 }
 
-// StopContainer stops a running container
-func (ch *ContainerHost) StopContainer(containerID string) error {
-	return ch.client.ContainerStop(ch.ctx, containerID, container.StopOptions{})
+// RemoveContainer force-removes a container and its egress helper. A
+// container that is already gone, e.g. through AutoRemove, is not an error.
+func (ch *ContainerHost) RemoveContainer(ctx context.Context, containerID string) error {
+	defer ch.releaseEgressOf(ctx, containerID)
+	err := ch.client.ContainerRemove(ctx, containerID, container.RemoveOptions{Force: true})
+	if err != nil && !errdefs.IsNotFound(err) && !errdefs.IsConflict(err) {
+		return fmt.Errorf("error removing container: %w", err)
+	}
+	return nil
 }
 
-// StopContainerWithContext stops a running container with a specific context for timeout control
-func (ch *ContainerHost) StopContainerWithContext(ctx context.Context, containerID string) error {
-	// Docker will send SIGTERM first, then SIGKILL after the timeout
-	// The default timeout is 10 seconds, but we're using the context to control it
-	return ch.client.ContainerStop(ctx, containerID, container.StopOptions{})
+// ShutdownContainer stops and removes a container after ctx was cancelled,
+// e.g. on Ctrl-C. It runs on a fresh context bounded by the stop timeout.
+func (ch *ContainerHost) ShutdownContainer(ctx context.Context, containerID string) error {
+	cleanupCtx, cancel := ch.cleanupContext(ctx)
+	defer cancel()
+
+	if err := ch.StopContainer(cleanupCtx, containerID); err != nil && !errdefs.IsNotFound(err) {
+		log.Debug().Err(err).Str("container_id", containerID).Msg("Graceful stop failed, forcing removal")
+	}
+	return ch.RemoveContainer(cleanupCtx, containerID)
 }
 
 // GetRootDir returns the root directory path
@@ -452,57 +513,41 @@ func (ch *ContainerHost) GetRootDir() string {
 }
 
 // CreateGitHubAuthConfig creates authentication configuration for GitHub Container Registry
+// from the shared credential manager, see the auth package for the sources.
 // Returns both the registry.AuthConfig and base64-encoded auth string for Docker API calls
 func CreateGitHubAuthConfig() (*registry.AuthConfig, string, error) {
-	// Try multiple authentication sources in order of preference
-
-	// 1. First try environment variables (highest priority for CI/CD)
-	username := os.Getenv("GITHUB_USERNAME")
-	password := os.Getenv("GITHUB_TOKEN")
-
-	// 2. If no token in env, try GitHub CLI authentication
-	if password == "" {
-		log.Debug().Msg("No GITHUB_TOKEN found, trying GitHub CLI authentication")
-		token, ghUsername, err := getGitHubCLIAuth()
-		if err == nil && token != "" {
-			password = token
-			if username == "" && ghUsername != "" {
-				username = ghUsername
-			}
-			log.Info().Msg("Using GitHub CLI authentication for ghcr.io")
-		} else if err != nil {
-			log.Debug().Err(err).Msg("GitHub CLI authentication not available")
-		}
-	}
-
-	// If username is not set but we have a token, use a default username for GitHub Container Registry
-	if username == "" && password != "" {
-		username = "github-actions" // Generic username that works with personal access tokens and GITHUB_TOKEN
+	cred, err := auth.Default().Get(context.Background(), auth.GHCR)
+	if err != nil {
+		return nil, "", fmt.Errorf("authentication required: %w", err)
 	}
 
-	if password == "" {
-		return nil, "", fmt.Errorf("authentication required: GITHUB_TOKEN environment variable must be set or GitHub CLI must be authenticated (run 'gh auth login')")
+	authConfig := cred.AuthConfig()
+	authStr, err := cred.EncodedAuth()
+	if err != nil {
+		return nil, "", err
 	}
 
-	// Create authentication config
-	authConfig := &registry.AuthConfig{
-		Username:      username,
-		Password:      password,
-		ServerAddress: "ghcr.io",
-	}
+	return &authConfig, authStr, nil
+}
 
-	// Encode authentication for Docker API
-	encodedJSON, err := json.Marshal(authConfig)
-	if err != nil {
-		return nil, "", fmt.Errorf("error encoding auth config: %w", err)
+// rejectedCredentials drops the cached GHCR credential when err shows the
+// registry rejected it, so the next attempt resolves it again, and adds a
+// hint on the scope pulls need
+func rejectedCredentials(err error) error {
+	msg := strings.ToLower(err.Error())
+	if !strings.Contains(msg, "unauthorized") && !strings.Contains(msg, "denied") {
+		return err
 	}
-	authStr := base64.StdEncoding.EncodeToString(encodedJSON)
-
-	return authConfig, authStr, nil
+	auth.Default().Invalidate(auth.GHCR)
+	return fmt.Errorf("%w (the token needs the %s scope; check it with 'r2r verify')", err, auth.ScopeReadPackages)
 }
 
-// EnsureImageExists checks if an image exists locally and pulls it based on the pull policy
-func (ch *ContainerHost) EnsureImageExists(imageName string, pullPolicy string, loadLocal bool) error {
+// EnsureImageExists checks if an image exists locally and pulls it based on the pull policy.
+// The whole operation, including the pull, is bounded by the pull timeout.
+func (ch *ContainerHost) EnsureImageExists(ctx context.Context, imageName string, pullPolicy string, loadLocal bool) error {
+	ctx, cancel := withTimeout(ctx, ch.timeouts.Pull)
+	defer cancel()
+
 	// Apply default if not specified
 	if pullPolicy == "" {
 		pullPolicy = "AutoDetect"
@@ -511,7 +556,7 @@ func (ch *ContainerHost) EnsureImageExists(imageName string, pullPolicy string,
 	// Handle "AutoDetect" policy - choose based on image tag and local availability
 	if pullPolicy == "AutoDetect" {
 		// First check if image exists locally
-		localImageInfo, err := ch.client.ImageInspect(ch.ctx, imageName)
+		localImageInfo, err := ch.client.ImageInspect(ctx, imageName)
 		hasLocalImage := err == nil
 
 		if hasLocalImage {
@@ -536,7 +581,7 @@ func (ch *ContainerHost) EnsureImageExists(imageName string, pullPolicy string,
 			// This is the most reliable indicator of a local build
 			if len(localImageInfo.RepoDigests) == 0 {
 				// Display to user that we're using a local build
-				fmt.Printf("🏠 Using local development image: %s\n", imageName)
+				progress.Println("🏠 Using local development image: %s", imageName)
 				log.Info().
 					Str("image", imageName).
 					Msg("Using local development image (AutoDetect: no registry digests)")
@@ -550,7 +595,7 @@ func (ch *ContainerHost) EnsureImageExists(imageName string, pullPolicy string,
 			// For specific version tags, check if it's a local build first (only if loadLocal is true)
 			if loadLocal && len(localImageInfo.RepoDigests) == 0 {
 				// Local build with version tag
-				fmt.Printf("🏠 Using local development image: %s\n", imageName)
+				progress.Println("🏠 Using local development image: %s", imageName)
 				log.Info().Str("image", imageName).Msg("Using local development image (AutoDetect: versioned local build)")
 				return nil
 			}
@@ -573,7 +618,7 @@ func (ch *ContainerHost) EnsureImageExists(imageName string, pullPolicy string,
 
 	// Handle "Never" policy - only use local image
 	if pullPolicy == "Never" {
-		_, err := ch.client.ImageInspect(ch.ctx, imageName)
+		_, err := ch.client.ImageInspect(ctx, imageName)
 		if err != nil {
 			return fmt.Errorf("image pull policy is 'Never' but image '%s' not found locally", imageName)
 		}
@@ -583,7 +628,7 @@ func (ch *ContainerHost) EnsureImageExists(imageName string, pullPolicy string,
 
 	// Handle "IfNotPresent" policy - check locally first
 	if pullPolicy == "IfNotPresent" {
-		_, err := ch.client.ImageInspect(ch.ctx, imageName)
+		_, err := ch.client.ImageInspect(ctx, imageName)
 		if err == nil {
 			// Image exists locally, no need to pull
 			log.Info().Str("image", imageName).Msg("Image already exists locally")
@@ -601,7 +646,7 @@ func (ch *ContainerHost) EnsureImageExists(imageName string, pullPolicy string,
 	}
 
 	// Check if Docker daemon is running before attempting login
-	_, pingErr := ch.client.Ping(ch.ctx)
+	_, pingErr := ch.client.Ping(ctx)
 	if pingErr != nil {
 		// Check for common Docker service not running errors
 		errStr := pingErr.Error()
@@ -615,7 +660,9 @@ func (ch *ContainerHost) EnsureImageExists(imageName string, pullPolicy string,
 	}
 
 	// Log in to registry
-	loginResp, err := ch.client.RegistryLogin(ch.ctx, *authConfig)
+	loginCtx, cancelLogin := withTimeout(ctx, ch.timeouts.Registry)
+	loginResp, err := ch.client.RegistryLogin(loginCtx, *authConfig)
+	cancelLogin()
 	if err != nil {
 		// Check if this is a Docker service issue
 		errStr := err.Error()
@@ -624,23 +671,27 @@ func (ch *ContainerHost) EnsureImageExists(imageName string, pullPolicy string,
 		   strings.Contains(errStr, "system cannot find the file specified") {
 			return fmt.Errorf("Docker service is not running. Please start Docker Desktop or the Docker daemon and try again")
 		}
-		return fmt.Errorf("error logging in to registry: %w", err)
+		return fmt.Errorf("error logging in to registry: %w", rejectedCredentials(err))
 	}
 	log.Info().Str("status", loginResp.Status).Msg("Successfully logged in to registry")
 
 	// Pull image with user feedback
-	fmt.Printf("🔍 Contacting registry for %s...\n", imageName)
-	reader, err := ch.client.ImagePull(ch.ctx, imageName, image.PullOptions{
+	contacting := progress.Spinner("Contacting registry for " + imageName)
+	reader, err := ch.client.ImagePull(ctx, imageName, image.PullOptions{
 		RegistryAuth: authStr,
 	})
+	contacting.Done("")
 	if err != nil {
-		return fmt.Errorf("error pulling image: %w", err)
+		return fmt.Errorf("error pulling image: %w", rejectedCredentials(err))
 	}
 	defer reader.Close()
 
 	// Display progress to user
 	if err := DisplayDockerProgress(reader); err != nil {
-		return fmt.Errorf("error during image pull: %w", err)
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return fmt.Errorf("error during image pull: %w", ctxErr)
+		}
+		return fmt.Errorf("error during image pull: %w", rejectedCredentials(err))
 	}
 
 	log.Info().Str("image", imageName).Msg("Successfully pulled image")
@@ -649,14 +700,14 @@ func (ch *ContainerHost) EnsureImageExists(imageName string, pullPolicy string,
 
 // ExecuteMetadataCommand executes the "extension-meta" command in an extension container
 // and returns the raw YAML output string or an error
-func (ch *ContainerHost) ExecuteMetadataCommand(ext *ExtensionConfig) (string, error) {
+func (ch *ContainerHost) ExecuteMetadataCommand(ctx context.Context, ext *ExtensionConfig) (string, error) {
 	// Ensure image exists locally (pull if necessary)
-	if err := ch.EnsureImageExists(ext.Image, ext.ImagePullPolicy, ext.LoadLocal); err != nil {
+	if err := ch.EnsureImageExists(ctx, ext.Image, ext.ImagePullPolicy, ext.LoadLocal); err != nil {
 		return "", fmt.Errorf("error ensuring image exists: %w", err)
 	}
 
 	// Inspect image to get configuration
-	imageInspect, err := ch.InspectImage(ext.Image)
+	imageInspect, err := ch.InspectImage(ctx, ext.Image)
 	if err != nil {
 		return "", fmt.Errorf("error inspecting image: %w", err)
 	}
@@ -668,16 +719,19 @@ func (ch *ContainerHost) ExecuteMetadataCommand(ext *ExtensionConfig) (string, e
 	containerConfig.Tty = false
 	containerConfig.OpenStdin = false
 
-	hostConfig := ch.CreateHostConfig()
+	// Tell the extension which metadata schema versions this CLI can read
+	containerConfig.Env = append(containerConfig.Env, MetadataSchemaVersionsEnv+"="+strings.Join(contracts.MetadataSchemaVersions(), ","))
+
+	hostConfig := ch.CreateHostConfig(ext)
 
 	// Create container
-	containerID, err := ch.CreateContainer(containerConfig, hostConfig)
+	containerID, err := ch.CreateContainer(ctx, containerConfig, hostConfig)
 	if err != nil {
 		return "", fmt.Errorf("error creating container: %w", err)
 	}
 
 	// Attach to container to capture output
-	attachResp, err := ch.client.ContainerAttach(ch.ctx, containerID, container.AttachOptions{
+	attachResp, err := ch.client.ContainerAttach(ctx, containerID, container.AttachOptions{
 		Stream: true,
 		Stdout: true,
 		Stderr: true,
@@ -688,34 +742,45 @@ func (ch *ContainerHost) ExecuteMetadataCommand(ext *ExtensionConfig) (string, e
 	defer attachResp.Close()
 
 	// Start container
-	if err := ch.StartContainer(containerID); err != nil {
+	if err := ch.StartContainer(ctx, containerID); err != nil {
+		_ = ch.ShutdownContainer(ctx, containerID)
 		return "", fmt.Errorf("error starting container: %w", err)
 	}
 
-	// Create timeout context (60 seconds)
-	timeoutCtx, cancel := context.WithTimeout(ch.ctx, 60*time.Second)
+	// Bound the command by the metadata timeout
+	timeoutCtx, cancel := withTimeout(ctx, ch.timeouts.Metadata)
 	defer cancel()
 
 	// Wait for container to finish with timeout
 	statusCh, errCh := ch.client.ContainerWait(timeoutCtx, containerID, container.WaitConditionNotRunning)
 
-	// Capture output
+	// Capture output. The container has no TTY, so stdout and stderr are
+	// multiplexed; the metadata is on stdout, errors are reported on stderr.
 	outputChan := make(chan string, 1)
+	stderrChan := make(chan string, 1)
 	errorChan := make(chan error, 1)
 
 	go func() {
-		output, err := io.ReadAll(attachResp.Reader)
-		if err != nil {
+		var stdout, stderr bytes.Buffer
+		if _, err := stdcopy.StdCopy(&stdout, &stderr, attachResp.Reader); err != nil {
 			errorChan <- fmt.Errorf("error reading output: %w", err)
 			return
 		}
-		outputChan <- string(output)
+		stderrChan <- stderr.String()
+		outputChan <- stdout.String()
 	}()
 
 	// Wait for container completion or timeout
 	select {
 	case err := <-errCh:
 		if err != nil {
+			if timeoutCtx.Err() != nil {
+				_ = ch.ShutdownContainer(timeoutCtx, containerID)
+				if ctx.Err() != nil {
+					return "", fmt.Errorf("extension-meta command cancelled: %w", ctx.Err())
+				}
+				return "", fmt.Errorf("extension-meta command timed out after %s", ch.timeouts.Metadata)
+			}
 			return "", fmt.Errorf("error waiting for container: %w", err)
 		}
 	case status := <-statusCh:
@@ -723,16 +788,22 @@ func (ch *ContainerHost) ExecuteMetadataCommand(ext *ExtensionConfig) (string, e
 		if status.StatusCode != 0 {
 			// Try to get error output
 			select {
-			case output := <-outputChan:
-				return "", fmt.Errorf("extension-meta command failed with exit code %d: %s", status.StatusCode, output)
+			case errOutput := <-stderrChan:
+				if strings.TrimSpace(errOutput) == "" {
+					errOutput = <-outputChan
+				}
+				return "", fmt.Errorf("extension-meta command failed with exit code %d: %s", status.StatusCode, strings.TrimSpace(errOutput))
 			case <-time.After(1 * time.Second):
 				return "", fmt.Errorf("extension-meta command failed with exit code %d", status.StatusCode)
 			}
 		}
 	case <-timeoutCtx.Done():
-		// Timeout occurred, try to stop the container
-		_ = ch.StopContainer(containerID)
-		return "", fmt.Errorf("extension-meta command timed out after 60 seconds")
+		// Timeout or cancellation, stop and remove the container
+		_ = ch.ShutdownContainer(timeoutCtx, containerID)
+		if ctx.Err() != nil {
+			return "", fmt.Errorf("extension-meta command cancelled: %w", ctx.Err())
+		}
+		return "", fmt.Errorf("extension-meta command timed out after %s", ch.timeouts.Metadata)
 	}
 
 	// Get the output
@@ -746,48 +817,10 @@ func (ch *ContainerHost) ExecuteMetadataCommand(ext *ExtensionConfig) (string, e
 	}
 }
 
-// getGitHubCLIAuth attempts to get GitHub authentication from the GitHub CLI
-func getGitHubCLIAuth() (token string, username string, error error) {
-	// Try to get the token from gh CLI
-	cmd := exec.Command("gh", "auth", "token")
-	output, err := cmd.Output()
-	if err != nil {
-		return "", "", fmt.Errorf("failed to get GitHub CLI token: %w", err)
-	}
-
-	token = strings.TrimSpace(string(output))
-	if token == "" {
-		return "", "", fmt.Errorf("GitHub CLI returned empty token")
-	}
-
-	// Try to get the username from gh CLI status
-	cmd = exec.Command("gh", "auth", "status", "-h", "github.com")
-	output, err = cmd.Output()
-	if err == nil {
-		// Parse the output to find the username
-		lines := strings.Split(string(output), "\n")
-		for _, line := range lines {
-			if strings.Contains(line, "Logged in to github.com account") {
-				// Extract username from line like "✓ Logged in to github.com account USERNAME (GH_TOKEN)"
-				parts := strings.Fields(line)
-				for i, part := range parts {
-					if part == "account" && i+1 < len(parts) {
-						username = strings.TrimSuffix(parts[i+1], "(GH_TOKEN)")
-						username = strings.TrimSpace(username)
-						break
-					}
-				}
-			}
-		}
-	}
-
-	return token, username, nil
-}
-
 // Close closes the Docker client connection
 // GetContainerSnapshot returns a snapshot of currently running containers
-func (ch *ContainerHost) GetContainerSnapshot() (map[string]string, error) {
-	containers, err := ch.client.ContainerList(ch.ctx, container.ListOptions{
+func (ch *ContainerHost) GetContainerSnapshot(ctx context.Context) (map[string]string, error) {
+	containers, err := ch.client.ContainerList(ctx, container.ListOptions{
 		All: false, // Only running containers
 	})
 	if err != nil {
@@ -805,7 +838,7 @@ func (ch *ContainerHost) GetContainerSnapshot() (map[string]string, error) {
 
 // WarnAboutNewContainers compares before/after snapshots and warns about new containers
 // If autoRemove is true, it will stop and remove the containers instead of just warning
-func (ch *ContainerHost) WarnAboutNewContainers(beforeSnapshot, afterSnapshot map[string]string, extensionImage string, autoRemove bool) {
+func (ch *ContainerHost) WarnAboutNewContainers(ctx context.Context, beforeSnapshot, afterSnapshot map[string]string, extensionImage string, autoRemove bool) {
 	for containerID, image := range afterSnapshot {
 		if _, existed := beforeSnapshot[containerID]; !existed {
 			// Skip our own main container
@@ -821,14 +854,14 @@ func (ch *ContainerHost) WarnAboutNewContainers(beforeSnapshot, afterSnapshot ma
 					Msg("Auto-removing detected child container: " + image)
 
 				// Stop and remove the container
-				if err := ch.client.ContainerStop(ch.ctx, containerID, container.StopOptions{}); err != nil {
+				if err := ch.StopContainer(ctx, containerID); err != nil {
 					log.Warn().
 						Str("container_id", containerID[:12]).
 						Str("error", err.Error()).
 						Msg("Failed to stop child container")
 				}
 
-				if err := ch.client.ContainerRemove(ch.ctx, containerID, container.RemoveOptions{Force: true}); err != nil {
+				if err := ch.RemoveContainer(ctx, containerID); err != nil {
 					log.Warn().
 						Str("container_id", containerID[:12]).
 						Str("error", err.Error()).
@@ -849,6 +882,9 @@ func (ch *ContainerHost) WarnAboutNewContainers(beforeSnapshot, afterSnapshot ma
 	}
 }
 
+// Close releases the egress helpers of the host's containers and closes the
+// Docker client
 func (ch *ContainerHost) Close() error {
+	ch.releaseAllEgress(context.Background())
 	return ch.client.Close()
 }
@@ -0,0 +1,124 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/pkg/stdcopy"
+)
+
+// Labels identifying the container of a background job. They let a later r2r
+// process find the container again after the submitting process has exited.
+const (
+	JobLabel          = "r2r.job"
+	JobExtensionLabel = "r2r.job.extension"
+)
+
+// JobContainer is the Docker state of a background job container
+type JobContainer struct {
+	ID         string
+	JobID      string
+	Extension  string
+	Running    bool
+	ExitCode   int
+	OOMKilled  bool
+	StartedAt  time.Time
+	FinishedAt time.Time
+}
+
+// StartJobContainer creates and starts a detached container running an
+// extension command for a background job. The container has no terminal or
+// stdin and is kept after it exits, so its logs and exit state can be
+// collected later.
+func (ch *ContainerHost) StartJobContainer(ctx context.Context, ext *ExtensionConfig, args []string, jobID string) (string, error) {
+	// The egress helper lives as long as this process, not the job
+	if ext.Egress != nil {
+		return "", fmt.Errorf("extension %s has egress controls, which background jobs do not support yet", ext.Name)
+	}
+
+	imageInspect, err := ch.InspectImage(ctx, ext.Image)
+	if err != nil {
+		return "", fmt.Errorf("error inspecting image: %w", err)
+	}
+
+	containerConfig := ch.CreateContainerConfig(ext, ModeRun, args, imageInspect)
+	containerConfig.Tty = false
+	containerConfig.OpenStdin = false
+	containerConfig.Labels[JobLabel] = jobID
+	containerConfig.Labels[JobExtensionLabel] = ext.Name
+
+	hostConfig := ch.CreateHostConfig(ext)
+	hostConfig.AutoRemove = false
+
+	containerID, err := ch.CreateContainer(ctx, containerConfig, hostConfig)
+	if err != nil {
+		return "", err
+	}
+	if err := ch.StartContainer(ctx, containerID); err != nil {
+		_ = ch.RemoveContainer(context.WithoutCancel(ctx), containerID)
+		return "", err
+	}
+	return containerID, nil
+}
+
+// ListJobContainers returns the containers of all background jobs, running
+// or exited
+func (ch *ContainerHost) ListJobContainers(ctx context.Context) ([]JobContainer, error) {
+	filterArgs := filters.NewArgs()
+	filterArgs.Add("label", JobLabel)
+
+	containers, err := ch.client.ContainerList(ctx, container.ListOptions{
+		All:     true,
+		Filters: filterArgs,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list job containers: %w", err)
+	}
+
+	jobs := make([]JobContainer, 0, len(containers))
+	for _, cont := range containers {
+		inspect, err := ch.client.ContainerInspect(ctx, cont.ID)
+		if err != nil {
+			// Removed between listing and inspecting
+			continue
+		}
+		job := JobContainer{
+			ID:        cont.ID,
+			JobID:     cont.Labels[JobLabel],
+			Extension: cont.Labels[JobExtensionLabel],
+		}
+		if state := inspect.State; state != nil {
+			job.Running = state.Running || state.Restarting
+			job.ExitCode = state.ExitCode
+			job.OOMKilled = state.OOMKilled
+			job.StartedAt, _ = time.Parse(time.RFC3339Nano, state.StartedAt)
+			job.FinishedAt, _ = time.Parse(time.RFC3339Nano, state.FinishedAt)
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, nil
+}
+
+// JobLogs copies the output of a job container to stdout and stderr. With
+// follow it returns once the container has exited or ctx is cancelled.
+func (ch *ContainerHost) JobLogs(ctx context.Context, containerID string, follow bool, stdout, stderr io.Writer) error {
+	reader, err := ch.client.ContainerLogs(ctx, containerID, container.LogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+		Follow:     follow,
+	})
+	if err != nil {
+		return fmt.Errorf("error reading container logs: %w", err)
+	}
+	defer reader.Close()
+
+	// Job containers have no TTY, so the stream is multiplexed
+	if _, err := stdcopy.StdCopy(stdout, stderr, reader); err != nil && ctx.Err() == nil {
+		return fmt.Errorf("error reading container logs: %w", err)
+	}
+	return ctx.Err()
+}
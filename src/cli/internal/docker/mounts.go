@@ -0,0 +1,53 @@
+package docker
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/docker/docker/api/types/mount"
+	"github.com/ready-to-release/eac/src/cli/internal/conf"
+	"github.com/rs/zerolog/log"
+)
+
+// WorkspaceDir is where the repository is mounted in extension containers
+const WorkspaceDir = "/var/task"
+
+// repositoryMounts mounts the repository with the access of ext and hides
+// its masked paths: directories behind an empty tmpfs, files behind
+// /dev/null. Masked paths that do not exist are skipped.
+func (ch *ContainerHost) repositoryMounts(ext *ExtensionConfig) []mount.Mount {
+	access := conf.RepoAccessReadWrite
+	if ext != nil && ext.RepoAccess != "" {
+		access = ext.RepoAccess
+	}
+	if access == conf.RepoAccessNone {
+		return nil
+	}
+	readonly := access == conf.RepoAccessReadOnly
+
+	mounts := []mount.Mount{{
+		Type:     mount.TypeBind,
+		Source:   ch.rootDir,
+		Target:   WorkspaceDir,
+		ReadOnly: readonly,
+	}}
+	if ext == nil {
+		return mounts
+	}
+
+	for _, masked := range ext.MaskPaths {
+		info, err := os.Stat(filepath.Join(ch.rootDir, filepath.FromSlash(masked)))
+		if err != nil {
+			log.Debug().Str("path", masked).Msg("Masked path does not exist, nothing to hide")
+			continue
+		}
+		target := path.Join(WorkspaceDir, filepath.ToSlash(filepath.Clean(masked)))
+		if info.IsDir() {
+			mounts = append(mounts, mount.Mount{Type: mount.TypeTmpfs, Target: target, ReadOnly: readonly})
+		} else {
+			mounts = append(mounts, mount.Mount{Type: mount.TypeBind, Source: "/dev/null", Target: target, ReadOnly: true})
+		}
+	}
+	return mounts
+}
@@ -0,0 +1,76 @@
+package docker
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/ready-to-release/eac/src/cli/internal/conf"
+	"github.com/ready-to-release/eac/src/cli/internal/policy"
+	"github.com/rs/zerolog/log"
+)
+
+// ExtensionLabel names the extension a container runs. Policies evaluated
+// before container creation see it as extension.name.
+const ExtensionLabel = "r2r.extension"
+
+// containerPolicyInput describes the container about to be created the way
+// policy rules see extensions: with the settings Docker is given, not the
+// ones configured
+func containerPolicyInput(containerConfig *container.Config, hostConfig *container.HostConfig) policy.Extension {
+	in := policy.Extension{
+		Name:  containerConfig.Labels[ExtensionLabel],
+		Image: containerConfig.Image,
+		User:  containerConfig.User,
+	}
+	for _, env := range containerConfig.Env {
+		name, _, _ := strings.Cut(env, "=")
+		in.Env = append(in.Env, name)
+	}
+	if hostConfig == nil {
+		return in
+	}
+
+	in.Privileged = hostConfig.Privileged
+	in.NetworkMode = string(hostConfig.NetworkMode)
+	if hostConfig.NanoCPUs > 0 {
+		in.CPULimit = strconv.FormatFloat(float64(hostConfig.NanoCPUs)/1e9, 'f', -1, 64)
+	}
+	if hostConfig.Memory > 0 {
+		in.MemoryLimit = strconv.FormatInt(hostConfig.Memory, 10)
+	}
+	in.RepoAccess = conf.RepoAccessNone
+	for _, m := range hostConfig.Mounts {
+		in.Volumes = append(in.Volumes, policy.Volume{Host: m.Source, Container: m.Target, Readonly: m.ReadOnly})
+		if m.Target == WorkspaceDir {
+			in.RepoAccess = conf.RepoAccessReadWrite
+			if m.ReadOnly {
+				in.RepoAccess = conf.RepoAccessReadOnly
+			}
+		}
+	}
+	for _, bind := range hostConfig.Binds {
+		parts := strings.Split(bind, ":")
+		if len(parts) < 2 {
+			continue
+		}
+		readonly := len(parts) > 2 && strings.Contains(parts[2], "ro")
+		in.Volumes = append(in.Volumes, policy.Volume{Host: parts[0], Container: parts[1], Readonly: readonly})
+	}
+	return in
+}
+
+// checkPolicies evaluates the policies of the repository against the
+// container about to be created
+func (ch *ContainerHost) checkPolicies(containerConfig *container.Config, hostConfig *container.HostConfig) error {
+	policies := ch.policies
+	if policies == nil {
+		var err error
+		if policies, err = policy.Default(); err != nil {
+			return err
+		}
+	}
+	return policies.Enforce(policy.PhaseContainer, containerPolicyInput(containerConfig, hostConfig), func(v policy.Violation) {
+		log.Warn().Str("policy", v.Policy).Msg(v.String())
+	})
+}
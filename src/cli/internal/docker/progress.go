@@ -7,6 +7,7 @@ import (
 	"io"
 	"strings"
 
+	"github.com/ready-to-release/eac/src/cli/internal/progress"
 	"github.com/rs/zerolog/log"
 )
 
@@ -28,12 +29,33 @@ type DockerError struct {
 
 // DisplayDockerProgress reads Docker JSON progress and displays it to the user
 func DisplayDockerProgress(reader io.Reader) error {
+	return displayDockerProgress(reader, progress.Default())
+}
+
+// displayDockerProgress shows a bar per layer being downloaded or extracted.
+// Nothing is shown when all layers already exist locally.
+func displayDockerProgress(reader io.Reader, display *progress.Display) error {
 	scanner := bufio.NewScanner(reader)
-	layerProgress := make(map[string]string)
+	layers := make(map[string]*progress.Task)
 	lastStatus := ""
-	hasActualDownload := false // Track if we're actually downloading anything
+	hasActualDownload := false    // Track if we're actually downloading anything
 	showedPullingMessage := false // Track if we've shown the initial pulling message
-	dotCount := 0 // Track number of progress dots shown
+
+	// Layers still running when the stream ends or fails are removed
+	defer func() {
+		for _, layer := range layers {
+			layer.Done("")
+		}
+	}()
+
+	// showPulling shows the pulling message once something is downloaded
+	showPulling := func() {
+		if !showedPullingMessage && lastStatus != "" {
+			display.Println("📦 %s", lastStatus)
+			showedPullingMessage = true
+		}
+		hasActualDownload = true
+	}
 
 	for scanner.Scan() {
 		line := scanner.Text()
@@ -45,101 +67,64 @@ func DisplayDockerProgress(reader io.Reader) error {
 		}
 
 		// Parse as progress update
-		var progress DockerProgress
-		if err := json.Unmarshal([]byte(line), &progress); err != nil {
+		var update DockerProgress
+		if err := json.Unmarshal([]byte(line), &update); err != nil {
 			// If we can't parse it, log it as debug
 			log.Debug().Str("line", line).Msg("Unparseable Docker output")
 			continue
 		}
 
-		// Build status message
-		statusMsg := progress.Status
-
 		// Handle different status types
 		switch {
-		case strings.HasPrefix(progress.Status, "Pulling from"):
+		case strings.HasPrefix(update.Status, "Pulling from"):
 			// Don't show immediately - wait to see if we actually download
-			lastStatus = progress.Status
+			lastStatus = update.Status
 
-		case progress.Status == "Pulling fs layer" || progress.Status == "Waiting":
-			// Track layer but don't display yet
-			if progress.ID != "" {
-				layerProgress[progress.ID] = "⏳ Waiting"
+		case update.Status == "Downloading" || update.Status == "Extracting":
+			showPulling()
+			if update.ID == "" {
+				continue
 			}
-
-		case progress.Status == "Downloading":
-			// We have actual downloads - show the pulling message if not shown yet
-			if !showedPullingMessage && lastStatus != "" {
-				fmt.Printf("📦 %s\n", lastStatus)
-				fmt.Printf("Downloading image")
-				showedPullingMessage = true
+			message := update.Status + " " + shortLayerID(update.ID)
+			layer, ok := layers[update.ID]
+			if !ok {
+				layer = display.Bar(message, update.ProgressDetail.Total)
+				layers[update.ID] = layer
 			}
-			hasActualDownload = true
-			// Show progress as dots instead of detailed layer info
-			if progress.ID != "" {
-				layerProgress[progress.ID] = "downloading"
-				showProgressDot(&dotCount)
+			layer.SetMessage(message)
+			layer.SetTotal(update.ProgressDetail.Total)
+			layer.SetCurrent(update.ProgressDetail.Current)
+
+		case update.Status == "Pull complete":
+			if layer, ok := layers[update.ID]; ok {
+				layer.Done("")
+				delete(layers, update.ID)
 			}
 
-		case progress.Status == "Extracting":
-			// Extracting means we downloaded something
-			if !showedPullingMessage && lastStatus != "" {
-				fmt.Printf("📦 %s\n", lastStatus)
-				fmt.Printf("Downloading image")
-				showedPullingMessage = true
-			}
-			hasActualDownload = true
-			// Show progress as dots
-			if progress.ID != "" {
-				layerProgress[progress.ID] = "extracting"
-				showProgressDot(&dotCount)
-			}
-
-		case progress.Status == "Pull complete":
-			// Mark layer as complete and show dot
-			if progress.ID != "" {
-				layerProgress[progress.ID] = "complete"
-				showProgressDot(&dotCount)
-			}
-
-		case progress.Status == "Already exists":
+		case update.Status == "Already exists":
 			// Layer already exists locally - don't show progress for this
-			if progress.ID != "" {
-				log.Debug().Str("layer", progress.ID).Msg("Layer already exists")
+			if update.ID != "" {
+				log.Debug().Str("layer", update.ID).Msg("Layer already exists")
 			}
 
-		case strings.Contains(progress.Status, "Downloaded newer image"):
+		case strings.Contains(update.Status, "Downloaded newer image"):
 			// Show final status for actual downloads
 			if !showedPullingMessage && lastStatus != "" {
-				// Show the pulling message before the completion
-				fmt.Printf("📦 %s\n", lastStatus)
-			}
-			if dotCount > 0 {
-				fmt.Printf("\n") // End the dots line
+				display.Println("📦 %s", lastStatus)
 			}
-			fmt.Printf("✅ %s\n", progress.Status)
+			display.Println("✅ %s", update.Status)
 
-		case strings.Contains(progress.Status, "Image is up to date"):
-			// Only show "up to date" message if we showed pulling info
+		case strings.Contains(update.Status, "Image is up to date"):
+			// Only show "up to date" message if we showed pulling info;
+			// otherwise stay silent - image was already present
 			if hasActualDownload || showedPullingMessage {
-				if dotCount > 0 {
-					fmt.Printf("\n") // End the dots line
-				}
-				fmt.Printf("✅ %s\n", progress.Status)
-			}
-			// Otherwise stay silent - image was already present
-
-		case progress.Status == "Download complete":
-			// Layer download complete
-			if progress.ID != "" {
-				layerProgress[progress.ID] = fmt.Sprintf("💾 %s: Downloaded", progress.ID[:12])
+				display.Println("✅ %s", update.Status)
 			}
 
 		default:
-			// Other status messages
-			if statusMsg != "" && statusMsg != lastStatus {
-				log.Debug().Str("status", statusMsg).Msg("Docker pull status")
-				lastStatus = statusMsg
+			// Other status messages, such as "Waiting" and "Download complete"
+			if update.Status != "" && update.Status != lastStatus {
+				log.Debug().Str("status", update.Status).Str("layer", update.ID).Msg("Docker pull status")
 			}
 		}
 	}
@@ -151,11 +136,11 @@ func DisplayDockerProgress(reader io.Reader) error {
 	return nil
 }
 
-// showProgressDot displays a single dot to show progress, limiting to reasonable length
-func showProgressDot(dotCount *int) {
-	// Limit dots to avoid overly long lines (max ~50 dots)
-	if *dotCount < 50 {
-		fmt.Print(".")
-		*dotCount++
+// shortLayerID returns the first 12 characters of a layer ID, as docker
+// pull shows them
+func shortLayerID(id string) string {
+	if len(id) > 12 {
+		return id[:12]
 	}
+	return id
 }
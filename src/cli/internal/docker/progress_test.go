@@ -0,0 +1,51 @@
+//go:build L0
+// +build L0
+
+package docker
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/ready-to-release/eac/src/cli/internal/progress"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDisplayDockerProgress(t *testing.T) {
+	t.Run("download", func(t *testing.T) {
+		var buf bytes.Buffer
+		stream := strings.Join([]string{
+			`{"status":"Pulling from org/image","id":"latest"}`,
+			`{"status":"Pulling fs layer","id":"0123456789abcdef"}`,
+			`{"status":"Downloading","progressDetail":{"current":100,"total":1000},"id":"0123456789abcdef"}`,
+			`{"status":"Extracting","progressDetail":{"current":1000,"total":1000},"id":"0123456789abcdef"}`,
+			`{"status":"Pull complete","id":"0123456789abcdef"}`,
+			`{"status":"Status: Downloaded newer image for org/image:latest"}`,
+		}, "\n")
+		require.NoError(t, displayDockerProgress(strings.NewReader(stream), progress.New(&buf, progress.ModePlain)))
+		assert.Equal(t, "📦 Pulling from org/image\n✅ Status: Downloaded newer image for org/image:latest\n", buf.String())
+	})
+
+	t.Run("up to date", func(t *testing.T) {
+		var buf bytes.Buffer
+		stream := strings.Join([]string{
+			`{"status":"Pulling from org/image","id":"latest"}`,
+			`{"status":"Already exists","id":"0123456789abcdef"}`,
+			`{"status":"Status: Image is up to date for org/image:latest"}`,
+		}, "\n")
+		require.NoError(t, displayDockerProgress(strings.NewReader(stream), progress.New(&buf, progress.ModePlain)))
+		assert.Empty(t, buf.String(), "nothing is shown when no layer was downloaded")
+	})
+
+	t.Run("error", func(t *testing.T) {
+		var buf bytes.Buffer
+		stream := strings.Join([]string{
+			`{"status":"Downloading","progressDetail":{"current":100,"total":1000},"id":"0123456789abcdef"}`,
+			`{"error":"unauthorized"}`,
+		}, "\n")
+		err := displayDockerProgress(strings.NewReader(stream), progress.New(&buf, progress.ModePlain))
+		assert.EqualError(t, err, "docker error: unauthorized")
+	})
+}
@@ -0,0 +1,98 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// RunToCompletion runs an extension command without a terminal or stdin,
+// streams its output to stdout and stderr, and returns how it exited. The
+// container is removed afterwards. Cancelling ctx stops the container and
+// returns ctx's error.
+func (ch *ContainerHost) RunToCompletion(ctx context.Context, ext *ExtensionConfig, args []string, stdout, stderr io.Writer) (*ContainerExit, error) {
+	imageInspect, err := ch.InspectImage(ctx, ext.Image)
+	if err != nil {
+		return nil, fmt.Errorf("error inspecting image: %w", err)
+	}
+
+	containerConfig := ch.CreateContainerConfig(ext, ModeRun, args, imageInspect)
+	containerConfig.Tty = false
+	containerConfig.OpenStdin = false
+
+	hostConfig := ch.CreateHostConfig(ext)
+	// Keep the container until its exit state has been read
+	hostConfig.AutoRemove = false
+
+	containerID, err := ch.CreateContainer(ctx, containerConfig, hostConfig)
+	if err != nil {
+		return nil, fmt.Errorf("error creating container: %w", err)
+	}
+	cleanupCtx := context.WithoutCancel(ctx)
+	defer func() {
+		if err := ch.RemoveContainer(cleanupCtx, containerID); err != nil {
+			log.Debug().Err(err).Str("container_id", containerID).Msg("Failed to remove container")
+		}
+	}()
+
+	attachResp, err := ch.AttachToContainer(ctx, containerID)
+	if err != nil {
+		return nil, fmt.Errorf("error attaching to container: %w", err)
+	}
+	defer attachResp.Close()
+
+	statusCh, errCh := ch.WaitForContainer(ctx, containerID)
+
+	started := time.Now()
+	if err := ch.StartContainer(ctx, containerID); err != nil {
+		return nil, fmt.Errorf("error starting container: %w", err)
+	}
+	stats := ch.CollectStats(ctx, containerID)
+	defer stats.Stop()
+	expired, stopTimer := ch.RunTimer()
+	defer stopTimer()
+
+	session := ch.NewSession(containerID, attachResp, SessionOptions{Output: stdout, Error: stderr})
+	if err := session.Start(ctx); err != nil {
+		_ = ch.ShutdownContainer(ctx, containerID)
+		return nil, fmt.Errorf("error starting session: %w", err)
+	}
+	defer session.Close()
+
+	select {
+	case <-statusCh:
+	case err := <-errCh:
+		if ctx.Err() != nil {
+			_ = ch.ShutdownContainer(ctx, containerID)
+			return nil, ctx.Err()
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error waiting for container: %w", err)
+		}
+	case <-ctx.Done():
+		_ = ch.ShutdownContainer(ctx, containerID)
+		return nil, ctx.Err()
+	case <-expired:
+		_ = ch.ShutdownContainer(ctx, containerID)
+		return nil, &TimeoutError{Timeout: ch.timeouts.Run}
+	}
+
+	// The attach stream ends once the remaining output has been copied
+	select {
+	case <-session.Done():
+	case <-ctx.Done():
+	}
+
+	resources := stats.Stop()
+	exit, err := ch.InspectExit(cleanupCtx, containerID)
+	if exit != nil {
+		exit.Resources = resources
+	}
+	if err := ch.FixOwnership(cleanupCtx, ext, started); err != nil {
+		log.Warn().Err(err).Str("extension", ext.Name).Msg("Failed to fix the ownership of created files")
+	}
+	return exit, err
+}
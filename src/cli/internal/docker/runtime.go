@@ -0,0 +1,53 @@
+package docker
+
+import (
+	"context"
+	"io"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/api/types/registry"
+	"github.com/docker/docker/client"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/ready-to-release/eac/src/cli/internal/conf"
+)
+
+// ContainerRuntime is the part of the Docker API the ContainerHost uses. The
+// Docker client implements it; FakeRuntime implements it in memory, so the
+// run, exec and job logic can be tested without a Docker daemon.
+type ContainerRuntime interface {
+	Ping(ctx context.Context) (types.Ping, error)
+	Close() error
+
+	ImageInspect(ctx context.Context, imageID string, inspectOpts ...client.ImageInspectOption) (image.InspectResponse, error)
+	ImagePull(ctx context.Context, refStr string, options image.PullOptions) (io.ReadCloser, error)
+	ImageBuild(ctx context.Context, buildContext io.Reader, options types.ImageBuildOptions) (types.ImageBuildResponse, error)
+	RegistryLogin(ctx context.Context, auth registry.AuthConfig) (registry.AuthenticateOKBody, error)
+
+	ContainerCreate(ctx context.Context, config *container.Config, hostConfig *container.HostConfig, networkingConfig *network.NetworkingConfig, platform *ocispec.Platform, containerName string) (container.CreateResponse, error)
+	ContainerStart(ctx context.Context, containerID string, options container.StartOptions) error
+	ContainerInspect(ctx context.Context, containerID string) (container.InspectResponse, error)
+	ContainerResize(ctx context.Context, containerID string, options container.ResizeOptions) error
+	ContainerAttach(ctx context.Context, containerID string, options container.AttachOptions) (types.HijackedResponse, error)
+	ContainerWait(ctx context.Context, containerID string, condition container.WaitCondition) (<-chan container.WaitResponse, <-chan error)
+	ContainerStop(ctx context.Context, containerID string, options container.StopOptions) error
+	ContainerRemove(ctx context.Context, containerID string, options container.RemoveOptions) error
+	ContainerList(ctx context.Context, options container.ListOptions) ([]container.Summary, error)
+	ContainerLogs(ctx context.Context, containerID string, options container.LogsOptions) (io.ReadCloser, error)
+	ContainerStats(ctx context.Context, containerID string, stream bool) (container.StatsResponseReader, error)
+}
+
+var _ ContainerRuntime = (*client.Client)(nil)
+
+// NewContainerHostWithRuntime creates a ContainerHost on an existing runtime,
+// e.g. a FakeRuntime, with rootDir mounted as the workspace
+func NewContainerHostWithRuntime(runtime ContainerRuntime, rootDir string, cfg *conf.Config) *ContainerHost {
+	return &ContainerHost{
+		client:   runtime,
+		rootDir:  rootDir,
+		config:   cfg,
+		timeouts: TimeoutsFromConfig(cfg),
+	}
+}
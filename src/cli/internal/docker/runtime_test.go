@@ -0,0 +1,537 @@
+//go:build L1
+// +build L1
+
+package docker
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/ready-to-release/eac/src/cli/internal/conf"
+	"github.com/ready-to-release/eac/src/cli/internal/policy"
+)
+
+const fakeMetadata = `name: "test-extension"
+version: "1.0.0"
+commands:
+  test:
+    description: "Test command"
+`
+
+// metadataImage answers extension-meta with fakeMetadata and some noise on
+// stderr, and fails any other command
+func metadataImage(name string) FakeImage {
+	return FakeImage{
+		Name: name,
+		Run: func(ctx context.Context, run FakeRun) int {
+			if len(run.Cmd) == 0 || run.Cmd[len(run.Cmd)-1] != "extension-meta" {
+				fmt.Fprintln(run.Stderr, "unknown command")
+				return 127
+			}
+			fmt.Fprintln(run.Stderr, "loading extension")
+			fmt.Fprint(run.Stdout, fakeMetadata)
+			return 0
+		},
+	}
+}
+
+func newFakeHost(t *testing.T, images ...FakeImage) (*ContainerHost, *FakeRuntime) {
+	t.Helper()
+	runtime := NewFakeRuntime(images...)
+	return NewContainerHostWithRuntime(runtime, t.TempDir(), &conf.Config{}), runtime
+}
+
+func TestExecuteMetadataCommand(t *testing.T) {
+	ext := &ExtensionConfig{
+		Name:            "test-extension",
+		Image:           "test/extension:1.0.0",
+		ImagePullPolicy: "Never",
+	}
+
+	t.Run("returns stdout", func(t *testing.T) {
+		host, runtime := newFakeHost(t, metadataImage(ext.Image))
+
+		output, err := host.ExecuteMetadataCommand(context.Background(), ext)
+		if err != nil {
+			t.Fatalf("ExecuteMetadataCommand() error = %v", err)
+		}
+		if output != fakeMetadata {
+			t.Errorf("output = %q, want %q", output, fakeMetadata)
+		}
+
+		containers := runtime.Containers()
+		if len(containers) != 1 {
+			t.Fatalf("created %d containers, want 1", len(containers))
+		}
+		c := containers[0]
+		if c.Config.Tty || c.Config.OpenStdin {
+			t.Error("metadata container should have no TTY or stdin")
+		}
+		if !c.Removed {
+			t.Error("metadata container should be removed after it exits")
+		}
+		found := false
+		for _, env := range c.Config.Env {
+			found = found || strings.HasPrefix(env, MetadataSchemaVersionsEnv+"=")
+		}
+		if !found {
+			t.Errorf("container env does not set %s", MetadataSchemaVersionsEnv)
+		}
+	})
+
+	t.Run("reports stderr on failure", func(t *testing.T) {
+		host, _ := newFakeHost(t, FakeImage{
+			Name: ext.Image,
+			Run: func(ctx context.Context, run FakeRun) int {
+				fmt.Fprintln(run.Stderr, "extension-meta: not found")
+				return 127
+			},
+		})
+
+		_, err := host.ExecuteMetadataCommand(context.Background(), ext)
+		if err == nil {
+			t.Fatal("expected an error for a non-zero exit code")
+		}
+		want := "extension-meta command failed with exit code 127: extension-meta: not found"
+		if err.Error() != want {
+			t.Errorf("error = %q, want %q", err, want)
+		}
+	})
+
+	t.Run("times out", func(t *testing.T) {
+		host, runtime := newFakeHost(t, FakeImage{
+			Name: ext.Image,
+			Run: func(ctx context.Context, run FakeRun) int {
+				<-ctx.Done()
+				return 143
+			},
+		})
+		host.timeouts.Metadata = 50 * time.Millisecond
+
+		_, err := host.ExecuteMetadataCommand(context.Background(), ext)
+		if err == nil || !strings.Contains(err.Error(), "timed out") {
+			t.Fatalf("error = %v, want a timeout", err)
+		}
+		if c := runtime.Containers()[0]; c.Running || !c.Removed {
+			t.Error("timed out container should be stopped and removed")
+		}
+	})
+
+	errorCases := []struct {
+		name          string
+		images        []FakeImage
+		failMethod    string
+		expectedError string
+	}{
+		{
+			name:          "image missing",
+			expectedError: "error ensuring image exists",
+		},
+		{
+			name:          "container creation failure",
+			images:        []FakeImage{metadataImage(ext.Image)},
+			failMethod:    "ContainerCreate",
+			expectedError: "error creating container",
+		},
+		{
+			name:          "container start failure",
+			images:        []FakeImage{metadataImage(ext.Image)},
+			failMethod:    "ContainerStart",
+			expectedError: "error starting container",
+		},
+	}
+	for _, tc := range errorCases {
+		t.Run(tc.name, func(t *testing.T) {
+			host, runtime := newFakeHost(t, tc.images...)
+			if tc.failMethod != "" {
+				runtime.Fail(tc.failMethod, errors.New("injected failure"))
+			}
+
+			_, err := host.ExecuteMetadataCommand(context.Background(), ext)
+			if err == nil || !strings.Contains(err.Error(), tc.expectedError) {
+				t.Errorf("error = %v, want it to contain %q", err, tc.expectedError)
+			}
+		})
+	}
+}
+
+func TestRunToCompletion(t *testing.T) {
+	ext := &ExtensionConfig{Name: "go", Image: "test/go:1.0.0"}
+
+	t.Run("returns the exit code and splits output", func(t *testing.T) {
+		host, runtime := newFakeHost(t, FakeImage{
+			Name:       ext.Image,
+			Entrypoint: []string{"/entrypoint"},
+			Run: func(ctx context.Context, run FakeRun) int {
+				fmt.Fprintf(run.Stdout, "args: %s\n", strings.Join(run.Cmd, " "))
+				fmt.Fprintln(run.Stderr, "warning")
+				return 3
+			},
+		})
+
+		var stdout, stderr bytes.Buffer
+		exit, err := host.RunToCompletion(context.Background(), ext, []string{"test", "./..."}, &stdout, &stderr)
+		if err != nil {
+			t.Fatalf("RunToCompletion() error = %v", err)
+		}
+		if exit.StatusCode != 3 {
+			t.Errorf("StatusCode = %d, want 3", exit.StatusCode)
+		}
+		if got := stdout.String(); got != "args: /entrypoint test ./...\n" {
+			t.Errorf("stdout = %q", got)
+		}
+		if got := stderr.String(); got != "warning\n" {
+			t.Errorf("stderr = %q", got)
+		}
+
+		c := runtime.Containers()[0]
+		if c.Config.WorkingDir != "" {
+			t.Errorf("WorkingDir = %q, want none for an image with an entrypoint", c.Config.WorkingDir)
+		}
+		if !c.Removed {
+			t.Error("container should be removed after its exit state was read")
+		}
+	})
+
+	t.Run("stops the container when cancelled", func(t *testing.T) {
+		started := make(chan struct{})
+		host, runtime := newFakeHost(t, FakeImage{
+			Name: ext.Image,
+			Run: func(ctx context.Context, run FakeRun) int {
+				close(started)
+				<-ctx.Done()
+				return 143
+			},
+		})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		go func() {
+			<-started
+			cancel()
+		}()
+
+		_, err := host.RunToCompletion(ctx, ext, []string{"serve"}, io.Discard, io.Discard)
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("error = %v, want context.Canceled", err)
+		}
+		if c := runtime.Containers()[0]; c.Running || !c.Removed {
+			t.Error("cancelled container should be stopped and removed")
+		}
+	})
+
+	t.Run("stops a container exceeding the run timeout", func(t *testing.T) {
+		host, runtime := newFakeHost(t, FakeImage{
+			Name: ext.Image,
+			Run: func(ctx context.Context, run FakeRun) int {
+				<-ctx.Done()
+				return 143
+			},
+		})
+		host.SetRunTimeout(50 * time.Millisecond)
+
+		_, err := host.RunToCompletion(context.Background(), ext, []string{"serve"}, io.Discard, io.Discard)
+		var timeoutErr *TimeoutError
+		if !errors.As(err, &timeoutErr) || timeoutErr.Timeout != 50*time.Millisecond {
+			t.Fatalf("error = %v, want a TimeoutError", err)
+		}
+		if c := runtime.Containers()[0]; c.Running || !c.Removed {
+			t.Error("timed out container should be stopped and removed")
+		}
+	})
+}
+
+func TestResourceUsage(t *testing.T) {
+	ext := &ExtensionConfig{Name: "go", Image: "test/go:1.0.0"}
+	sample := func(cpu, memory, cache, read, write, rx uint64) container.StatsResponse {
+		return container.StatsResponse{
+			Read:     time.Now(),
+			CPUStats: container.CPUStats{CPUUsage: container.CPUUsage{TotalUsage: cpu}},
+			MemoryStats: container.MemoryStats{
+				Usage: memory,
+				Limit: 1 << 30,
+				Stats: map[string]uint64{"inactive_file": cache},
+			},
+			BlkioStats: container.BlkioStats{IoServiceBytesRecursive: []container.BlkioStatEntry{
+				{Op: "read", Value: read},
+				{Op: "write", Value: write},
+			}},
+			Networks: map[string]container.NetworkStats{"eth0": {RxBytes: rx, TxBytes: rx / 2}},
+		}
+	}
+	host, _ := newFakeHost(t, FakeImage{
+		Name: ext.Image,
+		Stats: []container.StatsResponse{
+			sample(500_000_000, 300<<20, 100<<20, 1<<20, 0, 4096),
+			sample(1_500_000_000, 260<<20, 10<<20, 2<<20, 3<<20, 8192),
+			// A stopped container reports zeroes
+			{},
+		},
+	})
+
+	exit, err := host.RunToCompletion(context.Background(), ext, nil, io.Discard, io.Discard)
+	if err != nil {
+		t.Fatalf("RunToCompletion() error = %v", err)
+	}
+	want := &ResourceUsage{
+		PeakMemoryBytes:  250 << 20,
+		MemoryLimitBytes: 1 << 30,
+		CPUSeconds:       1.5,
+		BlockReadBytes:   2 << 20,
+		BlockWriteBytes:  3 << 20,
+		NetworkRxBytes:   8192,
+		NetworkTxBytes:   4096,
+		Samples:          2,
+	}
+	if exit.Resources == nil || *exit.Resources != *want {
+		t.Fatalf("Resources = %+v, want %+v", exit.Resources, want)
+	}
+	if got := exit.Resources.String(); got != "peak memory 250.0 MiB, CPU time 1.50s, disk read 2.0 MiB / write 3.0 MiB, network rx 8.0 KiB / tx 4.0 KiB" {
+		t.Errorf("String() = %q", got)
+	}
+
+	t.Run("without samples", func(t *testing.T) {
+		host, _ := newFakeHost(t, FakeImage{Name: ext.Image})
+		exit, err := host.RunToCompletion(context.Background(), ext, nil, io.Discard, io.Discard)
+		if err != nil {
+			t.Fatalf("RunToCompletion() error = %v", err)
+		}
+		if exit.Resources != nil {
+			t.Errorf("Resources = %+v, want nil", exit.Resources)
+		}
+	})
+}
+
+func TestJobContainers(t *testing.T) {
+	ext := &ExtensionConfig{Name: "go", Image: "test/go:1.0.0"}
+	host, runtime := newFakeHost(t, FakeImage{
+		Name: ext.Image,
+		Run: func(ctx context.Context, run FakeRun) int {
+			fmt.Fprintln(run.Stdout, "built")
+			fmt.Fprintln(run.Stderr, "1 warning")
+			return 1
+		},
+	})
+	ctx := context.Background()
+
+	id, err := host.StartJobContainer(ctx, ext, []string{"build"}, "job-1")
+	if err != nil {
+		t.Fatalf("StartJobContainer() error = %v", err)
+	}
+
+	// Not a job: must not be listed
+	if _, err := runtime.ContainerCreate(ctx, &container.Config{Image: ext.Image}, nil, nil, nil, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	if err := host.JobLogs(ctx, id, true, &stdout, &stderr); err != nil {
+		t.Fatalf("JobLogs() error = %v", err)
+	}
+	if stdout.String() != "built\n" || stderr.String() != "1 warning\n" {
+		t.Errorf("logs = %q / %q", stdout.String(), stderr.String())
+	}
+
+	jobs, err := host.ListJobContainers(ctx)
+	if err != nil {
+		t.Fatalf("ListJobContainers() error = %v", err)
+	}
+	if len(jobs) != 1 {
+		t.Fatalf("listed %d jobs, want 1", len(jobs))
+	}
+	job := jobs[0]
+	if job.ID != id || job.JobID != "job-1" || job.Extension != "go" {
+		t.Errorf("job = %+v", job)
+	}
+	if job.Running || job.ExitCode != 1 || job.FinishedAt.IsZero() {
+		t.Errorf("job state = running %v, exit %d, finished %v", job.Running, job.ExitCode, job.FinishedAt)
+	}
+}
+
+func TestCreateContainerEnforcesPolicies(t *testing.T) {
+	ext := &ExtensionConfig{Name: "go", Image: "docker.io/library/golang:1.22"}
+	host, runtime := newFakeHost(t, FakeImage{Name: ext.Image, Run: func(ctx context.Context, run FakeRun) int { return 0 }})
+
+	policies, err := policy.Parse([]byte(`
+mode: deny
+policies:
+  - name: trusted-registry
+    rule: extension.registry == "ghcr.io" && extension.name == "go"
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	host.policies = policies
+
+	_, err = host.RunToCompletion(context.Background(), ext, []string{"build"}, io.Discard, io.Discard)
+	var denied *policy.DeniedError
+	if !errors.As(err, &denied) {
+		t.Fatalf("error = %v, want a policy violation", err)
+	}
+	if denied.Violations[0].Extension != "go" {
+		t.Errorf("violation = %+v, want the extension named by its label", denied.Violations[0])
+	}
+	if n := len(runtime.Containers()); n != 0 {
+		t.Errorf("created %d containers, want none", n)
+	}
+}
+
+func TestEgressControls(t *testing.T) {
+	ext := &ExtensionConfig{
+		Name:   "go",
+		Image:  "test/go:1.0.0",
+		Egress: &conf.EgressConfig{Allow: []string{"proxy.golang.org", "ghcr.io:443"}},
+	}
+	helperDone := make(chan struct{})
+	host, runtime := newFakeHost(t,
+		FakeImage{Name: ext.Image},
+		FakeImage{
+			Name: DefaultHelperImage,
+			Run: func(ctx context.Context, run FakeRun) int {
+				defer close(helperDone)
+				fmt.Fprintln(run.Stderr, "rules for", strings.Join(run.Env, " "))
+				fmt.Fprintln(run.Stdout, egressReadyMarker)
+				_, _ = io.Copy(io.Discard, run.Stdin)
+				return 0
+			},
+		},
+	)
+
+	exit, err := host.RunToCompletion(context.Background(), ext, []string{"build"}, io.Discard, io.Discard)
+	if err != nil {
+		t.Fatalf("RunToCompletion() error = %v", err)
+	}
+	if exit.StatusCode != 0 {
+		t.Errorf("StatusCode = %d", exit.StatusCode)
+	}
+
+	containers := runtime.Containers()
+	if len(containers) != 2 {
+		t.Fatalf("created %d containers, want the helper and the extension", len(containers))
+	}
+	helper, extension := containers[0], containers[1]
+	if got := strings.Join(helper.Config.Env, " "); got != "R2R_EGRESS_ALLOW=proxy.golang.org ghcr.io:443" {
+		t.Errorf("helper env = %q", got)
+	}
+	if string(extension.HostConfig.NetworkMode) != "container:"+helper.ID {
+		t.Errorf("NetworkMode = %q, want the helper's namespace", extension.HostConfig.NetworkMode)
+	}
+	for _, m := range extension.HostConfig.Mounts {
+		if m.Target == "/var/run/docker.sock" {
+			t.Error("the Docker socket must not be mounted into a restricted container")
+		}
+	}
+
+	select {
+	case <-helperDone:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the helper should exit once the extension container is removed")
+	}
+
+	t.Run("fails when the helper fails", func(t *testing.T) {
+		host, runtime := newFakeHost(t,
+			FakeImage{Name: ext.Image},
+			FakeImage{
+				Name: DefaultHelperImage,
+				Run: func(ctx context.Context, run FakeRun) int {
+					fmt.Fprintln(run.Stderr, "cannot resolve proxy.golang.org")
+					return 1
+				},
+			},
+		)
+		_, err := host.RunToCompletion(context.Background(), ext, []string{"build"}, io.Discard, io.Discard)
+		if err == nil || !strings.Contains(err.Error(), "cannot resolve proxy.golang.org") {
+			t.Fatalf("error = %v, want the helper's error", err)
+		}
+		if n := len(runtime.Containers()); n != 1 {
+			t.Errorf("created %d containers, want only the helper", n)
+		}
+	})
+
+	t.Run("background jobs are refused", func(t *testing.T) {
+		if _, err := host.StartJobContainer(context.Background(), ext, []string{"build"}, "job-1"); err == nil {
+			t.Error("StartJobContainer() should refuse an extension with egress controls")
+		}
+	})
+}
+
+func TestRunAsAndOwnership(t *testing.T) {
+	defer func(original func() (int, int)) { hostIDs = original }(hostIDs)
+	hostIDs = func() (int, int) { return 1000, 1001 }
+
+	t.Run("runs as the host user", func(t *testing.T) {
+		ext := &ExtensionConfig{Name: "go", Image: "test/go:1.0.0", RunAs: conf.RunAsHost, FixOwnership: true}
+		host, runtime := newFakeHost(t, FakeImage{Name: ext.Image})
+
+		if _, err := host.RunToCompletion(context.Background(), ext, []string{"build"}, io.Discard, io.Discard); err != nil {
+			t.Fatalf("RunToCompletion() error = %v", err)
+		}
+		containers := runtime.Containers()
+		if len(containers) != 1 {
+			t.Fatalf("created %d containers, want no ownership helper", len(containers))
+		}
+		c := containers[0]
+		if c.Config.User != "1000:1001" {
+			t.Errorf("User = %q, want 1000:1001", c.Config.User)
+		}
+		if !strings.Contains(strings.Join(c.Config.Env, " "), "HOME=/tmp") {
+			t.Errorf("Env = %v, want a writable HOME", c.Config.Env)
+		}
+	})
+
+	t.Run("fixes ownership after running as root", func(t *testing.T) {
+		ext := &ExtensionConfig{Name: "go", Image: "test/go:1.0.0", RunAs: conf.RunAsRoot, FixOwnership: true}
+		host, runtime := newFakeHost(t, FakeImage{Name: ext.Image}, FakeImage{Name: DefaultHelperImage})
+
+		if _, err := host.RunToCompletion(context.Background(), ext, []string{"build"}, io.Discard, io.Discard); err != nil {
+			t.Fatalf("RunToCompletion() error = %v", err)
+		}
+		containers := runtime.Containers()
+		if len(containers) != 2 {
+			t.Fatalf("created %d containers, want the extension and the ownership helper", len(containers))
+		}
+		helper := containers[1]
+		if helper.Config.Labels[OwnershipHelperLabel] != "go" || helper.Config.User != "0:0" || !helper.Removed {
+			t.Errorf("helper = %+v", helper)
+		}
+		env := strings.Join(helper.Config.Env, " ")
+		if !strings.HasPrefix(env, "R2R_UID=1000 R2R_GID=1001 R2R_SINCE=") || !strings.HasSuffix(env, " TZ=UTC") {
+			t.Errorf("helper env = %q", env)
+		}
+		// Only files modified since the extension started change owner
+		if script := strings.Join(helper.Config.Entrypoint, " "); !strings.Contains(script, "-newer /tmp/r2r-since") {
+			t.Errorf("helper script = %q, want files older than the run left alone", script)
+		}
+	})
+
+	t.Run("passes the start of the run", func(t *testing.T) {
+		ext := &ExtensionConfig{Name: "go", Image: "test/go:1.0.0", RunAs: conf.RunAsRoot, FixOwnership: true}
+		host, runtime := newFakeHost(t, FakeImage{Name: DefaultHelperImage})
+		since := time.Date(2026, 3, 4, 5, 6, 7, 0, time.FixedZone("CET", 3600))
+		if err := host.FixOwnership(context.Background(), ext, since); err != nil {
+			t.Fatal(err)
+		}
+		containers := runtime.Containers()
+		if len(containers) != 1 || !slices.Contains(containers[0].Config.Env, "R2R_SINCE=202603040406.07") {
+			t.Errorf("helpers = %+v, want R2R_SINCE in UTC", containers)
+		}
+	})
+
+	t.Run("skips a read-only repository", func(t *testing.T) {
+		ext := &ExtensionConfig{Name: "go", Image: "test/go:1.0.0", RunAs: conf.RunAsRoot, FixOwnership: true, RepoAccess: conf.RepoAccessReadOnly}
+		host, runtime := newFakeHost(t, FakeImage{Name: ext.Image})
+		if err := host.FixOwnership(context.Background(), ext, time.Now()); err != nil {
+			t.Fatal(err)
+		}
+		if n := len(runtime.Containers()); n != 0 {
+			t.Errorf("created %d containers, want none", n)
+		}
+	})
+}
@@ -0,0 +1,170 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/moby/term"
+	"github.com/ready-to-release/eac/src/cli/internal/terminal"
+	"github.com/rs/zerolog/log"
+)
+
+// SessionOptions configures how a Session connects local I/O to a container
+type SessionOptions struct {
+	// TTY must match the container's Tty setting. With a TTY the output is a
+	// single raw stream; without one stdout and stderr are multiplexed.
+	TTY bool
+	// Stdin forwards local input to the container; the container must have
+	// OpenStdin enabled
+	Stdin bool
+
+	Input  io.Reader // defaults to os.Stdin
+	Output io.Writer // defaults to os.Stdout
+	Error  io.Writer // defaults to os.Stderr
+}
+
+// Session streams the I/O of an attached container to the local terminal. When
+// input is forwarded to a TTY the local terminal is put in raw mode, and
+// terminal resizes are forwarded to the container until the session is closed.
+type Session struct {
+	host        *ContainerHost
+	containerID string
+	attach      types.HijackedResponse
+	opts        SessionOptions
+
+	inFd       uintptr
+	state      *term.State
+	stopResize context.CancelFunc
+	closeOnce  sync.Once
+	done       chan error
+
+	width, height int
+}
+
+// NewSession creates a session for a container attached with AttachToContainer
+func (ch *ContainerHost) NewSession(containerID string, attach types.HijackedResponse, opts SessionOptions) *Session {
+	if opts.Input == nil {
+		opts.Input = os.Stdin
+	}
+	if opts.Output == nil {
+		opts.Output = os.Stdout
+	}
+	if opts.Error == nil {
+		opts.Error = os.Stderr
+	}
+	return &Session{
+		host:        ch,
+		containerID: containerID,
+		attach:      attach,
+		opts:        opts,
+		done:        make(chan error, 1),
+	}
+}
+
+// Start sets up the terminal and begins copying I/O. Call it after the
+// container has started, and always Close the session, including before
+// os.Exit, to restore the terminal.
+func (s *Session) Start(ctx context.Context) error {
+	if s.opts.TTY && s.opts.Stdin {
+		if fd, isTerminal := term.GetFdInfo(s.opts.Input); isTerminal {
+			state, err := term.SetRawTerminal(fd)
+			if err != nil {
+				return fmt.Errorf("error setting terminal to raw mode: %w", err)
+			}
+			s.inFd, s.state = fd, state
+			log.Debug().Msg("Local terminal set to raw mode")
+		}
+	}
+
+	if s.opts.TTY {
+		resizeCtx, cancel := context.WithCancel(ctx)
+		s.stopResize = cancel
+		s.resize(resizeCtx)
+		go s.monitorResize(resizeCtx)
+	}
+
+	go s.copyOutput()
+	if s.opts.Stdin {
+		go s.copyInput()
+	}
+	return nil
+}
+
+// Done receives the result of the output copy once the container closes its
+// output, normally when it exits
+func (s *Session) Done() <-chan error {
+	return s.done
+}
+
+// Close restores the terminal and stops forwarding resizes. It is safe to
+// call more than once.
+func (s *Session) Close() {
+	s.closeOnce.Do(func() {
+		if s.stopResize != nil {
+			s.stopResize()
+		}
+		if s.state != nil {
+			if err := term.RestoreTerminal(s.inFd, s.state); err != nil {
+				log.Debug().Err(err).Msg("Failed to restore terminal state")
+			}
+		}
+	})
+}
+
+// recoverPanic restores the terminal before re-raising a panic in a session
+// goroutine, which would otherwise leave the terminal in raw mode
+func (s *Session) recoverPanic() {
+	if r := recover(); r != nil {
+		s.Close()
+		panic(r)
+	}
+}
+
+func (s *Session) copyOutput() {
+	defer s.recoverPanic()
+
+	var err error
+	if s.opts.TTY {
+		// With a TTY Docker does not multiplex the stream
+		_, err = io.Copy(s.opts.Output, s.attach.Reader)
+	} else {
+		// Without a TTY remove the 8-byte stream headers
+		_, err = stdcopy.StdCopy(s.opts.Output, s.opts.Error, s.attach.Reader)
+	}
+	s.done <- err
+}
+
+func (s *Session) copyInput() {
+	defer s.recoverPanic()
+	defer func() {
+		// Close the stdin side of the connection when input ends
+		if conn, ok := s.attach.Conn.(interface{ CloseWrite() error }); ok {
+			conn.CloseWrite()
+		}
+	}()
+
+	if _, err := io.Copy(s.attach.Conn, s.opts.Input); err != nil && err != io.EOF {
+		log.Debug().Err(err).Msg("stdin copy error")
+	}
+}
+
+// resize forwards the local terminal size to the container when it changed
+func (s *Session) resize(ctx context.Context) {
+	width, height, err := terminal.GetSize()
+	if err != nil || width <= 0 || height <= 0 {
+		return
+	}
+	if width == s.width && height == s.height {
+		return
+	}
+	s.width, s.height = width, height
+
+	if err := s.host.ResizeContainer(ctx, s.containerID, width, height); err != nil {
+		log.Debug().Err(err).Msg("Failed to resize container TTY")
+	}
+}
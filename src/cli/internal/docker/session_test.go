@@ -0,0 +1,90 @@
+//go:build L0
+// +build L0
+
+package docker
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/pkg/stdcopy"
+)
+
+func newTestAttach(t *testing.T, output []byte) types.HijackedResponse {
+	t.Helper()
+	local, remote := net.Pipe()
+	t.Cleanup(func() {
+		local.Close()
+		remote.Close()
+	})
+	return types.HijackedResponse{
+		Conn:   local,
+		Reader: bufio.NewReader(bytes.NewReader(output)),
+	}
+}
+
+func waitSession(t *testing.T, session *Session) {
+	t.Helper()
+	select {
+	case err := <-session.Done():
+		if err != nil {
+			t.Fatalf("Unexpected copy error: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Session output did not finish")
+	}
+}
+
+func TestSessionDemultiplexesWithoutTTY(t *testing.T) {
+	var stream bytes.Buffer
+	stdcopy.NewStdWriter(&stream, stdcopy.Stdout).Write([]byte("out\n"))
+	stdcopy.NewStdWriter(&stream, stdcopy.Stderr).Write([]byte("err\n"))
+
+	var stdout, stderr bytes.Buffer
+	host := createMockContainerHost()
+	session := host.NewSession("test", newTestAttach(t, stream.Bytes()), SessionOptions{
+		Input:  strings.NewReader(""),
+		Output: &stdout,
+		Error:  &stderr,
+	})
+	if err := session.Start(context.Background()); err != nil {
+		t.Fatalf("Failed to start session: %v", err)
+	}
+	defer session.Close()
+	waitSession(t, session)
+
+	if stdout.String() != "out\n" {
+		t.Errorf("Expected stdout %q, got %q", "out\n", stdout.String())
+	}
+	if stderr.String() != "err\n" {
+		t.Errorf("Expected stderr %q, got %q", "err\n", stderr.String())
+	}
+}
+
+func TestSessionCopiesRawStreamWithTTY(t *testing.T) {
+	var stdout bytes.Buffer
+	host := createMockContainerHost()
+	session := host.NewSession("test", newTestAttach(t, []byte("\x1b[32mok\x1b[0m\r\n")), SessionOptions{
+		TTY:    true,
+		Input:  strings.NewReader(""),
+		Output: &stdout,
+	})
+	if err := session.Start(context.Background()); err != nil {
+		t.Fatalf("Failed to start session: %v", err)
+	}
+	waitSession(t, session)
+
+	// Close restores the terminal and must be safe to call repeatedly
+	session.Close()
+	session.Close()
+
+	if stdout.String() != "\x1b[32mok\x1b[0m\r\n" {
+		t.Errorf("Expected raw output, got %q", stdout.String())
+	}
+}
@@ -0,0 +1,29 @@
+//go:build !windows
+// +build !windows
+
+package docker
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// monitorResize forwards a resize on every SIGWINCH until ctx is done
+func (s *Session) monitorResize(ctx context.Context) {
+	defer s.recoverPanic()
+
+	winch := make(chan os.Signal, 1)
+	signal.Notify(winch, syscall.SIGWINCH)
+	defer signal.Stop(winch)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-winch:
+			s.resize(ctx)
+		}
+	}
+}
@@ -0,0 +1,27 @@
+//go:build windows
+// +build windows
+
+package docker
+
+import (
+	"context"
+	"time"
+)
+
+// monitorResize polls the console size until ctx is done, as Windows has no
+// SIGWINCH
+func (s *Session) monitorResize(ctx context.Context) {
+	defer s.recoverPanic()
+
+	ticker := time.NewTicker(250 * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.resize(ctx)
+		}
+	}
+}
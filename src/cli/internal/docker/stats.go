@@ -0,0 +1,167 @@
+package docker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/rs/zerolog/log"
+)
+
+// ResourceUsage is what a container used over its run, to help choose
+// memory_limit and cpu_limit values. Docker samples about once a second, so
+// spikes shorter than that can be missed.
+type ResourceUsage struct {
+	// PeakMemoryBytes is the highest memory use sampled, without the page
+	// cache the kernel can reclaim
+	PeakMemoryBytes uint64 `json:"peak_memory_bytes"`
+	// MemoryLimitBytes is the limit Docker reported: the memory of the host
+	// or VM when the container had no limit
+	MemoryLimitBytes uint64  `json:"memory_limit_bytes,omitempty"`
+	CPUSeconds       float64 `json:"cpu_seconds"`
+	BlockReadBytes   uint64  `json:"block_read_bytes"`
+	BlockWriteBytes  uint64  `json:"block_write_bytes"`
+	NetworkRxBytes   uint64  `json:"network_rx_bytes"`
+	NetworkTxBytes   uint64  `json:"network_tx_bytes"`
+	Samples          int     `json:"samples"`
+}
+
+// String formats the usage as a single line for the terminal
+func (u ResourceUsage) String() string {
+	return fmt.Sprintf("peak memory %s, CPU time %.2fs, disk read %s / write %s, network rx %s / tx %s",
+		formatBytes(u.PeakMemoryBytes), u.CPUSeconds,
+		formatBytes(u.BlockReadBytes), formatBytes(u.BlockWriteBytes),
+		formatBytes(u.NetworkRxBytes), formatBytes(u.NetworkTxBytes))
+}
+
+// add folds one stats sample into the usage. CPU, IO and network counters are
+// cumulative; a stopped container reports zeroes, so the maximum is kept.
+func (u *ResourceUsage) add(s *container.StatsResponse, windows bool) {
+	if s.Read.IsZero() && s.CPUStats.CPUUsage.TotalUsage == 0 && s.MemoryStats.Usage == 0 {
+		return
+	}
+	u.Samples++
+
+	memory := s.MemoryStats.Usage
+	if windows {
+		memory = s.MemoryStats.PrivateWorkingSet
+	} else if cache, ok := s.MemoryStats.Stats["inactive_file"]; ok && cache < memory {
+		// cgroup v2
+		memory -= cache
+	} else if cache, ok := s.MemoryStats.Stats["total_inactive_file"]; ok && cache < memory {
+		// cgroup v1
+		memory -= cache
+	}
+	u.PeakMemoryBytes = max(u.PeakMemoryBytes, memory)
+	if s.MemoryStats.Limit > 0 {
+		u.MemoryLimitBytes = s.MemoryStats.Limit
+	}
+
+	// Linux reports nanoseconds, Windows 100 nanosecond intervals
+	cpu := float64(s.CPUStats.CPUUsage.TotalUsage) / 1e9
+	if windows {
+		cpu *= 100
+	}
+	u.CPUSeconds = max(u.CPUSeconds, cpu)
+
+	var read, write uint64
+	for _, entry := range s.BlkioStats.IoServiceBytesRecursive {
+		switch strings.ToLower(entry.Op) {
+		case "read":
+			read += entry.Value
+		case "write":
+			write += entry.Value
+		}
+	}
+	if windows {
+		read, write = s.StorageStats.ReadSizeBytes, s.StorageStats.WriteSizeBytes
+	}
+	u.BlockReadBytes = max(u.BlockReadBytes, read)
+	u.BlockWriteBytes = max(u.BlockWriteBytes, write)
+
+	var rx, tx uint64
+	for _, network := range s.Networks {
+		rx += network.RxBytes
+		tx += network.TxBytes
+	}
+	u.NetworkRxBytes = max(u.NetworkRxBytes, rx)
+	u.NetworkTxBytes = max(u.NetworkTxBytes, tx)
+}
+
+// StatsCollector samples the resource usage of a running container
+type StatsCollector struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	mu    sync.Mutex
+	usage ResourceUsage
+}
+
+// CollectStats starts sampling the stats of a container; call it once the
+// container has started and Stop once it has exited
+func (ch *ContainerHost) CollectStats(ctx context.Context, containerID string) *StatsCollector {
+	ctx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	c := &StatsCollector{cancel: cancel, done: make(chan struct{})}
+	go func() {
+		defer close(c.done)
+		if err := c.collect(ctx, ch.client, containerID); err != nil && ctx.Err() == nil {
+			log.Debug().Err(err).Str("container_id", containerID).Msg("Failed to collect container stats")
+		}
+	}()
+	return c
+}
+
+func (c *StatsCollector) collect(ctx context.Context, runtime ContainerRuntime, containerID string) error {
+	stats, err := runtime.ContainerStats(ctx, containerID, true)
+	if err != nil {
+		return err
+	}
+	defer stats.Body.Close()
+
+	windows := stats.OSType == "windows"
+	decoder := json.NewDecoder(stats.Body)
+	for {
+		var sample container.StatsResponse
+		if err := decoder.Decode(&sample); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+		c.mu.Lock()
+		c.usage.add(&sample, windows)
+		c.mu.Unlock()
+	}
+}
+
+// Stop ends the sampling and returns the usage, nil when no sample was taken
+func (c *StatsCollector) Stop() *ResourceUsage {
+	c.cancel()
+	<-c.done
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.usage.Samples == 0 {
+		return nil
+	}
+	usage := c.usage
+	return &usage
+}
+
+// formatBytes formats n in the binary units memory_limit uses
+func formatBytes(n uint64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := uint64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,110 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ready-to-release/eac/src/cli/internal/conf"
+)
+
+// Timeouts bounds the individual Docker operations of a ContainerHost
+type Timeouts struct {
+	Pull     time.Duration
+	Create   time.Duration
+	Start    time.Duration
+	Stop     time.Duration
+	Metadata time.Duration
+	// Registry bounds logging in to the registry
+	Registry time.Duration
+	// Run bounds a whole extension run, from start to exit; zero is no limit
+	Run time.Duration
+}
+
+// DefaultTimeouts are used for operations without a configured timeout
+var DefaultTimeouts = Timeouts{
+	Pull:     10 * time.Minute,
+	Create:   30 * time.Second,
+	Start:    30 * time.Second,
+	Stop:     10 * time.Second,
+	Metadata: 60 * time.Second,
+	Registry: 30 * time.Second,
+}
+
+// TimeoutsFromConfig returns DefaultTimeouts overridden by defaults.timeout,
+// registry.timeout and defaults.timeouts. defaults.timeout bounds extension
+// runs and caps metadata calls, which never take longer than a run.
+func TimeoutsFromConfig(cfg *conf.Config) Timeouts {
+	timeouts := DefaultTimeouts
+	if cfg == nil {
+		return timeouts
+	}
+
+	override := func(target *time.Duration, seconds int) {
+		if seconds > 0 {
+			*target = time.Duration(seconds) * time.Second
+		}
+	}
+	if cfg.Registry != nil {
+		override(&timeouts.Registry, cfg.Registry.Timeout)
+	}
+	if cfg.Defaults == nil {
+		return timeouts
+	}
+	override(&timeouts.Run, cfg.Defaults.Timeout)
+	if configured := cfg.Defaults.Timeouts; configured != nil {
+		override(&timeouts.Pull, configured.Pull)
+		override(&timeouts.Create, configured.Create)
+		override(&timeouts.Start, configured.Start)
+		override(&timeouts.Stop, configured.Stop)
+		override(&timeouts.Metadata, configured.Metadata)
+	}
+	if timeouts.Run > 0 {
+		timeouts.Metadata = min(timeouts.Metadata, timeouts.Run)
+	}
+	return timeouts
+}
+
+// Timeouts returns the operation timeouts of the host
+func (ch *ContainerHost) Timeouts() Timeouts {
+	return ch.timeouts
+}
+
+// SetRunTimeout overrides the run timeout of the host, e.g. with --timeout;
+// zero removes the limit
+func (ch *ContainerHost) SetRunTimeout(d time.Duration) {
+	ch.timeouts.Run = d
+}
+
+// TimeoutError reports an extension run stopped for exceeding its timeout
+type TimeoutError struct {
+	Timeout time.Duration
+}
+
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("extension timed out after %s", e.Timeout)
+}
+
+// RunTimer fires when the run timeout of the host has passed, never when
+// there is none. stop releases the timer.
+func (ch *ContainerHost) RunTimer() (expired <-chan time.Time, stop func()) {
+	if ch.timeouts.Run <= 0 {
+		return nil, func() {}
+	}
+	timer := time.NewTimer(ch.timeouts.Run)
+	return timer.C, func() { timer.Stop() }
+}
+
+// withTimeout bounds ctx by d, or only makes it cancellable when d is zero
+func withTimeout(ctx context.Context, d time.Duration) (context.Context, context.CancelFunc) {
+	if d <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, d)
+}
+
+// cleanupContext returns a context for tearing down containers after ctx has
+// been cancelled. It keeps the values of ctx but not its cancellation.
+func (ch *ContainerHost) cleanupContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	return withTimeout(context.WithoutCancel(ctx), ch.timeouts.Stop+5*time.Second)
+}
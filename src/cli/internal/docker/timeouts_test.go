@@ -0,0 +1,54 @@
+//go:build L0
+// +build L0
+
+package docker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ready-to-release/eac/src/cli/internal/conf"
+)
+
+func TestTimeoutsFromConfig(t *testing.T) {
+	if got := TimeoutsFromConfig(&conf.Config{}); got != DefaultTimeouts {
+		t.Errorf("Expected default timeouts without configuration, got %+v", got)
+	}
+
+	cfg := &conf.Config{
+		Defaults: &conf.Defaults{
+			Timeouts: &conf.Timeouts{Pull: 1200, Stop: 30},
+		},
+	}
+	got := TimeoutsFromConfig(cfg)
+	if got.Pull != 20*time.Minute {
+		t.Errorf("Expected pull timeout 20m, got %s", got.Pull)
+	}
+	if got.Stop != 30*time.Second {
+		t.Errorf("Expected stop timeout 30s, got %s", got.Stop)
+	}
+	if got.Create != DefaultTimeouts.Create || got.Start != DefaultTimeouts.Start || got.Metadata != DefaultTimeouts.Metadata {
+		t.Errorf("Expected unset timeouts to keep their defaults, got %+v", got)
+	}
+}
+
+func TestTimeoutsFromConfigRunAndRegistry(t *testing.T) {
+	cfg := &conf.Config{
+		Registry: &conf.Registry{Timeout: 15},
+		Defaults: &conf.Defaults{Timeout: 20},
+	}
+	got := TimeoutsFromConfig(cfg)
+	if got.Run != 20*time.Second {
+		t.Errorf("Expected run timeout 20s, got %s", got.Run)
+	}
+	if got.Registry != 15*time.Second {
+		t.Errorf("Expected registry timeout 15s, got %s", got.Registry)
+	}
+	if got.Metadata != 20*time.Second {
+		t.Errorf("Expected the run timeout to cap the metadata timeout, got %s", got.Metadata)
+	}
+
+	if got := TimeoutsFromConfig(&conf.Config{}); got.Run != 0 {
+		t.Errorf("Expected no run timeout without configuration, got %s", got.Run)
+	}
+}
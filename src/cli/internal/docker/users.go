@@ -0,0 +1,140 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/ready-to-release/eac/src/cli/internal/conf"
+	"github.com/rs/zerolog/log"
+)
+
+// OwnershipHelperLabel marks the helper container that fixes the ownership
+// of files an extension created or changed; its value is the name of the
+// extension
+const OwnershipHelperLabel = "r2r.ownership.helper"
+
+// hostIDs returns the UID and GID of the user running r2r, -1 on platforms
+// without them
+var hostIDs = func() (int, int) {
+	return os.Getuid(), os.Getgid()
+}
+
+// containerUser converts run_as to the user of a container: "" for the user
+// of the image
+func containerUser(runAs string) string {
+	switch runAs {
+	case "", conf.RunAsImage:
+		return ""
+	case conf.RunAsRoot:
+		return "0:0"
+	case conf.RunAsHost:
+		uid, gid := hostIDs()
+		if uid < 0 {
+			return ""
+		}
+		return fmt.Sprintf("%d:%d", uid, gid)
+	}
+	return runAs
+}
+
+// userEnv returns the environment a container needs for its user: a user
+// without an entry in the image's passwd has no usable home directory
+func userEnv(ext *ExtensionConfig, user string) []string {
+	if user == "" || user == "0:0" {
+		return nil
+	}
+	for _, env := range ext.Env {
+		if env.Name == "HOME" {
+			return nil
+		}
+	}
+	return []string{"HOME=/tmp"}
+}
+
+// ownershipScript gives the files of the workspace that were modified since
+// R2R_SINCE (UTC, in touch -t format) and are not owned by R2R_UID to
+// R2R_UID:R2R_GID. Older files keep their owner, so files of other users in a
+// shared checkout are left alone. It does not cross into other mounts.
+const ownershipScript = `touch -t "$R2R_SINCE" /tmp/r2r-since && ` +
+	`find ` + WorkspaceDir + ` -xdev -newer /tmp/r2r-since ! -uid "$R2R_UID" -exec chown -h "$R2R_UID:$R2R_GID" {} +`
+
+// ownershipSinceFormat is the touch -t format of R2R_SINCE
+const ownershipSinceFormat = "200601021504.05"
+
+// FixOwnership gives files an extension created or modified in the
+// repository since it started to the user running r2r. It does nothing when
+// the extension ran as that user, cannot write to the repository or has
+// fix_ownership disabled.
+func (ch *ContainerHost) FixOwnership(ctx context.Context, ext *ExtensionConfig, since time.Time) error {
+	uid, gid := hostIDs()
+	if !ext.FixOwnership || uid < 0 || (ext.RepoAccess != "" && ext.RepoAccess != conf.RepoAccessReadWrite) {
+		return nil
+	}
+	hostUser := fmt.Sprintf("%d:%d", uid, gid)
+	user := containerUser(ext.RunAs)
+	if user == "" {
+		imageInspect, err := ch.InspectImage(ctx, ext.Image)
+		if err != nil {
+			return err
+		}
+		if imageInspect.Config != nil {
+			user = imageInspect.Config.User
+		}
+	}
+	if user == hostUser || user == strconv.Itoa(uid) || strings.HasPrefix(user, strconv.Itoa(uid)+":") {
+		return nil
+	}
+
+	if err := ch.EnsureImageExists(ctx, DefaultHelperImage, "IfNotPresent", false); err != nil {
+		return fmt.Errorf("error preparing ownership helper image: %w", err)
+	}
+	resp, err := ch.client.ContainerCreate(ctx, &container.Config{
+		Image:      DefaultHelperImage,
+		User:       "0:0",
+		Entrypoint: []string{"/bin/sh", "-c", ownershipScript},
+		Cmd:        []string{},
+		Env: []string{
+			"R2R_UID=" + strconv.Itoa(uid),
+			"R2R_GID=" + strconv.Itoa(gid),
+			"R2R_SINCE=" + since.UTC().Format(ownershipSinceFormat),
+			"TZ=UTC",
+		},
+		Labels: map[string]string{"r2r-cli": "", OwnershipHelperLabel: ext.Name},
+	}, &container.HostConfig{
+		Mounts:     []mount.Mount{{Type: mount.TypeBind, Source: ch.rootDir, Target: WorkspaceDir}},
+		UsernsMode: container.UsernsMode(ext.UsernsMode),
+	}, nil, nil, "")
+	if err != nil {
+		return fmt.Errorf("error creating ownership helper: %w", err)
+	}
+	defer func() {
+		if err := ch.RemoveContainer(context.WithoutCancel(ctx), resp.ID); err != nil {
+			log.Debug().Err(err).Str("container_id", resp.ID).Msg("Failed to remove ownership helper")
+		}
+	}()
+
+	statusCh, errCh := ch.WaitForContainer(ctx, resp.ID)
+	if err := ch.client.ContainerStart(ctx, resp.ID, container.StartOptions{}); err != nil {
+		return fmt.Errorf("error starting ownership helper: %w", err)
+	}
+	select {
+	case status := <-statusCh:
+		if status.StatusCode != 0 {
+			return fmt.Errorf("ownership helper exited with code %d", status.StatusCode)
+		}
+	case err := <-errCh:
+		if err != nil {
+			return fmt.Errorf("error waiting for ownership helper: %w", err)
+		}
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	log.Debug().Str("extension", ext.Name).Str("owner", hostUser).Msg("Fixed ownership of files in the repository")
+	return nil
+}
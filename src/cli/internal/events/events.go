@@ -0,0 +1,144 @@
+// Package events reports the lifecycle of a run as newline-delimited JSON,
+// so wrappers such as editor extensions can follow a run without parsing
+// the output meant for humans. Each line is one Event.
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Event types, in the order a run emits them
+const (
+	ConfigLoaded    = "config-loaded"
+	ImagePullStart  = "image-pull-start"
+	ImagePullFinish = "image-pull-finish"
+	ContainerStart  = "container-start"
+	OutputChunk     = "output-chunk"
+	Exit            = "exit"
+)
+
+// Output streams of an output-chunk event
+const (
+	Stdout = "stdout"
+	Stderr = "stderr"
+)
+
+// Event is one line of the event stream. Fields that do not apply to the
+// type are omitted.
+type Event struct {
+	Type        string    `json:"type"`
+	Time        time.Time `json:"time"`
+	Extension   string    `json:"extension,omitempty"`
+	Image       string    `json:"image,omitempty"`
+	ContainerID string    `json:"container_id,omitempty"`
+	Files       []string  `json:"files,omitempty"`   // config-loaded: configuration files, lowest precedence first
+	Updated     *bool     `json:"updated,omitempty"` // image-pull-finish: whether a new image was pulled
+	Stream      string    `json:"stream,omitempty"`  // output-chunk: stdout or stderr
+	Data        string    `json:"data,omitempty"`    // output-chunk: the raw output
+	ExitCode    *int      `json:"exit_code,omitempty"`
+	Status      string    `json:"status,omitempty"`
+	Error       string    `json:"error,omitempty"`
+}
+
+// Writer writes events as JSON lines. Its methods are safe for concurrent
+// use and do nothing on a nil Writer, so callers need not check whether
+// events were requested.
+type Writer struct {
+	mu     sync.Mutex
+	w      io.Writer
+	closer io.Closer
+	now    func() time.Time
+}
+
+// New returns a Writer writing to w
+func New(w io.Writer) *Writer {
+	return &Writer{w: w, now: time.Now}
+}
+
+// Open returns a Writer for the value of --events: a file descriptor
+// number inherited from the parent process, such as 3, or a file path,
+// which is created or truncated
+func Open(target string) (*Writer, error) {
+	if target == "" {
+		return nil, fmt.Errorf("no event target")
+	}
+	if fd, err := strconv.Atoi(target); err == nil {
+		if fd < 0 {
+			return nil, fmt.Errorf("invalid file descriptor %d", fd)
+		}
+		file := os.NewFile(uintptr(fd), "fd"+target)
+		if file == nil {
+			return nil, fmt.Errorf("invalid file descriptor %d", fd)
+		}
+		if _, err := file.Stat(); err != nil {
+			return nil, fmt.Errorf("file descriptor %d is not open: %w", fd, err)
+		}
+		w := New(file)
+		w.closer = file
+		return w, nil
+	}
+	file, err := os.Create(target)
+	if err != nil {
+		return nil, err
+	}
+	w := New(file)
+	w.closer = file
+	return w, nil
+}
+
+// Emit writes e, stamping it with the current time unless it has one
+func (w *Writer) Emit(e Event) error {
+	if w == nil {
+		return nil
+	}
+	if e.Time.IsZero() {
+		e.Time = w.now().UTC()
+	}
+	line, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	_, err = w.w.Write(line)
+	return err
+}
+
+// Output returns a writer emitting an output-chunk event for every write,
+// to be combined with the writers showing the output. Event errors are
+// ignored so they never interrupt the output.
+func (w *Writer) Output(extension, stream string) io.Writer {
+	if w == nil {
+		return io.Discard
+	}
+	return &outputWriter{events: w, extension: extension, stream: stream}
+}
+
+// Close closes the file the events are written to, if Open opened one
+func (w *Writer) Close() error {
+	if w == nil || w.closer == nil {
+		return nil
+	}
+	return w.closer.Close()
+}
+
+type outputWriter struct {
+	events    *Writer
+	extension string
+	stream    string
+}
+
+func (o *outputWriter) Write(p []byte) (int, error) {
+	if len(p) > 0 {
+		o.events.Emit(Event{Type: OutputChunk, Extension: o.extension, Stream: o.stream, Data: string(p)})
+	}
+	return len(p), nil
+}
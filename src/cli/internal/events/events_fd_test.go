@@ -0,0 +1,35 @@
+//go:build L0 && !windows
+// +build L0,!windows
+
+package events
+
+import (
+	"bufio"
+	"os"
+	"strconv"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenFileDescriptor(t *testing.T) {
+	r, pw, err := os.Pipe()
+	require.NoError(t, err)
+	defer r.Close()
+
+	// Hand a descriptor of its own to Open, as a parent process would
+	fd, err := syscall.Dup(int(pw.Fd()))
+	require.NoError(t, err)
+	require.NoError(t, pw.Close())
+
+	w, err := Open(strconv.Itoa(fd))
+	require.NoError(t, err)
+	require.NoError(t, w.Emit(Event{Type: Exit}))
+	require.NoError(t, w.Close())
+
+	line, err := bufio.NewReader(r).ReadString('\n')
+	require.NoError(t, err)
+	assert.Contains(t, line, `"type":"exit"`)
+}
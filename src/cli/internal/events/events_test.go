@@ -0,0 +1,97 @@
+//go:build L0
+// +build L0
+
+package events
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func decodeLines(t *testing.T, data []byte) []map[string]any {
+	t.Helper()
+	var events []map[string]any
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		var e map[string]any
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &e), scanner.Text())
+		events = append(events, e)
+	}
+	return events
+}
+
+func TestEmitWritesJSONLines(t *testing.T) {
+	var buf bytes.Buffer
+	w := New(&buf)
+	w.now = func() time.Time { return time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC) }
+
+	updated, code := false, 3
+	require.NoError(t, w.Emit(Event{Type: ConfigLoaded, Files: []string{"/repo/r2r-cli.yml"}}))
+	require.NoError(t, w.Emit(Event{Type: ImagePullFinish, Extension: "go", Image: "go:1", Updated: &updated}))
+	require.NoError(t, w.Emit(Event{Type: Exit, Extension: "go", ExitCode: &code, Status: "failed"}))
+
+	events := decodeLines(t, buf.Bytes())
+	require.Len(t, events, 3)
+	assert.Equal(t, "config-loaded", events[0]["type"])
+	assert.Equal(t, "2026-01-01T12:00:00Z", events[0]["time"])
+	assert.NotContains(t, events[0], "extension")
+	assert.Equal(t, false, events[1]["updated"])
+	assert.Equal(t, float64(3), events[2]["exit_code"])
+	assert.Equal(t, "failed", events[2]["status"])
+}
+
+func TestOutputEmitsChunks(t *testing.T) {
+	var buf bytes.Buffer
+	w := New(&buf)
+	out := w.Output("go", Stderr)
+
+	n, err := out.Write([]byte("line 1\n"))
+	require.NoError(t, err)
+	assert.Equal(t, 7, n)
+	_, _ = out.Write(nil)
+
+	events := decodeLines(t, buf.Bytes())
+	require.Len(t, events, 1)
+	assert.Equal(t, "output-chunk", events[0]["type"])
+	assert.Equal(t, "stderr", events[0]["stream"])
+	assert.Equal(t, "line 1\n", events[0]["data"])
+}
+
+func TestNilWriter(t *testing.T) {
+	var w *Writer
+	assert.NoError(t, w.Emit(Event{Type: Exit}))
+	_, err := w.Output("go", Stdout).Write([]byte("x"))
+	assert.NoError(t, err)
+	assert.NoError(t, w.Close())
+}
+
+func TestOpenFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.ndjson")
+	w, err := Open(path)
+	require.NoError(t, err)
+	require.NoError(t, w.Emit(Event{Type: ContainerStart, ContainerID: "abc"}))
+	require.NoError(t, w.Close())
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.True(t, strings.HasSuffix(string(data), "\n"))
+	assert.Equal(t, "abc", decodeLines(t, data)[0]["container_id"])
+}
+
+func TestOpenInvalidTarget(t *testing.T) {
+	_, err := Open("")
+	assert.Error(t, err)
+	_, err = Open("-1")
+	assert.Error(t, err)
+	_, err = Open("987")
+	assert.ErrorContains(t, err, "not open")
+}
@@ -1,6 +1,7 @@
 package extensions
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/ready-to-release/eac/src/cli/internal/conf"
@@ -13,9 +14,9 @@ type Installer struct {
 	host *docker.ContainerHost
 }
 
-// NewInstaller creates a new extension installer
-func NewInstaller() (*Installer, error) {
-	host, err := docker.NewContainerHost()
+// NewInstaller creates a new extension installer for cfg
+func NewInstaller(cfg *conf.Config) (*Installer, error) {
+	host, err := docker.NewContainerHostWithConfig(cfg)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create container host: %w", err)
 	}
@@ -35,7 +36,7 @@ func (i *Installer) Close() error {
 
 // EnsureExtensionImage ensures an extension's Docker image is available locally
 // It returns true if the image was actually updated, false if it was already up-to-date
-func (i *Installer) EnsureExtensionImage(extensionName string) (bool, error) {
+func (i *Installer) EnsureExtensionImage(ctx context.Context, extensionName string) (bool, error) {
 	// Find extension configuration
 	extConfig, err := i.host.FindExtension(extensionName)
 	if err != nil {
@@ -43,7 +44,7 @@ func (i *Installer) EnsureExtensionImage(extensionName string) (bool, error) {
 	}
 
 	// Check if image exists before pulling
-	beforePull, _ := i.host.InspectImage(extConfig.Image)
+	beforePull, _ := i.host.InspectImage(ctx, extConfig.Image)
 	imageExistedBefore := beforePull != nil
 	var beforeID string
 	var beforeDigest string
@@ -56,12 +57,12 @@ func (i *Installer) EnsureExtensionImage(extensionName string) (bool, error) {
 	}
 
 	// Ensure image exists (will pull if needed based on policy)
-	if err := i.host.EnsureImageExists(extConfig.Image, extConfig.ImagePullPolicy, extConfig.LoadLocal); err != nil {
+	if err := i.host.EnsureImageExists(ctx, extConfig.Image, extConfig.ImagePullPolicy, extConfig.LoadLocal); err != nil {
 		return false, fmt.Errorf("failed to ensure image exists: %w", err)
 	}
 
 	// Check if image was actually updated
-	afterPull, _ := i.host.InspectImage(extConfig.Image)
+	afterPull, _ := i.host.InspectImage(ctx, extConfig.Image)
 	imageExistsAfter := afterPull != nil
 
 	// Image was updated if:
@@ -85,10 +86,10 @@ func (i *Installer) EnsureExtensionImage(extensionName string) (bool, error) {
 }
 
 // InstallExtension installs a single extension by name
-func (i *Installer) InstallExtension(ext conf.Extension) error {
+func (i *Installer) InstallExtension(ctx context.Context, ext conf.Extension) error {
 	log.Debug().Str("extension", ext.Name).Msg("Installing extension")
 
-	pulled, err := i.EnsureExtensionImage(ext.Name)
+	pulled, err := i.EnsureExtensionImage(ctx, ext.Name)
 	if err != nil {
 		return fmt.Errorf("failed to install extension '%s': %w", ext.Name, err)
 	}
@@ -103,18 +104,18 @@ func (i *Installer) InstallExtension(ext conf.Extension) error {
 }
 
 // InstallAllExtensions installs all configured extensions
-func (i *Installer) InstallAllExtensions() error {
+func (i *Installer) InstallAllExtensions(ctx context.Context) error {
 	// Validate extensions exist
 	if err := i.host.ValidateExtensions(); err != nil {
 		return fmt.Errorf("extension validation failed: %w", err)
 	}
 
-	extensions := conf.Global.Extensions
+	extensions := i.host.Config().Extensions
 	successCount := 0
 	failureCount := 0
 
 	for _, ext := range extensions {
-		if err := i.InstallExtension(ext); err != nil {
+		if err := i.InstallExtension(ctx, ext); err != nil {
 			log.Error().Err(err).Str("extension", ext.Name).Msg("Failed to install extension")
 			failureCount++
 		} else {
@@ -0,0 +1,68 @@
+package extensions
+
+import (
+	"encoding/xml"
+	"fmt"
+)
+
+// JUnitSummary totals the test cases of a JUnit XML report
+type JUnitSummary struct {
+	Suites   int
+	Tests    int
+	Failures int
+	Errors   int
+	Skipped  int
+}
+
+// Passed reports whether no test failed or errored
+func (s JUnitSummary) Passed() bool {
+	return s.Failures == 0 && s.Errors == 0
+}
+
+type junitSuite struct {
+	Tests    int          `xml:"tests,attr"`
+	Failures int          `xml:"failures,attr"`
+	Errors   int          `xml:"errors,attr"`
+	Skipped  int          `xml:"skipped,attr"`
+	Suites   []junitSuite `xml:"testsuite"`
+}
+
+// ParseJUnitSummary totals a JUnit XML report with either a <testsuites> or
+// a single <testsuite> root. Counts come from the suite attributes.
+func ParseJUnitSummary(data []byte) (JUnitSummary, error) {
+	var root struct {
+		XMLName xml.Name
+		junitSuite
+	}
+	if err := xml.Unmarshal(data, &root); err != nil {
+		return JUnitSummary{}, fmt.Errorf("invalid JUnit XML: %w", err)
+	}
+
+	var summary JUnitSummary
+	switch root.XMLName.Local {
+	case "testsuite":
+		summary.add(root.junitSuite)
+	case "testsuites":
+		for _, suite := range root.Suites {
+			summary.add(suite)
+		}
+	default:
+		return JUnitSummary{}, fmt.Errorf("invalid JUnit XML: unexpected root element <%s>", root.XMLName.Local)
+	}
+	return summary, nil
+}
+
+// add counts a suite and its nested suites
+func (s *JUnitSummary) add(suite junitSuite) {
+	if len(suite.Suites) > 0 {
+		for _, nested := range suite.Suites {
+			s.add(nested)
+		}
+		return
+	}
+	s.Suites++
+	s.Tests += suite.Tests
+	s.Failures += suite.Failures
+	s.Errors += suite.Errors
+	s.Skipped += suite.Skipped
+}
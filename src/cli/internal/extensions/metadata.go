@@ -0,0 +1,113 @@
+package extensions
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ready-to-release/eac/contracts"
+	"github.com/ready-to-release/eac/src/cli/internal/validator"
+	"github.com/ready-to-release/eac/src/cli/internal/version"
+	"gopkg.in/yaml.v3"
+)
+
+// Metadata is the output of an extension's extension-meta command
+type Metadata struct {
+	Name          string                     `yaml:"name"`
+	Version       string                     `yaml:"version"`
+	Description   string                     `yaml:"description"`
+	SchemaVersion string                     `yaml:"schema-version"`
+	MinCLIVersion string                     `yaml:"min-cli-version"`
+	MaxCLIVersion string                     `yaml:"max-cli-version"`
+	Commands      map[string]MetadataCommand `yaml:"commands"`
+	Capabilities  []string                   `yaml:"capabilities"`
+	Metadata      map[string]string          `yaml:"metadata"`
+
+	raw map[string]interface{} // document as parsed, validated against the schema
+}
+
+// MetadataCommand describes a command offered by an extension
+type MetadataCommand struct {
+	Description string `yaml:"description"`
+	Usage       string `yaml:"usage"`
+}
+
+// ParseMetadata parses extension-meta output, which is YAML or JSON
+func ParseMetadata(output string) (*Metadata, error) {
+	var raw map[string]interface{}
+	if err := yaml.Unmarshal([]byte(output), &raw); err != nil {
+		return nil, fmt.Errorf("extension-meta output is not valid YAML: %w", err)
+	}
+	if raw == nil {
+		return nil, fmt.Errorf("extension-meta output is empty")
+	}
+
+	var meta Metadata
+	if err := yaml.Unmarshal([]byte(output), &meta); err != nil {
+		return nil, fmt.Errorf("extension-meta output does not match the metadata format: %w", err)
+	}
+	meta.raw = raw
+	return &meta, nil
+}
+
+// Validate checks the metadata against an embedded metadata schema version,
+// as picked by NegotiateSchema, and returns the violations, if any
+func (m *Metadata) Validate(schemaVersion string) ([]string, error) {
+	schema, err := contracts.MetadataSchema(schemaVersion)
+	if err != nil {
+		return nil, err
+	}
+	v, err := validator.NewSchemaValidator(schema)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := v.ValidateInterface(m.raw)
+	if err != nil {
+		return nil, err
+	}
+
+	var violations []string
+	for _, e := range result.Errors {
+		violations = append(violations, fmt.Sprintf("%s: %s", e.Field, e.Message))
+	}
+	return violations, nil
+}
+
+// Compatibility is the result of checking an extension against a CLI version
+type Compatibility struct {
+	Compatible bool
+	Reason     string
+}
+
+// CheckCLICompatibility checks cliVersion against the CLI version range the
+// extension declares. Development builds are always considered compatible.
+func (m *Metadata) CheckCLICompatibility(cliVersion string) Compatibility {
+	if m.MinCLIVersion == "" && m.MaxCLIVersion == "" {
+		return Compatibility{Compatible: true, Reason: "no CLI version range declared"}
+	}
+	if cliVersion == "" || cliVersion == "undefined" || cliVersion == "dev" {
+		return Compatibility{Compatible: true, Reason: "development build, version range not checked"}
+	}
+
+	cli := version.EnsurePrefix(cliVersion, "v")
+	if m.MinCLIVersion != "" && version.CompareVersions(cli, version.EnsurePrefix(m.MinCLIVersion, "v")) < 0 {
+		return Compatibility{Reason: fmt.Sprintf("requires r2r %s or newer, this is %s", m.MinCLIVersion, cliVersion)}
+	}
+	if m.MaxCLIVersion != "" && version.CompareVersions(cli, version.EnsurePrefix(m.MaxCLIVersion, "v")) > 0 {
+		return Compatibility{Reason: fmt.Sprintf("supports r2r up to %s, this is %s", m.MaxCLIVersion, cliVersion)}
+	}
+
+	return Compatibility{Compatible: true, Reason: fmt.Sprintf("r2r %s is within %s", cliVersion, m.cliRange())}
+}
+
+// cliRange formats the declared CLI version range
+func (m *Metadata) cliRange() string {
+	parts := []string{}
+	if m.MinCLIVersion != "" {
+		parts = append(parts, ">= "+m.MinCLIVersion)
+	}
+	if m.MaxCLIVersion != "" {
+		parts = append(parts, "<= "+m.MaxCLIVersion)
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,140 @@
+//go:build L0
+// +build L0
+
+package extensions
+
+import (
+	"strings"
+	"testing"
+)
+
+const validMetadata = `name: "test-extension"
+version: "1.0.0"
+description: "Test extension"
+schema-version: "1.0"
+min-cli-version: "v1.2.0"
+commands:
+  test:
+    description: "Run tests"
+capabilities:
+  - "testing"
+metadata:
+  author: "R2R CLI Team"
+`
+
+func TestParseAndValidateMetadata(t *testing.T) {
+	meta, err := ParseMetadata(validMetadata)
+	if err != nil {
+		t.Fatalf("Failed to parse metadata: %v", err)
+	}
+	if meta.Name != "test-extension" || meta.SchemaVersion != "1.0" {
+		t.Errorf("Unexpected metadata: %+v", meta)
+	}
+	if _, ok := meta.Commands["test"]; !ok {
+		t.Error("Expected a test command")
+	}
+
+	violations, err := meta.Validate("1.0")
+	if err != nil {
+		t.Fatalf("Failed to validate metadata: %v", err)
+	}
+	if len(violations) != 0 {
+		t.Errorf("Expected no violations, got %v", violations)
+	}
+}
+
+func TestValidateMetadataViolations(t *testing.T) {
+	meta, err := ParseMetadata(`{"name": "Bad Name", "schema-version": "2.0"}`)
+	if err != nil {
+		t.Fatalf("Failed to parse metadata: %v", err)
+	}
+
+	violations, err := meta.Validate("1.0")
+	if err != nil {
+		t.Fatalf("Failed to validate metadata: %v", err)
+	}
+	joined := strings.Join(violations, "\n")
+	for _, field := range []string{"version", "name", "schema-version"} {
+		if !strings.Contains(joined, field) {
+			t.Errorf("Expected a violation for %s, got:\n%s", field, joined)
+		}
+	}
+}
+
+func TestParseMetadataInvalid(t *testing.T) {
+	for _, output := range []string{"", "name: [unclosed", "- just\n- a list\n"} {
+		if _, err := ParseMetadata(output); err == nil {
+			t.Errorf("Expected an error for %q", output)
+		}
+	}
+}
+
+func TestCheckCLICompatibility(t *testing.T) {
+	meta := &Metadata{MinCLIVersion: "v1.2.0", MaxCLIVersion: "1.9.9"}
+
+	testCases := map[string]struct {
+		cliVersion string
+		compatible bool
+	}{
+		"within range":      {"v1.5.0", true},
+		"at minimum":        {"1.2.0", true},
+		"too old":           {"v1.1.9", false},
+		"too new":           {"v2.0.0", false},
+		"development build": {"undefined", true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			result := meta.CheckCLICompatibility(tc.cliVersion)
+			if result.Compatible != tc.compatible {
+				t.Errorf("Expected compatible=%v, got %+v", tc.compatible, result)
+			}
+			if result.Reason == "" {
+				t.Error("Expected a reason")
+			}
+		})
+	}
+
+	if !(&Metadata{}).CheckCLICompatibility("v1.0.0").Compatible {
+		t.Error("Expected an extension without a range to be compatible")
+	}
+}
+
+func TestParseJUnitSummary(t *testing.T) {
+	testCases := map[string]struct {
+		xml      string
+		expected JUnitSummary
+	}{
+		"single suite": {
+			`<testsuite name="a" tests="3" failures="1" errors="0" skipped="1"><testcase name="x"/></testsuite>`,
+			JUnitSummary{Suites: 1, Tests: 3, Failures: 1, Skipped: 1},
+		},
+		"suites": {
+			`<?xml version="1.0"?>
+<testsuites tests="5">
+  <testsuite name="a" tests="2" failures="0" errors="1"/>
+  <testsuite name="b" tests="3" failures="0" errors="0"/>
+</testsuites>`,
+			JUnitSummary{Suites: 2, Tests: 5, Errors: 1},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			summary, err := ParseJUnitSummary([]byte(tc.xml))
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if summary != tc.expected {
+				t.Errorf("Expected %+v, got %+v", tc.expected, summary)
+			}
+			if summary.Passed() {
+				t.Error("Expected the summary to report failures")
+			}
+		})
+	}
+
+	if _, err := ParseJUnitSummary([]byte(`<results/>`)); err == nil {
+		t.Error("Expected an error for a non-JUnit document")
+	}
+}
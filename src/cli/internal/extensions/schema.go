@@ -0,0 +1,206 @@
+package extensions
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ready-to-release/eac/contracts"
+)
+
+// SchemaVersion is an extension metadata schema version. Minor versions only
+// add optional fields, so output of 1.2 is also valid 1.0 metadata.
+type SchemaVersion struct {
+	Major int
+	Minor int
+}
+
+// ParseSchemaVersion parses a "major.minor" schema version
+func ParseSchemaVersion(s string) (SchemaVersion, error) {
+	major, minor, ok := strings.Cut(strings.TrimSpace(s), ".")
+	if !ok {
+		return SchemaVersion{}, fmt.Errorf("invalid metadata schema version %q, expected major.minor", s)
+	}
+	m, err1 := strconv.Atoi(major)
+	n, err2 := strconv.Atoi(minor)
+	if err1 != nil || err2 != nil || m < 0 || n < 0 {
+		return SchemaVersion{}, fmt.Errorf("invalid metadata schema version %q, expected major.minor", s)
+	}
+	return SchemaVersion{Major: m, Minor: n}, nil
+}
+
+func (v SchemaVersion) String() string {
+	return fmt.Sprintf("%d.%d", v.Major, v.Minor)
+}
+
+// Compare returns -1, 0 or 1 when v is older than, equal to or newer than o
+func (v SchemaVersion) Compare(o SchemaVersion) int {
+	switch {
+	case v.Major != o.Major:
+		return compareInt(v.Major, o.Major)
+	default:
+		return compareInt(v.Minor, o.Minor)
+	}
+}
+
+func compareInt(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// SchemaRange is an inclusive range of metadata schema versions
+type SchemaRange struct {
+	Min SchemaVersion
+	Max SchemaVersion
+}
+
+// ParseSchemaRange parses a single version ("1.0") or an inclusive range
+// ("1.0-1.2"), as used by metadata_schema_version
+func ParseSchemaRange(s string) (SchemaRange, error) {
+	low, high, isRange := strings.Cut(s, "-")
+	min, err := ParseSchemaVersion(low)
+	if err != nil {
+		return SchemaRange{}, err
+	}
+	if !isRange {
+		return SchemaRange{Min: min, Max: min}, nil
+	}
+	max, err := ParseSchemaVersion(high)
+	if err != nil {
+		return SchemaRange{}, err
+	}
+	if max.Compare(min) < 0 {
+		return SchemaRange{}, fmt.Errorf("invalid metadata schema range %q, %s is older than %s", s, max, min)
+	}
+	return SchemaRange{Min: min, Max: max}, nil
+}
+
+func (r SchemaRange) String() string {
+	if r.Min == r.Max {
+		return r.Min.String()
+	}
+	return r.Min.String() + "-" + r.Max.String()
+}
+
+// Contains reports whether v is within the range
+func (r SchemaRange) Contains(v SchemaVersion) bool {
+	return v.Compare(r.Min) >= 0 && v.Compare(r.Max) <= 0
+}
+
+// isSupported reports whether an embedded schema can validate some version
+// in r
+func (r SchemaRange) isSupported() bool {
+	for _, embedded := range SupportedSchemas() {
+		if embedded.Major >= r.Min.Major && embedded.Compare(r.Max) <= 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// SupportedSchemas returns the metadata schema versions this build embeds,
+// oldest first
+func SupportedSchemas() []SchemaVersion {
+	var versions []SchemaVersion
+	for _, s := range contracts.MetadataSchemaVersions() {
+		if v, err := ParseSchemaVersion(s); err == nil {
+			versions = append(versions, v)
+		}
+	}
+	sort.Slice(versions, func(i, j int) bool { return versions[i].Compare(versions[j]) < 0 })
+	return versions
+}
+
+// SupportedSchemaRange returns the range of metadata schema versions this
+// build can validate
+func SupportedSchemaRange() SchemaRange {
+	versions := SupportedSchemas()
+	if len(versions) == 0 {
+		return SchemaRange{}
+	}
+	return SchemaRange{Min: versions[0], Max: versions[len(versions)-1]}
+}
+
+// embeddedSchemaFor returns the embedded schema to validate v against: the
+// newest embedded version of the same major version that is not newer than v
+func embeddedSchemaFor(v SchemaVersion) (SchemaVersion, bool) {
+	var match SchemaVersion
+	found := false
+	for _, embedded := range SupportedSchemas() {
+		if embedded.Major == v.Major && embedded.Compare(v) <= 0 {
+			match, found = embedded, true
+		}
+	}
+	return match, found
+}
+
+// SchemaMismatchError reports an extension whose metadata schema versions
+// the CLI does not support
+type SchemaMismatchError struct {
+	Extension string
+	Schemas   SchemaRange // versions the extension declares or outputs
+	Supported SchemaRange // versions this CLI supports
+}
+
+func (e *SchemaMismatchError) Error() string {
+	if e.Schemas.Min.Compare(e.Supported.Max) > 0 {
+		return fmt.Sprintf("extension %s uses metadata schema %s, but this r2r supports %s: upgrade r2r with 'r2r update'",
+			e.Extension, e.Schemas, e.Supported)
+	}
+	return fmt.Sprintf("extension %s uses metadata schema %s, but this r2r requires %s: upgrade the extension image with 'r2r install %s'",
+		e.Extension, e.Schemas, e.Supported, e.Extension)
+}
+
+// CheckDeclaredSchema checks the metadata_schema_version an extension
+// declares in configuration against the versions this CLI supports. An empty
+// declaration is accepted.
+func CheckDeclaredSchema(extension, declared string) error {
+	if declared == "" {
+		return nil
+	}
+	declaredRange, err := ParseSchemaRange(declared)
+	if err != nil {
+		return fmt.Errorf("extension %s: %w", extension, err)
+	}
+	if !declaredRange.isSupported() {
+		return &SchemaMismatchError{Extension: extension, Schemas: declaredRange, Supported: SupportedSchemaRange()}
+	}
+	return nil
+}
+
+// NegotiateSchema picks the embedded schema version to validate the metadata
+// against. It fails when the schema-version of the output is not supported
+// by this CLI, or is outside the range declared in configuration.
+func (m *Metadata) NegotiateSchema(extension, declared string) (string, error) {
+	if err := CheckDeclaredSchema(extension, declared); err != nil {
+		return "", err
+	}
+	if m.SchemaVersion == "" {
+		// Validation reports the missing field against the newest schema
+		return contracts.MetadataSchemaVersion, nil
+	}
+
+	v, err := ParseSchemaVersion(m.SchemaVersion)
+	if err != nil {
+		return "", fmt.Errorf("extension %s: %w", extension, err)
+	}
+	if declared != "" {
+		declaredRange, _ := ParseSchemaRange(declared)
+		if !declaredRange.Contains(v) {
+			return "", fmt.Errorf("extension %s outputs metadata schema %s, but its configuration declares metadata_schema_version %s",
+				extension, v, declaredRange)
+		}
+	}
+
+	embedded, ok := embeddedSchemaFor(v)
+	if !ok {
+		return "", &SchemaMismatchError{Extension: extension, Schemas: SchemaRange{Min: v, Max: v}, Supported: SupportedSchemaRange()}
+	}
+	return embedded.String(), nil
+}
@@ -0,0 +1,133 @@
+//go:build L0
+// +build L0
+
+package extensions
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ready-to-release/eac/contracts"
+)
+
+func TestParseSchemaRange(t *testing.T) {
+	testCases := map[string]struct {
+		input    string
+		expected string
+		wantErr  bool
+	}{
+		"single version": {input: "1.0", expected: "1.0"},
+		"range":          {input: "1.0-1.2", expected: "1.0-1.2"},
+		"major range":    {input: "1.3-2.0", expected: "1.3-2.0"},
+		"reversed":       {input: "1.2-1.0", wantErr: true},
+		"no minor":       {input: "1", wantErr: true},
+		"not a version":  {input: "v1.0", wantErr: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			r, err := ParseSchemaRange(tc.input)
+			if tc.wantErr {
+				if err == nil {
+					t.Errorf("Expected an error for %q", tc.input)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if r.String() != tc.expected {
+				t.Errorf("Expected %s, got %s", tc.expected, r)
+			}
+		})
+	}
+}
+
+func TestSupportedSchemaRangeEndsAtCurrent(t *testing.T) {
+	current, err := ParseSchemaVersion(contracts.MetadataSchemaVersion)
+	if err != nil {
+		t.Fatalf("Invalid current schema version: %v", err)
+	}
+	if supported := SupportedSchemaRange(); supported.Max != current {
+		t.Errorf("Expected the supported range to end at %s, got %s", current, supported)
+	}
+}
+
+func TestCheckDeclaredSchema(t *testing.T) {
+	testCases := map[string]struct {
+		declared string
+		wantErr  string
+	}{
+		"not declared":       {declared: ""},
+		"supported":          {declared: "1.0"},
+		"newer minor":        {declared: "1.4"},
+		"range with support": {declared: "0.9-1.1"},
+		"newer major":        {declared: "2.0", wantErr: "upgrade r2r"},
+		"older major":        {declared: "0.1-0.9", wantErr: "upgrade the extension image"},
+		"invalid":            {declared: "latest", wantErr: "invalid metadata schema version"},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			err := CheckDeclaredSchema("pwsh", tc.declared)
+			if tc.wantErr == "" {
+				if err != nil {
+					t.Errorf("Unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("Expected error containing %q, got %v", tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestNegotiateSchema(t *testing.T) {
+	testCases := map[string]struct {
+		output   string
+		declared string
+		expected string
+		wantErr  string
+	}{
+		"exact":            {output: "1.0", expected: "1.0"},
+		"newer minor":      {output: "1.3", expected: "1.0"},
+		"missing":          {output: "", expected: "1.0"},
+		"within declared":  {output: "1.0", declared: "1.0-1.2", expected: "1.0"},
+		"outside declared": {output: "1.3", declared: "1.0-1.2", wantErr: "declares metadata_schema_version 1.0-1.2"},
+		"newer major":      {output: "2.0", wantErr: "upgrade r2r"},
+		"unparseable":      {output: "one", wantErr: "invalid metadata schema version"},
+		"declared too new": {output: "1.0", declared: "3.0", wantErr: "upgrade r2r"},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			meta := &Metadata{SchemaVersion: tc.output}
+			version, err := meta.NegotiateSchema("pwsh", tc.declared)
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Errorf("Expected error containing %q, got %v", tc.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if version != tc.expected {
+				t.Errorf("Expected schema %s, got %s", tc.expected, version)
+			}
+		})
+	}
+}
+
+func TestSchemaMismatchErrorType(t *testing.T) {
+	_, err := (&Metadata{SchemaVersion: "2.0"}).NegotiateSchema("pwsh", "")
+	var mismatch *SchemaMismatchError
+	if !errors.As(err, &mismatch) {
+		t.Fatalf("Expected a SchemaMismatchError, got %v", err)
+	}
+	if mismatch.Schemas.String() != "2.0" {
+		t.Errorf("Expected extension schema 2.0, got %s", mismatch.Schemas)
+	}
+}
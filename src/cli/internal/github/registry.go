@@ -1,16 +1,17 @@
 package github
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
-	"os"
 	"regexp"
 	"sort"
 	"strings"
 	"time"
 
+	"github.com/ready-to-release/eac/src/cli/internal/auth"
 	"github.com/rs/zerolog/log"
 )
 
@@ -18,27 +19,67 @@ import (
 type RegistryClient struct {
 	token    string
 	username string
+	auth     *auth.Manager
 	client   *http.Client
 }
 
-// NewRegistryClient creates a new GitHub registry client
+// NewRegistryClient creates a new GitHub registry client with the credentials
+// of the shared auth manager
 func NewRegistryClient() (*RegistryClient, error) {
-	token := os.Getenv("GITHUB_TOKEN")
-	username := os.Getenv("GITHUB_USERNAME")
-	
-	if token == "" || username == "" {
-		return nil, fmt.Errorf("GITHUB_TOKEN and GITHUB_USERNAME environment variables are required")
+	return NewRegistryClientWithAuth(auth.Default())
+}
+
+// NewRegistryClientWithAuth creates a registry client with the credentials of
+// the given auth manager
+func NewRegistryClientWithAuth(manager *auth.Manager) (*RegistryClient, error) {
+	cred, err := manager.Get(context.Background(), auth.GHCR)
+	if err != nil {
+		return nil, err
 	}
-	
+
 	return &RegistryClient{
-		token:    token,
-		username: username,
+		token:    cred.Token,
+		username: cred.Username,
+		auth:     manager,
 		client: &http.Client{
-			Timeout: 30 * time.Second,
+			Timeout: DefaultTimeout,
 		},
 	}, nil
 }
 
+// DefaultTimeout bounds each registry API request unless SetTimeout is used
+const DefaultTimeout = 30 * time.Second
+
+// SetTimeout bounds each registry API request by d; zero keeps the default
+func (c *RegistryClient) SetTimeout(d time.Duration) {
+	if d > 0 {
+		c.client.Timeout = d
+	}
+}
+
+// do sends an authenticated API request. When GitHub rejects the token, the
+// credential is resolved again and the request retried once with it, which
+// picks up a token refreshed by gh or rotated in the environment.
+func (c *RegistryClient) do(req *http.Request) (*http.Response, error) {
+	resp, err := c.do(req)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized || c.auth == nil {
+		return resp, err
+	}
+
+	c.auth.Invalidate(auth.GHCR)
+	cred, credErr := c.auth.Get(req.Context(), auth.GHCR)
+	if credErr != nil || cred.Token == c.token {
+		return resp, nil
+	}
+	resp.Body.Close()
+	log.Debug().Str("source", cred.Source).Msg("GitHub rejected the registry token, retrying with refreshed credentials")
+
+	c.token, c.username = cred.Token, cred.Username
+	retry := req.Clone(req.Context())
+	retry.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.token))
+	return c.client.Do(retry)
+}
+
 // Tag represents a container image tag
 type Tag struct {
 	Name      string    `json:"name"`
@@ -73,11 +114,10 @@ func (c *RegistryClient) ListTags(imagePath string) ([]string, error) {
 		return nil, fmt.Errorf("creating request: %w", err)
 	}
 	
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.token))
 	req.Header.Set("Accept", "application/vnd.github.v3+json")
 	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
 	
-	resp, err := c.client.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("executing request: %w", err)
 	}
@@ -236,11 +276,10 @@ func (c *RegistryClient) ListExtensions() ([]ExtensionInfo, error) {
 		return nil, fmt.Errorf("creating request: %w", err)
 	}
 	
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.token))
 	req.Header.Set("Accept", "application/vnd.github.v3+json")
 	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
 	
-	resp, err := c.client.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("executing request: %w", err)
 	}
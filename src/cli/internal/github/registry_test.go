@@ -2,29 +2,24 @@ package github
 
 import (
 	"fmt"
-	"os"
 	"testing"
+
+	"github.com/ready-to-release/eac/src/cli/internal/auth"
 )
 
 func TestNewRegistryClient(t *testing.T) {
-	// Save original env vars
-	origToken := os.Getenv("GITHUB_TOKEN")
-	origUsername := os.Getenv("GITHUB_USERNAME")
-	defer func() {
-		os.Setenv("GITHUB_TOKEN", origToken)
-		os.Setenv("GITHUB_USERNAME", origUsername)
-	}()
-
 	tests := []struct {
 		name     string
 		token    string
 		username string
+		wantUser string
 		wantErr  bool
 	}{
 		{
 			name:     "Valid credentials",
 			token:    "test-token",
 			username: "test-user",
+			wantUser: "test-user",
 			wantErr:  false,
 		},
 		{
@@ -37,16 +32,19 @@ func TestNewRegistryClient(t *testing.T) {
 			name:     "Missing username",
 			token:    "test-token",
 			username: "",
-			wantErr:  true,
+			wantUser: auth.DefaultUsername,
+			wantErr:  false,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			os.Setenv("GITHUB_TOKEN", tt.token)
-			os.Setenv("GITHUB_USERNAME", tt.username)
+			t.Setenv("GITHUB_TOKEN", tt.token)
+			t.Setenv("GITHUB_USERNAME", tt.username)
 
-			client, err := NewRegistryClient()
+			// Only the environment, so gh or docker logins of the machine do not interfere
+			manager := auth.NewManager(auth.EnvProvider{TokenEnv: "GITHUB_TOKEN", UsernameEnv: "GITHUB_USERNAME"})
+			client, err := NewRegistryClientWithAuth(manager)
 			if tt.wantErr {
 				if err == nil {
 					t.Errorf("Expected error, got nil")
@@ -56,7 +54,10 @@ func TestNewRegistryClient(t *testing.T) {
 					t.Errorf("Unexpected error: %v", err)
 				}
 				if client == nil {
-					t.Errorf("Expected client, got nil")
+					t.Fatalf("Expected client, got nil")
+				}
+				if client.username != tt.wantUser {
+					t.Errorf("username = %q, want %q", client.username, tt.wantUser)
 				}
 			}
 		})
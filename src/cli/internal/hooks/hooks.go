@@ -0,0 +1,149 @@
+// Package hooks runs the hooks configured around extension runs: pre_run
+// before the container starts, on_failure after a failed run and post_run
+// after every run. A hook is a host command or another extension; both get
+// the run described in R2R_HOOK_* environment variables.
+package hooks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"time"
+
+	"github.com/ready-to-release/eac/src/cli/internal/conf"
+	"github.com/rs/zerolog/log"
+)
+
+// Event is a point of the extension lifecycle hooks run at
+type Event string
+
+const (
+	PreRun    Event = "pre_run"
+	PostRun   Event = "post_run"
+	OnFailure Event = "on_failure"
+)
+
+// Run describes the extension run hooks are fired for. ExitCode, Status and
+// Duration are unset for pre_run.
+type Run struct {
+	Extension string
+	Image     string
+	ExitCode  int
+	Status    string
+	Duration  time.Duration
+}
+
+// Env returns the environment describing the run to a hook of event
+func (r Run) Env(event Event) []string {
+	env := []string{
+		"R2R_HOOK_EVENT=" + string(event),
+		"R2R_HOOK_EXTENSION=" + r.Extension,
+		"R2R_HOOK_IMAGE=" + r.Image,
+	}
+	if event != PreRun {
+		env = append(env,
+			"R2R_HOOK_EXIT_CODE="+strconv.Itoa(r.ExitCode),
+			"R2R_HOOK_STATUS="+r.Status,
+			"R2R_HOOK_DURATION_SECONDS="+strconv.FormatFloat(r.Duration.Seconds(), 'f', 3, 64),
+		)
+	}
+	return env
+}
+
+// For returns the hooks of event for an extension: the global hooks of cfg,
+// then those of the extension
+func For(cfg *conf.Config, extension string, event Event) []conf.Hook {
+	if cfg == nil {
+		return nil
+	}
+	hooks := pick(cfg.Hooks, event)
+	if ext, ok := cfg.FindExtension(extension); ok {
+		hooks = append(hooks, pick(ext.Hooks, event)...)
+	}
+	return hooks
+}
+
+func pick(hooks *conf.Hooks, event Event) []conf.Hook {
+	if hooks == nil {
+		return nil
+	}
+	switch event {
+	case PreRun:
+		return append([]conf.Hook(nil), hooks.PreRun...)
+	case PostRun:
+		return append([]conf.Hook(nil), hooks.PostRun...)
+	case OnFailure:
+		return append([]conf.Hook(nil), hooks.OnFailure...)
+	}
+	return nil
+}
+
+// ExtensionRunner runs an extension hook with its arguments and the run
+// environment, returning the exit code of its container
+type ExtensionRunner func(ctx context.Context, extension string, args []string, env []string) (int, error)
+
+// Runner fires hooks
+type Runner struct {
+	Dir          string // working directory of host commands, the repository root
+	Stdout       io.Writer
+	Stderr       io.Writer
+	RunExtension ExtensionRunner // nil rejects extension hooks
+}
+
+// Fire runs the hooks of event for run in order. A failing pre_run hook
+// stops the remaining hooks and its error is returned to abort the run; the
+// other events run every hook and return their failures joined.
+func (r *Runner) Fire(ctx context.Context, cfg *conf.Config, event Event, run Run) error {
+	var errs []error
+	for i, hook := range For(cfg, run.Extension, event) {
+		if err := r.run(ctx, hook, run.Env(event)); err != nil {
+			err = fmt.Errorf("%s hook %d: %w", event, i+1, err)
+			if event == PreRun {
+				return err
+			}
+			log.Warn().Err(err).Str("extension", run.Extension).Msg("Hook failed")
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (r *Runner) run(ctx context.Context, hook conf.Hook, env []string) error {
+	if hook.Extension != "" {
+		if r.RunExtension == nil {
+			return fmt.Errorf("cannot run extension %q here", hook.Extension)
+		}
+		log.Debug().Str("extension", hook.Extension).Strs("args", hook.Args).Msg("Running extension hook")
+		code, err := r.RunExtension(ctx, hook.Extension, hook.Args, env)
+		if err != nil {
+			return err
+		}
+		if code != 0 {
+			return fmt.Errorf("extension %q exited with code %d", hook.Extension, code)
+		}
+		return nil
+	}
+
+	log.Debug().Str("command", hook.Command).Msg("Running hook command")
+	cmd := shellCommand(ctx, hook.Command)
+	cmd.Dir = r.Dir
+	cmd.Env = append(os.Environ(), env...)
+	cmd.Stdout, cmd.Stderr = r.Stdout, r.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("command %q: %w", hook.Command, err)
+	}
+	return nil
+}
+
+// shellCommand runs command with the shell of the platform
+func shellCommand(ctx context.Context, command string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.CommandContext(ctx, "cmd", "/C", command)
+	}
+	return exec.CommandContext(ctx, "/bin/sh", "-c", command)
+}
@@ -0,0 +1,109 @@
+//go:build L0
+// +build L0
+
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ready-to-release/eac/src/cli/internal/conf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFor(t *testing.T) {
+	cfg := &conf.Config{
+		Hooks: &conf.Hooks{PostRun: []conf.Hook{{Command: "global"}}},
+		Extensions: []conf.Extension{
+			{Name: "go", Hooks: &conf.Hooks{PostRun: []conf.Hook{{Command: "go"}}, OnFailure: []conf.Hook{{Extension: "notify"}}}},
+			{Name: "notify"},
+		},
+	}
+
+	assert.Equal(t, []conf.Hook{{Command: "global"}, {Command: "go"}}, For(cfg, "go", PostRun))
+	assert.Equal(t, []conf.Hook{{Extension: "notify"}}, For(cfg, "go", OnFailure))
+	assert.Equal(t, []conf.Hook{{Command: "global"}}, For(cfg, "notify", PostRun))
+	assert.Empty(t, For(cfg, "go", PreRun))
+	assert.Empty(t, For(nil, "go", PostRun))
+}
+
+func TestRunEnv(t *testing.T) {
+	run := Run{Extension: "go", Image: "golang:1.24", ExitCode: 2, Status: "failed", Duration: 1500 * time.Millisecond}
+
+	assert.Equal(t, []string{
+		"R2R_HOOK_EVENT=post_run",
+		"R2R_HOOK_EXTENSION=go",
+		"R2R_HOOK_IMAGE=golang:1.24",
+		"R2R_HOOK_EXIT_CODE=2",
+		"R2R_HOOK_STATUS=failed",
+		"R2R_HOOK_DURATION_SECONDS=1.500",
+	}, run.Env(PostRun))
+	assert.NotContains(t, strings.Join(run.Env(PreRun), " "), "R2R_HOOK_EXIT_CODE")
+}
+
+func TestFire(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hook commands use POSIX shell syntax")
+	}
+	dir := t.TempDir()
+	run := Run{Extension: "go", Image: "golang:1.24", ExitCode: 1, Status: "failed"}
+
+	t.Run("runs commands with the run environment", func(t *testing.T) {
+		cfg := &conf.Config{Hooks: &conf.Hooks{PostRun: []conf.Hook{
+			{Command: `echo "$R2R_HOOK_EVENT $R2R_HOOK_EXTENSION $R2R_HOOK_EXIT_CODE" > hook.out`},
+		}}}
+		var stderr bytes.Buffer
+		runner := &Runner{Dir: dir, Stderr: &stderr}
+
+		require.NoError(t, runner.Fire(context.Background(), cfg, PostRun, run))
+		out, err := os.ReadFile(filepath.Join(dir, "hook.out"))
+		require.NoError(t, err)
+		assert.Equal(t, "post_run go 1\n", string(out))
+	})
+
+	t.Run("a failing pre_run hook stops the others", func(t *testing.T) {
+		cfg := &conf.Config{Hooks: &conf.Hooks{PreRun: []conf.Hook{
+			{Command: "exit 3"},
+			{Command: "touch never"},
+		}}}
+		runner := &Runner{Dir: dir}
+
+		err := runner.Fire(context.Background(), cfg, PreRun, run)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "pre_run hook 1")
+		assert.NoFileExists(t, filepath.Join(dir, "never"))
+	})
+
+	t.Run("other events run every hook", func(t *testing.T) {
+		cfg := &conf.Config{Hooks: &conf.Hooks{OnFailure: []conf.Hook{
+			{Command: "exit 1"},
+			{Extension: "notify", Args: []string{"send"}},
+		}}}
+		var gotArgs, gotEnv []string
+		runner := &Runner{Dir: dir, RunExtension: func(ctx context.Context, extension string, args []string, env []string) (int, error) {
+			gotArgs, gotEnv = args, env
+			return 0, errors.New("no docker")
+		}}
+
+		err := runner.Fire(context.Background(), cfg, OnFailure, run)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "on_failure hook 1")
+		assert.Contains(t, err.Error(), "on_failure hook 2: no docker")
+		assert.Equal(t, []string{"send"}, gotArgs)
+		assert.Contains(t, gotEnv, "R2R_HOOK_STATUS=failed")
+	})
+
+	t.Run("extension hooks need an extension runner", func(t *testing.T) {
+		cfg := &conf.Config{Hooks: &conf.Hooks{PreRun: []conf.Hook{{Extension: "notify"}}}}
+		err := (&Runner{Dir: dir}).Fire(context.Background(), cfg, PreRun, run)
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,268 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/ready-to-release/eac/src/cli/internal/docker"
+)
+
+// Runtime is the part of the container host the manager needs. It is
+// implemented by *docker.ContainerHost.
+type Runtime interface {
+	StartJobContainer(ctx context.Context, ext *docker.ExtensionConfig, args []string, jobID string) (string, error)
+	ListJobContainers(ctx context.Context) ([]docker.JobContainer, error)
+	JobLogs(ctx context.Context, containerID string, follow bool, stdout, stderr io.Writer) error
+	ShutdownContainer(ctx context.Context, containerID string) error
+	RemoveContainer(ctx context.Context, containerID string) error
+}
+
+// Manager submits jobs and keeps the stored state in step with Docker. Job
+// containers are labeled with their job ID, so a manager in a new r2r process
+// reattaches to jobs that were submitted by an earlier one.
+type Manager struct {
+	store   *Store
+	runtime Runtime
+	now     func() time.Time
+}
+
+// NewManager returns a manager for the jobs in store
+func NewManager(store *Store, runtime Runtime) *Manager {
+	return &Manager{store: store, runtime: runtime, now: time.Now}
+}
+
+// Submit starts an extension command in a detached container and returns the
+// new job
+func (m *Manager) Submit(ctx context.Context, ext *docker.ExtensionConfig, args []string) (*Job, error) {
+	job := &Job{
+		ID:        NewID(m.now()),
+		Extension: ext.Name,
+		Image:     ext.Image,
+		Args:      args,
+		Status:    StatusStarting,
+		CreatedAt: m.now().UTC(),
+	}
+	// Saved first so a job whose container starts is never untracked
+	if err := m.store.Save(job); err != nil {
+		return nil, err
+	}
+
+	containerID, err := m.runtime.StartJobContainer(ctx, ext, args, job.ID)
+	if err != nil {
+		m.finish(job, StatusFailed, nil, err.Error())
+		if saveErr := m.store.Save(job); saveErr != nil {
+			return nil, saveErr
+		}
+		return job, fmt.Errorf("failed to start job: %w", err)
+	}
+
+	job.ContainerID = containerID
+	job.Status = StatusRunning
+	if err := m.store.Save(job); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// List reconciles all jobs with their containers and returns them, oldest
+// first
+func (m *Manager) List(ctx context.Context) ([]*Job, error) {
+	if err := m.Sync(ctx); err != nil {
+		return nil, err
+	}
+	return m.store.List()
+}
+
+// Get reconciles and returns one job
+func (m *Manager) Get(ctx context.Context, id string) (*Job, error) {
+	if _, err := m.store.Load(id); err != nil {
+		return nil, err
+	}
+	if err := m.Sync(ctx); err != nil {
+		return nil, err
+	}
+	return m.store.Load(id)
+}
+
+// Sync updates the stored state from the job containers. Finished containers
+// have their output and exit state collected and are then removed. A
+// labeled container without stored state is adopted as a new job.
+func (m *Manager) Sync(ctx context.Context) error {
+	containers, err := m.runtime.ListJobContainers(ctx)
+	if err != nil {
+		return err
+	}
+	byJob := make(map[string]docker.JobContainer, len(containers))
+	for _, c := range containers {
+		if c.JobID != "" {
+			byJob[c.JobID] = c
+		}
+	}
+
+	jobs, err := m.store.List()
+	if err != nil {
+		return err
+	}
+	known := make(map[string]bool, len(jobs))
+	for _, job := range jobs {
+		known[job.ID] = true
+		c, found := byJob[job.ID]
+		if err := m.reconcile(ctx, job, c, found); err != nil {
+			return err
+		}
+	}
+
+	for id, c := range byJob {
+		if known[id] {
+			continue
+		}
+		job := &Job{
+			ID:          id,
+			Extension:   c.Extension,
+			ContainerID: c.ID,
+			Status:      StatusRunning,
+			CreatedAt:   c.StartedAt.UTC(),
+		}
+		if err := m.store.Save(job); err != nil {
+			return err
+		}
+		if err := m.reconcile(ctx, job, c, true); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// reconcile brings one job in line with its container, if found
+func (m *Manager) reconcile(ctx context.Context, job *Job, c docker.JobContainer, found bool) error {
+	if job.Status.Done() {
+		if found {
+			// Left behind by a cancel or collection that failed to remove it
+			_ = m.runtime.RemoveContainer(ctx, c.ID)
+		}
+		return nil
+	}
+
+	switch {
+	case !found && job.Status == StatusStarting && m.now().Sub(job.CreatedAt) < time.Minute:
+		// Still being submitted by another r2r process
+		return nil
+	case !found:
+		m.finish(job, StatusLost, nil, "job container no longer exists")
+	case c.Running:
+		job.ContainerID = c.ID
+		job.Status = StatusRunning
+	default:
+		job.ContainerID = c.ID
+		if err := m.collectLogs(ctx, job); err != nil {
+			return err
+		}
+		code := c.ExitCode
+		job.OOMKilled = c.OOMKilled
+		switch {
+		case c.OOMKilled:
+			m.finish(job, StatusFailed, &code, "container was killed because it ran out of memory")
+		case code != 0:
+			m.finish(job, StatusFailed, &code, "")
+		default:
+			m.finish(job, StatusSucceeded, &code, "")
+		}
+		if !c.FinishedAt.IsZero() {
+			finished := c.FinishedAt.UTC()
+			job.FinishedAt = &finished
+		}
+	}
+
+	if err := m.store.Save(job); err != nil {
+		return err
+	}
+	if job.Status.Done() && found {
+		if err := m.runtime.RemoveContainer(ctx, c.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Cancel stops a running job and keeps the output it produced so far
+func (m *Manager) Cancel(ctx context.Context, id string) (*Job, error) {
+	job, err := m.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if job.Status.Done() {
+		return job, fmt.Errorf("job %s has already finished: %s", job.ID, job.Status)
+	}
+
+	if job.ContainerID != "" {
+		if err := m.collectLogs(ctx, job); err != nil {
+			return nil, err
+		}
+		if err := m.runtime.ShutdownContainer(ctx, job.ContainerID); err != nil {
+			return nil, fmt.Errorf("failed to stop job %s: %w", job.ID, err)
+		}
+	}
+	m.finish(job, StatusCancelled, nil, "")
+	if err := m.store.Save(job); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// Logs writes the output of a job. The output of a running job comes from
+// its container; with follow it is streamed until the job finishes. The
+// output of a finished job comes from the log file kept in the job
+// directory.
+func (m *Manager) Logs(ctx context.Context, id string, follow bool, stdout, stderr io.Writer) error {
+	job, err := m.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if !job.Status.Done() && job.ContainerID != "" {
+		if err := m.runtime.JobLogs(ctx, job.ContainerID, follow, stdout, stderr); err != nil {
+			return err
+		}
+		if follow {
+			// Collect the result now the container has exited
+			return m.Sync(ctx)
+		}
+		return nil
+	}
+
+	f, err := os.Open(m.store.LogPath(job.ID))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read output of job %s: %w", job.ID, err)
+	}
+	defer f.Close()
+	_, err = io.Copy(stdout, f)
+	return err
+}
+
+// collectLogs copies the container output of a job into its log file
+func (m *Manager) collectLogs(ctx context.Context, job *Job) error {
+	f, err := os.Create(m.store.LogPath(job.ID))
+	if err != nil {
+		return fmt.Errorf("failed to save output of job %s: %w", job.ID, err)
+	}
+	defer f.Close()
+	if err := m.runtime.JobLogs(ctx, job.ContainerID, false, f, f); err != nil {
+		return fmt.Errorf("failed to save output of job %s: %w", job.ID, err)
+	}
+	return nil
+}
+
+// finish records the final state of a job
+func (m *Manager) finish(job *Job, status Status, exitCode *int, reason string) {
+	now := m.now().UTC()
+	job.Status = status
+	job.ExitCode = exitCode
+	job.Error = reason
+	job.FinishedAt = &now
+}
@@ -0,0 +1,203 @@
+//go:build L0
+// +build L0
+
+package jobs
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/ready-to-release/eac/src/cli/internal/docker"
+)
+
+// fakeRuntime keeps job containers in memory
+type fakeRuntime struct {
+	containers map[string]*docker.JobContainer // by container ID
+	logs       map[string]string
+	removed    []string
+	startErr   error
+}
+
+func newFakeRuntime() *fakeRuntime {
+	return &fakeRuntime{containers: map[string]*docker.JobContainer{}, logs: map[string]string{}}
+}
+
+func (f *fakeRuntime) StartJobContainer(ctx context.Context, ext *docker.ExtensionConfig, args []string, jobID string) (string, error) {
+	if f.startErr != nil {
+		return "", f.startErr
+	}
+	id := "c-" + jobID
+	f.containers[id] = &docker.JobContainer{ID: id, JobID: jobID, Extension: ext.Name, Running: true}
+	return id, nil
+}
+
+func (f *fakeRuntime) ListJobContainers(ctx context.Context) ([]docker.JobContainer, error) {
+	var list []docker.JobContainer
+	for _, c := range f.containers {
+		list = append(list, *c)
+	}
+	return list, nil
+}
+
+func (f *fakeRuntime) JobLogs(ctx context.Context, containerID string, follow bool, stdout, stderr io.Writer) error {
+	_, err := io.WriteString(stdout, f.logs[containerID])
+	return err
+}
+
+func (f *fakeRuntime) ShutdownContainer(ctx context.Context, containerID string) error {
+	return f.RemoveContainer(ctx, containerID)
+}
+
+func (f *fakeRuntime) RemoveContainer(ctx context.Context, containerID string) error {
+	delete(f.containers, containerID)
+	f.removed = append(f.removed, containerID)
+	return nil
+}
+
+func newTestManager(t *testing.T) (*Manager, *fakeRuntime) {
+	runtime := newFakeRuntime()
+	return NewManager(NewStore(t.TempDir()), runtime), runtime
+}
+
+func TestSubmitAndCollect(t *testing.T) {
+	ctx := context.Background()
+	m, runtime := newTestManager(t)
+
+	job, err := m.Submit(ctx, &docker.ExtensionConfig{Name: "go", Image: "go:latest"}, []string{"test"})
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	if job.Status != StatusRunning || job.ContainerID == "" {
+		t.Fatalf("Expected a running job with a container, got %+v", job)
+	}
+
+	c := runtime.containers[job.ContainerID]
+	c.Running = false
+	c.ExitCode = 2
+	runtime.logs[c.ID] = "FAIL\n"
+
+	job, err = m.Get(ctx, job.ID)
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if job.Status != StatusFailed || job.ExitCode == nil || *job.ExitCode != 2 || job.FinishedAt == nil {
+		t.Errorf("Expected a failed job with exit code 2, got %+v", job)
+	}
+	if _, exists := runtime.containers[c.ID]; exists {
+		t.Error("Expected the finished container to be removed")
+	}
+
+	var out bytes.Buffer
+	if err := m.Logs(ctx, job.ID, false, &out, &out); err != nil {
+		t.Fatalf("Logs failed: %v", err)
+	}
+	if out.String() != "FAIL\n" {
+		t.Errorf("Expected the collected output, got %q", out.String())
+	}
+}
+
+func TestSyncAdoptsLabeledContainers(t *testing.T) {
+	ctx := context.Background()
+	m, runtime := newTestManager(t)
+	runtime.containers["abc"] = &docker.JobContainer{ID: "abc", JobID: "20261016-120000-aaaaaa", Extension: "python", Running: true}
+
+	jobs, err := m.List(ctx)
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(jobs) != 1 || jobs[0].Extension != "python" || jobs[0].Status != StatusRunning || jobs[0].ContainerID != "abc" {
+		t.Errorf("Expected the container to be adopted as a running job, got %+v", jobs)
+	}
+}
+
+func TestSyncMarksMissingContainerLost(t *testing.T) {
+	ctx := context.Background()
+	m, runtime := newTestManager(t)
+
+	job, err := m.Submit(ctx, &docker.ExtensionConfig{Name: "go"}, nil)
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	delete(runtime.containers, job.ContainerID)
+
+	job, err = m.Get(ctx, job.ID)
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if job.Status != StatusLost {
+		t.Errorf("Expected status %s, got %s", StatusLost, job.Status)
+	}
+}
+
+func TestCancel(t *testing.T) {
+	ctx := context.Background()
+	m, runtime := newTestManager(t)
+
+	job, err := m.Submit(ctx, &docker.ExtensionConfig{Name: "go"}, nil)
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	runtime.logs[job.ContainerID] = "partial\n"
+
+	job, err = m.Cancel(ctx, job.ID)
+	if err != nil {
+		t.Fatalf("Cancel failed: %v", err)
+	}
+	if job.Status != StatusCancelled {
+		t.Errorf("Expected status %s, got %s", StatusCancelled, job.Status)
+	}
+	if len(runtime.containers) != 0 {
+		t.Error("Expected the container to be stopped and removed")
+	}
+
+	if _, err := m.Cancel(ctx, job.ID); err == nil {
+		t.Error("Expected an error cancelling a finished job")
+	}
+	if _, err := m.Cancel(ctx, "unknown"); !errors.Is(err, ErrJobNotFound) {
+		t.Errorf("Expected ErrJobNotFound, got %v", err)
+	}
+}
+
+func TestSubmitStartFailure(t *testing.T) {
+	m, runtime := newTestManager(t)
+	runtime.startErr = errors.New("no such image")
+
+	job, err := m.Submit(context.Background(), &docker.ExtensionConfig{Name: "go"}, nil)
+	if err == nil {
+		t.Fatal("Expected an error")
+	}
+	if job == nil || job.Status != StatusFailed || job.Error != "no such image" {
+		t.Errorf("Expected a failed job recording the error, got %+v", job)
+	}
+}
+
+func TestStoreListOrder(t *testing.T) {
+	store := NewStore(t.TempDir())
+	base := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	for i, id := range []string{"b", "a", "c"} {
+		job := &Job{ID: id, Status: StatusRunning, CreatedAt: base.Add(time.Duration(i) * time.Minute)}
+		if err := store.Save(job); err != nil {
+			t.Fatalf("Save failed: %v", err)
+		}
+	}
+
+	jobs, err := store.List()
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	var ids []string
+	for _, job := range jobs {
+		ids = append(ids, job.ID)
+	}
+	if len(ids) != 3 || ids[0] != "b" || ids[1] != "a" || ids[2] != "c" {
+		t.Errorf("Expected jobs in submission order, got %v", ids)
+	}
+
+	if _, err := store.Load("../b"); !errors.Is(err, ErrJobNotFound) {
+		t.Errorf("Expected ErrJobNotFound for a path, got %v", err)
+	}
+}
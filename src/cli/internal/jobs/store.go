@@ -0,0 +1,165 @@
+// Package jobs runs extension commands in the background. Job state is kept
+// under .r2r/jobs so any later r2r process can list, follow and cancel a job.
+package jobs
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// Status is the state of a background job
+type Status string
+
+const (
+	StatusStarting  Status = "starting"
+	StatusRunning   Status = "running"
+	StatusSucceeded Status = "succeeded"
+	StatusFailed    Status = "failed"
+	StatusCancelled Status = "cancelled"
+	// StatusLost marks a job whose container disappeared before its result
+	// was collected
+	StatusLost Status = "lost"
+)
+
+// Done reports whether the job has finished and will not change again
+func (s Status) Done() bool {
+	switch s {
+	case StatusSucceeded, StatusFailed, StatusCancelled, StatusLost:
+		return true
+	}
+	return false
+}
+
+// Job is the persisted state of a background job
+type Job struct {
+	ID          string     `json:"id"`
+	Extension   string     `json:"extension"`
+	Image       string     `json:"image,omitempty"`
+	Args        []string   `json:"args,omitempty"`
+	ContainerID string     `json:"container_id,omitempty"`
+	Status      Status     `json:"status"`
+	ExitCode    *int       `json:"exit_code,omitempty"`
+	OOMKilled   bool       `json:"oom_killed,omitempty"`
+	Error       string     `json:"error,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	FinishedAt  *time.Time `json:"finished_at,omitempty"`
+}
+
+// ErrJobNotFound is returned for an unknown job ID
+var ErrJobNotFound = errors.New("job not found")
+
+const (
+	jobFile = "job.json"
+	logFile = "output.log"
+)
+
+// Store persists jobs as one directory per job
+type Store struct {
+	dir string
+}
+
+// NewStore returns a store rooted at dir
+func NewStore(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+// DefaultDir returns the jobs directory of a repository
+func DefaultDir(repoRoot string) string {
+	return filepath.Join(repoRoot, ".r2r", "jobs")
+}
+
+// NewID returns a job ID that sorts by submission time
+func NewID(now time.Time) string {
+	suffix := make([]byte, 3)
+	_, _ = rand.Read(suffix)
+	return now.UTC().Format("20060102-150405") + "-" + hex.EncodeToString(suffix)
+}
+
+// Save writes the job state, replacing it atomically
+func (s *Store) Save(job *Job) error {
+	dir := filepath.Join(s.dir, job.ID)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create job directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(job, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode job %s: %w", job.ID, err)
+	}
+
+	tmp, err := os.CreateTemp(dir, jobFile+".*")
+	if err != nil {
+		return fmt.Errorf("failed to write job %s: %w", job.ID, err)
+	}
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write job %s: %w", job.ID, err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write job %s: %w", job.ID, err)
+	}
+	if err := os.Rename(tmp.Name(), filepath.Join(dir, jobFile)); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write job %s: %w", job.ID, err)
+	}
+	return nil
+}
+
+// Load reads a job by ID
+func (s *Store) Load(id string) (*Job, error) {
+	if id == "" || filepath.Base(id) != id {
+		return nil, fmt.Errorf("%w: %s", ErrJobNotFound, id)
+	}
+	data, err := os.ReadFile(filepath.Join(s.dir, id, jobFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("%w: %s", ErrJobNotFound, id)
+		}
+		return nil, fmt.Errorf("failed to read job %s: %w", id, err)
+	}
+	var job Job
+	if err := json.Unmarshal(data, &job); err != nil {
+		return nil, fmt.Errorf("failed to parse job %s: %w", id, err)
+	}
+	return &job, nil
+}
+
+// List returns all jobs, oldest first. Unreadable job directories are
+// skipped.
+func (s *Store) List() ([]*Job, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read jobs directory: %w", err)
+	}
+
+	var jobs []*Job
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		job, err := s.Load(entry.Name())
+		if err != nil {
+			continue
+		}
+		jobs = append(jobs, job)
+	}
+	sort.SliceStable(jobs, func(i, j int) bool { return jobs[i].CreatedAt.Before(jobs[j].CreatedAt) })
+	return jobs, nil
+}
+
+// LogPath returns the file the output of a finished job is kept in
+func (s *Store) LogPath(id string) string {
+	return filepath.Join(s.dir, id, logFile)
+}
@@ -11,6 +11,9 @@ import (
 	"time"
 
 	"github.com/hitoshi44/go-uid64"
+	"github.com/ready-to-release/eac/src/cli/internal/terminal"
+	"github.com/ready-to-release/eac/src/core/redact"
+	"github.com/ready-to-release/eac/src/core/reporoot"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 )
@@ -63,6 +66,30 @@ func (lw *LevelWriter) WriteLevel(level zerolog.Level, p []byte) (n int, err err
 	return len(p), nil
 }
 
+// redactWriter masks credentials in each log entry before it reaches the
+// file or console, keeping the level of the entry for level writers
+type redactWriter struct {
+	w io.Writer
+}
+
+func (rw redactWriter) Write(p []byte) (int, error) {
+	if _, err := rw.w.Write(redact.Bytes(p)); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func (rw redactWriter) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	lw, ok := rw.w.(zerolog.LevelWriter)
+	if !ok {
+		return rw.Write(p)
+	}
+	if _, err := lw.WriteLevel(level, redact.Bytes(p)); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
 // Initialize creates and configures the global logger instance
 func Initialize(cfg Config) error {
 	var err error
@@ -142,6 +169,10 @@ func New(cfg Config) (*Logger, error) {
 		writer = zerolog.MultiLevelWriter(writers...)
 	}
 
+	// Tokens end up in messages and fields (command lines, auth errors), so
+	// every entry is redacted before it is written
+	writer = redactWriter{w: writer}
+
 	// Create logger with standard fields
 	zlog := zerolog.New(writer).With().
 		Str("run_id", cfg.RunID).
@@ -287,6 +318,8 @@ func createFileWriter(filename string) (io.Writer, error) {
 }
 
 func createConsoleWriter(cfg Config, minLevel zerolog.Level) io.Writer {
+	noColor := !terminal.Detect().ColorEnabled()
+
 	if cfg.JSON || cfg.Environment == "ci" || cfg.Environment == "production" {
 		// Use plain JSON output for CI/production or when JSON is requested
 		return &LevelWriter{Writer: os.Stdout, level: minLevel}
@@ -303,7 +336,7 @@ func createConsoleWriter(cfg Config, minLevel zerolog.Level) io.Writer {
 		consoleWriter := zerolog.ConsoleWriter{
 			Out:        os.Stdout, // Send to stdout in minimal mode to avoid shell error interpretation
 			TimeFormat: "", // No timestamp for minimal output
-			NoColor:    os.Getenv("NO_COLOR") != "",
+			NoColor:    noColor,
 			FormatLevel: func(i interface{}) string {
 				if level, ok := i.(string); ok {
 					switch level {
@@ -352,7 +385,7 @@ func createConsoleWriter(cfg Config, minLevel zerolog.Level) io.Writer {
 	consoleWriter := zerolog.ConsoleWriter{
 		Out:        os.Stderr, // Send logs to stderr to keep stdout clean
 		TimeFormat: time.Kitchen,
-		NoColor:    os.Getenv("NO_COLOR") != "",
+		NoColor:    noColor,
 	}
 
 	return &LevelWriter{Writer: consoleWriter, level: minLevel}
@@ -364,30 +397,17 @@ func getVersion() string {
 }
 
 func getLogFilePath() string {
-	// Try to find repository root by looking for .git directory first
+	if root, err := reporoot.Find(""); err == nil {
+		return filepath.Join(root, "r2r.log")
+	}
+
 	currentDir, err := os.Getwd()
 	if err != nil {
 		return "r2r.log" // fallback to current directory
 	}
 
-	// First pass: look for .git directory (most reliable indicator of repo root)
+	// Look for go.mod if no repository was found (fallback)
 	dir := currentDir
-	for {
-		gitPath := filepath.Join(dir, ".git")
-		if _, err := os.Stat(gitPath); err == nil {
-			return filepath.Join(dir, "r2r.log")
-		}
-
-		parent := filepath.Dir(dir)
-		if parent == dir {
-			// Reached root directory, couldn't find .git
-			break
-		}
-		dir = parent
-	}
-
-	// Second pass: look for go.mod if no .git found (fallback)
-	dir = currentDir
 	for {
 		goModPath := filepath.Join(dir, "go.mod")
 		if _, err := os.Stat(goModPath); err == nil {
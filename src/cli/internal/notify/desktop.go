@@ -0,0 +1,44 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+)
+
+// The title and message reach the notification commands through the
+// environment, so they never need quoting for a script
+const (
+	titleEnv   = "R2R_NOTIFY_TITLE"
+	messageEnv = "R2R_NOTIFY_MESSAGE"
+)
+
+const appleScript = `display notification (system attribute "` + messageEnv + `") with title (system attribute "` + titleEnv + `")`
+
+const windowsToastScript = `[Windows.UI.Notifications.ToastNotificationManager, Windows.UI.Notifications, ContentType = WindowsRuntime] > $null
+$xml = [Windows.UI.Notifications.ToastNotificationManager]::GetTemplateContent([Windows.UI.Notifications.ToastTemplateType]::ToastText02)
+$text = $xml.GetElementsByTagName('text')
+$text.Item(0).AppendChild($xml.CreateTextNode($env:` + titleEnv + `)) > $null
+$text.Item(1).AppendChild($xml.CreateTextNode($env:` + messageEnv + `)) > $null
+[Windows.UI.Notifications.ToastNotificationManager]::CreateToastNotifier('r2r').Show([Windows.UI.Notifications.ToastNotification]::new($xml))`
+
+// showDesktop shows a notification with the tool of the platform: osascript
+// on macOS, a toast through PowerShell on Windows, notify-send elsewhere
+func showDesktop(ctx context.Context, title, message string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.CommandContext(ctx, "osascript", "-e", appleScript)
+	case "windows":
+		cmd = exec.CommandContext(ctx, "powershell", "-NoProfile", "-NonInteractive", "-Command", windowsToastScript)
+	default:
+		cmd = exec.CommandContext(ctx, "notify-send", "--app-name=r2r", title, message)
+	}
+	cmd.Env = append(os.Environ(), titleEnv+"="+title, messageEnv+"="+message)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%s: %w: %s", cmd.Path, err, output)
+	}
+	return nil
+}
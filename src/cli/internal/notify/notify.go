@@ -0,0 +1,165 @@
+// Package notify announces the end of long operations, such as image pulls
+// and extension runs, with a desktop notification, a webhook or a Slack
+// message, so users can switch to other work while they run. Operations
+// shorter than the threshold, and everything in CI, stay silent.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/ready-to-release/eac/src/cli/internal/conf"
+	"github.com/ready-to-release/eac/src/cli/internal/terminal"
+	"github.com/rs/zerolog/log"
+)
+
+// DefaultThreshold is how long an operation must take to notify when
+// notifications.threshold is not set
+const DefaultThreshold = 60 * time.Second
+
+// sendTimeout bounds sending one notification
+const sendTimeout = 5 * time.Second
+
+// Event is the end of an operation
+type Event struct {
+	Operation       string  `json:"operation"` // e.g. "run", "install", "build"
+	Subject         string  `json:"subject"`   // what it ran on, e.g. the extension names
+	Success         bool    `json:"success"`
+	Message         string  `json:"message,omitempty"`
+	DurationSeconds float64 `json:"duration_seconds"`
+}
+
+// Title is the one line summary of the event
+func (e Event) Title() string {
+	outcome := "finished"
+	if !e.Success {
+		outcome = "failed"
+	}
+	if e.Subject == "" {
+		return fmt.Sprintf("r2r %s %s", e.Operation, outcome)
+	}
+	return fmt.Sprintf("r2r %s %s %s", e.Operation, e.Subject, outcome)
+}
+
+// Notifier sends the notifications configured in notifications. A nil
+// Notifier sends nothing.
+type Notifier struct {
+	threshold time.Duration
+	desktop   bool
+	webhook   string
+	slack     string
+	client    *http.Client
+	// showDesktop shows a desktop notification; replaced in tests
+	showDesktop func(ctx context.Context, title, message string) error
+}
+
+// New returns the notifier of cfg, nil when no notification is configured
+// or r2r runs in CI
+func New(cfg *conf.Config) *Notifier {
+	if cfg == nil || cfg.Notifications == nil || terminal.IsCI() {
+		return nil
+	}
+	return newNotifier(cfg.Notifications)
+}
+
+func newNotifier(cfg *conf.Notifications) *Notifier {
+	if !cfg.Desktop && cfg.Webhook == "" && cfg.SlackWebhook == "" {
+		return nil
+	}
+	threshold := DefaultThreshold
+	if cfg.Threshold > 0 {
+		threshold = time.Duration(cfg.Threshold) * time.Second
+	}
+	return &Notifier{
+		threshold:   threshold,
+		desktop:     cfg.Desktop,
+		webhook:     cfg.Webhook,
+		slack:       cfg.SlackWebhook,
+		client:      &http.Client{Timeout: sendTimeout},
+		showDesktop: showDesktop,
+	}
+}
+
+// Done notifies the end of an operation started at started, unless it took
+// less than the threshold. Failures to notify are logged, never returned:
+// they must not change the outcome of the operation.
+func (n *Notifier) Done(ctx context.Context, event Event, started time.Time) {
+	if n == nil {
+		return
+	}
+	elapsed := time.Since(started)
+	if elapsed < n.threshold {
+		return
+	}
+	event.DurationSeconds = elapsed.Round(time.Millisecond).Seconds()
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), sendTimeout)
+	defer cancel()
+	if err := n.send(ctx, event, elapsed); err != nil {
+		log.Warn().Err(err).Str("operation", event.Operation).Msg("Failed to send notification")
+	}
+}
+
+func (n *Notifier) send(ctx context.Context, event Event, elapsed time.Duration) error {
+	message := fmt.Sprintf("after %s", elapsed.Round(time.Second))
+	if event.Message != "" {
+		message = event.Message + " " + message
+	}
+
+	var errs []error
+	if n.desktop {
+		if err := n.showDesktop(ctx, event.Title(), message); err != nil {
+			errs = append(errs, fmt.Errorf("desktop notification: %w", err))
+		}
+	}
+	if n.webhook != "" {
+		if err := n.post(ctx, n.webhook, event); err != nil {
+			errs = append(errs, fmt.Errorf("webhook: %w", err))
+		}
+	}
+	if n.slack != "" {
+		payload := map[string]string{"text": event.Title() + " " + message}
+		if err := n.post(ctx, n.slack, payload); err != nil {
+			errs = append(errs, fmt.Errorf("slack: %w", err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// post sends payload as JSON to target. Errors name only the host of
+// target: the path of a webhook, such as a Slack incoming webhook, is its
+// secret.
+func (n *Notifier) post(ctx context.Context, target string, payload any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	host := "the webhook"
+	if parsed, err := url.Parse(target); err == nil && parsed.Host != "" {
+		host = parsed.Host
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("invalid URL for %s", host)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := n.client.Do(req)
+	if err != nil {
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("posting to %s: %w", host, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("%s returned %s", host, resp.Status)
+	}
+	return nil
+}
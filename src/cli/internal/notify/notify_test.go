@@ -0,0 +1,112 @@
+//go:build L0
+// +build L0
+
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ready-to-release/eac/src/cli/internal/conf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recorder is a webhook endpoint remembering the bodies it received
+type recorder struct {
+	mu     sync.Mutex
+	bodies []map[string]any
+}
+
+func (r *recorder) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	var body map[string]any
+	_ = json.NewDecoder(req.Body).Decode(&body)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.bodies = append(r.bodies, body)
+}
+
+func TestEventTitle(t *testing.T) {
+	assert.Equal(t, "r2r run go finished", Event{Operation: "run", Subject: "go", Success: true}.Title())
+	assert.Equal(t, "r2r install failed", Event{Operation: "install"}.Title())
+}
+
+func TestNewWithoutTargets(t *testing.T) {
+	assert.Nil(t, newNotifier(&conf.Notifications{Threshold: 10}))
+	assert.Nil(t, New(&conf.Config{}))
+
+	// A nil notifier sends nothing
+	var n *Notifier
+	n.Done(context.Background(), Event{Operation: "run"}, time.Now().Add(-time.Hour))
+}
+
+func TestDone(t *testing.T) {
+	webhook, slack := &recorder{}, &recorder{}
+	webhookServer := httptest.NewServer(webhook)
+	defer webhookServer.Close()
+	slackServer := httptest.NewServer(slack)
+	defer slackServer.Close()
+
+	n := newNotifier(&conf.Notifications{
+		Threshold:    30,
+		Desktop:      true,
+		Webhook:      webhookServer.URL,
+		SlackWebhook: slackServer.URL,
+	})
+	require.NotNil(t, n)
+	var titles []string
+	n.showDesktop = func(ctx context.Context, title, message string) error {
+		titles = append(titles, title)
+		return nil
+	}
+
+	event := Event{Operation: "run", Subject: "go", Message: "exit code 2"}
+	n.Done(context.Background(), event, time.Now().Add(-10*time.Second))
+	assert.Empty(t, titles, "operations shorter than the threshold should not notify")
+
+	n.Done(context.Background(), event, time.Now().Add(-45*time.Second))
+	assert.Equal(t, []string{"r2r run go failed"}, titles)
+	require.Len(t, webhook.bodies, 1)
+	assert.Equal(t, "run", webhook.bodies[0]["operation"])
+	assert.Equal(t, false, webhook.bodies[0]["success"])
+	assert.InDelta(t, 45, webhook.bodies[0]["duration_seconds"], 1)
+	require.Len(t, slack.bodies, 1)
+	assert.Equal(t, "r2r run go failed exit code 2 after 45s", slack.bodies[0]["text"])
+}
+
+func TestDoneReportsFailedWebhooks(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	n := newNotifier(&conf.Notifications{Webhook: server.URL})
+	err := n.send(context.Background(), Event{Operation: "build"}, time.Minute)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "404")
+}
+
+func TestDoneKeepsWebhookPathsOutOfErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+	secret := "/services/T0001/B0001/XXXXXXXXXXXXXXXXXXXXXXXX"
+
+	// An answer and a failed connection
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closed.Close()
+	for _, webhook := range []string{server.URL + secret, closed.URL + secret} {
+		n := newNotifier(&conf.Notifications{SlackWebhook: webhook})
+		err := n.send(context.Background(), Event{Operation: "run"}, time.Minute)
+		require.Error(t, err)
+		assert.NotContains(t, err.Error(), "XXXXXXXX")
+		assert.Contains(t, err.Error(), "slack: ")
+		assert.Contains(t, err.Error(), "127.0.0.1")
+	}
+}
@@ -0,0 +1,230 @@
+// Package pathfilter renders the source globs of the module contracts as
+// GitHub Actions path filters, and checks filters pasted into workflows
+// against the contracts.
+//
+// Every rendered filter starts with a marker comment naming its module:
+//
+//	# r2r modules paths src-cli
+//	paths:
+//	  - 'src/cli/**'
+//
+// The marker lets a check find the filter again wherever it was pasted, in
+// an on.push.paths block or in a dorny/paths-filter config.
+package pathfilter
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/ready-to-release/eac/src/core/contracts/modules"
+)
+
+// Output formats
+const (
+	FormatGHA   = "gha"   // a paths: block per unit, for on.push and on.pull_request
+	FormatDorny = "dorny" // a dorny/paths-filter filters config
+)
+
+// MarkerPrefix starts the comment before each rendered filter
+const MarkerPrefix = "# r2r modules paths "
+
+// Unit is a deployable unit with the globs of its sources
+type Unit struct {
+	Moniker  string
+	Name     string
+	Patterns []string
+}
+
+// Units returns the units of the given monikers, or all units with source
+// globs when none are given, sorted by moniker
+func Units(registry *modules.Registry, monikers []string) ([]Unit, error) {
+	if len(monikers) == 0 {
+		monikers = registry.AllMonikers()
+	}
+
+	var units []Unit
+	for _, moniker := range monikers {
+		module, ok := registry.Get(moniker)
+		if !ok {
+			return nil, fmt.Errorf("no module contract %q", moniker)
+		}
+		if module.IsDefinitionsFile() {
+			continue
+		}
+		patterns := module.GetGlobPatterns()
+		if len(patterns) == 0 {
+			continue
+		}
+		units = append(units, Unit{Moniker: moniker, Name: module.Name, Patterns: patterns})
+	}
+	sort.Slice(units, func(i, j int) bool { return units[i].Moniker < units[j].Moniker })
+	return units, nil
+}
+
+// Render writes the filters of units in format, separated by blank lines
+func Render(w io.Writer, units []Unit, format string) error {
+	if format != FormatGHA && format != FormatDorny {
+		return fmt.Errorf("unknown format %q (use %s or %s)", format, FormatGHA, FormatDorny)
+	}
+
+	for i, unit := range units {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		key := "paths"
+		if format == FormatDorny {
+			key = unit.Moniker
+		}
+		fmt.Fprintf(w, "%s%s\n%s:\n", MarkerPrefix, unit.Moniker, key)
+		for _, pattern := range unit.Patterns {
+			fmt.Fprintf(w, "  - %s\n", quote(pattern))
+		}
+	}
+	return nil
+}
+
+// quote single-quotes a glob, as globs starting with * or containing : are
+// not plain YAML scalars
+func quote(pattern string) string {
+	return "'" + strings.ReplaceAll(pattern, "'", "''") + "'"
+}
+
+// Drift is a filter that no longer matches its contract
+type Drift struct {
+	Moniker string
+	Line    int      // line of the marker
+	Missing []string // globs of the contract not in the filter
+	Extra   []string // globs in the filter not in the contract
+	Message string   // set instead of Missing and Extra when the filter cannot be compared
+}
+
+func (d Drift) String() string {
+	if d.Message != "" {
+		return d.Moniker + ": " + d.Message
+	}
+	var parts []string
+	if len(d.Missing) > 0 {
+		parts = append(parts, "missing "+strings.Join(d.Missing, ", "))
+	}
+	if len(d.Extra) > 0 {
+		parts = append(parts, "not in the contract "+strings.Join(d.Extra, ", "))
+	}
+	return d.Moniker + ": " + strings.Join(parts, "; ")
+}
+
+// Filter is a marked filter found in a file
+type Filter struct {
+	Moniker  string
+	Line     int
+	Patterns []string
+	err      string
+}
+
+var (
+	listItemPattern = regexp.MustCompile(`^\s*-\s+(.+?)\s*$`)
+	keyPattern      = regexp.MustCompile(`^\s*[^\s#-][^:]*:\s*(\|)?\s*$`)
+)
+
+// Parse finds the marked filters in a workflow or filters file: the key
+// line after each marker and the list items that follow it
+func Parse(r io.Reader) ([]Filter, error) {
+	scanner := bufio.NewScanner(r)
+	var filters []Filter
+	current := -1 // index of the filter whose items are read
+	expectKey := false
+	lineNumber := 0
+
+	for scanner.Scan() {
+		lineNumber++
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+
+		if strings.HasPrefix(trimmed, MarkerPrefix) {
+			filters = append(filters, Filter{Moniker: strings.TrimSpace(strings.TrimPrefix(trimmed, MarkerPrefix)), Line: lineNumber})
+			current = len(filters) - 1
+			expectKey = true
+			continue
+		}
+		if current < 0 || trimmed == "" {
+			continue
+		}
+
+		if expectKey {
+			expectKey = false
+			if !keyPattern.MatchString(line) {
+				filters[current].err = "the marker is not followed by a key such as paths:"
+				current = -1
+			}
+			continue
+		}
+
+		match := listItemPattern.FindStringSubmatch(line)
+		if match == nil || strings.HasPrefix(trimmed, "#") {
+			current = -1
+			continue
+		}
+		filters[current].Patterns = append(filters[current].Patterns, unquote(match[1]))
+	}
+	return filters, scanner.Err()
+}
+
+// unquote removes YAML quotes from a scalar
+func unquote(value string) string {
+	if len(value) >= 2 {
+		switch {
+		case value[0] == '\'' && value[len(value)-1] == '\'':
+			return strings.ReplaceAll(value[1:len(value)-1], "''", "'")
+		case value[0] == '"' && value[len(value)-1] == '"':
+			return strings.ReplaceAll(value[1:len(value)-1], `\"`, `"`)
+		}
+	}
+	return value
+}
+
+// Check compares the marked filters of a file with the units. A filter for
+// a module without a contract, or without globs, is a drift too.
+func Check(filters []Filter, units []Unit) []Drift {
+	byMoniker := make(map[string]Unit, len(units))
+	for _, unit := range units {
+		byMoniker[unit.Moniker] = unit
+	}
+
+	var drifts []Drift
+	for _, filter := range filters {
+		drift := Drift{Moniker: filter.Moniker, Line: filter.Line}
+		unit, ok := byMoniker[filter.Moniker]
+		switch {
+		case filter.err != "":
+			drift.Message = filter.err
+		case !ok:
+			drift.Message = "no module contract with source globs"
+		default:
+			drift.Missing = difference(unit.Patterns, filter.Patterns)
+			drift.Extra = difference(filter.Patterns, unit.Patterns)
+			if len(drift.Missing) == 0 && len(drift.Extra) == 0 {
+				continue
+			}
+		}
+		drifts = append(drifts, drift)
+	}
+	return drifts
+}
+
+// difference returns the items of a that are not in b, in the order of a
+func difference(a, b []string) []string {
+	in := make(map[string]bool, len(b))
+	for _, item := range b {
+		in[item] = true
+	}
+	var diff []string
+	for _, item := range a {
+		if !in[item] {
+			diff = append(diff, item)
+		}
+	}
+	return diff
+}
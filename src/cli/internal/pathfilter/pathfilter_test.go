@@ -0,0 +1,109 @@
+//go:build L0
+// +build L0
+
+package pathfilter
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/ready-to-release/eac/src/core/contracts"
+	"github.com/ready-to-release/eac/src/core/contracts/modules"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testRegistry(t *testing.T) *modules.Registry {
+	registry := modules.NewRegistry("0.1.0", "")
+	for _, base := range []contracts.BaseContract{
+		{Moniker: "src-cli", Name: "CLI", Source: contracts.Source{Root: "src/cli", Includes: []string{"**", "/contracts/cli/**"}}},
+		{Moniker: "docs", Name: "Docs", Source: contracts.Source{Root: "docs", Includes: []string{"**/*.md"}}},
+		{Moniker: "definitions", Type: "definitions-type", Source: contracts.Source{Includes: []string{"definitions.yml"}}},
+		{Moniker: "empty", Name: "No sources"},
+	} {
+		require.NoError(t, registry.Add(modules.NewModuleContract(base, "")))
+	}
+	return registry
+}
+
+func TestUnits(t *testing.T) {
+	units, err := Units(testRegistry(t), nil)
+	require.NoError(t, err)
+	require.Len(t, units, 2, "definitions files and modules without sources are left out")
+	assert.Equal(t, "docs", units[0].Moniker)
+	assert.Equal(t, []string{"src/cli/**", "contracts/cli/**"}, units[1].Patterns)
+
+	_, err = Units(testRegistry(t), []string{"missing"})
+	assert.Error(t, err)
+}
+
+func TestRender(t *testing.T) {
+	units, err := Units(testRegistry(t), []string{"src-cli"})
+	require.NoError(t, err)
+
+	var gha bytes.Buffer
+	require.NoError(t, Render(&gha, units, FormatGHA))
+	assert.Equal(t, "# r2r modules paths src-cli\npaths:\n  - 'src/cli/**'\n  - 'contracts/cli/**'\n", gha.String())
+
+	var dorny bytes.Buffer
+	require.NoError(t, Render(&dorny, units, FormatDorny))
+	assert.Equal(t, "# r2r modules paths src-cli\nsrc-cli:\n  - 'src/cli/**'\n  - 'contracts/cli/**'\n", dorny.String())
+
+	assert.Error(t, Render(&dorny, units, "json"))
+}
+
+func TestParseAndCheck(t *testing.T) {
+	units, err := Units(testRegistry(t), nil)
+	require.NoError(t, err)
+
+	workflow := `on:
+  push:
+    # r2r modules paths src-cli
+    paths:
+      - 'src/cli/**'
+      - "contracts/cli/**"
+  pull_request:
+    # r2r modules paths docs
+    paths:
+      - docs/*.md
+      - 'README.md'
+jobs:
+  changes:
+    steps:
+      - uses: dorny/paths-filter@v3
+        with:
+          filters: |
+            # r2r modules paths removed
+            removed:
+              - 'old/**'
+            # r2r modules paths src-cli
+            - 'src/cli/**'
+`
+	filters, err := Parse(strings.NewReader(workflow))
+	require.NoError(t, err)
+	require.Len(t, filters, 4)
+	assert.Equal(t, Filter{Moniker: "src-cli", Line: 3, Patterns: []string{"src/cli/**", "contracts/cli/**"}}, filters[0])
+	assert.Equal(t, []string{"docs/*.md", "README.md"}, filters[1].Patterns)
+
+	drifts := Check(filters, units)
+	require.Len(t, drifts, 3)
+	assert.Equal(t, "docs: missing docs/**/*.md; not in the contract docs/*.md, README.md", drifts[0].String())
+	assert.Equal(t, 8, drifts[0].Line)
+	assert.Equal(t, "removed: no module contract with source globs", drifts[1].String())
+	assert.Equal(t, "src-cli: the marker is not followed by a key such as paths:", drifts[2].String())
+}
+
+func TestRenderedFiltersPassCheck(t *testing.T) {
+	units, err := Units(testRegistry(t), nil)
+	require.NoError(t, err)
+
+	for _, format := range []string{FormatGHA, FormatDorny} {
+		var buf bytes.Buffer
+		require.NoError(t, Render(&buf, units, format))
+		filters, err := Parse(&buf)
+		require.NoError(t, err)
+		assert.Len(t, filters, len(units))
+		assert.Empty(t, Check(filters, units), format)
+	}
+}
@@ -0,0 +1,1025 @@
+package policy
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Program is a compiled rule
+type Program struct {
+	source string
+	root   node
+}
+
+// Compile parses a rule
+func Compile(source string) (*Program, error) {
+	p := &parser{lexer: lexer{src: source}}
+	p.next()
+	root, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	if p.tok.kind != tokEOF {
+		return nil, p.errorf("unexpected %s", p.tok)
+	}
+	return &Program{source: source, root: root}, nil
+}
+
+// String returns the source of the rule
+func (p *Program) String() string {
+	return p.source
+}
+
+// Eval evaluates the rule with the given variables. It returns an error
+// when the rule fails or does not evaluate to a bool.
+func (p *Program) Eval(vars map[string]any) (bool, error) {
+	value, err := p.root.eval(&scope{vars: vars})
+	if err != nil {
+		return false, err
+	}
+	result, ok := value.(bool)
+	if !ok {
+		return false, fmt.Errorf("rule evaluated to %s, not a bool", typeName(value))
+	}
+	return result, nil
+}
+
+// Lexer
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokIdent
+	tokInt
+	tokFloat
+	tokString
+	tokOp
+)
+
+type token struct {
+	kind tokenKind
+	text string // operator, identifier or the decoded string
+	pos  int
+}
+
+func (t token) String() string {
+	switch t.kind {
+	case tokEOF:
+		return "end of rule"
+	case tokString:
+		return strconv.Quote(t.text)
+	}
+	return fmt.Sprintf("%q", t.text)
+}
+
+type lexer struct {
+	src string
+	pos int
+}
+
+var operators = []string{"&&", "||", "==", "!=", "<=", ">=", "!", "<", ">", "+", "-", "*", "/", "%", "(", ")", "[", "]", ",", ".", "?", ":"}
+
+func (l *lexer) next() (token, error) {
+	for l.pos < len(l.src) && unicode.IsSpace(rune(l.src[l.pos])) {
+		l.pos++
+	}
+	start := l.pos
+	if l.pos >= len(l.src) {
+		return token{kind: tokEOF, pos: start}, nil
+	}
+
+	c := l.src[l.pos]
+	switch {
+	case c == '_' || unicode.IsLetter(rune(c)):
+		for l.pos < len(l.src) && (l.src[l.pos] == '_' || unicode.IsLetter(rune(l.src[l.pos])) || unicode.IsDigit(rune(l.src[l.pos]))) {
+			l.pos++
+		}
+		return token{kind: tokIdent, text: l.src[start:l.pos], pos: start}, nil
+
+	case unicode.IsDigit(rune(c)):
+		kind := tokInt
+		l.digits()
+		// A fraction needs a digit after the dot
+		if l.pos+1 < len(l.src) && l.src[l.pos] == '.' && unicode.IsDigit(rune(l.src[l.pos+1])) {
+			kind = tokFloat
+			l.pos++
+			l.digits()
+		}
+		return token{kind: kind, text: l.src[start:l.pos], pos: start}, nil
+
+	case c == '"' || c == '\'':
+		var b strings.Builder
+		l.pos++
+		for l.pos < len(l.src) && l.src[l.pos] != c {
+			ch := l.src[l.pos]
+			if ch == '\\' && l.pos+1 < len(l.src) {
+				l.pos++
+				switch l.src[l.pos] {
+				case 'n':
+					ch = '\n'
+				case 't':
+					ch = '\t'
+				default:
+					ch = l.src[l.pos]
+				}
+			}
+			b.WriteByte(ch)
+			l.pos++
+		}
+		if l.pos >= len(l.src) {
+			return token{}, fmt.Errorf("unterminated string at offset %d", start)
+		}
+		l.pos++
+		return token{kind: tokString, text: b.String(), pos: start}, nil
+	}
+
+	for _, op := range operators {
+		if strings.HasPrefix(l.src[l.pos:], op) {
+			l.pos += len(op)
+			return token{kind: tokOp, text: op, pos: start}, nil
+		}
+	}
+	return token{}, fmt.Errorf("unexpected character %q at offset %d", c, start)
+}
+
+func (l *lexer) digits() {
+	for l.pos < len(l.src) && unicode.IsDigit(rune(l.src[l.pos])) {
+		l.pos++
+	}
+}
+
+// Parser
+
+type parser struct {
+	lexer lexer
+	tok   token
+	err   error
+}
+
+func (p *parser) next() {
+	if p.err != nil {
+		return
+	}
+	p.tok, p.err = p.lexer.next()
+	if p.err != nil {
+		p.tok = token{kind: tokEOF, pos: p.lexer.pos}
+	}
+}
+
+func (p *parser) errorf(format string, args ...any) error {
+	if p.err != nil {
+		return p.err
+	}
+	return fmt.Errorf("offset %d: %s", p.tok.pos, fmt.Sprintf(format, args...))
+}
+
+func (p *parser) isOp(op string) bool {
+	return p.tok.kind == tokOp && p.tok.text == op
+}
+
+func (p *parser) expect(op string) error {
+	if !p.isOp(op) {
+		return p.errorf("expected %q, found %s", op, p.tok)
+	}
+	p.next()
+	return nil
+}
+
+func (p *parser) parseExpr() (node, error) {
+	cond, err := p.parseBinary(0)
+	if err != nil {
+		return nil, err
+	}
+	if !p.isOp("?") {
+		return cond, nil
+	}
+	p.next()
+	then, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect(":"); err != nil {
+		return nil, err
+	}
+	otherwise, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	return &conditional{cond: cond, then: then, otherwise: otherwise}, nil
+}
+
+// precedence of binary operators, from loosest to tightest
+var precedence = map[string]int{
+	"||": 1,
+	"&&": 2,
+	"==": 3, "!=": 3, "<": 3, "<=": 3, ">": 3, ">=": 3, "in": 3,
+	"+": 4, "-": 4,
+	"*": 5, "/": 5, "%": 5,
+}
+
+func (p *parser) binaryOp() (string, int, bool) {
+	if p.tok.kind == tokOp || (p.tok.kind == tokIdent && p.tok.text == "in") {
+		if prec, ok := precedence[p.tok.text]; ok {
+			return p.tok.text, prec, true
+		}
+	}
+	return "", 0, false
+}
+
+func (p *parser) parseBinary(minPrec int) (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, prec, ok := p.binaryOp()
+		if !ok || prec <= minPrec {
+			return left, nil
+		}
+		p.next()
+		right, err := p.parseBinary(prec)
+		if err != nil {
+			return nil, err
+		}
+		left = &binary{op: op, left: left, right: right}
+	}
+}
+
+func (p *parser) parseUnary() (node, error) {
+	if p.isOp("!") || p.isOp("-") {
+		op := p.tok.text
+		p.next()
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &unary{op: op, operand: operand}, nil
+	}
+	return p.parsePostfix()
+}
+
+func (p *parser) parsePostfix() (node, error) {
+	n, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		switch {
+		case p.isOp("."):
+			p.next()
+			if p.tok.kind != tokIdent {
+				return nil, p.errorf("expected a field or method name, found %s", p.tok)
+			}
+			name, pos := p.tok.text, p.tok.pos
+			p.next()
+			if !p.isOp("(") {
+				n = &field{target: n, name: name}
+				continue
+			}
+			if name == "all" || name == "exists" {
+				n, err = p.parseMacro(n, name)
+			} else {
+				var args []node
+				if args, err = p.parseArgs(); err == nil {
+					n, err = newCall(pos, name, n, args)
+				}
+			}
+			if err != nil {
+				return nil, err
+			}
+		case p.isOp("["):
+			p.next()
+			i, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect("]"); err != nil {
+				return nil, err
+			}
+			n = &index{target: n, index: i}
+		default:
+			return n, nil
+		}
+	}
+}
+
+func (p *parser) parseMacro(target node, name string) (node, error) {
+	p.next() // (
+	if p.tok.kind != tokIdent {
+		return nil, p.errorf("%s() expects a variable name, found %s", name, p.tok)
+	}
+	variable := p.tok.text
+	p.next()
+	if err := p.expect(","); err != nil {
+		return nil, err
+	}
+	predicate, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect(")"); err != nil {
+		return nil, err
+	}
+	return &comprehension{all: name == "all", target: target, variable: variable, predicate: predicate}, nil
+}
+
+func (p *parser) parseArgs() ([]node, error) {
+	p.next() // (
+	var args []node
+	for !p.isOp(")") {
+		arg, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+		if !p.isOp(",") {
+			break
+		}
+		p.next()
+	}
+	return args, p.expect(")")
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	tok := p.tok
+	switch tok.kind {
+	case tokInt:
+		p.next()
+		v, err := strconv.ParseInt(tok.text, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("offset %d: invalid number %s", tok.pos, tok.text)
+		}
+		return &literal{value: v}, nil
+	case tokFloat:
+		p.next()
+		v, err := strconv.ParseFloat(tok.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("offset %d: invalid number %s", tok.pos, tok.text)
+		}
+		return &literal{value: v}, nil
+	case tokString:
+		p.next()
+		return &literal{value: tok.text}, nil
+	case tokIdent:
+		p.next()
+		switch tok.text {
+		case "true":
+			return &literal{value: true}, nil
+		case "false":
+			return &literal{value: false}, nil
+		case "null":
+			return &literal{value: nil}, nil
+		}
+		if !p.isOp("(") {
+			return &ident{name: tok.text}, nil
+		}
+		if tok.text == "has" {
+			args, err := p.parseArgs()
+			if err != nil {
+				return nil, err
+			}
+			f, ok := singleField(args)
+			if !ok {
+				return nil, fmt.Errorf("offset %d: has() expects a field, such as has(extension.cpu_limit)", tok.pos)
+			}
+			return &has{field: f}, nil
+		}
+		args, err := p.parseArgs()
+		if err != nil {
+			return nil, err
+		}
+		return newCall(tok.pos, tok.text, nil, args)
+	case tokOp:
+		switch tok.text {
+		case "(":
+			p.next()
+			n, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			return n, p.expect(")")
+		case "[":
+			p.next()
+			var items []node
+			for !p.isOp("]") {
+				item, err := p.parseExpr()
+				if err != nil {
+					return nil, err
+				}
+				items = append(items, item)
+				if !p.isOp(",") {
+					break
+				}
+				p.next()
+			}
+			return &list{items: items}, p.expect("]")
+		}
+	}
+	return nil, p.errorf("unexpected %s", tok)
+}
+
+func singleField(args []node) (*field, bool) {
+	if len(args) != 1 {
+		return nil, false
+	}
+	f, ok := args[0].(*field)
+	return f, ok
+}
+
+// Evaluation
+
+type scope struct {
+	vars   map[string]any
+	parent *scope
+}
+
+func (s *scope) lookup(name string) (any, bool) {
+	for ; s != nil; s = s.parent {
+		if v, ok := s.vars[name]; ok {
+			return v, true
+		}
+	}
+	return nil, false
+}
+
+type node interface {
+	eval(s *scope) (any, error)
+}
+
+type literal struct{ value any }
+
+func (n *literal) eval(*scope) (any, error) { return n.value, nil }
+
+type ident struct{ name string }
+
+func (n *ident) eval(s *scope) (any, error) {
+	if v, ok := s.lookup(n.name); ok {
+		return normalize(v), nil
+	}
+	return nil, fmt.Errorf("undeclared reference to %q", n.name)
+}
+
+type field struct {
+	target node
+	name   string
+}
+
+func (n *field) eval(s *scope) (any, error) {
+	target, err := n.target.eval(s)
+	if err != nil {
+		return nil, err
+	}
+	m, ok := target.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("cannot select field %q of %s", n.name, typeName(target))
+	}
+	v, ok := m[n.name]
+	if !ok {
+		return nil, fmt.Errorf("no such key: %s", n.name)
+	}
+	return normalize(v), nil
+}
+
+type has struct{ field *field }
+
+func (n *has) eval(s *scope) (any, error) {
+	target, err := n.field.target.eval(s)
+	if err != nil {
+		return nil, err
+	}
+	m, ok := target.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("has() cannot test field %q of %s", n.field.name, typeName(target))
+	}
+	v, ok := m[n.field.name]
+	// Like proto3 fields in CEL, zero values count as not set
+	return ok && !isZero(normalize(v)), nil
+}
+
+type index struct {
+	target node
+	index  node
+}
+
+func (n *index) eval(s *scope) (any, error) {
+	target, err := n.target.eval(s)
+	if err != nil {
+		return nil, err
+	}
+	i, err := n.index.eval(s)
+	if err != nil {
+		return nil, err
+	}
+	switch t := target.(type) {
+	case []any:
+		pos, ok := i.(int64)
+		if !ok {
+			return nil, fmt.Errorf("list index must be an int, not %s", typeName(i))
+		}
+		if pos < 0 || pos >= int64(len(t)) {
+			return nil, fmt.Errorf("index %d out of range", pos)
+		}
+		return normalize(t[pos]), nil
+	case map[string]any:
+		key, ok := i.(string)
+		if !ok {
+			return nil, fmt.Errorf("map key must be a string, not %s", typeName(i))
+		}
+		v, ok := t[key]
+		if !ok {
+			return nil, fmt.Errorf("no such key: %s", key)
+		}
+		return normalize(v), nil
+	}
+	return nil, fmt.Errorf("cannot index %s", typeName(target))
+}
+
+type list struct{ items []node }
+
+func (n *list) eval(s *scope) (any, error) {
+	values := make([]any, len(n.items))
+	for i, item := range n.items {
+		v, err := item.eval(s)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = v
+	}
+	return values, nil
+}
+
+type unary struct {
+	op      string
+	operand node
+}
+
+func (n *unary) eval(s *scope) (any, error) {
+	v, err := n.operand.eval(s)
+	if err != nil {
+		return nil, err
+	}
+	switch n.op {
+	case "!":
+		b, ok := v.(bool)
+		if !ok {
+			return nil, fmt.Errorf("operator ! expects a bool, not %s", typeName(v))
+		}
+		return !b, nil
+	default:
+		switch x := v.(type) {
+		case int64:
+			return -x, nil
+		case float64:
+			return -x, nil
+		}
+		return nil, fmt.Errorf("operator - expects a number, not %s", typeName(v))
+	}
+}
+
+type conditional struct {
+	cond, then, otherwise node
+}
+
+func (n *conditional) eval(s *scope) (any, error) {
+	v, err := n.cond.eval(s)
+	if err != nil {
+		return nil, err
+	}
+	b, ok := v.(bool)
+	if !ok {
+		return nil, fmt.Errorf("condition of ?: must be a bool, not %s", typeName(v))
+	}
+	if b {
+		return n.then.eval(s)
+	}
+	return n.otherwise.eval(s)
+}
+
+type binary struct {
+	op          string
+	left, right node
+}
+
+func (n *binary) eval(s *scope) (any, error) {
+	if n.op == "&&" || n.op == "||" {
+		return n.logical(s)
+	}
+
+	left, err := n.left.eval(s)
+	if err != nil {
+		return nil, err
+	}
+	right, err := n.right.eval(s)
+	if err != nil {
+		return nil, err
+	}
+
+	switch n.op {
+	case "==":
+		return equal(left, right), nil
+	case "!=":
+		return !equal(left, right), nil
+	case "in":
+		return contains(right, left)
+	case "<", "<=", ">", ">=":
+		c, err := compare(left, right)
+		if err != nil {
+			return nil, fmt.Errorf("operator %s: %w", n.op, err)
+		}
+		switch n.op {
+		case "<":
+			return c < 0, nil
+		case "<=":
+			return c <= 0, nil
+		case ">":
+			return c > 0, nil
+		default:
+			return c >= 0, nil
+		}
+	}
+	return arithmetic(n.op, left, right)
+}
+
+// logical evaluates && and || like CEL: an error on one side is ignored when
+// the other side decides the result
+func (n *binary) logical(s *scope) (any, error) {
+	decisive := n.op == "||"
+	left, leftErr := n.left.eval(s)
+	if b, ok := left.(bool); leftErr == nil && ok && b == decisive {
+		return decisive, nil
+	}
+	right, rightErr := n.right.eval(s)
+	if b, ok := right.(bool); rightErr == nil && ok && b == decisive {
+		return decisive, nil
+	}
+	if leftErr != nil {
+		return nil, leftErr
+	}
+	if rightErr != nil {
+		return nil, rightErr
+	}
+	if _, ok := left.(bool); !ok {
+		return nil, fmt.Errorf("operator %s expects bools, not %s", n.op, typeName(left))
+	}
+	if _, ok := right.(bool); !ok {
+		return nil, fmt.Errorf("operator %s expects bools, not %s", n.op, typeName(right))
+	}
+	return !decisive, nil
+}
+
+type comprehension struct {
+	all       bool
+	target    node
+	variable  string
+	predicate node
+}
+
+func (n *comprehension) eval(s *scope) (any, error) {
+	target, err := n.target.eval(s)
+	if err != nil {
+		return nil, err
+	}
+	var items []any
+	switch t := target.(type) {
+	case []any:
+		items = t
+	case map[string]any:
+		for key := range t {
+			items = append(items, key)
+		}
+	default:
+		return nil, fmt.Errorf("cannot iterate over %s", typeName(target))
+	}
+
+	for _, item := range items {
+		inner := &scope{vars: map[string]any{n.variable: item}, parent: s}
+		v, err := n.predicate.eval(inner)
+		if err != nil {
+			return nil, err
+		}
+		b, ok := v.(bool)
+		if !ok {
+			return nil, fmt.Errorf("predicate must be a bool, not %s", typeName(v))
+		}
+		if b != n.all {
+			return !n.all, nil
+		}
+	}
+	return n.all, nil
+}
+
+type call struct {
+	name   string
+	target node // nil for global functions
+	args   []node
+}
+
+// newCall checks that the function exists and takes args, so a misspelled
+// rule fails when it is compiled rather than when it is evaluated
+func newCall(pos int, name string, target node, args []node) (node, error) {
+	fn, ok := functions[name]
+	if !ok {
+		return nil, fmt.Errorf("offset %d: unknown function %s()", pos, name)
+	}
+	arity := len(args)
+	if target != nil {
+		arity++
+	}
+	if arity != fn.arity {
+		return nil, fmt.Errorf("offset %d: %s() expects %d arguments, got %d", pos, name, fn.arity, arity)
+	}
+	return &call{name: name, target: target, args: args}, nil
+}
+
+func (n *call) eval(s *scope) (any, error) {
+	var values []any
+	if n.target != nil {
+		target, err := n.target.eval(s)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, target)
+	}
+	for _, arg := range n.args {
+		v, err := arg.eval(s)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+	}
+
+	fn, ok := functions[n.name]
+	if !ok {
+		return nil, fmt.Errorf("unknown function %s()", n.name)
+	}
+	if len(values) != fn.arity {
+		return nil, fmt.Errorf("%s() expects %d arguments, got %d", n.name, fn.arity, len(values))
+	}
+	return fn.call(values)
+}
+
+type function struct {
+	arity int // including the target of a method
+	call  func(args []any) (any, error)
+}
+
+var functions = map[string]function{
+	"size": {1, func(args []any) (any, error) {
+		switch v := args[0].(type) {
+		case string:
+			return int64(len([]rune(v))), nil
+		case []any:
+			return int64(len(v)), nil
+		case map[string]any:
+			return int64(len(v)), nil
+		}
+		return nil, fmt.Errorf("size() of %s", typeName(args[0]))
+	}},
+	"int": {1, func(args []any) (any, error) {
+		switch v := args[0].(type) {
+		case int64:
+			return v, nil
+		case float64:
+			return int64(v), nil
+		case string:
+			i, err := strconv.ParseInt(v, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("int(%q): not a number", v)
+			}
+			return i, nil
+		}
+		return nil, fmt.Errorf("int() of %s", typeName(args[0]))
+	}},
+	"string": {1, func(args []any) (any, error) {
+		if args[0] == nil {
+			return "", nil
+		}
+		return fmt.Sprint(args[0]), nil
+	}},
+	"startsWith": stringMethod(strings.HasPrefix),
+	"endsWith":   stringMethod(strings.HasSuffix),
+	"contains":   stringMethod(strings.Contains),
+	"matches": {2, func(args []any) (any, error) {
+		s, pattern, err := twoStrings("matches", args)
+		if err != nil {
+			return nil, err
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("matches(): %w", err)
+		}
+		return re.MatchString(s), nil
+	}},
+	"lowerAscii": {1, func(args []any) (any, error) {
+		s, ok := args[0].(string)
+		if !ok {
+			return nil, fmt.Errorf("lowerAscii() of %s", typeName(args[0]))
+		}
+		return strings.ToLower(s), nil
+	}},
+}
+
+func stringMethod(fn func(s, arg string) bool) function {
+	return function{2, func(args []any) (any, error) {
+		s, arg, err := twoStrings("string method", args)
+		if err != nil {
+			return nil, err
+		}
+		return fn(s, arg), nil
+	}}
+}
+
+func twoStrings(name string, args []any) (string, string, error) {
+	s, ok1 := args[0].(string)
+	arg, ok2 := args[1].(string)
+	if !ok1 || !ok2 {
+		return "", "", fmt.Errorf("%s expects strings, not %s and %s", name, typeName(args[0]), typeName(args[1]))
+	}
+	return s, arg, nil
+}
+
+// normalize converts Go values of the input to the value types of rules:
+// int64, float64, string, bool, nil, []any and map[string]any
+func normalize(v any) any {
+	switch x := v.(type) {
+	case nil, bool, int64, float64, string, []any, map[string]any:
+		return v
+	case int:
+		return int64(x)
+	case int32:
+		return int64(x)
+	case uint:
+		return int64(x)
+	case float32:
+		return float64(x)
+	case []string:
+		items := make([]any, len(x))
+		for i, s := range x {
+			items[i] = s
+		}
+		return items
+	case []map[string]any:
+		items := make([]any, len(x))
+		for i, m := range x {
+			items[i] = m
+		}
+		return items
+	}
+	return v
+}
+
+func isZero(v any) bool {
+	switch x := v.(type) {
+	case nil:
+		return true
+	case bool:
+		return !x
+	case int64:
+		return x == 0
+	case float64:
+		return x == 0
+	case string:
+		return x == ""
+	case []any:
+		return len(x) == 0
+	case map[string]any:
+		return len(x) == 0
+	}
+	return false
+}
+
+func equal(a, b any) bool {
+	if x, y, ok := numbers(a, b); ok {
+		return x == y
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func contains(container, item any) (any, error) {
+	switch c := container.(type) {
+	case []any:
+		for _, v := range c {
+			if equal(v, item) {
+				return true, nil
+			}
+		}
+		return false, nil
+	case map[string]any:
+		key, ok := item.(string)
+		if !ok {
+			return false, nil
+		}
+		_, found := c[key]
+		return found, nil
+	}
+	return nil, fmt.Errorf("operator in expects a list or map, not %s", typeName(container))
+}
+
+func compare(a, b any) (int, error) {
+	if x, y, ok := numbers(a, b); ok {
+		switch {
+		case x < y:
+			return -1, nil
+		case x > y:
+			return 1, nil
+		}
+		return 0, nil
+	}
+	if x, ok := a.(string); ok {
+		if y, ok := b.(string); ok {
+			return strings.Compare(x, y), nil
+		}
+	}
+	return 0, fmt.Errorf("cannot compare %s and %s", typeName(a), typeName(b))
+}
+
+func arithmetic(op string, a, b any) (any, error) {
+	if op == "+" {
+		if x, ok := a.(string); ok {
+			if y, ok := b.(string); ok {
+				return x + y, nil
+			}
+		}
+		if x, ok := a.([]any); ok {
+			if y, ok := b.([]any); ok {
+				return append(append([]any{}, x...), y...), nil
+			}
+		}
+	}
+
+	xi, xInt := a.(int64)
+	yi, yInt := b.(int64)
+	if xInt && yInt {
+		switch op {
+		case "+":
+			return xi + yi, nil
+		case "-":
+			return xi - yi, nil
+		case "*":
+			return xi * yi, nil
+		case "/", "%":
+			if yi == 0 {
+				return nil, fmt.Errorf("division by zero")
+			}
+			if op == "/" {
+				return xi / yi, nil
+			}
+			return xi % yi, nil
+		}
+	}
+	if x, y, ok := numbers(a, b); ok {
+		switch op {
+		case "+":
+			return x + y, nil
+		case "-":
+			return x - y, nil
+		case "*":
+			return x * y, nil
+		case "/":
+			return x / y, nil
+		}
+	}
+	return nil, fmt.Errorf("operator %s does not apply to %s and %s", op, typeName(a), typeName(b))
+}
+
+// numbers converts two numeric values to float64
+func numbers(a, b any) (float64, float64, bool) {
+	x, ok1 := number(a)
+	y, ok2 := number(b)
+	return x, y, ok1 && ok2
+}
+
+func number(v any) (float64, bool) {
+	switch x := v.(type) {
+	case int64:
+		return float64(x), true
+	case float64:
+		return x, true
+	}
+	return 0, false
+}
+
+func typeName(v any) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "bool"
+	case int64:
+		return "int"
+	case float64:
+		return "double"
+	case string:
+		return "string"
+	case []any:
+		return "list"
+	case map[string]any:
+		return "map"
+	}
+	return fmt.Sprintf("%T", v)
+}
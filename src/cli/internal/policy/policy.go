@@ -0,0 +1,357 @@
+// Package policy evaluates the extension policies of a repository. Admins
+// ship them in .r2r/policies.yml as rules that must hold for every extension,
+// such as "no privileged extensions" or "images must come from
+// ghcr.io/ready-to-release". Rules are checked when the configuration is
+// loaded and again before each container is created, against the settings
+// the container is actually created with.
+//
+// Rules are written in a subset of CEL, the Common Expression Language:
+//
+//   - literals: true, false, null, 42, 1.5, "text", 'text', [1, 2]
+//   - field access and indexing: extension.image, extension.volumes[0]
+//   - operators: ! - * / % + == != < <= > >= in && || and c ? a : b
+//   - functions: has(x.field), size(x), int(x), string(x)
+//   - methods: s.startsWith(p), s.endsWith(p), s.contains(p), s.matches(re),
+//     s.lowerAscii(), x.size()
+//   - macros: list.all(v, predicate), list.exists(v, predicate)
+//
+// A violated policy fails the command in deny mode and logs a warning in
+// warn mode. By default policies deny in CI and warn locally.
+package policy
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/ready-to-release/eac/src/cli/internal/terminal"
+	"github.com/ready-to-release/eac/src/core/reporoot"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// FileEnv names the environment variable that sets the policy file
+	FileEnv = "R2R_POLICY_FILE"
+	// ModeEnv names the environment variable that sets the mode of policies
+	// that do not set one, overriding the CI detection
+	ModeEnv = "R2R_POLICY_MODE"
+)
+
+// Phases in which policies are evaluated
+const (
+	PhaseConfig    = "config"    // when the configuration is loaded
+	PhaseContainer = "container" // before a container is created
+)
+
+// Mode is what a violated policy does
+type Mode string
+
+const (
+	ModeAuto Mode = "auto" // deny in CI, warn otherwise
+	ModeWarn Mode = "warn"
+	ModeDeny Mode = "deny"
+)
+
+// ParseMode parses a mode; an empty value is auto
+func ParseMode(value string) (Mode, error) {
+	switch mode := Mode(strings.ToLower(strings.TrimSpace(value))); mode {
+	case "":
+		return ModeAuto, nil
+	case ModeAuto, ModeWarn, ModeDeny:
+		return mode, nil
+	}
+	return "", fmt.Errorf("invalid policy mode %q (use auto, warn or deny)", value)
+}
+
+// Policy is one rule
+type Policy struct {
+	Name        string `yaml:"name"`
+	Description string `yaml:"description,omitempty"`
+	Rule        string `yaml:"rule"`
+	// Mode overrides the mode of the file for this policy
+	Mode Mode `yaml:"mode,omitempty"`
+	// Phases limits the evaluation to some phases; all by default
+	Phases []string `yaml:"phases,omitempty"`
+
+	program *Program
+}
+
+// Set is the content of a policy file
+type Set struct {
+	Mode     Mode      `yaml:"mode,omitempty"`
+	Policies []*Policy `yaml:"policies"`
+
+	path string
+}
+
+// Load reads and compiles a policy file
+func Load(path string) (*Set, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read policy file: %w", err)
+	}
+	set, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid policy file %s: %w", path, err)
+	}
+	set.path = path
+	return set, nil
+}
+
+// Parse compiles the policies of a policy file
+func Parse(data []byte) (*Set, error) {
+	var set Set
+	if err := yaml.Unmarshal(data, &set); err != nil {
+		return nil, err
+	}
+	mode, err := ParseMode(string(set.Mode))
+	if err != nil {
+		return nil, err
+	}
+	set.Mode = mode
+
+	names := map[string]bool{}
+	for i, p := range set.Policies {
+		if p.Name == "" {
+			return nil, fmt.Errorf("policy %d has no name", i+1)
+		}
+		if names[p.Name] {
+			return nil, fmt.Errorf("policy %s is defined twice", p.Name)
+		}
+		names[p.Name] = true
+		if p.Mode != "" {
+			if p.Mode, err = ParseMode(string(p.Mode)); err != nil {
+				return nil, fmt.Errorf("policy %s: %w", p.Name, err)
+			}
+		}
+		for _, phase := range p.Phases {
+			if phase != PhaseConfig && phase != PhaseContainer {
+				return nil, fmt.Errorf("policy %s: unknown phase %q (use %s or %s)", p.Name, phase, PhaseConfig, PhaseContainer)
+			}
+		}
+		if p.program, err = Compile(p.Rule); err != nil {
+			return nil, fmt.Errorf("policy %s: invalid rule: %w", p.Name, err)
+		}
+	}
+	return &set, nil
+}
+
+// Path returns the file the policies were loaded from
+func (s *Set) Path() string {
+	return s.path
+}
+
+// DefaultPath returns the policy file: R2R_POLICY_FILE, or
+// .r2r/policies.yml in the repository root
+func DefaultPath() (string, error) {
+	if path := os.Getenv(FileEnv); path != "" {
+		return path, nil
+	}
+	root, err := reporoot.Find("")
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(root, ".r2r", "policies.yml"), nil
+}
+
+var (
+	defaultSet     *Set
+	defaultSetErr  error
+	defaultSetOnce sync.Once
+)
+
+// Default loads the policy file of the repository once. It returns nil
+// without an error when the repository has none.
+func Default() (*Set, error) {
+	defaultSetOnce.Do(func() {
+		path, err := DefaultPath()
+		if err != nil {
+			return
+		}
+		defaultSet, defaultSetErr = Load(path)
+		if errors.Is(defaultSetErr, os.ErrNotExist) && os.Getenv(FileEnv) == "" {
+			defaultSet, defaultSetErr = nil, nil
+		}
+	})
+	return defaultSet, defaultSetErr
+}
+
+// Extension is what rules see as `extension`
+type Extension struct {
+	Name        string
+	Image       string
+	Privileged  bool
+	NetworkMode string
+	// CPULimit is the number of CPUs, such as "1.5"; empty without a limit
+	CPULimit string
+	// MemoryLimit is the limit as configured, such as "512m"; before
+	// container creation it is the number of bytes. Empty without a limit.
+	MemoryLimit string
+	// User is run_as, such as "host" or "1000:1000", when the configuration
+	// is loaded and the user of the container before container creation;
+	// empty for the user of the image
+	User string
+	// RepoAccess is the access to the repository: rw, ro or none
+	RepoAccess string
+	Volumes    []Volume
+	Env        []string // names of the environment variables
+}
+
+// Volume is a bind mount of an extension
+type Volume struct {
+	Host      string
+	Container string
+	Readonly  bool
+}
+
+// vars converts the input to the variables of rules
+func vars(phase string, ext Extension) map[string]any {
+	volumes := make([]any, len(ext.Volumes))
+	for i, v := range ext.Volumes {
+		volumes[i] = map[string]any{"host": v.Host, "container": v.Container, "readonly": v.Readonly}
+	}
+	registry, repository, tag := splitImage(ext.Image)
+	return map[string]any{
+		"phase": phase,
+		"extension": map[string]any{
+			"name":         ext.Name,
+			"image":        ext.Image,
+			"registry":     registry,
+			"repository":   repository,
+			"tag":          tag,
+			"privileged":   ext.Privileged,
+			"network_mode": ext.NetworkMode,
+			"cpu_limit":    ext.CPULimit,
+			"memory_limit": ext.MemoryLimit,
+			"user":         ext.User,
+			"repo_access":  ext.RepoAccess,
+			"volumes":      volumes,
+			"env":          normalize(ext.Env),
+		},
+	}
+}
+
+// splitImage splits an image reference into registry, repository and tag.
+// Images without a registry host are on docker.io.
+func splitImage(image string) (registry, repository, tag string) {
+	ref, _, _ := strings.Cut(image, "@")
+	if i := strings.LastIndex(ref, ":"); i > strings.LastIndex(ref, "/") {
+		ref, tag = ref[:i], ref[i+1:]
+	}
+	registry = "docker.io"
+	if host, rest, ok := strings.Cut(ref, "/"); ok && (strings.ContainsAny(host, ".:") || host == "localhost") {
+		registry, ref = host, rest
+	}
+	return registry, ref, tag
+}
+
+// Violation is a policy an extension does not satisfy
+type Violation struct {
+	Policy      string
+	Description string
+	Extension   string
+	Phase       string
+	Mode        Mode // warn or deny
+	Err         error
+}
+
+func (v Violation) String() string {
+	detail := v.Description
+	if detail == "" {
+		detail = "rule not satisfied"
+	}
+	if v.Err != nil {
+		detail = "rule failed: " + v.Err.Error()
+	}
+	return fmt.Sprintf("extension %s violates policy %s: %s", v.Extension, v.Policy, detail)
+}
+
+// Evaluate returns the policies ext violates in phase. A rule that fails to
+// evaluate, for example on a missing field, is a violation.
+func (s *Set) Evaluate(phase string, ext Extension) []Violation {
+	if s == nil {
+		return nil
+	}
+	input := vars(phase, ext)
+	var violations []Violation
+	for _, p := range s.Policies {
+		if len(p.Phases) > 0 && !hasPhase(p.Phases, phase) {
+			continue
+		}
+		ok, err := p.program.Eval(input)
+		if ok && err == nil {
+			continue
+		}
+		violations = append(violations, Violation{
+			Policy:      p.Name,
+			Description: p.Description,
+			Extension:   ext.Name,
+			Phase:       phase,
+			Mode:        s.effectiveMode(p),
+			Err:         err,
+		})
+	}
+	return violations
+}
+
+func hasPhase(phases []string, phase string) bool {
+	for _, p := range phases {
+		if p == phase {
+			return true
+		}
+	}
+	return false
+}
+
+// effectiveMode resolves auto: the mode of the policy, of the file, of
+// R2R_POLICY_MODE, then deny in CI and warn otherwise
+func (s *Set) effectiveMode(p *Policy) Mode {
+	for _, mode := range []Mode{p.Mode, s.Mode} {
+		if mode == ModeWarn || mode == ModeDeny {
+			return mode
+		}
+	}
+	if mode, err := ParseMode(os.Getenv(ModeEnv)); err == nil && mode != ModeAuto {
+		return mode
+	}
+	if terminal.IsCI() {
+		return ModeDeny
+	}
+	return ModeWarn
+}
+
+// DeniedError is returned when policies in deny mode are violated
+type DeniedError struct {
+	Violations []Violation
+}
+
+func (e *DeniedError) Error() string {
+	lines := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		lines[i] = v.String()
+	}
+	if len(lines) == 1 {
+		return "policy violation: " + lines[0]
+	}
+	return "policy violations:\n  - " + strings.Join(lines, "\n  - ")
+}
+
+// Enforce evaluates the policies, passes violations in warn mode to warn and
+// returns a *DeniedError for the violations in deny mode
+func (s *Set) Enforce(phase string, ext Extension, warn func(Violation)) error {
+	var denied []Violation
+	for _, v := range s.Evaluate(phase, ext) {
+		if v.Mode == ModeDeny {
+			denied = append(denied, v)
+		} else if warn != nil {
+			warn(v)
+		}
+	}
+	if len(denied) > 0 {
+		return &DeniedError{Violations: denied}
+	}
+	return nil
+}
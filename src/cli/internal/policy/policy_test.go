@@ -0,0 +1,135 @@
+//go:build L0
+// +build L0
+
+package policy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEval(t *testing.T) {
+	vars := vars(PhaseConfig, Extension{
+		Name:     "go",
+		Image:    "ghcr.io/ready-to-release/go:1.2.0",
+		CPULimit: "2",
+		Volumes:  []Volume{{Host: "/tmp", Container: "/cache", Readonly: true}},
+		Env:      []string{"GOFLAGS"},
+	})
+
+	tests := []struct {
+		rule string
+		want bool
+	}{
+		{`!extension.privileged`, true},
+		{`extension.image.startsWith("ghcr.io/ready-to-release/")`, true},
+		{`extension.registry == "ghcr.io" && extension.tag != "latest"`, true},
+		{`extension.cpu_limit != ""`, true},
+		{`extension.volumes.all(v, v.readonly)`, true},
+		{`extension.volumes.exists(v, v.host == "/var/run/docker.sock")`, false},
+		{`"GOFLAGS" in extension.env && size(extension.env) == 1`, true},
+		{`!has(extension.user) && has(extension.cpu_limit)`, true},
+		{`phase == "config" ? extension.name.matches("^[a-z]+$") : false`, true},
+		{`int(extension.cpu_limit) * 2 >= 4 || false`, true},
+		{`extension.volumes[0].container.endsWith("cache")`, true},
+		{`extension.name.lowerAscii() in ['go', 'python']`, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.rule, func(t *testing.T) {
+			program, err := Compile(tt.rule)
+			require.NoError(t, err)
+			got, err := program.Eval(vars)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestEvalErrors(t *testing.T) {
+	for _, rule := range []string{`extension.missing`, `extension.name`, `extension.volumes[3].host == ""`, `1 + "a" == 2`} {
+		program, err := Compile(rule)
+		require.NoError(t, err, rule)
+		_, err = program.Eval(vars(PhaseConfig, Extension{}))
+		assert.Error(t, err, rule)
+	}
+	for _, rule := range []string{``, `extension.`, `(true`, `true false`, `foo(1)`, `extension.name.startsWith()`, `"open`} {
+		_, err := Compile(rule)
+		assert.Error(t, err, rule)
+	}
+}
+
+func TestSplitImage(t *testing.T) {
+	tests := map[string][3]string{
+		"golang":                           {"docker.io", "golang", ""},
+		"golang:1.22":                      {"docker.io", "golang", "1.22"},
+		"ghcr.io/ready-to-release/go:1.0":  {"ghcr.io", "ready-to-release/go", "1.0"},
+		"localhost:5000/go":                {"localhost:5000", "go", ""},
+		"ghcr.io/org/go@sha256:abc":        {"ghcr.io", "org/go", ""},
+		"ghcr.io/org/go:1.0@sha256:abc123": {"ghcr.io", "org/go", "1.0"},
+	}
+	for image, want := range tests {
+		registry, repository, tag := splitImage(image)
+		assert.Equal(t, want, [3]string{registry, repository, tag}, image)
+	}
+}
+
+const testPolicies = `
+policies:
+  - name: no-privileged
+    description: extensions must not run privileged
+    rule: "!extension.privileged"
+  - name: trusted-images
+    rule: extension.image.startsWith("ghcr.io/ready-to-release/")
+    mode: warn
+  - name: cpu-limit
+    rule: extension.cpu_limit != ""
+    phases: [container]
+`
+
+func TestEnforce(t *testing.T) {
+	set, err := Parse([]byte(testPolicies))
+	require.NoError(t, err)
+
+	ext := Extension{Name: "evil", Image: "docker.io/evil", Privileged: true}
+
+	t.Run("deny in CI", func(t *testing.T) {
+		t.Setenv("CI", "true")
+		t.Setenv(ModeEnv, "")
+		var warned []string
+		err := set.Enforce(PhaseConfig, ext, func(v Violation) { warned = append(warned, v.Policy) })
+		var denied *DeniedError
+		require.ErrorAs(t, err, &denied)
+		require.Len(t, denied.Violations, 1)
+		assert.Equal(t, "no-privileged", denied.Violations[0].Policy)
+		assert.Contains(t, err.Error(), "extensions must not run privileged")
+		assert.Equal(t, []string{"trusted-images"}, warned)
+	})
+
+	t.Run("mode from the environment", func(t *testing.T) {
+		t.Setenv(ModeEnv, "warn")
+		var warned []string
+		err := set.Enforce(PhaseContainer, ext, func(v Violation) { warned = append(warned, v.Policy) })
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"no-privileged", "trusted-images", "cpu-limit"}, warned)
+	})
+
+	t.Run("no policies", func(t *testing.T) {
+		var none *Set
+		assert.NoError(t, none.Enforce(PhaseConfig, ext, nil))
+	})
+}
+
+func TestParseErrors(t *testing.T) {
+	for name, data := range map[string]string{
+		"no name":       "policies:\n  - rule: true\n",
+		"duplicate":     "policies:\n  - {name: a, rule: 'true'}\n  - {name: a, rule: 'true'}\n",
+		"invalid rule":  "policies:\n  - {name: a, rule: 'extension.'}\n",
+		"invalid mode":  "mode: block\npolicies: []\n",
+		"unknown phase": "policies:\n  - {name: a, rule: 'true', phases: [build]}\n",
+	} {
+		_, err := Parse([]byte(data))
+		assert.Error(t, err, name)
+	}
+}
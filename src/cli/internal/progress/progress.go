@@ -0,0 +1,418 @@
+// Package progress shows the progress of long operations: spinners for
+// operations of unknown length and bars with an ETA for those with a known
+// total. On a terminal, all running tasks are redrawn in place in one live
+// area; in CI, when stderr is redirected or with R2R_PROGRESS=plain, each
+// task is reported by plain log lines instead.
+package progress
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ready-to-release/eac/src/cli/internal/terminal"
+)
+
+// Mode is how a display reports progress
+type Mode int
+
+const (
+	ModeLive  Mode = iota // redraw running tasks in place
+	ModePlain             // print a line when a task starts, advances and ends
+	ModeOff               // print nothing
+)
+
+// ParseMode parses the value of R2R_PROGRESS
+func ParseMode(value string) (Mode, bool) {
+	switch strings.ToLower(value) {
+	case "live":
+		return ModeLive, true
+	case "plain":
+		return ModePlain, true
+	case "off", "none", "false", "0":
+		return ModeOff, true
+	}
+	return ModeLive, false
+}
+
+const (
+	// showDelay is how long a task runs before it is shown, so fast
+	// operations such as cached lookups print nothing
+	showDelay = 300 * time.Millisecond
+	// tickInterval is the redraw interval of the live area
+	tickInterval = 100 * time.Millisecond
+	// plainInterval is the minimum interval between plain progress lines of
+	// a bar
+	plainInterval = 10 * time.Second
+	// barWidth is the number of cells of a bar
+	barWidth = 20
+)
+
+var spinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+// Display renders the tasks of one output stream. It is safe for concurrent
+// use.
+type Display struct {
+	w     io.Writer
+	mode  Mode
+	width int // columns of the terminal, lines are cut to fit
+	now   func() time.Time
+	// interval is the redraw interval; tests set it to zero and redraw by
+	// calling update
+	interval time.Duration
+
+	mu      sync.Mutex
+	tasks   []*Task
+	drawn   int // lines of the live area currently on screen
+	frame   int
+	ticking bool
+	stop    chan struct{}
+}
+
+// New returns a display writing to w in the given mode
+func New(w io.Writer, mode Mode) *Display {
+	d := &Display{w: w, mode: mode, now: time.Now, interval: tickInterval}
+	if mode == ModeLive {
+		d.width = terminal.GetWidth()
+	}
+	return d
+}
+
+var (
+	defaultDisplay     *Display
+	defaultDisplayOnce sync.Once
+)
+
+// Default returns the display of the CLI, writing to stderr. It is live on
+// a terminal that interprets escape sequences outside of CI, and plain
+// otherwise. R2R_PROGRESS=live|plain|off overrides the detection.
+func Default() *Display {
+	defaultDisplayOnce.Do(func() {
+		defaultDisplay = New(os.Stderr, detectMode())
+	})
+	return defaultDisplay
+}
+
+// SetMode changes the mode of the default display, for example to turn
+// progress off for --r2r-quiet
+func SetMode(mode Mode) {
+	d := Default()
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.mode = mode
+}
+
+func detectMode() Mode {
+	if mode, ok := ParseMode(os.Getenv("R2R_PROGRESS")); ok {
+		return mode
+	}
+	caps := terminal.Detect()
+	if !terminal.IsTerminalFile(os.Stderr) || !caps.VirtualTerminal || caps.CI || caps.Term == "dumb" {
+		return ModePlain
+	}
+	return ModeLive
+}
+
+// Spinner starts a task of unknown length on the default display
+func Spinner(message string) *Task {
+	return Default().Spinner(message)
+}
+
+// Bar starts a task with a known total on the default display
+func Bar(message string, total int64) *Task {
+	return Default().Bar(message, total)
+}
+
+// Println prints a line on the default display, see Display.Println
+func Println(format string, args ...any) {
+	Default().Println(format, args...)
+}
+
+// Spinner starts a task of unknown length
+func (d *Display) Spinner(message string) *Task {
+	return d.start(message, 0)
+}
+
+// Bar starts a task advancing towards total. Totals in bytes are shown as
+// sizes; use SetUnit for other units.
+func (d *Display) Bar(message string, total int64) *Task {
+	return d.start(message, total)
+}
+
+func (d *Display) start(message string, total int64) *Task {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	t := &Task{d: d, message: message, total: total, unit: UnitBytes, started: d.now()}
+	d.tasks = append(d.tasks, t)
+	if d.mode != ModeOff && d.interval > 0 && !d.ticking {
+		d.ticking = true
+		d.stop = make(chan struct{})
+		go d.tick(d.stop)
+	}
+	return t
+}
+
+// Println prints a line above the live area, so messages and running tasks
+// do not overwrite each other
+func (d *Display) Println(format string, args ...any) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.mode == ModeOff {
+		return
+	}
+	d.clear()
+	fmt.Fprintf(d.w, format+"\n", args...)
+	d.draw()
+}
+
+func (d *Display) tick(stop chan struct{}) {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			d.mu.Lock()
+			d.frame++
+			d.update()
+			d.mu.Unlock()
+		}
+	}
+}
+
+// update shows tasks that ran past the show delay and redraws the live
+// area. The caller holds the lock.
+func (d *Display) update() {
+	now := d.now()
+	switch d.mode {
+	case ModeLive:
+		d.clear()
+		d.draw()
+	case ModePlain:
+		for _, t := range d.tasks {
+			if !t.shown && now.Sub(t.started) >= showDelay {
+				t.shown = true
+				t.reported = now
+				fmt.Fprintf(d.w, "⏳ %s...\n", t.message)
+			} else if t.shown && t.total > 0 && now.Sub(t.reported) >= plainInterval {
+				t.reported = now
+				fmt.Fprintf(d.w, "   %s: %s\n", t.message, t.stats(now))
+			}
+		}
+	}
+}
+
+// clear erases the live area. The caller holds the lock.
+func (d *Display) clear() {
+	if d.drawn == 0 {
+		return
+	}
+	fmt.Fprintf(d.w, "\033[%dA\033[J", d.drawn)
+	d.drawn = 0
+}
+
+// draw writes the live area below the cursor. The caller holds the lock.
+func (d *Display) draw() {
+	if d.mode != ModeLive {
+		return
+	}
+	now := d.now()
+	for _, t := range d.tasks {
+		if !t.shown && now.Sub(t.started) < showDelay {
+			continue
+		}
+		t.shown = true
+		fmt.Fprintln(d.w, truncate(t.line(now, spinnerFrames[d.frame%len(spinnerFrames)]), d.width-1))
+		d.drawn++
+	}
+}
+
+// finish removes t from the running tasks and prints its final line when
+// it was shown. The caller holds the lock.
+func (d *Display) finish(t *Task, line string) {
+	for i, task := range d.tasks {
+		if task == t {
+			d.tasks = append(d.tasks[:i], d.tasks[i+1:]...)
+			break
+		}
+	}
+
+	if d.mode != ModeOff {
+		d.clear()
+		if t.shown && line != "" {
+			fmt.Fprintln(d.w, line)
+		}
+		d.draw()
+	}
+
+	if len(d.tasks) == 0 && d.ticking {
+		close(d.stop)
+		d.ticking = false
+	}
+}
+
+// Unit is how the counts of a bar are shown
+type Unit int
+
+const (
+	UnitBytes Unit = iota // sizes, such as 12.3 MB
+	UnitCount             // plain counts, such as 3/5
+)
+
+// Task is one running operation of a display
+type Task struct {
+	d *Display
+
+	message  string
+	current  int64
+	total    int64
+	unit     Unit
+	started  time.Time
+	shown    bool
+	reported time.Time // last plain progress line
+	finished bool
+}
+
+// SetMessage changes the message shown for the task
+func (t *Task) SetMessage(message string) {
+	t.d.mu.Lock()
+	defer t.d.mu.Unlock()
+	t.message = message
+}
+
+// SetUnit changes how the counts of a bar are shown
+func (t *Task) SetUnit(unit Unit) {
+	t.d.mu.Lock()
+	defer t.d.mu.Unlock()
+	t.unit = unit
+}
+
+// SetTotal changes the total, turning a spinner into a bar
+func (t *Task) SetTotal(total int64) {
+	t.d.mu.Lock()
+	defer t.d.mu.Unlock()
+	t.total = total
+}
+
+// SetCurrent sets how much of the total is done
+func (t *Task) SetCurrent(current int64) {
+	t.d.mu.Lock()
+	defer t.d.mu.Unlock()
+	t.current = current
+}
+
+// Add advances the task by n
+func (t *Task) Add(n int64) {
+	t.d.mu.Lock()
+	defer t.d.mu.Unlock()
+	t.current += n
+}
+
+// Done ends the task. When the task was shown, message is printed with the
+// duration of the task; an empty message ends it silently.
+func (t *Task) Done(message string) {
+	t.end(func(elapsed time.Duration) string {
+		if message == "" {
+			return ""
+		}
+		return fmt.Sprintf("✅ %s (%s)", message, formatDuration(elapsed))
+	})
+}
+
+// Fail ends the task after an error. The error itself is left to the
+// caller, which returns or logs it; only the failed task is noted.
+func (t *Task) Fail(err error) {
+	t.end(func(elapsed time.Duration) string {
+		if err == nil {
+			return ""
+		}
+		return fmt.Sprintf("❌ %s failed after %s", t.message, formatDuration(elapsed))
+	})
+}
+
+func (t *Task) end(line func(elapsed time.Duration) string) {
+	t.d.mu.Lock()
+	defer t.d.mu.Unlock()
+	if t.finished {
+		return
+	}
+	t.finished = true
+	t.d.finish(t, line(t.d.now().Sub(t.started)))
+}
+
+// line renders the task in the live area
+func (t *Task) line(now time.Time, frame string) string {
+	if t.total <= 0 {
+		return fmt.Sprintf("%s %s (%s)", frame, t.message, formatDuration(now.Sub(t.started)))
+	}
+	return fmt.Sprintf("%s %s %s %s", frame, t.message, bar(t.current, t.total), t.stats(now))
+}
+
+// stats renders the percentage, counts and ETA of a bar
+func (t *Task) stats(now time.Time) string {
+	percent := 0
+	if t.total > 0 {
+		percent = int(min(t.current, t.total) * 100 / t.total)
+	}
+	counts := fmt.Sprintf("%d/%d", t.current, t.total)
+	if t.unit == UnitBytes {
+		counts = formatBytes(t.current) + "/" + formatBytes(t.total)
+	}
+	stats := fmt.Sprintf("%3d%% %s", percent, counts)
+	if eta, ok := estimate(t.current, t.total, now.Sub(t.started)); ok {
+		stats += " ETA " + formatDuration(eta)
+	}
+	return stats
+}
+
+// estimate returns the remaining time at the average rate so far
+func estimate(current, total int64, elapsed time.Duration) (time.Duration, bool) {
+	if current <= 0 || total <= 0 || current >= total || elapsed < time.Second {
+		return 0, false
+	}
+	remaining := time.Duration(float64(elapsed) * float64(total-current) / float64(current))
+	return remaining, true
+}
+
+func bar(current, total int64) string {
+	filled := int(min(current, total) * barWidth / total)
+	return "[" + strings.Repeat("=", filled) + strings.Repeat(" ", barWidth-filled) + "]"
+}
+
+func formatBytes(n int64) string {
+	const unit = 1000
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "kMGTPE"[exp])
+}
+
+func formatDuration(d time.Duration) string {
+	if d < time.Minute {
+		return fmt.Sprintf("%.1fs", d.Seconds())
+	}
+	return d.Round(time.Second).String()
+}
+
+// truncate shortens line to width runes so it never wraps, which would
+// break redrawing the live area
+func truncate(line string, width int) string {
+	if width <= 0 {
+		return line
+	}
+	runes := []rune(line)
+	if len(runes) <= width {
+		return line
+	}
+	return string(runes[:width])
+}
@@ -0,0 +1,131 @@
+//go:build L0
+// +build L0
+
+package progress
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestDisplay returns a display with a fake clock that only redraws when
+// the test calls update
+func newTestDisplay(mode Mode) (*Display, *bytes.Buffer, *time.Time) {
+	var buf bytes.Buffer
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	d := New(&buf, mode)
+	d.interval = 0
+	d.width = 200
+	d.now = func() time.Time { return now }
+	return d, &buf, &now
+}
+
+func TestParseMode(t *testing.T) {
+	for value, want := range map[string]Mode{"live": ModeLive, "PLAIN": ModePlain, "off": ModeOff, "0": ModeOff} {
+		mode, ok := ParseMode(value)
+		assert.True(t, ok, value)
+		assert.Equal(t, want, mode, value)
+	}
+	_, ok := ParseMode("fancy")
+	assert.False(t, ok)
+}
+
+func TestFastTaskPrintsNothing(t *testing.T) {
+	for _, mode := range []Mode{ModeLive, ModePlain} {
+		d, buf, now := newTestDisplay(mode)
+		task := d.Spinner("Checking tags")
+		*now = now.Add(100 * time.Millisecond)
+		d.update()
+		task.Done("Checked tags")
+		assert.Empty(t, buf.String(), "mode %d", mode)
+	}
+}
+
+func TestPlainMode(t *testing.T) {
+	d, buf, now := newTestDisplay(ModePlain)
+	task := d.Bar("Downloading layer", 1000)
+
+	*now = now.Add(time.Second)
+	d.update()
+	assert.Equal(t, "⏳ Downloading layer...\n", buf.String())
+
+	buf.Reset()
+	task.SetCurrent(250)
+	*now = now.Add(plainInterval)
+	d.update()
+	assert.Equal(t, "   Downloading layer:  25% 250 B/1.0 kB ETA 33.0s\n", buf.String())
+
+	buf.Reset()
+	*now = now.Add(time.Second)
+	d.update()
+	assert.Empty(t, buf.String(), "progress lines are throttled")
+
+	task.Done("Downloaded layer")
+	assert.Equal(t, "✅ Downloaded layer (12.0s)\n", buf.String())
+	assert.Empty(t, d.tasks)
+}
+
+func TestLiveMode(t *testing.T) {
+	d, buf, now := newTestDisplay(ModeLive)
+	spinner := d.Spinner("Contacting registry")
+	bar := d.Bar("Preparing images", 4)
+	bar.SetUnit(UnitCount)
+	bar.SetCurrent(1)
+
+	*now = now.Add(2 * time.Second)
+	d.update()
+	assert.Equal(t, "⠋ Contacting registry (2.0s)\n⠋ Preparing images [=====               ]  25% 1/4 ETA 6.0s\n", buf.String())
+	assert.Equal(t, 2, d.drawn)
+
+	// A message goes above the live area, which is drawn again below it
+	buf.Reset()
+	d.Println("📦 Pulling from %s", "org/image")
+	assert.True(t, strings.HasPrefix(buf.String(), "\033[2A\033[J📦 Pulling from org/image\n⠋ Contacting registry"), buf.String())
+
+	buf.Reset()
+	spinner.Fail(errors.New("timeout"))
+	assert.Contains(t, buf.String(), "❌ Contacting registry failed after 2.0s\n")
+	assert.Equal(t, 1, d.drawn)
+
+	buf.Reset()
+	bar.Done("")
+	assert.Equal(t, "\033[1A\033[J", buf.String(), "an empty message ends the task silently")
+	assert.Zero(t, d.drawn)
+}
+
+func TestOffMode(t *testing.T) {
+	d, buf, now := newTestDisplay(ModeOff)
+	task := d.Spinner("Working")
+	*now = now.Add(time.Minute)
+	d.update()
+	d.Println("message")
+	task.Done("Worked")
+	assert.Empty(t, buf.String())
+}
+
+func TestEstimate(t *testing.T) {
+	eta, ok := estimate(25, 100, 10*time.Second)
+	assert.True(t, ok)
+	assert.Equal(t, 30*time.Second, eta)
+
+	_, ok = estimate(0, 100, 10*time.Second)
+	assert.False(t, ok, "no rate yet")
+	_, ok = estimate(50, 100, 100*time.Millisecond)
+	assert.False(t, ok, "too early to tell")
+	_, ok = estimate(100, 100, 10*time.Second)
+	assert.False(t, ok, "complete")
+}
+
+func TestFormat(t *testing.T) {
+	assert.Equal(t, "999 B", formatBytes(999))
+	assert.Equal(t, "12.3 MB", formatBytes(12_300_000))
+	assert.Equal(t, "1.5s", formatDuration(1500*time.Millisecond))
+	assert.Equal(t, "2m5s", formatDuration(125*time.Second))
+	assert.Equal(t, "[==========          ]", bar(5, 10))
+	assert.Equal(t, "ab", truncate("abc", 2))
+}
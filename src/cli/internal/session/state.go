@@ -0,0 +1,231 @@
+package session
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Keys of the per-session state used by the CLI
+const (
+	// KeyPinWarningShown is set once the unpinned extension warnings were
+	// shown, so they are not repeated on every command of the session
+	KeyPinWarningShown = "pin-warning-shown"
+)
+
+// stateVersion is bumped when the layout of the state file changes. A file
+// of another version is discarded.
+const stateVersion = 1
+
+// sessionTTL is how long the state of a session is kept after its last
+// write. Session identifiers are reused (parent PIDs), so stale sessions are
+// dropped rather than kept forever.
+const sessionTTL = 7 * 24 * time.Hour
+
+// Entry is one value of a session's state
+type Entry struct {
+	Value     json.RawMessage `json:"value,omitempty"`
+	UpdatedAt time.Time       `json:"updated_at"`
+	ExpiresAt time.Time       `json:"expires_at,omitempty"` // zero means until the session is dropped
+}
+
+func (e Entry) expired(now time.Time) bool {
+	return !e.ExpiresAt.IsZero() && now.After(e.ExpiresAt)
+}
+
+type sessionState struct {
+	UpdatedAt time.Time        `json:"updated_at"`
+	Entries   map[string]Entry `json:"entries"`
+}
+
+type stateFile struct {
+	Version  int                      `json:"version"`
+	Sessions map[string]*sessionState `json:"sessions"`
+}
+
+// Store is per-session memory of the CLI, such as warnings already shown. All
+// sessions share a single JSON file, keyed by session identifier.
+type Store struct {
+	path      string
+	sessionID string
+	mu        sync.Mutex
+	now       func() time.Time
+}
+
+// NewStore returns a store for the given session backed by the file at path
+func NewStore(path, sessionID string) *Store {
+	return &Store{path: path, sessionID: sessionID, now: time.Now}
+}
+
+// DefaultStore returns the store of the current shell session at
+// DefaultStatePath
+func DefaultStore() *Store {
+	return NewStore(DefaultStatePath(), GetIdentifier())
+}
+
+// DefaultStatePath returns the state file under $XDG_CACHE_HOME, or the
+// user cache directory of the platform when it is not set
+func DefaultStatePath() string {
+	dir := os.Getenv("XDG_CACHE_HOME")
+	if dir == "" {
+		var err error
+		if dir, err = os.UserCacheDir(); err != nil {
+			dir = os.TempDir()
+		}
+	}
+	return filepath.Join(dir, "r2r-cli", "state.json")
+}
+
+// Path returns the file backing the store
+func (s *Store) Path() string {
+	return s.path
+}
+
+// Get decodes the value stored under key into v. It reports false when the
+// key is not set or has expired.
+func (s *Store) Get(key string, v any) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state := s.read()
+	entry, ok := s.entry(state, key)
+	if !ok {
+		return false, nil
+	}
+	if v != nil && len(entry.Value) > 0 {
+		if err := json.Unmarshal(entry.Value, v); err != nil {
+			return false, fmt.Errorf("invalid session state %q: %w", key, err)
+		}
+	}
+	return true, nil
+}
+
+// Set stores v under key. With a positive ttl the value expires after it.
+func (s *Store) Set(key string, v any, ttl time.Duration) error {
+	value, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to encode session state %q: %w", key, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	entry := Entry{Value: value, UpdatedAt: now}
+	if ttl > 0 {
+		entry.ExpiresAt = now.Add(ttl)
+	}
+
+	state := s.read()
+	session := state.Sessions[s.sessionID]
+	if session == nil {
+		session = &sessionState{Entries: make(map[string]Entry)}
+		state.Sessions[s.sessionID] = session
+	}
+	session.Entries[key] = entry
+	session.UpdatedAt = now
+	return s.write(state)
+}
+
+// Mark sets a flag for ttl, see Marked
+func (s *Store) Mark(key string, ttl time.Duration) error {
+	return s.Set(key, true, ttl)
+}
+
+// Marked reports whether the flag was set by Mark and has not expired
+func (s *Store) Marked(key string) bool {
+	var marked bool
+	ok, err := s.Get(key, &marked)
+	return ok && err == nil && marked
+}
+
+// Delete removes key from the state of the session
+func (s *Store) Delete(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state := s.read()
+	session := state.Sessions[s.sessionID]
+	if session == nil {
+		return nil
+	}
+	if _, ok := session.Entries[key]; !ok {
+		return nil
+	}
+	delete(session.Entries, key)
+	return s.write(state)
+}
+
+func (s *Store) entry(state *stateFile, key string) (Entry, bool) {
+	session := state.Sessions[s.sessionID]
+	if session == nil {
+		return Entry{}, false
+	}
+	entry, ok := session.Entries[key]
+	if !ok || entry.expired(s.now()) {
+		return Entry{}, false
+	}
+	return entry, true
+}
+
+// read loads the state file. A missing, unreadable or corrupted file, or one
+// of another version, yields empty state: the state is only a memory of what
+// was done and can always be rebuilt.
+func (s *Store) read() *stateFile {
+	empty := &stateFile{Version: stateVersion, Sessions: make(map[string]*sessionState)}
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		return empty
+	}
+	var state stateFile
+	if err := json.Unmarshal(data, &state); err != nil || state.Version != stateVersion {
+		return empty
+	}
+	if state.Sessions == nil {
+		state.Sessions = make(map[string]*sessionState)
+	}
+	return &state
+}
+
+// write prunes expired entries and sessions and replaces the state file
+// atomically, so a concurrent reader never sees a partial file
+func (s *Store) write(state *stateFile) error {
+	now := s.now()
+	for id, session := range state.Sessions {
+		for key, entry := range session.Entries {
+			if entry.expired(now) {
+				delete(session.Entries, key)
+			}
+		}
+		if len(session.Entries) == 0 || now.Sub(session.UpdatedAt) > sessionTTL {
+			delete(state.Sessions, id)
+		}
+	}
+
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode session state: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("failed to create session state directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write session state: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write session state: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write session state: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("failed to write session state: %w", err)
+	}
+	return nil
+}
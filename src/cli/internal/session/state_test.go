@@ -0,0 +1,76 @@
+//go:build L1
+// +build L1
+
+package session
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStoreSetGet(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "r2r-cli", "state.json")
+	store := NewStore(path, "pid-1")
+
+	var value string
+	ok, err := store.Get("latest-release", &value)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	require.NoError(t, store.Set("latest-release", "1.2.3", 0))
+	ok, err = store.Get("latest-release", &value)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "1.2.3", value)
+
+	// Sessions share the file but not their state
+	other := NewStore(path, "pid-2")
+	ok, err = other.Get("latest-release", &value)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	require.NoError(t, store.Delete("latest-release"))
+	ok, _ = store.Get("latest-release", nil)
+	assert.False(t, ok)
+}
+
+func TestStoreExpiry(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	store := NewStore(path, "pid-1")
+	store.now = func() time.Time { return now }
+
+	require.NoError(t, store.Mark(KeyPinWarningShown, time.Hour))
+	assert.True(t, store.Marked(KeyPinWarningShown))
+
+	now = now.Add(time.Hour + time.Second)
+	assert.False(t, store.Marked(KeyPinWarningShown))
+
+	// Expired entries and empty sessions are pruned on the next write
+	other := NewStore(path, "pid-2")
+	other.now = store.now
+	require.NoError(t, other.Mark("other", 0))
+	state := store.read()
+	assert.NotContains(t, state.Sessions, "pid-1")
+	assert.Contains(t, state.Sessions, "pid-2")
+}
+
+func TestStoreCorruptedFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	require.NoError(t, os.WriteFile(path, []byte("{not json"), 0644))
+	store := NewStore(path, "pid-1")
+
+	assert.False(t, store.Marked(KeyPinWarningShown))
+	require.NoError(t, store.Mark(KeyPinWarningShown, time.Hour))
+	assert.True(t, store.Marked(KeyPinWarningShown))
+}
+
+func TestDefaultStatePath(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", "/xdg/cache")
+	assert.Equal(t, filepath.Join("/xdg/cache", "r2r-cli", "state.json"), DefaultStatePath())
+}
@@ -0,0 +1,234 @@
+package terminal
+
+import (
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ColorLevel is the number of colors a terminal can show
+type ColorLevel int
+
+const (
+	ColorNone      ColorLevel = iota // no colors, or colors disabled
+	Color16                          // basic ANSI colors
+	Color256                         // xterm 256 colors
+	ColorTrueColor                   // 24-bit colors
+)
+
+// String returns the name of the level
+func (l ColorLevel) String() string {
+	switch l {
+	case Color16:
+		return "16"
+	case Color256:
+		return "256"
+	case ColorTrueColor:
+		return "truecolor"
+	default:
+		return "none"
+	}
+}
+
+// Capabilities describes what the terminal of the CLI supports. It is the
+// single place deciding about colors, so CLI output and the environment of
+// extension containers agree.
+type Capabilities struct {
+	IsTerminal      bool       // stdout is a terminal
+	Width           int        // columns, 0 when unknown
+	Height          int        // lines, 0 when unknown
+	Term            string     // value of TERM
+	Color           ColorLevel // colors stdout can show
+	NoColor         bool       // colors were disabled with NO_COLOR (https://no-color.org)
+	Hyperlinks      bool       // OSC 8 hyperlinks are supported
+	VirtualTerminal bool       // ANSI escape sequences are interpreted
+	CI              bool       // running in a CI/CD environment
+}
+
+// ciIndicators are environment variables set by CI/CD systems
+var ciIndicators = []string{
+	"CI", "CONTINUOUS_INTEGRATION", "BUILD_ID",
+	"GITHUB_ACTIONS", "GITLAB_CI", "CIRCLECI", "TRAVIS", "APPVEYOR",
+	"AZUREDEVOPS_URL", "AZURE_HTTP_USER_AGENT", "AZURE_PIPELINES", "TF_BUILD",
+	"JENKINS_URL", "TEAMCITY_VERSION", "BUILDKITE", "DRONE", "SEMAPHORE",
+	"CODEBUILD_BUILD_ID", "BITBUCKET_BUILD_NUMBER",
+}
+
+// colorEnvVars are the environment variables that control colors, inherited
+// by extension containers outside of CI
+var colorEnvVars = []string{
+	"TERM", "COLORTERM", "CLICOLOR", "CLICOLOR_FORCE",
+	"NO_COLOR", "FORCE_COLOR", "COLOR",
+}
+
+// Detect returns the capabilities of the current terminal. On Windows it
+// enables the processing of ANSI escape sequences of the console.
+func Detect() Capabilities {
+	caps := detect(os.Getenv, IsTerminal(), enableVirtualTerminal())
+	if width, height, err := GetSize(); err == nil && width > 0 && height > 0 {
+		caps.Width, caps.Height = width, height
+	}
+	return caps
+}
+
+// IsCI reports whether the CLI runs in a CI/CD environment
+func IsCI() bool {
+	return isCI(os.Getenv)
+}
+
+func isCI(getenv func(string) string) bool {
+	for _, name := range ciIndicators {
+		if value := getenv(name); value != "" && value != "false" && value != "0" {
+			return true
+		}
+	}
+	return false
+}
+
+func detect(getenv func(string) string, isTerminal, virtualTerminal bool) Capabilities {
+	caps := Capabilities{
+		IsTerminal:      isTerminal,
+		Term:            getenv("TERM"),
+		VirtualTerminal: virtualTerminal,
+		CI:              isCI(getenv),
+		NoColor:         getenv("NO_COLOR") != "",
+	}
+	caps.Color = colorLevel(getenv, caps)
+	caps.Hyperlinks = hyperlinks(getenv, caps)
+	return caps
+}
+
+// colorLevel applies, in order: NO_COLOR, FORCE_COLOR and CLICOLOR_FORCE,
+// then whether stdout is a terminal that interprets escape sequences,
+// CLICOLOR, and the color depth advertised by COLORTERM and TERM
+func colorLevel(getenv func(string) string, caps Capabilities) ColorLevel {
+	if caps.NoColor {
+		return ColorNone
+	}
+
+	if force := getenv("FORCE_COLOR"); force != "" {
+		switch strings.ToLower(force) {
+		case "0", "false":
+			return ColorNone
+		case "2":
+			return Color256
+		case "3":
+			return ColorTrueColor
+		default:
+			return max(Color16, depth(getenv, caps))
+		}
+	}
+	if force := getenv("CLICOLOR_FORCE"); force != "" && force != "0" {
+		return max(Color16, depth(getenv, caps))
+	}
+
+	if !caps.IsTerminal || !caps.VirtualTerminal || caps.Term == "dumb" || getenv("CLICOLOR") == "0" {
+		return ColorNone
+	}
+	return max(Color16, depth(getenv, caps))
+}
+
+// depth is the color depth advertised by the terminal
+func depth(getenv func(string) string, caps Capabilities) ColorLevel {
+	switch colorterm := strings.ToLower(getenv("COLORTERM")); {
+	case colorterm == "truecolor" || colorterm == "24bit":
+		return ColorTrueColor
+	case getenv("WT_SESSION") != "":
+		// Windows Terminal
+		return ColorTrueColor
+	case strings.Contains(caps.Term, "256color"):
+		return Color256
+	case caps.Term == "" && caps.VirtualTerminal && caps.IsTerminal:
+		// Windows consoles do not set TERM
+		return Color256
+	}
+	return Color16
+}
+
+// hyperlinkPrograms are values of TERM_PROGRAM of terminals supporting OSC 8
+var hyperlinkPrograms = map[string]bool{
+	"iTerm.app": true,
+	"WezTerm":   true,
+	"vscode":    true,
+	"ghostty":   true,
+	"Hyper":     true,
+}
+
+func hyperlinks(getenv func(string) string, caps Capabilities) bool {
+	if force := getenv("FORCE_HYPERLINK"); force != "" {
+		return force != "0"
+	}
+	if !caps.IsTerminal || caps.CI || caps.Color == ColorNone {
+		return false
+	}
+	if hyperlinkPrograms[getenv("TERM_PROGRAM")] || getenv("WT_SESSION") != "" {
+		return true
+	}
+	if strings.Contains(caps.Term, "kitty") || strings.Contains(caps.Term, "foot") {
+		return true
+	}
+	// GNOME Terminal and other VTE terminals since 0.50
+	vte, err := strconv.Atoi(getenv("VTE_VERSION"))
+	return err == nil && vte >= 5000
+}
+
+// ColorEnabled reports whether output may contain ANSI colors
+func (c Capabilities) ColorEnabled() bool {
+	return c.Color > ColorNone
+}
+
+// Escape returns seq when colors are enabled and "" otherwise, so colored
+// output degrades to plain text
+func (c Capabilities) Escape(seq string) string {
+	if c.ColorEnabled() {
+		return seq
+	}
+	return ""
+}
+
+// Hyperlink returns text linked to url when the terminal supports
+// hyperlinks, and text otherwise
+func (c Capabilities) Hyperlink(url, text string) string {
+	if !c.Hyperlinks {
+		return text
+	}
+	return "\033]8;;" + url + "\033\\" + text + "\033]8;;\033\\"
+}
+
+// ContainerEnv returns the color settings of extension containers. In CI,
+// colors are disabled; otherwise the color settings of the shell are
+// inherited, defaulting to a 256-color terminal with truecolor support.
+func (c Capabilities) ContainerEnv() []string {
+	return c.containerEnv(os.Getenv)
+}
+
+func (c Capabilities) containerEnv(getenv func(string) string) []string {
+	if c.CI {
+		return []string{
+			"NO_COLOR=1",    // Disable colors in CI
+			"TERM=dumb",     // Simple terminal for CI
+			"FORCE_COLOR=0", // Force disable color
+			"CI=true",       // Indicate CI environment
+		}
+	}
+
+	envVars := []string{}
+	for _, name := range colorEnvVars {
+		if value := getenv(name); value != "" {
+			envVars = append(envVars, name+"="+value)
+		}
+	}
+	if len(envVars) > 0 {
+		return envVars
+	}
+
+	term := c.Term
+	if term == "" {
+		term = "xterm-256color" // Sensible default for modern terminals
+	}
+	return []string{
+		"TERM=" + term,
+		"COLORTERM=truecolor", // Modern terminal default supporting full color
+		// Don't set NO_COLOR or FORCE_COLOR - let programs decide based on their logic
+	}
+}
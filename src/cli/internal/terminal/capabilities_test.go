@@ -0,0 +1,162 @@
+package terminal
+
+import (
+	"slices"
+	"testing"
+)
+
+// env returns a getenv function reading from vars
+func env(vars map[string]string) func(string) string {
+	return func(name string) string { return vars[name] }
+}
+
+func TestIsCI(t *testing.T) {
+	testCases := []struct {
+		name     string
+		env      map[string]string
+		expected bool
+	}{
+		{"no CI environment", map[string]string{}, false},
+		{"GitHub Actions", map[string]string{"GITHUB_ACTIONS": "true"}, true},
+		{"Azure DevOps URL", map[string]string{"AZUREDEVOPS_URL": "http://azuredevops.example.com"}, true},
+		{"Azure DevOps", map[string]string{"TF_BUILD": "True"}, true},
+		{"GitLab CI", map[string]string{"GITLAB_CI": "true"}, true},
+		{"Jenkins", map[string]string{"JENKINS_URL": "http://jenkins"}, true},
+		{"Generic CI", map[string]string{"CI": "true"}, true},
+		{"CI with false value", map[string]string{"CI": "false"}, false},
+		{"CI with zero value", map[string]string{"CI": "0"}, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := isCI(env(tc.env)); got != tc.expected {
+				t.Errorf("isCI() = %v, want %v", got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestColorLevel(t *testing.T) {
+	testCases := []struct {
+		name     string
+		env      map[string]string
+		terminal bool
+		vt       bool
+		expected ColorLevel
+	}{
+		{"plain terminal", map[string]string{"TERM": "xterm"}, true, true, Color16},
+		{"256 color terminal", map[string]string{"TERM": "xterm-256color"}, true, true, Color256},
+		{"truecolor terminal", map[string]string{"TERM": "xterm-256color", "COLORTERM": "truecolor"}, true, true, ColorTrueColor},
+		{"Windows Terminal", map[string]string{"WT_SESSION": "1"}, true, true, ColorTrueColor},
+		{"Windows console", map[string]string{}, true, true, Color256},
+		{"legacy Windows console", map[string]string{}, true, false, ColorNone},
+		{"NO_COLOR", map[string]string{"TERM": "xterm-256color", "NO_COLOR": "1"}, true, true, ColorNone},
+		{"NO_COLOR wins over FORCE_COLOR", map[string]string{"NO_COLOR": "1", "FORCE_COLOR": "3"}, true, true, ColorNone},
+		{"empty NO_COLOR is ignored", map[string]string{"TERM": "xterm", "NO_COLOR": ""}, true, true, Color16},
+		{"redirected output", map[string]string{"TERM": "xterm-256color"}, false, true, ColorNone},
+		{"FORCE_COLOR when redirected", map[string]string{"FORCE_COLOR": "1"}, false, true, Color16},
+		{"FORCE_COLOR level", map[string]string{"FORCE_COLOR": "3"}, false, true, ColorTrueColor},
+		{"FORCE_COLOR=0", map[string]string{"TERM": "xterm", "FORCE_COLOR": "0"}, true, true, ColorNone},
+		{"CLICOLOR_FORCE when redirected", map[string]string{"CLICOLOR_FORCE": "1", "TERM": "xterm-256color"}, false, true, Color256},
+		{"CLICOLOR=0", map[string]string{"TERM": "xterm", "CLICOLOR": "0"}, true, true, ColorNone},
+		{"dumb terminal", map[string]string{"TERM": "dumb"}, true, true, ColorNone},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			caps := detect(env(tc.env), tc.terminal, tc.vt)
+			if caps.Color != tc.expected {
+				t.Errorf("Color = %v, want %v", caps.Color, tc.expected)
+			}
+			if caps.ColorEnabled() != (tc.expected != ColorNone) {
+				t.Errorf("ColorEnabled() = %v", caps.ColorEnabled())
+			}
+		})
+	}
+}
+
+func TestHyperlinks(t *testing.T) {
+	testCases := []struct {
+		name     string
+		env      map[string]string
+		terminal bool
+		expected bool
+	}{
+		{"iTerm", map[string]string{"TERM_PROGRAM": "iTerm.app", "TERM": "xterm-256color"}, true, true},
+		{"VTE", map[string]string{"VTE_VERSION": "6800", "TERM": "xterm-256color"}, true, true},
+		{"old VTE", map[string]string{"VTE_VERSION": "4200", "TERM": "xterm-256color"}, true, false},
+		{"unknown terminal", map[string]string{"TERM": "xterm-256color"}, true, false},
+		{"redirected output", map[string]string{"TERM_PROGRAM": "iTerm.app"}, false, false},
+		{"CI", map[string]string{"TERM_PROGRAM": "vscode", "CI": "true"}, true, false},
+		{"NO_COLOR", map[string]string{"TERM_PROGRAM": "vscode", "NO_COLOR": "1"}, true, false},
+		{"FORCE_HYPERLINK", map[string]string{"FORCE_HYPERLINK": "1"}, false, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := detect(env(tc.env), tc.terminal, true).Hyperlinks; got != tc.expected {
+				t.Errorf("Hyperlinks = %v, want %v", got, tc.expected)
+			}
+		})
+	}
+
+	linked := Capabilities{Hyperlinks: true}.Hyperlink("https://example.com", "docs")
+	if linked != "\033]8;;https://example.com\033\\docs\033]8;;\033\\" {
+		t.Errorf("Hyperlink() = %q", linked)
+	}
+	if plain := (Capabilities{}).Hyperlink("https://example.com", "docs"); plain != "docs" {
+		t.Errorf("Hyperlink() without support = %q", plain)
+	}
+}
+
+func TestEscape(t *testing.T) {
+	if got := (Capabilities{Color: Color16}).Escape("\033[1m"); got != "\033[1m" {
+		t.Errorf("Escape() with colors = %q", got)
+	}
+	if got := (Capabilities{Color: ColorNone}).Escape("\033[1m"); got != "" {
+		t.Errorf("Escape() without colors = %q", got)
+	}
+}
+
+func TestContainerEnv(t *testing.T) {
+	testCases := []struct {
+		name     string
+		env      map[string]string
+		expected []string
+	}{
+		{
+			name:     "CI defaults",
+			env:      map[string]string{"CI": "true", "TERM": "xterm-256color"},
+			expected: []string{"NO_COLOR=1", "TERM=dumb", "FORCE_COLOR=0", "CI=true"},
+		},
+		{
+			name:     "no color environment variables",
+			env:      map[string]string{},
+			expected: []string{"TERM=xterm-256color", "COLORTERM=truecolor"},
+		},
+		{
+			name:     "TERM environment variable set",
+			env:      map[string]string{"TERM": "screen-256color"},
+			expected: []string{"TERM=screen-256color"},
+		},
+		{
+			name:     "COLORTERM environment variable set",
+			env:      map[string]string{"COLORTERM": "truecolor"},
+			expected: []string{"COLORTERM=truecolor"},
+		},
+		{
+			name:     "multiple color environment variables",
+			env:      map[string]string{"TERM": "xterm-256color", "NO_COLOR": "1", "CLICOLOR": "0"},
+			expected: []string{"TERM=xterm-256color", "CLICOLOR=0", "NO_COLOR=1"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			caps := detect(env(tc.env), true, true)
+			if got := caps.containerEnv(env(tc.env)); !slices.Equal(got, tc.expected) {
+				t.Errorf("containerEnv() = %v, want %v", got, tc.expected)
+			}
+		})
+	}
+}
@@ -21,7 +21,12 @@ func GetSize() (width, height int, err error) {
 
 // IsTerminal returns true if stdout is a terminal
 func IsTerminal() bool {
-	fileInfo, err := os.Stdout.Stat()
+	return IsTerminalFile(os.Stdout)
+}
+
+// IsTerminalFile returns true if f is a terminal
+func IsTerminalFile(f *os.File) bool {
+	fileInfo, err := f.Stat()
 	if err != nil {
 		return false
 	}
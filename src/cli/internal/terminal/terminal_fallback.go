@@ -8,3 +8,8 @@ import "errors"
 func getTerminalSize() (width, height int, err error) {
 	return 80, 24, errors.New("terminal size detection not implemented for this platform")
 }
+
+// Fallback for unsupported platforms: escape sequences are not assumed
+func enableVirtualTerminal() bool {
+	return false
+}
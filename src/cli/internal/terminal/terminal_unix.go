@@ -14,3 +14,9 @@ func getTerminalSize() (width, height int, err error) {
 	}
 	return int(ws.Col), int(ws.Row), nil
 }
+
+// enableVirtualTerminal reports whether ANSI escape sequences are
+// interpreted, which Unix terminals always do
+func enableVirtualTerminal() bool {
+	return true
+}
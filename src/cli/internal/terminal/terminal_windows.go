@@ -52,3 +52,30 @@ func getTerminalSize() (width, height int, err error) {
 	height = int(csbi.Window.Bottom - csbi.Window.Top + 1)
 	return width, height, nil
 }
+
+// enableVirtualTerminalProcessing is the console mode flag making the
+// Windows console interpret ANSI escape sequences (Windows 10 and later)
+const enableVirtualTerminalProcessing = 0x0004
+
+// enableVirtualTerminal turns on ANSI escape sequence processing of the
+// console of stdout. It reports false on consoles that do not support it,
+// such as those of Windows versions before 10, or when stdout is redirected.
+func enableVirtualTerminal() bool {
+	handle, err := syscall.GetStdHandle(syscall.STD_OUTPUT_HANDLE)
+	if err != nil {
+		return false
+	}
+
+	var mode uint32
+	if err := syscall.GetConsoleMode(handle, &mode); err != nil {
+		return false
+	}
+	if mode&enableVirtualTerminalProcessing != 0 {
+		return true
+	}
+
+	kernel32 := syscall.NewLazyDLL("kernel32.dll")
+	setConsoleMode := kernel32.NewProc("SetConsoleMode")
+	ret, _, _ := setConsoleMode.Call(uintptr(handle), uintptr(mode|enableVirtualTerminalProcessing))
+	return ret != 0
+}
@@ -0,0 +1,578 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ready-to-release/eac/src/cli/internal/docker"
+	"github.com/ready-to-release/eac/src/cli/internal/jobs"
+)
+
+// Snapshot is the state shown by the dashboard. r2r ui --json prints the
+// same structure, so scripts and the dashboard see identical data.
+type Snapshot struct {
+	Extensions []ExtensionStatus           `json:"extensions"`
+	Containers []docker.ExtensionContainer `json:"containers"`
+	Jobs       []*jobs.Job                 `json:"jobs"`
+	UpdatedAt  time.Time                   `json:"updated_at"`
+}
+
+// ExtensionStatus is a configured extension and how its image is pinned
+type ExtensionStatus struct {
+	Name    string `json:"name"`
+	Image   string `json:"image"`
+	Pin     string `json:"pin"` // conf.PinPinned, conf.PinTagged or conf.PinUnpinned
+	DocsURL string `json:"docs_url,omitempty"`
+	Running int    `json:"running"` // number of running containers
+}
+
+// DashboardSource provides the data and actions of the dashboard
+type DashboardSource interface {
+	Snapshot(ctx context.Context) (*Snapshot, error)
+	// Run starts an extension without arguments as a background job
+	Run(ctx context.Context, extension string) (*jobs.Job, error)
+	StopContainer(ctx context.Context, containerID string) error
+	CancelJob(ctx context.Context, jobID string) error
+	// ContainerLogs and JobLogs copy the output to w until it ends or ctx
+	// is cancelled
+	ContainerLogs(ctx context.Context, containerID string, w io.Writer) error
+	JobLogs(ctx context.Context, jobID string, w io.Writer) error
+}
+
+// dashboardRefresh is how often the dashboard reloads its snapshot
+const dashboardRefresh = 2 * time.Second
+
+// recentJobs is how many jobs the dashboard lists, newest first
+const recentJobs = 10
+
+// logLines is how many lines of a log stream the dashboard keeps
+const logLines = 500
+
+type pane int
+
+const (
+	paneExtensions pane = iota
+	paneContainers
+	paneJobs
+	paneCount
+)
+
+var paneTitles = [paneCount]string{"Extensions", "Containers", "Jobs"}
+
+type snapshotMsg struct {
+	snapshot *Snapshot
+	err      error
+	// periodic is set for the refreshes of the ticker, which schedule the
+	// next one
+	periodic bool
+}
+
+type refreshTickMsg struct{}
+
+type statusMsg struct {
+	text    string
+	refresh bool
+}
+
+type logChunkMsg struct {
+	stream int
+	text   string
+}
+
+type logEndMsg struct {
+	stream int
+	err    error
+}
+
+// logView is an open log stream
+type logView struct {
+	title  string
+	stream int
+	lines  []string
+	ended  bool
+	cancel context.CancelFunc
+	msgs   chan tea.Msg
+}
+
+// Dashboard is the bubbletea model of r2r ui
+type Dashboard struct {
+	ctx      context.Context
+	source   DashboardSource
+	snapshot *Snapshot
+	err      error
+	focus    pane
+	cursor   [paneCount]int
+	status   string
+	logs     *logView
+	streams  int
+	height   int
+	// openURL opens a URL in the browser; replaced in tests
+	openURL func(url string) error
+}
+
+// NewDashboard returns the dashboard model for source
+func NewDashboard(ctx context.Context, source DashboardSource) *Dashboard {
+	return &Dashboard{ctx: ctx, source: source, openURL: openURL}
+}
+
+// RunDashboard shows the dashboard until the user quits or ctx is cancelled
+func RunDashboard(ctx context.Context, source DashboardSource) error {
+	m := NewDashboard(ctx, source)
+	defer m.closeLogs()
+	_, err := tea.NewProgram(m, tea.WithAltScreen(), tea.WithContext(ctx)).Run()
+	return err
+}
+
+func (m *Dashboard) Init() tea.Cmd {
+	return m.refresh(true)
+}
+
+func (m *Dashboard) refresh(periodic bool) tea.Cmd {
+	return func() tea.Msg {
+		snapshot, err := m.source.Snapshot(m.ctx)
+		return snapshotMsg{snapshot: snapshot, err: err, periodic: periodic}
+	}
+}
+
+func (m *Dashboard) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.height = msg.Height
+		return m, nil
+
+	case snapshotMsg:
+		m.err = msg.err
+		if msg.err == nil {
+			m.snapshot = msg.snapshot
+			m.clampCursors()
+		}
+		if !msg.periodic {
+			return m, nil
+		}
+		return m, tea.Tick(dashboardRefresh, func(time.Time) tea.Msg { return refreshTickMsg{} })
+
+	case refreshTickMsg:
+		return m, m.refresh(true)
+
+	case statusMsg:
+		m.status = msg.text
+		if msg.refresh {
+			return m, m.refresh(false)
+		}
+		return m, nil
+
+	case logChunkMsg:
+		if m.logs == nil || msg.stream != m.logs.stream {
+			return m, nil
+		}
+		m.logs.append(msg.text)
+		return m, waitForLog(m.logs.msgs)
+
+	case logEndMsg:
+		if m.logs != nil && msg.stream == m.logs.stream {
+			m.logs.ended = true
+			if msg.err != nil && m.ctx.Err() == nil {
+				m.status = fmt.Sprintf("Logs ended: %v", msg.err)
+			}
+		}
+		return m, nil
+
+	case tea.KeyMsg:
+		return m.handleKey(msg)
+	}
+	return m, nil
+}
+
+func (m *Dashboard) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "q", "ctrl+c":
+		m.closeLogs()
+		return m, tea.Quit
+	case "esc":
+		m.closeLogs()
+	case "tab":
+		m.focus = (m.focus + 1) % paneCount
+	case "shift+tab":
+		m.focus = (m.focus + paneCount - 1) % paneCount
+	case "up", "k":
+		if m.cursor[m.focus] > 0 {
+			m.cursor[m.focus]--
+		}
+	case "down", "j":
+		if m.cursor[m.focus] < m.paneLen(m.focus)-1 {
+			m.cursor[m.focus]++
+		}
+	case "r":
+		return m, m.runSelected()
+	case "s":
+		return m, m.stopSelected()
+	case "l", "enter":
+		return m, m.followSelected()
+	case "o":
+		return m, m.openDocs()
+	}
+	return m, nil
+}
+
+// runSelected submits the selected extension as a background job
+func (m *Dashboard) runSelected() tea.Cmd {
+	ext := m.selectedExtension()
+	if ext == nil || m.focus != paneExtensions {
+		return nil
+	}
+	m.status = fmt.Sprintf("Starting %s...", ext.Name)
+	name := ext.Name
+	return func() tea.Msg {
+		job, err := m.source.Run(m.ctx, name)
+		if err != nil {
+			return statusMsg{text: fmt.Sprintf("Failed to run %s: %v", name, err), refresh: true}
+		}
+		return statusMsg{text: fmt.Sprintf("Started job %s (%s)", job.ID, name), refresh: true}
+	}
+}
+
+// stopSelected stops the selected container, or cancels the selected job
+func (m *Dashboard) stopSelected() tea.Cmd {
+	switch m.focus {
+	case paneContainers:
+		c := m.selectedContainer()
+		if c == nil {
+			return nil
+		}
+		if c.JobID != "" {
+			return m.cancelJob(c.JobID)
+		}
+		id := c.ID
+		m.status = fmt.Sprintf("Stopping %s...", shortID(id))
+		return func() tea.Msg {
+			if err := m.source.StopContainer(m.ctx, id); err != nil {
+				return statusMsg{text: fmt.Sprintf("Failed to stop %s: %v", shortID(id), err), refresh: true}
+			}
+			return statusMsg{text: fmt.Sprintf("Stopped %s", shortID(id)), refresh: true}
+		}
+	case paneJobs:
+		if job := m.selectedJob(); job != nil {
+			return m.cancelJob(job.ID)
+		}
+	}
+	return nil
+}
+
+func (m *Dashboard) cancelJob(id string) tea.Cmd {
+	m.status = fmt.Sprintf("Cancelling job %s...", id)
+	return func() tea.Msg {
+		if err := m.source.CancelJob(m.ctx, id); err != nil {
+			return statusMsg{text: fmt.Sprintf("Failed to cancel job %s: %v", id, err), refresh: true}
+		}
+		return statusMsg{text: fmt.Sprintf("Cancelled job %s", id), refresh: true}
+	}
+}
+
+// followSelected opens the log stream of the selected container or job
+func (m *Dashboard) followSelected() tea.Cmd {
+	switch m.focus {
+	case paneContainers:
+		if c := m.selectedContainer(); c != nil {
+			id := c.ID
+			return m.openLogs(fmt.Sprintf("%s (%s)", c.Extension, shortID(id)), func(ctx context.Context, w io.Writer) error {
+				return m.source.ContainerLogs(ctx, id, w)
+			})
+		}
+	case paneJobs:
+		if job := m.selectedJob(); job != nil {
+			id := job.ID
+			return m.openLogs(fmt.Sprintf("job %s (%s)", id, job.Extension), func(ctx context.Context, w io.Writer) error {
+				return m.source.JobLogs(ctx, id, w)
+			})
+		}
+	}
+	return nil
+}
+
+// openLogs replaces the open log stream with the output of follow. The
+// output reaches the model as logChunkMsg, one message at a time.
+func (m *Dashboard) openLogs(title string, follow func(ctx context.Context, w io.Writer) error) tea.Cmd {
+	m.closeLogs()
+	m.streams++
+	ctx, cancel := context.WithCancel(m.ctx)
+	view := &logView{title: title, stream: m.streams, cancel: cancel, msgs: make(chan tea.Msg, 64)}
+	m.logs = view
+
+	go func() {
+		defer close(view.msgs)
+		send := func(msg tea.Msg) {
+			select {
+			case view.msgs <- msg:
+			case <-ctx.Done():
+			}
+		}
+		err := follow(ctx, writerFunc(func(p []byte) {
+			send(logChunkMsg{stream: view.stream, text: string(p)})
+		}))
+		send(logEndMsg{stream: view.stream, err: err})
+	}()
+	return waitForLog(view.msgs)
+}
+
+// writerFunc is an io.Writer calling a function with every write
+type writerFunc func(p []byte)
+
+func (f writerFunc) Write(p []byte) (int, error) {
+	f(p)
+	return len(p), nil
+}
+
+func waitForLog(msgs chan tea.Msg) tea.Cmd {
+	return func() tea.Msg {
+		msg, ok := <-msgs
+		if !ok {
+			return nil
+		}
+		return msg
+	}
+}
+
+func (m *Dashboard) closeLogs() {
+	if m.logs != nil {
+		m.logs.cancel()
+		m.logs = nil
+	}
+}
+
+func (v *logView) append(text string) {
+	parts := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	if len(v.lines) > 0 {
+		// Continue the last line, which had no newline yet
+		v.lines[len(v.lines)-1] += parts[0]
+		parts = parts[1:]
+	}
+	v.lines = append(v.lines, parts...)
+	if len(v.lines) > logLines {
+		v.lines = v.lines[len(v.lines)-logLines:]
+	}
+}
+
+// openDocs opens the docs_url of the selected extension, or of the
+// extension of the selected container or job
+func (m *Dashboard) openDocs() tea.Cmd {
+	var name string
+	switch m.focus {
+	case paneExtensions:
+		if ext := m.selectedExtension(); ext != nil {
+			name = ext.Name
+		}
+	case paneContainers:
+		if c := m.selectedContainer(); c != nil {
+			name = c.Extension
+		}
+	case paneJobs:
+		if job := m.selectedJob(); job != nil {
+			name = job.Extension
+		}
+	}
+	if name == "" {
+		return nil
+	}
+
+	var docsURL string
+	for _, ext := range m.snapshot.Extensions {
+		if ext.Name == name {
+			docsURL = ext.DocsURL
+		}
+	}
+	if docsURL == "" {
+		m.status = fmt.Sprintf("%s has no docs_url", name)
+		return nil
+	}
+	return func() tea.Msg {
+		if err := m.openURL(docsURL); err != nil {
+			return statusMsg{text: fmt.Sprintf("Failed to open %s: %v", docsURL, err)}
+		}
+		return statusMsg{text: fmt.Sprintf("Opened %s", docsURL)}
+	}
+}
+
+// openURL opens url with the browser of the platform
+func openURL(url string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", url)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
+	default:
+		cmd = exec.Command("xdg-open", url)
+	}
+	return cmd.Start()
+}
+
+func (m *Dashboard) selectedExtension() *ExtensionStatus {
+	if m.snapshot == nil || len(m.snapshot.Extensions) == 0 {
+		return nil
+	}
+	return &m.snapshot.Extensions[m.cursor[paneExtensions]]
+}
+
+func (m *Dashboard) selectedContainer() *docker.ExtensionContainer {
+	if m.snapshot == nil || len(m.snapshot.Containers) == 0 {
+		return nil
+	}
+	return &m.snapshot.Containers[m.cursor[paneContainers]]
+}
+
+func (m *Dashboard) selectedJob() *jobs.Job {
+	list := m.recentJobs()
+	if len(list) == 0 {
+		return nil
+	}
+	return list[m.cursor[paneJobs]]
+}
+
+// recentJobs returns the newest jobs first
+func (m *Dashboard) recentJobs() []*jobs.Job {
+	if m.snapshot == nil {
+		return nil
+	}
+	var list []*jobs.Job
+	for i := len(m.snapshot.Jobs) - 1; i >= 0 && len(list) < recentJobs; i-- {
+		list = append(list, m.snapshot.Jobs[i])
+	}
+	return list
+}
+
+func (m *Dashboard) paneLen(p pane) int {
+	if m.snapshot == nil {
+		return 0
+	}
+	switch p {
+	case paneExtensions:
+		return len(m.snapshot.Extensions)
+	case paneContainers:
+		return len(m.snapshot.Containers)
+	default:
+		return len(m.recentJobs())
+	}
+}
+
+// clampCursors keeps the cursors on a row after the lists changed
+func (m *Dashboard) clampCursors() {
+	for p := pane(0); p < paneCount; p++ {
+		m.cursor[p] = max(0, min(m.cursor[p], m.paneLen(p)-1))
+	}
+}
+
+func shortID(id string) string {
+	if len(id) > 12 {
+		return id[:12]
+	}
+	return id
+}
+
+var (
+	dashboardTitleStyle  = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("205"))
+	dashboardPaneStyle   = lipgloss.NewStyle().Bold(true)
+	dashboardFocusStyle  = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("205"))
+	dashboardCursorStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
+	dashboardDimStyle    = lipgloss.NewStyle().Faint(true)
+	dashboardErrorStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
+)
+
+func (m *Dashboard) View() string {
+	var b strings.Builder
+	b.WriteString(dashboardTitleStyle.Render("r2r dashboard"))
+	if m.snapshot != nil {
+		b.WriteString(dashboardDimStyle.Render(" · updated " + m.snapshot.UpdatedAt.Local().Format(time.TimeOnly)))
+	}
+	b.WriteString("\n")
+	if m.err != nil {
+		b.WriteString(dashboardErrorStyle.Render(fmt.Sprintf("Failed to refresh: %v", m.err)) + "\n")
+	}
+	if m.snapshot == nil {
+		b.WriteString("\nLoading...\n")
+		return b.String()
+	}
+
+	var extensionRows, containerRows, jobRows []string
+	for _, ext := range m.snapshot.Extensions {
+		running := ""
+		if ext.Running > 0 {
+			running = fmt.Sprintf("%d running", ext.Running)
+		}
+		extensionRows = append(extensionRows, fmt.Sprintf("%-20s %-9s %-12s %s", ext.Name, ext.Pin, running, ext.Image))
+	}
+	for _, c := range m.snapshot.Containers {
+		kind := "run"
+		if c.JobID != "" {
+			kind = "job " + c.JobID
+		}
+		containerRows = append(containerRows, fmt.Sprintf("%-12s %-20s %-10s %-28s %s",
+			shortID(c.ID), c.Extension, c.State, kind, time.Since(c.Created).Round(time.Second)))
+	}
+	for _, job := range m.recentJobs() {
+		exit := "-"
+		if job.ExitCode != nil {
+			exit = fmt.Sprint(*job.ExitCode)
+		}
+		jobRows = append(jobRows, fmt.Sprintf("%-24s %-20s %-10s %-4s %s",
+			job.ID, job.Extension, job.Status, exit, strings.Join(job.Args, " ")))
+	}
+	m.renderPane(&b, paneExtensions, extensionRows)
+	m.renderPane(&b, paneContainers, containerRows)
+	m.renderPane(&b, paneJobs, jobRows)
+
+	if m.logs != nil {
+		title := "Logs of " + m.logs.title
+		if m.logs.ended {
+			title += " (ended)"
+		}
+		b.WriteString("\n" + dashboardPaneStyle.Render(title) + "\n")
+		lines := m.logs.lines
+		if height := m.logHeight(); len(lines) > height {
+			lines = lines[len(lines)-height:]
+		}
+		for _, line := range lines {
+			b.WriteString("  " + line + "\n")
+		}
+	}
+
+	if m.status != "" {
+		b.WriteString("\n" + m.status + "\n")
+	}
+	b.WriteString("\n" + dashboardDimStyle.Render("tab pane · ↑/↓ select · r run · s stop · l logs · esc close logs · o docs · q quit") + "\n")
+	return b.String()
+}
+
+func (m *Dashboard) renderPane(b *strings.Builder, p pane, rows []string) {
+	title := dashboardPaneStyle.Render(paneTitles[p])
+	if p == m.focus {
+		title = dashboardFocusStyle.Render("▸ " + paneTitles[p])
+	}
+	b.WriteString("\n" + title + "\n")
+	if len(rows) == 0 {
+		b.WriteString(dashboardDimStyle.Render("  none") + "\n")
+		return
+	}
+	for i, row := range rows {
+		if p == m.focus && i == m.cursor[p] {
+			b.WriteString(dashboardCursorStyle.Render("> "+row) + "\n")
+			continue
+		}
+		b.WriteString("  " + row + "\n")
+	}
+}
+
+// logHeight is how many log lines fit below the panes
+func (m *Dashboard) logHeight() int {
+	if m.height == 0 {
+		return 15
+	}
+	used := 12 + m.paneLen(paneExtensions) + m.paneLen(paneContainers) + m.paneLen(paneJobs)
+	return max(5, m.height-used)
+}
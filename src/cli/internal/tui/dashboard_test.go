@@ -0,0 +1,188 @@
+//go:build L0
+// +build L0
+
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ready-to-release/eac/src/cli/internal/docker"
+	"github.com/ready-to-release/eac/src/cli/internal/jobs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSource records the actions of the dashboard
+type fakeSource struct {
+	snapshot  *Snapshot
+	ran       []string
+	stopped   []string
+	cancelled []string
+	logs      string
+}
+
+func (f *fakeSource) Snapshot(ctx context.Context) (*Snapshot, error) {
+	return f.snapshot, nil
+}
+
+func (f *fakeSource) Run(ctx context.Context, extension string) (*jobs.Job, error) {
+	f.ran = append(f.ran, extension)
+	if extension == "broken" {
+		return nil, errors.New("no image")
+	}
+	return &jobs.Job{ID: "job-3", Extension: extension}, nil
+}
+
+func (f *fakeSource) StopContainer(ctx context.Context, containerID string) error {
+	f.stopped = append(f.stopped, containerID)
+	return nil
+}
+
+func (f *fakeSource) CancelJob(ctx context.Context, jobID string) error {
+	f.cancelled = append(f.cancelled, jobID)
+	return nil
+}
+
+func (f *fakeSource) ContainerLogs(ctx context.Context, containerID string, w io.Writer) error {
+	_, err := fmt.Fprint(w, f.logs)
+	return err
+}
+
+func (f *fakeSource) JobLogs(ctx context.Context, jobID string, w io.Writer) error {
+	_, err := fmt.Fprintf(w, "output of %s\n", jobID)
+	return err
+}
+
+func newTestDashboard(t *testing.T) (*Dashboard, *fakeSource) {
+	exitCode := 0
+	source := &fakeSource{snapshot: &Snapshot{
+		Extensions: []ExtensionStatus{
+			{Name: "go", Image: "ghcr.io/r2r/go:sha-abc", Pin: "pinned", DocsURL: "https://example.com/go", Running: 1},
+			{Name: "broken", Image: "broken:latest", Pin: "unpinned"},
+		},
+		Containers: []docker.ExtensionContainer{
+			{ID: "run-container", Extension: "go", State: "running"},
+			{ID: "job-container", Extension: "go", JobID: "job-2", State: "running"},
+		},
+		Jobs: []*jobs.Job{
+			{ID: "job-1", Extension: "go", Status: jobs.StatusSucceeded, ExitCode: &exitCode},
+			{ID: "job-2", Extension: "go", Status: jobs.StatusRunning},
+		},
+		UpdatedAt: time.Now(),
+	}}
+	m := NewDashboard(context.Background(), source)
+	m.Update(m.refresh(false)())
+	return m, source
+}
+
+// press sends a key to the dashboard and runs the command it returns
+func press(m *Dashboard, key string) tea.Msg {
+	var msg tea.KeyMsg
+	switch key {
+	case "tab", "esc", "enter":
+		msg = tea.KeyMsg{Type: map[string]tea.KeyType{"tab": tea.KeyTab, "esc": tea.KeyEsc, "enter": tea.KeyEnter}[key]}
+	default:
+		msg = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}
+	}
+	_, cmd := m.Update(msg)
+	if cmd == nil {
+		return nil
+	}
+	result := cmd()
+	if result != nil {
+		m.Update(result)
+	}
+	return result
+}
+
+func TestDashboardView(t *testing.T) {
+	m, _ := newTestDashboard(t)
+
+	view := m.View()
+	assert.Contains(t, view, "go")
+	assert.Contains(t, view, "unpinned")
+	assert.Contains(t, view, "1 running")
+	assert.Contains(t, view, "job job-2")
+	// Newest job first
+	assert.Less(t, strings.Index(view, "job-2 "), strings.Index(view, "job-1 "))
+}
+
+func TestDashboardActions(t *testing.T) {
+	t.Run("runs the selected extension", func(t *testing.T) {
+		m, source := newTestDashboard(t)
+		press(m, "r")
+		assert.Equal(t, []string{"go"}, source.ran)
+		assert.Contains(t, m.status, "Started job job-3")
+
+		press(m, "j")
+		press(m, "r")
+		assert.Contains(t, m.status, "Failed to run broken: no image")
+	})
+
+	t.Run("stops containers and cancels jobs", func(t *testing.T) {
+		m, source := newTestDashboard(t)
+		press(m, "tab")
+		press(m, "s")
+		assert.Equal(t, []string{"run-container"}, source.stopped)
+
+		// A job container is cancelled through its job
+		press(m, "j")
+		press(m, "s")
+		assert.Equal(t, []string{"job-2"}, source.cancelled)
+
+		press(m, "tab")
+		press(m, "j")
+		press(m, "s")
+		assert.Equal(t, []string{"job-2", "job-1"}, source.cancelled)
+	})
+
+	t.Run("opens the docs", func(t *testing.T) {
+		m, _ := newTestDashboard(t)
+		var opened []string
+		m.openURL = func(url string) error {
+			opened = append(opened, url)
+			return nil
+		}
+		press(m, "o")
+		assert.Equal(t, []string{"https://example.com/go"}, opened)
+
+		press(m, "j")
+		press(m, "o")
+		assert.Equal(t, "broken has no docs_url", m.status)
+	})
+}
+
+func TestDashboardLogs(t *testing.T) {
+	m, source := newTestDashboard(t)
+	source.logs = "line 1\nline"
+
+	press(m, "tab")
+	msg := press(m, "enter")
+	require.IsType(t, logChunkMsg{}, msg)
+	// Follow the stream until it ends
+	cmd := waitForLog(m.logs.msgs)
+	for m.logs != nil && !m.logs.ended {
+		m.Update(cmd())
+	}
+	require.NotNil(t, m.logs)
+	assert.Equal(t, []string{"line 1", "line"}, m.logs.lines)
+	assert.Contains(t, m.View(), "Logs of go (run-containe) (ended)")
+
+	press(m, "esc")
+	assert.Nil(t, m.logs)
+	assert.NotContains(t, m.View(), "Logs of")
+}
+
+func TestLogViewAppend(t *testing.T) {
+	v := &logView{}
+	v.append("a")
+	v.append("b\r\nc\n")
+	assert.Equal(t, []string{"ab", "c", ""}, v.lines)
+}
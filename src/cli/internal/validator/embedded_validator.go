@@ -1,34 +1,46 @@
 package validator
 
 import (
-	_ "embed"
 	"encoding/json"
 	"fmt"
 	"strings"
 
+	"github.com/ready-to-release/eac/contracts"
 	"github.com/xeipuuv/gojsonschema"
 )
 
-// Embed the r2r-cli-config v0.1.0 schema at compile time// The schema is copied from contracts/cli/0.1.0/config.json by build-cli.ps1//
-//go:embed config/schema.json
-var embeddedSchema string
+// The r2r-cli-config schema is embedded from contracts/cli/<version>/schema.json
+var embeddedSchema = contracts.CLISchema()
 
 // EmbeddedValidator validates configurations using the embedded JSON schema
 type EmbeddedValidator struct {
 	schema *gojsonschema.Schema
+	raw    map[string]interface{} // parsed schema, walked by ValidateYAML
 }
 
 // NewEmbeddedValidator creates a validator using the embedded schema
 func NewEmbeddedValidator() (*EmbeddedValidator, error) {
+	return NewSchemaValidator(embeddedSchema)
+}
+
+// NewSchemaValidator creates a validator for the given JSON schema, e.g. one of
+// the extension metadata schemas
+func NewSchemaValidator(schemaJSON string) (*EmbeddedValidator, error) {
 	// Load the embedded schema
-	schemaLoader := gojsonschema.NewStringLoader(embeddedSchema)
+	schemaLoader := gojsonschema.NewStringLoader(schemaJSON)
 	schema, err := gojsonschema.NewSchema(schemaLoader)
 	if err != nil {
 		return nil, fmt.Errorf("failed to compile embedded schema: %w", err)
 	}
 
+	var raw map[string]interface{}
+	if err := json.Unmarshal([]byte(schemaJSON), &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse embedded schema: %w", err)
+	}
+
 	return &EmbeddedValidator{
 		schema: schema,
+		raw:    raw,
 	}, nil
 }
 
@@ -12,6 +12,12 @@ type ValidationError struct {
 	Message  string      // Human-readable error message
 	Value    interface{} // Actual value that failed validation
 	Expected string      // Expected format/value description
+
+	// Source position, set when validating a YAML document
+	File       string // Configuration file path
+	Line       int    // 1-based line, 0 when unknown
+	Column     int    // 1-based column, 0 when unknown
+	Suggestion string // Quick fix (e.g., "did you mean 'image'?")
 }
 
 // Error implements the error interface
@@ -22,6 +28,17 @@ func (e ValidationError) Error() string {
 	return e.Message
 }
 
+// Location returns "file:line:column" for errors with a source position
+func (e ValidationError) Location() string {
+	if e.Line == 0 {
+		return e.File
+	}
+	if e.File == "" {
+		return fmt.Sprintf("%d:%d", e.Line, e.Column)
+	}
+	return fmt.Sprintf("%s:%d:%d", e.File, e.Line, e.Column)
+}
+
 // ValidationResult contains all validation errors
 type ValidationResult struct {
 	Errors   []ValidationError
@@ -93,6 +110,7 @@ const (
 	RuleFormat         = "format"
 	RuleType           = "type"
 	RuleUnique         = "unique"
+	RuleUnknownField   = "additionalProperties"
 	RuleDeprecated     = "deprecated"
 	RuleNotImplemented = "not_implemented"
 )
@@ -104,4 +122,5 @@ const (
 	SeverityError Severity = iota
 	SeverityWarning
 	SeverityInfo
+	SeverityOff // check disabled
 )
@@ -0,0 +1,92 @@
+package validator
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// draft07 is the newest JSON schema draft the YAML language server supports
+const draft07 = "http://json-schema.org/draft-07/schema#"
+
+// EditorSchema returns the embedded schema converted for the YAML language
+// server, see ToDraft07
+func EditorSchema() (string, error) {
+	return ToDraft07(embeddedSchema)
+}
+
+// ToDraft07 rewrites a JSON schema with the draft-07 keywords the YAML
+// language server understands: $defs become definitions, their $refs
+// follow, and dependentRequired and dependentSchemas merge into
+// dependencies. Other keywords are kept, editors ignore what they do not
+// know.
+func ToDraft07(schemaJSON string) (string, error) {
+	var schema interface{}
+	if err := json.Unmarshal([]byte(schemaJSON), &schema); err != nil {
+		return "", fmt.Errorf("failed to parse schema: %w", err)
+	}
+	root, ok := toDraft07(schema).(map[string]interface{})
+	if !ok {
+		return "", fmt.Errorf("schema is not a JSON object")
+	}
+	root["$schema"] = draft07
+
+	data, err := json.MarshalIndent(root, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode schema: %w", err)
+	}
+	return string(data) + "\n", nil
+}
+
+func toDraft07(node interface{}) interface{} {
+	switch node := node.(type) {
+	case []interface{}:
+		for i, item := range node {
+			node[i] = toDraft07(item)
+		}
+		return node
+	case map[string]interface{}:
+		converted := make(map[string]interface{}, len(node))
+		dependencies := map[string]interface{}{}
+		for key, value := range node {
+			switch key {
+			case "$defs", "definitions":
+				converted["definitions"] = namedSchemas(value)
+			case "properties", "patternProperties":
+				converted[key] = namedSchemas(value)
+			case "$ref":
+				if ref, ok := value.(string); ok {
+					value = strings.Replace(ref, "/$defs/", "/definitions/", 1)
+				}
+				converted[key] = value
+			case "dependentRequired", "dependentSchemas", "dependencies":
+				if deps, ok := namedSchemas(value).(map[string]interface{}); ok {
+					for name, dep := range deps {
+						dependencies[name] = dep
+					}
+				}
+			default:
+				converted[key] = toDraft07(value)
+			}
+		}
+		if len(dependencies) > 0 {
+			converted["dependencies"] = dependencies
+		}
+		return converted
+	default:
+		return node
+	}
+}
+
+// namedSchemas converts the schemas of a keyword mapping names to schemas,
+// like properties, leaving the names alone even when they look like keywords
+func namedSchemas(node interface{}) interface{} {
+	schemas, ok := node.(map[string]interface{})
+	if !ok {
+		return node
+	}
+	for name, schema := range schemas {
+		schemas[name] = toDraft07(schema)
+	}
+	return schemas
+}
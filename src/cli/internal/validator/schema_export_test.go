@@ -0,0 +1,55 @@
+//go:build L0
+
+package validator
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestToDraft07(t *testing.T) {
+	schema := `{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "$defs": {"hook": {"type": "object", "dependentRequired": {"args": ["extension"]}}},
+  "properties": {
+    "dependencies": {"type": "string"},
+    "hooks": {"type": "array", "items": {"$ref": "#/$defs/hook"}}
+  }
+}`
+	converted, err := ToDraft07(schema)
+	if err != nil {
+		t.Fatalf("ToDraft07 failed: %v", err)
+	}
+
+	var got map[string]interface{}
+	if err := json.Unmarshal([]byte(converted), &got); err != nil {
+		t.Fatalf("converted schema is not JSON: %v", err)
+	}
+	var want map[string]interface{}
+	_ = json.Unmarshal([]byte(`{
+  "$schema": "http://json-schema.org/draft-07/schema#",
+  "definitions": {"hook": {"type": "object", "dependencies": {"args": ["extension"]}}},
+  "properties": {
+    "dependencies": {"type": "string"},
+    "hooks": {"type": "array", "items": {"$ref": "#/definitions/hook"}}
+  }
+}`), &want)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ToDraft07() =\n%s", converted)
+	}
+
+	if _, err := ToDraft07(`[]`); err == nil {
+		t.Error("ToDraft07 should reject a schema that is not an object")
+	}
+}
+
+func TestEditorSchema(t *testing.T) {
+	schema, err := EditorSchema()
+	if err != nil {
+		t.Fatalf("EditorSchema failed: %v", err)
+	}
+	if _, err := NewSchemaValidator(schema); err != nil {
+		t.Errorf("the editor schema should compile: %v", err)
+	}
+}
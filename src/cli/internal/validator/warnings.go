@@ -0,0 +1,236 @@
+package validator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Warning rule identifiers, used as keys of the validation.severities block
+const (
+	WarnUnpinnedTag           = "unpinned-tag"
+	WarnMissingResourceLimits = "missing-resource-limits"
+	WarnPrivilegedExtension   = "privileged-extension"
+	WarnHostNetwork           = "host-network"
+)
+
+// WarningRules lists every warning rule with its description
+var WarningRules = map[string]string{
+	WarnUnpinnedTag:           "extension image has no version tag or digest, or uses :latest",
+	WarnMissingResourceLimits: "extension has no memory_limit or cpu_limit, directly or through defaults",
+	WarnPrivilegedExtension:   "extension runs privileged",
+	WarnHostNetwork:           "extension uses the host network",
+}
+
+// ValidationConfigKey is the top-level key configuring the validator itself:
+//
+//	validation:
+//	  severities:
+//	    unpinned-tag: error
+//	    missing-resource-limits: off
+//
+// It is read by the validator and not checked against the schema.
+const ValidationConfigKey = "validation"
+
+// ParseSeverity parses "error", "warning" or "off"
+func ParseSeverity(s string) (Severity, error) {
+	switch strings.ToLower(s) {
+	case "error":
+		return SeverityError, nil
+	case "warning", "warn":
+		return SeverityWarning, nil
+	case "off", "none", "ignore":
+		return SeverityOff, nil
+	}
+	return SeverityOff, fmt.Errorf("invalid severity %q: use error, warning or off", s)
+}
+
+// severityOverrides reads validation.severities from the root mapping. Unknown
+// rules and invalid severities are reported as errors.
+func (w *yamlWalker) severityOverrides(root *yaml.Node) (map[string]Severity, []ValidationError) {
+	overrides := make(map[string]Severity)
+	var errs []ValidationError
+
+	block := lookup(root, ValidationConfigKey)
+	if block == nil {
+		return overrides, nil
+	}
+	if block.Kind != yaml.MappingNode {
+		return overrides, []ValidationError{w.newError(block, ValidationConfigKey, RuleType,
+			"Invalid type. Expected: object", "type: object")}
+	}
+
+	for _, pair := range mappingPairs(block) {
+		field := joinField(ValidationConfigKey, pair.key.Value)
+		if pair.key.Value != "severities" {
+			err := w.newError(pair.key, field, RuleUnknownField,
+				fmt.Sprintf("Additional property %s is not allowed", pair.key.Value), "")
+			if closestMatch(pair.key.Value, []string{"severities"}) != "" {
+				err.Suggestion = "did you mean 'severities'?"
+			}
+			errs = append(errs, err)
+			continue
+		}
+
+		severities := resolveAlias(pair.value)
+		if severities.Kind != yaml.MappingNode {
+			errs = append(errs, w.newError(severities, field, RuleType, "Invalid type. Expected: object", "type: object"))
+			continue
+		}
+		for _, rule := range mappingPairs(severities) {
+			ruleField := joinField(field, rule.key.Value)
+			if _, ok := WarningRules[rule.key.Value]; !ok {
+				err := w.newError(rule.key, ruleField, RuleUnknownField,
+					fmt.Sprintf("Unknown warning rule %s", rule.key.Value),
+					fmt.Sprintf("one of [%s]", strings.Join(sortedRules(), ", ")))
+				if match := closestMatch(rule.key.Value, sortedRules()); match != "" {
+					err.Suggestion = fmt.Sprintf("did you mean '%s'?", match)
+				}
+				errs = append(errs, err)
+				continue
+			}
+			severity, err := ParseSeverity(resolveAlias(rule.value).Value)
+			if err != nil {
+				errs = append(errs, w.newError(rule.value, ruleField, RuleEnum, err.Error(), "one of [error, warning, off]"))
+				continue
+			}
+			overrides[rule.key.Value] = severity
+		}
+	}
+
+	return overrides, errs
+}
+
+// checkWarnings runs the warning rules over the extensions of the document
+func (w *yamlWalker) checkWarnings(root *yaml.Node) []ValidationError {
+	var warnings []ValidationError
+
+	defaults := lookup(root, "defaults")
+	defaultMemory := lookup(defaults, "memory_limit") != nil
+	defaultCPU := lookup(defaults, "cpu_limit") != nil
+
+	extensions := lookup(root, "extensions")
+	if extensions == nil || extensions.Kind != yaml.SequenceNode {
+		return nil
+	}
+
+	for i, ext := range extensions.Content {
+		ext = resolveAlias(ext)
+		if ext.Kind != yaml.MappingNode {
+			continue
+		}
+		field := fmt.Sprintf("extensions[%d]", i)
+		name := scalarValue(lookup(ext, "name"))
+
+		if image := lookup(ext, "image"); image != nil && image.Kind == yaml.ScalarNode && scalarValue(lookup(ext, "load_local")) != "true" {
+			if tag, pinned := imageTag(image.Value); !pinned {
+				warning := w.newError(image, field+".image", WarnUnpinnedTag,
+					fmt.Sprintf("image %s is not pinned to a version", image.Value), "a version tag or digest")
+				if tag == "latest" {
+					warning.Message = fmt.Sprintf("image %s uses the mutable :latest tag", image.Value)
+				}
+				warning.Suggestion = "pin a version tag (e.g. :1.2.3) or a digest (@sha256:...)"
+				warnings = append(warnings, warning)
+			}
+		}
+
+		memory := lookup(ext, "memory_limit") != nil || defaultMemory
+		cpu := lookup(ext, "cpu_limit") != nil || defaultCPU
+		if !memory || !cpu {
+			var missing []string
+			if !memory {
+				missing = append(missing, "memory_limit")
+			}
+			if !cpu {
+				missing = append(missing, "cpu_limit")
+			}
+			warning := w.newError(ext, field, WarnMissingResourceLimits,
+				fmt.Sprintf("extension %s has no %s", name, strings.Join(missing, " or ")), strings.Join(missing, ", "))
+			warning.Suggestion = "set limits on the extension or in defaults"
+			warnings = append(warnings, warning)
+		}
+
+		if privileged := lookup(ext, "privileged"); privileged != nil && privileged.Value == "true" {
+			warning := w.newError(privileged, field+".privileged", WarnPrivilegedExtension,
+				fmt.Sprintf("extension %s runs privileged with full access to the host", name), "false")
+			warning.Suggestion = "remove privileged unless the extension needs host devices"
+			warnings = append(warnings, warning)
+		}
+
+		if network := lookup(ext, "network_mode"); network != nil && network.Value == "host" {
+			warning := w.newError(network, field+".network_mode", WarnHostNetwork,
+				fmt.Sprintf("extension %s shares the host network", name), "bridge")
+			warning.Suggestion = "use bridge with ports mappings instead"
+			warnings = append(warnings, warning)
+		}
+	}
+
+	return warnings
+}
+
+// applySeverities moves each warning to errors, warnings or nowhere according
+// to the overrides; rules default to warning
+func applySeverities(result *ValidationResult, warnings []ValidationError, overrides map[string]Severity) {
+	for _, warning := range warnings {
+		severity, ok := overrides[warning.Rule]
+		if !ok {
+			severity = SeverityWarning
+		}
+		switch severity {
+		case SeverityError:
+			result.Errors = append(result.Errors, warning)
+		case SeverityWarning:
+			result.Warnings = append(result.Warnings, warning)
+		}
+	}
+}
+
+// imageTag returns the tag of an image reference and whether it is pinned
+// (a digest, or a tag other than latest)
+func imageTag(image string) (string, bool) {
+	if strings.Contains(image, "@") {
+		return "", true
+	}
+	lastSlash := strings.LastIndex(image, "/")
+	colon := strings.LastIndex(image, ":")
+	if colon <= lastSlash {
+		return "", false
+	}
+	tag := image[colon+1:]
+	return tag, tag != "latest"
+}
+
+// lookup returns the value of key in a mapping node, or nil
+func lookup(node *yaml.Node, key string) *yaml.Node {
+	if node == nil {
+		return nil
+	}
+	node = resolveAlias(node)
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for _, pair := range mappingPairs(node) {
+		if pair.key.Value == key {
+			return resolveAlias(pair.value)
+		}
+	}
+	return nil
+}
+
+func scalarValue(node *yaml.Node) string {
+	if node == nil || node.Kind != yaml.ScalarNode {
+		return ""
+	}
+	return node.Value
+}
+
+func sortedRules() []string {
+	rules := make([]string, 0, len(WarningRules))
+	for rule := range WarningRules {
+		rules = append(rules, rule)
+	}
+	sort.Strings(rules)
+	return rules
+}
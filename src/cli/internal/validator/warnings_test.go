@@ -0,0 +1,110 @@
+//go:build L0
+
+package validator
+
+import (
+	"testing"
+)
+
+func TestWarningRules(t *testing.T) {
+	result := validateTestYAML(t, `extensions:
+  - name: pwsh
+    image: pwsh:latest
+  - name: go
+    image: ghcr.io/org/go
+  - name: pinned
+    image: localhost:5000/org/pinned:1.2.3
+  - name: digest
+    image: org/digest@sha256:abc
+`)
+	if !result.IsValid() {
+		t.Fatalf("Expected no errors, got: %v", result.Errors)
+	}
+
+	unpinned := 0
+	limits := 0
+	for _, w := range result.Warnings {
+		switch w.Rule {
+		case WarnUnpinnedTag:
+			unpinned++
+		case WarnMissingResourceLimits:
+			limits++
+		}
+	}
+	if unpinned != 2 {
+		t.Errorf("Expected 2 unpinned-tag warnings, got %d: %v", unpinned, result.Warnings)
+	}
+	if limits != 4 {
+		t.Errorf("Expected 4 missing-resource-limits warnings, got %d", limits)
+	}
+}
+
+func TestWarningDefaultsSatisfyLimits(t *testing.T) {
+	v, err := NewSchemaValidator(`{"type": "object"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := v.ValidateYAML([]byte(`defaults:
+  memory_limit: 1g
+  cpu_limit: "1.0"
+extensions:
+  - name: pwsh
+    image: pwsh:1.0.0
+    privileged: true
+    network_mode: host
+`), "r2r-cli.yml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rules := map[string]int{}
+	for _, w := range result.Warnings {
+		rules[w.Rule]++
+	}
+	if rules[WarnMissingResourceLimits] != 0 {
+		t.Errorf("Defaults should satisfy resource limits, got: %v", result.Warnings)
+	}
+	if rules[WarnPrivilegedExtension] != 1 || rules[WarnHostNetwork] != 1 {
+		t.Errorf("Expected privileged and host network warnings, got: %v", result.Warnings)
+	}
+	if result.Warnings[0].Line == 0 {
+		t.Error("Warnings should carry a position")
+	}
+}
+
+func TestSeverityOverrides(t *testing.T) {
+	result := validateTestYAML(t, `validation:
+  severities:
+    unpinned-tag: error
+    missing-resource-limits: off
+extensions:
+  - name: pwsh
+    image: pwsh:latest
+`)
+	if len(result.Warnings) != 0 {
+		t.Errorf("Expected missing-resource-limits to be off, got: %v", result.Warnings)
+	}
+	if len(result.Errors) != 1 || result.Errors[0].Rule != WarnUnpinnedTag {
+		t.Errorf("Expected unpinned-tag promoted to error, got: %v", result.Errors)
+	}
+}
+
+func TestSeverityOverridesInvalid(t *testing.T) {
+	result := validateTestYAML(t, `validation:
+  severities:
+    unpined-tag: error
+    host-network: fatal
+extensions:
+  - name: pwsh
+    image: pwsh:1.0.0
+`)
+	if len(result.Errors) != 2 {
+		t.Fatalf("Expected 2 errors, got: %v", result.Errors)
+	}
+	if result.Errors[0].Suggestion != "did you mean 'unpinned-tag'?" {
+		t.Errorf("Expected rule suggestion, got %q", result.Errors[0].Suggestion)
+	}
+	if result.Errors[1].Rule != RuleEnum || result.Errors[1].Line != 4 {
+		t.Errorf("Expected invalid severity at line 4, got: %+v", result.Errors[1])
+	}
+}
@@ -0,0 +1,502 @@
+package validator
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ValidateYAML validates a YAML document directly against the embedded schema,
+// walking the yaml.Node tree so every error carries the file, line and column
+// of the offending node. Common mistakes get a quick-fix suggestion: enum
+// values with the wrong case and misspelled keys. Warning rules run after the
+// schema checks, with severities taken from the validation block.
+func (v *EmbeddedValidator) ValidateYAML(data []byte, file string) (*ValidationResult, error) {
+	result := &ValidationResult{
+		Errors:   []ValidationError{},
+		Warnings: []ValidationError{},
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", file, err)
+	}
+
+	root := &yaml.Node{Kind: yaml.MappingNode, Line: 1, Column: 1}
+	if len(doc.Content) > 0 {
+		root = doc.Content[0]
+	}
+
+	w := &yamlWalker{root: v.raw, file: file}
+	overrides, errs := w.severityOverrides(root)
+	result.Errors = append(errs, w.validate(withoutKey(root, ValidationConfigKey), v.raw, "")...)
+	applySeverities(result, w.checkWarnings(root), overrides)
+	return result, nil
+}
+
+// withoutKey returns a copy of a mapping node without key
+func withoutKey(node *yaml.Node, key string) *yaml.Node {
+	if node.Kind != yaml.MappingNode {
+		return node
+	}
+	copied := *node
+	copied.Content = nil
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value != key {
+			copied.Content = append(copied.Content, node.Content[i], node.Content[i+1])
+		}
+	}
+	return &copied
+}
+
+// yamlWalker validates yaml.Node trees against a JSON schema held as a
+// generic map. It covers the keywords used by the CLI schema: $ref, type,
+// properties, required, additionalProperties, items, enum, pattern, bounds,
+// uniqueItems and allOf/anyOf/oneOf.
+type yamlWalker struct {
+	root map[string]interface{}
+	file string
+}
+
+func (w *yamlWalker) validate(node *yaml.Node, schema map[string]interface{}, field string) []ValidationError {
+	node = resolveAlias(node)
+	schema = w.resolveRef(schema)
+	if schema == nil {
+		return nil
+	}
+
+	var errs []ValidationError
+
+	if types := schemaTypes(schema); len(types) > 0 && !matchesAnyType(node, types) {
+		err := w.newError(node, field, RuleType,
+			fmt.Sprintf("Invalid type. Expected: %s, given: %s", strings.Join(types, " or "), nodeType(node)),
+			fmt.Sprintf("type: %s", strings.Join(types, " or ")))
+		if node.Kind == yaml.ScalarNode && node.Tag == "!!str" && quotedMatches(node.Value, types) {
+			err.Suggestion = "remove the quotes"
+		}
+		return append(errs, err)
+	}
+
+	if enum, ok := schema["enum"].([]interface{}); ok && node.Kind == yaml.ScalarNode {
+		errs = append(errs, w.checkEnum(node, enum, field)...)
+	}
+
+	switch node.Kind {
+	case yaml.MappingNode:
+		errs = append(errs, w.validateMapping(node, schema, field)...)
+	case yaml.SequenceNode:
+		errs = append(errs, w.validateSequence(node, schema, field)...)
+	case yaml.ScalarNode:
+		errs = append(errs, w.validateScalar(node, schema, field)...)
+	}
+
+	errs = append(errs, w.validateCombinators(node, schema, field)...)
+	return errs
+}
+
+func (w *yamlWalker) validateMapping(node *yaml.Node, schema map[string]interface{}, field string) []ValidationError {
+	var errs []ValidationError
+	properties, _ := schema["properties"].(map[string]interface{})
+	pairs := mappingPairs(node)
+
+	present := make(map[string]bool, len(pairs))
+	for _, pair := range pairs {
+		present[pair.key.Value] = true
+	}
+
+	if required, ok := schema["required"].([]interface{}); ok {
+		for _, r := range required {
+			name, _ := r.(string)
+			if name != "" && !present[name] {
+				errs = append(errs, w.newError(node, field, RuleRequired,
+					fmt.Sprintf("%s is required", name), name))
+			}
+		}
+	}
+
+	for _, pair := range pairs {
+		key := pair.key.Value
+		childField := joinField(field, key)
+
+		if propSchema, ok := properties[key].(map[string]interface{}); ok {
+			errs = append(errs, w.validate(pair.value, propSchema, childField)...)
+			continue
+		}
+
+		switch additional := schema["additionalProperties"].(type) {
+		case bool:
+			if !additional {
+				err := w.newError(pair.key, childField, RuleUnknownField,
+					fmt.Sprintf("Additional property %s is not allowed", key), "")
+				if match := closestMatch(key, sortedKeys(properties)); match != "" {
+					err.Suggestion = fmt.Sprintf("did you mean '%s'?", match)
+				}
+				errs = append(errs, err)
+			}
+		case map[string]interface{}:
+			errs = append(errs, w.validate(pair.value, additional, childField)...)
+		}
+	}
+
+	return errs
+}
+
+func (w *yamlWalker) validateSequence(node *yaml.Node, schema map[string]interface{}, field string) []ValidationError {
+	var errs []ValidationError
+
+	if minItems, ok := number(schema["minItems"]); ok && float64(len(node.Content)) < minItems {
+		errs = append(errs, w.newError(node, field, RuleMinItems,
+			fmt.Sprintf("Array must have at least %v items", minItems), fmt.Sprintf(">= %v items", minItems)))
+	}
+	if maxItems, ok := number(schema["maxItems"]); ok && float64(len(node.Content)) > maxItems {
+		errs = append(errs, w.newError(node, field, RuleMaxItems,
+			fmt.Sprintf("Array must have at most %v items", maxItems), fmt.Sprintf("<= %v items", maxItems)))
+	}
+
+	if unique, _ := schema["uniqueItems"].(bool); unique {
+		seen := make(map[string]bool)
+		for _, item := range node.Content {
+			item = resolveAlias(item)
+			if item.Kind != yaml.ScalarNode {
+				continue
+			}
+			if seen[item.Value] {
+				errs = append(errs, w.newError(item, field, RuleUnique,
+					fmt.Sprintf("Array items must be unique, %s is repeated", item.Value), "unique items"))
+			}
+			seen[item.Value] = true
+		}
+	}
+
+	if items, ok := schema["items"].(map[string]interface{}); ok {
+		for i, item := range node.Content {
+			errs = append(errs, w.validate(item, items, fmt.Sprintf("%s[%d]", field, i))...)
+		}
+	}
+
+	return errs
+}
+
+func (w *yamlWalker) validateScalar(node *yaml.Node, schema map[string]interface{}, field string) []ValidationError {
+	var errs []ValidationError
+
+	if node.Tag == "!!str" {
+		if pattern, ok := schema["pattern"].(string); ok {
+			re, err := regexp.Compile(pattern)
+			if err == nil && !re.MatchString(node.Value) {
+				errs = append(errs, w.newError(node, field, RulePattern,
+					fmt.Sprintf("Does not match pattern '%s'", pattern), fmt.Sprintf("match pattern %s", pattern)))
+			}
+		}
+		if minLength, ok := number(schema["minLength"]); ok && float64(len(node.Value)) < minLength {
+			errs = append(errs, w.newError(node, field, "minLength",
+				fmt.Sprintf("String length must be greater than or equal to %v", minLength), fmt.Sprintf(">= %v characters", minLength)))
+		}
+		if maxLength, ok := number(schema["maxLength"]); ok && float64(len(node.Value)) > maxLength {
+			errs = append(errs, w.newError(node, field, "maxLength",
+				fmt.Sprintf("String length must be less than or equal to %v", maxLength), fmt.Sprintf("<= %v characters", maxLength)))
+		}
+	}
+
+	if node.Tag == "!!int" || node.Tag == "!!float" {
+		value, err := strconv.ParseFloat(node.Value, 64)
+		if err != nil {
+			return errs
+		}
+		if minimum, ok := number(schema["minimum"]); ok && value < minimum {
+			errs = append(errs, w.newError(node, field, RuleMinimum,
+				fmt.Sprintf("Must be greater than or equal to %v", minimum), fmt.Sprintf(">= %v", minimum)))
+		}
+		if maximum, ok := number(schema["maximum"]); ok && value > maximum {
+			errs = append(errs, w.newError(node, field, RuleMaximum,
+				fmt.Sprintf("Must be less than or equal to %v", maximum), fmt.Sprintf("<= %v", maximum)))
+		}
+	}
+
+	return errs
+}
+
+func (w *yamlWalker) validateCombinators(node *yaml.Node, schema map[string]interface{}, field string) []ValidationError {
+	var errs []ValidationError
+
+	if allOf, ok := schema["allOf"].([]interface{}); ok {
+		for _, sub := range allOf {
+			if subSchema, ok := sub.(map[string]interface{}); ok {
+				errs = append(errs, w.validate(node, subSchema, field)...)
+			}
+		}
+	}
+
+	for _, keyword := range []string{"anyOf", "oneOf"} {
+		branches, ok := schema[keyword].([]interface{})
+		if !ok {
+			continue
+		}
+		matched := 0
+		for _, sub := range branches {
+			if subSchema, ok := sub.(map[string]interface{}); ok && len(w.validate(node, subSchema, field)) == 0 {
+				matched++
+			}
+		}
+		switch {
+		case matched == 0:
+			errs = append(errs, w.newError(node, field, keyword,
+				fmt.Sprintf("Must validate against at least one schema in %s", keyword), keyword))
+		case keyword == "oneOf" && matched > 1:
+			errs = append(errs, w.newError(node, field, keyword,
+				"Must validate against exactly one schema in oneOf, matched several", keyword))
+		}
+	}
+
+	return errs
+}
+
+func (w *yamlWalker) checkEnum(node *yaml.Node, enum []interface{}, field string) []ValidationError {
+	values := make([]string, len(enum))
+	for i, e := range enum {
+		values[i] = fmt.Sprintf("%v", e)
+		if values[i] == node.Value {
+			return nil
+		}
+	}
+
+	err := w.newError(node, field, RuleEnum,
+		fmt.Sprintf("%s must be one of the following: %s", node.Value, strings.Join(quoteAll(values), ", ")),
+		fmt.Sprintf("one of [%s]", strings.Join(values, ", ")))
+	for _, value := range values {
+		if strings.EqualFold(value, node.Value) {
+			err.Suggestion = fmt.Sprintf("did you mean '%s'? (values are case-sensitive)", value)
+		}
+	}
+	if err.Suggestion == "" {
+		if match := closestMatch(node.Value, values); match != "" {
+			err.Suggestion = fmt.Sprintf("did you mean '%s'?", match)
+		}
+	}
+	return []ValidationError{err}
+}
+
+func (w *yamlWalker) newError(node *yaml.Node, field, rule, message, expected string) ValidationError {
+	var value interface{}
+	if node.Kind == yaml.ScalarNode {
+		value = node.Value
+	}
+	return ValidationError{
+		Field:    field,
+		Rule:     rule,
+		Message:  message,
+		Value:    value,
+		Expected: expected,
+		File:     w.file,
+		Line:     node.Line,
+		Column:   node.Column,
+	}
+}
+
+// resolveRef follows local "#/..." references
+func (w *yamlWalker) resolveRef(schema map[string]interface{}) map[string]interface{} {
+	for i := 0; i < 32; i++ {
+		ref, ok := schema["$ref"].(string)
+		if !ok {
+			return schema
+		}
+		if !strings.HasPrefix(ref, "#/") {
+			return nil
+		}
+		var current interface{} = w.root
+		for _, part := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+			m, ok := current.(map[string]interface{})
+			if !ok {
+				return nil
+			}
+			current = m[strings.ReplaceAll(strings.ReplaceAll(part, "~1", "/"), "~0", "~")]
+		}
+		next, ok := current.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		schema = next
+	}
+	return nil
+}
+
+type yamlPair struct {
+	key   *yaml.Node
+	value *yaml.Node
+}
+
+// mappingPairs returns the key/value pairs of a mapping, expanding "<<" merge keys
+func mappingPairs(node *yaml.Node) []yamlPair {
+	var pairs []yamlPair
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		if key.Tag == "!!merge" {
+			merged := resolveAlias(value)
+			sources := []*yaml.Node{merged}
+			if merged.Kind == yaml.SequenceNode {
+				sources = merged.Content
+			}
+			for _, source := range sources {
+				if source = resolveAlias(source); source.Kind == yaml.MappingNode {
+					pairs = append(pairs, mappingPairs(source)...)
+				}
+			}
+			continue
+		}
+		pairs = append(pairs, yamlPair{key: key, value: value})
+	}
+	return pairs
+}
+
+func resolveAlias(node *yaml.Node) *yaml.Node {
+	for node.Kind == yaml.AliasNode && node.Alias != nil {
+		node = node.Alias
+	}
+	return node
+}
+
+func schemaTypes(schema map[string]interface{}) []string {
+	switch t := schema["type"].(type) {
+	case string:
+		return []string{t}
+	case []interface{}:
+		types := make([]string, 0, len(t))
+		for _, item := range t {
+			if s, ok := item.(string); ok {
+				types = append(types, s)
+			}
+		}
+		return types
+	}
+	return nil
+}
+
+func matchesAnyType(node *yaml.Node, types []string) bool {
+	given := nodeType(node)
+	for _, t := range types {
+		if t == given || (t == "number" && given == "integer") {
+			return true
+		}
+		if t == "integer" && given == "number" {
+			if value, err := strconv.ParseFloat(node.Value, 64); err == nil && value == math.Trunc(value) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// nodeType returns the JSON schema type of a YAML node
+func nodeType(node *yaml.Node) string {
+	switch node.Kind {
+	case yaml.MappingNode:
+		return "object"
+	case yaml.SequenceNode:
+		return "array"
+	}
+	switch node.Tag {
+	case "!!int":
+		return "integer"
+	case "!!float":
+		return "number"
+	case "!!bool":
+		return "boolean"
+	case "!!null":
+		return "null"
+	}
+	return "string"
+}
+
+// quotedMatches reports whether a quoted string would have the expected type unquoted
+func quotedMatches(value string, types []string) bool {
+	var unquoted yaml.Node
+	if err := yaml.Unmarshal([]byte(value), &unquoted); err != nil || len(unquoted.Content) == 0 {
+		return false
+	}
+	scalar := unquoted.Content[0]
+	return scalar.Kind == yaml.ScalarNode && scalar.Tag != "!!str" && matchesAnyType(scalar, types)
+}
+
+func joinField(parent, key string) string {
+	if parent == "" {
+		return key
+	}
+	return parent + "." + key
+}
+
+func number(v interface{}) (float64, bool) {
+	f, ok := v.(float64)
+	return f, ok
+}
+
+func quoteAll(values []string) []string {
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i] = `"` + v + `"`
+	}
+	return quoted
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// closestMatch returns the candidate nearest to s by edit distance, or "" when
+// none is close enough to be a plausible typo
+func closestMatch(s string, candidates []string) string {
+	best, bestDistance := "", -1
+	for _, candidate := range candidates {
+		distance := levenshtein(strings.ToLower(s), strings.ToLower(candidate))
+		if bestDistance == -1 || distance < bestDistance {
+			best, bestDistance = candidate, distance
+		}
+	}
+	threshold := len(s) / 3
+	if threshold < 1 {
+		threshold = 1
+	}
+	if threshold > 3 {
+		threshold = 3
+	}
+	if bestDistance < 0 || bestDistance > threshold {
+		return ""
+	}
+	return best
+}
+
+// levenshtein returns the edit distance between a and b, counting an adjacent
+// transposition ("imgae" -> "image") as a single edit
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	d := make([][]int, len(ra)+1)
+	for i := range d {
+		d[i] = make([]int, len(rb)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			d[i][j] = min(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+	return d[len(ra)][len(rb)]
+}
@@ -0,0 +1,204 @@
+//go:build L0
+
+package validator
+
+import (
+	"testing"
+)
+
+const testSchema = `{
+  "$schema": "http://json-schema.org/draft-07/schema#",
+  "type": "object",
+  "required": ["extensions"],
+  "additionalProperties": false,
+  "properties": {
+    "version": {"type": "string"},
+    "extensions": {
+      "type": "array",
+      "minItems": 1,
+      "items": {"$ref": "#/definitions/extension"}
+    }
+  },
+  "definitions": {
+    "extension": {
+      "type": "object",
+      "required": ["name", "image"],
+      "additionalProperties": false,
+      "properties": {
+        "name": {"type": "string", "pattern": "^[a-z0-9][a-z0-9-]*$"},
+        "image": {"type": "string"},
+        "image_pull_policy": {"type": "string", "enum": ["Always", "IfNotPresent", "Never"]},
+        "timeout": {"type": "integer", "minimum": 1}
+      }
+    }
+  }
+}`
+
+func validateTestYAML(t *testing.T, content string) *ValidationResult {
+	t.Helper()
+	v, err := NewSchemaValidator(testSchema)
+	if err != nil {
+		t.Fatalf("Failed to create validator: %v", err)
+	}
+	result, err := v.ValidateYAML([]byte(content), "r2r-cli.yml")
+	if err != nil {
+		t.Fatalf("Validation error: %v", err)
+	}
+	return result
+}
+
+func TestValidateYAMLValid(t *testing.T) {
+	result := validateTestYAML(t, `version: "1.0"
+extensions:
+  - name: pwsh
+    image: ghcr.io/ready-to-release/pwsh:latest
+    image_pull_policy: IfNotPresent
+    timeout: 60
+`)
+	if !result.IsValid() {
+		t.Errorf("Expected valid config, got: %v", result.Errors)
+	}
+}
+
+func TestValidateYAMLPositions(t *testing.T) {
+	testCases := []struct {
+		name       string
+		content    string
+		field      string
+		rule       string
+		line       int
+		column     int
+		suggestion string
+	}{
+		{
+			name: "enum wrong case",
+			content: `extensions:
+  - name: pwsh
+    image: pwsh:latest
+    image_pull_policy: ifnotpresent
+`,
+			field:      "extensions[0].image_pull_policy",
+			rule:       RuleEnum,
+			line:       4,
+			column:     24,
+			suggestion: "did you mean 'IfNotPresent'? (values are case-sensitive)",
+		},
+		{
+			name: "misspelled key",
+			content: `extensions:
+  - name: pwsh
+    imgae: pwsh:latest
+    image: pwsh:latest
+`,
+			field:      "extensions[0].imgae",
+			rule:       RuleUnknownField,
+			line:       3,
+			column:     5,
+			suggestion: "did you mean 'image'?",
+		},
+		{
+			name: "missing required field",
+			content: `extensions:
+  - name: pwsh
+`,
+			field:  "extensions[0]",
+			rule:   RuleRequired,
+			line:   2,
+			column: 5,
+		},
+		{
+			name: "pattern mismatch",
+			content: `extensions:
+  - name: Invalid_Name
+    image: pwsh:latest
+`,
+			field:  "extensions[0].name",
+			rule:   RulePattern,
+			line:   2,
+			column: 11,
+		},
+		{
+			name: "quoted integer",
+			content: `extensions:
+  - name: pwsh
+    image: pwsh:latest
+    timeout: "60"
+`,
+			field:      "extensions[0].timeout",
+			rule:       RuleType,
+			line:       4,
+			column:     14,
+			suggestion: "remove the quotes",
+		},
+		{
+			name: "below minimum",
+			content: `extensions:
+  - name: pwsh
+    image: pwsh:latest
+    timeout: 0
+`,
+			field:  "extensions[0].timeout",
+			rule:   RuleMinimum,
+			line:   4,
+			column: 14,
+		},
+		{
+			name:    "empty array",
+			content: "extensions: []\n",
+			field:   "extensions",
+			rule:    RuleMinItems,
+			line:    1,
+			column:  13,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := validateTestYAML(t, tc.content)
+			if len(result.Errors) != 1 {
+				t.Fatalf("Expected 1 error, got %d: %v", len(result.Errors), result.Errors)
+			}
+			e := result.Errors[0]
+			if e.Field != tc.field || e.Rule != tc.rule {
+				t.Errorf("Expected %s on %s, got %s on %s", tc.rule, tc.field, e.Rule, e.Field)
+			}
+			if e.Line != tc.line || e.Column != tc.column {
+				t.Errorf("Expected position %d:%d, got %d:%d", tc.line, tc.column, e.Line, e.Column)
+			}
+			if e.Suggestion != tc.suggestion {
+				t.Errorf("Expected suggestion %q, got %q", tc.suggestion, e.Suggestion)
+			}
+			if e.File != "r2r-cli.yml" {
+				t.Errorf("Expected file r2r-cli.yml, got %q", e.File)
+			}
+		})
+	}
+}
+
+func TestValidateYAMLMergeKeys(t *testing.T) {
+	result := validateTestYAML(t, `base: &base
+  image: pwsh:latest
+extensions:
+  - <<: *base
+    name: pwsh
+`)
+	if len(result.Errors) != 1 || result.Errors[0].Field != "base" {
+		t.Errorf("Expected only the unknown top-level key to fail, got: %v", result.Errors)
+	}
+}
+
+func TestClosestMatch(t *testing.T) {
+	candidates := []string{"image", "image_pull_policy", "name", "timeout"}
+	testCases := map[string]string{
+		"imgae":   "image",
+		"nmae":    "name",
+		"timeot":  "timeout",
+		"volumes": "",
+		"x":       "",
+	}
+	for input, expected := range testCases {
+		if got := closestMatch(input, candidates); got != expected {
+			t.Errorf("closestMatch(%q) = %q, expected %q", input, got, expected)
+		}
+	}
+}
@@ -0,0 +1,22 @@
+// Command: test-fixture write-file
+// Description: Write a file while declaring no side effects, as a mislabelled command would
+// Usage: go run . test-fixture write-file <path>
+// HasSideEffects: false
+package main
+
+import (
+	"os"
+
+	"github.com/ready-to-release/eac/src/commands/internal/registry"
+)
+
+func init() {
+	registry.Register(testFixtureWriteFile)
+}
+
+func testFixtureWriteFile() int {
+	if err := os.WriteFile(os.Args[3], []byte("written\n"), 0644); err != nil {
+		return 1
+	}
+	return 0
+}
@@ -8,10 +8,13 @@ require (
 	github.com/docker/go-connections v0.6.0
 	github.com/jedib0t/go-pretty/v6 v6.6.9
 	github.com/pelletier/go-toml/v2 v2.2.4
+	github.com/pmezard/go-difflib v1.0.0
+	github.com/ready-to-release/eac/contracts v0.0.0
 	github.com/ready-to-release/eac/src/core v0.0.0
 	github.com/ready-to-release/eac/src/core/ai v0.0.0-00010101000000-000000000000
 	github.com/spf13/cobra v1.10.1
 	github.com/stretchr/testify v1.11.1
+	github.com/xeipuuv/gojsonschema v1.2.0
 	gopkg.in/yaml.v3 v3.0.1
 )
 
@@ -54,7 +57,6 @@ require (
 	github.com/opencontainers/go-digest v1.0.0 // indirect
 	github.com/opencontainers/image-spec v1.1.1 // indirect
 	github.com/pkg/errors v0.9.1 // indirect
-	github.com/pmezard/go-difflib v1.0.0 // indirect
 	github.com/rivo/uniseg v0.4.7 // indirect
 	github.com/sashabaranov/go-openai v1.41.2 // indirect
 	github.com/spf13/pflag v1.0.10 // indirect
@@ -62,6 +64,8 @@ require (
 	github.com/tidwall/match v1.1.1 // indirect
 	github.com/tidwall/pretty v1.2.1 // indirect
 	github.com/tidwall/sjson v1.2.5 // indirect
+	github.com/xeipuuv/gojsonpointer v0.0.0-20180127040702-4e3ac2762d5f // indirect
+	github.com/xeipuuv/gojsonreference v0.0.0-20180127040603-bd5ef7bd5415 // indirect
 	go.opencensus.io v0.24.0 // indirect
 	go.opentelemetry.io/auto/sdk v1.1.0 // indirect
 	go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc v0.51.0 // indirect
@@ -86,6 +90,8 @@ require (
 	gotest.tools/v3 v3.5.2 // indirect
 )
 
+replace github.com/ready-to-release/eac/contracts => ../../contracts
+
 replace github.com/ready-to-release/eac/src/core => ../core
 
 replace github.com/ready-to-release/eac/src/core/ai => ../core/ai
@@ -109,7 +109,7 @@ func BuildModules() int {
 		multiWriter := io.MultiWriter(os.Stdout, logFile)
 
 		// Run build for this module
-		exitCode := runModuleBuild(module, workspaceRoot, moduleOutputDir, multiWriter)
+		exitCode := RunModuleBuild(module, workspaceRoot, moduleOutputDir, multiWriter)
 
 		logFile.Close()
 
@@ -145,8 +145,14 @@ func BuildModules() int {
 	return 0
 }
 
-// runModuleBuild runs build for a single module
-func runModuleBuild(module *modules.ModuleContract, workspaceRoot string, outputDir string, logWriter io.Writer) int {
+// HasBuilder reports whether modules of moduleType have a build function
+func HasBuilder(moduleType string) bool {
+	_, ok := buildFunctions[moduleType]
+	return ok
+}
+
+// RunModuleBuild runs build for a single module
+func RunModuleBuild(module *modules.ModuleContract, workspaceRoot string, outputDir string, logWriter io.Writer) int {
 	// Get build function for module type
 	buildFunc, hasBuilder := buildFunctions[module.Type]
 	if !hasBuilder {
@@ -0,0 +1,182 @@
+// Command: changelog
+// Description: Generate per-module changelog fragments and a release plan from commit history
+// Usage: go run . changelog [--format markdown|json] [--module <moniker>] [--all]
+// Flags:
+//   --format <markdown|json>: Output CHANGELOG.md fragments (default) or the JSON release plan
+//   --module <moniker>: Only include this module
+//   --all: Include modules whose changes do not require a release (docs, chore, ...)
+// HasSideEffects: false
+package changelog
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ready-to-release/eac/src/commands/impl/changelog/internal"
+	"github.com/ready-to-release/eac/src/commands/internal/registry"
+	"github.com/ready-to-release/eac/src/core/contracts/modules"
+	"github.com/ready-to-release/eac/src/core/repository"
+)
+
+func init() {
+	registry.Register(Changelog)
+}
+
+// Changelog groups the commits since each module's last release tag
+// (<moniker>/v<version>) by module and type, and plans the next versions
+func Changelog() int {
+	fs := flag.NewFlagSet("changelog", flag.ContinueOnError)
+	format := fs.String("format", "markdown", "Output format: markdown or json")
+	only := fs.String("module", "", "Only include this module")
+	all := fs.Bool("all", false, "Include modules that do not require a release")
+
+	// Skip binary path and "changelog"
+	args := []string{}
+	if len(os.Args) > 2 {
+		args = os.Args[2:]
+	}
+	if err := fs.Parse(args); err != nil {
+		return 1
+	}
+	if *format != "markdown" && *format != "json" {
+		fmt.Fprintf(os.Stderr, "Error: --format must be markdown or json\n")
+		return 1
+	}
+
+	workspaceRoot, err := repository.GetRepositoryRoot("")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to find repository root: %v\n", err)
+		return 1
+	}
+
+	moduleRegistry, err := modules.LoadFromWorkspace(workspaceRoot, "0.1.0")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading contracts: %v\n", err)
+		return 1
+	}
+
+	monikers := moduleRegistry.AllMonikers()
+	sort.Strings(monikers)
+	if *only != "" {
+		if !moduleRegistry.Has(*only) {
+			fmt.Fprintf(os.Stderr, "Error: unknown module: %s\n", *only)
+			return 1
+		}
+		monikers = []string{*only}
+	}
+
+	plan, err := planReleases(workspaceRoot, moduleRegistry, monikers, *all)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading git history: %v\n", err)
+		return 1
+	}
+
+	if *format == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(plan); err != nil {
+			fmt.Fprintf(os.Stderr, "Error encoding release plan: %v\n", err)
+			return 1
+		}
+		return 0
+	}
+
+	if len(plan.Releases) == 0 {
+		fmt.Println("No unreleased changes.")
+		return 0
+	}
+	for i, release := range plan.Releases {
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Print(release.Changelog)
+	}
+
+	return 0
+}
+
+// planReleases plans the releases of monikers from the commits since each
+// module's last release tag. Modules without changes are left out, and so are
+// modules whose changes require no release unless all is set.
+func planReleases(workspaceRoot string, moduleRegistry *modules.Registry, monikers []string, all bool) (changelog.ReleasePlan, error) {
+	date := time.Now().Format("2006-01-02")
+	history := make(map[string][]changelog.Commit) // revision range -> commits
+
+	plan := changelog.ReleasePlan{Releases: []changelog.Release{}}
+	for _, moniker := range monikers {
+		previousTag, current := latestRelease(workspaceRoot, moniker)
+
+		revisionRange := "HEAD"
+		if previousTag != "" {
+			revisionRange = previousTag + "..HEAD"
+		}
+		commits, ok := history[revisionRange]
+		if !ok {
+			var err error
+			commits, err = readCommits(workspaceRoot, revisionRange)
+			if err != nil {
+				return plan, err
+			}
+			history[revisionRange] = commits
+		}
+
+		entries := changelog.GroupByModule(commits)[moniker]
+		if len(entries) == 0 {
+			continue
+		}
+
+		release := changelog.NewRelease(moniker, current, previousTag, entries, date)
+		if !release.Releasable() && !all {
+			continue
+		}
+		if contract, ok := moduleRegistry.Get(moniker); ok {
+			release.ChangelogPath = contract.Source.ChangelogPath
+		}
+		plan.Releases = append(plan.Releases, release)
+	}
+	return plan, nil
+}
+
+// latestRelease returns the highest <moniker>/v<version> tag and its version
+func latestRelease(workspaceRoot, moniker string) (string, changelog.Version) {
+	cmd := exec.Command("git", "tag", "--list", moniker+"/v*", "--sort=-v:refname")
+	cmd.Dir = workspaceRoot
+	output, err := cmd.Output()
+	if err != nil {
+		return "", changelog.Version{}
+	}
+
+	for _, tag := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		if version, err := changelog.ParseVersion(strings.TrimPrefix(tag, moniker+"/")); err == nil {
+			return tag, version
+		}
+	}
+	return "", changelog.Version{}
+}
+
+// readCommits returns the commits in revisionRange, newest first
+func readCommits(workspaceRoot, revisionRange string) ([]changelog.Commit, error) {
+	cmd := exec.Command("git", "log", "--format=%H%x1f%B%x1e", revisionRange)
+	cmd.Dir = workspaceRoot
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+
+	var commits []changelog.Commit
+	for _, record := range strings.Split(string(output), "\x1e") {
+		record = strings.TrimLeft(record, "\n")
+		hash, message, ok := strings.Cut(record, "\x1f")
+		if !ok {
+			continue
+		}
+		commits = append(commits, changelog.Commit{Hash: hash, Message: strings.TrimSpace(message)})
+	}
+	return commits, nil
+}
@@ -0,0 +1,359 @@
+// Package changelog builds per-module changelogs and release plans from the
+// commit message structure enforced by commit-ai:
+//
+//	# <module|multi-module>: <type>: <summary>
+//
+//	<body>
+//
+//	## <module>
+//
+//	<module>: <type>: <summary>
+package changelog
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Bump is a semantic version increment
+type Bump int
+
+const (
+	BumpNone Bump = iota
+	BumpPatch
+	BumpMinor
+	BumpMajor
+)
+
+func (b Bump) String() string {
+	switch b {
+	case BumpMajor:
+		return "major"
+	case BumpMinor:
+		return "minor"
+	case BumpPatch:
+		return "patch"
+	}
+	return "none"
+}
+
+// MarshalText renders the bump as its name in JSON
+func (b Bump) MarshalText() ([]byte, error) {
+	return []byte(b.String()), nil
+}
+
+// MultiModuleScope is the title scope of commits with one section per module
+const MultiModuleScope = "multi-module"
+
+var (
+	titlePattern   = regexp.MustCompile(`^(?:# )?([a-z0-9\-]+):\s*(feat|fix|refactor|docs|chore|test|perf|style):\s*(.+)$`)
+	subjectPattern = regexp.MustCompile(`^([a-z0-9\-]+):\s*(feat|fix|refactor|docs|chore|test|perf|style):\s*(.+)$`)
+)
+
+// typeOrder lists the changelog section headings in output order
+var typeOrder = []struct {
+	Type    string
+	Heading string
+}{
+	{"feat", "Features"},
+	{"fix", "Bug Fixes"},
+	{"perf", "Performance"},
+	{"refactor", "Refactoring"},
+	{"docs", "Documentation"},
+	{"test", "Tests"},
+	{"style", "Style"},
+	{"chore", "Chores"},
+}
+
+// Commit is a commit from git history
+type Commit struct {
+	Hash    string
+	Message string
+}
+
+// Entry is a single changelog line for a module
+type Entry struct {
+	Module   string `json:"module"`
+	Type     string `json:"type"`
+	Summary  string `json:"summary"`
+	Breaking bool   `json:"breaking,omitempty"`
+	Hash     string `json:"hash"`
+}
+
+// Bump returns the version increment this entry requires:
+// breaking changes are major, feat is minor, fix and perf are patch
+func (e Entry) Bump() Bump {
+	switch {
+	case e.Breaking:
+		return BumpMajor
+	case e.Type == "feat":
+		return BumpMinor
+	case e.Type == "fix" || e.Type == "perf":
+		return BumpPatch
+	}
+	return BumpNone
+}
+
+// ParseCommit extracts the changelog entries of a commit. Single-module commits
+// yield one entry from the title; multi-module commits yield one entry per
+// module section. Messages that do not follow the structure yield nothing.
+func ParseCommit(commit Commit) []Entry {
+	lines := strings.Split(strings.ReplaceAll(commit.Message, "\r\n", "\n"), "\n")
+	if len(lines) == 0 {
+		return nil
+	}
+
+	title := titlePattern.FindStringSubmatch(strings.TrimSpace(lines[0]))
+	if title == nil {
+		return nil
+	}
+
+	var entries []Entry
+	if title[1] != MultiModuleScope {
+		entries = append(entries, Entry{
+			Module:   title[1],
+			Type:     title[2],
+			Summary:  strings.TrimSpace(title[3]),
+			Breaking: hasBreakingChange(lines[1:]),
+			Hash:     commit.Hash,
+		})
+	}
+
+	// Module sections: "## <module>" followed by a subject line, up to the next
+	// section or "---" separator
+	for i := 1; i < len(lines); i++ {
+		header := strings.TrimSpace(lines[i])
+		if !strings.HasPrefix(header, "## ") {
+			continue
+		}
+		module := strings.TrimSpace(strings.TrimPrefix(header, "## "))
+
+		end := i + 1
+		for end < len(lines) {
+			trimmed := strings.TrimSpace(lines[end])
+			if strings.HasPrefix(trimmed, "## ") || trimmed == "---" {
+				break
+			}
+			end++
+		}
+
+		for _, line := range lines[i+1 : end] {
+			trimmed := strings.TrimSpace(line)
+			if trimmed == "" {
+				continue
+			}
+			if subject := subjectPattern.FindStringSubmatch(trimmed); subject != nil && subject[1] == module {
+				entries = append(entries, Entry{
+					Module:   module,
+					Type:     subject[2],
+					Summary:  strings.TrimSpace(subject[3]),
+					Breaking: hasBreakingChange(lines[i+1 : end]),
+					Hash:     commit.Hash,
+				})
+			}
+			break
+		}
+		i = end - 1
+	}
+
+	return dedupe(entries)
+}
+
+// hasBreakingChange reports whether a "BREAKING CHANGE:" footer is present
+func hasBreakingChange(lines []string) bool {
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "BREAKING CHANGE:") || strings.HasPrefix(trimmed, "BREAKING-CHANGE:") {
+			return true
+		}
+	}
+	return false
+}
+
+// dedupe keeps one entry per module; a single-module title and its own module
+// section describe the same change, the section wins
+func dedupe(entries []Entry) []Entry {
+	index := make(map[string]int)
+	var result []Entry
+	for _, entry := range entries {
+		if i, ok := index[entry.Module]; ok {
+			result[i] = entry
+			continue
+		}
+		index[entry.Module] = len(result)
+		result = append(result, entry)
+	}
+	return result
+}
+
+// Version is a MAJOR.MINOR.PATCH version
+type Version struct {
+	Major, Minor, Patch int
+}
+
+// ParseVersion parses "1.2.3" or "v1.2.3"
+func ParseVersion(text string) (Version, error) {
+	parts := strings.Split(strings.TrimPrefix(text, "v"), ".")
+	if len(parts) != 3 {
+		return Version{}, fmt.Errorf("invalid version: %s", text)
+	}
+
+	var numbers [3]int
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return Version{}, fmt.Errorf("invalid version: %s", text)
+		}
+		numbers[i] = n
+	}
+
+	return Version{Major: numbers[0], Minor: numbers[1], Patch: numbers[2]}, nil
+}
+
+func (v Version) String() string {
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+// Next returns the version after applying bump
+func (v Version) Next(bump Bump) Version {
+	switch bump {
+	case BumpMajor:
+		return Version{Major: v.Major + 1}
+	case BumpMinor:
+		return Version{Major: v.Major, Minor: v.Minor + 1}
+	case BumpPatch:
+		return Version{Major: v.Major, Minor: v.Minor, Patch: v.Patch + 1}
+	}
+	return v
+}
+
+// TagName returns the release tag of a module version, e.g. "cli/v1.2.0"
+func TagName(module string, version Version) string {
+	return module + "/v" + version.String()
+}
+
+// Release is the planned release of a single module
+type Release struct {
+	Module         string  `json:"module"`
+	CurrentVersion string  `json:"current_version"`
+	NextVersion    string  `json:"next_version"`
+	Bump           Bump    `json:"bump"`
+	Tag            string  `json:"tag,omitempty"`
+	PreviousTag    string  `json:"previous_tag,omitempty"`
+	Date           string  `json:"date"`
+	ChangelogPath  string  `json:"changelog_path,omitempty"`
+	Entries        []Entry `json:"entries"`
+	Changelog      string  `json:"changelog"` // CHANGELOG.md fragment
+}
+
+// NewRelease plans the release of a module from its entries since previousTag.
+// current is the version of previousTag (0.0.0 when the module was never released).
+func NewRelease(module string, current Version, previousTag string, entries []Entry, date string) Release {
+	bump := BumpNone
+	for _, entry := range entries {
+		if b := entry.Bump(); b > bump {
+			bump = b
+		}
+	}
+
+	next := current.Next(bump)
+	release := Release{
+		Module:         module,
+		CurrentVersion: current.String(),
+		NextVersion:    next.String(),
+		Bump:           bump,
+		PreviousTag:    previousTag,
+		Date:           date,
+		Entries:        entries,
+	}
+	if bump != BumpNone {
+		release.Tag = TagName(module, next)
+	}
+	if release.Entries == nil {
+		release.Entries = []Entry{}
+	}
+	release.Changelog = release.Markdown()
+	return release
+}
+
+// Releasable reports whether the entries require a new version
+func (r Release) Releasable() bool {
+	return r.Bump != BumpNone
+}
+
+// Markdown renders the release as a CHANGELOG.md fragment
+func (r Release) Markdown() string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("## %s v%s", r.Module, r.NextVersion))
+	if r.Date != "" {
+		sb.WriteString(fmt.Sprintf(" (%s)", r.Date))
+	}
+	sb.WriteString("\n")
+
+	var breaking []Entry
+	for _, entry := range r.Entries {
+		if entry.Breaking {
+			breaking = append(breaking, entry)
+		}
+	}
+	if len(breaking) > 0 {
+		sb.WriteString("\n### Breaking Changes\n\n")
+		for _, entry := range breaking {
+			sb.WriteString(entryLine(entry))
+		}
+	}
+
+	for _, section := range typeOrder {
+		var lines []string
+		for _, entry := range r.Entries {
+			if entry.Type == section.Type {
+				lines = append(lines, entryLine(entry))
+			}
+		}
+		if len(lines) == 0 {
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("\n### %s\n\n", section.Heading))
+		for _, line := range lines {
+			sb.WriteString(line)
+		}
+	}
+
+	return sb.String()
+}
+
+func entryLine(entry Entry) string {
+	hash := entry.Hash
+	if len(hash) > 7 {
+		hash = hash[:7]
+	}
+	return fmt.Sprintf("- %s (%s)\n", entry.Summary, hash)
+}
+
+// GroupByModule collects the entries of commits (newest first) per module,
+// keeping history order within each module
+func GroupByModule(commits []Commit) map[string][]Entry {
+	grouped := make(map[string][]Entry)
+	for _, commit := range commits {
+		for _, entry := range ParseCommit(commit) {
+			grouped[entry.Module] = append(grouped[entry.Module], entry)
+		}
+	}
+	return grouped
+}
+
+// ReleasePlan is the JSON release plan for all modules with changes
+type ReleasePlan struct {
+	Releases []Release `json:"releases"`
+}
+
+// SortReleases orders releases by module name
+func SortReleases(releases []Release) {
+	sort.Slice(releases, func(i, j int) bool {
+		return releases[i].Module < releases[j].Module
+	})
+}
@@ -0,0 +1,103 @@
+package changelog
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseCommit_SingleModule(t *testing.T) {
+	entries := ParseCommit(Commit{
+		Hash:    "abcdef1234567",
+		Message: "# cli: feat: add changelog command\n\nBody text",
+	})
+
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 entry, got %d", len(entries))
+	}
+	entry := entries[0]
+	if entry.Module != "cli" || entry.Type != "feat" || entry.Summary != "add changelog command" {
+		t.Errorf("unexpected entry: %+v", entry)
+	}
+	if entry.Bump() != BumpMinor {
+		t.Errorf("expected minor bump, got %s", entry.Bump())
+	}
+}
+
+func TestParseCommit_MultiModule(t *testing.T) {
+	message := strings.Join([]string{
+		"# multi-module: feat: add release planning",
+		"",
+		"Body text",
+		"",
+		"## cli",
+		"",
+		"cli: feat: add changelog command",
+		"",
+		"Details",
+		"",
+		"---",
+		"",
+		"## core",
+		"",
+		"core: fix: handle missing tags",
+		"",
+		"BREAKING CHANGE: tags are now required",
+	}, "\n")
+
+	entries := ParseCommit(Commit{Hash: "abc", Message: message})
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d: %+v", len(entries), entries)
+	}
+	if entries[0].Module != "cli" || entries[0].Breaking {
+		t.Errorf("unexpected cli entry: %+v", entries[0])
+	}
+	if entries[1].Module != "core" || !entries[1].Breaking || entries[1].Bump() != BumpMajor {
+		t.Errorf("unexpected core entry: %+v", entries[1])
+	}
+}
+
+func TestParseCommit_Unstructured(t *testing.T) {
+	if entries := ParseCommit(Commit{Hash: "abc", Message: "Fix typo"}); len(entries) != 0 {
+		t.Errorf("expected no entries, got %+v", entries)
+	}
+}
+
+func TestNewRelease(t *testing.T) {
+	entries := []Entry{
+		{Module: "cli", Type: "fix", Summary: "fix flag", Hash: "1111111aaa"},
+		{Module: "cli", Type: "feat", Summary: "add flag", Hash: "2222222bbb"},
+		{Module: "cli", Type: "docs", Summary: "document flag", Hash: "3333333ccc"},
+	}
+
+	release := NewRelease("cli", Version{Major: 1, Minor: 2, Patch: 3}, "cli/v1.2.3", entries, "2025-01-01")
+
+	if release.NextVersion != "1.3.0" || release.Tag != "cli/v1.3.0" || release.Bump != BumpMinor {
+		t.Errorf("unexpected release: %+v", release)
+	}
+
+	for _, want := range []string{"## cli v1.3.0 (2025-01-01)", "### Features", "- add flag (2222222)", "### Bug Fixes", "### Documentation"} {
+		if !strings.Contains(release.Changelog, want) {
+			t.Errorf("changelog missing %q:\n%s", want, release.Changelog)
+		}
+	}
+	if strings.Index(release.Changelog, "### Features") > strings.Index(release.Changelog, "### Bug Fixes") {
+		t.Errorf("features should come before bug fixes:\n%s", release.Changelog)
+	}
+}
+
+func TestNewRelease_NotReleasable(t *testing.T) {
+	release := NewRelease("cli", Version{}, "", []Entry{{Module: "cli", Type: "chore", Summary: "tidy"}}, "")
+	if release.Releasable() || release.Tag != "" || release.NextVersion != "0.0.0" {
+		t.Errorf("unexpected release: %+v", release)
+	}
+}
+
+func TestVersionNext(t *testing.T) {
+	v := Version{Major: 1, Minor: 2, Patch: 3}
+	tests := map[Bump]string{BumpNone: "1.2.3", BumpPatch: "1.2.4", BumpMinor: "1.3.0", BumpMajor: "2.0.0"}
+	for bump, want := range tests {
+		if got := v.Next(bump).String(); got != want {
+			t.Errorf("Next(%s) = %s, want %s", bump, got, want)
+		}
+	}
+}
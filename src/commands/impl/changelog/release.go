@@ -0,0 +1,217 @@
+// Command: release plan
+// Description: Plan the releases of the deployable units changed since their last release tag, in dependency order
+// Usage: go run . release plan [--format text|json] [--module <moniker>]
+// Flags:
+//   --format <text|json>: Output format (default: text)
+//   --module <moniker>: Only plan this unit
+// HasSideEffects: false
+package changelog
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/ready-to-release/eac/src/commands/impl/build"
+	"github.com/ready-to-release/eac/src/commands/impl/changelog/internal"
+	"github.com/ready-to-release/eac/src/commands/internal/registry"
+	"github.com/ready-to-release/eac/src/core/contracts/modules"
+	"github.com/ready-to-release/eac/src/core/repository"
+)
+
+func init() {
+	registry.Register(ReleasePlan)
+}
+
+// ReleaseStep is the release of one deployable unit: its planned version
+// and what release execute does for it
+type ReleaseStep struct {
+	changelog.Release
+	Type      string   `json:"type"`
+	DependsOn []string `json:"depends_on,omitempty"` // units of the plan released before this one
+	Build     bool     `json:"build"`                // the unit type has a build function (build module)
+}
+
+// ReleaseOrder is the machine-readable output of release plan
+type ReleaseOrder struct {
+	Steps []ReleaseStep `json:"steps"`
+}
+
+// ReleasePlan prints the units that need a release with their next versions
+// and tags, in the order release execute builds and tags them
+func ReleasePlan() int {
+	fs := flag.NewFlagSet("release plan", flag.ContinueOnError)
+	format := fs.String("format", "text", "Output format: text or json")
+	only := fs.String("module", "", "Only plan this unit")
+
+	// Skip binary path, "release" and "plan"
+	args := []string{}
+	if len(os.Args) > 3 {
+		args = os.Args[3:]
+	}
+	if err := fs.Parse(args); err != nil {
+		return 1
+	}
+	if *format != "text" && *format != "json" {
+		fmt.Fprintf(os.Stderr, "Error: --format must be text or json\n")
+		return 1
+	}
+
+	_, steps, err := loadReleaseSteps(*only)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	if *format == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(ReleaseOrder{Steps: steps}); err != nil {
+			fmt.Fprintf(os.Stderr, "Error encoding release plan: %v\n", err)
+			return 1
+		}
+		return 0
+	}
+
+	if len(steps) == 0 {
+		fmt.Println("No unreleased changes.")
+		return 0
+	}
+	fmt.Printf("📦 %d unit(s) to release, in dependency order:\n", len(steps))
+	for i, step := range steps {
+		fmt.Printf("  %d. %s\n", i+1, describeStep(step))
+	}
+	return 0
+}
+
+// describeStep summarizes a release step on one line
+func describeStep(step ReleaseStep) string {
+	line := fmt.Sprintf("%s %s → %s (%s), tag %s", step.Module, step.CurrentVersion, step.NextVersion, step.Bump, step.Tag)
+	if step.Build {
+		line += ", build " + step.Type
+	} else {
+		line += ", no build"
+	}
+	if len(step.DependsOn) > 0 {
+		line += ", after " + strings.Join(step.DependsOn, ", ")
+	}
+	return line
+}
+
+// loadReleaseSteps plans the releases of all units, or of only, and orders
+// them by their dependencies
+func loadReleaseSteps(only string) (string, []ReleaseStep, error) {
+	workspaceRoot, err := repository.GetRepositoryRoot("")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to find repository root: %w", err)
+	}
+
+	moduleRegistry, err := modules.LoadFromWorkspace(workspaceRoot, "0.1.0")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to load contracts: %w", err)
+	}
+
+	monikers := moduleRegistry.AllMonikers()
+	if only != "" {
+		if !moduleRegistry.Has(only) {
+			return "", nil, fmt.Errorf("unknown module: %s", only)
+		}
+		monikers = []string{only}
+	}
+
+	plan, err := planReleases(workspaceRoot, moduleRegistry, monikers, false)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to read git history: %w", err)
+	}
+
+	steps, err := orderReleases(plan.Releases, moduleRegistry)
+	return workspaceRoot, steps, err
+}
+
+// orderReleases puts releases in dependency order: a unit comes after the
+// units of the plan it depends on, directly or through units without a
+// release. Units that do not depend on each other are ordered by name.
+func orderReleases(releases []changelog.Release, moduleRegistry *modules.Registry) ([]ReleaseStep, error) {
+	graph := moduleRegistry.GetDependencyGraph()
+
+	planned := make(map[string]bool, len(releases))
+	for _, release := range releases {
+		planned[release.Module] = true
+	}
+
+	steps := make(map[string]*ReleaseStep, len(releases))
+	waiting := make(map[string]int, len(releases)) // unreleased dependencies
+	dependents := make(map[string][]string)
+	for _, release := range releases {
+		step := &ReleaseStep{Release: release}
+		if module, ok := moduleRegistry.Get(release.Module); ok {
+			step.Type = module.Type
+			step.Build = build.HasBuilder(module.Type)
+		}
+		step.DependsOn = plannedDependencies(release.Module, graph, planned)
+		for _, dependency := range step.DependsOn {
+			dependents[dependency] = append(dependents[dependency], release.Module)
+		}
+		waiting[release.Module] = len(step.DependsOn)
+		steps[release.Module] = step
+	}
+
+	var ready []string
+	for moniker, count := range waiting {
+		if count == 0 {
+			ready = append(ready, moniker)
+		}
+	}
+
+	ordered := make([]ReleaseStep, 0, len(releases))
+	for len(ready) > 0 {
+		sort.Strings(ready)
+		moniker := ready[0]
+		ready = ready[1:]
+		ordered = append(ordered, *steps[moniker])
+		for _, dependent := range dependents[moniker] {
+			waiting[dependent]--
+			if waiting[dependent] == 0 {
+				ready = append(ready, dependent)
+			}
+		}
+	}
+
+	if len(ordered) < len(releases) {
+		var cycle []string
+		for moniker, count := range waiting {
+			if count > 0 {
+				cycle = append(cycle, moniker)
+			}
+		}
+		sort.Strings(cycle)
+		return nil, fmt.Errorf("dependency cycle between %s", strings.Join(cycle, ", "))
+	}
+	return ordered, nil
+}
+
+// plannedDependencies returns the planned units moniker depends on, following
+// depends_on through units that are not planned, sorted
+func plannedDependencies(moniker string, graph map[string][]string, planned map[string]bool) []string {
+	seen := map[string]bool{moniker: true}
+	queue := append([]string{}, graph[moniker]...)
+	var found []string
+	for len(queue) > 0 {
+		dependency := queue[0]
+		queue = queue[1:]
+		if seen[dependency] {
+			continue
+		}
+		seen[dependency] = true
+		if planned[dependency] {
+			found = append(found, dependency)
+			continue
+		}
+		queue = append(queue, graph[dependency]...)
+	}
+	sort.Strings(found)
+	return found
+}
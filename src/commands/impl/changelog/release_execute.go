@@ -0,0 +1,148 @@
+// Command: release execute
+// Description: Build, tag and push the deployable units changed since their last release tag, in dependency order
+// Usage: go run . release execute [--module <moniker>] [--push] [--remote <name>] [--skip-build]
+// Flags:
+//   --module <moniker>: Only release this unit
+//   --push: Push each tag to the remote after it is created
+//   --remote <name>: Remote to push the tags to (default: origin)
+//   --skip-build: Tag the units without building them
+// HasSideEffects: true
+// DryRun: true
+package changelog
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ready-to-release/eac/src/commands/impl/build"
+	"github.com/ready-to-release/eac/src/commands/internal/registry"
+	"github.com/ready-to-release/eac/src/core/contracts/modules"
+	"github.com/ready-to-release/eac/src/core/repository"
+)
+
+func init() {
+	registry.Register(ReleaseExecute)
+}
+
+// ReleaseExecute builds each planned unit with its type's build function,
+// creates its annotated release tag holding the changelog fragment and
+// optionally pushes the tag, one unit at a time in dependency order. The
+// first failure stops the release, so no unit is tagged before the units it
+// depends on. With --dry-run it prints the steps instead.
+func ReleaseExecute() int {
+	fs := flag.NewFlagSet("release execute", flag.ContinueOnError)
+	only := fs.String("module", "", "Only release this unit")
+	push := fs.Bool("push", false, "Push each tag to the remote after it is created")
+	remote := fs.String("remote", "origin", "Remote to push the tags to")
+	skipBuild := fs.Bool("skip-build", false, "Tag the units without building them")
+
+	// Skip binary path, "release" and "execute"
+	args := []string{}
+	if len(os.Args) > 3 {
+		args = os.Args[3:]
+	}
+	if err := fs.Parse(args); err != nil {
+		return 1
+	}
+
+	workspaceRoot, steps, err := loadReleaseSteps(*only)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	if len(steps) == 0 {
+		fmt.Println("No unreleased changes.")
+		return 0
+	}
+
+	dryRun := registry.DryRun()
+	if !dryRun {
+		// Tags mark HEAD, so the builds must come from HEAD too
+		status, err := repository.GitOutput(workspaceRoot, "status", "--porcelain", "--untracked-files=no")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: git status failed: %v\n", err)
+			return 1
+		}
+		if status != "" {
+			fmt.Fprintf(os.Stderr, "Error: the working tree has uncommitted changes; commit or stash them before releasing\n")
+			return 1
+		}
+	}
+
+	var moduleRegistry *modules.Registry
+	if !*skipBuild && !dryRun {
+		if moduleRegistry, err = modules.LoadFromWorkspace(workspaceRoot, "0.1.0"); err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading contracts: %v\n", err)
+			return 1
+		}
+	}
+
+	runDir := filepath.Join(workspaceRoot, "out", "release-results", time.Now().Format("2006-01-02-150405"))
+	for i, step := range steps {
+		fmt.Printf("=== [%d/%d] %s ===\n", i+1, len(steps), describeStep(step))
+
+		if dryRun {
+			if step.Build && !*skipBuild {
+				fmt.Printf("🔍 Would build %s (%s) into %s\n", step.Module, step.Type, filepath.Join(runDir, step.Module))
+			}
+			fmt.Printf("🔍 Would create tag %s\n", step.Tag)
+			if *push {
+				fmt.Printf("🔍 Would push %s to %s\n", step.Tag, *remote)
+			}
+			continue
+		}
+
+		if step.Build && !*skipBuild {
+			module, _ := moduleRegistry.Get(step.Module)
+			if exitCode := buildReleaseUnit(module, workspaceRoot, filepath.Join(runDir, step.Module)); exitCode != 0 {
+				fmt.Fprintf(os.Stderr, "❌ Build of %s failed; %s and the units after it were not released\n", step.Module, step.Tag)
+				return exitCode
+			}
+		}
+
+		if _, err := repository.GitOutput(workspaceRoot, "tag", "--annotate", step.Tag, "--message", strings.TrimSpace(step.Changelog)); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Failed to create tag %s: %v\n", step.Tag, err)
+			return 1
+		}
+		fmt.Printf("🏷️  Tagged %s\n", step.Tag)
+
+		if *push {
+			if _, err := repository.GitOutput(workspaceRoot, "push", *remote, "refs/tags/"+step.Tag); err != nil {
+				fmt.Fprintf(os.Stderr, "❌ Failed to push %s to %s: %v\n", step.Tag, *remote, err)
+				return 1
+			}
+			fmt.Printf("🚀 Pushed %s to %s\n", step.Tag, *remote)
+		}
+	}
+
+	if dryRun {
+		return 0
+	}
+	fmt.Printf("\n✅ Released %d unit(s)\n", len(steps))
+	if !*push {
+		fmt.Printf("💡 Push the tags with: git push %s --tags\n", *remote)
+	}
+	return 0
+}
+
+// buildReleaseUnit builds a unit into outputDir, logging to the console and
+// outputDir/build.log
+func buildReleaseUnit(module *modules.ModuleContract, workspaceRoot string, outputDir string) int {
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to create output directory: %v\n", err)
+		return 1
+	}
+	logFile, err := os.Create(filepath.Join(outputDir, "build.log"))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to create log file: %v\n", err)
+		return 1
+	}
+	defer logFile.Close()
+
+	return build.RunModuleBuild(module, workspaceRoot, outputDir, io.MultiWriter(os.Stdout, logFile))
+}
@@ -0,0 +1,62 @@
+package changelog
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ready-to-release/eac/src/commands/impl/changelog/internal"
+	"github.com/ready-to-release/eac/src/core/contracts"
+	"github.com/ready-to-release/eac/src/core/contracts/modules"
+)
+
+func releaseRegistry(t *testing.T, dependsOn map[string][]string) *modules.Registry {
+	moduleRegistry := modules.NewRegistry("0.1.0", "")
+	for moniker, dependencies := range dependsOn {
+		base := contracts.BaseContract{Moniker: moniker, Type: "go-library", DependsOn: dependencies}
+		if err := moduleRegistry.Add(modules.NewModuleContract(base, "")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return moduleRegistry
+}
+
+func TestOrderReleases(t *testing.T) {
+	// cli depends on core through config, which has no release
+	moduleRegistry := releaseRegistry(t, map[string][]string{
+		"cli":    {"config"},
+		"config": {"core"},
+		"core":   nil,
+		"docs":   nil,
+	})
+	releases := []changelog.Release{{Module: "cli"}, {Module: "core"}, {Module: "docs"}}
+
+	steps, err := orderReleases(releases, moduleRegistry)
+	if err != nil {
+		t.Fatalf("orderReleases() error = %v", err)
+	}
+	var order []string
+	for _, step := range steps {
+		order = append(order, step.Module)
+	}
+	if want := []string{"core", "cli", "docs"}; !reflect.DeepEqual(order, want) {
+		t.Errorf("order = %v, want %v", order, want)
+	}
+	if !reflect.DeepEqual(steps[1].DependsOn, []string{"core"}) {
+		t.Errorf("cli depends on %v, want [core]", steps[1].DependsOn)
+	}
+	if !steps[0].Build || steps[0].Type != "go-library" {
+		t.Errorf("Expected core to be built as go-library, got %+v", steps[0])
+	}
+}
+
+func TestOrderReleases_Cycle(t *testing.T) {
+	moduleRegistry := releaseRegistry(t, map[string][]string{
+		"a": {"b"},
+		"b": {"a"},
+	})
+	_, err := orderReleases([]changelog.Release{{Module: "a"}, {Module: "b"}}, moduleRegistry)
+	if err == nil || !strings.Contains(err.Error(), "a, b") {
+		t.Errorf("Expected a cycle error naming a and b, got %v", err)
+	}
+}
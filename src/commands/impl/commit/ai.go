@@ -1,7 +1,8 @@
 // Command: commit-ai
 // Description: Generate commit message using AI with staged changes and module mappings
-// Flags: --debug (save intermediate outputs and show debug info)
-// HasSideEffects: false
+// Flags: --include-unstaged (describe the whole working tree, staged, unstaged and untracked, and list the files that need staging), --debug (save intermediate outputs and show debug info), --token-budget <n> (max estimated tokens per agent context, default 60000), --concurrency <n> (parallel module section generation, default from .r2r/commit.yml or 4), --files <path>[,<path>...] (only these staged files), --hunks <path>:<n>[,<n>...] (only these hunks of a staged file), --commit (commit the staged changes with the message when it has no errors, signed per .r2r/commit.yml or commit.gpgSign; with --include-unstaged the listed files are staged first), --language <name> (language of the summary and body text, subject lines stay English; default from .r2r/commit.yml), --format <text|json> (default from .r2r/commit.yml; json also prints the contract violations and agent file findings with their file and line between >>>>>>DIAGNOSTICS START<<<<<< and >>>>>>DIAGNOSTICS END<<<<<<)
+// HasSideEffects: true
+// DryRun: true
 package commit
 
 import (
@@ -12,6 +13,8 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
 
 	commitmessage "github.com/ready-to-release/eac/src/commands/impl/commit/internal"
@@ -19,6 +22,9 @@ import (
 	"github.com/ready-to-release/eac/src/commands/internal/render"
 	"github.com/ready-to-release/eac/src/core/ai"
 	"github.com/ready-to-release/eac/src/core/ai/providers"
+	"github.com/ready-to-release/eac/src/core/collections"
+	"github.com/ready-to-release/eac/src/core/commitrules"
+	"github.com/ready-to-release/eac/src/core/contracts/modules"
 	"github.com/ready-to-release/eac/src/core/repository"
 	"github.com/ready-to-release/eac/src/core/repository/reports"
 )
@@ -28,13 +34,92 @@ func init() {
 }
 
 func CommitAI() int {
+	// Kill the warm claude processes left for calls that never came
+	defer providers.ShutdownClaudeCLIPool()
+
 	// Parse flags
 	debug := false
-	for _, arg := range os.Args[2:] { // Skip program name and "commit-ai"
-		if arg == "--debug" {
+	commit := false
+	includeUnstaged := false
+	tokenBudget := commitmessage.DefaultContextTokenBudget
+	concurrency := 0
+	language := ""
+	format := ""
+	var selectedFiles, selectedHunks []string
+	args := os.Args[2:] // Skip program name and "commit-ai"
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--debug":
 			debug = true
+		case arg == "--commit":
+			commit = true
+		case arg == "--include-unstaged":
+			includeUnstaged = true
+		case arg == "--token-budget" && i+1 < len(args):
+			i++
+			arg = "--token-budget=" + args[i]
+			fallthrough
+		case strings.HasPrefix(arg, "--token-budget="):
+			value, err := strconv.Atoi(strings.TrimPrefix(arg, "--token-budget="))
+			if err != nil || value <= 0 {
+				fmt.Fprintf(os.Stderr, "Error: --token-budget must be a positive number\n")
+				return 1
+			}
+			tokenBudget = value
+		case arg == "--concurrency" && i+1 < len(args):
+			i++
+			arg = "--concurrency=" + args[i]
+			fallthrough
+		case strings.HasPrefix(arg, "--concurrency="):
+			value, err := strconv.Atoi(strings.TrimPrefix(arg, "--concurrency="))
+			if err != nil || value <= 0 {
+				fmt.Fprintf(os.Stderr, "Error: --concurrency must be a positive number\n")
+				return 1
+			}
+			concurrency = value
+		case arg == "--files" && i+1 < len(args):
+			i++
+			arg = "--files=" + args[i]
+			fallthrough
+		case strings.HasPrefix(arg, "--files="):
+			selectedFiles = append(selectedFiles, strings.TrimPrefix(arg, "--files="))
+		case arg == "--language" && i+1 < len(args):
+			i++
+			arg = "--language=" + args[i]
+			fallthrough
+		case strings.HasPrefix(arg, "--language="):
+			language = strings.TrimSpace(strings.TrimPrefix(arg, "--language="))
+			if err := commitmessage.ValidateLanguage(language); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: --%v\n", err)
+				return 1
+			}
+		case arg == "--format" && i+1 < len(args):
+			i++
+			arg = "--format=" + args[i]
+			fallthrough
+		case strings.HasPrefix(arg, "--format="):
+			format = strings.TrimPrefix(arg, "--format=")
+			if format != "text" && format != "json" {
+				fmt.Fprintf(os.Stderr, "Error: --format must be text or json\n")
+				return 1
+			}
+		case arg == "--hunks" && i+1 < len(args):
+			i++
+			arg = "--hunks=" + args[i]
+			fallthrough
+		case strings.HasPrefix(arg, "--hunks="):
+			selectedHunks = append(selectedHunks, strings.TrimPrefix(arg, "--hunks="))
 		}
 	}
+	budget := commitmessage.NewContextBudget(tokenBudget)
+
+	// Partial commit: only the selected files and hunks are described
+	selection, err := commitmessage.ParseDiffSelection(selectedFiles, selectedHunks)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
 
 	// Get repository root
 	workspaceRoot, err := repository.GetRepositoryRoot("")
@@ -45,7 +130,7 @@ func CommitAI() int {
 
 	// LEVER 1: Verify contract implementation on startup
 	contractPath := filepath.Join(workspaceRoot, "contracts/commit-message/0.1.0/structure.yml")
-	contractErrors := commitmessage.VerifyContractImplementation(contractPath)
+	contractErrors := commitrules.VerifyContractImplementation(contractPath)
 	if len(contractErrors) > 0 {
 		fmt.Fprintf(os.Stderr, "❌ Contract implementation verification failed:\n")
 		for _, err := range contractErrors {
@@ -54,31 +139,140 @@ func CommitAI() int {
 		return 1
 	}
 
-	// LEVER 1: Get staged files with module mappings
-	report, err := reports.GetFilesModulesReport(true, false, true, workspaceRoot, "0.1.0")
+	commitConfig, err := commitmessage.LoadCommitConfig(workspaceRoot)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error getting module mappings: %v\n", err)
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		return 1
 	}
+	if language == "" {
+		language = commitConfig.Language
+	}
+	if format == "" {
+		format = commitConfig.Format
+	}
+	ruleConfig, err := commitrules.LoadRuleConfig(workspaceRoot)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	if commit && len(selection) > 0 {
+		fmt.Fprintf(os.Stderr, "Error: --commit commits all staged changes; stage the selected patch first\n")
+		return 1
+	}
+	if includeUnstaged && len(selection) > 0 {
+		fmt.Fprintf(os.Stderr, "Error: --include-unstaged describes the whole working tree; it cannot be combined with --files or --hunks\n")
+		return 1
+	}
+
+	// Merges, reverts and cherry-picks keep git's message format
+	inProgress, err := inProgressCommit(workspaceRoot)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	if inProgress != nil {
+		if len(selection) > 0 {
+			fmt.Fprintf(os.Stderr, "Error: cannot commit selected changes while a %s is in progress\n", inProgress.Kind)
+			return 1
+		}
+		if includeUnstaged {
+			fmt.Fprintf(os.Stderr, "Error: cannot include unstaged changes while a %s is in progress; resolve and stage them first\n", inProgress.Kind)
+			return 1
+		}
+		fmt.Printf("ℹ️  %s in progress: using git's message format instead of module sections\n", inProgress.Kind)
+		message := inProgress.Message(gitCommentChar())
+		fmt.Println(">>>>>>OUTPUT START<<<<<<")
+		fmt.Println(message)
+		fmt.Println("\n---")
+		fmt.Println()
+		if commit {
+			return commitStaged(workspaceRoot, message, commitConfig.Sign)
+		}
+		return 0
+	}
+
+	// LEVER 1: Get staged files with module mappings, or all changed files
+	// of the working tree with --include-unstaged
+	var report *reports.FilesModulesReport
+	var tree *workingTree
+	if includeUnstaged {
+		tree, err = collectWorkingTree(workspaceRoot)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading working tree: %v\n", err)
+			return 1
+		}
+		report = &reports.FilesModulesReport{TotalFiles: len(tree.Files), AllFiles: tree.Files}
+	} else {
+		report, err = reports.GetFilesModulesReport(true, false, true, workspaceRoot, "0.1.0")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error getting module mappings: %v\n", err)
+			return 1
+		}
+	}
 
+	changeKind := "staged"
+	if includeUnstaged {
+		changeKind = "changed"
+	}
 	if len(report.AllFiles) == 0 {
-		fmt.Println("No staged changes.")
+		if includeUnstaged {
+			fmt.Println("No changes.")
+		} else {
+			fmt.Println("No staged changes.")
+		}
 		return 0
 	}
 
-	// Build the staged files table (same format as "show files staged")
-	tb := render.NewTableBuilder().
-		WithHeaders("File", "Modules")
+	if len(selection) > 0 {
+		var selected []repository.RepositoryFileWithModule
+		for _, file := range report.AllFiles {
+			if selection.Includes(file.Name) {
+				selected = append(selected, file)
+			}
+		}
+		report.AllFiles = selected
+	}
 
-	for _, file := range report.AllFiles {
-		modulesStr := "NONE"
-		if len(file.Modules) > 0 {
-			modulesStr = strings.Join(file.Modules, ", ")
+	// Get git diff for staged changes (do not print anything yet)
+	var gitDiff string
+	if tree != nil {
+		gitDiff = tree.Diff
+	} else {
+		diffCmd := exec.Command("git", "diff", "--staged")
+		diffCmd.Dir = workspaceRoot
+		diffOutput, err := diffCmd.Output()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error getting git diff: %v\n", err)
+			return 1
+		}
+		gitDiff = string(diffOutput)
+	}
+
+	// The selected part of the diff doubles as the patch to stage for the partial commit
+	var patch string
+	if len(selection) > 0 {
+		patch, err = commitmessage.SelectDiff(gitDiff, selection)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
 		}
-		tb.AddRow(file.Name, modulesStr)
+		gitDiff = patch
+	}
+
+	// Binary, generated and oversize files are summarized instead of embedded
+	fileNames := make([]string, len(report.AllFiles))
+	for i, file := range report.AllFiles {
+		fileNames[i] = file.Name
 	}
+	fileClasses := commitmessage.ClassifyDiff(gitDiff, linguistGenerated(workspaceRoot, fileNames), budget.File)
 
-	stagedFilesTable := tb.Build()
+	// Oversize files get a generated summary, cached by blob hashes so retries
+	// and later commits of the same change reuse it
+	var summaries map[string]string
+	if commitConfig.Summaries.IsEnabled() {
+		summaries = summarizeOversizeFiles(workspaceRoot, gitDiff, fileClasses, commitConfig.Summaries.Model, budget.Diff)
+	}
+	gitDiff = commitmessage.SummarizeDiffWith(gitDiff, fileClasses, summaries)
 
 	// Extract unique modules from all files
 	moduleSet := make(map[string]bool)
@@ -88,24 +282,8 @@ func CommitAI() int {
 		}
 	}
 
-	// Build sorted list of unique modules
-	var affectedModules []string
-	for module := range moduleSet {
-		affectedModules = append(affectedModules, module)
-	}
-	// Sort for consistent output
-	// Note: Using simple sort since we don't have imports for sort yet
-	// The order doesn't matter for the agent, just consistency
-
-	// Get git diff for staged changes (do not print anything yet)
-	diffCmd := exec.Command("git", "diff", "--staged")
-	diffCmd.Dir = workspaceRoot
-	diffOutput, err := diffCmd.Output()
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error getting git diff: %v\n", err)
-		return 1
-	}
-	gitDiff := string(diffOutput)
+	// Sorted for deterministic section order
+	affectedModules := collections.SortedKeys(moduleSet)
 
 	if debug {
 		fmt.Fprintf(os.Stderr, "\n🔍 DEBUG: Affected modules count: %d\n", len(affectedModules))
@@ -114,90 +292,144 @@ func CommitAI() int {
 		}
 	}
 
-	// LEVER 2a: Build top-level context and invoke top-level agent
-	topLevelContext := buildTopLevelContext(stagedFilesTable, gitDiff, affectedModules)
-
-	if debug {
-		// DEBUG: Save top-level context
-		debugTopLevelContext := filepath.Join(workspaceRoot, "out/debug-top-level-context.md")
-		ioutil.WriteFile(debugTopLevelContext, []byte(topLevelContext), 0644)
-		fmt.Fprintf(os.Stderr, "\n🔍 DEBUG: Top-level context saved to %s\n", debugTopLevelContext)
-	}
-
-	var topLevelOutput string
-	err = commitmessage.WithProgress("🤖 Generating top-level commit summary...", func() error {
-		agentPath := filepath.Join(workspaceRoot, ".claude/agents/commit-message-top-level.md")
-		output, err := callClaudeAgentAPIRaw(agentPath, topLevelContext, workspaceRoot)
-		topLevelOutput = output
-		return err
-	})
-
+	// LEVER 2: Load the agent pipeline (.claude/pipeline.yml or built-in default)
+	pipeline, err := commitmessage.LoadPipeline(workspaceRoot)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "\n❌ Error running commit-message-top-level agent: %v\n", err)
+		fmt.Fprintf(os.Stderr, "Error loading pipeline: %v\n", err)
 		return 1
 	}
-
-	if debug {
-		// DEBUG: Save top-level output
-		debugTopLevelOutput := filepath.Join(workspaceRoot, "out/debug-top-level-output.md")
-		ioutil.WriteFile(debugTopLevelOutput, []byte(topLevelOutput), 0644)
-		fmt.Fprintf(os.Stderr, "🔍 DEBUG: Top-level output saved to %s\n", debugTopLevelOutput)
+	if err := commitConfig.ApplyToPipeline(pipeline); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	if concurrency > 0 {
+		pipeline.SetModuleParallelism(concurrency)
 	}
 
-	// LEVER 2b: Build module contexts and invoke module agent for each
-	// SPECIAL CASE: For single-module commits, skip module sections entirely
-	var moduleSections []string
+	// Broken agent files fail here instead of in the middle of the pipeline
+	if findings := commitmessage.ValidatePipelineAgents(workspaceRoot, pipeline); commitmessage.HasAgentErrors(findings) {
+		fmt.Fprintf(os.Stderr, "Error: invalid agent files (run: validate agents)\n")
+		printAgentFindings(os.Stderr, findings)
+		if format == "json" {
+			printDiagnostics(os.Stdout, agentDiagnostics(findings))
+		}
+		return 1
+	}
 
-	if len(affectedModules) > 1 {
-		// Multi-module: generate module sections
-		// Group files by module
-		moduleFilesMap := make(map[string][]repository.RepositoryFileWithModule)
-		for _, file := range report.AllFiles {
-			for _, module := range file.Modules {
-				moduleFilesMap[module] = append(moduleFilesMap[module], file)
+	// Typed progress for clients such as the MCP server: collect, context,
+	// the pipeline stages that run, then verify
+	var progress *commitmessage.ProgressTracker
+	if os.Getenv(commitmessage.ProgressFormatEnv) == "json" {
+		stages := append([]string{"collect", "context"}, pipeline.ActiveStages(len(affectedModules))...)
+		progress = commitmessage.NewProgressTracker(append(stages, "verify"), commitmessage.JSONProgressEmitter(os.Stderr))
+	}
+	progress.Update("collect", 1, 1, fmt.Sprintf("%d %s file(s) in %d module(s)", len(report.AllFiles), changeKind, len(affectedModules)))
+	progress.Update("context", 0, 1, "Building agent contexts")
+
+	// Documentation most relevant to the affected modules and staged paths
+	var docsSection string
+	if commitConfig.Docs.Enabled {
+		terms := commitmessage.DocTerms(affectedModules, fileNames)
+		docs, err := commitmessage.SelectDocs(workspaceRoot, commitConfig.Docs, terms)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "⚠️  Warning: documentation selection failed: %v\n", err)
+		}
+		docsSection = commitmessage.RenderDocs(docs)
+
+		if debug {
+			fmt.Fprintf(os.Stderr, "\n🔍 DEBUG: Documentation terms: %s\n", strings.Join(terms, ", "))
+			for _, doc := range docs {
+				status := "included"
+				if !doc.Included {
+					status = "skipped, " + doc.Reason
+				}
+				fmt.Fprintf(os.Stderr, "  %s (score %d, %d bytes, %s)\n", doc.Path, doc.Score, doc.Bytes, status)
 			}
 		}
+	}
 
-		for i, module := range affectedModules {
-			moduleFiles := moduleFilesMap[module]
-			moduleContext := buildModuleContext(module, moduleFiles, gitDiff)
-
-			if debug {
-				// DEBUG: Save module context
-				debugModuleContext := filepath.Join(workspaceRoot, fmt.Sprintf("out/debug-module-%d-%s-context.md", i+1, module))
-				ioutil.WriteFile(debugModuleContext, []byte(moduleContext), 0644)
-				fmt.Fprintf(os.Stderr, "🔍 DEBUG: Module context for %s saved to %s\n", module, debugModuleContext)
+	// Module contracts give each module agent focused context; missing contracts are not fatal
+	moduleRegistry, err := modules.LoadFromWorkspace(workspaceRoot, "0.1.0")
+	if err != nil && debug {
+		fmt.Fprintf(os.Stderr, "🔍 DEBUG: Module contracts not loaded: %v\n", err)
+	}
+	contracts := make(map[string]*modules.ModuleContract)
+	if moduleRegistry != nil {
+		for _, module := range affectedModules {
+			if contract, ok := moduleRegistry.Get(module); ok {
+				contracts[module] = contract
 			}
+		}
+	}
 
-			var moduleOutput string
-			progressMsg := fmt.Sprintf("🤖 Generating section for module %s (%d/%d)...", module, i+1, len(affectedModules))
-			err = commitmessage.WithProgress(progressMsg, func() error {
-				agentPath := filepath.Join(workspaceRoot, ".claude/agents/commit-message-module.md")
-				output, err := callClaudeAgentAPIRaw(agentPath, moduleContext, workspaceRoot)
-				moduleOutput = output
-				return err
-			})
-
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "\n❌ Error running commit-message-module agent for %s: %v\n", module, err)
-				return 1
-			}
+	// LEVER 2a/2b: Build the top-level and module contexts
+	contexts := buildAgentContexts(promptInput{
+		Files:       report.AllFiles,
+		FileClasses: fileClasses,
+		Diff:        gitDiff,
+		Modules:     affectedModules,
+		Contracts:   contracts,
+		Docs:        docsSection,
+		Language:    language,
+		Budget:      budget,
+	})
+	topLevelContext, moduleContexts, truncated := contexts.TopLevel, contexts.Modules, contexts.Truncated
 
-			if debug {
-				// DEBUG: Save module output
-				debugModuleOutput := filepath.Join(workspaceRoot, fmt.Sprintf("out/debug-module-%d-%s-output.md", i+1, module))
-				ioutil.WriteFile(debugModuleOutput, []byte(moduleOutput), 0644)
-				fmt.Fprintf(os.Stderr, "🔍 DEBUG: Module output for %s saved to %s\n", module, debugModuleOutput)
-			}
+	if debug {
+		// DEBUG: Save top-level context
+		debugTopLevelContext := filepath.Join(workspaceRoot, "out/debug-top-level-context.md")
+		ioutil.WriteFile(debugTopLevelContext, []byte(topLevelContext), 0644)
+		fmt.Fprintf(os.Stderr, "\n🔍 DEBUG: Top-level context saved to %s\n", debugTopLevelContext)
 
-			moduleSections = append(moduleSections, moduleOutput)
+		// DEBUG: Save module contexts
+		for i, module := range affectedModules {
+			debugModuleContext := filepath.Join(workspaceRoot, fmt.Sprintf("out/debug-module-%d-%s-context.md", i+1, module))
+			ioutil.WriteFile(debugModuleContext, []byte(moduleContexts[module]), 0644)
+			fmt.Fprintf(os.Stderr, "🔍 DEBUG: Module context for %s saved to %s\n", module, debugModuleContext)
 		}
-	} else if debug {
-		fmt.Fprintf(os.Stderr, "🔍 DEBUG: Single-module commit - skipping module sections\n")
 	}
 
-	// LEVER 3: Combine all sections
-	combinedMessage := combineCommitSections(topLevelOutput, moduleSections)
+	progress.Update("context", 1, 1, fmt.Sprintf("Built contexts for %d module(s)", len(affectedModules)))
+
+	// LEVER 3: Run the pipeline stages and combine their sections
+	state, err := commitmessage.RunPipeline(pipeline, workspaceRoot, commitmessage.PipelineInput{
+		CommitContext:  topLevelContext,
+		Modules:        affectedModules,
+		ModuleContexts: moduleContexts,
+	}, func(call commitmessage.AgentCall) (string, error) {
+		return callClaudeAgentAPIRaw(call, workspaceRoot)
+	}, commitmessage.PipelineHooks{
+		Progress: commitmessage.WithProgress,
+		Output: func(stage commitmessage.StageConfig, module string, output string) {
+			if !debug {
+				return
+			}
+			// DEBUG: Save stage output
+			name := stage.Name
+			if module != "" {
+				name += "-" + module
+			}
+			debugOutput := filepath.Join(workspaceRoot, fmt.Sprintf("out/debug-%s-output.md", name))
+			ioutil.WriteFile(debugOutput, []byte(output), 0644)
+			fmt.Fprintf(os.Stderr, "🔍 DEBUG: Output of stage %s saved to %s\n", name, debugOutput)
+		},
+		Warn: func(message string) {
+			fmt.Fprintf(os.Stderr, "⚠️  %s\n", message)
+		},
+		Usage: func(stage commitmessage.StageConfig, usage ai.Usage) {
+			fmt.Printf("   📊 %s: %s\n", stage.Name, usage)
+		},
+		Step: func(stage commitmessage.StageConfig, done int, total int) {
+			progress.Update(stage.Name, done, total, fmt.Sprintf("%d/%d done", done, total))
+		},
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "\n❌ Error running commit message pipeline: %v\n", err)
+		return 1
+	}
+	fmt.Printf("📊 Agent usage: %s\n", state.TotalUsage())
+
+	combinedMessage := state.Message()
 
 	if debug {
 		// DEBUG: Save combined message
@@ -227,7 +459,8 @@ func CommitAI() int {
 	}
 
 	// LEVER 5: Verify contract compliance (silent)
-	validationErrors := commitmessage.VerifyCommitMessageContract(cleanedOutput, affectedModules)
+	progress.Update("verify", 0, 1, "Verifying the message")
+	validationErrors := commitrules.VerifyCommitMessage(cleanedOutput, affectedModules, ruleConfig)
 
 	errorCount, warningCount := 0, 0
 	for _, verr := range validationErrors {
@@ -237,15 +470,70 @@ func CommitAI() int {
 			warningCount++
 		}
 	}
+	progress.Update("verify", 1, 1, fmt.Sprintf("%d error(s), %d warning(s)", errorCount, warningCount))
+
+	// Configured trailers (Signed-off-by, Reviewed-by, ticket IDs) go after all sections,
+	// preceded by the issues referenced in the branch name or the diff
+	branch := currentBranch(workspaceRoot)
+	trailers := commitConfig.Trailers.BuildTrailers(gitIdentity(workspaceRoot), branch)
+	if commitConfig.Issues.Enabled {
+		branchIssues := commitmessage.IssuesFromBranch(branch)
+		numbers := append(append([]int{}, branchIssues...), commitmessage.IssuesFromDiff(gitDiff)...)
+		issues := fetchIssues(workspaceRoot, numbers)
+		trailers = append(commitConfig.Issues.IssueTrailers(issues, branchIssues), trailers...)
+	}
+	cleanedOutput = commitmessage.AppendTrailers(cleanedOutput, trailers)
 
 	// Output for VSCode extension to detect
 	fmt.Println(">>>>>>OUTPUT START<<<<<<")
 	fmt.Println(cleanedOutput)
 	fmt.Println("\n---")
 
+	if patch != "" {
+		// Apply with: git reset -q && git apply --cached <patch>
+		fmt.Println(">>>>>>PATCH START<<<<<<")
+		fmt.Print(patch)
+		fmt.Println(">>>>>>PATCH END<<<<<<")
+		fmt.Println()
+	}
+
+	if tree != nil && len(tree.Unstaged) > 0 {
+		// Stage with: git add --all -- <files>
+		fmt.Println(">>>>>>UNSTAGED FILES START<<<<<<")
+		for _, file := range tree.Unstaged {
+			fmt.Println(file)
+		}
+		fmt.Println(">>>>>>UNSTAGED FILES END<<<<<<")
+		fmt.Println()
+	}
+
+	// Non-blocking stages that failed or timed out left the message of the earlier stages
+	if len(state.Skipped) > 0 {
+		var skipped []string
+		for _, stage := range state.Skipped {
+			skipped = append(skipped, fmt.Sprintf("%s (%s)", stage.Stage, stage.Reason))
+		}
+		fmt.Printf("ℹ️  Skipped stages: %s\n\n", strings.Join(skipped, ", "))
+	}
+
+	if truncated {
+		fmt.Printf("⚠️  %s changes exceeded the token budget (%d); the diff was truncated for generation. Review the message carefully.\n\n", strings.ToUpper(changeKind[:1])+changeKind[1:], tokenBudget)
+	}
+
+	if format == "json" {
+		if err := printDiagnostics(os.Stdout, messageDiagnostics(validationErrors)); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		fmt.Println()
+	}
+
 	// Print verification results
 	if len(validationErrors) == 0 {
 		fmt.Println() // Just a blank line
+		if commit {
+			return commitWorkingTree(workspaceRoot, tree, cleanedOutput, commitConfig.Sign)
+		}
 		return 0
 	}
 
@@ -266,100 +554,193 @@ func CommitAI() int {
 	}
 
 	if errorCount > 0 {
+		if commit {
+			fmt.Println("\n❌ Not committed: fix the contract violations first")
+		}
 		return 1
 	}
 
+	if commit {
+		fmt.Println()
+		return commitWorkingTree(workspaceRoot, tree, cleanedOutput, commitConfig.Sign)
+	}
 	return 0
 }
 
-// callClaudeAgentAPI invokes Claude CLI with isolated session (no --continue or --resume)
-func callClaudeAgentAPI(agentFilePath string, prompt string, workspaceRoot string) (string, error) {
-	// Read agent file to extract model from frontmatter
-	agentContent, err := ioutil.ReadFile(agentFilePath)
-	if err != nil {
-		return "", fmt.Errorf("failed to read agent file: %w", err)
+// commitWorkingTree stages the files listed as unstaged when tree is set, then
+// commits the staged changes with message
+func commitWorkingTree(workspaceRoot string, tree *workingTree, message string, sign *bool) int {
+	if registry.DryRun() {
+		if tree != nil && len(tree.Unstaged) > 0 {
+			fmt.Printf("🔍 Would stage %d file(s)\n", len(tree.Unstaged))
+		}
+		return commitStaged(workspaceRoot, message, sign)
 	}
+	if tree != nil {
+		if err := stageFiles(workspaceRoot, tree.Unstaged); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			return 1
+		}
+		if len(tree.Unstaged) > 0 {
+			fmt.Printf("ℹ️  Staged %d file(s)\n", len(tree.Unstaged))
+		}
+	}
+	return commitStaged(workspaceRoot, message, sign)
+}
 
-	model := extractModelFromAgent(string(agentContent))
-
-	// Build command arguments - IMPORTANT: No --continue or --resume flags for session isolation
-	args := []string{
-		"--print",
-		"--settings", `{"includeCoAuthoredBy":false,"disableAllHooks":true}`,
+// commitStaged commits the staged changes with message. sign forces signing on
+// or off; nil leaves it to commit.gpgSign, gpg.format and user.signingKey.
+func commitStaged(workspaceRoot string, message string, sign *bool) int {
+	if registry.DryRun() {
+		fmt.Println("🔍 Would commit the staged changes with the message above")
+		return 0
 	}
 
-	// Add model if specified in agent frontmatter
-	if model != "" {
-		args = append(args, "--model", model)
-		// Add fallback to haiku if using sonnet (for resilience during high load)
-		if model == "sonnet" {
-			args = append(args, "--fallback-model", "haiku")
+	args := []string{"commit", "--file=-", "--cleanup=whitespace"}
+	if sign != nil {
+		if *sign {
+			args = append(args, "--gpg-sign")
+		} else {
+			args = append(args, "--no-gpg-sign")
 		}
 	}
 
-	// Build full prompt: agent instructions + user input
-	fullPrompt := string(agentContent) + "\n\n>>>>>>>>>>INPUT STARTS NOW<<<<<<<<<<<\n\n" + prompt
-
-	cmd := exec.Command("claude", args...)
-	cmd.Stdin = strings.NewReader(fullPrompt)
-	cmd.Dir = workspaceRoot // Run from repository root for proper context
+	cmd := exec.Command("git", args...)
+	cmd.Dir = workspaceRoot
+	cmd.Stdin = strings.NewReader(message + "\n")
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ git commit failed: %v\n%s", err, output)
+		return 1
+	}
 
-	// CRITICAL: Remove ANTHROPIC_API_KEY to use Claude Pro subscription instead
-	cmd.Env = removeAPIKeyFromEnv(os.Environ())
+	revCmd := exec.Command("git", "rev-parse", "--short", "HEAD")
+	revCmd.Dir = workspaceRoot
+	sha, _ := revCmd.Output()
+	fmt.Printf("✅ Committed %s\n", strings.TrimSpace(string(sha)))
+	return 0
+}
 
-	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
+// gitIdentity returns "user.name <user.email>" from the git config, or "" when unset
+func gitIdentity(workspaceRoot string) string {
+	cmd := exec.Command("git", "var", "GIT_COMMITTER_IDENT")
+	cmd.Dir = workspaceRoot
+	output, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	// "Name <email> 1700000000 +0100": drop the timestamp
+	ident := strings.TrimSpace(string(output))
+	if end := strings.LastIndex(ident, ">"); end >= 0 {
+		return ident[:end+1]
+	}
+	return ""
+}
 
-	if err := cmd.Run(); err != nil {
-		stderrText := stderr.String()
-		stdoutText := stdout.String()
+// fetchIssues looks up issue titles with the gh CLI. Issues are kept without a
+// title when gh is not installed or the lookup fails.
+func fetchIssues(workspaceRoot string, numbers []int) []commitmessage.Issue {
+	_, ghErr := exec.LookPath("gh")
 
-		// Print both to help debug
-		if len(stderrText) > 0 {
-			fmt.Fprintf(os.Stderr, "Claude stderr:\n%s\n", stderrText)
+	var issues []commitmessage.Issue
+	seen := make(map[int]bool)
+	for _, number := range numbers {
+		if seen[number] {
+			continue
 		}
-		if len(stdoutText) > 0 {
-			fmt.Fprintf(os.Stderr, "Claude stdout:\n%s\n", stdoutText)
+		seen[number] = true
+
+		issue := commitmessage.Issue{Number: number}
+		if ghErr == nil {
+			cmd := exec.Command("gh", "issue", "view", strconv.Itoa(number), "--json", "title", "--jq", ".title")
+			cmd.Dir = workspaceRoot
+			if output, err := cmd.Output(); err == nil {
+				issue.Title = strings.TrimSpace(string(output))
+			}
 		}
-
-		return "", fmt.Errorf("claude CLI failed: %w\nStderr: %s\nStdout: %s", err, stderrText, stdoutText)
+		issues = append(issues, issue)
 	}
 
-	// Extract pure content (remove any wrapper text)
-	output := extractContentBlock(stdout.String())
-	return output, nil
+	sort.Slice(issues, func(i, j int) bool { return issues[i].Number < issues[j].Number })
+	return issues
 }
 
-// callClaudeAgentAPIRaw invokes AI provider using the executor abstraction
-func callClaudeAgentAPIRaw(agentFilePath string, prompt string, workspaceRoot string) (string, error) {
-	// Read agent file to extract model from frontmatter
-	agentContent, err := ioutil.ReadFile(agentFilePath)
+// currentBranch returns the checked out branch, or "" on a detached HEAD
+func currentBranch(workspaceRoot string) string {
+	cmd := exec.Command("git", "branch", "--show-current")
+	cmd.Dir = workspaceRoot
+	output, err := cmd.Output()
 	if err != nil {
-		return "", fmt.Errorf("failed to read agent file: %w", err)
+		return ""
 	}
+	return strings.TrimSpace(string(output))
+}
 
-	model := extractModelFromAgent(string(agentContent))
+// callClaudeAgentAPIRaw invokes AI provider using the executor abstraction.
+// A non-empty call.Model overrides the model from the agent frontmatter.
+func callClaudeAgentAPIRaw(call commitmessage.AgentCall, workspaceRoot string) (string, error) {
+	executor, err := newAgentExecutor(workspaceRoot)
+	if err != nil {
+		return "", err
+	}
+	return runAgentFile(executor, call)
+}
 
-	// Create executor and register providers
+// newAgentExecutor creates an executor with the built-in providers, execution
+// logging and, when R2R_AI_RECORDING is set, recording or replay of responses.
+// Run by an MCP server with sampling, the client's model generates instead.
+func newAgentExecutor(workspaceRoot string) (*ai.Executor, error) {
 	executor := ai.NewExecutor(workspaceRoot)
 	providers.RegisterBuiltIn(executor)
+	executor.SetLogger(ai.NewFileLogger(workspaceRoot))
+	if sampling := samplingProviderFromEnv(); sampling != nil {
+		executor.SetProvider(func(config *ai.Config) (ai.Provider, error) {
+			return sampling, nil
+		})
+	}
 
-	// Set up logging
-	logger := ai.NewFileLogger(workspaceRoot)
-	executor.SetLogger(logger)
+	recorder, err := ai.RecorderFromEnv(workspaceRoot)
+	if err != nil {
+		return nil, err
+	}
+	if recorder != nil {
+		executor.SetRecorder(recorder)
+	}
+	return executor, nil
+}
+
+// runAgentFile runs the agent file of call on its input through executor and
+// strips agent noise from the output
+func runAgentFile(executor *ai.Executor, call commitmessage.AgentCall) (string, error) {
+	agentFilePath, model, prompt := call.Agent, call.Model, call.Input
+
+	// Read agent file to extract model from frontmatter
+	agentContent, err := ioutil.ReadFile(agentFilePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read agent file: %w", err)
+	}
+
+	if model == "" {
+		model = extractModelFromAgent(string(agentContent))
+	}
 
 	// Build full prompt: agent instructions + user input
-	fullPrompt := string(agentContent) + "\n\n>>>>>>>>>>INPUT STARTS NOW<<<<<<<<<<<\n\n" + prompt
+	fullPrompt := agentPrompt(string(agentContent), prompt)
 
 	// Prepare options
-	var opts []ai.Option
+	opts := []ai.Option{ai.WithStage(call.Stage)}
 	if model != "" {
 		opts = append(opts, ai.WithModel(model))
 	}
+	if call.Usage != nil {
+		opts = append(opts, ai.WithUsage(call.Usage))
+	}
 
-	// Execute with context
-	ctx := context.Background()
+	// Execute with the stage context, which carries the stage timeout
+	ctx := call.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
 	output, err := executor.Execute(ctx, fullPrompt, opts...)
 	if err != nil {
 		return "", fmt.Errorf("AI execution failed: %w", err)
@@ -626,10 +1007,200 @@ func removeAPIKeyFromEnv(environ []string) []string {
 	return filtered
 }
 
-// buildTopLevelContext creates context for the top-level commit message agent
-func buildTopLevelContext(stagedFilesTable string, gitDiff string, affectedModules []string) string {
+// inProgressCommit detects a merge, revert or cherry-pick waiting to be committed
+func inProgressCommit(workspaceRoot string) (*commitmessage.InProgressCommit, error) {
+	heads := []struct {
+		file string
+		kind commitmessage.CommitKind
+	}{
+		{"MERGE_HEAD", commitmessage.CommitKindMerge},
+		{"CHERRY_PICK_HEAD", commitmessage.CommitKindCherryPick},
+		{"REVERT_HEAD", commitmessage.CommitKindRevert},
+	}
+
+	for _, head := range heads {
+		content, err := os.ReadFile(gitPath(workspaceRoot, head.file))
+		if err != nil {
+			continue
+		}
+		commit := strings.TrimSpace(strings.SplitN(string(content), "\n", 2)[0])
+		if commit == "" {
+			continue
+		}
+
+		logCmd := exec.Command("git", "log", "-1", "--format=%B", commit)
+		logCmd.Dir = workspaceRoot
+		original, err := logCmd.Output()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read message of %s %s: %w", head.file, commit, err)
+		}
+
+		prepared, _ := os.ReadFile(gitPath(workspaceRoot, "MERGE_MSG"))
+		return &commitmessage.InProgressCommit{
+			Kind:     head.kind,
+			Commit:   commit,
+			Original: string(original),
+			Prepared: string(prepared),
+		}, nil
+	}
+
+	return nil, nil
+}
+
+// gitPath resolves a path inside the git directory, which may be shared by worktrees
+func gitPath(workspaceRoot string, name string) string {
+	cmd := exec.Command("git", "rev-parse", "--git-path", name)
+	cmd.Dir = workspaceRoot
+	output, err := cmd.Output()
+	if err != nil {
+		return filepath.Join(workspaceRoot, ".git", name)
+	}
+	path := strings.TrimSpace(string(output))
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(workspaceRoot, path)
+	}
+	return path
+}
+
+// linguistGenerated reads the linguist-generated attribute of files from
+// .gitattributes. Files without the attribute are left out.
+func linguistGenerated(workspaceRoot string, files []string) map[string]bool {
+	generated := make(map[string]bool)
+	if len(files) == 0 {
+		return generated
+	}
+
+	cmd := exec.Command("git", "check-attr", "-z", "--stdin", "linguist-generated")
+	cmd.Dir = workspaceRoot
+	cmd.Stdin = strings.NewReader(strings.Join(files, "\x00") + "\x00")
+	output, err := cmd.Output()
+	if err != nil {
+		return generated
+	}
+
+	// Output is "<path>\0linguist-generated\0<value>\0" per file
+	fields := strings.Split(string(output), "\x00")
+	for i := 0; i+2 < len(fields); i += 3 {
+		switch fields[i+2] {
+		case "set", "true":
+			generated[fields[i]] = true
+		case "unset", "false":
+			generated[fields[i]] = false
+		}
+	}
+	return generated
+}
+
+// summarizeOversizeFiles returns generated summaries of the oversize files of
+// gitDiff, keyed by path. Failures are reported as warnings; those files keep
+// their hunk list.
+func summarizeOversizeFiles(workspaceRoot string, gitDiff string, fileClasses map[string]commitmessage.FileClass, model string, maxTokens int) map[string]string {
+	executor, err := newAgentExecutor(workspaceRoot)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  Warning: diff summaries disabled: %v\n", err)
+		return nil
+	}
+
+	var usage ai.Usage
+	cache := commitmessage.NewSummaryCache(filepath.Join(workspaceRoot, commitmessage.SummaryCacheDir))
+	summaries, stats, err := commitmessage.SummarizeOversize(gitDiff, fileClasses, cache, func(file commitmessage.FileDiff) (string, error) {
+		var callUsage ai.Usage
+		summary, err := executor.Execute(context.Background(), commitmessage.SummaryPrompt(file, maxTokens),
+			ai.WithModel(model), ai.WithStage("summarize"), ai.WithUsage(&callUsage))
+		usage.Add(callUsage)
+		return summary, err
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  Warning: diff summaries: %v\n", err)
+	}
+	if stats.Cached+stats.Generated > 0 {
+		fmt.Printf("ℹ️  Summarized %d large file diff(s): %d cached, %d generated\n", stats.Cached+stats.Generated, stats.Cached, stats.Generated)
+	}
+	if stats.Generated+stats.Failed > 0 {
+		fmt.Printf("   📊 summarize: %s\n", usage)
+	}
+	return summaries
+}
+
+// promptInput is what the agent contexts are built from
+type promptInput struct {
+	Files       []repository.RepositoryFileWithModule
+	FileClasses map[string]commitmessage.FileClass
+	Diff        string
+	Modules     []string                           // affected modules, sorted
+	Contracts   map[string]*modules.ModuleContract // module contracts, when found
+	Docs        string                             // rendered documentation section
+	Language    string                             // language of the prose
+	Budget      commitmessage.ContextBudget
+}
+
+// agentContexts are the inputs of the top-level and module agents
+type agentContexts struct {
+	TopLevel  string
+	Modules   map[string]string // module name -> module context
+	Truncated bool              // a files table or diff was cut to fit the budget
+}
+
+// buildAgentContexts builds the context of the top-level agent and of each
+// module agent. The golden tests in prompt_golden_test.go render it from
+// fixtures, so changes to the prompts show up in review.
+func buildAgentContexts(input promptInput) agentContexts {
+	// Build the staged files table (same format as "show files staged")
+	tb := render.NewTableBuilder().
+		WithHeaders("File", "Modules")
+
+	for _, file := range input.Files {
+		modulesStr := "NONE"
+		if len(file.Modules) > 0 {
+			modulesStr = strings.Join(file.Modules, ", ")
+		}
+		tb.AddRow(commitmessage.FileLabel(file.Name, input.FileClasses[file.Name]), modulesStr)
+	}
+
+	topLevel, truncated := buildTopLevelContext(tb.Build(), input.Diff, input.Modules, input.Docs, input.Budget)
+
+	// Prose in the team's language; titles and subject lines stay English
+	languageInstruction := commitmessage.LanguageInstruction(input.Language)
+	if languageInstruction != "" {
+		topLevel += "\n" + languageInstruction
+	}
+
+	// Group files by module
+	moduleFilesMap := make(map[string][]repository.RepositoryFileWithModule)
+	for _, file := range input.Files {
+		for _, module := range file.Modules {
+			moduleFilesMap[module] = append(moduleFilesMap[module], file)
+		}
+	}
+
+	moduleContexts := make(map[string]string)
+	for _, module := range input.Modules {
+		moduleContext, moduleTruncated := buildModuleContext(module, input.Contracts[module], moduleFilesMap[module], input.FileClasses, input.Diff, input.Budget)
+		if languageInstruction != "" {
+			moduleContext += "\n" + languageInstruction
+		}
+		moduleContexts[module] = moduleContext
+		truncated = truncated || moduleTruncated
+	}
+
+	return agentContexts{TopLevel: topLevel, Modules: moduleContexts, Truncated: truncated}
+}
+
+// agentPrompt is the full prompt of an agent: its instructions followed by
+// the input
+func agentPrompt(agentContent string, input string) string {
+	return agentContent + "\n\n>>>>>>>>>>INPUT STARTS NOW<<<<<<<<<<<\n\n" + input
+}
+
+// buildTopLevelContext creates context for the top-level commit message agent,
+// with the selected documentation section when not empty.
+// Reports whether the files table or diff was truncated to fit the budget.
+func buildTopLevelContext(stagedFilesTable string, gitDiff string, affectedModules []string, docsSection string, budget commitmessage.ContextBudget) (string, bool) {
 	var context bytes.Buffer
 
+	stagedFilesTable, tableTruncated := commitmessage.FitLines(stagedFilesTable, budget.Table)
+	gitDiff, diffTruncated := commitmessage.FitDiff(gitDiff, budget.Diff)
+
 	// Module Count and List
 	context.WriteString("## Module Count\n\n")
 	if len(affectedModules) == 1 {
@@ -645,6 +1216,9 @@ func buildTopLevelContext(stagedFilesTable string, gitDiff string, affectedModul
 	}
 	context.WriteString("\n")
 
+	// Relevant Documentation - selected by DocsConfig
+	context.WriteString(docsSection)
+
 	// Staged Files - shows all file-to-module mappings
 	context.WriteString("## Staged Files\n\n")
 	context.WriteString(stagedFilesTable)
@@ -656,11 +1230,12 @@ func buildTopLevelContext(stagedFilesTable string, gitDiff string, affectedModul
 	context.WriteString(gitDiff)
 	context.WriteString("\n```\n")
 
-	return context.String()
+	return context.String(), tableTruncated || diffTruncated
 }
 
-// buildModuleContext creates context for a single module section agent
-func buildModuleContext(moduleName string, moduleFiles []repository.RepositoryFileWithModule, fullDiff string) string {
+// buildModuleContext creates context for a single module section agent.
+// Reports whether the module diff was truncated to fit the budget.
+func buildModuleContext(moduleName string, contract *modules.ModuleContract, moduleFiles []repository.RepositoryFileWithModule, fileClasses map[string]commitmessage.FileClass, fullDiff string, budget commitmessage.ContextBudget) (string, bool) {
 	var context bytes.Buffer
 
 	// Module Name
@@ -668,25 +1243,41 @@ func buildModuleContext(moduleName string, moduleFiles []repository.RepositoryFi
 	context.WriteString(moduleName)
 	context.WriteString("\n\n")
 
+	// Module contract summary
+	if contract != nil {
+		context.WriteString("## Module Contract\n\n")
+		context.WriteString(fmt.Sprintf("- Type: %s\n", contract.Type))
+		if contract.Description != "" {
+			context.WriteString(fmt.Sprintf("- Description: %s\n", contract.Description))
+		}
+		if contract.Source.Root != "" {
+			context.WriteString(fmt.Sprintf("- Root: %s\n", contract.Source.Root))
+		}
+		if len(contract.DependsOn) > 0 {
+			context.WriteString(fmt.Sprintf("- Depends on: %s\n", strings.Join(contract.DependsOn, ", ")))
+		}
+		context.WriteString("\n")
+	}
+
 	// Files for this module
 	context.WriteString("## Files\n\n")
 	tb := render.NewTableBuilder().
 		WithHeaders("File")
 
 	for _, file := range moduleFiles {
-		tb.AddRow(file.Name)
+		tb.AddRow(commitmessage.FileLabel(file.Name, fileClasses[file.Name]))
 	}
 	context.WriteString(tb.Build())
 	context.WriteString("\n\n")
 
 	// Git diff filtered to this module's files
-	filteredDiff := filterDiffForModule(fullDiff, moduleFiles)
+	filteredDiff, truncated := commitmessage.FitDiff(filterDiffForModule(fullDiff, moduleFiles), budget.Diff)
 	context.WriteString("## Git Diff\n\n")
 	context.WriteString("```diff\n")
 	context.WriteString(filteredDiff)
 	context.WriteString("\n```\n")
 
-	return context.String()
+	return context.String(), truncated
 }
 
 // filterDiffForModule extracts only the diff chunks for files belonging to a specific module
@@ -818,29 +1409,3 @@ func addMissingModules(commitMessage string, affectedModules []string, allFiles
 
 	return result.String()
 }
-
-// combineCommitSections combines top-level section and module sections into final commit message
-func combineCommitSections(topLevel string, moduleSections []string) string {
-	var result bytes.Buffer
-
-	// Top-level section
-	result.WriteString(topLevel)
-
-	// Only add module sections if there are any (multi-module commits only)
-	if len(moduleSections) > 0 {
-		result.WriteString("\n\n")
-
-		// Module sections with --- separators
-		for i, section := range moduleSections {
-			result.WriteString(section)
-
-			// Add separator between modules (but not after the last one)
-			if i < len(moduleSections)-1 {
-				result.WriteString("\n\n---\n\n")
-			}
-		}
-	}
-
-	return result.String()
-}
-
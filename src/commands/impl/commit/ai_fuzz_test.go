@@ -0,0 +1,72 @@
+package commit
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ready-to-release/eac/src/core/ai"
+)
+
+// addRecordedResponses seeds a fuzz target with the agent responses
+// recorded in testdata/recordings
+func addRecordedResponses(f *testing.F) {
+	f.Helper()
+	paths, err := filepath.Glob(filepath.Join("testdata", "recordings", "*.json"))
+	if err != nil || len(paths) == 0 {
+		f.Fatalf("no recorded agent responses in testdata/recordings: %v", err)
+	}
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			f.Fatal(err)
+		}
+		var recording ai.Recording
+		if err := json.Unmarshal(data, &recording); err != nil {
+			f.Fatalf("%s: %v", path, err)
+		}
+		f.Add(recording.Response)
+	}
+}
+
+func FuzzExtractContentBlock(f *testing.F) {
+	addRecordedResponses(f)
+	f.Add("")
+	f.Add("```\n```")
+	f.Add("Agent: commit-message-generator")
+
+	f.Fuzz(func(t *testing.T, output string) {
+		content := extractContentBlock(output)
+
+		if content != strings.TrimSpace(content) {
+			t.Errorf("content is not trimmed: %q", content)
+		}
+		if again := extractContentBlock(content); again != content {
+			t.Errorf("not idempotent:\nfirst:  %q\nsecond: %q", content, again)
+		}
+		if !strings.Contains(output, content) {
+			t.Errorf("content %q is not taken from the output", content)
+		}
+	})
+}
+
+func FuzzStripAgentNoise(f *testing.F) {
+	addRecordedResponses(f)
+	f.Add("")
+	f.Add("## ---\n## ai")
+
+	f.Fuzz(func(t *testing.T, output string) {
+		for _, agentType := range []string{"top-level", "module", "unknown"} {
+			stripped := stripAgentNoise(output, agentType)
+
+			if stripped != strings.TrimSpace(stripped) {
+				t.Errorf("%s: output is not trimmed: %q", agentType, stripped)
+			}
+			if again := stripAgentNoise(stripped, agentType); again != stripped {
+				t.Errorf("%s: not idempotent:\nfirst:  %q\nsecond: %q", agentType, stripped, again)
+			}
+		}
+	})
+}
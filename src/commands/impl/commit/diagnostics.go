@@ -0,0 +1,88 @@
+package commit
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	commitmessage "github.com/ready-to-release/eac/src/commands/impl/commit/internal"
+	"github.com/ready-to-release/eac/src/core/commitrules"
+)
+
+// Markers around the diagnostics commit-ai prints with --format json. The
+// MCP commands server returns the JSON between them as a separate content
+// block (src/mcp/commands/main.go).
+const (
+	diagnosticsStart = ">>>>>>DIAGNOSTICS START<<<<<<"
+	diagnosticsEnd   = ">>>>>>DIAGNOSTICS END<<<<<<"
+)
+
+// Diagnostic is a finding with the location an editor opens for it: a line
+// of a repository file, or a line of the generated message when File is
+// empty
+type Diagnostic struct {
+	File     string `json:"file,omitempty"` // relative to the repository root
+	Line     int    `json:"line,omitempty"` // 1-based, 0 for the whole file or message
+	Code     string `json:"code"`
+	Rule     string `json:"rule,omitempty"`
+	Severity string `json:"severity"` // "error" or "warning"
+	Message  string `json:"message"`
+}
+
+// DiagnosticsReport is the JSON printed between the diagnostics markers
+type DiagnosticsReport struct {
+	Valid       bool         `json:"valid"`
+	Diagnostics []Diagnostic `json:"diagnostics"`
+}
+
+// messageDiagnostics locates contract violations in the generated message
+func messageDiagnostics(findings []commitrules.ValidationError) []Diagnostic {
+	diagnostics := make([]Diagnostic, 0, len(findings))
+	for _, finding := range findings {
+		diagnostics = append(diagnostics, Diagnostic{
+			Line:     finding.Line,
+			Code:     finding.Code,
+			Rule:     finding.Rule,
+			Severity: finding.Severity,
+			Message:  finding.Message,
+		})
+	}
+	return diagnostics
+}
+
+// agentDiagnostics locates findings in the agent files
+func agentDiagnostics(findings []commitmessage.AgentFinding) []Diagnostic {
+	diagnostics := make([]Diagnostic, 0, len(findings))
+	for _, finding := range findings {
+		diagnostics = append(diagnostics, Diagnostic{
+			File:     finding.File,
+			Line:     finding.Line,
+			Code:     finding.Check,
+			Severity: finding.Severity,
+			Message:  finding.Message,
+		})
+	}
+	return diagnostics
+}
+
+// printDiagnostics writes diagnostics as JSON between the diagnostics markers
+func printDiagnostics(w io.Writer, diagnostics []Diagnostic) error {
+	report := DiagnosticsReport{Valid: true, Diagnostics: diagnostics}
+	if report.Diagnostics == nil {
+		report.Diagnostics = []Diagnostic{}
+	}
+	for _, diagnostic := range diagnostics {
+		if diagnostic.Severity == "error" {
+			report.Valid = false
+		}
+	}
+
+	data, err := json.Marshal(report)
+	if err != nil {
+		return fmt.Errorf("failed to encode diagnostics: %w", err)
+	}
+	fmt.Fprintln(w, diagnosticsStart)
+	fmt.Fprintln(w, string(data))
+	fmt.Fprintln(w, diagnosticsEnd)
+	return nil
+}
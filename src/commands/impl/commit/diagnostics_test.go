@@ -0,0 +1,61 @@
+package commit
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	commitmessage "github.com/ready-to-release/eac/src/commands/impl/commit/internal"
+	"github.com/ready-to-release/eac/src/core/commitrules"
+)
+
+func TestPrintDiagnostics(t *testing.T) {
+	diagnostics := append(
+		messageDiagnostics([]commitrules.ValidationError{
+			{Code: "SUBJECT_LENGTH", Rule: "subject-length", Message: "Subject too long", Line: 3, Severity: "warning"},
+		}),
+		agentDiagnostics([]commitmessage.AgentFinding{
+			{File: ".claude/agents/commit-message-module.md", Line: 2, Check: "frontmatter", Severity: "error", Message: "missing name"},
+		})...,
+	)
+
+	var buf bytes.Buffer
+	if err := printDiagnostics(&buf, diagnostics); err != nil {
+		t.Fatalf("printDiagnostics() error = %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 || lines[0] != diagnosticsStart || lines[2] != diagnosticsEnd {
+		t.Fatalf("Unexpected output:\n%s", buf.String())
+	}
+
+	var report DiagnosticsReport
+	if err := json.Unmarshal([]byte(lines[1]), &report); err != nil {
+		t.Fatalf("Invalid JSON: %v", err)
+	}
+	if report.Valid {
+		t.Error("Expected an invalid report with an error diagnostic")
+	}
+	want := []Diagnostic{
+		{Line: 3, Code: "SUBJECT_LENGTH", Rule: "subject-length", Severity: "warning", Message: "Subject too long"},
+		{File: ".claude/agents/commit-message-module.md", Line: 2, Code: "frontmatter", Severity: "error", Message: "missing name"},
+	}
+	if len(report.Diagnostics) != len(want) {
+		t.Fatalf("Diagnostics = %+v, want %+v", report.Diagnostics, want)
+	}
+	for i := range want {
+		if report.Diagnostics[i] != want[i] {
+			t.Errorf("Diagnostics[%d] = %+v, want %+v", i, report.Diagnostics[i], want[i])
+		}
+	}
+}
+
+func TestPrintDiagnostics_NoFindings(t *testing.T) {
+	var buf bytes.Buffer
+	if err := printDiagnostics(&buf, nil); err != nil {
+		t.Fatalf("printDiagnostics() error = %v", err)
+	}
+	if !strings.Contains(buf.String(), `{"valid":true,"diagnostics":[]}`) {
+		t.Errorf("Unexpected output:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,96 @@
+// Command: validate commit-message install-hook
+// Description: Install a commit-msg git hook that runs validate commit-message
+// Usage: go run . validate commit-message install-hook [--force]
+// Flags:
+//   --force: Replace an existing commit-msg hook that was not installed by this command
+// HasSideEffects: true
+// DryRun: true
+package commit
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/ready-to-release/eac/src/commands/internal/registry"
+	"github.com/ready-to-release/eac/src/core/repository"
+)
+
+// hookMarker identifies hooks written by this command, so they can be updated in place
+const hookMarker = "# Installed by: validate commit-message install-hook"
+
+const commitMsgHook = `#!/bin/sh
+` + hookMarker + `
+# Validates the commit message against the commit message contract.
+# Bypass with: git commit --no-verify
+
+msg_file="$(cd "$(dirname "$1")" && pwd)/$(basename "$1")"
+cd "$(git rev-parse --show-toplevel)/src/commands" || exit 1
+exec go run . validate commit-message "$msg_file"
+`
+
+func init() {
+	registry.Register(InstallCommitMessageHook)
+}
+
+// InstallCommitMessageHook writes the commit-msg hook into the repository hooks directory
+func InstallCommitMessageHook() int {
+	fs := flag.NewFlagSet("validate commit-message install-hook", flag.ContinueOnError)
+	force := fs.Bool("force", false, "Replace an existing commit-msg hook")
+
+	// Skip binary path, "validate", "commit-message" and "install-hook"
+	args := []string{}
+	if len(os.Args) > 4 {
+		args = os.Args[4:]
+	}
+	if err := fs.Parse(args); err != nil {
+		return 1
+	}
+
+	workspaceRoot, err := repository.GetRepositoryRoot("")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to find repository root: %v\n", err)
+		return 1
+	}
+
+	// Honors core.hooksPath and worktrees
+	cmd := exec.Command("git", "rev-parse", "--git-path", "hooks")
+	cmd.Dir = workspaceRoot
+	output, err := cmd.Output()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to locate git hooks directory: %v\n", err)
+		return 1
+	}
+	hooksDir := strings.TrimSpace(string(output))
+	if !filepath.IsAbs(hooksDir) {
+		hooksDir = filepath.Join(workspaceRoot, hooksDir)
+	}
+	hookPath := filepath.Join(hooksDir, "commit-msg")
+
+	if existing, err := os.ReadFile(hookPath); err == nil {
+		if !strings.Contains(string(existing), hookMarker) && !*force {
+			fmt.Fprintf(os.Stderr, "Error: %s already exists and was not installed by this command (use --force to replace it)\n", hookPath)
+			return 1
+		}
+	}
+
+	if registry.DryRun() {
+		fmt.Printf("🔍 Would install commit-msg hook: %s\n", hookPath)
+		return 0
+	}
+
+	if err := os.MkdirAll(hooksDir, 0755); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to create hooks directory: %v\n", err)
+		return 1
+	}
+	if err := os.WriteFile(hookPath, []byte(commitMsgHook), 0755); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to write hook: %v\n", err)
+		return 1
+	}
+
+	fmt.Printf("✅ Installed commit-msg hook: %s\n", hookPath)
+	return 0
+}
@@ -0,0 +1,351 @@
+package commitmessage
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// AgentsDir holds the agent files, relative to the repository root
+const AgentsDir = ".claude/agents"
+
+// AgentFinding is a problem found in an agent file
+type AgentFinding struct {
+	File     string `json:"file"`           // relative to the repository root
+	Line     int    `json:"line,omitempty"` // 1-based, 0 for the whole file
+	Check    string `json:"check"`
+	Severity string `json:"severity"` // "error" or "warning"
+	Message  string `json:"message"`
+}
+
+func (f AgentFinding) Error() string {
+	location := f.File
+	if f.Line > 0 {
+		location = fmt.Sprintf("%s:%d", f.File, f.Line)
+	}
+	return fmt.Sprintf("%s: %s (%s)", location, f.Message, f.Check)
+}
+
+// AgentFrontmatter is the YAML header of an agent file
+type AgentFrontmatter struct {
+	Name        string `yaml:"name"`
+	Description string `yaml:"description"`
+	Model       string `yaml:"model"`
+	Arguments   []struct {
+		Name string `yaml:"name"`
+	} `yaml:"arguments"`
+}
+
+var (
+	agentHeadingPattern     = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*\s*$`)
+	agentLinkPattern        = regexp.MustCompile(`\]\(([^)\s]+)(?:\s+"[^"]*")?\)`)
+	agentFileRefPattern     = regexp.MustCompile(`(?:^|\s)@([\w./-]+\.\w+)`)
+	agentPlaceholderPattern = regexp.MustCompile(`\{\{\s*([\w-]+)\s*\}\}`)
+	agentModelPattern       = regexp.MustCompile(`^(haiku|sonnet|opus|inherit|claude-[\w.-]+)$`)
+)
+
+// defaultAgentArgument is the argument offered by the MCP prompts when an
+// agent declares none (src/mcp/commands/prompts.go)
+const defaultAgentArgument = "context"
+
+// AgentReport is the result of validating the agent files of a repository
+type AgentReport struct {
+	Files    []string       `json:"files"`
+	Valid    bool           `json:"valid"`
+	Errors   int            `json:"errors"`
+	Warnings int            `json:"warnings"`
+	Findings []AgentFinding `json:"findings"`
+}
+
+// ValidateAgents checks every agent file in AgentsDir and the agents of the
+// pipeline stages. Findings are sorted by file and line.
+func ValidateAgents(workspaceRoot string, pipeline *PipelineConfig) (*AgentReport, error) {
+	matches, err := filepath.Glob(filepath.Join(workspaceRoot, AgentsDir, "*.md"))
+	if err != nil {
+		return nil, err
+	}
+
+	report := &AgentReport{Files: []string{}, Findings: []AgentFinding{}}
+	seen := make(map[string]bool)
+	for _, file := range matches {
+		rel, err := filepath.Rel(workspaceRoot, file)
+		if err != nil {
+			return nil, err
+		}
+		rel = filepath.ToSlash(rel)
+		seen[rel] = true
+		report.Files = append(report.Files, rel)
+		report.Findings = append(report.Findings, ValidateAgentFile(workspaceRoot, rel)...)
+	}
+
+	// Stage agents outside AgentsDir, and stages whose agent is missing
+	if pipeline != nil {
+		for _, finding := range ValidatePipelineAgents(workspaceRoot, pipeline) {
+			if !seen[finding.File] || finding.Check == "pipeline-agent" {
+				report.Findings = append(report.Findings, finding)
+			}
+		}
+		for _, stage := range pipeline.Stages {
+			rel := filepath.ToSlash(filepath.Clean(stage.Agent))
+			if _, err := os.Stat(filepath.Join(workspaceRoot, rel)); err == nil && !seen[rel] {
+				seen[rel] = true
+				report.Files = append(report.Files, rel)
+			}
+		}
+	}
+
+	sortAgentFindings(report.Findings)
+	for _, finding := range report.Findings {
+		if finding.Severity == "error" {
+			report.Errors++
+		} else {
+			report.Warnings++
+		}
+	}
+	report.Valid = report.Errors == 0
+	return report, nil
+}
+
+// ValidatePipelineAgents checks the agent file of every pipeline stage. A
+// missing model is accepted for stages that set their own.
+func ValidatePipelineAgents(workspaceRoot string, pipeline *PipelineConfig) []AgentFinding {
+	var findings []AgentFinding
+	checked := make(map[string]bool)
+	for _, stage := range pipeline.Stages {
+		rel := filepath.ToSlash(filepath.Clean(stage.Agent))
+		if _, err := os.Stat(filepath.Join(workspaceRoot, rel)); err != nil {
+			findings = append(findings, AgentFinding{
+				File:     rel,
+				Check:    "pipeline-agent",
+				Severity: "error",
+				Message:  fmt.Sprintf("agent of stage %s not found", stage.Name),
+			})
+			continue
+		}
+		if checked[rel] {
+			continue
+		}
+		checked[rel] = true
+
+		for _, finding := range ValidateAgentFile(workspaceRoot, rel) {
+			if finding.Check == "required-field" && strings.Contains(finding.Message, "model") && stage.Model != "" {
+				continue
+			}
+			findings = append(findings, finding)
+		}
+	}
+	return findings
+}
+
+// ValidateAgentFile checks the frontmatter, doc references and prompt
+// sections of an agent file, given relative to the repository root
+func ValidateAgentFile(workspaceRoot string, rel string) []AgentFinding {
+	data, err := os.ReadFile(filepath.Join(workspaceRoot, rel))
+	if err != nil {
+		return []AgentFinding{{File: rel, Check: "read", Severity: "error", Message: err.Error()}}
+	}
+
+	var findings []AgentFinding
+	add := func(line int, check, severity, format string, args ...interface{}) {
+		findings = append(findings, AgentFinding{
+			File:     rel,
+			Line:     line,
+			Check:    check,
+			Severity: severity,
+			Message:  fmt.Sprintf(format, args...),
+		})
+	}
+
+	lines := strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n")
+
+	// Frontmatter
+	var frontmatter AgentFrontmatter
+	bodyStart := 0
+	switch {
+	case len(lines) == 0 || strings.TrimSpace(lines[0]) != "---":
+		add(1, "frontmatter", "error", "missing frontmatter (--- block with model and description)")
+	default:
+		end := -1
+		for i := 1; i < len(lines); i++ {
+			if strings.TrimSpace(lines[i]) == "---" {
+				end = i
+				break
+			}
+		}
+		if end < 0 {
+			add(1, "frontmatter", "error", "unterminated frontmatter")
+			return findings
+		}
+		if err := yaml.Unmarshal([]byte(strings.Join(lines[1:end], "\n")), &frontmatter); err != nil {
+			add(1, "frontmatter", "error", "invalid frontmatter: %v", firstLine(err.Error()))
+		}
+		bodyStart = end + 1
+	}
+
+	if bodyStart > 0 {
+		if strings.TrimSpace(frontmatter.Model) == "" {
+			add(1, "required-field", "error", "frontmatter field model is required")
+		} else if !agentModelPattern.MatchString(frontmatter.Model) {
+			add(1, "model", "warning", "unknown model %q (expected haiku, sonnet, opus or a claude-* model ID)", frontmatter.Model)
+		}
+		if strings.TrimSpace(frontmatter.Description) == "" {
+			add(1, "required-field", "error", "frontmatter field description is required")
+		}
+	}
+
+	arguments := map[string]bool{}
+	for _, arg := range frontmatter.Arguments {
+		arguments[arg.Name] = true
+	}
+	if len(arguments) == 0 {
+		arguments[defaultAgentArgument] = true
+	}
+
+	// Body: sections, code blocks, references and placeholders
+	type section struct {
+		line    int
+		level   int
+		title   string
+		content bool
+	}
+	var current *section
+	headings := make(map[string]int)
+	hasContent := false
+	fenceLine := 0
+
+	closeSection := func(next int) {
+		if current != nil && !current.content && (next == 0 || next <= current.level) {
+			add(current.line, "empty-section", "warning", "section %q has no content", current.title)
+		}
+	}
+
+	for i := bodyStart; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+		lineNumber := i + 1
+
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			if fenceLine == 0 {
+				fenceLine = lineNumber
+			} else {
+				fenceLine = 0
+			}
+			hasContent = true
+			if current != nil {
+				current.content = true
+			}
+			continue
+		}
+		if fenceLine != 0 {
+			continue
+		}
+
+		if match := agentHeadingPattern.FindStringSubmatch(trimmed); match != nil {
+			level := len(match[1])
+			closeSection(level)
+			key := strings.ToLower(match[2])
+			if first, ok := headings[key]; ok {
+				add(lineNumber, "duplicate-section", "warning", "section %q already declared on line %d", match[2], first)
+			} else {
+				headings[key] = lineNumber
+			}
+			current = &section{line: lineNumber, level: level, title: match[2]}
+			hasContent = true
+			continue
+		}
+
+		if trimmed == "" {
+			continue
+		}
+		hasContent = true
+		if current != nil {
+			current.content = true
+		}
+
+		for _, ref := range agentReferences(line) {
+			if !agentReferenceExists(workspaceRoot, rel, ref) {
+				add(lineNumber, "doc-reference", "error", "referenced file %s does not exist", ref)
+			}
+		}
+
+		for _, match := range agentPlaceholderPattern.FindAllStringSubmatch(line, -1) {
+			if !arguments[match[1]] {
+				add(lineNumber, "placeholder", "warning", "placeholder {{%s}} is not a declared argument", match[1])
+			}
+		}
+	}
+	closeSection(0)
+
+	if fenceLine != 0 {
+		add(fenceLine, "code-block", "error", "code block is never closed")
+	}
+	if !hasContent {
+		add(0, "empty-body", "error", "agent has no instructions")
+	}
+
+	sortAgentFindings(findings)
+	return findings
+}
+
+// agentReferences returns the local files referenced by markdown links and
+// @path mentions on a line
+func agentReferences(line string) []string {
+	var refs []string
+	for _, match := range agentLinkPattern.FindAllStringSubmatch(line, -1) {
+		target := match[1]
+		if strings.Contains(target, "://") || strings.HasPrefix(target, "#") || strings.HasPrefix(target, "mailto:") {
+			continue
+		}
+		if i := strings.IndexByte(target, '#'); i >= 0 {
+			target = target[:i]
+		}
+		refs = append(refs, target)
+	}
+	for _, match := range agentFileRefPattern.FindAllStringSubmatch(line, -1) {
+		refs = append(refs, match[1])
+	}
+	return refs
+}
+
+// agentReferenceExists resolves ref relative to the agent file, then to the
+// repository root
+func agentReferenceExists(workspaceRoot, agentFile, ref string) bool {
+	if filepath.IsAbs(ref) {
+		_, err := os.Stat(ref)
+		return err == nil
+	}
+	candidates := []string{
+		filepath.Join(workspaceRoot, filepath.Dir(agentFile), ref),
+		filepath.Join(workspaceRoot, ref),
+	}
+	for _, candidate := range candidates {
+		if _, err := os.Stat(candidate); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// HasAgentErrors reports whether any finding is an error
+func HasAgentErrors(findings []AgentFinding) bool {
+	for _, finding := range findings {
+		if finding.Severity == "error" {
+			return true
+		}
+	}
+	return false
+}
+
+func sortAgentFindings(findings []AgentFinding) {
+	sort.SliceStable(findings, func(i, j int) bool {
+		if findings[i].File != findings[j].File {
+			return findings[i].File < findings[j].File
+		}
+		return findings[i].Line < findings[j].Line
+	})
+}
@@ -0,0 +1,174 @@
+package commitmessage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeAgent writes an agent file relative to root
+func writeAgent(t *testing.T, root, rel, content string) {
+	t.Helper()
+	path := filepath.Join(root, rel)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// agentChecks returns the check IDs of findings
+func agentChecks(findings []AgentFinding) []string {
+	checks := make([]string, len(findings))
+	for i, finding := range findings {
+		checks[i] = finding.Check
+	}
+	return checks
+}
+
+func TestValidateAgentFile_Valid(t *testing.T) {
+	root := t.TempDir()
+	writeAgent(t, root, "docs/commit-format.md", "# Format")
+	writeAgent(t, root, ".claude/agents/writer.md", `---
+description: Writes commit messages
+model: sonnet
+---
+# Writer
+
+Follow [the format](../../docs/commit-format.md#title) and @docs/commit-format.md.
+
+## Input
+
+{{context}}
+
+`+"```"+`
+## not a heading, [not a link](missing.md)
+`+"```"+`
+`)
+
+	if findings := ValidateAgentFile(root, ".claude/agents/writer.md"); len(findings) != 0 {
+		t.Errorf("expected no findings, got %v", findings)
+	}
+}
+
+func TestValidateAgentFile_Frontmatter(t *testing.T) {
+	root := t.TempDir()
+	writeAgent(t, root, "missing.md", "# Agent\n\nInstructions")
+	writeAgent(t, root, "fields.md", "---\nname: fields\n---\nInstructions")
+	writeAgent(t, root, "model.md", "---\ndescription: d\nmodel: sonet\n---\nInstructions")
+	writeAgent(t, root, "unterminated.md", "---\nmodel: haiku\nInstructions")
+
+	tests := []struct {
+		file string
+		want []string
+	}{
+		{"missing.md", []string{"frontmatter"}},
+		{"fields.md", []string{"required-field", "required-field"}},
+		{"model.md", []string{"model"}},
+		{"unterminated.md", []string{"frontmatter"}},
+	}
+	for _, tt := range tests {
+		got := agentChecks(ValidateAgentFile(root, tt.file))
+		if len(got) != len(tt.want) {
+			t.Errorf("%s: expected %v, got %v", tt.file, tt.want, got)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("%s: expected %v, got %v", tt.file, tt.want, got)
+			}
+		}
+	}
+}
+
+func TestValidateAgentFile_BodyLint(t *testing.T) {
+	root := t.TempDir()
+	writeAgent(t, root, "agent.md", `---
+description: d
+model: haiku
+arguments:
+  - name: diff
+---
+# Agent
+
+See docs/[guide](docs/guide.md).
+
+## Rules
+## Examples
+
+Use {{diff}} and {{module}}.
+
+## rules
+
+Again.
+
+`+"```"+`
+unclosed
+`)
+
+	findings := ValidateAgentFile(root, "agent.md")
+	want := map[string]int{
+		"doc-reference":     9,
+		"empty-section":     11,
+		"placeholder":       14,
+		"duplicate-section": 16,
+		"code-block":        20,
+	}
+	if len(findings) != len(want) {
+		t.Fatalf("expected %d findings, got %v", len(want), findings)
+	}
+	for _, finding := range findings {
+		if line, ok := want[finding.Check]; !ok || line != finding.Line {
+			t.Errorf("unexpected finding %v", finding)
+		}
+	}
+	if !HasAgentErrors(findings) {
+		t.Error("expected errors")
+	}
+}
+
+func TestValidateAgentFile_EmptyBody(t *testing.T) {
+	root := t.TempDir()
+	writeAgent(t, root, "agent.md", "---\ndescription: d\nmodel: haiku\n---\n\n")
+
+	got := agentChecks(ValidateAgentFile(root, "agent.md"))
+	if len(got) != 1 || got[0] != "empty-body" {
+		t.Errorf("expected empty-body, got %v", got)
+	}
+}
+
+func TestValidateAgents_Pipeline(t *testing.T) {
+	root := t.TempDir()
+	writeAgent(t, root, ".claude/agents/generator.md", "---\ndescription: d\n---\nGenerate")
+	writeAgent(t, root, "agents/reviewer.md", "---\ndescription: d\n---\nReview")
+
+	pipeline := &PipelineConfig{Stages: []StageConfig{
+		{Name: "generator", Agent: ".claude/agents/generator.md"},
+		{Name: "reviewer", Agent: "agents/reviewer.md", Model: "haiku"},
+		{Name: "summary", Agent: ".claude/agents/summary.md"},
+	}}
+
+	report, err := ValidateAgents(root, pipeline)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// generator lacks a model; reviewer sets one in the stage
+	wantFiles := []string{".claude/agents/generator.md", "agents/reviewer.md"}
+	if len(report.Files) != len(wantFiles) || report.Files[0] != wantFiles[0] || report.Files[1] != wantFiles[1] {
+		t.Errorf("expected files %v, got %v", wantFiles, report.Files)
+	}
+	got := agentChecks(report.Findings)
+	if len(got) != 2 || got[0] != "required-field" || got[1] != "pipeline-agent" {
+		t.Errorf("unexpected findings %v", report.Findings)
+	}
+	if report.Valid || report.Errors != 2 {
+		t.Errorf("expected 2 errors, got %+v", report)
+	}
+
+	findings := ValidatePipelineAgents(root, pipeline)
+	if len(findings) != 2 {
+		t.Errorf("expected the pipeline check to report the same errors, got %v", findings)
+	}
+}
@@ -0,0 +1,54 @@
+package commitmessage
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/ready-to-release/eac/src/core/commitrules"
+)
+
+// largeCommitMessage returns a valid multi-module message with a section of
+// bodyLines lines, including a code block, per module
+func largeCommitMessage(modules, bodyLines int) (string, []string) {
+	var b strings.Builder
+	b.WriteString("# multi-module: feat: rework the module pipeline\n\n")
+	b.WriteString("Reworks how modules are built and released across the repository.\n\n")
+
+	var affected []string
+	for m := 0; m < modules; m++ {
+		module := fmt.Sprintf("module-%d", m)
+		affected = append(affected, module)
+		fmt.Fprintf(&b, "## %s\n\n%s: feat: update the build of module %d\n\n", module, module, m)
+		for l := 0; l < bodyLines; l++ {
+			if l == bodyLines/2 {
+				b.WriteString("```go\nfunc build() error {\n\treturn nil\n}\n```\n\n")
+			}
+			fmt.Fprintf(&b, "Line %d explains part of the change to %s in plain words.\n", l, module)
+		}
+		b.WriteString("\n---\n\n")
+	}
+	return b.String(), affected
+}
+
+func BenchmarkVerifyCommitMessage_Large(b *testing.B) {
+	message, affected := largeCommitMessage(20, 100)
+	b.SetBytes(int64(len(message)))
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		commitrules.VerifyCommitMessage(message, affected, nil)
+	}
+}
+
+func BenchmarkAutoCleanup_Large(b *testing.B) {
+	message, _ := largeCommitMessage(20, 100)
+	b.SetBytes(int64(len(message)))
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		AutoCleanup(message)
+	}
+}
@@ -0,0 +1,220 @@
+package commitmessage
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// DefaultContextTokenBudget is the default number of tokens an agent context may use
+const DefaultContextTokenBudget = 60000
+
+// charsPerToken is the rough number of characters per token used for estimation
+const charsPerToken = 4
+
+// markerReserve is the space kept for the "... [N more lines truncated]" marker
+const markerReserve = 40
+
+// EstimateTokens returns a rough token count for text (about 4 characters per token)
+func EstimateTokens(text string) int {
+	return (utf8.RuneCountInString(text) + charsPerToken - 1) / charsPerToken
+}
+
+// FileDiff is the diff of a single file
+type FileDiff struct {
+	Path    string
+	Header  []string // "diff --git", index and ---/+++ lines
+	Body    []string // hunk lines starting at the first "@@"
+	Added   int
+	Removed int
+	Binary  bool
+}
+
+// Text returns the file diff as it appeared in the full diff
+func (f FileDiff) Text() string {
+	lines := append(append([]string{}, f.Header...), f.Body...)
+	return strings.Join(lines, "\n")
+}
+
+// ParseDiff splits a unified git diff into per-file diffs
+func ParseDiff(diff string) []FileDiff {
+	var files []FileDiff
+	var current *FileDiff
+
+	for _, line := range strings.Split(diff, "\n") {
+		if strings.HasPrefix(line, "diff --git ") {
+			if current != nil {
+				files = append(files, *current)
+			}
+			current = &FileDiff{Path: diffPath(line), Header: []string{line}}
+			continue
+		}
+		if current == nil {
+			continue
+		}
+
+		if len(current.Body) == 0 && !strings.HasPrefix(line, "@@") {
+			current.Header = append(current.Header, line)
+			if strings.HasPrefix(line, "Binary files ") || line == "GIT binary patch" {
+				current.Binary = true
+			}
+			continue
+		}
+
+		current.Body = append(current.Body, line)
+		switch {
+		case strings.HasPrefix(line, "+"):
+			current.Added++
+		case strings.HasPrefix(line, "-"):
+			current.Removed++
+		}
+	}
+
+	if current != nil {
+		files = append(files, *current)
+	}
+
+	return files
+}
+
+// diffPath extracts the file path from "diff --git a/path b/path"
+func diffPath(header string) string {
+	parts := strings.Fields(header)
+	if len(parts) < 3 {
+		return ""
+	}
+	return strings.TrimPrefix(parts[2], "a/")
+}
+
+// FitDiff returns the diff unchanged when it fits the token budget. Otherwise it
+// returns per-file stats followed by the file diffs, where small files are kept
+// whole and large files are truncated to share the remaining budget.
+// The second return value reports whether anything was truncated.
+func FitDiff(diff string, maxTokens int) (string, bool) {
+	if maxTokens <= 0 || EstimateTokens(diff) <= maxTokens {
+		return diff, false
+	}
+
+	files := ParseDiff(diff)
+
+	var stats strings.Builder
+	stats.WriteString("# Diff truncated to fit the token budget. Per-file stats:\n")
+	for _, file := range files {
+		if file.Binary {
+			stats.WriteString(fmt.Sprintf("#   %s (binary)\n", file.Path))
+			continue
+		}
+		stats.WriteString(fmt.Sprintf("#   %s +%d -%d\n", file.Path, file.Added, file.Removed))
+	}
+
+	remaining := maxTokens*charsPerToken - len(stats.String())
+	if remaining < 0 || len(files) == 0 {
+		return strings.TrimRight(stats.String(), "\n"), true
+	}
+
+	// Give whole files to those below the fair share, then split the rest
+	// evenly between the large files
+	allowance := make([]int, len(files))
+	pending := make([]int, 0, len(files))
+	for i := range files {
+		pending = append(pending, i)
+	}
+	for len(pending) > 0 {
+		share := remaining / len(pending)
+		var large []int
+		for _, i := range pending {
+			size := len(files[i].Text()) + 1
+			if size <= share {
+				allowance[i] = size
+				remaining -= size
+			} else {
+				large = append(large, i)
+			}
+		}
+		if len(large) == len(pending) {
+			for _, i := range large {
+				allowance[i] = share
+			}
+			break
+		}
+		pending = large
+	}
+
+	var result strings.Builder
+	result.WriteString(stats.String())
+	for i, file := range files {
+		result.WriteString(truncateFileDiff(file, allowance[i]))
+		result.WriteString("\n")
+	}
+
+	return strings.TrimRight(result.String(), "\n"), true
+}
+
+// truncateFileDiff keeps the header and as many hunk lines as fit in maxChars
+func truncateFileDiff(file FileDiff, maxChars int) string {
+	text := file.Text()
+	if len(text)+1 <= maxChars {
+		return text
+	}
+
+	var result strings.Builder
+	for _, line := range file.Header {
+		result.WriteString(line + "\n")
+	}
+
+	kept := 0
+	for _, line := range file.Body {
+		if result.Len()+len(line)+1 > maxChars-markerReserve {
+			break
+		}
+		result.WriteString(line + "\n")
+		kept++
+	}
+
+	if omitted := len(file.Body) - kept; omitted > 0 {
+		result.WriteString(fmt.Sprintf("... [%d more lines truncated]", omitted))
+	}
+
+	return strings.TrimRight(result.String(), "\n")
+}
+
+// FitLines keeps as many leading lines of text as fit in maxTokens
+func FitLines(text string, maxTokens int) (string, bool) {
+	if maxTokens <= 0 || EstimateTokens(text) <= maxTokens {
+		return text, false
+	}
+
+	lines := strings.Split(text, "\n")
+	maxChars := maxTokens*charsPerToken - markerReserve
+
+	var result strings.Builder
+	kept := 0
+	for _, line := range lines {
+		if result.Len()+len(line)+1 > maxChars {
+			break
+		}
+		result.WriteString(line + "\n")
+		kept++
+	}
+	result.WriteString(fmt.Sprintf("... [%d more lines truncated]", len(lines)-kept))
+
+	return result.String(), true
+}
+
+// ContextBudget splits a token budget between the sections of an agent context
+type ContextBudget struct {
+	Table int // staged files table
+	Diff  int // git diff
+	File  int // diff of a single file, larger diffs are summarized
+}
+
+// NewContextBudget gives a tenth of the budget to the files table and the rest
+// to the diff, of which a single file may use a quarter
+func NewContextBudget(maxTokens int) ContextBudget {
+	diff := maxTokens - maxTokens/10
+	return ContextBudget{
+		Table: maxTokens / 10,
+		Diff:  diff,
+		File:  diff / 4,
+	}
+}
@@ -0,0 +1,72 @@
+package commitmessage
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func makeFileDiff(path string, lines int) string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("diff --git a/%s b/%s\n", path, path))
+	b.WriteString("index 1111111..2222222 100644\n")
+	b.WriteString(fmt.Sprintf("--- a/%s\n+++ b/%s\n", path, path))
+	b.WriteString(fmt.Sprintf("@@ -1,%d +1,%d @@\n", lines, lines))
+	for i := 0; i < lines; i++ {
+		b.WriteString(fmt.Sprintf("+added line %d with some content\n", i))
+	}
+	return b.String()
+}
+
+func TestParseDiff(t *testing.T) {
+	diff := makeFileDiff("a.go", 3) + "diff --git a/img.png b/img.png\nBinary files a/img.png and b/img.png differ\n"
+
+	files := ParseDiff(diff)
+	if len(files) != 2 {
+		t.Fatalf("expected 2 files, got %d", len(files))
+	}
+	if files[0].Path != "a.go" || files[0].Added != 3 || files[0].Removed != 0 {
+		t.Errorf("unexpected first file: %+v", files[0])
+	}
+	if !files[1].Binary {
+		t.Error("expected second file to be binary")
+	}
+}
+
+func TestFitDiff_UnchangedWithinBudget(t *testing.T) {
+	diff := makeFileDiff("a.go", 5)
+	got, truncated := FitDiff(diff, 10000)
+	if truncated || got != diff {
+		t.Error("expected diff within budget to be unchanged")
+	}
+}
+
+func TestFitDiff_TruncatesLargeFilesKeepsSmallFiles(t *testing.T) {
+	small := makeFileDiff("small.go", 2)
+	large := makeFileDiff("large.go", 5000)
+
+	got, truncated := FitDiff(large+small, 2000)
+	if !truncated {
+		t.Fatal("expected truncation")
+	}
+	if EstimateTokens(got) > 2000 {
+		t.Errorf("result exceeds budget: %d tokens", EstimateTokens(got))
+	}
+	if !strings.Contains(got, "large.go +5000 -0") {
+		t.Error("expected per-file stats for large.go")
+	}
+	if !strings.Contains(got, strings.TrimRight(small, "\n")) {
+		t.Error("expected small file to be kept whole")
+	}
+	if !strings.Contains(got, "more lines truncated]") {
+		t.Error("expected truncation marker")
+	}
+}
+
+func TestFitLines(t *testing.T) {
+	text := strings.Repeat("| file.go | module |\n", 1000)
+	got, truncated := FitLines(text, 100)
+	if !truncated || EstimateTokens(got) > 110 {
+		t.Errorf("expected truncated text near budget, got %d tokens", EstimateTokens(got))
+	}
+}
@@ -0,0 +1,158 @@
+package commitmessage
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// FileClass tells how the diff of a file is embedded in agent contexts
+type FileClass string
+
+const (
+	FileClassNormal    FileClass = ""          // diff embedded as is
+	FileClassBinary    FileClass = "binary"    // no text diff
+	FileClassGenerated FileClass = "generated" // lockfiles, snapshots, linguist-generated
+	FileClassOversize  FileClass = "oversize"  // diff larger than the per-file budget
+)
+
+// maxSummaryHunks caps the hunk headers listed in the summary of an oversize file
+const maxSummaryHunks = 20
+
+// generatedPatterns match the names of files that are generated without a
+// .gitattributes entry
+var generatedPatterns = []string{
+	"go.sum",
+	"package-lock.json",
+	"npm-shrinkwrap.json",
+	"yarn.lock",
+	"pnpm-lock.yaml",
+	"Cargo.lock",
+	"poetry.lock",
+	"Pipfile.lock",
+	"Gemfile.lock",
+	"composer.lock",
+	"*.snap",
+}
+
+// generatedDirs hold generated files only
+var generatedDirs = []string{"__snapshots__"}
+
+// IsGeneratedPath reports whether a file is generated by naming convention
+func IsGeneratedPath(filePath string) bool {
+	name := path.Base(filePath)
+	for _, pattern := range generatedPatterns {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	for _, dir := range strings.Split(path.Dir(filePath), "/") {
+		for _, generated := range generatedDirs {
+			if dir == generated {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ClassifyDiff classifies each file of diff. generated holds the files marked
+// linguist-generated in .gitattributes: true marks a file generated, false
+// overrides the naming conventions. Files whose diff exceeds maxFileTokens are
+// oversize; zero disables the limit.
+func ClassifyDiff(diff string, generated map[string]bool, maxFileTokens int) map[string]FileClass {
+	classes := make(map[string]FileClass)
+	for _, file := range ParseDiff(diff) {
+		classes[file.Path] = classifyFile(file, generated, maxFileTokens)
+	}
+	return classes
+}
+
+func classifyFile(file FileDiff, generated map[string]bool, maxFileTokens int) FileClass {
+	if file.Binary {
+		return FileClassBinary
+	}
+	if marked, ok := generated[file.Path]; ok {
+		if marked {
+			return FileClassGenerated
+		}
+	} else if IsGeneratedPath(file.Path) {
+		return FileClassGenerated
+	}
+	if maxFileTokens > 0 && EstimateTokens(file.Text()) > maxFileTokens {
+		return FileClassOversize
+	}
+	return FileClassNormal
+}
+
+// SummarizeDiff replaces the diffs of binary, generated and oversize files with
+// short summaries. The "diff --git" line is kept so the summaries still belong
+// to their modules.
+func SummarizeDiff(diff string, classes map[string]FileClass) string {
+	return SummarizeDiffWith(diff, classes, nil)
+}
+
+// SummarizeDiffWith is SummarizeDiff using the generated summaries of
+// oversize files, keyed by path, instead of their hunk lists
+func SummarizeDiffWith(diff string, classes map[string]FileClass, summaries map[string]string) string {
+	var result strings.Builder
+	for _, file := range ParseDiff(diff) {
+		if result.Len() > 0 {
+			result.WriteString("\n")
+		}
+		class := classes[file.Path]
+		if class == FileClassNormal {
+			result.WriteString(strings.TrimRight(file.Text(), "\n"))
+			continue
+		}
+		result.WriteString(file.Header[0] + "\n")
+		result.WriteString(summarizeFile(file, class, summaries[file.Path]))
+	}
+	return result.String()
+}
+
+// summarizeFile describes a file diff in a few "#" lines, using the generated
+// summary of an oversize file when there is one
+func summarizeFile(file FileDiff, class FileClass, generated string) string {
+	var summary strings.Builder
+	switch class {
+	case FileClassBinary:
+		summary.WriteString(fmt.Sprintf("# %s: binary file changed (diff omitted)", file.Path))
+	case FileClassGenerated:
+		summary.WriteString(fmt.Sprintf("# %s: generated file, +%d -%d lines (diff omitted)", file.Path, file.Added, file.Removed))
+	default:
+		if generated != "" {
+			summary.WriteString(fmt.Sprintf("# %s: large diff, +%d -%d lines (diff omitted). Summary:", file.Path, file.Added, file.Removed))
+			for i, line := range strings.Split(generated, "\n") {
+				if i == maxSummaryLines {
+					summary.WriteString("\n#   ...")
+					break
+				}
+				summary.WriteString("\n#   " + strings.TrimSpace(line))
+			}
+			break
+		}
+		summary.WriteString(fmt.Sprintf("# %s: large diff, +%d -%d lines (diff omitted). Hunks:", file.Path, file.Added, file.Removed))
+		hunks := 0
+		for _, line := range file.Body {
+			if !strings.HasPrefix(line, "@@") {
+				continue
+			}
+			if hunks == maxSummaryHunks {
+				summary.WriteString("\n#   ...")
+				break
+			}
+			summary.WriteString("\n#   " + line)
+			hunks++
+		}
+	}
+	return summary.String()
+}
+
+// FileLabel returns the file name annotated with its class for file tables
+func FileLabel(name string, class FileClass) string {
+	if class == FileClassNormal {
+		return name
+	}
+	return fmt.Sprintf("%s (%s)", name, class)
+}
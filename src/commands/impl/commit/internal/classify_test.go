@@ -0,0 +1,86 @@
+package commitmessage
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestIsGeneratedPath(t *testing.T) {
+	for path, want := range map[string]bool{
+		"src/cli/go.sum":                     true,
+		"web/package-lock.json":              true,
+		"ui/__snapshots__/button.test.js.ap": true,
+		"ui/button.test.js.snap":             true,
+		"src/cli/go.mod":                     false,
+		"docs/yarn.lock.md":                  false,
+	} {
+		if got := IsGeneratedPath(path); got != want {
+			t.Errorf("IsGeneratedPath(%q) = %v, want %v", path, got, want)
+		}
+	}
+}
+
+func TestClassifyDiff(t *testing.T) {
+	diff := makeFileDiff("main.go", 3) +
+		makeFileDiff("go.sum", 3) +
+		makeFileDiff("api.pb.go", 3) +
+		makeFileDiff("vendor.sum", 3) +
+		makeFileDiff("big.go", 200) +
+		"diff --git a/img.png b/img.png\nBinary files a/img.png and b/img.png differ\n"
+
+	generated := map[string]bool{"api.pb.go": true, "go.sum": false}
+	classes := ClassifyDiff(diff, generated, 500)
+
+	want := map[string]FileClass{
+		"main.go":    FileClassNormal,
+		"go.sum":     FileClassNormal, // linguist-generated=false overrides the convention
+		"api.pb.go":  FileClassGenerated,
+		"vendor.sum": FileClassNormal,
+		"big.go":     FileClassOversize,
+		"img.png":    FileClassBinary,
+	}
+	for path, class := range want {
+		if classes[path] != class {
+			t.Errorf("%s: class %q, want %q", path, classes[path], class)
+		}
+	}
+}
+
+func TestSummarizeDiff(t *testing.T) {
+	diff := makeFileDiff("main.go", 2) + makeFileDiff("package-lock.json", 500)
+	classes := ClassifyDiff(diff, nil, 0)
+
+	summary := SummarizeDiff(diff, classes)
+	if !strings.Contains(summary, "+added line 1 with some content") {
+		t.Error("expected normal file diff to be kept")
+	}
+	if strings.Count(summary, "added line") != 2 {
+		t.Errorf("expected generated file diff to be omitted, got:\n%s", summary)
+	}
+	if !strings.Contains(summary, "diff --git a/package-lock.json b/package-lock.json\n# package-lock.json: generated file, +500 -0 lines (diff omitted)") {
+		t.Errorf("expected generated file summary, got:\n%s", summary)
+	}
+
+	files := ParseDiff(summary)
+	if len(files) != 2 || files[1].Path != "package-lock.json" {
+		t.Errorf("expected summaries to stay attributable to their files, got %+v", files)
+	}
+}
+
+func TestSummarizeDiff_OversizeListsHunks(t *testing.T) {
+	diff := makeFileDiff("big.go", 300)
+	summary := SummarizeDiff(diff, map[string]FileClass{"big.go": FileClassOversize})
+
+	if !strings.Contains(summary, "large diff, +300 -0 lines") || !strings.Contains(summary, "#   @@ -1,300 +1,300 @@") {
+		t.Errorf("unexpected oversize summary:\n%s", summary)
+	}
+}
+
+func TestFileLabel(t *testing.T) {
+	if got := FileLabel("go.sum", FileClassGenerated); got != "go.sum (generated)" {
+		t.Errorf("unexpected label %q", got)
+	}
+	if got := FileLabel("main.go", FileClassNormal); got != "main.go" {
+		t.Errorf("unexpected label %q", got)
+	}
+}
@@ -3,13 +3,25 @@ package commitmessage
 import (
 	"regexp"
 	"strings"
+
+	"github.com/ready-to-release/eac/src/commands/internal/markdownlint"
+	"github.com/ready-to-release/eac/src/core/commitrules"
 )
 
+// lintOptions configures the markdownlint pass for commit messages.
+// Wrapping (MD013) is done by fixContent, which keeps subject lines separate,
+// and the trailing blank line (MD047) is handled by AutoCleanup itself.
+var lintOptions = markdownlint.Options{
+	LineLength: 72,
+	Disabled:   []string{"MD013", "MD047"},
+}
+
 // AutoCleanup performs automatic fixes on commit message before validation
 // This catches common issues that can be fixed programmatically without AI
 func AutoCleanup(commitMessage string) string {
-	// PHASE 1: Normalize all spacing and blank lines first
+	// PHASE 1: Normalize line endings, spacing and blank lines first
 	// This creates a stable foundation for content fixes
+	commitMessage = strings.ReplaceAll(commitMessage, "\r\n", "\n")
 	lines := normalizeSpacing(commitMessage)
 
 	// PHASE 2: Fix content (titles, subject lines, body wrapping)
@@ -19,13 +31,19 @@ func AutoCleanup(commitMessage string) string {
 	result := strings.Join(lines, "\n")
 	result = ensureCodeBlocksClosed(result)
 
+	// PHASE 4: Markdown formatting (heading/fence spacing, single H1, tables)
+	result = markdownlint.Fix(result, lintOptions)
+
 	// Remove trailing separators and blank lines
-	result = strings.TrimRight(result, "\n\t ")
+	result = strings.TrimRight(result, "\r\n\t ")
 
-	// Remove trailing --- separator if present
-	for strings.HasSuffix(strings.TrimRight(result, "\n\t "), "---") {
-		result = strings.TrimSuffix(strings.TrimRight(result, "\n\t "), "---")
-		result = strings.TrimRight(result, "\n\t ")
+	// Remove trailing --- separator lines if present
+	for {
+		last := strings.LastIndex(result, "\n")
+		if strings.TrimSpace(result[last+1:]) != "---" {
+			break
+		}
+		result = strings.TrimRight(result[:last+1], "\r\n\t ")
 	}
 
 	// Ensure file ends with exactly one blank line
@@ -41,13 +59,13 @@ func normalizeSpacing(commitMessage string) []string {
 	lines := strings.Split(commitMessage, "\n")
 	normalized := make([]string, 0, len(lines))
 
-	for i, line := range lines {
+	for _, line := range lines {
 		trimmed := strings.TrimSpace(line)
 
-		// Skip duplicate blank lines
+		// Skip leading and duplicate blank lines, so the title is the first line
 		if trimmed == "" {
 			// Keep blank line if previous line wasn't blank
-			if i == 0 || len(normalized) == 0 || strings.TrimSpace(normalized[len(normalized)-1]) != "" {
+			if len(normalized) > 0 && strings.TrimSpace(normalized[len(normalized)-1]) != "" {
 				normalized = append(normalized, "")
 			}
 			continue
@@ -69,9 +87,23 @@ func fixContent(lines []string) []string {
 	lastWasModuleHeader := false
 	needBlankLineAfterCodeBlock := false
 
+	// flushBody reflows the buffered body text; it must run before anything
+	// else is appended, or the body text would move after it
+	flushBody := func() {
+		if len(bodyBuffer) > 0 {
+			cleaned = append(cleaned, wrapBodyText(bodyBuffer)...)
+			bodyBuffer = []string{}
+		}
+	}
+
 	for i, line := range lines {
 		trimmed := strings.TrimSpace(line)
 
+		// Blank lines end a paragraph and fences end body text
+		if trimmed == "" || strings.HasPrefix(trimmed, "```") {
+			flushBody()
+		}
+
 		// If we need a blank line after code block and this is non-empty content, add it
 		if needBlankLineAfterCodeBlock && trimmed != "" && !strings.HasPrefix(trimmed, "```") {
 			if len(cleaned) > 0 && strings.TrimSpace(cleaned[len(cleaned)-1]) != "" {
@@ -102,15 +134,15 @@ func fixContent(lines []string) []string {
 		// FIX 1: Truncate title to 72 chars with ellipsis if needed
 		if i == 0 && strings.HasPrefix(trimmed, "# ") {
 			title := strings.TrimPrefix(trimmed, "# ")
-			if len("# "+title) > 72 {
-				// Truncate to 69 chars to leave room for "..."
-				title = title[:66]
+			if commitrules.DisplayWidth("# "+title) > 72 {
+				// Truncate to 69 columns to leave room for "..."
+				title = truncateToWidth(title, 66)
 				// Remove any trailing spaces, periods, or punctuation before adding ellipsis
 				title = strings.TrimRight(title, " .")
 				title = title + "..."
 			} else {
 				// Only remove trailing period if NOT truncated (no ellipsis)
-				title = strings.TrimSuffix(title, ".")
+				title = trimTrailingPeriod(title)
 			}
 			line = "# " + title
 			cleaned = append(cleaned, line)
@@ -119,17 +151,24 @@ func fixContent(lines []string) []string {
 			continue
 		}
 
+		// Later titles are module headers; demote them here rather than in the
+		// markdown pass (MD025), so they get the module header fixes
+		if i > 0 && !inCodeBlock && strings.HasPrefix(trimmed, "# ") {
+			trimmed = "#" + trimmed
+			line = trimmed
+		}
+
 		// FIX 2: CUT module headers at 72 chars, remove trailing periods
 		if strings.HasPrefix(trimmed, "## ") {
 			moduleName := strings.TrimPrefix(trimmed, "## ")
-			if len("## "+moduleName) > 72 {
-				// CUT to 69 chars to leave room for "..."
-				moduleName = moduleName[:66]
+			if commitrules.DisplayWidth("## "+moduleName) > 72 {
+				// CUT to 69 columns to leave room for "..."
+				moduleName = truncateToWidth(moduleName, 66)
 				moduleName = strings.TrimRight(moduleName, " .")
 				moduleName = moduleName + "..."
 			} else {
 				// Just remove trailing period
-				moduleName = strings.TrimSuffix(moduleName, ".")
+				moduleName = trimTrailingPeriod(moduleName)
 			}
 			line = "## " + moduleName
 		}
@@ -162,10 +201,10 @@ func fixContent(lines []string) []string {
 			}
 
 			// Remove trailing period
-			subjectLine = strings.TrimSuffix(subjectLine, ".")
+			subjectLine = trimTrailingPeriod(subjectLine)
 
 			// WRAP if too long (don't truncate semantic commits)
-			if len(subjectLine) > 72 {
+			if commitrules.DisplayWidth(subjectLine) > 72 {
 				wrapped := wrapSemanticCommitLine(subjectLine)
 				cleaned = append(cleaned, wrapped...)
 			} else {
@@ -183,10 +222,11 @@ func fixContent(lines []string) []string {
 
 		if subjectRegex.MatchString(trimmed) {
 			// Remove trailing period
-			line = strings.TrimSuffix(strings.TrimSpace(line), ".")
+			line = trimTrailingPeriod(strings.TrimSpace(line))
 
-			// WRAP if too long (don't truncate semantic commits)
-			if len(line) > 72 {
+			// WRAP if too long (don't truncate semantic commits). Inside body
+			// text the line is wrapped with the rest of its paragraph.
+			if commitrules.DisplayWidth(line) > 72 && (!inBodySection || inCodeBlock) {
 				wrapped := wrapSemanticCommitLine(line)
 				cleaned = append(cleaned, wrapped...)
 				continue
@@ -198,10 +238,7 @@ func fixContent(lines []string) []string {
 		if strings.HasPrefix(trimmed, "## ") {
 			lastWasModuleHeader = true
 			// Flush any buffered body text from previous section
-			if len(bodyBuffer) > 0 {
-				cleaned = append(cleaned, wrapBodyText(bodyBuffer)...)
-				bodyBuffer = []string{}
-			}
+			flushBody()
 			inBodySection = true
 
 			// Ensure exactly one blank line before section header (except for first header)
@@ -219,10 +256,7 @@ func fixContent(lines []string) []string {
 		// Detect end of body section
 		if inBodySection && (trimmed == "---" || strings.HasPrefix(trimmed, "```")) {
 			// Flush buffered body text
-			if len(bodyBuffer) > 0 {
-				cleaned = append(cleaned, wrapBodyText(bodyBuffer)...)
-				bodyBuffer = []string{}
-			}
+			flushBody()
 			inBodySection = false
 
 			// Add blank line before divider if needed
@@ -235,7 +269,7 @@ func fixContent(lines []string) []string {
 		}
 
 		// Buffer body text lines (ensuring blank line after header)
-		if inBodySection && !inCodeBlock && trimmed != "" && !strings.HasPrefix(trimmed, "|") {
+		if inBodySection && !inCodeBlock && trimmed != "" && !strings.HasPrefix(trimmed, "|") && !strings.HasPrefix(trimmed, "#") {
 			// If this is the first body text after a header, ensure blank line separator
 			if lastWasModuleHeader {
 				// Add blank line after header if not already present
@@ -257,89 +291,42 @@ func fixContent(lines []string) []string {
 		if trimmed != "" {
 			lastWasModuleHeader = false
 		}
+		flushBody()
 		cleaned = append(cleaned, line)
 	}
 
 	// Flush any remaining body text
-	if len(bodyBuffer) > 0 {
-		cleaned = append(cleaned, wrapBodyText(bodyBuffer)...)
-	}
+	flushBody()
 
 	return cleaned
 }
 
-// wrapSemanticCommitLine wraps a semantic commit line at 72 characters
+// trimTrailingPeriod removes trailing periods but keeps an ellipsis "...",
+// such as the one added when truncating, so cleanup is stable when repeated
+func trimTrailingPeriod(text string) string {
+	if strings.HasSuffix(text, "...") {
+		return text
+	}
+	return strings.TrimRight(text, ".")
+}
+
+// wrapSemanticCommitLine wraps a semantic commit line at 72 columns
 // Preserves the format: <module>: <type>: <description>
 func wrapSemanticCommitLine(line string) []string {
-	if len(line) <= 72 {
+	if commitrules.DisplayWidth(line) <= 72 {
 		return []string{line}
 	}
-
-	// Split at 72 chars and wrap the rest with proper indentation
-	var wrapped []string
-	currentLine := ""
-	words := strings.Fields(line)
-
-	for _, word := range words {
-		testLine := currentLine
-		if testLine != "" {
-			testLine += " "
-		}
-		testLine += word
-
-		if len(testLine) <= 72 {
-			currentLine = testLine
-		} else {
-			// Flush current line
-			if currentLine != "" {
-				wrapped = append(wrapped, currentLine)
-			}
-			currentLine = word
-		}
-	}
-
-	// Add remaining line
-	if currentLine != "" {
-		wrapped = append(wrapped, currentLine)
-	}
-
+	// The first line is read as the subject line when the message is cleaned
+	// again, so it must not end with a period either
+	wrapped := wrapText(line, 72)
+	wrapped[0] = trimTrailingPeriod(wrapped[0])
 	return wrapped
 }
 
-// wrapBodyText joins buffered lines and reflows at 72 characters
+// wrapBodyText joins buffered lines and reflows at 72 columns. Text without
+// spaces, such as Chinese or Japanese, breaks between wide characters.
 func wrapBodyText(lines []string) []string {
-	// Join all lines into one paragraph
-	paragraph := strings.Join(lines, " ")
-
-	// Split into sentences/phrases and wrap at 72 chars
-	var wrapped []string
-	var currentLine string
-
-	words := strings.Fields(paragraph)
-	for _, word := range words {
-		testLine := currentLine
-		if testLine != "" {
-			testLine += " "
-		}
-		testLine += word
-
-		if len(testLine) <= 72 {
-			currentLine = testLine
-		} else {
-			// Current word would exceed limit, flush current line
-			if currentLine != "" {
-				wrapped = append(wrapped, currentLine)
-			}
-			currentLine = word
-		}
-	}
-
-	// Add remaining line
-	if currentLine != "" {
-		wrapped = append(wrapped, currentLine)
-	}
-
-	return wrapped
+	return wrapText(joinWrappedLines(lines), 72)
 }
 
 // ensureCodeBlocksClosed adds missing closing fences
@@ -0,0 +1,57 @@
+package commitmessage
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAutoCleanup_FixesMarkdown(t *testing.T) {
+	input := "\n# src-commands: feat: add verifier\nThis commit adds a verifier.   \n# src-commands\n\nsrc-commands: feat: add verifier\n\n| File | Module |\n|---|---|\n| src/commands/main.go | src-commands |\n"
+
+	got := AutoCleanup(input)
+
+	if !strings.HasPrefix(got, "# src-commands: feat: add verifier\n\nThis commit adds a verifier.\n") {
+		t.Errorf("expected title followed by blank line and trimmed body, got:\n%s", got)
+	}
+	if !strings.Contains(got, "\n## src-commands\n") {
+		t.Errorf("expected second H1 demoted to module header, got:\n%s", got)
+	}
+	if !strings.Contains(got, "| File                 | Module       |") {
+		t.Errorf("expected aligned table, got:\n%s", got)
+	}
+	if !strings.HasSuffix(got, "|\n\n") {
+		t.Errorf("expected message to end with one blank line, got %q", got[len(got)-10:])
+	}
+}
+
+func TestAutoCleanup_Idempotent(t *testing.T) {
+	input := "# src-cli: fix: handle empty config\n\nHandles an empty configuration file without panicking during load.\n\n## src-cli\n\nsrc-cli: fix: handle empty config\n\n```go\nif len(data) == 0 {\n    return nil\n}\n```\n"
+
+	once := AutoCleanup(input)
+	if twice := AutoCleanup(once); once != twice {
+		t.Errorf("AutoCleanup is not idempotent:\n%q\n%q", once, twice)
+	}
+}
+
+func TestAutoCleanup_KeepsParagraphs(t *testing.T) {
+	input := "# src-cli: fix: handle empty config\n\nFirst paragraph.\n\nSecond paragraph\nwrapped by the agent.\n"
+
+	got := AutoCleanup(input)
+
+	want := "# src-cli: fix: handle empty config\n\nFirst paragraph.\n\nSecond paragraph wrapped by the agent.\n\n"
+	if got != want {
+		t.Errorf("expected paragraphs to stay separate:\n%q\ngot:\n%q", want, got)
+	}
+}
+
+func TestAutoCleanup_KeepsTruncatedTitleEllipsis(t *testing.T) {
+	input := "# src-cli: feat: " + strings.Repeat("word ", 20) + "\n\nBody.\n"
+
+	once := AutoCleanup(input)
+	if !strings.Contains(once, "...\n") {
+		t.Fatalf("expected truncated title with ellipsis, got %q", once)
+	}
+	if twice := AutoCleanup(once); twice != once {
+		t.Errorf("ellipsis changed on second cleanup:\n%q\n%q", once, twice)
+	}
+}
@@ -0,0 +1,276 @@
+package commitmessage
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/ready-to-release/eac/contracts"
+	"github.com/xeipuuv/gojsonschema"
+	"gopkg.in/yaml.v3"
+)
+
+// CommitConfigFile is the optional commit-ai configuration, relative to the repository root
+const CommitConfigFile = ".r2r/commit.yml"
+
+// CommitConfig configures how messages are generated, finished and committed.
+// The file is validated against contracts/commit-config/<version>/schema.json.
+type CommitConfig struct {
+	Trailers  TrailerConfig `yaml:"trailers"`
+	Issues    IssueConfig   `yaml:"issues"`
+	Sign      *bool         `yaml:"sign"`     // sign commits made by commit-ai; unset follows commit.gpgSign
+	Language  string        `yaml:"language"` // language of the summary and body text (default English)
+	Docs      DocsConfig    `yaml:"docs"`
+	Summaries SummaryConfig `yaml:"summaries"` // generated summaries of oversize file diffs
+
+	Format      string                   `yaml:"format"`      // default for --format (default "text")
+	Parallelism int                      `yaml:"parallelism"` // default for --concurrency (0 = per stage)
+	Timeout     time.Duration            `yaml:"timeout"`     // limit for runs started by the MCP commands server
+	Stages      map[string]StageOverride `yaml:"stages"`      // overrides for pipeline stages, by stage name
+}
+
+// StageOverride replaces settings of a pipeline stage; zero values keep the
+// stage's own
+type StageOverride struct {
+	Model       string        `yaml:"model"`
+	Timeout     time.Duration `yaml:"timeout"`
+	Parallelism int           `yaml:"parallelism"` // module-scope stages only
+}
+
+// TrailerConfig declares the trailers appended to generated messages
+type TrailerConfig struct {
+	SignedOffBy bool          `yaml:"signed_off_by"` // Signed-off-by with user.name and user.email
+	ReviewedBy  []string      `yaml:"reviewed_by"`   // one Reviewed-by per entry
+	Ticket      *TicketConfig `yaml:"ticket"`        // ticket ID taken from the branch name
+	Custom      []Trailer     `yaml:"custom"`        // fixed trailers
+}
+
+// TicketConfig extracts a ticket ID from the branch name
+type TicketConfig struct {
+	Pattern string `yaml:"pattern"` // regexp; the first group, or the whole match, is the ID
+	Key     string `yaml:"key"`     // trailer key (default "Refs")
+}
+
+// Trailer is a "Key: value" line at the end of a commit message
+type Trailer struct {
+	Key   string `yaml:"key"`
+	Value string `yaml:"value"`
+}
+
+func (t Trailer) String() string {
+	return t.Key + ": " + t.Value
+}
+
+// trailerKeyPattern matches valid git trailer keys
+var trailerKeyPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9-]*$`)
+
+// LoadCommitConfig reads .r2r/commit.yml, returning an empty config when it does not exist
+func LoadCommitConfig(workspaceRoot string) (*CommitConfig, error) {
+	var config CommitConfig
+
+	data, err := os.ReadFile(filepath.Join(workspaceRoot, CommitConfigFile))
+	if os.IsNotExist(err) {
+		return &config, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read commit config: %w", err)
+	}
+
+	var document interface{}
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("failed to parse commit config YAML: %w", err)
+	}
+	if err := validateCommitConfigSchema(document); err != nil {
+		return nil, fmt.Errorf("invalid commit config %s: %w", CommitConfigFile, err)
+	}
+
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse commit config YAML: %w", err)
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid commit config %s: %w", CommitConfigFile, err)
+	}
+
+	return &config, nil
+}
+
+// validateCommitConfigSchema checks a parsed commit config against the
+// embedded contract schema, reporting every violation
+func validateCommitConfigSchema(document interface{}) error {
+	if document == nil {
+		document = map[string]interface{}{}
+	}
+	schema := gojsonschema.NewStringLoader(contracts.CommitConfigSchema())
+	result, err := gojsonschema.Validate(schema, gojsonschema.NewGoLoader(document))
+	if err != nil {
+		return fmt.Errorf("schema validation failed: %w", err)
+	}
+	if result.Valid() {
+		return nil
+	}
+
+	var violations []string
+	for _, violation := range result.Errors() {
+		violations = append(violations, violation.Field()+": "+violation.Description())
+	}
+	return fmt.Errorf("%s", strings.Join(violations, "; "))
+}
+
+// Validate checks the language, trailer, issue, docs, summary and pipeline
+// declarations and fills in defaults
+func (c *CommitConfig) Validate() error {
+	if err := c.Issues.validate(); err != nil {
+		return err
+	}
+	c.Language = strings.TrimSpace(c.Language)
+	if err := ValidateLanguage(c.Language); err != nil {
+		return err
+	}
+	if err := c.Docs.validate(); err != nil {
+		return err
+	}
+	if err := c.Summaries.validate(); err != nil {
+		return err
+	}
+
+	if ticket := c.Trailers.Ticket; ticket != nil {
+		if ticket.Pattern == "" {
+			return fmt.Errorf("trailers.ticket: pattern is required")
+		}
+		if _, err := regexp.Compile(ticket.Pattern); err != nil {
+			return fmt.Errorf("trailers.ticket: invalid pattern: %w", err)
+		}
+		if ticket.Key == "" {
+			ticket.Key = "Refs"
+		}
+		if !trailerKeyPattern.MatchString(ticket.Key) {
+			return fmt.Errorf("trailers.ticket: invalid key %q", ticket.Key)
+		}
+	}
+
+	for i, trailer := range c.Trailers.Custom {
+		if !trailerKeyPattern.MatchString(trailer.Key) {
+			return fmt.Errorf("trailers.custom[%d]: invalid key %q", i, trailer.Key)
+		}
+		if strings.TrimSpace(trailer.Value) == "" {
+			return fmt.Errorf("trailers.custom[%d]: value is required", i)
+		}
+	}
+
+	if c.Format == "" {
+		c.Format = "text"
+	}
+	if c.Format != "text" && c.Format != "json" {
+		return fmt.Errorf("format: must be text or json")
+	}
+	if c.Parallelism < 0 {
+		return fmt.Errorf("parallelism: must not be negative")
+	}
+	if c.Timeout < 0 {
+		return fmt.Errorf("timeout: must not be negative")
+	}
+	for name, stage := range c.Stages {
+		if stage.Parallelism < 0 {
+			return fmt.Errorf("stages.%s: parallelism must not be negative", name)
+		}
+		if stage.Timeout < 0 {
+			return fmt.Errorf("stages.%s: timeout must not be negative", name)
+		}
+	}
+
+	return nil
+}
+
+// ApplyToPipeline sets the configured parallelism of the module-scope stages,
+// then the stage overrides. A stage that the pipeline does not declare is an
+// error, so a renamed stage is not silently left at its defaults.
+func (c *CommitConfig) ApplyToPipeline(pipeline *PipelineConfig) error {
+	if c.Parallelism > 0 {
+		pipeline.SetModuleParallelism(c.Parallelism)
+	}
+
+	for name, override := range c.Stages {
+		stage := pipeline.stage(name)
+		if stage == nil {
+			return fmt.Errorf("%s: stages.%s: no such pipeline stage (stages: %s)", CommitConfigFile, name, strings.Join(pipeline.stageNames(), ", "))
+		}
+		if override.Model != "" {
+			stage.Model = override.Model
+		}
+		if override.Timeout > 0 {
+			stage.Timeout = override.Timeout
+		}
+		if override.Parallelism > 0 {
+			if stage.Scope != ScopeModule {
+				return fmt.Errorf("%s: stages.%s: parallelism applies to module-scope stages only", CommitConfigFile, name)
+			}
+			stage.Parallelism = override.Parallelism
+		}
+	}
+	return nil
+}
+
+// BuildTrailers returns the configured trailers for a commit by identity
+// ("Name <email>") on branch. Signed-off-by comes last, as git places it.
+func (c TrailerConfig) BuildTrailers(identity string, branch string) []Trailer {
+	trailers := append([]Trailer{}, c.Custom...)
+
+	for _, reviewer := range c.ReviewedBy {
+		trailers = append(trailers, Trailer{Key: "Reviewed-by", Value: reviewer})
+	}
+
+	if c.Ticket != nil {
+		if id := TicketFromBranch(branch, c.Ticket.Pattern); id != "" {
+			trailers = append(trailers, Trailer{Key: c.Ticket.Key, Value: id})
+		}
+	}
+
+	if c.SignedOffBy && identity != "" {
+		trailers = append(trailers, Trailer{Key: "Signed-off-by", Value: identity})
+	}
+
+	return trailers
+}
+
+// TicketFromBranch extracts a ticket ID from a branch name: the first group
+// of pattern, or the whole match when it has no groups
+func TicketFromBranch(branch string, pattern string) string {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return ""
+	}
+	match := re.FindStringSubmatch(branch)
+	if match == nil {
+		return ""
+	}
+	if len(match) > 1 && match[1] != "" {
+		return match[1]
+	}
+	return match[0]
+}
+
+// AppendTrailers adds the trailers missing from message in a block after a blank line
+func AppendTrailers(message string, trailers []Trailer) string {
+	existing := make(map[string]bool)
+	for _, line := range strings.Split(message, "\n") {
+		existing[strings.TrimSpace(line)] = true
+	}
+
+	var lines []string
+	for _, trailer := range trailers {
+		line := trailer.String()
+		if !existing[line] {
+			existing[line] = true
+			lines = append(lines, line)
+		}
+	}
+	if len(lines) == 0 {
+		return message
+	}
+
+	return strings.TrimRight(message, "\n \t") + "\n\n" + strings.Join(lines, "\n")
+}
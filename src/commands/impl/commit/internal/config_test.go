@@ -0,0 +1,178 @@
+package commitmessage
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeCommitConfig(t *testing.T, content string) string {
+	root := t.TempDir()
+	path := filepath.Join(root, CommitConfigFile)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return root
+}
+
+func TestLoadCommitConfig_Missing(t *testing.T) {
+	config, err := LoadCommitConfig(t.TempDir())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if config.Sign != nil || len(config.Trailers.BuildTrailers("A <a@b>", "main")) != 0 {
+		t.Errorf("expected empty config, got %+v", config)
+	}
+}
+
+func TestLoadCommitConfig_Trailers(t *testing.T) {
+	root := writeCommitConfig(t, `
+sign: true
+trailers:
+  signed_off_by: true
+  reviewed_by: ["Jane Doe <jane@example.com>"]
+  ticket:
+    pattern: '(?i)\b([A-Z]+-\d+)'
+  custom:
+    - key: Change-Type
+      value: feature
+`)
+	config, err := LoadCommitConfig(root)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if config.Sign == nil || !*config.Sign {
+		t.Error("expected sign: true")
+	}
+
+	trailers := config.Trailers.BuildTrailers("Dev <dev@example.com>", "feature/ABC-123-stats")
+	var lines []string
+	for _, trailer := range trailers {
+		lines = append(lines, trailer.String())
+	}
+	want := "Change-Type: feature|Reviewed-by: Jane Doe <jane@example.com>|Refs: ABC-123|Signed-off-by: Dev <dev@example.com>"
+	if got := strings.Join(lines, "|"); got != want {
+		t.Errorf("trailers = %s\nwant %s", got, want)
+	}
+}
+
+func TestLoadCommitConfig_Invalid(t *testing.T) {
+	for _, content := range []string{
+		"trailers:\n  ticket:\n    pattern: '('\n",
+		"trailers:\n  ticket:\n    key: Refs\n",
+		"trailers:\n  custom:\n    - key: 'Bad Key'\n      value: x\n",
+		"trailers:\n  custom:\n    - key: Empty\n",
+		"unknown: true\n",
+		"format: yaml\n",
+		"parallelism: 0\n",
+		"timeout: soon\n",
+		"timeout: 0\n",
+		"stages:\n  module:\n    agent: other.md\n",
+	} {
+		if _, err := LoadCommitConfig(writeCommitConfig(t, content)); err == nil {
+			t.Errorf("expected error for:\n%s", content)
+		}
+	}
+}
+
+func TestAppendTrailers(t *testing.T) {
+	message := "# cli: feat: add stats\n\nBody\n\nRefs: ABC-1\n"
+	trailers := []Trailer{{Key: "Refs", Value: "ABC-1"}, {Key: "Signed-off-by", Value: "Dev <dev@example.com>"}}
+
+	got := AppendTrailers(message, trailers)
+	want := "# cli: feat: add stats\n\nBody\n\nRefs: ABC-1\n\nSigned-off-by: Dev <dev@example.com>"
+	if got != want {
+		t.Errorf("unexpected message:\n%s", got)
+	}
+	if AppendTrailers(got, trailers) != got {
+		t.Error("expected appending the same trailers again to be a no-op")
+	}
+}
+
+func TestTicketFromBranch(t *testing.T) {
+	if got := TicketFromBranch("fix/PROJ-42-crash", `[A-Z]+-\d+`); got != "PROJ-42" {
+		t.Errorf("unexpected ticket %q", got)
+	}
+	if got := TicketFromBranch("main", `[A-Z]+-\d+`); got != "" {
+		t.Errorf("expected no ticket, got %q", got)
+	}
+}
+
+func TestLoadCommitConfig_Issues(t *testing.T) {
+	config, err := LoadCommitConfig(writeCommitConfig(t, "issues:\n  enabled: true\n  close: true\n  close_keyword: fixes\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !config.Issues.Enabled || config.Issues.CloseKeyword != "Fixes" {
+		t.Errorf("unexpected issue config: %+v", config.Issues)
+	}
+
+	if _, err := LoadCommitConfig(writeCommitConfig(t, "issues:\n  close_keyword: ends\n")); err == nil {
+		t.Error("expected error for an unknown closing keyword")
+	}
+}
+
+func TestLoadCommitConfig_Pipeline(t *testing.T) {
+	root := writeCommitConfig(t, `
+format: json
+parallelism: 2
+timeout: 10m
+stages:
+  top-level:
+    model: opus
+    timeout: 90s
+  module:
+    model: haiku
+    parallelism: 8
+`)
+	config, err := LoadCommitConfig(root)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if config.Format != "json" || config.Parallelism != 2 || config.Timeout != 10*time.Minute {
+		t.Errorf("unexpected config: %+v", config)
+	}
+
+	pipeline := DefaultPipeline()
+	if err := config.ApplyToPipeline(pipeline); err != nil {
+		t.Fatalf("ApplyToPipeline() error = %v", err)
+	}
+	topLevel, module := pipeline.Stages[0], pipeline.Stages[1]
+	if topLevel.Model != "opus" || topLevel.Timeout != 90*time.Second {
+		t.Errorf("top-level stage = %+v", topLevel)
+	}
+	// The stage override wins over the top-level parallelism
+	if module.Model != "haiku" || module.Parallelism != 8 {
+		t.Errorf("module stage = %+v", module)
+	}
+}
+
+func TestCommitConfig_ApplyToPipeline_Invalid(t *testing.T) {
+	for _, content := range []string{
+		"stages:\n  reviewer:\n    model: haiku\n",
+		"stages:\n  top-level:\n    parallelism: 2\n",
+	} {
+		config, err := LoadCommitConfig(writeCommitConfig(t, content))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := config.ApplyToPipeline(DefaultPipeline()); err == nil {
+			t.Errorf("expected error for:\n%s", content)
+		}
+	}
+}
+
+func TestLoadCommitConfig_DefaultFormat(t *testing.T) {
+	config, err := LoadCommitConfig(writeCommitConfig(t, "timeout: 0s\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if config.Format != "text" || config.Timeout != 0 {
+		t.Errorf("unexpected config: %+v", config)
+	}
+}
@@ -0,0 +1,219 @@
+package commitmessage
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Defaults for documentation selection
+const (
+	DefaultMaxDocs     = 3
+	DefaultDocMaxBytes = 16000
+)
+
+// DocsConfig adds the documentation most relevant to the staged changes to
+// the top-level agent context
+type DocsConfig struct {
+	Enabled  bool     `yaml:"enabled"`
+	Paths    []string `yaml:"paths"`     // markdown files or directories searched (default "docs")
+	MaxDocs  int      `yaml:"max_docs"`  // most relevant docs included (default 3)
+	MaxBytes int      `yaml:"max_bytes"` // total size of the included docs (default 16000)
+}
+
+// validate checks the limits and fills in defaults
+func (c *DocsConfig) validate() error {
+	if c.MaxDocs < 0 {
+		return fmt.Errorf("docs.max_docs: must not be negative")
+	}
+	if c.MaxBytes < 0 {
+		return fmt.Errorf("docs.max_bytes: must not be negative")
+	}
+	if c.MaxDocs == 0 {
+		c.MaxDocs = DefaultMaxDocs
+	}
+	if c.MaxBytes == 0 {
+		c.MaxBytes = DefaultDocMaxBytes
+	}
+	if len(c.Paths) == 0 {
+		c.Paths = []string{"docs"}
+	}
+	return nil
+}
+
+// DocCandidate is a scored documentation file
+type DocCandidate struct {
+	Path     string // relative to the repository root
+	Score    int
+	Bytes    int
+	Included bool
+	Reason   string // why a relevant doc was left out
+	content  string
+}
+
+// docTermStopWords are path and module name parts that match too many docs
+var docTermStopWords = map[string]bool{
+	"src": true, "internal": true, "impl": true, "cmd": true, "pkg": true,
+	"go": true, "md": true, "yml": true, "yaml": true, "json": true,
+	"test": true, "tests": true, "main": true, "index": true, "readme": true,
+	"the": true, "and": true, "docs": true,
+}
+
+// Score weights of a term found in the doc path, a heading or the body
+const (
+	docPathWeight    = 5
+	docHeadingWeight = 3
+	docBodyMaxHits   = 5
+)
+
+// DocTerms returns the search terms for the affected modules and changed
+// paths: lowercase name parts of three or more characters
+func DocTerms(modules []string, paths []string) []string {
+	seen := make(map[string]bool)
+	var terms []string
+	for _, value := range append(append([]string{}, modules...), paths...) {
+		for _, part := range splitDocTerms(value) {
+			if len(part) < 3 || docTermStopWords[part] || seen[part] {
+				continue
+			}
+			seen[part] = true
+			terms = append(terms, part)
+		}
+	}
+	sort.Strings(terms)
+	return terms
+}
+
+func splitDocTerms(value string) []string {
+	return strings.FieldsFunc(strings.ToLower(value), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// SelectDocs scores the markdown files under config.Paths against terms and
+// marks the most relevant ones, up to MaxDocs within MaxBytes, as included.
+// Returns every doc that matched a term, most relevant first.
+func SelectDocs(workspaceRoot string, config DocsConfig, terms []string) ([]DocCandidate, error) {
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+	if len(terms) == 0 {
+		return nil, nil
+	}
+
+	var candidates []DocCandidate
+	seen := make(map[string]bool)
+	for _, root := range config.Paths {
+		err := filepath.WalkDir(filepath.Join(workspaceRoot, root), func(path string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				if os.IsNotExist(err) {
+					return nil
+				}
+				return err
+			}
+			if entry.IsDir() || !strings.EqualFold(filepath.Ext(path), ".md") {
+				return nil
+			}
+			rel, err := filepath.Rel(workspaceRoot, path)
+			if err != nil {
+				return err
+			}
+			rel = filepath.ToSlash(rel)
+			if seen[rel] {
+				return nil
+			}
+			seen[rel] = true
+
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			if score := scoreDoc(rel, string(data), terms); score > 0 {
+				candidates = append(candidates, DocCandidate{Path: rel, Score: score, Bytes: len(data), content: string(data)})
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to read docs in %s: %w", root, err)
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].Score != candidates[j].Score {
+			return candidates[i].Score > candidates[j].Score
+		}
+		return candidates[i].Path < candidates[j].Path
+	})
+
+	included, remaining := 0, config.MaxBytes
+	for i := range candidates {
+		candidate := &candidates[i]
+		switch {
+		case included >= config.MaxDocs:
+			candidate.Reason = "below top " + fmt.Sprint(config.MaxDocs)
+		case candidate.Bytes > remaining:
+			candidate.Reason = "over byte budget"
+		default:
+			candidate.Included = true
+			included++
+			remaining -= candidate.Bytes
+		}
+	}
+
+	return candidates, nil
+}
+
+// scoreDoc counts the terms in the path, headings and body of a doc. Body
+// hits are capped per term so long docs do not win by size alone.
+func scoreDoc(path string, content string, terms []string) int {
+	pathParts := make(map[string]bool)
+	for _, part := range splitDocTerms(path) {
+		pathParts[part] = true
+	}
+
+	var headings, body []string
+	for _, line := range strings.Split(strings.ToLower(content), "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "#") {
+			headings = append(headings, line)
+		} else {
+			body = append(body, line)
+		}
+	}
+	headingText := strings.Join(headings, "\n")
+	bodyWords := make(map[string]int)
+	for _, word := range splitDocTerms(strings.Join(body, "\n")) {
+		bodyWords[word]++
+	}
+
+	score := 0
+	for _, term := range terms {
+		if pathParts[term] {
+			score += docPathWeight
+		}
+		if strings.Contains(headingText, term) {
+			score += docHeadingWeight
+		}
+		score += min(bodyWords[term], docBodyMaxHits)
+	}
+	return score
+}
+
+// RenderDocs renders the included docs as a context section, or "" when
+// none is included
+func RenderDocs(candidates []DocCandidate) string {
+	var section strings.Builder
+	for _, candidate := range candidates {
+		if !candidate.Included {
+			continue
+		}
+		if section.Len() == 0 {
+			section.WriteString("## Relevant Documentation\n\n")
+		}
+		section.WriteString(fmt.Sprintf("### %s\n\n%s\n\n", candidate.Path, strings.TrimSpace(candidate.content)))
+	}
+	return section.String()
+}
@@ -0,0 +1,101 @@
+package commitmessage
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeDoc(t *testing.T, root, rel, content string) {
+	t.Helper()
+	path := filepath.Join(root, rel)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDocTerms(t *testing.T) {
+	terms := DocTerms([]string{"src-mcp-commands"}, []string{"src/mcp/commands/cancel.go", "docs/index.md"})
+	want := "cancel,commands,mcp"
+	if got := strings.Join(terms, ","); got != want {
+		t.Errorf("DocTerms = %s, want %s", got, want)
+	}
+}
+
+func TestSelectDocs_RanksByRelevance(t *testing.T) {
+	root := t.TempDir()
+	writeDoc(t, root, "docs/mcp/cancellation.md", "# Cancellation\n\nCancel MCP tool calls.")
+	writeDoc(t, root, "docs/mcp/overview.md", "# Overview\n\nThe MCP servers.")
+	writeDoc(t, root, "docs/release.md", "# Release\n\nTagging modules.")
+	writeDoc(t, root, "docs/notes.txt", "mcp cancel")
+
+	docs, err := SelectDocs(root, DocsConfig{}, []string{"cancel", "cancellation", "mcp"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var paths []string
+	for _, doc := range docs {
+		paths = append(paths, doc.Path)
+		if !doc.Included {
+			t.Errorf("expected %s to be included", doc.Path)
+		}
+	}
+	if got := strings.Join(paths, ","); got != "docs/mcp/cancellation.md,docs/mcp/overview.md" {
+		t.Errorf("unexpected selection order %s", got)
+	}
+
+	section := RenderDocs(docs)
+	if !strings.HasPrefix(section, "## Relevant Documentation\n\n### docs/mcp/cancellation.md\n\n# Cancellation") {
+		t.Errorf("unexpected section:\n%s", section)
+	}
+}
+
+func TestSelectDocs_Limits(t *testing.T) {
+	root := t.TempDir()
+	writeDoc(t, root, "docs/a-cache.md", "# Cache\n\ncache cache cache")
+	writeDoc(t, root, "docs/b-cache.md", "# Cache\n\ncache "+strings.Repeat("x", 200))
+	writeDoc(t, root, "docs/c-cache.md", "cache")
+
+	docs, err := SelectDocs(root, DocsConfig{MaxDocs: 2, MaxBytes: 100}, []string{"cache"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(docs) != 3 {
+		t.Fatalf("expected 3 candidates, got %+v", docs)
+	}
+
+	want := map[string]string{
+		"docs/a-cache.md": "",
+		"docs/b-cache.md": "over byte budget",
+		"docs/c-cache.md": "",
+	}
+	for _, doc := range docs {
+		if doc.Reason != want[doc.Path] || doc.Included != (want[doc.Path] == "") {
+			t.Errorf("unexpected selection of %s: %+v", doc.Path, doc)
+		}
+	}
+
+	docs, _ = SelectDocs(root, DocsConfig{MaxDocs: 1}, []string{"cache"})
+	if !docs[0].Included || docs[1].Reason != "below top 1" {
+		t.Errorf("expected only the top doc, got %+v", docs)
+	}
+}
+
+func TestSelectDocs_NoTermsOrDocs(t *testing.T) {
+	docs, err := SelectDocs(t.TempDir(), DocsConfig{Paths: []string{"missing"}}, []string{"cache"})
+	if err != nil || len(docs) != 0 {
+		t.Errorf("expected no docs, got %v, %v", docs, err)
+	}
+	if RenderDocs(docs) != "" {
+		t.Error("expected an empty section")
+	}
+
+	if _, err := SelectDocs(t.TempDir(), DocsConfig{MaxDocs: -1}, []string{"cache"}); err == nil {
+		t.Error("expected an error for a negative max_docs")
+	}
+}
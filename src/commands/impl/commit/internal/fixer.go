@@ -3,10 +3,12 @@ package commitmessage
 import (
 	"bytes"
 	"fmt"
+
+	"github.com/ready-to-release/eac/src/core/commitrules"
 )
 
 // FixWithFeedback attempts to fix validation errors by feeding them back to Claude
-func FixWithFeedback(agentFilePath string, originalPrompt string, commitMessage string, validationErrors []ValidationError, apiCaller func(string, string) (string, error)) (string, error) {
+func FixWithFeedback(agentFilePath string, originalPrompt string, commitMessage string, validationErrors []commitrules.ValidationError, apiCaller func(string, string) (string, error)) (string, error) {
 	if len(validationErrors) == 0 {
 		return commitMessage, nil
 	}
@@ -31,14 +33,14 @@ func FixWithFeedback(agentFilePath string, originalPrompt string, commitMessage
 }
 
 // buildValidationFeedback creates a feedback prompt from validation errors
-func buildValidationFeedback(commitMessage string, errors []ValidationError) string {
+func buildValidationFeedback(commitMessage string, errors []commitrules.ValidationError) string {
 	var feedback bytes.Buffer
 
 	feedback.WriteString("⚠️  CONTRACT VALIDATION FAILED\n\n")
 	feedback.WriteString("The commit message you generated has the following violations:\n\n")
 
 	// Group by severity
-	var errorList, warningList []ValidationError
+	var errorList, warningList []commitrules.ValidationError
 	for _, err := range errors {
 		if err.Severity == "error" {
 			errorList = append(errorList, err)
@@ -0,0 +1,108 @@
+package commitmessage
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// IssueConfig links generated messages to GitHub issues
+type IssueConfig struct {
+	Enabled      bool   `yaml:"enabled"`       // detect issue references and fetch their titles with gh
+	Close        bool   `yaml:"close"`         // add a closing keyword for the issues of the branch
+	CloseKeyword string `yaml:"close_keyword"` // GitHub closing keyword (default "Closes")
+}
+
+// Issue is a referenced issue; Title is empty when it could not be fetched
+type Issue struct {
+	Number int
+	Title  string
+}
+
+// closeKeywords are the keywords GitHub closes issues for
+var closeKeywords = []string{"Close", "Closes", "Closed", "Fix", "Fixes", "Fixed", "Resolve", "Resolves", "Resolved"}
+
+// branchIssuePattern matches issue numbers in branch names such as
+// "123-fix-crash", "feature/issue-123" or "fix/gh-123-crash"
+var branchIssuePattern = regexp.MustCompile(`(?i)(?:^|/)(?:issue-?|gh-?|#)?(\d+)(?:[-_]|$)`)
+
+// diffIssuePattern matches explicit issue references in added lines:
+// "refs #123", "fixes #123" or ".../issues/123" URLs
+var diffIssuePattern = regexp.MustCompile(`(?i)(?:\b(?:refs?|see|close[sd]?|fix(?:e[sd])?|resolve[sd]?)[: ]+#(\d+)\b|github\.com/[\w.-]+/[\w.-]+/issues/(\d+))`)
+
+// validate checks the closing keyword and fills in the default
+func (c *IssueConfig) validate() error {
+	if c.CloseKeyword == "" {
+		c.CloseKeyword = "Closes"
+	}
+	for _, keyword := range closeKeywords {
+		if strings.EqualFold(keyword, c.CloseKeyword) {
+			c.CloseKeyword = keyword
+			return nil
+		}
+	}
+	return fmt.Errorf("issues.close_keyword: %q is not a GitHub closing keyword (%s)", c.CloseKeyword, strings.Join(closeKeywords, ", "))
+}
+
+// IssuesFromBranch returns the issue numbers in a branch name
+func IssuesFromBranch(branch string) []int {
+	var numbers []int
+	for _, match := range branchIssuePattern.FindAllStringSubmatch(branch, -1) {
+		numbers = append(numbers, atoiDefault(match[1], 0))
+	}
+	return uniqueIssueNumbers(numbers)
+}
+
+// IssuesFromDiff returns the issues referenced in the added lines of a diff
+func IssuesFromDiff(diff string) []int {
+	var numbers []int
+	for _, line := range strings.Split(diff, "\n") {
+		if !strings.HasPrefix(line, "+") || strings.HasPrefix(line, "+++") {
+			continue
+		}
+		for _, match := range diffIssuePattern.FindAllStringSubmatch(line, -1) {
+			number := match[1]
+			if number == "" {
+				number = match[2]
+			}
+			numbers = append(numbers, atoiDefault(number, 0))
+		}
+	}
+	return uniqueIssueNumbers(numbers)
+}
+
+// IssueTrailers returns a "Refs: #123 <title>" trailer per issue and, when
+// closing is configured, a closing keyword trailer for each issue in closes
+func (c IssueConfig) IssueTrailers(issues []Issue, closes []int) []Trailer {
+	var trailers []Trailer
+	for _, issue := range issues {
+		value := "#" + strconv.Itoa(issue.Number)
+		if issue.Title != "" {
+			value += " " + issue.Title
+		}
+		trailers = append(trailers, Trailer{Key: "Refs", Value: value})
+	}
+
+	if c.Close {
+		for _, number := range closes {
+			trailers = append(trailers, Trailer{Key: c.CloseKeyword, Value: "#" + strconv.Itoa(number)})
+		}
+	}
+
+	return trailers
+}
+
+func uniqueIssueNumbers(numbers []int) []int {
+	var unique []int
+	seen := make(map[int]bool)
+	for _, n := range numbers {
+		if n > 0 && !seen[n] {
+			seen[n] = true
+			unique = append(unique, n)
+		}
+	}
+	sort.Ints(unique)
+	return unique
+}
@@ -0,0 +1,54 @@
+package commitmessage
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestIssuesFromBranch(t *testing.T) {
+	for branch, want := range map[string]string{
+		"123-fix-crash":          "[123]",
+		"feature/issue-45-stats": "[45]",
+		"fix/gh-7":               "[7]",
+		"feature/ABC-123-stats":  "[]",
+		"main":                   "[]",
+	} {
+		if got := fmt.Sprint(IssuesFromBranch(branch)); got != want {
+			t.Errorf("IssuesFromBranch(%q) = %s, want %s", branch, got, want)
+		}
+	}
+}
+
+func TestIssuesFromDiff(t *testing.T) {
+	diff := strings.Join([]string{
+		"+++ b/main.go",
+		"+// Fixes #12 and refs #3",
+		"+// See https://github.com/org/repo/issues/40",
+		"+color: #123;",
+		"-// refs #99",
+		" // refs #98",
+	}, "\n")
+	if got := fmt.Sprint(IssuesFromDiff(diff)); got != "[3 12 40]" {
+		t.Errorf("IssuesFromDiff = %s, want [3 12 40]", got)
+	}
+}
+
+func TestIssueTrailers(t *testing.T) {
+	config := IssueConfig{Enabled: true, Close: true, CloseKeyword: "Closes"}
+	trailers := config.IssueTrailers([]Issue{{Number: 12, Title: "Crash on empty log"}, {Number: 40}}, []int{12})
+
+	var lines []string
+	for _, trailer := range trailers {
+		lines = append(lines, trailer.String())
+	}
+	want := "Refs: #12 Crash on empty log|Refs: #40|Closes: #12"
+	if got := strings.Join(lines, "|"); got != want {
+		t.Errorf("trailers = %s\nwant %s", got, want)
+	}
+
+	config.Close = false
+	if len(config.IssueTrailers([]Issue{{Number: 12}}, []int{12})) != 1 {
+		t.Error("expected no closing keyword when close is off")
+	}
+}
@@ -0,0 +1,135 @@
+package commitmessage
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/ready-to-release/eac/src/core/commitrules"
+)
+
+// languagePattern accepts language names and tags such as "German", "de" or "pt-BR"
+var languagePattern = regexp.MustCompile(`^\p{L}[\p{L} _-]{0,39}$`)
+
+// ValidateLanguage checks a language from the flag, tool argument or config
+func ValidateLanguage(language string) error {
+	if language == "" || languagePattern.MatchString(language) {
+		return nil
+	}
+	return fmt.Errorf("language: %q is not a language name or tag", language)
+}
+
+// IsEnglish reports whether language is empty or English, which needs no instruction
+func IsEnglish(language string) bool {
+	language = strings.ToLower(strings.TrimSpace(language))
+	return language == "" || language == "english" || language == "en" || strings.HasPrefix(language, "en-") || strings.HasPrefix(language, "en_")
+}
+
+// LanguageInstruction is appended to agent contexts so the prose is written in
+// language while the parsed lines stay English. Empty for English.
+func LanguageInstruction(language string) string {
+	if IsEnglish(language) {
+		return ""
+	}
+	return fmt.Sprintf(`## Language
+
+Write the summary and body text in %s.
+Keep these in English, exactly as the format requires:
+- the title line: # <module|multi-module>: <type>: <summary>
+- module headers: ## <module>
+- module subject lines: <module>: <type>: <description>
+- code, identifiers, file paths and trailers
+`, language)
+}
+
+// truncateToWidth cuts text to at most width columns without splitting a character
+func truncateToWidth(text string, width int) string {
+	used := 0
+	for i, r := range text {
+		w := commitrules.DisplayWidth(string(r))
+		if used+w > width {
+			return text[:i]
+		}
+		used += w
+	}
+	return text
+}
+
+// wrapUnit is a piece of text that is never split when wrapping
+type wrapUnit struct {
+	text        string
+	spaceBefore bool
+}
+
+// wrapUnits splits text at spaces, and between wide characters, which need
+// no space to break a line (CJK text)
+func wrapUnits(text string) []wrapUnit {
+	var units []wrapUnit
+	for _, word := range strings.Fields(text) {
+		spaceBefore := true
+		var run strings.Builder
+		flush := func() {
+			if run.Len() > 0 {
+				units = append(units, wrapUnit{text: run.String(), spaceBefore: spaceBefore})
+				spaceBefore = false
+				run.Reset()
+			}
+		}
+		for _, r := range word {
+			if commitrules.IsWideRune(r) {
+				flush()
+				units = append(units, wrapUnit{text: string(r), spaceBefore: spaceBefore})
+				spaceBefore = false
+				continue
+			}
+			run.WriteRune(r)
+		}
+		flush()
+	}
+	return units
+}
+
+// wrapText fills lines up to width display columns
+func wrapText(text string, width int) []string {
+	var wrapped []string
+	var current string
+	for _, unit := range wrapUnits(text) {
+		candidate := current
+		if candidate != "" && unit.spaceBefore {
+			candidate += " "
+		}
+		candidate += unit.text
+
+		if commitrules.DisplayWidth(candidate) <= width || current == "" {
+			current = candidate
+			continue
+		}
+		wrapped = append(wrapped, current)
+		current = unit.text
+	}
+	if current != "" {
+		wrapped = append(wrapped, current)
+	}
+	return wrapped
+}
+
+// joinWrappedLines joins lines into a paragraph, without a space between
+// lines that break inside wide (CJK) text
+func joinWrappedLines(lines []string) string {
+	var paragraph strings.Builder
+	var last rune
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		first := []rune(line)[0]
+		if paragraph.Len() > 0 && !(commitrules.IsWideRune(last) && commitrules.IsWideRune(first)) {
+			paragraph.WriteString(" ")
+		}
+		paragraph.WriteString(line)
+		runes := []rune(line)
+		last = runes[len(runes)-1]
+	}
+	return paragraph.String()
+}
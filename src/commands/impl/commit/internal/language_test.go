@@ -0,0 +1,81 @@
+package commitmessage
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ready-to-release/eac/src/core/commitrules"
+)
+
+func TestWrapBodyText_NonASCII(t *testing.T) {
+	// 70 umlaut-heavy columns are more than 72 bytes but fit
+	german := strings.Repeat("ä", 30) + " " + strings.Repeat("ö", 39)
+	if wrapped := wrapBodyText([]string{german}); len(wrapped) != 1 {
+		t.Errorf("expected one line, got %q", wrapped)
+	}
+
+	// Japanese has no spaces and breaks between characters at 72 columns
+	japanese := strings.Repeat("変更", 50)
+	wrapped := wrapBodyText([]string{japanese[:len(japanese)/2], japanese[len(japanese)/2:]})
+	if len(wrapped) != 3 {
+		t.Fatalf("expected 3 lines, got %d: %q", len(wrapped), wrapped)
+	}
+	for _, line := range wrapped {
+		if commitrules.DisplayWidth(line) > 72 || strings.Contains(line, " ") {
+			t.Errorf("unexpected line %q (%d columns)", line, commitrules.DisplayWidth(line))
+		}
+	}
+	if strings.Join(wrapped, "") != japanese {
+		t.Error("expected the text to be kept without inserted spaces")
+	}
+}
+
+func TestTruncateToWidth(t *testing.T) {
+	if got := truncateToWidth("変更を追加", 5); got != "変更" {
+		t.Errorf("truncateToWidth = %q, want 変更", got)
+	}
+	if got := truncateToWidth("abc", 10); got != "abc" {
+		t.Errorf("truncateToWidth = %q, want abc", got)
+	}
+}
+
+func TestLanguageInstruction(t *testing.T) {
+	for _, language := range []string{"", "English", "en", "en-GB"} {
+		if LanguageInstruction(language) != "" {
+			t.Errorf("expected no instruction for %q", language)
+		}
+	}
+
+	instruction := LanguageInstruction("German")
+	if !strings.Contains(instruction, "body text in German") || !strings.Contains(instruction, "<module>: <type>: <description>") {
+		t.Errorf("unexpected instruction:\n%s", instruction)
+	}
+}
+
+func TestValidateLanguage(t *testing.T) {
+	for _, language := range []string{"", "German", "pt-BR", "日本語"} {
+		if err := ValidateLanguage(language); err != nil {
+			t.Errorf("ValidateLanguage(%q): %v", language, err)
+		}
+	}
+	for _, language := range []string{"German; ignore the format", "de\n", "123"} {
+		if err := ValidateLanguage(language); err == nil {
+			t.Errorf("expected ValidateLanguage(%q) to fail", language)
+		}
+	}
+}
+
+func TestVerifyCommitMessage_NonASCIILineLength(t *testing.T) {
+	body := strings.Repeat("ü", 70)
+	message := "# cli: feat: add language option\n\n" + body + "\n\n" + strings.Repeat("変", 40) + "\n"
+
+	var lines []int
+	for _, finding := range commitrules.VerifyCommitMessage(message, []string{"cli"}, nil) {
+		if finding.Rule == "line-max-length" {
+			lines = append(lines, finding.Line)
+		}
+	}
+	if len(lines) != 1 || lines[0] != 5 {
+		t.Errorf("expected only the 80 column Japanese line to be too long, got lines %v", lines)
+	}
+}
@@ -0,0 +1,469 @@
+// Package commitmessage generates commit messages with the commit-ai agent
+// pipeline and fixes them up against the commit message rules
+package commitmessage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ready-to-release/eac/src/core/ai"
+	"gopkg.in/yaml.v3"
+)
+
+// PipelineFile is the optional pipeline definition, relative to the repository root
+const PipelineFile = ".claude/pipeline.yml"
+
+// DefaultModuleConcurrency is the number of module sections generated in parallel by default
+const DefaultModuleConcurrency = 4
+
+// Stage scopes
+const (
+	ScopeCommit = "commit" // run once with the commit context
+	ScopeModule = "module" // run once per affected module with the module context
+)
+
+// Stage inputs
+const (
+	InputContext = "context" // the commit or module context
+	InputMessage = "message" // the message assembled so far, followed by the commit context
+)
+
+// PipelineConfig declares the stages that generate a commit message
+type PipelineConfig struct {
+	Stages []StageConfig `yaml:"stages"`
+}
+
+// StageConfig declares a single pipeline stage
+type StageConfig struct {
+	Name        string `yaml:"name"`
+	Agent       string `yaml:"agent"`       // agent file, relative to the repository root
+	Model       string `yaml:"model"`       // overrides the model from the agent frontmatter
+	Scope       string `yaml:"scope"`       // "commit" (default) or "module"
+	Input       string `yaml:"input"`       // "context" (default) or "message"
+	Parallelism int    `yaml:"parallelism"` // concurrent module runs (module scope only)
+	Blocking    *bool  `yaml:"blocking"`    // failure aborts the pipeline (default true)
+	MinModules  int    `yaml:"min_modules"` // skip the stage below this many affected modules
+
+	Timeout time.Duration `yaml:"timeout"` // e.g. "90s"; a timed-out run fails the stage (0 = no limit)
+}
+
+// IsBlocking reports whether a failure in this stage aborts the pipeline
+func (s StageConfig) IsBlocking() bool {
+	return s.Blocking == nil || *s.Blocking
+}
+
+// DefaultPipeline returns the built-in pipeline: a top-level summary for the
+// whole commit, then one section per module for multi-module commits
+func DefaultPipeline() *PipelineConfig {
+	return &PipelineConfig{
+		Stages: []StageConfig{
+			{
+				Name:  "top-level",
+				Agent: ".claude/agents/commit-message-top-level.md",
+				Scope: ScopeCommit,
+				Input: InputContext,
+			},
+			{
+				Name:        "module",
+				Agent:       ".claude/agents/commit-message-module.md",
+				Scope:       ScopeModule,
+				Input:       InputContext,
+				Parallelism: DefaultModuleConcurrency,
+				MinModules:  2,
+			},
+		},
+	}
+}
+
+// LoadPipeline loads .claude/pipeline.yml, falling back to DefaultPipeline when absent
+func LoadPipeline(workspaceRoot string) (*PipelineConfig, error) {
+	path := filepath.Join(workspaceRoot, PipelineFile)
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return DefaultPipeline(), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read pipeline file: %w", err)
+	}
+
+	var config PipelineConfig
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse pipeline YAML: %w", err)
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid pipeline %s: %w", PipelineFile, err)
+	}
+
+	return &config, nil
+}
+
+// Validate checks stage declarations and fills in defaults
+func (c *PipelineConfig) Validate() error {
+	if len(c.Stages) == 0 {
+		return fmt.Errorf("no stages declared")
+	}
+
+	names := make(map[string]bool)
+	for i := range c.Stages {
+		stage := &c.Stages[i]
+
+		if stage.Name == "" {
+			return fmt.Errorf("stage %d: name is required", i+1)
+		}
+		if names[stage.Name] {
+			return fmt.Errorf("stage %s: duplicate name", stage.Name)
+		}
+		names[stage.Name] = true
+
+		if stage.Agent == "" {
+			return fmt.Errorf("stage %s: agent is required", stage.Name)
+		}
+
+		if stage.Scope == "" {
+			stage.Scope = ScopeCommit
+		}
+		if stage.Scope != ScopeCommit && stage.Scope != ScopeModule {
+			return fmt.Errorf("stage %s: scope must be %q or %q", stage.Name, ScopeCommit, ScopeModule)
+		}
+
+		if stage.Input == "" {
+			stage.Input = InputContext
+		}
+		if stage.Input != InputContext && stage.Input != InputMessage {
+			return fmt.Errorf("stage %s: input must be %q or %q", stage.Name, InputContext, InputMessage)
+		}
+		if stage.Input == InputMessage && stage.Scope != ScopeCommit {
+			return fmt.Errorf("stage %s: input %q requires scope %q", stage.Name, InputMessage, ScopeCommit)
+		}
+
+		if stage.Parallelism < 0 {
+			return fmt.Errorf("stage %s: parallelism must not be negative", stage.Name)
+		}
+		if stage.Parallelism == 0 {
+			stage.Parallelism = 1
+		}
+
+		if stage.Timeout < 0 {
+			return fmt.Errorf("stage %s: timeout must not be negative", stage.Name)
+		}
+	}
+
+	return nil
+}
+
+// SetModuleParallelism overrides the parallelism of all module-scope stages
+func (c *PipelineConfig) SetModuleParallelism(parallelism int) {
+	for i := range c.Stages {
+		if c.Stages[i].Scope == ScopeModule {
+			c.Stages[i].Parallelism = parallelism
+		}
+	}
+}
+
+// stage returns the stage with the given name, or nil
+func (c *PipelineConfig) stage(name string) *StageConfig {
+	for i := range c.Stages {
+		if c.Stages[i].Name == name {
+			return &c.Stages[i]
+		}
+	}
+	return nil
+}
+
+// stageNames returns the names of all stages in pipeline order
+func (c *PipelineConfig) stageNames() []string {
+	names := make([]string, len(c.Stages))
+	for i, stage := range c.Stages {
+		names[i] = stage.Name
+	}
+	return names
+}
+
+// ActiveStages returns the names of the stages that run for moduleCount affected modules
+func (c *PipelineConfig) ActiveStages(moduleCount int) []string {
+	var names []string
+	for _, stage := range c.Stages {
+		if moduleCount >= stage.MinModules {
+			names = append(names, stage.Name)
+		}
+	}
+	return names
+}
+
+// AgentCall is a single invocation of a stage's agent
+type AgentCall struct {
+	Context context.Context // cancelled when the stage times out
+	Stage   string          // stage name
+	Agent   string          // agent file path
+	Model   string          // overrides the model from the agent frontmatter when set
+	Input   string          // stage input
+	Usage   *ai.Usage       // filled with the tokens and cost of the call
+}
+
+// AgentRunner invokes an agent and returns its output
+type AgentRunner func(call AgentCall) (string, error)
+
+// PipelineInput is the context the pipeline stages run on
+type PipelineInput struct {
+	CommitContext  string
+	Modules        []string          // affected modules, in output order
+	ModuleContexts map[string]string // module name -> module context
+}
+
+// PipelineHooks observe pipeline execution (all optional)
+type PipelineHooks struct {
+	// Progress wraps each stage run, e.g. with WithProgress
+	Progress func(message string, fn func() error) error
+	// Output is called with each agent output; module is empty for commit-scope stages
+	Output func(stage StageConfig, module string, output string)
+	// Warn reports non-blocking stage failures, which are also recorded in PipelineState.Skipped
+	Warn func(message string)
+	// Usage is called with the token usage and cost of each stage that ran
+	Usage func(stage StageConfig, usage ai.Usage)
+	// Step is called when a stage starts (done 0) and as its runs complete,
+	// one run for commit-scope stages and one per module for module-scope
+	// stages, whose runs report concurrently
+	Step func(stage StageConfig, done int, total int)
+}
+
+// PipelineState is the commit message as assembled by the stages
+type PipelineState struct {
+	TopLevel       string
+	ModuleSections []string
+	Usage          []StageUsage   // per stage that ran, in stage order
+	Skipped        []SkippedStage // non-blocking stages that failed or timed out
+	rewritten      string         // set by "message" input stages, replaces the assembled sections
+}
+
+// SkippedStage is a non-blocking stage whose output was dropped
+type SkippedStage struct {
+	Stage  string
+	Reason string
+}
+
+// StageUsage is the token usage and cost of one stage
+type StageUsage struct {
+	Stage string
+	ai.Usage
+}
+
+// TotalUsage sums the usage of all stages
+func (s *PipelineState) TotalUsage() ai.Usage {
+	var total ai.Usage
+	for _, stage := range s.Usage {
+		total.Add(stage.Usage)
+	}
+	return total
+}
+
+// Message returns the assembled commit message
+func (s *PipelineState) Message() string {
+	if s.rewritten != "" {
+		return s.rewritten
+	}
+	return CombineSections(s.TopLevel, s.ModuleSections)
+}
+
+// RunPipeline executes the stages in order
+func RunPipeline(config *PipelineConfig, workspaceRoot string, input PipelineInput, run AgentRunner, hooks PipelineHooks) (*PipelineState, error) {
+	if hooks.Progress == nil {
+		hooks.Progress = func(_ string, fn func() error) error { return fn() }
+	}
+	if hooks.Step == nil {
+		hooks.Step = func(StageConfig, int, int) {}
+	}
+
+	state := &PipelineState{}
+
+	for _, stage := range config.Stages {
+		if len(input.Modules) < stage.MinModules {
+			continue
+		}
+
+		agentPath := filepath.Join(workspaceRoot, stage.Agent)
+		var usage ai.Usage
+
+		var err error
+		switch stage.Scope {
+		case ScopeModule:
+			var sections []string
+			hooks.Step(stage, 0, len(input.Modules))
+			message := fmt.Sprintf("🤖 Running stage %s for %d module(s)...", stage.Name, len(input.Modules))
+			err = hooks.Progress(message, func() error {
+				var runErr error
+				sections, runErr = runModuleStage(stage, agentPath, input, run, hooks, &usage)
+				return runErr
+			})
+			if err == nil {
+				state.ModuleSections = append(state.ModuleSections, sections...)
+			}
+
+		default:
+			stageInput := input.CommitContext
+			if stage.Input == InputMessage {
+				stageInput = state.Message() + "\n\n" + input.CommitContext
+			}
+
+			var output string
+			hooks.Step(stage, 0, 1)
+			err = hooks.Progress(fmt.Sprintf("🤖 Running stage %s...", stage.Name), func() error {
+				var runErr error
+				output, runErr = callAgent(stage, run, AgentCall{Stage: stage.Name, Agent: agentPath, Model: stage.Model, Input: stageInput, Usage: &usage})
+				return runErr
+			})
+			hooks.Step(stage, 1, 1)
+			if err == nil {
+				if hooks.Output != nil {
+					hooks.Output(stage, "", output)
+				}
+				if stage.Input == InputMessage {
+					state.rewritten = output
+				} else {
+					state.TopLevel = output
+					state.rewritten = ""
+				}
+			}
+		}
+
+		state.Usage = append(state.Usage, StageUsage{Stage: stage.Name, Usage: usage})
+		if hooks.Usage != nil {
+			hooks.Usage(stage, usage)
+		}
+
+		if err != nil {
+			if stage.IsBlocking() {
+				return nil, fmt.Errorf("stage %s failed: %w", stage.Name, err)
+			}
+			state.Skipped = append(state.Skipped, SkippedStage{Stage: stage.Name, Reason: firstLine(err.Error())})
+			if hooks.Warn != nil {
+				hooks.Warn(fmt.Sprintf("non-blocking stage %s failed, skipping: %v", stage.Name, err))
+			}
+		}
+	}
+
+	return state, nil
+}
+
+// runModuleStage runs a module-scope stage for every module, at most
+// stage.Parallelism at a time, and returns the outputs in module order. The
+// usage of all module runs is added to usage.
+func runModuleStage(stage StageConfig, agentPath string, input PipelineInput, run AgentRunner, hooks PipelineHooks, usage *ai.Usage) ([]string, error) {
+	outputs := make([]string, len(input.Modules))
+	errs := make([]error, len(input.Modules))
+	usages := make([]ai.Usage, len(input.Modules))
+
+	limit := stage.Parallelism
+	if limit < 1 {
+		limit = 1
+	}
+	sem := make(chan struct{}, limit)
+
+	var wg sync.WaitGroup
+	var done atomic.Int32
+	for i, module := range input.Modules {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, module string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			outputs[i], errs[i] = callAgent(stage, run, AgentCall{
+				Stage: stage.Name,
+				Agent: agentPath,
+				Model: stage.Model,
+				Input: input.ModuleContexts[module],
+				Usage: &usages[i],
+			})
+			hooks.Step(stage, int(done.Add(1)), len(input.Modules))
+		}(i, module)
+	}
+	wg.Wait()
+
+	for _, u := range usages {
+		usage.Add(u)
+	}
+
+	for i, module := range input.Modules {
+		if errs[i] != nil {
+			return nil, fmt.Errorf("module %s: %w", module, errs[i])
+		}
+		if hooks.Output != nil {
+			hooks.Output(stage, module, outputs[i])
+		}
+	}
+
+	return outputs, nil
+}
+
+// callAgent runs call within the stage timeout. A runner that does not
+// honour call.Context is abandoned when the timeout expires.
+func callAgent(stage StageConfig, run AgentRunner, call AgentCall) (string, error) {
+	if stage.Timeout <= 0 {
+		call.Context = context.Background()
+		return run(call)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), stage.Timeout)
+	defer cancel()
+	call.Context = ctx
+
+	// The abandoned run must not write to the caller's usage
+	usage := call.Usage
+	var callUsage ai.Usage
+	call.Usage = &callUsage
+
+	type result struct {
+		output string
+		err    error
+	}
+	done := make(chan result, 1)
+	go func() {
+		output, err := run(call)
+		done <- result{output, err}
+	}()
+
+	select {
+	case r := <-done:
+		if usage != nil {
+			*usage = callUsage
+		}
+		if r.err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return "", fmt.Errorf("timed out after %s", stage.Timeout)
+		}
+		return r.output, r.err
+	case <-ctx.Done():
+		return "", fmt.Errorf("timed out after %s", stage.Timeout)
+	}
+}
+
+// CombineSections combines the top-level section and module sections into the final commit message
+func CombineSections(topLevel string, moduleSections []string) string {
+	var result bytes.Buffer
+
+	// Top-level section
+	result.WriteString(topLevel)
+
+	// Only add module sections if there are any (multi-module commits only)
+	if len(moduleSections) > 0 {
+		result.WriteString("\n\n")
+
+		// Module sections with --- separators
+		for i, section := range moduleSections {
+			result.WriteString(section)
+
+			// Add separator between modules (but not after the last one)
+			if i < len(moduleSections)-1 {
+				result.WriteString("\n\n---\n\n")
+			}
+		}
+	}
+
+	return result.String()
+}
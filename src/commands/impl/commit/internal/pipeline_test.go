@@ -0,0 +1,236 @@
+package commitmessage
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestLoadPipeline_DefaultWhenMissing(t *testing.T) {
+	config, err := LoadPipeline(t.TempDir())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(config.Stages) != 2 {
+		t.Fatalf("expected 2 default stages, got %d", len(config.Stages))
+	}
+	if config.Stages[1].Scope != ScopeModule || config.Stages[1].MinModules != 2 {
+		t.Errorf("unexpected module stage: %+v", config.Stages[1])
+	}
+}
+
+func TestLoadPipeline_FromFile(t *testing.T) {
+	root := t.TempDir()
+	content := `stages:
+  - name: generator
+    agent: .claude/agents/generator.md
+  - name: reviewer
+    agent: .claude/agents/reviewer.md
+    model: haiku
+    input: message
+    blocking: false
+    timeout: 90s
+`
+	if err := os.MkdirAll(filepath.Join(root, ".claude"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, PipelineFile), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	config, err := LoadPipeline(root)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if config.Stages[0].Scope != ScopeCommit || config.Stages[0].Input != InputContext {
+		t.Errorf("defaults not applied: %+v", config.Stages[0])
+	}
+	if config.Stages[1].IsBlocking() {
+		t.Error("expected reviewer stage to be non-blocking")
+	}
+	if config.Stages[1].Timeout != 90*time.Second {
+		t.Errorf("expected a 90s reviewer timeout, got %s", config.Stages[1].Timeout)
+	}
+}
+
+func TestPipelineConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name   string
+		stages []StageConfig
+		errMsg string
+	}{
+		{"no stages", nil, "no stages"},
+		{"missing agent", []StageConfig{{Name: "a"}}, "agent is required"},
+		{"duplicate", []StageConfig{{Name: "a", Agent: "x"}, {Name: "a", Agent: "y"}}, "duplicate"},
+		{"bad scope", []StageConfig{{Name: "a", Agent: "x", Scope: "file"}}, "scope must be"},
+		{"message on module", []StageConfig{{Name: "a", Agent: "x", Scope: ScopeModule, Input: InputMessage}}, "requires scope"},
+		{"negative timeout", []StageConfig{{Name: "a", Agent: "x", Timeout: -time.Second}}, "timeout must not be negative"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &PipelineConfig{Stages: tt.stages}
+			err := config.Validate()
+			if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+				t.Errorf("expected error containing %q, got %v", tt.errMsg, err)
+			}
+		})
+	}
+}
+
+func TestRunPipeline_AssemblesSectionsInModuleOrder(t *testing.T) {
+	config := DefaultPipeline()
+	config.Stages[1].Parallelism = 3
+
+	input := PipelineInput{
+		CommitContext:  "commit",
+		Modules:        []string{"mod-a", "mod-b", "mod-c"},
+		ModuleContexts: map[string]string{"mod-a": "a", "mod-b": "b", "mod-c": "c"},
+	}
+	run := func(call AgentCall) (string, error) {
+		call.Usage.Calls = 1
+		call.Usage.InputTokens = len(call.Input)
+		return fmt.Sprintf("%s:%s", filepath.Base(call.Agent), call.Input), nil
+	}
+
+	state, err := RunPipeline(config, "/repo", input, run, PipelineHooks{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []string{"commit-message-module.md:a", "commit-message-module.md:b", "commit-message-module.md:c"}
+	for i, section := range state.ModuleSections {
+		if section != want[i] {
+			t.Errorf("section %d: got %q, want %q", i, section, want[i])
+		}
+	}
+	if !strings.HasPrefix(state.Message(), "commit-message-top-level.md:commit") {
+		t.Errorf("unexpected message: %q", state.Message())
+	}
+
+	if len(state.Usage) != 2 || state.Usage[1].Stage != "module" || state.Usage[1].Calls != 3 {
+		t.Errorf("unexpected stage usage: %+v", state.Usage)
+	}
+	if total := state.TotalUsage(); total.Calls != 4 || total.InputTokens != len("commit")+3 {
+		t.Errorf("unexpected total usage: %+v", total)
+	}
+}
+
+func TestRunPipeline_BlockingAndNonBlockingFailures(t *testing.T) {
+	nonBlocking := false
+	config := &PipelineConfig{Stages: []StageConfig{
+		{Name: "generator", Agent: "gen.md", Scope: ScopeCommit, Input: InputContext},
+		{Name: "reviewer", Agent: "review.md", Scope: ScopeCommit, Input: InputMessage, Blocking: &nonBlocking},
+	}}
+	run := func(call AgentCall) (string, error) {
+		if strings.HasSuffix(call.Agent, "review.md") {
+			return "", fmt.Errorf("boom")
+		}
+		return "generated", nil
+	}
+
+	var warnings []string
+	state, err := RunPipeline(config, "", PipelineInput{}, run, PipelineHooks{
+		Warn: func(message string) { warnings = append(warnings, message) },
+	})
+	if err != nil {
+		t.Fatalf("non-blocking failure should not abort: %v", err)
+	}
+	if state.Message() != "generated" || len(warnings) != 1 {
+		t.Errorf("got message %q, warnings %v", state.Message(), warnings)
+	}
+
+	config.Stages[1].Blocking = nil
+	if _, err := RunPipeline(config, "", PipelineInput{}, run, PipelineHooks{}); err == nil {
+		t.Error("expected blocking failure to abort the pipeline")
+	}
+}
+
+func TestPipelineConfig_SetModuleParallelism(t *testing.T) {
+	config := DefaultPipeline()
+	config.SetModuleParallelism(8)
+
+	if config.Stages[0].Parallelism != 0 {
+		t.Errorf("commit-scope stage should be unchanged, got %d", config.Stages[0].Parallelism)
+	}
+	if config.Stages[1].Parallelism != 8 {
+		t.Errorf("expected module stage parallelism 8, got %d", config.Stages[1].Parallelism)
+	}
+}
+
+func TestRunPipeline_ReportsSteps(t *testing.T) {
+	config := DefaultPipeline()
+	input := PipelineInput{Modules: []string{"mod-a", "mod-b"}}
+	run := func(call AgentCall) (string, error) { return "ok", nil }
+
+	var mu sync.Mutex
+	steps := make(map[string][]int)
+	_, err := RunPipeline(config, "", input, run, PipelineHooks{
+		Step: func(stage StageConfig, done int, total int) {
+			mu.Lock()
+			defer mu.Unlock()
+			steps[stage.Name] = append(steps[stage.Name], done*10+total)
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := fmt.Sprint(steps["top-level"]); got != "[1 11]" {
+		t.Errorf("unexpected top-level steps %s", got)
+	}
+	moduleSteps := append([]int{}, steps["module"]...)
+	sort.Ints(moduleSteps)
+	if got := fmt.Sprint(moduleSteps); got != "[2 12 22]" {
+		t.Errorf("unexpected module steps %s", got)
+	}
+
+	if got := config.ActiveStages(1); len(got) != 1 || got[0] != "top-level" {
+		t.Errorf("expected only top-level for a single module, got %v", got)
+	}
+}
+
+func TestRunPipeline_StageTimeoutSkipsNonBlockingStage(t *testing.T) {
+	nonBlocking := false
+	config := &PipelineConfig{Stages: []StageConfig{
+		{Name: "generator", Agent: "gen.md", Scope: ScopeCommit, Input: InputContext},
+		{Name: "reviewer", Agent: "review.md", Scope: ScopeCommit, Input: InputMessage, Blocking: &nonBlocking, Timeout: 20 * time.Millisecond},
+		{Name: "title", Agent: "title.md", Scope: ScopeCommit, Input: InputMessage, Blocking: &nonBlocking, Timeout: 20 * time.Millisecond},
+	}}
+	run := func(call AgentCall) (string, error) {
+		switch {
+		case strings.HasSuffix(call.Agent, "review.md"):
+			<-call.Context.Done() // honours the timeout
+			return "", call.Context.Err()
+		case strings.HasSuffix(call.Agent, "title.md"):
+			time.Sleep(time.Second) // ignores the timeout
+			return "late", nil
+		}
+		return "base commit", nil
+	}
+
+	start := time.Now()
+	state, err := RunPipeline(config, "", PipelineInput{}, run, PipelineHooks{})
+	if err != nil {
+		t.Fatalf("timeouts of non-blocking stages should not abort: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("expected the title stage to be abandoned, took %s", elapsed)
+	}
+	if state.Message() != "base commit" {
+		t.Errorf("expected the base commit, got %q", state.Message())
+	}
+	if len(state.Skipped) != 2 || state.Skipped[0].Stage != "reviewer" || state.Skipped[0].Reason != "timed out after 20ms" {
+		t.Errorf("unexpected skipped stages: %+v", state.Skipped)
+	}
+
+	config.Stages[1].Blocking = nil
+	if _, err := RunPipeline(config, "", PipelineInput{}, run, PipelineHooks{}); err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Errorf("expected a blocking timeout to abort, got %v", err)
+	}
+}
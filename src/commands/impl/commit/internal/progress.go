@@ -2,11 +2,106 @@ package commitmessage
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"math/rand"
+	"sync"
 	"time"
 )
 
+// ProgressFormatEnv selects machine-readable progress: with R2R_PROGRESS=json
+// each ProgressEvent is printed to stderr as a line starting with ProgressLinePrefix
+const ProgressFormatEnv = "R2R_PROGRESS"
+
+// ProgressLinePrefix marks a JSON progress event line
+const ProgressLinePrefix = ">>>>>>PROGRESS "
+
+// ProgressEvent is a typed progress update for a multi-stage run
+type ProgressEvent struct {
+	Stage     string  `json:"stage"`            // stage id, e.g. "context" or a pipeline stage name
+	Ordinal   int     `json:"ordinal"`          // 1-based position of the stage
+	Total     int     `json:"total"`            // number of stages
+	Percent   float64 `json:"percent"`          // overall completion, 0-100
+	ElapsedMs int64   `json:"elapsed_ms"`       // time since the run started
+	EtaMs     int64   `json:"eta_ms,omitempty"` // estimated time remaining, once progress was made
+	Message   string  `json:"message,omitempty"`
+}
+
+// ProgressTracker turns stage transitions into ProgressEvents. Each stage
+// has an equal share of the total; a stage reports finer progress by
+// completed units (e.g. modules).
+type ProgressTracker struct {
+	mu      sync.Mutex
+	stages  []string
+	start   time.Time
+	percent float64
+	emit    func(ProgressEvent)
+	now     func() time.Time
+}
+
+// NewProgressTracker tracks the stages in order; emit receives every event
+func NewProgressTracker(stages []string, emit func(ProgressEvent)) *ProgressTracker {
+	return &ProgressTracker{stages: stages, start: time.Now(), emit: emit, now: time.Now}
+}
+
+// JSONProgressEmitter writes events as prefixed JSON lines to w
+func JSONProgressEmitter(w io.Writer) func(ProgressEvent) {
+	return func(event ProgressEvent) {
+		data, err := json.Marshal(event)
+		if err != nil {
+			return
+		}
+		fmt.Fprintf(w, "%s%s\n", ProgressLinePrefix, data)
+	}
+}
+
+// Update reports that stage has completed done of total units. Unknown stages
+// are ignored, and completion never goes backwards.
+func (t *ProgressTracker) Update(stage string, done int, total int, message string) {
+	if t == nil || t.emit == nil {
+		return
+	}
+
+	// Emit under the lock so concurrent updates arrive in order
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	ordinal := 0
+	for i, name := range t.stages {
+		if name == stage {
+			ordinal = i + 1
+			break
+		}
+	}
+	if ordinal == 0 {
+		return
+	}
+
+	fraction := 0.0
+	if total > 0 {
+		fraction = float64(done) / float64(total)
+	}
+	if percent := (float64(ordinal-1) + fraction) / float64(len(t.stages)) * 100; percent > t.percent {
+		t.percent = percent
+	}
+
+	elapsed := t.now().Sub(t.start)
+	event := ProgressEvent{
+		Stage:     stage,
+		Ordinal:   ordinal,
+		Total:     len(t.stages),
+		Percent:   t.percent,
+		ElapsedMs: elapsed.Milliseconds(),
+		Message:   message,
+	}
+	if t.percent > 0 && t.percent < 100 {
+		event.EtaMs = int64(float64(event.ElapsedMs) * (100 - t.percent) / t.percent)
+	}
+
+	t.emit(event)
+}
+
 // WhimsicalStatusLines are fun status messages shown during generation
 var WhimsicalStatusLines = []string{
 	"Discombobulating the git diffs...",
@@ -0,0 +1,62 @@
+package commitmessage
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestProgressTracker_StagesAndPercent(t *testing.T) {
+	var events []ProgressEvent
+	tracker := NewProgressTracker([]string{"collect", "module", "verify"}, func(event ProgressEvent) {
+		events = append(events, event)
+	})
+	start := tracker.start
+	tracker.now = func() time.Time { return start.Add(10 * time.Second) }
+
+	tracker.Update("collect", 1, 1, "")
+	tracker.Update("module", 1, 2, "")
+	tracker.Update("module", 0, 2, "") // late start report must not go backwards
+	tracker.Update("unknown", 1, 1, "")
+	tracker.Update("verify", 1, 1, "done")
+
+	if len(events) != 4 {
+		t.Fatalf("expected 4 events, got %+v", events)
+	}
+
+	half := events[1]
+	if half.Stage != "module" || half.Ordinal != 2 || half.Total != 3 || half.Percent != 50 {
+		t.Errorf("unexpected event %+v", half)
+	}
+	if half.ElapsedMs != 10000 || half.EtaMs != 10000 {
+		t.Errorf("expected 10s elapsed and 10s remaining, got %+v", half)
+	}
+	if events[2].Percent != 50 {
+		t.Errorf("expected progress to stay at 50, got %v", events[2].Percent)
+	}
+	if last := events[3]; last.Percent != 100 || last.EtaMs != 0 || last.Message != "done" {
+		t.Errorf("unexpected final event %+v", last)
+	}
+
+	var nilTracker *ProgressTracker
+	nilTracker.Update("collect", 1, 1, "") // disabled progress is a no-op
+}
+
+func TestJSONProgressEmitter(t *testing.T) {
+	var buf bytes.Buffer
+	JSONProgressEmitter(&buf)(ProgressEvent{Stage: "verify", Ordinal: 1, Total: 1, Percent: 100})
+
+	line := strings.TrimSuffix(buf.String(), "\n")
+	if !strings.HasPrefix(line, ProgressLinePrefix) {
+		t.Fatalf("missing prefix in %q", line)
+	}
+	var event ProgressEvent
+	if err := json.Unmarshal([]byte(strings.TrimPrefix(line, ProgressLinePrefix)), &event); err != nil {
+		t.Fatal(err)
+	}
+	if event.Stage != "verify" || event.Percent != 100 {
+		t.Errorf("unexpected event %+v", event)
+	}
+}
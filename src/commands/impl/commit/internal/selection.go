@@ -0,0 +1,203 @@
+package commitmessage
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DiffSelection selects the files and hunks of a staged diff for a partial
+// commit. Files map to 1-based hunk numbers; a file mapped to nil is
+// included whole.
+type DiffSelection map[string][]int
+
+// hunkHeaderPattern matches "@@ -oldStart[,oldCount] +newStart[,newCount] @@"
+var hunkHeaderPattern = regexp.MustCompile(`^@@ -(\d+)(?:,(\d+))? \+(\d+)(?:,(\d+))? @@(.*)$`)
+
+// ParseDiffSelection builds a selection from file paths and hunk specs.
+// Both accept comma-separated lists; a hunk spec is "<path>:<n>[,<n>...]".
+func ParseDiffSelection(files []string, hunks []string) (DiffSelection, error) {
+	selection := make(DiffSelection)
+
+	for _, value := range files {
+		for _, path := range strings.Split(value, ",") {
+			if path = strings.TrimSpace(path); path != "" {
+				selection[path] = nil
+			}
+		}
+	}
+
+	for _, spec := range hunks {
+		sep := strings.LastIndex(spec, ":")
+		if sep <= 0 || sep == len(spec)-1 {
+			return nil, fmt.Errorf("invalid hunk selection %q, expected <path>:<n>[,<n>...]", spec)
+		}
+		path := strings.TrimSpace(spec[:sep])
+		if numbers, ok := selection[path]; ok && numbers == nil {
+			continue // whole file already selected
+		}
+		for _, part := range strings.Split(spec[sep+1:], ",") {
+			n, err := strconv.Atoi(strings.TrimSpace(part))
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("invalid hunk number %q in %q", part, spec)
+			}
+			selection[path] = append(selection[path], n)
+		}
+	}
+
+	for path, numbers := range selection {
+		sort.Ints(numbers)
+		selection[path] = dedupInts(numbers)
+	}
+
+	return selection, nil
+}
+
+// Includes reports whether any change of path is selected
+func (s DiffSelection) Includes(path string) bool {
+	_, ok := s[path]
+	return ok
+}
+
+// SelectDiff returns the selected files and hunks of diff as a patch that
+// applies to the index with "git apply --cached". Hunk headers are
+// renumbered for the hunks left out, the same way "git add -p" does.
+func SelectDiff(diff string, selection DiffSelection) (string, error) {
+	var result strings.Builder
+	found := make(map[string]bool)
+
+	for _, file := range ParseDiff(diff) {
+		numbers, ok := selection[file.Path]
+		if !ok {
+			continue
+		}
+		found[file.Path] = true
+
+		text := file.Text()
+		if numbers != nil {
+			if file.Binary {
+				return "", fmt.Errorf("cannot select hunks of binary file %s", file.Path)
+			}
+			var err error
+			if text, err = selectHunks(file, numbers); err != nil {
+				return "", err
+			}
+		}
+
+		result.WriteString(strings.TrimRight(text, "\n"))
+		result.WriteString("\n")
+	}
+
+	var missing []string
+	for path := range selection {
+		if !found[path] {
+			missing = append(missing, path)
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return "", fmt.Errorf("selected files have no staged changes: %s", strings.Join(missing, ", "))
+	}
+
+	return result.String(), nil
+}
+
+// hunk is one "@@" section of a file diff
+type hunk struct {
+	oldStart, oldCount int
+	newStart, newCount int
+	section            string // text after the closing "@@"
+	lines              []string
+}
+
+// selectHunks keeps the numbered hunks of file and shifts the new-file line
+// numbers of each kept hunk by the line delta of the hunks dropped before it
+func selectHunks(file FileDiff, numbers []int) (string, error) {
+	hunks, err := splitHunks(file.Body)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", file.Path, err)
+	}
+
+	keep := make(map[int]bool)
+	for _, n := range numbers {
+		if n > len(hunks) {
+			return "", fmt.Errorf("%s has %d hunk(s), cannot select hunk %d", file.Path, len(hunks), n)
+		}
+		keep[n] = true
+	}
+
+	lines := append([]string{}, file.Header...)
+	dropped := 0
+	for i, h := range hunks {
+		if !keep[i+1] {
+			dropped += h.newCount - h.oldCount
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("@@ -%s +%s @@%s",
+			hunkRange(h.oldStart, h.oldCount), hunkRange(h.newStart-dropped, h.newCount), h.section))
+		lines = append(lines, h.lines...)
+	}
+
+	return strings.Join(lines, "\n"), nil
+}
+
+// splitHunks splits the body of a file diff at its "@@" headers
+func splitHunks(body []string) ([]hunk, error) {
+	var hunks []hunk
+	for _, line := range body {
+		if strings.HasPrefix(line, "@@") {
+			match := hunkHeaderPattern.FindStringSubmatch(line)
+			if match == nil {
+				return nil, fmt.Errorf("malformed hunk header %q", line)
+			}
+			hunks = append(hunks, hunk{
+				oldStart: atoiDefault(match[1], 0),
+				oldCount: atoiDefault(match[2], 1),
+				newStart: atoiDefault(match[3], 0),
+				newCount: atoiDefault(match[4], 1),
+				section:  match[5],
+			})
+			continue
+		}
+		if len(hunks) == 0 || line == "" {
+			continue
+		}
+		current := &hunks[len(hunks)-1]
+		current.lines = append(current.lines, line)
+	}
+	return hunks, nil
+}
+
+// hunkRange formats a hunk range, omitting a count of one like git does
+func hunkRange(start, count int) string {
+	if count == 1 {
+		return strconv.Itoa(start)
+	}
+	return fmt.Sprintf("%d,%d", start, count)
+}
+
+func atoiDefault(s string, def int) int {
+	if s == "" {
+		return def
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return def
+	}
+	return n
+}
+
+func dedupInts(sorted []int) []int {
+	if sorted == nil {
+		return nil
+	}
+	result := sorted[:0]
+	for i, n := range sorted {
+		if i == 0 || n != sorted[i-1] {
+			result = append(result, n)
+		}
+	}
+	return result
+}
@@ -0,0 +1,85 @@
+package commitmessage
+
+import (
+	"strings"
+	"testing"
+)
+
+const twoHunkDiff = `diff --git a/a.go b/a.go
+index 1111111..2222222 100644
+--- a/a.go
++++ b/a.go
+@@ -1,3 +1,4 @@ package a
+ line 1
++added near top
+ line 2
+ line 3
+@@ -10,3 +11,3 @@ func f() {
+ line 10
+-old line 11
++new line 11
+ line 12
+diff --git a/b.go b/b.go
+index 3333333..4444444 100644
+--- a/b.go
++++ b/b.go
+@@ -1 +1 @@
+-old b
++new b
+`
+
+func TestParseDiffSelection(t *testing.T) {
+	selection, err := ParseDiffSelection([]string{"b.go, c.go"}, []string{"a.go:2,1", "b.go:1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if numbers := selection["a.go"]; len(numbers) != 2 || numbers[0] != 1 || numbers[1] != 2 {
+		t.Errorf("expected hunks 1,2 of a.go, got %v", numbers)
+	}
+	if selection["b.go"] != nil || !selection.Includes("c.go") {
+		t.Errorf("expected whole files b.go and c.go, got %v", selection)
+	}
+
+	for _, spec := range []string{"a.go", "a.go:", ":1", "a.go:0", "a.go:x"} {
+		if _, err := ParseDiffSelection(nil, []string{spec}); err == nil {
+			t.Errorf("expected error for %q", spec)
+		}
+	}
+}
+
+func TestSelectDiff_RenumbersKeptHunks(t *testing.T) {
+	patch, err := SelectDiff(twoHunkDiff, DiffSelection{"a.go": {2}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The dropped first hunk added a line, so the second starts one line earlier
+	if !strings.Contains(patch, "@@ -10,3 +10,3 @@ func f() {\n line 10\n-old line 11") {
+		t.Errorf("expected renumbered second hunk, got:\n%s", patch)
+	}
+	if strings.Contains(patch, "added near top") || strings.Contains(patch, "b.go") {
+		t.Errorf("unselected changes in patch:\n%s", patch)
+	}
+	if !strings.HasPrefix(patch, "diff --git a/a.go b/a.go\n") || !strings.HasSuffix(patch, " line 12\n") {
+		t.Errorf("unexpected patch:\n%s", patch)
+	}
+}
+
+func TestSelectDiff_WholeFiles(t *testing.T) {
+	patch, err := SelectDiff(twoHunkDiff, DiffSelection{"b.go": nil})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if patch != twoHunkDiff[strings.Index(twoHunkDiff, "diff --git a/b.go"):] {
+		t.Errorf("expected b.go diff unchanged, got:\n%s", patch)
+	}
+}
+
+func TestSelectDiff_Errors(t *testing.T) {
+	if _, err := SelectDiff(twoHunkDiff, DiffSelection{"a.go": {3}}); err == nil {
+		t.Error("expected error for hunk out of range")
+	}
+	if _, err := SelectDiff(twoHunkDiff, DiffSelection{"missing.go": nil}); err == nil || !strings.Contains(err.Error(), "missing.go") {
+		t.Errorf("expected error naming the unstaged file, got %v", err)
+	}
+}
@@ -0,0 +1,101 @@
+package commitmessage
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CommitKind identifies commits that keep git's own message format instead of
+// the module section structure
+type CommitKind string
+
+const (
+	CommitKindMerge      CommitKind = "merge"
+	CommitKindRevert     CommitKind = "revert"
+	CommitKindCherryPick CommitKind = "cherry-pick"
+)
+
+// cherryPickTrailer is the line "git cherry-pick -x" appends
+const cherryPickTrailer = "(cherry picked from commit %s)"
+
+// InProgressCommit is a merge, revert or cherry-pick waiting to be committed
+type InProgressCommit struct {
+	Kind     CommitKind
+	Commit   string // MERGE_HEAD, REVERT_HEAD or CHERRY_PICK_HEAD
+	Original string // message of Commit
+	Prepared string // MERGE_MSG as prepared by git, may be empty
+}
+
+// Message returns the message for the commit: merges keep git's "Merge
+// branch ..." message, reverts reference the reverted commit and cherry-picks
+// keep the original message and reference the picked commit. Lines starting
+// with commentChar are dropped from the prepared merge and revert messages.
+func (c InProgressCommit) Message(commentChar string) string {
+	prepared := stripCommentLines(c.Prepared, commentChar)
+
+	switch c.Kind {
+	case CommitKindMerge:
+		if prepared != "" {
+			return prepared
+		}
+		return fmt.Sprintf("Merge commit '%s'", shortSHA(c.Commit))
+
+	case CommitKindRevert:
+		if prepared != "" && strings.Contains(prepared, c.Commit) {
+			return prepared
+		}
+		return fmt.Sprintf("Revert \"%s\"\n\nThis reverts commit %s.", firstLine(c.Original), c.Commit)
+
+	default:
+		// The original message, as the prepared one may mix "#" headings and comments
+		message := strings.TrimSpace(c.Original)
+		trailer := fmt.Sprintf(cherryPickTrailer, c.Commit)
+		if !strings.Contains(message, trailer) {
+			message += "\n\n" + trailer
+		}
+		return message
+	}
+}
+
+// SpecialCommitKind reports whether message is a merge or revert message,
+// which is exempt from the module section structure
+func SpecialCommitKind(message string) (CommitKind, bool) {
+	subject := firstLine(message)
+	switch {
+	case strings.HasPrefix(subject, "Merge branch ") ||
+		strings.HasPrefix(subject, "Merge remote-tracking branch ") ||
+		strings.HasPrefix(subject, "Merge pull request ") ||
+		strings.HasPrefix(subject, "Merge tag ") ||
+		strings.HasPrefix(subject, "Merge commit "):
+		return CommitKindMerge, true
+	case strings.HasPrefix(subject, `Revert "`) && strings.Contains(message, "This reverts commit "):
+		return CommitKindRevert, true
+	}
+	return "", false
+}
+
+func stripCommentLines(message string, commentChar string) string {
+	var kept []string
+	for _, line := range strings.Split(strings.ReplaceAll(message, "\r\n", "\n"), "\n") {
+		if commentChar != "" && strings.HasPrefix(line, commentChar) {
+			continue
+		}
+		kept = append(kept, line)
+	}
+	return strings.TrimSpace(strings.Join(kept, "\n"))
+}
+
+func firstLine(text string) string {
+	text = strings.TrimSpace(text)
+	if i := strings.Index(text, "\n"); i >= 0 {
+		return strings.TrimSpace(text[:i])
+	}
+	return text
+}
+
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
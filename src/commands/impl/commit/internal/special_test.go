@@ -0,0 +1,73 @@
+package commitmessage
+
+import (
+	"strings"
+	"testing"
+)
+
+const revertedSHA = "0123456789abcdef0123456789abcdef01234567"
+
+func TestInProgressCommit_MergeKeepsPreparedMessage(t *testing.T) {
+	commit := InProgressCommit{
+		Kind:     CommitKindMerge,
+		Commit:   revertedSHA,
+		Prepared: "Merge branch 'feature' into main\n\n# Conflicts:\n#\tsrc/cli/go.mod\n",
+	}
+	if got := commit.Message("#"); got != "Merge branch 'feature' into main" {
+		t.Errorf("unexpected merge message %q", got)
+	}
+
+	commit.Prepared = ""
+	if got := commit.Message("#"); got != "Merge commit '0123456'" {
+		t.Errorf("unexpected fallback merge message %q", got)
+	}
+}
+
+func TestInProgressCommit_RevertReferencesCommit(t *testing.T) {
+	commit := InProgressCommit{
+		Kind:     CommitKindRevert,
+		Commit:   revertedSHA,
+		Original: "# cli: feat: add stats\n\nDetails",
+	}
+	want := "Revert \"# cli: feat: add stats\"\n\nThis reverts commit " + revertedSHA + "."
+	if got := commit.Message("#"); got != want {
+		t.Errorf("unexpected revert message:\n%s", got)
+	}
+
+	commit.Prepared = "Revert \"add stats\"\n\nThis reverts commit " + revertedSHA + ".\n\nBroke CI."
+	if got := commit.Message(";"); !strings.HasSuffix(got, "Broke CI.") {
+		t.Errorf("expected prepared revert message to be kept, got:\n%s", got)
+	}
+}
+
+func TestInProgressCommit_CherryPickKeepsOriginal(t *testing.T) {
+	commit := InProgressCommit{
+		Kind:     CommitKindCherryPick,
+		Commit:   revertedSHA,
+		Original: "# cli: fix: handle empty log\n",
+	}
+	want := "# cli: fix: handle empty log\n\n(cherry picked from commit " + revertedSHA + ")"
+	if got := commit.Message(";"); got != want {
+		t.Errorf("unexpected cherry-pick message:\n%s", got)
+	}
+
+	commit.Original = want
+	if got := commit.Message(";"); strings.Count(got, "cherry picked") != 1 {
+		t.Errorf("expected a single cherry-pick reference, got:\n%s", got)
+	}
+}
+
+func TestSpecialCommitKind(t *testing.T) {
+	for message, want := range map[string]CommitKind{
+		"Merge branch 'feature' into main":                        CommitKindMerge,
+		"Merge pull request #12 from org/branch\n\nAdd stats":     CommitKindMerge,
+		"Revert \"add stats\"\n\nThis reverts commit abc1234.":    CommitKindRevert,
+		"Revert \"add stats\"":                                    "",
+		"# cli: feat: merge branch handling\n\n## cli\n\ndetails": "",
+	} {
+		kind, ok := SpecialCommitKind(message)
+		if kind != want || ok != (want != "") {
+			t.Errorf("SpecialCommitKind(%q) = %q, %v; want %q", message, kind, ok, want)
+		}
+	}
+}
@@ -0,0 +1,182 @@
+package commitmessage
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// SummaryCacheDir holds the cached summaries of oversize file diffs,
+// relative to the repository root
+const SummaryCacheDir = ".r2r/cache/diff-summaries"
+
+// DefaultSummaryModel summarizes oversize file diffs
+const DefaultSummaryModel = "haiku"
+
+// maxSummaryLines caps the lines of a generated summary embedded in contexts
+const maxSummaryLines = 12
+
+// SummaryConfig configures the generated summaries of oversize file diffs
+type SummaryConfig struct {
+	Enabled *bool  `yaml:"enabled"` // default true
+	Model   string `yaml:"model"`   // default haiku
+}
+
+// IsEnabled reports whether oversize file diffs are summarized by a model
+func (c SummaryConfig) IsEnabled() bool {
+	return c.Enabled == nil || *c.Enabled
+}
+
+func (c *SummaryConfig) validate() error {
+	if c.Model == "" {
+		c.Model = DefaultSummaryModel
+	}
+	return nil
+}
+
+// indexLinePattern matches the blob hashes of "index <old>..<new> [mode]"
+var indexLinePattern = regexp.MustCompile(`^index ([0-9a-f]+)\.\.([0-9a-f]+)`)
+
+// SummaryKey identifies the diff of a file by its path and the blob hashes
+// before and after the change, so the same change always maps to the same
+// summary. Diffs without an index line are keyed by their text.
+func SummaryKey(file FileDiff) string {
+	h := sha256.New()
+	h.Write([]byte(file.Path + "\x00"))
+	for _, line := range file.Header {
+		if match := indexLinePattern.FindStringSubmatch(line); match != nil {
+			h.Write([]byte(match[1] + "\x00" + match[2]))
+			return hex.EncodeToString(h.Sum(nil))
+		}
+	}
+	h.Write([]byte(file.Text()))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// CachedSummary is a stored summary of a file diff
+type CachedSummary struct {
+	Key       string    `json:"key"`
+	Path      string    `json:"path"`
+	Summary   string    `json:"summary"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// SummaryCache stores file diff summaries as one JSON file per key
+type SummaryCache struct {
+	dir string
+}
+
+// NewSummaryCache creates a cache storing summaries in dir
+func NewSummaryCache(dir string) *SummaryCache {
+	return &SummaryCache{dir: dir}
+}
+
+// Load returns the cached summary for key
+func (c *SummaryCache) Load(key string) (string, bool) {
+	data, err := os.ReadFile(c.path(key))
+	if err != nil {
+		return "", false
+	}
+	var cached CachedSummary
+	if err := json.Unmarshal(data, &cached); err != nil || cached.Summary == "" {
+		return "", false
+	}
+	return cached.Summary, true
+}
+
+// Save stores a summary, replacing the file atomically
+func (c *SummaryCache) Save(cached CachedSummary) error {
+	if err := os.MkdirAll(c.dir, 0755); err != nil {
+		return fmt.Errorf("failed to create summary cache: %w", err)
+	}
+	data, err := json.MarshalIndent(cached, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode summary: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(c.dir, cached.Key+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write summary: %w", err)
+	}
+	_, writeErr := tmp.Write(append(data, '\n'))
+	closeErr := tmp.Close()
+	if writeErr != nil || closeErr != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write summary: %w", errors.Join(writeErr, closeErr))
+	}
+	if err := os.Rename(tmp.Name(), c.path(cached.Key)); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write summary: %w", err)
+	}
+	return nil
+}
+
+func (c *SummaryCache) path(key string) string {
+	return filepath.Join(c.dir, key+".json")
+}
+
+// SummaryStats counts the summaries served from the cache and generated
+type SummaryStats struct {
+	Cached    int
+	Generated int
+	Failed    int
+}
+
+// SummarizeOversize returns a summary for every oversize file of diff, keyed
+// by path. Cached summaries are reused; the others are generated with
+// summarize and cached. Files whose summary fails are left out, so they keep
+// the hunk list of SummarizeDiff.
+func SummarizeOversize(diff string, classes map[string]FileClass, cache *SummaryCache, summarize func(FileDiff) (string, error)) (map[string]string, SummaryStats, error) {
+	summaries := make(map[string]string)
+	var stats SummaryStats
+	var errs []error
+
+	for _, file := range ParseDiff(diff) {
+		if classes[file.Path] != FileClassOversize {
+			continue
+		}
+
+		key := SummaryKey(file)
+		if summary, ok := cache.Load(key); ok {
+			summaries[file.Path] = summary
+			stats.Cached++
+			continue
+		}
+
+		summary, err := summarize(file)
+		summary = strings.TrimSpace(summary)
+		if err == nil && summary == "" {
+			err = fmt.Errorf("empty summary")
+		}
+		if err != nil {
+			stats.Failed++
+			errs = append(errs, fmt.Errorf("%s: %w", file.Path, err))
+			continue
+		}
+
+		summaries[file.Path] = summary
+		stats.Generated++
+		if err := cache.Save(CachedSummary{Key: key, Path: file.Path, Summary: summary, CreatedAt: time.Now().UTC()}); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return summaries, stats, errors.Join(errs...)
+}
+
+// SummaryPrompt asks a model to summarize the diff of a single file, truncated
+// to maxTokens
+func SummaryPrompt(file FileDiff, maxTokens int) string {
+	return fmt.Sprintf(`Summarize the following diff of %s for a commit message writer.
+Reply with at most %d short "- " bullet points naming what changed and why it matters.
+Do not quote code and do not add any other text.
+
+`+"```diff\n%s\n```", file.Path, maxSummaryLines/2, truncateFileDiff(file, maxTokens*charsPerToken))
+}
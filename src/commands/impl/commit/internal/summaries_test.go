@@ -0,0 +1,80 @@
+package commitmessage
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestSummaryKey(t *testing.T) {
+	file := ParseDiff(makeFileDiff("big.go", 10))[0]
+	same := ParseDiff(makeFileDiff("big.go", 20))[0]
+	other := ParseDiff(strings.Replace(makeFileDiff("big.go", 10), "2222222", "3333333", 1))[0]
+
+	// Same blobs, different context lines: same change
+	if SummaryKey(file) != SummaryKey(same) {
+		t.Error("expected the key to depend on the blob hashes only")
+	}
+	if SummaryKey(file) == SummaryKey(other) {
+		t.Error("expected a new blob to change the key")
+	}
+	if SummaryKey(file) == SummaryKey(ParseDiff(makeFileDiff("other.go", 10))[0]) {
+		t.Error("expected the path to change the key")
+	}
+}
+
+func TestSummarizeOversize_CachesSummaries(t *testing.T) {
+	diff := makeFileDiff("main.go", 2) + makeFileDiff("big.go", 300) + makeFileDiff("huge.go", 300)
+	classes := map[string]FileClass{"big.go": FileClassOversize, "huge.go": FileClassOversize}
+	cache := NewSummaryCache(t.TempDir())
+
+	calls := 0
+	summarize := func(file FileDiff) (string, error) {
+		calls++
+		if !strings.Contains(SummaryPrompt(file, 100), "diff of "+file.Path) {
+			t.Errorf("unexpected prompt for %s", file.Path)
+		}
+		return fmt.Sprintf("- reworked %s\n", file.Path), nil
+	}
+
+	summaries, stats, err := SummarizeOversize(diff, classes, cache, summarize)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 2 || stats.Generated != 2 || summaries["big.go"] != "- reworked big.go" {
+		t.Fatalf("expected 2 generated summaries, got %d calls, %+v, %v", calls, stats, summaries)
+	}
+
+	// A retry or a later commit of the same change reuses the summaries
+	summaries, stats, err = SummarizeOversize(diff, classes, cache, summarize)
+	if err != nil || calls != 2 || stats.Cached != 2 || summaries["huge.go"] != "- reworked huge.go" {
+		t.Errorf("expected cached summaries, got %d calls, %+v, %v, %v", calls, stats, summaries, err)
+	}
+}
+
+func TestSummarizeOversize_FailureKeepsHunks(t *testing.T) {
+	diff := makeFileDiff("big.go", 300)
+	classes := map[string]FileClass{"big.go": FileClassOversize}
+
+	summaries, stats, err := SummarizeOversize(diff, classes, NewSummaryCache(t.TempDir()), func(FileDiff) (string, error) {
+		return "", fmt.Errorf("model unavailable")
+	})
+	if err == nil || stats.Failed != 1 || len(summaries) != 0 {
+		t.Fatalf("expected a failed summary, got %+v, %v, %v", stats, summaries, err)
+	}
+
+	if summary := SummarizeDiffWith(diff, classes, summaries); !strings.Contains(summary, "Hunks:") {
+		t.Errorf("expected the hunk list, got:\n%s", summary)
+	}
+}
+
+func TestSummarizeDiffWith_GeneratedSummary(t *testing.T) {
+	diff := makeFileDiff("big.go", 300)
+	classes := map[string]FileClass{"big.go": FileClassOversize}
+
+	summary := SummarizeDiffWith(diff, classes, map[string]string{"big.go": "- split the parser\n- add tests"})
+	want := "diff --git a/big.go b/big.go\n# big.go: large diff, +300 -0 lines (diff omitted). Summary:\n#   - split the parser\n#   - add tests"
+	if summary != want {
+		t.Errorf("unexpected summary:\n%s", summary)
+	}
+}
@@ -0,0 +1,183 @@
+package commit
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	commitmessage "github.com/ready-to-release/eac/src/commands/impl/commit/internal"
+	"github.com/ready-to-release/eac/src/core/contracts"
+	"github.com/ready-to-release/eac/src/core/contracts/modules"
+	"github.com/ready-to-release/eac/src/core/repository"
+	"gopkg.in/yaml.v3"
+)
+
+// Golden tests for the agent contexts. Each directory in testdata/prompts
+// holds a fixture: case.yml, the staged diff (diff.patch) and optionally
+// docs. The rendered contexts are compared with the files in its golden
+// directory; run with -update to rewrite them after an intended change:
+//
+//	go test ./impl/commit -run TestAgentContexts_Golden -update
+var updateGolden = flag.Bool("update", false, "rewrite the golden files in testdata/prompts")
+
+// promptFixture is the case.yml of a golden test
+type promptFixture struct {
+	Files []struct {
+		Name    string   `yaml:"name"`
+		Modules []string `yaml:"modules"`
+	} `yaml:"files"`
+	Generated []string                          `yaml:"generated"` // linguist-generated paths
+	Contracts map[string]contracts.BaseContract `yaml:"contracts"`
+	Docs      bool                              `yaml:"docs"` // select docs from the fixture's docs directory
+	Language  string                            `yaml:"language"`
+	Tokens    int                               `yaml:"tokens"` // context token budget
+}
+
+func TestAgentContexts_Golden(t *testing.T) {
+	cases, err := filepath.Glob(filepath.Join("testdata", "prompts", "*", "case.yml"))
+	if err != nil || len(cases) == 0 {
+		t.Fatalf("no prompt fixtures in testdata/prompts: %v", err)
+	}
+
+	for _, casePath := range cases {
+		dir := filepath.Dir(casePath)
+		t.Run(filepath.Base(dir), func(t *testing.T) {
+			rendered := renderPromptFixture(t, dir)
+
+			goldenDir := filepath.Join(dir, "golden")
+			if *updateGolden {
+				if err := os.RemoveAll(goldenDir); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.MkdirAll(goldenDir, 0755); err != nil {
+					t.Fatal(err)
+				}
+				for name, content := range rendered {
+					if err := os.WriteFile(filepath.Join(goldenDir, name), []byte(content), 0644); err != nil {
+						t.Fatal(err)
+					}
+				}
+				return
+			}
+
+			goldens, _ := filepath.Glob(filepath.Join(goldenDir, "*.md"))
+			if len(goldens) != len(rendered) {
+				t.Errorf("rendered %d contexts, %s has %d (run with -update)", len(rendered), goldenDir, len(goldens))
+			}
+			for name, content := range rendered {
+				want, err := os.ReadFile(filepath.Join(goldenDir, name))
+				if err != nil {
+					t.Errorf("missing golden file %s (run with -update)", name)
+					continue
+				}
+				if content != string(want) {
+					t.Errorf("%s differs from the golden file (run with -update and review the diff):\n%s", name, firstDiff(string(want), content))
+				}
+			}
+		})
+	}
+}
+
+// renderPromptFixture builds the agent contexts of a fixture the way
+// CommitAI does and returns them by golden file name
+func renderPromptFixture(t *testing.T, dir string) map[string]string {
+	t.Helper()
+
+	data, err := os.ReadFile(filepath.Join(dir, "case.yml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fixture promptFixture
+	if err := yaml.Unmarshal(data, &fixture); err != nil {
+		t.Fatalf("invalid case.yml: %v", err)
+	}
+	diff, err := os.ReadFile(filepath.Join(dir, "diff.patch"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tokens := fixture.Tokens
+	if tokens == 0 {
+		tokens = commitmessage.DefaultContextTokenBudget
+	}
+	budget := commitmessage.NewContextBudget(tokens)
+
+	var files []repository.RepositoryFileWithModule
+	var fileNames []string
+	moduleSet := make(map[string]bool)
+	for _, file := range fixture.Files {
+		files = append(files, repository.RepositoryFileWithModule{Name: file.Name, Modules: file.Modules})
+		fileNames = append(fileNames, file.Name)
+		for _, module := range file.Modules {
+			moduleSet[module] = true
+		}
+	}
+	var affectedModules []string
+	for module := range moduleSet {
+		affectedModules = append(affectedModules, module)
+	}
+	sort.Strings(affectedModules)
+
+	generated := make(map[string]bool)
+	for _, path := range fixture.Generated {
+		generated[path] = true
+	}
+	gitDiff := strings.TrimRight(string(diff), "\n")
+	fileClasses := commitmessage.ClassifyDiff(gitDiff, generated, budget.File)
+	gitDiff = commitmessage.SummarizeDiffWith(gitDiff, fileClasses, nil)
+
+	var docsSection string
+	if fixture.Docs {
+		docs, err := commitmessage.SelectDocs(dir, commitmessage.DocsConfig{Enabled: true}, commitmessage.DocTerms(affectedModules, fileNames))
+		if err != nil {
+			t.Fatalf("selecting docs: %v", err)
+		}
+		docsSection = commitmessage.RenderDocs(docs)
+	}
+
+	moduleContracts := make(map[string]*modules.ModuleContract)
+	for module, base := range fixture.Contracts {
+		base.Moniker = module
+		moduleContracts[module] = modules.NewModuleContract(base, dir)
+	}
+
+	contexts := buildAgentContexts(promptInput{
+		Files:       files,
+		FileClasses: fileClasses,
+		Diff:        gitDiff,
+		Modules:     affectedModules,
+		Contracts:   moduleContracts,
+		Docs:        docsSection,
+		Language:    fixture.Language,
+		Budget:      budget,
+	})
+
+	rendered := map[string]string{"top-level.md": contexts.TopLevel}
+	for module, context := range contexts.Modules {
+		rendered["module-"+module+".md"] = context
+	}
+	return rendered
+}
+
+// firstDiff shows the first differing line of two texts
+func firstDiff(want, got string) string {
+	wantLines := strings.Split(want, "\n")
+	gotLines := strings.Split(got, "\n")
+	for i := 0; i < len(wantLines) || i < len(gotLines); i++ {
+		var w, g string
+		if i < len(wantLines) {
+			w = wantLines[i]
+		}
+		if i < len(gotLines) {
+			g = gotLines[i]
+		}
+		if w != g {
+			return fmt.Sprintf("line %d:\n  want: %q\n  got:  %q", i+1, w, g)
+		}
+	}
+	return ""
+}
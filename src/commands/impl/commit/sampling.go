@@ -0,0 +1,66 @@
+package commit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/ready-to-release/eac/src/core/ai"
+	"github.com/ready-to-release/eac/src/core/mcpserver"
+)
+
+// samplingProvider generates with the model of the MCP client that runs
+// commit-ai, through the sampling endpoint of the MCP server
+type samplingProvider struct {
+	url    string
+	token  string
+	client *http.Client
+}
+
+// samplingProviderFromEnv returns the sampling provider of the MCP server
+// running this command, or nil outside a server or without sampling
+func samplingProviderFromEnv() *samplingProvider {
+	url, token := os.Getenv(mcpserver.SamplingURLEnv), os.Getenv(mcpserver.SamplingTokenEnv)
+	if url == "" || token == "" {
+		return nil
+	}
+	return &samplingProvider{url: url, token: token, client: http.DefaultClient}
+}
+
+func (p *samplingProvider) Name() string { return "mcp-sampling" }
+
+func (p *samplingProvider) Execute(ctx context.Context, input string, opts ...ai.Option) (string, error) {
+	options := ai.ApplyOptions(opts...)
+	body, err := json.Marshal(mcpserver.SamplingRequest{Input: input, Model: options.Model, MaxTokens: options.MaxTokens})
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+p.token)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("sampling request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var result mcpserver.SamplingResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("invalid sampling response (status %d): %w", resp.StatusCode, err)
+	}
+	if result.Error != "" {
+		return "", fmt.Errorf("sampling failed: %s", result.Error)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("sampling failed with status %d", resp.StatusCode)
+	}
+	return result.Text, nil
+}
@@ -0,0 +1,110 @@
+package commit
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	commitmessage "github.com/ready-to-release/eac/src/commands/impl/commit/internal"
+	"github.com/ready-to-release/eac/src/core/mcpserver"
+)
+
+// samplingEndpoint serves a sampling endpoint that answers with respond and
+// points the environment at it
+func samplingEndpoint(t *testing.T, respond func(mcpserver.SamplingRequest) mcpserver.SamplingResponse) {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			json.NewEncoder(w).Encode(mcpserver.SamplingResponse{Error: "invalid token"})
+			return
+		}
+		var req mcpserver.SamplingRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("invalid sampling request: %v", err)
+		}
+		json.NewEncoder(w).Encode(respond(req))
+	}))
+	t.Cleanup(server.Close)
+	t.Setenv(mcpserver.SamplingURLEnv, server.URL)
+	t.Setenv(mcpserver.SamplingTokenEnv, "secret")
+}
+
+func writeAgentFile(t *testing.T) (root string, agent string) {
+	t.Helper()
+	root = t.TempDir()
+	agent = filepath.Join(root, "commit-message-module.md")
+	if err := os.WriteFile(agent, []byte("---\nmodel: haiku\n---\nMODULE AGENT\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return root, agent
+}
+
+func TestSamplingProviderRoundTrip(t *testing.T) {
+	var got mcpserver.SamplingRequest
+	samplingEndpoint(t, func(req mcpserver.SamplingRequest) mcpserver.SamplingResponse {
+		got = req
+		return mcpserver.SamplingResponse{Text: "Hello!\n## core\n\nfeat: sampled", Model: "client-model"}
+	})
+	t.Setenv("R2R_AI_RECORDING", "")
+
+	root, agent := writeAgentFile(t)
+	executor, err := newAgentExecutor(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	output, err := runAgentFile(executor, commitmessage.AgentCall{Agent: agent, Input: "module: core"})
+	if err != nil {
+		t.Fatalf("runAgentFile: %v", err)
+	}
+
+	if provider := executor.GetLastUsedProvider(); provider == nil || provider.Name() != "mcp-sampling" {
+		t.Errorf("provider = %v, want mcp-sampling", provider)
+	}
+	// The agent prompt and the model of its frontmatter go to the client
+	if !strings.Contains(got.Input, "MODULE AGENT") || !strings.HasSuffix(got.Input, "module: core") || got.Model != "haiku" {
+		t.Errorf("sampling request = %+v", got)
+	}
+	// The response is cleaned like the response of any other provider
+	if output != "## core\n\nfeat: sampled" {
+		t.Errorf("output = %q", output)
+	}
+}
+
+func TestSamplingProviderErrors(t *testing.T) {
+	samplingEndpoint(t, func(req mcpserver.SamplingRequest) mcpserver.SamplingResponse {
+		return mcpserver.SamplingResponse{Error: "user rejected sampling request"}
+	})
+	provider := samplingProviderFromEnv()
+
+	if _, err := provider.Execute(t.Context(), "input"); err == nil || !strings.Contains(err.Error(), "user rejected") {
+		t.Errorf("Execute = %v, want the error of the client", err)
+	}
+
+	provider.token = "wrong"
+	if _, err := provider.Execute(t.Context(), "input"); err == nil || !strings.Contains(err.Error(), "invalid token") {
+		t.Errorf("Execute with a wrong token = %v", err)
+	}
+}
+
+func TestSamplingProviderFallback(t *testing.T) {
+	// Without the sampling endpoint the configured providers generate
+	t.Setenv(mcpserver.SamplingURLEnv, "")
+	t.Setenv(mcpserver.SamplingTokenEnv, "")
+	if provider := samplingProviderFromEnv(); provider != nil {
+		t.Fatalf("samplingProviderFromEnv = %+v, want nil", provider)
+	}
+
+	executor, err := newAgentExecutor(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	provider, didFallback := executor.LoadProvider(nil)
+	if provider.Name() != "claude-cli" || !didFallback {
+		t.Errorf("provider = %s (fallback %v), want claude-cli", provider.Name(), didFallback)
+	}
+}
@@ -0,0 +1,401 @@
+// Command: pipeline selftest
+// Description: Check that commit-ai can run: claude CLI and login, agent files, commit configuration, git state and contracts
+// Usage: go run . pipeline selftest [--format text|json] [--live]
+// Flags:
+//   --format <text|json>: Output format (default: text)
+//   --live: Send a one-word prompt to claude to confirm the login (uses a few tokens)
+// HasSideEffects: false
+package commit
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	commitmessage "github.com/ready-to-release/eac/src/commands/impl/commit/internal"
+	"github.com/ready-to-release/eac/src/commands/internal/registry"
+	"github.com/ready-to-release/eac/src/core/commitrules"
+	"github.com/ready-to-release/eac/src/core/contracts/modules"
+	"github.com/ready-to-release/eac/src/core/repository"
+)
+
+func init() {
+	registry.Register(PipelineSelftest)
+}
+
+// Statuses of a selftest check
+const (
+	checkOK      = "ok"
+	checkWarning = "warning"
+	checkError   = "error"
+)
+
+// liveCheckTimeout bounds the prompt sent with --live
+const liveCheckTimeout = 90 * time.Second
+
+// SelftestCheck is the result of one readiness check
+type SelftestCheck struct {
+	Name    string `json:"name"`
+	Status  string `json:"status"` // "ok", "warning" or "error"
+	Message string `json:"message"`
+	Fix     string `json:"fix,omitempty"` // what to do when the check did not pass
+}
+
+// SelftestReport is the machine-readable result of pipeline selftest. The
+// pipeline is ready when no check has an error.
+type SelftestReport struct {
+	Ready  bool            `json:"ready"`
+	Checks []SelftestCheck `json:"checks"`
+}
+
+// PipelineSelftest checks everything commit-ai needs before it is started,
+// so an editor can show what to fix up front. Returns 1 when not ready.
+func PipelineSelftest() int {
+	fs := flag.NewFlagSet("pipeline selftest", flag.ContinueOnError)
+	format := fs.String("format", "text", "Output format: text or json")
+	live := fs.Bool("live", false, "Send a one-word prompt to claude to confirm the login")
+
+	// Skip binary path, "pipeline" and "selftest"
+	args := []string{}
+	if len(os.Args) > 3 {
+		args = os.Args[3:]
+	}
+	if err := fs.Parse(args); err != nil {
+		return 1
+	}
+	if fs.NArg() != 0 {
+		fmt.Fprintf(os.Stderr, "Usage: pipeline selftest [--format text|json] [--live]\n")
+		return 1
+	}
+	if *format != "text" && *format != "json" {
+		fmt.Fprintf(os.Stderr, "Error: --format must be text or json\n")
+		return 1
+	}
+
+	report := runSelftest(*live)
+
+	if *format == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(report); err != nil {
+			fmt.Fprintf(os.Stderr, "Error encoding report: %v\n", err)
+			return 1
+		}
+	} else {
+		printSelftestReport(report)
+	}
+
+	if !report.Ready {
+		return 1
+	}
+	return 0
+}
+
+// runSelftest runs the checks; those needing the repository are reported as
+// failed when it cannot be found
+func runSelftest(live bool) *SelftestReport {
+	checks := []SelftestCheck{checkClaudeCLI()}
+	checks = append(checks, checkClaudeLogin(live, checks[0].Status == checkOK))
+
+	workspaceRoot, err := repository.GetRepositoryRoot("")
+	if err != nil {
+		checks = append(checks, SelftestCheck{
+			Name:    "git",
+			Status:  checkError,
+			Message: fmt.Sprintf("no git repository: %v", err),
+			Fix:     "run from inside the repository",
+		})
+		return newSelftestReport(checks)
+	}
+
+	checks = append(checks,
+		checkAgentFiles(workspaceRoot),
+		checkCommitConfig(workspaceRoot),
+		checkGitState(workspaceRoot),
+		checkContracts(workspaceRoot),
+	)
+	return newSelftestReport(checks)
+}
+
+func newSelftestReport(checks []SelftestCheck) *SelftestReport {
+	report := &SelftestReport{Ready: true, Checks: checks}
+	for _, check := range checks {
+		if check.Status == checkError {
+			report.Ready = false
+		}
+	}
+	return report
+}
+
+// checkClaudeCLI finds the claude CLI that runs the agents
+func checkClaudeCLI() SelftestCheck {
+	check := SelftestCheck{Name: "claude-cli"}
+	path, err := exec.LookPath("claude")
+	if err != nil {
+		check.Status = checkError
+		check.Message = "claude CLI not found on PATH"
+		check.Fix = "install the claude CLI and add it to PATH"
+		return check
+	}
+
+	output, err := exec.Command(path, "--version").Output()
+	if err != nil {
+		check.Status = checkError
+		check.Message = fmt.Sprintf("%s --version failed: %v", path, err)
+		check.Fix = "reinstall the claude CLI"
+		return check
+	}
+	check.Status = checkOK
+	check.Message = fmt.Sprintf("%s (%s)", strings.TrimSpace(string(output)), path)
+	return check
+}
+
+// checkClaudeLogin checks for the subscription login the agents use.
+// ANTHROPIC_API_KEY does not count: it is removed before claude runs.
+func checkClaudeLogin(live bool, cliFound bool) SelftestCheck {
+	check := SelftestCheck{Name: "claude-login"}
+	if !cliFound {
+		check.Status = checkError
+		check.Message = "not checked: claude CLI not found"
+		return check
+	}
+
+	if live {
+		ctx, cancel := context.WithTimeout(context.Background(), liveCheckTimeout)
+		defer cancel()
+		cmd := exec.CommandContext(ctx, "claude", "--print", "--model", "haiku")
+		cmd.Stdin = strings.NewReader("Reply with the single word OK.")
+		cmd.Env = removeAPIKeyFromEnv(os.Environ())
+		if output, err := cmd.CombinedOutput(); err != nil {
+			check.Status = checkError
+			check.Message = fmt.Sprintf("claude did not answer a prompt: %v: %s", err, strings.TrimSpace(strings.SplitN(string(output), "\n", 2)[0]))
+			check.Fix = "run claude and log in with /login"
+			return check
+		}
+		check.Status = checkOK
+		check.Message = "claude answered a prompt"
+		return check
+	}
+
+	home, _ := os.UserHomeDir()
+	if source := claudeCredentialsSource(os.Getenv, home); source != "" {
+		check.Status = checkOK
+		check.Message = "logged in (" + source + ")"
+		return check
+	}
+	if runtime.GOOS == "darwin" {
+		// The login is kept in the keychain, which cannot be read without a prompt
+		check.Status = checkWarning
+		check.Message = "login not verified: credentials are kept in the macOS keychain"
+		check.Fix = "run pipeline selftest --live to confirm the login"
+		return check
+	}
+	check.Status = checkError
+	check.Message = "no claude login found"
+	check.Fix = "run claude and log in with /login"
+	return check
+}
+
+// claudeCredentialsSource returns where the claude login is found: an OAuth
+// token in the environment or the credentials file of the claude
+// configuration directory. It returns "" when there is none.
+func claudeCredentialsSource(getenv func(string) string, home string) string {
+	if getenv("CLAUDE_CODE_OAUTH_TOKEN") != "" {
+		return "CLAUDE_CODE_OAUTH_TOKEN"
+	}
+	configDir := getenv("CLAUDE_CONFIG_DIR")
+	if configDir == "" && home != "" {
+		configDir = filepath.Join(home, ".claude")
+	}
+	if configDir == "" {
+		return ""
+	}
+	credentials := filepath.Join(configDir, ".credentials.json")
+	if info, err := os.Stat(credentials); err == nil && info.Size() > 0 {
+		return credentials
+	}
+	return ""
+}
+
+// checkAgentFiles validates the agent files of the pipeline stages
+func checkAgentFiles(workspaceRoot string) SelftestCheck {
+	check := SelftestCheck{Name: "agents"}
+	pipeline, err := commitmessage.LoadPipeline(workspaceRoot)
+	if err != nil {
+		check.Status = checkError
+		check.Message = fmt.Sprintf("invalid pipeline: %v", err)
+		check.Fix = "fix .claude/pipeline.yml"
+		return check
+	}
+
+	findings := commitmessage.ValidatePipelineAgents(workspaceRoot, pipeline)
+	errorCount := 0
+	for _, finding := range findings {
+		if finding.Severity == "error" {
+			errorCount++
+		}
+	}
+	switch {
+	case errorCount > 0:
+		check.Status = checkError
+		check.Message = fmt.Sprintf("%d error(s) in agent files, first: %s", errorCount, firstAgentError(findings))
+		check.Fix = "run validate agents and fix the reported files"
+	case len(findings) > 0:
+		check.Status = checkWarning
+		check.Message = fmt.Sprintf("%d stage(s) ready, %d warning(s) in agent files", len(pipeline.Stages), len(findings))
+		check.Fix = "run validate agents to see the warnings"
+	default:
+		check.Status = checkOK
+		check.Message = fmt.Sprintf("%d stage(s) ready", len(pipeline.Stages))
+	}
+	return check
+}
+
+func firstAgentError(findings []commitmessage.AgentFinding) string {
+	for _, finding := range findings {
+		if finding.Severity == "error" {
+			return finding.Error()
+		}
+	}
+	return ""
+}
+
+// checkCommitConfig loads .r2r/commit.yml and .r2r/commit-rules.yml and
+// applies the stage overrides to the pipeline
+func checkCommitConfig(workspaceRoot string) SelftestCheck {
+	check := SelftestCheck{Name: "config"}
+	config, err := commitmessage.LoadCommitConfig(workspaceRoot)
+	if err != nil {
+		check.Status = checkError
+		check.Message = err.Error()
+		check.Fix = "fix " + commitmessage.CommitConfigFile
+		return check
+	}
+	// An invalid pipeline is reported by the agents check
+	if pipeline, err := commitmessage.LoadPipeline(workspaceRoot); err == nil {
+		if err := config.ApplyToPipeline(pipeline); err != nil {
+			check.Status = checkError
+			check.Message = err.Error()
+			check.Fix = "fix the stages of " + commitmessage.CommitConfigFile
+			return check
+		}
+	}
+	if _, err := commitrules.LoadRuleConfig(workspaceRoot); err != nil {
+		check.Status = checkError
+		check.Message = err.Error()
+		check.Fix = "fix .r2r/commit-rules.yml"
+		return check
+	}
+	check.Status = checkOK
+	check.Message = "commit configuration loaded"
+	return check
+}
+
+// checkGitState reports conflicts, operations in progress and whether there
+// is anything to commit
+func checkGitState(workspaceRoot string) SelftestCheck {
+	check := SelftestCheck{Name: "git"}
+
+	conflicts, err := gitLines(workspaceRoot, "diff", "--name-only", "--diff-filter=U")
+	if err != nil {
+		check.Status = checkError
+		check.Message = fmt.Sprintf("git diff failed: %v", err)
+		return check
+	}
+	if len(conflicts) > 0 {
+		check.Status = checkError
+		check.Message = fmt.Sprintf("%d file(s) with merge conflicts, e.g. %s", len(conflicts), conflicts[0])
+		check.Fix = "resolve the conflicts and stage the files"
+		return check
+	}
+
+	staged, err := gitLines(workspaceRoot, "diff", "--cached", "--name-only")
+	if err != nil {
+		check.Status = checkError
+		check.Message = fmt.Sprintf("git diff --cached failed: %v", err)
+		return check
+	}
+	branch := currentBranch(workspaceRoot)
+
+	inProgress, err := inProgressCommit(workspaceRoot)
+	switch {
+	case err != nil:
+		check.Status = checkWarning
+		check.Message = err.Error()
+	case inProgress != nil:
+		check.Status = checkWarning
+		check.Message = fmt.Sprintf("%s in progress: commit-ai keeps git's message format", inProgress.Kind)
+	case len(staged) == 0:
+		check.Status = checkWarning
+		check.Message = "nothing staged"
+		check.Fix = "stage changes, or run commit-ai with --include-unstaged"
+	case branch == "":
+		check.Status = checkWarning
+		check.Message = fmt.Sprintf("detached HEAD, %d staged file(s)", len(staged))
+		check.Fix = "check out a branch so branch issue references are found"
+	default:
+		check.Status = checkOK
+		check.Message = fmt.Sprintf("on %s, %d staged file(s)", branch, len(staged))
+	}
+	return check
+}
+
+// checkContracts loads the module contracts that map files to modules and
+// the commit message contract the message is verified against
+func checkContracts(workspaceRoot string) SelftestCheck {
+	check := SelftestCheck{Name: "contracts"}
+	contracts, err := modules.LoadFromWorkspaceLatest(workspaceRoot)
+	if err != nil {
+		check.Status = checkError
+		check.Message = fmt.Sprintf("module contracts do not load: %v", err)
+		check.Fix = "fix the files in contracts/modules"
+		return check
+	}
+	if errs := modules.ValidateRegistry(contracts); len(errs) > 0 {
+		check.Status = checkError
+		check.Message = fmt.Sprintf("%d invalid module contract(s), first: %v", len(errs), errs[0])
+		check.Fix = "fix the files in contracts/modules"
+		return check
+	}
+
+	contractPath := filepath.Join(workspaceRoot, "contracts/commit-message/0.1.0/structure.yml")
+	if errs := commitrules.VerifyContractImplementation(contractPath); len(errs) > 0 {
+		check.Status = checkError
+		check.Message = fmt.Sprintf("commit message contract: [%s] %s", errs[0].Code, errs[0].Message)
+		check.Fix = "restore contracts/commit-message/0.1.0/structure.yml"
+		return check
+	}
+
+	check.Status = checkOK
+	check.Message = fmt.Sprintf("%d module contract(s) and the commit message contract loaded", contracts.Count())
+	return check
+}
+
+func printSelftestReport(report *SelftestReport) {
+	for _, check := range report.Checks {
+		icon := "✅"
+		switch check.Status {
+		case checkWarning:
+			icon = "⚠️ "
+		case checkError:
+			icon = "❌"
+		}
+		fmt.Printf("%s %-13s %s\n", icon, check.Name, check.Message)
+		if check.Fix != "" && check.Status != checkOK {
+			fmt.Printf("   %-13s → %s\n", "", check.Fix)
+		}
+	}
+	fmt.Println()
+	if report.Ready {
+		fmt.Println("✅ The commit pipeline is ready")
+	} else {
+		fmt.Println("❌ The commit pipeline is not ready")
+	}
+}
@@ -0,0 +1,101 @@
+package commit
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestClaudeCredentialsSource(t *testing.T) {
+	home := t.TempDir()
+	env := map[string]string{}
+	getenv := func(name string) string { return env[name] }
+
+	if source := claudeCredentialsSource(getenv, home); source != "" {
+		t.Errorf("Expected no login, got %q", source)
+	}
+
+	credentials := filepath.Join(home, ".claude", ".credentials.json")
+	os.MkdirAll(filepath.Dir(credentials), 0755)
+	if err := os.WriteFile(credentials, []byte(`{"claudeAiOauth":{}}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if source := claudeCredentialsSource(getenv, home); source != credentials {
+		t.Errorf("source = %q, want %q", source, credentials)
+	}
+
+	// CLAUDE_CONFIG_DIR moves the credentials file
+	env["CLAUDE_CONFIG_DIR"] = t.TempDir()
+	if source := claudeCredentialsSource(getenv, home); source != "" {
+		t.Errorf("Expected no login in CLAUDE_CONFIG_DIR, got %q", source)
+	}
+
+	env["CLAUDE_CODE_OAUTH_TOKEN"] = "token"
+	if source := claudeCredentialsSource(getenv, home); source != "CLAUDE_CODE_OAUTH_TOKEN" {
+		t.Errorf("source = %q, want CLAUDE_CODE_OAUTH_TOKEN", source)
+	}
+}
+
+func TestNewSelftestReport(t *testing.T) {
+	report := newSelftestReport([]SelftestCheck{{Name: "a", Status: checkOK}, {Name: "b", Status: checkWarning}})
+	if !report.Ready {
+		t.Error("Warnings should not make the pipeline unready")
+	}
+	report = newSelftestReport([]SelftestCheck{{Name: "a", Status: checkOK}, {Name: "b", Status: checkError}})
+	if report.Ready {
+		t.Error("An error should make the pipeline unready")
+	}
+}
+
+func TestCheckGitState(t *testing.T) {
+	root := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = root
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, output)
+		}
+	}
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(root, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	git("init", "-q", "-b", "main")
+	git("config", "user.email", "dev@example.com")
+	git("config", "user.name", "dev")
+	write("file.txt", "one\n")
+	git("add", ".")
+	git("commit", "-q", "-m", "init")
+
+	check := checkGitState(root)
+	if check.Status != checkWarning || check.Message != "nothing staged" {
+		t.Errorf("Unexpected check with nothing staged: %+v", check)
+	}
+
+	write("file.txt", "one\ntwo\n")
+	git("add", "file.txt")
+	check = checkGitState(root)
+	if check.Status != checkOK || check.Message != "on main, 1 staged file(s)" {
+		t.Errorf("Unexpected check with a staged file: %+v", check)
+	}
+
+	// A conflicting merge leaves unmerged files
+	git("commit", "-q", "-m", "main change")
+	git("checkout", "-q", "-b", "topic", "HEAD~1")
+	write("file.txt", "one\nthree\n")
+	git("commit", "-q", "-am", "topic change")
+	cmd := exec.Command("git", "merge", "-q", "main")
+	cmd.Dir = root
+	cmd.Run()
+
+	check = checkGitState(root)
+	if check.Status != checkError || !strings.Contains(check.Message, "merge conflicts") {
+		t.Errorf("Unexpected check with a conflict: %+v", check)
+	}
+}
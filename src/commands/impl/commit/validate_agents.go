@@ -0,0 +1,108 @@
+// Command: validate agents
+// Description: Validate the agent files in .claude/agents: frontmatter, referenced docs and prompt sections
+// Usage: go run . validate agents [--format text|json]
+// Flags:
+//   --format <text|json>: Output format (default: text)
+// HasSideEffects: false
+package commit
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	commitmessage "github.com/ready-to-release/eac/src/commands/impl/commit/internal"
+	"github.com/ready-to-release/eac/src/commands/internal/registry"
+	"github.com/ready-to-release/eac/src/core/repository"
+)
+
+func init() {
+	registry.Register(ValidateAgents)
+}
+
+// ValidateAgents checks every agent file and the agents of the commit-ai
+// pipeline. Returns 1 when an agent file has errors.
+func ValidateAgents() int {
+	fs := flag.NewFlagSet("validate agents", flag.ContinueOnError)
+	format := fs.String("format", "text", "Output format: text or json")
+
+	// Skip binary path, "validate" and "agents"
+	args := []string{}
+	if len(os.Args) > 3 {
+		args = os.Args[3:]
+	}
+	if err := fs.Parse(args); err != nil {
+		return 1
+	}
+	if fs.NArg() != 0 {
+		fmt.Fprintf(os.Stderr, "Usage: validate agents [--format text|json]\n")
+		return 1
+	}
+	if *format != "text" && *format != "json" {
+		fmt.Fprintf(os.Stderr, "Error: --format must be text or json\n")
+		return 1
+	}
+
+	workspaceRoot, err := repository.GetRepositoryRoot("")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to find repository root: %v\n", err)
+		return 1
+	}
+
+	pipeline, err := commitmessage.LoadPipeline(workspaceRoot)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading pipeline: %v\n", err)
+		return 1
+	}
+
+	report, err := commitmessage.ValidateAgents(workspaceRoot, pipeline)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error validating agents: %v\n", err)
+		return 1
+	}
+
+	if *format == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(report); err != nil {
+			fmt.Fprintf(os.Stderr, "Error encoding report: %v\n", err)
+			return 1
+		}
+	} else {
+		printAgentReport(report)
+	}
+
+	if !report.Valid {
+		return 1
+	}
+	return 0
+}
+
+func printAgentReport(report *commitmessage.AgentReport) {
+	if len(report.Findings) == 0 {
+		fmt.Printf("✅ %d agent file(s) are valid\n", len(report.Files))
+		return
+	}
+
+	if report.Errors > 0 {
+		fmt.Printf("❌ Found %d error(s)\n", report.Errors)
+	}
+	if report.Warnings > 0 {
+		fmt.Printf("⚠️  Found %d warning(s)\n", report.Warnings)
+	}
+	fmt.Println()
+
+	printAgentFindings(os.Stdout, report.Findings)
+}
+
+func printAgentFindings(w io.Writer, findings []commitmessage.AgentFinding) {
+	for _, finding := range findings {
+		icon := "❌"
+		if finding.Severity == "warning" {
+			icon = "⚠️ "
+		}
+		fmt.Fprintf(w, "%s %s\n", icon, finding.Error())
+	}
+}
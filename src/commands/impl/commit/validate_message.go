@@ -0,0 +1,251 @@
+// Command: validate commit-message
+// Description: Validate a commit message file against the commit message contract
+// Usage: go run . validate commit-message <file> [--format text|json] [--modules <a,b>]
+// Flags:
+//   --format <text|json>: Output format (default: text)
+//   --modules <a,b>: Affected modules (default: modules of the staged files)
+// HasSideEffects: false
+package commit
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	commitmessage "github.com/ready-to-release/eac/src/commands/impl/commit/internal"
+	"github.com/ready-to-release/eac/src/commands/internal/registry"
+	"github.com/ready-to-release/eac/src/core/commitrules"
+	"github.com/ready-to-release/eac/src/core/repository"
+	"github.com/ready-to-release/eac/src/core/repository/reports"
+)
+
+// scissorsLine marks the start of the diff appended by "git commit --verbose"
+const scissorsLine = "------------------------ >8 ------------------------"
+
+func init() {
+	registry.Register(ValidateCommitMessage)
+}
+
+// CommitMessageReport is the machine-readable result of validate commit-message
+type CommitMessageReport struct {
+	File     string                        `json:"file"`
+	Modules  []string                      `json:"modules"`
+	Valid    bool                          `json:"valid"`
+	Errors   int                           `json:"errors"`
+	Warnings int                           `json:"warnings"`
+	Findings []commitrules.ValidationError `json:"findings"`
+}
+
+// ValidateCommitMessage runs the commit-ai contract rules on a message file.
+// Returns 1 when the message has errors, so it can gate a commit-msg hook or CI job.
+func ValidateCommitMessage() int {
+	fs := flag.NewFlagSet("validate commit-message", flag.ContinueOnError)
+	format := fs.String("format", "text", "Output format: text or json")
+	modulesFlag := fs.String("modules", "", "Comma-separated affected modules (default: modules of the staged files)")
+
+	// Skip binary path, "validate" and "commit-message"
+	args := []string{}
+	if len(os.Args) > 3 {
+		args = os.Args[3:]
+	}
+	if err := fs.Parse(reorderArgs(args)); err != nil {
+		return 1
+	}
+	if fs.NArg() != 1 {
+		fmt.Fprintf(os.Stderr, "Usage: validate commit-message <file> [--format text|json] [--modules <a,b>]\n")
+		return 1
+	}
+	if *format != "text" && *format != "json" {
+		fmt.Fprintf(os.Stderr, "Error: --format must be text or json\n")
+		return 1
+	}
+
+	file := fs.Arg(0)
+	if !filepath.IsAbs(file) {
+		file = filepath.Join(registry.InitialWorkingDir, file)
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to read commit message: %v\n", err)
+		return 1
+	}
+
+	var affectedModules []string
+	if *modulesFlag != "" {
+		for _, module := range strings.Split(*modulesFlag, ",") {
+			if module = strings.TrimSpace(module); module != "" {
+				affectedModules = append(affectedModules, module)
+			}
+		}
+	} else {
+		affectedModules, err = stagedModules()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error getting module mappings: %v\n", err)
+			return 1
+		}
+	}
+	sort.Strings(affectedModules)
+
+	ruleConfig, err := loadRuleConfig()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	message := stripGitComments(string(data), gitCommentChar())
+
+	// Merge and revert messages keep git's format
+	var findings []commitrules.ValidationError
+	if _, special := commitmessage.SpecialCommitKind(message); !special {
+		findings = commitrules.VerifyCommitMessage(message, affectedModules, ruleConfig)
+	}
+
+	report := CommitMessageReport{
+		File:     fs.Arg(0),
+		Modules:  affectedModules,
+		Findings: findings,
+	}
+	if report.Modules == nil {
+		report.Modules = []string{}
+	}
+	if report.Findings == nil {
+		report.Findings = []commitrules.ValidationError{}
+	}
+	for _, finding := range findings {
+		if finding.Severity == "error" {
+			report.Errors++
+		} else {
+			report.Warnings++
+		}
+	}
+	report.Valid = report.Errors == 0
+
+	if *format == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(report); err != nil {
+			fmt.Fprintf(os.Stderr, "Error encoding report: %v\n", err)
+			return 1
+		}
+	} else {
+		printCommitMessageReport(report)
+	}
+
+	if !report.Valid {
+		return 1
+	}
+	return 0
+}
+
+func printCommitMessageReport(report CommitMessageReport) {
+	if len(report.Findings) == 0 {
+		fmt.Printf("✅ %s follows the commit message contract\n", report.File)
+		return
+	}
+
+	if report.Errors > 0 {
+		fmt.Printf("❌ Found %d contract violation(s)\n", report.Errors)
+	}
+	if report.Warnings > 0 {
+		fmt.Printf("⚠️  Found %d warning(s)\n", report.Warnings)
+	}
+	fmt.Println()
+
+	for _, finding := range report.Findings {
+		icon := "❌"
+		if finding.Severity == "warning" {
+			icon = "⚠️ "
+		}
+		fmt.Printf("%s %s\n", icon, finding.Error())
+	}
+}
+
+// loadRuleConfig reads .r2r/commit-rules.yml of the current repository;
+// outside a repository the default rules apply
+func loadRuleConfig() (*commitrules.RuleConfig, error) {
+	workspaceRoot, err := repository.GetRepositoryRoot("")
+	if err != nil {
+		return nil, nil
+	}
+	return commitrules.LoadRuleConfig(workspaceRoot)
+}
+
+// stagedModules returns the modules owning the staged files
+func stagedModules() ([]string, error) {
+	workspaceRoot, err := repository.GetRepositoryRoot("")
+	if err != nil {
+		return nil, fmt.Errorf("failed to find repository root: %w", err)
+	}
+
+	report, err := reports.GetFilesModulesReport(true, false, true, workspaceRoot, "0.1.0")
+	if err != nil {
+		return nil, err
+	}
+
+	moduleSet := make(map[string]bool)
+	var modules []string
+	for _, file := range report.AllFiles {
+		for _, module := range file.Modules {
+			if !moduleSet[module] {
+				moduleSet[module] = true
+				modules = append(modules, module)
+			}
+		}
+	}
+	return modules, nil
+}
+
+// gitCommentChar returns core.commentChar, or "#" when unset
+func gitCommentChar() string {
+	output, err := exec.Command("git", "config", "core.commentChar").Output()
+	if err != nil {
+		return "#"
+	}
+	if char := strings.TrimSpace(string(output)); char != "" && char != "auto" {
+		return char
+	}
+	return "#"
+}
+
+// stripGitComments drops the "git commit --verbose" diff and, unless the comment
+// character is "#" (which would also match the message headings), comment lines
+func stripGitComments(message string, commentChar string) string {
+	lines := strings.Split(strings.ReplaceAll(message, "\r\n", "\n"), "\n")
+
+	var kept []string
+	for _, line := range lines {
+		if strings.HasSuffix(line, scissorsLine) {
+			break
+		}
+		if commentChar != "#" && strings.HasPrefix(line, commentChar) {
+			continue
+		}
+		kept = append(kept, line)
+	}
+
+	return strings.TrimRight(strings.Join(kept, "\n"), "\n \t")
+}
+
+// reorderArgs moves flags before positional arguments so that
+// "validate commit-message FILE --format json" parses like flag-first input
+func reorderArgs(args []string) []string {
+	var flags, positional []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if !strings.HasPrefix(arg, "-") {
+			positional = append(positional, arg)
+			continue
+		}
+		flags = append(flags, arg)
+		if !strings.Contains(arg, "=") && i+1 < len(args) {
+			i++
+			flags = append(flags, args[i])
+		}
+	}
+	return append(flags, positional...)
+}
@@ -0,0 +1,36 @@
+package commit
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestStripGitComments(t *testing.T) {
+	message := "# cli: feat: add flag\n\nBody text\n\n" +
+		"# ------------------------ >8 ------------------------\n" +
+		"diff --git a/x b/x\n"
+
+	got := stripGitComments(message, "#")
+	want := "# cli: feat: add flag\n\nBody text"
+	if got != want {
+		t.Errorf("stripGitComments() = %q, want %q", got, want)
+	}
+}
+
+func TestStripGitComments_CustomCommentChar(t *testing.T) {
+	message := "# cli: feat: add flag\n\nBody text\n; Please enter the commit message\n"
+
+	got := stripGitComments(message, ";")
+	want := "# cli: feat: add flag\n\nBody text"
+	if got != want {
+		t.Errorf("stripGitComments() = %q, want %q", got, want)
+	}
+}
+
+func TestReorderArgs(t *testing.T) {
+	got := reorderArgs([]string{"msg.txt", "--format", "json", "--modules=cli"})
+	want := []string{"--format", "json", "--modules=cli", "msg.txt"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("reorderArgs() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,132 @@
+package commit
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ready-to-release/eac/src/core/repository"
+)
+
+// emptyTreeHash is git's empty tree, the base of a repository without commits
+const emptyTreeHash = "4b825dc642cb6eb9a060e54bf8d69288fbee4904"
+
+// workingTree holds the changes of the working tree against HEAD, staged or not
+type workingTree struct {
+	Files    []repository.RepositoryFileWithModule // added, copied, modified and renamed files
+	Diff     string                                // tracked changes followed by untracked files
+	Unstaged []string                              // files with changes that are not staged yet
+}
+
+// collectWorkingTree reads all changes of the working tree, including
+// untracked files that are not ignored
+func collectWorkingTree(workspaceRoot string) (*workingTree, error) {
+	base := "HEAD"
+	if err := exec.Command("git", "-C", workspaceRoot, "rev-parse", "--verify", "-q", "HEAD").Run(); err != nil {
+		base = emptyTreeHash
+	}
+
+	changed, err := gitLines(workspaceRoot, "diff", base, "--name-only", "--diff-filter=ACMR")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list changed files: %w", err)
+	}
+	untracked, err := gitLines(workspaceRoot, "ls-files", "--others", "--exclude-standard")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list untracked files: %w", err)
+	}
+
+	diffOutput, err := exec.Command("git", "-C", workspaceRoot, "diff", base).Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get working tree diff: %w", err)
+	}
+	var diff bytes.Buffer
+	diff.Write(diffOutput)
+	for _, file := range untracked {
+		// --no-index exits 1 when the files differ, which they always do here
+		output, err := exec.Command("git", "-C", workspaceRoot, "diff", "--no-index", "--", "/dev/null", file).Output()
+		var exitErr *exec.ExitError
+		if err != nil && !(errors.As(err, &exitErr) && exitErr.ExitCode() == 1) {
+			return nil, fmt.Errorf("failed to diff untracked file %s: %w", file, err)
+		}
+		diff.Write(output)
+	}
+
+	var infos []repository.FileInfo
+	for _, file := range append(changed, untracked...) {
+		infos = append(infos, repository.FileInfo{
+			Path:         file,
+			AbsolutePath: filepath.Join(workspaceRoot, file),
+			IsTracked:    true,
+		})
+	}
+	files, err := repository.EnrichFilesWithModules(infos, workspaceRoot, "0.1.0")
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].Name < files[j].Name })
+
+	unstaged, err := unstagedFiles(workspaceRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	return &workingTree{Files: files, Diff: diff.String(), Unstaged: unstaged}, nil
+}
+
+// unstagedFiles lists the files "git add" would stage: modified or deleted in
+// the working tree, and untracked
+func unstagedFiles(workspaceRoot string) ([]string, error) {
+	output, err := exec.Command("git", "-C", workspaceRoot, "status", "--porcelain=v1", "-z", "--untracked-files=all").Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read git status: %w", err)
+	}
+
+	var files []string
+	entries := strings.Split(string(output), "\x00")
+	for i := 0; i < len(entries); i++ {
+		entry := entries[i]
+		if len(entry) < 4 {
+			continue
+		}
+		x, y, path := entry[0], entry[1], entry[3:]
+		if x == 'R' || x == 'C' {
+			i++ // the next entry is the source of the rename or copy
+		}
+		if y != ' ' || (x == '?' && y == '?') {
+			files = append(files, path)
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// stageFiles stages files, including deletions
+func stageFiles(workspaceRoot string, files []string) error {
+	if len(files) == 0 {
+		return nil
+	}
+	args := append([]string{"-C", workspaceRoot, "add", "--all", "--"}, files...)
+	if output, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+		return fmt.Errorf("git add failed: %v\n%s", err, output)
+	}
+	return nil
+}
+
+// gitLines runs git in workspaceRoot and returns the non-empty output lines
+func gitLines(workspaceRoot string, args ...string) ([]string, error) {
+	output, err := exec.Command("git", append([]string{"-C", workspaceRoot}, args...)...).Output()
+	if err != nil {
+		return nil, err
+	}
+	var lines []string
+	for _, line := range strings.Split(string(output), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines, nil
+}
@@ -0,0 +1,73 @@
+package commit
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCollectWorkingTree(t *testing.T) {
+	root := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = root
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, output)
+		}
+	}
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(root, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	os.MkdirAll(filepath.Join(root, "contracts", "modules", "0.1.0"), 0755)
+	write("contracts/modules/0.1.0/docs.yml", "moniker: docs\nsource:\n  root: .\n  includes: [\"**\"]\n  is_catch_all_singleton: true\n")
+
+	git("init", "-q")
+	git("config", "user.email", "dev@example.com")
+	git("config", "user.name", "dev")
+	write("modified.txt", "one\n")
+	write("deleted.txt", "gone\n")
+	git("add", ".")
+	git("commit", "-q", "-m", "init")
+
+	write("modified.txt", "one\ntwo\n")
+	write("staged.txt", "staged\n")
+	git("add", "staged.txt")
+	write("untracked.txt", "new\n")
+	os.Remove(filepath.Join(root, "deleted.txt"))
+
+	tree, err := collectWorkingTree(root)
+	if err != nil {
+		t.Fatalf("collectWorkingTree: %v", err)
+	}
+
+	var files []string
+	for _, file := range tree.Files {
+		files = append(files, file.Name)
+	}
+	if want := []string{"modified.txt", "staged.txt", "untracked.txt"}; !reflect.DeepEqual(files, want) {
+		t.Errorf("files = %v, want %v", files, want)
+	}
+	if want := []string{"deleted.txt", "modified.txt", "untracked.txt"}; !reflect.DeepEqual(tree.Unstaged, want) {
+		t.Errorf("unstaged = %v, want %v", tree.Unstaged, want)
+	}
+	for _, part := range []string{"+two", "+staged", "-gone", "b/untracked.txt", "+new"} {
+		if !strings.Contains(tree.Diff, part) {
+			t.Errorf("expected %q in the diff:\n%s", part, tree.Diff)
+		}
+	}
+
+	if err := stageFiles(root, tree.Unstaged); err != nil {
+		t.Fatalf("stageFiles: %v", err)
+	}
+	if unstaged, err := unstagedFiles(root); err != nil || len(unstaged) != 0 {
+		t.Errorf("expected everything staged, got %v, %v", unstaged, err)
+	}
+}
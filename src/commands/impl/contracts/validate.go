@@ -0,0 +1,104 @@
+// Command: contracts validate
+// Description: Validate module contracts: duplicates, dangling references, overlapping and unowned files
+// Usage: go run . contracts validate [--as-json] [--strict]
+// Flags:
+//   --as-json: Output the report as JSON
+//   --strict: Exit non-zero on warnings as well as errors
+// HasSideEffects: false
+package contracts
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ready-to-release/eac/src/commands/internal/registry"
+	"github.com/ready-to-release/eac/src/commands/internal/render"
+	"github.com/ready-to-release/eac/src/core/contracts/modules"
+	"github.com/ready-to-release/eac/src/core/repository"
+)
+
+func init() {
+	registry.Register(ContractsValidate)
+}
+
+// ContractsValidate checks the module contracts against each other and against
+// the tracked files of the repository
+func ContractsValidate() int {
+	asJSON := false
+	strict := false
+	for _, arg := range os.Args[3:] {
+		switch arg {
+		case "--as-json":
+			asJSON = true
+		case "--strict":
+			strict = true
+		default:
+			fmt.Fprintf(os.Stderr, "Error: unknown flag: %s\n", arg)
+			return 1
+		}
+	}
+
+	workspaceRoot, err := repository.GetRepositoryRoot("")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to find repository root: %v\n", err)
+		return 1
+	}
+
+	tracked, err := repository.GetRepositoryFiles(true, false, false, false, workspaceRoot)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error listing repository files: %v\n", err)
+		return 1
+	}
+	files := make([]string, 0, len(tracked))
+	for _, file := range tracked {
+		files = append(files, filepath.ToSlash(file.Path))
+	}
+
+	report, err := modules.ValidateWorkspace(workspaceRoot, "0.1.0", files)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error validating contracts: %v\n", err)
+		return 1
+	}
+
+	if asJSON {
+		output, err := render.RenderAsJSON(report)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error rendering JSON: %v\n", err)
+			return 1
+		}
+		fmt.Println(output)
+	} else {
+		printContractsReport(report)
+	}
+
+	if report.HasErrors() || (strict && len(report.Issues) > 0) {
+		return 1
+	}
+	return 0
+}
+
+func printContractsReport(report *modules.ValidationReport) {
+	errors := report.Count(modules.SeverityError)
+	warnings := report.Count(modules.SeverityWarning)
+
+	if len(report.Issues) == 0 {
+		fmt.Printf("✅ %d module contract(s) valid, %d file(s) each owned by one module\n", report.Modules, report.Files)
+		return
+	}
+
+	tb := render.NewTableBuilder().
+		WithHeaders("Severity", "Code", "Module", "Path", "Message", "Suggestion")
+
+	for _, issue := range report.Issues {
+		icon := "❌"
+		if issue.Severity == modules.SeverityWarning {
+			icon = "⚠️"
+		}
+		tb.AddRow(icon+" "+issue.Severity, issue.Code, issue.Module, issue.Path, issue.Message, issue.Suggestion)
+	}
+
+	fmt.Println(tb.Build())
+	fmt.Println()
+	fmt.Printf("%d module contract(s), %d file(s): %d error(s), %d warning(s)\n", report.Modules, report.Files, errors, warnings)
+}
@@ -22,6 +22,9 @@ type CommandInfo struct {
 	Description string   `json:"description"` // Command description
 	Parent      string   `json:"parent"`      // Parent command: "show" (empty for root)
 	IsLeaf      bool     `json:"is_leaf"`     // True if this is an executable command
+
+	HasSideEffects bool `json:"has_side_effects"` // Modifies repository files
+	SupportsDryRun bool `json:"supports_dry_run"` // Previews its changes with --dry-run
 }
 
 // CommandTree represents the hierarchical structure
@@ -59,6 +62,9 @@ func buildCommandTree() CommandTree {
 			Parts:       parts,
 			Description: reg.Description,
 			IsLeaf:      true,
+
+			HasSideEffects: reg.HasSideEffects,
+			SupportsDryRun: reg.SupportsDryRun,
 		}
 
 		// Determine parent
@@ -25,7 +25,7 @@ func Design() int {
 
 	// Check for subcommands
 	switch args[0] {
-	case "new", "add", "export":
+	case "new", "add", "export", "generate":
 		// Handled by separate registrations in respective files
 		return 0
 	case "serve":
@@ -34,8 +34,8 @@ func Design() int {
 	case "list":
 		// Handled by separate registration
 		return 0
-	case "validate":
-		// Handled by separate registration
+	case "validate", "lint":
+		// Handled by separate registrations
 		return 0
 	case "--help", "-h":
 		printDesignUsage()
@@ -57,6 +57,7 @@ func printDesignUsage() {
 	fmt.Println("  add container             Add container to workspace")
 	fmt.Println("  add relationship          Add relationship between containers")
 	fmt.Println("  export <module>           Export workspace DSL content")
+	fmt.Println("  generate <module>         Generate containers and relationships from module contracts")
 	fmt.Println()
 	fmt.Println("Viewing Subcommands:")
 	fmt.Println("  serve <module>            Start Structurizr Lite viewer")
@@ -65,6 +66,7 @@ func printDesignUsage() {
 	fmt.Println("Validation Subcommands:")
 	fmt.Println("  validate <module>         Validate workspace file for one module")
 	fmt.Println("  validate --all            Validate workspace files for all modules")
+	fmt.Println("  lint <module>             Lint workspace file without Docker (--all, --format json)")
 	fmt.Println()
 	fmt.Println("Examples:")
 	fmt.Println("  # Create workspace")
@@ -76,6 +78,9 @@ func printDesignUsage() {
 	fmt.Println("  # Add relationships")
 	fmt.Println("  go run . design add relationship src-cli parser executor --desc \"sends to\"")
 	fmt.Println()
+	fmt.Println("  # Generate the model from contracts/modules")
+	fmt.Println("  go run . design generate src-cli")
+	fmt.Println()
 	fmt.Println("  # Export workspace")
 	fmt.Println("  go run . design export src-cli")
 	fmt.Println()
@@ -88,6 +93,7 @@ func printDesignUsage() {
 	fmt.Println("  # Validate workspace")
 	fmt.Println("  go run . design validate src-cli")
 	fmt.Println("  go run . design validate --all")
+	fmt.Println("  go run . design lint src-cli --format json")
 	fmt.Println()
 	fmt.Println("For detailed help on a subcommand:")
 	fmt.Println("  go run . design <subcommand> --help")
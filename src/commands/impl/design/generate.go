@@ -0,0 +1,102 @@
+// Command: design generate
+// Description: Generate workspace containers and relationships from the module contracts
+// Usage: design generate <module> [--stdout]
+// HasSideEffects: true
+package design
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ready-to-release/eac/src/commands/impl/design/internal/workspace"
+	"github.com/ready-to-release/eac/src/commands/internal/registry"
+	"github.com/ready-to-release/eac/src/core/contracts/modules"
+	"github.com/ready-to-release/eac/src/core/repository"
+)
+
+func init() {
+	registry.Register(DesignGenerate)
+}
+
+// DesignGenerate scaffolds the model of a workspace from contracts/modules:
+// one container per module, relationships from depends_on
+func DesignGenerate() int {
+	args := os.Args[3:] // Skip "design" and "generate"
+
+	var module string
+	var stdout bool
+
+	for _, arg := range args {
+		switch arg {
+		case "--stdout":
+			stdout = true
+		case "--help", "-h":
+			printDesignGenerateUsage()
+			return 0
+		default:
+			if arg[0] == '-' {
+				fmt.Fprintf(os.Stderr, "Error: unknown flag: %s\n\n", arg)
+				printDesignGenerateUsage()
+				return 1
+			}
+			module = arg
+		}
+	}
+
+	if module == "" && !stdout {
+		fmt.Fprintf(os.Stderr, "Error: module is required\n\n")
+		printDesignGenerateUsage()
+		return 1
+	}
+
+	root, err := repository.GetRepositoryRoot("")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to find repository root: %v\n", err)
+		return 1
+	}
+	contracts, err := modules.LoadFromWorkspaceLatest(root)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to load module contracts: %v\n", err)
+		return 1
+	}
+
+	if stdout {
+		fmt.Print(workspace.GenerateContractsDSL(contracts.All()))
+		return 0
+	}
+
+	result, err := workspace.GenerateFromContracts(module, contracts.All())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	fmt.Println(result)
+	return 0
+}
+
+func printDesignGenerateUsage() {
+	fmt.Println("Generate workspace containers and relationships from the module contracts")
+	fmt.Println()
+	fmt.Println("Usage:")
+	fmt.Println("  go run . design generate <module> [--stdout]")
+	fmt.Println()
+	fmt.Println("Parameters:")
+	fmt.Println("  <module>              Module moniker of the workspace (e.g., src-cli)")
+	fmt.Println()
+	fmt.Println("Flags:")
+	fmt.Println("  --stdout              Print the generated model instead of writing it")
+	fmt.Println("  --help, -h            Show this help message")
+	fmt.Println()
+	fmt.Println("Each module contract in contracts/modules becomes a container tagged")
+	fmt.Println("\"Module\" and its type, with its type as technology. Each depends_on entry")
+	fmt.Println("becomes a \"Depends on\" relationship. The catch-all module is left out.")
+	fmt.Println()
+	fmt.Println("Examples:")
+	fmt.Println("  go run . design new src-cli --name \"CLI\" --description \"CLI Architecture\"")
+	fmt.Println("  go run . design generate src-cli")
+	fmt.Println()
+	fmt.Println("Output:")
+	fmt.Println("  Writes: specs/<module>/design/contracts.dsl (regenerated on every run)")
+	fmt.Println("  Adds \"!include contracts.dsl\" to the system block of workspace.dsl once")
+}
@@ -0,0 +1,664 @@
+// Package design provides a built-in linter for Structurizr workspace files
+// that runs without Docker
+package design
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Lint rule identifiers, reported in ValidationMessage.Rule
+const (
+	RuleSyntax              = "syntax"
+	RuleDuplicateIdentifier = "duplicate-identifier"
+	RuleUnresolvedReference = "unresolved-reference"
+	RuleDuplicateViewKey    = "duplicate-view-key"
+	RuleEmptyView           = "empty-view"
+	RuleUnusedStyle         = "unused-style"
+	RuleDuplicateStyle      = "duplicate-style"
+	RuleStyleValue          = "style-value"
+)
+
+// dslStatement is one statement of a DSL file: the tokens up to an opening
+// brace, a closing brace, or the end of the line
+type dslStatement struct {
+	line   int
+	tokens []string
+	open   bool // followed by {
+	close  bool // a closing }
+}
+
+// elementTagIndex is the position of the tags argument of each element keyword
+var elementTagIndex = map[string]int{
+	"person":                 3,
+	"softwaresystem":         3,
+	"container":              4,
+	"component":              4,
+	"element":                4,
+	"deploymentnode":         4,
+	"infrastructurenode":     4,
+	"softwaresysteminstance": 3,
+	"containerinstance":      3,
+	"deploymentenvironment":  0,
+	"deploymentgroup":        0,
+	"group":                  0,
+}
+
+// elementDefaultTags are the tags Structurizr adds to every element of a kind
+var elementDefaultTags = map[string]string{
+	"person":                 "Person",
+	"softwaresystem":         "Software System",
+	"container":              "Container",
+	"component":              "Component",
+	"deploymentnode":         "Deployment Node",
+	"infrastructurenode":     "Infrastructure Node",
+	"softwaresysteminstance": "Software System Instance",
+	"containerinstance":      "Container Instance",
+	"group":                  "Group",
+}
+
+// viewKeyIndex is the position of the key argument of each view keyword
+var viewKeyIndex = map[string]int{
+	"systemlandscape": 1,
+	"systemcontext":   2,
+	"container":       2,
+	"component":       2,
+	"dynamic":         2,
+	"deployment":      3,
+	"filtered":        4,
+	"custom":          1,
+	"image":           2,
+}
+
+// styleShapes are the shapes Structurizr can draw
+var styleShapes = map[string]bool{
+	"box": true, "roundedbox": true, "circle": true, "ellipse": true, "hexagon": true,
+	"diamond": true, "cylinder": true, "bucket": true, "pipe": true, "person": true,
+	"robot": true, "folder": true, "webbrowser": true, "window": true, "terminal": true,
+	"shell": true, "mobiledeviceportrait": true, "mobiledevicelandscape": true, "component": true,
+}
+
+var (
+	hexColorPattern   = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+	namedColorPattern = regexp.MustCompile(`^[a-zA-Z]+$`)
+)
+
+// lintFrame is an open block while walking the statements
+type lintFrame struct {
+	kind       string // workspace, model, element, relationship, views, view, styles, style, animation, other
+	identifier string // element identifier, for hierarchical identifiers
+	view       *lintView
+}
+
+// lintView tracks a view until its block closes
+type lintView struct {
+	kind    string
+	line    int
+	content bool // include statements, or relationships of a dynamic view
+}
+
+// lintReference is an identifier used before the whole file is known
+type lintReference struct {
+	line       int
+	identifier string
+	context    string
+}
+
+// lintStyle is an element or relationship style in the styles block
+type lintStyle struct {
+	line         int
+	tag          string
+	relationship bool
+}
+
+// dslLinter collects definitions and references of one workspace file
+type dslLinter struct {
+	messages     []ValidationMessage
+	identifiers  map[string]int // identifier -> line of definition
+	shortNames   map[string]bool
+	environments map[string]bool
+	elementTags  map[string]bool
+	relTags      map[string]bool
+	viewKeys     map[string]int
+	references   []lintReference
+	envRefs      []lintReference
+	styles       []lintStyle
+	hierarchical bool
+	external     bool // extends or includes other files, so definitions may be missing
+}
+
+// LintDSL checks the content of a workspace.dsl file for syntax errors,
+// duplicate identifiers, unresolved element references, views without
+// elements and style issues. Messages are sorted by line.
+func LintDSL(content string) []ValidationMessage {
+	l := &dslLinter{
+		identifiers:  map[string]int{},
+		shortNames:   map[string]bool{},
+		environments: map[string]bool{},
+		elementTags:  map[string]bool{"Element": true},
+		relTags:      map[string]bool{"Relationship": true},
+		viewKeys:     map[string]int{},
+	}
+
+	statements := l.tokenize(content)
+	var stack []lintFrame
+	for _, stmt := range statements {
+		if stmt.close {
+			if len(stack) == 0 {
+				l.add(stmt.line, "error", RuleSyntax, "unexpected '}' without an open block")
+				continue
+			}
+			l.closeFrame(stack[len(stack)-1])
+			stack = stack[:len(stack)-1]
+			continue
+		}
+
+		var parent lintFrame
+		if len(stack) > 0 {
+			parent = stack[len(stack)-1]
+		}
+		frame := l.statement(stmt, parent)
+		if stmt.open {
+			stack = append(stack, frame)
+		}
+	}
+	for len(stack) > 0 {
+		l.add(len(strings.Split(content, "\n")), "error", RuleSyntax, fmt.Sprintf("%s block is not closed", stack[len(stack)-1].kind))
+		stack = stack[:len(stack)-1]
+	}
+
+	l.resolve()
+
+	sort.SliceStable(l.messages, func(i, j int) bool { return l.messages[i].Line < l.messages[j].Line })
+	return l.messages
+}
+
+// LintWorkspace lints the workspace.dsl file at path
+func LintWorkspace(module string, path string) (*ValidationResult, error) {
+	start := time.Now()
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read workspace: %w", err)
+	}
+
+	result := &ValidationResult{
+		Module:        module,
+		WorkspacePath: path,
+		Errors:        []ValidationMessage{},
+		Warnings:      []ValidationMessage{},
+		Timestamp:     start,
+	}
+	for _, msg := range LintDSL(string(content)) {
+		if msg.Severity == "error" {
+			result.Errors = append(result.Errors, msg)
+		} else {
+			result.Warnings = append(result.Warnings, msg)
+		}
+	}
+	result.Valid = len(result.Errors) == 0
+	result.ExecutionTime = time.Since(start)
+	return result, nil
+}
+
+// LintModule lints the workspace of a module
+func LintModule(module string) (*ValidationResult, error) {
+	if err := ValidateModule(module); err != nil {
+		return nil, err
+	}
+	return LintWorkspace(module, filepath.Join(GetModulePath(module), "workspace.dsl"))
+}
+
+// LintAll lints the workspaces of all modules
+func LintAll() (*ValidationSummary, error) {
+	start := time.Now()
+	modules, err := ListAvailableModules()
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &ValidationSummary{Results: []ValidationResult{}, Timestamp: start}
+	for _, module := range modules {
+		result, err := LintModule(module.Name)
+		if err != nil {
+			return nil, fmt.Errorf("module %s: %w", module.Name, err)
+		}
+		summary.TotalModules++
+		if result.Valid {
+			summary.PassedModules++
+		} else {
+			summary.FailedModules++
+		}
+		summary.TotalErrors += len(result.Errors)
+		summary.TotalWarnings += len(result.Warnings)
+		summary.Results = append(summary.Results, *result)
+	}
+	summary.ExecutionTime = time.Since(start)
+	return summary, nil
+}
+
+func (l *dslLinter) add(line int, severity, rule, message string) {
+	l.messages = append(l.messages, ValidationMessage{Severity: severity, Message: message, Line: line, Rule: rule})
+}
+
+// tokenize splits content into statements, dropping comments and keeping
+// quoted strings as single tokens without their quotes
+func (l *dslLinter) tokenize(content string) []dslStatement {
+	var statements []dslStatement
+	inBlockComment := false
+
+	for i, raw := range strings.Split(content, "\n") {
+		line := i + 1
+		var tokens []string
+		flush := func(open bool) {
+			if len(tokens) > 0 || open {
+				statements = append(statements, dslStatement{line: line, tokens: tokens, open: open})
+			}
+			tokens = nil
+		}
+
+		for pos := 0; pos < len(raw); {
+			rest := raw[pos:]
+			if inBlockComment {
+				end := strings.Index(rest, "*/")
+				if end < 0 {
+					pos = len(raw)
+					continue
+				}
+				inBlockComment = false
+				pos += end + 2
+				continue
+			}
+
+			switch c := raw[pos]; {
+			case c == ' ' || c == '\t' || c == '\r':
+				pos++
+			case strings.HasPrefix(rest, "/*"):
+				inBlockComment = true
+				pos += 2
+			case strings.HasPrefix(rest, "//") || (c == '#' && len(tokens) == 0):
+				pos = len(raw)
+			case c == '{':
+				flush(true)
+				pos++
+			case c == '}':
+				flush(false)
+				statements = append(statements, dslStatement{line: line, close: true})
+				pos++
+			case c == '"':
+				end := pos + 1
+				var value strings.Builder
+				for end < len(raw) && raw[end] != '"' {
+					if raw[end] == '\\' && end+1 < len(raw) {
+						end++
+					}
+					value.WriteByte(raw[end])
+					end++
+				}
+				if end >= len(raw) {
+					l.add(line, "error", RuleSyntax, "unterminated string")
+				}
+				tokens = append(tokens, value.String())
+				pos = end + 1
+			default:
+				end := pos
+				for end < len(raw) && !strings.ContainsRune(" \t\r{}\"", rune(raw[end])) {
+					end++
+				}
+				tokens = append(tokens, raw[pos:end])
+				pos = end
+			}
+		}
+		flush(false)
+	}
+	return statements
+}
+
+// statement handles one statement in the block of parent and returns the frame
+// of the block it opens
+func (l *dslLinter) statement(stmt dslStatement, parent lintFrame) lintFrame {
+	tokens := stmt.tokens
+	if len(tokens) == 0 {
+		return lintFrame{kind: "other"}
+	}
+
+	// Assignments: <identifier> = <element or relationship>
+	identifier := ""
+	if len(tokens) >= 3 && tokens[1] == "=" {
+		identifier = tokens[0]
+		tokens = tokens[2:]
+	}
+	keyword := strings.ToLower(tokens[0])
+
+	switch {
+	case keyword == "workspace":
+		if len(tokens) > 1 && strings.ToLower(tokens[1]) == "extends" {
+			l.external = true
+		}
+		return lintFrame{kind: "workspace"}
+	case keyword == "!include" || keyword == "!extend" || keyword == "!plugin" || keyword == "!script":
+		l.external = true
+		return lintFrame{kind: "other"}
+	case keyword == "!identifiers":
+		l.hierarchical = len(tokens) > 1 && strings.ToLower(tokens[1]) == "hierarchical"
+		return lintFrame{kind: "other"}
+	case keyword == "!element" || keyword == "!ref":
+		if len(tokens) > 1 {
+			l.reference(stmt.line, tokens[1], keyword)
+		}
+		return lintFrame{kind: "element", identifier: tokens[len(tokens)-1]}
+	}
+
+	switch parent.kind {
+	case "workspace":
+		switch keyword {
+		case "model":
+			return lintFrame{kind: "model"}
+		case "views":
+			return lintFrame{kind: "views"}
+		}
+	case "views":
+		if keyword == "styles" {
+			return lintFrame{kind: "styles"}
+		}
+		if _, ok := viewKeyIndex[keyword]; ok {
+			return l.view(stmt, tokens, keyword)
+		}
+	case "styles":
+		if (keyword == "element" || keyword == "relationship") && len(tokens) > 1 {
+			l.styles = append(l.styles, lintStyle{line: stmt.line, tag: tokens[1], relationship: keyword == "relationship"})
+			return lintFrame{kind: "style"}
+		}
+	case "style":
+		l.styleProperty(stmt.line, keyword, tokens)
+		return lintFrame{kind: "other"}
+	case "view":
+		if keyword == "animation" {
+			return lintFrame{kind: "animation"}
+		}
+		l.viewStatement(stmt.line, keyword, tokens, parent.view)
+		return lintFrame{kind: "other"}
+	case "animation":
+		for _, ref := range tokens {
+			l.reference(stmt.line, ref, "animation")
+		}
+		return lintFrame{kind: "other"}
+	}
+
+	if parent.kind == "relationship" && keyword == "tags" {
+		for _, tag := range tokens[1:] {
+			l.addTags(l.relTags, tag)
+		}
+		return lintFrame{kind: "other"}
+	}
+	if parent.kind == "model" || parent.kind == "element" {
+		if arrow := arrowIndex(tokens); arrow >= 0 {
+			l.relationship(stmt.line, identifier, tokens, arrow, parent)
+			return lintFrame{kind: "relationship"}
+		}
+		if keyword == "tags" {
+			for _, tag := range tokens[1:] {
+				l.addTags(l.elementTags, tag)
+			}
+			return lintFrame{kind: "other"}
+		}
+		if tagIndex, ok := elementTagIndex[keyword]; ok {
+			return l.element(stmt, identifier, keyword, tokens, tagIndex, parent)
+		}
+	}
+	return lintFrame{kind: "other"}
+}
+
+// element registers an element definition and its tags
+func (l *dslLinter) element(stmt dslStatement, identifier, keyword string, tokens []string, tagIndex int, parent lintFrame) lintFrame {
+	if tag, ok := elementDefaultTags[keyword]; ok {
+		l.elementTags[tag] = true
+	}
+	if tagIndex > 0 && len(tokens) > tagIndex {
+		l.addTags(l.elementTags, tokens[tagIndex])
+	}
+
+	switch keyword {
+	case "softwaresysteminstance", "containerinstance":
+		if len(tokens) > 1 {
+			l.reference(stmt.line, tokens[1], keyword)
+		}
+	case "deploymentenvironment":
+		if len(tokens) > 1 {
+			l.environments[tokens[1]] = true
+		}
+	}
+
+	fullIdentifier := identifier
+	if identifier != "" {
+		if l.hierarchical && parent.identifier != "" {
+			fullIdentifier = parent.identifier + "." + identifier
+		}
+		l.define(stmt.line, fullIdentifier)
+	} else if keyword == "group" {
+		// Groups are transparent for hierarchical identifiers
+		fullIdentifier = parent.identifier
+	}
+	return lintFrame{kind: "element", identifier: fullIdentifier}
+}
+
+// arrowIndex returns the position of the relationship arrow in tokens, or -1
+func arrowIndex(tokens []string) int {
+	for i, token := range tokens {
+		if token == "->" || (strings.HasPrefix(token, "-") && strings.HasSuffix(token, "->")) {
+			return i
+		}
+	}
+	return -1
+}
+
+// relationship registers "[id =] [source] -> destination [description] [technology] [tags]";
+// without a source the enclosing element is the source
+func (l *dslLinter) relationship(line int, identifier string, tokens []string, arrow int, parent lintFrame) {
+	if arrow+1 >= len(tokens) {
+		l.add(line, "error", RuleSyntax, "relationship without a destination")
+		return
+	}
+	if arrow == 1 {
+		l.reference(line, tokens[0], "relationship source")
+	} else if arrow == 0 && parent.kind != "element" {
+		l.add(line, "error", RuleSyntax, "relationship without a source outside an element block")
+	}
+	l.reference(line, tokens[arrow+1], "relationship destination")
+	if len(tokens) > arrow+4 {
+		l.addTags(l.relTags, tokens[arrow+4])
+	}
+	if identifier != "" {
+		l.define(line, identifier)
+	}
+}
+
+// view registers a view and checks its scope and key
+func (l *dslLinter) view(stmt dslStatement, tokens []string, keyword string) lintFrame {
+	switch keyword {
+	case "systemcontext", "container", "component":
+		if len(tokens) > 1 {
+			l.reference(stmt.line, tokens[1], keyword+" view")
+		} else {
+			l.add(stmt.line, "error", RuleSyntax, fmt.Sprintf("%s view without a scope", tokens[0]))
+		}
+	case "dynamic", "image":
+		if len(tokens) > 1 && tokens[1] != "*" {
+			l.reference(stmt.line, tokens[1], keyword+" view")
+		}
+	case "deployment":
+		if len(tokens) > 1 && tokens[1] != "*" {
+			l.reference(stmt.line, tokens[1], "deployment view")
+		}
+		if len(tokens) > 2 {
+			l.envRefs = append(l.envRefs, lintReference{line: stmt.line, identifier: tokens[2], context: "deployment view"})
+		}
+	}
+
+	if index := viewKeyIndex[keyword]; len(tokens) > index {
+		key := tokens[index]
+		if first, ok := l.viewKeys[key]; ok {
+			l.add(stmt.line, "error", RuleDuplicateViewKey, fmt.Sprintf("view key %q is already used on line %d", key, first))
+		} else {
+			l.viewKeys[key] = stmt.line
+		}
+	}
+
+	return lintFrame{kind: "view", view: &lintView{kind: keyword, line: stmt.line}}
+}
+
+// viewStatement handles include, exclude and the relationships of dynamic views
+func (l *dslLinter) viewStatement(line int, keyword string, tokens []string, view *lintView) {
+	switch {
+	case keyword == "include" || keyword == "exclude":
+		if keyword == "include" {
+			view.content = true
+		}
+		for _, expression := range tokens[1:] {
+			if isViewExpression(expression) {
+				continue
+			}
+			l.reference(line, expression, keyword)
+		}
+	case view.kind == "dynamic" && len(tokens) >= 3 && strings.HasSuffix(tokens[1], "->"):
+		view.content = true
+		l.reference(line, tokens[0], "dynamic view")
+		l.reference(line, tokens[2], "dynamic view")
+	}
+}
+
+// isViewExpression reports wildcards and expressions, which are not identifiers
+func isViewExpression(expression string) bool {
+	return expression == "*" || strings.Contains(expression, "->") || strings.Contains(expression, "==") ||
+		strings.Contains(expression, "!=") || strings.HasPrefix(expression, "element.") || strings.HasPrefix(expression, "relationship.")
+}
+
+// styleProperty checks the value of a style property
+func (l *dslLinter) styleProperty(line int, property string, tokens []string) {
+	if len(tokens) < 2 {
+		l.add(line, "warning", RuleStyleValue, fmt.Sprintf("style property %s has no value", tokens[0]))
+		return
+	}
+	value := tokens[1]
+	switch property {
+	case "background", "color", "colour", "stroke":
+		if !hexColorPattern.MatchString(value) && !namedColorPattern.MatchString(value) {
+			l.add(line, "warning", RuleStyleValue, fmt.Sprintf("%s %q is not a #rrggbb color", tokens[0], value))
+		}
+	case "shape":
+		if !styleShapes[strings.ToLower(value)] {
+			l.add(line, "warning", RuleStyleValue, fmt.Sprintf("unknown shape %q", value))
+		}
+	case "border", "style":
+		switch strings.ToLower(value) {
+		case "solid", "dashed", "dotted":
+		default:
+			l.add(line, "warning", RuleStyleValue, fmt.Sprintf("%s %q is not solid, dashed or dotted", tokens[0], value))
+		}
+	case "opacity":
+		if n, err := strconv.Atoi(value); err != nil || n < 0 || n > 100 {
+			l.add(line, "warning", RuleStyleValue, fmt.Sprintf("opacity %q is not between 0 and 100", value))
+		}
+	case "width", "height", "fontsize", "thickness", "strokewidth":
+		if n, err := strconv.Atoi(value); err != nil || n <= 0 {
+			l.add(line, "warning", RuleStyleValue, fmt.Sprintf("%s %q is not a positive number", tokens[0], value))
+		}
+	}
+}
+
+// closeFrame reports views that show nothing when their block closes
+func (l *dslLinter) closeFrame(frame lintFrame) {
+	if frame.kind != "view" || frame.view.content {
+		return
+	}
+	switch frame.view.kind {
+	case "filtered", "image":
+		return
+	}
+	l.add(frame.view.line, "warning", RuleEmptyView, fmt.Sprintf("%s view has no include statements and shows no elements", frame.view.kind))
+}
+
+// define registers an identifier, reporting duplicates
+func (l *dslLinter) define(line int, identifier string) {
+	if first, ok := l.identifiers[identifier]; ok {
+		l.add(line, "error", RuleDuplicateIdentifier, fmt.Sprintf("identifier %q is already defined on line %d", identifier, first))
+		return
+	}
+	l.identifiers[identifier] = line
+	if dot := strings.LastIndex(identifier, "."); dot >= 0 {
+		l.shortNames[identifier[dot+1:]] = true
+	}
+}
+
+// reference records an identifier to resolve once the whole file is read
+func (l *dslLinter) reference(line int, identifier, context string) {
+	if identifier == "this" {
+		return
+	}
+	l.references = append(l.references, lintReference{line: line, identifier: identifier, context: context})
+}
+
+// builtInTag reports the tags Structurizr adds itself; styles for them are
+// common boilerplate even when no element of the kind exists yet
+func builtInTag(tag string) bool {
+	if tag == "Element" || tag == "Relationship" {
+		return true
+	}
+	for _, builtIn := range elementDefaultTags {
+		if tag == builtIn {
+			return true
+		}
+	}
+	return false
+}
+
+// addTags adds the comma separated tags of value to set
+func (l *dslLinter) addTags(set map[string]bool, value string) {
+	for _, tag := range strings.Split(value, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			set[tag] = true
+		}
+	}
+}
+
+// resolve reports unresolved references and styles for unused tags
+func (l *dslLinter) resolve() {
+	// Included or extended files may define what is missing here
+	severity := "error"
+	if l.external {
+		severity = "warning"
+	}
+
+	for _, ref := range l.references {
+		if _, ok := l.identifiers[ref.identifier]; ok || l.shortNames[ref.identifier] {
+			continue
+		}
+		l.add(ref.line, severity, RuleUnresolvedReference, fmt.Sprintf("%s references %q, which is not defined", ref.context, ref.identifier))
+	}
+	for _, ref := range l.envRefs {
+		if !l.environments[ref.identifier] {
+			l.add(ref.line, severity, RuleUnresolvedReference, fmt.Sprintf("%s references deployment environment %q, which is not defined", ref.context, ref.identifier))
+		}
+	}
+
+	seen := map[string]int{}
+	for _, style := range l.styles {
+		kind, tags := "element", l.elementTags
+		if style.relationship {
+			kind, tags = "relationship", l.relTags
+		}
+		if first, ok := seen[kind+":"+style.tag]; ok {
+			l.add(style.line, "warning", RuleDuplicateStyle, fmt.Sprintf("%s style %q is already defined on line %d", kind, style.tag, first))
+			continue
+		}
+		seen[kind+":"+style.tag] = style.line
+		if !tags[style.tag] && !builtInTag(style.tag) && !l.external {
+			l.add(style.line, "warning", RuleUnusedStyle, fmt.Sprintf("%s style %q matches no %s tag", kind, style.tag, kind))
+		}
+	}
+}
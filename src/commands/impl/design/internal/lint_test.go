@@ -0,0 +1,135 @@
+// Feature: src-commands_design-command
+// Unit tests for the built-in workspace linter
+package design
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/ready-to-release/eac/src/commands/impl/design/internal/workspace"
+)
+
+// lintKey identifies a message by line and rule, e.g. "09:unresolved-reference"
+func lintKey(msg ValidationMessage) string {
+	return fmt.Sprintf("%02d:%s", msg.Line, msg.Rule)
+}
+
+// lintRules returns the keys of messages
+func lintRules(messages []ValidationMessage) []string {
+	var rules []string
+	for _, msg := range messages {
+		rules = append(rules, lintKey(msg))
+	}
+	return rules
+}
+
+// TestLintDSL_BaseWorkspace tests that generated workspaces lint clean
+func TestLintDSL_BaseWorkspace(t *testing.T) {
+	messages := LintDSL(workspace.GenerateBaseDSL("CLI", "CLI Architecture"))
+
+	if len(messages) != 0 {
+		t.Errorf("Expected no messages for a new workspace, got %v", lintRules(messages))
+	}
+}
+
+// TestLintDSL_Diagnostics tests each rule with its line number
+func TestLintDSL_Diagnostics(t *testing.T) {
+	dsl := `workspace "Test" {
+    model {
+        user = person "User"
+        system = softwareSystem "System" {
+            api = container "API" "Serves requests" "Go" "Backend"
+            api = container "Worker"
+        }
+        user -> api "Uses"
+        user -> db "Reads"
+    }
+    views {
+        container system "Containers" {
+            include *
+        }
+        container system "Containers" {
+            include missing
+        }
+        systemContext system "Empty" {
+            autoLayout
+        }
+        styles {
+            element "Backend" {
+                background #12345
+                shape Blob
+            }
+            element "Frontend" {
+                color #ffffff
+            }
+            element "Backend" {
+                color #000000
+            }
+        }
+    }
+}
+`
+	messages := LintDSL(dsl)
+
+	want := map[string]string{
+		"06:duplicate-identifier": "already defined on line 5",
+		"09:unresolved-reference": `"db"`,
+		"15:duplicate-view-key":   `"Containers"`,
+		"16:unresolved-reference": `"missing"`,
+		"18:empty-view":           "no include statements",
+		"23:style-value":          "#12345",
+		"24:style-value":          "Blob",
+		"26:unused-style":         `"Frontend"`,
+		"29:duplicate-style":      "line 22",
+	}
+	got := map[string]string{}
+	for _, msg := range messages {
+		got[lintKey(msg)] = msg.Message
+	}
+	for key, fragment := range want {
+		if !strings.Contains(got[key], fragment) {
+			t.Errorf("Expected %s containing %q, got messages %v", key, fragment, lintRules(messages))
+		}
+	}
+	if len(messages) != len(want) {
+		t.Errorf("Expected %d messages, got %v", len(want), lintRules(messages))
+	}
+}
+
+// TestLintDSL_Syntax tests unbalanced braces and unterminated strings
+func TestLintDSL_Syntax(t *testing.T) {
+	messages := LintDSL("workspace {\n    model {\n        a = person \"A\n    }\n")
+
+	rules := strings.Join(lintRules(messages), " ")
+	if !strings.Contains(rules, "03:syntax") || !strings.Contains(rules, "05:syntax") {
+		t.Errorf("Expected syntax errors on lines 3 and 5, got %s", rules)
+	}
+}
+
+// TestLintDSL_HierarchicalAndIncludes tests identifier scoping and external definitions
+func TestLintDSL_HierarchicalAndIncludes(t *testing.T) {
+	dsl := `workspace {
+    !identifiers hierarchical
+    model {
+        a = softwareSystem "A" {
+            api = container "API"
+        }
+        b = softwareSystem "B" {
+            api = container "API"
+            -> a.api "Calls"
+        }
+    }
+}
+`
+	if messages := LintDSL(dsl); len(messages) != 0 {
+		t.Errorf("Expected no messages for hierarchical identifiers, got %v", lintRules(messages))
+	}
+
+	included := "workspace {\n    !include model.dsl\n    model {\n        a -> b\n    }\n}\n"
+	for _, msg := range LintDSL(included) {
+		if msg.Severity == "error" {
+			t.Errorf("Expected references to included files to be warnings, got: %s", msg.Message)
+		}
+	}
+}
@@ -203,3 +203,66 @@ func WriteValidationSummaryJSON(summary *ValidationSummary, outputPath string) e
 
 	return nil
 }
+
+// FormatLintResult formats a lint result for console output
+func FormatLintResult(result *ValidationResult) string {
+	output := ""
+
+	output += fmt.Sprintf("🔍 Linting module: %s\n", result.Module)
+	output += fmt.Sprintf("📄 Workspace: %s\n", result.WorkspacePath)
+	output += "\n"
+
+	if result.Valid {
+		output += "✅ Workspace is valid\n"
+	} else {
+		output += "❌ Workspace lint failed\n"
+	}
+
+	output += formatLintMessages("Errors", result.Errors, "  ")
+	output += formatLintMessages("Warnings", result.Warnings, "  ")
+
+	output += "\n📊 Summary:\n"
+	output += fmt.Sprintf("  Errors: %d\n", len(result.Errors))
+	output += fmt.Sprintf("  Warnings: %d\n", len(result.Warnings))
+
+	return output
+}
+
+// FormatLintSummary formats the lint results of all modules for console output
+func FormatLintSummary(summary *ValidationSummary) string {
+	output := "🔍 Linting all modules...\n\n"
+
+	for _, result := range summary.Results {
+		output += fmt.Sprintf("Module: %s\n", result.Module)
+		if result.Valid {
+			output += "  ✅ Valid\n"
+		} else {
+			output += "  ❌ Failed\n"
+		}
+		output += formatLintMessages("Errors", result.Errors, "    ")
+		output += formatLintMessages("Warnings", result.Warnings, "    ")
+		output += "\n"
+	}
+
+	output += "━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n\n"
+	output += "📊 Summary:\n"
+	output += fmt.Sprintf("  Total modules: %d\n", summary.TotalModules)
+	output += fmt.Sprintf("  Passed: %d\n", summary.PassedModules)
+	output += fmt.Sprintf("  Failed: %d\n", summary.FailedModules)
+	output += fmt.Sprintf("  Total errors: %d\n", summary.TotalErrors)
+	output += fmt.Sprintf("  Total warnings: %d\n", summary.TotalWarnings)
+
+	return output
+}
+
+// formatLintMessages lists messages with their line and rule
+func formatLintMessages(title string, messages []ValidationMessage, indent string) string {
+	if len(messages) == 0 {
+		return ""
+	}
+	output := fmt.Sprintf("\n%s%s:\n", indent[2:], title)
+	for _, msg := range messages {
+		output += fmt.Sprintf("%s- Line %d: %s [%s]\n", indent, msg.Line, msg.Message, msg.Rule)
+	}
+	return output
+}
@@ -28,10 +28,11 @@ type ValidationResult struct {
 
 // ValidationMessage represents a single error or warning
 type ValidationMessage struct {
-	Severity string `json:"severity"` // "error" or "warning"
-	Message  string `json:"message"`  // The validation message
-	Line     int    `json:"line"`     // Line number (if available)
-	Column   int    `json:"column"`   // Column number (if available)
+	Severity string `json:"severity"`       // "error" or "warning"
+	Message  string `json:"message"`        // The validation message
+	Line     int    `json:"line"`           // Line number (if available)
+	Column   int    `json:"column"`         // Column number (if available)
+	Rule     string `json:"rule,omitempty"` // Lint rule (design lint only)
 }
 
 // ValidationSummary aggregates results for multiple modules
@@ -0,0 +1,124 @@
+package workspace
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ready-to-release/eac/src/core/contracts/modules"
+)
+
+// ContractsFile is the generated model fragment, included by the system block of workspace.dsl
+const ContractsFile = "contracts.dsl"
+
+// contractsInclude is the statement that pulls the fragment into the system block
+const contractsInclude = "!include " + ContractsFile
+
+// GenerateContractsDSL generates one container per module contract and a
+// relationship per declared dependency. The catch-all module owns leftover
+// files rather than a deployable unit and is left out.
+func GenerateContractsDSL(contracts []*modules.ModuleContract) string {
+	sorted := make([]*modules.ModuleContract, 0, len(contracts))
+	known := make(map[string]bool)
+	for _, contract := range contracts {
+		if contract.Source.IsCatchAllSingleton != nil && *contract.Source.IsCatchAllSingleton {
+			continue
+		}
+		sorted = append(sorted, contract)
+		known[contract.Moniker] = true
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Moniker < sorted[j].Moniker })
+
+	var dsl strings.Builder
+	dsl.WriteString("# Generated by \"design generate\" from contracts/modules. Do not edit:\n")
+	dsl.WriteString("# run the command again after changing module contracts.\n")
+
+	dsl.WriteString("\n")
+	for _, contract := range sorted {
+		name := contract.Name
+		if name == "" {
+			name = contract.Moniker
+		}
+		tags := "Module"
+		if contract.Type != "" {
+			tags += "," + contract.Type
+		}
+		dsl.WriteString(fmt.Sprintf("%s = container %s %s %s %s\n",
+			SanitizeID(contract.Moniker), quoteDSL(name), quoteDSL(contract.Description), quoteDSL(contract.Type), quoteDSL(tags)))
+	}
+
+	var relationships []string
+	for _, contract := range sorted {
+		dependencies := append([]string{}, contract.DependsOn...)
+		sort.Strings(dependencies)
+		for _, dependency := range dependencies {
+			if !known[dependency] {
+				relationships = append(relationships, fmt.Sprintf("# %s depends on %q, which has no module contract", contract.Moniker, dependency))
+				continue
+			}
+			relationships = append(relationships, fmt.Sprintf("%s -> %s \"Depends on\"", SanitizeID(contract.Moniker), SanitizeID(dependency)))
+		}
+	}
+	if len(relationships) > 0 {
+		dsl.WriteString("\n")
+		dsl.WriteString(strings.Join(relationships, "\n"))
+		dsl.WriteString("\n")
+	}
+
+	return dsl.String()
+}
+
+// GenerateFromContracts writes the contracts fragment of a module's workspace
+// and includes it in the system block when it is not included yet
+func GenerateFromContracts(module string, contracts []*modules.ModuleContract) (string, error) {
+	if module == "" {
+		return "", fmt.Errorf("module is required")
+	}
+
+	dslPath, err := GetWorkspacePath(module)
+	if err != nil {
+		return "", err
+	}
+
+	dsl, err := os.ReadFile(dslPath)
+	if os.IsNotExist(err) {
+		return "", fmt.Errorf("workspace not found for module '%s' at %s (create it with: design new %s)", module, dslPath, module)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read DSL file: %w", err)
+	}
+
+	fragmentPath := filepath.Join(filepath.Dir(dslPath), ContractsFile)
+	if err := os.WriteFile(fragmentPath, []byte(GenerateContractsDSL(contracts)), 0644); err != nil {
+		return "", fmt.Errorf("failed to write %s: %w", ContractsFile, err)
+	}
+
+	if strings.Contains(string(dsl), contractsInclude) {
+		return fmt.Sprintf("Updated %s for %s module", fragmentPath, module), nil
+	}
+
+	systemIdx := strings.Index(string(dsl), "system = softwareSystem")
+	if systemIdx == -1 {
+		return "", fmt.Errorf("system not found in workspace; add %q to a softwareSystem block", contractsInclude)
+	}
+
+	insertIdx := strings.Index(string(dsl)[systemIdx:], "# Containers will be added here")
+	if insertIdx == -1 {
+		insertIdx = strings.Index(string(dsl)[systemIdx:], "}")
+	}
+	insertIdx += systemIdx
+
+	newDSL := string(dsl[:insertIdx]) + contractsInclude + "\n            " + string(dsl[insertIdx:])
+	if err := os.WriteFile(dslPath, []byte(newDSL), 0644); err != nil {
+		return "", fmt.Errorf("failed to write DSL file: %w", err)
+	}
+
+	return fmt.Sprintf("Generated %s and included it in the system of %s module", fragmentPath, module), nil
+}
+
+// quoteDSL quotes a DSL string argument
+func quoteDSL(value string) string {
+	return `"` + strings.ReplaceAll(strings.ReplaceAll(value, `\`, `\\`), `"`, `\"`) + `"`
+}
@@ -0,0 +1,53 @@
+package workspace
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ready-to-release/eac/src/core/contracts"
+	"github.com/ready-to-release/eac/src/core/contracts/modules"
+)
+
+func contract(moniker, name, kind string, dependsOn ...string) *modules.ModuleContract {
+	return modules.NewModuleContract(contracts.BaseContract{
+		Moniker:   moniker,
+		Name:      name,
+		Type:      kind,
+		DependsOn: dependsOn,
+	}, "")
+}
+
+func TestGenerateContractsDSL(t *testing.T) {
+	catchAll := true
+	other := contract("other", "Other", "")
+	other.Source.IsCatchAllSingleton = &catchAll
+
+	dsl := GenerateContractsDSL([]*modules.ModuleContract{
+		contract("src-core", "Core", "go-library"),
+		contract("src-cli", "CLI", "cli", "src-core", "src-missing"),
+		other,
+	})
+
+	for _, want := range []string{
+		`src_cli = container "CLI" "" "cli" "Module,cli"`,
+		`src_core = container "Core" "" "go-library" "Module,go-library"`,
+		`src_cli -> src_core "Depends on"`,
+		`# src-cli depends on "src-missing", which has no module contract`,
+	} {
+		if !strings.Contains(dsl, want) {
+			t.Errorf("expected %q in:\n%s", want, dsl)
+		}
+	}
+	if strings.Contains(dsl, "Other") {
+		t.Errorf("expected the catch-all module to be left out:\n%s", dsl)
+	}
+	if strings.Index(dsl, "src_cli =") > strings.Index(dsl, "src_core =") {
+		t.Errorf("expected containers sorted by moniker:\n%s", dsl)
+	}
+}
+
+func TestQuoteDSL(t *testing.T) {
+	if got := quoteDSL(`say "hi"`); got != `"say \"hi\""` {
+		t.Errorf("quoteDSL = %s", got)
+	}
+}
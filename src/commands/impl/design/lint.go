@@ -0,0 +1,135 @@
+// Command: design lint
+// Description: Lint workspace files without Docker: references, identifiers, views and styles
+// Usage: design lint <module> | --all [--format text|json]
+// HasSideEffects: false
+package design
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ready-to-release/eac/src/commands/impl/design/internal"
+	"github.com/ready-to-release/eac/src/commands/internal/registry"
+)
+
+func init() {
+	registry.Register(DesignLint)
+}
+
+// DesignLint checks workspace.dsl files with the built-in linter and reports
+// diagnostics with line numbers
+func DesignLint() int {
+	args := os.Args[3:] // Skip "design" and "lint"
+
+	var module string
+	var all bool
+	format := "text"
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+
+		switch {
+		case arg == "--all" || arg == "-a":
+			all = true
+		case arg == "--format" && i+1 < len(args):
+			i++
+			format = args[i]
+		case strings.HasPrefix(arg, "--format="):
+			format = strings.TrimPrefix(arg, "--format=")
+		case arg == "--help" || arg == "-h":
+			printLintUsage()
+			return 0
+		case !strings.HasPrefix(arg, "-"):
+			module = arg
+		default:
+			fmt.Fprintf(os.Stderr, "Error: unknown flag: %s\n", arg)
+			printLintUsage()
+			return 2
+		}
+	}
+
+	if format != "text" && format != "json" {
+		fmt.Fprintf(os.Stderr, "Error: --format must be text or json\n")
+		return 2
+	}
+
+	if all {
+		summary, err := design.LintAll()
+		if err != nil {
+			fmt.Printf("❌ Lint failed: %v\n", err)
+			return 2
+		}
+		if format == "json" {
+			return printLintJSON(summary, summary.FailedModules == 0)
+		}
+		fmt.Print(design.FormatLintSummary(summary))
+		if summary.FailedModules > 0 {
+			return 1
+		}
+		return 0
+	}
+
+	if module == "" {
+		fmt.Println("❌ Error: module name required or use --all flag")
+		fmt.Println()
+		printLintUsage()
+		return 2
+	}
+
+	result, err := design.LintModule(module)
+	if err != nil {
+		fmt.Printf("❌ Lint failed: %v\n", err)
+		return 2
+	}
+	if format == "json" {
+		return printLintJSON(result, result.Valid)
+	}
+	fmt.Print(design.FormatLintResult(result))
+	if result.Valid {
+		return 0
+	}
+	return 1
+}
+
+// printLintJSON prints v as indented JSON and returns the exit code for valid
+func printLintJSON(v interface{}, valid bool) int {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 2
+	}
+	fmt.Println(string(data))
+	if valid {
+		return 0
+	}
+	return 1
+}
+
+func printLintUsage() {
+	fmt.Println("Lint Structurizr workspace files with the built-in linter (no Docker required)")
+	fmt.Println()
+	fmt.Println("Usage:")
+	fmt.Println("  go run . design lint <module>    Lint one module")
+	fmt.Println("  go run . design lint --all       Lint all modules")
+	fmt.Println()
+	fmt.Println("Options:")
+	fmt.Println("  --all, -a              Lint all modules with workspace files")
+	fmt.Println("  --format text|json     Output format (default: text)")
+	fmt.Println("  --help, -h             Show this help message")
+	fmt.Println()
+	fmt.Println("Checks:")
+	fmt.Println("  syntax                 Unbalanced braces, unterminated strings, incomplete relationships")
+	fmt.Println("  duplicate-identifier   An identifier assigned twice")
+	fmt.Println("  unresolved-reference   Relationships, views, include/exclude and instances using undefined identifiers")
+	fmt.Println("  duplicate-view-key     Two views with the same key")
+	fmt.Println("  empty-view             Views without include statements (warning)")
+	fmt.Println("  unused-style           Styles for tags no element or relationship has (warning)")
+	fmt.Println("  duplicate-style        The same tag styled twice (warning)")
+	fmt.Println("  style-value            Invalid colors, shapes, borders and sizes (warning)")
+	fmt.Println()
+	fmt.Println("Unresolved references are warnings when the workspace uses !include or extends.")
+	fmt.Println()
+	fmt.Println("Exit codes: 0 valid, 1 errors found, 2 usage or read failure")
+}
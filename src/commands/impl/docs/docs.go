@@ -25,8 +25,8 @@ func Docs() int {
 
 	// Check for subcommands
 	switch args[0] {
-	case "serve":
-		// Handled by separate registration
+	case "serve", "lint", "nav", "stale":
+		// Handled by separate registrations
 		return 0
 	case "--help", "-h":
 		printDocsUsage()
@@ -43,10 +43,14 @@ func printDocsUsage() {
 	fmt.Println()
 	fmt.Println("Subcommands:")
 	fmt.Println("  serve  Start or stop MkDocs documentation server")
+	fmt.Println("  lint   Lint markdown under docs/ (--fix, --format json)")
+	fmt.Println("  nav    Regenerate the nav section of mkdocs.yml (--write)")
+	fmt.Println("  stale  Find references to removed or renamed commands, modules and config fields")
 	fmt.Println()
 	fmt.Println("Serve options:")
 	fmt.Println("  --no-auto-open-link    Don't open browser automatically")
 	fmt.Println("  --port, -p <port>      Port for MkDocs server (default: 8000)")
+	fmt.Println("  --mode <mode>          auto, docker, venv or native (default: auto)")
 	fmt.Println("  --debug                Stream container logs to stdout")
 	fmt.Println("  --stop                 Stop the running container")
 	fmt.Println()
@@ -55,5 +59,9 @@ func printDocsUsage() {
 	fmt.Println("  go run . docs serve --no-auto-open-link")
 	fmt.Println("  go run . docs serve --port 8001")
 	fmt.Println("  go run . docs serve --debug")
+	fmt.Println("  go run . docs serve --mode native")
 	fmt.Println("  go run . docs serve --stop")
+	fmt.Println("  go run . docs lint --fix")
+	fmt.Println("  go run . docs nav --write")
+	fmt.Println("  go run . docs stale --format json")
 }
@@ -47,3 +47,8 @@ func detectBrowser() string {
 func DetectBrowser() string {
 	return detectBrowser()
 }
+
+// OpenBrowser opens the default web browser to the given URL
+func OpenBrowser(url string) error {
+	return openBrowser(url)
+}
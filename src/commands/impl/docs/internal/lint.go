@@ -0,0 +1,126 @@
+package docs
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ready-to-release/eac/src/commands/internal/markdownlint"
+)
+
+// DocsDir is the MkDocs docs_dir, linted when no paths are given
+const DocsDir = "docs"
+
+// LintOptions returns the markdownlint options for documentation: the same
+// rules as commit messages, without paragraph wrapping unless lineLength is set
+func LintOptions(lineLength int, disabled []string) markdownlint.Options {
+	return markdownlint.Options{LineLength: lineLength, Disabled: disabled}
+}
+
+// FileReport holds the findings of one markdown file
+type FileReport struct {
+	Path     string                 `json:"path"` // relative to the repository root
+	Findings []markdownlint.Finding `json:"findings"`
+	Fixed    bool                   `json:"fixed"`          // the file was rewritten by --fix
+	Diff     string                 `json:"diff,omitempty"` // the rewrite --fix would make, with FixPreview
+}
+
+// FixMode selects what LintFiles does with files that have findings
+type FixMode int
+
+const (
+	FixOff     FixMode = iota // report the findings only
+	FixWrite                  // rewrite the files with markdownlint.Fix
+	FixPreview                // render the rewrite as a diff without writing it
+)
+
+// MarkdownFiles returns the markdown files under paths (files or directories,
+// relative to root), sorted. Hidden directories are skipped.
+func MarkdownFiles(root string, paths []string) ([]string, error) {
+	if len(paths) == 0 {
+		paths = []string{DocsDir}
+	}
+
+	seen := make(map[string]bool)
+	var files []string
+	for _, path := range paths {
+		absolute := path
+		if !filepath.IsAbs(path) {
+			absolute = filepath.Join(root, path)
+		}
+		info, err := os.Stat(absolute)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+
+		if !info.IsDir() {
+			if !seen[absolute] {
+				seen[absolute] = true
+				files = append(files, absolute)
+			}
+			continue
+		}
+
+		err = filepath.WalkDir(absolute, func(file string, entry os.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if entry.IsDir() && file != absolute && strings.HasPrefix(entry.Name(), ".") {
+				return filepath.SkipDir
+			}
+			if !entry.IsDir() && strings.EqualFold(filepath.Ext(file), ".md") && !seen[file] {
+				seen[file] = true
+				files = append(files, file)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan %s: %w", path, err)
+		}
+	}
+
+	sort.Strings(files)
+	return files, nil
+}
+
+// LintFiles lints the markdown files under paths and, depending on fix,
+// rewrites the files with findings or previews the rewrite
+func LintFiles(root string, paths []string, opts markdownlint.Options, fix FixMode) ([]FileReport, error) {
+	files, err := MarkdownFiles(root, paths)
+	if err != nil {
+		return nil, err
+	}
+
+	reports := make([]FileReport, 0, len(files))
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", file, err)
+		}
+
+		rel, err := filepath.Rel(root, file)
+		if err != nil {
+			rel = file
+		}
+		report := FileReport{
+			Path:     filepath.ToSlash(rel),
+			Findings: markdownlint.Lint(string(content), opts),
+		}
+
+		if fix != FixOff && len(report.Findings) > 0 {
+			fixed := markdownlint.Fix(string(content), opts)
+			if fix == FixPreview {
+				report.Diff = UnifiedDiff(report.Path, string(content), fixed)
+			} else {
+				if err := os.WriteFile(file, []byte(fixed), 0644); err != nil {
+					return nil, fmt.Errorf("failed to write %s: %w", file, err)
+				}
+				report.Fixed = true
+			}
+		}
+		reports = append(reports, report)
+	}
+	return reports, nil
+}
@@ -0,0 +1,63 @@
+package docs
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLintFiles(t *testing.T) {
+	root := t.TempDir()
+	write := func(name, content string) {
+		t.Helper()
+		path := filepath.Join(root, name)
+		os.MkdirAll(filepath.Dir(path), 0755)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("docs/index.md", "# Home\n\nWelcome.\n")
+	write("docs/how-to/setup.md", "# Setup\nRun it.  \n\n\n## Next\n")
+	write("docs/.drafts/wip.md", "no heading")
+	write("docs/notes.txt", "not markdown")
+
+	reports, err := LintFiles(root, nil, LintOptions(0, nil), FixOff)
+	if err != nil {
+		t.Fatalf("LintFiles: %v", err)
+	}
+	if len(reports) != 2 || reports[0].Path != "docs/how-to/setup.md" || reports[1].Path != "docs/index.md" {
+		t.Fatalf("unexpected files: %+v", reports)
+	}
+	if len(reports[1].Findings) != 0 {
+		t.Errorf("expected docs/index.md to be clean, got %+v", reports[1].Findings)
+	}
+	rules := map[string]bool{}
+	for _, finding := range reports[0].Findings {
+		rules[finding.Rule] = true
+	}
+	for _, rule := range []string{"MD009", "MD022", "MD012"} {
+		if !rules[rule] {
+			t.Errorf("expected %s for setup.md, got %+v", rule, reports[0].Findings)
+		}
+	}
+
+	// A preview renders the fix without writing it
+	before, _ := os.ReadFile(filepath.Join(root, "docs/how-to/setup.md"))
+	reports, err = LintFiles(root, []string{"docs/how-to"}, LintOptions(0, nil), FixPreview)
+	if err != nil || len(reports) != 1 || reports[0].Fixed || !strings.Contains(reports[0].Diff, "+++ b/docs/how-to/setup.md") {
+		t.Fatalf("expected a diff for setup.md, got %+v, %v", reports, err)
+	}
+	if after, _ := os.ReadFile(filepath.Join(root, "docs/how-to/setup.md")); string(after) != string(before) {
+		t.Errorf("preview rewrote setup.md:\n%s", after)
+	}
+
+	// --fix rewrites the file so a second run is clean
+	if _, err := LintFiles(root, []string{"docs/how-to"}, LintOptions(0, nil), FixWrite); err != nil {
+		t.Fatalf("LintFiles --fix: %v", err)
+	}
+	reports, err = LintFiles(root, []string{"docs/how-to/setup.md"}, LintOptions(0, nil), FixOff)
+	if err != nil || len(reports) != 1 || len(reports[0].Findings) != 0 {
+		t.Errorf("expected no findings after fixing, got %+v, %v", reports, err)
+	}
+}
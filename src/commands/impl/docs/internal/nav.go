@@ -0,0 +1,295 @@
+package docs
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+	"gopkg.in/yaml.v3"
+)
+
+// OrderFile lists the entries of a docs directory in navigation order, one
+// file or directory name per line. Unlisted entries follow in default order.
+const OrderFile = ".order"
+
+// diataxisSections are the Diátaxis sections in their conventional order
+var diataxisSections = []struct {
+	dir   string
+	title string
+}{
+	{"tutorials", "Tutorials"},
+	{"how-to", "How-to Guides"},
+	{"how-to-guides", "How-to Guides"},
+	{"reference", "Reference"},
+	{"explanation", "Explanation"},
+}
+
+// plainScalar matches titles that need no quoting in YAML
+var plainScalar = regexp.MustCompile(`^[\p{L}\p{N}][\p{L}\p{N} ._()/+-]*$`)
+
+// NavEntry is a page or a section of the MkDocs navigation
+type NavEntry struct {
+	Title    string
+	Path     string // page path relative to docs_dir; empty for sections
+	Children []NavEntry
+}
+
+// BuildNav builds the navigation of docsDir: index.md first, then the
+// Diátaxis sections, then the other entries by name, unless an .order file
+// says otherwise. Page titles come from the front matter title or the
+// first heading.
+func BuildNav(docsDir string) ([]NavEntry, error) {
+	return buildNavDir(docsDir, "")
+}
+
+func buildNavDir(docsDir, rel string) ([]NavEntry, error) {
+	dir := filepath.Join(docsDir, rel)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", dir, err)
+	}
+
+	var names []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if strings.HasPrefix(name, ".") || (!entry.IsDir() && !strings.EqualFold(filepath.Ext(name), ".md")) {
+			continue
+		}
+		names = append(names, name)
+	}
+
+	order, err := readOrderFile(filepath.Join(dir, OrderFile))
+	if err != nil {
+		return nil, err
+	}
+	sortNavNames(names, order)
+
+	var nav []NavEntry
+	for _, name := range names {
+		path := filepath.ToSlash(filepath.Join(rel, name))
+		info, err := os.Stat(filepath.Join(dir, name))
+		if err != nil {
+			return nil, err
+		}
+
+		if info.IsDir() {
+			children, err := buildNavDir(docsDir, path)
+			if err != nil {
+				return nil, err
+			}
+			if len(children) > 0 {
+				nav = append(nav, NavEntry{Title: sectionTitle(name), Children: children})
+			}
+			continue
+		}
+
+		title, err := pageTitle(filepath.Join(dir, name))
+		if err != nil {
+			return nil, err
+		}
+		nav = append(nav, NavEntry{Title: title, Path: path})
+	}
+	return nav, nil
+}
+
+// readOrderFile returns the names listed in an order file, nil when there is none
+func readOrderFile(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var names []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line != "" && !strings.HasPrefix(line, "#") {
+			names = append(names, line)
+		}
+	}
+	return names, scanner.Err()
+}
+
+// sortNavNames orders names by the order file, then index.md, the Diátaxis
+// sections and the rest by name
+func sortNavNames(names []string, order []string) {
+	rank := func(name string) int {
+		for i, ordered := range order {
+			if name == ordered || name == ordered+".md" {
+				return i
+			}
+		}
+		if strings.EqualFold(name, "index.md") || strings.EqualFold(name, "README.md") {
+			return len(order)
+		}
+		for i, section := range diataxisSections {
+			if name == section.dir {
+				return len(order) + 1 + i
+			}
+		}
+		return len(order) + 1 + len(diataxisSections)
+	}
+	sort.SliceStable(names, func(i, j int) bool {
+		ri, rj := rank(names[i]), rank(names[j])
+		if ri != rj {
+			return ri < rj
+		}
+		return names[i] < names[j]
+	})
+}
+
+// sectionTitle names a directory: the Diátaxis title, or the name in words
+func sectionTitle(dir string) string {
+	for _, section := range diataxisSections {
+		if dir == section.dir {
+			return section.title
+		}
+	}
+	return titleFromName(dir)
+}
+
+// pageTitle returns the front matter title, the first heading, or the file name in words
+func pageTitle(path string) (string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	text := strings.ReplaceAll(string(content), "\r\n", "\n")
+
+	if strings.HasPrefix(text, "---\n") {
+		if end := strings.Index(text[4:], "\n---"); end >= 0 {
+			var frontMatter struct {
+				Title string `yaml:"title"`
+			}
+			if err := yaml.Unmarshal([]byte(text[4:4+end]), &frontMatter); err == nil && frontMatter.Title != "" {
+				return frontMatter.Title, nil
+			}
+			text = text[4+end+4:]
+		}
+	}
+
+	inFence := false
+	for _, line := range strings.Split(text, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inFence = !inFence
+		}
+		if !inFence && strings.HasPrefix(line, "# ") {
+			return strings.TrimSpace(line[2:]), nil
+		}
+	}
+	return titleFromName(strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))), nil
+}
+
+// titleFromName turns "getting-started" into "Getting started"
+func titleFromName(name string) string {
+	words := strings.Fields(strings.NewReplacer("-", " ", "_", " ").Replace(name))
+	if len(words) == 0 {
+		return name
+	}
+	title := strings.Join(words, " ")
+	return strings.ToUpper(title[:1]) + title[1:]
+}
+
+// RenderNav renders entries as the nav section of mkdocs.yml
+func RenderNav(entries []NavEntry) string {
+	var b strings.Builder
+	b.WriteString("nav:\n")
+	renderNavEntries(&b, entries, "  ")
+	return b.String()
+}
+
+func renderNavEntries(b *strings.Builder, entries []NavEntry, indent string) {
+	for _, entry := range entries {
+		if entry.Path != "" {
+			fmt.Fprintf(b, "%s- %s: %s\n", indent, yamlScalar(entry.Title), entry.Path)
+			continue
+		}
+		fmt.Fprintf(b, "%s- %s:\n", indent, yamlScalar(entry.Title))
+		renderNavEntries(b, entry.Children, indent+"    ")
+	}
+}
+
+// yamlScalar quotes titles that are not plain YAML scalars
+func yamlScalar(value string) string {
+	if plainScalar.MatchString(value) && !strings.HasSuffix(value, " ") {
+		return value
+	}
+	return strconv.Quote(value)
+}
+
+// ReplaceNav replaces the top-level nav section of an mkdocs.yml, or appends
+// nav when there is none. The rest of the file is kept as written, including
+// tags such as !!python/name that a YAML round trip would lose.
+func ReplaceNav(config string, nav string) string {
+	lines := strings.SplitAfter(config, "\n")
+
+	start := -1
+	for i, line := range lines {
+		if strings.HasPrefix(line, "nav:") {
+			start = i
+			break
+		}
+	}
+	if start < 0 {
+		if config != "" && !strings.HasSuffix(config, "\n") {
+			config += "\n"
+		}
+		return config + "\n" + nav
+	}
+
+	end := start + 1
+	for end < len(lines) {
+		line := lines[end]
+		if strings.TrimSpace(line) != "" && !strings.HasPrefix(line, " ") && !strings.HasPrefix(line, "\t") && !strings.HasPrefix(line, "-") {
+			break
+		}
+		end++
+	}
+	// Keep the blank lines before the next key
+	for end > start+1 && strings.TrimSpace(lines[end-1]) == "" {
+		end--
+	}
+
+	return strings.Join(lines[:start], "") + nav + strings.Join(lines[end:], "")
+}
+
+// DocsDirFromConfig returns docs_dir of an mkdocs.yml, "docs" by default
+func DocsDirFromConfig(config string) string {
+	for _, line := range strings.Split(config, "\n") {
+		if strings.HasPrefix(line, "docs_dir:") {
+			value := strings.TrimSpace(strings.TrimPrefix(line, "docs_dir:"))
+			if value = strings.Trim(value, `"'`); value != "" {
+				return value
+			}
+		}
+	}
+	return DocsDir
+}
+
+// UnifiedDiff returns a unified diff of two versions of a file, empty when equal
+func UnifiedDiff(name, before, after string) string {
+	if before == after {
+		return ""
+	}
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(before),
+		B:        difflib.SplitLines(after),
+		FromFile: "a/" + name,
+		ToFile:   "b/" + name,
+		Context:  3,
+	})
+	if err != nil {
+		return ""
+	}
+	return diff
+}
@@ -0,0 +1,75 @@
+package docs
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBuildNav(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		os.MkdirAll(filepath.Dir(path), 0755)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("index.md", "---\ntitle: Home\n---\n\n# Welcome\n")
+	write("reference/commands.md", "# Commands: overview\n")
+	write("tutorials/getting-started.md", "```\n# not a heading\n```\n\nNo heading here.\n")
+	write("explanation/why.md", "# Why\n")
+	write("how-to/b.md", "# B\n")
+	write("how-to/a.md", "# A\n")
+	write("how-to/.order", "# B before A\nb\n")
+	write("archive/old.md", "# Old\n")
+	write("assets/logo.png", "png")
+
+	nav, err := BuildNav(dir)
+	if err != nil {
+		t.Fatalf("BuildNav: %v", err)
+	}
+
+	want := `nav:
+  - Home: index.md
+  - Tutorials:
+      - Getting started: tutorials/getting-started.md
+  - How-to Guides:
+      - B: how-to/b.md
+      - A: how-to/a.md
+  - Reference:
+      - "Commands: overview": reference/commands.md
+  - Explanation:
+      - Why: explanation/why.md
+  - Archive:
+      - Old: archive/old.md
+`
+	if got := RenderNav(nav); got != want {
+		t.Errorf("unexpected nav:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestReplaceNav(t *testing.T) {
+	config := "site_name: Docs\nnav:\n  - Old: old.md\n  - Section:\n      - x.md\n\ntheme:\n  name: material\n"
+	nav := "nav:\n  - Home: index.md\n"
+
+	got := ReplaceNav(config, nav)
+	want := "site_name: Docs\nnav:\n  - Home: index.md\n\ntheme:\n  name: material\n"
+	if got != want {
+		t.Errorf("ReplaceNav:\n%s\nwant:\n%s", got, want)
+	}
+
+	if got := ReplaceNav("site_name: Docs", nav); got != "site_name: Docs\n\n"+nav {
+		t.Errorf("expected nav to be appended, got:\n%s", got)
+	}
+
+	diff := UnifiedDiff("mkdocs.yml", config, got)
+	if !strings.Contains(diff, "-  - Old: old.md") || !strings.Contains(diff, "+  - Home: index.md") {
+		t.Errorf("unexpected diff:\n%s", diff)
+	}
+	if UnifiedDiff("mkdocs.yml", config, config) != "" {
+		t.Error("expected no diff for equal content")
+	}
+}
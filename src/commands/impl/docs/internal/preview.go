@@ -0,0 +1,111 @@
+package docs
+
+import (
+	"fmt"
+	"html"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// previewPage is the HTML page of the native preview
+const previewPage = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>%s</title>
+<style>
+body { margin: 0; display: flex; font-family: -apple-system, "Segoe UI", Roboto, sans-serif; line-height: 1.6; color: #222; }
+nav { width: 18rem; padding: 1rem; background: #f5f5f5; min-height: 100vh; box-sizing: border-box; font-size: 0.9rem; }
+nav ul { list-style: none; padding-left: 1rem; margin: 0; }
+nav > ul { padding-left: 0; }
+nav .section { font-weight: 600; margin-top: 0.5rem; }
+nav a.current { font-weight: 600; }
+main { max-width: 50rem; padding: 1rem 2rem; }
+pre { background: #f5f5f5; padding: 0.75rem; overflow-x: auto; }
+code { font-family: "SFMono-Regular", Consolas, monospace; font-size: 0.9em; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #ddd; padding: 0.25rem 0.5rem; }
+blockquote { border-left: 4px solid #ddd; margin-left: 0; padding-left: 1rem; color: #555; }
+.banner { font-size: 0.8rem; color: #888; }
+</style>
+</head>
+<body>
+<nav>%s</nav>
+<main>
+<p class="banner">Native preview: MkDocs theme and extensions are not applied.</p>
+%s
+</main>
+</body>
+</html>
+`
+
+// PreviewHandler serves the docs directory as HTML: markdown pages are
+// rendered with RenderMarkdown next to the navigation of BuildNav, other
+// files (images) are served as they are. The navigation is rebuilt on every
+// request so new pages show up without a restart.
+func PreviewHandler(docsDir string) http.Handler {
+	files := http.FileServer(http.Dir(docsDir))
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page := path.Clean("/" + r.URL.Path)
+		if strings.HasSuffix(r.URL.Path, "/") {
+			if _, err := os.Stat(filepath.Join(docsDir, filepath.FromSlash(page), "index.md")); err == nil {
+				page = path.Join(page, "index.md")
+			}
+		}
+		if !strings.EqualFold(path.Ext(page), ".md") {
+			// MkDocs style URLs: /how-to/setup/ is how-to/setup.md
+			if _, err := os.Stat(filepath.Join(docsDir, filepath.FromSlash(page)+".md")); err == nil && page != "/" {
+				page += ".md"
+			} else {
+				files.ServeHTTP(w, r)
+				return
+			}
+		}
+
+		content, err := os.ReadFile(filepath.Join(docsDir, filepath.FromSlash(page)))
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+
+		current := strings.TrimPrefix(page, "/")
+		title, _ := pageTitle(filepath.Join(docsDir, filepath.FromSlash(page)))
+		navHTML := ""
+		if nav, err := BuildNav(docsDir); err == nil {
+			navHTML = renderNavHTML(nav, current)
+		}
+
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		fmt.Fprintf(w, previewPage, html.EscapeString(title), navHTML, RenderMarkdown(string(content)))
+	})
+}
+
+// renderNavHTML renders the navigation as nested lists, marking the current page
+func renderNavHTML(entries []NavEntry, current string) string {
+	var b strings.Builder
+	b.WriteString("<ul>")
+	for _, entry := range entries {
+		if entry.Path == "" {
+			b.WriteString(`<li><div class="section">` + html.EscapeString(entry.Title) + "</div>")
+			b.WriteString(renderNavHTML(entry.Children, current))
+			b.WriteString("</li>")
+			continue
+		}
+		class := ""
+		if entry.Path == current {
+			class = ` class="current"`
+		}
+		fmt.Fprintf(&b, `<li><a href="/%s"%s>%s</a></li>`, html.EscapeString(entry.Path), class, html.EscapeString(entry.Title))
+	}
+	b.WriteString("</ul>")
+	return b.String()
+}
+
+// ServeNative serves the native preview of docsDir until the process is stopped
+func ServeNative(docsDir string, port int) error {
+	return http.ListenAndServe(fmt.Sprintf("127.0.0.1:%d", port), PreviewHandler(docsDir))
+}
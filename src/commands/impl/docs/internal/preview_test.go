@@ -0,0 +1,54 @@
+package docs
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPreviewHandler(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, "how-to"), 0755)
+	os.WriteFile(filepath.Join(dir, "index.md"), []byte("# Home\n"), 0644)
+	os.WriteFile(filepath.Join(dir, "how-to", "setup.md"), []byte("# Setup\n\nInstall it.\n"), 0644)
+	os.WriteFile(filepath.Join(dir, "logo.png"), []byte("png"), 0644)
+
+	server := httptest.NewServer(PreviewHandler(dir))
+	defer server.Close()
+
+	tests := []struct {
+		path   string
+		status int
+		want   []string
+	}{
+		{"/", http.StatusOK, []string{"<title>Home</title>", `<h1 id="home">Home</h1>`, `<a href="/how-to/setup.md">Setup</a>`}},
+		{"/how-to/setup/", http.StatusOK, []string{"<p>Install it.</p>"}},
+		{"/how-to/setup", http.StatusOK, []string{"<p>Install it.</p>", `<a href="/how-to/setup.md" class="current">Setup</a>`}},
+		{"/how-to/setup.md", http.StatusOK, []string{"<title>Setup</title>"}},
+		{"/logo.png", http.StatusOK, []string{"png"}},
+		{"/missing.md", http.StatusNotFound, nil},
+	}
+
+	for _, tt := range tests {
+		resp, err := http.Get(server.URL + tt.path)
+		if err != nil {
+			t.Fatalf("GET %s: %v", tt.path, err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		if resp.StatusCode != tt.status {
+			t.Errorf("GET %s: status %d, want %d", tt.path, resp.StatusCode, tt.status)
+			continue
+		}
+		for _, want := range tt.want {
+			if !strings.Contains(string(body), want) {
+				t.Errorf("GET %s: missing %q in:\n%s", tt.path, want, string(body))
+			}
+		}
+	}
+}
@@ -0,0 +1,183 @@
+package docs
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+)
+
+// The native preview renders the common subset of markdown used in the docs:
+// headings, paragraphs, lists, block quotes, fenced code, tables, rules,
+// links, images, emphasis and inline code. MkDocs extensions (admonitions,
+// tabs, macros) are shown as plain text.
+
+var (
+	headingPattern  = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*\s*$`)
+	listItemPattern = regexp.MustCompile(`^\s*([-*+]|\d+[.)])\s+(.*)$`)
+	rulePattern     = regexp.MustCompile(`^\s*([-*_])(\s*[-*_]){2,}\s*$`)
+	imagePattern    = regexp.MustCompile(`!\[([^\]]*)\]\(([^)\s]+)(?:\s+[^)]*)?\)`)
+	linkPattern     = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)(?:\s+[^)]*)?\)`)
+	strongPattern   = regexp.MustCompile(`\*\*([^*]+)\*\*|__([^_]+)__`)
+	emPattern       = regexp.MustCompile(`\*([^*\s][^*]*)\*|\b_([^_\s][^_]*)_\b`)
+	slugPattern     = regexp.MustCompile(`[^\p{L}\p{N}]+`)
+)
+
+// RenderMarkdown renders markdown to HTML for the native preview
+func RenderMarkdown(source string) string {
+	lines := strings.Split(stripFrontMatter(strings.ReplaceAll(source, "\r\n", "\n")), "\n")
+
+	var out strings.Builder
+	var paragraph []string
+	flush := func() {
+		if len(paragraph) > 0 {
+			out.WriteString("<p>" + renderInline(strings.Join(paragraph, " ")) + "</p>\n")
+			paragraph = nil
+		}
+	}
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case trimmed == "":
+			flush()
+		case strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~"):
+			flush()
+			fence := trimmed[:3]
+			language := strings.TrimSpace(strings.TrimLeft(trimmed, fence[:1]))
+			var code []string
+			for i++; i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), fence); i++ {
+				code = append(code, lines[i])
+			}
+			class := ""
+			if language != "" {
+				class = fmt.Sprintf(` class="language-%s"`, html.EscapeString(strings.Fields(language)[0]))
+			}
+			out.WriteString(fmt.Sprintf("<pre><code%s>%s</code></pre>\n", class, html.EscapeString(strings.Join(code, "\n"))))
+		case headingPattern.MatchString(trimmed):
+			flush()
+			match := headingPattern.FindStringSubmatch(trimmed)
+			level := len(match[1])
+			out.WriteString(fmt.Sprintf("<h%d id=\"%s\">%s</h%d>\n", level, slug(match[2]), renderInline(match[2]), level))
+		case rulePattern.MatchString(trimmed):
+			flush()
+			out.WriteString("<hr>\n")
+		case strings.HasPrefix(trimmed, ">"):
+			flush()
+			var quote []string
+			for ; i < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i]), ">"); i++ {
+				quote = append(quote, strings.TrimPrefix(strings.TrimPrefix(strings.TrimSpace(lines[i]), ">"), " "))
+			}
+			i--
+			out.WriteString("<blockquote>\n" + RenderMarkdown(strings.Join(quote, "\n")) + "</blockquote>\n")
+		case listItemPattern.MatchString(line):
+			flush()
+			tag := "ul"
+			if marker := listItemPattern.FindStringSubmatch(line)[1]; strings.ContainsAny(marker, "0123456789") {
+				tag = "ol"
+			}
+			out.WriteString("<" + tag + ">\n")
+			var item []string
+			writeItem := func() {
+				if len(item) > 0 {
+					out.WriteString("<li>" + renderInline(strings.Join(item, " ")) + "</li>\n")
+					item = nil
+				}
+			}
+			for ; i < len(lines); i++ {
+				if match := listItemPattern.FindStringSubmatch(lines[i]); match != nil {
+					writeItem()
+					item = append(item, match[2])
+					continue
+				}
+				// Indented continuation lines belong to the item
+				if strings.TrimSpace(lines[i]) != "" && (strings.HasPrefix(lines[i], " ") || strings.HasPrefix(lines[i], "\t")) {
+					item = append(item, strings.TrimSpace(lines[i]))
+					continue
+				}
+				break
+			}
+			writeItem()
+			i--
+			out.WriteString("</" + tag + ">\n")
+		case strings.HasPrefix(trimmed, "|") && i+1 < len(lines) && isTableSeparator(lines[i+1]):
+			flush()
+			out.WriteString("<table>\n<thead><tr>")
+			for _, cell := range tableCells(trimmed) {
+				out.WriteString("<th>" + renderInline(cell) + "</th>")
+			}
+			out.WriteString("</tr></thead>\n<tbody>\n")
+			for i += 2; i < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i]), "|"); i++ {
+				out.WriteString("<tr>")
+				for _, cell := range tableCells(strings.TrimSpace(lines[i])) {
+					out.WriteString("<td>" + renderInline(cell) + "</td>")
+				}
+				out.WriteString("</tr>\n")
+			}
+			i--
+			out.WriteString("</tbody>\n</table>\n")
+		default:
+			paragraph = append(paragraph, trimmed)
+		}
+	}
+	flush()
+	return out.String()
+}
+
+// renderInline renders code spans, images, links and emphasis of one block
+func renderInline(text string) string {
+	// Odd parts are code spans, which are not formatted further
+	parts := strings.Split(text, "`")
+	var out strings.Builder
+	for i, part := range parts {
+		if i%2 == 1 && i < len(parts)-1 {
+			out.WriteString("<code>" + html.EscapeString(part) + "</code>")
+			continue
+		}
+		if i%2 == 1 {
+			out.WriteString("`")
+		}
+		part = html.EscapeString(part)
+		part = imagePattern.ReplaceAllString(part, `<img src="$2" alt="$1">`)
+		part = linkPattern.ReplaceAllString(part, `<a href="$2">$1</a>`)
+		part = strongPattern.ReplaceAllString(part, "<strong>$1$2</strong>")
+		part = emPattern.ReplaceAllString(part, "<em>$1$2</em>")
+		out.WriteString(part)
+	}
+	return out.String()
+}
+
+// stripFrontMatter removes a leading YAML front matter block
+func stripFrontMatter(source string) string {
+	if !strings.HasPrefix(source, "---\n") {
+		return source
+	}
+	end := strings.Index(source[4:], "\n---")
+	if end < 0 {
+		return source
+	}
+	rest := source[4+end+4:]
+	return strings.TrimPrefix(rest, "\n")
+}
+
+// isTableSeparator matches the "| --- | :-: |" line under a table header
+func isTableSeparator(line string) bool {
+	line = strings.TrimSpace(line)
+	return strings.HasPrefix(line, "|") && strings.Trim(line, "|:- ") == "" && strings.Contains(line, "-")
+}
+
+// tableCells splits a table row into trimmed cells
+func tableCells(row string) []string {
+	cells := strings.Split(strings.Trim(row, "|"), "|")
+	for i := range cells {
+		cells[i] = strings.TrimSpace(cells[i])
+	}
+	return cells
+}
+
+// slug turns a heading into an anchor id the way MkDocs does
+func slug(text string) string {
+	return strings.Trim(slugPattern.ReplaceAllString(strings.ToLower(text), "-"), "-")
+}
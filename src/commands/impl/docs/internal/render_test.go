@@ -0,0 +1,41 @@
+package docs
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderMarkdown(t *testing.T) {
+	source := "---\ntitle: Home\n---\n" +
+		"# Getting Started\n\n" +
+		"Some **bold**, *em* and `a <b>` code\nwith a [link](setup.md \"Setup\").\n\n" +
+		"- one\n- two\n  continued\n\n" +
+		"1. first\n2. second\n\n" +
+		"> quoted\n\n" +
+		"```go\nfmt.Println(\"<x>\")\n```\n\n" +
+		"| Name | Value |\n| --- | :-: |\n| a | `b` |\n\n" +
+		"---\n\n" +
+		"![Logo](assets/logo.png)\n"
+
+	got := RenderMarkdown(source)
+
+	for _, want := range []string{
+		`<h1 id="getting-started">Getting Started</h1>`,
+		`<p>Some <strong>bold</strong>, <em>em</em> and <code>a &lt;b&gt;</code> code with a <a href="setup.md">link</a>.</p>`,
+		"<ul>\n<li>one</li>\n<li>two continued</li>\n</ul>",
+		"<ol>\n<li>first</li>\n<li>second</li>\n</ol>",
+		"<blockquote>\n<p>quoted</p>\n</blockquote>",
+		`<pre><code class="language-go">fmt.Println(&#34;&lt;x&gt;&#34;)</code></pre>`,
+		"<thead><tr><th>Name</th><th>Value</th></tr></thead>",
+		"<tr><td>a</td><td><code>b</code></td></tr>",
+		"<hr>",
+		`<img src="assets/logo.png" alt="Logo">`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing %q in:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "title: Home") {
+		t.Errorf("front matter rendered:\n%s", got)
+	}
+}
@@ -0,0 +1,472 @@
+package docs
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Kinds of stale references
+const (
+	StaleKindCommand = "command"
+	StaleKindModule  = "module"
+	StaleKindConfig  = "config"
+)
+
+var (
+	codeSpanPattern   = regexp.MustCompile("`([^`]+)`")
+	moduleProse       = regexp.MustCompile("`([a-z0-9][a-z0-9.-]*)` modules?\\b|\\bmodule `([a-z0-9][a-z0-9.-]*)`")
+	configPathPattern = regexp.MustCompile(`^[a-z_][a-z0-9_]*(\[\d*\])?(\.[a-z_][a-z0-9_]*(\[\d*\])?)+$`)
+	wordPattern       = regexp.MustCompile(`^[a-z][a-z0-9-]*$`)
+	indexPattern      = regexp.MustCompile(`\[\d*\]`)
+)
+
+// shellFences are the fenced code languages whose lines are read as commands
+var shellFences = map[string]bool{
+	"": true, "sh": true, "bash": true, "shell": true, "console": true,
+	"zsh": true, "text": true, "pwsh": true, "powershell": true,
+}
+
+// StaleIndex lists what the docs may reference
+type StaleIndex struct {
+	Commands     map[string]string // registered command -> usage line
+	Modules      []string          // module monikers
+	ConfigFields []string          // dotted config field paths, arrays flattened
+}
+
+// StaleFinding is a reference to a command, module or config field that no
+// longer exists
+type StaleFinding struct {
+	Path       string `json:"path"`
+	Line       int    `json:"line"`
+	Kind       string `json:"kind"`
+	Reference  string `json:"reference"`
+	Message    string `json:"message"`
+	Suggestion string `json:"suggestion,omitempty"`
+}
+
+// staleChecker holds the lookup tables built from a StaleIndex
+type staleChecker struct {
+	commands   map[string]bool
+	children   map[string][]string // command prefix -> next words ("" for the first word)
+	moduleArgs map[string]bool     // commands whose first argument is a module
+	modules    map[string]bool
+	moduleList []string
+	config     map[string]bool
+	configRoot map[string]bool
+	configLeaf map[string]bool // fields without declared children (free-form values)
+}
+
+func newStaleChecker(index StaleIndex) *staleChecker {
+	c := &staleChecker{
+		commands:   make(map[string]bool),
+		children:   make(map[string][]string),
+		moduleArgs: make(map[string]bool),
+		modules:    make(map[string]bool),
+		moduleList: index.Modules,
+		config:     make(map[string]bool),
+		configRoot: make(map[string]bool),
+		configLeaf: make(map[string]bool),
+	}
+
+	seen := make(map[string]bool)
+	for name, usage := range index.Commands {
+		words := strings.Fields(name)
+		var kept []string
+		for _, word := range words {
+			if !wordPattern.MatchString(word) {
+				break // placeholders such as <suite-moniker>
+			}
+			kept = append(kept, word)
+		}
+		for i := range kept {
+			prefix := strings.Join(kept[:i], " ")
+			if key := prefix + "\x00" + kept[i]; !seen[key] {
+				seen[key] = true
+				c.children[prefix] = append(c.children[prefix], kept[i])
+			}
+		}
+		command := strings.Join(kept, " ")
+		c.commands[command] = true
+		if takesModuleArg(command, usage) {
+			c.moduleArgs[command] = true
+		}
+	}
+
+	for _, module := range index.Modules {
+		c.modules[module] = true
+	}
+
+	for _, field := range index.ConfigFields {
+		segments := strings.Split(field, ".")
+		c.configRoot[segments[0]] = true
+		for i := range segments {
+			c.config[strings.Join(segments[:i+1], ".")] = true
+		}
+	}
+	for field := range c.config {
+		c.configLeaf[field] = true
+	}
+	for field := range c.config {
+		if i := strings.LastIndex(field, "."); i > 0 {
+			delete(c.configLeaf, field[:i])
+		}
+	}
+	return c
+}
+
+// takesModuleArg reports whether the first argument in a usage line is a module
+func takesModuleArg(command, usage string) bool {
+	usage = strings.TrimPrefix(strings.TrimSpace(usage), "go run . ")
+	rest := strings.Fields(strings.TrimPrefix(usage, command))
+	if !strings.HasPrefix(usage, command) || len(rest) == 0 {
+		return false
+	}
+	switch rest[0] {
+	case "<module>", "<moniker>", "[moniker1]", "<moniker1>":
+		return true
+	}
+	return false
+}
+
+// FindStale checks the markdown files under paths (default: docs) against index
+func FindStale(root string, paths []string, index StaleIndex) ([]StaleFinding, int, error) {
+	files, err := MarkdownFiles(root, paths)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	checker := newStaleChecker(index)
+	var findings []StaleFinding
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to read %s: %w", file, err)
+		}
+		rel, err := filepath.Rel(root, file)
+		if err != nil {
+			rel = file
+		}
+		for _, finding := range checker.check(string(content)) {
+			finding.Path = filepath.ToSlash(rel)
+			findings = append(findings, finding)
+		}
+	}
+	return findings, len(files), nil
+}
+
+// FindStaleInMarkdown checks one markdown document against index
+func FindStaleInMarkdown(content string, index StaleIndex) []StaleFinding {
+	return newStaleChecker(index).check(content)
+}
+
+func (c *staleChecker) check(content string) []StaleFinding {
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+
+	var findings []StaleFinding
+	report := func(line int, kind, reference, message, suggestion string) {
+		findings = append(findings, StaleFinding{
+			Line:       line,
+			Kind:       kind,
+			Reference:  reference,
+			Message:    message,
+			Suggestion: suggestion,
+		})
+	}
+
+	for i := 0; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			fence := trimmed[:3]
+			language := strings.ToLower(strings.TrimSpace(strings.TrimLeft(trimmed, fence[:1])))
+			if fields := strings.Fields(language); len(fields) > 0 {
+				language = fields[0]
+			}
+			start := i + 1
+			var block []string
+			for i++; i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), fence); i++ {
+				block = append(block, lines[i])
+			}
+
+			switch {
+			case language == "yaml" || language == "yml":
+				c.checkConfigBlock(strings.Join(block, "\n"), start, report)
+			case shellFences[language]:
+				for j, line := range block {
+					c.checkCommand(line, start+j+1, report)
+				}
+			}
+			continue
+		}
+
+		for _, match := range codeSpanPattern.FindAllStringSubmatch(lines[i], -1) {
+			span := strings.TrimSpace(match[1])
+			c.checkCommand(span, i+1, report)
+			if configPathPattern.MatchString(span) {
+				c.checkConfigPath(span, i+1, report)
+			}
+		}
+		for _, match := range moduleProse.FindAllStringSubmatch(lines[i], -1) {
+			c.checkModule(match[1]+match[2], i+1, report)
+		}
+	}
+	return findings
+}
+
+// checkCommand checks a command line or code span. Only text that starts
+// with a command group ("docs", "get", ...) or with "go run ." is read as a
+// command, so ordinary code is left alone.
+func (c *staleChecker) checkCommand(text string, line int, report func(int, string, string, string, string)) {
+	text = strings.TrimPrefix(strings.TrimSpace(text), "$ ")
+	explicit := strings.HasPrefix(text, "go run . ")
+	words := strings.Fields(strings.TrimPrefix(text, "go run . "))
+	if len(words) == 0 || (!explicit && !c.isGroup(words[0])) {
+		return
+	}
+
+	node, i := "", 0
+	for ; i < len(words) && wordPattern.MatchString(words[i]); i++ {
+		if !c.isPrefix(node, words[i]) {
+			break
+		}
+		node = strings.TrimSpace(node + " " + words[i])
+	}
+
+	if i < len(words) && wordPattern.MatchString(words[i]) {
+		// Top-level groups dispatch on the next word; deeper commands take
+		// arguments, which are only checked when they name a module
+		if node == "" || !c.commands[node] || (!strings.Contains(node, " ") && len(c.children[node]) > 0) {
+			reference := strings.TrimSpace(node + " " + words[i])
+			suggestion := closest(words[i], c.children[node])
+			if suggestion != "" {
+				suggestion = strings.TrimSpace(node + " " + suggestion)
+			}
+			report(line, StaleKindCommand, reference, fmt.Sprintf("unknown command: %s", reference), suggestion)
+			return
+		}
+		if c.moduleArgs[node] {
+			c.checkModule(words[i], line, report)
+		}
+	}
+
+	for j := i; j < len(words); j++ {
+		switch {
+		case words[j] == "--module" && j+1 < len(words):
+			c.checkModule(words[j+1], line, report)
+		case strings.HasPrefix(words[j], "--module="):
+			c.checkModule(strings.TrimPrefix(words[j], "--module="), line, report)
+		}
+	}
+}
+
+// isGroup reports whether word starts a multi-word command
+func (c *staleChecker) isGroup(word string) bool {
+	return len(c.children[word]) > 0
+}
+
+func (c *staleChecker) isPrefix(node, word string) bool {
+	for _, child := range c.children[node] {
+		if child == word {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *staleChecker) checkModule(name string, line int, report func(int, string, string, string, string)) {
+	name = strings.Trim(name, `"',`)
+	if len(c.modules) == 0 || c.modules[name] || !wordPattern.MatchString(name) {
+		return
+	}
+	report(line, StaleKindModule, name, fmt.Sprintf("unknown module: %s", name), closest(name, c.moduleList))
+}
+
+// checkConfigBlock checks the keys of a YAML block whose top-level keys
+// include a config field; other YAML (workflows, contracts) is skipped
+func (c *staleChecker) checkConfigBlock(block string, start int, report func(int, string, string, string, string)) {
+	if len(c.config) == 0 {
+		return
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(block), &doc); err != nil || len(doc.Content) == 0 {
+		return
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return
+	}
+	isConfig := false
+	for i := 0; i < len(root.Content); i += 2 {
+		if c.configRoot[root.Content[i].Value] {
+			isConfig = true
+		}
+	}
+	if isConfig {
+		c.checkConfigNode(root, "", start, report)
+	}
+}
+
+func (c *staleChecker) checkConfigNode(node *yaml.Node, path string, start int, report func(int, string, string, string, string)) {
+	switch node.Kind {
+	case yaml.SequenceNode:
+		for _, item := range node.Content {
+			c.checkConfigNode(item, path, start, report)
+		}
+	case yaml.MappingNode:
+		if path != "" && c.configLeaf[path] {
+			return
+		}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := node.Content[i]
+			field := strings.TrimPrefix(path+"."+key.Value, ".")
+			if !c.config[field] {
+				report(start+key.Line-1, StaleKindConfig, field, fmt.Sprintf("unknown config field: %s", field), c.closestField(path, key.Value))
+				continue
+			}
+			c.checkConfigNode(node.Content[i+1], field, start, report)
+		}
+	}
+}
+
+// checkConfigPath checks a dotted field path such as docker.pull_policy
+func (c *staleChecker) checkConfigPath(span string, line int, report func(int, string, string, string, string)) {
+	field := indexPattern.ReplaceAllString(span, "")
+	segments := strings.Split(field, ".")
+	if len(c.config) == 0 || !c.configRoot[segments[0]] {
+		return
+	}
+	for i := range segments {
+		path := strings.Join(segments[:i+1], ".")
+		if !c.config[path] {
+			parent := strings.Join(segments[:i], ".")
+			report(line, StaleKindConfig, field, fmt.Sprintf("unknown config field: %s", field), c.closestField(parent, segments[i]))
+			return
+		}
+		if c.configLeaf[path] {
+			return
+		}
+	}
+}
+
+// closestField suggests a sibling of an unknown field
+func (c *staleChecker) closestField(parent, name string) string {
+	var siblings []string
+	for field := range c.config {
+		rest, ok := strings.CutPrefix(field, parent+".")
+		if parent == "" {
+			rest, ok = field, true
+		}
+		if ok && !strings.Contains(rest, ".") {
+			siblings = append(siblings, rest)
+		}
+	}
+	if suggestion := closest(name, siblings); suggestion != "" {
+		return strings.TrimPrefix(parent+"."+suggestion, ".")
+	}
+	return ""
+}
+
+// closest returns the candidate nearest to word by edit distance, or "" when
+// none is close enough to be a likely rename
+func closest(word string, candidates []string) string {
+	sorted := append([]string(nil), candidates...)
+	sort.Strings(sorted)
+
+	best, bestDistance := "", len(word)/3+2
+	for _, candidate := range sorted {
+		if d := editDistance(word, candidate); d < bestDistance {
+			best, bestDistance = candidate, d
+		}
+	}
+	return best
+}
+
+func editDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
+
+// ConfigFieldsFromSchema returns the dotted field paths declared by a JSON
+// schema, following properties, array items and local $refs
+func ConfigFieldsFromSchema(schema []byte) ([]string, error) {
+	var root map[string]any
+	if err := json.Unmarshal(schema, &root); err != nil {
+		return nil, fmt.Errorf("failed to parse schema: %w", err)
+	}
+
+	seen := make(map[string]bool)
+	var fields []string
+	var walk func(node map[string]any, path string, depth int)
+	walk = func(node map[string]any, path string, depth int) {
+		if node == nil || depth > 16 {
+			return
+		}
+		if ref, ok := node["$ref"].(string); ok {
+			walk(resolveRef(root, ref), path, depth+1)
+		}
+		if properties, ok := node["properties"].(map[string]any); ok {
+			for name, property := range properties {
+				field := strings.TrimPrefix(path+"."+name, ".")
+				if !seen[field] {
+					seen[field] = true
+					fields = append(fields, field)
+				}
+				child, _ := property.(map[string]any)
+				walk(child, field, depth+1)
+			}
+		}
+		if items, ok := node["items"].(map[string]any); ok {
+			walk(items, path, depth+1)
+		}
+		for _, key := range []string{"allOf", "anyOf", "oneOf"} {
+			if list, ok := node[key].([]any); ok {
+				for _, item := range list {
+					child, _ := item.(map[string]any)
+					walk(child, path, depth+1)
+				}
+			}
+		}
+	}
+	walk(root, "", 0)
+
+	sort.Strings(fields)
+	return fields, nil
+}
+
+// resolveRef resolves a local reference such as #/definitions/extension
+func resolveRef(root map[string]any, ref string) map[string]any {
+	if !strings.HasPrefix(ref, "#/") {
+		return nil
+	}
+	node := root
+	for _, segment := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+		next, ok := node[segment].(map[string]any)
+		if !ok {
+			return nil
+		}
+		node = next
+	}
+	return node
+}
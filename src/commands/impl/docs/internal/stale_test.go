@@ -0,0 +1,86 @@
+package docs
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFindStaleInMarkdown(t *testing.T) {
+	index := StaleIndex{
+		Commands: map[string]string{
+			"docs":                  "",
+			"docs serve":            "docs serve [--mode auto|docker|venv|native]",
+			"docs lint":             "docs lint [<path>...]",
+			"get files":             "",
+			"get affected modules":  "",
+			"test module":           "test module <moniker> [--as-cucumber|--as-junit]",
+			"changelog":             "go run . changelog [--module <moniker>]",
+			"show suite <suite-id>": "",
+		},
+		Modules:      []string{"src-cli", "src-core"},
+		ConfigFields: []string{"docker.pull_policy", "docker.timeout", "extensions", "extensions.name", "extensions.env"},
+	}
+
+	content := "# Guide\n\n" +
+		"Run `docs serve`, then `docs servr` and `get affected modulez`.\n" +
+		"The `src-cli` module replaces the `src-cmd` module.\n" +
+		"Set `docker.pull_polcy` or `extensions[0].env.FOO`.\n\n" +
+		"```bash\n" +
+		"go run . test module src-clli\n" +
+		"go run . changelog --module src-core\n" +
+		"go run . release plan\n" +
+		"test -f docs/index.md\n" +
+		"```\n\n" +
+		"```yaml\n" +
+		"docker:\n" +
+		"  pull_policy: always\n" +
+		"  retries: 3\n" +
+		"extensions:\n" +
+		"  - name: go\n" +
+		"    env:\n" +
+		"      ANY: value\n" +
+		"```\n\n" +
+		"```yaml\n" +
+		"jobs:\n" +
+		"  build: {}\n" +
+		"```\n"
+
+	got := FindStaleInMarkdown(content, index)
+	want := []StaleFinding{
+		{Line: 3, Kind: StaleKindCommand, Reference: "docs servr", Message: "unknown command: docs servr", Suggestion: "docs serve"},
+		{Line: 3, Kind: StaleKindCommand, Reference: "get affected modulez", Message: "unknown command: get affected modulez", Suggestion: "get affected modules"},
+		{Line: 4, Kind: StaleKindModule, Reference: "src-cmd", Message: "unknown module: src-cmd", Suggestion: "src-cli"},
+		{Line: 5, Kind: StaleKindConfig, Reference: "docker.pull_polcy", Message: "unknown config field: docker.pull_polcy", Suggestion: "docker.pull_policy"},
+		{Line: 8, Kind: StaleKindModule, Reference: "src-clli", Message: "unknown module: src-clli", Suggestion: "src-cli"},
+		{Line: 10, Kind: StaleKindCommand, Reference: "release", Message: "unknown command: release"},
+		{Line: 16, Kind: StaleKindConfig, Reference: "docker.retries", Message: "unknown config field: docker.retries"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("findings:\ngot  %+v\nwant %+v", got, want)
+	}
+}
+
+func TestConfigFieldsFromSchema(t *testing.T) {
+	schema := `{
+  "type": "object",
+  "properties": {
+    "docker": {"$ref": "#/definitions/docker"},
+    "extensions": {"type": "array", "items": {"properties": {"name": {"type": "string"}}}}
+  },
+  "definitions": {
+    "docker": {"properties": {"timeout": {"type": "integer"}}}
+  }
+}`
+	got, err := ConfigFieldsFromSchema([]byte(schema))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"docker", "docker.timeout", "extensions", "extensions.name"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("fields = %v, want %v", got, want)
+	}
+
+	if fields, _ := ConfigFieldsFromSchema([]byte("{}")); len(fields) != 0 {
+		t.Errorf("empty schema: fields = %v", fields)
+	}
+}
@@ -0,0 +1,107 @@
+package docs
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+const (
+	// VenvDir is the Python virtual environment for MkDocs, relative to the repository root
+	VenvDir = ".r2r/cache/mkdocs-venv"
+	// requirementsFile pins the MkDocs packages of the container image, shared with the venv
+	requirementsFile = "containers/mkdocs/requirements.txt"
+	// venvStamp records the requirements the venv was installed from
+	venvStamp = ".r2r-requirements"
+)
+
+// defaultRequirements are installed when the repository has no requirements file
+var defaultRequirements = []string{"mkdocs", "mkdocs-material"}
+
+// venvBin returns the path of an executable inside the venv
+func venvBin(venv, name string) string {
+	if runtime.GOOS == "windows" {
+		return filepath.Join(venv, "Scripts", name+".exe")
+	}
+	return filepath.Join(venv, "bin", name)
+}
+
+// FindPython returns the Python 3 interpreter on PATH, or "" when there is none
+func FindPython() string {
+	for _, name := range []string{"python3", "python"} {
+		if path, err := exec.LookPath(name); err == nil {
+			return path
+		}
+	}
+	return ""
+}
+
+// EnsureVenv creates the MkDocs venv on first use and reinstalls the packages
+// when the requirements change. Returns the path of the mkdocs executable.
+func EnsureVenv(repoRoot string, out io.Writer) (string, error) {
+	python := FindPython()
+	if python == "" {
+		return "", fmt.Errorf("python 3 is not installed")
+	}
+
+	venv := filepath.Join(repoRoot, VenvDir)
+	installArgs := append([]string{"install", "--quiet"}, defaultRequirements...)
+	requirements := strings.Join(defaultRequirements, "\n")
+	if content, err := os.ReadFile(filepath.Join(repoRoot, requirementsFile)); err == nil {
+		installArgs = []string{"install", "--quiet", "-r", filepath.Join(repoRoot, requirementsFile)}
+		requirements = string(content)
+	}
+	sum := sha256.Sum256([]byte(requirements))
+	stamp := hex.EncodeToString(sum[:])
+
+	mkdocs := venvBin(venv, "mkdocs")
+	if installed, err := os.ReadFile(filepath.Join(venv, venvStamp)); err == nil && string(installed) == stamp {
+		if _, err := os.Stat(mkdocs); err == nil {
+			return mkdocs, nil
+		}
+	}
+
+	if _, err := os.Stat(venvBin(venv, "pip")); err != nil {
+		fmt.Fprintf(out, "📦 Creating Python venv: %s\n", VenvDir)
+		if err := runTo(out, python, "-m", "venv", venv); err != nil {
+			return "", fmt.Errorf("failed to create venv: %w", err)
+		}
+	}
+
+	fmt.Fprintf(out, "📦 Installing MkDocs packages\n")
+	if err := runTo(out, venvBin(venv, "pip"), installArgs...); err != nil {
+		return "", fmt.Errorf("failed to install MkDocs packages: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(venv, venvStamp), []byte(stamp), 0644); err != nil {
+		return "", err
+	}
+	return mkdocs, nil
+}
+
+// ServeVenv runs "mkdocs serve" from the venv until the process is stopped
+func ServeVenv(repoRoot string, port int, out io.Writer) error {
+	mkdocs, err := EnsureVenv(repoRoot, out)
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.Command(mkdocs, "serve", fmt.Sprintf("--dev-addr=127.0.0.1:%d", port))
+	cmd.Dir = repoRoot
+	cmd.Stdout = out
+	cmd.Stderr = out
+	return cmd.Run()
+}
+
+// runTo runs a command with its output written to out
+func runTo(out io.Writer, name string, args ...string) error {
+	cmd := exec.Command(name, args...)
+	cmd.Stdout = out
+	cmd.Stderr = out
+	return cmd.Run()
+}
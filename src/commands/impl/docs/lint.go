@@ -0,0 +1,185 @@
+// Command: docs lint
+// Description: Lint markdown documentation with the markdownlint rules shared with commit-ai
+// Usage: docs lint [<path>...] [--fix] [--format text|json] [--line-length <n>] [--disable <rule>[,<rule>...]]
+// HasSideEffects: true
+// DryRun: true
+package docs
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/ready-to-release/eac/src/commands/impl/docs/internal"
+	"github.com/ready-to-release/eac/src/commands/internal/registry"
+	"github.com/ready-to-release/eac/src/core/repository"
+)
+
+func init() {
+	registry.Register(DocsLint)
+}
+
+// DocsLint reports markdownlint findings per file under docs/ (or the given
+// paths) and fixes them with --fix
+func DocsLint() int {
+	args := os.Args[3:] // Skip "docs" and "lint"
+
+	var paths, disabled []string
+	var fix bool
+	format := "text"
+	lineLength := 0
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+
+		switch {
+		case arg == "--fix":
+			fix = true
+		case arg == "--format" && i+1 < len(args):
+			i++
+			arg = "--format=" + args[i]
+			fallthrough
+		case strings.HasPrefix(arg, "--format="):
+			format = strings.TrimPrefix(arg, "--format=")
+		case arg == "--line-length" && i+1 < len(args):
+			i++
+			arg = "--line-length=" + args[i]
+			fallthrough
+		case strings.HasPrefix(arg, "--line-length="):
+			value, err := strconv.Atoi(strings.TrimPrefix(arg, "--line-length="))
+			if err != nil || value < 0 {
+				fmt.Fprintf(os.Stderr, "Error: --line-length must be a number\n")
+				return 1
+			}
+			lineLength = value
+		case arg == "--disable" && i+1 < len(args):
+			i++
+			arg = "--disable=" + args[i]
+			fallthrough
+		case strings.HasPrefix(arg, "--disable="):
+			for _, rule := range strings.Split(strings.TrimPrefix(arg, "--disable="), ",") {
+				if rule = strings.TrimSpace(rule); rule != "" {
+					disabled = append(disabled, rule)
+				}
+			}
+		case arg == "--help" || arg == "-h":
+			printDocsLintUsage()
+			return 0
+		case strings.HasPrefix(arg, "-"):
+			fmt.Fprintf(os.Stderr, "Error: unknown flag: %s\n", arg)
+			return 1
+		default:
+			paths = append(paths, arg)
+		}
+	}
+
+	if format != "text" && format != "json" {
+		fmt.Fprintf(os.Stderr, "Error: --format must be text or json\n")
+		return 1
+	}
+
+	root, err := repository.GetRepositoryRoot("")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to find repository root: %v\n", err)
+		return 1
+	}
+
+	fixMode := docs.FixOff
+	if fix {
+		fixMode = docs.FixWrite
+		// A dry run shows the fixes instead of writing them
+		if registry.DryRun() {
+			fixMode = docs.FixPreview
+		}
+	}
+
+	reports, err := docs.LintFiles(root, paths, docs.LintOptions(lineLength, disabled), fixMode)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	// Findings left after fixing decide the exit code
+	remaining := 0
+	for _, report := range reports {
+		if !report.Fixed {
+			remaining += len(report.Findings)
+		}
+	}
+
+	if format == "json" {
+		data, err := json.MarshalIndent(reports, "", "  ")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		fmt.Println(string(data))
+	} else {
+		printDocsLintReports(reports, fixMode)
+	}
+
+	if remaining > 0 {
+		return 1
+	}
+	return 0
+}
+
+func printDocsLintReports(reports []docs.FileReport, fixMode docs.FixMode) {
+	findings, fixed := 0, 0
+	for _, report := range reports {
+		if len(report.Findings) == 0 {
+			continue
+		}
+		findings += len(report.Findings)
+		icon := "❌"
+		if report.Fixed {
+			icon = "✅"
+			fixed++
+		}
+		fmt.Printf("%s %s\n", icon, report.Path)
+		for _, finding := range report.Findings {
+			fmt.Printf("   line %d: %s %s\n", finding.Line, finding.Rule, finding.Description)
+		}
+		if report.Diff != "" {
+			fmt.Println()
+			fmt.Print(report.Diff)
+			fmt.Println()
+		}
+	}
+
+	if findings == 0 {
+		fmt.Printf("✅ %d markdown file(s), no findings\n", len(reports))
+		return
+	}
+	fmt.Printf("\n📊 %d markdown file(s), %d finding(s)", len(reports), findings)
+	if fixed > 0 {
+		fmt.Printf(", %d file(s) fixed", fixed)
+	} else if fixMode == docs.FixPreview {
+		fmt.Print(" (dry run: nothing was fixed)")
+	} else {
+		fmt.Print(" (fix with: docs lint --fix)")
+	}
+	fmt.Println()
+}
+
+func printDocsLintUsage() {
+	fmt.Println("Lint markdown documentation with the markdownlint rules shared with commit-ai")
+	fmt.Println()
+	fmt.Println("Usage:")
+	fmt.Println("  go run . docs lint [<path>...] [flags]")
+	fmt.Println()
+	fmt.Println("Parameters:")
+	fmt.Println("  <path>                     Markdown files or directories (default: docs)")
+	fmt.Println()
+	fmt.Println("Flags:")
+	fmt.Println("  --fix                      Rewrite files with findings (with --dry-run: show the diff)")
+	fmt.Println("  --format text|json         Output format (default: text)")
+	fmt.Println("  --line-length <n>          Wrap paragraphs at n columns (MD013, default: off)")
+	fmt.Println("  --disable <rule>[,...]     Skip rules, e.g. MD041,MD055")
+	fmt.Println()
+	fmt.Println("Rules: MD009, MD041, MD025, MD022, MD031, MD013, MD055, MD012, MD047")
+	fmt.Println()
+	fmt.Println("Exit codes: 0 no findings left, 1 findings or failure")
+}
@@ -0,0 +1,122 @@
+// Command: docs nav
+// Description: Regenerate the nav section of mkdocs.yml from the docs tree
+// Usage: docs nav [--write] [--config <path>]
+// HasSideEffects: true
+// DryRun: true
+package docs
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ready-to-release/eac/src/commands/impl/docs/internal"
+	"github.com/ready-to-release/eac/src/commands/internal/registry"
+	"github.com/ready-to-release/eac/src/core/repository"
+)
+
+func init() {
+	registry.Register(DocsNav)
+}
+
+// DocsNav shows the diff between the nav section of mkdocs.yml and the one
+// generated from the docs tree, and writes it with --write
+func DocsNav() int {
+	args := os.Args[3:] // Skip "docs" and "nav"
+
+	var write bool
+	config := "mkdocs.yml"
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+
+		switch {
+		case arg == "--write":
+			write = true
+		case arg == "--config" && i+1 < len(args):
+			i++
+			config = args[i]
+		case strings.HasPrefix(arg, "--config="):
+			config = strings.TrimPrefix(arg, "--config=")
+		case arg == "--help" || arg == "-h":
+			printDocsNavUsage()
+			return 0
+		default:
+			fmt.Fprintf(os.Stderr, "Error: unknown argument: %s\n", arg)
+			return 1
+		}
+	}
+
+	// A dry run shows the diff, as without --write
+	if registry.DryRun() {
+		write = false
+	}
+
+	root, err := repository.GetRepositoryRoot("")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to find repository root: %v\n", err)
+		return 1
+	}
+
+	configPath := config
+	if !filepath.IsAbs(configPath) {
+		configPath = filepath.Join(root, config)
+	}
+	content, err := os.ReadFile(configPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to read %s: %v\n", config, err)
+		return 1
+	}
+
+	// docs_dir is relative to mkdocs.yml
+	docsDir := filepath.Join(filepath.Dir(configPath), docs.DocsDirFromConfig(string(content)))
+	nav, err := docs.BuildNav(docsDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	updated := docs.ReplaceNav(string(content), docs.RenderNav(nav))
+	diff := docs.UnifiedDiff(filepath.ToSlash(config), string(content), updated)
+	if diff == "" {
+		fmt.Printf("✅ nav in %s is up to date\n", config)
+		return 0
+	}
+
+	fmt.Print(diff)
+	fmt.Println()
+
+	if !write {
+		fmt.Printf("ℹ️  nav in %s is out of date (update with: docs nav --write)\n", config)
+		return 1
+	}
+
+	if err := os.WriteFile(configPath, []byte(updated), 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to write %s: %v\n", config, err)
+		return 1
+	}
+	fmt.Printf("✅ Updated nav in %s\n", config)
+	return 0
+}
+
+func printDocsNavUsage() {
+	fmt.Println("Regenerate the nav section of mkdocs.yml from the docs tree")
+	fmt.Println()
+	fmt.Println("Usage:")
+	fmt.Println("  go run . docs nav [--write] [--config <path>]")
+	fmt.Println()
+	fmt.Println("Flags:")
+	fmt.Println("  --write            Write the new nav (default: show the diff only; ignored with --dry-run)")
+	fmt.Println("  --config <path>    MkDocs config (default: mkdocs.yml)")
+	fmt.Println()
+	fmt.Println("Order in each directory:")
+	fmt.Println("  1. Entries listed in .order (one file or directory name per line)")
+	fmt.Println("  2. index.md")
+	fmt.Println("  3. Diátaxis sections: tutorials, how-to, reference, explanation")
+	fmt.Println("  4. Everything else by name")
+	fmt.Println()
+	fmt.Println("Page titles come from the front matter title, else the first heading.")
+	fmt.Println()
+	fmt.Println("Exit codes: 0 up to date or written, 1 out of date or failure")
+}
@@ -1,21 +1,35 @@
 // Command: docs serve
 // Description: Start or stop MkDocs server
+// Usage: docs serve [--mode auto|docker|venv|native] [--port <port>] [--no-browser] [--debug] [--stop]
 // HasSideEffects: false
 package docs
 
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"strconv"
+	"strings"
 
 	"github.com/ready-to-release/eac/src/commands/impl/docs/internal"
 	"github.com/ready-to-release/eac/src/commands/internal/registry"
+	"github.com/ready-to-release/eac/src/core/repository"
 )
 
 func init() {
 	registry.Register(DocsServe)
 }
 
+// Serve modes: docker runs the MkDocs container, venv runs MkDocs from a local
+// Python venv, native renders markdown in-process. auto picks the first that
+// is available in that order.
+const (
+	serveModeAuto   = "auto"
+	serveModeDocker = "docker"
+	serveModeVenv   = "venv"
+	serveModeNative = "native"
+)
+
 // DocsServe starts or stops MkDocs server
 func DocsServe() int {
 	args := os.Args[3:] // Skip "go", "run", ".", "docs", and "serve"
@@ -24,6 +38,7 @@ func DocsServe() int {
 	var port int = 8000
 	var stop bool
 	var debug bool
+	mode := serveModeAuto
 
 	// Parse arguments
 	for i := 0; i < len(args); i++ {
@@ -36,6 +51,14 @@ func DocsServe() int {
 			stop = true
 		case "--debug":
 			debug = true
+		case "--mode":
+			if i+1 < len(args) {
+				i++
+				mode = args[i]
+			} else {
+				fmt.Fprintf(os.Stderr, "Error: --mode requires a value\n")
+				return 1
+			}
 		case "--port", "-p":
 			if i+1 < len(args) {
 				i++
@@ -50,21 +73,45 @@ func DocsServe() int {
 				return 1
 			}
 		default:
+			if strings.HasPrefix(arg, "--mode=") {
+				mode = strings.TrimPrefix(arg, "--mode=")
+				continue
+			}
 			fmt.Fprintf(os.Stderr, "Error: unknown flag: %s\n", arg)
 			return 1
 		}
 	}
 
-	// Handle --stop flag
+	switch mode {
+	case serveModeAuto, serveModeDocker, serveModeVenv, serveModeNative:
+	default:
+		fmt.Fprintf(os.Stderr, "Error: invalid mode: %s (expected auto, docker, venv or native)\n", mode)
+		return 1
+	}
+
+	// Handle --stop flag (only the container runs in the background)
 	if stop {
 		return handleDocsStop()
 	}
 
-	// Create client
-	client, err := docs.NewClient()
-	if err != nil {
-		fmt.Printf("❌ Failed to initialize: %v\n", err)
-		return 1
+	var client *docs.Client
+	if mode == serveModeAuto || mode == serveModeDocker {
+		var err error
+		client, err = docs.NewClient()
+		if err != nil && mode == serveModeDocker {
+			fmt.Printf("❌ Failed to initialize: %v\n", err)
+			return 1
+		}
+		if err != nil {
+			mode = serveModeNative
+			if docs.FindPython() != "" {
+				mode = serveModeVenv
+			}
+			fmt.Printf("ℹ️  Docker is not available, using %s mode\n", mode)
+		}
+	}
+	if client == nil {
+		return serveLocal(mode, port, noBrowser)
 	}
 	defer client.Close()
 
@@ -139,6 +186,47 @@ func DocsServe() int {
 	return 0
 }
 
+// serveLocal runs the venv or native preview in the foreground until stopped
+func serveLocal(mode string, port int, noBrowser bool) int {
+	root, err := repository.GetRepositoryRoot("")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to find repository root: %v\n", err)
+		return 1
+	}
+
+	url := fmt.Sprintf("http://localhost:%d", port)
+	if mode == serveModeVenv {
+		fmt.Printf("🚀 Starting MkDocs from %s\n", docs.VenvDir)
+		fmt.Printf("📚 Documentation: %s (Press Ctrl+C to stop)\n", url)
+	} else {
+		fmt.Printf("🚀 Starting native documentation preview\n")
+		fmt.Printf("📚 Documentation: %s (Press Ctrl+C to stop)\n", url)
+		fmt.Println("ℹ️  Markdown only: MkDocs theme and extensions are not applied")
+	}
+
+	if !noBrowser {
+		if err := docs.OpenBrowser(url); err != nil {
+			fmt.Printf("\n⚠️  Failed to open browser: %v\n", err)
+			fmt.Printf("📖 Please open manually: %s\n", url)
+		}
+	}
+
+	if mode == serveModeVenv {
+		err = docs.ServeVenv(root, port, os.Stdout)
+	} else {
+		docsDir := docs.DocsDir
+		if config, readErr := os.ReadFile(filepath.Join(root, "mkdocs.yml")); readErr == nil {
+			docsDir = docs.DocsDirFromConfig(string(config))
+		}
+		err = docs.ServeNative(filepath.Join(root, docsDir), port)
+	}
+	if err != nil {
+		fmt.Printf("❌ Failed to serve documentation: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
 func handleDocsStop() int {
 	client, err := docs.NewClient()
 	if err != nil {
@@ -0,0 +1,173 @@
+// Command: docs stale
+// Description: Find references in the docs to commands, modules or config fields that no longer exist
+// Usage: docs stale [<path>...] [--format text|json]
+// HasSideEffects: false
+package docs
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ready-to-release/eac/src/commands/impl/docs/internal"
+	"github.com/ready-to-release/eac/src/commands/internal/registry"
+	"github.com/ready-to-release/eac/src/core/contracts/reports"
+	"github.com/ready-to-release/eac/src/core/repository"
+)
+
+func init() {
+	registry.Register(DocsStale)
+}
+
+// DocsStale cross-references the docs against the command registry, the
+// module contracts and the r2r-cli.yml schema
+func DocsStale() int {
+	args := os.Args[3:] // Skip "docs" and "stale"
+
+	var paths []string
+	format := "text"
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+
+		switch {
+		case arg == "--format" && i+1 < len(args):
+			i++
+			format = args[i]
+		case strings.HasPrefix(arg, "--format="):
+			format = strings.TrimPrefix(arg, "--format=")
+		case arg == "--help" || arg == "-h":
+			printDocsStaleUsage()
+			return 0
+		case strings.HasPrefix(arg, "-"):
+			fmt.Fprintf(os.Stderr, "Error: unknown flag: %s\n", arg)
+			return 1
+		default:
+			paths = append(paths, arg)
+		}
+	}
+
+	if format != "text" && format != "json" {
+		fmt.Fprintf(os.Stderr, "Error: --format must be text or json\n")
+		return 1
+	}
+
+	root, err := repository.GetRepositoryRoot("")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to find repository root: %v\n", err)
+		return 1
+	}
+
+	index, notes := buildStaleIndex(root)
+	findings, files, err := docs.FindStale(root, paths, index)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	if format == "json" {
+		if findings == nil {
+			findings = []docs.StaleFinding{}
+		}
+		data, err := json.MarshalIndent(findings, "", "  ")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		fmt.Println(string(data))
+	} else {
+		for _, note := range notes {
+			fmt.Printf("ℹ️  %s\n", note)
+		}
+		printDocsStaleFindings(findings, files)
+	}
+
+	if len(findings) > 0 {
+		return 1
+	}
+	return 0
+}
+
+// buildStaleIndex collects the registered commands, the module monikers and
+// the config fields. Sources that cannot be read are skipped with a note.
+func buildStaleIndex(root string) (docs.StaleIndex, []string) {
+	var notes []string
+	index := docs.StaleIndex{Commands: make(map[string]string)}
+
+	for _, command := range registry.GetCommandRegistry() {
+		index.Commands[command.ActualCommand] = command.Usage
+	}
+
+	report, err := reports.GetModuleContracts(root, "0.1.0")
+	if err != nil {
+		notes = append(notes, fmt.Sprintf("Module references not checked: %v", err))
+	} else {
+		for _, module := range report.Modules {
+			index.Modules = append(index.Modules, module.Moniker)
+		}
+	}
+
+	schemas, _ := filepath.Glob(filepath.Join(root, "contracts", "cli", "*", "schema.json"))
+	sort.Strings(schemas)
+	if len(schemas) == 0 {
+		notes = append(notes, "Config references not checked: no contracts/cli/<version>/schema.json")
+		return index, notes
+	}
+	schema := schemas[len(schemas)-1]
+	content, err := os.ReadFile(schema)
+	if err == nil {
+		index.ConfigFields, err = docs.ConfigFieldsFromSchema(content)
+	}
+	switch {
+	case err != nil:
+		notes = append(notes, fmt.Sprintf("Config references not checked: %v", err))
+	case len(index.ConfigFields) == 0:
+		rel, _ := filepath.Rel(root, schema)
+		notes = append(notes, fmt.Sprintf("Config references not checked: %s declares no fields", filepath.ToSlash(rel)))
+	}
+	return index, notes
+}
+
+func printDocsStaleFindings(findings []docs.StaleFinding, files int) {
+	if len(findings) == 0 {
+		fmt.Printf("✅ %d markdown file(s), no stale references\n", files)
+		return
+	}
+
+	path := ""
+	for _, finding := range findings {
+		if finding.Path != path {
+			path = finding.Path
+			fmt.Printf("❌ %s\n", path)
+		}
+		fmt.Printf("   line %d: %s", finding.Line, finding.Message)
+		if finding.Suggestion != "" {
+			fmt.Printf(" (renamed to %s?)", finding.Suggestion)
+		}
+		fmt.Println()
+	}
+	fmt.Printf("\n📊 %d markdown file(s), %d stale reference(s)\n", files, len(findings))
+}
+
+func printDocsStaleUsage() {
+	fmt.Println("Find references in the docs to commands, modules or config fields that no longer exist")
+	fmt.Println()
+	fmt.Println("Usage:")
+	fmt.Println("  go run . docs stale [<path>...] [flags]")
+	fmt.Println()
+	fmt.Println("Parameters:")
+	fmt.Println("  <path>                 Markdown files or directories (default: docs)")
+	fmt.Println()
+	fmt.Println("Flags:")
+	fmt.Println("  --format text|json     Output format (default: text)")
+	fmt.Println()
+	fmt.Println("Checked references:")
+	fmt.Println("  commands    Code spans and shell blocks starting with a command group or \"go run .\"")
+	fmt.Println("  modules     Module arguments, --module values and \"the `x` module\" in prose")
+	fmt.Println("  config      YAML blocks and dotted paths (docker.timeout) of r2r-cli.yml fields")
+	fmt.Println()
+	fmt.Println("Exit codes: 0 no stale references, 1 stale references or failure")
+}
@@ -0,0 +1,79 @@
+// Command: get affected modules
+// Description: Get modules affected by changes since a base ref, with their dependents and CI path filters
+// Flags:
+//   --as-yaml: Output as YAML (default)
+//   --as-json: Output as JSON
+//   --as-toml: Output as TOML
+//   --base <ref>: Base ref to compare against, using its merge base with HEAD (default: origin/main)
+// HasSideEffects: false
+package get
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/ready-to-release/eac/src/commands/impl/get/internal"
+	"github.com/ready-to-release/eac/src/commands/internal/registry"
+	"github.com/ready-to-release/eac/src/core/repository"
+)
+
+func init() {
+	registry.Register(GetAffectedModules)
+}
+
+func GetAffectedModules() int {
+	// Get repository root
+	workspaceRoot, err := repository.GetRepositoryRoot("")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to find repository root: %v\n", err)
+		return 1
+	}
+
+	// Get base ref from flags
+	baseRef := "origin/main"
+	for i, arg := range os.Args {
+		if arg == "--base" && i+1 < len(os.Args) {
+			baseRef = os.Args[i+1]
+			break
+		}
+	}
+
+	// Files changed on this branch since it diverged from the base ref
+	cmd := exec.Command("git", "diff", "--name-only", baseRef+"...HEAD")
+	cmd.Dir = workspaceRoot
+	output, err := cmd.Output()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error getting changed files against %s: %v\n", baseRef, err)
+		return 1
+	}
+
+	changedFiles := strings.Split(strings.TrimSpace(string(output)), "\n")
+	if len(changedFiles) == 1 && changedFiles[0] == "" {
+		changedFiles = []string{}
+	}
+
+	// Use the shared get command helper
+	return get.ExecuteGetCommand(func() (interface{}, error) {
+		affected, err := repository.GetAffectedModules(changedFiles, workspaceRoot, "0.1.0")
+		if err != nil {
+			return nil, err
+		}
+
+		// Return as struct for proper serialization
+		return struct {
+			Base        string              `json:"base" yaml:"base" toml:"base"`
+			Changed     []string            `json:"changed" yaml:"changed" toml:"changed"`
+			Dependents  []string            `json:"dependents" yaml:"dependents" toml:"dependents"`
+			Affected    []string            `json:"affected" yaml:"affected" toml:"affected"`
+			PathFilters map[string][]string `json:"path_filters" yaml:"path_filters" toml:"path_filters"`
+		}{
+			Base:        baseRef,
+			Changed:     affected.Changed,
+			Dependents:  affected.Dependents,
+			Affected:    affected.Affected,
+			PathFilters: affected.PathFilters,
+		}, nil
+	})
+}
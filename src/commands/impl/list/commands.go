@@ -8,6 +8,7 @@ import (
 	"fmt"
 
 	"github.com/ready-to-release/eac/src/commands/internal/render"
+	"github.com/ready-to-release/eac/src/core/collections"
 )
 
 func init() {
@@ -16,20 +17,7 @@ func init() {
 
 func ListCommands() int {
 	// Get sorted command names
-	var names []string
-	commands := registry.GetCommands()
-	for name := range commands {
-		names = append(names, name)
-	}
-
-	// Simple alphabetical sort
-	for i := 0; i < len(names); i++ {
-		for j := i + 1; j < len(names); j++ {
-			if names[i] > names[j] {
-				names[i], names[j] = names[j], names[i]
-			}
-		}
-	}
+	names := collections.SortedKeys(registry.GetCommands())
 
 	// Render as compact list
 	result := render.RenderCompactList("Available Commands", names)
@@ -0,0 +1,180 @@
+// Package scaffold generates module contracts for existing source directories
+package scaffold
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// monikerPattern matches the module names accepted in commit message titles
+var monikerPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9\-]*$`)
+
+// ValidateMoniker checks that a moniker can be used as a contract filename and commit scope
+func ValidateMoniker(moniker string) error {
+	if !monikerPattern.MatchString(moniker) {
+		return fmt.Errorf("invalid module name %q: use lowercase letters, digits and dashes", moniker)
+	}
+	if moniker == "multi-module" {
+		return fmt.Errorf("invalid module name %q: reserved for multi-module commits", moniker)
+	}
+	return nil
+}
+
+// Spec describes the module contract to generate
+type Spec struct {
+	Moniker     string
+	Name        string
+	Type        string
+	Description string
+	Parent      string
+	Root        string   // source root, relative to the repository root
+	Includes    []string // glob patterns, relative to Root
+}
+
+// contractFile mirrors the fields of contracts.BaseContract written by the scaffold
+type contractFile struct {
+	Moniker     string `yaml:"moniker"`
+	Name        string `yaml:"name"`
+	Type        string `yaml:"type,omitempty"`
+	Description string `yaml:"description"`
+	Parent      string `yaml:"parent,omitempty"`
+	Versioning  struct {
+		VersionScheme string `yaml:"version_scheme"`
+	} `yaml:"versioning"`
+	Source struct {
+		Root     string   `yaml:"root"`
+		Includes []string `yaml:"includes"`
+	} `yaml:"source"`
+	DependsOn []string `yaml:"depends_on"`
+}
+
+// ContractYAML renders the module contract
+func ContractYAML(spec Spec) ([]byte, error) {
+	contract := contractFile{
+		Moniker:     spec.Moniker,
+		Name:        spec.Name,
+		Type:        spec.Type,
+		Description: spec.Description,
+		Parent:      spec.Parent,
+		DependsOn:   []string{},
+	}
+	contract.Versioning.VersionScheme = "semver"
+	contract.Source.Root = spec.Root
+	contract.Source.Includes = spec.Includes
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(contract); err != nil {
+		return nil, fmt.Errorf("failed to render contract: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// InferIncludes derives include globs from the files under a module root:
+// one "**/*.<ext>" pattern per extension and one "**/<name>" pattern per
+// extension-less file name (e.g. Makefile, Dockerfile)
+func InferIncludes(files []string) []string {
+	seen := make(map[string]bool)
+	var includes []string
+	for _, file := range files {
+		base := path.Base(filepath.ToSlash(file))
+		pattern := "**/" + base
+		if ext := path.Ext(base); ext != "" && ext != base {
+			pattern = "**/*" + ext
+		}
+		if !seen[pattern] {
+			seen[pattern] = true
+			includes = append(includes, pattern)
+		}
+	}
+	sort.Strings(includes)
+	return includes
+}
+
+// InferType guesses the module type from marker files in the module root
+func InferType(files []string) string {
+	markers := map[string]string{
+		"go.mod":         "go",
+		"package.json":   "node",
+		"pyproject.toml": "python",
+		"mkdocs.yml":     "docs",
+		"Dockerfile":     "container",
+	}
+	for _, file := range files {
+		if moduleType, ok := markers[filepath.ToSlash(file)]; ok {
+			return moduleType
+		}
+	}
+	return ""
+}
+
+// DefinitionsFile is the per-directory definitions file merged by "r2r definitions"
+const DefinitionsFile = "definitions.yml"
+
+// RegisterDefinition records the module moniker in <dir>/definitions.yml, creating
+// the file when missing and keeping existing content. Returns false when the
+// file already names a module.
+func RegisterDefinition(dir string, moniker string) (bool, error) {
+	definitionsPath := filepath.Join(dir, DefinitionsFile)
+
+	var doc yaml.Node
+	data, err := os.ReadFile(definitionsPath)
+	switch {
+	case os.IsNotExist(err):
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode}}}
+	case err != nil:
+		return false, fmt.Errorf("failed to read %s: %w", definitionsPath, err)
+	default:
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return false, fmt.Errorf("failed to parse %s: %w", definitionsPath, err)
+		}
+		if len(doc.Content) == 0 {
+			doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode}}}
+		}
+	}
+
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return false, fmt.Errorf("%s: expected a mapping at the top level", definitionsPath)
+	}
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if root.Content[i].Value == "module" {
+			return false, nil
+		}
+	}
+	root.Content = append(root.Content,
+		&yaml.Node{Kind: yaml.ScalarNode, Value: "module"},
+		&yaml.Node{Kind: yaml.ScalarNode, Value: moniker},
+	)
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&doc); err != nil {
+		return false, fmt.Errorf("failed to render %s: %w", definitionsPath, err)
+	}
+	if err := os.WriteFile(definitionsPath, buf.Bytes(), 0644); err != nil {
+		return false, fmt.Errorf("failed to write %s: %w", definitionsPath, err)
+	}
+	return true, nil
+}
+
+// DisplayName turns a moniker into a readable name ("src-cli" -> "Src Cli")
+func DisplayName(moniker string) string {
+	words := strings.Split(moniker, "-")
+	for i, word := range words {
+		if word != "" {
+			words[i] = strings.ToUpper(word[:1]) + word[1:]
+		}
+	}
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,75 @@
+package scaffold
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestInferIncludes(t *testing.T) {
+	files := []string{"go.mod", "main.go", "cmd/root.go", "Makefile", "docs/README.md", ".gitignore"}
+
+	got := InferIncludes(files)
+	want := []string{"**/*.go", "**/*.md", "**/*.mod", "**/.gitignore", "**/Makefile"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("InferIncludes() = %v, want %v", got, want)
+	}
+}
+
+func TestValidateMoniker(t *testing.T) {
+	for _, valid := range []string{"src-cli", "docs", "mcp2"} {
+		if err := ValidateMoniker(valid); err != nil {
+			t.Errorf("ValidateMoniker(%q) = %v, want nil", valid, err)
+		}
+	}
+	for _, invalid := range []string{"", "Src", "src_cli", "-cli", "multi-module", "a/b"} {
+		if err := ValidateMoniker(invalid); err == nil {
+			t.Errorf("ValidateMoniker(%q) = nil, want error", invalid)
+		}
+	}
+}
+
+func TestContractYAML(t *testing.T) {
+	content, err := ContractYAML(Spec{
+		Moniker:     "src-tool",
+		Name:        "Src Tool",
+		Type:        "go",
+		Description: "Module for src/tool",
+		Root:        "src/tool",
+		Includes:    []string{"**/*.go"},
+	})
+	if err != nil {
+		t.Fatalf("ContractYAML() error = %v", err)
+	}
+
+	for _, want := range []string{"moniker: src-tool", "type: go", "root: src/tool", "- '**/*.go'", "version_scheme: semver"} {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("contract missing %q:\n%s", want, content)
+		}
+	}
+}
+
+func TestRegisterDefinition(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, DefinitionsFile)
+	if err := os.WriteFile(path, []byte("owner: platform\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	registered, err := RegisterDefinition(dir, "src-tool")
+	if err != nil || !registered {
+		t.Fatalf("RegisterDefinition() = %v, %v; want true, nil", registered, err)
+	}
+
+	data, _ := os.ReadFile(path)
+	if string(data) != "owner: platform\nmodule: src-tool\n" {
+		t.Errorf("unexpected definitions.yml:\n%s", data)
+	}
+
+	registered, err = RegisterDefinition(dir, "other")
+	if err != nil || registered {
+		t.Errorf("second RegisterDefinition() = %v, %v; want false, nil", registered, err)
+	}
+}
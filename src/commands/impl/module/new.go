@@ -0,0 +1,257 @@
+// Command: module new
+// Description: Create a module contract for an existing path and register it in definitions.yml
+// Usage: module new <name> --path <dir> [--type <type>] [--description <description>] [--parent <moniker>] [--force]
+// HasSideEffects: true
+package module
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/ready-to-release/eac/src/commands/impl/module/internal"
+	"github.com/ready-to-release/eac/src/commands/internal/registry"
+	"github.com/ready-to-release/eac/src/core/contracts/modules"
+	"github.com/ready-to-release/eac/src/core/repository"
+)
+
+const contractVersion = "0.1.0"
+
+func init() {
+	registry.Register(ModuleNew)
+}
+
+// ModuleNew writes contracts/modules/0.1.0/<name>.yml with includes inferred
+// from the files under --path, then loads the contracts to validate it
+func ModuleNew() int {
+	args := os.Args[3:] // Skip program name, "module", "new"
+
+	if len(args) == 0 {
+		printModuleNewUsage()
+		return 1
+	}
+
+	moniker := args[0]
+	var sourcePath, moduleType, description, parent string
+	var force bool
+
+	// Parse flags
+	for i := 1; i < len(args); i++ {
+		switch args[i] {
+		case "--path", "-p", "--type", "-t", "--description", "-d", "--parent":
+			if i+1 >= len(args) {
+				fmt.Fprintf(os.Stderr, "Error: %s requires a value\n", args[i])
+				return 1
+			}
+			value := args[i+1]
+			switch args[i] {
+			case "--path", "-p":
+				sourcePath = value
+			case "--type", "-t":
+				moduleType = value
+			case "--description", "-d":
+				description = value
+			case "--parent":
+				parent = value
+			}
+			i++
+		case "--force", "-f":
+			force = true
+		case "--help", "-h":
+			printModuleNewUsage()
+			return 0
+		default:
+			fmt.Fprintf(os.Stderr, "Error: unknown flag: %s\n\n", args[i])
+			printModuleNewUsage()
+			return 1
+		}
+	}
+
+	if err := scaffold.ValidateMoniker(moniker); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	if sourcePath == "" {
+		fmt.Fprintf(os.Stderr, "Error: --path is required\n\n")
+		printModuleNewUsage()
+		return 1
+	}
+
+	workspaceRoot, err := repository.GetRepositoryRoot("")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to find repository root: %v\n", err)
+		return 1
+	}
+
+	// Resolve the path relative to where the command was started
+	absPath := sourcePath
+	if !filepath.IsAbs(absPath) {
+		absPath = filepath.Join(registry.InitialWorkingDir, sourcePath)
+	}
+	root, err := filepath.Rel(workspaceRoot, absPath)
+	if err != nil || strings.HasPrefix(root, "..") {
+		fmt.Fprintf(os.Stderr, "Error: %s is outside the repository\n", sourcePath)
+		return 1
+	}
+	root = filepath.ToSlash(root)
+	if root == "." {
+		fmt.Fprintf(os.Stderr, "Error: --path must be a subdirectory of the repository\n")
+		return 1
+	}
+	if info, err := os.Stat(absPath); err != nil || !info.IsDir() {
+		fmt.Fprintf(os.Stderr, "Error: %s is not a directory\n", sourcePath)
+		return 1
+	}
+
+	contractPath := filepath.Join(workspaceRoot, "contracts", "modules", contractVersion, moniker+".yml")
+	if _, err := os.Stat(contractPath); err == nil && !force {
+		fmt.Fprintf(os.Stderr, "Error: %s already exists (use --force to overwrite)\n", contractPath)
+		return 1
+	}
+
+	files, err := listFiles(workspaceRoot, root)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error listing files in %s: %v\n", root, err)
+		return 1
+	}
+	if len(files) == 0 {
+		fmt.Fprintf(os.Stderr, "Error: no files found in %s\n", root)
+		return 1
+	}
+
+	if moduleType == "" {
+		moduleType = scaffold.InferType(files)
+	}
+	if description == "" {
+		description = fmt.Sprintf("Module for %s", root)
+	}
+
+	content, err := scaffold.ContractYAML(scaffold.Spec{
+		Moniker:     moniker,
+		Name:        scaffold.DisplayName(moniker),
+		Type:        moduleType,
+		Description: description,
+		Parent:      parent,
+		Root:        root,
+		Includes:    scaffold.InferIncludes(files),
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	previous, readErr := os.ReadFile(contractPath)
+	if err := os.MkdirAll(filepath.Dir(contractPath), 0755); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to create contracts directory: %v\n", err)
+		return 1
+	}
+	if err := os.WriteFile(contractPath, content, 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to write contract: %v\n", err)
+		return 1
+	}
+
+	// Validate by loading all contracts; restore the previous state on failure
+	if err := validateModule(workspaceRoot, moniker, root, files); err != nil {
+		if readErr == nil {
+			os.WriteFile(contractPath, previous, 0644)
+		} else {
+			os.Remove(contractPath)
+		}
+		fmt.Fprintf(os.Stderr, "❌ Generated contract is invalid: %v\n", err)
+		return 1
+	}
+
+	registered, err := scaffold.RegisterDefinition(absPath, moniker)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	relContract, _ := filepath.Rel(workspaceRoot, contractPath)
+	fmt.Printf("✅ Created %s\n", filepath.ToSlash(relContract))
+	if registered {
+		fmt.Printf("✅ Registered %s in %s/%s\n", moniker, root, scaffold.DefinitionsFile)
+	} else {
+		fmt.Printf("⚠️  %s/%s already names a module, left unchanged\n", root, scaffold.DefinitionsFile)
+	}
+	fmt.Println()
+	fmt.Print(string(content))
+
+	return 0
+}
+
+// listFiles returns tracked and untracked (not ignored) files under root, relative to root
+func listFiles(workspaceRoot, root string) ([]string, error) {
+	cmd := exec.Command("git", "ls-files", "--cached", "--others", "--exclude-standard", "--", root)
+	cmd.Dir = workspaceRoot
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		if line == "" {
+			continue
+		}
+		files = append(files, strings.TrimPrefix(line, root+"/"))
+	}
+	return files, nil
+}
+
+// validateModule loads the contracts and checks that the module owns its files
+func validateModule(workspaceRoot, moniker, root string, files []string) error {
+	moduleRegistry, err := modules.LoadFromWorkspace(workspaceRoot, contractVersion)
+	if err != nil {
+		return err
+	}
+
+	module, ok := moduleRegistry.Get(moniker)
+	if !ok {
+		return fmt.Errorf("module %s not found after loading contracts", moniker)
+	}
+
+	for _, file := range files {
+		if !module.MatchesFile(root + "/" + file) {
+			return fmt.Errorf("module %s does not match %s/%s", moniker, root, file)
+		}
+	}
+
+	owned := false
+	for _, file := range files {
+		for _, owner := range moduleRegistry.FindModulesForFile(root + "/" + file) {
+			if owner.Moniker == moniker {
+				owned = true
+			}
+		}
+	}
+	if !owned {
+		return fmt.Errorf("module %s owns none of its files; another module claims them", moniker)
+	}
+
+	return nil
+}
+
+func printModuleNewUsage() {
+	fmt.Println("Create a module contract for an existing path")
+	fmt.Println()
+	fmt.Println("Usage:")
+	fmt.Println("  go run . module new <name> --path <dir> [--type <type>] [--description <description>] [--parent <moniker>] [--force]")
+	fmt.Println()
+	fmt.Println("Parameters:")
+	fmt.Println("  <name>                Module moniker (lowercase letters, digits and dashes)")
+	fmt.Println()
+	fmt.Println("Flags:")
+	fmt.Println("  --path, -p            Module source root (required)")
+	fmt.Println("  --type, -t            Module type (default: inferred from go.mod, package.json, ...)")
+	fmt.Println("  --description, -d     Module description")
+	fmt.Println("  --parent              Parent module moniker")
+	fmt.Println("  --force, -f           Overwrite an existing contract")
+	fmt.Println("  --help, -h            Show this help message")
+	fmt.Println()
+	fmt.Println("Output:")
+	fmt.Println("  Creates: contracts/modules/0.1.0/<name>.yml")
+	fmt.Println("  Updates: <dir>/definitions.yml")
+}
@@ -0,0 +1,173 @@
+// Package promotion records which version of each deployable unit runs in
+// which environment, and checks the gates an environment sets before a
+// version is promoted to it
+package promotion
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigFile declares the environments and their gates, relative to the
+// repository root. It is optional: without it any environment name is
+// accepted and has no gates.
+const ConfigFile = ".r2r/environments.yml"
+
+// StateFile records the version of each unit in each environment, relative
+// to the repository root. It is written by promote and meant to be committed.
+const StateFile = ".r2r/environments.state.yml"
+
+// Config declares the environments versions are promoted through
+type Config struct {
+	Environments map[string]Environment `yaml:"environments"`
+}
+
+// Environment is an environment and the gates of promotions to it
+type Environment struct {
+	From           []string `yaml:"from"`            // environments promotions may come from (default any)
+	RequiredChecks []string `yaml:"required_checks"` // check runs or statuses that must have succeeded on the commit
+	Approvals      int      `yaml:"approvals"`       // approving reviews required on the pull request of the commit
+	GitHub         bool     `yaml:"github"`          // also record promotions as deployments to the GitHub environment of this name
+}
+
+// HasRemoteGates reports whether checking the gates needs GitHub
+func (e Environment) HasRemoteGates() bool {
+	return len(e.RequiredChecks) > 0 || e.Approvals > 0
+}
+
+// LoadConfig reads .r2r/environments.yml, returning an empty config when it does not exist
+func LoadConfig(workspaceRoot string) (*Config, error) {
+	var config Config
+
+	data, err := os.ReadFile(filepath.Join(workspaceRoot, ConfigFile))
+	if os.IsNotExist(err) {
+		return &config, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read environments config: %w", err)
+	}
+
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse environments config YAML: %w", err)
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid environments config %s: %w", ConfigFile, err)
+	}
+
+	return &config, nil
+}
+
+// Validate checks the gates and that from lists declared environments
+func (c *Config) Validate() error {
+	for _, name := range c.names() {
+		environment := c.Environments[name]
+		if environment.Approvals < 0 {
+			return fmt.Errorf("environments.%s: approvals must not be negative", name)
+		}
+		for _, from := range environment.From {
+			if _, ok := c.Environments[from]; !ok {
+				return fmt.Errorf("environments.%s: from: unknown environment %q", name, from)
+			}
+		}
+	}
+	return nil
+}
+
+// Environment returns the declared environment of a name. Without declared
+// environments, every name is an environment without gates.
+func (c *Config) Environment(name string) (Environment, error) {
+	if len(c.Environments) == 0 {
+		return Environment{}, nil
+	}
+	environment, ok := c.Environments[name]
+	if !ok {
+		return Environment{}, fmt.Errorf("unknown environment %q (declared in %s: %v)", name, ConfigFile, c.names())
+	}
+	return environment, nil
+}
+
+func (c *Config) names() []string {
+	names := make([]string, 0, len(c.Environments))
+	for name := range c.Environments {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// State maps each unit to the deployment in each environment
+type State struct {
+	Units map[string]map[string]Deployment `yaml:"units"`
+}
+
+// Deployment is the version of a unit in an environment
+type Deployment struct {
+	Version      string `yaml:"version"`
+	Tag          string `yaml:"tag"`
+	Commit       string `yaml:"commit"`
+	From         string `yaml:"from,omitempty"` // environment it was promoted from; empty when deployed from a release
+	PromotedAt   string `yaml:"promoted_at"`    // RFC 3339
+	PromotedBy   string `yaml:"promoted_by,omitempty"`
+	DeploymentID int64  `yaml:"deployment_id,omitempty"` // GitHub deployment, for environments with github
+}
+
+// LoadState reads .r2r/environments.state.yml, returning an empty state when it does not exist
+func LoadState(workspaceRoot string) (*State, error) {
+	state := &State{}
+
+	data, err := os.ReadFile(filepath.Join(workspaceRoot, StateFile))
+	if os.IsNotExist(err) {
+		state.Units = make(map[string]map[string]Deployment)
+		return state, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read environment state: %w", err)
+	}
+
+	if err := yaml.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("failed to parse environment state %s: %w", StateFile, err)
+	}
+	if state.Units == nil {
+		state.Units = make(map[string]map[string]Deployment)
+	}
+	return state, nil
+}
+
+// Get returns the deployment of unit in environment
+func (s *State) Get(unit string, environment string) (Deployment, bool) {
+	deployment, ok := s.Units[unit][environment]
+	return deployment, ok
+}
+
+// Set records the deployment of unit in environment
+func (s *State) Set(unit string, environment string, deployment Deployment) {
+	if s.Units[unit] == nil {
+		s.Units[unit] = make(map[string]Deployment)
+	}
+	s.Units[unit][environment] = deployment
+}
+
+// stateHeader starts the state file
+const stateHeader = "# Versions of the deployable units per environment, written by promote.\n# Commit this file with the promotion.\n"
+
+// Save writes the state to .r2r/environments.state.yml
+func (s *State) Save(workspaceRoot string) error {
+	data, err := yaml.Marshal(s)
+	if err != nil {
+		return fmt.Errorf("failed to encode environment state: %w", err)
+	}
+
+	path := filepath.Join(workspaceRoot, StateFile)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(StateFile), err)
+	}
+	if err := os.WriteFile(path, append([]byte(stateHeader), data...), 0644); err != nil {
+		return fmt.Errorf("failed to write environment state: %w", err)
+	}
+	return nil
+}
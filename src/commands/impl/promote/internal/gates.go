@@ -0,0 +1,105 @@
+package promotion
+
+import (
+	"fmt"
+	"strings"
+)
+
+// GateResult is the outcome of one gate of a promotion
+type GateResult struct {
+	Gate    string `json:"gate"`
+	Passed  bool   `json:"passed"`
+	Message string `json:"message"`
+}
+
+// CheckGates checks the gates of promoting commit from an environment (""
+// for a release) to target. GitHub is only queried for the check and
+// approval gates.
+func CheckGates(target Environment, from string, commit string, github GitHubClient) []GateResult {
+	var results []GateResult
+
+	if len(target.From) > 0 {
+		result := GateResult{Gate: "from"}
+		for _, allowed := range target.From {
+			if allowed == from {
+				result.Passed = true
+			}
+		}
+		if result.Passed {
+			result.Message = "promoted from " + from
+		} else if from == "" {
+			result.Message = fmt.Sprintf("a release cannot be deployed here directly, promote it from %s", strings.Join(target.From, " or "))
+		} else {
+			result.Message = fmt.Sprintf("promotions come from %s, not %s", strings.Join(target.From, " or "), from)
+		}
+		results = append(results, result)
+	}
+
+	if len(target.RequiredChecks) > 0 {
+		results = append(results, checkRequiredChecks(target.RequiredChecks, commit, github))
+	}
+
+	if target.Approvals > 0 {
+		result := GateResult{Gate: "approvals"}
+		approvals, err := github.Approvals(commit)
+		switch {
+		case err != nil:
+			result.Message = fmt.Sprintf("failed to read the reviews: %v", err)
+		case approvals < target.Approvals:
+			result.Message = fmt.Sprintf("%d of %d required approval(s) on the pull request of %s", approvals, target.Approvals, shortCommit(commit))
+		default:
+			result.Passed = true
+			result.Message = fmt.Sprintf("%d approval(s)", approvals)
+		}
+		results = append(results, result)
+	}
+
+	return results
+}
+
+// checkRequiredChecks passes when every required check succeeded on commit
+func checkRequiredChecks(required []string, commit string, github GitHubClient) GateResult {
+	result := GateResult{Gate: "checks"}
+	conclusions, err := github.CheckRuns(commit)
+	if err != nil {
+		result.Message = fmt.Sprintf("failed to read the checks of %s: %v", shortCommit(commit), err)
+		return result
+	}
+
+	var problems []string
+	for _, name := range required {
+		conclusion, ok := conclusions[name]
+		switch {
+		case !ok:
+			problems = append(problems, name+" did not run")
+		case conclusion == "":
+			problems = append(problems, name+" is still running")
+		case conclusion != "success":
+			problems = append(problems, name+" is "+conclusion)
+		}
+	}
+	if len(problems) > 0 {
+		result.Message = strings.Join(problems, ", ")
+		return result
+	}
+	result.Passed = true
+	result.Message = fmt.Sprintf("%s succeeded on %s", strings.Join(required, ", "), shortCommit(commit))
+	return result
+}
+
+// GatesPassed reports whether every gate passed
+func GatesPassed(results []GateResult) bool {
+	for _, result := range results {
+		if !result.Passed {
+			return false
+		}
+	}
+	return true
+}
+
+func shortCommit(commit string) string {
+	if len(commit) > 12 {
+		return commit[:12]
+	}
+	return commit
+}
@@ -0,0 +1,164 @@
+package promotion
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// GitHubClient defines the GitHub operations of a promotion
+type GitHubClient interface {
+	// CheckRuns returns the conclusion of each check run and commit status
+	// of a commit by name; the conclusion is empty while it is running
+	CheckRuns(commit string) (map[string]string, error)
+	// Approvals counts the reviewers whose latest review approved the pull
+	// request that merged the commit
+	Approvals(commit string) (int, error)
+	// CreateDeployment creates a deployment of ref to an environment and returns its ID
+	CreateDeployment(ref string, environment string, description string, payload map[string]string) (int64, error)
+	// SetDeploymentStatus sets the state of a deployment
+	SetDeploymentStatus(deploymentID int64, environment string, state string) error
+}
+
+// GitHubCLIImpl implements GitHubClient using the gh CLI tool
+type GitHubCLIImpl struct {
+	repoPath string
+}
+
+// NewGitHubCLI creates a new GitHub CLI wrapper for the repository at repoPath
+func NewGitHubCLI(repoPath string) GitHubClient {
+	return &GitHubCLIImpl{
+		repoPath: repoPath,
+	}
+}
+
+// CheckRuns returns the conclusions of the check runs and commit statuses of commit
+func (g *GitHubCLIImpl) CheckRuns(commit string) (map[string]string, error) {
+	conclusions := make(map[string]string)
+
+	runs, err := g.api(nil, "repos/{owner}/{repo}/commits/"+commit+"/check-runs", "--paginate",
+		"--jq", `.check_runs[] | [.name, (.conclusion // "")] | @tsv`)
+	if err != nil {
+		return nil, err
+	}
+	for _, line := range lines(runs) {
+		name, conclusion, _ := strings.Cut(line, "\t")
+		conclusions[name] = conclusion
+	}
+
+	statuses, err := g.api(nil, "repos/{owner}/{repo}/commits/"+commit+"/status",
+		"--jq", `.statuses[] | [.context, .state] | @tsv`)
+	if err != nil {
+		return nil, err
+	}
+	for _, line := range lines(statuses) {
+		name, state, _ := strings.Cut(line, "\t")
+		if state == "pending" {
+			state = ""
+		}
+		conclusions[name] = state
+	}
+
+	return conclusions, nil
+}
+
+// Approvals counts the approving reviewers of the first pull request associated with commit
+func (g *GitHubCLIImpl) Approvals(commit string) (int, error) {
+	number, err := g.api(nil, "repos/{owner}/{repo}/commits/"+commit+"/pulls", "--jq", ".[0].number // empty")
+	if err != nil {
+		return 0, err
+	}
+	if number == "" {
+		return 0, nil
+	}
+
+	reviews, err := g.api(nil, "repos/{owner}/{repo}/pulls/"+number+"/reviews", "--paginate",
+		"--jq", `.[] | [.user.login, .state] | @tsv`)
+	if err != nil {
+		return 0, err
+	}
+
+	// Reviews come oldest first; comments do not change a reviewer's verdict
+	latest := make(map[string]string)
+	for _, line := range lines(reviews) {
+		reviewer, state, _ := strings.Cut(line, "\t")
+		if state != "COMMENTED" {
+			latest[reviewer] = state
+		}
+	}
+	approvals := 0
+	for _, state := range latest {
+		if state == "APPROVED" {
+			approvals++
+		}
+	}
+	return approvals, nil
+}
+
+// CreateDeployment creates a deployment of ref to environment. The gates are
+// checked by promote, so GitHub is asked not to check contexts or merge.
+func (g *GitHubCLIImpl) CreateDeployment(ref string, environment string, description string, payload map[string]string) (int64, error) {
+	body := map[string]interface{}{
+		"ref":               ref,
+		"environment":       environment,
+		"description":       description,
+		"auto_merge":        false,
+		"required_contexts": []string{},
+		"payload":           payload,
+	}
+	output, err := g.api(body, "--method", "POST", "repos/{owner}/{repo}/deployments", "--jq", ".id")
+	if err != nil {
+		return 0, fmt.Errorf("failed to create deployment: %w", err)
+	}
+	id, err := strconv.ParseInt(output, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("unexpected deployment ID %q", output)
+	}
+	return id, nil
+}
+
+// SetDeploymentStatus sets the state of a deployment
+func (g *GitHubCLIImpl) SetDeploymentStatus(deploymentID int64, environment string, state string) error {
+	body := map[string]interface{}{
+		"state":       state,
+		"environment": environment,
+	}
+	if _, err := g.api(body, "--method", "POST", fmt.Sprintf("repos/{owner}/{repo}/deployments/%d/statuses", deploymentID)); err != nil {
+		return fmt.Errorf("failed to set status of deployment %d: %w", deploymentID, err)
+	}
+	return nil
+}
+
+// api runs gh api with args, sending body as the JSON request body when set
+func (g *GitHubCLIImpl) api(body map[string]interface{}, args ...string) (string, error) {
+	args = append([]string{"api"}, args...)
+	cmd := exec.Command("gh", args...)
+	cmd.Dir = g.repoPath
+
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return "", err
+		}
+		cmd.Args = append(cmd.Args, "--input", "-")
+		cmd.Stdin = bytes.NewReader(data)
+	}
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("gh %s: %w: %s", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+func lines(output string) []string {
+	if output == "" {
+		return nil
+	}
+	return strings.Split(output, "\n")
+}
@@ -0,0 +1,155 @@
+package promotion
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type fakeGitHub struct {
+	conclusions map[string]string
+	approvals   int
+}
+
+func (f *fakeGitHub) CheckRuns(commit string) (map[string]string, error) {
+	return f.conclusions, nil
+}
+
+func (f *fakeGitHub) Approvals(commit string) (int, error) {
+	return f.approvals, nil
+}
+
+func (f *fakeGitHub) CreateDeployment(ref string, environment string, description string, payload map[string]string) (int64, error) {
+	return 1, nil
+}
+
+func (f *fakeGitHub) SetDeploymentStatus(deploymentID int64, environment string, state string) error {
+	return nil
+}
+
+func TestCheckGates(t *testing.T) {
+	prod := Environment{From: []string{"dev"}, RequiredChecks: []string{"build", "test"}, Approvals: 2}
+
+	tests := []struct {
+		name   string
+		from   string
+		github *fakeGitHub
+		failed []string
+	}{
+		{
+			name:   "all gates pass",
+			from:   "dev",
+			github: &fakeGitHub{conclusions: map[string]string{"build": "success", "test": "success", "lint": "failure"}, approvals: 2},
+		},
+		{
+			name:   "wrong source and running check",
+			from:   "",
+			github: &fakeGitHub{conclusions: map[string]string{"build": "success", "test": ""}, approvals: 2},
+			failed: []string{"from", "checks"},
+		},
+		{
+			name:   "missing check and approval",
+			from:   "dev",
+			github: &fakeGitHub{conclusions: map[string]string{"build": "success"}, approvals: 1},
+			failed: []string{"checks", "approvals"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results := CheckGates(prod, tt.from, "0123456789abcdef", tt.github)
+			if len(results) != 3 {
+				t.Fatalf("Expected 3 gate results, got %+v", results)
+			}
+			var failed []string
+			for _, result := range results {
+				if !result.Passed {
+					failed = append(failed, result.Gate)
+				}
+			}
+			if !reflect.DeepEqual(failed, tt.failed) {
+				t.Errorf("failed gates = %v, want %v (%+v)", failed, tt.failed, results)
+			}
+			if GatesPassed(results) != (len(tt.failed) == 0) {
+				t.Errorf("GatesPassed() = %v", GatesPassed(results))
+			}
+		})
+	}
+}
+
+func TestCheckGates_NoGitHubWithoutRemoteGates(t *testing.T) {
+	// A nil client would panic if it were queried
+	results := CheckGates(Environment{From: []string{"dev"}}, "dev", "abc", nil)
+	if !GatesPassed(results) {
+		t.Errorf("Expected the from gate to pass, got %+v", results)
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	root := t.TempDir()
+
+	config, err := LoadConfig(root)
+	if err != nil {
+		t.Fatalf("LoadConfig() without a file error = %v", err)
+	}
+	if _, err := config.Environment("anything"); err != nil {
+		t.Errorf("Expected any environment without a config, got %v", err)
+	}
+
+	writeFile(t, root, ConfigFile, "environments:\n  dev: {}\n  prod:\n    from: [dev]\n    approvals: 1\n    github: true\n")
+	config, err = LoadConfig(root)
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	prod, err := config.Environment("prod")
+	if err != nil || !prod.GitHub || prod.Approvals != 1 || !prod.HasRemoteGates() {
+		t.Errorf("Environment(prod) = %+v, %v", prod, err)
+	}
+	if _, err := config.Environment("staging"); err == nil || !strings.Contains(err.Error(), "[dev prod]") {
+		t.Errorf("Expected an unknown environment error listing dev and prod, got %v", err)
+	}
+
+	writeFile(t, root, ConfigFile, "environments:\n  prod:\n    from: [dev]\n")
+	if _, err := LoadConfig(root); err == nil || !strings.Contains(err.Error(), `unknown environment "dev"`) {
+		t.Errorf("Expected an unknown from environment error, got %v", err)
+	}
+}
+
+func TestState_RoundTrip(t *testing.T) {
+	root := t.TempDir()
+
+	state, err := LoadState(root)
+	if err != nil {
+		t.Fatalf("LoadState() without a file error = %v", err)
+	}
+	if _, ok := state.Get("src-cli", "dev"); ok {
+		t.Error("Expected no deployment in an empty state")
+	}
+
+	deployment := Deployment{Version: "1.2.0", Tag: "src-cli/v1.2.0", Commit: "abc", From: "dev", PromotedAt: "2026-01-02T03:04:05Z", DeploymentID: 42}
+	state.Set("src-cli", "prod", deployment)
+	if err := state.Save(root); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	loaded, err := LoadState(root)
+	if err != nil {
+		t.Fatalf("LoadState() error = %v", err)
+	}
+	if got, ok := loaded.Get("src-cli", "prod"); !ok || got != deployment {
+		t.Errorf("Get() = %+v, %v, want %+v", got, ok, deployment)
+	}
+}
+
+func writeFile(t *testing.T, root string, name string, content string) {
+	t.Helper()
+	path := filepath.Join(root, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,195 @@
+// Command: promote
+// Description: Promote a deployable unit's version to an environment after checking the environment's gates, recording it in .r2r/environments.state.yml
+// Usage: go run . promote <unit> --to <env> (--from <env> | --version <x.y.z>)
+// Flags:
+//   --to <env>: Environment to promote to
+//   --from <env>: Environment whose version of the unit is promoted
+//   --version <x.y.z>: Released version to deploy instead, e.g. to the first environment
+// HasSideEffects: true
+// DryRun: true
+package promote
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	promotion "github.com/ready-to-release/eac/src/commands/impl/promote/internal"
+	"github.com/ready-to-release/eac/src/commands/internal/registry"
+	"github.com/ready-to-release/eac/src/core/contracts/modules"
+	"github.com/ready-to-release/eac/src/core/repository"
+)
+
+func init() {
+	registry.Register(Promote)
+}
+
+// Promote deploys the version of a unit running in --from, or the released
+// --version, to --to. The version must have a release tag, and the gates of
+// --to (source environments, required checks, approvals) must pass on the
+// tagged commit. The promotion is recorded in the state file and, for
+// environments with github, as a GitHub deployment with a success status.
+func Promote() int {
+	fs := flag.NewFlagSet("promote", flag.ContinueOnError)
+	to := fs.String("to", "", "Environment to promote to")
+	from := fs.String("from", "", "Environment whose version of the unit is promoted")
+	version := fs.String("version", "", "Released version to deploy instead")
+
+	// Skip binary path and "promote"; the unit may come before or after the flags
+	args := []string{}
+	if len(os.Args) > 2 {
+		args = os.Args[2:]
+	}
+	unit := ""
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		unit, args = args[0], args[1:]
+	}
+	if err := fs.Parse(args); err != nil {
+		return 1
+	}
+	if unit == "" && fs.NArg() == 1 {
+		unit = fs.Arg(0)
+	} else if fs.NArg() > 0 {
+		fmt.Fprintf(os.Stderr, "Error: unexpected arguments: %s\n", strings.Join(fs.Args(), " "))
+		return 1
+	}
+
+	if unit == "" || *to == "" {
+		fmt.Fprintf(os.Stderr, "Error: a unit and --to are required\n")
+		fmt.Fprintf(os.Stderr, "Usage: promote <unit> --to <env> (--from <env> | --version <x.y.z>)\n")
+		return 1
+	}
+	if (*from == "") == (*version == "") {
+		fmt.Fprintf(os.Stderr, "Error: specify exactly one of --from and --version\n")
+		return 1
+	}
+	if *from == *to {
+		fmt.Fprintf(os.Stderr, "Error: --from and --to are both %s\n", *to)
+		return 1
+	}
+
+	workspaceRoot, err := repository.GetRepositoryRoot("")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to find repository root: %v\n", err)
+		return 1
+	}
+
+	moduleRegistry, err := modules.LoadFromWorkspace(workspaceRoot, "0.1.0")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading contracts: %v\n", err)
+		return 1
+	}
+	if !moduleRegistry.Has(unit) {
+		fmt.Fprintf(os.Stderr, "Error: unknown module: %s\n", unit)
+		return 1
+	}
+
+	config, err := promotion.LoadConfig(workspaceRoot)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	target, err := config.Environment(*to)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	if *from != "" {
+		if _, err := config.Environment(*from); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+	}
+
+	state, err := promotion.LoadState(workspaceRoot)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	if *from != "" {
+		source, ok := state.Get(unit, *from)
+		if !ok {
+			fmt.Fprintf(os.Stderr, "Error: %s has no version in %s\n", unit, *from)
+			return 1
+		}
+		*version = source.Version
+	}
+	*version = strings.TrimPrefix(*version, "v")
+
+	tag := fmt.Sprintf("%s/v%s", unit, *version)
+	commit, err := repository.GitOutput(workspaceRoot, "rev-list", "-n", "1", "refs/tags/"+tag)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: release tag %s not found: %v\n", tag, err)
+		return 1
+	}
+
+	if current, ok := state.Get(unit, *to); ok && current.Version == *version {
+		fmt.Printf("%s %s is already in %s\n", unit, *version, *to)
+		return 0
+	}
+
+	fmt.Printf("🚚 Promoting %s %s (%s) to %s\n", unit, *version, tag, *to)
+
+	var github promotion.GitHubClient
+	if target.HasRemoteGates() || target.GitHub {
+		github = promotion.NewGitHubCLI(workspaceRoot)
+	}
+
+	results := promotion.CheckGates(target, *from, commit, github)
+	for _, result := range results {
+		mark := "✅"
+		if !result.Passed {
+			mark = "❌"
+		}
+		fmt.Printf("  %s %s: %s\n", mark, result.Gate, result.Message)
+	}
+	if !promotion.GatesPassed(results) {
+		fmt.Fprintf(os.Stderr, "❌ Gates of %s failed; %s %s was not promoted\n", *to, unit, *version)
+		return 1
+	}
+
+	if registry.DryRun() {
+		if target.GitHub {
+			fmt.Printf("🔍 Would create a GitHub deployment of %s to %s\n", tag, *to)
+		}
+		fmt.Printf("🔍 Would record %s %s in %s in %s\n", unit, *version, *to, promotion.StateFile)
+		return 0
+	}
+
+	deployment := promotion.Deployment{
+		Version:    *version,
+		Tag:        tag,
+		Commit:     commit,
+		From:       *from,
+		PromotedAt: time.Now().UTC().Format(time.RFC3339),
+	}
+	deployment.PromotedBy, _ = repository.GitOutput(workspaceRoot, "config", "user.email")
+
+	if target.GitHub {
+		payload := map[string]string{"unit": unit, "version": *version, "from": *from}
+		description := fmt.Sprintf("Promote %s %s to %s", unit, *version, *to)
+		id, err := github.CreateDeployment(tag, *to, description, payload)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			return 1
+		}
+		if err := github.SetDeploymentStatus(id, *to, "success"); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			return 1
+		}
+		deployment.DeploymentID = id
+		fmt.Printf("🚀 Created GitHub deployment %d to %s\n", id, *to)
+	}
+
+	state.Set(unit, *to, deployment)
+	if err := state.Save(workspaceRoot); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	fmt.Printf("✅ Promoted %s %s to %s (recorded in %s)\n", unit, *version, *to, promotion.StateFile)
+	return 0
+}
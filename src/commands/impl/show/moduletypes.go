@@ -9,6 +9,7 @@ import (
 
 	"github.com/ready-to-release/eac/src/commands/internal/registry"
 	"github.com/ready-to-release/eac/src/commands/internal/render"
+	"github.com/ready-to-release/eac/src/core/collections"
 	"github.com/ready-to-release/eac/src/core/contracts/reports"
 	"github.com/ready-to-release/eac/src/core/repository"
 )
@@ -39,17 +40,7 @@ func ShowModuleTypes() int {
 	}
 
 	// Sort types alphabetically
-	var types []string
-	for t := range typeCount {
-		types = append(types, t)
-	}
-	for i := 0; i < len(types); i++ {
-		for j := i + 1; j < len(types); j++ {
-			if types[i] > types[j] {
-				types[i], types[j] = types[j], types[i]
-			}
-		}
-	}
+	types := collections.SortedKeys(typeCount)
 
 	// Build markdown table
 	tb := render.NewTableBuilder().
@@ -5,6 +5,7 @@
 //   --source <git-repo-url>: Git repository URL (default: https://github.com/ready-to-release/eac)
 //   --destination <path>: Destination path (default: .r2r/templates/specs)
 // HasSideEffects: true
+// DryRun: true
 package install
 
 import (
@@ -60,6 +61,11 @@ func TemplatesInstallSpecs() int {
 		return 1
 	}
 
+	if registry.DryRun() {
+		fmt.Printf("🔍 Would install %s from %s to %s\n", config.SourcePath, config.Source, config.Destination)
+		return 0
+	}
+
 	// Clone repository
 	fmt.Printf("Cloning templates from %s...\n", config.Source)
 	cloner := templates.NewGitCloner(config.Source)
@@ -3,13 +3,17 @@ package main
 
 import (
 	_ "github.com/ready-to-release/eac/src/commands/impl/build"
+	_ "github.com/ready-to-release/eac/src/commands/impl/changelog"
 	_ "github.com/ready-to-release/eac/src/commands/impl/commit"
+	_ "github.com/ready-to-release/eac/src/commands/impl/contracts"
 	_ "github.com/ready-to-release/eac/src/commands/impl/describe"
 	_ "github.com/ready-to-release/eac/src/commands/impl/design"
 	_ "github.com/ready-to-release/eac/src/commands/impl/docs"
 	_ "github.com/ready-to-release/eac/src/commands/impl/get"
 	_ "github.com/ready-to-release/eac/src/commands/impl/list"
+	_ "github.com/ready-to-release/eac/src/commands/impl/module"
 	_ "github.com/ready-to-release/eac/src/commands/impl/pipeline"
+	_ "github.com/ready-to-release/eac/src/commands/impl/promote"
 	_ "github.com/ready-to-release/eac/src/commands/impl/show"
 	_ "github.com/ready-to-release/eac/src/commands/impl/templates"
 	_ "github.com/ready-to-release/eac/src/commands/impl/templates/apply"
@@ -0,0 +1,318 @@
+package markdownlint
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+var (
+	headingPattern   = regexp.MustCompile(`^#{1,6}\s`)
+	listItemPattern  = regexp.MustCompile(`^\s*([-*+]|\d+[.)])\s`)
+	tableSepPattern  = regexp.MustCompile(`^\s*\|?\s*:?-+:?\s*(\|\s*:?-+:?\s*)*\|?\s*$`)
+	tableCellPattern = regexp.MustCompile(`^\s*\|`)
+)
+
+func isBlank(line string) bool {
+	return strings.TrimSpace(line) == ""
+}
+
+func isFence(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	return strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~")
+}
+
+func isHeading(line string) bool {
+	return headingPattern.MatchString(line)
+}
+
+func isTableRow(line string) bool {
+	return tableCellPattern.MatchString(line)
+}
+
+// codeMask marks lines inside fenced code blocks, including the fences
+func codeMask(lines []string) []bool {
+	mask := make([]bool, len(lines))
+	inCode := false
+	for i, line := range lines {
+		if isFence(line) {
+			mask[i] = true
+			inCode = !inCode
+			continue
+		}
+		mask[i] = inCode
+	}
+	return mask
+}
+
+// fixTrailingSpaces removes trailing whitespace outside code blocks (MD009)
+func fixTrailingSpaces(lines []string, _ Options) []string {
+	mask := codeMask(lines)
+	for i, line := range lines {
+		if !mask[i] || isFence(line) {
+			lines[i] = strings.TrimRight(line, " \t")
+		}
+	}
+	return lines
+}
+
+// fixFirstLineHeading removes leading blank lines before a top-level heading (MD041)
+func fixFirstLineHeading(lines []string, _ Options) []string {
+	for i, line := range lines {
+		if isBlank(line) {
+			continue
+		}
+		if strings.HasPrefix(line, "# ") {
+			return lines[i:]
+		}
+		return lines
+	}
+	return lines
+}
+
+// fixSingleH1 demotes every top-level heading after the first to level two (MD025)
+func fixSingleH1(lines []string, _ Options) []string {
+	mask := codeMask(lines)
+	seen := false
+	for i, line := range lines {
+		if mask[i] || !strings.HasPrefix(line, "# ") {
+			continue
+		}
+		if seen {
+			lines[i] = "#" + line
+		}
+		seen = true
+	}
+	return lines
+}
+
+// fixHeadingBlankLines surrounds headings with blank lines (MD022)
+func fixHeadingBlankLines(lines []string, _ Options) []string {
+	mask := codeMask(lines)
+	result := make([]string, 0, len(lines))
+	for i, line := range lines {
+		if mask[i] || !isHeading(line) {
+			result = append(result, line)
+			continue
+		}
+		if len(result) > 0 && !isBlank(result[len(result)-1]) {
+			result = append(result, "")
+		}
+		result = append(result, line)
+		if i+1 < len(lines) && !isBlank(lines[i+1]) {
+			result = append(result, "")
+		}
+	}
+	return result
+}
+
+// fixFenceBlankLines surrounds fenced code blocks with blank lines (MD031)
+func fixFenceBlankLines(lines []string, _ Options) []string {
+	result := make([]string, 0, len(lines))
+	inCode := false
+	for i, line := range lines {
+		if !isFence(line) {
+			result = append(result, line)
+			continue
+		}
+		if !inCode {
+			if len(result) > 0 && !isBlank(result[len(result)-1]) {
+				result = append(result, "")
+			}
+			result = append(result, line)
+		} else {
+			result = append(result, line)
+			if i+1 < len(lines) && !isBlank(lines[i+1]) {
+				result = append(result, "")
+			}
+		}
+		inCode = !inCode
+	}
+	return result
+}
+
+// fixParagraphWrap reflows plain paragraphs at the line length (MD013).
+// Headings, lists, tables, block quotes, code and lines without spaces are left alone.
+func fixParagraphWrap(lines []string, opts Options) []string {
+	if opts.LineLength <= 0 {
+		return lines
+	}
+
+	mask := codeMask(lines)
+	result := make([]string, 0, len(lines))
+	var paragraph []string
+
+	flush := func() {
+		if len(paragraph) == 0 {
+			return
+		}
+		result = append(result, wrapWords(strings.Join(paragraph, " "), opts.LineLength)...)
+		paragraph = nil
+	}
+
+	for i, line := range lines {
+		if mask[i] || isBlank(line) || !isParagraphLine(line) {
+			flush()
+			result = append(result, line)
+			continue
+		}
+		paragraph = append(paragraph, strings.TrimSpace(line))
+	}
+	flush()
+
+	return result
+}
+
+func isParagraphLine(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	return !isHeading(line) &&
+		!listItemPattern.MatchString(line) &&
+		!isTableRow(line) &&
+		!strings.HasPrefix(trimmed, ">") &&
+		trimmed != "---" && trimmed != "***" && trimmed != "___" &&
+		!strings.HasPrefix(line, "    ") && !strings.HasPrefix(line, "\t")
+}
+
+// wrapWords greedily wraps text at width; words longer than width get their own line
+func wrapWords(text string, width int) []string {
+	var wrapped []string
+	current := ""
+	for _, word := range strings.Fields(text) {
+		if current == "" {
+			current = word
+			continue
+		}
+		if utf8.RuneCountInString(current)+1+utf8.RuneCountInString(word) <= width {
+			current += " " + word
+			continue
+		}
+		wrapped = append(wrapped, current)
+		current = word
+	}
+	if current != "" {
+		wrapped = append(wrapped, current)
+	}
+	return wrapped
+}
+
+// fixTableAlignment pads table cells so columns line up (MD055 style)
+func fixTableAlignment(lines []string, _ Options) []string {
+	mask := codeMask(lines)
+	for start := 0; start < len(lines); start++ {
+		if mask[start] || !isTableRow(lines[start]) {
+			continue
+		}
+		end := start
+		for end < len(lines) && !mask[end] && isTableRow(lines[end]) {
+			end++
+		}
+		if end-start >= 2 && tableSepPattern.MatchString(lines[start+1]) {
+			copy(lines[start:end], alignTable(lines[start:end]))
+		}
+		start = end
+	}
+	return lines
+}
+
+func alignTable(rows []string) []string {
+	cells := make([][]string, len(rows))
+	columns := 0
+	for i, row := range rows {
+		cells[i] = splitTableRow(row)
+		if len(cells[i]) > columns {
+			columns = len(cells[i])
+		}
+	}
+
+	widths := make([]int, columns)
+	for i, row := range cells {
+		if i == 1 {
+			continue // separator row
+		}
+		for c, cell := range row {
+			if w := utf8.RuneCountInString(cell); w > widths[c] {
+				widths[c] = w
+			}
+		}
+	}
+	for c := range widths {
+		if widths[c] < 3 {
+			widths[c] = 3
+		}
+	}
+
+	aligned := make([]string, len(rows))
+	for i, row := range cells {
+		parts := make([]string, columns)
+		for c := 0; c < columns; c++ {
+			cell := ""
+			if c < len(row) {
+				cell = row[c]
+			}
+			if i == 1 {
+				parts[c] = separatorCell(cell, widths[c])
+				continue
+			}
+			parts[c] = cell + strings.Repeat(" ", widths[c]-utf8.RuneCountInString(cell))
+		}
+		aligned[i] = "| " + strings.Join(parts, " | ") + " |"
+	}
+	return aligned
+}
+
+func splitTableRow(row string) []string {
+	trimmed := strings.TrimSpace(row)
+	trimmed = strings.TrimPrefix(trimmed, "|")
+	trimmed = strings.TrimSuffix(trimmed, "|")
+	parts := strings.Split(trimmed, "|")
+	for i, part := range parts {
+		parts[i] = strings.TrimSpace(part)
+	}
+	return parts
+}
+
+// separatorCell keeps the column alignment colons of a separator cell
+func separatorCell(cell string, width int) string {
+	left := strings.HasPrefix(cell, ":")
+	right := strings.HasSuffix(cell, ":")
+	dashes := width
+	if left {
+		dashes--
+	}
+	if right {
+		dashes--
+	}
+	result := strings.Repeat("-", dashes)
+	if left {
+		result = ":" + result
+	}
+	if right {
+		result += ":"
+	}
+	return result
+}
+
+// fixMultipleBlankLines collapses consecutive blank lines outside code blocks (MD012)
+func fixMultipleBlankLines(lines []string, _ Options) []string {
+	mask := codeMask(lines)
+	result := make([]string, 0, len(lines))
+	for i, line := range lines {
+		if !mask[i] && isBlank(line) && len(result) > 0 && isBlank(result[len(result)-1]) {
+			continue
+		}
+		result = append(result, line)
+	}
+	return result
+}
+
+// fixTrailingNewline ends the content with exactly one newline (MD047)
+func fixTrailingNewline(lines []string, _ Options) []string {
+	end := len(lines)
+	for end > 0 && isBlank(lines[end-1]) {
+		end--
+	}
+	if end == 0 {
+		return []string{""}
+	}
+	return append(lines[:end], "")
+}
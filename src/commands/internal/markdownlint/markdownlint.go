@@ -0,0 +1,110 @@
+// Package markdownlint provides markdownlint-style fixers for markdown text.
+//
+// Each rule is a Fixer identified by its markdownlint rule ID. Fix applies all
+// fixers in order; Lint reports which rules would change the text.
+//
+// Example:
+//
+//	fixed := markdownlint.Fix(content, markdownlint.DefaultOptions())
+//	findings := markdownlint.Lint(content, markdownlint.DefaultOptions())
+package markdownlint
+
+import (
+	"strings"
+)
+
+// Options configures the fixers
+type Options struct {
+	// LineLength is the column paragraphs are wrapped at (MD013). 0 disables wrapping.
+	LineLength int
+	// Disabled lists rule IDs to skip (e.g. "MD013")
+	Disabled []string
+}
+
+// DefaultOptions returns options for commit-message style markdown (72 columns)
+func DefaultOptions() Options {
+	return Options{LineLength: 72}
+}
+
+func (o Options) enabled(id string) bool {
+	for _, disabled := range o.Disabled {
+		if strings.EqualFold(disabled, id) {
+			return false
+		}
+	}
+	return true
+}
+
+// Fixer rewrites lines to satisfy a single rule
+type Fixer struct {
+	ID          string
+	Description string
+	Fix         func(lines []string, opts Options) []string
+}
+
+// Finding is a rule violation reported by Lint
+type Finding struct {
+	Rule        string `json:"rule"`
+	Description string `json:"description"`
+	Line        int    `json:"line"` // first line that differs (1-based)
+}
+
+// Fixers returns all fixers in the order they are applied
+func Fixers() []Fixer {
+	return []Fixer{
+		{ID: "MD009", Description: "No trailing spaces", Fix: fixTrailingSpaces},
+		{ID: "MD041", Description: "First line should be a top-level heading", Fix: fixFirstLineHeading},
+		{ID: "MD025", Description: "Single top-level heading", Fix: fixSingleH1},
+		{ID: "MD022", Description: "Headings should be surrounded by blank lines", Fix: fixHeadingBlankLines},
+		{ID: "MD031", Description: "Fenced code blocks should be surrounded by blank lines", Fix: fixFenceBlankLines},
+		{ID: "MD013", Description: "Wrap paragraphs at the line length", Fix: fixParagraphWrap},
+		{ID: "MD055", Description: "Align table columns", Fix: fixTableAlignment},
+		{ID: "MD012", Description: "No multiple consecutive blank lines", Fix: fixMultipleBlankLines},
+		{ID: "MD047", Description: "Files should end with a single newline", Fix: fixTrailingNewline},
+	}
+}
+
+// Fix applies all enabled fixers and returns the fixed content
+func Fix(content string, opts Options) string {
+	lines := splitLines(content)
+	for _, fixer := range Fixers() {
+		if opts.enabled(fixer.ID) {
+			lines = fixer.Fix(lines, opts)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Lint reports each enabled rule whose fixer would change the content
+func Lint(content string, opts Options) []Finding {
+	original := splitLines(content)
+
+	var findings []Finding
+	for _, fixer := range Fixers() {
+		if !opts.enabled(fixer.ID) {
+			continue
+		}
+		fixed := fixer.Fix(append([]string{}, original...), opts)
+		if line := firstDifference(original, fixed); line > 0 {
+			findings = append(findings, Finding{
+				Rule:        fixer.ID,
+				Description: fixer.Description,
+				Line:        line,
+			})
+		}
+	}
+	return findings
+}
+
+func splitLines(content string) []string {
+	return strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+}
+
+func firstDifference(a, b []string) int {
+	for i := 0; i < len(a) || i < len(b); i++ {
+		if i >= len(a) || i >= len(b) || a[i] != b[i] {
+			return i + 1
+		}
+	}
+	return 0
+}
@@ -0,0 +1,132 @@
+package markdownlint
+
+import (
+	"strings"
+	"testing"
+)
+
+func fix(t *testing.T, fixer func([]string, Options) []string, input string, opts Options) string {
+	t.Helper()
+	return strings.Join(fixer(strings.Split(input, "\n"), opts), "\n")
+}
+
+func TestFixTrailingSpaces(t *testing.T) {
+	input := "# Title  \n\n```\ncode  \n```"
+	want := "# Title\n\n```\ncode  \n```"
+	if got := fix(t, fixTrailingSpaces, input, DefaultOptions()); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestFixFirstLineHeading(t *testing.T) {
+	if got := fix(t, fixFirstLineHeading, "\n\n# Title\nbody", DefaultOptions()); got != "# Title\nbody" {
+		t.Errorf("got %q", got)
+	}
+	if got := fix(t, fixFirstLineHeading, "\ntext", DefaultOptions()); got != "\ntext" {
+		t.Errorf("non-heading content should be unchanged, got %q", got)
+	}
+}
+
+func TestFixSingleH1(t *testing.T) {
+	input := "# One\n\n# Two\n\n```\n# comment\n```"
+	want := "# One\n\n## Two\n\n```\n# comment\n```"
+	if got := fix(t, fixSingleH1, input, DefaultOptions()); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestFixHeadingBlankLines(t *testing.T) {
+	input := "# Title\ntext\n## Section\nmore"
+	want := "# Title\n\ntext\n\n## Section\n\nmore"
+	if got := fix(t, fixHeadingBlankLines, input, DefaultOptions()); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestFixFenceBlankLines(t *testing.T) {
+	input := "text\n```go\nx := 1\n```\nafter"
+	want := "text\n\n```go\nx := 1\n```\n\nafter"
+	if got := fix(t, fixFenceBlankLines, input, DefaultOptions()); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestFixParagraphWrap(t *testing.T) {
+	input := "This is a long paragraph that goes well past the configured line length of\nthirty columns.\n\n- list item that is long but must not be wrapped at all\n\n```\na very long code line that must never be wrapped by the fixer\n```"
+	got := fix(t, fixParagraphWrap, input, Options{LineLength: 30})
+
+	for _, line := range strings.Split(strings.Split(got, "\n\n")[0], "\n") {
+		if len(line) > 30 {
+			t.Errorf("paragraph line exceeds 30 columns: %q", line)
+		}
+	}
+	if !strings.Contains(got, "- list item that is long but must not be wrapped at all") {
+		t.Error("list item should not be wrapped")
+	}
+	if !strings.Contains(got, "a very long code line that must never be wrapped by the fixer") {
+		t.Error("code should not be wrapped")
+	}
+}
+
+func TestFixTableAlignment(t *testing.T) {
+	input := "| File | Modules |\n|---|:-:|\n| src/main.go | src-cli |"
+	want := "| File        | Modules |\n| ----------- | :-----: |\n| src/main.go | src-cli |"
+	if got := fix(t, fixTableAlignment, input, DefaultOptions()); got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestFixMultipleBlankLines(t *testing.T) {
+	input := "a\n\n\n\nb\n```\n\n\n```"
+	want := "a\n\nb\n```\n\n\n```"
+	if got := fix(t, fixMultipleBlankLines, input, DefaultOptions()); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestFixTrailingNewline(t *testing.T) {
+	if got := fix(t, fixTrailingNewline, "text\n\n\n", DefaultOptions()); got != "text\n" {
+		t.Errorf("got %q", got)
+	}
+	if got := fix(t, fixTrailingNewline, "text", DefaultOptions()); got != "text\n" {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestFix_IsIdempotent(t *testing.T) {
+	input := "\n# Title\nSome body text that is quite long and should be wrapped at seventy two columns by the fixer.\n# Second\n| a | b |\n|-|-|\n| long cell | x |\n```\ncode\n```\ntext   \n\n\n"
+	once := Fix(input, DefaultOptions())
+	twice := Fix(once, DefaultOptions())
+	if once != twice {
+		t.Errorf("Fix is not idempotent:\n%q\n%q", once, twice)
+	}
+	if findings := Lint(once, DefaultOptions()); len(findings) != 0 {
+		t.Errorf("expected no findings after Fix, got %+v", findings)
+	}
+}
+
+func TestLint_ReportsRules(t *testing.T) {
+	findings := Lint("# Title\ntext  ", DefaultOptions())
+
+	rules := make(map[string]int)
+	for _, finding := range findings {
+		rules[finding.Rule] = finding.Line
+	}
+	if rules["MD009"] != 2 {
+		t.Errorf("expected MD009 on line 2, got %+v", findings)
+	}
+	if _, ok := rules["MD022"]; !ok {
+		t.Errorf("expected MD022 finding, got %+v", findings)
+	}
+	if _, ok := rules["MD047"]; !ok {
+		t.Errorf("expected MD047 finding, got %+v", findings)
+	}
+}
+
+func TestOptions_Disabled(t *testing.T) {
+	opts := DefaultOptions()
+	opts.Disabled = []string{"MD047"}
+	if got := Fix("text", opts); got != "text" {
+		t.Errorf("MD047 should be disabled, got %q", got)
+	}
+}
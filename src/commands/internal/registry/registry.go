@@ -6,6 +6,8 @@ import (
 	"os"
 	"runtime"
 	"strings"
+
+	"github.com/ready-to-release/eac/src/core/reporoot"
 )
 
 // InitialWorkingDir stores the working directory when the program started
@@ -32,32 +34,28 @@ func GetWorkspaceRoot() (string, error) {
 		return WorkspaceRoot, nil
 	}
 
-	// Import repository package at runtime to avoid circular dependency
-	// We'll use a simple implementation here instead
 	return findRepositoryRoot()
 }
 
-// findRepositoryRoot finds the git repository root by walking up directories
+// findRepositoryRoot finds the repository root of the working directory and
+// caches it in WorkspaceRoot
 func findRepositoryRoot() (string, error) {
-	startPath, err := os.Getwd()
+	root, err := reporoot.Find("")
 	if err != nil {
 		return "", err
 	}
+	WorkspaceRoot = root
+	return root, nil
+}
 
-	currentPath := startPath
-	for {
-		gitPath := currentPath + string(os.PathSeparator) + ".git"
-		if _, err := os.Stat(gitPath); err == nil {
-			WorkspaceRoot = currentPath
-			return currentPath, nil
-		}
+// DryRunEnv is set to "1" by the dispatcher when a command runs with
+// --dry-run, so the command (and commands it starts) only print what they
+// would do
+const DryRunEnv = "R2R_DRY_RUN"
 
-		parentPath := currentPath[:strings.LastIndex(currentPath, string(os.PathSeparator))]
-		if parentPath == currentPath || parentPath == "" {
-			return "", os.ErrNotExist
-		}
-		currentPath = parentPath
-	}
+// DryRun reports whether the current command runs with --dry-run
+func DryRun() bool {
+	return os.Getenv(DryRunEnv) == "1"
 }
 
 // CommandFunc is the signature for all command functions
@@ -71,6 +69,7 @@ type CommandRegistration struct {
 	Description    string // Command description from file header
 	Usage          string // Command usage from file header
 	HasSideEffects bool   // Whether command modifies repository files
+	SupportsDryRun bool   // Whether command honors --dry-run (DryRun) itself
 }
 
 // commands maps command names to their implementation functions
@@ -115,6 +114,17 @@ func Register(fn CommandFunc) {
 			"' in " + file + "\nMust be 'true' or 'false'")
 	}
 
+	// DryRun is optional and defaults to false
+	var supportsDryRun bool
+	switch metadata.DryRunStr {
+	case "", "false":
+	case "true":
+		supportsDryRun = true
+	default:
+		panic("registry.Register: invalid DryRun value '" + metadata.DryRunStr +
+			"' in " + file + "\nMust be 'true' or 'false'")
+	}
+
 	// Store in original commands map for backward compatibility
 	commands[metadata.CommandName] = fn
 
@@ -129,6 +139,7 @@ func Register(fn CommandFunc) {
 		Description:    metadata.Description,
 		Usage:          metadata.Usage,
 		HasSideEffects: hasSideEffects,
+		SupportsDryRun: supportsDryRun,
 	}
 }
 
@@ -138,6 +149,7 @@ type commandMetadata struct {
 	Description       string
 	Usage             string
 	HasSideEffectsStr string // Parsed from "// HasSideEffects:" comment
+	DryRunStr         string // Parsed from "// DryRun:" comment
 }
 
 // extractCommandMetadata parses a Go source file to extract command metadata from header comments
@@ -178,6 +190,11 @@ func extractCommandMetadata(filePath string) commandMetadata {
 		if strings.HasPrefix(line, "// HasSideEffects:") {
 			metadata.HasSideEffectsStr = strings.TrimSpace(strings.TrimPrefix(line, "// HasSideEffects:"))
 		}
+
+		// Extract DryRun
+		if strings.HasPrefix(line, "// DryRun:") {
+			metadata.DryRunStr = strings.TrimSpace(strings.TrimPrefix(line, "// DryRun:"))
+		}
 	}
 
 	return metadata
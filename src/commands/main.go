@@ -14,6 +14,7 @@ import (
 	"strings"
 
 	"github.com/ready-to-release/eac/src/commands/internal/registry"
+	"github.com/ready-to-release/eac/src/core/collections"
 )
 
 // InitialWorkingDir stores the working directory when the program started
@@ -56,12 +57,26 @@ func main() {
 		}
 	}
 
+	os.Exit(dispatch(os.Args))
+}
+
+// dispatch runs the command named by args, with os.Args set to args without
+// the global flags, and returns its exit code
+func dispatch(args []string) int {
+	// --dry-run is a global flag: commands read it through registry.DryRun
+	var dryRun bool
+	os.Args, dryRun = extractDryRun(args)
+	if dryRun {
+		os.Setenv(registry.DryRunEnv, "1")
+	}
+
 	if len(os.Args) < 2 {
 		printUsage()
-		os.Exit(1)
+		return 1
 	}
 
 	var cmdFunc registry.CommandFunc
+	var cmdName string
 	var exists bool
 
 	// Try longest match first for nested commands
@@ -70,6 +85,7 @@ func main() {
 		testPath := strings.Join(os.Args[1:argCount+1], " ")
 		if fn, found := commands[testPath]; found {
 			cmdFunc = fn
+			cmdName = testPath
 			exists = true
 			break
 		}
@@ -82,12 +98,22 @@ func main() {
 
 		if len(subcommands) > 0 {
 			printSubcommandHelp(prefix, subcommands)
-			os.Exit(0)
+			return 0
 		}
 
 		fmt.Fprintf(os.Stderr, "Error: Command not found: %s\n\n", prefix)
 		printUsage()
-		os.Exit(1)
+		return 1
+	}
+
+	// Fail closed: only commands declaring DryRun: true run on a dry run,
+	// whatever their HasSideEffects declaration says
+	if dryRun {
+		reg := registry.GetCommandByCanonical(registry.GetCanonicalName(cmdName))
+		if reg == nil || !reg.SupportsDryRun {
+			explainDryRun(cmdName, reg, os.Args[1+len(strings.Fields(cmdName)):])
+			return 0
+		}
 	}
 
 	exitCode := cmdFunc()
@@ -110,7 +136,47 @@ func main() {
 		fmt.Fprintf(os.Stderr, "=== End Stack Trace ===\n")
 	}
 
-	os.Exit(exitCode)
+	return exitCode
+}
+
+// extractDryRun removes --dry-run from args. Arguments after "--" are left
+// alone, as they belong to the command.
+func extractDryRun(args []string) ([]string, bool) {
+	dryRun := false
+	kept := make([]string, 0, len(args))
+	for i, arg := range args {
+		if arg == "--" {
+			kept = append(kept, args[i:]...)
+			break
+		}
+		if arg == "--dry-run" {
+			dryRun = true
+			continue
+		}
+		kept = append(kept, arg)
+	}
+	return kept, dryRun
+}
+
+// explainDryRun describes a command that does not declare dry-run support,
+// instead of running it
+func explainDryRun(cmdName string, reg *registry.CommandRegistration, args []string) {
+	fmt.Printf("🔍 Dry run: %s\n", strings.TrimSpace(cmdName+" "+strings.Join(args, " ")))
+	if reg == nil {
+		fmt.Printf("⚠️  '%s' does not declare dry-run support; nothing was executed\n", cmdName)
+		return
+	}
+	if reg.Description != "" {
+		fmt.Printf("   %s\n", reg.Description)
+	}
+	if reg.Usage != "" {
+		fmt.Printf("   Usage: %s\n", reg.Usage)
+	}
+	if reg.HasSideEffects {
+		fmt.Printf("⚠️  '%s' modifies the repository and cannot preview its changes; nothing was executed\n", cmdName)
+	} else {
+		fmt.Printf("⚠️  '%s' does not declare dry-run support; nothing was executed (run it without --dry-run)\n", cmdName)
+	}
 }
 
 // getSubcommands returns all commands that start with the given prefix
@@ -128,32 +194,13 @@ func getSubcommands(prefix string) []string {
 			remainder := strings.TrimPrefix(cmdName, searchPrefix)
 			parts := strings.Fields(remainder)
 			if len(parts) > 0 {
-				subcommand := parts[0]
-				// Only add unique subcommands
-				found := false
-				for _, existing := range subcommands {
-					if existing == subcommand {
-						found = true
-						break
-					}
-				}
-				if !found {
-					subcommands = append(subcommands, subcommand)
-				}
-			}
-		}
-	}
-
-	// Sort for consistent output
-	for i := 0; i < len(subcommands); i++ {
-		for j := i + 1; j < len(subcommands); j++ {
-			if subcommands[i] > subcommands[j] {
-				subcommands[i], subcommands[j] = subcommands[j], subcommands[i]
+				subcommands = append(subcommands, parts[0])
 			}
 		}
 	}
 
-	return subcommands
+	// Unique and sorted for consistent output
+	return collections.SortedUnique(subcommands)
 }
 
 // printSubcommandHelp prints help for a parent command
@@ -178,22 +225,7 @@ func printUsage() {
 	fmt.Println("")
 	fmt.Println("Available commands:")
 
-	var names []string
-	commands := registry.GetCommands()
-	for name := range commands {
-		names = append(names, name)
-	}
-
-	// Sort
-	for i := 0; i < len(names); i++ {
-		for j := i + 1; j < len(names); j++ {
-			if names[i] > names[j] {
-				names[i], names[j] = names[j], names[i]
-			}
-		}
-	}
-
-	for _, name := range names {
+	for _, name := range collections.SortedKeys(registry.GetCommands()) {
 		fmt.Printf("  %s\n", name)
 	}
 }
@@ -3,6 +3,9 @@
 package main
 
 import (
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/ready-to-release/eac/src/commands/internal/registry"
@@ -79,3 +82,88 @@ func TestGetSubcommandsSorted(t *testing.T) {
 		}
 	}
 }
+
+func TestExtractDryRun(t *testing.T) {
+	tests := []struct {
+		name   string
+		args   []string
+		want   []string
+		dryRun bool
+	}{
+		{"no flag", []string{"r2r", "module", "new", "x"}, []string{"r2r", "module", "new", "x"}, false},
+		{"flag anywhere", []string{"r2r", "--dry-run", "module", "new", "x"}, []string{"r2r", "module", "new", "x"}, true},
+		{"flag after args", []string{"r2r", "init", "--dry-run"}, []string{"r2r", "init"}, true},
+		{"after terminator", []string{"r2r", "pipeline", "run", "--", "--dry-run"}, []string{"r2r", "pipeline", "run", "--", "--dry-run"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, dryRun := extractDryRun(tt.args)
+			if dryRun != tt.dryRun {
+				t.Errorf("dryRun = %v, want %v", dryRun, tt.dryRun)
+			}
+			if strings.Join(got, " ") != strings.Join(tt.want, " ") {
+				t.Errorf("args = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// dispatchDryRun runs args through the dispatcher with --dry-run and restores
+// os.Args and R2R_DRY_RUN afterwards
+func dispatchDryRun(t *testing.T, args ...string) int {
+	t.Helper()
+	t.Setenv(registry.DryRunEnv, "")
+	saved := os.Args
+	defer func() { os.Args = saved }()
+	return dispatch(append([]string{"r2r", "--dry-run"}, args...))
+}
+
+func TestDryRunRefusesUndeclaredCommands(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out.txt")
+
+	// The fixture declares no side effects but writes a file
+	if code := dispatchDryRun(t, "test-fixture", "write-file", path); code != 0 {
+		t.Fatalf("dry run exit code = %d, want 0", code)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("dry run of a command without DryRun: true wrote %s", path)
+	}
+
+	saved := os.Args
+	defer func() { os.Args = saved }()
+	if code := dispatch([]string{"r2r", "test-fixture", "write-file", path}); code != 0 {
+		t.Fatalf("exit code = %d, want 0", code)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("expected the fixture to write %s without --dry-run: %v", path, err)
+	}
+}
+
+func TestDryRunDocsNavWrite(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv("R2R_REPO_ROOT", root)
+	config := "site_name: Test\nnav:\n  - Old: old.md\n"
+	files := map[string]string{
+		"mkdocs.yml":    config,
+		"docs/index.md": "# Home\n",
+		"docs/guide.md": "# Guide\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Out of date: the diff is shown and the exit code is 1, as without --write
+	if code := dispatchDryRun(t, "docs", "nav", "--write"); code != 1 {
+		t.Errorf("exit code = %d, want 1", code)
+	}
+	if content, _ := os.ReadFile(filepath.Join(root, "mkdocs.yml")); string(content) != config {
+		t.Errorf("dry run rewrote mkdocs.yml:\n%s", content)
+	}
+}
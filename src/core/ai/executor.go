@@ -36,7 +36,9 @@ type Executor struct {
 	lastUsedProvider  Provider
 	providerFactories map[string]ProviderFactory
 	fallbackFactory   ProviderFactory // Factory for fallback provider
+	providerFactory   ProviderFactory // Replaces the configured provider when set
 	logger            Logger
+	recorder          *Recorder // Records or replays provider calls when set
 }
 
 // ProviderFactory creates a provider from configuration
@@ -71,11 +73,22 @@ func (e *Executor) SetFallbackProvider(factory ProviderFactory) {
 	e.fallbackFactory = factory
 }
 
+// SetProvider makes every call use the provider of factory instead of the
+// configured one, e.g. the client's model when running under an MCP server
+func (e *Executor) SetProvider(factory ProviderFactory) {
+	e.providerFactory = factory
+}
+
 // SetLogger sets the execution logger
 func (e *Executor) SetLogger(logger Logger) {
 	e.logger = logger
 }
 
+// SetRecorder records or replays all provider calls through recorder
+func (e *Executor) SetRecorder(recorder *Recorder) {
+	e.recorder = recorder
+}
+
 // Execute runs an AI prompt through the configured provider
 func (e *Executor) Execute(ctx context.Context, input string, opts ...Option) (string, error) {
 	// Load configuration
@@ -88,6 +101,9 @@ func (e *Executor) Execute(ctx context.Context, input string, opts ...Option) (s
 
 	// Load provider (with fallback to claude-cli)
 	provider, didFallback := e.LoadProvider(config)
+	if e.recorder != nil {
+		provider = e.recorder.Wrap(provider)
+	}
 	e.lastUsedProvider = provider
 
 	// Log warning if we fell back due to config error
@@ -95,17 +111,38 @@ func (e *Executor) Execute(ctx context.Context, input string, opts ...Option) (s
 		fmt.Fprintf(os.Stderr, "Warning: Failed to load config (%v), using claude-cli fallback\n", configErr)
 	}
 
+	// Collect usage for the log, into the caller's Usage if one was passed
+	options := ApplyOptions(opts...)
+	usage := options.Usage
+	if usage == nil {
+		usage = &Usage{}
+		opts = append(opts, WithUsage(usage))
+	}
+
 	// Execute with provider
 	response, err := provider.Execute(ctx, input, opts...)
 
+	// A replayed response cost nothing
+	replayed := e.recorder != nil && e.recorder.Mode() == RecordingReplay
+	model := options.Model
+	if model == "" && config != nil && !didFallback {
+		model = config.Model
+	}
+	if err == nil && !replayed {
+		completeUsage(usage, model, input, response)
+	}
+
 	// Log execution
 	logEntry := &LogEntry{
-		Provider:  provider.Name(),
-		Input:     input,
-		Response:  response,
-		Success:   err == nil,
-		Error:     err,
+		Provider:    provider.Name(),
+		Model:       model,
+		Stage:       options.Stage,
+		Input:       input,
+		Response:    response,
+		Success:     err == nil,
+		Error:       err,
 		DidFallback: didFallback,
+		Usage:       *usage,
 	}
 	e.logger.LogExecution(ctx, logEntry)
 
@@ -132,6 +169,15 @@ func (e *Executor) loadConfig() (*Config, error) {
 // LoadProvider loads the configured provider or falls back
 // Exported for testing
 func (e *Executor) LoadProvider(config *Config) (Provider, bool) {
+	// A provider set with SetProvider replaces the configured one
+	if e.providerFactory != nil {
+		provider, err := e.providerFactory(config)
+		if err == nil {
+			return provider, false
+		}
+		fmt.Fprintf(os.Stderr, "Warning: Failed to create provider (%v), using the configured provider\n", err)
+	}
+
 	// If no config, fall back
 	if config == nil {
 		return e.createFallback(), true
@@ -183,9 +229,12 @@ func (l *noOpLogger) LogExecution(ctx context.Context, entry *LogEntry) {
 // LogEntry represents a single AI execution log
 type LogEntry struct {
 	Provider    string
+	Model       string
+	Stage       string
 	Input       string
 	Response    string
 	Success     bool
 	Error       error
 	DidFallback bool
+	Usage       Usage
 }
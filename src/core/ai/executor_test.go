@@ -331,3 +331,36 @@ func TestExecutor_WithMockProvider(t *testing.T) {
 		t.Errorf("Execute() response = %v, want %v", response, mockResponse)
 	}
 }
+
+func TestExecutor_SetProvider(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	// A configured provider is replaced by the one set with SetProvider
+	configPath := filepath.Join(tmpDir, ".r2r", "agent-config.yml")
+	if err := os.MkdirAll(filepath.Dir(configPath), 0755); err != nil {
+		t.Fatalf("failed to create config dir: %v", err)
+	}
+	if err := os.WriteFile(configPath, []byte("provider:\n  name: claude-cli\n  model: sonnet"), 0644); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+
+	executor := ai.NewExecutor(tmpDir)
+	providers.RegisterBuiltIn(executor)
+	executor.SetProvider(func(config *ai.Config) (ai.Provider, error) {
+		return providers.NewMockProvider("sampled"), nil
+	})
+
+	response, err := executor.Execute(context.Background(), "test input")
+	if err != nil || response != "sampled" {
+		t.Errorf("Execute() = %q, %v; want the response of the set provider", response, err)
+	}
+
+	// A provider that cannot be created leaves the configured one in place
+	executor.SetProvider(func(config *ai.Config) (ai.Provider, error) {
+		return nil, os.ErrNotExist
+	})
+	provider, didFallback := executor.LoadProvider(&ai.Config{ProviderName: "claude-cli", Model: "sonnet"})
+	if provider.Name() != "claude-cli" || didFallback {
+		t.Errorf("LoadProvider() = %s (fallback %v), want the configured claude-cli", provider.Name(), didFallback)
+	}
+}
@@ -38,8 +38,7 @@ type FileLogger struct {
 
 // NewFileLogger creates a logger that writes to the specified path
 func NewFileLogger(workspaceRoot string) *FileLogger {
-	logPath := filepath.Join(workspaceRoot, ".r2r", "logs", "ai-executions.jsonl")
-	return &FileLogger{logPath: logPath}
+	return &FileLogger{logPath: ExecutionLogPath(workspaceRoot)}
 }
 
 // LogExecution appends an execution log entry to the JSONL file
@@ -59,6 +58,24 @@ func (l *FileLogger) LogExecution(ctx context.Context, entry *LogEntry) {
 		"didFallback": entry.DidFallback,
 	}
 
+	if entry.Model != "" {
+		logData["model"] = entry.Model
+	}
+	if entry.Stage != "" {
+		logData["stage"] = entry.Stage
+	}
+	if entry.Success {
+		logData["input_tokens"] = entry.Usage.InputTokens
+		if entry.Usage.CachedTokens > 0 {
+			logData["cached_tokens"] = entry.Usage.CachedTokens
+		}
+		logData["output_tokens"] = entry.Usage.OutputTokens
+		logData["cost_usd"] = entry.Usage.CostUSD
+		if entry.Usage.Estimated {
+			logData["usage_estimated"] = true
+		}
+	}
+
 	// Add input (truncated if too long)
 	if len(entry.Input) > 200 {
 		logData["input"] = entry.Input[:200] + "..."
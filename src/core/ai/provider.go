@@ -44,6 +44,8 @@ type ExecuteOptions struct {
 	Model       string  // Model to use (e.g., "haiku", "sonnet", "gpt-4")
 	Temperature float64 // Randomness (0.0 - 1.0), default 0.3
 	MaxTokens   int     // Max response length, default 4000
+	Usage       *Usage  // Filled with the token usage of the call, if the provider reports it
+	Stage       string  // Pipeline stage the call belongs to, for logging only
 }
 
 // WithModel sets the AI model to use for execution
@@ -109,5 +109,10 @@ func (p *ClaudeAPI) Execute(ctx context.Context, input string, opts ...ai.Option
 		return "", fmt.Errorf("claude returned non-text content")
 	}
 
+	if options.Usage != nil {
+		options.Usage.InputTokens = int(message.Usage.InputTokens)
+		options.Usage.OutputTokens = int(message.Usage.OutputTokens)
+	}
+
 	return result, nil
 }
@@ -2,11 +2,9 @@
 package providers
 
 import (
-	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
-	"os"
-	"os/exec"
 	"strings"
 
 	"github.com/ready-to-release/eac/src/core/ai"
@@ -35,8 +33,10 @@ import (
 //   - Errors wrapped with context
 //   - Both stdout and stderr captured for debugging
 //   - API key removal is tested separately
+//   - Processes are bounded and warmed by a shared pool (claude_cli_pool.go)
 type ClaudeCLI struct {
 	defaultModel string
+	pool         *claudeCLIPool
 }
 
 // NewClaudeCLI creates a Claude CLI provider
@@ -44,6 +44,7 @@ type ClaudeCLI struct {
 func NewClaudeCLI() *ClaudeCLI {
 	return &ClaudeCLI{
 		defaultModel: "sonnet", // Default to sonnet for quality
+		pool:         defaultClaudeCLIPool,
 	}
 }
 
@@ -69,6 +70,7 @@ func (p *ClaudeCLI) Execute(ctx context.Context, input string, opts ...ai.Option
 	// Build command arguments
 	args := []string{
 		"--print",
+		"--output-format", "json", // Result with token usage and cost
 	}
 
 	// Add model if specified
@@ -76,31 +78,58 @@ func (p *ClaudeCLI) Execute(ctx context.Context, input string, opts ...ai.Option
 		args = append(args, "--model", model)
 	}
 
-	// Create command
-	cmd := exec.CommandContext(ctx, "claude", args...)
-	cmd.Stdin = strings.NewReader(input)
-
-	// CRITICAL: Remove ANTHROPIC_API_KEY to force subscription auth
+	// Execute through the pool, which removes ANTHROPIC_API_KEY to force subscription auth
 	// See docs/reference/modules/src-commands/claude-constraints.md for rationale
-	cmd.Env = removeAPIKeyFromEnv(os.Environ())
+	stdout, stderr, err := p.pool.Run(ctx, args, input)
+	if err != nil {
+		return "", fmt.Errorf("claude CLI execution failed: %w\nStderr: %s\nStdout: %s",
+			err, stderr, stdout)
+	}
 
-	// Capture output
-	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
+	output, usage, err := parseClaudeCLIOutput(stdout)
+	if err != nil {
+		return "", err
+	}
+	if options.Usage != nil && usage != nil {
+		*options.Usage = *usage
+	}
+	return output, nil
+}
 
-	// Execute
-	if err := cmd.Run(); err != nil {
-		stderrText := stderr.String()
-		stdoutText := stdout.String()
+// claudeCLIResult is the result printed by claude --output-format json
+type claudeCLIResult struct {
+	Result       string  `json:"result"`
+	IsError      bool    `json:"is_error"`
+	TotalCostUSD float64 `json:"total_cost_usd"`
+	Usage        struct {
+		InputTokens              int `json:"input_tokens"`
+		CacheCreationInputTokens int `json:"cache_creation_input_tokens"`
+		CacheReadInputTokens     int `json:"cache_read_input_tokens"`
+		OutputTokens             int `json:"output_tokens"`
+	} `json:"usage"`
+}
 
-		return "", fmt.Errorf("claude CLI execution failed: %w\nStderr: %s\nStdout: %s",
-			err, stderrText, stdoutText)
+// parseClaudeCLIOutput extracts the response and usage from the JSON result
+// of the claude CLI. Output that is not a JSON result, e.g. from an older
+// CLI, is returned as the response without usage.
+func parseClaudeCLIOutput(stdout string) (string, *ai.Usage, error) {
+	trimmed := strings.TrimSpace(stdout)
+
+	var result claudeCLIResult
+	if !strings.HasPrefix(trimmed, "{") || json.Unmarshal([]byte(trimmed), &result) != nil {
+		return trimmed, nil, nil
+	}
+	if result.IsError {
+		return "", nil, fmt.Errorf("claude CLI returned an error: %s", result.Result)
 	}
 
-	// Return output (trimmed)
-	output := strings.TrimSpace(stdout.String())
-	return output, nil
+	usage := &ai.Usage{
+		InputTokens:  result.Usage.InputTokens + result.Usage.CacheCreationInputTokens + result.Usage.CacheReadInputTokens,
+		CachedTokens: result.Usage.CacheReadInputTokens,
+		OutputTokens: result.Usage.OutputTokens,
+		CostUSD:      result.TotalCostUSD,
+	}
+	return strings.TrimSpace(result.Result), usage, nil
 }
 
 // removeAPIKeyFromEnv removes ANTHROPIC_API_KEY from environment variables
@@ -0,0 +1,254 @@
+// File: src/core/ai/providers/claude_cli_pool.go
+package providers
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// claudeCLIPool runs claude CLI processes for all ClaudeCLI providers in the process
+//
+// Intent: Cut the wall-clock time of multi-agent pipelines, where every agent
+// call pays the full claude CLI startup.
+//
+// Design (Three Rules of Vibe Coding):
+//
+// Easy to understand:
+//   - A semaphore bounds the claude processes that run at the same time
+//   - A warm process is started, and waits on stdin, while the previous call
+//     with the same arguments runs, so the next call skips the startup
+//   - Warm processes count against the same limit: running and idle
+//     processes together never exceed it, so with a limit of 1 nothing is
+//     pre-warmed
+//   - Every process still serves exactly one prompt (session isolation)
+//
+// Easy to change:
+//   - Limits come from environment variables
+//   - Process creation is a single function field, replaced in tests
+//
+// Hard to break:
+//   - Warm processes that exited while idle are discarded, never reused
+//   - A warm process whose parent exits reads EOF and exits without a prompt
+//   - ShutdownClaudeCLIPool kills idle warm processes; calls that were
+//     running do not start new ones, later calls warm up again
+type claudeCLIPool struct {
+	mu         sync.Mutex
+	slots      chan struct{}
+	warm       map[string]*claudeProcess // idle warm process per argument list
+	warmUp     bool
+	generation int // incremented by shutdown
+	newCommand func(args []string) *exec.Cmd
+}
+
+const (
+	// ClaudeCLIMaxProcessesEnv bounds the concurrently running claude processes (default 4)
+	ClaudeCLIMaxProcessesEnv = "R2R_CLAUDE_CLI_MAX_PROCESSES"
+	// ClaudeCLIWarmEnv disables warm processes when set to "false" or "0"
+	ClaudeCLIWarmEnv = "R2R_CLAUDE_CLI_WARM"
+
+	defaultClaudeCLIMaxProcesses = 4
+)
+
+var defaultClaudeCLIPool = newClaudeCLIPool(claudeCLIMaxProcesses(), claudeCLIWarmUp(), func(args []string) *exec.Cmd {
+	return exec.Command("claude", args...)
+})
+
+func newClaudeCLIPool(maxProcesses int, warmUp bool, newCommand func(args []string) *exec.Cmd) *claudeCLIPool {
+	return &claudeCLIPool{
+		slots:      make(chan struct{}, maxProcesses),
+		warm:       make(map[string]*claudeProcess),
+		warmUp:     warmUp,
+		newCommand: newCommand,
+	}
+}
+
+func claudeCLIMaxProcesses() int {
+	if n, err := strconv.Atoi(os.Getenv(ClaudeCLIMaxProcessesEnv)); err == nil && n > 0 {
+		return n
+	}
+	return defaultClaudeCLIMaxProcesses
+}
+
+func claudeCLIWarmUp() bool {
+	switch strings.ToLower(os.Getenv(ClaudeCLIWarmEnv)) {
+	case "false", "0", "off":
+		return false
+	}
+	return true
+}
+
+// ShutdownClaudeCLIPool kills the idle warm claude processes. Call it when no
+// more agent calls follow, e.g. at the end of a command; the pool stays
+// usable for the next one.
+func ShutdownClaudeCLIPool() {
+	defaultClaudeCLIPool.shutdown()
+}
+
+// claudeProcess is a started claude CLI process waiting for its prompt on stdin
+type claudeProcess struct {
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	stdout bytes.Buffer
+	stderr bytes.Buffer
+	done   chan struct{} // closed when the process exited
+	err    error         // exit error, set before done is closed
+}
+
+func (p *claudeCLIPool) start(args []string) (*claudeProcess, error) {
+	cmd := p.newCommand(args)
+
+	// CRITICAL: Remove ANTHROPIC_API_KEY to force subscription auth
+	cmd.Env = removeAPIKeyFromEnv(os.Environ())
+
+	proc := &claudeProcess{cmd: cmd, done: make(chan struct{})}
+	cmd.Stdout = &proc.stdout
+	cmd.Stderr = &proc.stderr
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	proc.stdin = stdin
+
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	go func() {
+		proc.err = cmd.Wait()
+		close(proc.done)
+	}()
+	return proc, nil
+}
+
+func (c *claudeProcess) exited() bool {
+	select {
+	case <-c.done:
+		return true
+	default:
+		return false
+	}
+}
+
+func (c *claudeProcess) kill() {
+	if c.cmd.Process != nil {
+		c.cmd.Process.Kill()
+	}
+}
+
+// Run sends input to a claude process started with args and returns its
+// stdout and stderr. Waits for a free slot first.
+func (p *claudeCLIPool) Run(ctx context.Context, args []string, input string) (string, string, error) {
+	select {
+	case p.slots <- struct{}{}:
+	case <-ctx.Done():
+		return "", "", ctx.Err()
+	}
+	defer func() { <-p.slots }()
+	generation := p.currentGeneration()
+
+	key := strings.Join(args, "\x00")
+	proc := p.take(key)
+	if proc == nil {
+		p.makeRoom()
+		var err error
+		if proc, err = p.start(args); err != nil {
+			return "", "", err
+		}
+	}
+
+	// Start the replacement while this call runs
+	p.prewarm(key, args, generation)
+
+	go func() {
+		io.WriteString(proc.stdin, input)
+		proc.stdin.Close()
+	}()
+
+	select {
+	case <-proc.done:
+	case <-ctx.Done():
+		proc.kill()
+		<-proc.done
+		return "", "", ctx.Err()
+	}
+
+	return proc.stdout.String(), proc.stderr.String(), proc.err
+}
+
+// take returns the idle warm process for key, or nil
+func (p *claudeCLIPool) take(key string) *claudeProcess {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	proc := p.warm[key]
+	delete(p.warm, key)
+	if proc != nil && proc.exited() {
+		return nil
+	}
+	return proc
+}
+
+func (p *claudeCLIPool) currentGeneration() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.generation
+}
+
+// makeRoom kills idle warm processes of other arguments until the process of
+// the caller, which holds a slot, fits in the limit
+func (p *claudeCLIPool) makeRoom() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for key, proc := range p.warm {
+		if len(p.slots)+len(p.warm) <= cap(p.slots) {
+			return
+		}
+		proc.kill()
+		delete(p.warm, key)
+	}
+}
+
+// prewarm starts an idle process for key unless one is waiting already, the
+// limit is reached or the pool was shut down since the call of generation
+// started
+func (p *claudeCLIPool) prewarm(key string, args []string, generation int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !p.warmUp || generation != p.generation {
+		return
+	}
+	if proc, ok := p.warm[key]; ok && !proc.exited() {
+		return
+	}
+	delete(p.warm, key)
+	if len(p.slots)+len(p.warm) >= cap(p.slots) {
+		return
+	}
+
+	proc, err := p.start(args)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to start warm claude process: %v\n", err)
+		return
+	}
+	p.warm[key] = proc
+}
+
+func (p *claudeCLIPool) shutdown() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.generation++
+	for key, proc := range p.warm {
+		proc.kill()
+		delete(p.warm, key)
+	}
+}
@@ -0,0 +1,146 @@
+package providers
+
+import (
+	"context"
+	"os/exec"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// catPool runs "cat" instead of claude, echoing the prompt, and counts the started processes
+func catPool(maxProcesses int, warmUp bool) (*claudeCLIPool, *atomic.Int32) {
+	var started atomic.Int32
+	pool := newClaudeCLIPool(maxProcesses, warmUp, func(args []string) *exec.Cmd {
+		started.Add(1)
+		return exec.Command("cat")
+	})
+	return pool, &started
+}
+
+func TestClaudeCLIPool_ReusesWarmProcess(t *testing.T) {
+	if _, err := exec.LookPath("cat"); err != nil {
+		t.Skip("cat not available")
+	}
+	pool, started := catPool(2, true)
+	defer pool.shutdown()
+
+	args := []string{"--print", "--model", "haiku"}
+	for _, prompt := range []string{"first", "second"} {
+		stdout, _, err := pool.Run(context.Background(), args, prompt)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if stdout != prompt {
+			t.Errorf("stdout = %q, want %q", stdout, prompt)
+		}
+	}
+
+	// cold start, then one warm replacement per call
+	if got := started.Load(); got != 3 {
+		t.Errorf("expected 3 started processes, got %d", got)
+	}
+
+	pool.shutdown()
+	if len(pool.warm) != 0 {
+		t.Error("expected shutdown to kill the warm process")
+	}
+}
+
+func TestClaudeCLIPool_WithoutWarmUp(t *testing.T) {
+	if _, err := exec.LookPath("cat"); err != nil {
+		t.Skip("cat not available")
+	}
+	pool, started := catPool(1, false)
+
+	for i := 0; i < 2; i++ {
+		if _, _, err := pool.Run(context.Background(), nil, "prompt"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if got := started.Load(); got != 2 {
+		t.Errorf("expected one process per call, got %d", got)
+	}
+}
+
+func TestClaudeCLIPool_WaitsForSlotAndHonoursContext(t *testing.T) {
+	pool, _ := catPool(1, false)
+
+	// Occupy the only slot
+	pool.slots <- struct{}{}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, _, err := pool.Run(ctx, nil, "prompt"); err != context.DeadlineExceeded {
+		t.Errorf("expected to give up waiting for a slot, got %v", err)
+	}
+	<-pool.slots
+
+	if _, err := exec.LookPath("sleep"); err != nil {
+		t.Skip("sleep not available")
+	}
+	pool.newCommand = func(args []string) *exec.Cmd { return exec.Command("sleep", "10") }
+
+	ctx, cancel = context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if _, _, err := pool.Run(ctx, nil, "prompt"); err != context.DeadlineExceeded {
+		t.Errorf("expected the running process to be killed, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("cancellation took %s", elapsed)
+	}
+}
+
+func TestClaudeCLIPool_WarmsUpAgainAfterShutdown(t *testing.T) {
+	if _, err := exec.LookPath("cat"); err != nil {
+		t.Skip("cat not available")
+	}
+	pool, started := catPool(2, true)
+	defer pool.shutdown()
+
+	for run := 0; run < 2; run++ {
+		if _, _, err := pool.Run(context.Background(), nil, "prompt"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(pool.warm) != 1 {
+			t.Errorf("run %d left %d warm processes, want 1", run, len(pool.warm))
+		}
+		// The end of a command, as in commit ai
+		pool.shutdown()
+	}
+	if got := started.Load(); got != 4 {
+		t.Errorf("expected a cold start and a warm process per run, got %d processes", got)
+	}
+}
+
+func TestClaudeCLIPool_WarmProcessesCountAgainstLimit(t *testing.T) {
+	if _, err := exec.LookPath("cat"); err != nil {
+		t.Skip("cat not available")
+	}
+
+	// With one slot the running call uses the whole limit
+	pool, started := catPool(1, true)
+	for i := 0; i < 2; i++ {
+		if _, _, err := pool.Run(context.Background(), nil, "prompt"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if got := started.Load(); got != 2 || len(pool.warm) != 0 {
+		t.Errorf("started %d processes with %d warm, want one per call and none warm", got, len(pool.warm))
+	}
+
+	pool, _ = catPool(2, true)
+	defer pool.shutdown()
+	if _, _, err := pool.Run(context.Background(), []string{"a"}, "prompt"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Another call is running: the idle process of "a" makes room for "b"
+	pool.slots <- struct{}{}
+	if _, _, err := pool.Run(context.Background(), []string{"b"}, "prompt"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	<-pool.slots
+	if len(pool.warm) != 0 {
+		t.Errorf("expected the warm process to be killed for the limit, %d left", len(pool.warm))
+	}
+}
@@ -90,3 +90,28 @@ func TestClaudeCLI_WithOptions(t *testing.T) {
 		t.Error("Execute() returned empty output")
 	}
 }
+
+func TestParseClaudeCLIOutput(t *testing.T) {
+	stdout := `{"type":"result","subtype":"success","is_error":false,"result":"  hello\n","total_cost_usd":0.0123,` +
+		`"usage":{"input_tokens":10,"cache_creation_input_tokens":200,"cache_read_input_tokens":30,"output_tokens":5}}`
+
+	output, usage, err := parseClaudeCLIOutput(stdout)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if output != "hello" {
+		t.Errorf("output = %q, want %q", output, "hello")
+	}
+	if usage == nil || usage.InputTokens != 240 || usage.CachedTokens != 30 || usage.OutputTokens != 5 || usage.CostUSD != 0.0123 {
+		t.Errorf("unexpected usage: %+v", usage)
+	}
+
+	output, usage, err = parseClaudeCLIOutput("plain text answer\n")
+	if err != nil || output != "plain text answer" || usage != nil {
+		t.Errorf("expected plain output without usage, got %q %+v %v", output, usage, err)
+	}
+
+	if _, _, err := parseClaudeCLIOutput(`{"is_error":true,"result":"Not logged in"}`); err == nil {
+		t.Error("expected an error for an error result")
+	}
+}
@@ -104,6 +104,10 @@ func (p *Gemini) Execute(ctx context.Context, input string, opts ...ai.Option) (
 	// Extract text from first part
 	part := resp.Candidates[0].Content.Parts[0]
 	if textPart, ok := part.(genai.Text); ok {
+		if options.Usage != nil && resp.UsageMetadata != nil {
+			options.Usage.InputTokens = int(resp.UsageMetadata.PromptTokenCount)
+			options.Usage.OutputTokens = int(resp.UsageMetadata.CandidatesTokenCount)
+		}
 		return string(textPart), nil
 	}
 
@@ -98,5 +98,10 @@ func (p *OpenAI) Execute(ctx context.Context, input string, opts ...ai.Option) (
 		return "", fmt.Errorf("openai returned no choices")
 	}
 
+	if options.Usage != nil {
+		options.Usage.InputTokens = resp.Usage.PromptTokens
+		options.Usage.OutputTokens = resp.Usage.CompletionTokens
+	}
+
 	return resp.Choices[0].Message.Content, nil
 }
@@ -0,0 +1,240 @@
+// File: src/core/ai/recording.go
+// Intent: Record AI responses to .r2r/recordings and replay them, so pipelines
+// built on the executor can be tested and demonstrated without the model
+//
+// Design (Three Rules of Vibe Coding):
+//
+// Easy to understand:
+//   - One JSON file per call, named by the hash of the request
+//   - Record mode calls the provider and saves the response
+//   - Replay mode never calls the provider; a missing recording is an error
+//
+// Easy to change:
+//   - Recorder wraps any Provider, so providers need no changes
+//   - Mode and location come from environment variables, no code changes
+//   - Redaction patterns are a single list
+//
+// Hard to break:
+//   - The key hashes the unredacted prompt and all options, so a changed
+//     prompt never replays a stale response
+//   - Files are written atomically
+//   - Redaction only touches the stored prompt, never the replayed response
+
+package ai
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// RecordingMode selects whether AI calls are recorded or replayed
+type RecordingMode string
+
+const (
+	RecordingOff    RecordingMode = ""
+	RecordingRecord RecordingMode = "record"
+	RecordingReplay RecordingMode = "replay"
+)
+
+// Environment variables configuring the recorder
+const (
+	RecordingModeEnv   = "R2R_AI_RECORDING"        // "record" or "replay"
+	RecordingDirEnv    = "R2R_AI_RECORDINGS_DIR"   // defaults to .r2r/recordings
+	RecordingRedactEnv = "R2R_AI_RECORDING_REDACT" // "true" redacts secrets from stored prompts
+)
+
+// ErrRecordingNotFound is returned in replay mode for a request that was never recorded
+var ErrRecordingNotFound = errors.New("no recording for AI request")
+
+// Recording is a stored AI response
+type Recording struct {
+	Key        string    `json:"key"`
+	Provider   string    `json:"provider"`
+	Model      string    `json:"model,omitempty"`
+	Prompt     string    `json:"prompt"`
+	Response   string    `json:"response"`
+	RecordedAt time.Time `json:"recorded_at"`
+}
+
+// Recorder stores and replays AI responses
+type Recorder struct {
+	dir    string
+	mode   RecordingMode
+	redact bool
+}
+
+// NewRecorder creates a recorder storing recordings in dir
+func NewRecorder(dir string, mode RecordingMode, redact bool) *Recorder {
+	return &Recorder{dir: dir, mode: mode, redact: redact}
+}
+
+// RecorderFromEnv creates a recorder from R2R_AI_RECORDING and related
+// variables. It returns nil when recording is off.
+func RecorderFromEnv(workspaceRoot string) (*Recorder, error) {
+	mode := RecordingMode(strings.ToLower(strings.TrimSpace(os.Getenv(RecordingModeEnv))))
+	switch mode {
+	case RecordingOff:
+		return nil, nil
+	case RecordingRecord, RecordingReplay:
+	default:
+		return nil, fmt.Errorf("invalid %s %q, expected record or replay", RecordingModeEnv, mode)
+	}
+
+	dir := os.Getenv(RecordingDirEnv)
+	if dir == "" {
+		dir = filepath.Join(workspaceRoot, ".r2r", "recordings")
+	}
+	return NewRecorder(dir, mode, os.Getenv(RecordingRedactEnv) == "true"), nil
+}
+
+// Mode returns the recording mode
+func (r *Recorder) Mode() RecordingMode {
+	return r.mode
+}
+
+// Wrap returns a provider that records or replays the calls to p
+func (r *Recorder) Wrap(p Provider) Provider {
+	return &recordingProvider{inner: p, recorder: r}
+}
+
+// RecordingKey returns the hash identifying a request
+func RecordingKey(input string, options *ExecuteOptions) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%g\x00%d\x00", options.Model, options.Temperature, options.MaxTokens)
+	h.Write([]byte(input))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Load reads the recording for key
+func (r *Recorder) Load(key string) (*Recording, error) {
+	data, err := os.ReadFile(r.path(key))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("%w: %s", ErrRecordingNotFound, key)
+		}
+		return nil, fmt.Errorf("failed to read recording %s: %w", key, err)
+	}
+	var rec Recording
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return nil, fmt.Errorf("failed to parse recording %s: %w", key, err)
+	}
+	return &rec, nil
+}
+
+// Save writes a recording, redacting the prompt if configured
+func (r *Recorder) Save(rec *Recording) error {
+	if r.redact {
+		copied := *rec
+		copied.Prompt = RedactSecrets(rec.Prompt)
+		rec = &copied
+	}
+
+	if err := os.MkdirAll(r.dir, 0755); err != nil {
+		return fmt.Errorf("failed to create recordings directory: %w", err)
+	}
+	data, err := json.MarshalIndent(rec, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode recording: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(r.dir, rec.Key+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write recording: %w", err)
+	}
+	_, writeErr := tmp.Write(append(data, '\n'))
+	closeErr := tmp.Close()
+	if writeErr != nil || closeErr != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write recording: %w", errors.Join(writeErr, closeErr))
+	}
+	if err := os.Rename(tmp.Name(), r.path(rec.Key)); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write recording: %w", err)
+	}
+	return nil
+}
+
+func (r *Recorder) path(key string) string {
+	return filepath.Join(r.dir, key+".json")
+}
+
+// recordingProvider records or replays the calls to inner
+type recordingProvider struct {
+	inner    Provider
+	recorder *Recorder
+}
+
+// Name returns "replay" in replay mode, otherwise the wrapped provider's name
+func (p *recordingProvider) Name() string {
+	if p.recorder.mode == RecordingReplay {
+		return "replay"
+	}
+	return p.inner.Name()
+}
+
+// Execute replays a recorded response, or calls the wrapped provider and
+// records its response
+func (p *recordingProvider) Execute(ctx context.Context, input string, opts ...Option) (string, error) {
+	options := ApplyOptions(opts...)
+	key := RecordingKey(input, options)
+
+	if p.recorder.mode == RecordingReplay {
+		rec, err := p.recorder.Load(key)
+		if err != nil {
+			if errors.Is(err, ErrRecordingNotFound) {
+				return "", fmt.Errorf("%w (record it by running with %s=record)", err, RecordingModeEnv)
+			}
+			return "", err
+		}
+		return rec.Response, nil
+	}
+
+	response, err := p.inner.Execute(ctx, input, opts...)
+	if err != nil || p.recorder.mode != RecordingRecord {
+		return response, err
+	}
+
+	rec := &Recording{
+		Key:        key,
+		Provider:   p.inner.Name(),
+		Model:      options.Model,
+		Prompt:     input,
+		Response:   response,
+		RecordedAt: time.Now().UTC(),
+	}
+	if err := p.recorder.Save(rec); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: Failed to save AI recording: %v\n", err)
+	}
+	return response, nil
+}
+
+// secretPatterns match credentials that must not end up in stored prompts
+var secretPatterns = []struct {
+	re          *regexp.Regexp
+	replacement string
+}{
+	{regexp.MustCompile(`sk-ant-[A-Za-z0-9_-]{10,}`), "[REDACTED]"},
+	{regexp.MustCompile(`sk-[A-Za-z0-9_-]{20,}`), "[REDACTED]"},
+	{regexp.MustCompile(`gh[pousr]_[A-Za-z0-9]{20,}`), "[REDACTED]"},
+	{regexp.MustCompile(`github_pat_[A-Za-z0-9_]{20,}`), "[REDACTED]"},
+	{regexp.MustCompile(`AKIA[0-9A-Z]{16}`), "[REDACTED]"},
+	{regexp.MustCompile(`AIza[0-9A-Za-z_-]{35}`), "[REDACTED]"},
+	{regexp.MustCompile(`(?i)((?:api[_-]?key|token|secret|password)["']?\s*[:=]\s*)["']?[^\s"',]+["']?`), "${1}[REDACTED]"},
+}
+
+// RedactSecrets replaces API keys, tokens and assigned secrets in text
+func RedactSecrets(text string) string {
+	for _, p := range secretPatterns {
+		text = p.re.ReplaceAllString(text, p.replacement)
+	}
+	return text
+}
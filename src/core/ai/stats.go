@@ -0,0 +1,125 @@
+// File: src/core/ai/stats.go
+// Intent: Aggregate the execution log into per-day usage statistics
+//
+// Design (Three Rules of Vibe Coding):
+//
+// Easy to understand:
+//   - Reads the JSONL log written by FileLogger
+//   - Groups executions by local date, optionally by pipeline stage
+//
+// Easy to change:
+//   - ExecutionRecord mirrors the log fields, new fields are ignored
+//
+// Hard to break:
+//   - Malformed lines are skipped, so one bad write does not hide history
+//   - A missing log is an empty history, not an error
+
+package ai
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// ExecutionLogPath returns the path of the execution log in a workspace
+func ExecutionLogPath(workspaceRoot string) string {
+	return filepath.Join(workspaceRoot, ".r2r", "logs", "ai-executions.jsonl")
+}
+
+// ExecutionRecord is one entry of the execution log
+type ExecutionRecord struct {
+	Timestamp      time.Time `json:"timestamp"`
+	Provider       string    `json:"provider"`
+	Model          string    `json:"model,omitempty"`
+	Stage          string    `json:"stage,omitempty"`
+	Success        bool      `json:"success"`
+	InputTokens    int       `json:"input_tokens"`
+	OutputTokens   int       `json:"output_tokens"`
+	CostUSD        float64   `json:"cost_usd"`
+	UsageEstimated bool      `json:"usage_estimated,omitempty"`
+}
+
+// ReadExecutionLog reads all entries of the execution log at path
+func ReadExecutionLog(path string) ([]ExecutionRecord, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to open execution log: %w", err)
+	}
+	defer f.Close()
+
+	var records []ExecutionRecord
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var record ExecutionRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			continue
+		}
+		records = append(records, record)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read execution log: %w", err)
+	}
+	return records, nil
+}
+
+// DailyUsage is the usage of one day, or of one stage on one day
+type DailyUsage struct {
+	Date     string `json:"date"` // local date, YYYY-MM-DD
+	Stage    string `json:"stage,omitempty"`
+	Failures int    `json:"failures"`
+	Usage
+}
+
+// AggregateDaily sums the records from since onwards per local date, and per
+// stage with byStage. Days are returned oldest first.
+func AggregateDaily(records []ExecutionRecord, since time.Time, byStage bool) []DailyUsage {
+	type key struct{ date, stage string }
+	totals := make(map[key]*DailyUsage)
+
+	for _, record := range records {
+		if record.Timestamp.Before(since) {
+			continue
+		}
+		k := key{date: record.Timestamp.Local().Format(time.DateOnly)}
+		if byStage {
+			k.stage = record.Stage
+		}
+		day, ok := totals[k]
+		if !ok {
+			day = &DailyUsage{Date: k.date, Stage: k.stage}
+			totals[k] = day
+		}
+		if !record.Success {
+			day.Failures++
+			continue
+		}
+		day.Add(Usage{
+			Calls:        1,
+			InputTokens:  record.InputTokens,
+			OutputTokens: record.OutputTokens,
+			CostUSD:      record.CostUSD,
+			Estimated:    record.UsageEstimated,
+		})
+	}
+
+	days := make([]DailyUsage, 0, len(totals))
+	for _, day := range totals {
+		days = append(days, *day)
+	}
+	sort.Slice(days, func(i, j int) bool {
+		if days[i].Date != days[j].Date {
+			return days[i].Date < days[j].Date
+		}
+		return days[i].Stage < days[j].Stage
+	})
+	return days
+}
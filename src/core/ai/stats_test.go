@@ -0,0 +1,78 @@
+// File: src/core/ai/stats_test.go
+package ai_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ready-to-release/eac/src/core/ai"
+)
+
+func TestExecutor_ReportsUsage(t *testing.T) {
+	root := t.TempDir()
+	executor := ai.NewExecutor(root)
+	executor.SetFallbackProvider(func(config *ai.Config) (ai.Provider, error) {
+		return ai.NewMockProvider(strings.Repeat("x", 40)), nil
+	})
+	executor.SetLogger(ai.NewFileLogger(root))
+
+	var usage ai.Usage
+	_, err := executor.Execute(context.Background(), strings.Repeat("y", 400),
+		ai.WithModel("sonnet"), ai.WithStage("top-level"), ai.WithUsage(&usage))
+	if err != nil {
+		t.Fatalf("execute failed: %v", err)
+	}
+
+	// The mock reports nothing, so tokens are estimated and priced as sonnet
+	if usage.Calls != 1 || usage.InputTokens != 100 || usage.OutputTokens != 10 || !usage.Estimated {
+		t.Errorf("unexpected usage: %+v", usage)
+	}
+	if want := (100*3.0 + 10*15.0) / 1e6; usage.CostUSD != want {
+		t.Errorf("cost = %v, want %v", usage.CostUSD, want)
+	}
+
+	records, err := ai.ReadExecutionLog(ai.ExecutionLogPath(root))
+	if err != nil || len(records) != 1 {
+		t.Fatalf("expected one log record, got %d (%v)", len(records), err)
+	}
+	if records[0].Stage != "top-level" || records[0].Model != "sonnet" || records[0].InputTokens != 100 {
+		t.Errorf("unexpected record: %+v", records[0])
+	}
+}
+
+func TestAggregateDaily(t *testing.T) {
+	day1 := time.Date(2026, 10, 14, 12, 0, 0, 0, time.Local)
+	day2 := day1.Add(24 * time.Hour)
+	records := []ai.ExecutionRecord{
+		{Timestamp: day1, Stage: "top-level", Success: true, InputTokens: 100, OutputTokens: 10, CostUSD: 0.01},
+		{Timestamp: day1, Stage: "module", Success: true, InputTokens: 50, OutputTokens: 5, CostUSD: 0.02},
+		{Timestamp: day2, Stage: "module", Success: false},
+		{Timestamp: day2, Stage: "module", Success: true, InputTokens: 1, OutputTokens: 1, UsageEstimated: true},
+		{Timestamp: day1.Add(-72 * time.Hour), Success: true, InputTokens: 999},
+	}
+
+	days := ai.AggregateDaily(records, day1.Add(-time.Hour), false)
+	if len(days) != 2 {
+		t.Fatalf("expected 2 days, got %+v", days)
+	}
+	if days[0].Date != "2026-10-14" || days[0].Calls != 2 || days[0].InputTokens != 150 || days[0].CostUSD != 0.03 {
+		t.Errorf("unexpected first day: %+v", days[0])
+	}
+	if days[1].Failures != 1 || days[1].Calls != 1 || !days[1].Estimated {
+		t.Errorf("unexpected second day: %+v", days[1])
+	}
+
+	byStage := ai.AggregateDaily(records, day1.Add(-time.Hour), true)
+	if len(byStage) != 3 || byStage[0].Stage != "module" || byStage[1].Stage != "top-level" {
+		t.Errorf("unexpected stage breakdown: %+v", byStage)
+	}
+}
+
+func TestUsage_String(t *testing.T) {
+	usage := ai.Usage{Calls: 2, InputTokens: 12345, OutputTokens: 450, CostUSD: 0.0412, Estimated: true}
+	if got, want := usage.String(), "2 calls, 12.3k in / 450 out tokens, ~$0.04"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
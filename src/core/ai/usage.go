@@ -0,0 +1,139 @@
+// File: src/core/ai/usage.go
+// Intent: Account for the tokens and cost of each AI execution
+//
+// Design (Three Rules of Vibe Coding):
+//
+// Easy to understand:
+//   - Providers fill a Usage passed with WithUsage
+//   - Providers that cannot report usage get an estimate from the text length
+//   - Cost comes from the provider when it reports one, else from a price table
+//
+// Easy to change:
+//   - Prices are a single table keyed by model family
+//   - Usage is opt-in through an option, so the Provider interface is unchanged
+//
+// Hard to break:
+//   - Estimated values are marked as such
+//   - Unknown models get no cost instead of a wrong one
+
+package ai
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Usage is the token usage and cost of AI executions
+type Usage struct {
+	Calls        int     `json:"calls"`
+	InputTokens  int     `json:"input_tokens"`
+	CachedTokens int     `json:"cached_tokens,omitempty"` // input tokens read from the prompt cache, included in InputTokens
+	OutputTokens int     `json:"output_tokens"`
+	CostUSD      float64 `json:"cost_usd"`
+	Estimated    bool    `json:"estimated,omitempty"` // tokens or cost were estimated
+}
+
+// WithUsage makes the provider report the usage of the execution into usage
+func WithUsage(usage *Usage) Option {
+	return func(opts *ExecuteOptions) {
+		opts.Usage = usage
+	}
+}
+
+// WithStage labels the execution with the pipeline stage it belongs to, for
+// logging and usage statistics
+func WithStage(stage string) Option {
+	return func(opts *ExecuteOptions) {
+		opts.Stage = stage
+	}
+}
+
+// Add accumulates o into u
+func (u *Usage) Add(o Usage) {
+	u.Calls += o.Calls
+	u.InputTokens += o.InputTokens
+	u.CachedTokens += o.CachedTokens
+	u.OutputTokens += o.OutputTokens
+	u.CostUSD += o.CostUSD
+	u.Estimated = u.Estimated || o.Estimated
+}
+
+// String summarizes the usage, e.g. "2 calls, 12.3k in / 450 out tokens, ~$0.04",
+// with "(8.0k cached)" after the input tokens when the prompt cache was hit
+func (u Usage) String() string {
+	calls := "calls"
+	if u.Calls == 1 {
+		calls = "call"
+	}
+	cost := fmt.Sprintf("$%.2f", u.CostUSD)
+	if u.Estimated {
+		cost = "~" + cost
+	}
+	input := formatTokens(u.InputTokens)
+	if u.CachedTokens > 0 {
+		input += fmt.Sprintf(" (%s cached)", formatTokens(u.CachedTokens))
+	}
+	return fmt.Sprintf("%d %s, %s in / %s out tokens, %s",
+		u.Calls, calls, input, formatTokens(u.OutputTokens), cost)
+}
+
+func formatTokens(n int) string {
+	if n < 1000 {
+		return fmt.Sprint(n)
+	}
+	return fmt.Sprintf("%.1fk", float64(n)/1000)
+}
+
+// EstimateTokens approximates the token count of text at four characters
+// per token
+func EstimateTokens(text string) int {
+	return (len(text) + 3) / 4
+}
+
+// modelPrices are USD per million input and output tokens, matched by model
+// name substring in order
+var modelPrices = []struct {
+	match  string
+	input  float64
+	output float64
+}{
+	{"haiku", 1, 5},
+	{"sonnet", 3, 15},
+	{"opus", 15, 75},
+	{"gpt-4o-mini", 0.15, 0.6},
+	{"gpt-4o", 2.5, 10},
+	{"gpt-4", 30, 60},
+	{"gpt-3.5", 0.5, 1.5},
+	{"gemini-1.5-flash", 0.075, 0.3},
+	{"gemini-1.5-pro", 1.25, 5},
+	{"gemini", 0.1, 0.4},
+}
+
+// EstimateCost returns the cost of a call to model from the price table. It
+// returns false for models without a known price.
+func EstimateCost(model string, inputTokens, outputTokens int) (float64, bool) {
+	model = strings.ToLower(model)
+	for _, price := range modelPrices {
+		if strings.Contains(model, price.match) {
+			return (float64(inputTokens)*price.input + float64(outputTokens)*price.output) / 1e6, true
+		}
+	}
+	return 0, false
+}
+
+// completeUsage fills in what the provider did not report: tokens estimated
+// from the text, cost from the price table
+func completeUsage(usage *Usage, model, input, response string) {
+	usage.Calls = 1
+	if usage.InputTokens == 0 && usage.OutputTokens == 0 {
+		usage.InputTokens = EstimateTokens(input)
+		usage.OutputTokens = EstimateTokens(response)
+		usage.Estimated = true
+	}
+	if usage.CostUSD == 0 {
+		if cost, ok := EstimateCost(model, usage.InputTokens, usage.OutputTokens); ok {
+			usage.CostUSD = cost
+			usage.Estimated = true
+		}
+	}
+}
@@ -0,0 +1,416 @@
+// Package audit writes the audit log: one JSON line per CLI command and MCP
+// tool call, appended to .r2r/audit.log in the repository root. Each entry
+// records when the call ran, its redacted arguments, how long it took, how
+// it ended and the containers it started. The log is only ever appended to;
+// when it grows past its size limit it is rotated to audit.log.1 and older
+// files are shifted up to audit.log.<MaxFiles>.
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ready-to-release/eac/src/core/redact"
+	"github.com/ready-to-release/eac/src/core/reporoot"
+)
+
+const (
+	// PathEnv names the environment variable that moves the audit log
+	PathEnv = "R2R_AUDIT_LOG"
+	// DisableEnv names the environment variable that turns the audit log
+	// off, see Enabled
+	DisableEnv = "R2R_AUDIT"
+
+	// DefaultMaxSize is the size in bytes above which the log is rotated
+	DefaultMaxSize = 10 << 20
+	// DefaultMaxFiles is the number of rotated files that are kept
+	DefaultMaxFiles = 5
+)
+
+// Sources of entries
+const (
+	SourceCLI = "cli"
+)
+
+// Kinds of entries
+const (
+	KindCommand = "command" // a CLI command
+	KindTool    = "tool"    // an MCP tool call
+)
+
+// Container is a container started during a call
+type Container struct {
+	ID      string `json:"id"`
+	Image   string `json:"image,omitempty"`
+	ImageID string `json:"image_id,omitempty"`
+}
+
+// Entry is one line of the audit log
+type Entry struct {
+	Time       time.Time       `json:"time"`
+	Source     string          `json:"source"` // "cli" or the name of the MCP server
+	Kind       string          `json:"kind"`
+	Name       string          `json:"name"` // command path or tool name
+	Args       []string        `json:"args,omitempty"`
+	Arguments  json.RawMessage `json:"arguments,omitempty"` // tool arguments
+	DurationMS int64           `json:"duration_ms"`
+	ExitCode   int             `json:"exit_code"`
+	Error      string          `json:"error,omitempty"`
+	Containers []Container     `json:"containers,omitempty"`
+	User       string          `json:"user,omitempty"`
+	PID        int             `json:"pid"`
+}
+
+// Log is an audit log file. It is safe for concurrent use; entries of
+// concurrent processes are each written with a single append, and a file
+// lock next to the log orders their rotations.
+type Log struct {
+	path     string
+	maxSize  int64
+	maxFiles int
+	mu       sync.Mutex
+}
+
+// New returns the audit log at path with the default rotation
+func New(path string) *Log {
+	return &Log{path: path, maxSize: DefaultMaxSize, maxFiles: DefaultMaxFiles}
+}
+
+// Path returns the path of the current log file
+func (l *Log) Path() string {
+	return l.path
+}
+
+// Append writes e as one line, rotating the log first when the line would
+// take it past its size limit
+func (l *Log) Append(e Entry) error {
+	line, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit entry: %w", err)
+	}
+	line = append(line, '\n')
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(l.path), 0755); err != nil {
+		return fmt.Errorf("failed to create audit log directory: %w", err)
+	}
+	if l.full(len(line)) {
+		if err := l.rotate(len(line)); err != nil {
+			return err
+		}
+	}
+
+	f, err := os.OpenFile(l.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer f.Close()
+	if _, err := f.Write(line); err != nil {
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+	return nil
+}
+
+// full reports whether appending n bytes would take the log past its size
+// limit
+func (l *Log) full(n int) bool {
+	info, err := os.Stat(l.path)
+	return err == nil && info.Size() > 0 && info.Size()+int64(n) > l.maxSize
+}
+
+// rotate shifts audit.log.N-1 to audit.log.N, dropping the oldest file, and
+// moves the current file to audit.log.1. The caller holds the mutex; the
+// file lock keeps other processes appending to the log from rotating at the
+// same time, and the log is only rotated if no other process did so while
+// this one waited for the lock.
+func (l *Log) rotate(n int) error {
+	release, err := acquireLock(l.path)
+	if err != nil {
+		return fmt.Errorf("failed to rotate audit log: %w", err)
+	}
+	defer release()
+
+	if !l.full(n) {
+		return nil
+	}
+	os.Remove(rotatedPath(l.path, l.maxFiles))
+	for i := l.maxFiles - 1; i >= 1; i-- {
+		if err := os.Rename(rotatedPath(l.path, i), rotatedPath(l.path, i+1)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to rotate audit log: %w", err)
+		}
+	}
+	if err := os.Rename(l.path, rotatedPath(l.path, 1)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to rotate audit log: %w", err)
+	}
+	return nil
+}
+
+func rotatedPath(path string, n int) string {
+	return path + "." + strconv.Itoa(n)
+}
+
+// Tail returns the last n entries of the log, reading rotated files when the
+// current one holds fewer. n <= 0 returns all entries. Lines that are not
+// valid entries, such as a line cut off by a full disk, are skipped.
+func (l *Log) Tail(n int) ([]Entry, error) {
+	var entries []Entry
+	for i := 0; i <= l.maxFiles; i++ {
+		path := l.path
+		if i > 0 {
+			path = rotatedPath(l.path, i)
+		}
+		file, err := readFile(path)
+		if errors.Is(err, os.ErrNotExist) {
+			if i == 0 {
+				continue
+			}
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		entries = append(file, entries...)
+		if n > 0 && len(entries) >= n {
+			break
+		}
+	}
+	if n > 0 && len(entries) > n {
+		entries = entries[len(entries)-n:]
+	}
+	return entries, nil
+}
+
+func readFile(path string) ([]Entry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Read(f)
+}
+
+// Read decodes the entries of an audit log, skipping invalid lines
+func Read(r io.Reader) ([]Entry, error) {
+	var entries []Entry
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16<<20)
+	for scanner.Scan() {
+		var e Entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			continue
+		}
+		entries = append(entries, e)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read audit log: %w", err)
+	}
+	return entries, nil
+}
+
+// Enabled reports whether the audit log is on. It is on unless R2R_AUDIT
+// is set to false, 0, off or no.
+func Enabled() bool {
+	switch strings.ToLower(os.Getenv(DisableEnv)) {
+	case "false", "0", "off", "no":
+		return false
+	}
+	return true
+}
+
+// DefaultPath returns the path of the audit log: R2R_AUDIT_LOG, or
+// .r2r/audit.log in the repository root
+func DefaultPath() (string, error) {
+	if path := os.Getenv(PathEnv); path != "" {
+		return filepath.Abs(path)
+	}
+	root, err := reporoot.Find("")
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(root, ".r2r", "audit.log"), nil
+}
+
+var (
+	defaultLog     *Log
+	defaultLogOnce sync.Once
+)
+
+// Default returns the audit log of the process, or nil when it is off or
+// the working directory is outside of a repository
+func Default() *Log {
+	defaultLogOnce.Do(func() {
+		if !Enabled() {
+			return
+		}
+		if path, err := DefaultPath(); err == nil {
+			defaultLog = New(path)
+		}
+	})
+	return defaultLog
+}
+
+// Record is a call in progress
+type Record struct {
+	log      *Log // nil when the audit log is off
+	entry    Entry
+	started  time.Time
+	mu       sync.Mutex
+	finished bool
+}
+
+// Start begins recording a call. args are redacted.
+func Start(source, kind, name string, args []string) *Record {
+	now := time.Now()
+	return &Record{
+		log:     Default(),
+		started: now,
+		entry: Entry{
+			Time:   now.UTC(),
+			Source: source,
+			Kind:   kind,
+			Name:   name,
+			Args:   RedactArgs(args),
+			User:   currentUser(),
+			PID:    os.Getpid(),
+		},
+	}
+}
+
+// StartTool begins recording an MCP tool call. Arguments whose names mark
+// them as secrets are masked, and the rest are redacted.
+func StartTool(server, tool string, arguments map[string]interface{}) *Record {
+	r := Start(server, KindTool, tool, nil)
+	if len(arguments) > 0 {
+		if data, err := json.Marshal(RedactArguments(arguments)); err == nil {
+			r.entry.Arguments = redact.Bytes(data)
+		}
+	}
+	return r
+}
+
+// AddContainer records a container started during the call
+func (r *Record) AddContainer(c Container) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entry.Containers = append(r.entry.Containers, c)
+}
+
+// Finish completes the record and appends it to the audit log. Writing
+// the audit log never fails the call, so errors are returned for logging
+// only. Finishing a record twice writes it once.
+func (r *Record) Finish(exitCode int, err error) error {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	if r.finished {
+		r.mu.Unlock()
+		return nil
+	}
+	r.finished = true
+	entry := r.entry
+	entry.DurationMS = time.Since(r.started).Milliseconds()
+	entry.ExitCode = exitCode
+	if err != nil {
+		entry.Error = redact.String(err.Error())
+	}
+	r.mu.Unlock()
+
+	if r.log == nil {
+		return nil
+	}
+	return r.log.Append(entry)
+}
+
+var (
+	current   *Record
+	currentMu sync.Mutex
+)
+
+// SetCurrent makes r the record of the command the process runs, which
+// AddCurrentContainer adds to
+func SetCurrent(r *Record) {
+	currentMu.Lock()
+	defer currentMu.Unlock()
+	current = r
+}
+
+// Current returns the record set with SetCurrent, or nil
+func Current() *Record {
+	currentMu.Lock()
+	defer currentMu.Unlock()
+	return current
+}
+
+// AddCurrentContainer records a container on the current record, if any
+func AddCurrentContainer(c Container) {
+	Current().AddContainer(c)
+}
+
+// RedactArgs returns args with credentials masked, including the values of
+// secret-named flags (--token value, --api-key=value) and assignments
+// (GITHUB_TOKEN=value)
+func RedactArgs(args []string) []string {
+	if len(args) == 0 {
+		return nil
+	}
+	redacted := make([]string, len(args))
+	for i, arg := range args {
+		name, _, assigned := strings.Cut(arg, "=")
+		switch {
+		case assigned && isSecretArg(name):
+			arg = name + "=" + redact.Mask
+		case i > 0 && strings.HasPrefix(args[i-1], "-") && !strings.Contains(args[i-1], "=") && isSecretArg(args[i-1]):
+			arg = redact.Mask
+		}
+		redacted[i] = redact.String(arg)
+	}
+	return redacted
+}
+
+// isSecretArg reports whether a flag or variable name marks a secret
+func isSecretArg(name string) bool {
+	return redact.IsSecretName(strings.ReplaceAll(strings.TrimLeft(name, "-"), "-", "_"))
+}
+
+// RedactArguments returns a copy of tool arguments with the values of
+// secret-named keys masked, recursing into objects
+func RedactArguments(arguments map[string]interface{}) map[string]interface{} {
+	redacted := make(map[string]interface{}, len(arguments))
+	for key, value := range arguments {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			redacted[key] = RedactArguments(v)
+		default:
+			if redact.IsSecretName(key) {
+				redacted[key] = redact.Mask
+			} else {
+				redacted[key] = value
+			}
+		}
+	}
+	return redacted
+}
+
+func currentUser() string {
+	for _, name := range []string{"USER", "USERNAME"} {
+		if user := os.Getenv(name); user != "" {
+			return user
+		}
+	}
+	return ""
+}
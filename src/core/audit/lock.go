@@ -0,0 +1,48 @@
+package audit
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// lockTimeout bounds how long a rotation waits for another process rotating
+// the same log
+const lockTimeout = 2 * time.Second
+
+// lockPath returns the advisory lock file guarding the rotation of the log
+// at path. The log itself is renamed by a rotation, so it cannot carry the
+// lock; the leading dot keeps the lock out of audit.log* listings.
+func lockPath(path string) string {
+	return filepath.Join(filepath.Dir(path), "."+filepath.Base(path)+".lock")
+}
+
+// acquireLock takes an exclusive advisory lock on the log at path. The
+// returned function releases it.
+func acquireLock(path string) (func(), error) {
+	f, err := os.OpenFile(lockPath(path), os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log lock: %w", err)
+	}
+
+	deadline := time.Now().Add(lockTimeout)
+	for {
+		locked, err := tryLock(f, true)
+		if err != nil {
+			f.Close()
+			return nil, fmt.Errorf("failed to lock audit log: %w", err)
+		}
+		if locked {
+			return func() {
+				unlock(f)
+				f.Close()
+			}, nil
+		}
+		if time.Now().After(deadline) {
+			f.Close()
+			return nil, fmt.Errorf("timed out after %s waiting for the audit log lock %s", lockTimeout, lockPath(path))
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
@@ -0,0 +1,44 @@
+// Package collections provides small generic helpers for sorting and
+// de-duplicating slices and map keys, used where commands list modules,
+// subcommands or command names.
+package collections
+
+import (
+	"cmp"
+	"maps"
+	"slices"
+)
+
+// SortedKeys returns the keys of m in ascending order
+func SortedKeys[K cmp.Ordered, V any](m map[K]V) []K {
+	return slices.Sorted(maps.Keys(m))
+}
+
+// Unique returns the items without duplicates, keeping the first occurrence
+// of each in its original position. items is not modified.
+func Unique[T comparable](items []T) []T {
+	if items == nil {
+		return nil
+	}
+	seen := make(map[T]struct{}, len(items))
+	result := make([]T, 0, len(items))
+	for _, item := range items {
+		if _, ok := seen[item]; ok {
+			continue
+		}
+		seen[item] = struct{}{}
+		result = append(result, item)
+	}
+	return result
+}
+
+// SortedUnique returns the items sorted in ascending order without
+// duplicates. items is not modified.
+func SortedUnique[T cmp.Ordered](items []T) []T {
+	if items == nil {
+		return nil
+	}
+	result := slices.Clone(items)
+	slices.Sort(result)
+	return slices.Compact(result)
+}
@@ -0,0 +1,42 @@
+package collections
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSortedKeys(t *testing.T) {
+	got := SortedKeys(map[string]int{"src-cli": 1, "docs": 2, "src-core": 3})
+	want := []string{"docs", "src-cli", "src-core"}
+	if !slices.Equal(got, want) {
+		t.Errorf("SortedKeys() = %v, want %v", got, want)
+	}
+	if got := SortedKeys(map[string]bool(nil)); len(got) != 0 {
+		t.Errorf("SortedKeys(nil) = %v, want empty", got)
+	}
+}
+
+func TestUnique(t *testing.T) {
+	items := []string{"b", "a", "b", "c", "a"}
+	got := Unique(items)
+	if want := []string{"b", "a", "c"}; !slices.Equal(got, want) {
+		t.Errorf("Unique() = %v, want %v", got, want)
+	}
+	if want := []string{"b", "a", "b", "c", "a"}; !slices.Equal(items, want) {
+		t.Errorf("Unique() modified its input: %v", items)
+	}
+	if got := Unique([]int(nil)); got != nil {
+		t.Errorf("Unique(nil) = %v, want nil", got)
+	}
+}
+
+func TestSortedUnique(t *testing.T) {
+	items := []int{3, 1, 3, 2, 1}
+	got := SortedUnique(items)
+	if want := []int{1, 2, 3}; !slices.Equal(got, want) {
+		t.Errorf("SortedUnique() = %v, want %v", got, want)
+	}
+	if want := []int{3, 1, 3, 2, 1}; !slices.Equal(items, want) {
+		t.Errorf("SortedUnique() modified its input: %v", items)
+	}
+}
@@ -0,0 +1,46 @@
+package commitrules
+
+import (
+	"strings"
+	"testing"
+)
+
+func FuzzModuleSubjects(f *testing.F) {
+	f.Add("ai", "ai: feat: record responses", "commands", "commands: fix: keep newline")
+	f.Add("src/core", "src/core: refactor(ai)!: split providers", "docs", "docs: docs: typo")
+
+	f.Fuzz(func(t *testing.T, module1, subject1, module2, subject2 string) {
+		modules := []string{module1, module2}
+		subjects := []string{subject1, subject2}
+		for i := range modules {
+			modules[i] = strings.TrimSpace(modules[i])
+			subjects[i] = strings.TrimSpace(subjects[i])
+			if !validSectionLine(modules[i]) || !validSectionLine(subjects[i]) {
+				t.Skip()
+			}
+		}
+
+		sections := make([]string, len(modules))
+		for i := range modules {
+			sections[i] = "## " + modules[i] + "\n\n" + subjects[i] + "\n\nBody of " + modules[i] + "."
+		}
+		message := "# multi-module: feat: title\n\nSummary.\n\n" + strings.Join(sections, "\n\n---\n\n")
+
+		parsed := moduleSubjects(strings.Split(message, "\n"))
+		if len(parsed) != len(modules) {
+			t.Fatalf("parsed %d module sections, want %d:\n%s", len(parsed), len(modules), message)
+		}
+		for i, subject := range parsed {
+			if subject.Module != modules[i] || subject.Text != subjects[i] {
+				t.Errorf("section %d parsed as %q / %q, want %q / %q", i, subject.Module, subject.Text, modules[i], subjects[i])
+			}
+		}
+	})
+}
+
+// validSectionLine reports whether text fits on one line of a module section
+// without being read as a header or separator
+func validSectionLine(text string) bool {
+	return text != "" && text != "---" && !strings.ContainsAny(text, "\r\n") &&
+		!strings.HasPrefix(text, "## ") && strings.TrimSpace(text) == text
+}
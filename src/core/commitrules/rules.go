@@ -0,0 +1,236 @@
+package commitrules
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// RuleConfigFile is the optional rule configuration, relative to the repository root
+const RuleConfigFile = ".r2r/commit-rules.yml"
+
+// Rule is a commit message check. Check reports findings with Code, Message
+// and Line; Rule and Severity are filled in from the rule and its configuration.
+type Rule struct {
+	ID          string
+	Description string
+	Severity    string // default severity: "error" or "warning"
+	MaxLength   int    // default threshold, 0 when the rule has none
+	Check       func(input RuleInput) []ValidationError
+}
+
+// RuleInput is what a rule checks
+type RuleInput struct {
+	Lines           []string // message lines; inline directives are blanked
+	AffectedModules []string // modules that had staged changes
+	MaxLength       int      // configured threshold
+}
+
+// RuleConfig is the content of .r2r/commit-rules.yml:
+//
+//	rules:
+//	  line-max-length:
+//	    severity: error
+//	    max_length: 100
+//	  top-level-body:
+//	    enabled: false
+type RuleConfig struct {
+	Rules map[string]RuleSetting `yaml:"rules"`
+}
+
+// RuleSetting overrides the defaults of one rule
+type RuleSetting struct {
+	Enabled   *bool  `yaml:"enabled"`    // unset keeps the rule enabled
+	Severity  string `yaml:"severity"`   // "error" or "warning"
+	MaxLength int    `yaml:"max_length"` // only for rules with a threshold
+}
+
+// registeredRules run in registration order, starting with the built-in rules
+var registeredRules = builtinRules()
+
+// suppressPattern matches inline directives such as
+// "<!-- commit-rules-disable line-max-length -->"
+var suppressPattern = regexp.MustCompile(`^<!--\s*commit-rules-(disable-next-line|disable)\b(.*?)-->$`)
+
+// RegisterRule adds a rule to the registry. It panics on a duplicate or
+// incomplete rule, as rules are registered during initialization.
+func RegisterRule(rule Rule) {
+	if rule.ID == "" || rule.Check == nil {
+		panic("commit message rule needs an ID and a Check function")
+	}
+	if rule.Severity != "error" && rule.Severity != "warning" {
+		panic(fmt.Sprintf("commit message rule %s: invalid severity %q", rule.ID, rule.Severity))
+	}
+	if _, exists := findRule(rule.ID); exists {
+		panic(fmt.Sprintf("commit message rule %s is already registered", rule.ID))
+	}
+	registeredRules = append(registeredRules, rule)
+}
+
+// Rules returns the registered rules in the order they run
+func Rules() []Rule {
+	return append([]Rule{}, registeredRules...)
+}
+
+func findRule(id string) (Rule, bool) {
+	for _, rule := range registeredRules {
+		if rule.ID == id {
+			return rule, true
+		}
+	}
+	return Rule{}, false
+}
+
+// LoadRuleConfig reads .r2r/commit-rules.yml, returning an empty config when it does not exist
+func LoadRuleConfig(workspaceRoot string) (*RuleConfig, error) {
+	var config RuleConfig
+
+	data, err := os.ReadFile(filepath.Join(workspaceRoot, RuleConfigFile))
+	if os.IsNotExist(err) {
+		return &config, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rule config: %w", err)
+	}
+
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse rule config YAML: %w", err)
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid rule config %s: %w", RuleConfigFile, err)
+	}
+
+	return &config, nil
+}
+
+// Validate checks that every setting names a registered rule and fits it
+func (c *RuleConfig) Validate() error {
+	ids := make([]string, 0, len(c.Rules))
+	for id := range c.Rules {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		setting := c.Rules[id]
+		rule, exists := findRule(id)
+		if !exists {
+			return fmt.Errorf("rules.%s: unknown rule", id)
+		}
+		if setting.Severity != "" && setting.Severity != "error" && setting.Severity != "warning" {
+			return fmt.Errorf("rules.%s: severity must be error or warning, got %q", id, setting.Severity)
+		}
+		if setting.MaxLength < 0 {
+			return fmt.Errorf("rules.%s: max_length must be positive", id)
+		}
+		if setting.MaxLength > 0 && rule.MaxLength == 0 {
+			return fmt.Errorf("rules.%s: rule has no max_length", id)
+		}
+	}
+
+	return nil
+}
+
+func (c *RuleConfig) setting(id string) RuleSetting {
+	if c == nil {
+		return RuleSetting{}
+	}
+	return c.Rules[id]
+}
+
+// VerifyCommitMessage runs the enabled rules on a commit message. Findings
+// can be suppressed inline with HTML comments, which do not render:
+//
+//	<!-- commit-rules-disable line-max-length -->            whole message
+//	<!-- commit-rules-disable-next-line line-max-length -->  following line
+//
+// A directive without rule IDs suppresses all rules. config may be nil.
+func VerifyCommitMessage(commitMessage string, affectedModules []string, config *RuleConfig) []ValidationError {
+	lines := strings.Split(commitMessage, "\n")
+	if len(lines) == 0 {
+		return []ValidationError{{
+			Code:     "EMPTY_MESSAGE",
+			Message:  "Commit message is empty",
+			Severity: "error",
+		}}
+	}
+
+	lines, suppressed := parseSuppressions(lines)
+
+	var findings []ValidationError
+	for _, rule := range registeredRules {
+		setting := config.setting(rule.ID)
+		if setting.Enabled != nil && !*setting.Enabled {
+			continue
+		}
+
+		input := RuleInput{Lines: lines, AffectedModules: affectedModules, MaxLength: rule.MaxLength}
+		if setting.MaxLength > 0 {
+			input.MaxLength = setting.MaxLength
+		}
+
+		for _, finding := range rule.Check(input) {
+			if suppressed(rule.ID, finding.Line) {
+				continue
+			}
+			finding.Rule = rule.ID
+			finding.Severity = rule.Severity
+			if setting.Severity != "" {
+				finding.Severity = setting.Severity
+			}
+			findings = append(findings, finding)
+		}
+	}
+
+	return findings
+}
+
+// parseSuppressions blanks the directive lines, keeping line numbers, and
+// returns whether a rule is suppressed on a line
+func parseSuppressions(lines []string) ([]string, func(id string, line int) bool) {
+	cleaned := append([]string{}, lines...)
+	message := make(map[string]bool)
+	byLine := make(map[int]map[string]bool)
+
+	for i, line := range lines {
+		match := suppressPattern.FindStringSubmatch(strings.TrimSpace(line))
+		if match == nil {
+			continue
+		}
+		cleaned[i] = ""
+
+		ids := strings.FieldsFunc(match[2], func(r rune) bool {
+			return r == ',' || r == ' ' || r == '\t'
+		})
+		if len(ids) == 0 {
+			ids = []string{"*"}
+		}
+
+		target := message
+		if match[1] == "disable-next-line" {
+			// Line numbers are 1-based, so the next line is i+2
+			target = byLine[i+2]
+			if target == nil {
+				target = make(map[string]bool)
+				byLine[i+2] = target
+			}
+		}
+		for _, id := range ids {
+			target[id] = true
+		}
+	}
+
+	return cleaned, func(id string, line int) bool {
+		if message["*"] || message[id] {
+			return true
+		}
+		ids := byLine[line]
+		return line > 0 && (ids["*"] || ids[id])
+	}
+}
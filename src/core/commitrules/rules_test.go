@@ -0,0 +1,151 @@
+package commitrules
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const longBodyMessage = `# cli: feat: add stats
+
+This body line is deliberately written to be longer than seventy-two characters.
+`
+
+func writeRuleConfig(t *testing.T, content string) string {
+	root := t.TempDir()
+	path := filepath.Join(root, RuleConfigFile)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return root
+}
+
+func findingsByRule(findings []ValidationError) map[string]ValidationError {
+	byRule := make(map[string]ValidationError)
+	for _, finding := range findings {
+		byRule[finding.Rule] = finding
+	}
+	return byRule
+}
+
+func TestVerifyCommitMessage_FindingsCarryRuleIDs(t *testing.T) {
+	findings := findingsByRule(VerifyCommitMessage(longBodyMessage, nil, nil))
+
+	finding, ok := findings["line-max-length"]
+	if !ok || finding.Code != "LINE_TOO_LONG" || finding.Severity != "warning" || finding.Line != 3 {
+		t.Fatalf("expected a line-max-length warning on line 3, got %+v", findings)
+	}
+	if !strings.HasSuffix(finding.Error(), "(line-max-length)") {
+		t.Errorf("expected the rule ID in %q", finding.Error())
+	}
+}
+
+func TestVerifyCommitMessage_Config(t *testing.T) {
+	config, err := LoadRuleConfig(writeRuleConfig(t, `
+rules:
+  line-max-length:
+    severity: error
+    max_length: 60
+  title-heading:
+    enabled: false
+`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	findings := findingsByRule(VerifyCommitMessage(longBodyMessage, nil, config))
+	finding := findings["line-max-length"]
+	if finding.Severity != "error" || !strings.Contains(finding.Message, "exceeds 60 characters") {
+		t.Errorf("expected the configured severity and threshold, got %+v", finding)
+	}
+
+	findings = findingsByRule(VerifyCommitMessage("no heading\n\nBody", nil, config))
+	if _, ok := findings["title-heading"]; ok {
+		t.Error("expected title-heading to be disabled")
+	}
+
+	config.Rules["line-max-length"] = RuleSetting{MaxLength: 100}
+	if _, ok := findingsByRule(VerifyCommitMessage(longBodyMessage, nil, config))["line-max-length"]; ok {
+		t.Error("expected no finding under a raised threshold")
+	}
+}
+
+func TestLoadRuleConfig_Invalid(t *testing.T) {
+	for _, content := range []string{
+		"rules:\n  no-such-rule:\n    enabled: false\n",
+		"rules:\n  line-max-length:\n    severity: fatal\n",
+		"rules:\n  title-heading:\n    max_length: 10\n",
+		"rules:\n  title-max-length:\n    max_length: -1\n",
+	} {
+		if _, err := LoadRuleConfig(writeRuleConfig(t, content)); err == nil {
+			t.Errorf("expected error for:\n%s", content)
+		}
+	}
+
+	if config, err := LoadRuleConfig(t.TempDir()); err != nil || len(config.Rules) != 0 {
+		t.Errorf("expected an empty config without a file, got %+v, %v", config, err)
+	}
+}
+
+func TestVerifyCommitMessage_InlineSuppression(t *testing.T) {
+	nextLine := `# cli: feat: add stats
+
+<!-- commit-rules-disable-next-line line-max-length -->
+This body line is deliberately written to be longer than seventy-two characters.
+And this one is also deliberately written to be longer than seventy-two characters.
+`
+	var lines []int
+	for _, finding := range VerifyCommitMessage(nextLine, nil, nil) {
+		if finding.Rule == "line-max-length" {
+			lines = append(lines, finding.Line)
+		}
+	}
+	if len(lines) != 1 || lines[0] != 5 {
+		t.Errorf("expected only line 5 to be reported, got %v", lines)
+	}
+
+	whole := "<!-- commit-rules-disable title-heading, top-level-body -->\n" + strings.Replace(longBodyMessage, "# ", "", 1)
+	findings := findingsByRule(VerifyCommitMessage(whole, nil, nil))
+	if _, ok := findings["title-heading"]; ok {
+		t.Error("expected title-heading to be suppressed")
+	}
+	if _, ok := findings["line-max-length"]; !ok {
+		t.Error("expected line-max-length to still be reported")
+	}
+
+	all := "<!-- commit-rules-disable -->\n" + longBodyMessage
+	if findings := VerifyCommitMessage(all, nil, nil); len(findings) != 0 {
+		t.Errorf("expected all rules to be suppressed, got %v", findings)
+	}
+}
+
+func TestRegisterRule(t *testing.T) {
+	defer func(rules []Rule) { registeredRules = rules }(registeredRules)
+
+	RegisterRule(Rule{
+		ID:       "no-wip",
+		Severity: "error",
+		Check: func(input RuleInput) []ValidationError {
+			if strings.Contains(input.Lines[0], "WIP") {
+				return []ValidationError{{Code: "WIP_COMMIT", Message: "Work in progress", Line: 1}}
+			}
+			return nil
+		},
+	})
+
+	findings := findingsByRule(VerifyCommitMessage("# cli: feat: WIP stats\n\nBody", nil, nil))
+	if findings["no-wip"].Code != "WIP_COMMIT" {
+		t.Errorf("expected the registered rule to run, got %+v", findings)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a duplicate rule to panic")
+		}
+	}()
+	RegisterRule(Rule{ID: "no-wip", Severity: "error", Check: func(RuleInput) []ValidationError { return nil }})
+}
@@ -0,0 +1,497 @@
+// Package commitrules validates commit messages against the commit message
+// contract with configurable, suppressible rules
+package commitrules
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ValidationError represents a contract violation
+type ValidationError struct {
+	Code     string `json:"code"`
+	Rule     string `json:"rule,omitempty"` // ID of the rule that reported it, for suppression
+	Message  string `json:"message"`
+	Line     int    `json:"line,omitempty"`
+	Severity string `json:"severity"` // "error" or "warning"
+}
+
+func (e ValidationError) Error() string {
+	message := e.Message
+	if e.Rule != "" {
+		message += " (" + e.Rule + ")"
+	}
+	if e.Line > 0 {
+		return fmt.Sprintf("[%s] Line %d: %s", e.Code, e.Line, message)
+	}
+	return fmt.Sprintf("[%s] %s", e.Code, message)
+}
+
+// CommitMessageContract represents the structure.yml contract
+type CommitMessageContract struct {
+	Version     string `yaml:"version"`
+	Name        string `yaml:"name"`
+	Description string `yaml:"description"`
+	Structure   []struct {
+		Section   string `yaml:"section"`
+		Required  bool   `yaml:"required"`
+		Format    string `yaml:"format"`
+		MaxLength int    `yaml:"max_length,omitempty"`
+	} `yaml:"structure"`
+	SemanticTypes     []string         `yaml:"semantic_types"`
+	SubjectLineFormat string           `yaml:"subject_line_format"`
+	Constraints       map[string]any   `yaml:"constraints"`
+	MarkdownRules     []map[string]any `yaml:"markdown_rules"`
+	AntiCorruption    map[string]any   `yaml:"anti_corruption"`
+}
+
+// LoadContract loads and parses the structure.yml file
+func LoadContract(contractPath string) (*CommitMessageContract, error) {
+	data, err := os.ReadFile(contractPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read contract file: %w", err)
+	}
+
+	var contract CommitMessageContract
+	if err := yaml.Unmarshal(data, &contract); err != nil {
+		return nil, fmt.Errorf("failed to parse contract YAML: %w", err)
+	}
+
+	return &contract, nil
+}
+
+// VerifyContractImplementation validates that verifier implements all contract rules
+func VerifyContractImplementation(contractPath string) []ValidationError {
+	var errors []ValidationError
+
+	contract, err := LoadContract(contractPath)
+	if err != nil {
+		errors = append(errors, ValidationError{
+			Code:     "CONTRACT_LOAD_ERROR",
+			Message:  err.Error(),
+			Severity: "error",
+		})
+		return errors
+	}
+
+	// Verify version matches
+	expectedVersion := "0.1.0"
+	if contract.Version != expectedVersion {
+		errors = append(errors, ValidationError{
+			Code:     "CONTRACT_VERSION_MISMATCH",
+			Message:  fmt.Sprintf("Expected version %s, got %s", expectedVersion, contract.Version),
+			Severity: "error",
+		})
+	}
+
+	// Verify required structure sections
+	requiredSections := map[string]bool{
+		"top_level_heading": false,
+		"top_level_body":    false,
+		"module_sections":   false,
+	}
+
+	for _, section := range contract.Structure {
+		if section.Required {
+			if _, exists := requiredSections[section.Section]; exists {
+				requiredSections[section.Section] = true
+			}
+		}
+
+		// Verify top_level_heading max_length
+		if section.Section == "top_level_heading" {
+			if section.MaxLength != 72 {
+				errors = append(errors, ValidationError{
+					Code:     "CONTRACT_CONSTRAINT_MISMATCH",
+					Message:  fmt.Sprintf("top_level_heading max_length should be 72, got %d", section.MaxLength),
+					Severity: "error",
+				})
+			}
+		}
+	}
+
+	for section, found := range requiredSections {
+		if !found {
+			errors = append(errors, ValidationError{
+				Code:     "CONTRACT_MISSING_SECTION",
+				Message:  fmt.Sprintf("Contract missing required section: %s", section),
+				Severity: "error",
+			})
+		}
+	}
+
+	// Verify semantic types
+	expectedTypes := map[string]bool{
+		"feat": true, "fix": true, "refactor": true, "docs": true,
+		"chore": true, "test": true, "perf": true, "style": true,
+	}
+	for _, t := range contract.SemanticTypes {
+		if !expectedTypes[t] {
+			errors = append(errors, ValidationError{
+				Code:     "CONTRACT_UNKNOWN_TYPE",
+				Message:  fmt.Sprintf("Unknown semantic type in contract: %s", t),
+				Severity: "warning",
+			})
+		}
+		delete(expectedTypes, t)
+	}
+	for missingType := range expectedTypes {
+		errors = append(errors, ValidationError{
+			Code:     "CONTRACT_MISSING_TYPE",
+			Message:  fmt.Sprintf("Contract missing semantic type: %s", missingType),
+			Severity: "error",
+		})
+	}
+
+	// Verify subject line format
+	if contract.SubjectLineFormat != "<module>: <type>: <description>" {
+		errors = append(errors, ValidationError{
+			Code:     "CONTRACT_FORMAT_MISMATCH",
+			Message:  fmt.Sprintf("subject_line_format mismatch: %s", contract.SubjectLineFormat),
+			Severity: "error",
+		})
+	}
+
+	// Verify constraints
+	requiredConstraints := map[string]any{
+		"max_line_length":         72,
+		"no_trailing_periods":     true,
+		"code_blocks_closed":      true,
+		"module_header_no_colons": true,
+	}
+
+	for key, expectedVal := range requiredConstraints {
+		actualVal, exists := contract.Constraints[key]
+		if !exists {
+			errors = append(errors, ValidationError{
+				Code:     "CONTRACT_MISSING_CONSTRAINT",
+				Message:  fmt.Sprintf("Contract missing constraint: %s", key),
+				Severity: "error",
+			})
+		} else if actualVal != expectedVal {
+			errors = append(errors, ValidationError{
+				Code:     "CONTRACT_CONSTRAINT_VALUE",
+				Message:  fmt.Sprintf("Constraint %s should be %v, got %v", key, expectedVal, actualVal),
+				Severity: "error",
+			})
+		}
+	}
+
+	// Verify markdown rules exist
+	if len(contract.MarkdownRules) == 0 {
+		errors = append(errors, ValidationError{
+			Code:     "CONTRACT_MISSING_MARKDOWN_RULES",
+			Message:  "Contract should define markdown_rules",
+			Severity: "warning",
+		})
+	}
+
+	return errors
+}
+
+// conventionalCommitRegex matches the title: # <module|multi-module>: <type>: <summary>
+var conventionalCommitRegex = regexp.MustCompile(`^# ([a-z0-9\-]+|multi-module):\s*(feat|fix|refactor|docs|chore|test|perf|style):\s*(.+)$`)
+
+// subjectRegex matches a module subject line: <module>: <type>: <description>
+var subjectRegex = regexp.MustCompile(`^([a-z0-9\-]+):\s*(feat|fix|refactor|docs|chore|test|perf|style):\s*(.+)$`)
+
+// VerifyCommitMessageContract validates a commit message against contracts/commit-message/0.1.0/structure.yml
+// with the default rule settings. affectedModules is the list of modules that had staged changes
+func VerifyCommitMessageContract(commitMessage string, affectedModules []string) []ValidationError {
+	return VerifyCommitMessage(commitMessage, affectedModules, nil)
+}
+
+// builtinRules are the contract rules, in the order they run
+func builtinRules() []Rule {
+	return []Rule{
+		{ID: "title-heading", Description: "First line is a '# ' heading", Severity: "error", Check: checkTitleHeading},
+		{ID: "title-format", Description: "Title follows '# <module|multi-module>: <type>: <summary>'", Severity: "error", Check: checkTitleFormat},
+		{ID: "title-max-length", Description: "Title fits max_length characters", Severity: "error", MaxLength: 72, Check: checkTitleLength},
+		{ID: "title-trailing-period", Description: "Title does not end with a period", Severity: "error", Check: checkTitlePeriod},
+		{ID: "module-header-format", Description: "Module headers are plain module names", Severity: "error", Check: checkModuleHeaders},
+		{ID: "line-max-length", Description: "Body lines fit max_length characters", Severity: "warning", MaxLength: 72, Check: checkLineLength},
+		{ID: "top-level-body", Description: "Body text follows the title before module sections", Severity: "error", Check: checkTopLevelBody},
+		{ID: "module-sections", Description: "Multi-module commits have a section per affected module", Severity: "error", Check: checkModuleSections},
+		{ID: "subject-format", Description: "Module sections start with '<module>: <type>: <description>'", Severity: "error", Check: checkSubjectFormat},
+		{ID: "subject-max-length", Description: "Module subject lines fit max_length characters", Severity: "error", MaxLength: 72, Check: checkSubjectLength},
+		{ID: "subject-trailing-period", Description: "Module subject lines do not end with a period", Severity: "error", Check: checkSubjectPeriod},
+		{ID: "code-blocks-closed", Description: "Code blocks are closed", Severity: "error", Check: checkCodeBlocks},
+	}
+}
+
+// checkTitleHeading requires the first line to be a top-level heading
+func checkTitleHeading(input RuleInput) []ValidationError {
+	if strings.HasPrefix(input.Lines[0], "# ") {
+		return nil
+	}
+	return []ValidationError{{
+		Code:    "MISSING_TOP_HEADING",
+		Message: "First line must start with '# '",
+		Line:    1,
+	}}
+}
+
+// checkTitleFormat requires the conventional commit format in the title
+func checkTitleFormat(input RuleInput) []ValidationError {
+	title := input.Lines[0]
+	if !strings.HasPrefix(title, "# ") || conventionalCommitRegex.MatchString(title) {
+		return nil
+	}
+	return []ValidationError{{
+		Code:    "INVALID_TITLE_FORMAT",
+		Message: "Title must follow format: # <module|multi-module>: <type>: <summary>",
+		Line:    1,
+	}}
+}
+
+// checkTitleLength limits the title, including the "# " prefix
+func checkTitleLength(input RuleInput) []ValidationError {
+	title := input.Lines[0]
+	if !strings.HasPrefix(title, "# ") || DisplayWidth(title) <= input.MaxLength {
+		return nil
+	}
+	return []ValidationError{{
+		Code:    "TITLE_TOO_LONG",
+		Message: fmt.Sprintf("Title exceeds %d characters (%d chars)", input.MaxLength, DisplayWidth(title)),
+		Line:    1,
+	}}
+}
+
+// checkTitlePeriod rejects a trailing period, except an ellipsis "..."
+func checkTitlePeriod(input RuleInput) []ValidationError {
+	title := input.Lines[0]
+	if !strings.HasPrefix(title, "# ") || !hasTrailingPeriod(title) {
+		return nil
+	}
+	return []ValidationError{{
+		Code:    "TITLE_TRAILING_PERIOD",
+		Message: "Title must not end with period",
+		Line:    1,
+	}}
+}
+
+// checkModuleHeaders requires plain module names (no colons) in "## " headers
+func checkModuleHeaders(input RuleInput) []ValidationError {
+	var errors []ValidationError
+	for i, line := range input.Lines {
+		trimmed := strings.TrimSpace(line)
+		if !strings.HasPrefix(trimmed, "## ") {
+			continue
+		}
+		moduleHeader := strings.TrimPrefix(trimmed, "## ")
+		if strings.Contains(moduleHeader, ":") {
+			errors = append(errors, ValidationError{
+				Code:    "MODULE_HEADER_FORMAT",
+				Message: fmt.Sprintf("Module header must be plain name only, found: '%s'", moduleHeader),
+				Line:    i + 1,
+			})
+		}
+	}
+	return errors
+}
+
+// checkLineLength limits the display width of body text lines (skipping headers, tables, code fences, horizontal rules)
+func checkLineLength(input RuleInput) []ValidationError {
+	var errors []ValidationError
+	for i, line := range input.Lines {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" ||
+			strings.HasPrefix(trimmed, "#") ||
+			strings.HasPrefix(trimmed, "|") ||
+			strings.HasPrefix(trimmed, "```") ||
+			trimmed == "---" ||
+			strings.HasPrefix(trimmed, "Agent:") {
+			continue
+		}
+		if width := DisplayWidth(trimmed); width > input.MaxLength {
+			errors = append(errors, ValidationError{
+				Code:    "LINE_TOO_LONG",
+				Message: fmt.Sprintf("Line exceeds %d characters (%d chars)", input.MaxLength, width),
+				Line:    i + 1,
+			})
+		}
+	}
+	return errors
+}
+
+// checkTopLevelBody requires body text after the title, before any module section
+func checkTopLevelBody(input RuleInput) []ValidationError {
+	for _, line := range input.Lines[1:] {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "## ") {
+			break
+		}
+		if trimmed != "" && !strings.HasPrefix(trimmed, "#") {
+			return nil
+		}
+	}
+	return []ValidationError{{
+		Code:    "MISSING_TOP_LEVEL_BODY",
+		Message: "Missing top-level body text after title (before module sections)",
+	}}
+}
+
+// checkModuleSections requires a section per affected module in multi-module commits.
+// Single-module commits don't require module sections.
+func checkModuleSections(input RuleInput) []ValidationError {
+	if len(input.AffectedModules) <= 1 {
+		return nil
+	}
+
+	foundModules := make(map[string]bool)
+	for _, line := range input.Lines {
+		if trimmed := strings.TrimSpace(line); strings.HasPrefix(trimmed, "## ") {
+			foundModules[strings.TrimPrefix(trimmed, "## ")] = true
+		}
+	}
+
+	if len(foundModules) == 0 {
+		return []ValidationError{{
+			Code:    "MISSING_MODULE_SECTION",
+			Message: fmt.Sprintf("Multi-module commit missing module sections. Expected: %s", strings.Join(input.AffectedModules, ", ")),
+		}}
+	}
+
+	var missingModules []string
+	for _, expectedModule := range input.AffectedModules {
+		if !foundModules[expectedModule] {
+			missingModules = append(missingModules, expectedModule)
+		}
+	}
+	if len(missingModules) == 0 {
+		return nil
+	}
+	return []ValidationError{{
+		Code:    "MISSING_MODULE_SECTION",
+		Message: fmt.Sprintf("Missing module sections for: %s", strings.Join(missingModules, ", ")),
+	}}
+}
+
+// checkSubjectFormat requires each module section to start with a semantic subject line
+func checkSubjectFormat(input RuleInput) []ValidationError {
+	var errors []ValidationError
+	for _, subject := range moduleSubjects(input.Lines) {
+		switch {
+		case subject.Line == 0:
+			errors = append(errors, ValidationError{
+				Code:    "MISSING_SUBJECT_LINE",
+				Message: fmt.Sprintf("Module '%s' missing subject line", subject.Module),
+			})
+		case !subjectRegex.MatchString(subject.Text):
+			errors = append(errors, ValidationError{
+				Code:    "INVALID_SUBJECT_FORMAT",
+				Message: fmt.Sprintf("Subject line does not follow '<module>: <type>: <description>' format: %s", subject.Text),
+				Line:    subject.Line,
+			})
+		}
+	}
+	return errors
+}
+
+// checkSubjectLength limits well-formed module subject lines
+func checkSubjectLength(input RuleInput) []ValidationError {
+	var errors []ValidationError
+	for _, subject := range moduleSubjects(input.Lines) {
+		if subjectRegex.MatchString(subject.Text) && DisplayWidth(subject.Text) > input.MaxLength {
+			errors = append(errors, ValidationError{
+				Code:    "SUBJECT_TOO_LONG",
+				Message: fmt.Sprintf("Subject line exceeds %d characters (%d chars)", input.MaxLength, DisplayWidth(subject.Text)),
+				Line:    subject.Line,
+			})
+		}
+	}
+	return errors
+}
+
+// checkSubjectPeriod rejects a trailing period on well-formed module subject lines
+func checkSubjectPeriod(input RuleInput) []ValidationError {
+	var errors []ValidationError
+	for _, subject := range moduleSubjects(input.Lines) {
+		if subjectRegex.MatchString(subject.Text) && hasTrailingPeriod(subject.Text) {
+			errors = append(errors, ValidationError{
+				Code:    "SUBJECT_TRAILING_PERIOD",
+				Message: "Subject line must not end with period",
+				Line:    subject.Line,
+			})
+		}
+	}
+	return errors
+}
+
+// moduleSubject is the first non-blank line of a module section; Line is 0
+// when the section has none
+type moduleSubject struct {
+	Module string
+	Line   int
+	Text   string
+}
+
+// moduleSubjects returns the subject line of each module section that is
+// followed by content or by another section
+func moduleSubjects(lines []string) []moduleSubject {
+	var subjects []moduleSubject
+
+	inModuleSection := false
+	currentModule := ""
+	foundSubjectLine := false
+
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+
+		// Detect module section header
+		if strings.HasPrefix(trimmed, "## ") {
+			if inModuleSection && !foundSubjectLine {
+				subjects = append(subjects, moduleSubject{Module: currentModule})
+			}
+			inModuleSection = true
+			currentModule = strings.TrimPrefix(trimmed, "## ")
+			foundSubjectLine = false
+			continue
+		}
+
+		// The first non-blank line of a section is its subject line
+		if inModuleSection && !foundSubjectLine && trimmed != "" {
+			subjects = append(subjects, moduleSubject{Module: currentModule, Line: i + 1, Text: trimmed})
+			foundSubjectLine = true
+		}
+
+		// Exit module section when we hit horizontal rule
+		if trimmed == "---" {
+			inModuleSection = false
+		}
+	}
+
+	return subjects
+}
+
+// checkCodeBlocks ensures all code blocks are properly closed
+func checkCodeBlocks(input RuleInput) []ValidationError {
+	codeBlockOpen := false
+	codeBlockLine := 0
+
+	for i, line := range input.Lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			codeBlockOpen = !codeBlockOpen
+			if codeBlockOpen {
+				codeBlockLine = i + 1
+			}
+		}
+	}
+
+	if !codeBlockOpen {
+		return nil
+	}
+	return []ValidationError{{
+		Code:    "UNCLOSED_CODE_BLOCK",
+		Message: fmt.Sprintf("Code block opened at line %d is not closed", codeBlockLine),
+	}}
+}
+
+// hasTrailingPeriod reports a trailing period that is not part of an ellipsis "..."
+func hasTrailingPeriod(text string) bool {
+	return strings.HasSuffix(text, ".") && !strings.HasSuffix(text, "...")
+}
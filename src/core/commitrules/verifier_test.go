@@ -1,4 +1,4 @@
-package commitmessage
+package commitrules
 
 import (
 	"testing"
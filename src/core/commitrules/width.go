@@ -0,0 +1,39 @@
+package commitrules
+
+import "unicode"
+
+// DisplayWidth returns the number of terminal columns text occupies: wide
+// (East Asian) characters count two, combining marks none. The length rules
+// use it so non-ASCII text wraps at the same visual width as ASCII.
+func DisplayWidth(text string) int {
+	width := 0
+	for _, r := range text {
+		switch {
+		case unicode.Is(unicode.Mn, r) || unicode.Is(unicode.Me, r) || r == '\u200d':
+		case IsWideRune(r):
+			width += 2
+		default:
+			width++
+		}
+	}
+	return width
+}
+
+// IsWideRune reports East Asian wide and fullwidth characters
+func IsWideRune(r rune) bool {
+	if unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul) {
+		return true
+	}
+	switch {
+	case r >= 0x1100 && r <= 0x115F, // Hangul Jamo initials
+		r >= 0x2E80 && r <= 0x303E, // CJK radicals, symbols and punctuation
+		r >= 0x3041 && r <= 0x33FF, // kana, CJK compatibility
+		r >= 0xFE30 && r <= 0xFE4F, // CJK compatibility forms
+		r >= 0xFF00 && r <= 0xFF60, // fullwidth forms
+		r >= 0xFFE0 && r <= 0xFFE6,
+		r >= 0x1F300 && r <= 0x1F64F, // emoji
+		r >= 0x1F900 && r <= 0x1F9FF:
+		return true
+	}
+	return false
+}
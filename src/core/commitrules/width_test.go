@@ -0,0 +1,18 @@
+package commitrules
+
+import "testing"
+
+func TestDisplayWidth(t *testing.T) {
+	for text, want := range map[string]int{
+		"plain ascii":    11,
+		"Änderung übers": 14,
+		"変更を追加":          10,
+		"한국어":            6,
+		"e\u0301":        1, // e + combining acute accent
+		"ＡＢ":             4, // fullwidth
+	} {
+		if got := DisplayWidth(text); got != want {
+			t.Errorf("DisplayWidth(%q) = %d, want %d", text, got, want)
+		}
+	}
+}
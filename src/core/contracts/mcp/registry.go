@@ -0,0 +1,204 @@
+// Package mcp loads the tool namespace registry of the MCP servers
+// (contracts/mcp/tools.yml), which keeps tool names unique across servers.
+package mcp
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ready-to-release/eac/src/core/contracts"
+	"gopkg.in/yaml.v3"
+)
+
+// ToolsFile is the registry location relative to the workspace root
+const ToolsFile = "contracts/mcp/tools.yml"
+
+// Namespace is the part of the tool name space owned by one server
+type Namespace struct {
+	Server      string   `yaml:"server"`
+	Prefix      string   `yaml:"prefix"`
+	Default     bool     `yaml:"default"` // owns names no other namespace claims
+	Tools       []string `yaml:"tools"`   // names owned outside the prefix
+	Description string   `yaml:"description"`
+}
+
+// Registry assigns tool names to servers
+type Registry struct {
+	Namespaces []Namespace `yaml:"namespaces"`
+}
+
+// Load reads and validates the registry of a workspace
+func Load(workspaceRoot string) (*Registry, error) {
+	var registry Registry
+	if err := contracts.NewLoader(workspaceRoot).LoadYAML(ToolsFile, &registry); err != nil {
+		return nil, err
+	}
+	if err := registry.Validate(); err != nil {
+		return nil, contracts.NewContractError("validate", ToolsFile, err, err.Error())
+	}
+	return &registry, nil
+}
+
+// Parse parses and validates registry YAML
+func Parse(data []byte) (*Registry, error) {
+	var registry Registry
+	if err := yaml.Unmarshal(data, &registry); err != nil {
+		return nil, fmt.Errorf("failed to parse tool registry: %w", err)
+	}
+	if err := registry.Validate(); err != nil {
+		return nil, err
+	}
+	return &registry, nil
+}
+
+// Validate checks that servers and prefixes are unique, that no prefix
+// starts another, that listed tools belong to no other prefix and that at
+// most one namespace is the default
+func (r *Registry) Validate() error {
+	var problems []string
+	servers := make(map[string]bool)
+	tools := make(map[string]string)
+	defaults := 0
+
+	for i, ns := range r.Namespaces {
+		switch {
+		case ns.Server == "":
+			problems = append(problems, fmt.Sprintf("namespaces[%d]: server is required", i))
+		case servers[ns.Server]:
+			problems = append(problems, fmt.Sprintf("server %s is listed more than once", ns.Server))
+		}
+		servers[ns.Server] = true
+
+		if !strings.HasSuffix(ns.Prefix, "-") || len(ns.Prefix) < 2 {
+			problems = append(problems, fmt.Sprintf("server %s: prefix %q must be a name followed by \"-\"", ns.Server, ns.Prefix))
+		}
+		for _, other := range r.Namespaces[i+1:] {
+			if ns.Prefix != "" && other.Prefix != "" && (strings.HasPrefix(ns.Prefix, other.Prefix) || strings.HasPrefix(other.Prefix, ns.Prefix)) {
+				problems = append(problems, fmt.Sprintf("prefixes of %s (%s) and %s (%s) overlap", ns.Server, ns.Prefix, other.Server, other.Prefix))
+			}
+		}
+
+		if ns.Default {
+			defaults++
+		}
+
+		for _, tool := range ns.Tools {
+			if owner, ok := tools[tool]; ok {
+				problems = append(problems, fmt.Sprintf("tool %s is listed by %s and %s", tool, owner, ns.Server))
+				continue
+			}
+			tools[tool] = ns.Server
+		}
+	}
+
+	for tool, server := range tools {
+		if owner := r.prefixOwner(tool); owner != "" && owner != server {
+			problems = append(problems, fmt.Sprintf("tool %s of %s is in the namespace of %s", tool, server, owner))
+		}
+	}
+	if defaults > 1 {
+		problems = append(problems, "more than one namespace is the default")
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+	sort.Strings(problems)
+	return fmt.Errorf("invalid tool registry: %s", strings.Join(problems, "; "))
+}
+
+// Namespace returns the namespace of a server
+func (r *Registry) Namespace(server string) (Namespace, bool) {
+	for _, ns := range r.Namespaces {
+		if ns.Server == server {
+			return ns, true
+		}
+	}
+	return Namespace{}, false
+}
+
+// Owner returns the server a tool name belongs to: the server listing it,
+// the server whose prefix it starts with, or the default namespace. Empty
+// when no server owns it.
+func (r *Registry) Owner(tool string) string {
+	for _, ns := range r.Namespaces {
+		for _, name := range ns.Tools {
+			if name == tool {
+				return ns.Server
+			}
+		}
+	}
+	if owner := r.prefixOwner(tool); owner != "" {
+		return owner
+	}
+	for _, ns := range r.Namespaces {
+		if ns.Default {
+			return ns.Server
+		}
+	}
+	return ""
+}
+
+// prefixOwner returns the server whose prefix tool starts with
+func (r *Registry) prefixOwner(tool string) string {
+	for _, ns := range r.Namespaces {
+		if ns.Prefix != "" && strings.HasPrefix(tool, ns.Prefix) {
+			return ns.Server
+		}
+	}
+	return ""
+}
+
+// ToolName returns the name a server advertises a tool under: the name
+// itself when the server owns it, otherwise the name with the server's
+// prefix. Servers missing from the registry keep their names.
+func (r *Registry) ToolName(server, tool string) string {
+	ns, ok := r.Namespace(server)
+	if !ok || r.Owner(tool) == server {
+		return tool
+	}
+	return ns.Prefix + tool
+}
+
+// Names maps the names a server advertises to its tool names, for looking
+// up the tool of a call
+func (r *Registry) Names(server string, tools []string) map[string]string {
+	names := make(map[string]string, len(tools))
+	for _, tool := range tools {
+		names[r.ToolName(server, tool)] = tool
+	}
+	return names
+}
+
+// Check returns the problems of the tool names advertised per server: names
+// owned by another server, names advertised by more than one server and
+// servers missing from the registry
+func (r *Registry) Check(advertised map[string][]string) []string {
+	var problems []string
+	seen := make(map[string]string)
+
+	servers := make([]string, 0, len(advertised))
+	for server := range advertised {
+		servers = append(servers, server)
+	}
+	sort.Strings(servers)
+
+	for _, server := range servers {
+		if _, ok := r.Namespace(server); !ok {
+			problems = append(problems, fmt.Sprintf("server %s has no namespace in %s", server, ToolsFile))
+			continue
+		}
+		for _, tool := range advertised[server] {
+			if other, ok := seen[tool]; ok {
+				problems = append(problems, fmt.Sprintf("tool %s is advertised by %s and %s", tool, other, server))
+				continue
+			}
+			seen[tool] = server
+			if owner := r.Owner(tool); owner != server {
+				problems = append(problems, fmt.Sprintf("tool %s of %s belongs to the namespace of %s", tool, server, owner))
+			}
+		}
+	}
+	return problems
+}
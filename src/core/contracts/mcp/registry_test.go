@@ -0,0 +1,135 @@
+package mcp
+
+import (
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+const testRegistry = `
+namespaces:
+  - server: commands
+    prefix: r2r-
+    default: true
+  - server: jobs
+    prefix: jobs-
+  - server: shell
+    prefix: shell-
+    tools: [execute-shell]
+`
+
+func TestParse_Valid(t *testing.T) {
+	registry, err := Parse([]byte(testRegistry))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if len(registry.Namespaces) != 3 {
+		t.Errorf("Expected 3 namespaces, got %d", len(registry.Namespaces))
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		yaml    string
+		problem string
+	}{
+		{"duplicate server", "namespaces: [{server: a, prefix: a-}, {server: a, prefix: b-}]", "listed more than once"},
+		{"missing dash", "namespaces: [{server: a, prefix: a}]", "must be a name followed by"},
+		{"overlapping prefixes", "namespaces: [{server: a, prefix: gh-}, {server: b, prefix: gh-pr-}]", "overlap"},
+		{"tool in other prefix", "namespaces: [{server: a, prefix: a-, tools: [b-x]}, {server: b, prefix: b-}]", "in the namespace of b"},
+		{"tool listed twice", "namespaces: [{server: a, prefix: a-, tools: [x]}, {server: b, prefix: b-, tools: [x]}]", "listed by a and b"},
+		{"two defaults", "namespaces: [{server: a, prefix: a-, default: true}, {server: b, prefix: b-, default: true}]", "more than one namespace"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.yaml))
+			if err == nil || !strings.Contains(err.Error(), tt.problem) {
+				t.Errorf("Expected error containing %q, got %v", tt.problem, err)
+			}
+		})
+	}
+}
+
+func TestOwner(t *testing.T) {
+	registry, _ := Parse([]byte(testRegistry))
+
+	tests := map[string]string{
+		"jobs-submit":   "jobs",
+		"execute-shell": "shell",
+		"shell-run":     "shell",
+		"docs-lint":     "commands",
+		"r2r-jobs-list": "commands",
+	}
+	for tool, want := range tests {
+		if got := registry.Owner(tool); got != want {
+			t.Errorf("Owner(%s) = %s, want %s", tool, got, want)
+		}
+	}
+}
+
+func TestToolName(t *testing.T) {
+	registry, _ := Parse([]byte(testRegistry))
+
+	tests := []struct {
+		server, tool, advertised string
+	}{
+		{"commands", "docs-lint", "docs-lint"},
+		{"commands", "jobs-submit", "r2r-jobs-submit"},
+		{"commands", "execute-shell", "r2r-execute-shell"},
+		{"shell", "execute-shell", "execute-shell"},
+		{"shell", "list-shells", "shell-list-shells"},
+		{"jobs", "jobs-list", "jobs-list"},
+		{"unknown", "anything", "anything"},
+	}
+
+	for _, tt := range tests {
+		if got := registry.ToolName(tt.server, tt.tool); got != tt.advertised {
+			t.Errorf("ToolName(%s, %s) = %s, want %s", tt.server, tt.tool, got, tt.advertised)
+		}
+		if got := registry.Names(tt.server, []string{tt.tool})[tt.advertised]; got != tt.tool {
+			t.Errorf("Names(%s, [%s])[%s] = %s, want %s", tt.server, tt.tool, tt.advertised, got, tt.tool)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	registry, _ := Parse([]byte(testRegistry))
+
+	problems := registry.Check(map[string][]string{
+		"commands": {"docs-lint", "jobs-submit"},
+		"jobs":     {"jobs-submit"},
+		"shell":    {"execute-shell", "docs-lint"},
+		"docs":     {"search-docs"},
+	})
+
+	want := []string{
+		"tool jobs-submit of commands belongs to the namespace of jobs",
+		"server docs has no namespace",
+		"tool jobs-submit is advertised by commands and jobs",
+		"tool docs-lint is advertised by commands and shell",
+	}
+	joined := strings.Join(problems, "\n")
+	for _, problem := range want {
+		if !strings.Contains(joined, problem) {
+			t.Errorf("Expected problem %q in:\n%s", problem, joined)
+		}
+	}
+}
+
+func TestLoad_RepositoryRegistry(t *testing.T) {
+	_, file, _, _ := runtime.Caller(0)
+	root := filepath.Join(filepath.Dir(file), "..", "..", "..", "..")
+
+	registry, err := Load(root)
+	if err != nil {
+		t.Fatalf("%s is invalid: %v", ToolsFile, err)
+	}
+	for _, server := range []string{"commands", "github", "jobs", "shell"} {
+		if _, ok := registry.Namespace(server); !ok {
+			t.Errorf("Expected a namespace for %s", server)
+		}
+	}
+}
@@ -35,51 +35,7 @@ func LoadFromWorkspace(workspaceRoot, version string) (*Registry, error) {
 			return err
 		}
 
-		// Apply defaults
-		if base.Type == "" {
-			base.Type = "no-module-type"
-		}
-		if base.Parent == "" {
-			base.Parent = "."
-		}
-		if base.Versioning.VersionScheme == "" {
-			base.Versioning.VersionScheme = "semver"
-		}
-		if base.Description == "" {
-			base.Description = base.Name
-		}
-		if base.Source.ChangelogPath == "" {
-			if base.Source.Root == "/" {
-				base.Source.ChangelogPath = "CHANGELOG.md"
-			} else {
-				base.Source.ChangelogPath = base.Source.Root + "/CHANGELOG.md"
-			}
-		}
-		// Only apply default includes if:
-		// 1. Includes is nil (not set in YAML)
-		// 2. OR it's a catch-all singleton (which needs patterns to work)
-		if base.Source.Includes == nil {
-			base.Source.Includes = []string{"**/*", "*"}
-		} else if len(base.Source.Includes) == 0 {
-			// includes: [] explicitly set - keep it empty (null filter)
-			// UNLESS it's a catch-all singleton which needs patterns
-			if base.Source.IsCatchAllSingleton != nil && *base.Source.IsCatchAllSingleton {
-				base.Source.Includes = []string{"**/*", "*"}
-			}
-		}
-		// ExcludeChildrenOwnedSource defaults to true
-		if base.Source.ExcludeChildrenOwnedSource == nil {
-			trueVal := true
-			base.Source.ExcludeChildrenOwnedSource = &trueVal
-		}
-		// DependsOn defaults to empty list
-		if base.DependsOn == nil {
-			base.DependsOn = []string{}
-		}
-		// UsedBy defaults to empty list
-		if base.UsedBy == nil {
-			base.UsedBy = []string{}
-		}
+		applyDefaults(&base)
 
 		// Validate required fields
 		if base.Moniker == "" {
@@ -144,6 +100,54 @@ func LoadFromWorkspace(workspaceRoot, version string) (*Registry, error) {
 	return registry, nil
 }
 
+// applyDefaults fills in the optional fields of a module contract
+func applyDefaults(base *contracts.BaseContract) {
+	if base.Type == "" {
+		base.Type = "no-module-type"
+	}
+	if base.Parent == "" {
+		base.Parent = "."
+	}
+	if base.Versioning.VersionScheme == "" {
+		base.Versioning.VersionScheme = "semver"
+	}
+	if base.Description == "" {
+		base.Description = base.Name
+	}
+	if base.Source.ChangelogPath == "" {
+		if base.Source.Root == "/" {
+			base.Source.ChangelogPath = "CHANGELOG.md"
+		} else {
+			base.Source.ChangelogPath = base.Source.Root + "/CHANGELOG.md"
+		}
+	}
+	// Only apply default includes if:
+	// 1. Includes is nil (not set in YAML)
+	// 2. OR it's a catch-all singleton (which needs patterns to work)
+	if base.Source.Includes == nil {
+		base.Source.Includes = []string{"**/*", "*"}
+	} else if len(base.Source.Includes) == 0 {
+		// includes: [] explicitly set - keep it empty (null filter)
+		// UNLESS it's a catch-all singleton which needs patterns
+		if base.Source.IsCatchAllSingleton != nil && *base.Source.IsCatchAllSingleton {
+			base.Source.Includes = []string{"**/*", "*"}
+		}
+	}
+	// ExcludeChildrenOwnedSource defaults to true
+	if base.Source.ExcludeChildrenOwnedSource == nil {
+		trueVal := true
+		base.Source.ExcludeChildrenOwnedSource = &trueVal
+	}
+	// DependsOn defaults to empty list
+	if base.DependsOn == nil {
+		base.DependsOn = []string{}
+	}
+	// UsedBy defaults to empty list
+	if base.UsedBy == nil {
+		base.UsedBy = []string{}
+	}
+}
+
 // LoadFromWorkspaceLatest loads module contracts using the latest version
 // This scans the contracts/modules directory to find the highest version
 func LoadFromWorkspaceLatest(workspaceRoot string) (*Registry, error) {
@@ -0,0 +1,300 @@
+package modules
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ready-to-release/eac/src/core/contracts"
+	"gopkg.in/yaml.v3"
+)
+
+// Issue severities
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// Issue is a single problem found in the module contracts
+type Issue struct {
+	Severity   string `json:"severity" yaml:"severity"`
+	Code       string `json:"code" yaml:"code"`
+	Module     string `json:"module,omitempty" yaml:"module,omitempty"`
+	Path       string `json:"path,omitempty" yaml:"path,omitempty"`
+	Message    string `json:"message" yaml:"message"`
+	Suggestion string `json:"suggestion,omitempty" yaml:"suggestion,omitempty"`
+}
+
+// ValidationReport collects the issues found by ValidateWorkspace
+type ValidationReport struct {
+	Modules int     `json:"modules" yaml:"modules"`
+	Files   int     `json:"files" yaml:"files"`
+	Issues  []Issue `json:"issues" yaml:"issues"`
+}
+
+// HasErrors reports whether any issue has error severity
+func (r *ValidationReport) HasErrors() bool {
+	for _, issue := range r.Issues {
+		if issue.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// Count returns the number of issues with the given severity
+func (r *ValidationReport) Count(severity string) int {
+	count := 0
+	for _, issue := range r.Issues {
+		if issue.Severity == severity {
+			count++
+		}
+	}
+	return count
+}
+
+func (r *ValidationReport) add(issue Issue) {
+	r.Issues = append(r.Issues, issue)
+}
+
+// ValidateWorkspace loads every module contract of a version without stopping at
+// the first problem and checks the registry against the repository files:
+// parse errors, filename mismatches, duplicate monikers, dangling parent,
+// depends_on and used_by references, multiple catch-all modules, files owned by
+// more than one module, files owned by no module and modules owning no files.
+// files are repository-relative paths (forward slashes).
+func ValidateWorkspace(workspaceRoot, version string, files []string) (*ValidationReport, error) {
+	report := &ValidationReport{Issues: []Issue{}}
+
+	pattern := filepath.Join(workspaceRoot, "contracts", "modules", version, "*.yml")
+	paths, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("failed to glob %s: %w", pattern, err)
+	}
+	sort.Strings(paths)
+
+	registry := NewRegistry(version, workspaceRoot)
+	definedIn := make(map[string]string) // moniker -> first contract file
+
+	for _, fullPath := range paths {
+		relPath, _ := filepath.Rel(workspaceRoot, fullPath)
+		relPath = filepath.ToSlash(relPath)
+
+		data, err := os.ReadFile(fullPath)
+		if err != nil {
+			report.add(Issue{Severity: SeverityError, Code: "READ_FAILED", Path: relPath, Message: err.Error()})
+			continue
+		}
+
+		var base contracts.BaseContract
+		if err := yaml.Unmarshal(data, &base); err != nil {
+			report.add(Issue{Severity: SeverityError, Code: "PARSE_FAILED", Path: relPath, Message: err.Error()})
+			continue
+		}
+		applyDefaults(&base)
+
+		if base.Moniker == "" {
+			report.add(Issue{Severity: SeverityError, Code: "MISSING_MONIKER", Path: relPath, Message: "moniker field is required"})
+			continue
+		}
+
+		if expected := base.Moniker + ".yml"; path.Base(relPath) != expected {
+			report.add(Issue{
+				Severity:   SeverityError,
+				Code:       "FILENAME_MISMATCH",
+				Module:     base.Moniker,
+				Path:       relPath,
+				Message:    fmt.Sprintf("filename does not match moniker '%s'", base.Moniker),
+				Suggestion: fmt.Sprintf("rename the file to %s", expected),
+			})
+		}
+
+		if first, exists := definedIn[base.Moniker]; exists {
+			report.add(Issue{
+				Severity:   SeverityError,
+				Code:       "DUPLICATE_MONIKER",
+				Module:     base.Moniker,
+				Path:       relPath,
+				Message:    fmt.Sprintf("moniker already defined in %s", first),
+				Suggestion: "give each module a unique moniker",
+			})
+			continue
+		}
+		definedIn[base.Moniker] = relPath
+
+		registry.Add(NewModuleContract(base, workspaceRoot))
+	}
+
+	report.Modules = registry.Count()
+	report.Files = len(files)
+
+	checkReferences(registry, definedIn, report)
+	checkCatchAll(registry, report)
+	checkOwnership(registry, files, report)
+
+	return report, nil
+}
+
+// checkReferences reports parent, depends_on and used_by monikers that do not exist
+func checkReferences(registry *Registry, definedIn map[string]string, report *ValidationReport) {
+	for _, moniker := range registry.AllMonikers() {
+		module, _ := registry.Get(moniker)
+		contractPath := definedIn[moniker]
+
+		if module.Parent != "." && !registry.Has(module.Parent) {
+			report.add(Issue{
+				Severity:   SeverityError,
+				Code:       "DANGLING_PARENT",
+				Module:     module.Moniker,
+				Path:       contractPath,
+				Message:    fmt.Sprintf("parent '%s' does not exist", module.Parent),
+				Suggestion: suggestMoniker(registry, module.Parent),
+			})
+		} else if err := ValidateParentChain(module, registry); err != nil {
+			report.add(Issue{Severity: SeverityError, Code: "INVALID_PARENT_CHAIN", Module: module.Moniker, Path: contractPath, Message: err.Error()})
+		}
+
+		for _, field := range []struct {
+			name  string
+			refs  []string
+			code  string
+			label string
+		}{
+			{"depends_on", module.DependsOn, "DANGLING_DEPENDENCY", "dependency"},
+			{"used_by", module.UsedBy, "DANGLING_USED_BY", "used_by reference"},
+		} {
+			for _, ref := range field.refs {
+				if registry.Has(ref) {
+					continue
+				}
+				report.add(Issue{
+					Severity:   SeverityError,
+					Code:       field.code,
+					Module:     module.Moniker,
+					Path:       contractPath,
+					Message:    fmt.Sprintf("%s '%s' in %s does not exist", field.label, ref, field.name),
+					Suggestion: suggestMoniker(registry, ref),
+				})
+			}
+		}
+	}
+}
+
+// checkCatchAll reports more than one catch-all singleton module
+func checkCatchAll(registry *Registry, report *ValidationReport) {
+	var catchAll []string
+	for _, moniker := range registry.AllMonikers() {
+		module, _ := registry.Get(moniker)
+		if module.Source.IsCatchAllSingleton != nil && *module.Source.IsCatchAllSingleton {
+			catchAll = append(catchAll, moniker)
+		}
+	}
+	if len(catchAll) > 1 {
+		report.add(Issue{
+			Severity:   SeverityError,
+			Code:       "MULTIPLE_CATCH_ALL",
+			Message:    fmt.Sprintf("multiple catch-all singleton modules: %s", strings.Join(catchAll, ", ")),
+			Suggestion: "set is_catch_all_singleton on only one module",
+		})
+	}
+}
+
+// checkOwnership maps every file to its modules and reports overlaps, files
+// left to the catch-all (or to nobody), and modules without files
+func checkOwnership(registry *Registry, files []string, report *ValidationReport) {
+	catchAll := registry.GetCatchAllModule()
+
+	overlaps := make(map[string][]string) // "a, b" -> files
+	unowned := make(map[string][]string)  // top-level directory -> files
+	owned := make(map[string]int)         // moniker -> file count
+
+	for _, file := range files {
+		owners := registry.FindModulesForFile(file)
+		for _, owner := range owners {
+			owned[owner.Moniker]++
+		}
+
+		switch {
+		case len(owners) > 1:
+			var monikers []string
+			for _, owner := range owners {
+				monikers = append(monikers, owner.Moniker)
+			}
+			sort.Strings(monikers)
+			key := strings.Join(monikers, ", ")
+			overlaps[key] = append(overlaps[key], file)
+		case len(owners) == 0 || (catchAll != nil && owners[0] == catchAll):
+			dir := strings.SplitN(file, "/", 2)[0]
+			if !strings.Contains(file, "/") {
+				dir = "/"
+			}
+			unowned[dir] = append(unowned[dir], file)
+		}
+	}
+
+	for _, key := range sortedKeys(overlaps) {
+		matched := overlaps[key]
+		report.add(Issue{
+			Severity:   SeverityWarning,
+			Code:       "OVERLAPPING_PATTERNS",
+			Module:     key,
+			Path:       matched[0],
+			Message:    fmt.Sprintf("%d file(s) owned by more than one module, e.g. %s", len(matched), matched[0]),
+			Suggestion: "narrow the includes or set one module as the parent of the other",
+		})
+	}
+
+	for _, dir := range sortedKeys(unowned) {
+		matched := unowned[dir]
+		message := fmt.Sprintf("%d file(s) under %s are matched by no module, e.g. %s", len(matched), dir, matched[0])
+		if catchAll != nil {
+			message = fmt.Sprintf("%d file(s) under %s fall into catch-all module '%s', e.g. %s", len(matched), dir, catchAll.Moniker, matched[0])
+		}
+		report.add(Issue{
+			Severity:   SeverityWarning,
+			Code:       "UNOWNED_FILES",
+			Path:       dir,
+			Message:    message,
+			Suggestion: "add a module with: module new <name> --path <dir>",
+		})
+	}
+
+	if len(files) == 0 {
+		return
+	}
+	for _, moniker := range registry.AllMonikers() {
+		module, _ := registry.Get(moniker)
+		if owned[moniker] > 0 || module == catchAll || len(module.Source.Includes) == 0 {
+			continue
+		}
+		report.add(Issue{
+			Severity:   SeverityWarning,
+			Code:       "EMPTY_MODULE",
+			Module:     moniker,
+			Message:    "module owns no files",
+			Suggestion: fmt.Sprintf("check source.root '%s' and includes", module.Source.Root),
+		})
+	}
+}
+
+// suggestMoniker proposes an existing moniker that contains or is contained in ref
+func suggestMoniker(registry *Registry, ref string) string {
+	for _, moniker := range registry.AllMonikers() {
+		if strings.Contains(moniker, ref) || strings.Contains(ref, moniker) {
+			return fmt.Sprintf("did you mean '%s'?", moniker)
+		}
+	}
+	return ""
+}
+
+func sortedKeys(m map[string][]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
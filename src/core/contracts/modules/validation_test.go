@@ -0,0 +1,75 @@
+package modules
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeContract(t *testing.T, root, name, content string) {
+	t.Helper()
+	dir := filepath.Join(root, "contracts", "modules", "0.1.0")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func issueCodes(report *ValidationReport) map[string]int {
+	codes := make(map[string]int)
+	for _, issue := range report.Issues {
+		codes[issue.Code]++
+	}
+	return codes
+}
+
+func TestValidateWorkspace_Clean(t *testing.T) {
+	root := t.TempDir()
+	writeContract(t, root, "src-cli.yml", "moniker: src-cli\nsource:\n  root: src/cli\n  includes: ['**/*.go']\n")
+	writeContract(t, root, "src-core.yml", "moniker: src-core\nsource:\n  root: src/core\n  includes: ['**/*.go']\ndepends_on: []\n")
+
+	report, err := ValidateWorkspace(root, "0.1.0", []string{"src/cli/main.go", "src/core/core.go"})
+	if err != nil {
+		t.Fatalf("ValidateWorkspace() error = %v", err)
+	}
+	if len(report.Issues) != 0 {
+		t.Errorf("expected no issues, got %+v", report.Issues)
+	}
+	if report.Modules != 2 || report.Files != 2 {
+		t.Errorf("unexpected counts: %+v", report)
+	}
+}
+
+func TestValidateWorkspace_Problems(t *testing.T) {
+	root := t.TempDir()
+	writeContract(t, root, "src-cli.yml", "moniker: src-cli\nsource:\n  root: src/cli\n  includes: ['**/*.go']\ndepends_on: [src-cor]\n")
+	writeContract(t, root, "x-src-cli.yml", "moniker: src-cli\nsource:\n  root: src/cli\n")
+	writeContract(t, root, "src-core.yml", "moniker: src-core\nsource:\n  root: src/core\n  includes: ['**/*.go']\n")
+	writeContract(t, root, "src-core-tools.yml", "moniker: src-core-tools\nparent: missing\nsource:\n  root: src\n  includes: ['**/core.go']\n  exclude_children_owned_source: false\n")
+	writeContract(t, root, "empty.yml", "moniker: empty\nsource:\n  root: src/empty\n  includes: ['**/*.go']\n")
+	writeContract(t, root, "broken.yml", "moniker: [\n")
+
+	files := []string{"src/cli/main.go", "src/core/core.go", "docs/index.md"}
+	report, err := ValidateWorkspace(root, "0.1.0", files)
+	if err != nil {
+		t.Fatalf("ValidateWorkspace() error = %v", err)
+	}
+
+	codes := issueCodes(report)
+	for _, code := range []string{"PARSE_FAILED", "FILENAME_MISMATCH", "DUPLICATE_MONIKER", "DANGLING_DEPENDENCY", "DANGLING_PARENT", "OVERLAPPING_PATTERNS", "UNOWNED_FILES", "EMPTY_MODULE"} {
+		if codes[code] == 0 {
+			t.Errorf("expected %s issue, got %+v", code, report.Issues)
+		}
+	}
+	if !report.HasErrors() {
+		t.Error("expected HasErrors() to be true")
+	}
+
+	for _, issue := range report.Issues {
+		if issue.Code == "DANGLING_DEPENDENCY" && issue.Suggestion != "did you mean 'src-core'?" {
+			t.Errorf("unexpected suggestion: %q", issue.Suggestion)
+		}
+	}
+}
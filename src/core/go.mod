@@ -7,6 +7,7 @@ require gopkg.in/yaml.v3 v3.0.1
 require (
 	github.com/gobwas/glob v0.2.3
 	github.com/stretchr/testify v1.11.1
+	golang.org/x/sys v0.36.0
 )
 
 require (
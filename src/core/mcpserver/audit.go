@@ -0,0 +1,19 @@
+package mcpserver
+
+import (
+	"fmt"
+
+	"github.com/ready-to-release/eac/src/core/audit"
+)
+
+// FinishAudit writes the audit log entry of a tool call. A failure to write
+// the audit log is reported on Stderr and does not fail the call.
+func FinishAudit(record *audit.Record, failed bool, err error) {
+	code := 0
+	if failed {
+		code = 1
+	}
+	if writeErr := record.Finish(code, err); writeErr != nil {
+		fmt.Fprintf(Stderr, "Failed to write the audit log: %v\n", writeErr)
+	}
+}
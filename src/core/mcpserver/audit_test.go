@@ -0,0 +1,46 @@
+package mcpserver
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ready-to-release/eac/src/core/audit"
+)
+
+// The audit log of the process is resolved once, so this is the only test
+// that writes it
+func TestFinishAudit(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	t.Setenv(audit.PathEnv, path)
+	t.Setenv(audit.DisableEnv, "")
+
+	FinishAudit(audit.StartTool("mcp:test", "ok-tool", nil), false, nil)
+	FinishAudit(audit.StartTool("mcp:test", "bad-tool", nil), true, errors.New("Invalid params"))
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("audit log has %d entries, want 2:\n%s", len(lines), data)
+	}
+
+	var ok, bad audit.Entry
+	if err := json.Unmarshal([]byte(lines[0]), &ok); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal([]byte(lines[1]), &bad); err != nil {
+		t.Fatal(err)
+	}
+	if ok.Source != "mcp:test" || ok.Kind != audit.KindTool || ok.ExitCode != 0 || ok.Error != "" {
+		t.Errorf("entry of a successful call = %+v", ok)
+	}
+	if bad.ExitCode != 1 || bad.Error != "Invalid params" {
+		t.Errorf("entry of a failed call = %+v", bad)
+	}
+}
@@ -0,0 +1,147 @@
+package mcpserver
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"sync"
+	"syscall"
+)
+
+// Server lifecycle: shutdown hooks, child-process tracking and signal handling.
+// Child processes are killed on shutdown so they are not orphaned when the
+// client closes stdin or the server receives SIGINT/SIGTERM.
+
+// Lifecycle tracks the child processes and shutdown hooks of a server
+type Lifecycle struct {
+	mu       sync.Mutex
+	hooks    []func()
+	children map[*exec.Cmd]struct{}
+	once     sync.Once
+	stopping bool
+}
+
+// NewLifecycle returns a lifecycle with no children or hooks
+func NewLifecycle() *Lifecycle {
+	return &Lifecycle{children: make(map[*exec.Cmd]struct{})}
+}
+
+// OnShutdown registers a hook to run on shutdown (in reverse order of registration)
+func (l *Lifecycle) OnShutdown(hook func()) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.hooks = append(l.hooks, hook)
+}
+
+// Shutdown runs the shutdown hooks and kills tracked child processes. Safe to call more than once.
+func (l *Lifecycle) Shutdown() {
+	l.once.Do(func() {
+		l.mu.Lock()
+		l.stopping = true
+		hooks := l.hooks
+		children := make([]*exec.Cmd, 0, len(l.children))
+		for cmd := range l.children {
+			children = append(children, cmd)
+		}
+		l.mu.Unlock()
+
+		for i := len(hooks) - 1; i >= 0; i-- {
+			hooks[i]()
+		}
+
+		for _, cmd := range children {
+			if err := KillProcessTree(cmd); err != nil {
+				fmt.Fprintf(Stderr, "Error killing child process %d: %v\n", cmd.Process.Pid, err)
+			}
+		}
+	})
+}
+
+// Run starts cmd as a tracked child process and waits for it to finish
+func (l *Lifecycle) Run(cmd *exec.Cmd) error {
+	return l.RunContext(context.Background(), cmd)
+}
+
+// RunContext is Run, killing the process tree and returning ctx.Err() when
+// ctx is cancelled first
+func (l *Lifecycle) RunContext(ctx context.Context, cmd *exec.Cmd) error {
+	PrepareProcessTree(cmd)
+
+	l.mu.Lock()
+	if l.stopping {
+		l.mu.Unlock()
+		return fmt.Errorf("server is shutting down")
+	}
+	if err := cmd.Start(); err != nil {
+		l.mu.Unlock()
+		return err
+	}
+	l.children[cmd] = struct{}{}
+	l.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+			if err := KillProcessTree(cmd); err != nil {
+				fmt.Fprintf(Stderr, "Error killing child process %d: %v\n", cmd.Process.Pid, err)
+			}
+		case <-done:
+		}
+	}()
+
+	err := cmd.Wait()
+	close(done)
+
+	l.mu.Lock()
+	delete(l.children, cmd)
+	l.mu.Unlock()
+
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
+	return err
+}
+
+// Output runs cmd as a tracked child process and returns its stdout
+func (l *Lifecycle) Output(cmd *exec.Cmd) ([]byte, error) {
+	var stdout bytes.Buffer
+	cmd.Stdout = &stdout
+	err := l.Run(cmd)
+	return stdout.Bytes(), err
+}
+
+// CombinedOutput runs cmd as a tracked child process and returns stdout and stderr
+func (l *Lifecycle) CombinedOutput(cmd *exec.Cmd) ([]byte, error) {
+	var output bytes.Buffer
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+	err := l.Run(cmd)
+	return output.Bytes(), err
+}
+
+// HandleSignals shuts down and exits on SIGINT/SIGTERM, with the shell's
+// 128+n code of the signal
+func (l *Lifecycle) HandleSignals() {
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+
+	go func() {
+		sig := <-sigChan
+		fmt.Fprintf(Stderr, "Received %v, shutting down\n", sig)
+		l.Shutdown()
+		os.Exit(signalExitCode(sig))
+	}()
+}
+
+// signalExitCode returns the exit code of a process ended by sig: 130 for
+// SIGINT, 143 for SIGTERM
+func signalExitCode(sig os.Signal) int {
+	if sig == syscall.SIGTERM {
+		return 128 + int(syscall.SIGTERM)
+	}
+	return 128 + int(syscall.SIGINT)
+}
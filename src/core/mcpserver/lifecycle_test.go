@@ -0,0 +1,114 @@
+package mcpserver
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func skipOnWindows(t *testing.T) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+}
+
+func TestLifecycleShutdownHooksRunInReverse(t *testing.T) {
+	l := NewLifecycle()
+	var order []string
+	l.OnShutdown(func() { order = append(order, "first") })
+	l.OnShutdown(func() { order = append(order, "second") })
+
+	l.Shutdown()
+	l.Shutdown()
+
+	if strings.Join(order, ",") != "second,first" {
+		t.Errorf("hooks ran as %v, want second,first once", order)
+	}
+}
+
+func TestLifecycleRunAfterShutdown(t *testing.T) {
+	skipOnWindows(t)
+	l := NewLifecycle()
+	l.Shutdown()
+
+	if err := l.Run(exec.Command("sh", "-c", "true")); err == nil {
+		t.Error("expected Run to fail after Shutdown")
+	}
+}
+
+func TestLifecycleOutput(t *testing.T) {
+	skipOnWindows(t)
+	l := NewLifecycle()
+
+	output, err := l.CombinedOutput(exec.Command("sh", "-c", "echo out; echo err >&2"))
+	if err != nil {
+		t.Fatalf("CombinedOutput: %v", err)
+	}
+	if !strings.Contains(string(output), "out") || !strings.Contains(string(output), "err") {
+		t.Errorf("CombinedOutput = %q", output)
+	}
+}
+
+func TestLifecycleShutdownKillsChildren(t *testing.T) {
+	skipOnWindows(t)
+	l := NewLifecycle()
+
+	done := make(chan error, 1)
+	go func() { done <- l.Run(exec.Command("sh", "-c", "sleep 30")) }()
+
+	// Wait for the child to be tracked
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		l.mu.Lock()
+		n := len(l.children)
+		l.mu.Unlock()
+		if n == 1 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("child was not tracked")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	l.Shutdown()
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Error("expected the killed child to fail")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Shutdown did not kill the child")
+	}
+}
+
+func TestLifecycleRunContextCancel(t *testing.T) {
+	skipOnWindows(t)
+	l := NewLifecycle()
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := l.RunContext(ctx, exec.Command("sh", "-c", "sleep 30"))
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("RunContext error = %v, want context.DeadlineExceeded", err)
+	}
+	if time.Since(start) > 5*time.Second {
+		t.Error("RunContext did not kill the child on cancellation")
+	}
+}
+
+func TestSignalExitCode(t *testing.T) {
+	for sig, want := range map[os.Signal]int{os.Interrupt: 130, syscall.SIGTERM: 143} {
+		if got := signalExitCode(sig); got != want {
+			t.Errorf("signalExitCode(%v) = %d, want %d", sig, got, want)
+		}
+	}
+}
@@ -0,0 +1,25 @@
+//go:build !windows
+
+package mcpserver
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// PrepareProcessTree starts the child in its own process group
+func PrepareProcessTree(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+}
+
+// KillProcessTree kills the child's process group, including grandchildren
+// such as the binary spawned by "go run"
+func KillProcessTree(cmd *exec.Cmd) error {
+	if cmd.Process == nil {
+		return nil
+	}
+	if err := syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL); err != nil {
+		return cmd.Process.Kill()
+	}
+	return nil
+}
@@ -0,0 +1,24 @@
+//go:build windows
+
+package mcpserver
+
+import (
+	"os/exec"
+	"strconv"
+)
+
+// PrepareProcessTree needs no setup on Windows, where taskkill /T finds
+// the descendants
+func PrepareProcessTree(cmd *exec.Cmd) {}
+
+// KillProcessTree kills the child and its descendants via taskkill
+func KillProcessTree(cmd *exec.Cmd) error {
+	if cmd.Process == nil {
+		return nil
+	}
+	kill := exec.Command("taskkill", "/T", "/F", "/PID", strconv.Itoa(cmd.Process.Pid))
+	if err := kill.Run(); err != nil {
+		return cmd.Process.Kill()
+	}
+	return nil
+}
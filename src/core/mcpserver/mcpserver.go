@@ -0,0 +1,16 @@
+// Package mcpserver holds the parts the r2r MCP servers (commands, github,
+// jobs and shell) share: the server lifecycle with child-process tracking,
+// the JSON-RPC transport over stdio, the tool names of the namespace
+// registry, the validation and decoding of tool arguments, the audit log
+// entries of tool calls and the sampling endpoint of the commands a server
+// runs.
+package mcpserver
+
+import (
+	"os"
+
+	"github.com/ready-to-release/eac/src/core/redact"
+)
+
+// Stderr is the diagnostics stream of a server, with credentials masked
+var Stderr = redact.NewWriter(os.Stderr)
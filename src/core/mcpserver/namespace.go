@@ -0,0 +1,98 @@
+package mcpserver
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/ready-to-release/eac/src/core/contracts"
+	toolregistry "github.com/ready-to-release/eac/src/core/contracts/mcp"
+	"github.com/ready-to-release/eac/src/core/repository"
+)
+
+// Tool names follow the namespace registry (contracts/mcp/tools.yml): tools
+// outside a server's namespace are advertised with its prefix, so names do
+// not collide with other servers.
+
+// Tool is an MCP tool definition
+type Tool struct {
+	Name        string      `json:"name"`
+	Description string      `json:"description"`
+	InputSchema InputSchema `json:"inputSchema"`
+}
+
+// Namespace maps the tools of one server to the names the registry assigns.
+// The registry of the workspace is read once, on first use; without one the
+// tools keep their names.
+type Namespace struct {
+	server   string
+	load     func() *toolregistry.Registry
+	once     sync.Once
+	registry *toolregistry.Registry
+}
+
+// NewNamespace returns the namespace of server in the registry of the
+// workspace
+func NewNamespace(server string) *Namespace {
+	return &Namespace{server: server, load: loadToolRegistry}
+}
+
+// Advertise returns the tools under the names the registry assigns
+func (n *Namespace) Advertise(tools []Tool) []Tool {
+	registry := n.Registry()
+	if registry == nil {
+		return tools
+	}
+	advertised := make([]Tool, len(tools))
+	for i, tool := range tools {
+		tool.Name = registry.ToolName(n.server, tool.Name)
+		advertised[i] = tool
+	}
+	return advertised
+}
+
+// Resolve maps an advertised name back to the name of one of tools. tools is
+// only called when the registry renames tools.
+func (n *Namespace) Resolve(name string, tools func() []string) string {
+	registry := n.Registry()
+	if registry == nil {
+		return name
+	}
+	if tool, ok := registry.Names(n.server, tools())[name]; ok {
+		return tool
+	}
+	return name
+}
+
+// Registry returns the registry of the workspace, nil without one
+func (n *Namespace) Registry() *toolregistry.Registry {
+	n.once.Do(func() {
+		n.registry = n.load()
+	})
+	return n.registry
+}
+
+// ToolNames returns the names of tools
+func ToolNames(tools []Tool) []string {
+	names := make([]string, len(tools))
+	for i, tool := range tools {
+		names[i] = tool.Name
+	}
+	return names
+}
+
+// loadToolRegistry reads the registry of the workspace. A registry that does
+// not load is reported on Stderr and ignored.
+func loadToolRegistry() *toolregistry.Registry {
+	root, err := repository.FindWorkspaceRoot("")
+	if err != nil {
+		return nil
+	}
+	registry, err := toolregistry.Load(root)
+	if err != nil {
+		if !contracts.IsNotFound(err) {
+			fmt.Fprintf(Stderr, "Ignoring tool registry: %v\n", err)
+		}
+		return nil
+	}
+	return registry
+}
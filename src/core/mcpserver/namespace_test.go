@@ -0,0 +1,85 @@
+package mcpserver
+
+import (
+	"reflect"
+	"testing"
+
+	toolregistry "github.com/ready-to-release/eac/src/core/contracts/mcp"
+)
+
+const testRegistry = `
+namespaces:
+  - server: commands
+    prefix: r2r-
+    default: true
+  - server: jobs
+    prefix: jobs-
+    tools: [list-jobs]
+`
+
+// testNamespace returns the namespace of server in testRegistry, counting
+// the loads of the registry
+func testNamespace(t *testing.T, server string, loads *int) *Namespace {
+	t.Helper()
+	registry, err := toolregistry.Parse([]byte(testRegistry))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &Namespace{server: server, load: func() *toolregistry.Registry {
+		*loads++
+		return registry
+	}}
+}
+
+func TestNamespace(t *testing.T) {
+	loads := 0
+	namespace := testNamespace(t, "jobs", &loads)
+	tools := []Tool{{Name: "list-jobs"}, {Name: "submit"}, {Name: "status"}}
+
+	advertised := namespace.Advertise(tools)
+	if got := ToolNames(advertised); !reflect.DeepEqual(got, []string{"list-jobs", "jobs-submit", "jobs-status"}) {
+		t.Errorf("Advertise() = %v", got)
+	}
+	if tools[1].Name != "submit" {
+		t.Error("Advertise() renamed the tools it was given")
+	}
+
+	names := func() []string { return ToolNames(tools) }
+	for advertised, want := range map[string]string{
+		"jobs-submit": "submit",
+		"list-jobs":   "list-jobs",
+		// Unknown names are left to the server to reject
+		"submit":  "submit",
+		"unknown": "unknown",
+	} {
+		if got := namespace.Resolve(advertised, names); got != want {
+			t.Errorf("Resolve(%q) = %q, want %q", advertised, got, want)
+		}
+	}
+
+	// The registry is read once
+	if loads != 1 {
+		t.Errorf("registry loaded %d times, want 1", loads)
+	}
+}
+
+func TestNamespaceWithoutRegistry(t *testing.T) {
+	loads := 0
+	namespace := &Namespace{server: "jobs", load: func() *toolregistry.Registry {
+		loads++
+		return nil
+	}}
+	tools := []Tool{{Name: "submit"}}
+
+	if got := namespace.Advertise(tools); !reflect.DeepEqual(got, tools) {
+		t.Errorf("Advertise() = %v, want the tools unchanged", got)
+	}
+	// Without a registry the tools are not needed to resolve a name
+	resolved := namespace.Resolve("jobs-submit", func() []string {
+		t.Error("Resolve() listed the tools without a registry")
+		return nil
+	})
+	if resolved != "jobs-submit" || loads != 1 {
+		t.Errorf("Resolve() = %q after %d loads", resolved, loads)
+	}
+}
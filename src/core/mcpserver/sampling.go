@@ -0,0 +1,27 @@
+package mcpserver
+
+// Commands run by a server generate text with the client's model through a
+// sampling endpoint the server serves on the loopback interface for the
+// length of the run. The server passes the endpoint and a token to the
+// command in the environment; a command without them uses its own provider.
+
+const (
+	// SamplingURLEnv holds the URL commands post a SamplingRequest to
+	SamplingURLEnv = "R2R_MCP_SAMPLING_URL"
+	// SamplingTokenEnv holds the bearer token the endpoint expects
+	SamplingTokenEnv = "R2R_MCP_SAMPLING_TOKEN"
+)
+
+// SamplingRequest asks the client to generate a reply to Input
+type SamplingRequest struct {
+	Input     string `json:"input"`
+	Model     string `json:"model,omitempty"` // model hint, e.g. "haiku"
+	MaxTokens int    `json:"max_tokens,omitempty"`
+}
+
+// SamplingResponse is the generated text, or the error of the request
+type SamplingResponse struct {
+	Text  string `json:"text,omitempty"`
+	Model string `json:"model,omitempty"`
+	Error string `json:"error,omitempty"`
+}
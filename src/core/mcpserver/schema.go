@@ -0,0 +1,245 @@
+package mcpserver
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Tool arguments: validation against the tool's InputSchema and decoding into
+// the typed input of its handler. Both report an ArgumentError, which the
+// servers answer with -32602.
+
+// InputSchema is the JSON schema of a tool's arguments
+type InputSchema struct {
+	Type       string              `json:"type"`
+	Properties map[string]Property `json:"properties"`
+	Required   []string            `json:"required,omitempty"`
+}
+
+// Property is the schema of an argument. Items describes the elements of an
+// array; Properties and Required the fields of an object.
+type Property struct {
+	Type        string              `json:"type"`
+	Description string              `json:"description"`
+	Enum        []string            `json:"enum,omitempty"`
+	Items       *Property           `json:"items,omitempty"`
+	Properties  map[string]Property `json:"properties,omitempty"`
+	Required    []string            `json:"required,omitempty"`
+}
+
+// ArgumentError describes an invalid argument, e.g. at
+// "arguments.comments[0].line"
+type ArgumentError struct {
+	Path    string
+	Message string
+}
+
+func (e *ArgumentError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Path, e.Message)
+}
+
+// ValidateArguments checks required fields, types and enums, descending into
+// object properties and array items
+func ValidateArguments(schema InputSchema, args map[string]interface{}) []*ArgumentError {
+	return validateObject("arguments", schema.Properties, schema.Required, args)
+}
+
+func validateObject(path string, properties map[string]Property, required []string, fields map[string]interface{}) []*ArgumentError {
+	var errs []*ArgumentError
+
+	for _, name := range required {
+		if _, ok := fields[name]; !ok {
+			errs = append(errs, &ArgumentError{
+				Path:    path + "." + name,
+				Message: "is required",
+			})
+		}
+	}
+
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		prop, ok := properties[name]
+		if !ok {
+			continue
+		}
+		errs = append(errs, validateValue(path+"."+name, prop, fields[name])...)
+	}
+
+	return errs
+}
+
+func validateValue(path string, prop Property, value interface{}) []*ArgumentError {
+	if !matchesType(prop.Type, value) {
+		return []*ArgumentError{{
+			Path:    path,
+			Message: fmt.Sprintf("expected %s, got %s", prop.Type, jsonTypeName(value)),
+		}}
+	}
+
+	if len(prop.Enum) > 0 && !inEnum(prop.Enum, value) {
+		return []*ArgumentError{{
+			Path:    path,
+			Message: fmt.Sprintf("must be one of: %s", strings.Join(prop.Enum, ", ")),
+		}}
+	}
+
+	switch value := value.(type) {
+	case map[string]interface{}:
+		if prop.Properties != nil || prop.Required != nil {
+			return validateObject(path, prop.Properties, prop.Required, value)
+		}
+	case []interface{}:
+		if prop.Items != nil {
+			var errs []*ArgumentError
+			for i, item := range value {
+				errs = append(errs, validateValue(fmt.Sprintf("%s[%d]", path, i), *prop.Items, item)...)
+			}
+			return errs
+		}
+	}
+	return nil
+}
+
+// FormatArgumentErrors joins argument errors into a single error message
+func FormatArgumentErrors(errs ...*ArgumentError) string {
+	messages := make([]string, len(errs))
+	for i, err := range errs {
+		messages[i] = err.Error()
+	}
+	return "Invalid params: " + strings.Join(messages, "; ")
+}
+
+// DecodeArguments decodes the arguments of a tool call into target, a
+// pointer to the handler's input struct with json tags. A value of the
+// wrong type is reported as an *ArgumentError.
+func DecodeArguments(args map[string]interface{}, target interface{}) error {
+	data, err := json.Marshal(args)
+	if err != nil {
+		return &ArgumentError{Path: "arguments", Message: err.Error()}
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	if err := decoder.Decode(target); err != nil {
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) {
+			return &ArgumentError{
+				Path:    fieldPath(typeErr.Field),
+				Message: fmt.Sprintf("expected %s, got %s", goTypeName(typeErr.Type.String()), typeErr.Value),
+			}
+		}
+		return &ArgumentError{Path: "arguments", Message: err.Error()}
+	}
+	return nil
+}
+
+// Handle decodes the arguments into the input of handler and calls it. Tool
+// servers dispatch each tool through Handle, so handlers take typed input
+// rather than reading the arguments map.
+func Handle[In, Out any](args map[string]interface{}, handler func(In) Out) (Out, error) {
+	var input In
+	if err := DecodeArguments(args, &input); err != nil {
+		var zero Out
+		return zero, err
+	}
+	return handler(input), nil
+}
+
+// fieldPath turns a decode error field ("comments.0.line") into an argument
+// path ("arguments.comments[0].line")
+func fieldPath(field string) string {
+	path := "arguments"
+	if field == "" {
+		return path
+	}
+	for _, name := range strings.Split(field, ".") {
+		if _, err := strconv.Atoi(name); err == nil {
+			path += "[" + name + "]"
+		} else {
+			path += "." + name
+		}
+	}
+	return path
+}
+
+// goTypeName names a Go type of a decode target as its JSON schema type
+func goTypeName(name string) string {
+	name = strings.TrimPrefix(name, "*")
+	switch {
+	case name == "string":
+		return "string"
+	case name == "bool":
+		return "boolean"
+	case strings.HasPrefix(name, "int") || strings.HasPrefix(name, "uint"):
+		return "integer"
+	case strings.HasPrefix(name, "float"):
+		return "number"
+	case strings.HasPrefix(name, "[]"):
+		return "array"
+	}
+	return "object"
+}
+
+func matchesType(schemaType string, value interface{}) bool {
+	switch schemaType {
+	case "", "any":
+		return true
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	case "integer":
+		n, ok := value.(float64)
+		return ok && n == math.Trunc(n)
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "object":
+		_, ok := value.(map[string]interface{})
+		return ok
+	case "array":
+		_, ok := value.([]interface{})
+		return ok
+	}
+	return false
+}
+
+func jsonTypeName(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "boolean"
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+func inEnum(enum []string, value interface{}) bool {
+	s := fmt.Sprint(value)
+	for _, allowed := range enum {
+		if allowed == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,217 @@
+package mcpserver
+
+import (
+	"encoding/json"
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+var reviewSchema = InputSchema{
+	Type: "object",
+	Properties: map[string]Property{
+		"number": {Type: "integer"},
+		"event":  {Type: "string", Enum: []string{"approve", "comment"}},
+		"labels": {Type: "array", Items: &Property{Type: "string"}},
+		"comments": {
+			Type: "array",
+			Items: &Property{
+				Type: "object",
+				Properties: map[string]Property{
+					"path": {Type: "string"},
+					"line": {Type: "integer"},
+				},
+				Required: []string{"path", "line"},
+			},
+		},
+		"payload": {Type: "object"},
+	},
+	Required: []string{"number", "event"},
+}
+
+// parseArguments decodes a JSON object as the arguments of a tool call
+func parseArguments(t *testing.T, text string) map[string]interface{} {
+	t.Helper()
+	var args map[string]interface{}
+	if err := json.Unmarshal([]byte(text), &args); err != nil {
+		t.Fatal(err)
+	}
+	return args
+}
+
+func TestValidateArguments(t *testing.T) {
+	tests := []struct {
+		name string
+		args string
+		want string
+	}{
+		{
+			name: "valid",
+			args: `{"number":1,"event":"approve","labels":["a"],"comments":[{"path":"a.go","line":3}],"payload":{"any":[1]}}`,
+		},
+		{
+			name: "unknown arguments are ignored",
+			args: `{"number":1,"event":"approve","extra":true}`,
+		},
+		{
+			name: "missing required",
+			args: `{}`,
+			want: "arguments.number: is required; arguments.event: is required",
+		},
+		{
+			name: "wrong type",
+			args: `{"number":"1","event":"approve"}`,
+			want: "arguments.number: expected integer, got string",
+		},
+		{
+			name: "fractional integer",
+			args: `{"number":1.5,"event":"approve"}`,
+			want: "arguments.number: expected integer, got number",
+		},
+		{
+			name: "not in enum",
+			args: `{"number":1,"event":"merge"}`,
+			want: "arguments.event: must be one of: approve, comment",
+		},
+		{
+			name: "array item type",
+			args: `{"number":1,"event":"approve","labels":["a",2]}`,
+			want: "arguments.labels[1]: expected string, got number",
+		},
+		{
+			name: "nested required and type",
+			args: `{"number":1,"event":"approve","comments":[{"path":"a.go","line":1},{"line":"2"}]}`,
+			want: "arguments.comments[1].path: is required; arguments.comments[1].line: expected integer, got string",
+		},
+		{
+			name: "array item not an object",
+			args: `{"number":1,"event":"approve","comments":["a.go:1"]}`,
+			want: "arguments.comments[0]: expected object, got string",
+		},
+		{
+			name: "null is not a string",
+			args: `{"number":1,"event":null}`,
+			want: "arguments.event: expected string, got null",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := ValidateArguments(reviewSchema, parseArguments(t, tt.args))
+			if tt.want == "" {
+				if len(errs) > 0 {
+					t.Errorf("ValidateArguments = %s, want no errors", FormatArgumentErrors(errs...))
+				}
+				return
+			}
+			if got := FormatArgumentErrors(errs...); got != "Invalid params: "+tt.want {
+				t.Errorf("ValidateArguments = %q, want %q", got, "Invalid params: "+tt.want)
+			}
+		})
+	}
+}
+
+type reviewInput struct {
+	Number   int      `json:"number"`
+	Event    string   `json:"event"`
+	Labels   []string `json:"labels"`
+	Approved *bool    `json:"approved"`
+	Comments []struct {
+		Path string `json:"path"`
+		Line int    `json:"line"`
+	} `json:"comments"`
+	Payload map[string]interface{} `json:"payload"`
+}
+
+func TestDecodeArguments(t *testing.T) {
+	args := parseArguments(t, `{"number":7,"event":"comment","labels":["x","y"],"approved":false,"comments":[{"path":"a.go","line":3}],"payload":{"k":"v"},"extra":1}`)
+
+	var input reviewInput
+	if err := DecodeArguments(args, &input); err != nil {
+		t.Fatal(err)
+	}
+	if input.Number != 7 || input.Event != "comment" || strings.Join(input.Labels, ",") != "x,y" {
+		t.Errorf("input = %+v", input)
+	}
+	if input.Approved == nil || *input.Approved {
+		t.Errorf("approved = %v, want a pointer to false", input.Approved)
+	}
+	if len(input.Comments) != 1 || input.Comments[0].Path != "a.go" || input.Comments[0].Line != 3 {
+		t.Errorf("comments = %+v", input.Comments)
+	}
+	if input.Payload["k"] != "v" {
+		t.Errorf("payload = %v", input.Payload)
+	}
+
+	// Leaving out an optional argument leaves the zero value
+	input = reviewInput{}
+	if err := DecodeArguments(nil, &input); err != nil || input.Approved != nil || input.Number != 0 {
+		t.Errorf("DecodeArguments(nil) = %+v, %v", input, err)
+	}
+}
+
+var arrayIndex = regexp.MustCompile(`\[\d+\]`)
+
+func TestDecodeArgumentsErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		args string
+		want string
+	}{
+		{name: "string for integer", args: `{"number":"7"}`, want: "arguments.number: expected integer, got string"},
+		{name: "fraction for integer", args: `{"number":7.5}`, want: "arguments.number: expected integer, got number 7.5"},
+		{name: "object for string", args: `{"event":{}}`, want: "arguments.event: expected string, got object"},
+		{name: "array item", args: `{"labels":["x",1]}`, want: "arguments.labels: expected string, got number"},
+		{name: "nested field", args: `{"comments":[{"line":"3"}]}`, want: "arguments.comments.line: expected integer, got string"},
+		{name: "string for array", args: `{"labels":"x"}`, want: "arguments.labels: expected array, got string"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var input reviewInput
+			err := DecodeArguments(parseArguments(t, tt.args), &input)
+			var argErr *ArgumentError
+			if !errors.As(err, &argErr) {
+				t.Fatalf("DecodeArguments = %v, want an ArgumentError", err)
+			}
+			// Older encoding/json leaves the array index out of the field
+			got := arrayIndex.ReplaceAllString(argErr.Error(), "")
+			if got != tt.want {
+				t.Errorf("DecodeArguments = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHandle(t *testing.T) {
+	handler := func(input reviewInput) string { return input.Event }
+
+	got, err := Handle(parseArguments(t, `{"event":"approve"}`), handler)
+	if err != nil || got != "approve" {
+		t.Errorf("Handle = %q, %v; want approve", got, err)
+	}
+
+	called := false
+	_, err = Handle(parseArguments(t, `{"event":1}`), func(input reviewInput) string {
+		called = true
+		return ""
+	})
+	var argErr *ArgumentError
+	if !errors.As(err, &argErr) || called {
+		t.Errorf("Handle = %v (handler called: %v), want an ArgumentError without calling the handler", err, called)
+	}
+}
+
+func TestFieldPath(t *testing.T) {
+	for field, want := range map[string]string{
+		"":                "arguments",
+		"number":          "arguments.number",
+		"comments.0.line": "arguments.comments[0].line",
+		"labels.1":        "arguments.labels[1]",
+	} {
+		if got := fieldPath(field); got != want {
+			t.Errorf("fieldPath(%q) = %q, want %q", field, got, want)
+		}
+	}
+}
@@ -0,0 +1,172 @@
+package mcpserver
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/ready-to-release/eac/src/core/redact"
+)
+
+// JSON-RPC transport over stdio. Accepts newline-delimited messages and
+// LSP-style Content-Length framing. Responses use the framing of the
+// last received message.
+
+// MaxMessageSize is the largest accepted message (32MB)
+const MaxMessageSize = 32 << 20
+
+// ErrMessageTooLarge is returned for messages over MaxMessageSize
+var ErrMessageTooLarge = errors.New("message too large")
+
+// ErrInvalidHeader is returned for malformed Content-Length headers
+var ErrInvalidHeader = errors.New("invalid header")
+
+// MessageReader reads messages and switches w to the framing of each one
+type MessageReader struct {
+	reader  *bufio.Reader
+	writer  *MessageWriter
+	maxSize int
+}
+
+// NewMessageReader reads messages of up to MaxMessageSize from r
+func NewMessageReader(r io.Reader, w *MessageWriter) *MessageReader {
+	return &MessageReader{reader: bufio.NewReaderSize(r, 64*1024), writer: w, maxSize: MaxMessageSize}
+}
+
+// ReadMessage returns the next message body. Blank lines are skipped.
+// On ErrMessageTooLarge the oversize message has been consumed and reading can continue.
+func (m *MessageReader) ReadMessage() ([]byte, error) {
+	for {
+		line, err := m.readLine()
+		if err != nil && !(err == io.EOF && len(line) > 0) {
+			return nil, err
+		}
+
+		trimmed := bytes.TrimSpace(line)
+		if len(trimmed) == 0 {
+			if err == io.EOF {
+				return nil, err
+			}
+			continue
+		}
+
+		if bytes.HasPrefix(bytes.ToLower(trimmed), []byte("content-length:")) {
+			m.writer.SetFramed(true)
+			return m.readFramed(trimmed)
+		}
+
+		m.writer.SetFramed(false)
+		return trimmed, nil
+	}
+}
+
+// readLine reads a full line, discarding it if it exceeds maxSize
+func (m *MessageReader) readLine() ([]byte, error) {
+	var line []byte
+	for {
+		chunk, err := m.reader.ReadSlice('\n')
+		if len(line)+len(chunk) > m.maxSize {
+			if err == bufio.ErrBufferFull {
+				m.discardLine()
+			}
+			return nil, fmt.Errorf("%w: exceeds %d bytes", ErrMessageTooLarge, m.maxSize)
+		}
+		line = append(line, chunk...)
+		if err == bufio.ErrBufferFull {
+			continue
+		}
+		return line, err
+	}
+}
+
+func (m *MessageReader) discardLine() {
+	for {
+		_, err := m.reader.ReadSlice('\n')
+		if err != bufio.ErrBufferFull {
+			return
+		}
+	}
+}
+
+// readFramed reads the remaining headers and the Content-Length body
+func (m *MessageReader) readFramed(firstHeader []byte) ([]byte, error) {
+	value := strings.TrimSpace(string(firstHeader[len("content-length:"):]))
+	length, err := strconv.Atoi(value)
+	if err != nil || length < 0 {
+		return nil, fmt.Errorf("%w: Content-Length %q", ErrInvalidHeader, value)
+	}
+
+	// Skip other headers (e.g. Content-Type) up to the blank line
+	for {
+		line, err := m.readLine()
+		if err != nil {
+			return nil, err
+		}
+		if len(bytes.TrimSpace(line)) == 0 {
+			break
+		}
+	}
+
+	if length > m.maxSize {
+		if _, err := io.CopyN(io.Discard, m.reader, int64(length)); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w: %d bytes exceeds %d bytes", ErrMessageTooLarge, length, m.maxSize)
+	}
+
+	body := make([]byte, length)
+	if _, err := io.ReadFull(m.reader, body); err != nil {
+		return nil, err
+	}
+	return body, nil
+}
+
+// MessageWriter writes each encoded message, adding a Content-Length header in framed mode
+type MessageWriter struct {
+	w      io.Writer
+	framed atomic.Bool
+	// mu keeps the header and body of a message together when handlers
+	// write concurrently
+	mu sync.Mutex
+}
+
+// NewMessageWriter writes newline-delimited messages to w until SetFramed
+func NewMessageWriter(w io.Writer) *MessageWriter {
+	return &MessageWriter{w: w}
+}
+
+// SetFramed switches between Content-Length framing and newline-delimited messages
+func (m *MessageWriter) SetFramed(framed bool) {
+	m.framed.Store(framed)
+}
+
+// Write expects one complete message per call, as produced by json.Encoder.Encode,
+// and is safe for concurrent use. Credentials in tool output and errors are masked before the message leaves
+// the server; masking keeps the JSON valid.
+func (m *MessageWriter) Write(p []byte) (int, error) {
+	n := len(p)
+	p = redact.Bytes(p)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !m.framed.Load() {
+		if _, err := m.w.Write(p); err != nil {
+			return 0, err
+		}
+		return n, nil
+	}
+
+	body := bytes.TrimRight(p, "\n")
+	if _, err := fmt.Fprintf(m.w, "Content-Length: %d\r\n\r\n", len(body)); err != nil {
+		return 0, err
+	}
+	if _, err := m.w.Write(body); err != nil {
+		return 0, err
+	}
+	return n, nil
+}
@@ -0,0 +1,203 @@
+package mcpserver
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+// readAll reads messages until EOF and returns each body, or "error: <err>"
+// for a message that failed, with the framing after each message
+func readAll(t *testing.T, r io.Reader, maxSize int) ([]string, []bool) {
+	t.Helper()
+	writer := NewMessageWriter(io.Discard)
+	reader := NewMessageReader(r, writer)
+	if maxSize > 0 {
+		reader.maxSize = maxSize
+	}
+
+	var messages []string
+	var framed []bool
+	for i := 0; i < 100; i++ {
+		body, err := reader.ReadMessage()
+		if err == io.EOF {
+			return messages, framed
+		}
+		if err != nil {
+			messages = append(messages, "error: "+err.Error())
+			if !errors.Is(err, ErrMessageTooLarge) && !errors.Is(err, ErrInvalidHeader) {
+				return messages, framed
+			}
+		} else {
+			messages = append(messages, string(body))
+		}
+		framed = append(framed, writer.framed.Load())
+	}
+	t.Fatal("ReadMessage did not reach EOF")
+	return nil, nil
+}
+
+func TestMessageReader(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		maxSize int
+		oneByte bool // deliver the input one byte per read, splitting every header
+		want    []string
+		framed  []bool
+	}{
+		{
+			name:   "newline delimited",
+			input:  "{\"id\":1}\n{\"id\":2}\n",
+			want:   []string{`{"id":1}`, `{"id":2}`},
+			framed: []bool{false, false},
+		},
+		{
+			name:   "blank lines and CRLF are skipped",
+			input:  "\n\r\n  {\"id\":1}\r\n\n",
+			want:   []string{`{"id":1}`},
+			framed: []bool{false},
+		},
+		{
+			name:   "last message without newline",
+			input:  `{"id":1}`,
+			want:   []string{`{"id":1}`},
+			framed: []bool{false},
+		},
+		{
+			name:   "content-length framing",
+			input:  "Content-Length: 8\r\n\r\n{\"id\":1}Content-Length: 8\r\n\r\n{\"id\":2}",
+			want:   []string{`{"id":1}`, `{"id":2}`},
+			framed: []bool{true, true},
+		},
+		{
+			name:   "header case and extra headers",
+			input:  "content-length: 8\r\nContent-Type: application/vscode-jsonrpc; charset=utf-8\r\n\r\n{\"id\":1}",
+			want:   []string{`{"id":1}`},
+			framed: []bool{true},
+		},
+		{
+			name:   "body with newlines is read by length",
+			input:  "Content-Length: 10\r\n\r\n{\n\"id\":1\n}",
+			want:   []string{"{\n\"id\":1\n}"},
+			framed: []bool{true},
+		},
+		{
+			name:    "split headers",
+			input:   "Content-Length: 8\r\nContent-Type: application/json\r\n\r\n{\"id\":1}\n{\"id\":2}\n",
+			oneByte: true,
+			want:    []string{`{"id":1}`, `{"id":2}`},
+			framed:  []bool{true, false},
+		},
+		{
+			name:   "mixed framing follows each message",
+			input:  "{\"id\":1}\nContent-Length: 8\n\n{\"id\":2}\n{\"id\":3}\n",
+			want:   []string{`{"id":1}`, `{"id":2}`, `{"id":3}`},
+			framed: []bool{false, true, false},
+		},
+		{
+			name:   "non-numeric length",
+			input:  "Content-Length: abc\r\n\r\n{\"id\":2}\n",
+			want:   []string{`error: invalid header: Content-Length "abc"`, `{"id":2}`},
+			framed: []bool{true, false},
+		},
+		{
+			name:   "negative length",
+			input:  "Content-Length: -5\r\n\r\n",
+			want:   []string{`error: invalid header: Content-Length "-5"`},
+			framed: []bool{true},
+		},
+		{
+			name:  "body shorter than its length",
+			input: "Content-Length: 20\r\n\r\n{\"id\":1}",
+			want:  []string{"error: unexpected EOF"},
+		},
+		{
+			name:    "oversized frame is skipped",
+			input:   "Content-Length: 24\r\n\r\n{\"id\":1,\"pad\":\"xxxxxxx\"}Content-Length: 8\r\n\r\n{\"id\":2}",
+			maxSize: 20,
+			want:    []string{"error: message too large: 24 bytes exceeds 20 bytes", `{"id":2}`},
+			framed:  []bool{true, true},
+		},
+		{
+			name:    "oversized line is skipped",
+			input:   "{\"id\":1,\"pad\":\"xxxxxxxx\"}\n{\"id\":2}\n",
+			maxSize: 16,
+			want:    []string{"error: message too large: exceeds 16 bytes", `{"id":2}`},
+			framed:  []bool{false, false},
+		},
+		{
+			name:    "oversized line beyond the read buffer is discarded",
+			input:   "{\"pad\":\"" + strings.Repeat("x", 200*1024) + "\"}\n{\"id\":2}\n",
+			maxSize: 1024,
+			want:    []string{"error: message too large: exceeds 1024 bytes", `{"id":2}`},
+			framed:  []bool{false, false},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var r io.Reader = strings.NewReader(tt.input)
+			if tt.oneByte {
+				r = iotest.OneByteReader(r)
+			}
+
+			got, framed := readAll(t, r, tt.maxSize)
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("messages = %q, want %q", got, tt.want)
+			}
+			if tt.framed != nil && len(framed) == len(tt.framed) {
+				for i := range framed {
+					if framed[i] != tt.framed[i] {
+						t.Errorf("message %d framed = %v, want %v", i, framed[i], tt.framed[i])
+					}
+				}
+			} else if tt.framed != nil {
+				t.Errorf("framing = %v, want %v", framed, tt.framed)
+			}
+		})
+	}
+}
+
+func TestMessageWriter(t *testing.T) {
+	var out bytes.Buffer
+	writer := NewMessageWriter(&out)
+
+	if _, err := writer.Write([]byte("{\"id\":1}\n")); err != nil {
+		t.Fatal(err)
+	}
+	writer.SetFramed(true)
+	if _, err := writer.Write([]byte("{\"id\":2}\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "{\"id\":1}\nContent-Length: 8\r\n\r\n{\"id\":2}"
+	if out.String() != want {
+		t.Errorf("output = %q, want %q", out.String(), want)
+	}
+}
+
+func TestMessageWriterRedacts(t *testing.T) {
+	var out bytes.Buffer
+	writer := NewMessageWriter(&out)
+	writer.SetFramed(true)
+
+	token := "ghp_" + strings.Repeat("a", 36)
+	message := "{\"text\":\"token " + token + "\"}\n"
+	n, err := writer.Write([]byte(message))
+	if err != nil || n != len(message) {
+		t.Fatalf("Write = %d, %v; want %d, nil", n, err, len(message))
+	}
+	if strings.Contains(out.String(), token) {
+		t.Errorf("token was not masked: %q", out.String())
+	}
+
+	// The Content-Length header matches the masked body
+	body, err := NewMessageReader(&out, NewMessageWriter(io.Discard)).ReadMessage()
+	if err != nil || !strings.Contains(string(body), "***") {
+		t.Errorf("ReadMessage = %q, %v", body, err)
+	}
+}
@@ -0,0 +1,224 @@
+// Package redact masks credentials in text before it reaches logs, error
+// messages or MCP clients. It replaces the values of secret environment
+// variables, secrets registered at runtime (such as tokens resolved from the
+// GitHub CLI) and well-known token shapes: bearer tokens, GitHub tokens,
+// Authorization headers, passwords in URLs and on command lines.
+package redact
+
+import (
+	"cmp"
+	"encoding/json"
+	"io"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Mask replaces every redacted secret
+const Mask = "***"
+
+// minSecretLength is the shortest value of a secret environment variable
+// that is masked. Shorter values ("1", "true") would mask unrelated text.
+const minSecretLength = 8
+
+// secretNameParts mark an environment variable as holding a secret when its
+// upper-cased name contains one of them
+var secretNameParts = []string{"TOKEN", "SECRET", "PASSWORD", "PASSWD", "API_KEY", "APIKEY", "PRIVATE_KEY", "ACCESS_KEY", "CREDENTIAL"}
+
+// patterns mask secrets by shape. Each keeps its first group, the part that
+// says what the secret is, and its optional second group after the secret.
+// None of them match quotes or backslashes, so redacting encoded JSON keeps
+// it valid.
+var patterns = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)(authorization:\s*(?:bearer\s+|basic\s+|token\s+)?)[^\s"'\\,;]+`),
+	regexp.MustCompile(`(?i)(\bbearer\s+)[A-Za-z0-9\-._~+/]{8,}=*`),
+	regexp.MustCompile(`()\b(?:gh[pousr]_[A-Za-z0-9]{20,}|github_pat_[A-Za-z0-9_]{20,})\b`),
+	regexp.MustCompile(`(://[^\s:/@"'\\]+:)[^\s/@"'\\]+(@)`),
+	regexp.MustCompile(`(?i)((?:^|\s)--?(?:token|password|passwd|secret|api-key)(?:=|\s+))[^\s"'\\]+`),
+}
+
+// Redactor masks a set of known secret values and the secret patterns. It is
+// safe for concurrent use.
+type Redactor struct {
+	mu       sync.RWMutex
+	secrets  map[string]bool
+	replacer *strings.Replacer
+}
+
+// New returns a redactor masking the given secret values
+func New(secrets ...string) *Redactor {
+	r := &Redactor{secrets: make(map[string]bool)}
+	r.Add(secrets...)
+	return r
+}
+
+// FromEnv returns a redactor masking the values of the secret variables in
+// environ, which is in the form of os.Environ
+func FromEnv(environ []string) *Redactor {
+	var secrets []string
+	for _, kv := range environ {
+		name, value, ok := strings.Cut(kv, "=")
+		if ok && IsSecretName(name) && len(value) >= minSecretLength {
+			secrets = append(secrets, value)
+		}
+	}
+	return New(secrets...)
+}
+
+// IsSecretName reports whether an environment variable of this name holds a
+// secret, such as GITHUB_TOKEN or DOCKER_PASSWORD
+func IsSecretName(name string) bool {
+	upper := strings.ToUpper(name)
+	for _, part := range secretNameParts {
+		if strings.Contains(upper, part) {
+			return true
+		}
+	}
+	return false
+}
+
+// Add registers more secret values. Values shorter than the minimum length
+// of environment secrets are still added: callers know they are secrets.
+func (r *Redactor) Add(secrets ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	changed := false
+	for _, secret := range secrets {
+		secret = strings.TrimSpace(secret)
+		if secret == "" || r.secrets[secret] {
+			continue
+		}
+		r.secrets[secret] = true
+		// Match the secret inside encoded JSON too
+		if encoded, err := json.Marshal(secret); err == nil {
+			if escaped := string(encoded[1 : len(encoded)-1]); escaped != secret {
+				r.secrets[escaped] = true
+			}
+		}
+		changed = true
+	}
+	if !changed {
+		return
+	}
+
+	// Longer secrets first, so a secret containing another is masked whole
+	values := make([]string, 0, len(r.secrets))
+	for secret := range r.secrets {
+		values = append(values, secret)
+	}
+	slices.SortFunc(values, func(a, b string) int {
+		return cmp.Or(cmp.Compare(len(b), len(a)), cmp.Compare(a, b))
+	})
+	pairs := make([]string, 0, 2*len(values))
+	for _, secret := range values {
+		pairs = append(pairs, secret, Mask)
+	}
+	r.replacer = strings.NewReplacer(pairs...)
+}
+
+// String returns s with all secrets masked
+func (r *Redactor) String(s string) string {
+	r.mu.RLock()
+	replacer := r.replacer
+	r.mu.RUnlock()
+
+	if replacer != nil {
+		s = replacer.Replace(s)
+	}
+	for _, pattern := range patterns {
+		s = pattern.ReplaceAllString(s, "${1}"+Mask+"${2}")
+	}
+	return s
+}
+
+// Bytes returns p with all secrets masked. p is not modified.
+func (r *Redactor) Bytes(p []byte) []byte {
+	return []byte(r.String(string(p)))
+}
+
+// Error returns err with its message redacted. The returned error still
+// unwraps to err, so errors.Is and errors.As keep working.
+func (r *Redactor) Error(err error) error {
+	if err == nil {
+		return nil
+	}
+	msg := err.Error()
+	redacted := r.String(msg)
+	if redacted == msg {
+		return err
+	}
+	return &redactedError{msg: redacted, err: err}
+}
+
+type redactedError struct {
+	msg string
+	err error
+}
+
+func (e *redactedError) Error() string { return e.msg }
+func (e *redactedError) Unwrap() error { return e.err }
+
+// Writer returns a writer masking secrets in each write before passing it to
+// w. Secrets are only found within a single write, which holds for loggers
+// writing one entry per call.
+func (r *Redactor) Writer(w io.Writer) io.Writer {
+	return &Writer{w: w, r: r}
+}
+
+// Writer masks secrets in what is written through it
+type Writer struct {
+	w io.Writer
+	r *Redactor
+}
+
+// Write writes the redacted p. It reports len(p) on success, as the caller
+// wrote all of p even when fewer bytes reached the underlying writer.
+func (w *Writer) Write(p []byte) (int, error) {
+	if _, err := w.w.Write(w.r.Bytes(p)); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+var (
+	defaultRedactor     *Redactor
+	defaultRedactorOnce sync.Once
+)
+
+// Default returns the redactor of the process, masking the secret variables
+// of its environment at first use and the secrets added with AddSecret
+func Default() *Redactor {
+	defaultRedactorOnce.Do(func() {
+		defaultRedactor = FromEnv(os.Environ())
+	})
+	return defaultRedactor
+}
+
+// AddSecret registers secret values with the default redactor, for secrets
+// that are not in the environment
+func AddSecret(secrets ...string) {
+	Default().Add(secrets...)
+}
+
+// String masks secrets in s with the default redactor
+func String(s string) string {
+	return Default().String(s)
+}
+
+// Bytes masks secrets in p with the default redactor
+func Bytes(p []byte) []byte {
+	return Default().Bytes(p)
+}
+
+// Error redacts the message of err with the default redactor
+func Error(err error) error {
+	return Default().Error(err)
+}
+
+// NewWriter wraps w to mask secrets with the default redactor
+func NewWriter(w io.Writer) io.Writer {
+	return Default().Writer(w)
+}
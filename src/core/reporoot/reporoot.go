@@ -0,0 +1,175 @@
+// Package reporoot resolves the root of the repository the CLI, the commands
+// and the MCP servers work in. It is the single implementation of "find the
+// repository root": R2R_REPO_ROOT overrides it, and otherwise the nearest
+// directory holding a .git entry is the root, where .git may be a directory
+// (a repository), or a file pointing to the git directory (a worktree or a
+// submodule). It does not run git, so it also works in containers without
+// git and with the placeholder .git directory of `r2r init --use-pwd-as-root`.
+package reporoot
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// OverrideEnv names the environment variable that sets the repository root,
+// skipping detection
+const OverrideEnv = "R2R_REPO_ROOT"
+
+// ErrNotFound is returned when no directory above the start holds a .git
+// entry
+var ErrNotFound = errors.New("not a git repository (or any parent up to mount point)")
+
+// Kind is the kind of checkout a root is
+type Kind int
+
+const (
+	KindRepository Kind = iota // a repository with its own .git directory
+	KindWorktree               // a linked worktree of `git worktree add`
+	KindSubmodule              // a submodule checked out in a superproject
+	KindOverride               // set with R2R_REPO_ROOT
+)
+
+// String returns the name of the kind
+func (k Kind) String() string {
+	switch k {
+	case KindWorktree:
+		return "worktree"
+	case KindSubmodule:
+		return "submodule"
+	case KindOverride:
+		return "override"
+	default:
+		return "repository"
+	}
+}
+
+// Root is a resolved repository root
+type Root struct {
+	Path         string // absolute path of the working tree
+	GitDir       string // absolute path of the git directory, empty when unknown
+	Kind         Kind
+	Superproject string // root of the superproject of a submodule
+}
+
+// Find returns the repository root containing start, or the current
+// directory when start is empty
+func Find(start string) (string, error) {
+	root, err := Resolve(start)
+	if err != nil {
+		return "", err
+	}
+	return root.Path, nil
+}
+
+// Resolve returns the repository root containing start, or the current
+// directory when start is empty, with what kind of checkout it is
+func Resolve(start string) (*Root, error) {
+	if override := os.Getenv(OverrideEnv); override != "" {
+		return resolveOverride(override)
+	}
+	return Detect(start)
+}
+
+// Detect resolves the root like Resolve, ignoring R2R_REPO_ROOT
+func Detect(start string) (*Root, error) {
+	if start == "" {
+		wd, err := os.Getwd()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get current directory: %w", err)
+		}
+		start = wd
+	}
+	abs, err := filepath.Abs(start)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get absolute path of %s: %w", start, err)
+	}
+
+	for dir := filepath.Clean(abs); ; {
+		if root, ok := rootAt(dir); ok {
+			return root, nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return nil, fmt.Errorf("%w: %s", ErrNotFound, abs)
+		}
+		dir = parent
+	}
+}
+
+func resolveOverride(path string) (*Root, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s %q: %w", OverrideEnv, path, err)
+	}
+	if info, err := os.Stat(abs); err != nil || !info.IsDir() {
+		return nil, fmt.Errorf("%s=%s is not a directory", OverrideEnv, path)
+	}
+	root := &Root{Path: filepath.Clean(abs), Kind: KindOverride}
+	if detected, ok := rootAt(root.Path); ok {
+		root.GitDir = detected.GitDir
+		root.Superproject = detected.Superproject
+	}
+	return root, nil
+}
+
+// rootAt reports whether dir holds a .git entry, and which kind of root it
+// is. A .git file that does not point to a git directory is ignored.
+func rootAt(dir string) (*Root, bool) {
+	gitPath := filepath.Join(dir, ".git")
+	info, err := os.Stat(gitPath)
+	if err != nil {
+		return nil, false
+	}
+	if info.IsDir() {
+		return &Root{Path: dir, GitDir: gitPath, Kind: KindRepository}, true
+	}
+	if !info.Mode().IsRegular() {
+		return nil, false
+	}
+
+	gitDir, ok := readGitFile(gitPath)
+	if !ok {
+		return nil, false
+	}
+	root := &Root{Path: dir, GitDir: gitDir, Kind: KindRepository}
+	// Worktrees keep their git directory in <common>/worktrees/<name> and
+	// submodules in <superproject>/.git/modules/<path>; a repository created
+	// with --separate-git-dir has any other location
+	slashed := filepath.ToSlash(gitDir)
+	switch {
+	case strings.Contains(slashed, "/worktrees/"):
+		root.Kind = KindWorktree
+	case strings.Contains(slashed, "/modules/"):
+		root.Kind = KindSubmodule
+		if super, err := Detect(filepath.Dir(dir)); err == nil {
+			root.Superproject = super.Path
+		}
+	}
+	return root, true
+}
+
+// readGitFile reads the "gitdir: <path>" line of a .git file. Relative paths
+// are relative to the directory of the file.
+func readGitFile(path string) (string, bool) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", false
+	}
+	line, _, _ := strings.Cut(string(data), "\n")
+	gitDir, ok := strings.CutPrefix(strings.TrimSpace(line), "gitdir:")
+	if !ok {
+		return "", false
+	}
+	gitDir = strings.TrimSpace(gitDir)
+	if gitDir == "" {
+		return "", false
+	}
+	if !filepath.IsAbs(gitDir) {
+		gitDir = filepath.Join(filepath.Dir(path), gitDir)
+	}
+	return filepath.Clean(gitDir), true
+}
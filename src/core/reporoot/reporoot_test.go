@@ -0,0 +1,137 @@
+package reporoot
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// layout creates directories (names ending in /) and files under a
+// temporary directory and returns it
+func layout(t *testing.T, entries map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range entries {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if name[len(name)-1] == '/' {
+			if err := os.MkdirAll(path, 0755); err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestResolve(t *testing.T) {
+	t.Setenv(OverrideEnv, "")
+	dir := layout(t, map[string]string{
+		"repo/.git/":                     "",
+		"repo/src/cli/":                  "",
+		"repo/.git/modules/libs/shared/": "",
+		"repo/libs/shared/.git":          "gitdir: ../../.git/modules/libs/shared\n",
+		"repo/libs/shared/pkg/":          "",
+		"repo/.git/worktrees/feature/":   "",
+		"feature/.git":                   "gitdir: " + filepath.Join("..", "repo", ".git", "worktrees", "feature") + "\n",
+		"feature/docs/":                  "",
+		"separate/.git":                  "gitdir: /srv/git/separate.git\n",
+		"notgit/.git":                    "placeholder\n",
+		"notgit/inner/":                  "",
+	})
+
+	tests := []struct {
+		name         string
+		start        string
+		path         string
+		kind         Kind
+		superproject string
+	}{
+		{"repository root", "repo", "repo", KindRepository, ""},
+		{"repository subdirectory", "repo/src/cli", "repo", KindRepository, ""},
+		{"submodule", "repo/libs/shared/pkg", "repo/libs/shared", KindSubmodule, "repo"},
+		{"worktree", "feature/docs", "feature", KindWorktree, ""},
+		{"separate git dir", "separate", "separate", KindRepository, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root, err := Resolve(filepath.Join(dir, tt.start))
+			if err != nil {
+				t.Fatalf("Resolve() error = %v", err)
+			}
+			if want := filepath.Join(dir, tt.path); root.Path != want {
+				t.Errorf("Path = %s, want %s", root.Path, want)
+			}
+			if root.Kind != tt.kind {
+				t.Errorf("Kind = %v, want %v", root.Kind, tt.kind)
+			}
+			if tt.superproject != "" {
+				if want := filepath.Join(dir, tt.superproject); root.Superproject != want {
+					t.Errorf("Superproject = %s, want %s", root.Superproject, want)
+				}
+			}
+		})
+	}
+
+	t.Run("git dir of worktree", func(t *testing.T) {
+		root, err := Resolve(filepath.Join(dir, "feature"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := filepath.Join(dir, "repo", ".git", "worktrees", "feature"); root.GitDir != want {
+			t.Errorf("GitDir = %s, want %s", root.GitDir, want)
+		}
+	})
+
+	t.Run("invalid .git file is ignored", func(t *testing.T) {
+		_, err := Resolve(filepath.Join(dir, "notgit", "inner"))
+		// The temporary directory may itself be inside a repository
+		if err != nil && !errors.Is(err, ErrNotFound) {
+			t.Errorf("Resolve() error = %v", err)
+		}
+		if root, err := Resolve(filepath.Join(dir, "notgit")); err == nil && root.Path == filepath.Join(dir, "notgit") {
+			t.Error("a .git file without gitdir was taken as a root")
+		}
+	})
+}
+
+func TestResolveOverride(t *testing.T) {
+	dir := layout(t, map[string]string{"repo/.git/": "", "elsewhere/": ""})
+
+	t.Setenv(OverrideEnv, filepath.Join(dir, "repo"))
+	root, err := Resolve(filepath.Join(dir, "elsewhere"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if root.Path != filepath.Join(dir, "repo") || root.Kind != KindOverride {
+		t.Errorf("Resolve() = %+v, want the override", root)
+	}
+	if root.GitDir != filepath.Join(dir, "repo", ".git") {
+		t.Errorf("GitDir = %s", root.GitDir)
+	}
+
+	detected, err := Detect(filepath.Join(dir, "repo"))
+	if err != nil || detected.Kind != KindRepository {
+		t.Errorf("Detect() = %+v, %v, want detection without the override", detected, err)
+	}
+
+	t.Setenv(OverrideEnv, filepath.Join(dir, "missing"))
+	if _, err := Find(""); err == nil {
+		t.Error("Find() accepted an override that is not a directory")
+	}
+}
+
+func TestFindNotFound(t *testing.T) {
+	t.Setenv(OverrideEnv, "")
+	// Filesystem roots are not repositories, so a start above any
+	// repository fails, unless the test runs in a repository at the root
+	if _, err := Detect(string(filepath.Separator)); err != nil && !errors.Is(err, ErrNotFound) {
+		t.Errorf("Detect() error = %v, want ErrNotFound", err)
+	}
+}
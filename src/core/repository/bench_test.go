@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// benchModules are the module contracts of the benchmark workspace: a
+// catch-all, top-level modules and nested children that take over part of
+// their parent's source
+var benchModules = map[string]string{
+	"repository":   "source:\n  root: /\n  is_catch_all_singleton: true\n",
+	"docs":         "source:\n  root: docs\n  includes: [\"**/*.md\"]\n",
+	"src-cli":      "source:\n  root: src/cli\n",
+	"src-commands": "source:\n  root: src/commands\n",
+	"src-core":     "source:\n  root: src/core\n",
+	"src-core-ai":  "parent: src-core\nsource:\n  root: src/core/ai\n",
+	"src-mcp":      "source:\n  root: src/mcp\n  includes: [\"**/*.go\", \"**/go.mod\"]\n",
+	"src-mcp-jobs": "parent: src-mcp\nsource:\n  root: src/mcp/jobs\n",
+	"contracts":    "source:\n  root: contracts\n  includes: [\"**/*.yml\", \"**/*.json\"]\n",
+	"readme":       "source:\n  root: /\n  includes: [\"/README.md\", \"/**/README.md\"]\n",
+}
+
+// benchPaths spreads n files over the module roots and unowned directories
+func benchPaths(n int) []FileInfo {
+	dirs := []string{
+		"docs/guide", "src/cli/cmd", "src/cli/internal/conf", "src/commands/impl/commit",
+		"src/core/repository", "src/core/ai", "src/mcp/commands", "src/mcp/jobs",
+		"contracts/modules/0.1.0", "scripts", "",
+	}
+	exts := []string{".go", ".md", ".yml", ".json", "_test.go"}
+
+	files := make([]FileInfo, n)
+	for i := range files {
+		dir := dirs[i%len(dirs)]
+		name := fmt.Sprintf("file%d%s", i, exts[i%len(exts)])
+		if i%97 == 0 {
+			name = "README.md"
+		}
+		files[i] = FileInfo{Path: filepath.ToSlash(filepath.Join(dir, name))}
+	}
+	return files
+}
+
+func writeBenchWorkspace(b *testing.B) string {
+	b.Helper()
+	root := b.TempDir()
+	dir := filepath.Join(root, "contracts", "modules", "0.1.0")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		b.Fatal(err)
+	}
+	for moniker, source := range benchModules {
+		content := fmt.Sprintf("moniker: %s\nname: %s\n%s", moniker, moniker, source)
+		if err := os.WriteFile(filepath.Join(dir, moniker+".yml"), []byte(content), 0644); err != nil {
+			b.Fatal(err)
+		}
+	}
+	return root
+}
+
+func BenchmarkEnrichFilesWithModules_10k(b *testing.B) {
+	root := writeBenchWorkspace(b)
+	files := benchPaths(10000)
+
+	// Fail on a broken workspace instead of measuring the error path
+	enriched, err := EnrichFilesWithModules(files, root, "0.1.0")
+	if err != nil {
+		b.Fatal(err)
+	}
+	if orphans := GetOrphanFiles(enriched); len(orphans) > 0 {
+		b.Fatalf("%d files without a module, e.g. %s", len(orphans), orphans[0].Name)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := EnrichFilesWithModules(files, root, "0.1.0"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -312,6 +312,84 @@ func GetChangedModules(changedFiles []string, rootPath string, version string) (
 	return result, nil
 }
 
+// AffectedModules lists the modules affected by a set of changed files
+type AffectedModules struct {
+	Changed     []string            `json:"changed" yaml:"changed"`           // Modules owning a changed file
+	Dependents  []string            `json:"dependents" yaml:"dependents"`     // Modules depending (transitively) on a changed module
+	Affected    []string            `json:"affected" yaml:"affected"`         // Changed and dependents
+	PathFilters map[string][]string `json:"path_filters" yaml:"path_filters"` // Affected module -> GitHub Actions path globs
+}
+
+// GetAffectedModules returns the modules owning the changed files, the modules
+// depending on them, and the path filters of all affected modules
+func GetAffectedModules(changedFiles []string, rootPath string, version string) (*AffectedModules, error) {
+	if rootPath == "" {
+		var err error
+		rootPath, err = GetRepositoryRoot("")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	changed, err := GetChangedModules(changedFiles, rootPath, version)
+	if err != nil {
+		return nil, err
+	}
+
+	registry, err := modules.LoadFromWorkspace(rootPath, version)
+	if err != nil {
+		return nil, NewRepositoryError("affected-modules", rootPath, err, "failed to load module contracts")
+	}
+
+	affected := collectDependents(registry.GetReverseDependencyGraph(), changed)
+
+	result := &AffectedModules{
+		Changed:     changed,
+		Dependents:  []string{},
+		Affected:    affected,
+		PathFilters: make(map[string][]string),
+	}
+
+	isChanged := make(map[string]bool)
+	for _, moniker := range changed {
+		isChanged[moniker] = true
+	}
+	for _, moniker := range affected {
+		if !isChanged[moniker] {
+			result.Dependents = append(result.Dependents, moniker)
+		}
+		if module, ok := registry.Get(moniker); ok {
+			result.PathFilters[moniker] = module.GetGlobPatterns()
+		}
+	}
+
+	return result, nil
+}
+
+// collectDependents returns roots plus every module reachable through the
+// reverse dependency graph, sorted
+func collectDependents(dependents map[string][]string, roots []string) []string {
+	seen := make(map[string]bool)
+	queue := append([]string{}, roots...)
+	for len(queue) > 0 {
+		moniker := queue[0]
+		queue = queue[1:]
+		if seen[moniker] {
+			continue
+		}
+		seen[moniker] = true
+		queue = append(queue, dependents[moniker]...)
+	}
+
+	result := []string{}
+	for moniker := range seen {
+		result = append(result, moniker)
+	}
+	sort.Strings(result)
+
+	return result
+}
+
 // GetPlantUMLDiagram generates a PlantUML diagram from the dependency graph
 func GetPlantUMLDiagram(graph *ModuleDependencyGraph) string {
 	output := "@startuml\n"
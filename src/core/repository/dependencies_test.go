@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCollectDependents(t *testing.T) {
+	dependents := map[string][]string{
+		"core":     {"cli", "commands"},
+		"commands": {"mcp-commands"},
+		"cli":      {},
+		"docs":     {},
+	}
+
+	got := collectDependents(dependents, []string{"core"})
+	want := []string{"cli", "commands", "core", "mcp-commands"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("collectDependents() = %v, want %v", got, want)
+	}
+}
+
+func TestCollectDependents_NoRoots(t *testing.T) {
+	got := collectDependents(map[string][]string{"core": {"cli"}}, nil)
+	if len(got) != 0 {
+		t.Errorf("collectDependents() = %v, want empty", got)
+	}
+}
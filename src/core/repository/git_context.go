@@ -131,3 +131,15 @@ func (ctx *GitContext) BuildGitHubFileURL(filePath string) string {
 func (ctx *GitContext) BuildGitHubBlobURL(filePath string) string {
 	return ctx.BuildGitHubFileURL(filePath)
 }
+
+// GitOutput runs git in dir and returns its trimmed output; the error
+// includes what git printed on stderr
+func GitOutput(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	output, err := cmd.Output()
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		return "", fmt.Errorf("%w: %s", err, strings.TrimSpace(string(exitErr.Stderr)))
+	}
+	return strings.TrimSpace(string(output)), err
+}
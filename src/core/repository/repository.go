@@ -1,11 +1,14 @@
 package repository
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
+
+	"github.com/ready-to-release/eac/src/core/reporoot"
 )
 
 // Repository represents a Git repository
@@ -45,8 +48,9 @@ func NewRepositoryError(op, path string, err error, message string) *RepositoryE
 // GetRepositoryRoot finds and returns the root directory of the Git repository
 // starting from the given path (or current directory if empty).
 //
-// It searches upward through parent directories until it finds a .git directory,
-// or returns an error if no repository is found.
+// It searches upward through parent directories until it finds a .git entry,
+// including the .git file of a worktree or submodule, or returns an error if
+// no repository is found. R2R_REPO_ROOT overrides the search, see reporoot.
 //
 // Example:
 //   root, err := repository.GetRepositoryRoot("")
@@ -67,36 +71,14 @@ func GetRepositoryRoot(startPath string) (string, error) {
 		return "", NewRepositoryError("abs", startPath, err, "failed to get absolute path")
 	}
 
-	// Try using git to find the root (most reliable)
-	cmd := exec.Command("git", "rev-parse", "--show-toplevel")
-	cmd.Dir = absPath
-	output, err := cmd.Output()
-	if err == nil {
-		root := strings.TrimSpace(string(output))
-		// Normalize path separators for Windows
-		root = filepath.Clean(root)
-		return root, nil
+	root, err := reporoot.Find(absPath)
+	if errors.Is(err, reporoot.ErrNotFound) {
+		return "", NewRepositoryError("find", absPath, err, "not a git repository (or any parent up to mount point)")
 	}
-
-	// Fallback: manually search for .git directory
-	currentPath := absPath
-	for {
-		gitPath := filepath.Join(currentPath, ".git")
-		if info, err := os.Stat(gitPath); err == nil {
-			// Found .git - check if it's a directory or file (submodule/worktree)
-			if info.IsDir() || info.Mode().IsRegular() {
-				return currentPath, nil
-			}
-		}
-
-		// Move to parent directory
-		parentPath := filepath.Dir(currentPath)
-		if parentPath == currentPath {
-			// Reached filesystem root without finding .git
-			return "", NewRepositoryError("find", absPath, nil, "not a git repository (or any parent up to mount point)")
-		}
-		currentPath = parentPath
+	if err != nil {
+		return "", NewRepositoryError("find", absPath, err, err.Error())
 	}
+	return root, nil
 }
 
 // FileInfo represents information about a repository file
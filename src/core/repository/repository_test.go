@@ -5,6 +5,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -392,3 +393,19 @@ func TestRepositoryError_Unwrap(t *testing.T) {
 		t.Error("Unwrap returned wrong error")
 	}
 }
+
+func TestGitOutput(t *testing.T) {
+	repoDir := createTestGitRepo(t)
+	defer os.RemoveAll(repoDir)
+
+	output, err := GitOutput(repoDir, "config", "user.email")
+	if err != nil || output != "test@example.com" {
+		t.Errorf("GitOutput() = %q, %v; want the trimmed output", output, err)
+	}
+
+	// The error carries what git printed on stderr
+	_, err = GitOutput(repoDir, "rev-parse", "--verify", "refs/tags/missing")
+	if err == nil || !strings.Contains(err.Error(), "fatal:") {
+		t.Errorf("GitOutput() error = %v, want git's message", err)
+	}
+}
@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// WorkspaceRootEnv names the environment variable holding the workspace root.
+// Editors with multi-root workspaces may list several roots, separated by
+// os.PathListSeparator; the first one is the default.
+const WorkspaceRootEnv = "WORKSPACE_ROOT"
+
+// WorkspaceRoots returns the absolute roots listed in WORKSPACE_ROOT
+func WorkspaceRoots() []string {
+	var roots []string
+	for _, root := range filepath.SplitList(os.Getenv(WorkspaceRootEnv)) {
+		root = strings.TrimSpace(root)
+		if root == "" {
+			continue
+		}
+		if abs, err := filepath.Abs(root); err == nil {
+			root = abs
+		}
+		roots = append(roots, filepath.Clean(root))
+	}
+	return roots
+}
+
+// FindWorkspaceRoot resolves the workspace root without relying on the
+// location of any particular file. In order of precedence:
+//
+//  1. selector, when given: the path or folder name of one of the
+//     WORKSPACE_ROOT roots, or any path inside a git repository
+//  2. the first root in WORKSPACE_ROOT
+//  3. git rev-parse --show-toplevel from the current directory
+//
+// Example:
+//
+//	root, err := repository.FindWorkspaceRoot("")      // default root
+//	root, err := repository.FindWorkspaceRoot("docs")  // root folder named docs
+func FindWorkspaceRoot(selector string) (string, error) {
+	roots := WorkspaceRoots()
+
+	if selector != "" {
+		return selectWorkspaceRoot(roots, selector)
+	}
+
+	if len(roots) > 0 {
+		if info, err := os.Stat(roots[0]); err != nil || !info.IsDir() {
+			return "", NewRepositoryError("workspace", roots[0], err, WorkspaceRootEnv+" is not a directory")
+		}
+		return roots[0], nil
+	}
+
+	return GetRepositoryRoot("")
+}
+
+// selectWorkspaceRoot picks the root matching selector by path or folder name,
+// falling back to the repository containing selector when it is a path
+func selectWorkspaceRoot(roots []string, selector string) (string, error) {
+	path := selector
+	if abs, err := filepath.Abs(selector); err == nil {
+		path = abs
+	}
+
+	for _, root := range roots {
+		if root == filepath.Clean(path) || filepath.Base(root) == selector {
+			return root, nil
+		}
+	}
+
+	if info, err := os.Stat(path); err == nil && info.IsDir() && filepath.IsAbs(selector) {
+		return GetRepositoryRoot(path)
+	}
+
+	names := make([]string, len(roots))
+	for i, root := range roots {
+		names[i] = filepath.Base(root)
+	}
+	message := "unknown workspace"
+	if len(names) > 0 {
+		message += ", available: " + strings.Join(names, ", ")
+	}
+	return "", NewRepositoryError("workspace", selector, nil, message)
+}
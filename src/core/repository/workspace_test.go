@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFindWorkspaceRoot_FromEnv(t *testing.T) {
+	first := t.TempDir()
+	second := filepath.Join(t.TempDir(), "docs")
+	if err := os.Mkdir(second, 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(WorkspaceRootEnv, first+string(os.PathListSeparator)+second)
+
+	root, err := FindWorkspaceRoot("")
+	if err != nil || root != first {
+		t.Errorf("expected default root %s, got %s (%v)", first, root, err)
+	}
+
+	for _, selector := range []string{"docs", second} {
+		root, err := FindWorkspaceRoot(selector)
+		if err != nil || root != second {
+			t.Errorf("selector %q: expected %s, got %s (%v)", selector, second, root, err)
+		}
+	}
+}
+
+func TestFindWorkspaceRoot_UnknownSelector(t *testing.T) {
+	t.Setenv(WorkspaceRootEnv, t.TempDir())
+
+	_, err := FindWorkspaceRoot("missing")
+	if err == nil || !strings.Contains(err.Error(), "unknown workspace") {
+		t.Errorf("expected unknown workspace error, got %v", err)
+	}
+}
+
+func TestFindWorkspaceRoot_SelectorInsideRepository(t *testing.T) {
+	repoDir := createTestGitRepo(t)
+	defer os.RemoveAll(repoDir)
+	createTestFile(t, repoDir, "sub/file.txt", "content")
+	t.Setenv(WorkspaceRootEnv, "")
+
+	root, err := FindWorkspaceRoot(filepath.Join(repoDir, "sub"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected, _ := filepath.EvalSymlinks(repoDir)
+	actual, _ := filepath.EvalSymlinks(root)
+	if actual != expected {
+		t.Errorf("expected repository root %s, got %s", expected, actual)
+	}
+}
+
+func TestFindWorkspaceRoot_MissingEnvRoot(t *testing.T) {
+	t.Setenv(WorkspaceRootEnv, filepath.Join(t.TempDir(), "gone"))
+
+	if _, err := FindWorkspaceRoot(""); err == nil {
+		t.Error("expected error for a WORKSPACE_ROOT that does not exist")
+	}
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/ready-to-release/eac/src/core/mcpserver"
+)
+
+// Benchmarks for r2r bench: JSON-RPC round trips through the transport, in
+// both newline-delimited and Content-Length framing
+
+// benchTools returns n tools shaped like the ones getCommandTools advertises
+func benchTools(n int) []Tool {
+	tools := make([]Tool, n)
+	for i := range tools {
+		cmd := CommandInfo{Name: fmt.Sprintf("group%d command%d", i%8, i), SupportsDryRun: i%2 == 0}
+		schema := InputSchema{
+			Type: "object",
+			Properties: map[string]Property{
+				"args":    {Type: "string", Description: "Additional arguments (optional)"},
+				"dry_run": dryRunProperty(cmd),
+			},
+		}
+		addWorkspaceProperty(&schema)
+		tools[i] = Tool{
+			Name:        strings.ReplaceAll(cmd.Name, " ", "-"),
+			Description: fmt.Sprintf("Execute '%s' command", cmd.Name),
+			InputSchema: schema,
+		}
+	}
+	return tools
+}
+
+// frame wraps a message body in the given transport framing
+func frame(body []byte, framed bool) []byte {
+	if framed {
+		return append([]byte(fmt.Sprintf("Content-Length: %d\r\n\r\n", len(body))), body...)
+	}
+	return append(body, '\n')
+}
+
+// roundTrip reads one request from input, answers it with respond and
+// decodes the response the way a client does
+func roundTrip(b *testing.B, input []byte, output *bytes.Buffer, respond func(encoder *json.Encoder, req *MCPRequest)) {
+	output.Reset()
+	writer := mcpserver.NewMessageWriter(output)
+	reader := mcpserver.NewMessageReader(bytes.NewReader(input), writer)
+	encoder := json.NewEncoder(writer)
+
+	message, err := reader.ReadMessage()
+	if err != nil {
+		b.Fatal(err)
+	}
+	var req MCPRequest
+	if err := json.Unmarshal(message, &req); err != nil {
+		b.Fatal(err)
+	}
+	respond(encoder, &req)
+
+	body := output.Bytes()
+	if i := bytes.Index(body, []byte("\r\n\r\n")); i >= 0 {
+		body = body[i+4:]
+	}
+	var resp MCPResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		b.Fatal(err)
+	}
+	if resp.Error != nil {
+		b.Fatalf("unexpected error response: %s", resp.Error.Message)
+	}
+}
+
+func BenchmarkToolsListRoundTrip(b *testing.B) {
+	tools := benchTools(60)
+	request := []byte(`{"jsonrpc":"2.0","id":1,"method":"tools/list"}`)
+
+	for _, framed := range []bool{false, true} {
+		b.Run(transportName(framed), func(b *testing.B) {
+			input := frame(request, framed)
+			var output bytes.Buffer
+
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				roundTrip(b, input, &output, func(encoder *json.Encoder, req *MCPRequest) {
+					sendResponse(encoder, req.ID, map[string]interface{}{"tools": tools})
+				})
+			}
+		})
+	}
+}
+
+func BenchmarkToolCallRoundTrip(b *testing.B) {
+	tool := benchTools(1)[0]
+	request := []byte(fmt.Sprintf(`{"jsonrpc":"2.0","id":7,"method":"tools/call","params":{"name":%q,"arguments":{"args":"--module src-cli --verbose","dry_run":true}}}`, tool.Name))
+	// Command output is the bulk of a tools/call response
+	output := strings.Repeat("ok   github.com/ready-to-release/eac/src/cli/internal/conf\t0.472s\n", 1000)
+
+	for _, framed := range []bool{false, true} {
+		b.Run(transportName(framed), func(b *testing.B) {
+			input := frame(request, framed)
+			var buf bytes.Buffer
+
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				roundTrip(b, input, &buf, func(encoder *json.Encoder, req *MCPRequest) {
+					var params CallToolParams
+					if err := json.Unmarshal(req.Params, &params); err != nil {
+						b.Fatal(err)
+					}
+					if errs := mcpserver.ValidateArguments(tool.InputSchema, params.Arguments); len(errs) > 0 {
+						b.Fatal(mcpserver.FormatArgumentErrors(errs...))
+					}
+					sendResponse(encoder, req.ID, textResult(output))
+				})
+			}
+		})
+	}
+}
+
+func transportName(framed bool) string {
+	if framed {
+		return "content-length"
+	}
+	return "newline"
+}
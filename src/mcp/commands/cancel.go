@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+// Request cancellation: tools/call handlers run with a context that is
+// cancelled by notifications/cancelled (MCP) or $/cancelRequest (LSP-style
+// clients). Cancelling kills the command's process tree, including claude
+// subprocesses, and the call is answered with a RequestCancelled error.
+
+// codeRequestCancelled is the JSON-RPC error code for cancelled requests
+const codeRequestCancelled = -32800
+
+// CancelledParams accepts both notifications/cancelled ({"requestId"}) and
+// $/cancelRequest ({"id"})
+type CancelledParams struct {
+	RequestID interface{} `json:"requestId"`
+	ID        interface{} `json:"id"`
+	Reason    string      `json:"reason,omitempty"`
+}
+
+// InflightCalls tracks the cancel functions of running tool calls by request ID
+type InflightCalls struct {
+	mu      sync.Mutex
+	cancels map[string]context.CancelFunc
+}
+
+var inflightCalls = &InflightCalls{cancels: make(map[string]context.CancelFunc)}
+
+// isCancelNotification reports whether method cancels an in-flight request
+func isCancelNotification(method string) bool {
+	return method == "notifications/cancelled" || method == "$/cancelRequest"
+}
+
+// Start returns the context for request id and a function to call when the request is done
+func (c *InflightCalls) Start(id interface{}) (context.Context, func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+	if id == nil {
+		return ctx, cancel
+	}
+
+	key := fmt.Sprint(id)
+	c.mu.Lock()
+	c.cancels[key] = cancel
+	c.mu.Unlock()
+
+	return ctx, func() {
+		c.mu.Lock()
+		delete(c.cancels, key)
+		c.mu.Unlock()
+		cancel()
+	}
+}
+
+// Cancel cancels the request named in a cancellation notification. Unknown
+// or finished requests are ignored, as the notification may race the response.
+func (c *InflightCalls) Cancel(raw json.RawMessage) bool {
+	var params CancelledParams
+	if err := json.Unmarshal(raw, &params); err != nil {
+		return false
+	}
+	id := params.RequestID
+	if id == nil {
+		id = params.ID
+	}
+	if id == nil {
+		return false
+	}
+
+	c.mu.Lock()
+	cancel, ok := c.cancels[fmt.Sprint(id)]
+	c.mu.Unlock()
+
+	if ok {
+		cancel()
+	}
+	return ok
+}
+
+// CancelAll cancels every in-flight request (used on shutdown)
+func (c *InflightCalls) CancelAll() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, cancel := range c.cancels {
+		cancel()
+	}
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ready-to-release/eac/src/core/audit"
+	"github.com/ready-to-release/eac/src/core/mcpserver"
+)
+
+// agentWorkspace creates a workspace root with one agent, "writer"
+func agentWorkspace(t *testing.T) {
+	t.Helper()
+	root := t.TempDir()
+	agents := filepath.Join(root, ".claude", "agents")
+	if err := os.MkdirAll(agents, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(agents, "writer.md"), []byte("---\nname: writer\n---\nWrite.\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("WORKSPACE_ROOT", root)
+	t.Setenv(audit.PathEnv, filepath.Join(t.TempDir(), "audit.jsonl"))
+}
+
+// nextMessage returns the next message the server wrote
+func nextMessage(t *testing.T, messages <-chan IncomingMessage) IncomingMessage {
+	t.Helper()
+	select {
+	case msg := <-messages:
+		return msg
+	case <-time.After(10 * time.Second):
+		t.Fatal("the server wrote no message")
+		return IncomingMessage{}
+	}
+}
+
+func TestCancelRequest(t *testing.T) {
+	tests := []struct {
+		name   string
+		cancel string
+	}{
+		{name: "MCP", cancel: `{"jsonrpc":"2.0","method":"notifications/cancelled","params":{"requestId":7,"reason":"closed"}}`},
+		{name: "LSP", cancel: `{"jsonrpc":"2.0","method":"$/cancelRequest","params":{"id":7}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			agentWorkspace(t)
+			// The client never answers, so run-agent waits until cancelled
+			encoder, messages := useSamplingClient(t, func(params CreateMessageParams) *IncomingMessage {
+				return nil
+			})
+
+			input, client := io.Pipe()
+			served := make(chan func())
+			go func() {
+				served <- serve(mcpserver.NewMessageReader(input, mcpserver.NewMessageWriter(io.Discard)), encoder)
+			}()
+			send := func(message string) {
+				t.Helper()
+				if _, err := io.WriteString(client, message+"\n"); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			send(`{"jsonrpc":"2.0","id":7,"method":"tools/call","params":{"name":"run-agent","arguments":{"agent":"writer","input":"hello"}}}`)
+			sampling := nextMessage(t, messages)
+			if sampling.Method != "sampling/createMessage" {
+				t.Fatalf("message = %+v, want sampling/createMessage", sampling)
+			}
+
+			send(tt.cancel)
+
+			// The handler's context is cancelled: its sampling request is
+			// cancelled at the client and the call answered as cancelled
+			msg := nextMessage(t, messages)
+			var cancelled CancelledParams
+			json.Unmarshal(msg.Params, &cancelled)
+			if msg.Method != "notifications/cancelled" || cancelled.RequestID != sampling.ID {
+				t.Errorf("message = %+v, want notifications/cancelled for %v", msg, sampling.ID)
+			}
+			msg = nextMessage(t, messages)
+			if fmt.Sprint(msg.ID) != "7" || msg.Error == nil || msg.Error.Code != codeRequestCancelled || msg.Result != nil {
+				t.Errorf("response = %+v, want a -32800 error", msg)
+			}
+
+			// Nothing is sent for the call afterwards, not even when the
+			// client answers the cancelled sampling request late
+			send(fmt.Sprintf(`{"jsonrpc":"2.0","id":%q,"result":{"role":"assistant","content":{"type":"text","text":"late"},"model":"m"}}`, sampling.ID))
+			send(tt.cancel)
+			send(`{"jsonrpc":"2.0","id":8,"method":"ping"}`)
+			if msg := nextMessage(t, messages); fmt.Sprint(msg.ID) != "8" {
+				t.Errorf("message = %+v, want only the answer to request 8", msg)
+			}
+
+			client.Close()
+			wait := <-served
+			wait()
+			select {
+			case msg := <-messages:
+				t.Errorf("unexpected message after the cancellation: %+v", msg)
+			default:
+			}
+		})
+	}
+}
+
+func TestInflightCalls(t *testing.T) {
+	calls := &InflightCalls{cancels: make(map[string]context.CancelFunc)}
+
+	ctx, done := calls.Start(1)
+	other, otherDone := calls.Start("b")
+	defer otherDone()
+
+	if calls.Cancel([]byte(`{"requestId":2}`)) || calls.Cancel([]byte(`{}`)) || calls.Cancel([]byte(`not json`)) {
+		t.Error("Cancel of an unknown request reported success")
+	}
+	if ctx.Err() != nil {
+		t.Fatal("an unrelated cancellation cancelled the call")
+	}
+
+	// IDs match whatever their JSON type
+	if !calls.Cancel([]byte(`{"id":"1"}`)) || ctx.Err() == nil {
+		t.Error("$/cancelRequest did not cancel the call")
+	}
+	if other.Err() != nil {
+		t.Error("cancelling one call cancelled another")
+	}
+
+	// A finished call is forgotten
+	done()
+	if calls.Cancel([]byte(`{"requestId":1}`)) {
+		t.Error("Cancel of a finished request reported success")
+	}
+
+	calls.CancelAll()
+	if other.Err() == nil {
+		t.Error("CancelAll left a call running")
+	}
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ready-to-release/eac/contracts"
+	"github.com/ready-to-release/eac/src/core/repository"
+	"github.com/xeipuuv/gojsonschema"
+	"gopkg.in/yaml.v3"
+)
+
+// commitConfigFile is the commit-ai configuration of a workspace root,
+// validated against contracts/commit-config/<version>/schema.json. commit-ai
+// reads the rest of it itself; the server applies the run timeout.
+const commitConfigFile = ".r2r/commit.yml"
+
+// commitSettings are the settings of .r2r/commit.yml the server applies
+type commitSettings struct {
+	Timeout    time.Duration // limit for commit-ai runs
+	HasTimeout bool          // Timeout is set; 0s disables the limit
+}
+
+// fileStamp identifies a version of a file; the zero value is a missing file
+type fileStamp struct {
+	modTime int64 // Unix nanoseconds
+	size    int64
+}
+
+type commitConfigEntry struct {
+	stamp    fileStamp
+	settings commitSettings
+	err      error
+}
+
+// commitConfigCache holds the commit configuration of each workspace root,
+// reloading a file when its modification time or size changes
+type commitConfigCache struct {
+	mu      sync.Mutex
+	entries map[string]*commitConfigEntry
+}
+
+var commitConfigs = &commitConfigCache{entries: make(map[string]*commitConfigEntry)}
+
+// Preload loads the configuration of the WORKSPACE_ROOT roots, or of the
+// repository of the working directory, at startup so errors are logged early
+func (c *commitConfigCache) Preload() {
+	roots := repository.WorkspaceRoots()
+	if len(roots) == 0 {
+		if root, err := repository.FindWorkspaceRoot(""); err == nil {
+			roots = []string{root}
+		}
+	}
+	for _, root := range roots {
+		c.Get(root)
+	}
+}
+
+// Get returns the settings of a workspace root, or why its configuration
+// is invalid. A missing file has no settings.
+func (c *commitConfigCache) Get(root string) (commitSettings, error) {
+	path := filepath.Join(root, commitConfigFile)
+	var stamp fileStamp
+	if info, err := os.Stat(path); err == nil {
+		stamp = fileStamp{modTime: info.ModTime().UnixNano(), size: info.Size()}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, loaded := c.entries[root]
+	if loaded && entry.stamp == stamp {
+		return entry.settings, entry.err
+	}
+
+	entry = &commitConfigEntry{stamp: stamp}
+	if stamp != (fileStamp{}) {
+		entry.settings, entry.err = loadCommitSettings(path)
+		switch {
+		case entry.err != nil:
+			fmt.Fprintf(stderr, "Error loading %s: %v\n", path, entry.err)
+		case loaded:
+			fmt.Fprintf(stderr, "Reloaded %s\n", path)
+		default:
+			fmt.Fprintf(stderr, "Loaded %s\n", path)
+		}
+	} else if loaded {
+		fmt.Fprintf(stderr, "Removed %s, using defaults\n", path)
+	}
+	c.entries[root] = entry
+	return entry.settings, entry.err
+}
+
+// loadCommitSettings validates a commit configuration against the contract
+// schema and reads the settings the server applies
+func loadCommitSettings(path string) (commitSettings, error) {
+	var settings commitSettings
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return settings, fmt.Errorf("failed to read %s: %w", commitConfigFile, err)
+	}
+
+	var document interface{}
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return settings, fmt.Errorf("failed to parse %s: %w", commitConfigFile, err)
+	}
+	if document == nil {
+		document = map[string]interface{}{}
+	}
+	result, err := gojsonschema.Validate(gojsonschema.NewStringLoader(contracts.CommitConfigSchema()), gojsonschema.NewGoLoader(document))
+	if err != nil {
+		return settings, fmt.Errorf("failed to validate %s: %w", commitConfigFile, err)
+	}
+	if !result.Valid() {
+		var violations []string
+		for _, violation := range result.Errors() {
+			violations = append(violations, violation.Field()+": "+violation.Description())
+		}
+		return settings, fmt.Errorf("invalid %s: %s", commitConfigFile, strings.Join(violations, "; "))
+	}
+
+	var config struct {
+		Timeout string `yaml:"timeout"`
+	}
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return settings, fmt.Errorf("failed to parse %s: %w", commitConfigFile, err)
+	}
+	if config.Timeout != "" {
+		// The schema only admits durations
+		settings.Timeout, _ = time.ParseDuration(config.Timeout)
+		settings.HasTimeout = true
+	}
+	return settings, nil
+}
+
+// commandMaxRuntime returns the runtime limit of a command in a workspace
+// root and where it is configured: the timeout of .r2r/commit.yml for
+// commit-ai, otherwise MCP_COMMAND_MAX_RUNTIME
+func commandMaxRuntime(root string, commandName string) (time.Duration, string, error) {
+	if commandName == "commit ai" {
+		settings, err := commitConfigs.Get(root)
+		if err != nil {
+			return 0, "", err
+		}
+		if settings.HasTimeout {
+			return settings.Timeout, commitConfigFile + " timeout", nil
+		}
+	}
+	return envDuration("MCP_COMMAND_MAX_RUNTIME", defaultMaxRuntime), "MCP_COMMAND_MAX_RUNTIME", nil
+}
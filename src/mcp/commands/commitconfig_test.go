@@ -0,0 +1,98 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCommitConfigCache_Reload(t *testing.T) {
+	t.Setenv("MCP_COMMAND_MAX_RUNTIME", "")
+	root := t.TempDir()
+	path := filepath.Join(root, commitConfigFile)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	write := func(content string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cache := &commitConfigCache{entries: make(map[string]*commitConfigEntry)}
+
+	settings, err := cache.Get(root)
+	if err != nil || settings.HasTimeout {
+		t.Fatalf("Expected no settings without a file, got %+v, %v", settings, err)
+	}
+
+	write("timeout: 10m\nformat: json\n")
+	settings, err = cache.Get(root)
+	if err != nil || !settings.HasTimeout || settings.Timeout != 10*time.Minute {
+		t.Fatalf("Unexpected settings: %+v, %v", settings, err)
+	}
+
+	// A changed file is reloaded
+	write("timeout: 0s\n")
+	settings, err = cache.Get(root)
+	if err != nil || !settings.HasTimeout || settings.Timeout != 0 {
+		t.Fatalf("Expected the reloaded timeout 0s, got %+v, %v", settings, err)
+	}
+
+	write("timeout: 5 minutes\n")
+	if _, err := cache.Get(root); err == nil || !strings.Contains(err.Error(), "timeout") {
+		t.Errorf("Expected a schema error for the timeout, got %v", err)
+	}
+
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	settings, err = cache.Get(root)
+	if err != nil || settings.HasTimeout {
+		t.Errorf("Expected no settings after removal, got %+v, %v", settings, err)
+	}
+}
+
+func TestLoadCommitSettings_Schema(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "commit.yml")
+	for content, valid := range map[string]bool{
+		"":                                       true,
+		"language: German\nparallelism: 2\n":     true,
+		"stages:\n  module:\n    model: haiku\n": true,
+		"unknown: true\n":                        false,
+		"format: yaml\n":                         false,
+		"stages:\n  module:\n    agent: a.md\n":  false,
+	} {
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := loadCommitSettings(path); (err == nil) != valid {
+			t.Errorf("loadCommitSettings(%q) error = %v, want valid %v", content, err, valid)
+		}
+	}
+}
+
+func TestCommandMaxRuntime(t *testing.T) {
+	t.Setenv("MCP_COMMAND_MAX_RUNTIME", "3m")
+	root := t.TempDir()
+	path := filepath.Join(root, commitConfigFile)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("timeout: 10m\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// callTool passes the command name with spaces
+	runtime, source, err := commandMaxRuntime(root, "commit ai")
+	if err != nil || runtime != 10*time.Minute || source != commitConfigFile+" timeout" {
+		t.Errorf("commit ai: %s from %q, %v; want 10m from %s", runtime, source, err, commitConfigFile)
+	}
+
+	runtime, source, err = commandMaxRuntime(root, "commit validate")
+	if err != nil || runtime != 3*time.Minute || source != "MCP_COMMAND_MAX_RUNTIME" {
+		t.Errorf("commit validate: %s from %q, %v; want 3m from MCP_COMMAND_MAX_RUNTIME", runtime, source, err)
+	}
+}
@@ -2,12 +2,21 @@ module github.com/ready-to-release/eac/mcp-server-commands
 
 go 1.25.3
 
-require github.com/ready-to-release/eac/src/core v0.0.0
+require (
+	github.com/ready-to-release/eac/contracts v0.0.0
+	github.com/ready-to-release/eac/src/core v0.0.0
+	github.com/xeipuuv/gojsonschema v1.2.0
+	gopkg.in/yaml.v3 v3.0.1
+)
 
 require (
 	github.com/gobwas/glob v0.2.3 // indirect
 	github.com/kr/text v0.2.0 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
+	github.com/xeipuuv/gojsonpointer v0.0.0-20180127040702-4e3ac2762d5f // indirect
+	github.com/xeipuuv/gojsonreference v0.0.0-20180127040603-bd5ef7bd5415 // indirect
+	golang.org/x/sys v0.36.0 // indirect
 )
 
+replace github.com/ready-to-release/eac/contracts => ../../../contracts
+
 replace github.com/ready-to-release/eac/src/core => ../../core
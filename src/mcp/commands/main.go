@@ -1,14 +1,21 @@
 package main
 
 import (
-	"bufio"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
+	"github.com/ready-to-release/eac/src/core/audit"
+	"github.com/ready-to-release/eac/src/core/mcpserver"
 	"github.com/ready-to-release/eac/src/core/repository"
 )
 
@@ -33,26 +40,18 @@ type MCPError struct {
 	Message string `json:"message"`
 }
 
-type Tool struct {
-	Name        string      `json:"name"`
-	Description string      `json:"description"`
-	InputSchema InputSchema `json:"inputSchema"`
-}
-
-type InputSchema struct {
-	Type       string              `json:"type"`
-	Properties map[string]Property `json:"properties"`
-	Required   []string            `json:"required,omitempty"`
-}
-
-type Property struct {
-	Type        string `json:"type"`
-	Description string `json:"description"`
-}
+// Tool, InputSchema and Property are shared with the other servers, which
+// validate arguments against them (src/core/mcpserver)
+type (
+	Tool        = mcpserver.Tool
+	InputSchema = mcpserver.InputSchema
+	Property    = mcpserver.Property
+)
 
 type CallToolParams struct {
 	Name      string                 `json:"name"`
 	Arguments map[string]interface{} `json:"arguments"`
+	Meta      *RequestMeta           `json:"_meta,omitempty"`
 }
 
 type ToolResult struct {
@@ -71,36 +70,111 @@ type CommandInfo struct {
 	Description string   `json:"description"`
 	Parent      string   `json:"parent"`
 	IsLeaf      bool     `json:"is_leaf"`
+
+	HasSideEffects bool `json:"has_side_effects"`
+	SupportsDryRun bool `json:"supports_dry_run"`
 }
 
 type CommandTree struct {
-	Commands []CommandInfo      `json:"commands"`
+	Commands []CommandInfo       `json:"commands"`
 	Tree     map[string][]string `json:"tree"`
 }
 
+// lifecycle tracks the child processes of the server and kills them on
+// shutdown
+var lifecycle = mcpserver.NewLifecycle()
+
+// stderr is the diagnostics stream of the server, with credentials masked
+var stderr = mcpserver.Stderr
+
 func main() {
-	scanner := bufio.NewScanner(os.Stdin)
-	encoder := json.NewEncoder(os.Stdout)
+	writer := mcpserver.NewMessageWriter(os.Stdout)
+	reader := mcpserver.NewMessageReader(os.Stdin, writer)
+	encoder := json.NewEncoder(writer)
 
-	for scanner.Scan() {
-		line := scanner.Text()
-		if line == "" {
-			continue
+	lifecycle.HandleSignals()
+	lifecycle.OnShutdown(sampler.Cancel)
+	lifecycle.OnShutdown(inflightCalls.CancelAll)
+
+	commitConfigs.Preload()
+
+	wait := serve(reader, encoder)
+
+	// stdin closed: stop children so in-flight handlers return
+	lifecycle.Shutdown()
+	wait()
+}
+
+// serve answers the messages of reader until the input ends. Tool calls run
+// concurrently; the returned function waits for the ones still running.
+func serve(reader *mcpserver.MessageReader, encoder *json.Encoder) func() {
+	var inflight sync.WaitGroup
+
+	for {
+		line, err := reader.ReadMessage()
+		if err != nil {
+			if errors.Is(err, mcpserver.ErrMessageTooLarge) {
+				sendError(encoder, nil, -32600, err.Error())
+				continue
+			}
+			if errors.Is(err, mcpserver.ErrInvalidHeader) {
+				sendError(encoder, nil, -32700, err.Error())
+				continue
+			}
+			break
 		}
 
-		var req MCPRequest
-		if err := json.Unmarshal([]byte(line), &req); err != nil {
+		var msg IncomingMessage
+		if err := json.Unmarshal(line, &msg); err != nil {
 			sendError(encoder, nil, -32700, "Parse error")
 			continue
 		}
 
-		handleRequest(encoder, &req)
+		// Responses to server-initiated requests (sampling); a late response
+		// to a cancelled request is dropped
+		if sampler.Deliver(&msg) || msg.Method == "" && (msg.Result != nil || msg.Error != nil) {
+			continue
+		}
+
+		if isCancelNotification(msg.Method) {
+			inflightCalls.Cancel(msg.Params)
+			continue
+		}
+
+		req := MCPRequest{
+			JSONRPC: msg.JSONRPC,
+			ID:      msg.ID,
+			Method:  msg.Method,
+			Params:  msg.Params,
+		}
+
+		// Tool calls may wait on sampling responses or be cancelled, so keep reading stdin
+		if req.Method == "tools/call" {
+			ctx, done := inflightCalls.Start(req.ID)
+			inflight.Add(1)
+			go func() {
+				defer inflight.Done()
+				defer done()
+				handleRequest(ctx, encoder, &req)
+			}()
+			continue
+		}
+
+		handleRequest(context.Background(), encoder, &req)
 	}
+
+	return inflight.Wait
 }
 
-func handleRequest(encoder *json.Encoder, req *MCPRequest) {
+func handleRequest(ctx context.Context, encoder *json.Encoder, req *MCPRequest) {
 	switch req.Method {
 	case "initialize":
+		var params InitializeParams
+		if len(req.Params) > 0 {
+			json.Unmarshal(req.Params, &params)
+		}
+		sampler.SetSupported(params.Capabilities.Sampling != nil)
+
 		sendResponse(encoder, req.ID, map[string]interface{}{
 			"protocolVersion": "2024-11-05",
 			"serverInfo": map[string]string{
@@ -108,12 +182,13 @@ func handleRequest(encoder *json.Encoder, req *MCPRequest) {
 				"version": "0.1.0",
 			},
 			"capabilities": map[string]interface{}{
-				"tools": map[string]bool{},
+				"tools":   map[string]bool{},
+				"prompts": map[string]bool{},
 			},
 		})
 
 	case "tools/list":
-		tools := getCommandTools()
+		tools := namespace.Advertise(getCommandTools())
 		sendResponse(encoder, req.ID, map[string]interface{}{
 			"tools": tools,
 		})
@@ -124,10 +199,61 @@ func handleRequest(encoder *json.Encoder, req *MCPRequest) {
 			sendError(encoder, req.ID, -32602, "Invalid params")
 			return
 		}
+		params.Name = namespace.Resolve(params.Name, toolNames)
+		record := audit.StartTool(auditSource, params.Name, params.Arguments)
+
+		if tool, ok := findTool(params.Name); ok {
+			if errs := mcpserver.ValidateArguments(tool.InputSchema, params.Arguments); len(errs) > 0 {
+				message := mcpserver.FormatArgumentErrors(errs...)
+				mcpserver.FinishAudit(record, true, errors.New(message))
+				sendError(encoder, req.ID, -32602, message)
+				return
+			}
+		}
 
-		result := callTool(&params)
+		result, err := callTool(ctx, encoder, &params)
+		var argErr *mcpserver.ArgumentError
+		if errors.As(err, &argErr) {
+			message := mcpserver.FormatArgumentErrors(argErr)
+			mcpserver.FinishAudit(record, true, errors.New(message))
+			sendError(encoder, req.ID, -32602, message)
+			return
+		}
+		if ctx.Err() != nil {
+			mcpserver.FinishAudit(record, true, ctx.Err())
+			sendError(encoder, req.ID, codeRequestCancelled, "Request cancelled")
+			return
+		}
+		mcpserver.FinishAudit(record, toolFailed(result), nil)
 		sendResponse(encoder, req.ID, result)
 
+	case "prompts/list":
+		sendResponse(encoder, req.ID, map[string]interface{}{
+			"prompts": getAgentPrompts(),
+		})
+
+	case "prompts/get":
+		var params GetPromptParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			sendError(encoder, req.ID, -32602, "Invalid params")
+			return
+		}
+
+		result, err := getAgentPrompt(&params)
+		if err != nil {
+			sendError(encoder, req.ID, -32602, err.Error())
+			return
+		}
+		sendResponse(encoder, req.ID, result)
+
+	case "shutdown":
+		lifecycle.Shutdown()
+		sendResponse(encoder, req.ID, map[string]interface{}{})
+
+	case "exit":
+		lifecycle.Shutdown()
+		os.Exit(0)
+
 	default:
 		sendError(encoder, req.ID, -32601, "Method not found")
 	}
@@ -138,6 +264,10 @@ func getCommandTools() []Tool {
 	tree := describeCommands()
 	var tools []Tool
 
+	if len(loadAgents("")) > 0 {
+		tools = append(tools, runAgentTool())
+	}
+
 	for _, cmd := range tree.Commands {
 		// Convert command name to kebab-case for tool name
 		toolName := strings.ReplaceAll(cmd.Name, " ", "-")
@@ -152,27 +282,183 @@ func getCommandTools() []Tool {
 			description = fmt.Sprintf("Execute '%s' command", cmd.Name)
 		}
 
+		properties := map[string]Property{
+			"args": {
+				Type:        "string",
+				Description: "Additional arguments (optional)",
+			},
+		}
+		properties["dry_run"] = dryRunProperty(cmd)
+		for name, prop := range commandProperties[toolName] {
+			properties[name] = prop
+		}
+
+		schema := InputSchema{
+			Type:       "object",
+			Properties: properties,
+		}
+		addWorkspaceProperty(&schema)
+
 		tools = append(tools, Tool{
 			Name:        toolName,
 			Description: description,
-			InputSchema: InputSchema{
-				Type: "object",
-				Properties: map[string]Property{
-					"args": {
-						Type:        "string",
-						Description: "Additional arguments (optional)",
-					},
-				},
-			},
+			InputSchema: schema,
 		})
 	}
 
+	knownTools.Store(tools)
 	return tools
 }
 
+// dryRunProperty describes the dry_run argument, which passes --dry-run to
+// the command
+func dryRunProperty(cmd CommandInfo) Property {
+	description := "Print what the command would do without doing it (optional)"
+	switch {
+	case cmd.SupportsDryRun:
+		description += "; the command previews its changes"
+	case cmd.HasSideEffects:
+		description += "; the command cannot preview its changes, so only its description is printed and nothing runs"
+	default:
+		description += "; the command does not declare dry-run support, so only its description is printed and nothing runs (it does not modify the repository; call it without dry_run)"
+	}
+	return Property{Type: "boolean", Description: description}
+}
+
+// commandProperties declares structured arguments of specific command tools.
+// Each array item is passed to the command as "--<name> <item>", a true
+// boolean as "--<name>" and a non-empty string as "--<name> <value>".
+var commandProperties = map[string]map[string]Property{
+	"docs-lint": {
+		"fix": {
+			Type:        "boolean",
+			Description: "Rewrite the files with findings",
+		},
+		"format": {
+			Type:        "string",
+			Description: "Output format; json returns the findings per file with path, rule, description and line",
+			Enum:        []string{"text", "json"},
+		},
+	},
+	"docs-serve": {
+		"mode": {
+			Type:        "string",
+			Description: "Server to run: docker (MkDocs container), venv (MkDocs from a local Python venv), native (built-in markdown preview) or auto, the first one available; venv and native keep running until stopped",
+			Enum:        []string{"auto", "docker", "venv", "native"},
+		},
+	},
+	"docs-nav": {
+		"write": {
+			Type:        "boolean",
+			Description: "Write the regenerated nav to mkdocs.yml; without it only the diff is returned",
+		},
+	},
+	"docs-stale": {
+		"format": {
+			Type:        "string",
+			Description: "Output format; json returns the stale references with path, line, kind, reference and suggested rename",
+			Enum:        []string{"text", "json"},
+		},
+	},
+	"design-lint": {
+		"all": {
+			Type:        "boolean",
+			Description: "Lint the workspaces of all modules instead of the module given in args",
+		},
+		"format": {
+			Type:        "string",
+			Description: "Output format; json returns the diagnostics with line, rule and severity",
+			Enum:        []string{"text", "json"},
+		},
+	},
+	"pipeline-selftest": {
+		"format": {
+			Type:        "string",
+			Description: "Output format; json returns the readiness report with ready and each check's name, status (ok, warning or error), message and fix",
+			Enum:        []string{"text", "json"},
+		},
+		"live": {
+			Type:        "boolean",
+			Description: "Send a one-word prompt to claude to confirm the login, which otherwise is only checked for credentials (uses a few tokens)",
+		},
+	},
+	"commit-ai": {
+		"files": {
+			Type:        "array",
+			Description: "Only describe these staged files, for a partial commit. The output includes the patch to stage between >>>>>>PATCH START<<<<<< and >>>>>>PATCH END<<<<<< (apply with: git reset -q && git apply --cached)",
+			Items:       &Property{Type: "string"},
+		},
+		"hunks": {
+			Type:        "array",
+			Description: "Only describe these hunks of staged files, as \"<path>:<n>[,<n>...]\" with 1-based hunk numbers. Combines with files",
+			Items:       &Property{Type: "string"},
+		},
+		"commit": {
+			Type:        "boolean",
+			Description: "Commit the staged changes with the generated message when it has no contract violations. Signing follows .r2r/commit.yml or the repository git config. With include_unstaged, the listed files are staged first",
+		},
+		"include_unstaged": {
+			Type:        "boolean",
+			Description: "Describe the whole working tree (staged, unstaged and untracked changes) instead of the staged changes only. The files that need staging are listed between >>>>>>UNSTAGED FILES START<<<<<< and >>>>>>UNSTAGED FILES END<<<<<< (stage with: git add --all --)",
+		},
+		"format": {
+			Type:        "string",
+			Description: "Output format; json adds a second content block holding the contract violations and agent file findings as JSON, each with file (empty for the generated message) and line, for editor diagnostics",
+			Enum:        []string{"text", "json"},
+		},
+		"language": {
+			Type:        "string",
+			Description: "Language of the summary and body text, e.g. \"German\" or \"ja\" (default: language in .r2r/commit.yml, else English). Titles and subject lines stay English",
+		},
+	},
+}
+
+// addWorkspaceProperty lets tools choose the workspace root when WORKSPACE_ROOT
+// lists several (multi-root editor workspaces)
+func addWorkspaceProperty(schema *InputSchema) {
+	roots := repository.WorkspaceRoots()
+	if len(roots) < 2 {
+		return
+	}
+
+	names := make([]string, len(roots))
+	for i, root := range roots {
+		names[i] = filepath.Base(root)
+	}
+	schema.Properties["workspace"] = Property{
+		Type:        "string",
+		Description: fmt.Sprintf("Workspace root folder name or path (optional, default %s). Available: %s", names[0], strings.Join(names, ", ")),
+	}
+}
+
+// knownTools caches the tools from the last tools/list for argument validation
+var knownTools atomic.Value
+
+// commandTools returns the tools of the last tools/list. A call before any
+// tools/list discovers them, so it is validated and resolved all the same.
+func commandTools() []Tool {
+	if tools, ok := knownTools.Load().([]Tool); ok {
+		return tools
+	}
+	return getCommandTools()
+}
+
+// findTool looks up a tool definition by name
+func findTool(name string) (Tool, bool) {
+	if name == "run-agent" {
+		return runAgentTool(), true
+	}
+	for _, tool := range commandTools() {
+		if tool.Name == name {
+			return tool, true
+		}
+	}
+	return Tool{}, false
+}
+
 // describeCommands calls "go run ./src/commands describe commands" to get command info
 func describeCommands() CommandTree {
-	repoRoot := findRepoRoot()
+	repoRoot := findRepoRoot("")
 	if repoRoot == "" {
 		return CommandTree{Commands: []CommandInfo{}}
 	}
@@ -181,40 +467,134 @@ func describeCommands() CommandTree {
 	cmd := exec.Command("go", "run", ".", "describe", "commands")
 	cmd.Dir = cmdPath
 
-	output, err := cmd.Output()
+	output, err := lifecycle.Output(cmd)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error describing commands: %v\n", err)
+		fmt.Fprintf(stderr, "Error describing commands: %v\n", err)
 		return CommandTree{Commands: []CommandInfo{}}
 	}
 
 	var tree CommandTree
 	if err := json.Unmarshal(output, &tree); err != nil {
-		fmt.Fprintf(os.Stderr, "Error parsing command tree: %v\n", err)
+		fmt.Fprintf(stderr, "Error parsing command tree: %v\n", err)
 		return CommandTree{Commands: []CommandInfo{}}
 	}
 
 	return tree
 }
 
-func callTool(params *CallToolParams) ToolResult {
+// commandArgs are the arguments every command tool takes; the arguments for
+// the command's own flags are read by propertyArgs
+type commandArgs struct {
+	Args      string `json:"args"`
+	Workspace string `json:"workspace"`
+	DryRun    bool   `json:"dry_run"`
+}
+
+// callTool runs a tool. It returns an *mcpserver.ArgumentError when the
+// arguments do not decode.
+func callTool(ctx context.Context, encoder *json.Encoder, params *CallToolParams) (ToolResult, error) {
+	if params.Name == "run-agent" {
+		return callRunAgent(ctx, encoder, params)
+	}
+
+	var args commandArgs
+	if err := mcpserver.DecodeArguments(params.Arguments, &args); err != nil {
+		return ToolResult{}, err
+	}
+
 	// Convert tool name back to command name (kebab-case to space-separated)
 	commandName := strings.ReplaceAll(params.Name, "-", " ")
 
-	// Get additional args if provided
-	args := ""
-	if argsVal, ok := params.Arguments["args"].(string); ok {
-		args = argsVal
+	progress := newProgressReporter(encoder, params.Meta)
+
+	flags := propertyArgs(params.Name, params.Arguments)
+	if args.DryRun {
+		flags = append(flags, "--dry-run")
 	}
 
-	output := execCommand(commandName, args)
-	return textResult(output)
+	env, stopSampling, err := commandSampling(ctx, encoder, commandName)
+	if err != nil {
+		return textResult(fmt.Sprintf("Error: %v", err)), nil
+	}
+	defer stopSampling()
+
+	output := execCommand(ctx, args.Workspace, commandName, args.Args, env, progress, flags...)
+	return commandResult(output), nil
 }
 
-// execCommand executes a command via "go run ./src/commands <command> [args]"
-func execCommand(commandName string, additionalArgs string) string {
-	repoRoot := findRepoRoot()
-	if repoRoot == "" {
-		return "Error: Could not find repository root"
+// Markers around the JSON diagnostics a command prints with --format json
+// (src/commands/impl/commit/diagnostics.go)
+const (
+	diagnosticsStart = ">>>>>>DIAGNOSTICS START<<<<<<"
+	diagnosticsEnd   = ">>>>>>DIAGNOSTICS END<<<<<<"
+)
+
+// commandResult returns the output of a command as a text block. Diagnostics
+// the command printed are moved to a second block holding only their JSON,
+// so clients can read the file and line of each without parsing the text.
+func commandResult(output string) ToolResult {
+	start := strings.Index(output, diagnosticsStart)
+	if start < 0 {
+		return textResult(output)
+	}
+	end := strings.Index(output[start:], diagnosticsEnd)
+	if end < 0 {
+		return textResult(output)
+	}
+	end += start
+
+	diagnostics := strings.TrimSpace(output[start+len(diagnosticsStart) : end])
+	if !json.Valid([]byte(diagnostics)) {
+		return textResult(output)
+	}
+	text := strings.TrimRight(output[:start], "\n") + "\n" + strings.TrimLeft(output[end+len(diagnosticsEnd):], "\n")
+	result := textResult(strings.TrimSpace(text))
+	result.Content = append(result.Content, Content{Type: "text", Text: diagnostics})
+	return result
+}
+
+// propertyArgs converts the structured arguments of a command tool to flags,
+// with snake_case argument names as kebab-case flags
+func propertyArgs(toolName string, arguments map[string]interface{}) []string {
+	properties := commandProperties[toolName]
+	names := make([]string, 0, len(properties))
+	for name := range properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var flags []string
+	for _, name := range names {
+		flag := "--" + strings.ReplaceAll(name, "_", "-")
+		switch value := arguments[name].(type) {
+		case []interface{}:
+			for _, item := range value {
+				flags = append(flags, flag, fmt.Sprint(item))
+			}
+		case bool:
+			if value {
+				flags = append(flags, flag)
+			}
+		case string:
+			if value != "" {
+				flags = append(flags, flag, value)
+			}
+		}
+	}
+	return flags
+}
+
+// execCommand executes a command via "go run ./src/commands <command> [args] [flags]"
+// in the given workspace, with env added to its environment. With a progress
+// reporter the command prints typed progress events, which are sent as
+// notifications instead of returned, and the output is streamed as it is
+// printed. Cancelling ctx kills the command; so does exceeding
+// MCP_COMMAND_MAX_RUNTIME (for commit-ai, the timeout of .r2r/commit.yml),
+// which returns the partial output with a timeout marker.
+func execCommand(ctx context.Context, workspace string, commandName string, additionalArgs string, env []string, progress *progressReporter, flags ...string) string {
+	repoRoot, err := repository.FindWorkspaceRoot(workspace)
+	if err != nil {
+		return fmt.Sprintf("Error: Could not find repository root: %v", err)
 	}
 
 	cmdPath := filepath.Join(repoRoot, "src", "commands")
@@ -224,6 +604,7 @@ func execCommand(commandName string, additionalArgs string) string {
 	if additionalArgs != "" {
 		cmdParts = append(cmdParts, strings.Fields(additionalArgs)...)
 	}
+	cmdParts = append(cmdParts, flags...)
 
 	// Prepend "go run ."
 	cmdArgs := append([]string{"run", "."}, cmdParts...)
@@ -231,7 +612,35 @@ func execCommand(commandName string, additionalArgs string) string {
 	cmd := exec.Command("go", cmdArgs...)
 	cmd.Dir = cmdPath
 
-	output, err := cmd.CombinedOutput()
+	filter := &progressFilter{}
+	if progress != nil {
+		env = append(env, progressEnv)
+		filter.report = progress.Report
+		stop := streamOutput(progress, filter)
+		defer stop()
+	}
+	if len(env) > 0 {
+		cmd.Env = append(os.Environ(), env...)
+	}
+	cmd.Stdout = filter
+	cmd.Stderr = filter
+
+	runCtx := ctx
+	maxRuntime, runtimeSource, err := commandMaxRuntime(repoRoot, commandName)
+	if err != nil {
+		return fmt.Sprintf("Error: %v", err)
+	}
+	if maxRuntime > 0 {
+		var cancel context.CancelFunc
+		runCtx, cancel = context.WithTimeout(ctx, maxRuntime)
+		defer cancel()
+	}
+
+	err = lifecycle.RunContext(runCtx, cmd)
+	output := filter.Output()
+	if errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil {
+		return fmt.Sprintf("%s\n\n%s %s (%s); the output above is partial", strings.TrimSpace(string(output)), timeoutMarker, maxRuntime, runtimeSource)
+	}
 	if err != nil {
 		return fmt.Sprintf("Error executing command '%s': %v\n\nOutput:\n%s", commandName, err, string(output))
 	}
@@ -239,10 +648,31 @@ func execCommand(commandName string, additionalArgs string) string {
 	return strings.TrimSpace(string(output))
 }
 
-// findRepoRoot walks up directory tree to find repository root
-func findRepoRoot() string {
-	root, err := repository.GetRepositoryRoot("")
+// defaultMaxRuntime bounds a command run; MCP_COMMAND_MAX_RUNTIME overrides
+// it and 0 disables the limit
+const defaultMaxRuntime = 30 * time.Minute
+
+// timeoutMarker starts the last line of the output of a command that
+// exceeded the maximum runtime
+const timeoutMarker = "[timeout] Command killed after"
+
+// toolFailed reports whether a command tool result reports a failure: the
+// command could not run, exited non-zero or exceeded its maximum runtime
+func toolFailed(result ToolResult) bool {
+	for _, content := range result.Content {
+		if strings.HasPrefix(content.Text, "Error") || strings.Contains(content.Text, timeoutMarker) {
+			return true
+		}
+	}
+	return false
+}
+
+// findRepoRoot resolves the workspace root from the workspace tool argument,
+// WORKSPACE_ROOT or the git repository of the working directory
+func findRepoRoot(workspace string) string {
+	root, err := repository.FindWorkspaceRoot(workspace)
 	if err != nil {
+		fmt.Fprintf(stderr, "Error finding workspace root: %v\n", err)
 		return ""
 	}
 	return root
@@ -257,12 +687,48 @@ func textResult(text string) ToolResult {
 	}
 }
 
+// outputMu serializes writes to stdout across concurrent handlers
+var outputMu sync.Mutex
+
+func sendRequest(encoder *json.Encoder, id interface{}, method string, params interface{}) {
+	raw, _ := json.Marshal(params)
+	req := MCPRequest{
+		JSONRPC: "2.0",
+		ID:      id,
+		Method:  method,
+		Params:  raw,
+	}
+	outputMu.Lock()
+	defer outputMu.Unlock()
+	encoder.Encode(req)
+}
+
+// MCPNotification is a JSON-RPC notification, which has no ID
+type MCPNotification struct {
+	JSONRPC string      `json:"jsonrpc"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params,omitempty"`
+}
+
+func sendNotification(encoder *json.Encoder, method string, params interface{}) {
+	notification := MCPNotification{
+		JSONRPC: "2.0",
+		Method:  method,
+		Params:  params,
+	}
+	outputMu.Lock()
+	defer outputMu.Unlock()
+	encoder.Encode(notification)
+}
+
 func sendResponse(encoder *json.Encoder, id interface{}, result interface{}) {
 	resp := MCPResponse{
 		JSONRPC: "2.0",
 		ID:      id,
 		Result:  result,
 	}
+	outputMu.Lock()
+	defer outputMu.Unlock()
 	encoder.Encode(resp)
 }
 
@@ -275,5 +741,7 @@ func sendError(encoder *json.Encoder, id interface{}, code int, message string)
 			Message: message,
 		},
 	}
+	outputMu.Lock()
+	defer outputMu.Unlock()
 	encoder.Encode(resp)
 }
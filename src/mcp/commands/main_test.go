@@ -0,0 +1,41 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCommandResult(t *testing.T) {
+	output := "Error executing command 'commit ai': exit status 1\n\nOutput:\n" +
+		">>>>>>OUTPUT START<<<<<<\n# message\n\n---\n\n" +
+		diagnosticsStart + "\n" + `{"valid":false,"diagnostics":[{"line":1,"code":"TITLE","severity":"error","message":"bad"}]}` + "\n" + diagnosticsEnd + "\n\n" +
+		"❌ Found 1 contract violation(s):"
+
+	result := commandResult(output)
+	if len(result.Content) != 2 {
+		t.Fatalf("Expected 2 content blocks, got %d", len(result.Content))
+	}
+	text := result.Content[0].Text
+	if strings.Contains(text, diagnosticsStart) || !strings.Contains(text, "---\n❌ Found 1") {
+		t.Errorf("Unexpected text block:\n%s", text)
+	}
+	if !strings.HasPrefix(text, "Error executing command") {
+		t.Error("The text block should still report the failure")
+	}
+	if !strings.HasPrefix(result.Content[1].Text, `{"valid":false`) {
+		t.Errorf("Unexpected JSON block: %s", result.Content[1].Text)
+	}
+}
+
+func TestCommandResult_TextOnly(t *testing.T) {
+	for _, output := range []string{
+		"no diagnostics",
+		diagnosticsStart + "\nnot json\n" + diagnosticsEnd,
+		diagnosticsStart + "\n{}",
+	} {
+		result := commandResult(output)
+		if len(result.Content) != 1 || result.Content[0].Text != output {
+			t.Errorf("commandResult(%q) = %+v, want the output unchanged", output, result.Content)
+		}
+	}
+}
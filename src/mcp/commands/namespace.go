@@ -0,0 +1,19 @@
+package main
+
+import "github.com/ready-to-release/eac/src/core/mcpserver"
+
+// serverNamespace is the name of this server in the tool namespace registry
+// (contracts/mcp/tools.yml)
+const serverNamespace = "commands"
+
+// auditSource marks the audit log entries of this server
+const auditSource = "mcp:" + serverNamespace
+
+// namespace advertises the tools under the names the registry assigns
+var namespace = mcpserver.NewNamespace(serverNamespace)
+
+// toolNames returns the names of the tools of this server, run-agent
+// included
+func toolNames() []string {
+	return append([]string{"run-agent"}, mcpserver.ToolNames(commandTools())...)
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ready-to-release/eac/src/core/audit"
+	"github.com/ready-to-release/eac/src/core/mcpserver"
+)
+
+// describeCommand is the src/commands main of a workspace with one command,
+// "docs lint"
+const describeCommand = `package main
+
+import (
+	"fmt"
+	"os"
+)
+
+func main() {
+	if len(os.Args) > 2 && os.Args[1] == "describe" {
+		fmt.Print(` + "`" + `{"commands":[{"name":"docs lint","parts":["docs","lint"],"is_leaf":true}]}` + "`" + `)
+		return
+	}
+	fmt.Println("ran", os.Args[1:])
+}
+`
+
+// namespaceRegistry gives "docs-" to another server, so the commands server
+// advertises docs lint as r2r-docs-lint
+const namespaceRegistry = `namespaces:
+  - server: commands
+    prefix: r2r-
+    default: true
+  - server: docs
+    prefix: docs-
+`
+
+func TestCallBeforeToolsList(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		filepath.Join("src", "commands", "go.mod"):     "module fake\n\ngo 1.21\n",
+		filepath.Join("src", "commands", "main.go"):    describeCommand,
+		filepath.Join("contracts", "mcp", "tools.yml"): namespaceRegistry,
+	}
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv("WORKSPACE_ROOT", root)
+	t.Setenv(audit.PathEnv, filepath.Join(t.TempDir(), "audit.jsonl"))
+
+	// A fresh server: no registry read and no tools/list yet
+	previousNamespace := namespace
+	namespace = mcpserver.NewNamespace(serverNamespace)
+	knownTools = atomic.Value{}
+	t.Cleanup(func() {
+		namespace = previousNamespace
+		knownTools = atomic.Value{}
+	})
+
+	call := func(arguments string) MCPResponse {
+		t.Helper()
+		var out bytes.Buffer
+		params := `{"name":"r2r-docs-lint","arguments":` + arguments + `}`
+		handleRequest(context.Background(), json.NewEncoder(&out), &MCPRequest{JSONRPC: "2.0", ID: 1, Method: "tools/call", Params: json.RawMessage(params)})
+		var resp MCPResponse
+		if err := json.Unmarshal(out.Bytes(), &resp); err != nil {
+			t.Fatalf("invalid response %q: %v", out.String(), err)
+		}
+		return resp
+	}
+
+	// The prefixed name resolves and the arguments are checked against the
+	// schema of docs lint
+	resp := call(`{"dry_run":"yes"}`)
+	if resp.Error == nil || resp.Error.Code != -32602 || !strings.Contains(resp.Error.Message, "dry_run") {
+		t.Fatalf("call with an invalid argument = %+v, want -32602 for dry_run", resp)
+	}
+
+	resp = call(`{"args":"--fix"}`)
+	if resp.Error != nil {
+		t.Fatalf("call = %+v", resp.Error)
+	}
+	data, _ := json.Marshal(resp.Result)
+	if !strings.Contains(string(data), "ran [docs lint --fix]") {
+		t.Errorf("result = %s, want the output of docs lint", data)
+	}
+}
@@ -0,0 +1,277 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MCP progress: tool calls with a progressToken in _meta receive
+// notifications/progress built from the typed progress events that commands
+// print when run with R2R_PROGRESS=json. The command output is streamed in
+// notifications too, with heartbeats while the command is quiet, so hanging
+// commands are visible before they finish.
+
+// progressEnv asks commands for machine-readable progress
+const progressEnv = "R2R_PROGRESS=json"
+
+// progressLinePrefix marks a progress event line in command output
+const progressLinePrefix = ">>>>>>PROGRESS "
+
+// progressMetaKey carries the full ProgressEvent in the notification _meta
+const progressMetaKey = "r2r/progress"
+
+// outputMetaKey carries streamed output and heartbeatMetaKey the elapsed
+// time of heartbeats in the notification _meta
+const (
+	outputMetaKey    = "r2r/output"
+	heartbeatMetaKey = "r2r/heartbeat"
+)
+
+// streamInterval is how often new output is sent; tests shorten it
+var streamInterval = time.Second
+
+// defaultHeartbeatInterval is how long a command may be quiet before a
+// heartbeat is sent; MCP_COMMAND_HEARTBEAT overrides it
+const defaultHeartbeatInterval = 15 * time.Second
+
+// ProgressEvent from src/commands/impl/commit/internal/progress.go
+type ProgressEvent struct {
+	Stage     string  `json:"stage"`
+	Ordinal   int     `json:"ordinal"`
+	Total     int     `json:"total"`
+	Percent   float64 `json:"percent"`
+	ElapsedMs int64   `json:"elapsed_ms"`
+	EtaMs     int64   `json:"eta_ms,omitempty"`
+	Message   string  `json:"message,omitempty"`
+}
+
+// RequestMeta is the _meta of a request
+type RequestMeta struct {
+	ProgressToken interface{} `json:"progressToken,omitempty"`
+}
+
+type ProgressNotificationParams struct {
+	ProgressToken interface{}            `json:"progressToken"`
+	Progress      float64                `json:"progress"`
+	Total         float64                `json:"total,omitempty"`
+	Message       string                 `json:"message,omitempty"`
+	Meta          map[string]interface{} `json:"_meta,omitempty"`
+}
+
+// HeartbeatInfo is the _meta of a heartbeat
+type HeartbeatInfo struct {
+	ElapsedMs int64 `json:"elapsed_ms"`
+}
+
+// progressReporter sends the progress of one tool call
+type progressReporter struct {
+	encoder *json.Encoder
+	token   interface{}
+
+	mu      sync.Mutex
+	last    float64
+	typed   bool
+	sent    time.Time
+	started time.Time
+}
+
+// newProgressReporter returns nil when the request did not ask for progress
+func newProgressReporter(encoder *json.Encoder, meta *RequestMeta) *progressReporter {
+	if meta == nil || meta.ProgressToken == nil {
+		return nil
+	}
+	now := time.Now()
+	return &progressReporter{encoder: encoder, token: meta.ProgressToken, last: -1, sent: now, started: now}
+}
+
+// Report sends event as notifications/progress. Progress must increase with
+// every notification, so events that do not advance it are dropped.
+func (p *progressReporter) Report(event ProgressEvent) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if event.Percent <= p.last {
+		return
+	}
+	p.typed = true
+	p.send(event.Percent, formatProgress(event), progressMetaKey, event)
+}
+
+// Output sends command output. It advances progress by the smallest possible
+// step, so the percentage of the last event stays as it was.
+func (p *progressReporter) Output(text string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.send(p.nextProgress(), strings.TrimRight(text, "\r\n"), outputMetaKey, text)
+}
+
+// Heartbeat sends a notification when nothing was sent for interval
+func (p *progressReporter) Heartbeat(interval time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if time.Since(p.sent) < interval {
+		return
+	}
+	elapsed := time.Since(p.started)
+	message := fmt.Sprintf("still running (%s elapsed)", elapsed.Round(time.Second))
+	p.send(p.nextProgress(), message, heartbeatMetaKey, HeartbeatInfo{ElapsedMs: elapsed.Milliseconds()})
+}
+
+// nextProgress returns the smallest progress above the last one
+func (p *progressReporter) nextProgress() float64 {
+	if p.last < 0 {
+		return 0
+	}
+	return math.Nextafter(p.last, math.Inf(1))
+}
+
+// send sends a notification; callers hold p.mu. Total is only set once a
+// typed event reported a percentage, so commands without typed progress
+// show as indeterminate.
+func (p *progressReporter) send(progress float64, message, metaKey string, meta interface{}) {
+	total := 0.0
+	if p.typed {
+		total = 100
+	}
+	p.last = progress
+	p.sent = time.Now()
+
+	sendNotification(p.encoder, "notifications/progress", ProgressNotificationParams{
+		ProgressToken: p.token,
+		Progress:      progress,
+		Total:         total,
+		Message:       message,
+		Meta:          map[string]interface{}{metaKey: meta},
+	})
+}
+
+// streamOutput sends the new output of filter every streamInterval, and a
+// heartbeat when the command was quiet for MCP_COMMAND_HEARTBEAT, until
+// stop is called
+func streamOutput(progress *progressReporter, filter *progressFilter) (stop func()) {
+	heartbeat := envDuration("MCP_COMMAND_HEARTBEAT", defaultHeartbeatInterval)
+	done := make(chan struct{})
+	finished := make(chan struct{})
+
+	go func() {
+		defer close(finished)
+		ticker := time.NewTicker(streamInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if text := filter.Unstreamed(); text != "" {
+					progress.Output(text)
+				} else if heartbeat > 0 {
+					progress.Heartbeat(heartbeat)
+				}
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		<-finished
+	}
+}
+
+// formatProgress renders an event as "[3/4] top-level: 0/1 done (12s elapsed, ~30s left)"
+func formatProgress(event ProgressEvent) string {
+	message := event.Message
+	if message == "" {
+		message = event.Stage
+	} else {
+		message = event.Stage + ": " + message
+	}
+
+	timing := fmt.Sprintf("%s elapsed", (time.Duration(event.ElapsedMs) * time.Millisecond).Round(time.Second))
+	if event.EtaMs > 0 {
+		timing += fmt.Sprintf(", ~%s left", (time.Duration(event.EtaMs) * time.Millisecond).Round(time.Second))
+	}
+
+	return fmt.Sprintf("[%d/%d] %s (%s)", event.Ordinal, event.Total, message, timing)
+}
+
+func envDuration(name string, fallback time.Duration) time.Duration {
+	if value, err := time.ParseDuration(os.Getenv(name)); err == nil {
+		return value
+	}
+	return fallback
+}
+
+// progressFilter collects command output, passing progress event lines to
+// report instead. Set it as both Stdout and Stderr so output stays ordered.
+type progressFilter struct {
+	mu      sync.Mutex
+	output  bytes.Buffer
+	pending []byte
+	report  func(ProgressEvent)
+	// streamed counts the bytes of output and pending already streamed
+	streamed int
+}
+
+func (f *progressFilter) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.pending = append(f.pending, p...)
+	for {
+		i := bytes.IndexByte(f.pending, '\n')
+		if i < 0 {
+			return len(p), nil
+		}
+		f.writeLine(f.pending[:i+1])
+		f.pending = f.pending[i+1:]
+	}
+}
+
+func (f *progressFilter) writeLine(line []byte) {
+	if data, ok := bytes.CutPrefix(line, []byte(progressLinePrefix)); ok {
+		var event ProgressEvent
+		if err := json.Unmarshal(data, &event); err == nil {
+			if f.report != nil {
+				f.report(event)
+			}
+			return
+		}
+	}
+	f.output.Write(line)
+}
+
+// Unstreamed returns the output not streamed yet and marks it streamed. An
+// incomplete last line is included, so a prompt waiting for input shows up,
+// unless it may still become a progress line.
+func (f *progressFilter) Unstreamed() string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	text := f.output.Bytes()[min(f.streamed, f.output.Len()):]
+	streamed := f.output.Len()
+	if len(f.pending) > 0 && !bytes.HasPrefix(f.pending, []byte(progressLinePrefix)) && !bytes.HasPrefix([]byte(progressLinePrefix), f.pending) {
+		partial := f.pending[max(f.streamed-f.output.Len(), 0):]
+		text = append(append([]byte{}, text...), partial...)
+		streamed += len(f.pending)
+	}
+	if streamed > f.streamed {
+		f.streamed = streamed
+	}
+	return string(text)
+}
+
+// Output returns the collected output without progress lines
+func (f *progressFilter) Output() []byte {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.output.Write(f.pending)
+	f.pending = nil
+	return f.output.Bytes()
+}
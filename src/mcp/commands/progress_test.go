@@ -0,0 +1,214 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// progressClient returns an encoder and the progress notifications written
+// to it
+func progressClient(t *testing.T) (*json.Encoder, <-chan ProgressNotificationParams) {
+	t.Helper()
+	reader, writer := io.Pipe()
+	t.Cleanup(func() { writer.Close() })
+	notifications := make(chan ProgressNotificationParams, 100)
+	go func() {
+		decoder := json.NewDecoder(reader)
+		for {
+			var msg struct {
+				Method string                     `json:"method"`
+				Params ProgressNotificationParams `json:"params"`
+			}
+			if err := decoder.Decode(&msg); err != nil {
+				return
+			}
+			if msg.Method != "notifications/progress" {
+				t.Errorf("unexpected %s", msg.Method)
+			}
+			notifications <- msg.Params
+		}
+	}()
+	return json.NewEncoder(writer), notifications
+}
+
+// receive returns the notifications sent within wait
+func receive(notifications <-chan ProgressNotificationParams, wait time.Duration) []ProgressNotificationParams {
+	var received []ProgressNotificationParams
+	timeout := time.After(wait)
+	for {
+		select {
+		case notification := <-notifications:
+			received = append(received, notification)
+		case <-timeout:
+			return received
+		}
+	}
+}
+
+// kind returns the _meta key of a notification: output, progress or heartbeat
+func kind(notification ProgressNotificationParams) string {
+	for key := range notification.Meta {
+		return strings.TrimPrefix(key, "r2r/")
+	}
+	return ""
+}
+
+// checkProgress checks that every notification carries token and advances
+// the progress
+func checkProgress(t *testing.T, notifications []ProgressNotificationParams, token interface{}) {
+	t.Helper()
+	last := -1.0
+	for i, notification := range notifications {
+		if notification.ProgressToken != token {
+			t.Errorf("notification %d has token %v, want %v", i, notification.ProgressToken, token)
+		}
+		if notification.Progress <= last {
+			t.Errorf("notification %d has progress %v after %v", i, notification.Progress, last)
+		}
+		last = notification.Progress
+	}
+}
+
+func TestProgressReporter(t *testing.T) {
+	encoder, notifications := progressClient(t)
+	progress := newProgressReporter(encoder, &RequestMeta{ProgressToken: "call-7"})
+	filter := &progressFilter{report: progress.Report}
+
+	write := func(text string) {
+		if _, err := filter.Write([]byte(text)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("building\n")
+	progress.Output(filter.Unstreamed())
+	write(progressLinePrefix + `{"stage":"module","ordinal":1,"total":4,"percent":25,"elapsed_ms":2000}` + "\n")
+	// Events that do not advance the progress are dropped
+	write(progressLinePrefix + `{"stage":"module","ordinal":1,"total":4,"percent":10}` + "\n")
+	write("done\n")
+	progress.Output(filter.Unstreamed())
+	progress.Heartbeat(0)
+
+	received := receive(notifications, 200*time.Millisecond)
+	checkProgress(t, received, "call-7")
+	want := []struct {
+		kind    string
+		message string
+		total   float64
+	}{
+		// Without a typed event the progress is indeterminate
+		{"output", "building", 0},
+		{"progress", "[1/4] module (2s elapsed)", 100},
+		{"output", "done", 100},
+		{"heartbeat", "still running (0s elapsed)", 100},
+	}
+	if len(received) != len(want) {
+		t.Fatalf("received %d notifications, want %d: %+v", len(received), len(want), received)
+	}
+	for i, w := range want {
+		if kind(received[i]) != w.kind || received[i].Message != w.message || received[i].Total != w.total {
+			t.Errorf("notification %d = %s %q (total %v), want %s %q (total %v)", i, kind(received[i]), received[i].Message, received[i].Total, w.kind, w.message, w.total)
+		}
+	}
+	if received[1].Progress != 25 || received[2].Progress <= 25 || received[2].Progress > 25.000001 {
+		t.Errorf("progress = %v, %v; want 25 and the next value above it", received[1].Progress, received[2].Progress)
+	}
+	if got := string(filter.Output()); got != "building\ndone\n" {
+		t.Errorf("output = %q, want the output without progress lines", got)
+	}
+}
+
+func TestProgressReporterWithoutToken(t *testing.T) {
+	if progress := newProgressReporter(json.NewEncoder(io.Discard), &RequestMeta{}); progress != nil {
+		t.Errorf("newProgressReporter without a token = %+v, want nil", progress)
+	}
+	var progress *progressReporter
+	progress.Report(ProgressEvent{Percent: 50})
+}
+
+// fakeCommand is the src/commands main of a workspace: it prints output,
+// typed progress when asked for it, and is quiet in between
+const fakeCommand = `package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+func main() {
+	event := func(ordinal int) {
+		if os.Getenv("R2R_PROGRESS") == "json" {
+			fmt.Printf(">>>>>>PROGRESS {\"stage\":\"generate\",\"ordinal\":%d,\"total\":2,\"percent\":%d}\n", ordinal, ordinal*50)
+		}
+	}
+	fmt.Println("starting", os.Args[1:])
+	time.Sleep(300 * time.Millisecond)
+	event(1)
+	time.Sleep(500 * time.Millisecond)
+	event(2)
+	fmt.Println("finished")
+}
+`
+
+func TestExecCommandProgress(t *testing.T) {
+	root := t.TempDir()
+	commands := filepath.Join(root, "src", "commands")
+	if err := os.MkdirAll(commands, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range map[string]string{"go.mod": "module fake\n\ngo 1.21\n", "main.go": fakeCommand} {
+		if err := os.WriteFile(filepath.Join(commands, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv("WORKSPACE_ROOT", root)
+	t.Setenv("MCP_COMMAND_HEARTBEAT", "100ms")
+	t.Setenv("MCP_COMMAND_MAX_RUNTIME", "")
+	interval := streamInterval
+	streamInterval = 20 * time.Millisecond
+	t.Cleanup(func() { streamInterval = interval })
+
+	encoder, notifications := progressClient(t)
+	progress := newProgressReporter(encoder, &RequestMeta{ProgressToken: 42.0})
+	output := execCommand(context.Background(), "", "fake run", "--all", nil, progress)
+	if output != "starting [fake run --all]\nfinished" {
+		t.Errorf("output = %q", output)
+	}
+
+	// Heartbeats stop with the command: nothing arrives after it returned
+	// but the notifications still being decoded
+	sent := receive(notifications, 50*time.Millisecond)
+	if late := receive(notifications, 400*time.Millisecond); len(late) > 0 {
+		t.Errorf("notifications after the command finished: %+v", late)
+	}
+	checkProgress(t, sent, 42.0)
+
+	// Heartbeats keep the command visible while it compiles and while it is
+	// quiet, the output is streamed before the first event and the events
+	// arrive in order
+	var kinds []string
+	for _, notification := range sent {
+		if kinds == nil || kinds[len(kinds)-1] != kind(notification) {
+			kinds = append(kinds, kind(notification))
+		}
+	}
+	got := strings.Join(kinds, ",")
+	if output := strings.Index(got, "output"); output < 0 || output > strings.Index(got, "progress") || !strings.Contains(got, "progress,heartbeat,progress") {
+		t.Errorf("notification kinds = %s, want output before the events and heartbeats between them", got)
+	}
+	var events []string
+	for _, notification := range sent {
+		if kind(notification) == "progress" {
+			events = append(events, notification.Message)
+		}
+	}
+	if len(events) != 2 || !strings.HasPrefix(events[0], "[1/2] generate") || !strings.HasPrefix(events[1], "[2/2] generate") {
+		t.Errorf("events = %q", events)
+	}
+}
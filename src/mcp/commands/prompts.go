@@ -0,0 +1,204 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Agent files in .claude/agents exposed as MCP prompts
+
+type Prompt struct {
+	Name        string           `json:"name"`
+	Description string           `json:"description,omitempty"`
+	Arguments   []PromptArgument `json:"arguments,omitempty"`
+}
+
+type PromptArgument struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Required    bool   `json:"required,omitempty"`
+}
+
+type GetPromptParams struct {
+	Name      string            `json:"name"`
+	Arguments map[string]string `json:"arguments"`
+}
+
+type PromptMessage struct {
+	Role    string  `json:"role"`
+	Content Content `json:"content"`
+}
+
+type GetPromptResult struct {
+	Description string          `json:"description,omitempty"`
+	Messages    []PromptMessage `json:"messages"`
+}
+
+// agentFrontmatter is the YAML header of an agent markdown file
+type agentFrontmatter struct {
+	Name        string           `yaml:"name"`
+	Description string           `yaml:"description"`
+	Model       string           `yaml:"model"`
+	Arguments   []PromptArgument `yaml:"arguments"`
+}
+
+type agentFile struct {
+	Frontmatter agentFrontmatter
+	Body        string
+}
+
+// defaultPromptArgument is offered when an agent declares no arguments
+var defaultPromptArgument = PromptArgument{
+	Name:        "context",
+	Description: "Context appended to the agent instructions (optional)",
+}
+
+// getAgentPrompts lists all agents in .claude/agents as prompts
+func getAgentPrompts() []Prompt {
+	agents := loadAgents("")
+
+	names := make([]string, 0, len(agents))
+	for name := range agents {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	prompts := make([]Prompt, 0, len(names))
+	for _, name := range names {
+		agent := agents[name]
+		prompts = append(prompts, Prompt{
+			Name:        name,
+			Description: agent.Frontmatter.Description,
+			Arguments:   promptArguments(agent.Frontmatter),
+		})
+	}
+
+	return prompts
+}
+
+// getAgentPrompt renders a single agent prompt with the given arguments
+func getAgentPrompt(params *GetPromptParams) (*GetPromptResult, error) {
+	agent, ok := loadAgents("")[params.Name]
+	if !ok {
+		return nil, fmt.Errorf("prompt not found: %s", params.Name)
+	}
+
+	for _, arg := range promptArguments(agent.Frontmatter) {
+		if arg.Required && params.Arguments[arg.Name] == "" {
+			return nil, fmt.Errorf("missing required argument: %s", arg.Name)
+		}
+	}
+
+	return &GetPromptResult{
+		Description: agent.Frontmatter.Description,
+		Messages: []PromptMessage{{
+			Role: "user",
+			Content: Content{
+				Type: "text",
+				Text: renderAgentPrompt(agent, params.Arguments),
+			},
+		}},
+	}, nil
+}
+
+func promptArguments(fm agentFrontmatter) []PromptArgument {
+	if len(fm.Arguments) == 0 {
+		return []PromptArgument{defaultPromptArgument}
+	}
+	return fm.Arguments
+}
+
+// renderAgentPrompt substitutes {{name}} placeholders in the agent body.
+// Arguments without a placeholder are appended as labelled sections.
+func renderAgentPrompt(agent agentFile, args map[string]string) string {
+	text := agent.Body
+
+	names := make([]string, 0, len(args))
+	for name := range args {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var extra strings.Builder
+	for _, name := range names {
+		value := args[name]
+		placeholder := "{{" + name + "}}"
+		if strings.Contains(text, placeholder) {
+			text = strings.ReplaceAll(text, placeholder, value)
+			continue
+		}
+		if value == "" {
+			continue
+		}
+		extra.WriteString(fmt.Sprintf("\n\n## %s\n\n%s", name, value))
+	}
+
+	return strings.TrimSpace(strings.TrimSpace(text) + extra.String())
+}
+
+// loadAgents reads all agent markdown files of a workspace keyed by prompt name
+func loadAgents(workspace string) map[string]agentFile {
+	agents := make(map[string]agentFile)
+
+	repoRoot := findRepoRoot(workspace)
+	if repoRoot == "" {
+		return agents
+	}
+
+	files, err := filepath.Glob(filepath.Join(repoRoot, ".claude", "agents", "*.md"))
+	if err != nil {
+		return agents
+	}
+
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			fmt.Fprintf(stderr, "Error reading agent %s: %v\n", file, err)
+			continue
+		}
+
+		agent, err := parseAgentFile(string(content))
+		if err != nil {
+			fmt.Fprintf(stderr, "Error parsing agent %s: %v\n", file, err)
+			continue
+		}
+
+		name := agent.Frontmatter.Name
+		if name == "" {
+			name = strings.TrimSuffix(filepath.Base(file), ".md")
+		}
+		agents[name] = agent
+	}
+
+	return agents
+}
+
+// parseAgentFile splits an agent file into frontmatter and body
+func parseAgentFile(content string) (agentFile, error) {
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+
+	if !strings.HasPrefix(content, "---\n") {
+		return agentFile{Body: content}, nil
+	}
+
+	rest := content[len("---\n"):]
+	end := strings.Index(rest, "\n---")
+	if end < 0 {
+		return agentFile{}, fmt.Errorf("unterminated frontmatter")
+	}
+
+	var fm agentFrontmatter
+	if err := yaml.Unmarshal([]byte(rest[:end]), &fm); err != nil {
+		return agentFile{}, fmt.Errorf("invalid frontmatter: %w", err)
+	}
+
+	body := rest[end+len("\n---"):]
+	body = strings.TrimPrefix(body, "\n")
+
+	return agentFile{Frontmatter: fm, Body: body}, nil
+}
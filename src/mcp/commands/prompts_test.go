@@ -0,0 +1,289 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// promptWorkspace creates a workspace root with the given agent files of
+// .claude/agents
+func promptWorkspace(t *testing.T, agents map[string]string) {
+	t.Helper()
+	root := t.TempDir()
+	dir := filepath.Join(root, ".claude", "agents")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range agents {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv("WORKSPACE_ROOT", root)
+}
+
+// reviewerAgent declares a required and an optional argument, one of them
+// used as a placeholder
+const reviewerAgent = `---
+name: reviewer
+description: Reviews a change
+arguments:
+  - name: diff
+    description: The diff to review
+    required: true
+  - name: focus
+    description: What to look at
+---
+Review this change:
+
+{{diff}}
+`
+
+func TestParseAgentFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    agentFile
+		wantErr string
+	}{
+		{
+			name:    "without frontmatter",
+			content: "Just instructions.\n",
+			want:    agentFile{Body: "Just instructions.\n"},
+		},
+		{
+			name:    "frontmatter",
+			content: "---\nname: writer\ndescription: Writes\nmodel: sonnet\n---\nWrite.\n",
+			want:    agentFile{Frontmatter: agentFrontmatter{Name: "writer", Description: "Writes", Model: "sonnet"}, Body: "Write.\n"},
+		},
+		{
+			name:    "windows line endings",
+			content: "---\r\nname: writer\r\n---\r\nWrite.\r\n",
+			want:    agentFile{Frontmatter: agentFrontmatter{Name: "writer"}, Body: "Write.\n"},
+		},
+		{
+			name:    "arguments",
+			content: "---\narguments:\n  - name: diff\n    required: true\n---\n{{diff}}",
+			want:    agentFile{Frontmatter: agentFrontmatter{Arguments: []PromptArgument{{Name: "diff", Required: true}}}, Body: "{{diff}}"},
+		},
+		{
+			name:    "empty body",
+			content: "---\nname: empty\n---",
+			want:    agentFile{Frontmatter: agentFrontmatter{Name: "empty"}},
+		},
+		{
+			name:    "unterminated frontmatter",
+			content: "---\nname: writer\nWrite.\n",
+			wantErr: "unterminated frontmatter",
+		},
+		{
+			name:    "invalid frontmatter",
+			content: "---\nname: [writer\n---\nWrite.\n",
+			wantErr: "invalid frontmatter",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseAgentFile(tt.content)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("parseAgentFile() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseAgentFile() = %+v, %v; want %+v", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadAgents(t *testing.T) {
+	promptWorkspace(t, map[string]string{
+		"reviewer.md": reviewerAgent,
+		// Without a name the file name is the prompt name
+		"plain.md":  "Plain instructions.",
+		"broken.md": "---\nname: broken\n",
+		"notes.txt": "not an agent",
+	})
+
+	agents := loadAgents("")
+	var names []string
+	for name := range agents {
+		names = append(names, name)
+	}
+	if len(agents) != 2 || agents["reviewer"].Frontmatter.Description != "Reviews a change" || agents["plain"].Body != "Plain instructions." {
+		t.Errorf("loadAgents() = %v, want reviewer and plain", names)
+	}
+}
+
+func TestRenderAgentPrompt(t *testing.T) {
+	agent := agentFile{Body: "Review {{diff}} twice: {{diff}}\n"}
+
+	tests := []struct {
+		name string
+		args map[string]string
+		want string
+	}{
+		{"no arguments", nil, "Review {{diff}} twice: {{diff}}"},
+		{"placeholder", map[string]string{"diff": "a.go"}, "Review a.go twice: a.go"},
+		{"empty placeholder", map[string]string{"diff": ""}, "Review  twice:"},
+		{
+			name: "arguments without a placeholder are appended in name order",
+			args: map[string]string{"diff": "a.go", "zeta": "last", "focus": "naming"},
+			want: "Review a.go twice: a.go\n\n## focus\n\nnaming\n\n## zeta\n\nlast",
+		},
+		{"empty appended argument", map[string]string{"focus": ""}, "Review {{diff}} twice: {{diff}}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := renderAgentPrompt(agent, tt.args); got != tt.want {
+				t.Errorf("renderAgentPrompt() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetAgentPrompt(t *testing.T) {
+	promptWorkspace(t, map[string]string{
+		"reviewer.md": reviewerAgent,
+		"writer.md":   "---\nname: writer\n---\nWrite.\n",
+	})
+
+	tests := []struct {
+		name      string
+		prompt    string
+		arguments map[string]string
+		want      string
+		wantErr   string
+	}{
+		{
+			name:      "required argument",
+			prompt:    "reviewer",
+			arguments: map[string]string{"diff": "+added"},
+			want:      "Review this change:\n\n+added",
+		},
+		{
+			name:      "optional argument",
+			prompt:    "reviewer",
+			arguments: map[string]string{"diff": "+added", "focus": "tests"},
+			want:      "Review this change:\n\n+added\n\n## focus\n\ntests",
+		},
+		{
+			// Arguments the agent does not declare are passed on as sections
+			name:      "unknown argument",
+			prompt:    "reviewer",
+			arguments: map[string]string{"diff": "+added", "ticket": "R2R-7"},
+			want:      "Review this change:\n\n+added\n\n## ticket\n\nR2R-7",
+		},
+		{
+			name:    "missing required argument",
+			prompt:  "reviewer",
+			wantErr: "missing required argument: diff",
+		},
+		{
+			name:      "empty required argument",
+			prompt:    "reviewer",
+			arguments: map[string]string{"diff": ""},
+			wantErr:   "missing required argument: diff",
+		},
+		{
+			name:      "default context argument",
+			prompt:    "writer",
+			arguments: map[string]string{"context": "A changelog"},
+			want:      "Write.\n\n## context\n\nA changelog",
+		},
+		{
+			name:    "unknown prompt",
+			prompt:  "editor",
+			wantErr: "prompt not found: editor",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := getAgentPrompt(&GetPromptParams{Name: tt.prompt, Arguments: tt.arguments})
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("getAgentPrompt() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(result.Messages) != 1 || result.Messages[0].Role != "user" || result.Messages[0].Content.Text != tt.want {
+				t.Errorf("getAgentPrompt() = %+v, want one user message %q", result.Messages, tt.want)
+			}
+		})
+	}
+}
+
+// promptRequest sends a prompts request through handleRequest and returns
+// the raw response
+func promptRequest(t *testing.T, method, params string) (json.RawMessage, *MCPError) {
+	t.Helper()
+	var out bytes.Buffer
+	handleRequest(context.Background(), json.NewEncoder(&out), &MCPRequest{JSONRPC: "2.0", ID: 1, Method: method, Params: json.RawMessage(params)})
+	var resp struct {
+		Result json.RawMessage `json:"result"`
+		Error  *MCPError       `json:"error"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &resp); err != nil {
+		t.Fatalf("invalid response %q: %v", out.String(), err)
+	}
+	return resp.Result, resp.Error
+}
+
+func TestPromptsHandlers(t *testing.T) {
+	promptWorkspace(t, map[string]string{
+		"reviewer.md": reviewerAgent,
+		"writer.md":   "---\nname: writer\ndescription: Writes\n---\nWrite.\n",
+	})
+
+	result, rpcErr := promptRequest(t, "prompts/list", `{}`)
+	if rpcErr != nil {
+		t.Fatalf("prompts/list error = %+v", rpcErr)
+	}
+	var list struct {
+		Prompts []Prompt `json:"prompts"`
+	}
+	if err := json.Unmarshal(result, &list); err != nil {
+		t.Fatal(err)
+	}
+	want := []Prompt{
+		{Name: "reviewer", Description: "Reviews a change", Arguments: []PromptArgument{
+			{Name: "diff", Description: "The diff to review", Required: true},
+			{Name: "focus", Description: "What to look at"},
+		}},
+		// Agents without arguments offer the context argument
+		{Name: "writer", Description: "Writes", Arguments: []PromptArgument{defaultPromptArgument}},
+	}
+	if !reflect.DeepEqual(list.Prompts, want) {
+		t.Errorf("prompts/list = %+v, want %+v", list.Prompts, want)
+	}
+
+	result, rpcErr = promptRequest(t, "prompts/get", `{"name":"reviewer","arguments":{"diff":"+added"}}`)
+	var prompt GetPromptResult
+	if rpcErr != nil || json.Unmarshal(result, &prompt) != nil || prompt.Description != "Reviews a change" || len(prompt.Messages) != 1 {
+		t.Errorf("prompts/get = %s, %+v", result, rpcErr)
+	}
+
+	for params, message := range map[string]string{
+		`{"name":"reviewer"}`:               "missing required argument: diff",
+		`{"name":"editor"}`:                 "prompt not found: editor",
+		`{"name":"reviewer","arguments":7}`: "Invalid params",
+	} {
+		if _, rpcErr := promptRequest(t, "prompts/get", params); rpcErr == nil || rpcErr.Code != -32602 || rpcErr.Message != message {
+			t.Errorf("prompts/get %s error = %+v, want -32602 %q", params, rpcErr, message)
+		}
+	}
+}
@@ -0,0 +1,399 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ready-to-release/eac/src/core/mcpserver"
+	"github.com/ready-to-release/eac/src/core/repository"
+)
+
+// MCP sampling: ask the client (host editor) to generate with its own model.
+// Falls back to the local claude CLI when the client does not support
+// sampling or when MCP_SAMPLING=off. run-agent samples directly; commit-ai
+// runs as a separate process and samples through a samplingBridge.
+
+const samplingTimeout = 5 * time.Minute
+
+// IncomingMessage is any JSON-RPC message read from stdin: a request from
+// the client, or a response to a request sent by this server.
+type IncomingMessage struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      interface{}     `json:"id"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *MCPError       `json:"error,omitempty"`
+}
+
+type SamplingMessage struct {
+	Role    string  `json:"role"`
+	Content Content `json:"content"`
+}
+
+type ModelHint struct {
+	Name string `json:"name"`
+}
+
+type ModelPreferences struct {
+	Hints []ModelHint `json:"hints,omitempty"`
+}
+
+type CreateMessageParams struct {
+	Messages         []SamplingMessage `json:"messages"`
+	ModelPreferences *ModelPreferences `json:"modelPreferences,omitempty"`
+	SystemPrompt     string            `json:"systemPrompt,omitempty"`
+	MaxTokens        int               `json:"maxTokens"`
+}
+
+type CreateMessageResult struct {
+	Role    string  `json:"role"`
+	Content Content `json:"content"`
+	Model   string  `json:"model"`
+}
+
+type InitializeParams struct {
+	Capabilities struct {
+		Sampling *struct{} `json:"sampling,omitempty"`
+	} `json:"capabilities"`
+}
+
+// Sampler tracks client sampling support and in-flight sampling requests
+type Sampler struct {
+	mu        sync.Mutex
+	supported bool
+	nextID    int
+	pending   map[string]chan *IncomingMessage
+}
+
+var sampler = &Sampler{pending: make(map[string]chan *IncomingMessage)}
+
+// SetSupported records whether the client advertised the sampling capability
+func (s *Sampler) SetSupported(supported bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.supported = supported
+}
+
+// Enabled reports whether generation should go through MCP sampling
+func (s *Sampler) Enabled() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.supported && samplingFlagEnabled()
+}
+
+// Deliver routes a client response to the waiting sampling request.
+// Returns false if the message is not a response to one of our requests.
+func (s *Sampler) Deliver(msg *IncomingMessage) bool {
+	if msg.Method != "" || msg.ID == nil {
+		return false
+	}
+
+	key := fmt.Sprint(msg.ID)
+
+	s.mu.Lock()
+	ch, ok := s.pending[key]
+	delete(s.pending, key)
+	s.mu.Unlock()
+
+	if !ok {
+		return false
+	}
+	ch <- msg
+	return true
+}
+
+// Cancel fails all in-flight sampling requests (used on shutdown)
+func (s *Sampler) Cancel() {
+	s.mu.Lock()
+	pending := s.pending
+	s.pending = make(map[string]chan *IncomingMessage)
+	s.mu.Unlock()
+
+	for _, ch := range pending {
+		ch <- &IncomingMessage{Error: &MCPError{Code: -32800, Message: "server shutting down"}}
+	}
+}
+
+// CreateMessage sends sampling/createMessage to the client and waits for the
+// result. Cancelling ctx cancels the sampling request at the client too.
+func (s *Sampler) CreateMessage(ctx context.Context, encoder *json.Encoder, params CreateMessageParams) (*CreateMessageResult, error) {
+	s.mu.Lock()
+	s.nextID++
+	id := fmt.Sprintf("sampling-%d", s.nextID)
+	ch := make(chan *IncomingMessage, 1)
+	s.pending[id] = ch
+	s.mu.Unlock()
+
+	sendRequest(encoder, id, "sampling/createMessage", params)
+
+	select {
+	case msg := <-ch:
+		if msg.Error != nil {
+			return nil, fmt.Errorf("sampling failed: %s", msg.Error.Message)
+		}
+		var result CreateMessageResult
+		if err := json.Unmarshal(msg.Result, &result); err != nil {
+			return nil, fmt.Errorf("invalid sampling result: %w", err)
+		}
+		return &result, nil
+
+	case <-ctx.Done():
+		s.mu.Lock()
+		delete(s.pending, id)
+		s.mu.Unlock()
+		sendNotification(encoder, "notifications/cancelled", CancelledParams{RequestID: id, Reason: "tool call cancelled"})
+		return nil, ctx.Err()
+
+	case <-time.After(samplingTimeout):
+		s.mu.Lock()
+		delete(s.pending, id)
+		s.mu.Unlock()
+		return nil, fmt.Errorf("sampling timed out after %s", samplingTimeout)
+	}
+}
+
+func samplingFlagEnabled() bool {
+	switch strings.ToLower(os.Getenv("MCP_SAMPLING")) {
+	case "off", "false", "0", "disabled":
+		return false
+	}
+	return true
+}
+
+// runAgentTool is the tool definition for generating with an agent prompt
+func runAgentTool() Tool {
+	tool := Tool{
+		Name:        "run-agent",
+		Description: "Run a .claude/agents prompt and return the generated text (uses MCP sampling when available, otherwise the claude CLI)",
+		InputSchema: InputSchema{
+			Type: "object",
+			Properties: map[string]Property{
+				"agent": {
+					Type:        "string",
+					Description: "Agent name (see prompts/list)",
+				},
+				"input": {
+					Type:        "string",
+					Description: "Input appended to the agent instructions",
+				},
+			},
+			Required: []string{"agent", "input"},
+		},
+	}
+	addWorkspaceProperty(&tool.InputSchema)
+	return tool
+}
+
+// runAgentArgs are the arguments of the run-agent tool
+type runAgentArgs struct {
+	Agent     string `json:"agent"`
+	Input     string `json:"input"`
+	Workspace string `json:"workspace"`
+}
+
+// callRunAgent generates text for an agent prompt
+func callRunAgent(ctx context.Context, encoder *json.Encoder, params *CallToolParams) (ToolResult, error) {
+	var args runAgentArgs
+	if err := mcpserver.DecodeArguments(params.Arguments, &args); err != nil {
+		return ToolResult{}, err
+	}
+
+	repoRoot, err := repository.FindWorkspaceRoot(args.Workspace)
+	if err != nil {
+		return textResult(fmt.Sprintf("Error: Could not find repository root: %v", err)), nil
+	}
+
+	agent, ok := loadAgents(args.Workspace)[args.Agent]
+	if !ok {
+		return textResult(fmt.Sprintf("Error: agent not found: %s", args.Agent)), nil
+	}
+
+	var output string
+	if sampler.Enabled() {
+		output, err = generateWithSampling(ctx, encoder, agent, args.Input)
+	} else {
+		output, err = generateWithCLI(ctx, agent, args.Input, repoRoot)
+	}
+	if err != nil {
+		return textResult(fmt.Sprintf("Error running agent '%s': %v", args.Agent, err)), nil
+	}
+
+	return textResult(strings.TrimSpace(output)), nil
+}
+
+func generateWithSampling(ctx context.Context, encoder *json.Encoder, agent agentFile, input string) (string, error) {
+	params := CreateMessageParams{
+		SystemPrompt: strings.TrimSpace(agent.Body),
+		Messages: []SamplingMessage{{
+			Role:    "user",
+			Content: Content{Type: "text", Text: input},
+		}},
+		MaxTokens: 4096,
+	}
+	if agent.Frontmatter.Model != "" {
+		params.ModelPreferences = &ModelPreferences{
+			Hints: []ModelHint{{Name: agent.Frontmatter.Model}},
+		}
+	}
+
+	result, err := sampler.CreateMessage(ctx, encoder, params)
+	if err != nil {
+		return "", err
+	}
+	return result.Content.Text, nil
+}
+
+// generateWithCLI invokes the claude CLI in repoRoot the same way the commit pipeline does
+func generateWithCLI(ctx context.Context, agent agentFile, input string, repoRoot string) (string, error) {
+	if _, err := exec.LookPath("claude"); err != nil {
+		return "", fmt.Errorf("client does not support sampling and claude CLI not found in PATH")
+	}
+
+	args := []string{
+		"--print",
+		"--settings", `{"includeCoAuthoredBy":false,"disableAllHooks":true}`,
+	}
+	if agent.Frontmatter.Model != "" {
+		args = append(args, "--model", agent.Frontmatter.Model)
+	}
+
+	fullPrompt := agent.Body + "\n\n>>>>>>>>>>INPUT STARTS NOW<<<<<<<<<<<\n\n" + input
+
+	cmd := exec.Command("claude", args...)
+	cmd.Stdin = strings.NewReader(fullPrompt)
+	cmd.Dir = repoRoot
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := lifecycle.RunContext(ctx, cmd); err != nil {
+		return "", fmt.Errorf("claude CLI failed: %w\nStderr: %s", err, stderr.String())
+	}
+
+	return stdout.String(), nil
+}
+
+// samplingCommands are the commands that generate through the sampling
+// bridge; they read the bridge from the environment (see src/core/mcpserver)
+var samplingCommands = map[string]bool{
+	"commit ai": true,
+}
+
+// samplingBridge serves the sampling endpoint of one command run on the
+// loopback interface, forwarding each request to the client with
+// sampling/createMessage
+type samplingBridge struct {
+	ctx      context.Context
+	encoder  *json.Encoder
+	token    string
+	listener net.Listener
+	server   *http.Server
+}
+
+// commandSampling returns the environment that lets commandName sample
+// through the client, and the function that stops the bridge. Without
+// sampling the environment is empty and the command uses its own provider.
+func commandSampling(ctx context.Context, encoder *json.Encoder, commandName string) ([]string, func(), error) {
+	if !samplingCommands[commandName] || !sampler.Enabled() {
+		return nil, func() {}, nil
+	}
+	bridge, err := startSamplingBridge(ctx, encoder)
+	if err != nil {
+		return nil, nil, err
+	}
+	return bridge.Env(), bridge.Close, nil
+}
+
+// startSamplingBridge serves a sampling endpoint until Close. Requests are
+// cancelled with ctx, the context of the tool call.
+func startSamplingBridge(ctx context.Context, encoder *json.Encoder) (*samplingBridge, error) {
+	secret := make([]byte, 16)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, fmt.Errorf("failed to create sampling token: %w", err)
+	}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, fmt.Errorf("failed to start sampling endpoint: %w", err)
+	}
+
+	bridge := &samplingBridge{ctx: ctx, encoder: encoder, token: hex.EncodeToString(secret), listener: listener}
+	bridge.server = &http.Server{Handler: bridge, ReadHeaderTimeout: 10 * time.Second}
+	go bridge.server.Serve(listener)
+	return bridge, nil
+}
+
+// Env returns the variables that point a command at the bridge
+func (b *samplingBridge) Env() []string {
+	return []string{
+		mcpserver.SamplingURLEnv + "=http://" + b.listener.Addr().String(),
+		mcpserver.SamplingTokenEnv + "=" + b.token,
+	}
+}
+
+// Close stops the bridge, failing the requests still waiting for the client
+func (b *samplingBridge) Close() {
+	b.server.Close()
+}
+
+func (b *samplingBridge) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	respond := func(status int, response mcpserver.SamplingResponse) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(response)
+	}
+
+	if subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), []byte("Bearer "+b.token)) != 1 {
+		respond(http.StatusUnauthorized, mcpserver.SamplingResponse{Error: "invalid sampling token"})
+		return
+	}
+	if r.Method != http.MethodPost {
+		respond(http.StatusMethodNotAllowed, mcpserver.SamplingResponse{Error: "use POST"})
+		return
+	}
+	var req mcpserver.SamplingRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respond(http.StatusBadRequest, mcpserver.SamplingResponse{Error: fmt.Sprintf("invalid sampling request: %v", err)})
+		return
+	}
+
+	params := CreateMessageParams{
+		Messages: []SamplingMessage{{
+			Role:    "user",
+			Content: Content{Type: "text", Text: req.Input},
+		}},
+		MaxTokens: req.MaxTokens,
+	}
+	if params.MaxTokens <= 0 {
+		params.MaxTokens = 4096
+	}
+	if req.Model != "" {
+		params.ModelPreferences = &ModelPreferences{Hints: []ModelHint{{Name: req.Model}}}
+	}
+
+	// Stop waiting for the client when either the tool call or the
+	// command's request ends
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+	defer context.AfterFunc(b.ctx, cancel)()
+
+	result, err := sampler.CreateMessage(ctx, b.encoder, params)
+	if err != nil {
+		respond(http.StatusBadGateway, mcpserver.SamplingResponse{Error: err.Error()})
+		return
+	}
+	respond(http.StatusOK, mcpserver.SamplingResponse{Text: result.Content.Text, Model: result.Model})
+}
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ready-to-release/eac/src/core/mcpserver"
+)
+
+// useSamplingClient marks the client as supporting sampling and returns an
+// encoder whose sampling/createMessage requests answer handles; a nil answer
+// leaves the request waiting. Every message written is sent on the channel,
+// requests and responses alike.
+func useSamplingClient(t *testing.T, answer func(CreateMessageParams) *IncomingMessage) (*json.Encoder, <-chan IncomingMessage) {
+	t.Helper()
+	t.Setenv("MCP_SAMPLING", "")
+	sampler.SetSupported(true)
+	t.Cleanup(func() { sampler.SetSupported(false) })
+
+	reader, writer := io.Pipe()
+	t.Cleanup(func() { writer.Close() })
+	messages := make(chan IncomingMessage, 10)
+	go func() {
+		decoder := json.NewDecoder(reader)
+		for {
+			var msg IncomingMessage
+			if err := decoder.Decode(&msg); err != nil {
+				return
+			}
+			messages <- msg
+			if msg.Method != "sampling/createMessage" {
+				continue
+			}
+			var params CreateMessageParams
+			json.Unmarshal(msg.Params, &params)
+			if reply := answer(params); reply != nil {
+				reply.ID = msg.ID
+				sampler.Deliver(reply)
+			}
+		}
+	}()
+	return json.NewEncoder(writer), messages
+}
+
+// postSampling posts a request to the endpoint in env, as commit-ai does
+func postSampling(t *testing.T, env []string, token string, req mcpserver.SamplingRequest) (int, mcpserver.SamplingResponse) {
+	t.Helper()
+	var url string
+	for _, variable := range env {
+		if value, ok := strings.CutPrefix(variable, mcpserver.SamplingURLEnv+"="); ok {
+			url = value
+		}
+		if value, ok := strings.CutPrefix(variable, mcpserver.SamplingTokenEnv+"="); ok && token == "" {
+			token = value
+		}
+	}
+	body, _ := json.Marshal(req)
+	httpReq, _ := http.NewRequest(http.MethodPost, url, strings.NewReader(string(body)))
+	httpReq.Header.Set("Authorization", "Bearer "+token)
+	resp, err := http.DefaultClient.Do(httpReq)
+	if err != nil {
+		t.Fatalf("sampling request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	var result mcpserver.SamplingResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		t.Fatalf("invalid sampling response: %v", err)
+	}
+	return resp.StatusCode, result
+}
+
+func TestCommandSamplingRoundTrip(t *testing.T) {
+	var got CreateMessageParams
+	encoder, messages := useSamplingClient(t, func(params CreateMessageParams) *IncomingMessage {
+		got = params
+		result, _ := json.Marshal(CreateMessageResult{Role: "assistant", Content: Content{Type: "text", Text: "## core\n\nfeat: sampled"}, Model: "client-model"})
+		return &IncomingMessage{JSONRPC: "2.0", Result: result}
+	})
+
+	env, stop, err := commandSampling(context.Background(), encoder, "commit ai")
+	if err != nil || len(env) != 2 {
+		t.Fatalf("commandSampling = %q, %v", env, err)
+	}
+	defer stop()
+
+	status, response := postSampling(t, env, "", mcpserver.SamplingRequest{Input: "agent prompt\n\nmodule: core", Model: "haiku"})
+	if status != http.StatusOK || response.Text != "## core\n\nfeat: sampled" || response.Model != "client-model" {
+		t.Errorf("response = %d %+v", status, response)
+	}
+
+	request := <-messages
+	if request.Method != "sampling/createMessage" || !strings.HasPrefix(request.ID.(string), "sampling-") {
+		t.Errorf("request = %+v, want sampling/createMessage", request)
+	}
+	if len(got.Messages) != 1 || got.Messages[0].Content.Text != "agent prompt\n\nmodule: core" {
+		t.Errorf("messages = %+v", got.Messages)
+	}
+	if got.ModelPreferences == nil || got.ModelPreferences.Hints[0].Name != "haiku" || got.MaxTokens != 4096 {
+		t.Errorf("params = %+v, want the haiku hint and the default max tokens", got)
+	}
+}
+
+func TestCommandSamplingErrors(t *testing.T) {
+	encoder, _ := useSamplingClient(t, func(params CreateMessageParams) *IncomingMessage {
+		return &IncomingMessage{JSONRPC: "2.0", Error: &MCPError{Code: -1, Message: "user rejected sampling request"}}
+	})
+	env, stop, err := commandSampling(context.Background(), encoder, "commit ai")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stop()
+
+	status, response := postSampling(t, env, "", mcpserver.SamplingRequest{Input: "prompt"})
+	if status != http.StatusBadGateway || !strings.Contains(response.Error, "user rejected") {
+		t.Errorf("client error: response = %d %+v", status, response)
+	}
+
+	status, response = postSampling(t, env, "wrong", mcpserver.SamplingRequest{Input: "prompt"})
+	if status != http.StatusUnauthorized || response.Error == "" {
+		t.Errorf("wrong token: response = %d %+v", status, response)
+	}
+}
+
+func TestCommandSamplingCancelled(t *testing.T) {
+	encoder, messages := useSamplingClient(t, func(params CreateMessageParams) *IncomingMessage {
+		return nil
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	env, stop, err := commandSampling(ctx, encoder, "commit ai")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stop()
+
+	// Cancel the tool call once the request reached the client
+	go func() {
+		<-messages
+		cancel()
+	}()
+	status, response := postSampling(t, env, "", mcpserver.SamplingRequest{Input: "prompt"})
+	if status != http.StatusBadGateway || !strings.Contains(response.Error, "canceled") {
+		t.Errorf("response = %d %+v, want the cancellation", status, response)
+	}
+
+	select {
+	case msg := <-messages:
+		if msg.Method != "notifications/cancelled" {
+			t.Errorf("message = %+v, want notifications/cancelled", msg)
+		}
+	case <-time.After(5 * time.Second):
+		t.Error("the client was not told the request is cancelled")
+	}
+}
+
+func TestCommandSamplingFallback(t *testing.T) {
+	// Without sampling commands get no endpoint and use their own provider
+	tests := []struct {
+		name      string
+		supported bool
+		flag      string
+		command   string
+	}{
+		{name: "client without sampling", supported: false, command: "commit ai"},
+		{name: "MCP_SAMPLING off", supported: true, flag: "off", command: "commit ai"},
+		{name: "command without sampling", supported: true, command: "commit validate"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("MCP_SAMPLING", tt.flag)
+			sampler.SetSupported(tt.supported)
+			defer sampler.SetSupported(false)
+
+			env, stop, err := commandSampling(context.Background(), json.NewEncoder(io.Discard), tt.command)
+			if err != nil || env != nil {
+				t.Errorf("commandSampling = %q, %v; want no environment", env, err)
+			}
+			stop()
+		})
+	}
+}
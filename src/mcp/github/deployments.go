@@ -0,0 +1,148 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Delivery tools: repository_dispatch events and deployments with their
+// statuses, posted through the REST API with "gh api"
+
+// deploymentStates are the states a deployment status can report
+var deploymentStates = []string{"queued", "pending", "in_progress", "success", "failure", "error", "inactive"}
+
+// deploymentFields selects the fields returned for deployments
+const deploymentFields = "{id, ref, sha, environment, description, created_at, url}"
+
+type dispatchArgs struct {
+	EventType     string                 `json:"event_type"`
+	ClientPayload map[string]interface{} `json:"client_payload"`
+	Repo          string                 `json:"repo"`
+}
+
+func repoDispatch(args dispatchArgs) ToolResult {
+	if strings.TrimSpace(args.EventType) == "" {
+		return errorResult("event_type must not be empty")
+	}
+
+	payload := map[string]interface{}{"event_type": args.EventType}
+	if args.ClientPayload != nil {
+		// GitHub accepts at most 10 top-level properties
+		if len(args.ClientPayload) > 10 {
+			return errorResult("client_payload must have at most 10 top-level properties")
+		}
+		payload["client_payload"] = args.ClientPayload
+	}
+
+	output := postAPI(apiRepo(args.Repo)+"/dispatches", payload, "")
+	if text := output.Content[0].Text; text == "" {
+		return textResult(fmt.Sprintf("Dispatched repository_dispatch event %q", args.EventType))
+	}
+	return output
+}
+
+// deploymentCreateArgs leave optional booleans nil when not given, and
+// required_contexts nil rather than empty, which skips the checks
+type deploymentCreateArgs struct {
+	Ref                   string                 `json:"ref"`
+	Environment           string                 `json:"environment"`
+	Description           string                 `json:"description"`
+	Task                  string                 `json:"task"`
+	Payload               map[string]interface{} `json:"payload"`
+	RequiredContexts      []string               `json:"required_contexts"`
+	AutoMerge             *bool                  `json:"auto_merge"`
+	TransientEnvironment  *bool                  `json:"transient_environment"`
+	ProductionEnvironment *bool                  `json:"production_environment"`
+	Repo                  string                 `json:"repo"`
+}
+
+func deploymentCreate(args deploymentCreateArgs) ToolResult {
+	if args.Ref == "" || args.Environment == "" {
+		return errorResult("ref and environment must not be empty")
+	}
+
+	// auto_merge defaults to false: a deployment should ship the ref as given,
+	// not merge the default branch into it first
+	payload := map[string]interface{}{
+		"ref":         args.Ref,
+		"environment": args.Environment,
+		"auto_merge":  false,
+	}
+	for name, value := range map[string]string{"description": args.Description, "task": args.Task} {
+		if value != "" {
+			payload[name] = value
+		}
+	}
+	for name, value := range map[string]*bool{
+		"auto_merge":             args.AutoMerge,
+		"transient_environment":  args.TransientEnvironment,
+		"production_environment": args.ProductionEnvironment,
+	} {
+		if value != nil {
+			payload[name] = *value
+		}
+	}
+	if args.Payload != nil {
+		payload["payload"] = args.Payload
+	}
+	if args.RequiredContexts != nil {
+		payload["required_contexts"] = args.RequiredContexts
+	}
+
+	return postAPI(apiRepo(args.Repo)+"/deployments", payload, deploymentFields)
+}
+
+type deploymentStatusArgs struct {
+	DeploymentID   int64  `json:"deployment_id"`
+	State          string `json:"state"`
+	Description    string `json:"description"`
+	Environment    string `json:"environment"`
+	EnvironmentURL string `json:"environment_url"`
+	LogURL         string `json:"log_url"`
+	AutoInactive   *bool  `json:"auto_inactive"`
+	Repo           string `json:"repo"`
+}
+
+func deploymentStatus(args deploymentStatusArgs) ToolResult {
+	payload := map[string]interface{}{"state": args.State}
+	for name, value := range map[string]string{
+		"description":     args.Description,
+		"environment":     args.Environment,
+		"environment_url": args.EnvironmentURL,
+		"log_url":         args.LogURL,
+	} {
+		if value != "" {
+			payload[name] = value
+		}
+	}
+	if args.AutoInactive != nil {
+		payload["auto_inactive"] = *args.AutoInactive
+	}
+
+	endpoint := fmt.Sprintf("%s/deployments/%d/statuses", apiRepo(args.Repo), args.DeploymentID)
+	return postAPI(endpoint, payload, "{id, state, environment, environment_url, log_url, created_at}")
+}
+
+type deploymentListArgs struct {
+	Environment string `json:"environment"`
+	Ref         string `json:"ref"`
+	Limit       int    `json:"limit"`
+	Repo        string `json:"repo"`
+}
+
+func deploymentList(args deploymentListArgs) ToolResult {
+	ghArgs := []string{"api", "--method", "GET", apiRepo(args.Repo) + "/deployments"}
+	if args.Environment != "" {
+		ghArgs = append(ghArgs, "-f", "environment="+args.Environment)
+	}
+	if args.Ref != "" {
+		ghArgs = append(ghArgs, "-f", "ref="+args.Ref)
+	}
+	limit := 10
+	if args.Limit > 0 {
+		limit = args.Limit
+	}
+	ghArgs = append(ghArgs, "-f", "per_page="+strconv.Itoa(limit), "--jq", "[.[] | "+deploymentFields+"]")
+	return textResult(execGH(ghArgs...))
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRepoDispatch(t *testing.T) {
+	useFakeGH(t)
+
+	text, isError := callGitHubTool(t, "gh-repo-dispatch", `{"event_type":"deploy","client_payload":{"env":"staging"},"repo":"octo/app"}`)
+	if isError || text != `Dispatched repository_dispatch event "deploy"` {
+		t.Errorf("gh-repo-dispatch = %q (error %v)", text, isError)
+	}
+	calls := ghCalls(t)
+	if got := strings.Join(calls[0].Args, " "); got != "api --include --method POST repos/octo/app/dispatches --input -" {
+		t.Errorf("call = %s", got)
+	}
+	want := map[string]interface{}{"event_type": "deploy", "client_payload": map[string]interface{}{"env": "staging"}}
+	if payload := decodeInput(t, calls[0]); !reflect.DeepEqual(payload, want) {
+		t.Errorf("payload = %v, want %v", payload, want)
+	}
+
+	var properties []string
+	for i := 0; i < 11; i++ {
+		properties = append(properties, fmt.Sprintf(`"p%d":%d`, i, i))
+	}
+	for arguments, want := range map[string]string{
+		`{"event_type":" "}`: "event_type must not be empty",
+		`{"event_type":"deploy","client_payload":{` + strings.Join(properties, ",") + `}}`: "at most 10 top-level properties",
+	} {
+		if text, isError := callGitHubTool(t, "gh-repo-dispatch", arguments); !isError || !strings.Contains(text, want) {
+			t.Errorf("gh-repo-dispatch %s = %q, want %q", arguments, text, want)
+		}
+	}
+	if len(ghCalls(t)) != 1 {
+		t.Error("a rejected dispatch called gh")
+	}
+}
+
+func TestDeploymentCreate(t *testing.T) {
+	useFakeGH(t)
+
+	tests := []struct {
+		name      string
+		arguments string
+		want      map[string]interface{}
+	}{
+		{
+			name:      "defaults",
+			arguments: `{"ref":"v1.2.0","environment":"production"}`,
+			// auto_merge is off and required_contexts left to GitHub
+			want: map[string]interface{}{"ref": "v1.2.0", "environment": "production", "auto_merge": false},
+		},
+		{
+			name:      "all fields",
+			arguments: `{"ref":"main","environment":"preview","description":"PR 7","task":"deploy:migrations","payload":{"pr":7},"required_contexts":[],"auto_merge":true,"transient_environment":true,"production_environment":false}`,
+			// An empty required_contexts skips the status checks
+			want: map[string]interface{}{
+				"ref": "main", "environment": "preview", "description": "PR 7", "task": "deploy:migrations",
+				"payload": map[string]interface{}{"pr": 7.0}, "required_contexts": []interface{}{},
+				"auto_merge": true, "transient_environment": true, "production_environment": false,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text, isError := callGitHubTool(t, "gh-deployment-create", tt.arguments)
+			if isError || text != "https://github.com/repos/{owner}/{repo}/deployments" {
+				t.Errorf("gh-deployment-create = %q (error %v)", text, isError)
+			}
+			calls := ghCalls(t)
+			call := calls[len(calls)-1]
+			if got := call.Args[len(call.Args)-1]; got != deploymentFields {
+				t.Errorf("jq = %s, want the deployment fields", got)
+			}
+			if payload := decodeInput(t, call); !reflect.DeepEqual(payload, tt.want) {
+				t.Errorf("payload = %v, want %v", payload, tt.want)
+			}
+		})
+	}
+
+	if text, isError := callGitHubTool(t, "gh-deployment-create", `{"ref":"","environment":"production"}`); !isError || text != "ref and environment must not be empty" {
+		t.Errorf("gh-deployment-create without ref = %q", text)
+	}
+}
+
+func TestDeploymentStatus(t *testing.T) {
+	useFakeGH(t)
+
+	text, isError := callGitHubTool(t, "gh-deployment-status", `{"deployment_id":2961823771,"state":"success","environment_url":"https://app.example.com","auto_inactive":false,"repo":"octo/app"}`)
+	if isError || text != "https://github.com/repos/octo/app/deployments/2961823771/statuses" {
+		t.Errorf("gh-deployment-status = %q (error %v)", text, isError)
+	}
+	want := map[string]interface{}{"state": "success", "environment_url": "https://app.example.com", "auto_inactive": false}
+	if payload := decodeInput(t, ghCalls(t)[0]); !reflect.DeepEqual(payload, want) {
+		t.Errorf("payload = %v, want %v", payload, want)
+	}
+}
+
+func TestDeploymentList(t *testing.T) {
+	useFakeGH(t)
+
+	callGitHubTool(t, "gh-deployment-list", `{}`)
+	callGitHubTool(t, "gh-deployment-list", `{"environment":"production","ref":"main","limit":3,"repo":"octo/app"}`)
+
+	calls := ghCalls(t)
+	want := []string{
+		"api --include --method GET repos/{owner}/{repo}/deployments -f per_page=10 --jq [.[] | " + deploymentFields + "]",
+		"api --include --method GET repos/octo/app/deployments -f environment=production -f ref=main -f per_page=3 --jq [.[] | " + deploymentFields + "]",
+	}
+	if len(calls) != len(want) {
+		t.Fatalf("calls = %+v", calls)
+	}
+	for i := range want {
+		if got := strings.Join(calls[i].Args, " "); got != want[i] {
+			t.Errorf("call %d = %s, want %s", i, got, want[i])
+		}
+	}
+}
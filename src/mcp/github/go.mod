@@ -1,3 +1,13 @@
 module github.com/ready-to-release/eac/mcp-server-github
 
 go 1.25.3
+
+require github.com/ready-to-release/eac/src/core v0.0.0
+
+require (
+	github.com/gobwas/glob v0.2.3 // indirect
+	golang.org/x/sys v0.36.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+replace github.com/ready-to-release/eac/src/core => ../../core
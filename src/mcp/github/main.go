@@ -1,14 +1,18 @@
 package main
 
 import (
-	"bufio"
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"runtime"
-	"strings"
+	"sync"
+
+	"github.com/ready-to-release/eac/src/core/audit"
+	"github.com/ready-to-release/eac/src/core/mcpserver"
 )
 
 // MCP Server for GitHub CLI integration
@@ -32,22 +36,13 @@ type MCPError struct {
 	Message string `json:"message"`
 }
 
-type Tool struct {
-	Name        string      `json:"name"`
-	Description string      `json:"description"`
-	InputSchema InputSchema `json:"inputSchema"`
-}
-
-type InputSchema struct {
-	Type       string              `json:"type"`
-	Properties map[string]Property `json:"properties"`
-	Required   []string            `json:"required,omitempty"`
-}
-
-type Property struct {
-	Type        string `json:"type"`
-	Description string `json:"description"`
-}
+// Tool, InputSchema and Property are shared with the other servers, which
+// validate arguments against them (src/core/mcpserver)
+type (
+	Tool        = mcpserver.Tool
+	InputSchema = mcpserver.InputSchema
+	Property    = mcpserver.Property
+)
 
 type CallToolParams struct {
 	Name      string                 `json:"name"`
@@ -56,6 +51,7 @@ type CallToolParams struct {
 
 type ToolResult struct {
 	Content []Content `json:"content"`
+	IsError bool      `json:"isError,omitempty"`
 }
 
 type Content struct {
@@ -63,24 +59,65 @@ type Content struct {
 	Text string `json:"text"`
 }
 
+// lifecycle tracks the child processes of the server and kills them on
+// shutdown
+var lifecycle = mcpserver.NewLifecycle()
+
+// stderr is the diagnostics stream of the server, with credentials masked
+var stderr = mcpserver.Stderr
+
 func main() {
-	scanner := bufio.NewScanner(os.Stdin)
-	encoder := json.NewEncoder(os.Stdout)
+	writer := mcpserver.NewMessageWriter(os.Stdout)
+	reader := mcpserver.NewMessageReader(os.Stdin, writer)
+	encoder := json.NewEncoder(writer)
 
-	for scanner.Scan() {
-		line := scanner.Text()
-		if line == "" {
-			continue
+	lifecycle.HandleSignals()
+
+	wait := serve(reader, encoder)
+
+	// stdin closed: stop children so in-flight handlers return
+	lifecycle.Shutdown()
+	wait()
+}
+
+// serve answers the messages of reader until the input ends. Tool calls run
+// concurrently; the returned function waits for the ones still running.
+func serve(reader *mcpserver.MessageReader, encoder *json.Encoder) func() {
+	var inflight sync.WaitGroup
+	for {
+		line, err := reader.ReadMessage()
+		if err != nil {
+			if errors.Is(err, mcpserver.ErrMessageTooLarge) {
+				sendError(encoder, nil, -32600, err.Error())
+				continue
+			}
+			if errors.Is(err, mcpserver.ErrInvalidHeader) {
+				sendError(encoder, nil, -32700, err.Error())
+				continue
+			}
+			break
 		}
 
 		var req MCPRequest
-		if err := json.Unmarshal([]byte(line), &req); err != nil {
+		if err := json.Unmarshal(line, &req); err != nil {
 			sendError(encoder, nil, -32700, "Parse error")
 			continue
 		}
 
+		// Tool calls run concurrently; ghGuard bounds the gh processes and
+		// the message writer keeps their responses whole
+		if req.Method == "tools/call" {
+			inflight.Add(1)
+			go func() {
+				defer inflight.Done()
+				handleRequest(encoder, &req)
+			}()
+			continue
+		}
+
 		handleRequest(encoder, &req)
 	}
+	return inflight.Wait
 }
 
 func handleRequest(encoder *json.Encoder, req *MCPRequest) {
@@ -98,61 +135,7 @@ func handleRequest(encoder *json.Encoder, req *MCPRequest) {
 		})
 
 	case "tools/list":
-		tools := []Tool{
-			{
-				Name:        "gh-repo-view",
-				Description: "View repository details",
-				InputSchema: InputSchema{
-					Type: "object",
-					Properties: map[string]Property{
-						"repo": {
-							Type:        "string",
-							Description: "Repository in format owner/repo",
-						},
-					},
-					Required: []string{"repo"},
-				},
-			},
-			{
-				Name:        "gh-issue-create",
-				Description: "Create a new issue",
-				InputSchema: InputSchema{
-					Type: "object",
-					Properties: map[string]Property{
-						"title": {
-							Type:        "string",
-							Description: "Issue title",
-						},
-						"body": {
-							Type:        "string",
-							Description: "Issue body",
-						},
-					},
-					Required: []string{"title"},
-				},
-			},
-			{
-				Name:        "gh-pr-list",
-				Description: "List pull requests",
-				InputSchema: InputSchema{
-					Type: "object",
-					Properties: map[string]Property{
-						"state": {
-							Type:        "string",
-							Description: "PR state: open, closed, merged, all",
-						},
-					},
-				},
-			},
-			{
-				Name:        "gh-run-list",
-				Description: "List workflow runs",
-				InputSchema: InputSchema{
-					Type:       "object",
-					Properties: map[string]Property{},
-				},
-			},
-		}
+		tools := namespace.Advertise(getTools())
 		sendResponse(encoder, req.ID, map[string]interface{}{
 			"tools": tools,
 		})
@@ -163,72 +146,462 @@ func handleRequest(encoder *json.Encoder, req *MCPRequest) {
 			sendError(encoder, req.ID, -32602, "Invalid params")
 			return
 		}
+		params.Name = namespace.Resolve(params.Name, toolNames)
+		record := audit.StartTool(auditSource, params.Name, params.Arguments)
+
+		if tool, ok := findTool(params.Name); ok {
+			if errs := mcpserver.ValidateArguments(tool.InputSchema, params.Arguments); len(errs) > 0 {
+				message := mcpserver.FormatArgumentErrors(errs...)
+				mcpserver.FinishAudit(record, true, errors.New(message))
+				sendError(encoder, req.ID, -32602, message)
+				return
+			}
+		}
 
-		result := callTool(&params)
+		result, err := callTool(&params)
+		var argErr *mcpserver.ArgumentError
+		if errors.As(err, &argErr) {
+			message := mcpserver.FormatArgumentErrors(argErr)
+			mcpserver.FinishAudit(record, true, errors.New(message))
+			sendError(encoder, req.ID, -32602, message)
+			return
+		}
+		mcpserver.FinishAudit(record, result.IsError, nil)
 		sendResponse(encoder, req.ID, result)
 
+	case "shutdown":
+		lifecycle.Shutdown()
+		sendResponse(encoder, req.ID, map[string]interface{}{})
+
+	case "exit":
+		lifecycle.Shutdown()
+		os.Exit(0)
+
 	default:
 		sendError(encoder, req.ID, -32601, "Method not found")
 	}
 }
 
-func callTool(params *CallToolParams) ToolResult {
+// getTools returns the tools exposed by this server
+func getTools() []Tool {
+	return []Tool{
+		{
+			Name:        "gh-repo-view",
+			Description: "View repository details",
+			InputSchema: InputSchema{
+				Type: "object",
+				Properties: map[string]Property{
+					"repo": {
+						Type:        "string",
+						Description: "Repository in format owner/repo",
+					},
+				},
+				Required: []string{"repo"},
+			},
+		},
+		{
+			Name:        "gh-issue-create",
+			Description: "Create a new issue",
+			InputSchema: InputSchema{
+				Type: "object",
+				Properties: map[string]Property{
+					"title": {
+						Type:        "string",
+						Description: "Issue title",
+					},
+					"body": {
+						Type:        "string",
+						Description: "Issue body",
+					},
+				},
+				Required: []string{"title"},
+			},
+		},
+		{
+			Name:        "gh-pr-list",
+			Description: "List pull requests",
+			InputSchema: InputSchema{
+				Type: "object",
+				Properties: map[string]Property{
+					"state": {
+						Type:        "string",
+						Description: "PR state: open, closed, merged, all",
+						Enum:        []string{"open", "closed", "merged", "all"},
+					},
+				},
+			},
+		},
+		{
+			Name:        "gh-pr-diff",
+			Description: "Show the diff of a pull request",
+			InputSchema: InputSchema{
+				Type: "object",
+				Properties: map[string]Property{
+					"number": {
+						Type:        "integer",
+						Description: "Pull request number",
+					},
+					"repo": {
+						Type:        "string",
+						Description: "Repository in format owner/repo (default: current repository)",
+					},
+					"name_only": {
+						Type:        "boolean",
+						Description: "List the changed file names only",
+					},
+				},
+				Required: []string{"number"},
+			},
+		},
+		{
+			Name:        "gh-pr-comment",
+			Description: "Comment on a pull request, or on a line of its diff when path and line are given",
+			InputSchema: InputSchema{
+				Type: "object",
+				Properties: map[string]Property{
+					"number": {
+						Type:        "integer",
+						Description: "Pull request number",
+					},
+					"body": {
+						Type:        "string",
+						Description: "Comment text (markdown)",
+					},
+					"repo": {
+						Type:        "string",
+						Description: "Repository in format owner/repo (default: current repository)",
+					},
+					"path": {
+						Type:        "string",
+						Description: "File to anchor the comment to, relative to the repository root",
+					},
+					"line": {
+						Type:        "integer",
+						Description: "Line of the file in the diff to anchor the comment to (last line of a range)",
+					},
+					"start_line": {
+						Type:        "integer",
+						Description: "First line of a multi-line range ending at line",
+					},
+					"side": {
+						Type:        "string",
+						Description: "Side of the diff: RIGHT for added or unchanged lines (default), LEFT for removed lines",
+						Enum:        []string{"LEFT", "RIGHT"},
+					},
+				},
+				Required: []string{"number", "body"},
+			},
+		},
+		{
+			Name:        "gh-pr-review",
+			Description: "Submit a pull request review that approves, requests changes or comments, optionally with line comments",
+			InputSchema: InputSchema{
+				Type: "object",
+				Properties: map[string]Property{
+					"number": {
+						Type:        "integer",
+						Description: "Pull request number",
+					},
+					"event": {
+						Type:        "string",
+						Description: "Review outcome; request-changes and comment require a body or comments",
+						Enum:        []string{"approve", "request-changes", "comment"},
+					},
+					"body": {
+						Type:        "string",
+						Description: "Review summary (markdown)",
+					},
+					"repo": {
+						Type:        "string",
+						Description: "Repository in format owner/repo (default: current repository)",
+					},
+					"comments": {
+						Type:        "array",
+						Description: "Line comments submitted with the review",
+						Items: &Property{
+							Type:        "object",
+							Description: "Comment anchored to a line of the diff",
+							Properties: map[string]Property{
+								"path":       {Type: "string", Description: "File relative to the repository root"},
+								"line":       {Type: "integer", Description: "Line of the file in the diff (last line of a range)"},
+								"start_line": {Type: "integer", Description: "First line of a multi-line range"},
+								"side":       {Type: "string", Description: "LEFT or RIGHT (default)", Enum: []string{"LEFT", "RIGHT"}},
+								"body":       {Type: "string", Description: "Comment text (markdown)"},
+							},
+							Required: []string{"path", "line", "body"},
+						},
+					},
+				},
+				Required: []string{"number", "event"},
+			},
+		},
+		{
+			Name:        "gh-repo-dispatch",
+			Description: "Fire a repository_dispatch event, which starts the workflows listening for its event type",
+			InputSchema: InputSchema{
+				Type: "object",
+				Properties: map[string]Property{
+					"event_type": {
+						Type:        "string",
+						Description: "Event type matched by \"on: repository_dispatch: types\" in workflows",
+					},
+					"client_payload": {
+						Type:        "object",
+						Description: "JSON payload passed to workflows as github.event.client_payload (at most 10 top-level properties)",
+					},
+					"repo": {
+						Type:        "string",
+						Description: "Repository in format owner/repo (default: current repository)",
+					},
+				},
+				Required: []string{"event_type"},
+			},
+		},
+		{
+			Name:        "gh-deployment-create",
+			Description: "Create a deployment of a ref to an environment",
+			InputSchema: InputSchema{
+				Type: "object",
+				Properties: map[string]Property{
+					"ref": {
+						Type:        "string",
+						Description: "Branch, tag or commit SHA to deploy",
+					},
+					"environment": {
+						Type:        "string",
+						Description: "Target environment, e.g. staging or production",
+					},
+					"description": {
+						Type:        "string",
+						Description: "Short description of the deployment",
+					},
+					"task": {
+						Type:        "string",
+						Description: "Task to execute (default: deploy)",
+					},
+					"payload": {
+						Type:        "object",
+						Description: "JSON payload with extra information for the deployment",
+					},
+					"required_contexts": {
+						Type:        "array",
+						Description: "Status checks that must pass on the ref; an empty array skips the checks (default: all checks)",
+						Items:       &Property{Type: "string", Description: "Status check context"},
+					},
+					"auto_merge": {
+						Type:        "boolean",
+						Description: "Merge the default branch into the ref before deploying (default: false)",
+					},
+					"transient_environment": {
+						Type:        "boolean",
+						Description: "The environment is removed when no longer needed, e.g. a review app",
+					},
+					"production_environment": {
+						Type:        "boolean",
+						Description: "The environment is one end users interact with",
+					},
+					"repo": {
+						Type:        "string",
+						Description: "Repository in format owner/repo (default: current repository)",
+					},
+				},
+				Required: []string{"ref", "environment"},
+			},
+		},
+		{
+			Name:        "gh-deployment-status",
+			Description: "Update a deployment by creating a deployment status",
+			InputSchema: InputSchema{
+				Type: "object",
+				Properties: map[string]Property{
+					"deployment_id": {
+						Type:        "integer",
+						Description: "Deployment id returned by gh-deployment-create or gh-deployment-list",
+					},
+					"state": {
+						Type:        "string",
+						Description: "Deployment state",
+						Enum:        deploymentStates,
+					},
+					"description": {
+						Type:        "string",
+						Description: "Short description of the status",
+					},
+					"environment_url": {
+						Type:        "string",
+						Description: "URL of the deployed environment",
+					},
+					"log_url": {
+						Type:        "string",
+						Description: "URL of the deployment output, e.g. the workflow run",
+					},
+					"environment": {
+						Type:        "string",
+						Description: "Change the environment of the deployment",
+					},
+					"auto_inactive": {
+						Type:        "boolean",
+						Description: "Mark earlier successful deployments to the environment inactive (default: true)",
+					},
+					"repo": {
+						Type:        "string",
+						Description: "Repository in format owner/repo (default: current repository)",
+					},
+				},
+				Required: []string{"deployment_id", "state"},
+			},
+		},
+		{
+			Name:        "gh-deployment-list",
+			Description: "List recent deployments, newest first",
+			InputSchema: InputSchema{
+				Type: "object",
+				Properties: map[string]Property{
+					"environment": {
+						Type:        "string",
+						Description: "Only deployments to this environment",
+					},
+					"ref": {
+						Type:        "string",
+						Description: "Only deployments of this ref",
+					},
+					"limit": {
+						Type:        "integer",
+						Description: "Maximum number of deployments (default: 10)",
+					},
+					"repo": {
+						Type:        "string",
+						Description: "Repository in format owner/repo (default: current repository)",
+					},
+				},
+			},
+		},
+		{
+			Name:        "gh-run-list",
+			Description: "List workflow runs",
+			InputSchema: InputSchema{
+				Type:       "object",
+				Properties: map[string]Property{},
+			},
+		},
+	}
+}
+
+// findTool looks up a tool definition by name
+func findTool(name string) (Tool, bool) {
+	for _, tool := range getTools() {
+		if tool.Name == name {
+			return tool, true
+		}
+	}
+	return Tool{}, false
+}
+
+// callTool runs a tool, decoding its arguments into the input of the tool's
+// handler. It returns an *mcpserver.ArgumentError when they do not decode.
+func callTool(params *CallToolParams) (ToolResult, error) {
+	args := params.Arguments
 	switch params.Name {
 	case "gh-repo-view":
-		repo, ok := params.Arguments["repo"].(string)
-		if !ok {
-			return errorResult("repo must be a string")
-		}
-		output := execGH("repo", "view", repo)
-		return textResult(output)
+		return mcpserver.Handle(args, repoView)
 
 	case "gh-issue-create":
-		title, _ := params.Arguments["title"].(string)
-		body, _ := params.Arguments["body"].(string)
-
-		args := []string{"issue", "create", "--title", title}
-		if body != "" {
-			args = append(args, "--body", body)
-		}
-		output := execGH(args...)
-		return textResult(output)
+		return mcpserver.Handle(args, issueCreate)
 
 	case "gh-pr-list":
-		state, ok := params.Arguments["state"].(string)
-		if !ok {
-			state = "open"
-		}
-		output := execGH("pr", "list", "--state", state, "--json", "number,title,author,createdAt")
-		return textResult(output)
+		return mcpserver.Handle(args, prList)
+
+	case "gh-pr-diff":
+		return mcpserver.Handle(args, prDiff)
+
+	case "gh-pr-comment":
+		return mcpserver.Handle(args, prComment)
+
+	case "gh-pr-review":
+		return mcpserver.Handle(args, prReview)
+
+	case "gh-repo-dispatch":
+		return mcpserver.Handle(args, repoDispatch)
+
+	case "gh-deployment-create":
+		return mcpserver.Handle(args, deploymentCreate)
+
+	case "gh-deployment-status":
+		return mcpserver.Handle(args, deploymentStatus)
+
+	case "gh-deployment-list":
+		return mcpserver.Handle(args, deploymentList)
 
 	case "gh-run-list":
 		output := execGH("run", "list", "--json", "databaseId,name,status,conclusion,createdAt")
-		return textResult(output)
+		return textResult(output), nil
 
 	default:
-		return errorResult(fmt.Sprintf("Unknown tool: %s", params.Name))
+		return errorResult(fmt.Sprintf("Unknown tool: %s", params.Name)), nil
 	}
 }
 
+type repoViewArgs struct {
+	Repo string `json:"repo"`
+}
+
+func repoView(args repoViewArgs) ToolResult {
+	if args.Repo == "" {
+		return errorResult("repo must not be empty")
+	}
+	return textResult(execGH("repo", "view", args.Repo))
+}
+
+type issueCreateArgs struct {
+	Title string `json:"title"`
+	Body  string `json:"body"`
+}
+
+func issueCreate(args issueCreateArgs) ToolResult {
+	ghArgs := []string{"issue", "create", "--title", args.Title}
+	if args.Body != "" {
+		ghArgs = append(ghArgs, "--body", args.Body)
+	}
+	return textResult(execGH(ghArgs...))
+}
+
+type prListArgs struct {
+	State string `json:"state"`
+}
+
+func prList(args prListArgs) ToolResult {
+	state := args.State
+	if state == "" {
+		state = "open"
+	}
+	return textResult(execGH("pr", "list", "--state", state, "--json", "number,title,author,createdAt"))
+}
+
 func execGH(args ...string) string {
+	return execGHInput(nil, args...)
+}
+
+// execGHInput runs gh through the guard, with input on stdin when given (as
+// used by "gh api --input -")
+func execGHInput(input []byte, args ...string) string {
 	ghPath := findGH()
 	if ghPath == "" {
 		return "Error: GitHub CLI (gh) not found. Please install it from https://cli.github.com/"
 	}
 
-	cmd := exec.Command(ghPath, args...)
-
-	// Pass through GITHUB_TOKEN if set
-	if token := os.Getenv("GITHUB_TOKEN"); token != "" {
-		cmd.Env = append(os.Environ(), "GH_TOKEN="+token)
-	}
+	return ghGuard.Run(args, input, func(args []string) ([]byte, error) {
+		cmd := exec.Command(ghPath, args...)
+		if input != nil {
+			cmd.Stdin = bytes.NewReader(input)
+		}
 
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		return fmt.Sprintf("Error: %v\nOutput: %s", err, string(output))
-	}
+		// Pass through GITHUB_TOKEN if set
+		if token := os.Getenv("GITHUB_TOKEN"); token != "" {
+			cmd.Env = append(os.Environ(), "GH_TOKEN="+token)
+		}
 
-	return strings.TrimSpace(string(output))
+		return lifecycle.CombinedOutput(cmd)
+	})
 }
 
 // findGH locates the gh CLI executable
@@ -271,6 +644,7 @@ func errorResult(message string) ToolResult {
 			Type: "text",
 			Text: message,
 		}},
+		IsError: true,
 	}
 }
 
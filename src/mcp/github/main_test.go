@@ -0,0 +1,275 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ready-to-release/eac/src/core/audit"
+	"github.com/ready-to-release/eac/src/core/mcpserver"
+)
+
+// The tools run a copy of the test binary as gh: with FAKE_GH_LOG set,
+// TestMain logs the call in that directory and answers like GitHub.
+
+// fakeGHDir holds the copy of the test binary named gh
+var fakeGHDir string
+
+func TestMain(m *testing.M) {
+	if os.Getenv("FAKE_GH_LOG") != "" && strings.HasPrefix(filepath.Base(os.Args[0]), "gh") {
+		os.Exit(fakeGH(os.Args[1:]))
+	}
+
+	dir, err := os.MkdirTemp("", "fake-gh")
+	if err == nil {
+		err = copyExecutable(filepath.Join(dir, "gh"+exeSuffix()))
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to create the fake gh: %v\n", err)
+		os.Exit(1)
+	}
+	fakeGHDir = dir
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+func exeSuffix() string {
+	if runtime.GOOS == "windows" {
+		return ".exe"
+	}
+	return ""
+}
+
+func copyExecutable(target string) error {
+	executable, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(executable)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(target, data, 0755)
+}
+
+// ghInvocation is a call of the fake gh
+type ghInvocation struct {
+	Args  []string `json:"args"`
+	Input string   `json:"input,omitempty"`
+	Token string   `json:"token,omitempty"`
+}
+
+// fakeGH answers gh calls. The first FAKE_GH_RATE_LIMITED api calls are
+// rejected by an exhausted rate limit that resets FAKE_GH_RESET_IN seconds
+// later, or without a reset time when that is not set.
+func fakeGH(args []string) int {
+	logPath := filepath.Join(os.Getenv("FAKE_GH_LOG"), "calls.jsonl")
+	previous, _ := os.ReadFile(logPath)
+	calls := bytes.Count(previous, []byte("\n"))
+
+	call := ghInvocation{Args: args, Token: os.Getenv("GH_TOKEN")}
+	if slices.Contains(args, "--input") {
+		input, _ := io.ReadAll(os.Stdin)
+		call.Input = string(input)
+	}
+	line, _ := json.Marshal(call)
+	file, err := os.OpenFile(logPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return 2
+	}
+	file.Write(append(line, '\n'))
+	file.Close()
+
+	switch {
+	case args[0] == "api":
+		endpoint := ""
+		for _, arg := range args {
+			if strings.HasPrefix(arg, "repos/") {
+				endpoint = arg
+			}
+		}
+		if limited, _ := strconv.Atoi(os.Getenv("FAKE_GH_RATE_LIMITED")); calls < limited {
+			fmt.Print("HTTP/2.0 403 Forbidden\r\nX-Ratelimit-Remaining: 0\r\n")
+			if resetIn, err := strconv.Atoi(os.Getenv("FAKE_GH_RESET_IN")); err == nil {
+				fmt.Printf("X-Ratelimit-Reset: %d\r\n", time.Now().Unix()+int64(resetIn))
+			}
+			fmt.Print("\r\n{\"message\":\"API rate limit exceeded for user ID 1.\"}\n")
+			fmt.Fprintln(os.Stderr, "gh: API rate limit exceeded for user ID 1. (HTTP 403)")
+			return 1
+		}
+		if strings.HasSuffix(endpoint, "/dispatches") {
+			fmt.Print("HTTP/2.0 204 No Content\r\nX-Ratelimit-Remaining: 4999\r\n\r\n")
+			return 0
+		}
+		fmt.Print("HTTP/2.0 200 OK\r\nX-Ratelimit-Remaining: 4999\r\n\r\n")
+		fmt.Println("https://github.com/" + endpoint)
+	case len(args) > 2 && args[0] == "pr" && args[1] == "view":
+		if args[2] == "404" {
+			fmt.Fprintln(os.Stderr, "GraphQL: Could not resolve to a PullRequest with the number of 404.")
+			return 1
+		}
+		fmt.Println("0123abc")
+	default:
+		fmt.Println("gh " + strings.Join(args, " "))
+	}
+	return 0
+}
+
+// useFakeGH puts the fake gh on PATH, with a guard that neither coalesces
+// nor waits long for rate limits
+func useFakeGH(t *testing.T) {
+	t.Helper()
+	t.Setenv("PATH", fakeGHDir)
+	t.Setenv("FAKE_GH_LOG", t.TempDir())
+	t.Setenv("FAKE_GH_RATE_LIMITED", "")
+	t.Setenv("FAKE_GH_RESET_IN", "")
+	t.Setenv("GITHUB_TOKEN", "")
+
+	guard := ghGuard
+	ghGuard = newGHGuard(defaultGHConcurrency, 0, 10*time.Second)
+	t.Cleanup(func() { ghGuard = guard })
+}
+
+// ghCalls returns the calls of the fake gh so far
+func ghCalls(t *testing.T) []ghInvocation {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(os.Getenv("FAKE_GH_LOG"), "calls.jsonl"))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	var calls []ghInvocation
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		var call ghInvocation
+		if err := json.Unmarshal([]byte(line), &call); err != nil {
+			t.Fatalf("invalid call %q: %v", line, err)
+		}
+		calls = append(calls, call)
+	}
+	return calls
+}
+
+// callGitHubTool sends a tools/call request through handleRequest and
+// returns the text of the result
+func callGitHubTool(t *testing.T, name string, arguments string) (string, bool) {
+	t.Helper()
+	t.Setenv(audit.PathEnv, filepath.Join(t.TempDir(), "audit.jsonl"))
+
+	params := `{"name":"` + name + `","arguments":` + arguments + `}`
+	var out bytes.Buffer
+	handleRequest(json.NewEncoder(&out), &MCPRequest{JSONRPC: "2.0", ID: 1, Method: "tools/call", Params: json.RawMessage(params)})
+
+	var resp struct {
+		Result *ToolResult `json:"result"`
+		Error  *MCPError   `json:"error"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &resp); err != nil {
+		t.Fatalf("invalid response %q: %v", out.String(), err)
+	}
+	if resp.Error != nil {
+		t.Fatalf("%s returned error %d: %s", name, resp.Error.Code, resp.Error.Message)
+	}
+	if resp.Result == nil || len(resp.Result.Content) != 1 {
+		t.Fatalf("%s returned %s", name, out.String())
+	}
+	return resp.Result.Content[0].Text, resp.Result.IsError
+}
+
+// decodeInput returns the JSON posted by a gh api call
+func decodeInput(t *testing.T, call ghInvocation) map[string]interface{} {
+	t.Helper()
+	var payload map[string]interface{}
+	if err := json.Unmarshal([]byte(call.Input), &payload); err != nil {
+		t.Fatalf("gh %v posted %q: %v", call.Args, call.Input, err)
+	}
+	return payload
+}
+
+func TestExecGH(t *testing.T) {
+	useFakeGH(t)
+	t.Setenv("GITHUB_TOKEN", "ghp_test")
+
+	text, isError := callGitHubTool(t, "gh-pr-list", `{"state":"merged"}`)
+	if isError || text != "gh pr list --state merged --json number,title,author,createdAt" {
+		t.Errorf("gh-pr-list = %q", text)
+	}
+	// GITHUB_TOKEN is passed to gh as GH_TOKEN
+	if calls := ghCalls(t); len(calls) != 1 || calls[0].Token != "ghp_test" {
+		t.Errorf("calls = %+v, want one call with GH_TOKEN", calls)
+	}
+
+	t.Setenv("PATH", t.TempDir())
+	if text, _ := callGitHubTool(t, "gh-repo-view", `{"repo":"octo/app"}`); !strings.Contains(text, "GitHub CLI (gh) not found") {
+		t.Errorf("gh-repo-view without gh = %q", text)
+	}
+}
+
+// slowWriter pauses before each write, giving concurrent writers the chance
+// to interleave
+type slowWriter struct {
+	w io.Writer
+}
+
+func (s slowWriter) Write(p []byte) (int, error) {
+	time.Sleep(time.Millisecond)
+	return s.w.Write(p)
+}
+
+func TestServeConcurrentCalls(t *testing.T) {
+	useFakeGH(t)
+	t.Setenv(audit.PathEnv, filepath.Join(t.TempDir(), "audit.jsonl"))
+
+	// Content-Length framing writes a header and a body per response
+	const calls = 20
+	var input bytes.Buffer
+	for id := 1; id <= calls; id++ {
+		body := fmt.Sprintf(`{"jsonrpc":"2.0","id":%d,"method":"tools/call","params":{"name":"gh-pr-diff","arguments":{"number":%d}}}`, id, id)
+		fmt.Fprintf(&input, "Content-Length: %d\r\n\r\n%s", len(body), body)
+	}
+
+	output, server := io.Pipe()
+	writer := mcpserver.NewMessageWriter(slowWriter{server})
+	go func() {
+		serve(mcpserver.NewMessageReader(&input, writer), json.NewEncoder(writer))()
+		server.Close()
+	}()
+
+	// Every frame holds one whole response
+	reader := mcpserver.NewMessageReader(output, mcpserver.NewMessageWriter(io.Discard))
+	seen := map[int]bool{}
+	for {
+		body, err := reader.ReadMessage()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("ReadMessage: %v", err)
+		}
+		var resp struct {
+			ID     int         `json:"id"`
+			Result *ToolResult `json:"result"`
+		}
+		if err := json.Unmarshal(body, &resp); err != nil {
+			t.Fatalf("invalid frame %q: %v", body, err)
+		}
+		if resp.Result == nil || resp.Result.Content[0].Text != fmt.Sprintf("gh pr diff %d", resp.ID) {
+			t.Errorf("response %d = %s", resp.ID, body)
+		}
+		seen[resp.ID] = true
+	}
+	if len(seen) != calls {
+		t.Errorf("received %d responses, want %d", len(seen), calls)
+	}
+}
@@ -0,0 +1,203 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Pull request review tools. Plain comments and reviews go through "gh pr";
+// line-anchored comments need the REST API, which gh reaches with "gh api".
+
+// reviewEvents maps the review tool events to the REST API events and the
+// "gh pr review" flags
+var reviewEvents = map[string]struct {
+	api  string
+	flag string
+}{
+	"approve":         {"APPROVE", "--approve"},
+	"request-changes": {"REQUEST_CHANGES", "--request-changes"},
+	"comment":         {"COMMENT", "--comment"},
+}
+
+// lineComment is a review comment anchored to a line of the diff
+type lineComment struct {
+	Path      string `json:"path"`
+	Line      int    `json:"line"`
+	Side      string `json:"side"`
+	StartLine int    `json:"start_line,omitempty"`
+	StartSide string `json:"start_side,omitempty"`
+	Body      string `json:"body"`
+}
+
+// prArgs identify the pull request of a tool call
+type prArgs struct {
+	Number int    `json:"number"`
+	Repo   string `json:"repo"`
+}
+
+type prDiffArgs struct {
+	prArgs
+	NameOnly bool `json:"name_only"`
+}
+
+func prDiff(args prDiffArgs) ToolResult {
+	ghArgs := []string{"pr", "diff", args.number()}
+	if args.NameOnly {
+		ghArgs = append(ghArgs, "--name-only")
+	}
+	ghArgs = append(ghArgs, repoFlag(args.Repo)...)
+	return textResult(execGH(ghArgs...))
+}
+
+// prCommentArgs are a pull request with a comment, anchored to a line when
+// path and line are given
+type prCommentArgs struct {
+	prArgs
+	lineComment
+}
+
+func prComment(args prCommentArgs) ToolResult {
+	if strings.TrimSpace(args.Body) == "" {
+		return errorResult("body must not be empty")
+	}
+
+	if args.Path == "" && args.Line == 0 {
+		ghArgs := append([]string{"pr", "comment", args.number(), "--body", args.Body}, repoFlag(args.Repo)...)
+		return textResult(execGH(ghArgs...))
+	}
+
+	comment, err := checkLineComment(args.lineComment)
+	if err != nil {
+		return errorResult(err.Error())
+	}
+
+	commit, err := headCommit(args.prArgs)
+	if err != nil {
+		return errorResult(err.Error())
+	}
+
+	payload := struct {
+		lineComment
+		CommitID string `json:"commit_id"`
+	}{comment, commit}
+	return postAPI(apiRepo(args.Repo)+"/pulls/"+args.number()+"/comments", payload, ".html_url")
+}
+
+type prReviewArgs struct {
+	prArgs
+	Event    string        `json:"event"`
+	Body     string        `json:"body"`
+	Comments []lineComment `json:"comments"`
+}
+
+func prReview(args prReviewArgs) ToolResult {
+	event := reviewEvents[args.Event]
+	if args.Event != "approve" && strings.TrimSpace(args.Body) == "" && len(args.Comments) == 0 {
+		return errorResult(fmt.Sprintf("%s requires a body or comments", args.Event))
+	}
+
+	if len(args.Comments) == 0 {
+		ghArgs := []string{"pr", "review", args.number(), event.flag}
+		if args.Body != "" {
+			ghArgs = append(ghArgs, "--body", args.Body)
+		}
+		ghArgs = append(ghArgs, repoFlag(args.Repo)...)
+		return textResult(execGH(ghArgs...))
+	}
+
+	comments := make([]lineComment, 0, len(args.Comments))
+	for i, item := range args.Comments {
+		comment, err := checkLineComment(item)
+		if err != nil {
+			return errorResult(fmt.Sprintf("comments[%d]: %v", i, err))
+		}
+		comments = append(comments, comment)
+	}
+
+	commit, err := headCommit(args.prArgs)
+	if err != nil {
+		return errorResult(err.Error())
+	}
+
+	payload := struct {
+		CommitID string        `json:"commit_id"`
+		Event    string        `json:"event"`
+		Body     string        `json:"body,omitempty"`
+		Comments []lineComment `json:"comments"`
+	}{commit, event.api, args.Body, comments}
+	return postAPI(apiRepo(args.Repo)+"/pulls/"+args.number()+"/reviews", payload, ".html_url")
+}
+
+// checkLineComment checks path, line, start_line, side and body of a line
+// comment, with side RIGHT unless given
+func checkLineComment(comment lineComment) (lineComment, error) {
+	comment.Side = strings.ToUpper(comment.Side)
+	if comment.Side == "" {
+		comment.Side = "RIGHT"
+	}
+	comment.StartSide = ""
+
+	switch {
+	case comment.Path == "" || comment.Line <= 0:
+		return comment, fmt.Errorf("path and line must be given together")
+	case strings.TrimSpace(comment.Body) == "":
+		return comment, fmt.Errorf("body must not be empty")
+	case comment.Side != "LEFT" && comment.Side != "RIGHT":
+		return comment, fmt.Errorf("side must be LEFT or RIGHT")
+	case comment.StartLine != 0 && comment.StartLine >= comment.Line:
+		return comment, fmt.Errorf("start_line must be before line")
+	}
+	if comment.StartLine != 0 {
+		comment.StartSide = comment.Side
+	}
+	return comment, nil
+}
+
+// headCommit returns the head commit of the pull request, which line
+// comments are anchored to
+func headCommit(pr prArgs) (string, error) {
+	ghArgs := append([]string{"pr", "view", pr.number(), "--json", "headRefOid", "--jq", ".headRefOid"}, repoFlag(pr.Repo)...)
+	output := execGH(ghArgs...)
+	if strings.HasPrefix(output, "Error:") || output == "" {
+		return "", fmt.Errorf("failed to resolve the head commit of pull request %s: %s", pr.number(), output)
+	}
+	return output, nil
+}
+
+// postAPI posts payload as JSON to a REST endpoint and returns the response
+// filtered by the jq expression
+func postAPI(endpoint string, payload interface{}, jq string) ToolResult {
+	input, err := json.Marshal(payload)
+	if err != nil {
+		return errorResult(fmt.Sprintf("Error: %v", err))
+	}
+	args := []string{"api", "--method", "POST", endpoint, "--input", "-"}
+	if jq != "" {
+		args = append(args, "--jq", jq)
+	}
+	return textResult(execGHInput(input, args...))
+}
+
+// number returns the pull request number as gh expects it
+func (pr prArgs) number() string {
+	return strconv.Itoa(pr.Number)
+}
+
+// repoFlag returns --repo for gh pr commands when a repository is given
+func repoFlag(repo string) []string {
+	if repo != "" {
+		return []string{"--repo", repo}
+	}
+	return nil
+}
+
+// apiRepo returns the REST path of the repository; gh api fills in
+// {owner}/{repo} from the current repository
+func apiRepo(repo string) string {
+	if repo != "" {
+		return "repos/" + repo
+	}
+	return "repos/{owner}/{repo}"
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestPRDiff(t *testing.T) {
+	useFakeGH(t)
+
+	text, isError := callGitHubTool(t, "gh-pr-diff", `{"number":7,"name_only":true,"repo":"octo/app"}`)
+	if isError || text != "gh pr diff 7 --name-only --repo octo/app" {
+		t.Errorf("gh-pr-diff = %q", text)
+	}
+}
+
+func TestPRComment(t *testing.T) {
+	useFakeGH(t)
+
+	// A plain comment goes through gh pr comment
+	text, isError := callGitHubTool(t, "gh-pr-comment", `{"number":7,"body":"Looks good"}`)
+	if isError || text != "gh pr comment 7 --body Looks good" {
+		t.Errorf("plain comment = %q", text)
+	}
+
+	// A line comment is posted to the REST API on the head commit
+	text, isError = callGitHubTool(t, "gh-pr-comment", `{"number":7,"repo":"octo/app","path":"main.go","line":12,"side":"LEFT","body":"Why?"}`)
+	if isError || text != "https://github.com/repos/octo/app/pulls/7/comments" {
+		t.Errorf("line comment = %q (error %v)", text, isError)
+	}
+	calls := ghCalls(t)
+	if len(calls) != 3 {
+		t.Fatalf("calls = %+v, want comment, head commit and post", calls)
+	}
+	if got := strings.Join(calls[1].Args, " "); got != "pr view 7 --json headRefOid --jq .headRefOid --repo octo/app" {
+		t.Errorf("head commit call = %s", got)
+	}
+	if got := strings.Join(calls[2].Args, " "); got != "api --include --method POST repos/octo/app/pulls/7/comments --input - --jq .html_url" {
+		t.Errorf("post call = %s", got)
+	}
+	want := map[string]interface{}{"path": "main.go", "line": 12.0, "side": "LEFT", "body": "Why?", "commit_id": "0123abc"}
+	if payload := decodeInput(t, calls[2]); !reflect.DeepEqual(payload, want) {
+		t.Errorf("payload = %v, want %v", payload, want)
+	}
+}
+
+func TestPRCommentErrors(t *testing.T) {
+	useFakeGH(t)
+
+	tests := []struct {
+		name      string
+		arguments string
+		want      string
+		calls     int
+	}{
+		{"empty body", `{"number":7,"body":"  "}`, "body must not be empty", 0},
+		{"path without line", `{"number":7,"path":"main.go","body":"x"}`, "path and line must be given together", 0},
+		{"start after line", `{"number":7,"path":"main.go","line":3,"start_line":5,"body":"x"}`, "start_line must be before line", 0},
+		{"unknown pull request", `{"number":404,"path":"main.go","line":3,"body":"x"}`, "failed to resolve the head commit of pull request 404", 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := len(ghCalls(t))
+			text, isError := callGitHubTool(t, "gh-pr-comment", tt.arguments)
+			if !isError || !strings.Contains(text, tt.want) {
+				t.Errorf("gh-pr-comment = %q (error %v), want %q", text, isError, tt.want)
+			}
+			// Nothing is posted for a rejected comment
+			if calls := len(ghCalls(t)) - before; calls != tt.calls {
+				t.Errorf("gh was called %d times, want %d", calls, tt.calls)
+			}
+		})
+	}
+}
+
+func TestPRReview(t *testing.T) {
+	useFakeGH(t)
+
+	text, isError := callGitHubTool(t, "gh-pr-review", `{"number":7,"event":"approve"}`)
+	if isError || text != "gh pr review 7 --approve" {
+		t.Errorf("approve = %q", text)
+	}
+
+	text, isError = callGitHubTool(t, "gh-pr-review", `{"number":7,"event":"request-changes"}`)
+	if !isError || text != "request-changes requires a body or comments" {
+		t.Errorf("request-changes without a body = %q (error %v)", text, isError)
+	}
+
+	text, isError = callGitHubTool(t, "gh-pr-review", `{"number":7,"event":"comment","body":"Some notes","repo":"octo/app"}`)
+	if isError || text != "gh pr review 7 --comment --body Some notes --repo octo/app" {
+		t.Errorf("comment = %q", text)
+	}
+
+	// Line comments make the review a REST API call
+	text, isError = callGitHubTool(t, "gh-pr-review", `{"number":7,"event":"request-changes","comments":[
+		{"path":"a.go","line":3,"body":"Rename"},
+		{"path":"b.go","line":9,"start_line":4,"side":"LEFT","body":"Drop this block"}
+	]}`)
+	if isError || text != "https://github.com/repos/{owner}/{repo}/pulls/7/reviews" {
+		t.Errorf("review with comments = %q (error %v)", text, isError)
+	}
+	calls := ghCalls(t)
+	payload := decodeInput(t, calls[len(calls)-1])
+	want := map[string]interface{}{
+		"commit_id": "0123abc",
+		"event":     "REQUEST_CHANGES",
+		"comments": []interface{}{
+			map[string]interface{}{"path": "a.go", "line": 3.0, "side": "RIGHT", "body": "Rename"},
+			map[string]interface{}{"path": "b.go", "line": 9.0, "side": "LEFT", "start_line": 4.0, "start_side": "LEFT", "body": "Drop this block"},
+		},
+	}
+	if !reflect.DeepEqual(payload, want) {
+		t.Errorf("payload = %v, want %v", payload, want)
+	}
+
+	before := len(calls)
+	text, isError = callGitHubTool(t, "gh-pr-review", `{"number":7,"event":"comment","comments":[{"path":"a.go","line":3,"body":"ok"},{"path":"a.go","line":3,"start_line":3,"body":"x"}]}`)
+	if !isError || text != "comments[1]: start_line must be before line" {
+		t.Errorf("review with an invalid comment = %q (error %v)", text, isError)
+	}
+	if len(ghCalls(t)) != before {
+		t.Error("a review with an invalid comment called gh")
+	}
+}
@@ -0,0 +1,245 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Guard for gh invocations: agents fan out tool calls, so the number of gh
+// processes is bounded, identical read calls within a short window share one
+// process, and GitHub rate limit responses are retried after the delay the
+// response asks for.
+
+const (
+	defaultGHConcurrency    = 4
+	defaultGHCoalesceWindow = 2 * time.Second
+	defaultGHMaxRetryWait   = 2 * time.Minute
+	ghMaxRetries            = 3
+	// secondaryRateLimitWait is the wait GitHub recommends when a secondary
+	// rate limit response carries no retry-after header
+	secondaryRateLimitWait = time.Minute
+)
+
+var ghGuard = newGHGuard(
+	envInt("MCP_GH_CONCURRENCY", defaultGHConcurrency),
+	envDuration("MCP_GH_COALESCE_WINDOW", defaultGHCoalesceWindow),
+	envDuration("MCP_GH_MAX_RETRY_WAIT", defaultGHMaxRetryWait),
+)
+
+// GHGuard limits, coalesces and retries gh invocations
+type GHGuard struct {
+	slots   chan struct{}
+	window  time.Duration
+	maxWait time.Duration
+
+	mu    sync.Mutex
+	calls map[string]*ghCall
+}
+
+// ghCall is an in-flight or recently finished read call
+type ghCall struct {
+	done     chan struct{}
+	output   string
+	finished time.Time
+}
+
+func newGHGuard(concurrency int, window, maxWait time.Duration) *GHGuard {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	return &GHGuard{
+		slots:   make(chan struct{}, concurrency),
+		window:  window,
+		maxWait: maxWait,
+		calls:   make(map[string]*ghCall),
+	}
+}
+
+// Run runs gh with args through run. Read calls with the same arguments
+// that are in flight or finished within the coalescing window share the
+// output; failed calls are not reused once they finish.
+func (g *GHGuard) Run(args []string, input []byte, run func(args []string) ([]byte, error)) string {
+	if input != nil || !isReadCall(args) || g.window <= 0 {
+		return g.runWithRetry(args, run)
+	}
+
+	key := strings.Join(args, "\x00")
+	g.mu.Lock()
+	now := time.Now()
+	for k, call := range g.calls {
+		if !call.finished.IsZero() && now.Sub(call.finished) >= g.window {
+			delete(g.calls, k)
+		}
+	}
+	if call, ok := g.calls[key]; ok {
+		g.mu.Unlock()
+		<-call.done
+		return call.output
+	}
+	call := &ghCall{done: make(chan struct{})}
+	g.calls[key] = call
+	g.mu.Unlock()
+
+	call.output = g.runWithRetry(args, run)
+
+	g.mu.Lock()
+	call.finished = time.Now()
+	if strings.HasPrefix(call.output, "Error:") {
+		delete(g.calls, key)
+	}
+	g.mu.Unlock()
+	close(call.done)
+
+	return call.output
+}
+
+// runWithRetry runs gh in a concurrency slot and retries rate limited calls.
+// The slot is released while waiting.
+func (g *GHGuard) runWithRetry(args []string, run func(args []string) ([]byte, error)) string {
+	for attempt := 0; ; attempt++ {
+		g.slots <- struct{}{}
+		raw, err := run(withResponseHeaders(args))
+		<-g.slots
+
+		body, headers := splitResponseHeaders(string(raw))
+		output := strings.TrimSpace(body)
+		if err != nil {
+			output = fmt.Sprintf("Error: %v\nOutput: %s", err, body)
+		}
+		if err == nil {
+			return output
+		}
+		wait, limited := rateLimitWait(output, headers, attempt)
+		if !limited || attempt >= ghMaxRetries {
+			return output
+		}
+		if wait > g.maxWait {
+			return fmt.Sprintf("%s\nRate limited by GitHub: retry after %s", output, wait.Round(time.Second))
+		}
+
+		fmt.Fprintf(stderr, "GitHub rate limit hit, retrying gh %s in %s\n", args[0], wait.Round(time.Second))
+		time.Sleep(wait)
+	}
+}
+
+// isReadCall reports whether gh args only read: gh api GET requests and the
+// view, list, diff, status and checks subcommands
+func isReadCall(args []string) bool {
+	if len(args) == 0 {
+		return false
+	}
+	if args[0] == "api" {
+		for i, arg := range args {
+			switch {
+			case (arg == "--method" || arg == "-X") && i+1 < len(args):
+				if !strings.EqualFold(args[i+1], "GET") {
+					return false
+				}
+			case strings.HasPrefix(arg, "--method="):
+				if !strings.EqualFold(strings.TrimPrefix(arg, "--method="), "GET") {
+					return false
+				}
+			case arg == "--input":
+				return false
+			}
+		}
+		return true
+	}
+	if len(args) < 2 {
+		return false
+	}
+	switch args[1] {
+	case "view", "list", "diff", "status", "checks":
+		return true
+	}
+	return false
+}
+
+// withResponseHeaders adds --include to gh api calls so the rate limit
+// headers of the response can be read
+func withResponseHeaders(args []string) []string {
+	if len(args) == 0 || args[0] != "api" {
+		return args
+	}
+	for _, arg := range args {
+		if arg == "--include" || arg == "-i" {
+			return args
+		}
+	}
+	return append([]string{"api", "--include"}, args[1:]...)
+}
+
+// splitResponseHeaders removes HTTP status and header blocks printed by
+// "gh api --include" from output and returns the headers in lower case
+func splitResponseHeaders(output string) (string, map[string]string) {
+	if !strings.HasPrefix(output, "HTTP/") && !strings.Contains(output, "\nHTTP/") {
+		return output, nil
+	}
+
+	headers := make(map[string]string)
+	var kept []string
+	inHeaders := false
+	for _, line := range strings.Split(output, "\n") {
+		trimmed := strings.TrimRight(line, "\r")
+		switch {
+		case strings.HasPrefix(trimmed, "HTTP/"):
+			inHeaders = true
+			if fields := strings.Fields(trimmed); len(fields) > 1 {
+				headers[":status"] = fields[1]
+			}
+		case inHeaders && trimmed == "":
+			inHeaders = false
+		case inHeaders:
+			if name, value, ok := strings.Cut(trimmed, ":"); ok {
+				headers[strings.ToLower(strings.TrimSpace(name))] = strings.TrimSpace(value)
+			}
+		default:
+			kept = append(kept, line)
+		}
+	}
+	return strings.Join(kept, "\n"), headers
+}
+
+// rateLimitWait returns how long to wait before retrying a failed call that
+// was rate limited: the retry-after header, the reset time of an exhausted limit, or an
+// increasing wait for secondary limits without headers
+func rateLimitWait(output string, headers map[string]string, attempt int) (time.Duration, bool) {
+	lower := strings.ToLower(output)
+	secondary := strings.Contains(lower, "secondary rate limit") || strings.Contains(lower, "abuse detection")
+	limited := secondary || strings.Contains(lower, "rate limit exceeded") || headers[":status"] == "429"
+	if !limited {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(headers["retry-after"]); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if headers["x-ratelimit-remaining"] == "0" {
+		if reset, err := strconv.ParseInt(headers["x-ratelimit-reset"], 10, 64); err == nil {
+			return max(time.Until(time.Unix(reset, 0))+time.Second, 0), true
+		}
+	}
+	if secondary || headers[":status"] == "429" {
+		return secondaryRateLimitWait << attempt, true
+	}
+	// An exhausted primary limit without a reset time cannot be waited out
+	return 0, false
+}
+
+func envInt(name string, fallback int) int {
+	if value, err := strconv.Atoi(os.Getenv(name)); err == nil {
+		return value
+	}
+	return fallback
+}
+
+func envDuration(name string, fallback time.Duration) time.Duration {
+	if value, err := time.ParseDuration(os.Getenv(name)); err == nil {
+		return value
+	}
+	return fallback
+}
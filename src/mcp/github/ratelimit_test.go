@@ -0,0 +1,133 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRateLimitWait(t *testing.T) {
+	reset := strconv.FormatInt(time.Now().Add(30*time.Second).Unix(), 10)
+
+	tests := []struct {
+		name        string
+		output      string
+		headers     map[string]string
+		attempt     int
+		wantLimited bool
+		wantMin     time.Duration
+		wantMax     time.Duration
+	}{
+		{
+			name:   "other failure",
+			output: "Error: exit status 1\nOutput: HTTP 404: Not Found",
+		},
+		{
+			name:        "retry-after",
+			output:      "Error: exit status 1\nOutput: secondary rate limit",
+			headers:     map[string]string{":status": "403", "retry-after": "7"},
+			wantLimited: true, wantMin: 7 * time.Second, wantMax: 7 * time.Second,
+		},
+		{
+			name:        "exhausted limit waits for the reset",
+			output:      "Error: exit status 1\nOutput: API rate limit exceeded",
+			headers:     map[string]string{":status": "403", "x-ratelimit-remaining": "0", "x-ratelimit-reset": reset},
+			wantLimited: true, wantMin: 29 * time.Second, wantMax: 32 * time.Second,
+		},
+		{
+			name:        "exhausted limit reset in the past",
+			output:      "API rate limit exceeded",
+			headers:     map[string]string{"x-ratelimit-remaining": "0", "x-ratelimit-reset": "1"},
+			wantLimited: true, wantMin: 0, wantMax: 0,
+		},
+		{
+			// Without a reset time the limit cannot be waited out
+			name:    "exhausted limit without reset",
+			output:  "API rate limit exceeded",
+			headers: map[string]string{"x-ratelimit-remaining": "0"},
+		},
+		{
+			name:        "secondary limit without headers backs off",
+			output:      "You have exceeded a secondary rate limit",
+			attempt:     2,
+			wantLimited: true, wantMin: 4 * time.Minute, wantMax: 4 * time.Minute,
+		},
+		{
+			name:        "429",
+			output:      "Error: exit status 1",
+			headers:     map[string]string{":status": "429"},
+			wantLimited: true, wantMin: time.Minute, wantMax: time.Minute,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wait, limited := rateLimitWait(tt.output, tt.headers, tt.attempt)
+			if limited != tt.wantLimited || wait < tt.wantMin || wait > tt.wantMax {
+				t.Errorf("rateLimitWait() = %s, %v; want %v between %s and %s", wait, limited, tt.wantLimited, tt.wantMin, tt.wantMax)
+			}
+		})
+	}
+}
+
+func TestRateLimitRetry(t *testing.T) {
+	useFakeGH(t)
+
+	// The first call finds the limit exhausted until the next second
+	t.Setenv("FAKE_GH_RATE_LIMITED", "1")
+	t.Setenv("FAKE_GH_RESET_IN", "1")
+
+	start := time.Now()
+	text, isError := callGitHubTool(t, "gh-deployment-list", `{"repo":"octo/app"}`)
+	if isError || text != "https://github.com/repos/octo/app/deployments" {
+		t.Errorf("gh-deployment-list = %q (error %v), want the retried response", text, isError)
+	}
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("retried after %s, want after the reset", elapsed)
+	}
+	if calls := ghCalls(t); len(calls) != 2 {
+		t.Errorf("gh was called %d times, want 2", len(calls))
+	}
+}
+
+func TestRateLimitGivesUp(t *testing.T) {
+	tests := []struct {
+		name      string
+		limited   string
+		resetIn   string
+		wantCalls int
+		want      string
+	}{
+		{
+			// The guard in the tests waits at most 10s
+			name: "reset after the maximum wait", limited: "1", resetIn: "3600",
+			wantCalls: 1, want: "Rate limited by GitHub: retry after",
+		},
+		{
+			name: "no reset time", limited: "1", resetIn: "",
+			wantCalls: 1, want: "API rate limit exceeded",
+		},
+		{
+			name: "retries exhausted", limited: "10", resetIn: "0",
+			wantCalls: ghMaxRetries + 1, want: "API rate limit exceeded",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useFakeGH(t)
+			t.Setenv("FAKE_GH_RATE_LIMITED", tt.limited)
+			t.Setenv("FAKE_GH_RESET_IN", tt.resetIn)
+
+			text, isError := callGitHubTool(t, "gh-deployment-list", `{}`)
+			// Failed gh calls are reported in the text, like every gh error
+			if isError || !strings.HasPrefix(text, "Error: exit status 1") || !strings.Contains(text, tt.want) {
+				t.Errorf("gh-deployment-list = %q, want %q", text, tt.want)
+			}
+			if calls := ghCalls(t); len(calls) != tt.wantCalls {
+				t.Errorf("gh was called %d times, want %d", len(calls), tt.wantCalls)
+			}
+		})
+	}
+}
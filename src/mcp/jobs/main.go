@@ -0,0 +1,382 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/ready-to-release/eac/src/core/audit"
+	"github.com/ready-to-release/eac/src/core/mcpserver"
+	"github.com/ready-to-release/eac/src/core/repository"
+)
+
+// MCP Server for r2r background jobs. Tools call "r2r jobs ... --json", so
+// jobs submitted here can also be followed with the CLI and vice versa.
+
+type MCPRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      interface{}     `json:"id"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type MCPResponse struct {
+	JSONRPC string      `json:"jsonrpc"`
+	ID      interface{} `json:"id"`
+	Result  interface{} `json:"result,omitempty"`
+	Error   *MCPError   `json:"error,omitempty"`
+}
+
+type MCPError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// Tool, InputSchema and Property are shared with the other servers, which
+// validate arguments against them (src/core/mcpserver)
+type (
+	Tool        = mcpserver.Tool
+	InputSchema = mcpserver.InputSchema
+	Property    = mcpserver.Property
+)
+
+type CallToolParams struct {
+	Name      string                 `json:"name"`
+	Arguments map[string]interface{} `json:"arguments"`
+}
+
+type ToolResult struct {
+	Content []Content `json:"content"`
+	IsError bool      `json:"isError,omitempty"`
+}
+
+type Content struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// lifecycle tracks the child processes of the server and kills them on
+// shutdown
+var lifecycle = mcpserver.NewLifecycle()
+
+// stderr is the diagnostics stream of the server, with credentials masked
+var stderr = mcpserver.Stderr
+
+func main() {
+	writer := mcpserver.NewMessageWriter(os.Stdout)
+	reader := mcpserver.NewMessageReader(os.Stdin, writer)
+	encoder := json.NewEncoder(writer)
+
+	lifecycle.HandleSignals()
+
+	for {
+		line, err := reader.ReadMessage()
+		if err != nil {
+			if errors.Is(err, mcpserver.ErrMessageTooLarge) {
+				sendError(encoder, nil, -32600, err.Error())
+				continue
+			}
+			if errors.Is(err, mcpserver.ErrInvalidHeader) {
+				sendError(encoder, nil, -32700, err.Error())
+				continue
+			}
+			break
+		}
+
+		var req MCPRequest
+		if err := json.Unmarshal(line, &req); err != nil {
+			sendError(encoder, nil, -32700, "Parse error")
+			continue
+		}
+
+		handleRequest(encoder, &req)
+	}
+
+	lifecycle.Shutdown()
+}
+
+func handleRequest(encoder *json.Encoder, req *MCPRequest) {
+	switch req.Method {
+	case "initialize":
+		sendResponse(encoder, req.ID, map[string]interface{}{
+			"protocolVersion": "2024-11-05",
+			"serverInfo": map[string]string{
+				"name":    "mcp-server-jobs",
+				"version": "0.1.0",
+			},
+			"capabilities": map[string]interface{}{
+				"tools": map[string]bool{},
+			},
+		})
+
+	case "tools/list":
+		tools := namespace.Advertise(getTools())
+		sendResponse(encoder, req.ID, map[string]interface{}{
+			"tools": tools,
+		})
+
+	case "tools/call":
+		var params CallToolParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			sendError(encoder, req.ID, -32602, "Invalid params")
+			return
+		}
+		params.Name = namespace.Resolve(params.Name, toolNames)
+		record := audit.StartTool(auditSource, params.Name, params.Arguments)
+
+		if tool, ok := findTool(params.Name); ok {
+			if errs := mcpserver.ValidateArguments(tool.InputSchema, params.Arguments); len(errs) > 0 {
+				message := mcpserver.FormatArgumentErrors(errs...)
+				mcpserver.FinishAudit(record, true, errors.New(message))
+				sendError(encoder, req.ID, -32602, message)
+				return
+			}
+		}
+
+		result, err := callTool(&params)
+		var argErr *mcpserver.ArgumentError
+		if errors.As(err, &argErr) {
+			message := mcpserver.FormatArgumentErrors(argErr)
+			mcpserver.FinishAudit(record, true, errors.New(message))
+			sendError(encoder, req.ID, -32602, message)
+			return
+		}
+		mcpserver.FinishAudit(record, result.IsError, nil)
+		sendResponse(encoder, req.ID, result)
+
+	case "shutdown":
+		lifecycle.Shutdown()
+		sendResponse(encoder, req.ID, map[string]interface{}{})
+
+	case "exit":
+		lifecycle.Shutdown()
+		os.Exit(0)
+
+	default:
+		sendError(encoder, req.ID, -32601, "Method not found")
+	}
+}
+
+// getTools returns the tools exposed by this server
+func getTools() []Tool {
+	jobID := Property{
+		Type:        "string",
+		Description: "Job ID as returned by jobs-submit or jobs-list",
+	}
+
+	tools := []Tool{
+		{
+			Name:        "jobs-submit",
+			Description: "Start an extension command as a background job and return the job",
+			InputSchema: InputSchema{
+				Type: "object",
+				Properties: map[string]Property{
+					"extension": {
+						Type:        "string",
+						Description: "Name of a configured extension, e.g. go",
+					},
+					"args": {
+						Type:        "array",
+						Description: "Command and arguments to run in the extension container",
+						Items:       &Property{Type: "string"},
+					},
+				},
+				Required: []string{"extension"},
+			},
+		},
+		{
+			Name:        "jobs-list",
+			Description: "List background jobs with their status and exit code",
+			InputSchema: InputSchema{
+				Type:       "object",
+				Properties: map[string]Property{},
+			},
+		},
+		{
+			Name:        "jobs-logs",
+			Description: "Get the output of a background job so far",
+			InputSchema: InputSchema{
+				Type: "object",
+				Properties: map[string]Property{
+					"job_id": jobID,
+					"tail": {
+						Type:        "integer",
+						Description: "Only return the last N lines",
+					},
+				},
+				Required: []string{"job_id"},
+			},
+		},
+		{
+			Name:        "jobs-cancel",
+			Description: "Stop a running background job",
+			InputSchema: InputSchema{
+				Type: "object",
+				Properties: map[string]Property{
+					"job_id": jobID,
+				},
+				Required: []string{"job_id"},
+			},
+		},
+	}
+
+	for i := range tools {
+		addWorkspaceProperty(&tools[i].InputSchema)
+	}
+	return tools
+}
+
+// addWorkspaceProperty lets tools choose the workspace root when WORKSPACE_ROOT
+// lists several (multi-root editor workspaces)
+func addWorkspaceProperty(schema *InputSchema) {
+	roots := repository.WorkspaceRoots()
+	if len(roots) < 2 {
+		return
+	}
+
+	names := make([]string, len(roots))
+	for i, root := range roots {
+		names[i] = filepath.Base(root)
+	}
+	schema.Properties["workspace"] = Property{
+		Type:        "string",
+		Description: fmt.Sprintf("Workspace root folder name or path (optional, default %s). Available: %s", names[0], strings.Join(names, ", ")),
+	}
+}
+
+// findTool looks up a tool definition by name
+func findTool(name string) (Tool, bool) {
+	for _, tool := range getTools() {
+		if tool.Name == name {
+			return tool, true
+		}
+	}
+	return Tool{}, false
+}
+
+// jobArgs are the arguments of the jobs tools
+type jobArgs struct {
+	Workspace string   `json:"workspace"`
+	Extension string   `json:"extension"`
+	Args      []string `json:"args"`
+	JobID     string   `json:"job_id"`
+	Tail      int      `json:"tail"`
+}
+
+// callTool runs a tool. It returns an *mcpserver.ArgumentError when the
+// arguments do not decode.
+func callTool(params *CallToolParams) (ToolResult, error) {
+	var args jobArgs
+	if err := mcpserver.DecodeArguments(params.Arguments, &args); err != nil {
+		return ToolResult{}, err
+	}
+
+	switch params.Name {
+	case "jobs-submit":
+		command := append([]string{"jobs", "submit", "--json", args.Extension}, args.Args...)
+		return execR2R(args.Workspace, command...), nil
+
+	case "jobs-list":
+		return execR2R(args.Workspace, "jobs", "list", "--json"), nil
+
+	case "jobs-logs":
+		result := execR2R(args.Workspace, "jobs", "logs", args.JobID)
+		if args.Tail > 0 && !result.IsError {
+			result.Content[0].Text = lastLines(result.Content[0].Text, args.Tail)
+		}
+		return result, nil
+
+	case "jobs-cancel":
+		return execR2R(args.Workspace, "jobs", "cancel", "--json", args.JobID), nil
+
+	default:
+		return errorResult(fmt.Sprintf("Unknown tool: %s", params.Name)), nil
+	}
+}
+
+// execR2R runs r2r in the selected workspace root and returns its output
+func execR2R(workspace string, args ...string) ToolResult {
+	r2rPath := findR2R()
+	if r2rPath == "" {
+		return errorResult("Error: r2r not found. Add it to PATH or set R2R_PATH")
+	}
+
+	root, err := repository.FindWorkspaceRoot(workspace)
+	if err != nil {
+		return errorResult(fmt.Sprintf("Error: Could not find repository root: %v", err))
+	}
+
+	cmd := exec.Command(r2rPath, args...)
+	cmd.Dir = root
+
+	output, err := lifecycle.CombinedOutput(cmd)
+	if err != nil {
+		return errorResult(fmt.Sprintf("Error: %v\nOutput: %s", err, strings.TrimSpace(string(output))))
+	}
+
+	return textResult(strings.TrimSpace(string(output)))
+}
+
+// findR2R locates the r2r executable, preferring R2R_PATH
+func findR2R() string {
+	if path := os.Getenv("R2R_PATH"); path != "" {
+		return path
+	}
+	if path, err := exec.LookPath("r2r"); err == nil {
+		return path
+	}
+	return ""
+}
+
+// lastLines returns the last n lines of text
+func lastLines(text string, n int) string {
+	lines := strings.Split(text, "\n")
+	if len(lines) <= n {
+		return text
+	}
+	return strings.Join(lines[len(lines)-n:], "\n")
+}
+
+func textResult(text string) ToolResult {
+	return ToolResult{
+		Content: []Content{{
+			Type: "text",
+			Text: text,
+		}},
+	}
+}
+
+func errorResult(message string) ToolResult {
+	return ToolResult{
+		Content: []Content{{
+			Type: "text",
+			Text: message,
+		}},
+		IsError: true,
+	}
+}
+
+func sendResponse(encoder *json.Encoder, id interface{}, result interface{}) {
+	resp := MCPResponse{
+		JSONRPC: "2.0",
+		ID:      id,
+		Result:  result,
+	}
+	encoder.Encode(resp)
+}
+
+func sendError(encoder *json.Encoder, id interface{}, code int, message string) {
+	resp := MCPResponse{
+		JSONRPC: "2.0",
+		ID:      id,
+		Error: &MCPError{
+			Code:    code,
+			Message: message,
+		},
+	}
+	encoder.Encode(resp)
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ready-to-release/eac/src/core/audit"
+)
+
+// callJobsTool sends a tools/call request through handleRequest and returns
+// the response
+func callJobsTool(t *testing.T, name string, arguments string) MCPResponse {
+	t.Helper()
+	t.Setenv(audit.PathEnv, filepath.Join(t.TempDir(), "audit.jsonl"))
+
+	params := `{"name":"` + name + `","arguments":` + arguments + `}`
+	var out bytes.Buffer
+	handleRequest(json.NewEncoder(&out), &MCPRequest{JSONRPC: "2.0", ID: 1, Method: "tools/call", Params: json.RawMessage(params)})
+
+	var resp MCPResponse
+	if err := json.Unmarshal(out.Bytes(), &resp); err != nil {
+		t.Fatalf("invalid response %q: %v", out.String(), err)
+	}
+	return resp
+}
+
+func TestInvalidArguments(t *testing.T) {
+	tests := []struct {
+		name      string
+		tool      string
+		arguments string
+		want      string
+	}{
+		{
+			name:      "missing required",
+			tool:      "jobs-logs",
+			arguments: `{}`,
+			want:      "arguments.job_id: is required",
+		},
+		{
+			name:      "wrong type",
+			tool:      "jobs-logs",
+			arguments: `{"job_id":"j1","tail":"10"}`,
+			want:      "arguments.tail: expected integer, got string",
+		},
+		{
+			name:      "array item",
+			tool:      "jobs-submit",
+			arguments: `{"extension":"go","args":["test",1]}`,
+			want:      "arguments.args[1]: expected string, got number",
+		},
+		{
+			// workspace is only in the schema with several workspace roots,
+			// so decoding catches it
+			name:      "undeclared argument of the wrong type",
+			tool:      "jobs-list",
+			arguments: `{"workspace":5}`,
+			want:      "arguments.workspace: expected string, got number",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("WORKSPACE_ROOT", "")
+			resp := callJobsTool(t, tt.tool, tt.arguments)
+			if resp.Error == nil {
+				t.Fatalf("expected an error response, got %+v", resp.Result)
+			}
+			if resp.Error.Code != -32602 {
+				t.Errorf("code = %d, want -32602", resp.Error.Code)
+			}
+			if !strings.HasPrefix(resp.Error.Message, "Invalid params: ") || !strings.Contains(resp.Error.Message, tt.want) {
+				t.Errorf("message = %q, want %q", resp.Error.Message, tt.want)
+			}
+		})
+	}
+}
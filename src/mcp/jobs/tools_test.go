@@ -0,0 +1,225 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// The tools run the test binary as r2r: with FAKE_R2R_STATE set, TestMain
+// answers "r2r jobs ..." from a job list kept in that directory.
+
+func TestMain(m *testing.M) {
+	if os.Getenv("FAKE_R2R_STATE") != "" && len(os.Args) > 1 && os.Args[1] == "jobs" {
+		os.Exit(fakeR2R(os.Args[2:]))
+	}
+	os.Exit(m.Run())
+}
+
+type fakeJob struct {
+	ID        string   `json:"id"`
+	Extension string   `json:"extension"`
+	Args      []string `json:"args,omitempty"`
+	Status    string   `json:"status"`
+	Dir       string   `json:"dir"`
+}
+
+// fakeR2R implements submit, list, logs and cancel of "r2r jobs"
+func fakeR2R(args []string) int {
+	statePath := filepath.Join(os.Getenv("FAKE_R2R_STATE"), "jobs.json")
+	var jobs []fakeJob
+	if data, err := os.ReadFile(statePath); err == nil {
+		json.Unmarshal(data, &jobs)
+	}
+	save := func() {
+		data, _ := json.Marshal(jobs)
+		os.WriteFile(statePath, data, 0644)
+	}
+	find := func(id string) *fakeJob {
+		for i := range jobs {
+			if jobs[i].ID == id {
+				return &jobs[i]
+			}
+		}
+		fmt.Fprintf(os.Stderr, "❌ job not found: %s\n", id)
+		return nil
+	}
+	printJSON := func(v interface{}) {
+		data, _ := json.MarshalIndent(v, "", "  ")
+		fmt.Println(string(data))
+	}
+
+	// Drop --json, which every tool passes but logs
+	var words []string
+	for _, arg := range args {
+		if arg != "--json" {
+			words = append(words, arg)
+		}
+	}
+
+	dir, _ := os.Getwd()
+	switch words[0] {
+	case "submit":
+		job := fakeJob{ID: fmt.Sprintf("job-%d", len(jobs)+1), Extension: words[1], Args: words[2:], Status: "running", Dir: dir}
+		jobs = append(jobs, job)
+		save()
+		printJSON(job)
+	case "list":
+		if jobs == nil {
+			jobs = []fakeJob{}
+		}
+		printJSON(jobs)
+	case "logs":
+		job := find(words[1])
+		if job == nil {
+			return 1
+		}
+		for i := 1; i <= 5; i++ {
+			fmt.Printf("%s line %d\n", job.ID, i)
+		}
+	case "cancel":
+		job := find(words[1])
+		if job == nil {
+			return 1
+		}
+		job.Status = "cancelled"
+		save()
+		printJSON(job)
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %s\n", words[0])
+		return 1
+	}
+	return 0
+}
+
+// useFakeR2R points the tools at the fake r2r and a workspace root
+func useFakeR2R(t *testing.T) string {
+	t.Helper()
+	executable, err := os.Executable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	root := t.TempDir()
+	t.Setenv("R2R_PATH", executable)
+	t.Setenv("FAKE_R2R_STATE", t.TempDir())
+	t.Setenv("WORKSPACE_ROOT", root)
+	return root
+}
+
+// toolText calls a tool and returns the text of its result
+func toolText(t *testing.T, name string, arguments string) (string, bool) {
+	t.Helper()
+	resp := callJobsTool(t, name, arguments)
+	if resp.Error != nil {
+		t.Fatalf("%s returned error %d: %s", name, resp.Error.Code, resp.Error.Message)
+	}
+	data, _ := json.Marshal(resp.Result)
+	var result ToolResult
+	if err := json.Unmarshal(data, &result); err != nil || len(result.Content) != 1 {
+		t.Fatalf("%s returned %s", name, data)
+	}
+	return result.Content[0].Text, result.IsError
+}
+
+func decodeJob(t *testing.T, text string) fakeJob {
+	t.Helper()
+	var job fakeJob
+	if err := json.Unmarshal([]byte(text), &job); err != nil {
+		t.Fatalf("not a job: %q", text)
+	}
+	return job
+}
+
+func TestJobsSubmit(t *testing.T) {
+	root := useFakeR2R(t)
+
+	text, isError := toolText(t, "jobs-submit", `{"extension":"go","args":["test","./...","-run","Test A"]}`)
+	if isError {
+		t.Fatalf("jobs-submit failed: %s", text)
+	}
+	job := decodeJob(t, text)
+	if job.ID != "job-1" || job.Extension != "go" || job.Status != "running" {
+		t.Errorf("job = %+v", job)
+	}
+	// Arguments are passed one by one, without shell splitting
+	if strings.Join(job.Args, "|") != "test|./...|-run|Test A" {
+		t.Errorf("args = %q", job.Args)
+	}
+	if realRoot, _ := filepath.EvalSymlinks(root); job.Dir != root && job.Dir != realRoot {
+		t.Errorf("r2r ran in %s, want the workspace root %s", job.Dir, root)
+	}
+}
+
+func TestJobsList(t *testing.T) {
+	useFakeR2R(t)
+
+	text, _ := toolText(t, "jobs-list", `{}`)
+	if text != "[]" {
+		t.Errorf("jobs-list without jobs = %q, want []", text)
+	}
+
+	toolText(t, "jobs-submit", `{"extension":"go"}`)
+	toolText(t, "jobs-submit", `{"extension":"pwsh","args":["-c","Get-Date"]}`)
+
+	text, isError := toolText(t, "jobs-list", `{}`)
+	var jobs []fakeJob
+	if err := json.Unmarshal([]byte(text), &jobs); err != nil || isError {
+		t.Fatalf("jobs-list = %q", text)
+	}
+	if len(jobs) != 2 || jobs[0].Status != "running" || jobs[1].Extension != "pwsh" {
+		t.Errorf("jobs = %+v", jobs)
+	}
+}
+
+func TestJobsLogs(t *testing.T) {
+	useFakeR2R(t)
+	toolText(t, "jobs-submit", `{"extension":"go"}`)
+
+	text, isError := toolText(t, "jobs-logs", `{"job_id":"job-1"}`)
+	if isError || strings.Count(text, "\n") != 4 || !strings.HasPrefix(text, "job-1 line 1") {
+		t.Errorf("jobs-logs = %q", text)
+	}
+
+	text, _ = toolText(t, "jobs-logs", `{"job_id":"job-1","tail":2}`)
+	if text != "job-1 line 4\njob-1 line 5" {
+		t.Errorf("jobs-logs with tail 2 = %q", text)
+	}
+
+	text, isError = toolText(t, "jobs-logs", `{"job_id":"job-9","tail":2}`)
+	if !isError || !strings.Contains(text, "job not found") {
+		t.Errorf("jobs-logs of an unknown job = %q (error %v), want the r2r error", text, isError)
+	}
+}
+
+func TestJobsCancel(t *testing.T) {
+	useFakeR2R(t)
+	toolText(t, "jobs-submit", `{"extension":"go"}`)
+
+	text, isError := toolText(t, "jobs-cancel", `{"job_id":"job-1"}`)
+	if isError || decodeJob(t, text).Status != "cancelled" {
+		t.Errorf("jobs-cancel = %q", text)
+	}
+
+	text, _ = toolText(t, "jobs-list", `{}`)
+	if !strings.Contains(text, `"status": "cancelled"`) {
+		t.Errorf("jobs-list after cancel = %q", text)
+	}
+
+	text, isError = toolText(t, "jobs-cancel", `{"job_id":"job-9"}`)
+	if !isError || !strings.Contains(text, "job not found") {
+		t.Errorf("jobs-cancel of an unknown job = %q (error %v)", text, isError)
+	}
+}
+
+func TestJobsWithoutR2R(t *testing.T) {
+	t.Setenv("R2R_PATH", "")
+	t.Setenv("PATH", t.TempDir())
+
+	text, isError := toolText(t, "jobs-list", `{}`)
+	if !isError || !strings.Contains(text, "r2r not found") {
+		t.Errorf("jobs-list without r2r = %q (error %v)", text, isError)
+	}
+}
@@ -0,0 +1,15 @@
+module github.com/ready-to-release/eac/mcp-server-shell
+
+go 1.25.3
+
+require (
+	github.com/ready-to-release/eac/src/core v0.0.0
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require (
+	github.com/gobwas/glob v0.2.3 // indirect
+	golang.org/x/sys v0.36.0 // indirect
+)
+
+replace github.com/ready-to-release/eac/src/core => ../../core
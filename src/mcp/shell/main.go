@@ -0,0 +1,478 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ready-to-release/eac/src/core/audit"
+	"github.com/ready-to-release/eac/src/core/mcpserver"
+	"github.com/ready-to-release/eac/src/core/repository"
+)
+
+// MCP Server for shell commands: PowerShell, bash, sh, zsh and cmd, whichever
+// are installed (shells.go). Commands are checked against the command policy
+// (policy.go) before they run.
+
+type MCPRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      interface{}     `json:"id"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type MCPResponse struct {
+	JSONRPC string      `json:"jsonrpc"`
+	ID      interface{} `json:"id"`
+	Result  interface{} `json:"result,omitempty"`
+	Error   *MCPError   `json:"error,omitempty"`
+}
+
+type MCPError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// Tool, InputSchema and Property are shared with the other servers, which
+// validate arguments against them (src/core/mcpserver)
+type (
+	Tool        = mcpserver.Tool
+	InputSchema = mcpserver.InputSchema
+	Property    = mcpserver.Property
+)
+
+type CallToolParams struct {
+	Name      string                 `json:"name"`
+	Arguments map[string]interface{} `json:"arguments"`
+}
+
+type ToolResult struct {
+	Content []Content `json:"content"`
+	IsError bool      `json:"isError,omitempty"`
+}
+
+type Content struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// lifecycle tracks the child processes of the server and kills them on
+// shutdown
+var lifecycle = mcpserver.NewLifecycle()
+
+// stderr is the diagnostics stream of the server, with credentials masked
+var stderr = mcpserver.Stderr
+
+func main() {
+	writer := mcpserver.NewMessageWriter(os.Stdout)
+	reader := mcpserver.NewMessageReader(os.Stdin, writer)
+	encoder := json.NewEncoder(writer)
+
+	lifecycle.HandleSignals()
+
+	for {
+		line, err := reader.ReadMessage()
+		if err != nil {
+			if errors.Is(err, mcpserver.ErrMessageTooLarge) {
+				sendError(encoder, nil, -32600, err.Error())
+				continue
+			}
+			if errors.Is(err, mcpserver.ErrInvalidHeader) {
+				sendError(encoder, nil, -32700, err.Error())
+				continue
+			}
+			break
+		}
+
+		var req MCPRequest
+		if err := json.Unmarshal(line, &req); err != nil {
+			sendError(encoder, nil, -32700, "Parse error")
+			continue
+		}
+
+		handleRequest(encoder, &req)
+	}
+
+	lifecycle.Shutdown()
+}
+
+func handleRequest(encoder *json.Encoder, req *MCPRequest) {
+	switch req.Method {
+	case "initialize":
+		sendResponse(encoder, req.ID, map[string]interface{}{
+			"protocolVersion": "2024-11-05",
+			"serverInfo": map[string]string{
+				"name":    "mcp-server-shell",
+				"version": "0.1.0",
+			},
+			"capabilities": map[string]interface{}{
+				"tools": map[string]bool{},
+			},
+		})
+
+	case "tools/list":
+		tools := namespace.Advertise(getTools())
+		sendResponse(encoder, req.ID, map[string]interface{}{
+			"tools": tools,
+		})
+
+	case "tools/call":
+		var params CallToolParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			sendError(encoder, req.ID, -32602, "Invalid params")
+			return
+		}
+		params.Name = namespace.Resolve(params.Name, toolNames)
+		record := audit.StartTool(auditSource, params.Name, params.Arguments)
+
+		if tool, ok := findTool(params.Name); ok {
+			if errs := mcpserver.ValidateArguments(tool.InputSchema, params.Arguments); len(errs) > 0 {
+				message := mcpserver.FormatArgumentErrors(errs...)
+				mcpserver.FinishAudit(record, true, errors.New(message))
+				sendError(encoder, req.ID, -32602, message)
+				return
+			}
+		}
+
+		result, err := callTool(&params)
+		var argErr *mcpserver.ArgumentError
+		if errors.As(err, &argErr) {
+			message := mcpserver.FormatArgumentErrors(argErr)
+			mcpserver.FinishAudit(record, true, errors.New(message))
+			sendError(encoder, req.ID, -32602, message)
+			return
+		}
+		mcpserver.FinishAudit(record, result.IsError, nil)
+		sendResponse(encoder, req.ID, result)
+
+	case "shutdown":
+		lifecycle.Shutdown()
+		sendResponse(encoder, req.ID, map[string]interface{}{})
+
+	case "exit":
+		lifecycle.Shutdown()
+		os.Exit(0)
+
+	default:
+		sendError(encoder, req.ID, -32601, "Method not found")
+	}
+}
+
+// getTools returns the tools exposed by this server
+func getTools() []Tool {
+	detected := detectShells()
+	defaultShell := "none"
+	if len(detected) > 0 {
+		defaultShell = detected[0].Name
+	}
+	runProperties := map[string]Property{
+		"timeout_seconds": {
+			Type:        "integer",
+			Description: fmt.Sprintf("Seconds before the command is killed (optional, default %d, at most %d)", int(defaultRunOptions().Timeout.Seconds()), int(maxTimeout().Seconds())),
+		},
+		"cwd": {
+			Type:        "string",
+			Description: "Working directory inside the repository, relative to the repository root or absolute (optional, default the server's working directory)",
+		},
+		"use_repo_root": {
+			Type:        "boolean",
+			Description: "Run in the repository root, as the r2r CLI does (optional, default false; ignored when cwd is given)",
+		},
+		"env": {
+			Type:        "object",
+			Description: "Environment variables to set, e.g. {\"R2R_LOG_LEVEL\": \"debug\"} (optional; variables such as PATH, BASH_ENV and LD_PRELOAD are refused)",
+		},
+	}
+
+	tools := []Tool{
+		{
+			Name:        "execute-shell",
+			Description: "Execute a command with an installed shell. Commands rejected by the command policy return the violations as JSON.",
+			InputSchema: InputSchema{
+				Type: "object",
+				Properties: map[string]Property{
+					"command": {
+						Type:        "string",
+						Description: "Command or script to execute",
+					},
+					"shell": {
+						Type:        "string",
+						Description: fmt.Sprintf("Shell to run the command with (optional, default %s)", defaultShell),
+						Enum:        shellNames(detected),
+					},
+				},
+				Required: []string{"command"},
+			},
+		},
+		{
+			Name:        "execute-pwsh",
+			Description: "Execute a PowerShell command. Same as execute-shell with shell pwsh.",
+			InputSchema: InputSchema{
+				Type: "object",
+				Properties: map[string]Property{
+					"command": {
+						Type:        "string",
+						Description: "PowerShell command or script to execute",
+					},
+				},
+				Required: []string{"command"},
+			},
+		},
+		{
+			Name:        "list-shells",
+			Description: "List the installed shells and the default shell",
+			InputSchema: InputSchema{
+				Type:       "object",
+				Properties: map[string]Property{},
+			},
+		},
+		{
+			Name:        "get-pwsh-modules",
+			Description: "List available PowerShell modules",
+			InputSchema: InputSchema{
+				Type:       "object",
+				Properties: map[string]Property{},
+			},
+		},
+	}
+
+	for i := range tools {
+		if strings.HasPrefix(tools[i].Name, "execute-") {
+			for name, property := range runProperties {
+				tools[i].InputSchema.Properties[name] = property
+			}
+		}
+		addWorkspaceProperty(&tools[i].InputSchema)
+	}
+	return tools
+}
+
+// addWorkspaceProperty lets tools choose the workspace root when WORKSPACE_ROOT
+// lists several (multi-root editor workspaces)
+func addWorkspaceProperty(schema *InputSchema) {
+	roots := repository.WorkspaceRoots()
+	if len(roots) < 2 {
+		return
+	}
+
+	names := make([]string, len(roots))
+	for i, root := range roots {
+		names[i] = filepath.Base(root)
+	}
+	schema.Properties["workspace"] = Property{
+		Type:        "string",
+		Description: fmt.Sprintf("Workspace root folder name or path (optional, default %s). Available: %s", names[0], strings.Join(names, ", ")),
+	}
+}
+
+// findTool looks up a tool definition by name
+func findTool(name string) (Tool, bool) {
+	for _, tool := range getTools() {
+		if tool.Name == name {
+			return tool, true
+		}
+	}
+	return Tool{}, false
+}
+
+// shellArgs are the arguments of the shell tools
+type shellArgs struct {
+	Workspace      string            `json:"workspace"`
+	Command        string            `json:"command"`
+	Shell          string            `json:"shell"`
+	TimeoutSeconds *int              `json:"timeout_seconds"`
+	Cwd            string            `json:"cwd"`
+	UseRepoRoot    bool              `json:"use_repo_root"`
+	Env            map[string]string `json:"env"`
+}
+
+// callTool runs a tool. It returns an *mcpserver.ArgumentError when the
+// arguments do not decode.
+func callTool(params *CallToolParams) (ToolResult, error) {
+	var args shellArgs
+	if err := mcpserver.DecodeArguments(params.Arguments, &args); err != nil {
+		return ToolResult{}, err
+	}
+
+	switch params.Name {
+	case "execute-shell", "execute-pwsh":
+		if strings.TrimSpace(args.Command) == "" {
+			return errorResult("command must not be empty"), nil
+		}
+		shell := "pwsh"
+		if params.Name == "execute-shell" {
+			shell = args.Shell
+			if shell == "" {
+				detected := detectShells()
+				if len(detected) == 0 {
+					return errorResult("Error: no supported shell found on PATH"), nil
+				}
+				shell = detected[0].Name
+			}
+		}
+		options, err := runOptions(args)
+		if err != nil {
+			return errorResult(err.Error()), nil
+		}
+		if result, ok := checkPolicy(args.Workspace, args.Command); !ok {
+			return result, nil
+		}
+		return execShell(shell, args.Command, options), nil
+
+	case "list-shells":
+		data, _ := json.MarshalIndent(detectShells(), "", "  ")
+		return textResult(string(data)), nil
+
+	case "get-pwsh-modules":
+		return execShell("pwsh", "Get-Module -ListAvailable | Sort-Object Name -Unique | Select-Object Name, Version | ConvertTo-Json -Compress", defaultRunOptions()), nil
+
+	default:
+		return errorResult(fmt.Sprintf("Unknown tool: %s", params.Name)), nil
+	}
+}
+
+// runOptions applies the timeout_seconds, cwd, use_repo_root and env
+// arguments to the default limits
+func runOptions(args shellArgs) (RunOptions, error) {
+	options := defaultRunOptions()
+	if args.TimeoutSeconds != nil {
+		seconds := *args.TimeoutSeconds
+		timeout := time.Duration(seconds) * time.Second
+		if seconds <= 0 || timeout > maxTimeout() {
+			return options, fmt.Errorf("timeout_seconds must be between 1 and %d", int(maxTimeout().Seconds()))
+		}
+		options.Timeout = timeout
+	}
+
+	if args.Cwd != "" || args.UseRepoRoot {
+		root, err := repository.FindWorkspaceRoot(args.Workspace)
+		if err != nil {
+			return options, fmt.Errorf("cwd and use_repo_root need a repository: %v", err)
+		}
+		options.Dir, err = resolveDir(root, args.Cwd)
+		if err != nil {
+			return options, err
+		}
+	}
+
+	for name, value := range args.Env {
+		if name == "" || strings.ContainsAny(name, "=\x00") {
+			return options, fmt.Errorf("invalid environment variable name %q", name)
+		}
+		if protectedEnv(name) {
+			return options, fmt.Errorf("environment variable %s cannot be set: it changes what the shell sources or runs", name)
+		}
+		options.Env = append(options.Env, name+"="+value)
+	}
+	sort.Strings(options.Env)
+	return options, nil
+}
+
+// protectedEnvNames are the variables that make a shell source a file or run
+// other programs than the command names, which the command policy cannot see
+var protectedEnvNames = []string{"BASH_ENV", "ENV", "PATH", "ZDOTDIR", "PSMODULEPATH", "IFS", "SHELLOPTS", "BASHOPTS", "PROMPT_COMMAND"}
+
+// protectedEnv reports whether the env argument must not set name. Names are
+// compared case-insensitively, as Windows does.
+func protectedEnv(name string) bool {
+	upper := strings.ToUpper(name)
+	if strings.HasPrefix(upper, "LD_") || strings.HasPrefix(upper, "DYLD_") || strings.HasPrefix(upper, "BASH_FUNC_") {
+		return true
+	}
+	return slices.Contains(protectedEnvNames, upper)
+}
+
+// resolveDir resolves cwd against the repository root and checks that it is
+// a directory inside the repository, following symlinks. An empty cwd is the
+// root itself.
+func resolveDir(root, cwd string) (string, error) {
+	dir := cwd
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(root, dir)
+	}
+	resolved, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return "", fmt.Errorf("cwd %s does not exist", cwd)
+	}
+	if info, err := os.Stat(resolved); err != nil || !info.IsDir() {
+		return "", fmt.Errorf("cwd %s is not a directory", cwd)
+	}
+	realRoot, err := filepath.EvalSymlinks(root)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve repository root %s: %v", root, err)
+	}
+	rel, err := filepath.Rel(realRoot, resolved)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("cwd %s is outside the repository %s", cwd, root)
+	}
+	return resolved, nil
+}
+
+// checkPolicy checks command against the policy of the workspace. Outside a
+// repository only the built-in rules apply.
+func checkPolicy(workspace, command string) (ToolResult, bool) {
+	root, _ := repository.FindWorkspaceRoot(workspace)
+	policy, err := LoadPolicy(root)
+	if err != nil {
+		return policyError([]Violation{{Rule: "policy", Reason: err.Error()}}), false
+	}
+	if violations := policy.Check(command); len(violations) > 0 {
+		return policyError(violations), false
+	}
+	return ToolResult{}, true
+}
+
+// policyError returns the violations as a JSON error result
+func policyError(violations []Violation) ToolResult {
+	data, _ := json.MarshalIndent(map[string]interface{}{
+		"error":      "policy_violation",
+		"violations": violations,
+	}, "", "  ")
+	return errorResult(string(data))
+}
+
+func textResult(text string) ToolResult {
+	return ToolResult{
+		Content: []Content{{
+			Type: "text",
+			Text: text,
+		}},
+	}
+}
+
+func errorResult(message string) ToolResult {
+	return ToolResult{
+		Content: []Content{{
+			Type: "text",
+			Text: message,
+		}},
+		IsError: true,
+	}
+}
+
+func sendResponse(encoder *json.Encoder, id interface{}, result interface{}) {
+	resp := MCPResponse{
+		JSONRPC: "2.0",
+		ID:      id,
+		Result:  result,
+	}
+	encoder.Encode(resp)
+}
+
+func sendError(encoder *json.Encoder, id interface{}, code int, message string) {
+	resp := MCPResponse{
+		JSONRPC: "2.0",
+		ID:      id,
+		Error: &MCPError{
+			Code:    code,
+			Message: message,
+		},
+	}
+	encoder.Encode(resp)
+}
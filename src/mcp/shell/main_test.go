@@ -0,0 +1,167 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// workspace creates a repository root with a src/app directory, a README
+// file and a sibling directory outside the root
+func workspace(t *testing.T) (root string, outside string) {
+	t.Helper()
+	base := t.TempDir()
+	root = filepath.Join(base, "repo")
+	outside = filepath.Join(base, "outside")
+	for _, dir := range []string{filepath.Join(root, "src", "app"), outside} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(root, "README.md"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	return root, outside
+}
+
+// symlink creates link pointing to target, skipping the test where the
+// platform does not allow it
+func symlink(t *testing.T, target, link string) {
+	t.Helper()
+	if err := os.Symlink(target, link); err != nil {
+		if runtime.GOOS == "windows" {
+			t.Skipf("symlinks need developer mode on Windows: %v", err)
+		}
+		t.Fatal(err)
+	}
+}
+
+func TestResolveDir(t *testing.T) {
+	root, outside := workspace(t)
+	symlink(t, outside, filepath.Join(root, "escape"))
+	symlink(t, filepath.Join(root, "src"), filepath.Join(root, "source"))
+
+	realRoot, err := filepath.EvalSymlinks(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		cwd     string
+		want    string // relative to the resolved root
+		wantErr string
+	}{
+		{name: "empty is the root", cwd: "", want: "."},
+		{name: "relative", cwd: "src/app", want: "src/app"},
+		{name: "dot segments inside the root", cwd: "src/app/../app/./", want: "src/app"},
+		{name: "absolute inside the root", cwd: filepath.Join(root, "src"), want: "src"},
+		{name: "symlink inside the root", cwd: "source/app", want: "src/app"},
+		{name: "parent of the root", cwd: "..", wantErr: "outside the repository"},
+		{name: "traversal to a sibling", cwd: "src/../../outside", wantErr: "outside the repository"},
+		{name: "absolute outside the root", cwd: outside, wantErr: "outside the repository"},
+		{name: "symlink out of the root", cwd: "escape", wantErr: "outside the repository"},
+		{name: "missing", cwd: "missing", wantErr: "does not exist"},
+		{name: "file", cwd: "README.md", wantErr: "is not a directory"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir, err := resolveDir(root, filepath.FromSlash(tt.cwd))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("resolveDir(%q) = %q, %v; want an error containing %q", tt.cwd, dir, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("resolveDir(%q): %v", tt.cwd, err)
+			}
+			if want := filepath.Join(realRoot, filepath.FromSlash(tt.want)); dir != want {
+				t.Errorf("resolveDir(%q) = %q, want %q", tt.cwd, dir, want)
+			}
+		})
+	}
+}
+
+func TestResolveDirSiblingWithRootPrefix(t *testing.T) {
+	// A sibling whose name starts with the root's name is still outside
+	root, _ := workspace(t)
+	sibling := root + "-other"
+	if err := os.MkdirAll(sibling, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, cwd := range []string{sibling, filepath.Join("..", filepath.Base(sibling))} {
+		if dir, err := resolveDir(root, cwd); err == nil || !strings.Contains(err.Error(), "outside the repository") {
+			t.Errorf("resolveDir(%q) = %q, %v; want outside the repository", cwd, dir, err)
+		}
+	}
+}
+
+func TestRunOptionsWorkingDirectory(t *testing.T) {
+	root, outside := workspace(t)
+	t.Setenv("WORKSPACE_ROOT", root)
+
+	realRoot, err := filepath.EvalSymlinks(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	options, err := runOptions(shellArgs{UseRepoRoot: true})
+	if err != nil || options.Dir != realRoot {
+		t.Errorf("use_repo_root: Dir = %q, %v; want %q", options.Dir, err, realRoot)
+	}
+
+	options, err = runOptions(shellArgs{Cwd: "src"})
+	if err != nil || options.Dir != filepath.Join(realRoot, "src") {
+		t.Errorf("cwd src: Dir = %q, %v", options.Dir, err)
+	}
+
+	if _, err := runOptions(shellArgs{Cwd: outside}); err == nil {
+		t.Error("cwd outside the repository should be rejected")
+	}
+
+	// Without cwd the command runs in the server's directory
+	options, err = runOptions(shellArgs{})
+	if err != nil || options.Dir != "" {
+		t.Errorf("no cwd: Dir = %q, %v; want empty", options.Dir, err)
+	}
+}
+
+func TestRunOptionsEnv(t *testing.T) {
+	options, err := runOptions(shellArgs{Env: map[string]string{"R2R_LOG_LEVEL": "debug", "CI": "1"}})
+	if err != nil || strings.Join(options.Env, " ") != "CI=1 R2R_LOG_LEVEL=debug" {
+		t.Errorf("Env = %q, %v", options.Env, err)
+	}
+
+	// Variables that make the shell source a file or run other programs
+	// would get past the command policy
+	for _, name := range []string{"BASH_ENV", "ENV", "PATH", "Path", "LD_PRELOAD", "DYLD_INSERT_LIBRARIES", "ZDOTDIR", "PSModulePath", "BASH_FUNC_ls%%", "", "A=B"} {
+		if _, err := runOptions(shellArgs{Env: map[string]string{name: "/tmp/x"}}); err == nil {
+			t.Errorf("env %q was accepted", name)
+		}
+	}
+}
+
+func TestCallToolRefusesBashEnv(t *testing.T) {
+	script := filepath.Join(t.TempDir(), "env.sh")
+	if err := os.WriteFile(script, []byte("echo sourced\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	arguments := map[string]interface{}{
+		"command": "echo ran",
+		"shell":   "bash",
+		"env":     map[string]interface{}{"BASH_ENV": script},
+	}
+
+	result, err := callTool(&CallToolParams{Name: "execute-shell", Arguments: arguments})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.IsError || !strings.Contains(result.Content[0].Text, "BASH_ENV cannot be set") {
+		t.Errorf("execute-shell with BASH_ENV = %+v, want it refused", result)
+	}
+}
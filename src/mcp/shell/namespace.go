@@ -0,0 +1,18 @@
+package main
+
+import "github.com/ready-to-release/eac/src/core/mcpserver"
+
+// serverNamespace is the name of this server in the tool namespace registry
+// (contracts/mcp/tools.yml)
+const serverNamespace = "shell"
+
+// auditSource marks the audit log entries of this server
+const auditSource = "mcp:" + serverNamespace
+
+// namespace advertises the tools under the names the registry assigns
+var namespace = mcpserver.NewNamespace(serverNamespace)
+
+// toolNames returns the names of the tools of this server
+func toolNames() []string {
+	return mcpserver.ToolNames(getTools())
+}
@@ -0,0 +1,374 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Command policy, checked before a command runs. The policy is a guard
+// against mistakes in shared environments, not a sandbox: it inspects the
+// command text, so code that builds commands at run time is not seen. The
+// same rules apply to every shell; statements are split at the separators
+// PowerShell, POSIX shells and cmd have in common.
+
+// PolicyFile is the policy location relative to the workspace root;
+// MCP_SHELL_POLICY overrides it
+const PolicyFile = ".r2r/shell-policy.yml"
+
+// Policy lists the commands that may run
+type Policy struct {
+	// Allow, when not empty, lists the only commands that may run. Each entry
+	// is a case-insensitive regular expression matched against the whole
+	// command name, e.g. "Get-.*" or "\./scripts/.+\.ps1".
+	Allow []string `yaml:"allow"`
+	// Deny rejects statements matching a case-insensitive regular expression
+	Deny []DenyRule `yaml:"deny"`
+	// Network false rejects commands that reach the network
+	Network *bool `yaml:"network"`
+
+	allow []*regexp.Regexp
+	deny  []*regexp.Regexp
+}
+
+// DenyRule is a banned operation
+type DenyRule struct {
+	Pattern string `yaml:"pattern"`
+	Reason  string `yaml:"reason"`
+}
+
+// Violation is a statement rejected by the policy
+type Violation struct {
+	Rule      string `json:"rule"`
+	Statement string `json:"statement"`
+	Reason    string `json:"reason"`
+}
+
+// keywords start statements that are not commands
+var keywords = map[string]bool{
+	"if": true, "elseif": true, "else": true, "foreach": true, "for": true, "while": true,
+	"do": true, "until": true, "switch": true, "try": true, "catch": true, "finally": true,
+	"function": true, "filter": true, "param": true, "begin": true, "process": true, "end": true,
+	"return": true, "break": true, "continue": true, "throw": true, "trap": true, "exit": true,
+	"in": true, "class": true, "enum": true,
+	"then": true, "elif": true, "fi": true, "done": true, "case": true, "esac": true,
+	"!": true,
+}
+
+// wrappers run the command that follows them, e.g. sudo rm -rf /
+var wrappers = map[string]bool{
+	"sudo": true, "doas": true, "env": true, "nohup": true, "exec": true, "command": true,
+	"builtin": true, "nice": true, "time": true, "xargs": true, "call": true,
+}
+
+// networkCommands reach the network; rejected when the policy sets network: false
+var networkCommands = map[string]bool{
+	"invoke-webrequest": true, "iwr": true, "invoke-restmethod": true, "irm": true,
+	"curl": true, "wget": true, "start-bitstransfer": true, "test-netconnection": true,
+	"send-mailmessage": true, "invoke-command": true, "enter-pssession": true,
+	"new-pssession": true, "ssh": true, "scp": true, "sftp": true, "ftp": true,
+	"rsync": true, "nc": true, "ncat": true, "netcat": true, "telnet": true, "bitsadmin": true,
+}
+
+// networkTypes are .NET types used to reach the network from PowerShell
+var networkTypes = regexp.MustCompile(`(?i)\b(System\.)?Net\.(WebClient|Http\.HttpClient|Sockets\.)|\[(System\.)?Net\.WebRequest\]`)
+
+// removeCommands are Remove-Item, its aliases and the POSIX and cmd
+// removal commands
+var removeCommands = map[string]bool{
+	"remove-item": true, "rm": true, "ri": true, "del": true, "erase": true, "rd": true, "rmdir": true,
+}
+
+// rootPaths are paths whose recursive removal is always rejected
+var rootPaths = regexp.MustCompile(`(?i)^['"]?(/|\\|[a-z]:[\\/]?|~[\\/]?|\$home[\\/]?|\$env:(userprofile|homedrive|systemroot|home)[\\/]?)\*?['"]?$`)
+
+// builtinRules apply with or without a policy file
+var builtinRules = []struct {
+	name   string
+	reason string
+	match  func(command string, args []string) bool
+}{
+	{
+		name:   "builtin:recursive-root-delete",
+		reason: "recursive removal of a filesystem root or home directory",
+		match: func(command string, args []string) bool {
+			if !removeCommands[command] || !isRecursive(args) {
+				return false
+			}
+			for _, arg := range args {
+				if rootPaths.MatchString(strings.TrimRight(arg, ",")) {
+					return true
+				}
+			}
+			return false
+		},
+	},
+	{
+		name:   "builtin:disk-format",
+		reason: "formatting or clearing disks",
+		match: func(command string, _ []string) bool {
+			switch command {
+			case "format-volume", "clear-disk", "initialize-disk", "remove-partition", "format",
+				"diskpart", "wipefs", "mkfs":
+				return true
+			}
+			return strings.HasPrefix(command, "mkfs.")
+		},
+	},
+	{
+		name:   "builtin:shutdown",
+		reason: "shutting down or restarting the machine",
+		match: func(command string, _ []string) bool {
+			switch command {
+			case "stop-computer", "restart-computer", "shutdown", "reboot", "poweroff", "halt":
+				return true
+			}
+			return false
+		},
+	},
+}
+
+// LoadPolicy reads the policy for a workspace. Without a policy file only
+// the built-in rules apply.
+func LoadPolicy(root string) (*Policy, error) {
+	path := os.Getenv("MCP_SHELL_POLICY")
+	if path == "" {
+		path = filepath.Join(root, PolicyFile)
+	}
+
+	policy := &Policy{}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) && os.Getenv("MCP_SHELL_POLICY") == "" {
+		return policy, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read policy %s: %w", path, err)
+	}
+	if err := yaml.Unmarshal(data, policy); err != nil {
+		return nil, fmt.Errorf("invalid policy %s: %w", path, err)
+	}
+
+	for _, pattern := range policy.Allow {
+		re, err := regexp.Compile(`(?i)^(?:` + pattern + `)$`)
+		if err != nil {
+			return nil, fmt.Errorf("invalid allow pattern %q in %s: %w", pattern, path, err)
+		}
+		policy.allow = append(policy.allow, re)
+	}
+	for _, rule := range policy.Deny {
+		re, err := regexp.Compile(`(?i)` + rule.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid deny pattern %q in %s: %w", rule.Pattern, path, err)
+		}
+		policy.deny = append(policy.deny, re)
+	}
+	return policy, nil
+}
+
+// Check returns the violations of a command, empty when it may run
+func (p *Policy) Check(script string) []Violation {
+	var violations []Violation
+	for _, statement := range splitStatements(script) {
+		words := commandWords(strings.Fields(statement))
+		if len(words) == 0 || !isCommandName(words[0]) {
+			continue
+		}
+		name := strings.Trim(words[0], `'"`)
+		command := strings.ToLower(name)
+		args := words[1:]
+
+		for _, rule := range builtinRules {
+			if rule.match(command, args) {
+				violations = append(violations, Violation{Rule: rule.name, Statement: statement, Reason: rule.reason})
+			}
+		}
+
+		// Deny patterns also see the command without what precedes it, so
+		// "sudo git push" matches "^git push"
+		unwrapped := strings.Join(words, " ")
+		for i, re := range p.deny {
+			if re.MatchString(statement) || re.MatchString(unwrapped) {
+				reason := p.Deny[i].Reason
+				if reason == "" {
+					reason = fmt.Sprintf("matches deny pattern %q", p.Deny[i].Pattern)
+				}
+				violations = append(violations, Violation{Rule: "deny", Statement: statement, Reason: reason})
+			}
+		}
+
+		if p.Network != nil && !*p.Network && (networkCommands[command] || networkTypes.MatchString(statement)) {
+			violations = append(violations, Violation{Rule: "network", Statement: statement, Reason: "network access is disabled by the policy"})
+		}
+
+		if len(p.allow) > 0 && !p.allowed(name) {
+			violations = append(violations, Violation{Rule: "allow", Statement: statement, Reason: fmt.Sprintf("%s is not in the allow list", name)})
+		}
+	}
+
+	// .NET network types can appear anywhere, e.g. in a method call
+	if p.Network != nil && !*p.Network && len(violations) == 0 && networkTypes.MatchString(script) {
+		violations = append(violations, Violation{Rule: "network", Statement: strings.TrimSpace(script), Reason: "network access is disabled by the policy"})
+	}
+	return violations
+}
+
+func (p *Policy) allowed(name string) bool {
+	for _, re := range p.allow {
+		if re.MatchString(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// commandWords skips what precedes the command in a statement: assignments
+// ($x = ..., FOO=bar), keywords, the call operators (& ./x.ps1, . ./x.sh)
+// and wrappers such as sudo with their flags
+func commandWords(words []string) []string {
+	for len(words) > 0 {
+		word := strings.ToLower(words[0])
+		switch {
+		case strings.HasPrefix(word, "$") || word == "=" || word == "&" || word == "." || keywords[word]:
+			words = words[1:]
+		case strings.Contains(word, "=") && !strings.HasPrefix(word, "-"):
+			words = words[1:]
+		case wrappers[word]:
+			words = words[1:]
+			for len(words) > 0 && strings.HasPrefix(words[0], "-") {
+				words = words[1:]
+			}
+		default:
+			return words
+		}
+	}
+	return words
+}
+
+// splitStatements splits a script into statements at ; | & newlines,
+// braces, parentheses and backticks outside quotes, so commands in
+// pipelines, script blocks and subexpressions are checked on their own. A
+// $( subexpression inside double quotes runs too, so it is split out of the
+// string as well.
+func splitStatements(script string) []string {
+	var statements []string
+	var current strings.Builder
+	var quote, prev rune
+	comment := false
+	// depth counts the open parentheses; resume holds the depths at which a
+	// subexpression inside a double-quoted string closes
+	depth := 0
+	var resume []int
+
+	flush := func() {
+		if statement := strings.Join(strings.Fields(current.String()), " "); statement != "" {
+			statements = append(statements, statement)
+		}
+		current.Reset()
+	}
+
+	for _, r := range script {
+		text := current.String()
+		switch {
+		case comment:
+			if r == '\n' {
+				comment = false
+				flush()
+			}
+		case quote == '"' && r == '(' && strings.HasSuffix(text, "$"):
+			depth++
+			resume = append(resume, depth)
+			quote = 0
+			flush()
+		case quote != 0:
+			current.WriteRune(r)
+			if r == quote {
+				quote = 0
+			}
+		case r == '\'' || r == '"':
+			quote = r
+			current.WriteRune(r)
+		case r == '#' && (text == "" || strings.HasSuffix(text, " ") || strings.HasSuffix(text, "\t")):
+			// Comments run to the end of the line
+			comment = true
+		case r == '(':
+			depth++
+			flush()
+		case r == ')':
+			flush()
+			if len(resume) > 0 && resume[len(resume)-1] == depth {
+				resume = resume[:len(resume)-1]
+				quote = '"'
+			}
+			depth--
+		case strings.ContainsRune(";|\n\r{}`", r):
+			flush()
+		case r == '&' && prev == '&':
+			// The second & of &&
+		case r == '&' && strings.TrimSpace(text) != "":
+			// && and a trailing & end a statement; a leading & is the call operator
+			flush()
+		default:
+			current.WriteRune(r)
+		}
+		prev = r
+	}
+	flush()
+	return statements
+}
+
+// isCommandName reports whether a word names a command or script rather
+// than a value (number, string, operator)
+func isCommandName(word string) bool {
+	word = strings.Trim(word, `'"`)
+	if word == "" || strings.Contains(word, "=") {
+		return false
+	}
+	first := word[0]
+	switch {
+	case (first >= 'a' && first <= 'z') || (first >= 'A' && first <= 'Z'):
+		return true
+	case first == '.':
+		// ./x.ps1 or ..\x.ps1, not a member access such as .Count
+		return strings.HasPrefix(word, "./") || strings.HasPrefix(word, `.\`) || strings.HasPrefix(word, "..")
+	}
+	return first == '/' || first == '\\' || first == '~'
+}
+
+// isRecursive reports whether removal args ask for recursion: -Recurse and
+// its prefixes, --recursive, combined POSIX flags (-rf) or cmd's /s
+func isRecursive(args []string) bool {
+	if hasFlag(args, "recurse", "r") {
+		return true
+	}
+	for _, arg := range args {
+		lower := strings.ToLower(arg)
+		switch {
+		case lower == "--recursive" || lower == "/s":
+			return true
+		case len(arg) > 2 && arg[0] == '-' && arg[1] != '-' && strings.ContainsAny(arg, "rR") &&
+			strings.Trim(arg[1:], "rRfidvI") == "":
+			return true
+		}
+	}
+	return false
+}
+
+// hasFlag reports whether args contain a switch, matching PowerShell's
+// unambiguous prefixes (-Recurse, -rec, -r)
+func hasFlag(args []string, name string, shortest string) bool {
+	for _, arg := range args {
+		flag := strings.ToLower(strings.TrimPrefix(arg, "-"))
+		if !strings.HasPrefix(arg, "-") {
+			continue
+		}
+		flag, _, _ = strings.Cut(flag, ":")
+		if len(flag) >= len(shortest) && strings.HasPrefix(name, flag) {
+			return true
+		}
+	}
+	return false
+}
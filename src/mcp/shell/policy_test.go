@@ -0,0 +1,210 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// loadTestPolicy writes a policy file to a workspace and loads it
+func loadTestPolicy(t *testing.T, yaml string) *Policy {
+	t.Helper()
+	t.Setenv("MCP_SHELL_POLICY", "")
+	root := t.TempDir()
+	if yaml != "" {
+		path := filepath.Join(root, PolicyFile)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(yaml), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	policy, err := LoadPolicy(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return policy
+}
+
+// rules returns the rules of the violations, comma-separated
+func rules(violations []Violation) string {
+	names := make([]string, len(violations))
+	for i, violation := range violations {
+		names[i] = violation.Rule
+	}
+	return strings.Join(names, ",")
+}
+
+func TestDefaultPolicy(t *testing.T) {
+	policy := loadTestPolicy(t, "")
+
+	tests := []struct {
+		command string
+		want    string
+	}{
+		{"Get-ChildItem -Recurse", ""},
+		{"rm -rf ./build", ""},
+		{"Remove-Item -Recurse -Force .\\out", ""},
+		{"curl https://example.com", ""},
+		{"rm -rf /", "builtin:recursive-root-delete"},
+		{"rm -r -f ~/", "builtin:recursive-root-delete"},
+		{"sudo rm -rf /*", "builtin:recursive-root-delete"},
+		{"Remove-Item -Recurse C:\\", "builtin:recursive-root-delete"},
+		{"ri -rec $HOME", "builtin:recursive-root-delete"},
+		{"rd /s /q C:\\", "builtin:recursive-root-delete"},
+		{"rm /", ""},
+		{"Format-Volume -DriveLetter D", "builtin:disk-format"},
+		{"mkfs.ext4 /dev/sdb1", "builtin:disk-format"},
+		{"shutdown -h now", "builtin:shutdown"},
+		{"Restart-Computer", "builtin:shutdown"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.command, func(t *testing.T) {
+			if got := rules(policy.Check(tt.command)); got != tt.want {
+				t.Errorf("Check(%q) = %q, want %q", tt.command, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPolicyDenyWins(t *testing.T) {
+	policy := loadTestPolicy(t, `
+allow:
+  - git
+  - Get-.*
+deny:
+  - pattern: git\s+push\s+.*--force
+    reason: force pushes rewrite shared history
+  - pattern: Get-Credential
+`)
+
+	tests := []struct {
+		command string
+		want    string
+	}{
+		{"git status", ""},
+		{"git push origin main", ""},
+		{"Get-ChildItem", ""},
+		// Allowed commands are still rejected by a matching deny rule
+		{"git push origin main --force", "deny"},
+		{"GIT PUSH origin --FORCE", "deny"},
+		{"Get-Credential", "deny"},
+		// Commands outside the allow list are rejected, and so are the
+		// built-in rules even for allowed names
+		{"npm install", "allow"},
+		{"rm -rf /", "builtin:recursive-root-delete,allow"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.command, func(t *testing.T) {
+			if got := rules(policy.Check(tt.command)); got != tt.want {
+				t.Errorf("Check(%q) = %q, want %q", tt.command, got, tt.want)
+			}
+		})
+	}
+
+	violations := policy.Check("git push --force")
+	if len(violations) != 1 || violations[0].Reason != "force pushes rewrite shared history" {
+		t.Errorf("Check = %+v, want the reason of the deny rule", violations)
+	}
+	violations = policy.Check("Get-Credential")
+	if len(violations) != 1 || !strings.Contains(violations[0].Reason, `"Get-Credential"`) {
+		t.Errorf("Check = %+v, want the pattern as the reason", violations)
+	}
+}
+
+func TestPolicyChaining(t *testing.T) {
+	policy := loadTestPolicy(t, `
+deny:
+  - pattern: ^git push
+network: false
+`)
+
+	// Each command hides a rejected statement behind a separator, a
+	// wrapper or a subexpression
+	tests := []struct {
+		name    string
+		command string
+		want    string
+	}{
+		{"semicolon", "echo hi; rm -rf /", "builtin:recursive-root-delete"},
+		{"newline", "echo hi\ngit push", "deny"},
+		{"pipe", "git status | git push", "deny"},
+		{"xargs", "echo / | xargs rm -rf /", "builtin:recursive-root-delete"},
+		{"and", "true && git push", "deny"},
+		{"or", "false || shutdown now", "builtin:shutdown"},
+		{"background", "sleep 1 & git push", "deny"},
+		{"posix subexpression", "echo $(curl https://example.com)", "network"},
+		{"backticks", "echo `git push`", "deny"},
+		{"subexpression in a string", `Write-Host "got $(Invoke-WebRequest https://example.com) (done)"`, "network"},
+		{"nested subexpression in a string", `echo "a $(echo "$(git push)") b"`, "deny"},
+		{"string after a subexpression", `echo "$(date) ; git push"`, ""},
+		{"script block", "& { Remove-Item -Recurse -Force C:\\ }", "builtin:recursive-root-delete"},
+		{"call operator", "& 'Stop-Computer'", "builtin:shutdown"},
+		{"assignment", "$r = Invoke-RestMethod https://example.com", "network"},
+		{"env prefix", "GIT_TRACE=1 env -i git push", "deny"},
+		{"if block", "if ($true) { git push }", "deny"},
+		{".NET type", "[System.Net.WebClient]::new().DownloadString('https://example.com')", "network"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := rules(policy.Check(tt.command)); got != tt.want {
+				t.Errorf("Check(%q) = %q, want %q", tt.command, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPolicyQuoting(t *testing.T) {
+	policy := loadTestPolicy(t, `
+deny:
+  - pattern: ^git push
+`)
+
+	tests := []struct {
+		name    string
+		command string
+		want    string
+	}{
+		{"separator in single quotes", "echo 'a; git push'", ""},
+		{"separator in double quotes", `echo "a && git push"`, ""},
+		{"comment", "echo hi # ; git push", ""},
+		{"quoted command name", "'rm' -rf /", "builtin:recursive-root-delete"},
+		{"quoted root path", `rm -rf "/"`, "builtin:recursive-root-delete"},
+		{"quoted home", `Remove-Item -Recurse -Force '$HOME'`, "builtin:recursive-root-delete"},
+		{"statement after a closed quote", "echo 'a'; git push", "deny"},
+		{"hash inside a word", "echo a#b; git push", "deny"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := rules(policy.Check(tt.command)); got != tt.want {
+				t.Errorf("Check(%q) = %q, want %q", tt.command, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadPolicyErrors(t *testing.T) {
+	root := t.TempDir()
+	path := filepath.Join(root, "policy.yml")
+	t.Setenv("MCP_SHELL_POLICY", path)
+
+	// MCP_SHELL_POLICY must exist
+	if _, err := LoadPolicy(root); err == nil {
+		t.Error("LoadPolicy with a missing MCP_SHELL_POLICY should fail")
+	}
+
+	for _, yaml := range []string{"allow: [\"(\"]\n", "deny:\n  - pattern: \"[\"\n", "allow: {\n"} {
+		if err := os.WriteFile(path, []byte(yaml), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadPolicy(root); err == nil {
+			t.Errorf("LoadPolicy(%q) should fail", yaml)
+		}
+	}
+}
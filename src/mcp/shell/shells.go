@@ -0,0 +1,268 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/ready-to-release/eac/src/core/mcpserver"
+)
+
+// Shells the server runs commands with. Every shell runs non-interactively
+// without user profiles or rc files, so a command behaves the same on every
+// machine, and every run shares the same timeout and output limits.
+
+const (
+	defaultTimeout   = 2 * time.Minute
+	defaultMaxTime   = 10 * time.Minute
+	defaultMaxOutput = 1 << 20
+)
+
+// Shell is a shell commands can run with
+type Shell struct {
+	Name string
+	// candidates are the executables tried in order
+	candidates []string
+	// args returns the arguments that run a command
+	args func(command string) []string
+	// env is added to the environment of every run
+	env []string
+	// windows is true for shells that only exist on Windows
+	windows bool
+}
+
+// shells are the supported shells. Profiles and rc files are skipped:
+// BASH_ENV and ENV name files non-interactive bash and sh would source.
+var shells = []Shell{
+	{
+		Name:       "pwsh",
+		candidates: []string{"pwsh", "powershell"},
+		args: func(command string) []string {
+			return []string{"-NoLogo", "-NoProfile", "-NonInteractive", "-Command", command}
+		},
+		env: []string{"POWERSHELL_TELEMETRY_OPTOUT=1", "POWERSHELL_UPDATECHECK=Off"},
+	},
+	{
+		Name:       "bash",
+		candidates: []string{"bash"},
+		args:       func(command string) []string { return []string{"--noprofile", "--norc", "-c", command} },
+		env:        []string{"BASH_ENV="},
+	},
+	{
+		Name:       "sh",
+		candidates: []string{"sh"},
+		args:       func(command string) []string { return []string{"-c", command} },
+		env:        []string{"ENV="},
+	},
+	{
+		Name:       "zsh",
+		candidates: []string{"zsh"},
+		args:       func(command string) []string { return []string{"-f", "-c", command} },
+	},
+	{
+		Name:       "cmd",
+		candidates: []string{"cmd"},
+		// /d skips AutoRun commands from the registry
+		args:    func(command string) []string { return []string{"/d", "/s", "/c", command} },
+		windows: true,
+	},
+}
+
+// shellPreference orders the shells when picking the default
+func shellPreference() []string {
+	if runtime.GOOS == "windows" {
+		return []string{"pwsh", "cmd", "bash", "sh", "zsh"}
+	}
+	return []string{"bash", "sh", "zsh", "pwsh"}
+}
+
+// commonEnv keeps output free of colors and pagers
+var commonEnv = []string{"TERM=dumb", "NO_COLOR=1", "PAGER=cat", "GIT_PAGER=cat"}
+
+// DetectedShell is a shell found on this machine
+type DetectedShell struct {
+	Name    string `json:"name"`
+	Path    string `json:"path"`
+	Default bool   `json:"default"`
+}
+
+// detectShells returns the shells found on PATH, default first in the
+// platform's order of preference. MCP_SHELL names the default when it is
+// available.
+func detectShells() []DetectedShell {
+	var detected []DetectedShell
+	for _, name := range shellPreference() {
+		shell, _ := findShell(name)
+		if path := shell.path(); path != "" {
+			detected = append(detected, DetectedShell{Name: name, Path: path})
+		}
+	}
+	if len(detected) == 0 {
+		return nil
+	}
+
+	index := 0
+	if preferred := os.Getenv("MCP_SHELL"); preferred != "" {
+		for i, shell := range detected {
+			if shell.Name == preferred {
+				index = i
+			}
+		}
+	}
+	detected[index].Default = true
+	detected[0], detected[index] = detected[index], detected[0]
+	return detected
+}
+
+// shellNames returns the names of the detected shells
+func shellNames(detected []DetectedShell) []string {
+	names := make([]string, len(detected))
+	for i, shell := range detected {
+		names[i] = shell.Name
+	}
+	return names
+}
+
+// findShell looks up a supported shell by name
+func findShell(name string) (Shell, bool) {
+	for _, shell := range shells {
+		if shell.Name == name {
+			return shell, true
+		}
+	}
+	return Shell{}, false
+}
+
+// path returns the executable of the shell, empty when it is not installed
+func (s Shell) path() string {
+	if s.windows && runtime.GOOS != "windows" {
+		return ""
+	}
+	for _, name := range s.candidates {
+		if path, err := exec.LookPath(name); err == nil {
+			return path
+		}
+	}
+	return ""
+}
+
+// RunOptions are the limits, working directory and extra environment of a run
+type RunOptions struct {
+	Timeout   time.Duration
+	MaxOutput int
+	// Dir is the working directory; empty runs in the server's directory
+	Dir string
+	// Env is added after the shell's environment, so it can override it
+	Env []string
+}
+
+// defaultRunOptions reads the limits from MCP_SHELL_TIMEOUT and
+// MCP_SHELL_MAX_OUTPUT
+func defaultRunOptions() RunOptions {
+	return RunOptions{
+		Timeout:   envDuration("MCP_SHELL_TIMEOUT", defaultTimeout),
+		MaxOutput: envInt("MCP_SHELL_MAX_OUTPUT", defaultMaxOutput),
+	}
+}
+
+// maxTimeout caps the timeout_seconds argument
+func maxTimeout() time.Duration {
+	return envDuration("MCP_SHELL_MAX_TIMEOUT", defaultMaxTime)
+}
+
+// execShell runs a command with a shell and returns its output
+func execShell(name, command string, options RunOptions) ToolResult {
+	shell, ok := findShell(name)
+	if !ok {
+		return errorResult(fmt.Sprintf("Error: unknown shell %q", name))
+	}
+	path := shell.path()
+	if path == "" {
+		if name == "pwsh" {
+			return errorResult("Error: PowerShell not found. Install PowerShell 7 (pwsh) from https://aka.ms/powershell")
+		}
+		return errorResult(fmt.Sprintf("Error: %s not found on PATH", name))
+	}
+
+	cmd := exec.Command(path, shell.args(command)...)
+	if name == "cmd" {
+		setCommandLine(cmd, fmt.Sprintf(`"%s" /d /s /c "%s"`, path, command))
+	}
+	cmd.Dir = options.Dir
+	cmd.Env = append(append(append(os.Environ(), commonEnv...), shell.env...), options.Env...)
+
+	output := &tailBuffer{max: options.MaxOutput}
+	cmd.Stdout = output
+	cmd.Stderr = output
+
+	var timedOut atomic.Bool
+	if options.Timeout > 0 {
+		timer := time.AfterFunc(options.Timeout, func() {
+			timedOut.Store(true)
+			mcpserver.KillProcessTree(cmd)
+		})
+		defer timer.Stop()
+	}
+
+	err := lifecycle.Run(cmd)
+	text := output.String()
+	switch {
+	case timedOut.Load():
+		return errorResult(fmt.Sprintf("Error: command timed out after %s\nOutput: %s", options.Timeout, text))
+	case err != nil:
+		return errorResult(fmt.Sprintf("Error: %v\nOutput: %s", err, text))
+	}
+	return textResult(text)
+}
+
+// tailBuffer keeps the last max bytes written to it, where errors and
+// summaries usually are
+type tailBuffer struct {
+	buf     bytes.Buffer
+	max     int
+	dropped int
+}
+
+func (b *tailBuffer) Write(p []byte) (int, error) {
+	b.buf.Write(p)
+	if b.max > 0 && b.buf.Len() > 2*b.max {
+		excess := b.buf.Len() - b.max
+		b.dropped += excess
+		b.buf.Next(excess)
+	}
+	return len(p), nil
+}
+
+func (b *tailBuffer) String() string {
+	data := b.buf.Bytes()
+	dropped := b.dropped
+	if b.max > 0 && len(data) > b.max {
+		dropped += len(data) - b.max
+		data = data[len(data)-b.max:]
+	}
+	text := strings.TrimSpace(string(data))
+	if dropped > 0 {
+		text = fmt.Sprintf("[output truncated: first %d bytes omitted]\n%s", dropped, text)
+	}
+	return text
+}
+
+func envInt(name string, fallback int) int {
+	if value, err := strconv.Atoi(os.Getenv(name)); err == nil {
+		return value
+	}
+	return fallback
+}
+
+func envDuration(name string, fallback time.Duration) time.Duration {
+	if value, err := time.ParseDuration(os.Getenv(name)); err == nil {
+		return value
+	}
+	return fallback
+}
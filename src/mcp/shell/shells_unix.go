@@ -0,0 +1,8 @@
+//go:build !windows
+
+package main
+
+import "os/exec"
+
+// setCommandLine is only needed for cmd.exe on Windows
+func setCommandLine(cmd *exec.Cmd, line string) {}